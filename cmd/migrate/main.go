@@ -1,24 +1,42 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
 	"pulse/internal/config"
+	"pulse/internal/crypto"
 	"pulse/internal/database"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+	"pulse/internal/service"
 )
 
 func main() {
 	// 定义命令行参数
 	var (
-		action  = flag.String("action", "up", "Migration action: up, down, status, version, force")
+		action  = flag.String("action", "up", "Migration action: up, down, status, version, force, plan, verify, seed, rotate-keys")
 		steps   = flag.String("steps", "1", "Number of steps for down migration")
 		version = flag.String("version", "", "Version to force (required for force action)")
 		envFile = flag.String("env", ".env", "Environment file path")
+
+		// 蓝绿发布安全模式：存在删除数据或旧版本不兼容的迁移时拒绝执行 up
+		safe = flag.Bool("safe", false, "Refuse to run up when pending migrations contain data-loss or breaking changes")
+
+		// 初始化数据参数
+		adminUsername = flag.String("admin-username", "admin", "Admin username for seed action")
+		adminEmail    = flag.String("admin-email", "admin@example.com", "Admin email for seed action")
+		adminPassword = flag.String("admin-password", os.Getenv("PULSE_ADMIN_PASSWORD"), "Admin password for seed action, random if empty")
+		sampleRules   = flag.Bool("sample-rules", true, "Seed sample alert rules")
+
+		// 密钥轮换参数
+		batchSize = flag.Int("batch-size", 100, "Number of records per batch for rotate-keys action")
 	)
 	flag.Parse()
 
@@ -36,6 +54,15 @@ func main() {
 		logger.Fatal("Failed to load config", zap.Error(err))
 	}
 
+	// 从密钥提供者加载数据库密码等密钥
+	secretProvider, err := config.NewSecretProvider(cfg.Secrets)
+	if err != nil {
+		logger.Fatal("Failed to create secrets provider", zap.Error(err))
+	}
+	if err := cfg.LoadSecrets(context.Background(), secretProvider); err != nil {
+		logger.Fatal("Failed to load secrets", zap.Error(err))
+	}
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		logger.Fatal("Invalid config", zap.Error(err))
@@ -51,6 +78,16 @@ func main() {
 	// 执行迁移操作
 	switch *action {
 	case "up":
+		if *safe {
+			plan, err := db.PlanMigrations()
+			if err != nil {
+				logger.Fatal("Failed to plan migrations", zap.Error(err))
+			}
+			if plan.HasRisk(database.MigrationRiskDataLoss, database.MigrationRiskBreaking) {
+				printMigrationPlan(plan)
+				logger.Fatal("Pending migrations are not safe for blue/green deployment, run without -safe after the previous version is drained")
+			}
+		}
 		if err := db.RunMigrations(); err != nil {
 			logger.Fatal("Failed to run migrations", zap.Error(err))
 		}
@@ -91,7 +128,125 @@ func main() {
 		}
 		logger.Info("Migration version forced successfully", zap.Int("version", versionInt))
 
+	case "plan":
+		plan, err := db.PlanMigrations()
+		if err != nil {
+			logger.Fatal("Failed to plan migrations", zap.Error(err))
+		}
+		printMigrationPlan(plan)
+
+	case "verify":
+		// 校验迁移状态和表结构，存在未执行的迁移或表结构与模型不一致时以非零状态退出
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		plan, err := db.PlanMigrations()
+		if err != nil {
+			logger.Fatal("Failed to plan migrations", zap.Error(err))
+		}
+		drifts, err := db.VerifySchema(ctx)
+		if err != nil {
+			logger.Fatal("Failed to verify schema", zap.Error(err))
+		}
+
+		fmt.Printf("Current migration version: %d (dirty: %t, pending: %d)\n", plan.CurrentVersion, plan.Dirty, len(plan.Pending))
+		for _, drift := range drifts {
+			if drift.Column == "" {
+				fmt.Printf("  %s: %s\n", drift.Kind, drift.Table)
+			} else {
+				fmt.Printf("  %s: %s.%s\n", drift.Kind, drift.Table, drift.Column)
+			}
+		}
+		if plan.Dirty || len(plan.Pending) > 0 || len(drifts) > 0 {
+			logger.Fatal("Schema verification failed",
+				zap.Bool("dirty", plan.Dirty),
+				zap.Int("pending", len(plan.Pending)),
+				zap.Int("drifts", len(drifts)),
+			)
+		}
+		fmt.Println("Schema matches models")
+
+	case "seed":
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		result, err := db.Seed(ctx, database.SeedOptions{
+			AdminUsername: *adminUsername,
+			AdminEmail:    *adminEmail,
+			AdminPassword: *adminPassword,
+			SampleRules:   *sampleRules,
+		})
+		if err != nil {
+			logger.Fatal("Failed to seed database", zap.Error(err))
+		}
+		fmt.Printf("Roles created: %d\n", result.Roles)
+		fmt.Printf("Sample rules created: %d\n", result.Rules)
+		if result.AdminCreated {
+			fmt.Printf("Admin user created: %s\n", *adminUsername)
+		}
+		// 随机生成的密码只输出一次
+		if result.AdminPassword != "" {
+			fmt.Printf("Admin password: %s\n", result.AdminPassword)
+		}
+
+	case "rotate-keys":
+		// 将数据源配置、集成密钥、Webhook凭据和用户敏感信息重新加密为当前主密钥版本
+		// JWT 密钥只用于解密早期版本加密的历史数据，轮换后重新加密为当前主密钥版本
+		keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{
+			Keys:           cfg.Security.EncryptionKeys,
+			ActiveVersion:  cfg.Security.EncryptionActiveKey,
+			LegacyKey:      cfg.Security.EncryptionKey,
+			IndexKey:       cfg.Security.EncryptionIndexKey,
+			DecryptOnlyKey: cfg.JWT.Secret,
+		})
+		if err != nil {
+			logger.Fatal("Failed to initialize encryption keys", zap.Error(err))
+		}
+
+		repoManager := repository.NewRepositoryManagerWithReader(db.DB, db.DB, keyManager, cfg.Security.EncryptUserPII, nil)
+		report, err := service.NewKeyRotationService(repoManager, *batchSize, logger).RotateEncryptionKeys(context.Background())
+		if err != nil {
+			logger.Fatal("Failed to rotate encryption keys", zap.Error(err))
+		}
+		fmt.Printf("Active key version: %s\n", report.ActiveVersion)
+		for _, target := range []struct {
+			name   string
+			result *models.KeyRotationResult
+		}{
+			{"Data sources", report.DataSources},
+			{"Webhook integrations", report.WebhookIntegrations},
+			{"Webhooks", report.Webhooks},
+			{"Users", report.Users},
+			{"Webhook subscriptions", report.WebhookSubscriptions},
+		} {
+			fmt.Printf("%s scanned: %d, rotated: %d, skipped: %d, failed: %d\n", target.name,
+				target.result.Scanned, target.result.Rotated, target.result.Skipped, target.result.Failed)
+		}
+		if report.Failed() > 0 {
+			logger.Fatal("Some records could not be decrypted, see warnings above")
+		}
+
 	default:
 		logger.Fatal("Unknown action", zap.String("action", *action))
 	}
 }
+
+// printMigrationPlan 输出待执行的迁移及其风险提示
+func printMigrationPlan(plan *database.MigrationPlan) {
+	fmt.Printf("Current migration version: %d\n", plan.CurrentVersion)
+	if plan.Dirty {
+		fmt.Println("WARNING: database is in dirty state, fix it with -action force before migrating")
+	}
+	if len(plan.Pending) == 0 {
+		fmt.Println("No pending migrations")
+		return
+	}
+
+	fmt.Printf("Pending migrations: %d\n", len(plan.Pending))
+	for _, pending := range plan.Pending {
+		fmt.Printf("  %d %s\n", pending.Version, pending.Name)
+		for _, warning := range pending.Warnings {
+			fmt.Printf("    [%s] %s\n      %s\n", warning.Risk, warning.Message, warning.Statement)
+		}
+	}
+}
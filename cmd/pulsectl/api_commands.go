@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+	"pulse/internal/worker"
+)
+
+// labelFlags 可重复指定的 key=value 标签参数
+type labelFlags map[string]string
+
+// String 返回参数的字符串形式
+func (l labelFlags) String() string {
+	return formatLabels(l)
+}
+
+// Set 解析一个 key=value
+func (l labelFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	l[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	return nil
+}
+
+// runSilences 管理当前 Token 用户的告警暂停
+func runSilences(a *app, args []string) error {
+	sub, args, err := a.subcommand("silences", args, "list", "create", "delete")
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+
+	switch sub {
+	case "create":
+		matchers := labelFlags{}
+		fs := a.flagSet("silences create")
+		alertID := fs.String("alert", "", "Alert ID to silence")
+		fs.Var(matchers, "match", "Label matcher key=value, repeatable; silences every alert carrying all labels")
+		duration := fs.Duration("duration", time.Hour, "Silence duration, e.g. 30m or 4h")
+		reason := fs.String("reason", "", "Reason for the silence")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+
+		req := &models.AlertSnoozeRequest{
+			Matchers:        matchers,
+			DurationSeconds: int(duration.Seconds()),
+			Reason:          *reason,
+		}
+		if *alertID != "" {
+			req.AlertID = alertID
+		}
+		if err := req.Validate(); err != nil {
+			return err
+		}
+		var snooze models.AlertSnooze
+		if err := client.do(ctx, http.MethodPost, "/snoozes", req, &snooze); err != nil {
+			return err
+		}
+		return a.printSilences([]*models.AlertSnooze{&snooze}, &snooze)
+
+	case "delete":
+		fs := a.flagSet("silences delete")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			fmt.Fprintln(a.stderr, "Usage: pulsectl silences delete <id>...")
+			return errUsage
+		}
+		for _, id := range fs.Args() {
+			if err := client.do(ctx, http.MethodDelete, "/snoozes/"+url.PathEscape(id), nil, nil); err != nil {
+				return fmt.Errorf("delete silence %s: %w", id, err)
+			}
+			fmt.Fprintf(a.stdout, "Silence %s deleted\n", id)
+		}
+		return nil
+
+	default:
+		if err := parse(a.flagSet("silences list"), args); err != nil {
+			return err
+		}
+		var snoozes []*models.AlertSnooze
+		if err := client.do(ctx, http.MethodGet, "/snoozes", nil, &snoozes); err != nil {
+			return err
+		}
+		return a.printSilences(snoozes, snoozes)
+	}
+}
+
+// printSilences 输出告警暂停列表
+func (a *app) printSilences(snoozes []*models.AlertSnooze, v interface{}) error {
+	rows := make([][]string, 0, len(snoozes))
+	for _, s := range snoozes {
+		target := formatLabels(s.Matchers)
+		if s.AlertID != nil {
+			target = "alert=" + *s.AlertID
+		}
+		expires := s.ExpiresAt
+		rows = append(rows, []string{s.ID, target, formatTime(&expires), orDash(s.Reason)})
+	}
+	return a.print(v, []string{"ID", "TARGET", "EXPIRES", "REASON"}, rows)
+}
+
+// runJobs 查看和控制后台任务
+func runJobs(a *app, args []string) error {
+	sub, args, err := a.subcommand("jobs", args, "list", "get", "trigger", "pause", "resume", "cancel")
+	if err != nil {
+		return err
+	}
+	fs := a.flagSet("jobs " + sub)
+	wait := fs.Bool("wait", false, "For trigger: wait until the triggered run finishes and fail if it failed")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if sub != "list" && fs.NArg() != 1 {
+		fmt.Fprintf(a.stderr, "Usage: pulsectl jobs %s <name>\n", sub)
+		return errUsage
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+
+	if sub == "list" {
+		var jobs []worker.JobStatus
+		if err := client.do(ctx, http.MethodGet, "/admin/jobs", nil, &jobs); err != nil {
+			return err
+		}
+		return a.printJobs(jobs, jobs)
+	}
+
+	path := "/admin/jobs/" + url.PathEscape(fs.Arg(0))
+	var job worker.JobStatus
+	if sub == "get" {
+		if err := client.do(ctx, http.MethodGet, path, nil, &job); err != nil {
+			return err
+		}
+		return a.printJobs([]worker.JobStatus{job}, job)
+	}
+
+	if err := client.do(ctx, http.MethodPost, path+"/"+sub, nil, &job); err != nil {
+		return err
+	}
+	if sub == "trigger" && *wait {
+		if job, err = waitForRun(ctx, client, path, job.Runs); err != nil {
+			return err
+		}
+		if job.LastResult != worker.JobResultSucceeded {
+			_ = a.printJobs([]worker.JobStatus{job}, job)
+			return fmt.Errorf("job %s %s: %s", job.Name, job.LastResult, job.LastError)
+		}
+	}
+	return a.printJobs([]worker.JobStatus{job}, job)
+}
+
+// jobPollInterval 等待任务执行完成时查询状态的间隔
+var jobPollInterval = time.Second
+
+// waitForRun 等待执行次数超过 runs 且任务不在执行中，超时由 ctx 控制
+func waitForRun(ctx context.Context, client *apiClient, path string, runs int64) (worker.JobStatus, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		var job worker.JobStatus
+		if err := client.do(ctx, http.MethodGet, path, nil, &job); err != nil {
+			return job, err
+		}
+		if job.Runs > runs && job.State != worker.JobStateRunning {
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return job, errors.New("timed out waiting for the job to finish, increase -timeout")
+		case <-ticker.C:
+		}
+	}
+}
+
+// printJobs 输出后台任务列表
+func (a *app) printJobs(jobs []worker.JobStatus, v interface{}) error {
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, []string{
+			job.Name, job.State, job.Interval.String(), formatTime(job.LastRunAt), job.LastDuration.Round(time.Millisecond).String(),
+			orDash(job.LastResult), formatTime(job.NextRunAt), strconv.FormatInt(job.Runs, 10), strconv.FormatInt(job.Failures, 10),
+			orDash(job.LastError),
+		})
+	}
+	return a.print(v, []string{"NAME", "STATE", "INTERVAL", "LAST RUN", "DURATION", "RESULT", "NEXT RUN", "RUNS", "FAILURES", "ERROR"}, rows)
+}
+
+// runMaintenance 执行维护任务
+func runMaintenance(a *app, args []string) error {
+	_, args, err := a.subcommand("maintenance", args, "rotate-keys")
+	if err != nil {
+		return err
+	}
+	if err := parse(a.flagSet("maintenance rotate-keys"), args); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+
+	var report models.KeyRotationReport
+	if err := client.do(ctx, http.MethodPost, "/admin/encryption/rotate", nil, &report); err != nil {
+		return err
+	}
+	if err := a.printKeyRotation(&report); err != nil {
+		return err
+	}
+	if report.Failed() > 0 {
+		return fmt.Errorf("%d records could not be re-encrypted", report.Failed())
+	}
+	return nil
+}
+
+// printKeyRotation 输出密钥轮换结果
+func (a *app) printKeyRotation(report *models.KeyRotationReport) error {
+	rows := make([][]string, 0, 5)
+	for _, target := range []struct {
+		name   string
+		result *models.KeyRotationResult
+	}{
+		{"data_sources", report.DataSources},
+		{"webhook_integrations", report.WebhookIntegrations},
+		{"webhooks", report.Webhooks},
+		{"users", report.Users},
+		{"webhook_subscriptions", report.WebhookSubscriptions},
+	} {
+		if target.result == nil {
+			continue
+		}
+		rows = append(rows, []string{target.name, report.ActiveVersion, strconv.Itoa(target.result.Scanned),
+			strconv.Itoa(target.result.Rotated), strconv.Itoa(target.result.Skipped), strconv.Itoa(target.result.Failed)})
+	}
+	return a.print(report, []string{"TARGET", "KEY VERSION", "SCANNED", "ROTATED", "SKIPPED", "FAILED"}, rows)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// apiClient Pulse API 客户端
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// apiError API 返回的错误响应，结构同 apierror.Response
+type apiError struct {
+	Status  int             `json:"-"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Details json.RawMessage `json:"details,omitempty"`
+	TraceID string          `json:"trace_id,omitempty"`
+}
+
+// Error 返回错误信息
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%s (HTTP %d", e.Message, e.Status)
+	if e.Code != "" {
+		msg += ", code " + e.Code
+	}
+	if e.TraceID != "" {
+		msg += ", trace " + e.TraceID
+	}
+	msg += ")"
+	if len(e.Details) > 0 && string(e.Details) != "null" {
+		msg += ": " + strings.Trim(string(e.Details), `"`)
+	}
+	return msg
+}
+
+// client 创建 API 客户端，API 命令需要 Token
+func (a *app) client() (*apiClient, error) {
+	if a.opts.token == "" {
+		return nil, errors.New("API commands require a token, set -token or PULSE_TOKEN")
+	}
+	if _, err := url.ParseRequestURI(a.opts.server); err != nil {
+		return nil, fmt.Errorf("invalid server address %q: %w", a.opts.server, err)
+	}
+	return &apiClient{
+		baseURL: strings.TrimRight(a.opts.server, "/") + "/api/v1",
+		token:   a.opts.token,
+		http:    &http.Client{Timeout: a.opts.timeout},
+	}, nil
+}
+
+// do 发送请求，body 不为 nil 时编码为 JSON，响应的 data 字段解码到 out
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{Status: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+			if apiErr.Message == "" {
+				apiErr.Message = http.StatusText(resp.StatusCode)
+			}
+		}
+		return apiErr
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/worker"
+)
+
+// runApp 执行命令，返回退出码、标准输出和标准错误
+func runApp(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	a := &app{stdout: &stdout, stderr: &stderr, stdin: strings.NewReader("")}
+	code := a.main(args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestJobsTriggerWait(t *testing.T) {
+	jobPollInterval = 10 * time.Millisecond
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+		status := worker.JobStatus{Name: "data_retention", State: worker.JobStateIdle, Runs: 3}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/jobs/data_retention/trigger":
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/jobs/data_retention":
+			// 第二次查询时本次触发的执行已完成
+			if atomic.AddInt32(&polls, 1) >= 2 {
+				status.Runs = 4
+				status.LastResult = worker.JobResultFailed
+				status.LastError = "archive bucket unavailable"
+			} else {
+				status.State = worker.JobStateRunning
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": status})
+	}))
+	defer server.Close()
+
+	code, stdout, stderr := runApp("-server", server.URL, "-token", "secret-token", "jobs", "trigger", "-wait", "data_retention")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout, "data_retention")
+	assert.Contains(t, stderr, "job data_retention failed: archive bucket unavailable")
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&polls), int32(2))
+}
+
+func TestAPIErrorAndUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"NOT_FOUND","message":"Background job not found","details":"nope","trace_id":"abc"}`))
+	}))
+	defer server.Close()
+
+	code, _, stderr := runApp("-server", server.URL, "-token", "t", "jobs", "get", "nope")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "Background job not found (HTTP 404, code NOT_FOUND, trace abc): nope")
+
+	code, _, stderr = runApp("-server", server.URL, "jobs", "list")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "require a token")
+
+	code, _, stderr = runApp("jobs", "explode")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown jobs subcommand")
+
+	code, _, _ = runApp("-o", "xml", "jobs", "list")
+	assert.Equal(t, 2, code)
+}
+
+func TestSilencesCreateValidatesBeforeRequest(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"service": "api", "env": "prod"}, body["matchers"])
+		assert.Equal(t, float64(7200), body["duration_seconds"])
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"id": "s1", "matchers": body["matchers"], "reason": body["reason"], "expires_at": time.Now().Add(2 * time.Hour),
+		}})
+	}))
+	defer server.Close()
+
+	code, _, _ := runApp("-server", server.URL, "-token", "t", "silences", "create", "-duration", "2h")
+	assert.Equal(t, 1, code)
+	assert.Zero(t, atomic.LoadInt32(&requests))
+
+	code, stdout, _ := runApp("-server", server.URL, "-token", "t", "silences", "create",
+		"-match", "service=api", "-match", "env=prod", "-duration", "2h", "-reason", "deploy")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "env=prod,service=api")
+	assert.Contains(t, stdout, "deploy")
+}
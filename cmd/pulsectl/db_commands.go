@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"pulse/internal/config"
+	"pulse/internal/crypto"
+	"pulse/internal/database"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// store 数据库命令使用的数据库连接和仓储
+type store struct {
+	db    *database.DB
+	repos repository.RepositoryManager
+}
+
+// openStore 按环境文件连接数据库，加密配置与服务端一致，以便读写加密字段
+func (a *app) openStore() (*store, error) {
+	cfg, err := config.Load(a.opts.envFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	secretProvider, err := config.NewSecretProvider(cfg.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets provider: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+	if err := cfg.LoadSecrets(ctx, secretProvider); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{
+		Keys:           cfg.Security.EncryptionKeys,
+		ActiveVersion:  cfg.Security.EncryptionActiveKey,
+		LegacyKey:      cfg.Security.EncryptionKey,
+		IndexKey:       cfg.Security.EncryptionIndexKey,
+		DecryptOnlyKey: cfg.JWT.Secret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize encryption keys: %w", err)
+	}
+
+	db, err := database.New(&cfg.Database, zap.NewNop())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return &store{
+		db:    db,
+		repos: repository.NewRepositoryManagerWithReader(db.DB, db.DB, keyManager, cfg.Security.EncryptUserPII, nil),
+	}, nil
+}
+
+// runBootstrap 创建内置角色和初始管理员，已存在的记录跳过，可重复执行
+func runBootstrap(a *app, args []string) error {
+	fs := a.flagSet("bootstrap")
+	username := fs.String("admin-username", "admin", "Admin username")
+	email := fs.String("admin-email", "admin@example.com", "Admin email")
+	password := fs.String("admin-password", os.Getenv("PULSE_ADMIN_PASSWORD"), "Admin password, random if empty (env PULSE_ADMIN_PASSWORD)")
+	sampleRules := fs.Bool("sample-rules", false, "Also create sample alert rules")
+	migrate := fs.Bool("migrate", true, "Run database migrations first")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	s, err := a.openStore()
+	if err != nil {
+		return err
+	}
+	defer s.db.Close()
+
+	if *migrate {
+		if err := s.db.RunMigrations(); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+	result, err := s.db.Seed(ctx, database.SeedOptions{
+		AdminUsername: *username,
+		AdminEmail:    *email,
+		AdminPassword: *password,
+		SampleRules:   *sampleRules,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap database: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "Roles created: %d\n", result.Roles)
+	fmt.Fprintf(a.stdout, "Sample rules created: %d\n", result.Rules)
+	if result.AdminCreated {
+		fmt.Fprintf(a.stdout, "Admin user created: %s\n", *username)
+	} else {
+		fmt.Fprintf(a.stdout, "Admin user already exists: %s\n", *username)
+	}
+	// 随机生成的密码只输出一次
+	if result.AdminPassword != "" {
+		fmt.Fprintf(a.stdout, "Admin password: %s\n", result.AdminPassword)
+	}
+	return nil
+}
+
+// runUsers 管理用户
+func runUsers(a *app, args []string) error {
+	sub, args, err := a.subcommand("users", args, "list", "create", "set-role", "enable", "disable", "reset-password")
+	if err != nil {
+		return err
+	}
+
+	fs := a.flagSet("users " + sub)
+	var (
+		role, status, keyword *string
+		email, displayName    *string
+		password              *string
+		page, pageSize        *int
+	)
+	switch sub {
+	case "list":
+		role = fs.String("role", "", "Filter by role")
+		status = fs.String("status", "", "Filter by status")
+		keyword = fs.String("q", "", "Search username, email and display name")
+		page = fs.Int("page", 1, "Page number")
+		pageSize = fs.Int("page-size", 100, "Page size, at most 100")
+	case "create":
+		email = fs.String("email", "", "Email (required)")
+		displayName = fs.String("display-name", "", "Display name, defaults to the username")
+		role = fs.String("role", string(models.UserRoleViewer), "Role: admin, operator, developer, viewer or guest")
+		password = fs.String("password", "", "Password, read from stdin when set to -, random if empty")
+	case "set-role":
+		role = fs.String("role", "", "New role (required)")
+	case "reset-password":
+		password = fs.String("password", "", "New password, read from stdin when set to -, random if empty")
+	}
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if sub != "list" && fs.NArg() != 1 {
+		fmt.Fprintf(a.stderr, "Usage: pulsectl users %s [flags] <username>\n", sub)
+		return errUsage
+	}
+
+	s, err := a.openStore()
+	if err != nil {
+		return err
+	}
+	defer s.db.Close()
+	users := s.repos.User()
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+
+	if sub == "list" {
+		filter := &models.UserFilter{Page: *page, PageSize: *pageSize}
+		if *role != "" {
+			r := models.UserRole(*role)
+			filter.Role = &r
+		}
+		if *status != "" {
+			st := models.UserStatus(*status)
+			filter.Status = &st
+		}
+		if *keyword != "" {
+			filter.Keyword = keyword
+		}
+		list, err := users.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return a.printUsers(list.Users, list)
+	}
+
+	username := fs.Arg(0)
+	if sub == "create" {
+		if *email == "" {
+			return errors.New("-email is required")
+		}
+		if *displayName == "" {
+			displayName = &username
+		}
+		plain, generated, err := a.readPassword(*password)
+		if err != nil {
+			return err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		user := &models.User{
+			Username:     username,
+			Email:        *email,
+			PasswordHash: string(hash),
+			DisplayName:  *displayName,
+			Role:         models.UserRole(*role),
+			Status:       models.UserStatusActive,
+		}
+		if err := user.Validate(); err != nil {
+			return err
+		}
+		if exists, err := users.ExistsByUsername(ctx, username); err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("user %s already exists", username)
+		}
+		if err := users.Create(ctx, user); err != nil {
+			return err
+		}
+		if err := a.printUsers([]*models.User{user}, user); err != nil {
+			return err
+		}
+		a.printGeneratedPassword(generated, plain)
+		return nil
+	}
+
+	user, err := users.GetByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", username, err)
+	}
+
+	switch sub {
+	case "set-role":
+		newRole := models.UserRole(*role)
+		if !newRole.IsValid() {
+			return fmt.Errorf("invalid role %q", *role)
+		}
+		user.Role = newRole
+		if err := users.Update(ctx, user); err != nil {
+			return err
+		}
+	case "enable":
+		if err := users.Activate(ctx, user.ID); err != nil {
+			return err
+		}
+		user.Status = models.UserStatusActive
+	case "disable":
+		if err := users.UpdateStatus(ctx, user.ID, models.UserStatusDisabled); err != nil {
+			return err
+		}
+		user.Status = models.UserStatusDisabled
+	case "reset-password":
+		plain, generated, err := a.readPassword(*password)
+		if err != nil {
+			return err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		if err := users.UpdatePassword(ctx, user.ID, string(hash)); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "Password of %s reset\n", username)
+		a.printGeneratedPassword(generated, plain)
+		return nil
+	}
+	return a.printUsers([]*models.User{user}, user)
+}
+
+// readPassword 读取密码：- 表示从标准输入读取第一行，为空时生成随机密码
+func (a *app) readPassword(value string) (string, bool, error) {
+	switch value {
+	case "-":
+		data, err := io.ReadAll(io.LimitReader(a.stdin, 4096))
+		if err != nil {
+			return "", false, err
+		}
+		line, _, _ := strings.Cut(string(data), "\n")
+		line = strings.TrimRight(line, "\r")
+		if len(line) < 8 {
+			return "", false, errors.New("password must be at least 8 characters")
+		}
+		return line, false, nil
+	case "":
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", false, fmt.Errorf("failed to generate password: %w", err)
+		}
+		return hex.EncodeToString(buf), true, nil
+	default:
+		if len(value) < 8 {
+			return "", false, errors.New("password must be at least 8 characters")
+		}
+		return value, false, nil
+	}
+}
+
+// printGeneratedPassword 输出随机生成的密码，只输出一次，写到标准错误以免混入 JSON 输出
+func (a *app) printGeneratedPassword(generated bool, password string) {
+	if generated {
+		fmt.Fprintf(a.stderr, "Generated password: %s\n", password)
+	}
+}
+
+// printUsers 输出用户列表
+func (a *app) printUsers(users []*models.User, v interface{}) error {
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []string{u.ID, u.Username, u.Email, string(u.Role), string(u.Status), formatTime(u.LastLoginAt)})
+	}
+	return a.print(v, []string{"ID", "USERNAME", "EMAIL", "ROLE", "STATUS", "LAST LOGIN"}, rows)
+}
+
+// runRules 导出和导入告警规则
+func runRules(a *app, args []string) error {
+	sub, args, err := a.subcommand("rules", args, "export", "import")
+	if err != nil {
+		return err
+	}
+
+	fs := a.flagSet("rules " + sub)
+	file := fs.String("f", "-", "Rule file, - for stdout/stdin")
+	format := fs.String("format", "yaml", "Export format: yaml or json")
+	dryRun := fs.Bool("dry-run", false, "For import: report changes without writing them")
+	actor := fs.String("actor", "pulsectl", "For import: recorded as creator/updater of imported rules")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *format != "yaml" && *format != "json" {
+		return fmt.Errorf("unknown format %q, expected yaml or json", *format)
+	}
+
+	var data []byte
+	if sub == "import" {
+		if *file == "-" {
+			data, err = io.ReadAll(a.stdin)
+		} else {
+			data, err = os.ReadFile(*file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read rule file: %w", err)
+		}
+	}
+
+	s, err := a.openStore()
+	if err != nil {
+		return err
+	}
+	defer s.db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.timeout)
+	defer cancel()
+
+	if sub == "export" {
+		bundle, err := exportRules(ctx, s.repos)
+		if err != nil {
+			return err
+		}
+		out := a.stdout
+		if *file != "-" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := encodeRuleBundle(out, bundle, *format); err != nil {
+			return err
+		}
+		if *file != "-" {
+			fmt.Fprintf(a.stderr, "Exported %d rules to %s\n", len(bundle.Rules), *file)
+		}
+		return nil
+	}
+
+	bundle, err := decodeRuleBundle(data)
+	if err != nil {
+		return err
+	}
+	importer := &ruleImporter{
+		rules: s.repos.Rule(),
+		dataSources: func(ctx context.Context, name string) (string, error) {
+			ds, err := s.repos.DataSource().GetByName(ctx, name)
+			if err != nil {
+				return "", err
+			}
+			return ds.ID, nil
+		},
+		actor:  *actor,
+		dryRun: *dryRun,
+	}
+	items := importer.Import(ctx, bundle)
+
+	rows := make([][]string, 0, len(items))
+	failed := 0
+	for _, item := range items {
+		if item.Action == importFailed {
+			failed++
+		}
+		rows = append(rows, []string{item.Name, item.Action, orDash(item.Error)})
+	}
+	if err := a.print(items, []string{"RULE", "ACTION", "ERROR"}, rows); err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Fprintln(a.stderr, "Dry run, no changes written")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rules failed to import", failed, len(items))
+	}
+	return nil
+}
+
+// ruleExportPageSize 导出时每次查询的规则数量
+const ruleExportPageSize = 100
+
+// exportRules 分页读取全部规则，数据源按名称引用
+func exportRules(ctx context.Context, repos repository.RepositoryManager) (*ruleBundle, error) {
+	bundle := &ruleBundle{Version: models.ConfigBundleVersion, Rules: make([]models.RuleSpec, 0)}
+	dataSources := make(map[string]string)
+	for page := 1; ; page++ {
+		list, err := repos.Rule().List(ctx, &models.RuleFilter{Page: page, PageSize: ruleExportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range list.Rules {
+			name, ok := dataSources[rule.DataSourceID]
+			if !ok {
+				// 数据源已删除时只保留ID
+				if ds, err := repos.DataSource().GetByID(ctx, rule.DataSourceID); err == nil {
+					name = ds.Name
+				}
+				dataSources[rule.DataSourceID] = name
+			}
+			bundle.Rules = append(bundle.Rules, models.NewRuleSpec(rule, name))
+		}
+		if len(list.Rules) < ruleExportPageSize {
+			return bundle, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// pulsectl 平台管理命令行工具，适用于脚本和 CI 流水线。
+// 告警暂停和后台任务通过 API 管理，需要管理员 Token；用户、规则导入导出和初始化直接连接数据库，
+// 用于服务尚未启动或还没有管理员账号的场景
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errUsage 参数错误，输出用法并以退出码 2 退出
+var errUsage = errors.New("invalid usage")
+
+// globalOptions 全局参数
+type globalOptions struct {
+	server  string
+	token   string
+	envFile string
+	output  string
+	timeout time.Duration
+}
+
+// command 子命令
+type command struct {
+	summary string
+	run     func(app *app, args []string) error
+}
+
+// app 命令执行上下文
+type app struct {
+	opts   globalOptions
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+}
+
+// commands 所有子命令，API 表示通过服务端 API 执行，DB 表示直接连接数据库执行
+var commands = map[string]command{
+	"silences":    {summary: "[API] list, create or delete alert silences (snoozes)", run: runSilences},
+	"jobs":        {summary: "[API] list background jobs and trigger, pause, resume or cancel them", run: runJobs},
+	"maintenance": {summary: "[API] run maintenance tasks: rotate-keys", run: runMaintenance},
+	"users":       {summary: "[DB] list, create and update users", run: runUsers},
+	"rules":       {summary: "[DB] export and import alert rules", run: runRules},
+	"bootstrap":   {summary: "[DB] create built-in roles and the initial admin user", run: runBootstrap},
+}
+
+func main() {
+	a := &app{stdout: os.Stdout, stderr: os.Stderr, stdin: os.Stdin}
+	os.Exit(a.main(os.Args[1:]))
+}
+
+// main 解析全局参数并执行子命令，返回进程退出码
+func (a *app) main(args []string) int {
+	fs := flag.NewFlagSet("pulsectl", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.opts.server, "server", envOr("PULSE_SERVER", "http://localhost:8080"), "Pulse API address (env PULSE_SERVER)")
+	fs.StringVar(&a.opts.token, "token", os.Getenv("PULSE_TOKEN"), "Bearer token for API commands (env PULSE_TOKEN)")
+	fs.StringVar(&a.opts.envFile, "env", envOr("PULSE_ENV_FILE", ".env"), "Environment file used by database commands (env PULSE_ENV_FILE)")
+	fs.StringVar(&a.opts.output, "o", "table", "Output format: table or json")
+	fs.DurationVar(&a.opts.timeout, "timeout", 30*time.Second, "Timeout for each command")
+	fs.Usage = func() { a.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if a.opts.output != "table" && a.opts.output != "json" {
+		fmt.Fprintf(a.stderr, "unknown output format: %s\n", a.opts.output)
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		a.usage(fs)
+		return 2
+	}
+	cmd, ok := commands[rest[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown command: %s\n\n", rest[0])
+		a.usage(fs)
+		return 2
+	}
+
+	if err := cmd.run(a, rest[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(a.stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// usage 输出全局用法
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: pulsectl [global flags] <command> <subcommand> [flags]")
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(a.stderr, "\nGlobal flags:")
+	fs.PrintDefaults()
+}
+
+// subcommand 取出子命令名，缺少时输出可用子命令
+func (a *app) subcommand(name string, args []string, available ...string) (string, []string, error) {
+	if len(args) == 0 || !contains(available, args[0]) {
+		if len(args) > 0 {
+			fmt.Fprintf(a.stderr, "unknown %s subcommand: %s\n", name, args[0])
+		}
+		fmt.Fprintf(a.stderr, "Usage: pulsectl %s <%s> [flags]\n", name, strings.Join(available, "|"))
+		return "", nil, errUsage
+	}
+	return args[0], args[1:], nil
+}
+
+// flagSet 创建子命令参数集，参数错误时不退出进程
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("pulsectl "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// parse 解析子命令参数，参数错误时返回 errUsage
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// envOr 返回环境变量的值，未设置时返回 fallback
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// contains 判断字符串是否在列表中
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
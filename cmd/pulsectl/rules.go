@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"pulse/internal/models"
+)
+
+// ruleBundle 规则导出文件，规则按名称匹配导入，数据源按名称引用以便在不同环境间迁移。
+// 格式与声明式配置一致，导出的文件也可以通过 POST /api/v1/config/apply 应用
+type ruleBundle struct {
+	Version string            `json:"version"`
+	Rules   []models.RuleSpec `json:"rules"`
+}
+
+// encodeRuleBundle 按格式（yaml 或 json）写出规则导出文件。
+// YAML 由 JSON 转换得到，两种格式的字段名一致
+func encodeRuleBundle(w io.Writer, bundle *ruleBundle, format string) error {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err = buf.WriteTo(w)
+		return err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// decodeRuleBundle 解析规则导出文件，JSON 是 YAML 的子集，两种格式都按 YAML 解析后转换
+func decodeRuleBundle(data []byte) (*ruleBundle, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+	converted, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+
+	var bundle ruleBundle
+	dec := json.NewDecoder(bytes.NewReader(converted))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+	if bundle.Version != "" && bundle.Version != models.ConfigBundleVersion {
+		return nil, fmt.Errorf("unsupported rule file version %q, expected %q", bundle.Version, models.ConfigBundleVersion)
+	}
+
+	seen := make(map[string]bool, len(bundle.Rules))
+	for i, spec := range bundle.Rules {
+		if strings.TrimSpace(spec.Name) == "" {
+			return nil, fmt.Errorf("rule #%d has no name", i+1)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	return &bundle, nil
+}
+
+// ruleStore 导入规则使用的规则仓储
+type ruleStore interface {
+	GetByName(ctx context.Context, name string) (*models.Rule, error)
+	Create(ctx context.Context, rule *models.Rule) error
+	Update(ctx context.Context, rule *models.Rule) error
+}
+
+// dataSourceResolver 按名称解析数据源ID
+type dataSourceResolver func(ctx context.Context, name string) (string, error)
+
+// 规则导入结果
+const (
+	importCreated   = "created"
+	importUpdated   = "updated"
+	importUnchanged = "unchanged"
+	importFailed    = "failed"
+)
+
+// ruleImportItem 单条规则的导入结果
+type ruleImportItem struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ruleImporter 按名称创建或更新规则，内容相同的规则跳过，单条失败不影响其他规则
+type ruleImporter struct {
+	rules       ruleStore
+	dataSources dataSourceResolver
+	actor       string
+	dryRun      bool
+}
+
+// Import 导入规则，返回每条规则的结果
+func (i *ruleImporter) Import(ctx context.Context, bundle *ruleBundle) []ruleImportItem {
+	items := make([]ruleImportItem, 0, len(bundle.Rules))
+	for _, spec := range bundle.Rules {
+		action, err := i.importOne(ctx, spec)
+		item := ruleImportItem{Name: spec.Name, Action: action}
+		if err != nil {
+			item.Action = importFailed
+			item.Error = err.Error()
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// importOne 导入一条规则，返回执行的动作
+func (i *ruleImporter) importOne(ctx context.Context, spec models.RuleSpec) (string, error) {
+	dataSourceID := spec.DataSourceID
+	if spec.DataSource != "" {
+		id, err := i.dataSources(ctx, spec.DataSource)
+		if err != nil {
+			return "", fmt.Errorf("data source %q: %w", spec.DataSource, err)
+		}
+		dataSourceID = id
+	}
+
+	existing, err := i.rules.GetByName(ctx, spec.Name)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return "", err
+	}
+
+	if existing == nil {
+		rule := &models.Rule{CreatedBy: i.actor}
+		if err := spec.ApplyTo(rule, dataSourceID); err != nil {
+			return "", err
+		}
+		if !i.dryRun {
+			if err := i.rules.Create(ctx, rule); err != nil {
+				return "", err
+			}
+		}
+		return importCreated, nil
+	}
+
+	before := models.NewRuleSpec(existing, "")
+	updated := *existing
+	if err := spec.ApplyTo(&updated, dataSourceID); err != nil {
+		return "", err
+	}
+	if specEqual(before, models.NewRuleSpec(&updated, "")) {
+		return importUnchanged, nil
+	}
+	if !i.dryRun {
+		updatedBy := i.actor
+		updated.UpdatedBy = &updatedBy
+		if err := i.rules.Update(ctx, &updated); err != nil {
+			return "", err
+		}
+	}
+	return importUpdated, nil
+}
+
+// specEqual 比较两个规则定义，按 JSON 编码结果比较以忽略 nil 与空集合的差异
+func specEqual(a, b models.RuleSpec) bool {
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(left, right)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+// fakeRuleStore 内存规则仓储
+type fakeRuleStore struct {
+	rules   map[string]*models.Rule
+	created int
+	updated int
+}
+
+func (s *fakeRuleStore) GetByName(ctx context.Context, name string) (*models.Rule, error) {
+	if rule, ok := s.rules[name]; ok {
+		copied := *rule
+		return &copied, nil
+	}
+	return nil, models.ErrRuleNotFound
+}
+
+func (s *fakeRuleStore) Create(ctx context.Context, rule *models.Rule) error {
+	s.created++
+	s.rules[rule.Name] = rule
+	return nil
+}
+
+func (s *fakeRuleStore) Update(ctx context.Context, rule *models.Rule) error {
+	s.updated++
+	s.rules[rule.Name] = rule
+	return nil
+}
+
+func testRule() *models.Rule {
+	threshold := 0.9
+	return &models.Rule{
+		ID:                 "r1",
+		DataSourceID:       "ds-1",
+		Name:               "HighCPU",
+		Description:        "CPU usage above 90%",
+		Type:               models.RuleTypeMetric,
+		Status:             models.RuleStatusActive,
+		Enabled:            true,
+		Severity:           models.AlertSeverityCritical,
+		Expression:         "avg(cpu_usage) > 0.9",
+		Labels:             map[string]string{"team": "sre"},
+		EvaluationInterval: time.Minute,
+		ForDuration:        5 * time.Minute,
+		Threshold:          &threshold,
+		EvalCount:          42,
+		CreatedBy:          "alice",
+	}
+}
+
+func TestRuleBundleRoundTrip(t *testing.T) {
+	bundle := &ruleBundle{Version: models.ConfigBundleVersion, Rules: []models.RuleSpec{models.NewRuleSpec(testRule(), "prometheus-main")}}
+
+	for _, format := range []string{"yaml", "json"} {
+		var buf bytes.Buffer
+		require.NoError(t, encodeRuleBundle(&buf, bundle, format))
+		assert.Contains(t, buf.String(), "evaluation_interval")
+		assert.NotContains(t, buf.String(), "eval_count")
+
+		decoded, err := decodeRuleBundle(buf.Bytes())
+		require.NoError(t, err, format)
+		assert.Equal(t, bundle, decoded, format)
+	}
+
+	_, err := decodeRuleBundle([]byte("version: pulse/v1\nrules:\n  - name: a\n  - name: a\n"))
+	assert.ErrorContains(t, err, "duplicate rule name")
+	_, err = decodeRuleBundle([]byte("rules:\n  - name: a\n    unknown: 1\n"))
+	assert.Error(t, err)
+}
+
+func TestRuleImporter(t *testing.T) {
+	existing := testRule()
+	store := &fakeRuleStore{rules: map[string]*models.Rule{existing.Name: existing}}
+	importer := &ruleImporter{
+		rules: store,
+		dataSources: func(ctx context.Context, name string) (string, error) {
+			if name == "prometheus-main" {
+				return "ds-1", nil
+			}
+			return "", errors.New("数据源不存在")
+		},
+		actor: "ci",
+	}
+
+	unchanged := models.NewRuleSpec(testRule(), "prometheus-main")
+	changed := models.NewRuleSpec(testRule(), "prometheus-main")
+	changed.For = "10m"
+	created := models.NewRuleSpec(testRule(), "prometheus-main")
+	created.Name = "HighMemory"
+	missingDS := models.NewRuleSpec(testRule(), "missing")
+	missingDS.Name = "Orphan"
+	invalid := models.NewRuleSpec(testRule(), "prometheus-main")
+	invalid.Name = "BadInterval"
+	invalid.EvaluationInterval = "soon"
+
+	items := importer.Import(context.Background(), &ruleBundle{Rules: []models.RuleSpec{unchanged, created, missingDS, invalid}})
+	assert.Equal(t, []string{importUnchanged, importCreated, importFailed, importFailed}, actions(items))
+	assert.Equal(t, 1, store.created)
+	assert.Equal(t, "ci", store.rules["HighMemory"].CreatedBy)
+
+	// 试运行只报告变更
+	importer.dryRun = true
+	items = importer.Import(context.Background(), &ruleBundle{Rules: []models.RuleSpec{changed}})
+	assert.Equal(t, []string{importUpdated}, actions(items))
+	assert.Zero(t, store.updated)
+
+	importer.dryRun = false
+	importer.Import(context.Background(), &ruleBundle{Rules: []models.RuleSpec{changed}})
+	assert.Equal(t, 1, store.updated)
+	assert.Equal(t, 10*time.Minute, store.rules["HighCPU"].ForDuration)
+	assert.Equal(t, int64(42), store.rules["HighCPU"].EvalCount)
+	assert.Equal(t, "ci", *store.rules["HighCPU"].UpdatedBy)
+}
+
+func actions(items []ruleImportItem) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Action)
+	}
+	return result
+}
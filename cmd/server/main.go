@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,21 +10,36 @@ import (
 	"syscall"
 	"time"
 
+	redisv8 "github.com/go-redis/redis/v8"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"pulse/internal/cache"
 	"pulse/internal/config"
 	"pulse/internal/crypto"
 	"pulse/internal/database"
 	"pulse/internal/gateway"
+	"pulse/internal/lock"
+	"pulse/internal/middleware"
+	"pulse/internal/monitor"
+	"pulse/internal/pkg/ipfilter"
+	"pulse/internal/pkg/loglevel"
+	"pulse/internal/pkg/maintenance"
+	"pulse/internal/pkg/redact"
+	"pulse/internal/pkg/spa"
+	pulseredis "pulse/internal/redis"
+	"pulse/internal/queue"
 	"pulse/internal/repository"
 	"pulse/internal/service"
+	"pulse/internal/worker"
+	"pulse/web"
 )
 
 func main() {
 	// 初始化日志
-	logger, err := initLogger()
+	logger, logLevels, err := initLogger()
 	if err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -38,10 +54,23 @@ func main() {
 		logger.Fatal("Failed to load config", zap.Error(err))
 	}
 
+	// 从密钥提供者加载 JWT 密钥、数据库密码和加密密钥
+	secretProvider, err := config.NewSecretProvider(cfg.Secrets)
+	if err != nil {
+		logger.Fatal("Failed to create secrets provider", zap.Error(err))
+	}
+	secretsCtx, secretsCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	err = cfg.LoadSecrets(secretsCtx, secretProvider)
+	secretsCancel()
+	if err != nil {
+		logger.Fatal("Failed to load secrets", zap.Error(err))
+	}
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		logger.Fatal("Invalid config", zap.Error(err))
 	}
+	setLogLevel(logLevels, cfg.App.LogLevel)
 
 	logger.Info("Configuration loaded",
 		zap.String("environment", cfg.App.Environment),
@@ -50,7 +79,7 @@ func main() {
 	)
 
 	// 连接数据库
-	db, err := database.New(&cfg.Database, logger)
+	db, err := database.New(&cfg.Database, logger.Named("database"))
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
@@ -76,28 +105,32 @@ func main() {
 	}
 	logger.Info("Database health check passed")
 
-	// 初始化加密服务 (使用JWT密钥作为加密密钥)
-	encryptionService := crypto.NewAESEncryptionService(cfg.JWT.Secret)
+	// 初始化加密服务
+	encryptionService, err := newEncryptionService(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize encryption service", zap.Error(err))
+	}
+	if cfg.Security.EncryptionKeys == "" {
+		logger.Warn("ENCRYPTION_KEYS is not set, deriving data encryption key from legacy key")
+	}
+	logger.Info("Encryption service initialized", zap.String("active_key_version", encryptionService.ActiveVersion()))
+
+	// 启动只读副本健康检查
+	db.StartReplicaHealthCheck(context.Background())
+
+	// 外部集成共用的 HTTP 客户端工厂
+	httpClients, err := cfg.NewHTTPClientFactory()
+	if err != nil {
+		logger.Fatal("Failed to create HTTP client factory", zap.Error(err))
+	}
 
 	// 初始化仓库管理器
-	repoManager := repository.NewRepositoryManager(db.DB, encryptionService)
+	repoManager := repository.NewRepositoryManagerWithReader(db.DB, db.Reader(), encryptionService, cfg.Security.EncryptUserPII, httpClients)
 	logger.Info("Repository manager initialized")
 
-	// 初始化服务层
-	serviceManager := service.NewServiceManager(repoManager, logger, cfg)
-	logger.Info("Service manager initialized")
-
-	// 暂时禁用Worker管理器，专注于API网关测试
-	// workerManager := worker.NewManager(serviceManager, logger)
-	// logger.Info("Worker manager initialized")
-	// if err := workerManager.Start(ctx); err != nil {
-	// 	logger.Fatal("Failed to start worker manager", zap.Error(err))
-	// }
-	// defer workerManager.Stop()
-	logger.Info("Worker manager disabled for API gateway testing")
-
 	// 初始化Redis客户端（可选）
 	var redisClient *redis.Client
+	var redisState *pulseredis.Availability
 	redisAddr := fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
 	if cfg.Redis.Host != "" {
 		logger.Info("Connecting to Redis...", zap.String("address", redisAddr))
@@ -107,50 +140,230 @@ func main() {
 			DB:       cfg.Redis.DB,
 		})
 
-		// 测试Redis连接
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := redisClient.Ping(ctx).Err(); err != nil {
-			logger.Warn("Redis connection failed, using memory-based features", zap.Error(err))
-			redisClient = nil
-		} else {
+		// 探测Redis连接，不可用时以降级模式启动，后台定期重连
+		redisState = pulseredis.NewAvailability(func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}, pulseredis.DefaultProbeInterval, logger)
+		if redisState.Available() {
 			logger.Info("Redis connected successfully")
 		}
+		redisState.Start(context.Background())
+		defer redisState.Stop()
+	}
+
+	// 缓存、队列和健康检查使用的 Redis 客户端
+	var cacheRedisClient *redisv8.Client
+	if redisClient != nil {
+		cacheRedisClient = redisv8.NewClient(&redisv8.Options{
+			Addr:     redisAddr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		defer cacheRedisClient.Close()
+	}
+
+	// 功能开关和仪表盘缓存，Redis 不可用时降级为进程内缓存
+	var sharedCacheBackend cache.Cache
+	if cacheRedisClient != nil {
+		sharedCacheBackend = cache.NewRedisCache(cacheRedisClient, cache.WithPrefix("pulse:"))
+	}
+	sharedCache := cache.NewFallbackCache(sharedCacheBackend, redisState)
+
+	// 维护模式，状态通过共享缓存在实例间同步，配置开启时启动即进入维护模式
+	maintenanceMode := maintenance.New(cfg.App.MaintenanceRetryAfter)
+	maintenanceMode.SetStore(sharedCache)
+	if err := maintenanceMode.Sync(context.Background()); err != nil {
+		logger.Warn("Failed to load maintenance mode state", zap.Error(err))
+	}
+	if cfg.App.MaintenanceMode && !maintenanceMode.Enabled() {
+		if _, err := maintenanceMode.Enable(context.Background(), "", "config"); err != nil {
+			logger.Warn("Failed to enable maintenance mode", zap.Error(err))
+		}
+	}
+	if maintenanceMode.Enabled() {
+		logger.Warn("Maintenance mode is enabled, write requests will be rejected")
+	}
+
+	// 维护期间暂存告警接入请求的队列，Redis 不可用时降级为进程内队列
+	var queueBackend queue.Queue
+	if cfg.Redis.Host != "" {
+		queueRedisClient, err := pulseredis.New(&cfg.Redis, logger.Named("queue"))
+		if err != nil {
+			logger.Warn("Redis queue not available, buffering ingest requests in memory", zap.Error(err))
+		} else {
+			defer queueRedisClient.Close()
+			queueBackend = queue.NewRedisQueue(queueRedisClient, cfg, logger.Named("queue"))
+		}
+	}
+	ingestQueue := queue.NewFallbackQueue(queueBackend, redisState, logger.Named("queue"))
+	if err := ingestQueue.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start ingest queue", zap.Error(err))
+	}
+	defer ingestQueue.Close()
+
+	// 初始化服务层
+	serviceManager := service.NewServiceManagerWithCache(repoManager, sharedCache, httpClients, logger.Named("service"), cfg)
+	logger.Info("Service manager initialized")
+
+	// 启动后台Worker
+	var workerManager worker.Manager
+	if cfg.Performance.WorkersEnabled {
+		workerManager = worker.NewManager(serviceManager, logger.Named("worker"))
+		if cfg.JobLock.Enabled {
+			workerManager.UseJobLocker(newJobLocker(cfg.JobLock, cacheRedisClient, logger.Named("worker")))
+		}
+		if err := workerManager.Start(context.Background()); err != nil {
+			logger.Fatal("Failed to start worker manager", zap.Error(err))
+		}
+		defer workerManager.Stop()
+		logger.Info("Worker manager started")
+	} else {
+		logger.Info("Worker manager disabled")
+	}
+
+	// 依赖健康监控，仅数据库故障时判定为未就绪，其余依赖故障时降级运行
+	var healthMonitor *monitor.HealthMonitor
+	if cfg.HealthCheck.Enabled {
+		healthMonitor = newHealthMonitor(cfg, db, cacheRedisClient, serviceManager, workerManager)
+		monitorCtx, stopMonitor := context.WithCancel(context.Background())
+		defer stopMonitor()
+		go healthMonitor.Start(monitorCtx)
+		logger.Info("Health monitor started", zap.Duration("interval", cfg.HealthCheck.Interval))
+	}
+
+	// 平台自监控，内置规则触发时生成 source=self 的告警，与外部告警走同一处理流程
+	ingestStats := monitor.NewIngestStats()
+	if cfg.SelfMonitor.Enabled {
+		selfMonitor := newSelfMonitor(cfg, repoManager, serviceManager, healthMonitor, ingestStats, logger)
+		selfMonitorCtx, stopSelfMonitor := context.WithCancel(context.Background())
+		defer stopSelfMonitor()
+		go selfMonitor.Start(selfMonitorCtx)
+		logger.Info("Self monitor started", zap.Duration("interval", cfg.SelfMonitor.Interval))
 	}
 
 	// 初始化API网关
 	logger.Info("Initializing API Gateway...")
 	
-	// 准备API Keys（示例数据，生产环境应从数据库或配置文件读取）
-	apiKeys := map[string]string{
-		"demo-api-key-1": "user-1",
-		"demo-api-key-2": "user-2",
-	}
-
 	_ = gateway.GatewayConfig{
 		JWTSecret:   cfg.JWT.Secret,
 		RedisClient: redisClient,
-		APIKeys:     apiKeys,
 	}
 
 	// 创建logrus logger用于网关
 	logrusLogger := logrus.New()
-	logrusLogger.SetLevel(logrus.InfoLevel)
+	logLevels.OnChange(func(level zapcore.Level) {
+		if parsed, err := logrus.ParseLevel(level.String()); err == nil {
+			logrusLogger.SetLevel(parsed)
+		}
+	})
+	setLogLevel(logLevels, cfg.App.LogLevel)
 	
 	// 创建API网关
 	gateway := gateway.NewGateway(logrusLogger, redisClient, serviceManager)
+	gateway.SetRedisAvailability(redisState)
+	gateway.SetHealthMonitor(healthMonitor)
+	gateway.SetIngestStats(ingestStats)
+	gateway.SetLogLevels(logLevels)
+	gateway.SetDiagnosticsEnabled(cfg.App.PProfEnabled)
+	gateway.SetMaintenanceMode(maintenanceMode)
+	gateway.SetIngestQueue(ingestQueue)
+	gateway.SetRequestTimeouts(cfg.RequestTimeout.Read, cfg.RequestTimeout.Write, cfg.RequestTimeout.Export)
+	gateway.SetStatusPage(cfg.StatusPage.Enabled, cfg.StatusPage.Token)
+	if workerManager != nil {
+		gateway.SetJobRegistry(workerManager.Jobs())
+	}
+	if cfg.App.WebUIEnabled {
+		if webUI, err := spa.New(web.Dist(), spa.Options{}); err != nil {
+			logger.Warn("Web UI not available, run make build-web before building the binary", zap.Error(err))
+		} else {
+			gateway.SetWebUI(webUI)
+			logger.Info("Web UI enabled")
+		}
+	}
+
+	// 配置限流策略，关闭限流时仍创建限流实例，便于热更新重新开启
+	rateLimitConfig := middleware.DefaultRateLimitConfig(redisClient)
+	if redisClient != nil {
+		rateLimitConfig.Limiter = middleware.NewFallbackRateLimiter(
+			middleware.NewRedisRateLimiter(redisClient), middleware.NewMemoryRateLimiter(), redisState)
+	}
+	applyRateLimitPolicies(&rateLimitConfig, cfg.Reloadable())
+	gateway.SetRateLimitConfig(&rateLimitConfig)
+
+	// 配置告警接入端点的来源IP限制
+	ingestFilter, err := ipfilter.New(cfg.Security.IngestAllowedIPs, cfg.Security.IngestDeniedIPs)
+	if err != nil {
+		logger.Fatal("Invalid ingest IP filter", zap.Error(err))
+	}
+	gateway.SetIngestIPFilter(ingestFilter)
+
+	// 注册机器访问使用的 API Key 及其权限范围
+	apiKeys, err := cfg.Security.ParseAPIKeys()
+	if err != nil {
+		logger.Fatal("Invalid API keys", zap.Error(err))
+	}
+	principals := make(map[string]middleware.APIKeyPrincipal, len(apiKeys))
+	for _, key := range apiKeys {
+		principals[key.Key] = middleware.APIKeyPrincipal{UserID: key.UserID, Scopes: key.Scopes}
+	}
+	if err := gateway.SetAPIKeys(principals); err != nil {
+		logger.Fatal("Invalid API key scopes", zap.Error(err))
+	}
 	logger.Info("API gateway initialized")
 
 	// 设置路由
 	handler := gateway.SetupRoutes()
+	gateway.RateLimit().SetEnabled(cfg.Security.RateLimitEnabled)
+	if err := gateway.StartIngestReplay(context.Background()); err != nil {
+		logger.Fatal("Failed to subscribe ingest buffer", zap.Error(err))
+	}
 	logger.Info("API gateway routes configured")
 
-	// 创建 HTTP 服务器
+	// 监听配置文件，热更新日志级别、限流策略和功能开关
+	configWatcher := config.NewWatcher(".env", cfg)
+	maintenanceConfigured := cfg.App.MaintenanceMode
+	configWatcher.OnReload(func(reloaded config.ReloadableConfig) {
+		setLogLevel(logLevels, reloaded.LogLevel)
+		gateway.RateLimit().Update(func(c *middleware.RateLimitConfig) {
+			applyRateLimitPolicies(c, reloaded)
+		})
+		gateway.RateLimit().SetEnabled(reloaded.RateLimitEnabled)
+		serviceManager.FeatureFlag().SetDefaults(reloaded.FeatureFlags)
+		// 仅在配置项变化时切换，避免覆盖通过管理接口设置的状态
+		if reloaded.MaintenanceMode != maintenanceConfigured {
+			maintenanceConfigured = reloaded.MaintenanceMode
+			if err := setMaintenanceMode(maintenanceMode, reloaded.MaintenanceMode); err != nil {
+				logger.Warn("Failed to switch maintenance mode", zap.Error(err))
+			}
+		}
+		logger.Info("Configuration reloaded",
+			zap.String("log_level", reloaded.LogLevel),
+			zap.Bool("rate_limit_enabled", reloaded.RateLimitEnabled),
+			zap.Strings("feature_flags", reloaded.FeatureFlags),
+		)
+	})
+	configWatcher.OnError(func(err error) {
+		logger.Warn("Failed to reload configuration, keeping current settings", zap.Error(err))
+	})
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if err := configWatcher.Start(watchCtx); err != nil {
+		logger.Warn("Config hot reload disabled", zap.Error(err))
+	}
+	go maintenanceMode.Watch(watchCtx, maintenanceSyncInterval, func(err error) {
+		logger.Warn("Failed to sync maintenance mode state", zap.Error(err))
+	})
+
+	// 创建 HTTP 服务器，写超时不短于导出类请求的处理时限，超时响应才能写回客户端
+	writeTimeout := cfg.Performance.WriteTimeout
+	if writeTimeout > 0 && writeTimeout < cfg.RequestTimeout.Export+serverWriteTimeoutMargin {
+		writeTimeout = cfg.RequestTimeout.Export + serverWriteTimeoutMargin
+	}
 	server := &http.Server{
 		Addr:         cfg.GetServerAddress(),
 		Handler:      handler,
 		ReadTimeout:  cfg.Performance.ReadTimeout,
-		WriteTimeout: cfg.Performance.WriteTimeout,
+		WriteTimeout: writeTimeout,
 		IdleTimeout:  cfg.Performance.IdleTimeout,
 	}
 
@@ -162,6 +375,22 @@ func main() {
 		}
 	}()
 
+	// 本机诊断端口，不经认证，便于通过端口转发直接使用 go tool pprof
+	if cfg.App.PProfEnabled && cfg.App.PProfPort > 0 {
+		diagnosticsServer := &http.Server{
+			Addr:              fmt.Sprintf("127.0.0.1:%d", cfg.App.PProfPort),
+			Handler:           monitor.NewDiagnosticsHandler("/debug"),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info("Starting diagnostics server", zap.String("address", diagnosticsServer.Addr))
+			if err := diagnosticsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Diagnostics server failed", zap.Error(err))
+			}
+		}()
+		defer diagnosticsServer.Close()
+	}
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -180,17 +409,170 @@ func main() {
 	}
 }
 
-// initLogger 初始化日志器
-func initLogger() (*zap.Logger, error) {
+// initLogger 初始化日志器，返回的日志级别可在运行时按全局和模块调整
+func initLogger() (*zap.Logger, *loglevel.Levels, error) {
 	env := os.Getenv("APP_ENVIRONMENT")
 	if env == "" {
 		env = "development"
 	}
 
+	zapConfig := zap.NewDevelopmentConfig()
 	if env == "production" {
-		return zap.NewProduction()
+		zapConfig = zap.NewProductionConfig()
+	}
+	levels := loglevel.New(zapConfig.Level.Level())
+	zapConfig.Level = levels.AtomicLevel()
+
+	// 所有日志在写入前脱敏密码、令牌等敏感信息，按日志器名称应用模块级别
+	logger, err := zapConfig.Build(zap.WrapCore(redact.NewCore), zap.WrapCore(levels.NewCore))
+	return logger, levels, err
+}
+
+// setLogLevel 设置全局日志级别，无法识别的级别保持不变
+func setLogLevel(levels *loglevel.Levels, name string) {
+	if name == "" {
+		return
+	}
+	level, err := loglevel.Parse(name)
+	if err != nil {
+		return
 	}
-	return zap.NewDevelopment()
+	levels.SetGlobal(level)
+}
+
+// setMaintenanceMode 按配置文件切换维护模式
+func setMaintenanceMode(mode *maintenance.Mode, enabled bool) error {
+	var err error
+	if enabled {
+		_, err = mode.Enable(context.Background(), "", "config")
+	} else {
+		_, err = mode.Disable(context.Background(), "config")
+	}
+	return err
+}
+
+// newEncryptionService 创建信封加密服务，必须配置数据加密密钥
+// 早期版本沿用的 JWT 密钥只用于解密历史数据，不用于加密新数据
+func newEncryptionService(cfg *config.Config) (*crypto.KeyManager, error) {
+	return crypto.NewKeyManager(crypto.KeyManagerConfig{
+		Keys:           cfg.Security.EncryptionKeys,
+		ActiveVersion:  cfg.Security.EncryptionActiveKey,
+		LegacyKey:      cfg.Security.EncryptionKey,
+		IndexKey:       cfg.Security.EncryptionIndexKey,
+		DecryptOnlyKey: cfg.JWT.Secret,
+	})
+}
+
+// workerStaleAfter Worker心跳超时的下限，定时类Worker按其执行间隔放宽
+const workerStaleAfter = 5 * time.Minute
+
+// maintenanceSyncInterval 从共享缓存同步其他实例切换的维护模式状态的间隔
+const maintenanceSyncInterval = 5 * time.Second
+
+// serverWriteTimeoutMargin HTTP 服务器写超时在请求处理时限之外预留的写回响应时间
+const serverWriteTimeoutMargin = 5 * time.Second
+
+// newJobLocker 创建基于 Redis 锁租约的后台任务锁，未配置 Redis 时不加锁
+// 获取锁失败（含 Redis 不可达）时任务不执行，执行期间失去锁时任务被取消，避免多个实例重复执行
+func newJobLocker(cfg config.JobLockConfig, redisClient *redisv8.Client, logger *zap.Logger) worker.JobLocker {
+	if redisClient == nil {
+		logger.Warn("Job lock enabled but Redis is not configured, jobs run without locking")
+		return nil
+	}
+	jobLock := lock.NewRedisLock(redisClient, "pulse:lock:")
+	return func(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+		err := jobLock.Hold(ctx, key, fn, lock.WithTTL(cfg.TTL), lock.WithRenewInterval(cfg.RenewInterval))
+		if errors.Is(err, lock.ErrLockLost) {
+			logger.Warn("Lost job lock while running, job canceled", zap.String("key", key), zap.Error(err))
+		}
+		return err
+	}
+}
+
+// ruleEvaluationLagMetrics 返回规则评估延迟指标，每次 /health/metrics 请求时查询，查询失败时输出错误信息
+func ruleEvaluationLagMetrics(rules service.RuleService, timeout time.Duration) monitor.MetricsFunc {
+	return func() map[string]interface{} {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		report, err := rules.EvaluationLag(ctx)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return map[string]interface{}{
+			"total":          report.Total,
+			"late":           report.Late,
+			"missed":         report.Missed,
+			"overdue":        report.Overdue,
+			"max_delay_ms":   report.MaxDelayMs,
+			"max_overdue_ms": report.MaxOverdueMs,
+		}
+	}
+}
+
+// newHealthMonitor 创建依赖健康监控器
+// 数据库为关键依赖；Redis、消息队列、数据源和Worker故障时服务仍可降级运行，只标记为降级
+func newHealthMonitor(cfg *config.Config, db *database.DB, redisClient *redisv8.Client,
+	serviceManager service.ServiceManager, workerManager worker.Manager) *monitor.HealthMonitor {
+	healthMonitor := monitor.NewHealthMonitor(cfg.HealthCheck.Interval, cfg.HealthCheck.Timeout)
+
+	healthMonitor.AddCheck(monitor.NewDatabaseHealthCheck("postgres", db.DB))
+	healthMonitor.AddMetrics("database", db.Metrics)
+	healthMonitor.AddMetrics("ingest_rejections", serviceManager.IngestRejection().Metrics)
+	healthMonitor.AddMetrics("query_cache", serviceManager.QueryCache().Metrics)
+	healthMonitor.AddMetrics("rule_evaluation_lag", ruleEvaluationLagMetrics(serviceManager.Rule(), cfg.HealthCheck.Timeout))
+	if redisClient != nil {
+		healthMonitor.AddCheck(monitor.NewOptionalCheck(monitor.NewRedisHealthCheck("redis", redisClient)))
+		healthMonitor.AddCheck(monitor.NewOptionalCheck(
+			monitor.NewQueueLagHealthCheck("queue", redisClient, cfg.HealthCheck.QueueMaxLag)))
+	}
+	healthMonitor.AddCheck(monitor.NewOptionalCheck(
+		monitor.NewDataSourceHealthCheck("datasources", serviceManager.DataSource())))
+	if workerManager != nil {
+		staleAfter := 3 * serviceManager.KnowledgeSchedule().Interval()
+		if staleAfter < workerStaleAfter {
+			staleAfter = workerStaleAfter
+		}
+		healthMonitor.AddCheck(monitor.NewOptionalCheck(worker.NewHealthCheck(workerManager, staleAfter)))
+	}
+
+	return healthMonitor
+}
+
+// newSelfMonitor 创建平台自监控器
+// 数据库和 Redis 健康规则依赖健康监控器的检查结果，未启用健康检查时不注册
+func newSelfMonitor(cfg *config.Config, repoManager repository.RepositoryManager, serviceManager service.ServiceManager,
+	healthMonitor *monitor.HealthMonitor, ingestStats *monitor.IngestStats, logger *zap.Logger) *monitor.SelfMonitor {
+	selfMonitor := monitor.NewSelfMonitor(repoManager.Alert(), serviceManager.Alert(),
+		cfg.SelfMonitor.Interval, cfg.HealthCheck.Timeout, logger)
+
+	selfMonitor.AddRule(monitor.NewIngestFailureRule(ingestStats, cfg.SelfMonitor.IngestFailureThreshold))
+	selfMonitor.AddRule(monitor.NewEvaluationBacklogRule(repoManager.Rule(), cfg.SelfMonitor.EvaluationBacklogThreshold))
+	selfMonitor.AddRule(monitor.NewEvaluationMissedRule(serviceManager.Rule(), cfg.SelfMonitor.EvaluationMissedThreshold))
+	selfMonitor.AddRule(monitor.NewNotificationFailureRule(repoManager.Notification(),
+		cfg.SelfMonitor.NotificationFailureWindow, cfg.SelfMonitor.NotificationFailureRate,
+		cfg.SelfMonitor.NotificationFailureMinSamples))
+	if healthMonitor != nil {
+		selfMonitor.AddRule(monitor.NewHealthCheckRule(monitor.SelfRuleDatabaseHealth, healthMonitor, "postgres"))
+		if cfg.Redis.Host != "" {
+			selfMonitor.AddRule(monitor.NewHealthCheckRule(monitor.SelfRuleRedisHealth, healthMonitor, "redis"))
+		}
+	}
+
+	return selfMonitor
+}
+
+// applyRateLimitPolicies 根据配置设置默认和 Webhook 限流策略
+func applyRateLimitPolicies(c *middleware.RateLimitConfig, reloadable config.ReloadableConfig) {
+	c.DefaultPolicy = middleware.RateLimitPolicy{
+		Limit:  reloadable.RateLimitRPS,
+		Window: time.Second,
+		Burst:  reloadable.RateLimitBurst,
+	}
+	c.SetRoutePolicy("webhook", middleware.RateLimitPolicy{
+		Limit:  reloadable.RateLimitWebhookRPM,
+		Window: time.Minute,
+	})
 }
 
 // setupRoutes 设置路由
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Availability 后端可用性状态，由 Redis 可用性探测器实现
+type Availability interface {
+	Available() bool
+	ReportFailure(err error)
+}
+
+// FallbackCache 在 Redis 不可用时自动降级到内存缓存的实现
+// Redis 操作失败时立即切换到内存缓存，Redis 恢复后切回并清空内存缓存，避免再次降级时读到过期数据
+type FallbackCache struct {
+	primary      Cache
+	fallback     Cache
+	availability Availability
+	degraded     atomic.Bool
+}
+
+// NewFallbackCache 创建可降级缓存，primary 为 nil 时始终使用内存缓存
+func NewFallbackCache(primary Cache, availability Availability, opts ...CacheOption) *FallbackCache {
+	return &FallbackCache{
+		primary:      primary,
+		fallback:     NewMemoryCache(opts...),
+		availability: availability,
+	}
+}
+
+// Degraded 返回当前是否处于降级模式
+func (f *FallbackCache) Degraded() bool {
+	return !f.usePrimary()
+}
+
+// usePrimary 判断是否使用 Redis，从降级恢复时清空内存缓存
+func (f *FallbackCache) usePrimary() bool {
+	if f.primary == nil || f.availability == nil || !f.availability.Available() {
+		f.degraded.Store(true)
+		return false
+	}
+	if f.degraded.Swap(false) {
+		_ = f.fallback.FlushAll(context.Background())
+	}
+	return true
+}
+
+// do 优先在 Redis 上执行，失败时上报故障并在内存缓存上重试
+func (f *FallbackCache) do(fn func(c Cache) error) error {
+	if f.usePrimary() {
+		err := fn(f.primary)
+		if err == nil {
+			return nil
+		}
+		f.availability.ReportFailure(err)
+		f.degraded.Store(true)
+	}
+	return fn(f.fallback)
+}
+
+// Get 获取缓存值
+func (f *FallbackCache) Get(ctx context.Context, key string) (result string, err error) {
+	err = f.do(func(c Cache) (e error) {
+		result, e = c.Get(ctx, key)
+		return
+	})
+	return
+}
+
+// Set 设置缓存值
+func (f *FallbackCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return f.do(func(c Cache) error {
+		return c.Set(ctx, key, value, ttl)
+	})
+}
+
+// Del 删除缓存
+func (f *FallbackCache) Del(ctx context.Context, keys ...string) error {
+	return f.do(func(c Cache) error {
+		return c.Del(ctx, keys...)
+	})
+}
+
+// Exists 检查键是否存在
+func (f *FallbackCache) Exists(ctx context.Context, keys ...string) (result int64, err error) {
+	err = f.do(func(c Cache) (e error) {
+		result, e = c.Exists(ctx, keys...)
+		return
+	})
+	return
+}
+
+// Expire 设置过期时间
+func (f *FallbackCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return f.do(func(c Cache) error {
+		return c.Expire(ctx, key, ttl)
+	})
+}
+
+// TTL 获取剩余过期时间
+func (f *FallbackCache) TTL(ctx context.Context, key string) (result time.Duration, err error) {
+	err = f.do(func(c Cache) (e error) {
+		result, e = c.TTL(ctx, key)
+		return
+	})
+	return
+}
+
+// Incr 递增
+func (f *FallbackCache) Incr(ctx context.Context, key string) (int64, error) {
+	return f.IncrBy(ctx, key, 1)
+}
+
+// IncrBy 按指定值递增
+func (f *FallbackCache) IncrBy(ctx context.Context, key string, value int64) (result int64, err error) {
+	err = f.do(func(c Cache) (e error) {
+		result, e = c.IncrBy(ctx, key, value)
+		return
+	})
+	return
+}
+
+// Decr 递减
+func (f *FallbackCache) Decr(ctx context.Context, key string) (int64, error) {
+	return f.IncrBy(ctx, key, -1)
+}
+
+// DecrBy 按指定值递减
+func (f *FallbackCache) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return f.IncrBy(ctx, key, -value)
+}
+
+// MGet 批量获取
+func (f *FallbackCache) MGet(ctx context.Context, keys ...string) (result []interface{}, err error) {
+	err = f.do(func(c Cache) (e error) {
+		result, e = c.MGet(ctx, keys...)
+		return
+	})
+	return
+}
+
+// MSet 批量设置
+func (f *FallbackCache) MSet(ctx context.Context, pairs ...interface{}) error {
+	return f.do(func(c Cache) error {
+		return c.MSet(ctx, pairs...)
+	})
+}
+
+// FlushAll 清空所有缓存
+func (f *FallbackCache) FlushAll(ctx context.Context) error {
+	return f.do(func(c Cache) error {
+		return c.FlushAll(ctx)
+	})
+}
+
+// Close 关闭连接
+func (f *FallbackCache) Close() error {
+	if f.primary != nil {
+		return f.primary.Close()
+	}
+	return nil
+}
+
+// Ping 健康检查，降级模式下内存缓存始终可用
+func (f *FallbackCache) Ping(ctx context.Context) error {
+	return f.do(func(c Cache) error {
+		return c.Ping(ctx)
+	})
+}
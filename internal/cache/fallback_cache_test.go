@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAvailability 可控的可用性状态
+type stubAvailability struct {
+	available bool
+	failures  int
+}
+
+func (s *stubAvailability) Available() bool { return s.available }
+
+func (s *stubAvailability) ReportFailure(err error) {
+	s.failures++
+	s.available = false
+}
+
+// failingCache 所有操作均失败的缓存，模拟 Redis 连接断开
+type failingCache struct {
+	*MemoryCache
+}
+
+func (f *failingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "k", "v", time.Minute))
+	got, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", got)
+
+	ttl, _ := c.TTL(ctx, "k")
+	assert.Equal(t, time.Minute, ttl)
+
+	n, err := c.IncrBy(ctx, "counter", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	ttl, _ = c.TTL(ctx, "counter")
+	assert.Equal(t, time.Duration(-1), ttl)
+
+	now = now.Add(2 * time.Minute)
+	got, _ = c.Get(ctx, "k")
+	assert.Empty(t, got)
+	ttl, _ = c.TTL(ctx, "k")
+	assert.Equal(t, time.Duration(-2), ttl)
+}
+
+func TestFallbackCache_FallsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	availability := &stubAvailability{available: true}
+	fc := NewFallbackCache(&failingCache{MemoryCache: NewMemoryCache()}, availability)
+
+	assert.False(t, fc.Degraded())
+	require.NoError(t, fc.Set(ctx, "k", "v", time.Minute))
+	assert.Equal(t, 1, availability.failures)
+	assert.True(t, fc.Degraded())
+
+	got, err := fc.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", got)
+}
+
+func TestFallbackCache_FlushesMemoryOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemoryCache()
+	availability := &stubAvailability{available: false}
+	fc := NewFallbackCache(primary, availability)
+
+	require.NoError(t, fc.Set(ctx, "k", "stale", time.Minute))
+	assert.True(t, fc.Degraded())
+
+	availability.available = true
+	assert.False(t, fc.Degraded())
+
+	exists, err := fc.fallback.Exists(ctx, "k")
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+}
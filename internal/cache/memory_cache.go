@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryItem 内存缓存条目，expiresAt 为零值表示不过期
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// MemoryCache 进程内缓存实现，用于 Redis 不可用时的降级
+// 数据不在实例之间共享，进程重启后丢失
+type MemoryCache struct {
+	mu    sync.Mutex
+	items map[string]*memoryItem
+	opts  *CacheOptions
+	now   func() time.Time
+}
+
+// NewMemoryCache 创建内存缓存
+func NewMemoryCache(opts ...CacheOption) *MemoryCache {
+	return &MemoryCache{
+		items: make(map[string]*memoryItem),
+		opts:  applyCacheOptions(opts...),
+		now:   time.Now,
+	}
+}
+
+// get 获取未过期的条目，过期条目顺带删除，调用方需持有锁
+func (m *MemoryCache) get(key string) (*memoryItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if item.expired(m.now()) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return item, true
+}
+
+// Get 获取缓存值
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item, ok := m.get(key); ok {
+		return item.value, nil
+	}
+	return "", nil
+}
+
+// Set 设置缓存值
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := m.opts.Serializer.Serialize(value)
+	if err != nil {
+		return fmt.Errorf("failed to serialize value: %w", err)
+	}
+	if ttl <= 0 {
+		ttl = m.opts.DefaultTTL
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items[key] = &memoryItem{value: string(data), expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+// Del 删除缓存
+func (m *MemoryCache) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+// Exists 检查键是否存在
+func (m *MemoryCache) Exists(ctx context.Context, keys ...string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int64
+	for _, key := range keys {
+		if _, ok := m.get(key); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Expire 设置过期时间
+func (m *MemoryCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item, ok := m.get(key); ok {
+		item.expiresAt = m.now().Add(ttl)
+	}
+	return nil
+}
+
+// TTL 获取剩余过期时间，与 Redis 一致：键不存在返回 -2，未设置过期返回 -1
+func (m *MemoryCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.get(key)
+	if !ok {
+		return -2, nil
+	}
+	if item.expiresAt.IsZero() {
+		return -1, nil
+	}
+	return item.expiresAt.Sub(m.now()), nil
+}
+
+// Incr 递增
+func (m *MemoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	return m.IncrBy(ctx, key, 1)
+}
+
+// IncrBy 按指定值递增，键不存在时从 0 开始且不过期
+func (m *MemoryCache) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.get(key)
+	if !ok {
+		item = &memoryItem{value: "0"}
+		m.items[key] = item
+	}
+
+	current, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment: value is not an integer")
+	}
+
+	current += value
+	item.value = strconv.FormatInt(current, 10)
+	return current, nil
+}
+
+// Decr 递减
+func (m *MemoryCache) Decr(ctx context.Context, key string) (int64, error) {
+	return m.IncrBy(ctx, key, -1)
+}
+
+// DecrBy 按指定值递减
+func (m *MemoryCache) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return m.IncrBy(ctx, key, -value)
+}
+
+// MGet 批量获取，不存在的键返回 nil
+func (m *MemoryCache) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if item, ok := m.get(key); ok {
+			result[i] = item.value
+		}
+	}
+	return result, nil
+}
+
+// MSet 批量设置，与 Redis MSET 一致不设置过期时间
+func (m *MemoryCache) MSet(ctx context.Context, pairs ...interface{}) error {
+	if len(pairs)%2 != 0 {
+		return fmt.Errorf("pairs must be even number")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return fmt.Errorf("key must be string")
+		}
+		data, err := m.opts.Serializer.Serialize(pairs[i+1])
+		if err != nil {
+			return fmt.Errorf("failed to serialize value: %w", err)
+		}
+		m.items[key] = &memoryItem{value: string(data)}
+	}
+	return nil
+}
+
+// FlushAll 清空所有缓存
+func (m *MemoryCache) FlushAll(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items = make(map[string]*memoryItem)
+	return nil
+}
+
+// Close 关闭缓存
+func (m *MemoryCache) Close() error {
+	return nil
+}
+
+// Ping 健康检查
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
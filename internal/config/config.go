@@ -3,11 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
+
+	"pulse/internal/pkg/httpclient"
 )
 
 // Config 应用配置结构
@@ -36,6 +40,9 @@ type Config struct {
 	// 文件存储配置
 	FileStorage FileStorageConfig `mapstructure:",squash"`
 
+	// 知识库配置
+	Knowledge KnowledgeConfig `mapstructure:",squash"`
+
 	// 安全配置
 	Security SecurityConfig `mapstructure:",squash"`
 
@@ -44,6 +51,83 @@ type Config struct {
 
 	// 健康检查配置
 	HealthCheck HealthCheckConfig `mapstructure:",squash"`
+
+	// 平台自监控配置
+	SelfMonitor SelfMonitorConfig `mapstructure:",squash"`
+
+	// 规则自动修复动作配置
+	Remediation RemediationConfig `mapstructure:",squash"`
+
+	// 聊天平台命令配置
+	ChatOps ChatOpsConfig `mapstructure:",squash"`
+
+	// 外部 ITSM 工单同步配置
+	ITSM ITSMConfig `mapstructure:",squash"`
+
+	// 心跳监控配置
+	Heartbeat HeartbeatConfig `mapstructure:",squash"`
+
+	// Kubernetes 数据源采集配置
+	Kubernetes KubernetesConfig `mapstructure:",squash"`
+	// SLO 错误预算计算配置
+	SLO SLOConfig `mapstructure:",squash"`
+	// 外部数据源和 Webhook 调用的熔断配置
+	CircuitBreaker CircuitBreakerConfig `mapstructure:",squash"`
+	// 外部集成 HTTP 客户端的默认超时和重试策略
+	HTTPClient HTTPClientConfig `mapstructure:",squash"`
+	// 告警自动解决配置
+	AutoResolve AutoResolveConfig `mapstructure:",squash"`
+	// 多语言消息目录配置
+	I18n I18nConfig `mapstructure:",squash"`
+	// 标签治理任务配置
+	TagGovernance TagGovernanceConfig `mapstructure:",squash"`
+	// 数据保留和归档配置
+	Retention RetentionConfig `mapstructure:",squash"`
+	// 交班报告配置
+	Handover HandoverConfig `mapstructure:",squash"`
+	// 站内通知配置
+	Inbox InboxConfig `mapstructure:",squash"`
+	// 变更事件配置
+	ChangeEvent ChangeEventConfig `mapstructure:",squash"`
+	// 出站 Webhook 订阅配置
+	WebhookSubscription WebhookSubscriptionConfig `mapstructure:",squash"`
+	// 工单导入配置
+	TicketImport TicketImportConfig `mapstructure:",squash"`
+	// 告警统计配置
+	AlertStats AlertStatsConfig `mapstructure:",squash"`
+	// 告警标签基数配置
+	LabelCardinality LabelCardinalityConfig `mapstructure:",squash"`
+	// 告警标签自动补全配置
+	LabelAutocomplete LabelAutocompleteConfig `mapstructure:",squash"`
+	// 工单满意度调查配置
+	TicketSurvey TicketSurveyConfig `mapstructure:",squash"`
+	// 工单SLA暂停配置
+	TicketSLA TicketSLAConfig `mapstructure:",squash"`
+	// 敏感操作审批配置
+	Approval ApprovalConfig `mapstructure:",squash"`
+	// 告警接入载荷校验配置
+	IngestSchema IngestSchemaConfig `mapstructure:",squash"`
+	// 多实例部署的后台任务锁配置
+	JobLock JobLockConfig `mapstructure:",squash"`
+	// 异步任务配置
+	AsyncTask AsyncTaskConfig `mapstructure:",squash"`
+	// 按路由类别的请求处理时限配置
+	RequestTimeout RequestTimeoutConfig `mapstructure:",squash"`
+	// 数据源查询结果缓存配置
+	QueryCache QueryCacheConfig `mapstructure:",squash"`
+	// 指标远程写入配置
+	RemoteWrite RemoteWriteConfig `mapstructure:",squash"`
+	// 公开状态页配置
+	StatusPage StatusPageConfig `mapstructure:",squash"`
+	// 服务健康度配置
+	ServiceHealth ServiceHealthConfig `mapstructure:",squash"`
+	// 规则预测配置
+	RuleForecast RuleForecastConfig `mapstructure:",squash"`
+	// 告警分诊评分配置
+	AlertTriage AlertTriageConfig `mapstructure:",squash"`
+
+	// 密钥来源配置
+	Secrets SecretsConfig `mapstructure:",squash"`
 }
 
 // AppConfig 应用基本配置
@@ -60,13 +144,25 @@ type AppConfig struct {
 	LogLevel  string `mapstructure:"LOG_LEVEL" validate:"oneof=debug info warn error"`
 	LogFormat string `mapstructure:"LOG_FORMAT" validate:"oneof=json text"`
 
-	// 性能分析配置
+	// 性能分析配置：开启后在管理员路由 /api/v1/admin/debug 下提供 pprof 和运行时诊断，
+	// PProfPort 不为 0 时另在 127.0.0.1 的该端口提供不经认证的诊断端点
 	PProfEnabled bool `mapstructure:"PPROF_ENABLED"`
-	PProfPort    int  `mapstructure:"PPROF_PORT" validate:"min=1,max=65535"`
+	PProfPort    int  `mapstructure:"PPROF_PORT" validate:"min=0,max=65535"`
 
 	// API 文档配置
 	APIDocsEnabled bool   `mapstructure:"API_DOCS_ENABLED"`
 	APIDocsPath    string `mapstructure:"API_DOCS_PATH"`
+
+	// 前端页面配置：开启后由服务端直接提供编译时内嵌的前端页面，前端未构建时忽略
+	WebUIEnabled bool `mapstructure:"WEB_UI_ENABLED"`
+
+	// 功能开关，逗号分隔的已启用功能名称，支持热更新
+	FeatureFlags []string `mapstructure:"FEATURE_FLAGS"`
+
+	// 维护模式：开启后拒绝写请求，告警接入请求暂存到队列，关闭后补处理；支持热更新，也可通过管理接口切换。
+	// MaintenanceRetryAfter 为维护期间返回给客户端的 Retry-After
+	MaintenanceMode       bool          `mapstructure:"MAINTENANCE_MODE"`
+	MaintenanceRetryAfter time.Duration `mapstructure:"MAINTENANCE_RETRY_AFTER"`
 }
 
 // DatabaseConfig 数据库配置
@@ -81,9 +177,25 @@ type DatabaseConfig struct {
 	MaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
 	ConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
 	ConnMaxIdleTime time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
-	MigrationPath   string        `mapstructure:"DB_MIGRATION_PATH"`
+	MigrationPath   string        `mapstructure:"DB_MIGRATION_PATH"` // 为空时使用编译进二进制的迁移文件
 	MigrationTable  string        `mapstructure:"DB_MIGRATION_TABLE"`
+	// MigrationLockTimeout 等待其他实例完成迁移的最长时间，多个副本同时启动时只有一个执行迁移
+	MigrationLockTimeout time.Duration `mapstructure:"DB_MIGRATION_LOCK_TIMEOUT"`
 	AutoMigrate     bool          `mapstructure:"DB_AUTO_MIGRATE"`
+
+	// 只读副本，列表、统计等只读查询优先路由到副本，副本不可用时回退主库
+	ReplicaDSNs                []string      `mapstructure:"DB_REPLICA_DSNS"`
+	ReplicaHealthCheckInterval time.Duration `mapstructure:"DB_REPLICA_HEALTH_CHECK_INTERVAL"`
+
+	// 慢查询日志，执行时间超过阈值的语句以 warn 级别记录（参数值脱敏），并计入 /health/metrics 的慢查询计数，负数表示关闭
+	SlowQueryThreshold time.Duration `mapstructure:"DB_SLOW_QUERY_THRESHOLD"`
+}
+
+// databasePoolDefaults 各环境的连接池默认值，未显式配置的连接池参数按 APP_ENV 取值
+var databasePoolDefaults = map[string]DatabaseConfig{
+	"development": {MaxOpenConns: 10, MaxIdleConns: 2, ConnMaxLifetime: 5 * time.Minute, ConnMaxIdleTime: time.Minute},
+	"staging":     {MaxOpenConns: 25, MaxIdleConns: 5, ConnMaxLifetime: 15 * time.Minute, ConnMaxIdleTime: 5 * time.Minute},
+	"production":  {MaxOpenConns: 50, MaxIdleConns: 25, ConnMaxLifetime: 30 * time.Minute, ConnMaxIdleTime: 10 * time.Minute},
 }
 
 // RedisConfig Redis 配置
@@ -108,6 +220,16 @@ type AlertConfig struct {
 	EvaluationInterval       time.Duration `mapstructure:"ALERT_EVALUATION_INTERVAL"`
 	HistoryRetentionDays     int           `mapstructure:"ALERT_HISTORY_RETENTION_DAYS" validate:"min=1"`
 	MaxConcurrentEvaluations int           `mapstructure:"ALERT_MAX_CONCURRENT_EVALUATIONS" validate:"min=1"`
+	// SnoozeCleanupInterval 清理到期的用户告警暂停的周期
+	SnoozeCleanupInterval time.Duration `mapstructure:"ALERT_SNOOZE_CLEANUP_INTERVAL"`
+	// RetentionDays 告警保留天数，过期的月分区没有未解决告警时整体删除
+	RetentionDays int `mapstructure:"ALERT_RETENTION_DAYS" validate:"min=0"`
+	// PartitionCheckInterval 创建后续月分区和清理过期分区的周期
+	PartitionCheckInterval time.Duration `mapstructure:"ALERT_PARTITION_CHECK_INTERVAL"`
+	// PartitionPremakeMonths 提前创建的月分区数量
+	PartitionPremakeMonths int `mapstructure:"ALERT_PARTITION_PREMAKE_MONTHS" validate:"min=0"`
+	// OccurrenceSampleInterval 未解决告警重复触发时记入告警历史的采样间隔，间隔内的重复触发只累加次数
+	OccurrenceSampleInterval time.Duration `mapstructure:"ALERT_OCCURRENCE_SAMPLE_INTERVAL"`
 }
 
 // NotificationConfig 通知配置
@@ -123,6 +245,83 @@ type NotificationConfig struct {
 
 	// Slack 配置
 	Slack SlackConfig `mapstructure:",squash"`
+
+	// DigestInterval 检查并汇总发送到期的延迟通知的周期
+	DigestInterval time.Duration `mapstructure:"NOTIFICATION_DIGEST_INTERVAL"`
+
+	// RetryPolicies 按通知渠道覆盖 HTTP 客户端的默认超时和重试策略，格式为 渠道.字段=值，多个以逗号分隔，
+	// 如 dingtalk.timeout=10s,dingtalk.max_attempts=5,webhook.retryable_status_codes=429|503
+	RetryPolicies string `mapstructure:"NOTIFICATION_RETRY_POLICIES"`
+
+	// ConnectionOptions 按通知渠道覆盖全局代理和证书校验选项，格式同 RetryPolicies，
+	// 字段为 proxy_url、disable_proxy、ca_file、insecure_skip_verify，如 slack.proxy_url=http://proxy:3128
+	ConnectionOptions string `mapstructure:"NOTIFICATION_CONNECTION_OPTIONS"`
+
+	// TestModeChannels 处于测试模式的通知渠道，如 dingtalk、slack。测试模式下的通知照常记录投递但不实际发送，
+	// 用于接入新渠道时验证通知规则而不打扰接收人
+	TestModeChannels []string `mapstructure:"NOTIFICATION_TEST_MODE_CHANNELS"`
+
+	// 告警通知中的一键操作链接
+	ActionLink ActionLinkConfig `mapstructure:",squash"`
+
+	// 移动端推送
+	Push PushConfig `mapstructure:",squash"`
+}
+
+// PushConfig 移动端推送配置，FCM 和 APNs 分别在配置了凭据后启用
+type PushConfig struct {
+	// FCMCredentialsFile Firebase 服务账号 JSON 密钥文件路径
+	FCMCredentialsFile string `mapstructure:"PUSH_FCM_CREDENTIALS_FILE"`
+	// FCMProjectID Firebase 项目 ID，为空时使用服务账号所属项目
+	FCMProjectID string `mapstructure:"PUSH_FCM_PROJECT_ID"`
+	// APNsKeyFile APNs 认证密钥（.p8）文件路径
+	APNsKeyFile string `mapstructure:"PUSH_APNS_KEY_FILE"`
+	APNsKeyID   string `mapstructure:"PUSH_APNS_KEY_ID"`
+	APNsTeamID  string `mapstructure:"PUSH_APNS_TEAM_ID"`
+	// APNsTopic 应用的 Bundle ID
+	APNsTopic string `mapstructure:"PUSH_APNS_TOPIC"`
+	// APNsProduction 使用生产环境，否则使用沙盒环境
+	APNsProduction bool `mapstructure:"PUSH_APNS_PRODUCTION"`
+	// DefaultSeverities 用户未设置推送偏好时推送的告警级别，多个以逗号分隔
+	DefaultSeverities string `mapstructure:"PUSH_DEFAULT_SEVERITIES"`
+}
+
+// ActionLinkConfig 告警通知一键操作链接配置，链接携带签名令牌，接收人无需登录即可确认、解决或暂停告警，
+// 每个链接只能使用一次，过期后失效
+type ActionLinkConfig struct {
+	// BaseURL 链接指向的平台外部访问地址，如 https://pulse.example.com，为空时不生成链接
+	BaseURL string `mapstructure:"NOTIFICATION_ACTION_LINK_BASE_URL" validate:"omitempty,url"`
+	// Secret 链接签名密钥，配置 BaseURL 时必填，且不能与 JWT 密钥相同
+	Secret string `mapstructure:"NOTIFICATION_ACTION_LINK_SECRET"`
+	// TTL 链接有效期
+	TTL time.Duration `mapstructure:"NOTIFICATION_ACTION_LINK_TTL"`
+	// SnoozeDuration 通过暂停链接暂停告警的时长
+	SnoozeDuration time.Duration `mapstructure:"NOTIFICATION_ACTION_LINK_SNOOZE_DURATION"`
+}
+
+// ParseRetryPolicies 解析按通知渠道配置的重试策略
+func (c NotificationConfig) ParseRetryPolicies() (map[string]httpclient.Policy, error) {
+	policies := make(map[string]httpclient.Policy)
+	for _, item := range strings.Split(c.RetryPolicies, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		channel, field, hasField := strings.Cut(strings.TrimSpace(key), ".")
+		if !ok || !hasField || channel == "" {
+			return nil, fmt.Errorf("NOTIFICATION_RETRY_POLICIES 的配置项 %q 格式应为 渠道.字段=值", item)
+		}
+		policy := policies[channel]
+		if err := policy.Set(field, value); err != nil {
+			return nil, fmt.Errorf("NOTIFICATION_RETRY_POLICIES 中渠道 %s 的配置无效: %w", channel, err)
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("NOTIFICATION_RETRY_POLICIES 中渠道 %s 的配置无效: %w", channel, err)
+		}
+		policies[channel] = policy
+	}
+	return policies, nil
 }
 
 // SMTPConfig 邮件配置
@@ -187,8 +386,43 @@ type InfluxDBConfig struct {
 type FileStorageConfig struct {
 	Type      string `mapstructure:"FILE_STORAGE_TYPE" validate:"oneof=local s3 oss"`
 	LocalPath string `mapstructure:"FILE_STORAGE_LOCAL_PATH"`
+	// PublicURL 附件公开访问地址前缀，为空时根据存储类型生成
+	PublicURL string `mapstructure:"FILE_STORAGE_PUBLIC_URL"`
 	S3        S3Config `mapstructure:",squash"`
 	OSS       OSSConfig `mapstructure:",squash"`
+
+	// 附件病毒扫描配置
+	Scan AttachmentScanConfig `mapstructure:",squash"`
+}
+
+// AttachmentScanConfig 附件病毒扫描配置
+type AttachmentScanConfig struct {
+	Enabled        bool          `mapstructure:"ATTACHMENT_SCAN_ENABLED"`
+	Scanner        string        `mapstructure:"ATTACHMENT_SCANNER" validate:"omitempty,oneof=clamav http"`
+	ClamAVAddress  string        `mapstructure:"CLAMAV_ADDRESS"`
+	HTTPURL        string        `mapstructure:"ATTACHMENT_SCAN_HTTP_URL" validate:"omitempty,url"`
+	HTTPToken      string        `mapstructure:"ATTACHMENT_SCAN_HTTP_TOKEN"`
+	Timeout        time.Duration `mapstructure:"ATTACHMENT_SCAN_TIMEOUT"`
+	Interval       time.Duration `mapstructure:"ATTACHMENT_SCAN_INTERVAL"`
+	BatchSize      int           `mapstructure:"ATTACHMENT_SCAN_BATCH_SIZE"`
+	// MaxAttempts 扫描器出错（超时、服务不可用等）时的最多扫描次数，达到后标记为扫描失败
+	MaxAttempts int `mapstructure:"ATTACHMENT_SCAN_MAX_ATTEMPTS"`
+	// RetryBackoff 扫描器出错后的首次重试等待时间，之后按指数增长，不超过 MaxRetryBackoff
+	RetryBackoff    time.Duration `mapstructure:"ATTACHMENT_SCAN_RETRY_BACKOFF"`
+	MaxRetryBackoff time.Duration `mapstructure:"ATTACHMENT_SCAN_MAX_RETRY_BACKOFF"`
+	QuarantinePath string        `mapstructure:"ATTACHMENT_QUARANTINE_PATH"`
+}
+
+// KnowledgeConfig 知识库配置
+type KnowledgeConfig struct {
+	// SchedulerInterval 定时发布和过期提醒的检查间隔
+	SchedulerInterval time.Duration `mapstructure:"KNOWLEDGE_SCHEDULER_INTERVAL"`
+	// ExpiryWarningDays 过期前多少天提醒作者复查
+	ExpiryWarningDays int `mapstructure:"KNOWLEDGE_EXPIRY_WARNING_DAYS" validate:"gte=0"`
+	// QualityFlagThreshold 工单解决时评价的质量分低于该值的文章标记为需要更新
+	QualityFlagThreshold float64 `mapstructure:"KNOWLEDGE_QUALITY_FLAG_THRESHOLD" validate:"gte=0,lte=1"`
+	// QualityMinFeedback 评价数达到该值后才标记文章需要更新
+	QualityMinFeedback int `mapstructure:"KNOWLEDGE_QUALITY_MIN_FEEDBACK" validate:"gte=0"`
 }
 
 // S3Config S3 配置
@@ -220,10 +454,35 @@ type SecurityConfig struct {
 	RateLimitEnabled bool `mapstructure:"RATE_LIMIT_ENABLED"`
 	RateLimitRPS     int  `mapstructure:"RATE_LIMIT_RPS" validate:"min=1"`
 	RateLimitBurst   int  `mapstructure:"RATE_LIMIT_BURST" validate:"min=1"`
+	// Webhook 接入的独立限流，每分钟请求数
+	RateLimitWebhookRPM int `mapstructure:"RATE_LIMIT_WEBHOOK_RPM" validate:"min=1"`
 
 	// API Key 配置
 	APIKeyEnabled bool   `mapstructure:"API_KEY_ENABLED"`
 	APIKeyHeader  string `mapstructure:"API_KEY_HEADER"`
+	// APIKeys 机器访问使用的 API Key，格式为 "用户ID:密钥:权限范围|权限范围"，多个以逗号分隔，
+	// 如 svc-forwarder:k1:alerts:ingest,svc-bot:k2:knowledge:read。未配置权限范围时拥有全部权限
+	APIKeys string `mapstructure:"API_KEYS"`
+
+	// 告警接入端点的来源IP限制，条目为 CIDR 或单个IP，黑名单优先
+	IngestAllowedIPs []string `mapstructure:"INGEST_ALLOWED_IPS"`
+	IngestDeniedIPs  []string `mapstructure:"INGEST_DENIED_IPS"`
+
+	// 旧版敏感字段加密密钥，未配置主密钥列表时由其派生主密钥，配置主密钥列表后仅用于解密历史数据
+	// 与主密钥列表至少配置一项，JWT 密钥只用于解密早期版本加密的历史数据
+	EncryptionKey string `mapstructure:"ENCRYPTION_KEY"`
+
+	// 信封加密主密钥列表，格式为 "v1:密钥,v2:密钥"，轮换时追加新版本并切换当前版本
+	// 当前版本为空时使用列表中的最后一个
+	EncryptionKeys      string `mapstructure:"ENCRYPTION_KEYS"`
+	EncryptionActiveKey string `mapstructure:"ENCRYPTION_ACTIVE_KEY"`
+
+	// 盲索引密钥，用于按邮箱、手机号查询加密存储的用户，为空时沿用旧版密钥
+	// 修改后需重新执行密钥轮换以重建索引
+	EncryptionIndexKey string `mapstructure:"ENCRYPTION_INDEX_KEY"`
+
+	// 是否加密存储用户邮箱和手机号，关闭后已加密的数据仍可读取
+	EncryptUserPII bool `mapstructure:"ENCRYPT_USER_PII"`
 }
 
 // PerformanceConfig 性能配置
@@ -237,6 +496,9 @@ type PerformanceConfig struct {
 	// 工作池配置
 	WorkerPoolSize  int `mapstructure:"WORKER_POOL_SIZE" validate:"min=1"`
 	QueueBufferSize int `mapstructure:"QUEUE_BUFFER_SIZE" validate:"min=1"`
+
+	// 是否在服务进程内启动后台 Worker
+	WorkersEnabled bool `mapstructure:"WORKERS_ENABLED"`
 }
 
 // HealthCheckConfig 健康检查配置
@@ -244,6 +506,531 @@ type HealthCheckConfig struct {
 	Enabled  bool          `mapstructure:"HEALTH_CHECK_ENABLED"`
 	Interval time.Duration `mapstructure:"HEALTH_CHECK_INTERVAL"`
 	Timeout  time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+
+	// 消息队列最早待消费消息的等待时长超过该值时标记为降级
+	QueueMaxLag time.Duration `mapstructure:"HEALTH_CHECK_QUEUE_MAX_LAG"`
+}
+
+// SelfMonitorConfig 平台自监控配置，内置规则触发时生成来源为 self 的告警
+type SelfMonitorConfig struct {
+	Enabled  bool          `mapstructure:"SELF_MONITOR_ENABLED"`
+	Interval time.Duration `mapstructure:"SELF_MONITOR_INTERVAL"`
+
+	// 单个检查周期内告警接入失败次数达到该值时触发告警
+	IngestFailureThreshold int `mapstructure:"SELF_MONITOR_INGEST_FAILURE_THRESHOLD"`
+
+	// 待评估规则数量达到该值时触发告警
+	EvaluationBacklogThreshold int `mapstructure:"SELF_MONITOR_EVALUATION_BACKLOG_THRESHOLD"`
+
+	// 任一规则连续错过该次数的评估，或逾期达到该数量的评估间隔仍未评估时触发告警
+	EvaluationMissedThreshold int `mapstructure:"SELF_MONITOR_EVALUATION_MISSED_THRESHOLD"`
+
+	// 统计窗口内通知失败率达到该值时触发告警，样本数不足时不评估
+	NotificationFailureRate       float64       `mapstructure:"SELF_MONITOR_NOTIFICATION_FAILURE_RATE"`
+	NotificationFailureWindow     time.Duration `mapstructure:"SELF_MONITOR_NOTIFICATION_FAILURE_WINDOW"`
+	NotificationFailureMinSamples int           `mapstructure:"SELF_MONITOR_NOTIFICATION_FAILURE_MIN_SAMPLES"`
+}
+
+// RemediationConfig 规则自动修复动作配置，Worker 按周期为新告警安排并执行规则上的修复动作
+type RemediationConfig struct {
+	Enabled   bool          `mapstructure:"REMEDIATION_ENABLED"`
+	Interval  time.Duration `mapstructure:"REMEDIATION_INTERVAL"`
+	Timeout   time.Duration `mapstructure:"REMEDIATION_TIMEOUT"`
+	BatchSize int           `mapstructure:"REMEDIATION_BATCH_SIZE"`
+
+	// 只为该时间窗口内产生的告警安排修复动作，避免启用后对历史告警补跑
+	Lookback time.Duration `mapstructure:"REMEDIATION_LOOKBACK"`
+
+	// Ansible Tower/AWX
+	AWXURL          string        `mapstructure:"AWX_URL"`
+	AWXToken        string        `mapstructure:"AWX_TOKEN"`
+	AWXPollInterval time.Duration `mapstructure:"AWX_POLL_INTERVAL"`
+
+	// SaltStack salt-api
+	SaltAPIURL   string `mapstructure:"SALT_API_URL"`
+	SaltUsername string `mapstructure:"SALT_USERNAME"`
+	SaltPassword string `mapstructure:"SALT_PASSWORD"`
+	SaltEauth    string `mapstructure:"SALT_EAUTH"`
+}
+
+// ChatOpsConfig 聊天平台命令配置，接收 Slack 斜杠命令和钉钉机器人回调，按签名密钥校验请求来源
+type ChatOpsConfig struct {
+	Enabled            bool   `mapstructure:"CHATOPS_ENABLED"`
+	SlackSigningSecret string `mapstructure:"SLACK_SIGNING_SECRET"`
+	DingTalkAppSecret  string `mapstructure:"DINGTALK_APP_SECRET"`
+
+	// /silence 命令允许的最长静默时长
+	MaxSilence time.Duration `mapstructure:"CHATOPS_MAX_SILENCE"`
+}
+
+// ITSMConfig 外部 ITSM 工单同步配置，Worker 按周期将平台工单推送到 Jira、ServiceNow 等系统
+type ITSMConfig struct {
+	Enabled     bool          `mapstructure:"ITSM_SYNC_ENABLED"`
+	Interval    time.Duration `mapstructure:"ITSM_SYNC_INTERVAL"`
+	BatchSize   int           `mapstructure:"ITSM_SYNC_BATCH_SIZE"`
+	HTTPTimeout time.Duration `mapstructure:"ITSM_HTTP_TIMEOUT"`
+}
+
+// HeartbeatConfig 心跳监控配置，Worker 按周期检查超时未上报的心跳并触发告警
+type HeartbeatConfig struct {
+	CheckInterval time.Duration `mapstructure:"HEARTBEAT_CHECK_INTERVAL"`
+	BatchSize     int           `mapstructure:"HEARTBEAT_BATCH_SIZE"`
+}
+
+// KubernetesConfig Kubernetes 数据源采集配置，Worker 按周期将集群的 Warning 事件和异常状况同步为告警
+type KubernetesConfig struct {
+	SyncInterval     time.Duration `mapstructure:"KUBERNETES_SYNC_INTERVAL"`
+	EventWindow      time.Duration `mapstructure:"KUBERNETES_EVENT_WINDOW"`
+	PodNotReadyAfter time.Duration `mapstructure:"KUBERNETES_POD_NOT_READY_AFTER"`
+	HTTPTimeout      time.Duration `mapstructure:"KUBERNETES_HTTP_TIMEOUT"`
+}
+
+// SLOConfig SLO 错误预算计算配置，Worker 按周期通过 Prometheus 数据源计算每个 SLO 的剩余错误预算
+type SLOConfig struct {
+	EvaluationInterval time.Duration `mapstructure:"SLO_EVALUATION_INTERVAL"`
+	QueryTimeout       time.Duration `mapstructure:"SLO_QUERY_TIMEOUT"`
+	// RuleEvaluationInterval 自动生成的消耗速率告警规则的评估间隔
+	RuleEvaluationInterval time.Duration `mapstructure:"SLO_RULE_EVALUATION_INTERVAL"`
+}
+
+// CircuitBreakerConfig 熔断配置，每个数据源和 Webhook 使用独立的熔断器，
+// 健康检查、查询和 Webhook 投递连续失败后暂停调用，避免挂起的远端占满 Worker 协程
+type CircuitBreakerConfig struct {
+	// FailureThreshold 连续失败多少次后熔断，0 表示不熔断
+	FailureThreshold int `mapstructure:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" validate:"gte=0"`
+	// OpenTimeout 熔断后多久放行探测调用
+	OpenTimeout time.Duration `mapstructure:"CIRCUIT_BREAKER_OPEN_TIMEOUT"`
+	// HalfOpenMaxCalls 探测阶段同时放行的调用数
+	HalfOpenMaxCalls int `mapstructure:"CIRCUIT_BREAKER_HALF_OPEN_MAX_CALLS" validate:"gte=0"`
+	// CallTimeout 单次调用的超时时间
+	CallTimeout time.Duration `mapstructure:"CIRCUIT_BREAKER_CALL_TIMEOUT"`
+}
+
+// ParseConnectionOptions 解析按通知渠道配置的代理和证书校验选项
+func (c NotificationConfig) ParseConnectionOptions() (map[string]httpclient.Options, error) {
+	options := make(map[string]httpclient.Options)
+	for _, item := range strings.Split(c.ConnectionOptions, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		channel, field, hasField := strings.Cut(strings.TrimSpace(key), ".")
+		if !ok || !hasField || channel == "" {
+			return nil, fmt.Errorf("NOTIFICATION_CONNECTION_OPTIONS 的配置项格式应为 渠道.字段=值")
+		}
+		channelOptions := options[channel]
+		if err := channelOptions.Set(field, value); err != nil {
+			return nil, fmt.Errorf("NOTIFICATION_CONNECTION_OPTIONS 中渠道 %s 的配置无效: %w", channel, err)
+		}
+		options[channel] = channelOptions
+	}
+	return options, nil
+}
+
+// HTTPClientConfig 外部集成 HTTP 客户端的默认超时和重试策略，
+// 数据源可在连接配置的 retry_policy 中、通知渠道可通过 NOTIFICATION_RETRY_POLICIES 单独覆盖
+type HTTPClientConfig struct {
+	// Timeout 单次请求的超时时间
+	Timeout time.Duration `mapstructure:"HTTP_CLIENT_TIMEOUT"`
+	// MaxAttempts 最大尝试次数（含首次请求），1 表示不重试
+	MaxAttempts int `mapstructure:"HTTP_CLIENT_MAX_ATTEMPTS" validate:"gte=0,lte=10"`
+	// InitialBackoff 首次重试前的等待时间，之后每次翻倍，不超过 MaxBackoff
+	InitialBackoff time.Duration `mapstructure:"HTTP_CLIENT_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `mapstructure:"HTTP_CLIENT_MAX_BACKOFF"`
+	// Jitter 重试等待时间随机减少的最大比例
+	Jitter float64 `mapstructure:"HTTP_CLIENT_JITTER" validate:"gte=0,lte=1"`
+	// RetryableStatusCodes 需要重试的响应状态码，逗号分隔，连接错误和超时总是重试
+	RetryableStatusCodes string `mapstructure:"HTTP_CLIENT_RETRYABLE_STATUS_CODES"`
+
+	// ProxyURL 出站请求使用的代理，为空时使用 HTTP_PROXY、HTTPS_PROXY 和 NO_PROXY 环境变量
+	ProxyURL string `mapstructure:"HTTP_CLIENT_PROXY_URL"`
+	// NoProxy 不经过 ProxyURL 的主机，格式同 NO_PROXY
+	NoProxy string `mapstructure:"HTTP_CLIENT_NO_PROXY"`
+	// CAFile 额外信任的 CA 证书文件，多个以逗号分隔，用于校验使用私有 CA 签发证书的内部服务
+	CAFile string `mapstructure:"HTTP_CLIENT_CA_FILE"`
+	// InsecureSkipVerify 跳过所有出站请求的服务端证书校验，仅用于测试环境
+	InsecureSkipVerify bool `mapstructure:"HTTP_CLIENT_INSECURE_SKIP_VERIFY"`
+}
+
+// Options 转换为 HTTP 客户端的全局连接选项
+func (c HTTPClientConfig) Options() httpclient.Options {
+	return httpclient.Options{
+		ProxyURL:           c.ProxyURL,
+		NoProxy:            c.NoProxy,
+		CAFile:             c.CAFile,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+}
+
+// Policy 转换为 HTTP 客户端的默认策略
+func (c HTTPClientConfig) Policy() (httpclient.Policy, error) {
+	codes, err := httpclient.ParseStatusCodes(c.RetryableStatusCodes, ",")
+	if err != nil {
+		return httpclient.Policy{}, fmt.Errorf("HTTP_CLIENT_RETRYABLE_STATUS_CODES 无效: %w", err)
+	}
+	policy := httpclient.Policy{
+		Timeout:              c.Timeout,
+		MaxAttempts:          c.MaxAttempts,
+		InitialBackoff:       c.InitialBackoff,
+		MaxBackoff:           c.MaxBackoff,
+		Jitter:               c.Jitter,
+		RetryableStatusCodes: codes,
+	}
+	if err := policy.Validate(); err != nil {
+		return httpclient.Policy{}, err
+	}
+	return policy, nil
+}
+
+// NewHTTPClientFactory 根据默认策略、全局连接选项和通知渠道的配置创建外部集成共用的 HTTP 客户端工厂
+func (c *Config) NewHTTPClientFactory() (*httpclient.Factory, error) {
+	defaults, err := c.HTTPClient.Policy()
+	if err != nil {
+		return nil, err
+	}
+	channelPolicies, err := c.Notification.ParseRetryPolicies()
+	if err != nil {
+		return nil, err
+	}
+	channelOptions, err := c.Notification.ParseConnectionOptions()
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.NewFactory(defaults, c.HTTPClient.Options(), channelPolicies, channelOptions)
+}
+
+// AutoResolveConfig 告警自动解决配置，Worker 按周期解决超过 TTL 未再次触发的告警，
+// 避免从不发送恢复通知的来源产生的告警一直处于触发状态
+type AutoResolveConfig struct {
+	CheckInterval time.Duration `mapstructure:"AUTO_RESOLVE_CHECK_INTERVAL"`
+	BatchSize     int           `mapstructure:"AUTO_RESOLVE_BATCH_SIZE" validate:"gte=0"`
+	// DefaultTTL 未单独配置 TTL 的来源使用的 TTL，为 0 时不自动解决；心跳、Kubernetes 和自监控告警由平台检测恢复，不使用默认 TTL
+	DefaultTTL time.Duration `mapstructure:"AUTO_RESOLVE_DEFAULT_TTL"`
+	// SourceTTLs 按告警来源配置的 TTL，格式为 source=ttl，多个以逗号分隔，如 custom=4h,zabbix=24h
+	SourceTTLs string `mapstructure:"AUTO_RESOLVE_SOURCE_TTLS"`
+}
+
+// APIKey 配置文件中的 API Key，Scopes 为空时拥有全部权限
+type APIKey struct {
+	UserID string
+	Key    string
+	Scopes []string
+}
+
+// ParseAPIKeys 解析机器访问使用的 API Key，同一密钥不能重复配置
+func (c SecurityConfig) ParseAPIKeys() ([]APIKey, error) {
+	var keys []APIKey
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(c.APIKeys, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		userID, rest, _ := strings.Cut(item, ":")
+		key, scopes, _ := strings.Cut(rest, ":")
+		userID, key = strings.TrimSpace(userID), strings.TrimSpace(key)
+		if userID == "" || key == "" {
+			return nil, fmt.Errorf("API_KEYS 的配置项格式应为 用户ID:密钥:权限范围|权限范围")
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("API_KEYS 中用户 %s 的密钥重复", userID)
+		}
+		seen[key] = true
+
+		apiKey := APIKey{UserID: userID, Key: key}
+		for _, scope := range strings.Split(scopes, "|") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				apiKey.Scopes = append(apiKey.Scopes, scope)
+			}
+		}
+		keys = append(keys, apiKey)
+	}
+	return keys, nil
+}
+
+// ParseSourceTTLs 解析按告警来源配置的 TTL
+func (c AutoResolveConfig) ParseSourceTTLs() (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for _, item := range strings.Split(c.SourceTTLs, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		source, raw, ok := strings.Cut(item, "=")
+		source = strings.TrimSpace(source)
+		if !ok || source == "" {
+			return nil, fmt.Errorf("AUTO_RESOLVE_SOURCE_TTLS 的配置项 %q 格式应为 source=ttl", item)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("AUTO_RESOLVE_SOURCE_TTLS 中来源 %s 的 TTL %q 无效", source, raw)
+		}
+		ttls[source] = ttl
+	}
+	return ttls, nil
+}
+
+// I18nConfig 多语言配置，内置 zh-CN 和 en-US 消息目录
+type I18nConfig struct {
+	// CatalogDir 额外消息目录所在目录，其中的 <语言>.json 覆盖内置消息或增加新语言，为空时只使用内置消息目录
+	CatalogDir string `mapstructure:"I18N_CATALOG_DIR"`
+}
+
+// TagGovernanceConfig 标签治理配置，重命名、合并和删除标签的任务由 Worker 分批执行
+type TagGovernanceConfig struct {
+	JobCheckInterval time.Duration `mapstructure:"TAG_JOB_CHECK_INTERVAL"`
+	// BatchSize 每批更新的资源数量
+	BatchSize int `mapstructure:"TAG_JOB_BATCH_SIZE" validate:"gte=0"`
+}
+
+// RetentionConfig 数据保留配置，告警和告警历史的保留天数见 AlertConfig
+type RetentionConfig struct {
+	// ArchiveEnabled 清理前将过期记录导出为 gzip 压缩的 NDJSON 保存到对象存储
+	ArchiveEnabled bool `mapstructure:"RETENTION_ARCHIVE_ENABLED"`
+	// ArchiveInterval 归档和清理过期记录的周期
+	ArchiveInterval time.Duration `mapstructure:"RETENTION_ARCHIVE_INTERVAL"`
+	// ArchivePrefix 归档文件在对象存储中的键前缀
+	ArchivePrefix string `mapstructure:"RETENTION_ARCHIVE_PREFIX"`
+	// BatchSize 每个归档文件包含的最大记录数
+	BatchSize int `mapstructure:"RETENTION_ARCHIVE_BATCH_SIZE" validate:"gte=0"`
+	// NotificationRetentionDays 通知记录保留天数，0 表示不清理
+	NotificationRetentionDays int `mapstructure:"NOTIFICATION_RETENTION_DAYS" validate:"min=0"`
+	// RestoreHoldDays 从归档恢复的记录保留天数，期间不会被重新归档
+	RestoreHoldDays int `mapstructure:"RETENTION_RESTORE_HOLD_DAYS" validate:"gte=0"`
+}
+
+// HandoverConfig 交班报告配置，Worker 按周期检查到达班次交接时刻的团队并生成交班报告
+type HandoverConfig struct {
+	CheckInterval time.Duration `mapstructure:"HANDOVER_CHECK_INTERVAL"`
+	// ItemLimit 交班报告每一类事项最多列出的条数
+	ItemLimit int `mapstructure:"HANDOVER_ITEM_LIMIT" validate:"gte=0"`
+}
+
+// InboxConfig 站内通知配置
+type InboxConfig struct {
+	// SLACheckInterval 检查工单 SLA 超时并通知处理人的周期
+	SLACheckInterval time.Duration `mapstructure:"INBOX_SLA_CHECK_INTERVAL"`
+	// StreamPollInterval 实时推送连接检查其他实例投递的通知的周期，同时作为心跳间隔
+	StreamPollInterval time.Duration `mapstructure:"INBOX_STREAM_POLL_INTERVAL"`
+}
+
+// ChangeEventConfig 变更事件配置，告警触发前窗口内同一服务的变更视为可能原因
+type ChangeEventConfig struct {
+	CorrelationWindow time.Duration `mapstructure:"CHANGE_CORRELATION_WINDOW"`
+}
+
+// WebhookSubscriptionConfig 出站 Webhook 订阅配置，告警和工单事件按订阅顺序投递，失败后按指数退避重试
+type WebhookSubscriptionConfig struct {
+	// DeliveryInterval 投递待发送事件的周期
+	DeliveryInterval time.Duration `mapstructure:"WEBHOOK_DELIVERY_INTERVAL"`
+	// DeliveryTimeout 单次投递的超时时间
+	DeliveryTimeout time.Duration `mapstructure:"WEBHOOK_DELIVERY_TIMEOUT"`
+	// RetryBackoff 第一次重试的等待时间，之后每次翻倍，不超过 MaxRetryBackoff
+	RetryBackoff    time.Duration `mapstructure:"WEBHOOK_DELIVERY_RETRY_BACKOFF"`
+	MaxRetryBackoff time.Duration `mapstructure:"WEBHOOK_DELIVERY_MAX_RETRY_BACKOFF"`
+	// MaxAttempts 订阅未设置时单个事件的最多投递次数
+	MaxAttempts int `mapstructure:"WEBHOOK_DELIVERY_MAX_ATTEMPTS"`
+	// DisableAfterFailures 订阅未设置时连续多少个事件投递失败后停用订阅
+	DisableAfterFailures int `mapstructure:"WEBHOOK_DISABLE_AFTER_FAILURES"`
+	// AllowPrivateTargets 是否允许订阅地址指向本机、内网或链路本地地址，默认拒绝以防止服务端请求伪造
+	AllowPrivateTargets bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE_TARGETS"`
+}
+
+// TicketImportConfig 工单导入配置，确认列映射后的导入任务由 Worker 在后台逐行执行
+type TicketImportConfig struct {
+	JobCheckInterval time.Duration `mapstructure:"TICKET_IMPORT_JOB_CHECK_INTERVAL"`
+	// ProgressBatchSize 每处理多少行更新一次任务进度
+	ProgressBatchSize int `mapstructure:"TICKET_IMPORT_PROGRESS_BATCH_SIZE" validate:"gte=0"`
+}
+
+// AlertStatsConfig 告警统计配置，Worker 定期把告警汇总到小时汇总表，统计接口只读取汇总表
+type AlertStatsConfig struct {
+	RollupInterval time.Duration `mapstructure:"ALERT_STATS_ROLLUP_INTERVAL"`
+	// RollupOverlap 每次汇总回看的时长，覆盖汇总时尚未提交的告警更新
+	RollupOverlap time.Duration `mapstructure:"ALERT_STATS_ROLLUP_OVERLAP"`
+}
+
+// LabelCardinalityConfig 告警标签基数防护配置。每个实例在统计窗口内分别统计入站告警各标签键的不同值数量，
+// 超过上限的标签键按 Action 处理；Drop 和 Normalize 中的标签键始终丢弃或规范化，Protected 中的标签键不做处理
+type LabelCardinalityConfig struct {
+	Limit     int           `mapstructure:"LABEL_CARDINALITY_LIMIT" validate:"gte=0"`
+	Window    time.Duration `mapstructure:"LABEL_CARDINALITY_WINDOW"`
+	Action    string        `mapstructure:"LABEL_CARDINALITY_ACTION" validate:"omitempty,oneof=report drop normalize"`
+	Drop      []string      `mapstructure:"LABEL_CARDINALITY_DROP"`
+	Normalize []string      `mapstructure:"LABEL_CARDINALITY_NORMALIZE"`
+	Protected []string      `mapstructure:"LABEL_CARDINALITY_PROTECTED"`
+}
+
+// LabelAutocompleteConfig 告警标签自动补全配置，标签键和值从最近 Window 内创建的告警中统计，
+// 统计结果缓存 CacheTTL，多实例部署时配置 Redis 可在实例间共享
+type LabelAutocompleteConfig struct {
+	Window   time.Duration `mapstructure:"LABEL_AUTOCOMPLETE_WINDOW"`
+	CacheTTL time.Duration `mapstructure:"LABEL_AUTOCOMPLETE_CACHE_TTL"`
+}
+
+// TicketSurveyConfig 工单满意度调查配置，开启后 Worker 定期向近期关闭工单的报告人发送评价链接，
+// 评价链接使用 NOTIFICATION_ACTION_LINK_BASE_URL 作为平台外部地址，未配置时不发送
+type TicketSurveyConfig struct {
+	Enabled bool `mapstructure:"TICKET_SURVEY_ENABLED"`
+	// TTL 评价链接的有效期
+	TTL           time.Duration `mapstructure:"TICKET_SURVEY_TTL"`
+	CheckInterval time.Duration `mapstructure:"TICKET_SURVEY_CHECK_INTERVAL"`
+	// Lookback 只为这段时间内关闭的工单发送调查，开启功能时不会补发历史工单
+	Lookback time.Duration `mapstructure:"TICKET_SURVEY_LOOKBACK"`
+}
+
+// TicketSLAConfig 工单SLA暂停配置。工单进入 PauseStatuses 中的状态（如等待客户或第三方回复）时自动暂停SLA计时，
+// 离开这些状态时恢复；暂停期间不判定超时，恢复后截止时间顺延暂停的时长
+type TicketSLAConfig struct {
+	PauseStatuses []string `mapstructure:"TICKET_SLA_PAUSE_STATUSES"`
+}
+
+// ApprovalConfig 敏感操作审批配置。RequiredActions 中的操作提交后需要申请人以外的管理员批准才执行，
+// 可选 datasource.delete、rules.disable_all、tickets.bulk_close；其他操作提交后立即执行
+type ApprovalConfig struct {
+	RequiredActions []string `mapstructure:"APPROVAL_REQUIRED_ACTIONS"`
+	// TTL 审批请求的有效期，过期后不能再批准
+	TTL time.Duration `mapstructure:"APPROVAL_TTL"`
+}
+
+// IngestSchemaConfig 告警接入载荷校验配置。lenient 模式忽略未知字段，自定义 Webhook 中单条告警转换失败时其余告警照常处理；
+// strict 模式拒绝未知字段和多余内容，自定义 Webhook 中任一告警转换失败时拒绝整个载荷
+type IngestSchemaConfig struct {
+	Mode string `mapstructure:"INGEST_SCHEMA_MODE" validate:"omitempty,oneof=lenient strict"`
+	// RejectionSampleRate 被拒绝的请求保存原始载荷的比例，负数表示不保存
+	RejectionSampleRate float64 `mapstructure:"INGEST_REJECTION_SAMPLE_RATE" validate:"lte=1"`
+	// RejectionKeep 每个集成保留的最近拒绝记录条数
+	RejectionKeep int `mapstructure:"INGEST_REJECTION_KEEP" validate:"gte=0"`
+}
+
+// JobLockConfig 后台任务锁配置。多实例部署时开启，定时任务执行前在 Redis 获取任务锁，同一时间只有一个实例执行；
+// 执行期间按 RenewInterval 续期，续期失败（含与 Redis 失联）超过 TTL 时停止执行，避免多个实例重复计算。
+// Redis 不可用时任务不执行
+type JobLockConfig struct {
+	Enabled bool `mapstructure:"JOB_LOCK_ENABLED"`
+	// TTL 任务锁的租约时间，实例失联后其他实例最晚在 TTL 后接管
+	TTL time.Duration `mapstructure:"JOB_LOCK_TTL"`
+	// RenewInterval 任务执行期间续期任务锁的间隔，应明显小于 TTL
+	RenewInterval time.Duration `mapstructure:"JOB_LOCK_RENEW_INTERVAL" validate:"ltfield=TTL"`
+}
+
+// AsyncTaskConfig 异步任务配置，Worker 按周期领取并执行待执行的任务，清理结束超过保留时间的任务
+type AsyncTaskConfig struct {
+	CheckInterval time.Duration `mapstructure:"ASYNC_TASK_CHECK_INTERVAL"`
+	// Retention 任务结束后保留任务记录和结果文件的时间
+	Retention time.Duration `mapstructure:"ASYNC_TASK_RETENTION"`
+	// Timeout 单个任务的最长执行时间，执行中的任务超过该时间仍未结束（如实例在执行期间退出）时标记为失败
+	Timeout time.Duration `mapstructure:"ASYNC_TASK_TIMEOUT"`
+	// MaxResultSize 结果文件的最大字节数，超出时任务失败
+	MaxResultSize int64 `mapstructure:"ASYNC_TASK_MAX_RESULT_SIZE" validate:"gte=0"`
+}
+
+// RequestTimeoutConfig 按路由类别的请求处理时限，时限写入请求 context，客户端断开或超出时限时仓储层查询随之取消
+type RequestTimeoutConfig struct {
+	// Read 读请求（GET、HEAD）的处理时限
+	Read time.Duration `mapstructure:"REQUEST_TIMEOUT_READ" validate:"gte=0"`
+	// Write 写请求的处理时限
+	Write time.Duration `mapstructure:"REQUEST_TIMEOUT_WRITE" validate:"gte=0"`
+	// Export 导出、导入、备份恢复等请求的处理时限
+	Export time.Duration `mapstructure:"REQUEST_TIMEOUT_EXPORT" validate:"gte=0"`
+}
+
+// QueryCacheConfig 数据源查询结果缓存配置，开启后相同数据源、表达式和评估间隔的查询在评估间隔内共享结果，
+// 缓存保存在共享缓存中，多实例间共享
+type QueryCacheConfig struct {
+	Enabled bool `mapstructure:"QUERY_CACHE_ENABLED"`
+}
+
+// RemoteWriteConfig 指标远程写入配置，采集端通过 Prometheus remote-write 协议推送的指标存入内置指标存储，
+// 配置了 ForwardURL 时原样转发到该地址（如 Prometheus、VictoriaMetrics 的 remote-write 接口）而不在本地存储
+type RemoteWriteConfig struct {
+	ForwardURL string `mapstructure:"REMOTE_WRITE_FORWARD_URL" validate:"omitempty,url"`
+	// Retention 内置指标存储中样本的保留时间，Worker 按 CleanupInterval 周期清理过期样本和不再上报的序列
+	Retention       time.Duration `mapstructure:"REMOTE_WRITE_RETENTION"`
+	CleanupInterval time.Duration `mapstructure:"REMOTE_WRITE_CLEANUP_INTERVAL"`
+	// MaxSamples 单次写入请求允许的最大样本数
+	MaxSamples int `mapstructure:"REMOTE_WRITE_MAX_SAMPLES" validate:"gte=0"`
+	// Lookback 即时查询时向前查找最新样本的时间范围，超过该时间未上报的序列视为无数据
+	Lookback time.Duration `mapstructure:"REMOTE_WRITE_LOOKBACK"`
+}
+
+// StatusPageConfig 公开状态页配置，开启后网关在 /status 提供不需要登录的状态页，展示组件状态、故障和计划维护的进展，
+// 访客可订阅邮件通知；确认和退订链接使用 NOTIFICATION_ACTION_LINK_BASE_URL 作为平台外部地址，未配置时不能订阅
+type StatusPageConfig struct {
+	Enabled bool   `mapstructure:"STATUS_PAGE_ENABLED"`
+	Title   string `mapstructure:"STATUS_PAGE_TITLE"`
+	// Token 不为空时访问状态页需要在 token 查询参数或 X-Status-Token 请求头中提供该令牌
+	Token string `mapstructure:"STATUS_PAGE_TOKEN"`
+	// Components 在状态页展示的服务目录中的服务，为空时展示全部服务
+	Components []string `mapstructure:"STATUS_PAGE_COMPONENTS"`
+	// CacheTTL 状态页内容的缓存时长，同时作为响应的 Cache-Control max-age
+	CacheTTL time.Duration `mapstructure:"STATUS_PAGE_CACHE_TTL"`
+	// HistoryWindow 状态页展示这段时间内结束的故障和维护
+	HistoryWindow time.Duration `mapstructure:"STATUS_PAGE_HISTORY_WINDOW"`
+	// NotifyInterval Worker 检查待通知进展说明的周期
+	NotifyInterval time.Duration `mapstructure:"STATUS_PAGE_NOTIFY_INTERVAL"`
+	// AlertHealth 组件状态同时参考服务的告警健康度，取故障、维护和告警中最差的状态
+	AlertHealth bool `mapstructure:"STATUS_PAGE_ALERT_HEALTH"`
+}
+
+// serviceHealthSeverities 可配置权重的告警级别
+var serviceHealthSeverities = []string{"critical", "high", "medium", "low", "info"}
+
+// ServiceHealthConfig 服务健康度配置，按服务当前活跃告警中权重最高的级别确定健康状态
+type ServiceHealthConfig struct {
+	// Weights 告警级别的权重（0-100），格式为 severity=weight，多个以逗号分隔，如 critical=100,high=60；
+	// 未配置的级别使用默认权重，权重为 0 的级别不影响健康度
+	Weights string `mapstructure:"SERVICE_HEALTH_WEIGHTS"`
+}
+
+// RuleForecastConfig 规则预测配置，规则的 forecast 注解指定预测方法后，Worker 按周期查询规则表达式的近期历史，
+// 预测在预测范围内将达到规则阈值时创建预测告警，不再预测达到阈值或已经达到阈值时解决
+type RuleForecastConfig struct {
+	Enabled  bool          `mapstructure:"RULE_FORECAST_ENABLED"`
+	Interval time.Duration `mapstructure:"RULE_FORECAST_INTERVAL"`
+	// Lookback 查询的历史范围，规则的 forecast_lookback 注解优先
+	Lookback time.Duration `mapstructure:"RULE_FORECAST_LOOKBACK"`
+	// Horizon 预测范围，规则的 forecast_horizon 注解优先
+	Horizon time.Duration `mapstructure:"RULE_FORECAST_HORIZON"`
+	// Step 区间查询的步长，也是预测的时间粒度
+	Step time.Duration `mapstructure:"RULE_FORECAST_STEP"`
+	// Severity 预测告警的级别，规则的 forecast_severity 注解优先
+	Severity     string        `mapstructure:"RULE_FORECAST_SEVERITY" validate:"omitempty,oneof=critical high medium low info"`
+	QueryTimeout time.Duration `mapstructure:"RULE_FORECAST_QUERY_TIMEOUT"`
+}
+
+// AlertTriageConfig 告警分诊评分配置，新告警创建时调用外部评分服务（如机器学习模型）获取优先级评分和建议的处理人，
+// 评分服务不可用或超时时跳过评分，告警照常创建
+type AlertTriageConfig struct {
+	Enabled bool   `mapstructure:"ALERT_TRIAGE_ENABLED"`
+	URL     string `mapstructure:"ALERT_TRIAGE_URL" validate:"omitempty,url"`
+	Token   string `mapstructure:"ALERT_TRIAGE_TOKEN"`
+	// Timeout 单次评分请求的超时时间，评分在告警创建路径上同步执行，应尽量短
+	Timeout time.Duration `mapstructure:"ALERT_TRIAGE_TIMEOUT"`
+}
+
+// ParseWeights 解析告警级别权重，未配置的级别使用默认权重
+func (c ServiceHealthConfig) ParseWeights() (map[string]int, error) {
+	weights := map[string]int{"critical": 100, "high": 60, "medium": 30, "low": 10, "info": 0}
+	for _, item := range strings.Split(c.Weights, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		severity, raw, ok := strings.Cut(item, "=")
+		severity = strings.TrimSpace(severity)
+		if !ok || !slices.Contains(serviceHealthSeverities, severity) {
+			return nil, fmt.Errorf("SERVICE_HEALTH_WEIGHTS 的配置项 %q 格式应为 severity=weight，级别为 %s 之一",
+				item, strings.Join(serviceHealthSeverities, "、"))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || weight < 0 || weight > 100 {
+			return nil, fmt.Errorf("SERVICE_HEALTH_WEIGHTS 中级别 %s 的权重 %q 无效，应为 0-100 的整数", severity, raw)
+		}
+		weights[severity] = weight
+	}
+	return weights, nil
 }
 
 // Load 加载配置
@@ -254,16 +1041,23 @@ func Load(envFile ...string) (*Config, error) {
 		envFileName = envFile[0]
 	}
 
+	return loadFile(envFileName)
+}
+
+// loadFile 从配置文件和环境变量加载配置，每次使用独立的 viper 实例，便于热更新时重复加载
+func loadFile(envFileName string) (*Config, error) {
+	v := viper.New()
+
 	// 设置配置文件名和路径
-	viper.SetConfigFile(envFileName)
-	viper.SetConfigType("env")
-	viper.AddConfigPath(".")
+	v.SetConfigFile(envFileName)
+	v.SetConfigType("env")
+	v.AddConfigPath(".")
 
 	// 自动读取环境变量
-	viper.AutomaticEnv()
+	v.AutomaticEnv()
 
 	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		// 如果配置文件不存在，只使用环境变量
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -274,7 +1068,7 @@ func Load(envFile ...string) (*Config, error) {
 	cfg := &Config{}
 
 	// 解析配置
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
@@ -290,7 +1084,32 @@ func Load(envFile ...string) (*Config, error) {
 // Validate 验证配置
 func (c *Config) Validate() error {
 	validate := validator.New()
-	return validate.Struct(c)
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+	if _, err := c.AutoResolve.ParseSourceTTLs(); err != nil {
+		return err
+	}
+	if _, err := c.ServiceHealth.ParseWeights(); err != nil {
+		return err
+	}
+	if c.AlertTriage.Enabled && c.AlertTriage.URL == "" {
+		return fmt.Errorf("开启告警分诊评分时 ALERT_TRIAGE_URL 不能为空")
+	}
+	if _, err := c.Security.ParseAPIKeys(); err != nil {
+		return err
+	}
+	// 操作链接和状态页订阅链接使用单独的签名密钥，不沿用 JWT 密钥
+	if c.Notification.ActionLink.BaseURL != "" && c.Notification.ActionLink.Secret == "" {
+		return fmt.Errorf("配置 NOTIFICATION_ACTION_LINK_BASE_URL 时 NOTIFICATION_ACTION_LINK_SECRET 不能为空")
+	}
+	if c.Notification.ActionLink.Secret != "" && c.Notification.ActionLink.Secret == c.JWT.Secret {
+		return fmt.Errorf("NOTIFICATION_ACTION_LINK_SECRET 不能与 JWT_SECRET 相同")
+	}
+	if _, err := c.NewHTTPClientFactory(); err != nil {
+		return err
+	}
+	return nil
 }
 
 // setDefaults 设置默认值
@@ -320,6 +1139,9 @@ func (c *Config) setDefaults() {
 	if c.App.APIDocsPath == "" {
 		c.App.APIDocsPath = "/docs"
 	}
+	if c.App.MaintenanceRetryAfter == 0 {
+		c.App.MaintenanceRetryAfter = time.Minute
+	}
 
 	// 数据库默认值
 	if c.Database.Host == "" {
@@ -331,24 +1153,37 @@ func (c *Config) setDefaults() {
 	if c.Database.SSLMode == "" {
 		c.Database.SSLMode = "disable"
 	}
+	pool, ok := databasePoolDefaults[c.App.Env]
+	if !ok {
+		pool = databasePoolDefaults["development"]
+	}
 	if c.Database.MaxOpenConns == 0 {
-		c.Database.MaxOpenConns = 25
+		c.Database.MaxOpenConns = pool.MaxOpenConns
 	}
 	if c.Database.MaxIdleConns == 0 {
-		c.Database.MaxIdleConns = 5
+		c.Database.MaxIdleConns = pool.MaxIdleConns
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		c.Database.MaxIdleConns = c.Database.MaxOpenConns
 	}
 	if c.Database.ConnMaxLifetime == 0 {
-		c.Database.ConnMaxLifetime = 5 * time.Minute
+		c.Database.ConnMaxLifetime = pool.ConnMaxLifetime
 	}
 	if c.Database.ConnMaxIdleTime == 0 {
-		c.Database.ConnMaxIdleTime = 5 * time.Minute
-	}
-	if c.Database.MigrationPath == "" {
-		c.Database.MigrationPath = "file://./migrations"
+		c.Database.ConnMaxIdleTime = pool.ConnMaxIdleTime
 	}
 	if c.Database.MigrationTable == "" {
 		c.Database.MigrationTable = "schema_migrations"
 	}
+	if c.Database.MigrationLockTimeout == 0 {
+		c.Database.MigrationLockTimeout = 5 * time.Minute
+	}
+	if c.Database.ReplicaHealthCheckInterval == 0 {
+		c.Database.ReplicaHealthCheckInterval = 30 * time.Second
+	}
+	if c.Database.SlowQueryThreshold == 0 {
+		c.Database.SlowQueryThreshold = 500 * time.Millisecond
+	}
 
 	// Redis 默认值
 	if c.Redis.Host == "" {
@@ -382,6 +1217,35 @@ func (c *Config) setDefaults() {
 	if c.Alert.MaxConcurrentEvaluations == 0 {
 		c.Alert.MaxConcurrentEvaluations = 10
 	}
+	if c.Alert.SnoozeCleanupInterval == 0 {
+		c.Alert.SnoozeCleanupInterval = 5 * time.Minute
+	}
+	if c.Alert.RetentionDays == 0 {
+		c.Alert.RetentionDays = 365
+	}
+	if c.Alert.PartitionCheckInterval == 0 {
+		c.Alert.PartitionCheckInterval = time.Hour
+	}
+	if c.Alert.PartitionPremakeMonths == 0 {
+		c.Alert.PartitionPremakeMonths = 3
+	}
+	if c.Alert.OccurrenceSampleInterval == 0 {
+		c.Alert.OccurrenceSampleInterval = 5 * time.Minute
+	}
+
+	// 通知默认值
+	if c.Notification.DigestInterval == 0 {
+		c.Notification.DigestInterval = 5 * time.Minute
+	}
+	if c.Notification.ActionLink.TTL == 0 {
+		c.Notification.ActionLink.TTL = 24 * time.Hour
+	}
+	if c.Notification.ActionLink.SnoozeDuration == 0 {
+		c.Notification.ActionLink.SnoozeDuration = time.Hour
+	}
+	if c.Notification.Push.DefaultSeverities == "" {
+		c.Notification.Push.DefaultSeverities = "critical,high"
+	}
 
 	// 性能默认值
 	if c.Performance.MaxRequestSize == 0 {
@@ -413,6 +1277,359 @@ func (c *Config) setDefaults() {
 	if c.HealthCheck.Timeout == 0 {
 		c.HealthCheck.Timeout = 5 * time.Second
 	}
+	if c.HealthCheck.QueueMaxLag == 0 {
+		c.HealthCheck.QueueMaxLag = 5 * time.Minute
+	}
+
+	// 自监控默认值
+	if c.SelfMonitor.Interval == 0 {
+		c.SelfMonitor.Interval = time.Minute
+	}
+	if c.SelfMonitor.IngestFailureThreshold == 0 {
+		c.SelfMonitor.IngestFailureThreshold = 10
+	}
+	if c.SelfMonitor.EvaluationBacklogThreshold == 0 {
+		c.SelfMonitor.EvaluationBacklogThreshold = 100
+	}
+	if c.SelfMonitor.EvaluationMissedThreshold == 0 {
+		c.SelfMonitor.EvaluationMissedThreshold = 3
+	}
+	if c.SelfMonitor.NotificationFailureRate == 0 {
+		c.SelfMonitor.NotificationFailureRate = 0.2
+	}
+	if c.SelfMonitor.NotificationFailureWindow == 0 {
+		c.SelfMonitor.NotificationFailureWindow = 15 * time.Minute
+	}
+	if c.SelfMonitor.NotificationFailureMinSamples == 0 {
+		c.SelfMonitor.NotificationFailureMinSamples = 20
+	}
+
+	// 自动修复默认值
+	if c.Remediation.Interval == 0 {
+		c.Remediation.Interval = 30 * time.Second
+	}
+	if c.Remediation.Timeout == 0 {
+		c.Remediation.Timeout = 5 * time.Minute
+	}
+	if c.Remediation.BatchSize == 0 {
+		c.Remediation.BatchSize = 20
+	}
+	if c.Remediation.Lookback == 0 {
+		c.Remediation.Lookback = time.Hour
+	}
+	if c.Remediation.AWXPollInterval == 0 {
+		c.Remediation.AWXPollInterval = 5 * time.Second
+	}
+	if c.Remediation.SaltEauth == "" {
+		c.Remediation.SaltEauth = "pam"
+	}
+
+	// ChatOps 默认值
+	if c.ChatOps.MaxSilence == 0 {
+		c.ChatOps.MaxSilence = 24 * time.Hour
+	}
+
+	// ITSM 同步默认值
+	if c.ITSM.Interval == 0 {
+		c.ITSM.Interval = time.Minute
+	}
+	if c.ITSM.BatchSize == 0 {
+		c.ITSM.BatchSize = 50
+	}
+	if c.ITSM.HTTPTimeout == 0 {
+		c.ITSM.HTTPTimeout = 30 * time.Second
+	}
+
+	// 心跳监控默认值
+	if c.Heartbeat.CheckInterval == 0 {
+		c.Heartbeat.CheckInterval = 30 * time.Second
+	}
+	if c.Heartbeat.BatchSize == 0 {
+		c.Heartbeat.BatchSize = 100
+	}
+
+	// Kubernetes 采集默认值
+	if c.Kubernetes.SyncInterval == 0 {
+		c.Kubernetes.SyncInterval = time.Minute
+	}
+	if c.Kubernetes.EventWindow == 0 {
+		c.Kubernetes.EventWindow = 15 * time.Minute
+	}
+	if c.Kubernetes.PodNotReadyAfter == 0 {
+		c.Kubernetes.PodNotReadyAfter = 5 * time.Minute
+	}
+	if c.Kubernetes.HTTPTimeout == 0 {
+		c.Kubernetes.HTTPTimeout = 30 * time.Second
+	}
+
+	// SLO 默认值
+	if c.SLO.EvaluationInterval == 0 {
+		c.SLO.EvaluationInterval = 5 * time.Minute
+	}
+	if c.SLO.QueryTimeout == 0 {
+		c.SLO.QueryTimeout = 30 * time.Second
+	}
+	if c.SLO.RuleEvaluationInterval == 0 {
+		c.SLO.RuleEvaluationInterval = time.Minute
+	}
+
+	// 熔断默认值
+	if c.CircuitBreaker.FailureThreshold == 0 {
+		c.CircuitBreaker.FailureThreshold = 5
+	}
+	if c.CircuitBreaker.OpenTimeout == 0 {
+		c.CircuitBreaker.OpenTimeout = time.Minute
+	}
+	if c.CircuitBreaker.HalfOpenMaxCalls == 0 {
+		c.CircuitBreaker.HalfOpenMaxCalls = 1
+	}
+	if c.CircuitBreaker.CallTimeout == 0 {
+		c.CircuitBreaker.CallTimeout = time.Minute
+	}
+
+	// 外部集成 HTTP 客户端默认值
+	if c.HTTPClient.Timeout == 0 {
+		c.HTTPClient.Timeout = 30 * time.Second
+	}
+	if c.HTTPClient.MaxAttempts == 0 {
+		c.HTTPClient.MaxAttempts = 2
+	}
+	if c.HTTPClient.InitialBackoff == 0 {
+		c.HTTPClient.InitialBackoff = 500 * time.Millisecond
+	}
+	if c.HTTPClient.MaxBackoff == 0 {
+		c.HTTPClient.MaxBackoff = 10 * time.Second
+	}
+	if c.HTTPClient.Jitter == 0 {
+		c.HTTPClient.Jitter = 0.2
+	}
+	if c.HTTPClient.RetryableStatusCodes == "" {
+		c.HTTPClient.RetryableStatusCodes = "429,502,503,504"
+	}
+
+	// 告警自动解决默认值
+	if c.AutoResolve.CheckInterval == 0 {
+		c.AutoResolve.CheckInterval = time.Minute
+	}
+	if c.AutoResolve.BatchSize == 0 {
+		c.AutoResolve.BatchSize = 500
+	}
+
+	// 标签治理默认值
+	if c.TagGovernance.JobCheckInterval == 0 {
+		c.TagGovernance.JobCheckInterval = 30 * time.Second
+	}
+	if c.TagGovernance.BatchSize == 0 {
+		c.TagGovernance.BatchSize = 500
+	}
+
+	// 数据保留默认值
+	if c.Retention.ArchiveInterval == 0 {
+		c.Retention.ArchiveInterval = 24 * time.Hour
+	}
+	if c.Retention.ArchivePrefix == "" {
+		c.Retention.ArchivePrefix = "archives"
+	}
+	if c.Retention.BatchSize == 0 {
+		c.Retention.BatchSize = 10000
+	}
+	if c.Retention.RestoreHoldDays == 0 {
+		c.Retention.RestoreHoldDays = 7
+	}
+
+	// 交班报告默认值
+	if c.Handover.CheckInterval == 0 {
+		c.Handover.CheckInterval = time.Minute
+	}
+	if c.Handover.ItemLimit == 0 {
+		c.Handover.ItemLimit = 50
+	}
+
+	// 站内通知默认值
+	if c.Inbox.SLACheckInterval == 0 {
+		c.Inbox.SLACheckInterval = time.Minute
+	}
+	if c.Inbox.StreamPollInterval == 0 {
+		c.Inbox.StreamPollInterval = 5 * time.Second
+	}
+
+	// 变更关联默认值
+	if c.ChangeEvent.CorrelationWindow == 0 {
+		c.ChangeEvent.CorrelationWindow = 30 * time.Minute
+	}
+
+	// 出站 Webhook 订阅默认值
+	if c.WebhookSubscription.DeliveryInterval == 0 {
+		c.WebhookSubscription.DeliveryInterval = 10 * time.Second
+	}
+	if c.WebhookSubscription.DeliveryTimeout == 0 {
+		c.WebhookSubscription.DeliveryTimeout = 10 * time.Second
+	}
+	if c.WebhookSubscription.RetryBackoff == 0 {
+		c.WebhookSubscription.RetryBackoff = 30 * time.Second
+	}
+	if c.WebhookSubscription.MaxRetryBackoff == 0 {
+		c.WebhookSubscription.MaxRetryBackoff = time.Hour
+	}
+	if c.WebhookSubscription.MaxAttempts == 0 {
+		c.WebhookSubscription.MaxAttempts = 8
+	}
+	if c.WebhookSubscription.DisableAfterFailures == 0 {
+		c.WebhookSubscription.DisableAfterFailures = 5
+	}
+
+	// 工单导入默认值
+	if c.TicketImport.JobCheckInterval == 0 {
+		c.TicketImport.JobCheckInterval = 10 * time.Second
+	}
+	if c.TicketImport.ProgressBatchSize == 0 {
+		c.TicketImport.ProgressBatchSize = 100
+	}
+
+	// 告警统计默认值
+	if c.AlertStats.RollupInterval == 0 {
+		c.AlertStats.RollupInterval = time.Minute
+	}
+	if c.AlertStats.RollupOverlap == 0 {
+		c.AlertStats.RollupOverlap = 5 * time.Minute
+	}
+
+	// 告警标签基数默认值
+	if c.LabelCardinality.Limit == 0 {
+		c.LabelCardinality.Limit = 1000
+	}
+	if c.LabelCardinality.Window == 0 {
+		c.LabelCardinality.Window = time.Hour
+	}
+	if c.LabelCardinality.Action == "" {
+		c.LabelCardinality.Action = "normalize"
+	}
+
+	// 告警标签自动补全默认值
+	if c.LabelAutocomplete.Window == 0 {
+		c.LabelAutocomplete.Window = 7 * 24 * time.Hour
+	}
+	if c.LabelAutocomplete.CacheTTL == 0 {
+		c.LabelAutocomplete.CacheTTL = time.Minute
+	}
+
+	// 工单满意度调查默认值
+	if c.TicketSurvey.TTL == 0 {
+		c.TicketSurvey.TTL = 7 * 24 * time.Hour
+	}
+	if c.TicketSurvey.CheckInterval == 0 {
+		c.TicketSurvey.CheckInterval = time.Minute
+	}
+	if c.TicketSurvey.Lookback == 0 {
+		c.TicketSurvey.Lookback = 24 * time.Hour
+	}
+
+	// 工单SLA暂停默认值
+	if c.TicketSLA.PauseStatuses == nil {
+		c.TicketSLA.PauseStatuses = []string{"pending"}
+	}
+
+	// 敏感操作审批默认值
+	if c.Approval.TTL == 0 {
+		c.Approval.TTL = 24 * time.Hour
+	}
+
+	// 告警接入载荷校验默认值
+	if c.IngestSchema.Mode == "" {
+		c.IngestSchema.Mode = "lenient"
+	}
+	if c.IngestSchema.RejectionSampleRate == 0 {
+		c.IngestSchema.RejectionSampleRate = 0.1
+	}
+	if c.IngestSchema.RejectionKeep == 0 {
+		c.IngestSchema.RejectionKeep = 500
+	}
+
+	// 异步任务默认值
+	if c.AsyncTask.CheckInterval == 0 {
+		c.AsyncTask.CheckInterval = 5 * time.Second
+	}
+	if c.AsyncTask.Retention == 0 {
+		c.AsyncTask.Retention = 24 * time.Hour
+	}
+	if c.AsyncTask.Timeout == 0 {
+		c.AsyncTask.Timeout = 30 * time.Minute
+	}
+	if c.AsyncTask.MaxResultSize == 0 {
+		c.AsyncTask.MaxResultSize = 100 << 20
+	}
+
+	// 指标远程写入默认值
+	if c.RemoteWrite.Retention == 0 {
+		c.RemoteWrite.Retention = 15 * 24 * time.Hour
+	}
+	if c.RemoteWrite.CleanupInterval == 0 {
+		c.RemoteWrite.CleanupInterval = time.Hour
+	}
+	if c.RemoteWrite.MaxSamples == 0 {
+		c.RemoteWrite.MaxSamples = 100000
+	}
+	if c.RemoteWrite.Lookback == 0 {
+		c.RemoteWrite.Lookback = 5 * time.Minute
+	}
+
+	// 公开状态页默认值
+	if c.StatusPage.Title == "" {
+		c.StatusPage.Title = "Pulse Status"
+	}
+	if c.StatusPage.CacheTTL == 0 {
+		c.StatusPage.CacheTTL = time.Minute
+	}
+	if c.StatusPage.HistoryWindow == 0 {
+		c.StatusPage.HistoryWindow = 7 * 24 * time.Hour
+	}
+	if c.StatusPage.NotifyInterval == 0 {
+		c.StatusPage.NotifyInterval = 30 * time.Second
+	}
+
+	// 规则预测默认值
+	if c.RuleForecast.Interval == 0 {
+		c.RuleForecast.Interval = 5 * time.Minute
+	}
+	if c.RuleForecast.Lookback == 0 {
+		c.RuleForecast.Lookback = 6 * time.Hour
+	}
+	if c.RuleForecast.Horizon == 0 {
+		c.RuleForecast.Horizon = 24 * time.Hour
+	}
+	if c.RuleForecast.Step == 0 {
+		c.RuleForecast.Step = 5 * time.Minute
+	}
+	if c.RuleForecast.Severity == "" {
+		c.RuleForecast.Severity = "medium"
+	}
+	if c.RuleForecast.QueryTimeout == 0 {
+		c.RuleForecast.QueryTimeout = 30 * time.Second
+	}
+
+	// 告警分诊评分默认值
+	if c.AlertTriage.Timeout == 0 {
+		c.AlertTriage.Timeout = 2 * time.Second
+	}
+
+	// 请求处理时限默认值
+	if c.RequestTimeout.Read == 0 {
+		c.RequestTimeout.Read = 5 * time.Second
+	}
+	if c.RequestTimeout.Write == 0 {
+		c.RequestTimeout.Write = 30 * time.Second
+	}
+	if c.RequestTimeout.Export == 0 {
+		c.RequestTimeout.Export = 120 * time.Second
+	}
+
+	// 后台任务锁默认值
+	if c.JobLock.TTL == 0 {
+		c.JobLock.TTL = 30 * time.Second
+	}
+	if c.JobLock.RenewInterval == 0 {
+		c.JobLock.RenewInterval = 10 * time.Second
+	}
 
 	// 安全默认值
 	if len(c.Security.CORSAllowedOrigins) == 0 {
@@ -430,6 +1647,9 @@ func (c *Config) setDefaults() {
 	if c.Security.RateLimitBurst == 0 {
 		c.Security.RateLimitBurst = 200
 	}
+	if c.Security.RateLimitWebhookRPM == 0 {
+		c.Security.RateLimitWebhookRPM = 300
+	}
 	if c.Security.APIKeyHeader == "" {
 		c.Security.APIKeyHeader = "X-API-Key"
 	}
@@ -441,6 +1661,49 @@ func (c *Config) setDefaults() {
 	if c.FileStorage.LocalPath == "" {
 		c.FileStorage.LocalPath = "./uploads"
 	}
+
+	// 附件扫描默认值
+	if c.FileStorage.Scan.Scanner == "" {
+		c.FileStorage.Scan.Scanner = "clamav"
+	}
+	if c.FileStorage.Scan.ClamAVAddress == "" {
+		c.FileStorage.Scan.ClamAVAddress = "localhost:3310"
+	}
+	if c.FileStorage.Scan.Timeout == 0 {
+		c.FileStorage.Scan.Timeout = 60 * time.Second
+	}
+	if c.FileStorage.Scan.Interval == 0 {
+		c.FileStorage.Scan.Interval = 10 * time.Second
+	}
+	if c.FileStorage.Scan.BatchSize == 0 {
+		c.FileStorage.Scan.BatchSize = 20
+	}
+	if c.FileStorage.Scan.MaxAttempts == 0 {
+		c.FileStorage.Scan.MaxAttempts = 5
+	}
+	if c.FileStorage.Scan.RetryBackoff == 0 {
+		c.FileStorage.Scan.RetryBackoff = time.Minute
+	}
+	if c.FileStorage.Scan.MaxRetryBackoff == 0 {
+		c.FileStorage.Scan.MaxRetryBackoff = time.Hour
+	}
+	if c.FileStorage.Scan.QuarantinePath == "" {
+		c.FileStorage.Scan.QuarantinePath = "./uploads/quarantine"
+	}
+
+	// 知识库默认值
+	if c.Knowledge.SchedulerInterval == 0 {
+		c.Knowledge.SchedulerInterval = time.Minute
+	}
+	if c.Knowledge.ExpiryWarningDays == 0 {
+		c.Knowledge.ExpiryWarningDays = 7
+	}
+	if c.Knowledge.QualityFlagThreshold == 0 {
+		c.Knowledge.QualityFlagThreshold = 0.4
+	}
+	if c.Knowledge.QualityMinFeedback == 0 {
+		c.Knowledge.QualityMinFeedback = 3
+	}
 }
 
 // processStringSliceEnvVars 处理字符串数组环境变量
@@ -455,6 +1718,52 @@ func (c *Config) processStringSliceEnvVars() {
 	if corsHeaders := os.Getenv("CORS_ALLOWED_HEADERS"); corsHeaders != "" {
 		c.Security.CORSAllowedHeaders = strings.Split(corsHeaders, ",")
 	}
+
+	// 处理功能开关
+	if featureFlags := os.Getenv("FEATURE_FLAGS"); featureFlags != "" {
+		c.App.FeatureFlags = strings.Split(featureFlags, ",")
+	}
+
+	// 处理数据库只读副本，多个 DSN 以逗号分隔
+	if replicaDSNs := os.Getenv("DB_REPLICA_DSNS"); replicaDSNs != "" {
+		c.Database.ReplicaDSNs = strings.Split(replicaDSNs, ",")
+	}
+
+	// 处理告警标签基数防护的标签键列表
+	if drop := os.Getenv("LABEL_CARDINALITY_DROP"); drop != "" {
+		c.LabelCardinality.Drop = strings.Split(drop, ",")
+	}
+	if normalize := os.Getenv("LABEL_CARDINALITY_NORMALIZE"); normalize != "" {
+		c.LabelCardinality.Normalize = strings.Split(normalize, ",")
+	}
+	if protected := os.Getenv("LABEL_CARDINALITY_PROTECTED"); protected != "" {
+		c.LabelCardinality.Protected = strings.Split(protected, ",")
+	}
+
+	// 处理测试模式的通知渠道列表
+	if channels := os.Getenv("NOTIFICATION_TEST_MODE_CHANNELS"); channels != "" {
+		c.Notification.TestModeChannels = strings.Split(channels, ",")
+	}
+
+	// 处理自动暂停SLA的工单状态列表
+	if statuses := os.Getenv("TICKET_SLA_PAUSE_STATUSES"); statuses != "" {
+		c.TicketSLA.PauseStatuses = strings.Split(statuses, ",")
+	}
+
+	// 处理需要审批的敏感操作列表
+	if actions := os.Getenv("APPROVAL_REQUIRED_ACTIONS"); actions != "" {
+		c.Approval.RequiredActions = strings.Split(actions, ",")
+	}
+}
+
+// FeatureEnabled 判断功能开关是否启用
+func (c *Config) FeatureEnabled(name string) bool {
+	for _, flag := range c.App.FeatureFlags {
+		if strings.TrimSpace(flag) == name {
+			return true
+		}
+	}
+	return false
 }
 
 // IsProduction 判断是否为生产环境
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 密钥来源
+const (
+	SecretsProviderEnv   = "env"
+	SecretsProviderFile  = "file"
+	SecretsProviderVault = "vault"
+)
+
+// 受密钥提供者管理的配置项
+const (
+	SecretJWT                = "JWT_SECRET"
+	SecretDBPassword         = "DB_PASSWORD"
+	SecretEncryptionKey      = "ENCRYPTION_KEY"
+	SecretEncryptionKeys     = "ENCRYPTION_KEYS"
+	SecretEncryptionIndexKey = "ENCRYPTION_INDEX_KEY"
+	SecretAWXToken           = "AWX_TOKEN"
+	SecretSaltPassword       = "SALT_PASSWORD"
+	SecretSlackSigningSecret = "SLACK_SIGNING_SECRET"
+	SecretDingTalkAppSecret  = "DINGTALK_APP_SECRET"
+	SecretActionLink         = "NOTIFICATION_ACTION_LINK_SECRET"
+)
+
+// ErrSecretNotFound 密钥不存在，调用方保留配置文件中的值
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretsConfig 密钥来源配置
+type SecretsConfig struct {
+	Provider string `mapstructure:"SECRETS_PROVIDER" validate:"omitempty,oneof=env file vault"`
+
+	// 文件密钥目录，每个密钥一个文件，文件名为小写的配置项名称（如 jwt_secret），兼容 Docker/Kubernetes secrets
+	FileDir string `mapstructure:"SECRETS_FILE_DIR"`
+
+	// HashiCorp Vault 配置，VaultPath 为完整的 API 路径，如 KV v2 的 secret/data/pulse
+	VaultAddress   string        `mapstructure:"VAULT_ADDR"`
+	VaultToken     string        `mapstructure:"VAULT_TOKEN"`
+	VaultNamespace string        `mapstructure:"VAULT_NAMESPACE"`
+	VaultPath      string        `mapstructure:"VAULT_SECRET_PATH"`
+	VaultTimeout   time.Duration `mapstructure:"VAULT_TIMEOUT"`
+}
+
+// SecretProvider 密钥提供者
+type SecretProvider interface {
+	// GetSecret 获取密钥，不存在时返回 ErrSecretNotFound
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+// NewSecretProvider 根据配置创建密钥提供者，未配置时从环境变量读取
+func NewSecretProvider(cfg SecretsConfig) (SecretProvider, error) {
+	switch cfg.Provider {
+	case "", SecretsProviderEnv:
+		return EnvSecretProvider{}, nil
+	case SecretsProviderFile:
+		dir := cfg.FileDir
+		if dir == "" {
+			dir = "/run/secrets"
+		}
+		return FileSecretProvider{Dir: dir}, nil
+	case SecretsProviderVault:
+		if cfg.VaultAddress == "" || cfg.VaultPath == "" {
+			return nil, fmt.Errorf("VAULT_ADDR and VAULT_SECRET_PATH are required for vault secrets provider")
+		}
+		return NewVaultSecretProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %s", cfg.Provider)
+	}
+}
+
+// LoadSecrets 从密钥提供者读取 JWT 密钥、数据库密码、加密密钥、自动修复凭据和 ChatOps 签名密钥并覆盖配置
+// 提供者中不存在的密钥保留配置文件或环境变量中的值
+func (c *Config) LoadSecrets(ctx context.Context, provider SecretProvider) error {
+	targets := []struct {
+		name  string
+		value *string
+	}{
+		{SecretJWT, &c.JWT.Secret},
+		{SecretDBPassword, &c.Database.Password},
+		{SecretEncryptionKey, &c.Security.EncryptionKey},
+		{SecretEncryptionKeys, &c.Security.EncryptionKeys},
+		{SecretEncryptionIndexKey, &c.Security.EncryptionIndexKey},
+		{SecretAWXToken, &c.Remediation.AWXToken},
+		{SecretSaltPassword, &c.Remediation.SaltPassword},
+		{SecretSlackSigningSecret, &c.ChatOps.SlackSigningSecret},
+		{SecretDingTalkAppSecret, &c.ChatOps.DingTalkAppSecret},
+		{SecretActionLink, &c.Notification.ActionLink.Secret},
+	}
+
+	for _, target := range targets {
+		value, err := provider.GetSecret(ctx, target.name)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load secret %s: %w", target.name, err)
+		}
+		*target.value = value
+	}
+	return nil
+}
+
+// EnvSecretProvider 从环境变量读取密钥
+type EnvSecretProvider struct{}
+
+// GetSecret 获取密钥
+func (EnvSecretProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// FileSecretProvider 从目录中的文件读取密钥，文件内容首尾空白会被去除
+type FileSecretProvider struct {
+	Dir string
+}
+
+// GetSecret 获取密钥
+func (p FileSecretProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, strings.ToLower(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// VaultSecretProvider 从 HashiCorp Vault KV 引擎读取密钥，同时支持 KV v1 和 v2
+// 首次读取后缓存整个密钥路径，避免每个配置项都请求一次 Vault
+type VaultSecretProvider struct {
+	address   string
+	token     string
+	namespace string
+	path      string
+	client    *http.Client
+
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewVaultSecretProvider 创建 Vault 密钥提供者
+func NewVaultSecretProvider(cfg SecretsConfig) *VaultSecretProvider {
+	timeout := cfg.VaultTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &VaultSecretProvider{
+		address:   strings.TrimRight(cfg.VaultAddress, "/"),
+		token:     cfg.VaultToken,
+		namespace: cfg.VaultNamespace,
+		path:      strings.Trim(cfg.VaultPath, "/"),
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// GetSecret 获取密钥，键名先按原样匹配，再按小写匹配
+func (p *VaultSecretProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	values, err := p.load(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range []string{name, strings.ToLower(name)} {
+		if value, ok := values[key]; ok && value != "" {
+			return value, nil
+		}
+	}
+	return "", ErrSecretNotFound
+}
+
+// load 读取并缓存密钥路径下的所有键值
+func (p *VaultSecretProvider) load(ctx context.Context) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.values != nil {
+		return p.values, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		p.values = map[string]string{}
+		return p.values, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// KV v2 的键值嵌套在 data.data 中
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		if str, ok := value.(string); ok {
+			values[key] = str
+		}
+	}
+	p.values = values
+	return values, nil
+}
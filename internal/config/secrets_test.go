@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "jwt_secret"), []byte("from-file-secret\n"), 0o600))
+
+	cfg := &Config{}
+	cfg.JWT.Secret = "from-env"
+	cfg.Database.Password = "db-from-env"
+
+	require.NoError(t, cfg.LoadSecrets(context.Background(), FileSecretProvider{Dir: dir}))
+	assert.Equal(t, "from-file-secret", cfg.JWT.Secret)
+	// 文件不存在时保留原值
+	assert.Equal(t, "db-from-env", cfg.Database.Password)
+}
+
+func TestVaultSecretProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/v1/secret/data/pulse", r.URL.Path)
+		assert.Equal(t, "root-token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"data":{"data":{"DB_PASSWORD":"vault-db","encryption_key":"vault-key"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewSecretProvider(SecretsConfig{
+		Provider:     SecretsProviderVault,
+		VaultAddress: server.URL,
+		VaultToken:   "root-token",
+		VaultPath:    "/secret/data/pulse",
+	})
+	require.NoError(t, err)
+
+	cfg := &Config{}
+	cfg.JWT.Secret = "unchanged"
+	require.NoError(t, cfg.LoadSecrets(context.Background(), provider))
+	assert.Equal(t, "unchanged", cfg.JWT.Secret)
+	assert.Equal(t, "vault-db", cfg.Database.Password)
+	assert.Equal(t, "vault-key", cfg.Security.EncryptionKey)
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewSecretProvider_Invalid(t *testing.T) {
+	_, err := NewSecretProvider(SecretsConfig{Provider: SecretsProviderVault})
+	assert.Error(t, err)
+
+	_, err = NewSecretProvider(SecretsConfig{Provider: "aws"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-playground/validator/v10"
+)
+
+// ReloadableConfig 支持热更新的配置项
+// 数据库、Redis、监听地址等结构性配置修改后仍需重启服务
+type ReloadableConfig struct {
+	LogLevel            string `validate:"oneof=debug info warn error"`
+	RateLimitEnabled    bool
+	RateLimitRPS        int `validate:"min=1"`
+	RateLimitBurst      int `validate:"min=1"`
+	RateLimitWebhookRPM int `validate:"min=1"`
+	FeatureFlags        []string
+	MaintenanceMode     bool
+}
+
+// Reloadable 返回当前配置中支持热更新的部分
+func (c *Config) Reloadable() ReloadableConfig {
+	return ReloadableConfig{
+		LogLevel:            c.App.LogLevel,
+		RateLimitEnabled:    c.Security.RateLimitEnabled,
+		RateLimitRPS:        c.Security.RateLimitRPS,
+		RateLimitBurst:      c.Security.RateLimitBurst,
+		RateLimitWebhookRPM: c.Security.RateLimitWebhookRPM,
+		FeatureFlags:        append([]string(nil), c.App.FeatureFlags...),
+		MaintenanceMode:     c.App.MaintenanceMode,
+	}
+}
+
+// FeatureEnabled 判断功能开关是否启用
+func (r ReloadableConfig) FeatureEnabled(name string) bool {
+	return (&Config{App: AppConfig{FeatureFlags: r.FeatureFlags}}).FeatureEnabled(name)
+}
+
+// watcherDebounce 编辑器保存文件时可能连续触发多个事件，合并后只重新加载一次
+const watcherDebounce = 500 * time.Millisecond
+
+// Watcher 监听配置文件变化并热更新配置
+type Watcher struct {
+	path     string
+	debounce time.Duration
+
+	mu       sync.Mutex
+	current  ReloadableConfig
+	handlers []func(ReloadableConfig)
+	onError  func(error)
+}
+
+// NewWatcher 创建配置监听器，cfg 为启动时加载的配置
+func NewWatcher(path string, cfg *Config) *Watcher {
+	return &Watcher{
+		path:     path,
+		debounce: watcherDebounce,
+		current:  cfg.Reloadable(),
+	}
+}
+
+// OnReload 注册配置变化回调，仅在可热更新的配置项发生变化时调用
+func (w *Watcher) OnReload(fn func(ReloadableConfig)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, fn)
+}
+
+// OnError 注册重新加载失败回调，失败时保留原有配置
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = fn
+}
+
+// Current 返回当前生效的可热更新配置
+func (w *Watcher) Current() ReloadableConfig {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Reload 重新加载配置文件，返回可热更新配置项是否发生变化
+func (w *Watcher) Reload() (bool, error) {
+	cfg, err := loadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+
+	next := cfg.Reloadable()
+	if err := validator.New().Struct(next); err != nil {
+		return false, fmt.Errorf("invalid config: %w", err)
+	}
+
+	w.mu.Lock()
+	if reflect.DeepEqual(w.current, next) {
+		w.mu.Unlock()
+		return false, nil
+	}
+	w.current = next
+	handlers := append([]func(ReloadableConfig){}, w.handlers...)
+	w.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(next)
+	}
+	return true, nil
+}
+
+// Start 开始监听配置文件，ctx 取消后停止
+// 监听文件所在目录而不是文件本身，兼容编辑器替换文件和 Kubernetes ConfigMap 的符号链接切换
+func (w *Watcher) Start(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	dir := filepath.Dir(w.path)
+	if err := fsWatcher.Add(dir); err != nil {
+		fsWatcher.Close()
+		return fmt.Errorf("failed to watch config directory %s: %w", dir, err)
+	}
+
+	target := filepath.Clean(w.path)
+	go func() {
+		defer fsWatcher.Close()
+
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != target && filepath.Base(event.Name) != "..data" {
+					continue
+				}
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(w.debounce)
+				timerC = timer.C
+			case <-timerC:
+				timerC = nil
+				if _, err := w.Reload(); err != nil {
+					w.reportError(err)
+				}
+			case err, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+				w.reportError(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// reportError 调用错误回调
+func (w *Watcher) reportError(err error) {
+	w.mu.Lock()
+	onError := w.onError
+	w.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=info\nRATE_LIMIT_RPS=100\n"), 0o600))
+
+	cfg, err := loadFile(path)
+	require.NoError(t, err)
+
+	watcher := NewWatcher(path, cfg)
+	var reloaded []ReloadableConfig
+	watcher.OnReload(func(r ReloadableConfig) { reloaded = append(reloaded, r) })
+
+	// 未变化时不触发回调
+	changed, err := watcher.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=debug\nRATE_LIMIT_RPS=50\nFEATURE_FLAGS=new-dashboard,alert-replay\n"), 0o600))
+	changed, err = watcher.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.Len(t, reloaded, 1)
+	assert.Equal(t, "debug", reloaded[0].LogLevel)
+	assert.Equal(t, 50, reloaded[0].RateLimitRPS)
+	assert.True(t, reloaded[0].FeatureEnabled("alert-replay"))
+
+	// 非法配置保留原有值
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=verbose\n"), 0o600))
+	_, err = watcher.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "debug", watcher.Current().LogLevel)
+}
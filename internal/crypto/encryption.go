@@ -121,6 +121,36 @@ func (s *aesEncryptionService) EncryptDataSourceConfig(config *models.DataSource
 		config.Token = &encrypted
 	}
 	
+	// 加密kubeconfig
+	if config.Kubeconfig != nil && *config.Kubeconfig != "" {
+		encrypted, err := s.Encrypt(*config.Kubeconfig)
+		if err != nil {
+			return err
+		}
+		config.Kubeconfig = &encrypted
+	}
+	
+	// 加密代理地址，可能包含认证信息
+	if config.ProxyURL != nil && *config.ProxyURL != "" {
+		encrypted, err := s.Encrypt(*config.ProxyURL)
+		if err != nil {
+			return err
+		}
+		config.ProxyURL = &encrypted
+	}
+
+	// 加密客户端证书和私钥
+	for _, field := range []**string{&config.ClientCert, &config.ClientKey} {
+		if *field == nil || **field == "" {
+			continue
+		}
+		encrypted, err := s.Encrypt(**field)
+		if err != nil {
+			return err
+		}
+		*field = &encrypted
+	}
+	
 	return nil
 }
 
@@ -148,5 +178,35 @@ func (s *aesEncryptionService) DecryptDataSourceConfig(config *models.DataSource
 		config.Token = &decrypted
 	}
 	
+	// 解密kubeconfig
+	if config.Kubeconfig != nil && *config.Kubeconfig != "" {
+		decrypted, err := s.Decrypt(*config.Kubeconfig)
+		if err != nil {
+			return err
+		}
+		config.Kubeconfig = &decrypted
+	}
+	
+	// 解密代理地址，可能包含认证信息
+	if config.ProxyURL != nil && *config.ProxyURL != "" {
+		decrypted, err := s.Decrypt(*config.ProxyURL)
+		if err != nil {
+			return err
+		}
+		config.ProxyURL = &decrypted
+	}
+
+	// 解密客户端证书和私钥
+	for _, field := range []**string{&config.ClientCert, &config.ClientKey} {
+		if *field == nil || **field == "" {
+			continue
+		}
+		decrypted, err := s.Decrypt(**field)
+		if err != nil {
+			return err
+		}
+		*field = &decrypted
+	}
+	
 	return nil
 }
\ No newline at end of file
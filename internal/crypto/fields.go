@@ -0,0 +1,29 @@
+package crypto
+
+import "strings"
+
+// FieldEncryptor 字段级加密，除加解密外提供盲索引用于加密字段的等值查询
+type FieldEncryptor interface {
+	EncryptionService
+	// BlindIndex 计算字段值的盲索引，与主密钥版本无关，轮换主密钥后保持不变
+	BlindIndex(value string) string
+}
+
+// IsEncrypted 判断值是否为信封加密格式，用于兼容启用加密前写入的明文数据
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// sensitiveHeaderKeywords 需要加密存储的请求头名称关键词
+var sensitiveHeaderKeywords = []string{"authorization", "token", "secret", "password", "api-key", "apikey", "signature"}
+
+// IsSensitiveHeader 判断请求头是否携带凭据，如 Authorization、X-Api-Key、X-Auth-Token
+func IsSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, keyword := range sensitiveHeaderKeywords {
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
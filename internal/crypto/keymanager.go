@@ -0,0 +1,352 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"pulse/internal/models"
+)
+
+// 信封加密密文格式：enc:v1:<密钥版本>:<加密后的数据密钥>:<加密后的数据>
+// 每个值使用随机生成的数据密钥（DEK）以 AES-256-GCM 加密，数据密钥再由指定版本的主密钥（KEK）加密
+// 没有前缀的密文为旧版格式，使用旧版密钥解密
+const (
+	envelopePrefix = "enc:v1:"
+
+	// LegacyKeyVersion 旧版密文（未使用信封加密）的密钥版本
+	LegacyKeyVersion = "legacy"
+
+	// DefaultKeyVersion 未配置密钥列表时，由旧版密钥派生的主密钥版本
+	DefaultKeyVersion = "v0"
+)
+
+var (
+	// ErrNoEncryptionKey 未配置数据加密密钥，只用于解密的密钥不能用于加密新数据
+	ErrNoEncryptionKey = errors.New("no data encryption key configured, set ENCRYPTION_KEYS or ENCRYPTION_KEY")
+	// ErrUnknownKeyVersion 密文使用的密钥版本未配置
+	ErrUnknownKeyVersion = errors.New("unknown encryption key version")
+	// ErrInvalidCiphertext 密文格式错误
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// KeyRotator 支持密钥轮换的加密服务
+type KeyRotator interface {
+	EncryptionService
+	// ActiveVersion 当前用于加密的主密钥版本
+	ActiveVersion() string
+	// Rotate 将密文重新加密为当前主密钥版本，返回是否发生变化
+	Rotate(ciphertext string) (string, bool, error)
+	// RotateDataSourceConfig 将数据源配置中的密文重新加密为当前主密钥版本，返回是否有字段发生变化
+	RotateDataSourceConfig(config *models.DataSourceConfig) (bool, error)
+}
+
+// KeyManagerConfig 密钥管理配置
+type KeyManagerConfig struct {
+	// Keys 主密钥列表，格式为 "v1:密钥,v2:密钥"
+	// 密钥为 base64 编码的 32 字节时直接使用，否则取 SHA-256 摘要
+	Keys string
+	// ActiveVersion 用于加密的主密钥版本，为空时使用列表中的最后一个
+	ActiveVersion string
+	// LegacyKey 旧版加密密钥，用于解密未使用信封加密的历史数据
+	LegacyKey string
+	// IndexKey 盲索引密钥，修改后已有的盲索引全部失效
+	// 为空时依次使用旧版密钥和第一个主密钥
+	IndexKey string
+	// DecryptOnlyKey 早期版本未配置加密密钥时沿用的 JWT 密钥，只用于解密历史数据，不用于加密新数据和计算盲索引
+	// 未配置旧版密钥时用于解密旧版密文，未配置 DefaultKeyVersion 时用于解密该版本的信封密文
+	DecryptOnlyKey string
+}
+
+// KeyManager 基于信封加密和多版本主密钥的加密服务
+// 新数据始终使用当前版本的主密钥加密，历史版本的主密钥仅用于解密，便于平滑轮换
+type KeyManager struct {
+	keys     map[string][]byte
+	versions []string // 已配置的主密钥版本，不含只用于解密的密钥
+	active   string
+	legacy   EncryptionService
+	indexKey []byte
+}
+
+// NewKeyManager 创建密钥管理器
+func NewKeyManager(cfg KeyManagerConfig) (*KeyManager, error) {
+	m := &KeyManager{keys: make(map[string][]byte)}
+
+	switch {
+	case cfg.LegacyKey != "":
+		m.legacy = NewAESEncryptionService(cfg.LegacyKey)
+	case cfg.DecryptOnlyKey != "":
+		m.legacy = NewAESEncryptionService(cfg.DecryptOnlyKey)
+	}
+
+	for _, entry := range strings.Split(cfg.Keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		version, secret, ok := strings.Cut(entry, ":")
+		version = strings.TrimSpace(version)
+		secret = strings.TrimSpace(secret)
+		if !ok || version == "" || secret == "" {
+			return nil, fmt.Errorf("invalid encryption key entry %q, expected version:key", entry)
+		}
+		if version == LegacyKeyVersion {
+			return nil, fmt.Errorf("encryption key version %q is reserved", version)
+		}
+		if _, exists := m.keys[version]; exists {
+			return nil, fmt.Errorf("duplicate encryption key version %q", version)
+		}
+
+		m.keys[version] = deriveKey(secret)
+		m.versions = append(m.versions, version)
+	}
+
+	// 未配置密钥列表时由旧版密钥派生主密钥，保证新数据仍使用信封加密
+	// 只用于解密的密钥不能作为主密钥，两者都未配置时拒绝启动
+	if len(m.versions) == 0 {
+		if cfg.LegacyKey == "" {
+			return nil, ErrNoEncryptionKey
+		}
+		m.keys[DefaultKeyVersion] = deriveKey(cfg.LegacyKey)
+		m.versions = append(m.versions, DefaultKeyVersion)
+	}
+
+	m.active = cfg.ActiveVersion
+	if m.active == "" {
+		m.active = m.versions[len(m.versions)-1]
+	}
+	if _, ok := m.keys[m.active]; !ok {
+		return nil, fmt.Errorf("active encryption key version %q is not configured", m.active)
+	}
+
+	// 早期版本由 JWT 密钥派生 DefaultKeyVersion，注册在确定当前版本之后，只用于解密
+	if _, exists := m.keys[DefaultKeyVersion]; !exists && cfg.DecryptOnlyKey != "" {
+		m.keys[DefaultKeyVersion] = deriveKey(cfg.DecryptOnlyKey)
+	}
+
+	switch {
+	case cfg.IndexKey != "":
+		m.indexKey = deriveKey(cfg.IndexKey)
+	case cfg.LegacyKey != "":
+		m.indexKey = deriveKey(cfg.LegacyKey)
+	default:
+		m.indexKey = m.keys[m.versions[0]]
+	}
+
+	return m, nil
+}
+
+// deriveKey 将配置的密钥转换为 32 字节的 AES-256 密钥
+func deriveKey(secret string) []byte {
+	if decoded, err := base64.StdEncoding.DecodeString(secret); err == nil && len(decoded) == 32 {
+		return decoded
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// ActiveVersion 当前用于加密的主密钥版本
+func (m *KeyManager) ActiveVersion() string {
+	return m.active
+}
+
+// Versions 已配置的主密钥版本，不含只用于解密的密钥
+func (m *KeyManager) Versions() []string {
+	return append([]string(nil), m.versions...)
+}
+
+// KeyVersion 返回密文使用的主密钥版本，旧版密文返回 LegacyKeyVersion
+func (m *KeyManager) KeyVersion(ciphertext string) string {
+	if !strings.HasPrefix(ciphertext, envelopePrefix) {
+		return LegacyKeyVersion
+	}
+	version, _, _ := strings.Cut(strings.TrimPrefix(ciphertext, envelopePrefix), ":")
+	return version
+}
+
+// BlindIndex 计算字段值的盲索引（HMAC-SHA256），值在计算前转为小写并去除首尾空白
+func (m *KeyManager) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, m.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt 使用当前版本的主密钥加密字符串
+func (m *KeyManager) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", err
+	}
+
+	wrapped, err := sealGCM(m.keys[m.active], dek, []byte(m.active))
+	if err != nil {
+		return "", err
+	}
+	data, err := sealGCM(dek, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + m.active + ":" +
+		base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt 解密字符串，根据密文中的版本选择主密钥，兼容旧版密文
+func (m *KeyManager) Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(ciphertext, envelopePrefix) {
+		if m.legacy == nil {
+			return "", fmt.Errorf("%w: %s", ErrUnknownKeyVersion, LegacyKeyVersion)
+		}
+		return m.legacy.Decrypt(ciphertext)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(ciphertext, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", ErrInvalidCiphertext
+	}
+
+	version := parts[0]
+	kek, ok := m.keys[version]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKeyVersion, version)
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	dek, err := openGCM(kek, wrapped, []byte(version))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	plaintext, err := openGCM(dek, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Rotate 将密文重新加密为当前版本，已是当前版本时原样返回
+func (m *KeyManager) Rotate(ciphertext string) (string, bool, error) {
+	if ciphertext == "" || m.KeyVersion(ciphertext) == m.active {
+		return ciphertext, false, nil
+	}
+
+	plaintext, err := m.Decrypt(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	rotated, err := m.Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return rotated, true, nil
+}
+
+// EncryptDataSourceConfig 加密数据源配置中的敏感信息
+func (m *KeyManager) EncryptDataSourceConfig(config *models.DataSourceConfig) error {
+	return m.applyDataSourceConfig(config, func(value string) (string, bool, error) {
+		encrypted, err := m.Encrypt(value)
+		return encrypted, true, err
+	})
+}
+
+// DecryptDataSourceConfig 解密数据源配置中的敏感信息
+func (m *KeyManager) DecryptDataSourceConfig(config *models.DataSourceConfig) error {
+	return m.applyDataSourceConfig(config, func(value string) (string, bool, error) {
+		decrypted, err := m.Decrypt(value)
+		return decrypted, true, err
+	})
+}
+
+// RotateDataSourceConfig 将数据源配置中的密文重新加密为当前主密钥版本
+func (m *KeyManager) RotateDataSourceConfig(config *models.DataSourceConfig) (bool, error) {
+	changed := false
+	err := m.applyDataSourceConfig(config, func(value string) (string, bool, error) {
+		rotated, ok, err := m.Rotate(value)
+		changed = changed || ok
+		return rotated, ok, err
+	})
+	return changed, err
+}
+
+// applyDataSourceConfig 对数据源配置中的密码、Token、kubeconfig、代理地址和客户端证书执行转换
+func (m *KeyManager) applyDataSourceConfig(config *models.DataSourceConfig, fn func(string) (string, bool, error)) error {
+	if config == nil {
+		return nil
+	}
+
+	for _, field := range []**string{&config.Password, &config.Token, &config.Kubeconfig, &config.ProxyURL, &config.ClientCert, &config.ClientKey} {
+		if *field == nil || **field == "" {
+			continue
+		}
+		value, changed, err := fn(**field)
+		if err != nil {
+			return err
+		}
+		if changed {
+			*field = &value
+		}
+	}
+	return nil
+}
+
+// sealGCM 使用 AES-GCM 加密，随机 nonce 放在密文前
+func sealGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openGCM 解密 sealGCM 生成的密文
+func openGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, additionalData)
+}
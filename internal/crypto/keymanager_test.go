@@ -0,0 +1,195 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestNewKeyManager(t *testing.T) {
+	rawKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+	tests := []struct {
+		name       string
+		cfg        KeyManagerConfig
+		wantActive string
+		wantErr    bool
+	}{
+		{"最后一个密钥为当前版本", KeyManagerConfig{Keys: "v1:first, v2:" + rawKey}, "v2", false},
+		{"指定当前版本", KeyManagerConfig{Keys: "v1:first,v2:second", ActiveVersion: "v1"}, "v1", false},
+		{"仅配置旧版密钥", KeyManagerConfig{LegacyKey: "jwt-secret"}, DefaultKeyVersion, false},
+		{"未配置任何密钥", KeyManagerConfig{}, "", true},
+		{"仅配置只用于解密的密钥", KeyManagerConfig{DecryptOnlyKey: "jwt-secret"}, "", true},
+		{"只用于解密的密钥不能作为当前版本", KeyManagerConfig{Keys: "v1:first", ActiveVersion: DefaultKeyVersion, DecryptOnlyKey: "jwt-secret"}, "", true},
+		{"格式错误", KeyManagerConfig{Keys: "v1"}, "", true},
+		{"版本重复", KeyManagerConfig{Keys: "v1:a,v1:b"}, "", true},
+		{"保留版本", KeyManagerConfig{Keys: "legacy:a"}, "", true},
+		{"当前版本不存在", KeyManagerConfig{Keys: "v1:a", ActiveVersion: "v2"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewKeyManager(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, m.ActiveVersion())
+		})
+	}
+}
+
+func TestKeyManager_EncryptDecrypt(t *testing.T) {
+	m, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first"})
+	require.NoError(t, err)
+
+	ciphertext, err := m.Encrypt("s3cret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "enc:v1:v1:"))
+	assert.Equal(t, "v1", m.KeyVersion(ciphertext))
+
+	// 每次加密使用不同的数据密钥
+	again, err := m.Encrypt("s3cret")
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again)
+
+	plaintext, err := m.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", plaintext)
+
+	empty, err := m.Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// 篡改密文无法解密
+	_, err = m.Decrypt(ciphertext[:len(ciphertext)-4] + "AAAA")
+	assert.Error(t, err)
+
+	_, err = m.Decrypt("enc:v1:v9:AAAA:AAAA")
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+
+	_, err = m.Decrypt("enc:v1:v1:broken")
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestKeyManager_LegacyCiphertext(t *testing.T) {
+	legacy, err := NewAESEncryptionService("jwt-secret").Encrypt("password")
+	require.NoError(t, err)
+
+	m, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	assert.Equal(t, LegacyKeyVersion, m.KeyVersion(legacy))
+
+	plaintext, err := m.Decrypt(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "password", plaintext)
+
+	// 未配置旧版密钥时无法解密历史数据
+	withoutLegacy, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first"})
+	require.NoError(t, err)
+	_, err = withoutLegacy.Decrypt(legacy)
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+}
+
+func TestKeyManager_DecryptOnlyKey(t *testing.T) {
+	_, err := NewKeyManager(KeyManagerConfig{DecryptOnlyKey: "jwt-secret"})
+	assert.ErrorIs(t, err, ErrNoEncryptionKey)
+
+	// 早期版本未配置加密密钥时，旧版密文、v0 信封密文和盲索引都由 JWT 密钥派生
+	legacy, err := NewAESEncryptionService("jwt-secret").Encrypt("password")
+	require.NoError(t, err)
+	early, err := NewKeyManager(KeyManagerConfig{LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	envelope, err := early.Encrypt("token")
+	require.NoError(t, err)
+
+	m, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first", DecryptOnlyKey: "jwt-secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", m.ActiveVersion())
+	assert.Equal(t, []string{"v1"}, m.Versions())
+
+	plaintext, err := m.Decrypt(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "password", plaintext)
+	plaintext, err = m.Decrypt(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, "token", plaintext)
+
+	// 新数据和轮换后的数据使用配置的主密钥，盲索引不再由 JWT 密钥派生
+	rotated, changed, err := m.Rotate(envelope)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "v1", m.KeyVersion(rotated))
+	assert.NotEqual(t, early.BlindIndex("alice@example.com"), m.BlindIndex("alice@example.com"))
+
+	// 配置的 v0 优先于只用于解密的密钥
+	configured, err := NewKeyManager(KeyManagerConfig{Keys: "v0:zero", DecryptOnlyKey: "jwt-secret"})
+	require.NoError(t, err)
+	_, err = configured.Decrypt(envelope)
+	assert.Error(t, err)
+}
+
+func TestKeyManager_RotateDataSourceConfig(t *testing.T) {
+	old, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+
+	password := "db-password"
+	token := "api-token"
+	config := &models.DataSourceConfig{Password: &password, Token: &token}
+	require.NoError(t, old.EncryptDataSourceConfig(config))
+
+	// 追加 v2 并切换为当前版本
+	current, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first,v2:second", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+
+	changed, err := current.RotateDataSourceConfig(config)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "v2", current.KeyVersion(*config.Password))
+	assert.Equal(t, "v2", current.KeyVersion(*config.Token))
+
+	// 已是当前版本时不再变化
+	changed, err = current.RotateDataSourceConfig(config)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// 移除 v1 后仍可解密
+	rotated, err := NewKeyManager(KeyManagerConfig{Keys: "v2:second"})
+	require.NoError(t, err)
+	require.NoError(t, rotated.DecryptDataSourceConfig(config))
+	assert.Equal(t, "db-password", *config.Password)
+	assert.Equal(t, "api-token", *config.Token)
+}
+
+func TestKeyManager_BlindIndex(t *testing.T) {
+	old, err := NewKeyManager(KeyManagerConfig{Keys: "v1:first", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	current, err := NewKeyManager(KeyManagerConfig{Keys: "v2:second", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+
+	// 盲索引与主密钥版本无关，忽略大小写和首尾空白
+	index := old.BlindIndex("Alice@Example.com ")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, current.BlindIndex("alice@example.com"))
+	assert.NotEqual(t, index, current.BlindIndex("bob@example.com"))
+
+	// 单独配置的索引密钥优先
+	indexed, err := NewKeyManager(KeyManagerConfig{Keys: "v2:second", LegacyKey: "jwt-secret", IndexKey: "index-secret"})
+	require.NoError(t, err)
+	assert.NotEqual(t, index, indexed.BlindIndex("alice@example.com"))
+}
+
+func TestIsSensitiveHeader(t *testing.T) {
+	for _, name := range []string{"Authorization", "X-Api-Key", "X-Auth-Token", "X-Hub-Signature"} {
+		assert.True(t, IsSensitiveHeader(name), name)
+	}
+	for _, name := range []string{"Content-Type", "X-Request-ID"} {
+		assert.False(t, IsSensitiveHeader(name), name)
+	}
+}
@@ -7,20 +7,28 @@ import (
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"pulse/internal/config"
+	"pulse/migrations"
 )
 
 // DB 数据库连接包装器
 type DB struct {
 	*sqlx.DB
-	config *config.DatabaseConfig
-	logger *zap.Logger
+	config   *config.DatabaseConfig
+	logger   *zap.Logger
+	replicas []*replica
+	reader   *ReadRouter
+
+	slowQueries     *SlowQueryStats
+	canceledQueries *CanceledQueryStats
 }
 
 // NewConnection 创建新的数据库连接（兼容性函数）
@@ -40,17 +48,29 @@ func New(cfg *config.DatabaseConfig, logger *zap.Logger) (*DB, error) {
 		return nil, fmt.Errorf("logger is required")
 	}
 
-	// 连接数据库
-	db, err := sqlx.Connect("postgres", cfg.GetDSN())
+	// 连接数据库，主库和只读副本共用慢查询和中断语句计数
+	slowQueries := &SlowQueryStats{threshold: cfg.SlowQueryThreshold}
+	if slowQueries.threshold < 0 {
+		slowQueries.threshold = 0
+	}
+	canceledQueries := &CanceledQueryStats{}
+	open := func(dsn string) (*sqlx.DB, error) {
+		return openDB(dsn, slowQueries, canceledQueries, logger)
+	}
+
+	db, err := open(cfg.GetDSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// 配置连接池
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
-	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	configurePool := func(db *sqlx.DB) {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+	configurePool(db)
 
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -66,17 +86,90 @@ func New(cfg *config.DatabaseConfig, logger *zap.Logger) (*DB, error) {
 		zap.Int("max_idle_conns", cfg.MaxIdleConns),
 		zap.Duration("conn_max_lifetime", cfg.ConnMaxLifetime),
 		zap.Duration("conn_max_idle_time", cfg.ConnMaxIdleTime),
+		zap.Duration("slow_query_threshold", slowQueries.threshold),
 	)
 
+	// 连接只读副本
+	replicas, err := connectReplicas(cfg.ReplicaDSNs, open, configurePool, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if len(replicas) > 0 {
+		logger.Info("Database replicas configured", zap.Int("replicas", len(replicas)))
+	}
+
 	return &DB{
-		DB:     db,
-		config: cfg,
-		logger: logger,
+		DB:       db,
+		config:   cfg,
+		logger:   logger,
+		replicas: replicas,
+		reader:   newReadRouter(db, replicas, logger),
+
+		slowQueries:     slowQueries,
+		canceledQueries: canceledQueries,
 	}, nil
 }
 
+// Reader 返回只读查询路由器，未配置副本时所有查询走主库
+func (db *DB) Reader() *ReadRouter {
+	return db.reader
+}
+
+// Metrics 返回主库连接池、慢查询和中断语句指标，供 /health/metrics 输出
+func (db *DB) Metrics() map[string]interface{} {
+	stats := db.Stats()
+	return map[string]interface{}{
+		"pool": map[string]interface{}{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+			"max_idle_closed":      stats.MaxIdleClosed,
+			"max_idle_time_closed": stats.MaxIdleTimeClosed,
+			"max_lifetime_closed":  stats.MaxLifetimeClosed,
+		},
+		"healthy_replicas": db.reader.HealthyReplicas(),
+		"slow_queries":     db.slowQueries.Snapshot(),
+		"canceled_queries": db.canceledQueries.Snapshot(),
+	}
+}
+
+// StartReplicaHealthCheck 启动只读副本健康检查，ctx 取消后退出
+func (db *DB) StartReplicaHealthCheck(ctx context.Context) {
+	if len(db.replicas) == 0 {
+		return
+	}
+
+	interval := db.config.ReplicaHealthCheckInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				db.reader.checkReplicas(ctx)
+			}
+		}
+	}()
+}
+
 // Close 关闭数据库连接
 func (db *DB) Close() error {
+	for _, rep := range db.replicas {
+		if err := rep.db.Close(); err != nil {
+			db.logger.Warn("Failed to close replica connection", zap.String("replica", rep.name), zap.Error(err))
+		}
+	}
 	if db.DB != nil {
 		db.logger.Info("Closing database connection")
 		return db.DB.Close()
@@ -123,10 +216,35 @@ func (db *DB) GetStats() sql.DBStats {
 	return db.Stats()
 }
 
-// RunMigrations 运行数据库迁移
+// migrationSource 返回迁移文件来源，用于日志
+func (db *DB) migrationSource() string {
+	if db.config.MigrationPath == "" {
+		return "embedded"
+	}
+	return db.config.MigrationPath
+}
+
+// newMigrate 创建 migrate 实例，未配置迁移路径时使用编译进二进制的迁移文件
+func (db *DB) newMigrate(driver migratedb.Driver) (*migrate.Migrate, error) {
+	if db.config.MigrationPath == "" {
+		source, err := iofs.New(migrations.FS, ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+		}
+		return migrate.NewWithInstance("iofs", source, "postgres", driver)
+	}
+	return migrate.NewWithDatabaseInstance(db.config.MigrationPath, "postgres", driver)
+}
+
+// RunMigrations 持有迁移锁运行数据库迁移
 func (db *DB) RunMigrations() error {
+	return db.withMigrationLock(db.runMigrations)
+}
+
+// runMigrations 运行数据库迁移
+func (db *DB) runMigrations() error {
 	db.logger.Info("Starting database migrations",
-		zap.String("migration_path", db.config.MigrationPath),
+		zap.String("migration_source", db.migrationSource()),
 		zap.String("migration_table", db.config.MigrationTable),
 	)
 
@@ -139,11 +257,7 @@ func (db *DB) RunMigrations() error {
 	}
 
 	// 创建 migrate 实例
-	m, err := migrate.NewWithDatabaseInstance(
-		db.config.MigrationPath,
-		"postgres",
-		driver,
-	)
+	m, err := db.newMigrate(driver)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %w", err)
 	}
@@ -188,11 +302,16 @@ func (db *DB) RunMigrations() error {
 	return nil
 }
 
-// RollbackMigrations 回滚数据库迁移
+// RollbackMigrations 持有迁移锁回滚数据库迁移
 func (db *DB) RollbackMigrations(steps int) error {
 	if steps <= 0 {
 		return fmt.Errorf("steps must be positive")
 	}
+	return db.withMigrationLock(func() error { return db.rollbackMigrations(steps) })
+}
+
+// rollbackMigrations 回滚数据库迁移
+func (db *DB) rollbackMigrations(steps int) error {
 
 	db.logger.Info("Starting database migration rollback",
 		zap.Int("steps", steps),
@@ -207,11 +326,7 @@ func (db *DB) RollbackMigrations(steps int) error {
 	}
 
 	// 创建 migrate 实例
-	m, err := migrate.NewWithDatabaseInstance(
-		db.config.MigrationPath,
-		"postgres",
-		driver,
-	)
+	m, err := db.newMigrate(driver)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %w", err)
 	}
@@ -259,11 +374,7 @@ func (db *DB) MigrationStatus() (version uint, dirty bool, err error) {
 	}
 
 	// 创建 migrate 实例
-	m, err := migrate.NewWithDatabaseInstance(
-		db.config.MigrationPath,
-		"postgres",
-		driver,
-	)
+	m, err := db.newMigrate(driver)
 	if err != nil {
 		return 0, false, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
@@ -278,8 +389,13 @@ func (db *DB) MigrationStatus() (version uint, dirty bool, err error) {
 	return version, dirty, nil
 }
 
-// ForceMigrationVersion 强制设置迁移版本（用于修复dirty状态）
+// ForceMigrationVersion 持有迁移锁强制设置迁移版本（用于修复dirty状态）
 func (db *DB) ForceMigrationVersion(version int) error {
+	return db.withMigrationLock(func() error { return db.forceMigrationVersion(version) })
+}
+
+// forceMigrationVersion 强制设置迁移版本
+func (db *DB) forceMigrationVersion(version int) error {
 	db.logger.Info("Forcing migration version",
 		zap.Int("version", version),
 	)
@@ -293,11 +409,7 @@ func (db *DB) ForceMigrationVersion(version int) error {
 	}
 
 	// 创建 migrate 实例
-	m, err := migrate.NewWithDatabaseInstance(
-		db.config.MigrationPath,
-		"postgres",
-		driver,
-	)
+	m, err := db.newMigrate(driver)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %w", err)
 	}
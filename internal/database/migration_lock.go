@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// migrationLockPollInterval 等待迁移锁时的重试间隔
+const migrationLockPollInterval = 2 * time.Second
+
+// ErrMigrationLocked 其他实例正在执行迁移，等待超时
+var ErrMigrationLocked = errors.New("another migration is in progress")
+
+// migrationLockKey 迁移锁使用的 PostgreSQL 会话级 advisory lock 键，按迁移表区分
+func migrationLockKey(table string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("pulse:migrations:" + table))
+	return int64(h.Sum64())
+}
+
+// withMigrationLock 持有迁移锁执行 fn，避免多个副本同时迁移。
+// 锁被占用时每隔 migrationLockPollInterval 重试，超过 DB_MIGRATION_LOCK_TIMEOUT 返回 ErrMigrationLocked；
+// 锁绑定在专用连接上，进程异常退出时随连接断开自动释放
+func (db *DB) withMigrationLock(fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), db.config.MigrationLockTimeout)
+	defer cancel()
+
+	conn, err := db.DB.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	key := migrationLockKey(db.config.MigrationTable)
+	for waited := false; ; waited = true {
+		var acquired bool
+		if err := conn.QueryRowContext(context.Background(), "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if !waited {
+			db.logger.Info("Waiting for another instance to finish migrations",
+				zap.Duration("timeout", db.config.MigrationLockTimeout))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: lock not released within %s", ErrMigrationLocked, db.config.MigrationLockTimeout)
+		case <-time.After(migrationLockPollInterval):
+		}
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			db.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	return fn()
+}
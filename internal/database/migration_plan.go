@@ -0,0 +1,275 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"pulse/migrations"
+)
+
+// 迁移语句的风险类型
+const (
+	// MigrationRiskDataLoss 删除表、列或数据，执行后无法通过回滚恢复
+	MigrationRiskDataLoss = "data_loss"
+	// MigrationRiskBreaking 蓝绿发布期间仍在运行的旧版本实例会因此出错
+	MigrationRiskBreaking = "breaking"
+	// MigrationRiskLocking 长时间持有表锁，阻塞读写
+	MigrationRiskLocking = "locking"
+)
+
+// MigrationWarning 迁移语句的风险提示
+type MigrationWarning struct {
+	Risk      string `json:"risk"`
+	Message   string `json:"message"`
+	Statement string `json:"statement"`
+}
+
+// PendingMigration 待执行的迁移
+type PendingMigration struct {
+	Version  uint               `json:"version"`
+	Name     string             `json:"name"`
+	Warnings []MigrationWarning `json:"warnings,omitempty"`
+}
+
+// MigrationPlan 迁移计划
+type MigrationPlan struct {
+	CurrentVersion uint               `json:"current_version"`
+	Dirty          bool               `json:"dirty"`
+	Pending        []PendingMigration `json:"pending"`
+}
+
+// HasRisk 待执行的迁移是否包含指定类型的风险
+func (p *MigrationPlan) HasRisk(risks ...string) bool {
+	for _, pending := range p.Pending {
+		for _, warning := range pending.Warnings {
+			for _, risk := range risks {
+				if warning.Risk == risk {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// migrationRule 迁移语句的风险检查规则，按规范化（大写、单空格）后的语句匹配，
+// 匹配到的片段再满足 exclude 时不提示
+type migrationRule struct {
+	risk    string
+	pattern *regexp.Regexp
+	exclude *regexp.Regexp
+	message string
+}
+
+var migrationRules = []migrationRule{
+	{risk: MigrationRiskDataLoss, pattern: regexp.MustCompile(`^DROP (TABLE|SCHEMA|MATERIALIZED VIEW)\b`),
+		message: "drops a table or schema and all of its data"},
+	{risk: MigrationRiskDataLoss, pattern: regexp.MustCompile(`^ALTER TABLE .*\bDROP COLUMN\b`),
+		message: "drops a column and its data; instances still running the previous version will fail to read or write it"},
+	{risk: MigrationRiskDataLoss, pattern: regexp.MustCompile(`^(TRUNCATE|DELETE FROM)\b`),
+		message: "deletes rows"},
+	{risk: MigrationRiskDataLoss, pattern: regexp.MustCompile(`^(DO|CREATE (OR REPLACE )?(FUNCTION|PROCEDURE))\b.*\b(DROP TABLE|DROP COLUMN|TRUNCATE|DELETE FROM)\b`),
+		message: "runs a procedural block that may drop tables, columns or rows; review it manually"},
+	{risk: MigrationRiskBreaking, pattern: regexp.MustCompile(`^ALTER TABLE .*\bRENAME\b`),
+		message: "renames a table or column; instances still running the previous version use the old name"},
+	{risk: MigrationRiskBreaking, pattern: regexp.MustCompile(`\bALTER COLUMN \S+ SET NOT NULL\b`),
+		message: "adds a NOT NULL constraint; writes from the previous version that omit the column will fail"},
+	{risk: MigrationRiskBreaking, pattern: regexp.MustCompile(`\bADD COLUMN (IF NOT EXISTS )?\S+ [^,]*\bNOT NULL\b[^,]*`),
+		exclude: regexp.MustCompile(`\bDEFAULT\b|\bGENERATED\b`),
+		message: "adds a NOT NULL column without a default; inserts from the previous version will fail"},
+	{risk: MigrationRiskLocking, pattern: regexp.MustCompile(`\bALTER COLUMN \S+ (SET DATA )?TYPE\b`),
+		message: "changes a column type, which may rewrite the table under an exclusive lock"},
+	{risk: MigrationRiskLocking, pattern: regexp.MustCompile(`^CREATE (UNIQUE )?INDEX( CONCURRENTLY)?\b`),
+		exclude: regexp.MustCompile(`\bCONCURRENTLY\b`),
+		message: "builds an index without CONCURRENTLY, blocking writes to the table until it finishes"},
+}
+
+var (
+	createdTablePattern = regexp.MustCompile(`^CREATE TABLE (IF NOT EXISTS )?([^\s(]+)`)
+	targetTablePattern  = regexp.MustCompile(`^(?:ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([^\s(]+)|CREATE (?:UNIQUE )?INDEX .*? ON (?:ONLY )?([^\s(]+))`)
+)
+
+// AnalyzeMigration 检查迁移 SQL 中的破坏性语句，
+// 同一迁移中新建的表还没有数据和旧版本的读写，对其加锁的语句不提示
+func AnalyzeMigration(sql string) []MigrationWarning {
+	var warnings []MigrationWarning
+	created := make(map[string]bool)
+	for _, statement := range splitSQLStatements(sql) {
+		normalized := strings.ToUpper(strings.Join(strings.Fields(statement), " "))
+		if m := createdTablePattern.FindStringSubmatch(normalized); m != nil {
+			created[m[2]] = true
+		}
+		var target string
+		if m := targetTablePattern.FindStringSubmatch(normalized); m != nil {
+			target = m[1] + m[2]
+		}
+
+		for _, rule := range migrationRules {
+			match := rule.pattern.FindString(normalized)
+			if match == "" {
+				continue
+			}
+			if rule.exclude != nil && rule.exclude.MatchString(match) {
+				continue
+			}
+			if rule.risk == MigrationRiskLocking && created[target] {
+				continue
+			}
+			warnings = append(warnings, MigrationWarning{
+				Risk:      rule.risk,
+				Message:   rule.message,
+				Statement: summarizeStatement(statement),
+			})
+		}
+	}
+	return warnings
+}
+
+// splitSQLStatements 按分号拆分 SQL 语句，忽略注释以及字符串和 $$ 函数体中的分号
+func splitSQLStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	var dollarTag string
+	inString := false
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case dollarTag != "":
+			if strings.HasPrefix(sql[i:], dollarTag) {
+				current.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+		case inString:
+			if ch == '\'' {
+				inString = false
+			}
+		case ch == '\'':
+			inString = true
+		case ch == '-' && strings.HasPrefix(sql[i:], "--"):
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+				current.WriteByte('\n')
+			} else {
+				i = len(sql)
+			}
+			continue
+		case ch == '$':
+			if tag := dollarQuoteTag.FindString(sql[i:]); tag != "" {
+				dollarTag = tag
+				current.WriteString(tag)
+				i += len(tag) - 1
+				continue
+			}
+		case ch == ';':
+			flush()
+			continue
+		}
+		current.WriteByte(ch)
+	}
+	flush()
+	return statements
+}
+
+var dollarQuoteTag = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+// summarizeStatement 将语句压缩为单行，过长时截断
+func summarizeStatement(statement string) string {
+	const maxLength = 120
+	summary := strings.Join(strings.Fields(statement), " ")
+	if len(summary) > maxLength {
+		return summary[:maxLength] + "..."
+	}
+	return summary
+}
+
+// openMigrationSource 打开迁移文件来源，未配置迁移路径时使用编译进二进制的迁移文件
+func (db *DB) openMigrationSource() (source.Driver, error) {
+	if db.config.MigrationPath == "" {
+		driver, err := iofs.New(migrations.FS, ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+		}
+		return driver, nil
+	}
+	driver, err := source.Open(db.config.MigrationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration source: %w", err)
+	}
+	return driver, nil
+}
+
+// PlanMigrations 列出待执行的迁移及其中的破坏性语句，不修改数据库
+func (db *DB) PlanMigrations() (*MigrationPlan, error) {
+	version, dirty, err := db.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := db.openMigrationSource()
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close()
+
+	pending, err := pendingMigrations(driver, version)
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationPlan{CurrentVersion: version, Dirty: dirty, Pending: pending}, nil
+}
+
+// pendingMigrations 读取版本大于 current 的迁移，current 为 0 时返回全部迁移
+func pendingMigrations(driver source.Driver, current uint) ([]PendingMigration, error) {
+	pending := []PendingMigration{}
+	version, err := driver.First()
+	for err == nil {
+		if version > current {
+			migration, readErr := readPendingMigration(driver, version)
+			if readErr != nil {
+				return nil, readErr
+			}
+			pending = append(pending, migration)
+		}
+		version, err = driver.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	return pending, nil
+}
+
+// readPendingMigration 读取单个迁移的 up 文件并检查风险
+func readPendingMigration(driver source.Driver, version uint) (PendingMigration, error) {
+	body, name, err := driver.ReadUp(version)
+	if errors.Is(err, fs.ErrNotExist) {
+		// 只有 down 文件的版本执行 up 时不做任何变更
+		return PendingMigration{Version: version}, nil
+	}
+	if err != nil {
+		return PendingMigration{}, fmt.Errorf("failed to read migration %d: %w", version, err)
+	}
+	defer body.Close()
+
+	sql, err := io.ReadAll(body)
+	if err != nil {
+		return PendingMigration{}, fmt.Errorf("failed to read migration %d: %w", version, err)
+	}
+	return PendingMigration{Version: version, Name: name, Warnings: AnalyzeMigration(string(sql))}, nil
+}
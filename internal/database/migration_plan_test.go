@@ -0,0 +1,155 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+func warningRisks(warnings []MigrationWarning) []string {
+	risks := []string{}
+	for _, warning := range warnings {
+		risks = append(risks, warning.Risk)
+	}
+	return risks
+}
+
+func TestAnalyzeMigration(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"create table", "CREATE TABLE foo (id UUID PRIMARY KEY, name TEXT NOT NULL);", []string{}},
+		{"drop table", "DROP TABLE IF EXISTS foo;", []string{MigrationRiskDataLoss}},
+		{"drop column", "ALTER TABLE foo DROP COLUMN bar;", []string{MigrationRiskDataLoss}},
+		{"drop constraint", "ALTER TABLE foo DROP CONSTRAINT foo_check, ALTER COLUMN bar DROP DEFAULT;", []string{}},
+		{"rename column", "ALTER TABLE foo RENAME COLUMN bar TO baz;", []string{MigrationRiskBreaking}},
+		{"set not null", "ALTER TABLE foo ALTER COLUMN bar SET NOT NULL;", []string{MigrationRiskBreaking}},
+		{"add not null without default", "ALTER TABLE foo ADD COLUMN bar TEXT NOT NULL;", []string{MigrationRiskBreaking}},
+		{"add not null with default", "ALTER TABLE foo ADD COLUMN bar TEXT NOT NULL DEFAULT '', ADD COLUMN baz INT;", []string{}},
+		{"change type", "ALTER TABLE foo ALTER COLUMN bar TYPE JSONB USING bar::jsonb;", []string{MigrationRiskLocking}},
+		{"blocking index", "CREATE UNIQUE INDEX idx_foo ON foo(bar);", []string{MigrationRiskLocking}},
+		{"concurrent index", "CREATE INDEX CONCURRENTLY idx_foo ON foo(bar);", []string{}},
+		{"index on new table", "CREATE TABLE IF NOT EXISTS foo (bar TEXT);\nCREATE INDEX idx_foo ON foo(bar);", []string{}},
+		{"procedural drop", "DO $$ BEGIN EXECUTE format('DROP TABLE %I', 'foo'); END $$;", []string{MigrationRiskDataLoss}},
+		{"comments and strings", "-- DROP TABLE foo;\nINSERT INTO foo VALUES ('DROP TABLE bar;');", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, warningRisks(AnalyzeMigration(tt.sql)))
+		})
+	}
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	sql := `CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+-- 注释中的分号;
+UPDATE foo SET note = 'a;b';`
+
+	statements := splitSQLStatements(sql)
+	require.Len(t, statements, 2)
+	assert.Contains(t, statements[0], "RETURN NEW;")
+	assert.Equal(t, "UPDATE foo SET note = 'a;b'", statements[1])
+}
+
+func TestPendingMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_create_foo.up.sql":   {Data: []byte("CREATE TABLE foo (id INT);")},
+		"001_create_foo.down.sql": {Data: []byte("DROP TABLE foo;")},
+		"002_drop_bar.up.sql":     {Data: []byte("ALTER TABLE foo DROP COLUMN bar;")},
+		"003_rename.up.sql":       {Data: []byte("ALTER TABLE foo RENAME TO foos;")},
+	}
+	driver, err := iofs.New(fsys, ".")
+	require.NoError(t, err)
+	defer driver.Close()
+
+	pending, err := pendingMigrations(driver, 1)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, uint(2), pending[0].Version)
+	assert.Equal(t, "drop_bar", pending[0].Name)
+
+	plan := &MigrationPlan{CurrentVersion: 1, Pending: pending}
+	assert.True(t, plan.HasRisk(MigrationRiskDataLoss))
+	assert.True(t, plan.HasRisk(MigrationRiskBreaking))
+	assert.False(t, plan.HasRisk(MigrationRiskLocking))
+
+	all, err := pendingMigrations(driver, 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestCompareSchema(t *testing.T) {
+	type base struct {
+		ID string `db:"id"`
+	}
+	type model struct {
+		base
+		Name     string `db:"name"`
+		Computed string `db:"-"`
+		Ignored  string
+	}
+	assert.Equal(t, []string{"id", "name"}, ModelColumns(model{}))
+
+	tables := []SchemaTable{{Table: "foos", Model: model{}}, {Table: "bars", Model: model{}}}
+	drifts := CompareSchema(tables, map[string]map[string]ColumnInfo{
+		"foos": {
+			"id":         {},
+			"owner":      {},
+			"created_at": {HasDefault: true},
+			"note":       {Nullable: true},
+		},
+	})
+	assert.Equal(t, []SchemaDrift{
+		{Table: "foos", Column: "name", Kind: SchemaDriftMissingColumn},
+		{Table: "foos", Column: "owner", Kind: SchemaDriftRequiredColumn},
+		{Table: "bars", Kind: SchemaDriftMissingTable},
+	}, drifts)
+
+	// 注册的模型都应有可校验的列
+	for _, table := range ModelTables {
+		assert.NotEmpty(t, ModelColumns(table.Model), table.Table)
+	}
+	assert.Contains(t, ModelColumns(models.AlertSnooze{}), "expires_at")
+}
+
+func TestWithMigrationLock(t *testing.T) {
+	sqlxDB, mock := newMockDB(t)
+	cfg := &config.DatabaseConfig{MigrationTable: "schema_migrations"}
+	db := &DB{DB: sqlxDB, config: cfg, logger: zap.NewNop()}
+	key := migrationLockKey(cfg.MigrationTable)
+
+	mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(key).WillReturnResult(sqlmock.NewResult(0, 0))
+	ran := false
+	require.NoError(t, db.withMigrationLock(func() error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+
+	// 锁被占用且未配置等待时间时立即失败，不执行迁移
+	mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	err := db.withMigrationLock(func() error {
+		return errors.New("should not run")
+	})
+	assert.ErrorIs(t, err, ErrMigrationLocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// CanceledQueryStats 因请求上下文结束而中断的语句计数，主库和只读副本共用。
+// 客户端断开连接和超出请求处理时限分别计数，用于评估被浪费的数据库工作
+type CanceledQueryStats struct {
+	clientCanceled   atomic.Int64
+	deadlineExceeded atomic.Int64
+}
+
+// record 按上下文结束原因记录一次中断的语句，上下文未结束时返回 false
+func (s *CanceledQueryStats) record(ctx context.Context) bool {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.DeadlineExceeded):
+		s.deadlineExceeded.Add(1)
+	default:
+		s.clientCanceled.Add(1)
+	}
+	return true
+}
+
+// Count 中断的语句总数
+func (s *CanceledQueryStats) Count() int64 {
+	return s.clientCanceled.Load() + s.deadlineExceeded.Load()
+}
+
+// Snapshot 返回中断语句指标
+func (s *CanceledQueryStats) Snapshot() map[string]interface{} {
+	return map[string]interface{}{
+		"client_canceled":   s.clientCanceled.Load(),
+		"deadline_exceeded": s.deadlineExceeded.Load(),
+	}
+}
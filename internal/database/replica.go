@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// replica 只读副本连接
+type replica struct {
+	name    string
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// ReadRouter 只读查询路由器
+// 按轮询方式把查询分发到健康的只读副本，副本不可用或查询失败时回退到主库
+type ReadRouter struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+	logger   *zap.Logger
+}
+
+// newReadRouter 创建只读查询路由器
+func newReadRouter(primary *sqlx.DB, replicas []*replica, logger *zap.Logger) *ReadRouter {
+	return &ReadRouter{
+		primary:  primary,
+		replicas: replicas,
+		logger:   logger,
+	}
+}
+
+// pick 轮询选择一个健康的副本，没有健康副本时返回 nil
+func (r *ReadRouter) pick() *replica {
+	n := len(r.replicas)
+	if n == 0 {
+		return nil
+	}
+
+	start := r.next.Add(1)
+	for i := 0; i < n; i++ {
+		rep := r.replicas[(start+uint64(i))%uint64(n)]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// run 在副本上执行查询，失败时回退到主库重试
+func (r *ReadRouter) run(ctx context.Context, fn func(db *sqlx.DB) error) error {
+	rep := r.pick()
+	if rep == nil {
+		return fn(r.primary)
+	}
+
+	err := fn(rep.db)
+	if !shouldFallback(ctx, err) {
+		return err
+	}
+
+	// 数据库返回的错误（如与复制冲突被取消的查询）不代表副本不可用，仅对连接类错误摘除副本
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		r.markUnhealthy(rep, err)
+	}
+	r.logger.Warn("Replica query failed, falling back to primary",
+		zap.String("replica", rep.name), zap.Error(err))
+
+	return fn(r.primary)
+}
+
+// shouldFallback 判断副本查询错误是否需要回退主库
+func shouldFallback(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	// 调用方取消或超时，主库重试同样会失败
+	return ctx.Err() == nil
+}
+
+// markUnhealthy 摘除副本，等待健康检查恢复
+func (r *ReadRouter) markUnhealthy(rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		r.logger.Warn("Replica marked unhealthy", zap.String("replica", rep.name), zap.Error(err))
+	}
+}
+
+// GetContext 查询单行到结构体
+func (r *ReadRouter) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.run(ctx, func(db *sqlx.DB) error {
+		return db.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// SelectContext 查询多行到切片
+func (r *ReadRouter) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.run(ctx, func(db *sqlx.DB) error {
+		return db.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// QueryContext 执行查询
+func (r *ReadRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := r.run(ctx, func(db *sqlx.DB) (err error) {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryxContext 执行查询
+func (r *ReadRouter) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := r.run(ctx, func(db *sqlx.DB) (err error) {
+		rows, err = db.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowxContext 查询单行，查询本身失败时回退主库，扫描阶段的错误由调用方处理
+func (r *ReadRouter) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	var row *sqlx.Row
+	_ = r.run(ctx, func(db *sqlx.DB) error {
+		row = db.QueryRowxContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// BeginTxx 在副本上开启事务，用于服务端游标等需要事务的只读查询，开启失败时回退到主库。
+// 事务开启后的查询错误不再回退
+func (r *ReadRouter) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	var tx *sqlx.Tx
+	err := r.run(ctx, func(db *sqlx.DB) (err error) {
+		tx, err = db.BeginTxx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// HealthyReplicas 返回健康副本数量
+func (r *ReadRouter) HealthyReplicas() int {
+	count := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			count++
+		}
+	}
+	return count
+}
+
+// checkReplicas 探测所有副本并更新健康状态
+func (r *ReadRouter) checkReplicas(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		if err != nil {
+			r.markUnhealthy(rep, err)
+			continue
+		}
+		if !rep.healthy.Swap(true) {
+			r.logger.Info("Replica recovered", zap.String("replica", rep.name))
+		}
+	}
+}
+
+// connectReplicas 连接只读副本，连接失败的副本先标记为不健康，由健康检查恢复
+func connectReplicas(dsns []string, open func(dsn string) (*sqlx.DB, error), configure func(*sqlx.DB), logger *zap.Logger) ([]*replica, error) {
+	replicas := make([]*replica, 0, len(dsns))
+	for i, dsn := range dsns {
+		if dsn == "" {
+			continue
+		}
+
+		db, err := open(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open replica %d: %w", i, err)
+		}
+		configure(db)
+
+		rep := &replica{name: fmt.Sprintf("replica-%d", i), db: db}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := db.PingContext(ctx); err != nil {
+			logger.Warn("Replica unavailable, read queries will use primary",
+				zap.String("replica", rep.name), zap.Error(err))
+		} else {
+			rep.healthy.Store(true)
+		}
+		cancel()
+
+		replicas = append(replicas, rep)
+	}
+	return replicas, nil
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func newTestRouter(t *testing.T) (*ReadRouter, *replica, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	primary, primaryMock := newMockDB(t)
+	replicaDB, replicaMock := newMockDB(t)
+
+	rep := &replica{name: "replica-0", db: replicaDB}
+	rep.healthy.Store(true)
+	return newReadRouter(primary, []*replica{rep}, zap.NewNop()), rep, primaryMock, replicaMock
+}
+
+func TestReadRouter_UsesReplica(t *testing.T) {
+	router, _, primaryMock, replicaMock := newTestRouter(t)
+	replicaMock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	var count int64
+	require.NoError(t, router.GetContext(context.Background(), &count, "SELECT COUNT(*) FROM alerts"))
+	assert.Equal(t, int64(7), count)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestReadRouter_FallsBackToPrimary(t *testing.T) {
+	router, rep, primaryMock, replicaMock := newTestRouter(t)
+	replicaMock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New("connection refused"))
+	primaryMock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	var count int64
+	require.NoError(t, router.GetContext(context.Background(), &count, "SELECT COUNT(*) FROM alerts"))
+	assert.Equal(t, int64(3), count)
+	assert.False(t, rep.healthy.Load())
+	assert.Equal(t, 0, router.HealthyReplicas())
+
+	// 副本摘除后直接查询主库
+	primaryMock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	require.NoError(t, router.GetContext(context.Background(), &count, "SELECT COUNT(*) FROM alerts"))
+	assert.Equal(t, int64(4), count)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestReadRouter_QueryErrorKeepsReplica(t *testing.T) {
+	router, rep, primaryMock, replicaMock := newTestRouter(t)
+	replicaMock.ExpectQuery("SELECT").WillReturnError(&pq.Error{Code: "40001", Message: "canceling statement due to conflict with recovery"})
+	primaryMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a1"))
+
+	rows, err := router.QueryContext(context.Background(), "SELECT id FROM alerts")
+	require.NoError(t, err)
+	rows.Close()
+	assert.True(t, rep.healthy.Load())
+}
+
+func TestReadRouter_NoRowsDoesNotFallback(t *testing.T) {
+	router, _, primaryMock, replicaMock := newTestRouter(t)
+	replicaMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var id string
+	err := router.GetContext(context.Background(), &id, "SELECT id FROM alerts WHERE id = $1", "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
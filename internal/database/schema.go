@@ -0,0 +1,187 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// 表结构偏差类型
+const (
+	// SchemaDriftMissingTable 模型对应的表不存在
+	SchemaDriftMissingTable = "missing_table"
+	// SchemaDriftMissingColumn 模型字段对应的列不存在，读写该模型的查询会失败
+	SchemaDriftMissingColumn = "missing_column"
+	// SchemaDriftRequiredColumn 表中存在模型未声明的非空且无默认值的列，模型插入数据会失败
+	SchemaDriftRequiredColumn = "unmapped_required_column"
+)
+
+// SchemaTable 模型与数据表的对应关系
+type SchemaTable struct {
+	Table string
+	Model interface{}
+}
+
+// ModelTables 校验表结构的模型，表名与仓储查询使用的表一致，模型中带 db 标签的字段都应有对应的列
+var ModelTables = []SchemaTable{
+	{Table: "users", Model: models.User{}},
+	{Table: "alerts", Model: models.Alert{}},
+	{Table: "rules", Model: models.Rule{}},
+	{Table: "data_sources", Model: models.DataSource{}},
+	{Table: "tickets", Model: models.Ticket{}},
+	{Table: "knowledge_articles", Model: models.Knowledge{}},
+	{Table: "webhook_integrations", Model: models.WebhookIntegration{}},
+	{Table: "feature_flags", Model: models.FeatureFlag{}},
+	{Table: "postmortems", Model: models.Postmortem{}},
+	{Table: "remediation_executions", Model: models.RemediationExecution{}},
+	{Table: "chat_user_mappings", Model: models.ChatUserMapping{}},
+	{Table: "heartbeats", Model: models.Heartbeat{}},
+	{Table: "catalog_services", Model: models.CatalogService{}},
+	{Table: "slos", Model: models.SLO{}},
+	{Table: "alert_snoozes", Model: models.AlertSnooze{}},
+	{Table: "notification_calendars", Model: models.NotificationCalendar{}},
+	{Table: "language_preferences", Model: models.LanguagePreference{}},
+	{Table: "tag_jobs", Model: models.TagJob{}},
+	{Table: "data_archives", Model: models.DataArchive{}},
+	{Table: "assignment_policies", Model: models.AssignmentPolicy{}},
+	{Table: "handover_schedules", Model: models.HandoverSchedule{}},
+	{Table: "handover_reports", Model: models.HandoverReport{}},
+	{Table: "alert_action_link_uses", Model: models.AlertActionLinkUse{}},
+	{Table: "push_devices", Model: models.PushDevice{}},
+	{Table: "push_preferences", Model: models.PushPreference{}},
+	{Table: "inbox_items", Model: models.InboxItem{}},
+	{Table: "user_quiet_hours", Model: models.QuietHours{}},
+	{Table: "change_events", Model: models.ChangeEvent{}},
+	{Table: "notification_attempts", Model: models.NotificationAttempt{}},
+	{Table: "webhook_subscriptions", Model: models.WebhookSubscription{}},
+	{Table: "webhook_deliveries", Model: models.WebhookDelivery{}},
+	{Table: "ticket_import_jobs", Model: models.TicketImportJob{}},
+	{Table: "alert_stats_hourly", Model: models.AlertStatsRow{}},
+	{Table: "ticket_surveys", Model: models.TicketSurvey{}},
+	{Table: "ticket_knowledge_links", Model: models.TicketKnowledgeLink{}},
+	{Table: "approval_requests", Model: models.ApprovalRequest{}},
+	{Table: "ingest_rejections", Model: models.IngestRejection{}},
+	{Table: "async_tasks", Model: models.AsyncTask{}},
+	{Table: "notification_channels", Model: models.NotificationChannel{}},
+	{Table: "metric_series", Model: models.MetricSeries{}},
+	{Table: "metric_samples", Model: models.MetricSample{}},
+	{Table: "ticket_sla_pauses", Model: models.TicketSLAPause{}},
+	{Table: "alert_severity_rules", Model: models.AlertSeverityRule{}},
+	{Table: "status_incidents", Model: models.StatusIncident{}},
+	{Table: "status_incident_updates", Model: models.StatusIncidentUpdate{}},
+	{Table: "status_subscribers", Model: models.StatusSubscriber{}},
+}
+
+// ColumnInfo 数据库中的列
+type ColumnInfo struct {
+	Nullable   bool
+	HasDefault bool
+}
+
+// SchemaDrift 模型与数据库表结构的偏差
+type SchemaDrift struct {
+	Table  string `json:"table"`
+	Column string `json:"column,omitempty"`
+	Kind   string `json:"kind"`
+}
+
+// ModelColumns 返回模型 db 标签声明的列，包含嵌入结构体的字段
+func ModelColumns(model interface{}) []string {
+	var columns []string
+	collectModelColumns(reflect.TypeOf(model), &columns)
+	return columns
+}
+
+func collectModelColumns(t reflect.Type, columns *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			if field.Anonymous {
+				collectModelColumns(field.Type, columns)
+			}
+			continue
+		}
+		*columns = append(*columns, tag)
+	}
+}
+
+// CompareSchema 对比模型与数据库中的实际列，actual 为表名到列名到列信息的映射
+func CompareSchema(tables []SchemaTable, actual map[string]map[string]ColumnInfo) []SchemaDrift {
+	drifts := []SchemaDrift{}
+	for _, table := range tables {
+		columns, ok := actual[table.Table]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Table: table.Table, Kind: SchemaDriftMissingTable})
+			continue
+		}
+
+		declared := make(map[string]bool)
+		for _, column := range ModelColumns(table.Model) {
+			declared[column] = true
+			if _, exists := columns[column]; !exists {
+				drifts = append(drifts, SchemaDrift{Table: table.Table, Column: column, Kind: SchemaDriftMissingColumn})
+			}
+		}
+
+		var required []string
+		for name, info := range columns {
+			if !declared[name] && !info.Nullable && !info.HasDefault {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		for _, name := range required {
+			drifts = append(drifts, SchemaDrift{Table: table.Table, Column: name, Kind: SchemaDriftRequiredColumn})
+		}
+	}
+	return drifts
+}
+
+// VerifySchema 对比 ModelTables 中的模型与当前 schema 下的实际表结构
+func (db *DB) VerifySchema(ctx context.Context) ([]SchemaDrift, error) {
+	tables := make([]string, 0, len(ModelTables))
+	for _, table := range ModelTables {
+		tables = append(tables, table.Table)
+	}
+
+	var rows []struct {
+		Table      string  `db:"table_name"`
+		Column     string  `db:"column_name"`
+		Nullable   string  `db:"is_nullable"`
+		Default    *string `db:"column_default"`
+		Generated  string  `db:"is_generated"`
+		IsIdentity string  `db:"is_identity"`
+	}
+	query := `
+		SELECT table_name, column_name, is_nullable, column_default, is_generated, is_identity
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1)`
+	if err := db.SelectContext(ctx, &rows, query, pq.Array(tables)); err != nil {
+		return nil, fmt.Errorf("failed to read table columns: %w", err)
+	}
+
+	actual := make(map[string]map[string]ColumnInfo)
+	for _, row := range rows {
+		if actual[row.Table] == nil {
+			actual[row.Table] = make(map[string]ColumnInfo)
+		}
+		actual[row.Table][row.Column] = ColumnInfo{
+			Nullable:   row.Nullable == "YES",
+			HasDefault: row.Default != nil || row.Generated == "ALWAYS" || row.IsIdentity == "YES",
+		}
+	}
+	return CompareSchema(ModelTables, actual), nil
+}
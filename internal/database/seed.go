@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"pulse/internal/models"
+)
+
+// SeedOptions 初始化数据选项
+type SeedOptions struct {
+	AdminUsername string
+	AdminEmail    string
+	AdminPassword string // 为空时生成随机密码
+	SampleRules   bool   // 是否写入示例告警规则
+}
+
+// SeedResult 初始化数据结果，只统计本次新写入的记录
+type SeedResult struct {
+	Roles         int
+	AdminCreated  bool
+	AdminPassword string // 仅在生成随机密码且新建了管理员时返回
+	Rules         int
+}
+
+// seedRuleGroup 示例规则所属的规则组
+const seedRuleGroup = "default"
+
+// roleDescriptions 内置角色说明
+var roleDescriptions = map[models.UserRole]string{
+	models.UserRoleAdmin:    "管理员，拥有全部权限",
+	models.UserRoleOperator: "运维工程师，可处理告警、规则和工单",
+	models.UserRoleViewer:   "只读用户",
+	models.UserRoleGuest:    "访客，仅可查看告警和知识库",
+}
+
+// seedRule 示例告警规则
+type seedRule struct {
+	Name        string
+	DisplayName string
+	Description string
+	Query       string
+	Severity    models.AlertSeverity
+	For         int
+}
+
+// sampleRules 示例规则以草稿状态写入，配置数据源后由用户启用
+var sampleRules = []seedRule{
+	{
+		Name:        "InstanceDown",
+		DisplayName: "实例宕机",
+		Description: "采集目标连续 1 分钟不可达",
+		Query:       `up == 0`,
+		Severity:    models.AlertSeverityCritical,
+		For:         60,
+	},
+	{
+		Name:        "HighCPUUsage",
+		DisplayName: "CPU 使用率过高",
+		Description: "实例 CPU 使用率持续 5 分钟超过 90%",
+		Query:       `100 - (avg by (instance) (rate(node_cpu_seconds_total{mode="idle"}[5m])) * 100) > 90`,
+		Severity:    models.AlertSeverityHigh,
+		For:         300,
+	},
+	{
+		Name:        "HighMemoryUsage",
+		DisplayName: "内存使用率过高",
+		Description: "实例内存使用率持续 5 分钟超过 90%",
+		Query:       `(1 - node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes) * 100 > 90`,
+		Severity:    models.AlertSeverityHigh,
+		For:         300,
+	},
+	{
+		Name:        "DiskSpaceLow",
+		DisplayName: "磁盘空间不足",
+		Description: "文件系统可用空间低于 10%",
+		Query:       `node_filesystem_avail_bytes{fstype!~"tmpfs|overlay"} / node_filesystem_size_bytes * 100 < 10`,
+		Severity:    models.AlertSeverityMedium,
+		For:         600,
+	},
+}
+
+// Seed 写入初始化数据：内置角色、管理员账号和示例规则
+// 所有写入都是幂等的，已存在的记录保持不变，可在每次部署时重复执行
+func (db *DB) Seed(ctx context.Context, opts SeedOptions) (*SeedResult, error) {
+	if opts.AdminUsername == "" {
+		opts.AdminUsername = "admin"
+	}
+	if opts.AdminEmail == "" {
+		opts.AdminEmail = "admin@example.com"
+	}
+
+	result := &SeedResult{}
+	err := db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		roles, err := seedRoles(ctx, tx)
+		if err != nil {
+			return err
+		}
+		result.Roles = roles
+
+		if err := seedAdmin(ctx, tx, opts, result); err != nil {
+			return err
+		}
+
+		if opts.SampleRules {
+			rules, err := seedSampleRules(ctx, tx)
+			if err != nil {
+				return err
+			}
+			result.Rules = rules
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	db.logger.Info("Seed data applied",
+		zap.Int("roles", result.Roles),
+		zap.Bool("admin_created", result.AdminCreated),
+		zap.Int("rules", result.Rules),
+	)
+	return result, nil
+}
+
+// seedRoles 写入内置角色对应的权限组
+func seedRoles(ctx context.Context, tx *sqlx.Tx) (int, error) {
+	roles := make([]string, 0, len(models.RolePermissions))
+	for role := range models.RolePermissions {
+		roles = append(roles, string(role))
+	}
+	sort.Strings(roles)
+
+	query := `
+		INSERT INTO permission_groups (name, description, permissions)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) WHERE deleted_at IS NULL DO NOTHING`
+
+	created := 0
+	for _, role := range roles {
+		permissions, err := json.Marshal(models.RolePermissions[models.UserRole(role)])
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal permissions for role %s: %w", role, err)
+		}
+
+		res, err := tx.ExecContext(ctx, query, role, roleDescriptions[models.UserRole(role)], string(permissions))
+		if err != nil {
+			return 0, fmt.Errorf("failed to seed role %s: %w", role, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// seedAdmin 创建管理员账号，用户名或邮箱已存在时跳过
+func seedAdmin(ctx context.Context, tx *sqlx.Tx, opts SeedOptions, result *SeedResult) error {
+	password := opts.AdminPassword
+	generated := password == ""
+	if generated {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("failed to generate admin password: %w", err)
+		}
+		password = hex.EncodeToString(buf)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash admin password: %w", err)
+	}
+
+	query := `
+		INSERT INTO users (username, email, password_hash, full_name, status, role, email_verified, email_verified_at)
+		VALUES ($1, $2, $3, 'System Administrator', 'active', 'admin', TRUE, CURRENT_TIMESTAMP)
+		ON CONFLICT DO NOTHING`
+
+	res, err := tx.ExecContext(ctx, query, opts.AdminUsername, opts.AdminEmail, string(hash))
+	if err != nil {
+		return fmt.Errorf("failed to seed admin user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		result.AdminCreated = true
+		if generated {
+			result.AdminPassword = password
+		}
+	}
+	return nil
+}
+
+// seedSampleRules 写入示例规则组和规则
+func seedSampleRules(ctx context.Context, tx *sqlx.Tx) (int, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO rule_groups (name, display_name, description)
+		VALUES ($1, '默认规则组', '初始化时创建的示例规则组')
+		ON CONFLICT (name) DO NOTHING`, seedRuleGroup)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed rule group: %w", err)
+	}
+
+	var groupID string
+	if err := tx.GetContext(ctx, &groupID, `SELECT id FROM rule_groups WHERE name = $1`, seedRuleGroup); err != nil {
+		return 0, fmt.Errorf("failed to get rule group: %w", err)
+	}
+
+	query := `
+		INSERT INTO rules (name, display_name, description, group_id, rule_type, query, severity, for_duration, labels, status, enabled)
+		VALUES ($1, $2, $3, $4, 'prometheus', $5, $6, $7, $8, 'draft', FALSE)
+		ON CONFLICT (name, group_id) DO NOTHING`
+
+	created := 0
+	for _, rule := range sampleRules {
+		labels, err := json.Marshal(map[string]string{"source": "seed"})
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal rule labels: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, query, rule.Name, rule.DisplayName, rule.Description, groupID,
+			rule.Query, rule.Severity, rule.For, string(labels))
+		if err != nil {
+			return 0, fmt.Errorf("failed to seed rule %s: %w", rule.Name, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			created++
+		}
+	}
+	return created, nil
+}
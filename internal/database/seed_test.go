@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/migrations"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	source, err := iofs.New(migrations.FS, ".")
+	require.NoError(t, err)
+	defer source.Close()
+
+	version, err := source.First()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+}
+
+func TestSeed(t *testing.T) {
+	sqlxDB, mock := newMockDB(t)
+	db := &DB{DB: sqlxDB, config: &config.DatabaseConfig{}, logger: zap.NewNop()}
+
+	mock.ExpectBegin()
+	for range models.RolePermissions {
+		mock.ExpectExec(`INSERT INTO permission_groups`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	// 管理员已存在
+	mock.ExpectExec(`INSERT INTO users`).WithArgs("admin", "admin@example.com", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO rule_groups`).WithArgs(seedRuleGroup).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id FROM rule_groups`).WithArgs(seedRuleGroup).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("group-1"))
+	for i := range sampleRules {
+		mock.ExpectExec(`INSERT INTO rules`).WillReturnResult(sqlmock.NewResult(0, int64(i%2)))
+	}
+	mock.ExpectCommit()
+
+	result, err := db.Seed(context.Background(), SeedOptions{SampleRules: true})
+	require.NoError(t, err)
+	assert.Equal(t, len(models.RolePermissions), result.Roles)
+	assert.False(t, result.AdminCreated)
+	assert.Empty(t, result.AdminPassword)
+	assert.Equal(t, len(sampleRules)/2, result.Rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
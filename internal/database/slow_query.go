@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// slowQueryMaxLength 慢查询日志中语句的最大长度
+const slowQueryMaxLength = 2000
+
+// SlowQueryStats 慢查询计数，主库和只读副本共用
+type SlowQueryStats struct {
+	threshold  time.Duration
+	count      atomic.Int64
+	totalNanos atomic.Int64
+	maxNanos   atomic.Int64
+}
+
+// record 记录一次慢查询
+func (s *SlowQueryStats) record(d time.Duration) {
+	s.count.Add(1)
+	s.totalNanos.Add(int64(d))
+	for {
+		max := s.maxNanos.Load()
+		if int64(d) <= max || s.maxNanos.CompareAndSwap(max, int64(d)) {
+			return
+		}
+	}
+}
+
+// Count 慢查询次数
+func (s *SlowQueryStats) Count() int64 {
+	return s.count.Load()
+}
+
+// Snapshot 返回慢查询指标，阈值为 0 表示未开启慢查询日志
+func (s *SlowQueryStats) Snapshot() map[string]interface{} {
+	return map[string]interface{}{
+		"threshold_ms":      s.threshold.Milliseconds(),
+		"count":             s.count.Load(),
+		"total_duration_ms": time.Duration(s.totalNanos.Load()).Milliseconds(),
+		"max_duration_ms":   time.Duration(s.maxNanos.Load()).Milliseconds(),
+	}
+}
+
+// openDB 打开数据库连接池，在驱动层记录因请求上下文结束而中断的语句，
+// threshold 大于 0 时同时记录执行时间超过阈值的语句
+func openDB(dsn string, stats *SlowQueryStats, canceled *CanceledQueryStats, logger *zap.Logger) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sqlx.NewDb(sql.OpenDB(&slowQueryConnector{Connector: connector, stats: stats, canceled: canceled, logger: logger}), "postgres"), nil
+}
+
+// slowQueryConnector 包装驱动连接器，为每个连接记录慢查询和中断的语句
+type slowQueryConnector struct {
+	driver.Connector
+	stats    *SlowQueryStats
+	canceled *CanceledQueryStats
+	logger   *zap.Logger
+}
+
+// Connect 创建连接
+func (c *slowQueryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slowQueryConn{Conn: conn, connector: c}, nil
+}
+
+// observe 记录因请求上下文结束而中断的语句和执行时间超过阈值的语句，参数只记录类型，避免日志泄露敏感数据
+func (c *slowQueryConnector) observe(ctx context.Context, start time.Time, query string, args []driver.NamedValue, err error) {
+	elapsed := time.Since(start)
+	if err != nil && c.canceled != nil && c.canceled.record(ctx) {
+		c.logger.Debug("Query canceled", zap.Duration("duration", elapsed), zap.String("query", compactQuery(query)), zap.Error(ctx.Err()))
+	}
+	if c.stats.threshold <= 0 || elapsed < c.stats.threshold {
+		return
+	}
+	c.stats.record(elapsed)
+
+	fields := []zap.Field{
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", c.stats.threshold),
+		zap.String("query", compactQuery(query)),
+		zap.Strings("args", redactArgs(args)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	c.logger.Warn("Slow query", fields...)
+}
+
+// slowQueryConn 记录 QueryContext 和 ExecContext 的执行时间，预编译语句（如 COPY）不计时
+type slowQueryConn struct {
+	driver.Conn
+	connector *slowQueryConnector
+}
+
+// QueryContext 执行查询，记录到返回第一批结果的耗时
+func (c *slowQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.connector.observe(ctx, start, query, args, err)
+	return rows, err
+}
+
+// ExecContext 执行语句
+func (c *slowQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.connector.observe(ctx, start, query, args, err)
+	return result, err
+}
+
+// PrepareContext 预编译语句
+func (c *slowQueryConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// BeginTx 开启事务，驱动不支持 BeginTx 时回退到 Begin
+func (c *slowQueryConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+// Ping 探测连接
+func (c *slowQueryConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession 连接放回连接池前重置会话
+func (c *slowQueryConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid 判断连接是否可以复用
+func (c *slowQueryConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// compactQuery 合并语句中的空白并截断过长的语句
+func compactQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > slowQueryMaxLength {
+		query = query[:slowQueryMaxLength] + "..."
+	}
+	return query
+}
+
+// redactArgs 将参数值替换为类型，如 $1=<string>
+func redactArgs(args []driver.NamedValue) []string {
+	redacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg.Value == nil {
+			redacted = append(redacted, fmt.Sprintf("$%d=NULL", arg.Ordinal))
+			continue
+		}
+		redacted = append(redacted, fmt.Sprintf("$%d=<%T>", arg.Ordinal, arg.Value))
+	}
+	return redacted
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// dsnConnector 通过 DSN 打开 sqlmock 连接的连接器
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+
+func (c *dsnConnector) Driver() driver.Driver { return c.driver }
+
+func TestSlowQueryConnector(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("slow_query_test")
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	stats := &SlowQueryStats{threshold: 20 * time.Millisecond}
+	db := sql.OpenDB(&slowQueryConnector{
+		Connector: &dsnConnector{dsn: "slow_query_test", driver: mockDB.Driver()},
+		stats:     stats,
+		logger:    zap.New(core),
+	})
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").WithArgs("secret@example.com", 1).
+		WillDelayFor(30 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err = db.ExecContext(context.Background(), "UPDATE users\n\t SET email = $1 WHERE id = $2", "secret@example.com", 1)
+	require.NoError(t, err)
+	rows, err := db.QueryContext(context.Background(), "SELECT id FROM users")
+	require.NoError(t, err)
+	rows.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// 只记录超过阈值的语句，参数值脱敏
+	assert.Equal(t, int64(1), stats.Count())
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "UPDATE users SET email = $1 WHERE id = $2", fields["query"])
+	assert.Equal(t, []interface{}{"$1=<string>", "$2=<int64>"}, fields["args"])
+	assert.NotContains(t, logs.All()[0].Entry.Message+fields["query"].(string), "secret@example.com")
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, int64(20), snapshot["threshold_ms"])
+	assert.GreaterOrEqual(t, snapshot["max_duration_ms"].(int64), int64(30))
+}
+
+func TestSlowQueryConnector_CountsCanceledQueries(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("canceled_query_test")
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	canceled := &CanceledQueryStats{}
+	db := sql.OpenDB(&slowQueryConnector{
+		Connector: &dsnConnector{dsn: "canceled_query_test", driver: mockDB.Driver()},
+		stats:     &SlowQueryStats{},
+		canceled:  canceled,
+		logger:    zap.NewNop(),
+	})
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT id").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("DELETE FROM users").WillReturnError(sql.ErrConnDone)
+
+	// 客户端断开连接
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = db.QueryContext(ctx, "SELECT id FROM users")
+	require.Error(t, err)
+
+	// 超出请求处理时限
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = db.QueryContext(ctx, "SELECT id FROM users")
+	require.Error(t, err)
+
+	// 上下文未结束时的执行失败不计入
+	_, err = db.ExecContext(context.Background(), "DELETE FROM users")
+	require.Error(t, err)
+
+	assert.Equal(t, int64(2), canceled.Count())
+	assert.Equal(t, map[string]interface{}{"client_canceled": int64(1), "deadline_exceeded": int64(1)}, canceled.Snapshot())
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+)
+
+// errorStatus 根据错误分类映射HTTP状态码，无法识别的错误视为服务器内部错误
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, models.ErrPermissionDenied),
+		errors.Is(err, models.ErrSourceIPNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrInvalidToken),
+		errors.Is(err, models.ErrTokenExpired),
+		errors.Is(err, models.ErrInvalidCredentials),
+		errors.Is(err, models.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, breaker.ErrOpen):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
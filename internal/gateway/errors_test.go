@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pulse/internal/models"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"具体的不存在错误", models.ErrTicketNotFound, http.StatusNotFound},
+		{"包装后的不存在错误", fmt.Errorf("获取工单失败: %w", models.ErrTicketNotFound), http.StatusNotFound},
+		{"唯一约束冲突", models.ErrUserExists, http.StatusConflict},
+		{"状态不允许", fmt.Errorf("%w: 只有归档状态的知识库才能取消归档", models.ErrKnowledgeInvalidStatus), http.StatusPreconditionFailed},
+		{"权限不足", models.ErrPermissionDenied, http.StatusForbidden},
+		{"参数无效", fmt.Errorf("%w: 工单ID列表不能为空", models.ErrInvalidInput), http.StatusBadRequest},
+		{"认证失败", models.ErrInvalidCredentials, http.StatusUnauthorized},
+		{"签名无效", fmt.Errorf("%w: 签名不匹配", models.ErrInvalidSignature), http.StatusUnauthorized},
+		{"来源IP受限", models.ErrSourceIPNotAllowed, http.StatusForbidden},
+		{"未知错误", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorStatus(tt.err))
+		})
+	}
+}
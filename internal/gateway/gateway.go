@@ -9,7 +9,15 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"pulse/internal/middleware"
+	"pulse/internal/models"
+	"pulse/internal/monitor"
+	"pulse/internal/pkg/ipfilter"
+	"pulse/internal/pkg/loglevel"
+	"pulse/internal/pkg/maintenance"
+	"pulse/internal/queue"
+	pulseredis "pulse/internal/redis"
 	"pulse/internal/service"
+	"pulse/internal/worker"
 )
 
 // Gateway API网关
@@ -20,6 +28,44 @@ type Gateway struct {
 	authService    middleware.AuthService
 	rbacService    middleware.RBACService
 	serviceManager service.ServiceManager
+	rateLimit      *middleware.RateLimitConfig
+	rateLimiter    *middleware.DynamicRateLimit
+	ingestFilter   *ipfilter.Filter
+	redisState     *pulseredis.Availability
+	healthMonitor  *monitor.HealthMonitor
+	ingestStats    *monitor.IngestStats
+	logLevels      *loglevel.Levels
+	diagnostics    bool
+	jobs           *worker.JobRegistry
+	webUI          http.Handler
+	maintenance    *maintenance.Mode
+	ingestQueue    queue.Queue
+	timeouts       requestTimeouts
+	// statusPage 是否提供公开状态页，statusPageToken 不为空时访问状态页需要提供该令牌
+	statusPage      bool
+	statusPageToken string
+}
+
+// requestTimeouts 按路由类别的请求处理时限
+type requestTimeouts struct {
+	read, write, export time.Duration
+}
+
+// defaultRequestTimeouts 默认的请求处理时限
+var defaultRequestTimeouts = requestTimeouts{read: 5 * time.Second, write: 30 * time.Second, export: 120 * time.Second}
+
+// exportRoutes 按导出时限处理的路由，传输文件或批量读写数据的耗时与数据量相关
+var exportRoutes = []string{
+	"/api/v1/tickets/import",
+	"/api/v1/tickets/import/:id/start",
+	"/api/v1/tickets/:id/attachments/:attachment_id/download",
+	"/api/v1/knowledge/export",
+	"/api/v1/knowledge/import",
+	"/api/v1/tasks/:id/result",
+	"/api/v1/admin/backup",
+	"/api/v1/admin/backup/restore",
+	"/api/v1/admin/retention/restore",
+	"/api/v1/admin/replay",
 }
 
 // GatewayConfig 网关配置
@@ -43,6 +89,9 @@ func NewGateway(logger *logrus.Logger, redisClient *redis.Client, serviceManager
 	// 创建RBAC服务
 	rbacService := middleware.NewDefaultRBACService()
 
+	// 默认限流配置，Redis 不可用时退化为内存限流
+	rateLimit := middleware.DefaultRateLimitConfig(redisClient)
+
 	return &Gateway{
 		logger:         logger,
 		router:         router,
@@ -50,6 +99,94 @@ func NewGateway(logger *logrus.Logger, redisClient *redis.Client, serviceManager
 		authService:    authService,
 		rbacService:    rbacService,
 		serviceManager: serviceManager,
+		rateLimit:      &rateLimit,
+		timeouts:       defaultRequestTimeouts,
+	}
+}
+
+// SetRequestTimeouts 设置读请求、写请求和导出导入类请求的处理时限，为 0 时保持默认值，需在 SetupRoutes 之前调用
+func (g *Gateway) SetRequestTimeouts(read, write, export time.Duration) {
+	if read > 0 {
+		g.timeouts.read = read
+	}
+	if write > 0 {
+		g.timeouts.write = write
+	}
+	if export > 0 {
+		g.timeouts.export = export
+	}
+}
+
+// SetRateLimitConfig 设置限流配置，传入 nil 时关闭限流，需在 SetupRoutes 之前调用
+func (g *Gateway) SetRateLimitConfig(config *middleware.RateLimitConfig) {
+	g.rateLimit = config
+}
+
+// SetIngestIPFilter 设置告警接入端点的全局来源IP过滤，需在 SetupRoutes 之前调用
+func (g *Gateway) SetIngestIPFilter(filter *ipfilter.Filter) {
+	g.ingestFilter = filter
+}
+
+// SetRedisAvailability 设置 Redis 可用性探测器，健康检查端点据此报告降级状态
+func (g *Gateway) SetRedisAvailability(availability *pulseredis.Availability) {
+	g.redisState = availability
+}
+
+// SetHealthMonitor 设置依赖健康监控器，注册 /health/live、/health/ready 和 /health/metrics 端点，需在 SetupRoutes 之前调用
+func (g *Gateway) SetHealthMonitor(healthMonitor *monitor.HealthMonitor) {
+	g.healthMonitor = healthMonitor
+}
+
+// SetIngestStats 设置告警接入计数，接入成功和失败次数供平台自监控使用
+func (g *Gateway) SetIngestStats(stats *monitor.IngestStats) {
+	g.ingestStats = stats
+}
+
+// SetLogLevels 设置运行时日志级别，注册管理员调整日志级别的端点，需在 SetupRoutes 之前调用
+func (g *Gateway) SetLogLevels(levels *loglevel.Levels) {
+	g.logLevels = levels
+}
+
+// SetDiagnosticsEnabled 开启后在管理员路由下挂载 pprof、expvar 和运行时快照端点，需在 SetupRoutes 之前调用
+func (g *Gateway) SetDiagnosticsEnabled(enabled bool) {
+	g.diagnostics = enabled
+}
+
+// SetJobRegistry 设置后台任务注册表，注册管理员查看和控制后台任务的端点，需在 SetupRoutes 之前调用
+func (g *Gateway) SetJobRegistry(jobs *worker.JobRegistry) {
+	g.jobs = jobs
+}
+
+// SetStatusPage 开启公开状态页，token 不为空时访问状态页需要提供该令牌，需在 SetupRoutes 之前调用
+func (g *Gateway) SetStatusPage(enabled bool, token string) {
+	g.statusPage = enabled
+	g.statusPageToken = token
+}
+
+// SetWebUI 设置前端静态文件处理器，未匹配 API 路由的页面请求交给它处理，需在 SetupRoutes 之前调用
+func (g *Gateway) SetWebUI(handler http.Handler) {
+	g.webUI = handler
+}
+
+// SetMaintenanceMode 设置维护模式，开启后拒绝写请求并注册管理员切换维护模式的端点，需在 SetupRoutes 之前调用
+func (g *Gateway) SetMaintenanceMode(mode *maintenance.Mode) {
+	g.maintenance = mode
+}
+
+// SetIngestQueue 设置维护期间暂存告警接入请求的队列，未设置时维护期间接入请求返回 503
+func (g *Gateway) SetIngestQueue(q queue.Queue) {
+	g.ingestQueue = q
+}
+
+// recordIngest 记录一次告警接入结果，未设置接入计数时忽略
+func (g *Gateway) recordIngest(ok bool) {
+	if g.ingestStats == nil {
+		return
+	}
+	if ok {
+		g.ingestStats.RecordSuccess()
+	} else {
+		g.ingestStats.RecordFailure()
 	}
 }
 
@@ -87,7 +224,7 @@ func (g *Gateway) registerDefaultMiddleware() {
 	// 日志中间件
 	loggerConfig := middleware.LoggerConfig{
 		Logger:        g.logger,
-		SkipPaths:     []string{"/health", "/status"},
+		SkipPaths:     []string{"/health", "/health/live", "/health/ready", "/health/metrics", "/status"},
 		EnableDetails: false,
 	}
 	g.router.Use(middleware.LoggerMiddleware(loggerConfig))
@@ -99,63 +236,686 @@ func (g *Gateway) registerDefaultMiddleware() {
 	}
 	g.router.Use(middleware.RecoveryMiddleware(recoveryConfig))
 
-	// 限流中间件
-	if g.redisClient != nil {
-		rateLimitConfig := middleware.DefaultRateLimitConfig(g.redisClient)
-		rateLimitConfig.Logger = g.logger
-		g.router.Use(middleware.RateLimitMiddleware(rateLimitConfig))
-	}
-
 	// 指标收集中间件
 	g.router.Use(middleware.MetricsMiddleware())
 
-	// 超时中间件
+	// 超时中间件，按读、写和导出导入分类限制处理时间，流式导出的耗时与数据量相关，不限制处理时间
 	timeoutConfig := middleware.TimeoutConfig{
-		Timeout: 30 * time.Second,
-		Message: "Request timeout",
+		Timeout:       g.timeouts.write,
+		ReadTimeout:   g.timeouts.read,
+		WriteTimeout:  g.timeouts.write,
+		ExportTimeout: g.timeouts.export,
+		ExportRoutes:  exportRoutes,
+		Message:       "Request timeout",
+		SkipPaths:     []string{"/api/v1/alerts/stream", "/api/v1/tickets/stream", "/api/v1/admin/audit-logs/stream", "/api/v1/inbox/stream"},
 	}
 	g.router.Use(middleware.TimeoutMiddleware(timeoutConfig))
+
+	// 维护模式中间件，维护期间拒绝写请求
+	if g.maintenance != nil {
+		g.router.Use(middleware.MaintenanceMiddleware(middleware.MaintenanceConfig{
+			Mode:      g.maintenance,
+			SkipPaths: maintenanceSkipPaths,
+		}))
+	}
 }
 
 // registerRoutes 注册路由
 func (g *Gateway) registerRoutes() {
 	// 健康检查端点
+	// Redis 不可用时服务仍可处理请求，返回 200 并标记降级
 	g.router.GET("/health", func(c *gin.Context) {
+		status := "healthy"
+		if g.degraded() {
+			status = "degraded"
+		}
 		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"timestamp": time.Now().Unix(),
+			"status":     status,
+			"degraded":   g.degraded(),
+			"components": g.componentStatus(),
+			"timestamp":  time.Now().Unix(),
 		})
 	})
 
 	// 状态检查端点
 	g.router.GET("/status", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"status":    "running",
-			"version":   "1.0.0",
-			"timestamp": time.Now().Unix(),
+			"status":     "running",
+			"version":    "1.0.0",
+			"degraded":   g.degraded(),
+			"components": g.componentStatus(),
+			"timestamp":  time.Now().Unix(),
 		})
 	})
 
+	// 依赖健康检查端点，供容器编排的存活和就绪探针使用
+	if g.healthMonitor != nil {
+		healthHandler := monitor.NewHealthHandler(g.healthMonitor)
+		g.router.GET("/health/live", gin.WrapF(healthHandler.HandleLiveness))
+		g.router.GET("/health/ready", gin.WrapF(healthHandler.HandleReadiness))
+		g.router.GET("/health/metrics", gin.WrapF(healthHandler.HandleMetrics))
+	}
+
+	// 告警接入路由
+	g.registerIngestRoutes()
+
+	// 聊天平台命令回调路由
+	g.registerChatOpsRoutes()
+
+	// 外部 ITSM 系统 Webhook 路由
+	g.registerITSMRoutes()
+
+	// 心跳上报路由
+	g.registerHeartbeatRoutes()
+
+	// 通知中的告警操作链接路由
+	g.registerAlertActionRoutes()
+
+	// 工单满意度评价链接路由
+	g.registerTicketSurveyRoutes()
+
+	// 公开状态页路由
+	g.registerStatusPageRoutes()
+
+	// 未匹配的路由，前端页面或 404
+	g.registerWebUIRoutes()
+
 	// API路由组
 	api := g.router.Group("/api/v1")
 	{
 		// 需要认证的路由
 		api.Use(middleware.RequireAuthMiddleware(g.authService))
 
+		// 模拟登录会话在响应头中标明执行模拟的管理员，只读会话拒绝写请求，所有请求记录审计日志
+		api.Use(middleware.ImpersonationMiddleware(g.auditImpersonatedRequest))
+
+		// API Key 只能访问权限范围内的接口，各路由需要的权限范围见 routeScopes
+		api.Use(middleware.APIKeyScopeMiddleware(routeScopes))
+
+		// 限流放在认证之后，便于按 API Key 或用户区分令牌桶
+		if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+			api.Use(rateLimit)
+		}
+
+		// 功能开关，处理函数可通过 middleware.FeatureEnabled 按用户和租户灰度切换新功能
+		api.Use(middleware.FeatureFlagMiddleware(g.featureEnabled))
+		api.GET("/feature-flags", g.evaluateFeatureFlags)
+
+		// 首页仪表盘汇总
+		api.GET("/dashboard", g.getDashboard)
+
 		// 告警相关路由
 		alerts := api.Group("/alerts")
 		{
-			alerts.GET("", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{"message": "alerts endpoint"})
-			})
+			alerts.GET("", g.listAlerts)
+			alerts.GET("/stream", g.streamAlerts)
+			alerts.GET("/stats", g.getAlertStats)
+			alerts.GET("/stats/trend", g.getAlertStatsTrend)
 			alerts.POST("", func(c *gin.Context) {
 				c.JSON(http.StatusCreated, gin.H{"message": "alert created"})
 			})
+			alerts.GET("/:id/timeline", g.getAlertTimeline)
+			alerts.POST("/:id/comments", g.addAlertComment)
+			alerts.GET("/:id/remediations", g.listAlertRemediations)
+			alerts.POST("/:id/snooze", g.snoozeAlert)
+			alerts.GET("/:id/changes", g.listAlertChanges)
+			alerts.GET("/:id/notifications", g.listAlertNotifications)
+		}
+
+		// 当前用户的告警暂停
+		snoozes := api.Group("/snoozes")
+		{
+			snoozes.GET("", g.listAlertSnoozes)
+			snoozes.POST("", g.createAlertSnooze)
+			snoozes.DELETE("/:id", g.deleteAlertSnooze)
+		}
+
+		// 规则修复动作审批
+		api.POST("/remediations/:id/approve", g.approveRemediation)
+		api.POST("/remediations/:id/reject", g.rejectRemediation)
+
+		// 工单相关路由
+		tickets := api.Group("/tickets")
+		{
+			tickets.GET("/stream", g.streamTickets)
+			tickets.GET("/csat", g.getTicketCSAT)
+			tickets.GET("/knowledge-feedback/pending", g.listPendingKnowledgeFeedback)
+			tickets.GET("/import", g.listTicketImports)
+			tickets.POST("/import", g.uploadTicketImport)
+			tickets.GET("/import/:id", g.getTicketImport)
+			tickets.POST("/import/:id/start", g.startTicketImport)
+			tickets.GET("/:id/worklogs", g.listTicketWorklogs)
+			tickets.POST("/:id/worklogs", g.createTicketWorklog)
+			tickets.PUT("/:id/worklogs/:worklog_id", g.updateTicketWorklog)
+			tickets.DELETE("/:id/worklogs/:worklog_id", g.deleteTicketWorklog)
+			tickets.GET("/:id/attachments/:attachment_id/download", g.downloadTicketAttachment)
+			tickets.GET("/:id/external-links", g.listTicketExternalLinks)
+			tickets.POST("/:id/auto-assign", g.autoAssignTicket)
+			tickets.GET("/:id/sla", g.getTicketSLAClock)
+			tickets.POST("/:id/sla/pause", g.pauseTicketSLA)
+			tickets.POST("/:id/sla/resume", g.resumeTicketSLA)
+			tickets.GET("/:id/notifications", g.listTicketNotifications)
+			tickets.GET("/:id/knowledge", g.listTicketKnowledgeLinks)
+			tickets.POST("/:id/knowledge", g.linkTicketKnowledge)
+			tickets.DELETE("/:id/knowledge/:knowledge_id", g.unlinkTicketKnowledge)
+			tickets.POST("/:id/knowledge/:knowledge_id/feedback", g.submitKnowledgeFeedback)
+		}
+
+		// 通知投递记录、手动重发和渠道测试
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("/:id", g.getNotificationDelivery)
+			notifications.POST("/:id/resend", g.resendNotification)
+			notifications.POST("/channels/:id/test", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin)), g.testNotificationChannel)
+		}
+
+		// 知识库相关路由
+		knowledge := api.Group("/knowledge")
+		{
+			knowledge.GET("", g.listKnowledge)
+			knowledge.POST("", g.createKnowledge)
+			knowledge.GET("/search", g.searchKnowledge)
+			knowledge.GET("/quality", g.listKnowledgeQuality)
+			knowledge.GET("/slug/:slug", g.getKnowledgeBySlug)
+			knowledge.POST("/check-duplicates", g.checkKnowledgeDuplicates)
+			knowledge.POST("/import", g.importKnowledge)
+			knowledge.GET("/export", g.exportKnowledge)
+			knowledge.GET("/:id/rendered", g.getRenderedKnowledge)
+			knowledge.GET("/:id/acl", g.getKnowledgeACL)
+			knowledge.PUT("/:id/acl", g.setKnowledgeACL)
+			knowledge.GET("/:id/attachments/:attachment_id/raw", g.getKnowledgeAttachmentRaw)
+			knowledge.POST("/:id/submit-review", g.submitKnowledgeForReview)
+			knowledge.POST("/:id/review", g.reviewKnowledge)
+			knowledge.GET("/:id/review", g.getKnowledgeReviewStatus)
+			knowledge.PUT("/:id/schedule", g.scheduleKnowledge)
+			knowledge.DELETE("/:id/schedule", g.cancelKnowledgeSchedule)
+			knowledge.PUT("/:id/expiry", g.updateKnowledgeExpiry)
+			knowledge.GET("/:id/comments", g.listKnowledgeComments)
+			knowledge.POST("/:id/comments", g.createKnowledgeComment)
+			knowledge.PUT("/:id/comments/:comment_id", g.updateKnowledgeComment)
+			knowledge.DELETE("/:id/comments/:comment_id", g.deleteKnowledgeComment)
+			knowledge.POST("/:id/comments/:comment_id/reactions", g.addKnowledgeCommentReaction)
+			knowledge.DELETE("/:id/comments/:comment_id/reactions", g.removeKnowledgeCommentReaction)
+			knowledge.POST("/:id/comments/:comment_id/flag", g.flagKnowledgeComment)
+			knowledge.POST("/:id/comments/:comment_id/moderate", g.moderateKnowledgeComment)
+		}
+
+		// 被举报的知识库评论
+		api.GET("/knowledge-comments/flagged", g.listFlaggedKnowledgeComments)
+
+		// 知识库分类访问控制
+		api.GET("/knowledge-categories/:id/acl", g.getKnowledgeCategoryACL)
+		api.PUT("/knowledge-categories/:id/acl", g.setKnowledgeCategoryACL)
+
+		// 知识库审核队列与审核策略
+		knowledgeReviews := api.Group("/knowledge-reviews")
+		{
+			knowledgeReviews.GET("/pending", g.listPendingKnowledgeReviews)
+			knowledgeReviews.GET("/policies", g.listKnowledgeReviewPolicies)
+			knowledgeReviews.POST("/policies", g.createKnowledgeReviewPolicy)
+			knowledgeReviews.GET("/policies/:id", g.getKnowledgeReviewPolicy)
+			knowledgeReviews.PUT("/policies/:id", g.updateKnowledgeReviewPolicy)
+			knowledgeReviews.DELETE("/policies/:id", g.deleteKnowledgeReviewPolicy)
+		}
+
+		// 异步任务，导出、批量检查等耗时操作提交后轮询任务获取进度和结果
+		tasks := api.Group("/tasks")
+		{
+			tasks.GET("", g.listAsyncTasks)
+			tasks.POST("", g.submitAsyncTask)
+			tasks.GET("/:id", g.getAsyncTask)
+			tasks.GET("/:id/result", g.downloadAsyncTaskResult)
+		}
+
+		// 工时报表
+		api.GET("/timesheets", g.getTimesheet)
+
+		// 事件复盘
+		api.GET("/postmortem-templates", g.listPostmortemTemplates)
+		postmortems := api.Group("/postmortems")
+		{
+			postmortems.GET("", g.listPostmortems)
+			postmortems.POST("", g.createPostmortem)
+			postmortems.GET("/:id", g.getPostmortem)
+			postmortems.PUT("/:id", g.updatePostmortem)
+			postmortems.DELETE("/:id", g.deletePostmortem)
+			postmortems.POST("/:id/publish", g.publishPostmortem)
+			postmortems.POST("/:id/action-items", g.createPostmortemActionItem)
+			postmortems.PUT("/:id/action-items/:item_id", g.updatePostmortemActionItem)
+			postmortems.DELETE("/:id/action-items/:item_id", g.deletePostmortemActionItem)
+		}
+
+		// 交班报告，到达交接时刻时由 Worker 自动生成，也可手动生成
+		handovers := api.Group("/handovers")
+		{
+			handovers.GET("", g.listHandoverReports)
+			handovers.POST("/generate", g.generateHandoverReport)
+			handovers.GET("/:id", g.getHandoverReport)
+		}
+
+		// 告警接入集成管理，创建和轮换密钥时返回签名密钥明文，仅限管理员和运维人员
+		integrations := api.Group("/webhook-integrations", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin), string(models.UserRoleOperator)))
+		{
+			integrations.GET("", g.listWebhookIntegrations)
+			integrations.POST("", g.createWebhookIntegration)
+			integrations.GET("/:id", g.getWebhookIntegration)
+			integrations.PUT("/:id", g.updateWebhookIntegration)
+			integrations.DELETE("/:id", g.deleteWebhookIntegration)
+			integrations.POST("/:id/rotate-secret", g.rotateWebhookIntegrationSecret)
+			integrations.POST("/:id/preview-mapping", g.previewWebhookIntegrationMapping)
+			integrations.GET("/:id/rejections", g.getWebhookIntegrationRejections)
+		}
+
+		// 出站 Webhook 订阅管理，订阅地址由服务端发起请求且创建时返回签名密钥明文，仅限管理员和运维人员
+		subscriptions := api.Group("/webhook-subscriptions", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin), string(models.UserRoleOperator)))
+		{
+			subscriptions.GET("", g.listWebhookSubscriptions)
+			subscriptions.POST("", g.createWebhookSubscription)
+			subscriptions.GET("/:id", g.getWebhookSubscription)
+			subscriptions.PUT("/:id", g.updateWebhookSubscription)
+			subscriptions.DELETE("/:id", g.deleteWebhookSubscription)
+			subscriptions.POST("/:id/rotate-secret", g.rotateWebhookSubscriptionSecret)
+			subscriptions.GET("/:id/deliveries", g.listWebhookDeliveries)
+			subscriptions.POST("/:id/deliveries/:delivery_id/redeliver", g.redeliverWebhookDelivery)
+		}
+
+		// GraphQL 查询，前端一次请求获取告警、工单、规则、知识库及其关联数据
+		api.POST("/graphql", g.graphqlQuery)
+		api.GET("/graphql/schema", g.graphqlSchema)
+
+		// 系统管理路由，仅管理员可访问
+		admin := api.Group("/admin", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin)))
+		{
+			admin.POST("/encryption/rotate", g.rotateEncryptionKeys)
+
+			// 模拟用户登录，签发以用户身份访问的短期令牌，用于复现用户反馈的权限和可见性问题
+			admin.POST("/impersonate", g.startImpersonation)
+
+			admin.GET("/feature-flags", g.listFeatureFlags)
+			admin.POST("/feature-flags", g.createFeatureFlag)
+			admin.GET("/feature-flags/:key", g.getFeatureFlag)
+			admin.PUT("/feature-flags/:key", g.updateFeatureFlag)
+			admin.DELETE("/feature-flags/:key", g.deleteFeatureFlag)
+
+			// 分派策略，按团队配置工单和告警的自动分派方式
+			admin.GET("/assignment-policies", g.listAssignmentPolicies)
+			admin.POST("/assignment-policies", g.createAssignmentPolicy)
+			admin.GET("/assignment-policies/:id", g.getAssignmentPolicy)
+			admin.PUT("/assignment-policies/:id", g.updateAssignmentPolicy)
+			admin.DELETE("/assignment-policies/:id", g.deleteAssignmentPolicy)
+
+			// 交接排班，按团队配置每天的交接时刻和接收交班报告的频道
+			admin.GET("/handover-schedules", g.listHandoverSchedules)
+			admin.POST("/handover-schedules", g.createHandoverSchedule)
+			admin.GET("/handover-schedules/:id", g.getHandoverSchedule)
+			admin.PUT("/handover-schedules/:id", g.updateHandoverSchedule)
+			admin.DELETE("/handover-schedules/:id", g.deleteHandoverSchedule)
+
+			admin.GET("/chatops/users", g.listChatUserMappings)
+			admin.POST("/chatops/users", g.createChatUserMapping)
+			admin.DELETE("/chatops/users/:id", g.deleteChatUserMapping)
+			admin.GET("/itsm-connectors", g.listITSMConnectors)
+			admin.POST("/itsm-connectors", g.createITSMConnector)
+			admin.GET("/itsm-connectors/:id", g.getITSMConnector)
+			admin.PUT("/itsm-connectors/:id", g.updateITSMConnector)
+			admin.DELETE("/itsm-connectors/:id", g.deleteITSMConnector)
+			admin.GET("/tags/usage", g.listTagUsage)
+			admin.GET("/tags/jobs", g.listTagJobs)
+			admin.POST("/tags/jobs", g.createTagJob)
+			admin.GET("/tags/jobs/:id", g.getTagJob)
+			admin.GET("/labels/cardinality", g.getLabelCardinality)
+			admin.GET("/rules/evaluation-lag", g.getRuleEvaluationLag)
+			admin.GET("/rules/:id/forecast", g.getRuleForecast)
+			admin.GET("/query-cache", g.getQueryCacheStats)
+			admin.DELETE("/query-cache", g.invalidateQueryCache)
+			admin.GET("/retention", g.getRetentionStatus)
+			admin.GET("/retention/archives", g.listDataArchives)
+			admin.POST("/retention/restore", g.restoreDataArchive)
+			admin.GET("/audit-logs/stream", g.streamAuditLogs)
+
+			// 敏感操作审批，配置为需要审批的操作由申请人以外的管理员批准后执行
+			admin.GET("/approvals", g.listApprovals)
+			admin.POST("/approvals", g.submitApproval)
+			admin.GET("/approvals/:id", g.getApproval)
+			admin.GET("/approvals/:id/history", g.getApprovalHistory)
+			admin.POST("/approvals/:id/approve", g.approveApproval)
+			admin.POST("/approvals/:id/reject", g.rejectApproval)
+			admin.POST("/approvals/:id/cancel", g.cancelApproval)
+			admin.POST("/backup", g.exportBackup)
+			admin.POST("/backup/restore", g.restoreBackup)
+			admin.POST("/replay", g.replayAlerts)
+			if g.maintenance != nil {
+				admin.GET("/maintenance", g.getMaintenanceMode)
+				admin.POST("/maintenance/enable", g.enableMaintenanceMode)
+				admin.POST("/maintenance/disable", g.disableMaintenanceMode)
+			}
+			if g.logLevels != nil {
+				admin.GET("/log-level", g.getLogLevel)
+				admin.PUT("/log-level", g.setLogLevel)
+				admin.PUT("/log-level/:module", g.setModuleLogLevel)
+				admin.DELETE("/log-level/:module", g.resetModuleLogLevel)
+			}
+			if g.jobs != nil {
+				admin.GET("/jobs", g.listJobs)
+				admin.GET("/jobs/:name", g.getJob)
+				admin.POST("/jobs/:name/trigger", g.triggerJob)
+				admin.POST("/jobs/:name/pause", g.pauseJob)
+				admin.POST("/jobs/:name/resume", g.resumeJob)
+				admin.POST("/jobs/:name/cancel", g.cancelJob)
+			}
+			// 运行时诊断：pprof、expvar、运行时概况和 goroutine/heap 快照下载
+			if g.diagnostics {
+				admin.Any("/debug/*path", gin.WrapH(monitor.NewDiagnosticsHandler("/api/v1/admin/debug")))
+			}
+		}
+
+		// 心跳检查相关路由
+		heartbeats := api.Group("/heartbeats")
+		{
+			heartbeats.GET("", g.listHeartbeats)
+			heartbeats.POST("", g.createHeartbeat)
+			heartbeats.GET("/status", g.getHeartbeatStatus)
+			heartbeats.GET("/:id", g.getHeartbeat)
+			heartbeats.PUT("/:id", g.updateHeartbeat)
+			heartbeats.DELETE("/:id", g.deleteHeartbeat)
+		}
+
+		// 服务目录相关路由
+		services := api.Group("/services")
+		{
+			services.GET("", g.listCatalogServices)
+			services.POST("", g.createCatalogService)
+			services.GET("/health", g.getServiceHealthRollup)
+			services.GET("/:id", g.getCatalogService)
+			services.PUT("/:id", g.updateCatalogService)
+			services.DELETE("/:id", g.deleteCatalogService)
+			services.GET("/:id/slo", g.getCatalogServiceSLO)
+			services.GET("/:id/slos", g.getServiceSLOBudgets)
+			services.GET("/:id/health", g.getServiceHealth)
+		}
+
+		// 变更事件，CI/CD 登记的发布、配置变更和功能开关切换，告警创建时关联同一服务最近的变更
+		changeEvents := api.Group("/change-events")
+		{
+			changeEvents.GET("", g.listChangeEvents)
+			changeEvents.POST("", g.createChangeEvent)
+			changeEvents.GET("/:id", g.getChangeEvent)
+			changeEvents.DELETE("/:id", g.deleteChangeEvent)
+		}
+
+		// 告警标签自动补全，为过滤界面和通知路由、告警暂停的匹配条件编辑器提供最近告警中的标签键和值
+		labels := api.Group("/labels")
+		{
+			labels.GET("", g.listLabelKeys)
+			labels.GET("/:key/values", g.listLabelValues)
+		}
+
+		// 内置指标存储的即时查询，指标由采集端通过 /api/v1/ingest/remote-write 推送
+		api.GET("/metrics/query", g.queryMetrics)
+
+		// SLO 管理，错误预算由 Worker 定期计算
+		slos := api.Group("/slos")
+		{
+			slos.GET("", g.listSLOs)
+			slos.POST("", g.createSLO)
+			slos.GET("/:id", g.getSLO)
+			slos.PUT("/:id", g.updateSLO)
+			slos.DELETE("/:id", g.deleteSLO)
+		}
+
+		// 多语言，枚举显示名和用户、团队的语言偏好
+		localization := api.Group("/i18n")
+		{
+			localization.GET("/enums", g.getEnumNames)
+			localization.GET("/language", g.getUserLanguage)
+			localization.PUT("/language", g.setUserLanguage)
+			localization.DELETE("/language", g.deleteUserLanguage)
+			localization.GET("/teams/:team/language", g.getTeamLanguage)
+			localization.PUT("/teams/:team/language", g.setTeamLanguage)
+			localization.DELETE("/teams/:team/language", g.deleteTeamLanguage)
+		}
+
+		// 移动端推送，当前用户的推送设备和按告警级别的推送偏好
+		pushes := api.Group("/push")
+		{
+			pushes.GET("/devices", g.listPushDevices)
+			pushes.POST("/devices", g.registerPushDevice)
+			pushes.DELETE("/devices/:id", g.deletePushDevice)
+			pushes.GET("/preferences", g.getPushPreference)
+			pushes.PUT("/preferences", g.setPushPreference)
+		}
+
+		// 免打扰，当前用户每周的免打扰时段和临时免打扰，期间只通知允许级别的告警
+		quietHours := api.Group("/quiet-hours")
+		{
+			quietHours.GET("", g.getQuietHours)
+			quietHours.PUT("", g.setQuietHours)
+			quietHours.DELETE("", g.deleteQuietHours)
+			quietHours.POST("/dnd", g.startDND)
+			quietHours.DELETE("/dnd", g.endDND)
+		}
+
+		// 站内通知收件箱，当前用户的通知、未读数和实时推送
+		inbox := api.Group("/inbox")
+		{
+			inbox.GET("", g.listInbox)
+			inbox.GET("/unread-count", g.getInboxUnreadCount)
+			inbox.GET("/stream", g.streamInbox)
+			inbox.POST("/read", g.markInboxRead)
+			inbox.POST("/read-all", g.markAllInboxRead)
+		}
+
+		// 通知日历，非工作时间的低级别告警通知延迟到工作时间汇总发送
+		notificationCalendars := api.Group("/notification-calendars")
+		{
+			notificationCalendars.GET("", g.listNotificationCalendars)
+			notificationCalendars.POST("", g.createNotificationCalendar)
+			notificationCalendars.GET("/:id", g.getNotificationCalendar)
+			notificationCalendars.PUT("/:id", g.updateNotificationCalendar)
+			notificationCalendars.DELETE("/:id", g.deleteNotificationCalendar)
+		}
+
+		// 团队通知渠道，团队告警的通知优先使用团队启用的同类型渠道，管理员和运维人员可管理
+		notificationChannels := api.Group("/notification-channels", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin), string(models.UserRoleOperator)))
+		{
+			notificationChannels.GET("", g.listNotificationChannels)
+			notificationChannels.POST("", g.createNotificationChannel)
+			notificationChannels.GET("/:id", g.getNotificationChannel)
+			notificationChannels.PUT("/:id", g.updateNotificationChannel)
+			notificationChannels.DELETE("/:id", g.deleteNotificationChannel)
+			notificationChannels.POST("/:id/test", g.testTeamNotificationChannel)
+		}
+
+		// 告警重新分级规则，按标签、时段和服务等级在分派和通知路由之前调整入站告警的级别，管理员和运维人员可管理
+		severityRules := api.Group("/alert-severity-rules", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin), string(models.UserRoleOperator)))
+		{
+			severityRules.GET("", g.listAlertSeverityRules)
+			severityRules.POST("", g.createAlertSeverityRule)
+			severityRules.POST("/test", g.testAlertSeverityRules)
+			severityRules.GET("/:id", g.getAlertSeverityRule)
+			severityRules.PUT("/:id", g.updateAlertSeverityRule)
+			severityRules.DELETE("/:id", g.deleteAlertSeverityRule)
+		}
+
+		// 公开状态页的故障和计划维护，发布进展时邮件通知订阅者，管理员和运维人员可管理
+		statusIncidents := api.Group("/status-page/incidents", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin), string(models.UserRoleOperator)))
+		{
+			statusIncidents.GET("", g.listStatusIncidents)
+			statusIncidents.POST("", g.createStatusIncident)
+			statusIncidents.GET("/:id", g.getStatusIncident)
+			statusIncidents.PUT("/:id", g.updateStatusIncident)
+			statusIncidents.DELETE("/:id", g.deleteStatusIncident)
+			statusIncidents.POST("/:id/updates", g.postStatusIncidentUpdate)
+		}
+
+		// 路由试运行，按样例告警排查通知路由，仅管理员可执行
+		api.POST("/routes/test", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin)), g.testRoute)
+
+		// 声明式配置，按配置文件创建、更新和删除数据源、规则、通知路由和告警暂停，仅管理员可执行
+		api.POST("/config/apply", middleware.RequireRoleMiddleware(nil, string(models.UserRoleAdmin)), g.applyConfig)
+
+		// 数据源详情，包含健康检查和查询的熔断状态
+		api.GET("/datasources/:id", g.getDataSource)
+
+		// Kubernetes 数据源立即采集
+		api.POST("/datasources/:id/kubernetes/sync", g.syncKubernetesDataSource)
+
+		// 自定义字段相关路由
+		customFields := api.Group("/custom-fields")
+		{
+			customFields.GET("", g.listCustomFields)
+			customFields.POST("", g.createCustomField)
+			customFields.GET("/schema/:resource", g.getCustomFieldSchema)
+			customFields.GET("/:id", g.getCustomField)
+			customFields.PUT("/:id", g.updateCustomField)
+			customFields.DELETE("/:id", g.deleteCustomField)
 		}
 	}
 }
 
+// registerIngestRoutes 注册告警和指标接入路由
+// 告警接入端点不使用用户认证，而是校验来源IP和集成密钥签名；指标推送端点使用用户或 API Key 认证
+func (g *Gateway) registerIngestRoutes() {
+	ingest := g.router.Group("/api/v1/ingest")
+	g.useIngestMiddleware(ingest)
+	ingest.POST("/:id/alerts", g.ingestAlert)
+	// Prometheus remote-write 指标推送使用 JWT 或 API Key 认证，API Key 须有 metrics:write 权限范围
+	ingest.POST("/remote-write", middleware.RequireAuthMiddleware(g.authService),
+		middleware.APIKeyScopeMiddleware(routeScopes), g.remoteWrite)
+
+	// 自定义 Webhook 按集成的载荷映射转换任意结构的载荷
+	custom := g.router.Group("/api/v1/webhooks/custom")
+	g.useIngestMiddleware(custom)
+	custom.POST("/:integrationID", g.ingestCustomWebhook)
+}
+
+// useIngestMiddleware 为告警接入路由添加全局来源IP过滤和限流
+func (g *Gateway) useIngestMiddleware(group *gin.RouterGroup) {
+	if !g.ingestFilter.Empty() {
+		group.Use(middleware.IPFilterMiddleware(middleware.IPFilterConfig{
+			Filter: g.ingestFilter,
+			Logger: g.logger,
+		}))
+	}
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		group.Use(rateLimit)
+	}
+}
+
+// registerChatOpsRoutes 注册聊天平台命令回调路由
+// 回调端点不使用用户认证，而是校验平台签名，并按聊天用户映射确定执行命令的平台用户
+func (g *Gateway) registerChatOpsRoutes() {
+	chatops := g.router.Group("/api/v1/chatops")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		chatops.Use(rateLimit)
+	}
+
+	chatops.POST("/slack", g.handleSlackCommand)
+	chatops.POST("/dingtalk", g.handleDingTalkCommand)
+}
+
+// registerITSMRoutes 注册外部 ITSM 系统 Webhook 路由
+// Webhook 端点不使用用户认证，而是校验连接器的 Webhook 密钥
+func (g *Gateway) registerITSMRoutes() {
+	itsm := g.router.Group("/api/v1/itsm")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		itsm.Use(rateLimit)
+	}
+
+	itsm.POST("/:id/webhook", g.handleITSMWebhook)
+}
+
+// registerHeartbeatRoutes 注册心跳上报路由
+// 上报端点不使用用户认证，上报地址中的令牌即凭据
+func (g *Gateway) registerHeartbeatRoutes() {
+	heartbeats := g.router.Group("/api/v1/heartbeats")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		heartbeats.Use(rateLimit)
+	}
+
+	heartbeats.POST("/:token", g.pingHeartbeat)
+}
+
+// registerAlertActionRoutes 注册告警操作链接路由
+// 链接不使用用户认证，签名令牌即凭据，GET 只展示确认信息，POST 执行操作
+func (g *Gateway) registerAlertActionRoutes() {
+	actions := g.router.Group("/api/v1/alert-actions")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		actions.Use(rateLimit)
+	}
+
+	actions.GET("/:token", g.previewAlertAction)
+	actions.POST("/:token", g.executeAlertAction)
+}
+
+// registerTicketSurveyRoutes 注册工单满意度评价链接路由
+// 链接不使用用户认证，随机令牌即凭据，GET 只展示评价表单，POST 提交评价
+func (g *Gateway) registerTicketSurveyRoutes() {
+	surveys := g.router.Group("/api/v1/ticket-surveys")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		surveys.Use(rateLimit)
+	}
+
+	surveys.GET("/:token", g.viewTicketSurvey)
+	surveys.POST("/:token", g.respondTicketSurveyForm)
+}
+
+// registerStatusPageRoutes 注册公开状态页路由，未开启状态页时不注册
+// 状态页不使用用户认证，配置了令牌时访问状态页和订阅需要提供令牌；确认和退订链接中的签名令牌即凭据。
+// /status 已用于服务状态检查，状态页使用单独的路径
+func (g *Gateway) registerStatusPageRoutes() {
+	if !g.statusPage {
+		return
+	}
+
+	status := g.router.Group("/status-page")
+	if rateLimit := g.rateLimitMiddleware(); rateLimit != nil {
+		status.Use(rateLimit)
+	}
+
+	status.GET("", g.statusPageAuthMiddleware(), g.viewStatusPage)
+	status.POST("/subscribe", g.statusPageAuthMiddleware(), g.subscribeStatusPage)
+	status.GET("/subscriptions/:token", g.viewStatusSubscription)
+	status.POST("/subscriptions/:token/confirm", g.confirmStatusSubscription)
+	status.POST("/subscriptions/:token/unsubscribe", g.unsubscribeStatusPage)
+}
+
+// rateLimitMiddleware 根据限流配置创建中间件，未启用限流时返回 nil
+func (g *Gateway) rateLimitMiddleware() gin.HandlerFunc {
+	if g.rateLimit == nil {
+		return nil
+	}
+
+	// API 路由和告警接入路由共用同一个限流实例，便于热更新策略
+	if g.rateLimiter == nil {
+		config := *g.rateLimit
+		if config.Logger == nil {
+			config.Logger = g.logger
+		}
+		g.rateLimiter = middleware.NewDynamicRateLimit(config)
+	}
+	return g.rateLimiter.Middleware()
+}
+
+// RateLimit 返回运行中的限流实例，用于热更新限流策略，未启用限流或尚未调用 SetupRoutes 时返回 nil
+func (g *Gateway) RateLimit() *middleware.DynamicRateLimit {
+	return g.rateLimiter
+}
+
 // GetRouter 获取Gin路由器
 func (g *Gateway) GetRouter() *gin.Engine {
 	return g.router
 }
+
+// degraded 返回是否处于降级模式：配置了 Redis 但当前不可用
+func (g *Gateway) degraded() bool {
+	return g.redisState.Configured() && !g.redisState.Available()
+}
+
+// componentStatus 返回依赖组件状态
+func (g *Gateway) componentStatus() gin.H {
+	return gin.H{
+		"redis":       g.redisState.Status(),
+		"maintenance": g.maintenance.Status(),
+	}
+}
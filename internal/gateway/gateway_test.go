@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestGateway_SetupRoutesWithStatusPage(t *testing.T) {
+	g := NewGateway(logrus.New(), nil, &MockServiceManager{})
+	g.SetStatusPage(true, "")
+
+	// 状态页不能与服务状态检查端点 /status 重复注册
+	var handler http.Handler
+	require.NotPanics(t, func() { handler = g.SetupRoutes() })
+
+	routes := make(map[string]bool)
+	for _, route := range g.router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes["GET /status"])
+	assert.True(t, routes["GET /status-page"])
+	assert.True(t, routes["POST /status-page/subscribe"])
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"running"`)
+}
+
+func TestGateway_WebhookManagementRequiresOperator(t *testing.T) {
+	g := NewGateway(logrus.New(), nil, &MockServiceManager{})
+	handler := g.SetupRoutes()
+	token, err := g.authService.GenerateToken("u1", "viewer", "viewer@example.com", []string{string(models.UserRoleViewer)})
+	require.NoError(t, err)
+
+	// 只读用户不能管理接入集成和出站订阅，避免获取签名密钥明文或让服务端请求任意地址
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/webhook-integrations"},
+		{http.MethodPost, "/api/v1/webhook-integrations"},
+		{http.MethodPost, "/api/v1/webhook-integrations/i1/rotate-secret"},
+		{http.MethodGet, "/api/v1/webhook-subscriptions"},
+		{http.MethodPost, "/api/v1/webhook-subscriptions"},
+		{http.MethodPut, "/api/v1/webhook-subscriptions/s1"},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", r.method, r.path)
+	}
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
 )
 
 // 健康检查处理函数
@@ -32,26 +34,56 @@ func (g *Gateway) statusCheck(c *gin.Context) {
 // 认证相关处理函数
 func (g *Gateway) login(c *gin.Context) {
 	// TODO: 实现登录逻辑
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) logout(c *gin.Context) {
 	// TODO: 实现登出逻辑
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) refreshToken(c *gin.Context) {
 	// TODO: 实现刷新令牌逻辑
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) resetPassword(c *gin.Context) {
 	// TODO: 实现重置密码逻辑
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 // 告警相关处理函数
 func (g *Gateway) listAlerts(c *gin.Context) {
+	filter := parseAlertFilter(c)
+
+	// 调用告警服务获取列表
+	alerts, total, err := g.serviceManager.Alert().List(c.Request.Context(), filter)
+	if err != nil {
+		g.logger.WithError(err).Error("获取告警列表失败")
+		apierror.Respond(c, errorStatus(err), "获取告警列表失败", err.Error())
+		return
+	}
+
+	// 计算总页数
+	totalPages := int(total) / filter.PageSize
+	if int(total)%filter.PageSize > 0 {
+		totalPages++
+	}
+
+	// 构造响应
+	response := &models.AlertList{
+		Alerts:     alerts,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: totalPages,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseAlertFilter 从查询参数解析告警列表的过滤条件
+func parseAlertFilter(c *gin.Context) *models.AlertFilter {
 	// 解析查询参数
 	filter := &models.AlertFilter{
 		Page:     1,
@@ -118,6 +150,18 @@ func (g *Gateway) listAlerts(c *gin.Context) {
 		}
 	}
 
+	// 过滤表达式，如 q=severity in (critical,warning) and labels.cluster="prod" and starts_at>now-24h
+	if query := c.Query("q"); query != "" {
+		filter.Query = &query
+	}
+
+	// 默认不返回当前用户暂停中的告警，include_snoozed=true 时返回全部
+	if includeSnoozed, _ := strconv.ParseBool(c.Query("include_snoozed")); !includeSnoozed {
+		if userID := c.GetString("user_id"); userID != "" {
+			filter.HideSnoozedFor = &userID
+		}
+	}
+
 	// 解析排序参数
 	if sortBy := c.Query("sort_by"); sortBy != "" {
 		filter.SortBy = &sortBy
@@ -129,58 +173,41 @@ func (g *Gateway) listAlerts(c *gin.Context) {
 		}
 	}
 
-	// 调用告警服务获取列表
-	alerts, total, err := g.serviceManager.Alert().List(c.Request.Context(), filter)
-	if err != nil {
-		g.logger.WithError(err).Error("获取告警列表失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取告警列表失败",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	// 计算总页数
-	totalPages := int(total) / filter.PageSize
-	if int(total)%filter.PageSize > 0 {
-		totalPages++
-	}
-
-	// 构造响应
-	response := &models.AlertList{
-		Alerts:     alerts,
-		Total:      total,
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		TotalPages: totalPages,
-	}
-
-	c.JSON(http.StatusOK, response)
+	return filter
 }
 
 func (g *Gateway) createAlert(c *gin.Context) {
 	// 解析请求体
 	var req models.AlertCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析创建告警请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 验证请求数据
 	if err := req.Validate(); err != nil {
 		g.logger.WithError(err).Error("创建告警请求验证失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求数据验证失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
 		return
 	}
 
 	// 构造告警对象
+	alert := newAlertFromRequest(&req)
+
+	// 调用告警服务创建告警
+	if err := g.serviceManager.Alert().Create(c.Request.Context(), alert); err != nil {
+		g.recordIngest(false)
+		g.logger.WithError(err).Error("创建告警失败")
+		apierror.Respond(c, errorStatus(err), "创建告警失败", err.Error())
+		return
+	}
+	g.recordIngest(true)
+
+	g.logger.WithField("alert_id", alert.ID).Info("告警创建成功")
+	c.JSON(http.StatusCreated, alert)
+}
+
+// newAlertFromRequest 根据创建请求构造告警对象，未指定开始时间时使用当前时间
+func newAlertFromRequest(req *models.AlertCreateRequest) *models.Alert {
 	alert := &models.Alert{
 		RuleID:       req.RuleID,
 		DataSourceID: req.DataSourceID,
@@ -196,35 +223,20 @@ func (g *Gateway) createAlert(c *gin.Context) {
 		GeneratorURL: req.GeneratorURL,
 	}
 
-	// 设置开始时间
 	if req.StartsAt != nil {
 		alert.StartsAt = *req.StartsAt
 	} else {
 		alert.StartsAt = time.Now()
 	}
 
-	// 调用告警服务创建告警
-	if err := g.serviceManager.Alert().Create(c.Request.Context(), alert); err != nil {
-		g.logger.WithError(err).Error("创建告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "创建告警失败",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	g.logger.WithField("alert_id", alert.ID).Info("告警创建成功")
-	c.JSON(http.StatusCreated, alert)
+	return alert
 }
 
 func (g *Gateway) getAlert(c *gin.Context) {
 	// 获取告警ID
 	alertID := c.Param("id")
 	if alertID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "告警ID不能为空",
-			"message": "请提供有效的告警ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
 		return
 	}
 
@@ -232,19 +244,13 @@ func (g *Gateway) getAlert(c *gin.Context) {
 	alert, err := g.serviceManager.Alert().GetByID(c.Request.Context(), alertID)
 	if err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("获取告警详情失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取告警详情失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取告警详情失败", err.Error())
 		return
 	}
 
 	// 检查告警是否存在
 	if alert == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "告警不存在",
-			"message": "指定的告警ID不存在",
-		})
+		apierror.Respond(c, http.StatusNotFound, "告警不存在", "指定的告警ID不存在")
 		return
 	}
 
@@ -255,31 +261,20 @@ func (g *Gateway) updateAlert(c *gin.Context) {
 	// 获取告警ID
 	alertID := c.Param("id")
 	if alertID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "告警ID不能为空",
-			"message": "请提供有效的告警ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
 		return
 	}
 
 	// 解析请求体
 	var req models.AlertUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析更新告警请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 验证请求数据
 	if err := req.Validate(); err != nil {
 		g.logger.WithError(err).Error("更新告警请求验证失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求数据验证失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
 		return
 	}
 
@@ -287,18 +282,12 @@ func (g *Gateway) updateAlert(c *gin.Context) {
 	alert, err := g.serviceManager.Alert().GetByID(c.Request.Context(), alertID)
 	if err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("获取告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取告警失败", err.Error())
 		return
 	}
 
 	if alert == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "告警不存在",
-			"message": "指定的告警ID不存在",
-		})
+		apierror.Respond(c, http.StatusNotFound, "告警不存在", "指定的告警ID不存在")
 		return
 	}
 
@@ -334,10 +323,7 @@ func (g *Gateway) updateAlert(c *gin.Context) {
 	// 调用告警服务更新告警
 	if err := g.serviceManager.Alert().Update(c.Request.Context(), alert); err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("更新告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "更新告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "更新告警失败", err.Error())
 		return
 	}
 
@@ -349,10 +335,7 @@ func (g *Gateway) deleteAlert(c *gin.Context) {
 	// 获取告警ID
 	alertID := c.Param("id")
 	if alertID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "告警ID不能为空",
-			"message": "请提供有效的告警ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
 		return
 	}
 
@@ -360,28 +343,19 @@ func (g *Gateway) deleteAlert(c *gin.Context) {
 	alert, err := g.serviceManager.Alert().GetByID(c.Request.Context(), alertID)
 	if err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("获取告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取告警失败", err.Error())
 		return
 	}
 
 	if alert == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "告警不存在",
-			"message": "指定的告警ID不存在",
-		})
+		apierror.Respond(c, http.StatusNotFound, "告警不存在", "指定的告警ID不存在")
 		return
 	}
 
 	// 调用告警服务删除告警
 	if err := g.serviceManager.Alert().Delete(c.Request.Context(), alertID); err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("删除告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "删除告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "删除告警失败", err.Error())
 		return
 	}
 
@@ -396,41 +370,27 @@ func (g *Gateway) acknowledgeAlert(c *gin.Context) {
 	// 获取告警ID
 	alertID := c.Param("id")
 	if alertID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "告警ID不能为空",
-			"message": "请提供有效的告警ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
 		return
 	}
 
 	// 解析请求体
 	var req models.AlertAckRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析确认告警请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 验证请求数据
 	if err := req.Validate(); err != nil {
 		g.logger.WithError(err).Error("确认告警请求验证失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求数据验证失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
 		return
 	}
 
 	// 调用告警服务确认告警
 	if err := g.serviceManager.Alert().Acknowledge(c.Request.Context(), alertID, req.UserID); err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("确认告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "确认告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "确认告警失败", err.Error())
 		return
 	}
 
@@ -445,41 +405,27 @@ func (g *Gateway) resolveAlert(c *gin.Context) {
 	// 获取告警ID
 	alertID := c.Param("id")
 	if alertID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "告警ID不能为空",
-			"message": "请提供有效的告警ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
 		return
 	}
 
 	// 解析请求体
 	var req models.AlertResolveRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析解决告警请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 验证请求数据
 	if err := req.Validate(); err != nil {
 		g.logger.WithError(err).Error("解决告警请求验证失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求数据验证失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
 		return
 	}
 
 	// 调用告警服务解决告警
 	if err := g.serviceManager.Alert().Resolve(c.Request.Context(), alertID, req.UserID); err != nil {
 		g.logger.WithError(err).WithField("alert_id", alertID).Error("解决告警失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "解决告警失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "解决告警失败", err.Error())
 		return
 	}
 
@@ -541,10 +487,7 @@ func (g *Gateway) listRules(c *gin.Context) {
 	rules, total, err := g.serviceManager.Rule().List(c.Request.Context(), filter)
 	if err != nil {
 		g.logger.WithError(err).Error("获取规则列表失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取规则列表失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取规则列表失败", err.Error())
 		return
 	}
 
@@ -569,22 +512,14 @@ func (g *Gateway) listRules(c *gin.Context) {
 func (g *Gateway) createRule(c *gin.Context) {
 	// 解析请求体
 	var req models.RuleCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析创建规则请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 验证请求数据
 	if err := req.Validate(); err != nil {
 		g.logger.WithError(err).Error("创建规则请求验证失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求数据验证失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
 		return
 	}
 
@@ -606,10 +541,7 @@ func (g *Gateway) createRule(c *gin.Context) {
 	// 调用规则服务创建规则
 	if err := g.serviceManager.Rule().Create(c.Request.Context(), rule); err != nil {
 		g.logger.WithError(err).Error("创建规则失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "创建规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "创建规则失败", err.Error())
 		return
 	}
 
@@ -624,10 +556,7 @@ func (g *Gateway) getRule(c *gin.Context) {
 	// 获取规则ID
 	ruleID := c.Param("id")
 	if ruleID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "规则ID不能为空",
-			"message": "请提供有效的规则ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "规则ID不能为空", "请提供有效的规则ID")
 		return
 	}
 
@@ -635,10 +564,7 @@ func (g *Gateway) getRule(c *gin.Context) {
 	rule, err := g.serviceManager.Rule().GetByID(c.Request.Context(), ruleID)
 	if err != nil {
 		g.logger.WithError(err).WithField("rule_id", ruleID).Error("获取规则失败")
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "获取规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusNotFound, "获取规则失败", err.Error())
 		return
 	}
 
@@ -652,21 +578,13 @@ func (g *Gateway) updateRule(c *gin.Context) {
 	// 获取规则ID
 	ruleID := c.Param("id")
 	if ruleID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "规则ID不能为空",
-			"message": "请提供有效的规则ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "规则ID不能为空", "请提供有效的规则ID")
 		return
 	}
 
 	// 解析请求体
 	var req models.RuleUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		g.logger.WithError(err).Error("解析更新规则请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -722,10 +640,7 @@ func (g *Gateway) updateRule(c *gin.Context) {
 	// 调用规则服务更新规则
 	if err := g.serviceManager.Rule().Update(c.Request.Context(), rule); err != nil {
 		g.logger.WithError(err).WithField("rule_id", ruleID).Error("更新规则失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "更新规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "更新规则失败", err.Error())
 		return
 	}
 
@@ -740,20 +655,14 @@ func (g *Gateway) deleteRule(c *gin.Context) {
 	// 获取规则ID
 	ruleID := c.Param("id")
 	if ruleID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "规则ID不能为空",
-			"message": "请提供有效的规则ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "规则ID不能为空", "请提供有效的规则ID")
 		return
 	}
 
 	// 调用规则服务删除规则
 	if err := g.serviceManager.Rule().Delete(c.Request.Context(), ruleID); err != nil {
 		g.logger.WithError(err).WithField("rule_id", ruleID).Error("删除规则失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "删除规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "删除规则失败", err.Error())
 		return
 	}
 
@@ -768,20 +677,14 @@ func (g *Gateway) enableRule(c *gin.Context) {
 	// 获取规则ID
 	ruleID := c.Param("id")
 	if ruleID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "规则ID不能为空",
-			"message": "请提供有效的规则ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "规则ID不能为空", "请提供有效的规则ID")
 		return
 	}
 
 	// 调用规则服务启用规则
 	if err := g.serviceManager.Rule().Enable(c.Request.Context(), ruleID); err != nil {
 		g.logger.WithError(err).WithField("rule_id", ruleID).Error("启用规则失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "启用规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "启用规则失败", err.Error())
 		return
 	}
 
@@ -796,20 +699,14 @@ func (g *Gateway) disableRule(c *gin.Context) {
 	// 获取规则ID
 	ruleID := c.Param("id")
 	if ruleID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "规则ID不能为空",
-			"message": "请提供有效的规则ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "规则ID不能为空", "请提供有效的规则ID")
 		return
 	}
 
 	// 调用规则服务禁用规则
 	if err := g.serviceManager.Rule().Disable(c.Request.Context(), ruleID); err != nil {
 		g.logger.WithError(err).WithField("rule_id", ruleID).Error("禁用规则失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "禁用规则失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "禁用规则失败", err.Error())
 		return
 	}
 
@@ -851,14 +748,18 @@ func (g *Gateway) listDataSources(c *gin.Context) {
 	// 调用服务层
 	dataSources, total, err := g.serviceManager.DataSource().List(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, errorStatus(err), "", err)
 		return
 	}
 	
-	// 返回结果
+	// 返回结果，隐藏密码等敏感信息
+	redacted := make([]*models.DataSource, len(dataSources))
+	for i, dataSource := range dataSources {
+		redacted[i] = dataSource.Redacted()
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
-			"data_sources": dataSources,
+			"data_sources": redacted,
 			"total":        total,
 			"page":         filter.Page,
 			"page_size":    filter.PageSize,
@@ -868,47 +769,45 @@ func (g *Gateway) listDataSources(c *gin.Context) {
 
 func (g *Gateway) createDataSource(c *gin.Context) {
 	var dataSource models.DataSource
-	if err := c.ShouldBindJSON(&dataSource); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+	if !bindJSON(c, &dataSource) {
 		return
 	}
 	
 	// 调用服务层创建数据源
 	if err := g.serviceManager.DataSource().Create(c.Request.Context(), &dataSource); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, errorStatus(err), "", err)
 		return
 	}
 	
-	c.JSON(http.StatusCreated, gin.H{"data": dataSource})
+	c.JSON(http.StatusCreated, gin.H{"data": dataSource.Redacted()})
 }
 
 func (g *Gateway) getDataSource(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "数据源ID不能为空"})
+		apierror.Respond(c, http.StatusBadRequest, "数据源ID不能为空", nil)
 		return
 	}
 	
 	// 调用服务层获取数据源
 	dataSource, err := g.serviceManager.DataSource().GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusNotFound, "", err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{"data": dataSource})
+	c.JSON(http.StatusOK, gin.H{"data": dataSource.Redacted()})
 }
 
 func (g *Gateway) updateDataSource(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "数据源ID不能为空"})
+		apierror.Respond(c, http.StatusBadRequest, "数据源ID不能为空", nil)
 		return
 	}
 	
 	var dataSource models.DataSource
-	if err := c.ShouldBindJSON(&dataSource); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+	if !bindJSON(c, &dataSource) {
 		return
 	}
 	
@@ -917,23 +816,29 @@ func (g *Gateway) updateDataSource(c *gin.Context) {
 	
 	// 调用服务层更新数据源
 	if err := g.serviceManager.DataSource().Update(c.Request.Context(), &dataSource); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, errorStatus(err), "", err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{"data": dataSource})
+	c.JSON(http.StatusOK, gin.H{"data": dataSource.Redacted()})
 }
 
 func (g *Gateway) deleteDataSource(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "数据源ID不能为空"})
+		apierror.Respond(c, http.StatusBadRequest, "数据源ID不能为空", nil)
 		return
 	}
 	
+	// 删除数据源需要审批时只能通过审批请求执行
+	if g.serviceManager.Approval().RequiresApproval(models.ApprovalActionDataSourceDelete) {
+		apierror.Respond(c, errorStatus(models.ErrApprovalRequired), "", models.ErrApprovalRequired)
+		return
+	}
+
 	// 调用服务层删除数据源
 	if err := g.serviceManager.DataSource().Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, errorStatus(err), "", err)
 		return
 	}
 	
@@ -943,13 +848,13 @@ func (g *Gateway) deleteDataSource(c *gin.Context) {
 func (g *Gateway) testDataSource(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "数据源ID不能为空"})
+		apierror.Respond(c, http.StatusBadRequest, "数据源ID不能为空", nil)
 		return
 	}
 	
 	// 调用服务层测试数据源连接
 	if err := g.serviceManager.DataSource().TestConnection(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, errorStatus(err), "", err)
 		return
 	}
 	
@@ -958,73 +863,222 @@ func (g *Gateway) testDataSource(c *gin.Context) {
 
 // 工单相关处理函数
 func (g *Gateway) listTickets(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) createTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) getTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) updateTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) deleteTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) assignTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 // 知识库相关处理函数
 func (g *Gateway) listKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	filter := &models.KnowledgeFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	// 解析分页参数
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			filter.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			filter.PageSize = pageSize
+		}
+	}
+
+	// 解析过滤参数
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.KnowledgeStatus(statusStr)
+		filter.Status = &status
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		knowledgeType := models.KnowledgeType(typeStr)
+		filter.Type = &knowledgeType
+	}
+
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		filter.CategoryID = &categoryID
+	}
+
+	if keyword := c.Query("keyword"); keyword != "" {
+		filter.Keyword = &keyword
+	}
+
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		filter.Tags = tags
+	}
+
+	// 按访问控制过滤，受限文章不出现在列表中
+	filter.ViewerID = g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), c.GetString("user_id"))
+
+	knowledge, total, err := g.serviceManager.Knowledge().List(c.Request.Context(), filter)
+	if err != nil {
+		g.logger.WithError(err).Error("获取知识库列表失败")
+		apierror.Respond(c, errorStatus(err), "获取知识库列表失败", err.Error())
+		return
+	}
+
+	// 计算总页数
+	totalPages := int(total) / filter.PageSize
+	if int(total)%filter.PageSize > 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"knowledge":   knowledge,
+		"total":       total,
+		"page":        filter.Page,
+		"page_size":   filter.PageSize,
+		"total_pages": totalPages,
+	})
 }
 
 func (g *Gateway) createKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	var req models.KnowledgeCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	knowledge := &models.Knowledge{
+		Title:      req.Title,
+		Summary:    req.Summary,
+		Content:    req.Content,
+		Type:       req.Type,
+		Visibility: req.Visibility,
+		Format:     req.Format,
+		CategoryID: req.CategoryID,
+		Tags:       req.Tags,
+		Keywords:   req.Keywords,
+		TeamID:     req.TeamID,
+		RelatedIDs: req.RelatedIDs,
+		ExpiresAt:  req.ExpiresAt,
+		AuthorID:   c.GetString("user_id"),
+	}
+	if req.Slug != nil {
+		knowledge.Slug = *req.Slug
+	}
+	if req.Language != nil {
+		knowledge.Language = *req.Language
+	}
+	if req.Priority != nil {
+		knowledge.Priority = *req.Priority
+	}
+	if req.IsFeatured != nil {
+		knowledge.IsFeatured = *req.IsFeatured
+	}
+	if req.Metadata != nil {
+		knowledge.Metadata = *req.Metadata
+	}
+
+	// 创建前检测相似文章，仅作为警告返回，不阻断创建
+	warnings := make([]*models.KnowledgeWarning, 0)
+	duplicates, err := g.serviceManager.Knowledge().CheckDuplicates(c.Request.Context(), &models.KnowledgeDuplicateCheckRequest{
+		Title:    req.Title,
+		Content:  req.Content,
+		ViewerID: g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), knowledge.AuthorID),
+	})
+	if err != nil {
+		g.logger.WithError(err).WithField("title", req.Title).Warn("检测相似文章失败")
+	} else if len(duplicates) > 0 {
+		warnings = append(warnings, &models.KnowledgeWarning{
+			Code:       models.KnowledgeWarningPossibleDuplicate,
+			Message:    fmt.Sprintf("发现%d篇可能重复的文章", len(duplicates)),
+			Duplicates: duplicates,
+		})
+	}
+
+	if err := g.serviceManager.Knowledge().Create(c.Request.Context(), knowledge); err != nil {
+		g.logger.WithError(err).WithField("title", req.Title).Error("创建知识库条目失败")
+		apierror.Respond(c, errorStatus(err), "创建知识库条目失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "知识库条目创建成功",
+		"data":     knowledge,
+		"warnings": warnings,
+	})
 }
 
 func (g *Gateway) getKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) updateKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) deleteKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) searchKnowledge(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	query := c.Query("q")
+	if query == "" {
+		apierror.Respond(c, http.StatusBadRequest, "搜索关键词不能为空", "请通过参数q提供搜索关键词")
+		return
+	}
+
+	viewerID := g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), c.GetString("user_id"))
+	knowledge, err := g.serviceManager.Knowledge().Search(c.Request.Context(), query, viewerID)
+	if err != nil {
+		g.logger.WithError(err).WithField("query", query).Error("搜索知识库失败")
+		apierror.Respond(c, errorStatus(err), "搜索知识库失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"knowledge": knowledge,
+		"total":     len(knowledge),
+		"query":     query,
+	})
 }
 
 // 用户相关处理函数
 func (g *Gateway) listUsers(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) createUser(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) getUser(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) updateUser(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) deleteUser(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 // Webhook相关处理函数
@@ -1068,10 +1122,7 @@ func (g *Gateway) listWebhooks(c *gin.Context) {
 	webhooks, total, err := g.serviceManager.Webhook().List(c.Request.Context(), filter)
 	if err != nil {
 		g.logger.WithError(err).Error("获取Webhook列表失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取Webhook列表失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取Webhook列表失败", err.Error())
 		return
 	}
 
@@ -1081,9 +1132,13 @@ func (g *Gateway) listWebhooks(c *gin.Context) {
 		totalPages++
 	}
 
-	// 构造响应
+	// 构造响应，隐藏密钥等敏感信息
+	redacted := make([]*models.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		redacted[i] = webhook.Redacted()
+	}
 	response := gin.H{
-		"webhooks":    webhooks,
+		"webhooks":    redacted,
 		"total":       total,
 		"page":        filter.Page,
 		"page_size":   filter.PageSize,
@@ -1096,37 +1151,26 @@ func (g *Gateway) listWebhooks(c *gin.Context) {
 func (g *Gateway) createWebhook(c *gin.Context) {
 	// 解析请求体
 	var webhook models.Webhook
-	if err := c.ShouldBindJSON(&webhook); err != nil {
-		g.logger.WithError(err).Error("解析创建Webhook请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &webhook) {
 		return
 	}
 
 	// 调用Webhook服务创建Webhook
 	if err := g.serviceManager.Webhook().Create(c.Request.Context(), &webhook); err != nil {
 		g.logger.WithError(err).Error("创建Webhook失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "创建Webhook失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "创建Webhook失败", err.Error())
 		return
 	}
 
 	g.logger.WithField("webhook_id", webhook.ID).Info("Webhook创建成功")
-	c.JSON(http.StatusCreated, webhook)
+	c.JSON(http.StatusCreated, webhook.Redacted())
 }
 
 func (g *Gateway) getWebhook(c *gin.Context) {
 	// 获取Webhook ID
 	webhookID := c.Param("id")
 	if webhookID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Webhook ID不能为空",
-			"message": "请提供有效的Webhook ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Webhook ID不能为空", "请提供有效的Webhook ID")
 		return
 	}
 
@@ -1134,44 +1178,30 @@ func (g *Gateway) getWebhook(c *gin.Context) {
 	webhook, err := g.serviceManager.Webhook().GetByID(c.Request.Context(), webhookID)
 	if err != nil {
 		g.logger.WithError(err).WithField("webhook_id", webhookID).Error("获取Webhook详情失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "获取Webhook详情失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "获取Webhook详情失败", err.Error())
 		return
 	}
 
 	// 检查Webhook是否存在
 	if webhook == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Webhook不存在",
-			"message": "指定的Webhook ID不存在",
-		})
+		apierror.Respond(c, http.StatusNotFound, "Webhook不存在", "指定的Webhook ID不存在")
 		return
 	}
 
-	c.JSON(http.StatusOK, webhook)
+	c.JSON(http.StatusOK, webhook.Redacted())
 }
 
 func (g *Gateway) updateWebhook(c *gin.Context) {
 	// 获取Webhook ID
 	webhookID := c.Param("id")
 	if webhookID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Webhook ID不能为空",
-			"message": "请提供有效的Webhook ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Webhook ID不能为空", "请提供有效的Webhook ID")
 		return
 	}
 
 	// 解析请求体
 	var webhook models.Webhook
-	if err := c.ShouldBindJSON(&webhook); err != nil {
-		g.logger.WithError(err).Error("解析更新Webhook请求失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "请求参数无效",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &webhook) {
 		return
 	}
 
@@ -1179,10 +1209,7 @@ func (g *Gateway) updateWebhook(c *gin.Context) {
 	webhookUUID, err := uuid.Parse(webhookID)
 	if err != nil {
 		g.logger.WithError(err).Error("解析Webhook ID失败")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Webhook ID格式无效",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Webhook ID格式无效", err.Error())
 		return
 	}
 	webhook.ID = webhookUUID
@@ -1190,35 +1217,26 @@ func (g *Gateway) updateWebhook(c *gin.Context) {
 	// 调用Webhook服务更新Webhook
 	if err := g.serviceManager.Webhook().Update(c.Request.Context(), &webhook); err != nil {
 		g.logger.WithError(err).WithField("webhook_id", webhookID).Error("更新Webhook失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "更新Webhook失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "更新Webhook失败", err.Error())
 		return
 	}
 
 	g.logger.WithField("webhook_id", webhookID).Info("Webhook更新成功")
-	c.JSON(http.StatusOK, webhook)
+	c.JSON(http.StatusOK, webhook.Redacted())
 }
 
 func (g *Gateway) deleteWebhook(c *gin.Context) {
 	// 获取Webhook ID
 	webhookID := c.Param("id")
 	if webhookID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Webhook ID不能为空",
-			"message": "请提供有效的Webhook ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Webhook ID不能为空", "请提供有效的Webhook ID")
 		return
 	}
 
 	// 调用Webhook服务删除Webhook
 	if err := g.serviceManager.Webhook().Delete(c.Request.Context(), webhookID); err != nil {
 		g.logger.WithError(err).WithField("webhook_id", webhookID).Error("删除Webhook失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "删除Webhook失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "删除Webhook失败", err.Error())
 		return
 	}
 
@@ -1231,25 +1249,22 @@ func (g *Gateway) deleteWebhook(c *gin.Context) {
 
 // 配置相关处理函数
 func (g *Gateway) listConfig(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) setConfig(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) deleteConfig(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
 
 func (g *Gateway) triggerWebhook(c *gin.Context) {
 	// 获取Webhook ID
 	webhookID := c.Param("id")
 	if webhookID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Webhook ID不能为空",
-			"message": "请提供有效的Webhook ID",
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Webhook ID不能为空", "请提供有效的Webhook ID")
 		return
 	}
 
@@ -1263,10 +1278,7 @@ func (g *Gateway) triggerWebhook(c *gin.Context) {
 	// 调用Webhook服务触发Webhook
 	if err := g.serviceManager.Webhook().Trigger(c.Request.Context(), webhookID, payload); err != nil {
 		g.logger.WithError(err).WithField("webhook_id", webhookID).Error("触发Webhook失败")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "触发Webhook失败",
-			"message": err.Error(),
-		})
+		apierror.Respond(c, errorStatus(err), "触发Webhook失败", err.Error())
 		return
 	}
 
@@ -1281,5 +1293,5 @@ func (g *Gateway) triggerWebhook(c *gin.Context) {
 // Worker状态处理函数
 func (g *Gateway) getWorkerStatus(c *gin.Context) {
 	// TODO: 实现获取Worker状态逻辑
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented yet"})
+	apierror.Respond(c, http.StatusNotImplemented, "功能未实现", nil)
 }
\ No newline at end of file
@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// alertActionPage 操作链接的确认和结果页面。邮件客户端和聊天工具会预先打开链接，
+// 因此打开链接只展示确认页面，由用户点击按钮提交后才执行操作
+var alertActionPage = template.Must(template.New("alert-action").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Pulse</title></head>
+<body>
+{{if .Error}}<p>{{.Error}}</p>{{else}}{{with .View}}
+<h3>{{.AlertName}}</h3>
+<p>{{.Severity}} / {{.Status}}</p>
+{{if .Used}}<p>{{$.Done}}</p>{{else}}
+<form method="post"><button type="submit">{{$.Label}}</button></form>{{end}}
+{{end}}{{end}}
+</body>
+</html>`))
+
+// alertActionLabels 确认页面上各操作的按钮文本
+var alertActionLabels = map[string]string{
+	"ack":     "确认告警",
+	"resolve": "解决告警",
+	"snooze":  "暂停通知",
+}
+
+// 告警操作链接相关处理函数
+func (g *Gateway) previewAlertAction(c *gin.Context) {
+	view, err := g.serviceManager.AlertActionLink().Preview(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		g.respondAlertActionError(c, err, "打开告警操作链接失败")
+		return
+	}
+
+	g.respondAlertAction(c, view)
+}
+
+func (g *Gateway) executeAlertAction(c *gin.Context) {
+	view, err := g.serviceManager.AlertActionLink().Execute(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		g.respondAlertActionError(c, err, "执行告警操作失败")
+		return
+	}
+
+	g.respondAlertAction(c, view)
+}
+
+// respondAlertAction 浏览器访问时返回页面，其他客户端返回 JSON
+func (g *Gateway) respondAlertAction(c *gin.Context, view *models.AlertActionLinkView) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		c.JSON(http.StatusOK, gin.H{
+			"data": view,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = alertActionPage.Execute(c.Writer, gin.H{
+		"View":  view,
+		"Label": localizeText(c, alertActionLabels[view.Action]),
+		"Done":  localizeText(c, "操作已完成"),
+	})
+}
+
+func (g *Gateway) respondAlertActionError(c *gin.Context, err error, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		apierror.Respond(c, errorStatus(err), message, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(errorStatus(err))
+	_ = alertActionPage.Execute(c.Writer, gin.H{
+		"Error": localizeText(c, message) + ": " + localizeText(c, err.Error()),
+	})
+}
+
+// localizeText 按请求语言翻译页面文本，没有对应翻译时返回原文
+func localizeText(c *gin.Context, text string) string {
+	if localized, ok := apierror.Localize(text, apierror.LocaleFromContext(c)); ok {
+		return localized
+	}
+	return text
+}
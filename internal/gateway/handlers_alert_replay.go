@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// replayAlerts 按当前的路由配置试运行时间范围内的历史告警，返回每个告警会通知的接收人，不发送通知
+func (g *Gateway) replayAlerts(c *gin.Context) {
+	var req models.AlertReplayRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := g.serviceManager.AlertReplay().Replay(c.Request.Context(), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "告警回放失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// testRoute 按当前的路由配置试运行样例告警，返回路由步骤、接收人、告警指纹以及生效的静默和暂停，不创建告警也不发送通知
+func (g *Gateway) testRoute(c *gin.Context) {
+	var req models.RoutePreviewRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	preview, err := g.serviceManager.AlertReplay().Preview(c.Request.Context(), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "路由试运行失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": preview,
+	})
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警重新分级规则相关处理函数，规则在告警接入时、分派和通知路由之前调整告警级别
+func (g *Gateway) listAlertSeverityRules(c *gin.Context) {
+	rules, err := g.serviceManager.AlertSeverityRule().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警重新分级规则列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  rules,
+		"total": len(rules),
+	})
+}
+
+func (g *Gateway) getAlertSeverityRule(c *gin.Context) {
+	rule, err := g.serviceManager.AlertSeverityRule().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警重新分级规则失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rule,
+	})
+}
+
+func (g *Gateway) createAlertSeverityRule(c *gin.Context) {
+	var req models.AlertSeverityRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := g.serviceManager.AlertSeverityRule().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建告警重新分级规则失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "告警重新分级规则创建成功",
+		"data":    rule,
+	})
+}
+
+func (g *Gateway) updateAlertSeverityRule(c *gin.Context) {
+	var req models.AlertSeverityRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := g.serviceManager.AlertSeverityRule().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新告警重新分级规则失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "告警重新分级规则更新成功",
+		"data":    rule,
+	})
+}
+
+func (g *Gateway) deleteAlertSeverityRule(c *gin.Context) {
+	if err := g.serviceManager.AlertSeverityRule().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除告警重新分级规则失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "告警重新分级规则删除成功",
+	})
+}
+
+// testAlertSeverityRules 按样例告警的标签和级别试算重新分级结果，用于上线规则前排查
+func (g *Gateway) testAlertSeverityRules(c *gin.Context) {
+	var req models.AlertSeverityRuleTestRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := g.serviceManager.AlertSeverityRule().Test(c.Request.Context(), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "试算告警重新分级失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
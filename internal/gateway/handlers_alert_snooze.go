@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警暂停相关处理函数，暂停只对当前用户生效
+func (g *Gateway) listAlertSnoozes(c *gin.Context) {
+	snoozes, err := g.serviceManager.AlertSnooze().List(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警暂停列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  snoozes,
+		"total": len(snoozes),
+	})
+}
+
+func (g *Gateway) createAlertSnooze(c *gin.Context) {
+	var req models.AlertSnoozeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	g.respondAlertSnooze(c, &req)
+}
+
+func (g *Gateway) snoozeAlert(c *gin.Context) {
+	var req models.AlertSnoozeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	alertID := c.Param("id")
+	req.AlertID = &alertID
+	req.Matchers = nil
+
+	g.respondAlertSnooze(c, &req)
+}
+
+// respondAlertSnooze 为当前用户创建告警暂停并返回结果
+func (g *Gateway) respondAlertSnooze(c *gin.Context, req *models.AlertSnoozeRequest) {
+	snooze, err := g.serviceManager.AlertSnooze().Create(c.Request.Context(), req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "暂停告警失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "告警已暂停",
+		"data":    snooze,
+	})
+}
+
+func (g *Gateway) deleteAlertSnooze(c *gin.Context) {
+	if err := g.serviceManager.AlertSnooze().Delete(c.Request.Context(), c.Param("id"), c.GetString("user_id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "取消告警暂停失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "告警暂停已取消",
+	})
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警统计相关处理函数
+func (g *Gateway) getAlertStats(c *gin.Context) {
+	query, ok := parseAlertStatsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := g.serviceManager.AlertStats().Stats(c.Request.Context(), query)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警统计失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
+
+func (g *Gateway) getAlertStatsTrend(c *gin.Context) {
+	query, ok := parseAlertStatsQuery(c)
+	if !ok {
+		return
+	}
+	query.Interval = models.AlertStatsInterval(c.Query("interval"))
+
+	trend, err := g.serviceManager.AlertStats().Trend(c.Request.Context(), query)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警趋势失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": trend,
+	})
+}
+
+// parseAlertStatsQuery 解析统计时间范围和过滤条件，解析失败时已写入响应
+func parseAlertStatsQuery(c *gin.Context) (*models.AlertStatsQuery, bool) {
+	query := &models.AlertStatsQuery{}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return nil, false
+		}
+		query.From = from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return nil, false
+		}
+		query.To = to
+	}
+
+	// 参数存在但为空时过滤没有规则或没有团队的告警
+	if ruleID, ok := c.GetQuery("rule_id"); ok {
+		query.RuleID = &ruleID
+	}
+	if team, ok := c.GetQuery("team"); ok {
+		query.Team = &team
+	}
+	if severityStr := c.Query("severity"); severityStr != "" {
+		severity := models.AlertSeverity(severityStr)
+		query.Severity = &severity
+	}
+	return query, true
+}
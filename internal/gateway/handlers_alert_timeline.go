@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警时间线相关处理函数
+func (g *Gateway) getAlertTimeline(c *gin.Context) {
+	id := c.Param("id")
+
+	timeline, err := g.serviceManager.AlertTimeline().GetTimeline(c.Request.Context(), id)
+	if err != nil {
+		g.logger.WithError(err).WithField("alert_id", id).Error("获取告警时间线失败")
+		apierror.Respond(c, errorStatus(err), "获取告警时间线失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": timeline,
+	})
+}
+
+func (g *Gateway) addAlertComment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.AlertCommentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	event, err := g.serviceManager.AlertTimeline().AddComment(c.Request.Context(), id, c.GetString("user_id"), req.Comment)
+	if err != nil {
+		g.logger.WithError(err).WithField("alert_id", id).Error("添加告警评论失败")
+		apierror.Respond(c, errorStatus(err), "添加告警评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "告警评论添加成功",
+		"data":    event,
+	})
+}
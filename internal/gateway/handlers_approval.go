@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 敏感操作审批相关处理函数
+func (g *Gateway) listApprovals(c *gin.Context) {
+	filter := &models.ApprovalFilter{}
+	if status := c.Query("status"); status != "" {
+		s := models.ApprovalStatus(status)
+		filter.Status = &s
+	}
+	if action := c.Query("action"); action != "" {
+		a := models.ApprovalAction(action)
+		filter.Action = &a
+	}
+	if requesterID := c.Query("requester_id"); requesterID != "" {
+		filter.RequesterID = &requesterID
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	requests, err := g.serviceManager.Approval().List(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取审批请求列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  requests,
+		"total": len(requests),
+	})
+}
+
+func (g *Gateway) submitApproval(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ApprovalSubmitRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	request, err := g.serviceManager.Approval().Submit(c.Request.Context(), userID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("action", req.Action).WithField("user_id", userID).Error("提交敏感操作失败")
+		apierror.Respond(c, errorStatus(err), "提交敏感操作失败", err.Error())
+		return
+	}
+
+	message := "操作已执行"
+	if request.IsPending() {
+		message = "操作已提交审批"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+		"data":    request,
+	})
+}
+
+func (g *Gateway) getApproval(c *gin.Context) {
+	request, err := g.serviceManager.Approval().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取审批请求失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": request,
+	})
+}
+
+func (g *Gateway) getApprovalHistory(c *gin.Context) {
+	logs, err := g.serviceManager.Approval().History(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取审批记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  logs,
+		"total": len(logs),
+	})
+}
+
+func (g *Gateway) approveApproval(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req models.ApprovalDecisionRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	request, err := g.serviceManager.Approval().Approve(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("approval_id", id).WithField("approver_id", userID).Error("批准审批请求失败")
+		apierror.Respond(c, errorStatus(err), "批准审批请求失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已批准",
+		"data":    request,
+	})
+}
+
+func (g *Gateway) rejectApproval(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req models.ApprovalDecisionRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	request, err := g.serviceManager.Approval().Reject(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("approval_id", id).WithField("approver_id", userID).Error("驳回审批请求失败")
+		apierror.Respond(c, errorStatus(err), "驳回审批请求失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已驳回",
+		"data":    request,
+	})
+}
+
+func (g *Gateway) cancelApproval(c *gin.Context) {
+	id := c.Param("id")
+
+	request, err := g.serviceManager.Approval().Cancel(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "撤回审批请求失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已撤回",
+		"data":    request,
+	})
+}
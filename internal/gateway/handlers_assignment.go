@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 分派策略相关处理函数，自动创建的工单和告警按所属团队的策略分派处理人
+func (g *Gateway) listAssignmentPolicies(c *gin.Context) {
+	policies, err := g.serviceManager.Assignment().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取分派策略列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  policies,
+		"total": len(policies),
+	})
+}
+
+func (g *Gateway) getAssignmentPolicy(c *gin.Context) {
+	policy, err := g.serviceManager.Assignment().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取分派策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": policy,
+	})
+}
+
+func (g *Gateway) createAssignmentPolicy(c *gin.Context) {
+	var req models.AssignmentPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy, err := g.serviceManager.Assignment().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建分派策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "分派策略创建成功",
+		"data":    policy,
+	})
+}
+
+func (g *Gateway) updateAssignmentPolicy(c *gin.Context) {
+	var req models.AssignmentPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy, err := g.serviceManager.Assignment().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新分派策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "分派策略更新成功",
+		"data":    policy,
+	})
+}
+
+func (g *Gateway) deleteAssignmentPolicy(c *gin.Context) {
+	if err := g.serviceManager.Assignment().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除分派策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "分派策略删除成功",
+	})
+}
+
+// autoAssignTicket 按团队的分派策略将工单重新分派给当前处理人以外的成员，供升级流程调用
+func (g *Gateway) autoAssignTicket(c *gin.Context) {
+	var req models.TicketAutoAssignRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	ticket, err := g.serviceManager.Ticket().AutoAssign(c.Request.Context(), c.Param("id"), strings.TrimSpace(req.Team))
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", c.Param("id")).Warn("工单自动分派失败")
+		apierror.Respond(c, errorStatus(err), "工单自动分派失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "工单已重新分派",
+		"data":    ticket,
+	})
+}
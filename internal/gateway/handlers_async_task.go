@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 异步任务相关处理函数，导出、批量检查等耗时操作提交为任务后立即返回，调用方轮询任务获取进度和结果
+func (g *Gateway) submitAsyncTask(c *gin.Context) {
+	var req models.AsyncTaskRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	g.enqueueAsyncTask(c, &req)
+}
+
+// enqueueAsyncTask 提交异步任务并返回 202，Location 头指向任务状态地址
+func (g *Gateway) enqueueAsyncTask(c *gin.Context, req *models.AsyncTaskRequest) {
+	task, err := g.serviceManager.AsyncTask().Submit(c.Request.Context(), req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "提交异步任务失败", err.Error())
+		return
+	}
+
+	c.Header("Location", asyncTaskURL(task.ID))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "异步任务已提交",
+		"data":    task,
+	})
+}
+
+func (g *Gateway) listAsyncTasks(c *gin.Context) {
+	tasks, err := g.serviceManager.AsyncTask().List(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取异步任务列表失败", err.Error())
+		return
+	}
+	for _, task := range tasks {
+		setAsyncTaskResultURL(task)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  tasks,
+		"total": len(tasks),
+	})
+}
+
+func (g *Gateway) getAsyncTask(c *gin.Context) {
+	task, err := g.serviceManager.AsyncTask().Get(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取异步任务失败", err.Error())
+		return
+	}
+	setAsyncTaskResultURL(task)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": task,
+	})
+}
+
+func (g *Gateway) downloadAsyncTaskResult(c *gin.Context) {
+	file, err := g.serviceManager.AsyncTask().ResultFile(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "下载异步任务结果失败", err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// asyncTaskURL 任务状态地址
+func asyncTaskURL(id string) string {
+	return "/api/v1/tasks/" + id
+}
+
+// setAsyncTaskResultURL 为有结果文件的已完成任务填充下载地址
+func setAsyncTaskResultURL(task *models.AsyncTask) {
+	if task.HasResultFile() {
+		task.ResultURL = asyncTaskURL(task.ID) + "/result"
+	}
+}
+
+// wantsAsync 检查请求是否要求以异步任务执行
+func wantsAsync(c *gin.Context) bool {
+	return c.Query("async") == "true"
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 工单附件相关处理函数
+func (g *Gateway) downloadTicketAttachment(c *gin.Context) {
+	ticketID := c.Param("id")
+	attachmentID := c.Param("attachment_id")
+	if ticketID == "" || attachmentID == "" {
+		apierror.Respond(c, http.StatusBadRequest, "附件ID不能为空", "请提供有效的工单ID和附件ID")
+		return
+	}
+
+	attachment, err := g.serviceManager.AttachmentScan().GetDownloadableAttachment(c.Request.Context(), ticketID, attachmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrAttachmentNotFound):
+			apierror.Respond(c, http.StatusNotFound, "附件不存在", err.Error())
+		case errors.Is(err, models.ErrAttachmentNotClean):
+			g.logger.WithField("attachment_id", attachmentID).WithField("scan_status", attachment.ScanStatus).Warn("拦截未通过安全扫描的附件下载")
+			apierror.Respond(c, http.StatusForbidden, "附件暂不可下载", gin.H{
+				"reason":      err.Error(),
+				"scan_status": attachment.ScanStatus,
+			})
+		default:
+			g.logger.WithError(err).WithField("attachment_id", attachmentID).Error("获取附件失败")
+			apierror.Respond(c, http.StatusInternalServerError, "获取附件失败", err.Error())
+		}
+		return
+	}
+
+	fileName := attachment.OriginalFilename
+	if fileName == "" {
+		fileName = attachment.Filename
+	}
+
+	c.FileAttachment(attachment.FilePath, fileName)
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// exportBackup 导出平台配置备份，备份包使用请求中的口令签名，恢复时需要提供相同的口令
+func (g *Gateway) exportBackup(c *gin.Context) {
+	var req models.BackupExportRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	var buf bytes.Buffer
+	manifest, err := g.serviceManager.Backup().Export(c.Request.Context(), req.Passphrase, c.GetString("user_id"), &buf)
+	if err != nil {
+		g.logger.WithError(err).Error("导出配置备份失败")
+		apierror.Respond(c, errorStatus(err), "导出配置备份失败", err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("pulse-backup-%s.zip", manifest.CreatedAt.Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("X-Backup-Version", manifest.PulseVersion)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// restoreBackup 恢复平台配置备份，表单包含备份包文件 file 和导出时使用的口令 passphrase
+func (g *Gateway) restoreBackup(c *gin.Context) {
+	passphrase := c.PostForm("passphrase")
+	if passphrase == "" {
+		apierror.Respond(c, http.StatusBadRequest, "请提供备份口令", nil)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请上传备份文件", err.Error())
+		return
+	}
+	if fileHeader.Size > models.BackupMaxSize {
+		apierror.Respond(c, http.StatusRequestEntityTooLarge, "备份文件过大", fmt.Sprintf("备份文件不能超过 %d MB", models.BackupMaxSize>>20))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取备份文件失败", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.BackupMaxSize))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取备份文件失败", err.Error())
+		return
+	}
+
+	result, err := g.serviceManager.Backup().Restore(c.Request.Context(), data, passphrase, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).Error("恢复配置备份失败")
+		apierror.Respond(c, errorStatus(err), "恢复配置备份失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "配置备份已恢复",
+		"data":    result,
+	})
+}
@@ -0,0 +1,114 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 变更事件相关处理函数
+func (g *Gateway) listChangeEvents(c *gin.Context) {
+	filter := &models.ChangeEventFilter{Page: 1, PageSize: 20}
+	if service := c.Query("service"); service != "" {
+		filter.Service = &service
+	}
+	if typeStr := c.Query("type"); typeStr != "" {
+		changeType := models.ChangeEventType(typeStr)
+		filter.Type = &changeType
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.To = &to
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	events, total, err := g.serviceManager.ChangeEvent().List(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取变更事件列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"total": total,
+	})
+}
+
+func (g *Gateway) createChangeEvent(c *gin.Context) {
+	var req models.ChangeEventRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	event, err := g.serviceManager.ChangeEvent().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "登记变更事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "变更事件登记成功",
+		"data":    event,
+	})
+}
+
+func (g *Gateway) getChangeEvent(c *gin.Context) {
+	event, err := g.serviceManager.ChangeEvent().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取变更事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": event,
+	})
+}
+
+func (g *Gateway) deleteChangeEvent(c *gin.Context) {
+	if err := g.serviceManager.ChangeEvent().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除变更事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "变更事件删除成功",
+	})
+}
+
+// listAlertChanges 告警触发前同一服务上的变更，作为可能原因展示在告警详情中
+func (g *Gateway) listAlertChanges(c *gin.Context) {
+	events, err := g.serviceManager.ChangeEvent().ForAlert(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取告警关联变更失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"total": len(events),
+	})
+}
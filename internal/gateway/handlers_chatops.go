@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/signature"
+)
+
+const (
+	// maxChatOpsBodySize 聊天平台回调请求体大小上限
+	maxChatOpsBodySize = 64 << 10
+	// chatCommandInternalErrorReply 聊天命令因内部错误失败时回复的通用说明
+	chatCommandInternalErrorReply = "操作失败: 服务内部错误，请稍后重试或联系管理员"
+)
+
+// dingTalkMessage 钉钉机器人回调消息，只解析命令执行需要的字段
+type dingTalkMessage struct {
+	Text struct {
+		Content string `json:"content"`
+	} `json:"text"`
+	SenderStaffID string `json:"senderStaffId"`
+	SenderID      string `json:"senderId"`
+}
+
+// 聊天平台命令相关处理函数
+func (g *Gateway) handleSlackCommand(c *gin.Context) {
+	body, ok := g.readChatOpsRequest(c, models.ChatPlatformSlack,
+		c.GetHeader(signature.HeaderSlackSignature), c.GetHeader(signature.HeaderSlackTimestamp))
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "解析Slack命令失败", err.Error())
+		return
+	}
+
+	text := slackCommandText(form.Get("command"), form.Get("text"))
+	reply, err := g.executeChatCommand(c, models.ChatPlatformSlack, form.Get("user_id"), text)
+
+	// 执行失败的回复只对发起人可见
+	responseType := "in_channel"
+	if err != nil {
+		responseType = "ephemeral"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"response_type": responseType,
+		"text":          reply,
+	})
+}
+
+func (g *Gateway) handleDingTalkCommand(c *gin.Context) {
+	body, ok := g.readChatOpsRequest(c, models.ChatPlatformDingTalk,
+		c.GetHeader(signature.HeaderDingTalkSign), c.GetHeader(signature.HeaderDingTalkTimestamp))
+	if !ok {
+		return
+	}
+
+	var message dingTalkMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "解析钉钉消息失败", err.Error())
+		return
+	}
+
+	chatUserID := message.SenderStaffID
+	if chatUserID == "" {
+		chatUserID = message.SenderID
+	}
+	reply, _ := g.executeChatCommand(c, models.ChatPlatformDingTalk, chatUserID, message.Text.Content)
+
+	c.JSON(http.StatusOK, gin.H{
+		"msgtype": "text",
+		"text":    gin.H{"content": reply},
+	})
+}
+
+// readChatOpsRequest 读取回调请求体并校验签名，失败时已写入响应
+func (g *Gateway) readChatOpsRequest(c *gin.Context, platform models.ChatPlatform, signatureHeader, timestampHeader string) ([]byte, bool) {
+	if !g.serviceManager.ChatOps().Enabled() {
+		apierror.Respond(c, http.StatusNotFound, "ChatOps 未启用", nil)
+		return nil, false
+	}
+
+	// 签名基于原始请求体计算，先完整读取再解析
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxChatOpsBodySize+1))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取请求体失败", err.Error())
+		return nil, false
+	}
+	if len(body) > maxChatOpsBodySize {
+		apierror.RespondCode(c, apierror.CodePayloadTooLarge, "", gin.H{"max_bytes": maxChatOpsBodySize})
+		return nil, false
+	}
+
+	if err := g.serviceManager.ChatOps().Verify(platform, signatureHeader, timestampHeader, body); err != nil {
+		apierror.Respond(c, errorStatus(err), "聊天平台请求校验失败", err.Error())
+		return nil, false
+	}
+
+	return body, true
+}
+
+// executeChatCommand 执行聊天命令，失败时返回回复给聊天用户的错误说明。
+// 内部错误可能包含数据库等实现细节，只记录日志，回复通用说明
+func (g *Gateway) executeChatCommand(c *gin.Context, platform models.ChatPlatform, chatUserID, text string) (string, error) {
+	reply, err := g.serviceManager.ChatOps().Execute(c.Request.Context(), platform, chatUserID, text)
+	if err != nil {
+		if errorStatus(err) == http.StatusInternalServerError {
+			g.logger.WithError(err).WithField("platform", platform).WithField("chat_user_id", chatUserID).Error("执行聊天命令失败")
+			return chatCommandInternalErrorReply, err
+		}
+		return "操作失败: " + err.Error(), err
+	}
+	return reply, nil
+}
+
+// slackCommandText 合并斜杠命令和参数，/ack 等命令直接注册为斜杠命令时命令名即动作，
+// 注册为统一入口（如 /pulse ack <id>）时动作在参数中
+func slackCommandText(command, text string) string {
+	action := strings.TrimPrefix(command, "/")
+	if models.ChatCommandAction(action).IsValid() {
+		return action + " " + text
+	}
+	return text
+}
+
+func (g *Gateway) listChatUserMappings(c *gin.Context) {
+	platform := models.ChatPlatform(c.Query("platform"))
+
+	mappings, err := g.serviceManager.ChatOps().ListUserMappings(c.Request.Context(), platform)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取聊天用户绑定失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  mappings,
+		"total": len(mappings),
+	})
+}
+
+func (g *Gateway) createChatUserMapping(c *gin.Context) {
+	var req models.ChatUserMappingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	mapping, err := g.serviceManager.ChatOps().BindUser(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "绑定聊天用户失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "聊天用户绑定成功",
+		"data":    mapping,
+	})
+}
+
+func (g *Gateway) deleteChatUserMapping(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := g.serviceManager.ChatOps().UnbindUser(c.Request.Context(), id); err != nil {
+		apierror.Respond(c, errorStatus(err), "解除聊天用户绑定失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "聊天用户绑定已解除",
+	})
+}
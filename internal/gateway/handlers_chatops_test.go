@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"pulse/internal/models"
+	"pulse/internal/service"
+)
+
+// fakeChatOpsService 执行命令时返回固定的错误
+type fakeChatOpsService struct {
+	service.ChatOpsService
+	err error
+}
+
+func (s *fakeChatOpsService) Execute(ctx context.Context, platform models.ChatPlatform, chatUserID, text string) (string, error) {
+	return "", s.err
+}
+
+type chatOpsServiceManager struct {
+	*MockServiceManager
+	chatOps service.ChatOpsService
+}
+
+func (m *chatOpsServiceManager) ChatOps() service.ChatOpsService { return m.chatOps }
+
+func TestExecuteChatCommand_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	execute := func(err error) string {
+		g := &Gateway{
+			logger:         logrus.New(),
+			serviceManager: &chatOpsServiceManager{MockServiceManager: &MockServiceManager{}, chatOps: &fakeChatOpsService{err: err}},
+		}
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/api/v1/chatops/slack", nil)
+		reply, execErr := g.executeChatCommand(c, models.ChatPlatformSlack, "U1", "ack a1")
+		assert.ErrorIs(t, execErr, err)
+		return reply
+	}
+
+	// 用户可处理的错误原样回复
+	assert.Equal(t, "操作失败: "+models.ErrAlertNotFound.Error(), execute(models.ErrAlertNotFound))
+
+	// 内部错误只回复通用说明，不泄露实现细节
+	reply := execute(fmt.Errorf("更新告警状态失败: %w", errors.New("pq: connection refused to 10.0.0.5:5432")))
+	assert.Equal(t, chatCommandInternalErrorReply, reply)
+	assert.NotContains(t, reply, "10.0.0.5")
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// configBundleMaxSize 声明式配置请求体的最大字节数
+const configBundleMaxSize = 10 << 20
+
+// applyConfig 应用声明式配置，请求体为 JSON 或 YAML（Content-Type 包含 yaml）格式的配置文件。
+// dry_run=true 时只返回变更预览，prune=true 时删除配置文件未包含的资源
+func (g *Gateway) applyConfig(c *gin.Context) {
+	dryRun, err := parseBoolQuery(c, "dry_run")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求参数无效", err.Error())
+		return
+	}
+	prune, err := parseBoolQuery(c, "prune")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求参数无效", err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, configBundleMaxSize+1))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取声明式配置失败", err.Error())
+		return
+	}
+	if len(data) > configBundleMaxSize {
+		apierror.Respond(c, http.StatusRequestEntityTooLarge, "声明式配置过大", fmt.Sprintf("声明式配置不能超过 %d MB", configBundleMaxSize>>20))
+		return
+	}
+	bundle, err := decodeConfigBundle(data, strings.Contains(c.ContentType(), "yaml"))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "声明式配置解析失败", err.Error())
+		return
+	}
+
+	opts := models.ConfigApplyOptions{DryRun: dryRun, Prune: prune}
+	result, err := g.serviceManager.ConfigApply().Apply(c.Request.Context(), bundle, opts, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, models.ErrConfigBundleInvalid) && result != nil {
+			apierror.Respond(c, http.StatusBadRequest, "声明式配置校验失败", result.Issues)
+			return
+		}
+		g.logger.WithError(err).WithField("dry_run", dryRun).Error("应用声明式配置失败")
+		apierror.Respond(c, errorStatus(err), "应用声明式配置失败", err.Error())
+		return
+	}
+
+	message := "声明式配置已应用"
+	if dryRun {
+		message = "声明式配置预览完成，未做任何变更"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    result,
+	})
+}
+
+// decodeConfigBundle 解析声明式配置，不允许未知字段，避免字段名拼写错误被静默忽略。
+// YAML 先转换为 JSON 再解析，两种格式的字段名一致
+func decodeConfigBundle(data []byte, isYAML bool) (*models.ConfigBundle, error) {
+	if isYAML {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	}
+
+	var bundle models.ConfigBundle
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// parseBoolQuery 解析布尔查询参数，未提供时为 false
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s 必须为布尔值", name)
+	}
+	return parsed, nil
+}
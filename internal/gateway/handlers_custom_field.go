@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 自定义字段相关处理函数
+func (g *Gateway) listCustomFields(c *gin.Context) {
+	// 解析查询参数
+	filter := &models.CustomFieldDefinitionFilter{
+		Page:     1,
+		PageSize: 100,
+	}
+
+	// 解析分页参数
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			filter.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 200 {
+			filter.PageSize = pageSize
+		}
+	}
+
+	// 解析过滤参数
+	if resourceStr := c.Query("resource"); resourceStr != "" {
+		resource := models.CustomFieldResource(resourceStr)
+		if !resource.IsValid() {
+			apierror.Respond(c, http.StatusBadRequest, "无效的资源类型", "资源类型必须是 ticket")
+			return
+		}
+		filter.Resource = &resource
+	}
+
+	if enabledStr := c.Query("enabled"); enabledStr != "" {
+		if enabled, err := strconv.ParseBool(enabledStr); err == nil {
+			filter.Enabled = &enabled
+		}
+	}
+
+	definitions, total, err := g.serviceManager.CustomField().List(c.Request.Context(), filter)
+	if err != nil {
+		g.logger.WithError(err).Error("获取自定义字段列表失败")
+		apierror.Respond(c, errorStatus(err), "获取自定义字段列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"custom_fields": definitions,
+		"total":         total,
+		"page":          filter.Page,
+		"page_size":     filter.PageSize,
+	})
+}
+
+func (g *Gateway) createCustomField(c *gin.Context) {
+	// 解析请求体
+	var req models.CustomFieldDefinitionCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 验证请求数据
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	def := req.ToDefinition()
+	if userID := c.GetString("user_id"); userID != "" {
+		def.CreatedBy = &userID
+	}
+
+	if err := g.serviceManager.CustomField().Create(c.Request.Context(), def); err != nil {
+		g.logger.WithError(err).WithField("key", def.Key).Error("创建自定义字段失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "创建自定义字段失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("custom_field_id", def.ID).Info("自定义字段创建成功")
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "自定义字段创建成功",
+		"data":    def,
+	})
+}
+
+func (g *Gateway) getCustomField(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		apierror.Respond(c, http.StatusBadRequest, "自定义字段ID不能为空", "请提供有效的自定义字段ID")
+		return
+	}
+
+	def, err := g.serviceManager.CustomField().GetByID(c.Request.Context(), id)
+	if err != nil {
+		g.logger.WithError(err).WithField("custom_field_id", id).Error("获取自定义字段失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "获取自定义字段失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, def)
+}
+
+func (g *Gateway) updateCustomField(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		apierror.Respond(c, http.StatusBadRequest, "自定义字段ID不能为空", "请提供有效的自定义字段ID")
+		return
+	}
+
+	// 解析请求体
+	var req models.CustomFieldDefinitionUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 获取现有定义
+	def, err := g.serviceManager.CustomField().GetByID(c.Request.Context(), id)
+	if err != nil {
+		g.logger.WithError(err).WithField("custom_field_id", id).Error("获取自定义字段失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "获取自定义字段失败", err.Error())
+		return
+	}
+
+	req.ApplyTo(def)
+
+	if err := g.serviceManager.CustomField().Update(c.Request.Context(), def); err != nil {
+		g.logger.WithError(err).WithField("custom_field_id", id).Error("更新自定义字段失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "更新自定义字段失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("custom_field_id", id).Info("自定义字段更新成功")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "自定义字段更新成功",
+		"data":    def,
+	})
+}
+
+func (g *Gateway) deleteCustomField(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		apierror.Respond(c, http.StatusBadRequest, "自定义字段ID不能为空", "请提供有效的自定义字段ID")
+		return
+	}
+
+	if err := g.serviceManager.CustomField().Delete(c.Request.Context(), id); err != nil {
+		g.logger.WithError(err).WithField("custom_field_id", id).Error("删除自定义字段失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "删除自定义字段失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("custom_field_id", id).Info("自定义字段删除成功")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "自定义字段删除成功",
+		"id":      id,
+	})
+}
+
+func (g *Gateway) getCustomFieldSchema(c *gin.Context) {
+	resource := models.CustomFieldResource(c.Param("resource"))
+	if !resource.IsValid() {
+		apierror.Respond(c, http.StatusBadRequest, "无效的资源类型", "资源类型必须是 ticket")
+		return
+	}
+
+	definitions, err := g.serviceManager.CustomField().GetFormSchema(c.Request.Context(), resource)
+	if err != nil {
+		g.logger.WithError(err).WithField("resource", resource).Error("获取自定义字段表单失败")
+		apierror.Respond(c, customFieldErrorStatus(err), "获取自定义字段表单失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resource": resource,
+		"fields":   definitions,
+	})
+}
+
+// customFieldErrorStatus 将自定义字段错误映射为HTTP状态码
+func customFieldErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrCustomFieldNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrCustomFieldExists):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err)
+	}
+}
@@ -0,0 +1,23 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// getDashboard 获取首页仪表盘汇总数据，替代前端分别请求各项统计
+func (g *Gateway) getDashboard(c *gin.Context) {
+	summary, err := g.serviceManager.Dashboard().GetSummary(c.Request.Context())
+	if err != nil {
+		g.logger.WithError(err).Error("获取仪表盘数据失败")
+		apierror.Respond(c, errorStatus(err), "获取仪表盘数据失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
@@ -0,0 +1,26 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// rotateEncryptionKeys 将数据源配置、集成密钥、Webhook凭据和用户敏感信息重新加密为当前主密钥版本
+// 新增主密钥并切换 ENCRYPTION_ACTIVE_KEY 后调用，全部轮换成功后才能从配置中移除旧密钥
+// 切换 ENCRYPT_USER_PII 或 ENCRYPTION_INDEX_KEY 后也需调用，以加密或解密已有用户数据并重建盲索引
+func (g *Gateway) rotateEncryptionKeys(c *gin.Context) {
+	result, err := g.serviceManager.KeyRotation().RotateEncryptionKeys(c.Request.Context())
+	if err != nil {
+		g.logger.WithError(err).Error("轮换加密密钥失败")
+		apierror.Respond(c, errorStatus(err), "轮换加密密钥失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "加密密钥轮换完成",
+		"data":    result,
+	})
+}
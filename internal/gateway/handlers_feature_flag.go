@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// tenantHeader 未从认证信息中解析出租户时，从该请求头读取租户
+const tenantHeader = "X-Tenant-ID"
+
+// featureFlagContext 构建当前请求的功能开关评估上下文
+func featureFlagContext(c *gin.Context) models.FeatureFlagContext {
+	tenant := c.GetString("tenant_id")
+	if tenant == "" {
+		tenant = c.GetHeader(tenantHeader)
+	}
+	return models.FeatureFlagContext{
+		UserID: c.GetString("user_id"),
+		Tenant: tenant,
+	}
+}
+
+// featureEnabled 判断功能是否对当前请求的用户和租户启用，供功能开关中间件使用
+func (g *Gateway) featureEnabled(c *gin.Context, key string) bool {
+	return g.serviceManager.FeatureFlag().IsEnabled(c.Request.Context(), key, featureFlagContext(c))
+}
+
+// 功能开关相关处理函数
+func (g *Gateway) evaluateFeatureFlags(c *gin.Context) {
+	flags, err := g.serviceManager.FeatureFlag().Evaluate(c.Request.Context(), featureFlagContext(c))
+	if err != nil {
+		g.logger.WithError(err).Error("评估功能开关失败")
+		apierror.Respond(c, errorStatus(err), "评估功能开关失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": flags,
+	})
+}
+
+func (g *Gateway) listFeatureFlags(c *gin.Context) {
+	flags, err := g.serviceManager.FeatureFlag().List(c.Request.Context())
+	if err != nil {
+		g.logger.WithError(err).Error("获取功能开关列表失败")
+		apierror.Respond(c, errorStatus(err), "获取功能开关列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flags": flags,
+		"total": len(flags),
+	})
+}
+
+func (g *Gateway) createFeatureFlag(c *gin.Context) {
+	var req models.FeatureFlagRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	flag := &models.FeatureFlag{}
+	req.ApplyTo(flag)
+	if userID := c.GetString("user_id"); userID != "" {
+		flag.CreatedBy = &userID
+		flag.UpdatedBy = &userID
+	}
+
+	if err := g.serviceManager.FeatureFlag().Create(c.Request.Context(), flag); err != nil {
+		g.logger.WithError(err).WithField("flag", flag.Key).Error("创建功能开关失败")
+		apierror.Respond(c, errorStatus(err), "创建功能开关失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "功能开关创建成功",
+		"data":    flag,
+	})
+}
+
+func (g *Gateway) getFeatureFlag(c *gin.Context) {
+	key := c.Param("key")
+
+	flag, err := g.serviceManager.FeatureFlag().Get(c.Request.Context(), key)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取功能开关失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": flag,
+	})
+}
+
+// updateFeatureFlag 更新功能开关，开关键以路径参数为准
+func (g *Gateway) updateFeatureFlag(c *gin.Context) {
+	key := c.Param("key")
+
+	var req models.FeatureFlagRequest
+	req.Key = key
+	if !bindJSON(c, &req) {
+		return
+	}
+	req.Key = key
+
+	flag, err := g.serviceManager.FeatureFlag().Get(c.Request.Context(), key)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取功能开关失败", err.Error())
+		return
+	}
+
+	req.ApplyTo(flag)
+	if userID := c.GetString("user_id"); userID != "" {
+		flag.UpdatedBy = &userID
+	}
+
+	if err := g.serviceManager.FeatureFlag().Update(c.Request.Context(), flag); err != nil {
+		g.logger.WithError(err).WithField("flag", key).Error("更新功能开关失败")
+		apierror.Respond(c, errorStatus(err), "更新功能开关失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "功能开关更新成功",
+		"data":    flag,
+	})
+}
+
+func (g *Gateway) deleteFeatureFlag(c *gin.Context) {
+	key := c.Param("key")
+
+	if err := g.serviceManager.FeatureFlag().Delete(c.Request.Context(), key); err != nil {
+		g.logger.WithError(err).WithField("flag", key).Error("删除功能开关失败")
+		apierror.Respond(c, errorStatus(err), "删除功能开关失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "功能开关删除成功",
+	})
+}
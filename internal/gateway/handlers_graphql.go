@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/graphql"
+)
+
+// graphqlQuery 执行 GraphQL 查询，响应使用 GraphQL 的 {data, errors} 格式，
+// 查询无法解析或校验不通过时返回 400，字段级错误仍返回 200
+func (g *Gateway) graphqlQuery(c *gin.Context) {
+	var req graphql.Request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	resp := g.serviceManager.GraphQL().Execute(c.Request.Context(), &req, c.GetString("user_id"))
+	if resp.Data == nil {
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// graphqlSchema 以 Schema 定义语言返回可查询的类型，供前端生成类型定义
+func (g *Gateway) graphqlSchema(c *gin.Context) {
+	c.String(http.StatusOK, g.serviceManager.GraphQL().SDL())
+}
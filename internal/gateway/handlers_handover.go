@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 交班报告相关处理函数，交接排班配置团队每天的交接时刻，到达交接时刻时生成上一班次的交班报告
+func (g *Gateway) listHandoverSchedules(c *gin.Context) {
+	schedules, err := g.serviceManager.Handover().ListSchedules(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取交接排班列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  schedules,
+		"total": len(schedules),
+	})
+}
+
+func (g *Gateway) getHandoverSchedule(c *gin.Context) {
+	schedule, err := g.serviceManager.Handover().GetSchedule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取交接排班失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+func (g *Gateway) createHandoverSchedule(c *gin.Context) {
+	var req models.HandoverScheduleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	schedule, err := g.serviceManager.Handover().CreateSchedule(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建交接排班失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "交接排班创建成功",
+		"data":    schedule,
+	})
+}
+
+func (g *Gateway) updateHandoverSchedule(c *gin.Context) {
+	var req models.HandoverScheduleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	schedule, err := g.serviceManager.Handover().UpdateSchedule(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新交接排班失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "交接排班更新成功",
+		"data":    schedule,
+	})
+}
+
+func (g *Gateway) deleteHandoverSchedule(c *gin.Context) {
+	if err := g.serviceManager.Handover().DeleteSchedule(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除交接排班失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "交接排班删除成功",
+	})
+}
+
+// listHandoverReports 获取交班报告列表，支持 team 和 limit 查询参数
+func (g *Gateway) listHandoverReports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	reports, err := g.serviceManager.Handover().ListReports(c.Request.Context(), c.Query("team"), limit)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取交班报告列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reports,
+		"total": len(reports),
+	})
+}
+
+func (g *Gateway) getHandoverReport(c *gin.Context) {
+	report, err := g.serviceManager.Handover().GetReport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取交班报告失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// generateHandoverReport 手动生成截至当前的交班报告并保存为知识库草稿
+func (g *Gateway) generateHandoverReport(c *gin.Context) {
+	var req models.HandoverGenerateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := g.serviceManager.Handover().Generate(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("team", req.Team).Warn("生成交班报告失败")
+		apierror.Respond(c, errorStatus(err), "生成交班报告失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "交班报告已生成",
+		"data":    report,
+	})
+}
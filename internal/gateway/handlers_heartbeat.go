@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 心跳检查相关处理函数
+func (g *Gateway) listHeartbeats(c *gin.Context) {
+	heartbeats, err := g.serviceManager.Heartbeat().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取心跳检查列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  heartbeats,
+		"total": len(heartbeats),
+	})
+}
+
+func (g *Gateway) getHeartbeatStatus(c *gin.Context) {
+	status, err := g.serviceManager.Heartbeat().Status(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取心跳状态失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+func (g *Gateway) getHeartbeat(c *gin.Context) {
+	heartbeat, err := g.serviceManager.Heartbeat().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取心跳检查失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": heartbeat,
+	})
+}
+
+func (g *Gateway) createHeartbeat(c *gin.Context) {
+	var req models.HeartbeatRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	heartbeat, err := g.serviceManager.Heartbeat().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建心跳检查失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "心跳检查创建成功",
+		"data":    heartbeat,
+	})
+}
+
+func (g *Gateway) updateHeartbeat(c *gin.Context) {
+	var req models.HeartbeatRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	heartbeat, err := g.serviceManager.Heartbeat().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新心跳检查失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "心跳检查更新成功",
+		"data":    heartbeat,
+	})
+}
+
+func (g *Gateway) deleteHeartbeat(c *gin.Context) {
+	if err := g.serviceManager.Heartbeat().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除心跳检查失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "心跳检查删除成功",
+	})
+}
+
+// pingHeartbeat 接收外部任务的心跳上报
+func (g *Gateway) pingHeartbeat(c *gin.Context) {
+	if err := g.serviceManager.Heartbeat().Ping(c.Request.Context(), c.Param("token")); err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).Error("记录心跳上报失败")
+		}
+		apierror.Respond(c, status, "心跳上报失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/middleware"
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// startImpersonation 管理员模拟用户登录，返回以用户身份和角色访问接口的短期令牌。
+// 模拟登录会话中不能再次模拟其他用户
+func (g *Gateway) startImpersonation(c *gin.Context) {
+	if _, ok := middleware.ImpersonationClaims(c); ok {
+		apierror.RespondCode(c, apierror.CodeForbidden, "模拟登录会话中不能模拟其他用户", nil)
+		return
+	}
+
+	var req models.ImpersonationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	session, err := g.serviceManager.Impersonation().Start(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "模拟用户登录失败", err.Error())
+		return
+	}
+
+	impersonator := middleware.Impersonator{ID: session.ImpersonatorID, Name: session.ImpersonatorName, Scope: string(session.Scope)}
+	user := session.User
+	token, err := g.authService.GenerateImpersonationToken(impersonator, user.ID, user.Username, user.Email,
+		[]string{string(user.Role)}, session.ExpiresAt)
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "模拟用户登录失败", err.Error())
+		return
+	}
+	session.Token = token
+	session.TokenType = "Bearer"
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "模拟登录令牌已签发",
+		"data":    session,
+	})
+}
+
+// auditImpersonatedRequest 记录模拟登录会话中的请求，请求处理完成后写入，不受请求取消的影响
+func (g *Gateway) auditImpersonatedRequest(c *gin.Context, claims *middleware.JWTClaims) {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	err := g.serviceManager.Impersonation().Record(context.WithoutCancel(c.Request.Context()), &models.ImpersonatedRequest{
+		ImpersonatorID: claims.ImpersonatorID,
+		UserID:         claims.UserID,
+		Method:         c.Request.Method,
+		Path:           path,
+		Status:         c.Writer.Status(),
+		IPAddress:      c.ClientIP(),
+		RequestID:      c.GetString("request_id"),
+	})
+	if err != nil {
+		g.logger.WithError(err).WithField("impersonator_id", claims.ImpersonatorID).Error("记录模拟登录请求审计日志失败")
+	}
+}
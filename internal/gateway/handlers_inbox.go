@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 站内通知收件箱相关处理函数，均只操作当前用户的通知
+func (g *Gateway) listInbox(c *gin.Context) {
+	unread, err := parseBoolQuery(c, "unread")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求参数无效", err.Error())
+		return
+	}
+	filter := &models.InboxFilter{UnreadOnly: unread, Limit: 50}
+	if before := c.Query("before"); before != "" {
+		seq, err := strconv.ParseInt(before, 10, 64)
+		if err != nil || seq < 0 {
+			apierror.Respond(c, http.StatusBadRequest, "请求参数无效", "before 必须为非负整数")
+			return
+		}
+		filter.Before = seq
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			filter.Limit = l
+		}
+	}
+
+	list, err := g.serviceManager.Inbox().List(c.Request.Context(), c.GetString("user_id"), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取站内通知失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   list.Items,
+		"total":  len(list.Items),
+		"unread": list.Unread,
+	})
+}
+
+func (g *Gateway) getInboxUnreadCount(c *gin.Context) {
+	unread, err := g.serviceManager.Inbox().UnreadCount(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取未读通知数失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread": unread,
+	})
+}
+
+func (g *Gateway) markInboxRead(c *gin.Context) {
+	var req models.InboxReadRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	unread, err := g.serviceManager.Inbox().MarkRead(c.Request.Context(), c.GetString("user_id"), req.IDs)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "标记通知已读失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知已标记为已读",
+		"unread":  unread,
+	})
+}
+
+func (g *Gateway) markAllInboxRead(c *gin.Context) {
+	unread, err := g.serviceManager.Inbox().MarkAllRead(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "标记通知已读失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "全部通知已标记为已读",
+		"unread":  unread,
+	})
+}
+
+// streamInbox 以 Server-Sent Events 实时推送当前用户的新通知（notification 事件，事件 ID 为通知的 Seq）
+// 和未读数变化（unread 事件）。重连时按 Last-Event-ID 请求头或 after 参数补发断开期间的通知，
+// 首次连接只推送之后的新通知
+func (g *Gateway) streamInbox(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	inbox := g.serviceManager.Inbox()
+
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("after")
+	}
+	// 先订阅再确定起始位置，期间投递的通知不会遗漏
+	changes, cancel := inbox.Subscribe(userID)
+	defer cancel()
+
+	var after int64
+	var err error
+	if cursor != "" {
+		after, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil || after < 0 {
+			apierror.Respond(c, http.StatusBadRequest, "请求参数无效", "Last-Event-ID 或 after 必须为非负整数")
+			return
+		}
+	} else if after, err = inbox.LatestSeq(ctx, userID); err != nil {
+		apierror.Respond(c, errorStatus(err), "获取站内通知失败", err.Error())
+		return
+	}
+
+	// 连接长期保持，取消服务端的写超时，客户端断开时请求上下文结束
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ticker := time.NewTicker(inbox.StreamPollInterval())
+	defer ticker.Stop()
+
+	unread := -1
+	heartbeat := false
+	for {
+		sent := false
+		for {
+			items, err := inbox.Since(ctx, userID, after)
+			if err != nil {
+				g.streamInboxError(c, err)
+				return
+			}
+			if len(items) == 0 {
+				break
+			}
+			for _, item := range items {
+				writeServerSentEvent(c.Writer, strconv.FormatInt(item.Seq, 10), "notification", item)
+				after = item.Seq
+			}
+			sent = true
+		}
+
+		count, err := inbox.UnreadCount(ctx, userID)
+		if err != nil {
+			g.streamInboxError(c, err)
+			return
+		}
+		if count != unread {
+			writeServerSentEvent(c.Writer, "", "unread", gin.H{"unread": count})
+			unread = count
+			sent = true
+		}
+		if !sent && heartbeat {
+			// 注释行作为心跳，避免代理关闭空闲连接
+			_, _ = io.WriteString(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			heartbeat = false
+		case <-ticker.C:
+			heartbeat = true
+		}
+	}
+}
+
+// streamInboxError 实时推送中读取通知失败时发送 error 事件并结束连接，客户端按 Last-Event-ID 重连
+func (g *Gateway) streamInboxError(c *gin.Context, err error) {
+	if c.Request.Context().Err() != nil {
+		return
+	}
+	g.logger.WithError(err).WithField("user_id", c.GetString("user_id")).Error("站内通知实时推送失败")
+	message, _ := apierror.Localize("站内通知实时推送失败", apierror.LocaleFromContext(c))
+	writeServerSentEvent(c.Writer, "", "error", gin.H{"error": message})
+	c.Writer.Flush()
+}
+
+// writeServerSentEvent 写出一个 Server-Sent Events 事件，data 序列化为单行 JSON
+func writeServerSentEvent(w io.Writer, id, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
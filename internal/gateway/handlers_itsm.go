@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// maxITSMWebhookBodySize 外部 ITSM 系统 Webhook 请求体大小上限，Jira 负载包含完整的问题字段
+const maxITSMWebhookBodySize = 1 << 20
+
+// headerITSMSignature 外部 ITSM 系统 Webhook 签名头，值为 sha256=<请求体的 HMAC-SHA256 十六进制值>
+const headerITSMSignature = "X-Hub-Signature"
+
+// 外部 ITSM 同步相关处理函数
+func (g *Gateway) listITSMConnectors(c *gin.Context) {
+	connectors, err := g.serviceManager.ITSM().ListConnectors(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取ITSM连接器列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  connectors,
+		"total": len(connectors),
+	})
+}
+
+func (g *Gateway) getITSMConnector(c *gin.Context) {
+	connector, err := g.serviceManager.ITSM().GetConnector(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取ITSM连接器失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": connector,
+	})
+}
+
+func (g *Gateway) createITSMConnector(c *gin.Context) {
+	var req models.ITSMConnectorRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := g.serviceManager.ITSM().CreateConnector(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建ITSM连接器失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "ITSM连接器创建成功，请妥善保存Webhook密钥，之后将无法再次查看",
+		"data":    result,
+	})
+}
+
+func (g *Gateway) updateITSMConnector(c *gin.Context) {
+	var req models.ITSMConnectorRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	connector, err := g.serviceManager.ITSM().UpdateConnector(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新ITSM连接器失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ITSM连接器更新成功",
+		"data":    connector,
+	})
+}
+
+func (g *Gateway) deleteITSMConnector(c *gin.Context) {
+	if err := g.serviceManager.ITSM().DeleteConnector(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除ITSM连接器失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ITSM连接器删除成功",
+	})
+}
+
+func (g *Gateway) listTicketExternalLinks(c *gin.Context) {
+	links, err := g.serviceManager.ITSM().ListTicketLinks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单外部关联失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  links,
+		"total": len(links),
+	})
+}
+
+// handleITSMWebhook 接收外部 ITSM 系统推送的工单变更，请求须携带签名头或 token 查询参数
+func (g *Gateway) handleITSMWebhook(c *gin.Context) {
+	id := c.Param("id")
+
+	// 签名基于原始请求体计算，先完整读取再解析
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxITSMWebhookBodySize+1))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取请求体失败", err.Error())
+		return
+	}
+	if len(body) > maxITSMWebhookBodySize {
+		apierror.RespondCode(c, apierror.CodePayloadTooLarge, "", gin.H{"max_bytes": maxITSMWebhookBodySize})
+		return
+	}
+
+	result, err := g.serviceManager.ITSM().HandleWebhook(c.Request.Context(), id, c.Query("token"),
+		c.GetHeader(headerITSMSignature), body)
+	if err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).WithField("connector_id", id).Error("处理ITSM Webhook失败")
+		}
+		apierror.Respond(c, status, "处理ITSM Webhook失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
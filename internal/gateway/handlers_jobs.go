@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/worker"
+)
+
+// 后台任务相关处理函数，任务为按间隔定时执行的 Worker，如 slo、notification_digest、data_retention
+func (g *Gateway) listJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": g.jobs.List()})
+}
+
+func (g *Gateway) getJob(c *gin.Context) {
+	job, ok := g.lookupJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job.Status()})
+}
+
+func (g *Gateway) triggerJob(c *gin.Context) {
+	job, ok := g.lookupJob(c)
+	if !ok {
+		return
+	}
+	if err := job.Trigger(); err != nil {
+		respondJobError(c, err)
+		return
+	}
+	g.logger.WithField("job", job.Name()).WithField("user_id", c.GetString("user_id")).Info("后台任务已手动触发")
+	c.JSON(http.StatusAccepted, gin.H{"data": job.Status()})
+}
+
+func (g *Gateway) pauseJob(c *gin.Context) {
+	job, ok := g.lookupJob(c)
+	if !ok {
+		return
+	}
+	job.Pause()
+	g.logger.WithField("job", job.Name()).WithField("user_id", c.GetString("user_id")).Info("后台任务已暂停")
+	c.JSON(http.StatusOK, gin.H{"data": job.Status()})
+}
+
+func (g *Gateway) resumeJob(c *gin.Context) {
+	job, ok := g.lookupJob(c)
+	if !ok {
+		return
+	}
+	job.Resume()
+	g.logger.WithField("job", job.Name()).WithField("user_id", c.GetString("user_id")).Info("后台任务已恢复")
+	c.JSON(http.StatusOK, gin.H{"data": job.Status()})
+}
+
+func (g *Gateway) cancelJob(c *gin.Context) {
+	job, ok := g.lookupJob(c)
+	if !ok {
+		return
+	}
+	if err := job.Cancel(); err != nil {
+		respondJobError(c, err)
+		return
+	}
+	g.logger.WithField("job", job.Name()).WithField("user_id", c.GetString("user_id")).Info("后台任务已取消")
+	c.JSON(http.StatusOK, gin.H{"data": job.Status()})
+}
+
+// lookupJob 按路径参数查找任务，不存在时返回 404
+func (g *Gateway) lookupJob(c *gin.Context) (*worker.Job, bool) {
+	job, err := g.jobs.Get(c.Param("name"))
+	if err != nil {
+		respondJobError(c, err)
+		return nil, false
+	}
+	return job, true
+}
+
+// respondJobError 将任务控制错误转换为 API 错误响应
+func respondJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, worker.ErrJobNotFound):
+		apierror.Respond(c, http.StatusNotFound, "后台任务不存在", c.Param("name"))
+	case errors.Is(err, worker.ErrJobNotScheduled):
+		apierror.Respond(c, http.StatusConflict, "后台任务未启用", c.Param("name"))
+	case errors.Is(err, worker.ErrJobNotRunning):
+		apierror.Respond(c, http.StatusConflict, "后台任务未在执行", c.Param("name"))
+	default:
+		apierror.Respond(c, http.StatusInternalServerError, "服务器内部错误", err.Error())
+	}
+}
@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库渲染相关处理函数
+func (g *Gateway) getRenderedKnowledge(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		apierror.Respond(c, http.StatusBadRequest, "知识库条目ID不能为空", "请提供有效的知识库条目ID")
+		return
+	}
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	rendered, err := g.serviceManager.Knowledge().Render(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) {
+			apierror.Respond(c, http.StatusNotFound, "知识库条目不存在", err.Error())
+			return
+		}
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("渲染知识库条目失败")
+		apierror.Respond(c, errorStatus(err), "渲染知识库条目失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rendered,
+	})
+}
+
+func (g *Gateway) getKnowledgeAttachmentRaw(c *gin.Context) {
+	id := c.Param("id")
+	attachmentID := c.Param("attachment_id")
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	attachment, err := g.serviceManager.Knowledge().GetAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		if errors.Is(err, models.ErrAttachmentNotFound) {
+			apierror.Respond(c, http.StatusNotFound, "附件不存在", err.Error())
+			return
+		}
+		g.logger.WithError(err).WithField("knowledge_id", id).WithField("attachment_id", attachmentID).Error("获取知识库附件失败")
+		apierror.Respond(c, errorStatus(err), "获取知识库附件失败", err.Error())
+		return
+	}
+
+	// 禁止浏览器嗅探内容类型，避免附件被当作HTML执行
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; sandbox")
+	if attachment.MimeType != "" {
+		c.Header("Content-Type", attachment.MimeType)
+	}
+	c.File(attachment.FilePath)
+}
+
+func (g *Gateway) checkKnowledgeDuplicates(c *gin.Context) {
+	var req models.KnowledgeDuplicateCheckRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	req.ViewerID = g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), c.GetString("user_id"))
+	duplicates, err := g.serviceManager.Knowledge().CheckDuplicates(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidInput) {
+			apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+			return
+		}
+		g.logger.WithError(err).WithField("title", req.Title).Error("检测相似文章失败")
+		apierror.Respond(c, errorStatus(err), "检测相似文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"duplicates": duplicates,
+		"total":      len(duplicates),
+	})
+}
+
+func (g *Gateway) getKnowledgeBySlug(c *gin.Context) {
+	value := c.Param("slug")
+
+	lookup, err := g.serviceManager.Knowledge().GetBySlug(c.Request.Context(), value)
+	if err != nil {
+		g.logger.WithError(err).WithField("slug", value).Warn("根据slug获取知识库条目失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "获取知识库条目失败", err.Error())
+		return
+	}
+
+	if !g.requireKnowledgeAccess(c, lookup.KnowledgeID, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	// 标题修改后旧slug永久重定向到新地址
+	if lookup.Moved {
+		c.Redirect(http.StatusMovedPermanently, path.Join(path.Dir(c.Request.URL.Path), lookup.Slug))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": lookup.Knowledge,
+	})
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库访问控制相关处理函数
+func (g *Gateway) getKnowledgeACL(c *gin.Context) {
+	g.getACL(c, models.KnowledgeACLResourceArticle)
+}
+
+func (g *Gateway) setKnowledgeACL(c *gin.Context) {
+	g.setACL(c, models.KnowledgeACLResourceArticle)
+}
+
+func (g *Gateway) getKnowledgeCategoryACL(c *gin.Context) {
+	g.getACL(c, models.KnowledgeACLResourceCategory)
+}
+
+func (g *Gateway) setKnowledgeCategoryACL(c *gin.Context) {
+	g.setACL(c, models.KnowledgeACLResourceCategory)
+}
+
+func (g *Gateway) getACL(c *gin.Context, resourceType models.KnowledgeACLResourceType) {
+	id := c.Param("id")
+
+	entries, err := g.serviceManager.KnowledgeACL().GetACL(c.Request.Context(), resourceType, id, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("resource_id", id).Error("获取访问控制失败")
+		apierror.Respond(c, knowledgeACLErrorStatus(err), "获取访问控制失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+	})
+}
+
+func (g *Gateway) setACL(c *gin.Context, resourceType models.KnowledgeACLResourceType) {
+	id := c.Param("id")
+
+	var req models.KnowledgeACLRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	entries, err := g.serviceManager.KnowledgeACL().SetACL(c.Request.Context(), resourceType, id, c.GetString("user_id"), &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("resource_id", id).Error("设置访问控制失败")
+		apierror.Respond(c, knowledgeACLErrorStatus(err), "设置访问控制失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "访问控制设置成功",
+		"data":    entries,
+	})
+}
+
+// requireKnowledgeAccess 检查当前用户对文章的权限，无权限时写入错误响应并返回 false
+func (g *Gateway) requireKnowledgeAccess(c *gin.Context, knowledgeID string, permission models.KnowledgeACLPermission) bool {
+	err := g.serviceManager.KnowledgeACL().CheckAccess(c.Request.Context(), knowledgeID, c.GetString("user_id"), permission)
+	if err == nil {
+		return true
+	}
+
+	status := knowledgeACLErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		g.logger.WithError(err).WithField("knowledge_id", knowledgeID).Error("检查知识库访问权限失败")
+	}
+	apierror.Respond(c, status, "无权访问该知识库条目", err.Error())
+	return false
+}
+
+// knowledgeACLErrorStatus 将访问控制错误映射为HTTP状态码
+func knowledgeACLErrorStatus(err error) int {
+	if errors.Is(err, models.ErrKnowledgeCategoryNotFound) {
+		return http.StatusNotFound
+	}
+	return knowledgeReviewErrorStatus(err)
+}
@@ -0,0 +1,207 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库评论相关处理函数
+func (g *Gateway) listKnowledgeComments(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	comments, err := g.serviceManager.KnowledgeComment().List(c.Request.Context(), id, userID)
+	if err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("获取评论列表失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "获取评论列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    len(comments),
+	})
+}
+
+func (g *Gateway) createKnowledgeComment(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	var req models.KnowledgeCommentCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment, err := g.serviceManager.KnowledgeComment().Create(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("发表评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "发表评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "评论发表成功",
+		"data":    comment,
+	})
+}
+
+func (g *Gateway) updateKnowledgeComment(c *gin.Context) {
+	commentID := c.Param("comment_id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeCommentUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment, err := g.serviceManager.KnowledgeComment().Update(c.Request.Context(), commentID, userID, req.Content)
+	if err != nil {
+		g.logger.WithError(err).WithField("comment_id", commentID).Error("编辑评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "编辑评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "评论编辑成功",
+		"data":    comment,
+	})
+}
+
+func (g *Gateway) deleteKnowledgeComment(c *gin.Context) {
+	commentID := c.Param("comment_id")
+	userID := c.GetString("user_id")
+
+	if err := g.serviceManager.KnowledgeComment().Delete(c.Request.Context(), commentID, userID); err != nil {
+		g.logger.WithError(err).WithField("comment_id", commentID).Error("删除评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "删除评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "评论删除成功",
+	})
+}
+
+func (g *Gateway) addKnowledgeCommentReaction(c *gin.Context) {
+	g.reactToKnowledgeComment(c, true)
+}
+
+func (g *Gateway) removeKnowledgeCommentReaction(c *gin.Context) {
+	g.reactToKnowledgeComment(c, false)
+}
+
+// reactToKnowledgeComment 添加或取消表情回应
+func (g *Gateway) reactToKnowledgeComment(c *gin.Context, add bool) {
+	commentID := c.Param("comment_id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeCommentReactionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment, err := g.serviceManager.KnowledgeComment().React(c.Request.Context(), commentID, userID, req.Reaction, add)
+	if err != nil {
+		g.logger.WithError(err).WithField("comment_id", commentID).Error("更新表情回应失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "更新表情回应失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": comment,
+	})
+}
+
+func (g *Gateway) flagKnowledgeComment(c *gin.Context) {
+	commentID := c.Param("comment_id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeCommentFlagRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := g.serviceManager.KnowledgeComment().Flag(c.Request.Context(), commentID, userID, req.Reason); err != nil {
+		g.logger.WithError(err).WithField("comment_id", commentID).Error("举报评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "举报评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "举报已提交",
+	})
+}
+
+func (g *Gateway) moderateKnowledgeComment(c *gin.Context) {
+	commentID := c.Param("comment_id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeCommentModerateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment, err := g.serviceManager.KnowledgeComment().Moderate(c.Request.Context(), commentID, userID, req.Status, req.Reason)
+	if err != nil {
+		g.logger.WithError(err).WithField("comment_id", commentID).Error("审核评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "审核评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "评论审核成功",
+		"data":    comment,
+	})
+}
+
+func (g *Gateway) listFlaggedKnowledgeComments(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 200 {
+			limit = l
+		}
+	}
+
+	comments, err := g.serviceManager.KnowledgeComment().ListFlagged(c.Request.Context(), userID, limit)
+	if err != nil {
+		g.logger.WithError(err).WithField("user_id", userID).Error("获取举报评论失败")
+		apierror.Respond(c, knowledgeCommentErrorStatus(err), "获取举报评论失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    len(comments),
+	})
+}
+
+// knowledgeCommentErrorStatus 将知识库评论错误映射为HTTP状态码
+func knowledgeCommentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrKnowledgeNotFound),
+		errors.Is(err, models.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err)
+	}
+}
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 工单引用知识库文章及有用性反馈相关处理函数
+func (g *Gateway) listTicketKnowledgeLinks(c *gin.Context) {
+	links, err := g.serviceManager.KnowledgeFeedback().ListByTicket(c.Request.Context(), c.Param("id"))
+	if err == nil {
+		links, err = g.readableKnowledgeLinks(c, links)
+	}
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单引用的文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  links,
+		"total": len(links),
+	})
+}
+
+func (g *Gateway) linkTicketKnowledge(c *gin.Context) {
+	ticketID := c.Param("id")
+
+	var req models.TicketKnowledgeLinkRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !g.requireKnowledgeAccess(c, req.KnowledgeID, models.KnowledgeACLPermissionRead) {
+		return
+	}
+
+	link, err := g.serviceManager.KnowledgeFeedback().Link(c.Request.Context(), ticketID, req.KnowledgeID, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("引用知识库文章失败")
+		apierror.Respond(c, errorStatus(err), "引用知识库文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "引用成功",
+		"data":    link,
+	})
+}
+
+func (g *Gateway) unlinkTicketKnowledge(c *gin.Context) {
+	ticketID := c.Param("id")
+
+	if err := g.serviceManager.KnowledgeFeedback().Unlink(c.Request.Context(), ticketID, c.Param("knowledge_id")); err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("取消引用知识库文章失败")
+		apierror.Respond(c, errorStatus(err), "取消引用知识库文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消引用",
+	})
+}
+
+func (g *Gateway) submitKnowledgeFeedback(c *gin.Context) {
+	ticketID := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeFeedbackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	link, err := g.serviceManager.KnowledgeFeedback().Submit(c.Request.Context(), ticketID, c.Param("knowledge_id"), userID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).WithField("user_id", userID).Error("评价引用文章失败")
+		apierror.Respond(c, errorStatus(err), "评价引用文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "评价成功",
+		"data":    link,
+	})
+}
+
+func (g *Gateway) listPendingKnowledgeFeedback(c *gin.Context) {
+	links, err := g.serviceManager.KnowledgeFeedback().Pending(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取待评价的文章失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  links,
+		"total": len(links),
+	})
+}
+
+func (g *Gateway) listKnowledgeQuality(c *gin.Context) {
+	flaggedOnly, _ := strconv.ParseBool(c.Query("flagged"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	viewerID := g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), c.GetString("user_id"))
+
+	qualities, err := g.serviceManager.KnowledgeFeedback().Quality(c.Request.Context(), flaggedOnly, limit, viewerID)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取文章质量失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  qualities,
+		"total": len(qualities),
+	})
+}
+
+// readableKnowledgeLinks 过滤掉当前用户无权阅读的文章引用，避免通过工单泄露受限文章的标题
+func (g *Gateway) readableKnowledgeLinks(c *gin.Context, links []*models.TicketKnowledgeLink) ([]*models.TicketKnowledgeLink, error) {
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	if g.serviceManager.KnowledgeACL().ViewerScope(ctx, userID) == nil {
+		return links, nil
+	}
+
+	readable := make([]*models.TicketKnowledgeLink, 0, len(links))
+	for _, link := range links {
+		err := g.serviceManager.KnowledgeACL().CheckAccess(ctx, link.KnowledgeID, userID, models.KnowledgeACLPermissionRead)
+		switch {
+		case err == nil:
+			readable = append(readable, link)
+		case errors.Is(err, models.ErrPermissionDenied), errors.Is(err, models.ErrKnowledgeNotFound):
+		default:
+			return nil, err
+		}
+	}
+	return readable, nil
+}
@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+	"pulse/internal/service"
+)
+
+// fakeKnowledgeACLService admin 不受访问控制限制，其他用户只能阅读 readable 中的文章
+type fakeKnowledgeACLService struct {
+	service.KnowledgeACLService
+	admin    string
+	readable map[string]bool
+}
+
+func (s *fakeKnowledgeACLService) CheckAccess(ctx context.Context, knowledgeID, userID string, permission models.KnowledgeACLPermission) error {
+	if userID == s.admin || s.readable[knowledgeID] {
+		return nil
+	}
+	return models.ErrPermissionDenied
+}
+
+func (s *fakeKnowledgeACLService) ViewerScope(ctx context.Context, userID string) *string {
+	if userID == s.admin {
+		return nil
+	}
+	return &userID
+}
+
+// fakeKnowledgeFeedbackService 记录引用和质量查询使用的访问控制范围
+type fakeKnowledgeFeedbackService struct {
+	service.KnowledgeFeedbackService
+	links        []*models.TicketKnowledgeLink
+	qualityScope *string
+}
+
+func (s *fakeKnowledgeFeedbackService) ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketKnowledgeLink, error) {
+	return s.links, nil
+}
+
+func (s *fakeKnowledgeFeedbackService) Link(ctx context.Context, ticketID, knowledgeID, userID string) (*models.TicketKnowledgeLink, error) {
+	link := &models.TicketKnowledgeLink{ID: "l" + knowledgeID, TicketID: ticketID, KnowledgeID: knowledgeID, LinkedBy: userID}
+	s.links = append(s.links, link)
+	return link, nil
+}
+
+func (s *fakeKnowledgeFeedbackService) Quality(ctx context.Context, flaggedOnly bool, limit int, viewerID *string) ([]*models.KnowledgeQuality, error) {
+	s.qualityScope = viewerID
+	return []*models.KnowledgeQuality{}, nil
+}
+
+type knowledgeFeedbackServiceManager struct {
+	*MockServiceManager
+	acl      service.KnowledgeACLService
+	feedback service.KnowledgeFeedbackService
+}
+
+func (m *knowledgeFeedbackServiceManager) KnowledgeACL() service.KnowledgeACLService { return m.acl }
+
+func (m *knowledgeFeedbackServiceManager) KnowledgeFeedback() service.KnowledgeFeedbackService {
+	return m.feedback
+}
+
+func setupKnowledgeFeedbackHandlerTest() (*gin.Engine, *fakeKnowledgeFeedbackService) {
+	gin.SetMode(gin.TestMode)
+	feedback := &fakeKnowledgeFeedbackService{links: []*models.TicketKnowledgeLink{
+		{ID: "l1", TicketID: "t1", KnowledgeID: "kb1", KnowledgeTitle: "重启网关"},
+		{ID: "l2", TicketID: "t1", KnowledgeID: "kb2", KnowledgeTitle: "核心数据库凭据轮换"},
+	}}
+	gateway := &Gateway{
+		logger: logrus.New(),
+		serviceManager: &knowledgeFeedbackServiceManager{
+			MockServiceManager: &MockServiceManager{},
+			acl:                &fakeKnowledgeACLService{admin: "admin", readable: map[string]bool{"kb1": true}},
+			feedback:           feedback,
+		},
+	}
+
+	router := gin.New()
+	// 以请求头模拟认证中间件写入的当前用户
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User-ID"))
+		c.Next()
+	})
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/tickets/:id/knowledge", gateway.listTicketKnowledgeLinks)
+		v1.POST("/tickets/:id/knowledge", gateway.linkTicketKnowledge)
+		v1.GET("/knowledge/quality", gateway.listKnowledgeQuality)
+	}
+	return router, feedback
+}
+
+func TestListTicketKnowledgeLinks_OmitsUnreadableArticles(t *testing.T) {
+	router, _ := setupKnowledgeFeedbackHandlerTest()
+	list := func(userID string) []models.TicketKnowledgeLink {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets/t1/knowledge", nil)
+		req.Header.Set("X-User-ID", userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []models.TicketKnowledgeLink `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	links := list("u1")
+	require.Len(t, links, 1)
+	assert.Equal(t, "kb1", links[0].KnowledgeID)
+
+	assert.Len(t, list("admin"), 2)
+}
+
+func TestLinkTicketKnowledge_RequiresReadAccess(t *testing.T) {
+	router, feedback := setupKnowledgeFeedbackHandlerTest()
+	link := func(knowledgeID string) int {
+		body, _ := json.Marshal(map[string]string{"knowledge_id": knowledgeID})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tickets/t2/knowledge", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "u1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, link("kb2"))
+	assert.Len(t, feedback.links, 2)
+
+	assert.Equal(t, http.StatusCreated, link("kb1"))
+	assert.Len(t, feedback.links, 3)
+}
+
+func TestListKnowledgeQuality_UsesViewerScope(t *testing.T) {
+	router, feedback := setupKnowledgeFeedbackHandlerTest()
+	quality := func(userID string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/knowledge/quality", nil)
+		req.Header.Set("X-User-ID", userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	quality("u1")
+	require.NotNil(t, feedback.qualityScope)
+	assert.Equal(t, "u1", *feedback.qualityScope)
+
+	// 管理员不受访问控制限制
+	quality("admin")
+	assert.Nil(t, feedback.qualityScope)
+}
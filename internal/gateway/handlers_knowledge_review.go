@@ -0,0 +1,214 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库审核相关处理函数
+func (g *Gateway) submitKnowledgeForReview(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	submission, err := g.serviceManager.KnowledgeReview().SubmitForReview(c.Request.Context(), id, userID)
+	if err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("提交知识库审核失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "提交知识库审核失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "已提交审核",
+		"data":    submission,
+	})
+}
+
+func (g *Gateway) reviewKnowledge(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req models.KnowledgeReviewRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	var (
+		submission *models.KnowledgeReviewSubmission
+		err        error
+	)
+	if req.Approved {
+		submission, err = g.serviceManager.KnowledgeReview().Approve(c.Request.Context(), id, userID, req.Comment)
+	} else {
+		submission, err = g.serviceManager.KnowledgeReview().Reject(c.Request.Context(), id, userID, req.Comment)
+	}
+	if err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).WithField("reviewer_id", userID).Error("审核知识库条目失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "审核知识库条目失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "审核成功",
+		"data":    submission,
+	})
+}
+
+func (g *Gateway) getKnowledgeReviewStatus(c *gin.Context) {
+	id := c.Param("id")
+
+	submission, err := g.serviceManager.KnowledgeReview().GetReviewStatus(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "获取审核状态失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": submission,
+	})
+}
+
+func (g *Gateway) listPendingKnowledgeReviews(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	page := 1
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	pageSize := 20
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 && ps <= 100 {
+			pageSize = ps
+		}
+	}
+
+	queue, err := g.serviceManager.KnowledgeReview().ListPendingReviews(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		g.logger.WithError(err).WithField("user_id", userID).Error("获取待审核队列失败")
+		apierror.Respond(c, errorStatus(err), "获取待审核队列失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+func (g *Gateway) listKnowledgeReviewPolicies(c *gin.Context) {
+	policies, err := g.serviceManager.KnowledgeReview().ListPolicies(c.Request.Context())
+	if err != nil {
+		g.logger.WithError(err).Error("获取审核策略列表失败")
+		apierror.Respond(c, errorStatus(err), "获取审核策略列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+		"total":    len(policies),
+	})
+}
+
+func (g *Gateway) createKnowledgeReviewPolicy(c *gin.Context) {
+	var req models.KnowledgeReviewPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy := &models.KnowledgeReviewPolicy{Enabled: true}
+	req.ApplyTo(policy)
+	if userID := c.GetString("user_id"); userID != "" {
+		policy.CreatedBy = &userID
+	}
+
+	if err := g.serviceManager.KnowledgeReview().CreatePolicy(c.Request.Context(), policy); err != nil {
+		g.logger.WithError(err).WithField("name", policy.Name).Error("创建审核策略失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "创建审核策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "审核策略创建成功",
+		"data":    policy,
+	})
+}
+
+func (g *Gateway) getKnowledgeReviewPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	policy, err := g.serviceManager.KnowledgeReview().GetPolicy(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "获取审核策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": policy,
+	})
+}
+
+func (g *Gateway) updateKnowledgeReviewPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.KnowledgeReviewPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy, err := g.serviceManager.KnowledgeReview().GetPolicy(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "获取审核策略失败", err.Error())
+		return
+	}
+
+	req.ApplyTo(policy)
+	if err := g.serviceManager.KnowledgeReview().UpdatePolicy(c.Request.Context(), policy); err != nil {
+		g.logger.WithError(err).WithField("policy_id", id).Error("更新审核策略失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "更新审核策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "审核策略更新成功",
+		"data":    policy,
+	})
+}
+
+func (g *Gateway) deleteKnowledgeReviewPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := g.serviceManager.KnowledgeReview().DeletePolicy(c.Request.Context(), id); err != nil {
+		g.logger.WithError(err).WithField("policy_id", id).Error("删除审核策略失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "删除审核策略失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "审核策略删除成功",
+	})
+}
+
+// knowledgeReviewErrorStatus 将知识库审核错误映射为HTTP状态码
+func knowledgeReviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrKnowledgeNotFound),
+		errors.Is(err, models.ErrKnowledgeReviewNotFound),
+		errors.Is(err, models.ErrKnowledgeReviewPolicyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrKnowledgeReviewPolicyExists),
+		errors.Is(err, models.ErrKnowledgeReviewDecided),
+		errors.Is(err, models.ErrKnowledgeInvalidStatus):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err)
+	}
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库定时发布与过期时间相关处理函数
+func (g *Gateway) scheduleKnowledge(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.KnowledgeScheduleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionWrite) {
+		return
+	}
+
+	knowledge, err := g.serviceManager.KnowledgeSchedule().Schedule(c.Request.Context(), id, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("设置定时发布失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "设置定时发布失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "定时发布设置成功",
+		"data":    knowledge,
+	})
+}
+
+func (g *Gateway) cancelKnowledgeSchedule(c *gin.Context) {
+	id := c.Param("id")
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionWrite) {
+		return
+	}
+
+	if err := g.serviceManager.KnowledgeSchedule().CancelSchedule(c.Request.Context(), id); err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("取消定时发布失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "取消定时发布失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消定时发布",
+	})
+}
+
+func (g *Gateway) updateKnowledgeExpiry(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.KnowledgeExpiryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !g.requireKnowledgeAccess(c, id, models.KnowledgeACLPermissionWrite) {
+		return
+	}
+
+	if err := g.serviceManager.KnowledgeSchedule().UpdateExpiry(c.Request.Context(), id, req.ExpiresAt); err != nil {
+		g.logger.WithError(err).WithField("knowledge_id", id).Error("更新过期时间失败")
+		apierror.Respond(c, knowledgeReviewErrorStatus(err), "更新过期时间失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "过期时间更新成功",
+		"expires_at": req.ExpiresAt,
+	})
+}
@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 知识库导入导出相关处理函数
+func (g *Gateway) importKnowledge(c *gin.Context) {
+	userID := c.GetString("user_id")
+	format := models.KnowledgeImportFormat(c.DefaultPostForm("format", string(models.KnowledgeImportFormatMarkdown)))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请上传导入文件", err.Error())
+		return
+	}
+	if fileHeader.Size > models.KnowledgeImportMaxSize {
+		apierror.Respond(c, http.StatusRequestEntityTooLarge, "导入文件过大", fmt.Sprintf("导入文件不能超过 %d MB", models.KnowledgeImportMaxSize>>20))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取导入文件失败", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.KnowledgeImportMaxSize))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取导入文件失败", err.Error())
+		return
+	}
+
+	result, err := g.serviceManager.KnowledgeTransfer().Import(c.Request.Context(), format, data, userID)
+	if err != nil {
+		g.logger.WithError(err).WithField("format", format).Error("导入知识库失败")
+		apierror.Respond(c, errorStatus(err), "导入知识库失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("成功导入%d篇文章，失败%d篇", result.Imported, result.Failed),
+		"data":    result,
+	})
+}
+
+func (g *Gateway) exportKnowledge(c *gin.Context) {
+	categoryIDs := c.QueryArray("category_id")
+
+	// async=true 时提交导出任务，完成后通过任务的 result_url 下载
+	if wantsAsync(c) {
+		params, err := json.Marshal(models.KnowledgeExportParams{CategoryIDs: categoryIDs})
+		if err != nil {
+			apierror.Respond(c, http.StatusInternalServerError, "提交异步任务失败", err.Error())
+			return
+		}
+		g.enqueueAsyncTask(c, &models.AsyncTaskRequest{Type: models.AsyncTaskKnowledgeExport, Params: params})
+		return
+	}
+
+	viewerID := g.serviceManager.KnowledgeACL().ViewerScope(c.Request.Context(), c.GetString("user_id"))
+
+	var buf bytes.Buffer
+	count, err := g.serviceManager.KnowledgeTransfer().Export(c.Request.Context(), categoryIDs, viewerID, &buf)
+	if err != nil {
+		g.logger.WithError(err).WithField("category_ids", categoryIDs).Error("导出知识库失败")
+		apierror.Respond(c, errorStatus(err), "导出知识库失败", err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("knowledge-export-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("X-Export-Count", fmt.Sprintf("%d", count))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
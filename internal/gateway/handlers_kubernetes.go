@@ -0,0 +1,23 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// syncKubernetesDataSource 立即采集 Kubernetes 数据源，无需等待下一个采集周期
+func (g *Gateway) syncKubernetesDataSource(c *gin.Context) {
+	result, err := g.serviceManager.Kubernetes().SyncDataSource(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "采集Kubernetes数据源失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kubernetes数据源采集完成",
+		"data":    result,
+	})
+}
@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警标签自动补全相关处理函数
+
+// labelSuggestionQuery 读取前缀和返回数量参数
+func labelSuggestionQuery(c *gin.Context) *models.LabelSuggestionQuery {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return &models.LabelSuggestionQuery{Prefix: c.Query("prefix"), Limit: limit}
+}
+
+func (g *Gateway) listLabelKeys(c *gin.Context) {
+	suggestions, err := g.serviceManager.LabelAutocomplete().Keys(c.Request.Context(), labelSuggestionQuery(c))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签键失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": suggestions,
+	})
+}
+
+func (g *Gateway) listLabelValues(c *gin.Context) {
+	suggestions, err := g.serviceManager.LabelAutocomplete().Values(c.Request.Context(), c.Param("key"), labelSuggestionQuery(c))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签值失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": suggestions,
+	})
+}
@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// 告警标签基数相关处理函数
+func (g *Gateway) getLabelCardinality(c *gin.Context) {
+	top, _ := strconv.Atoi(c.Query("top"))
+
+	report, err := g.serviceManager.LabelCardinality().Report(c.Request.Context(), top)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签基数报告失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// getEnumNames 获取枚举显示名，使用当前用户的语言偏好，未设置时使用 Accept-Language
+func (g *Gateway) getEnumNames(c *gin.Context) {
+	localization := g.serviceManager.Localization()
+	locale, enums := localization.Enums(c.Request.Context(), c.GetString("user_id"), apierror.LocaleFromContext(c))
+
+	c.Header("Content-Language", string(locale))
+	c.JSON(http.StatusOK, gin.H{
+		"locale":  locale,
+		"locales": localization.Locales(),
+		"data":    enums,
+	})
+}
+
+func (g *Gateway) getUserLanguage(c *gin.Context) {
+	g.respondLanguage(c, models.LanguageScopeUser, c.GetString("user_id"))
+}
+
+func (g *Gateway) setUserLanguage(c *gin.Context) {
+	g.updateLanguage(c, models.LanguageScopeUser, c.GetString("user_id"))
+}
+
+func (g *Gateway) deleteUserLanguage(c *gin.Context) {
+	g.removeLanguage(c, models.LanguageScopeUser, c.GetString("user_id"))
+}
+
+func (g *Gateway) getTeamLanguage(c *gin.Context) {
+	g.respondLanguage(c, models.LanguageScopeTeam, c.Param("team"))
+}
+
+func (g *Gateway) setTeamLanguage(c *gin.Context) {
+	g.updateLanguage(c, models.LanguageScopeTeam, c.Param("team"))
+}
+
+func (g *Gateway) deleteTeamLanguage(c *gin.Context) {
+	g.removeLanguage(c, models.LanguageScopeTeam, c.Param("team"))
+}
+
+// respondLanguage 返回用户或团队的语言偏好
+func (g *Gateway) respondLanguage(c *gin.Context, scope models.LanguageScope, subject string) {
+	pref, err := g.serviceManager.Localization().GetLanguage(c.Request.Context(), scope, subject)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取语言偏好失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": pref,
+	})
+}
+
+// updateLanguage 设置用户或团队的语言偏好
+func (g *Gateway) updateLanguage(c *gin.Context, scope models.LanguageScope, subject string) {
+	var req models.LanguagePreferenceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	pref, err := g.serviceManager.Localization().SetLanguage(c.Request.Context(), scope, subject, &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "设置语言偏好失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "语言偏好设置成功",
+		"data":    pref,
+	})
+}
+
+// removeLanguage 删除用户或团队的语言偏好
+func (g *Gateway) removeLanguage(c *gin.Context, scope models.LanguageScope, subject string) {
+	if err := g.serviceManager.Localization().DeleteLanguage(c.Request.Context(), scope, subject); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除语言偏好失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "语言偏好已删除",
+	})
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/loglevel"
+)
+
+// logLevelRequest 调整日志级别请求
+type logLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// 运行时日志级别相关处理函数，模块为 zap 日志器名称，如 worker、worker.slo、service、database
+func (g *Gateway) getLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": g.logLevels.Snapshot()})
+}
+
+func (g *Gateway) setLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	level, err := loglevel.Parse(req.Level)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "日志级别无效", err.Error())
+		return
+	}
+
+	g.logLevels.SetGlobal(level)
+	g.logger.WithField("level", level.String()).WithField("user_id", c.GetString("user_id")).Info("全局日志级别已调整")
+	c.JSON(http.StatusOK, gin.H{"data": g.logLevels.Snapshot()})
+}
+
+func (g *Gateway) setModuleLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	level, err := loglevel.Parse(req.Level)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "日志级别无效", err.Error())
+		return
+	}
+
+	module := c.Param("module")
+	if err := g.logLevels.SetModule(module, level); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "日志级别无效", err.Error())
+		return
+	}
+	g.logger.WithField("module", module).WithField("level", level.String()).WithField("user_id", c.GetString("user_id")).Info("模块日志级别已调整")
+	c.JSON(http.StatusOK, gin.H{"data": g.logLevels.Snapshot()})
+}
+
+func (g *Gateway) resetModuleLogLevel(c *gin.Context) {
+	module := c.Param("module")
+	if !g.logLevels.ResetModule(module) {
+		apierror.Respond(c, http.StatusNotFound, "模块未设置日志级别", module)
+		return
+	}
+	g.logger.WithField("module", module).WithField("user_id", c.GetString("user_id")).Info("模块日志级别已恢复为全局级别")
+	c.JSON(http.StatusOK, gin.H{"data": g.logLevels.Snapshot()})
+}
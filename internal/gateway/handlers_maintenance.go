@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/maintenance"
+	"pulse/internal/queue"
+)
+
+// ingestReplayMaxRetry 补处理暂存的接入请求时，数据库等临时故障的最大重试次数
+const ingestReplayMaxRetry = 5
+
+// maintenanceSkipPaths 维护期间仍放行的写请求：切换维护模式的接口和自行暂存请求的告警接入接口
+var maintenanceSkipPaths = []string{
+	"/api/v1/admin/maintenance/enable",
+	"/api/v1/admin/maintenance/disable",
+	"/api/v1/ingest/:id/alerts",
+	"/api/v1/webhooks/custom/:integrationID",
+}
+
+// 维护模式相关处理函数，维护期间拒绝写请求，告警接入请求暂存到队列，维护结束后补处理
+func (g *Gateway) getMaintenanceMode(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": g.maintenance.Status()})
+}
+
+func (g *Gateway) enableMaintenanceMode(c *gin.Context) {
+	var req models.MaintenanceModeRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	userID := c.GetString("user_id")
+	status, err := g.maintenance.Enable(c.Request.Context(), req.Reason, userID)
+	if err != nil {
+		g.logger.WithError(err).Error("开启维护模式失败")
+		apierror.Respond(c, http.StatusInternalServerError, "开启维护模式失败", err.Error())
+		return
+	}
+	g.logger.WithField("reason", req.Reason).WithField("user_id", userID).Warn("维护模式已开启")
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+func (g *Gateway) disableMaintenanceMode(c *gin.Context) {
+	userID := c.GetString("user_id")
+	status, err := g.maintenance.Disable(c.Request.Context(), userID)
+	if err != nil {
+		g.logger.WithError(err).Error("关闭维护模式失败")
+		apierror.Respond(c, http.StatusInternalServerError, "关闭维护模式失败", err.Error())
+		return
+	}
+	g.logger.WithField("user_id", userID).Info("维护模式已关闭")
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// bufferIngest 维护期间将接入请求暂存到队列并返回 202，未配置队列或写入失败时返回 503 由上游重试
+func (g *Gateway) bufferIngest(c *gin.Context, ingest *models.BufferedIngest) {
+	respondUnavailable := func(err error) {
+		status := g.maintenance.Status()
+		c.Header("Retry-After", strconv.Itoa(status.RetryAfter))
+		apierror.RespondCode(c, apierror.CodeMaintenanceMode, "", err.Error())
+	}
+	if g.ingestQueue == nil {
+		respondUnavailable(fmt.Errorf("未配置接入暂存队列"))
+		return
+	}
+
+	ingest.ClientIP = c.ClientIP()
+	ingest.ReceivedAt = time.Now()
+	payload, err := json.Marshal(ingest)
+	if err != nil {
+		respondUnavailable(err)
+		return
+	}
+	if err := g.ingestQueue.Publish(c.Request.Context(), models.IngestBufferTopic, payload, queue.WithMaxRetry(ingestReplayMaxRetry)); err != nil {
+		g.logger.WithError(err).WithField("integration_id", ingest.IntegrationID).Error("暂存告警接入请求失败")
+		respondUnavailable(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "系统维护中，告警已暂存，维护结束后处理",
+	})
+}
+
+// StartIngestReplay 维护模式关闭时订阅暂存队列补处理接入请求，开启时取消订阅使请求留在队列中，
+// 需在 SetIngestQueue 和 SetMaintenanceMode 之后调用
+func (g *Gateway) StartIngestReplay(ctx context.Context) error {
+	if g.ingestQueue == nil {
+		return nil
+	}
+
+	subscribe := func() error {
+		return g.ingestQueue.Subscribe(ctx, models.IngestBufferTopic, g.replayIngest,
+			queue.WithSubscribeMaxRetry(ingestReplayMaxRetry), queue.WithRetryDelay(5*time.Second))
+	}
+	if g.maintenance != nil {
+		g.maintenance.OnChange(func(status maintenance.Status) {
+			var err error
+			if status.Enabled {
+				err = g.ingestQueue.Unsubscribe(models.IngestBufferTopic)
+			} else {
+				err = subscribe()
+			}
+			if err != nil {
+				g.logger.WithError(err).Warn("切换接入暂存队列订阅失败")
+			}
+		})
+	}
+	if g.maintenance.Enabled() {
+		return nil
+	}
+	return subscribe()
+}
+
+// replayIngest 补处理暂存的接入请求。签名按接收时间校验；校验或格式错误的请求记录后丢弃，
+// 数据库等临时故障返回错误由队列重试
+func (g *Gateway) replayIngest(ctx context.Context, msg *queue.Message) error {
+	var ingest models.BufferedIngest
+	if err := json.Unmarshal(msg.Payload, &ingest); err != nil {
+		g.logger.WithError(err).Warn("暂存的告警接入请求格式无效，已丢弃")
+		return nil
+	}
+
+	err := g.processBufferedIngest(ctx, &ingest)
+	if err == nil {
+		g.recordIngest(true)
+		return nil
+	}
+	if errorStatus(err) == http.StatusInternalServerError {
+		return err
+	}
+	g.recordIngest(false)
+	g.logger.WithError(err).WithField("integration_id", ingest.IntegrationID).
+		WithField("received_at", ingest.ReceivedAt).Warn("暂存的告警接入请求处理失败，已丢弃")
+	return nil
+}
+
+func (g *Gateway) processBufferedIngest(ctx context.Context, ingest *models.BufferedIngest) error {
+	integrations := g.serviceManager.WebhookIntegration()
+	var integration *models.WebhookIntegration
+	var err error
+	if ingest.Signature != "" || ingest.Kind == models.BufferedIngestAlert {
+		integration, err = integrations.VerifyAt(ctx, ingest.IntegrationID, ingest.ClientIP,
+			ingest.Signature, ingest.Timestamp, ingest.Body, ingest.ReceivedAt)
+	} else {
+		integration, err = integrations.VerifyToken(ctx, ingest.IntegrationID, ingest.ClientIP, ingest.Token)
+	}
+	if err != nil {
+		return err
+	}
+
+	rejections := g.serviceManager.IngestRejection()
+	switch ingest.Kind {
+	case models.BufferedIngestAlert:
+		req := models.AlertCreateRequest{Source: integration.Source}
+		if err := decodeIngestAlert(ingest.Body, rejections.Mode(), &req); err != nil {
+			rejections.Record(ctx, integration.ID, models.IngestEndpointAlerts, ingest.ClientIP, ingest.Body, err)
+			return err
+		}
+		return g.serviceManager.Alert().Create(ctx, newIngestedAlert(&req, integration))
+	case models.BufferedIngestCustom:
+		result, err := integrations.IngestCustom(ctx, integration, ingest.Body)
+		if err != nil {
+			rejections.Record(ctx, integration.ID, models.IngestEndpointCustom, ingest.ClientIP, ingest.Body, err)
+			return err
+		}
+		if len(result.Errors) > 0 {
+			err := models.NewIngestSchemaError(models.IngestRejectMappingError, "%s", strings.Join(result.Errors, "; "))
+			rejections.Record(ctx, integration.ID, models.IngestEndpointCustom, ingest.ClientIP, ingest.Body, err)
+			return fmt.Errorf("%w: %d 条告警处理失败", models.ErrInvalidInput, len(result.Errors))
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: 未知的接入类型 %s", models.ErrInvalidInput, ingest.Kind)
+	}
+}
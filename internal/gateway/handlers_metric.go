@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/remotewrite"
+)
+
+// maxRemoteWriteBodySize remote-write 请求体（压缩后）大小上限
+const maxRemoteWriteBodySize = 8 << 20
+
+// remoteWrite 接收采集端通过 Prometheus remote-write 协议推送的指标。
+// 成功时返回 204；请求格式错误返回 4xx，采集端丢弃该批样本；维护模式和存储失败返回 5xx，采集端稍后重试
+func (g *Gateway) remoteWrite(c *gin.Context) {
+	if encoding := c.GetHeader("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, remotewrite.ContentEncoding) {
+		apierror.Respond(c, http.StatusUnsupportedMediaType, "不支持的压缩方式，请使用 snappy", encoding)
+		return
+	}
+	body, ok := readLimitedBody(c, maxRemoteWriteBodySize)
+	if !ok {
+		return
+	}
+	if g.maintenance.Enabled() {
+		apierror.RespondCode(c, apierror.CodeMaintenanceMode, "", nil)
+		return
+	}
+
+	if _, err := g.serviceManager.Metric().RemoteWrite(c.Request.Context(), body); err != nil {
+		if errors.Is(err, remotewrite.ErrTooLarge) {
+			apierror.RespondCode(c, apierror.CodePayloadTooLarge, "", err.Error())
+			return
+		}
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).Error("写入远程指标失败")
+		}
+		apierror.Respond(c, status, "写入远程指标失败", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// queryMetrics 对内置指标存储执行即时查询，阈值规则和采集端推送的指标可据此校验表达式
+func (g *Gateway) queryMetrics(c *gin.Context) {
+	query := c.Query("query")
+	if strings.TrimSpace(query) == "" {
+		apierror.Respond(c, http.StatusBadRequest, "查询表达式不能为空", nil)
+		return
+	}
+
+	var at time.Time
+	if timeStr := c.Query("time"); timeStr != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, timeStr); err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "查询时间格式无效", err.Error())
+			return
+		}
+	}
+
+	result, err := g.serviceManager.Metric().Evaluate(c.Request.Context(), query, at)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "查询指标失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 通知日历相关处理函数，非工作时间的低级别告警通知按日历延迟到工作时间汇总发送
+func (g *Gateway) listNotificationCalendars(c *gin.Context) {
+	calendars, err := g.serviceManager.NotificationCalendar().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知日历列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  calendars,
+		"total": len(calendars),
+	})
+}
+
+func (g *Gateway) getNotificationCalendar(c *gin.Context) {
+	calendar, err := g.serviceManager.NotificationCalendar().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知日历失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": calendar,
+	})
+}
+
+func (g *Gateway) createNotificationCalendar(c *gin.Context) {
+	var req models.NotificationCalendarRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	calendar, err := g.serviceManager.NotificationCalendar().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建通知日历失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "通知日历创建成功",
+		"data":    calendar,
+	})
+}
+
+func (g *Gateway) updateNotificationCalendar(c *gin.Context) {
+	var req models.NotificationCalendarRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	calendar, err := g.serviceManager.NotificationCalendar().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新通知日历失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知日历更新成功",
+		"data":    calendar,
+	})
+}
+
+func (g *Gateway) deleteNotificationCalendar(c *gin.Context) {
+	if err := g.serviceManager.NotificationCalendar().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除通知日历失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知日历删除成功",
+	})
+}
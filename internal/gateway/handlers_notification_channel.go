@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 团队通知渠道相关处理函数，渠道地址和密钥加密存储，接口不返回
+func (g *Gateway) listNotificationChannels(c *gin.Context) {
+	filter := &models.NotificationChannelFilter{
+		Team: c.Query("team"),
+		Type: models.NotificationType(c.Query("type")),
+	}
+
+	channels, err := g.serviceManager.NotificationChannel().List(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知渠道列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  channels,
+		"total": len(channels),
+	})
+}
+
+func (g *Gateway) getNotificationChannel(c *gin.Context) {
+	channel, err := g.serviceManager.NotificationChannel().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知渠道失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": channel,
+	})
+}
+
+func (g *Gateway) createNotificationChannel(c *gin.Context) {
+	var req models.NotificationChannelRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	channel, err := g.serviceManager.NotificationChannel().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建通知渠道失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "通知渠道创建成功",
+		"data":    channel,
+	})
+}
+
+func (g *Gateway) updateNotificationChannel(c *gin.Context) {
+	var req models.NotificationChannelRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	channel, err := g.serviceManager.NotificationChannel().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新通知渠道失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知渠道更新成功",
+		"data":    channel,
+	})
+}
+
+func (g *Gateway) deleteNotificationChannel(c *gin.Context) {
+	if err := g.serviceManager.NotificationChannel().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除通知渠道失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知渠道删除成功",
+	})
+}
+
+// testTeamNotificationChannel 使用团队渠道的配置发送测试消息，检查失败时仍返回 200 和失败的检查结果
+func (g *Gateway) testTeamNotificationChannel(c *gin.Context) {
+	var req models.ChannelTestRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := g.serviceManager.NotificationChannel().Test(c.Request.Context(), c.Param("id"), req.Recipient)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "测试通知渠道失败", err.Error())
+		return
+	}
+
+	message := "渠道测试通过"
+	if !result.Success {
+		message = "渠道测试未通过"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    result,
+	})
+}
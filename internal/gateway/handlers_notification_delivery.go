@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 通知投递审计和渠道测试相关处理函数，用于排查"没有收到通知"的问题
+
+// listAlertNotifications 告警的全部通知及每次投递的渠道、目标和结果
+func (g *Gateway) listAlertNotifications(c *gin.Context) {
+	deliveries, err := g.serviceManager.Notification().ListAlertDeliveries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知投递记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"total": len(deliveries),
+	})
+}
+
+// listTicketNotifications 工单关联告警的全部通知及投递记录
+func (g *Gateway) listTicketNotifications(c *gin.Context) {
+	deliveries, err := g.serviceManager.Notification().ListTicketDeliveries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知投递记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"total": len(deliveries),
+	})
+}
+
+func (g *Gateway) getNotificationDelivery(c *gin.Context) {
+	delivery, err := g.serviceManager.Notification().GetDelivery(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取通知投递记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": delivery,
+	})
+}
+
+// resendNotification 按原渠道和原内容重发通知，投递失败时仍返回本次的投递记录
+func (g *Gateway) resendNotification(c *gin.Context) {
+	attempt, err := g.serviceManager.Notification().Resend(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "重发通知失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "通知已重发",
+		"data":    attempt,
+	})
+}
+
+// testNotificationChannel 向通知渠道发送测试消息并返回 DNS、TLS、认证和渠道响应的检查结果，
+// 渠道 ID 为通知类型。检查失败时仍返回 200 和失败的检查结果
+func (g *Gateway) testNotificationChannel(c *gin.Context) {
+	var req models.ChannelTestRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	channel := models.NotificationType(c.Param("id"))
+	result, err := g.serviceManager.Notification().TestChannel(c.Request.Context(), channel, req.Recipient)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "测试通知渠道失败", err.Error())
+		return
+	}
+
+	message := "渠道测试通过"
+	if !result.Success {
+		message = "渠道测试未通过"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    result,
+	})
+}
@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 事件复盘相关处理函数
+func (g *Gateway) listPostmortemTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": g.serviceManager.Postmortem().Templates(),
+	})
+}
+
+func (g *Gateway) listPostmortems(c *gin.Context) {
+	filter := &models.PostmortemFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			filter.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			filter.PageSize = pageSize
+		}
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.PostmortemStatus(statusStr)
+		if status.IsValid() {
+			filter.Status = &status
+		}
+	}
+
+	if incidentID := c.Query("incident_id"); incidentID != "" {
+		filter.IncidentID = &incidentID
+	}
+
+	if alertID := c.Query("alert_id"); alertID != "" {
+		filter.AlertID = &alertID
+	}
+
+	list, err := g.serviceManager.Postmortem().List(c.Request.Context(), filter)
+	if err != nil {
+		g.logger.WithError(err).Error("获取复盘列表失败")
+		apierror.Respond(c, errorStatus(err), "获取复盘列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (g *Gateway) createPostmortem(c *gin.Context) {
+	var req models.PostmortemCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	postmortem, err := g.serviceManager.Postmortem().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("title", req.Title).Error("创建复盘失败")
+		apierror.Respond(c, errorStatus(err), "创建复盘失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "复盘创建成功",
+		"data":    postmortem,
+	})
+}
+
+func (g *Gateway) getPostmortem(c *gin.Context) {
+	id := c.Param("id")
+
+	postmortem, err := g.serviceManager.Postmortem().Get(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取复盘失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": postmortem,
+	})
+}
+
+func (g *Gateway) updatePostmortem(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.PostmortemUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	postmortem, err := g.serviceManager.Postmortem().Update(c.Request.Context(), id, &req, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("postmortem_id", id).Error("更新复盘失败")
+		apierror.Respond(c, errorStatus(err), "更新复盘失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "复盘更新成功",
+		"data":    postmortem,
+	})
+}
+
+func (g *Gateway) deletePostmortem(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := g.serviceManager.Postmortem().Delete(c.Request.Context(), id); err != nil {
+		g.logger.WithError(err).WithField("postmortem_id", id).Error("删除复盘失败")
+		apierror.Respond(c, errorStatus(err), "删除复盘失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "复盘删除成功",
+	})
+}
+
+func (g *Gateway) publishPostmortem(c *gin.Context) {
+	id := c.Param("id")
+
+	postmortem, err := g.serviceManager.Postmortem().Publish(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("postmortem_id", id).Error("发布复盘失败")
+		apierror.Respond(c, errorStatus(err), "发布复盘失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "复盘已发布到知识库",
+		"data":    postmortem,
+	})
+}
+
+func (g *Gateway) createPostmortemActionItem(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.PostmortemActionItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := g.serviceManager.Postmortem().AddActionItem(c.Request.Context(), id, &req, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("postmortem_id", id).Error("创建复盘改进项失败")
+		apierror.Respond(c, errorStatus(err), "创建复盘改进项失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "复盘改进项创建成功",
+		"data":    item,
+	})
+}
+
+func (g *Gateway) updatePostmortemActionItem(c *gin.Context) {
+	id := c.Param("id")
+	itemID := c.Param("item_id")
+
+	var req models.PostmortemActionItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := g.serviceManager.Postmortem().UpdateActionItem(c.Request.Context(), id, itemID, &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("item_id", itemID).Error("更新复盘改进项失败")
+		apierror.Respond(c, errorStatus(err), "更新复盘改进项失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "复盘改进项更新成功",
+		"data":    item,
+	})
+}
+
+func (g *Gateway) deletePostmortemActionItem(c *gin.Context) {
+	id := c.Param("id")
+	itemID := c.Param("item_id")
+
+	if err := g.serviceManager.Postmortem().DeleteActionItem(c.Request.Context(), id, itemID); err != nil {
+		g.logger.WithError(err).WithField("item_id", itemID).Error("删除复盘改进项失败")
+		apierror.Respond(c, errorStatus(err), "删除复盘改进项失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "复盘改进项删除成功",
+	})
+}
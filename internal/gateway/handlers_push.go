@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 移动端推送相关处理函数，设备和偏好均属于当前用户
+func (g *Gateway) listPushDevices(c *gin.Context) {
+	devices, err := g.serviceManager.Push().ListDevices(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取推送设备列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  devices,
+		"total": len(devices),
+	})
+}
+
+func (g *Gateway) registerPushDevice(c *gin.Context) {
+	var req models.PushDeviceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	device, err := g.serviceManager.Push().RegisterDevice(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "注册推送设备失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "推送设备注册成功",
+		"data":    device,
+	})
+}
+
+func (g *Gateway) deletePushDevice(c *gin.Context) {
+	if err := g.serviceManager.Push().DeleteDevice(c.Request.Context(), c.Param("id"), c.GetString("user_id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除推送设备失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "推送设备删除成功",
+	})
+}
+
+func (g *Gateway) getPushPreference(c *gin.Context) {
+	pref, err := g.serviceManager.Push().GetPreference(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取推送偏好失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": pref,
+	})
+}
+
+func (g *Gateway) setPushPreference(c *gin.Context) {
+	var req models.PushPreferenceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	pref, err := g.serviceManager.Push().SetPreference(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "设置推送偏好失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "推送偏好设置成功",
+		"data":    pref,
+	})
+}
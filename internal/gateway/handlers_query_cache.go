@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// 数据源查询结果缓存相关处理函数，查看缓存命中情况，数据源配置或指标变更后手动清除缓存
+func (g *Gateway) getQueryCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": g.serviceManager.QueryCache().Metrics()})
+}
+
+// invalidateQueryCache 清除数据源的缓存查询结果，未指定 data_source_id 时清除全部缓存
+func (g *Gateway) invalidateQueryCache(c *gin.Context) {
+	dataSourceID := c.Query("data_source_id")
+	if err := g.serviceManager.QueryCache().Invalidate(c.Request.Context(), dataSourceID); err != nil {
+		apierror.Respond(c, errorStatus(err), "清除查询结果缓存失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("data_source_id", dataSourceID).WithField("user_id", c.GetString("user_id")).Info("查询结果缓存已清除")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "查询结果缓存已清除",
+		"data":    g.serviceManager.QueryCache().Metrics(),
+	})
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 免打扰相关处理函数，设置均属于当前用户
+func (g *Gateway) getQuietHours(c *gin.Context) {
+	quiet, err := g.serviceManager.QuietHours().Get(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取免打扰设置失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": quiet,
+	})
+}
+
+func (g *Gateway) setQuietHours(c *gin.Context) {
+	var req models.QuietHoursRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quiet, err := g.serviceManager.QuietHours().SetSchedule(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "设置免打扰时段失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "免打扰时段设置成功",
+		"data":    quiet,
+	})
+}
+
+func (g *Gateway) deleteQuietHours(c *gin.Context) {
+	if err := g.serviceManager.QuietHours().Delete(c.Request.Context(), c.GetString("user_id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "清除免打扰设置失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "免打扰设置已清除",
+	})
+}
+
+func (g *Gateway) startDND(c *gin.Context) {
+	var req models.DNDRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quiet, err := g.serviceManager.QuietHours().StartDND(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "开启免打扰失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "免打扰已开启",
+		"data":    quiet,
+	})
+}
+
+func (g *Gateway) endDND(c *gin.Context) {
+	quiet, err := g.serviceManager.QuietHours().EndDND(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "结束免打扰失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "免打扰已结束",
+		"data":    quiet,
+	})
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// 规则修复动作相关处理函数
+func (g *Gateway) listAlertRemediations(c *gin.Context) {
+	id := c.Param("id")
+
+	executions, err := g.serviceManager.Remediation().ListByAlert(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取修复动作执行记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  executions,
+		"total": len(executions),
+	})
+}
+
+func (g *Gateway) approveRemediation(c *gin.Context) {
+	id := c.Param("id")
+
+	execution, err := g.serviceManager.Remediation().Approve(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("execution_id", id).Error("批准修复动作失败")
+		apierror.Respond(c, errorStatus(err), "批准修复动作失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "修复动作已批准，将由后台任务执行",
+		"data":    execution,
+	})
+}
+
+func (g *Gateway) rejectRemediation(c *gin.Context) {
+	id := c.Param("id")
+
+	execution, err := g.serviceManager.Remediation().Reject(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("execution_id", id).Error("拒绝修复动作失败")
+		apierror.Respond(c, errorStatus(err), "拒绝修复动作失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "修复动作已拒绝",
+		"data":    execution,
+	})
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 数据保留相关处理函数，超出保留期的记录由后台 Worker 归档到对象存储后清理
+func (g *Gateway) getRetentionStatus(c *gin.Context) {
+	statuses, err := g.serviceManager.DataRetention().Status(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取数据保留状态失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  statuses,
+		"total": len(statuses),
+	})
+}
+
+func (g *Gateway) listDataArchives(c *gin.Context) {
+	filter := &models.DataArchiveFilter{Page: 1, PageSize: 20}
+	if resourceStr := c.Query("resource"); resourceStr != "" {
+		resource := models.RetentionResource(resourceStr)
+		filter.Resource = &resource
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.To = &to
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	archives, total, err := g.serviceManager.DataRetention().ListArchives(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取归档文件列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  archives,
+		"total": total,
+	})
+}
+
+func (g *Gateway) restoreDataArchive(c *gin.Context) {
+	var req models.DataArchiveRestoreRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	restore, err := g.serviceManager.DataRetention().Restore(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "恢复归档失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "归档已恢复",
+		"data":    restore,
+	})
+}
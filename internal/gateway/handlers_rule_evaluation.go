@@ -0,0 +1,31 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// getRuleEvaluationLag 获取启用规则的计划评估时间与实际评估时间的差距，逾期最久的规则排在最前
+func (g *Gateway) getRuleEvaluationLag(c *gin.Context) {
+	report, err := g.serviceManager.Rule().EvaluationLag(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取规则评估延迟失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// getRuleForecast 立即预测配置了预测的规则何时达到阈值，用于配置预测时预览结果
+func (g *Gateway) getRuleForecast(c *gin.Context) {
+	forecast, err := g.serviceManager.RuleForecast().Forecast(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取规则预测失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": forecast})
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// defaultSLOWindowDays SLO 默认统计最近 30 天
+const defaultSLOWindowDays = 30
+
+// 服务目录相关处理函数
+func (g *Gateway) listCatalogServices(c *gin.Context) {
+	services, err := g.serviceManager.ServiceCatalog().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  services,
+		"total": len(services),
+	})
+}
+
+func (g *Gateway) getCatalogService(c *gin.Context) {
+	service, err := g.serviceManager.ServiceCatalog().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": service,
+	})
+}
+
+func (g *Gateway) createCatalogService(c *gin.Context) {
+	var req models.CatalogServiceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	service, err := g.serviceManager.ServiceCatalog().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建服务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "服务创建成功",
+		"data":    service,
+	})
+}
+
+func (g *Gateway) updateCatalogService(c *gin.Context) {
+	var req models.CatalogServiceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	service, err := g.serviceManager.ServiceCatalog().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新服务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "服务更新成功",
+		"data":    service,
+	})
+}
+
+func (g *Gateway) deleteCatalogService(c *gin.Context) {
+	if err := g.serviceManager.ServiceCatalog().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除服务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "服务删除成功",
+	})
+}
+
+// getCatalogServiceSLO 获取服务最近 days 天的可用性和错误预算
+func (g *Gateway) getCatalogServiceSLO(c *gin.Context) {
+	days := defaultSLOWindowDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 {
+			apierror.Respond(c, http.StatusBadRequest, "统计天数无效", "days 必须是正整数")
+			return
+		}
+		days = parsed
+	}
+
+	slo, err := g.serviceManager.ServiceCatalog().SLO(c.Request.Context(), c.Param("id"), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务SLO失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": slo,
+	})
+}
+
+// getServiceHealth 获取服务当前的健康度
+func (g *Gateway) getServiceHealth(c *gin.Context) {
+	health, err := g.serviceManager.ServiceHealth().Health(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务健康度失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": health,
+	})
+}
+
+// getServiceHealthRollup 获取全部服务的健康度汇总
+func (g *Gateway) getServiceHealthRollup(c *gin.Context) {
+	rollup, err := g.serviceManager.ServiceHealth().Rollup(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务健康度汇总失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rollup,
+	})
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// SLO 相关处理函数
+func (g *Gateway) listSLOs(c *gin.Context) {
+	slos, err := g.serviceManager.SLO().List(c.Request.Context(), c.Query("service_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取SLO列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  slos,
+		"total": len(slos),
+	})
+}
+
+func (g *Gateway) getSLO(c *gin.Context) {
+	slo, err := g.serviceManager.SLO().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取SLO失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": slo,
+	})
+}
+
+func (g *Gateway) createSLO(c *gin.Context) {
+	var req models.SLORequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	slo, err := g.serviceManager.SLO().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建SLO失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "SLO创建成功",
+		"data":    slo,
+	})
+}
+
+func (g *Gateway) updateSLO(c *gin.Context) {
+	var req models.SLORequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	slo, err := g.serviceManager.SLO().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新SLO失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SLO更新成功",
+		"data":    slo,
+	})
+}
+
+func (g *Gateway) deleteSLO(c *gin.Context) {
+	if err := g.serviceManager.SLO().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除SLO失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SLO删除成功",
+	})
+}
+
+// getServiceSLOBudgets 获取服务下全部 SLO 的剩余错误预算
+func (g *Gateway) getServiceSLOBudgets(c *gin.Context) {
+	budgets, err := g.serviceManager.SLO().ServiceBudgets(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取服务错误预算失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": budgets,
+	})
+}
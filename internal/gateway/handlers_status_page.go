@@ -0,0 +1,339 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// statusPageTokenHeader 受令牌保护的状态页也可在该请求头中提供令牌
+const statusPageTokenHeader = "X-Status-Token"
+
+// statusPageTemplate 公开状态页。页面不依赖前端资源，订阅表单直接提交到网关
+var statusPageTemplate = template.Must(template.New("status-page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Page.Title}}</title></head>
+<body>
+<h2>{{.Page.Title}}</h2>
+<p><strong>{{index .States .Page.State}}</strong></p>
+<ul>{{range .Page.Components}}
+<li>{{.Name}}{{if .Description}} - {{.Description}}{{end}}: {{index $.States .State}}</li>{{end}}
+</ul>
+{{with .Page.Incidents}}<h3>{{$.Labels.incidents}}</h3>{{range .}}{{template "incident" .}}{{end}}{{end}}
+{{with .Page.Maintenances}}<h3>{{$.Labels.maintenances}}</h3>{{range .}}{{template "incident" .}}{{end}}{{end}}
+{{with .Page.History}}<h3>{{$.Labels.history}}</h3>{{range .}}{{template "incident" .}}{{end}}{{end}}
+<h3>{{.Labels.subscribe}}</h3>
+<form method="post" action="/status-page/subscribe{{if .Token}}?token={{.Token}}{{end}}">
+<input type="email" name="email" required maxlength="255" placeholder="email@example.com">
+<button type="submit">{{.Labels.subscribe}}</button>
+</form>
+<p><small>{{.Page.GeneratedAt.UTC.Format "2006-01-02 15:04 UTC"}}</small></p>
+</body>
+</html>
+{{define "incident"}}<div>
+<h4>{{.Title}} ({{.Status}})</h4>
+<p>{{range $i, $c := .Components}}{{if $i}}, {{end}}{{$c}}{{end}}{{with .ScheduledStart}} | {{.UTC.Format "2006-01-02 15:04"}}{{end}}{{with .ScheduledEnd}} - {{.UTC.Format "2006-01-02 15:04 UTC"}}{{end}}</p>
+<ul>{{range .Updates}}<li><strong>{{.Status}}</strong> {{.CreatedAt.UTC.Format "2006-01-02 15:04 UTC"}}: {{.Message}}</li>{{end}}</ul>
+</div>{{end}}`))
+
+// statusSubscriptionTemplate 确认和退订页面。与操作链接一样，打开链接只展示订阅，由订阅者提交后才确认或退订
+var statusSubscriptionTemplate = template.Must(template.New("status-subscription").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Pulse</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{with .View}}<p>{{.Email}}</p>
+{{if not .Confirmed}}<form method="post" action="{{$.Path}}/confirm"><button type="submit">{{$.Confirm}}</button></form>{{end}}
+<form method="post" action="{{$.Path}}/unsubscribe"><button type="submit">{{$.Unsubscribe}}</button></form>{{end}}
+</body>
+</html>`))
+
+// statusPageStateLabels 组件状态在状态页上的文字
+var statusPageStateLabels = map[models.StatusComponentState]string{
+	models.StatusComponentOperational:         "运行正常",
+	models.StatusComponentUnderMaintenance:    "维护中",
+	models.StatusComponentDegradedPerformance: "性能下降",
+	models.StatusComponentPartialOutage:       "部分不可用",
+	models.StatusComponentMajorOutage:         "完全不可用",
+}
+
+// statusPageAuthMiddleware 配置了状态页令牌时校验 token 查询参数或 X-Status-Token 请求头
+func (g *Gateway) statusPageAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if g.statusPageToken == "" {
+			c.Next()
+			return
+		}
+		token := c.GetHeader(statusPageTokenHeader)
+		if token == "" {
+			token = c.Query("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(g.statusPageToken)) != 1 {
+			apierror.Respond(c, http.StatusUnauthorized, "状态页令牌无效", models.ErrInvalidToken.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// viewStatusPage 公开状态页，浏览器访问时返回页面，其他客户端返回 JSON。
+// 内容在缓存时长内不变，响应允许浏览器和 CDN 缓存并支持 If-None-Match 条件请求
+func (g *Gateway) viewStatusPage(c *gin.Context) {
+	statusPage := g.serviceManager.StatusPage()
+	page, err := statusPage.Page(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取状态页失败", err.Error())
+		return
+	}
+
+	html := c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+	format := "json"
+	if html {
+		format = "html"
+	}
+	etag := fmt.Sprintf(`W/"%x-%s-%s"`, page.GeneratedAt.UnixNano(), format, apierror.LocaleFromContext(c))
+	// 受令牌保护的状态页不能由共享缓存保存
+	visibility := "public"
+	if g.statusPageToken != "" {
+		visibility = "private"
+	}
+	c.Header("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(statusPage.CacheTTL().Seconds())))
+	c.Header("ETag", etag)
+	c.Header("Vary", "Accept, Accept-Language")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if !html {
+		c.JSON(http.StatusOK, gin.H{
+			"data": page,
+		})
+		return
+	}
+
+	states := make(map[models.StatusComponentState]string, len(statusPageStateLabels))
+	for state, label := range statusPageStateLabels {
+		states[state] = localizeText(c, label)
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = statusPageTemplate.Execute(c.Writer, gin.H{
+		"Page":   page,
+		"States": states,
+		"Token":  c.Query("token"),
+		"Labels": map[string]string{
+			"incidents":    localizeText(c, "当前故障"),
+			"maintenances": localizeText(c, "计划维护"),
+			"history":      localizeText(c, "最近的事件"),
+			"subscribe":    localizeText(c, "订阅邮件通知"),
+		},
+	})
+}
+
+// etagMatches 检查 If-None-Match 是否包含 etag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Gateway) subscribeStatusPage(c *gin.Context) {
+	var req models.StatusSubscribeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		g.respondStatusSubscriptionError(c, models.ErrInvalidInput, "订阅状态页失败")
+		return
+	}
+
+	if err := g.serviceManager.StatusPage().Subscribe(c.Request.Context(), &req); err != nil {
+		g.respondStatusSubscriptionError(c, err, "订阅状态页失败")
+		return
+	}
+
+	g.respondStatusSubscription(c, http.StatusAccepted, nil, "请查收确认邮件，确认后开始接收通知")
+}
+
+func (g *Gateway) viewStatusSubscription(c *gin.Context) {
+	view, err := g.serviceManager.StatusPage().Subscription(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		g.respondStatusSubscriptionError(c, err, "打开订阅链接失败")
+		return
+	}
+
+	g.respondStatusSubscription(c, http.StatusOK, view, "")
+}
+
+func (g *Gateway) confirmStatusSubscription(c *gin.Context) {
+	view, err := g.serviceManager.StatusPage().Confirm(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		g.respondStatusSubscriptionError(c, err, "确认订阅失败")
+		return
+	}
+
+	g.respondStatusSubscription(c, http.StatusOK, view, "订阅已确认")
+}
+
+func (g *Gateway) unsubscribeStatusPage(c *gin.Context) {
+	if err := g.serviceManager.StatusPage().Unsubscribe(c.Request.Context(), c.Param("token")); err != nil {
+		g.respondStatusSubscriptionError(c, err, "退订失败")
+		return
+	}
+
+	g.respondStatusSubscription(c, http.StatusOK, nil, "已退订")
+}
+
+// respondStatusSubscription 浏览器访问时返回页面，其他客户端返回 JSON
+func (g *Gateway) respondStatusSubscription(c *gin.Context, status int, view *models.StatusSubscriptionView, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		body := gin.H{}
+		if message != "" {
+			body["message"] = message
+		}
+		if view != nil {
+			body["data"] = view
+		}
+		c.JSON(status, body)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	data := gin.H{
+		"Path":        "/status-page/subscriptions/" + c.Param("token"),
+		"Confirm":     localizeText(c, "确认订阅"),
+		"Unsubscribe": localizeText(c, "退订"),
+	}
+	if message != "" {
+		data["Message"] = localizeText(c, message)
+	}
+	if view != nil {
+		data["View"] = view
+	}
+	_ = statusSubscriptionTemplate.Execute(c.Writer, data)
+}
+
+func (g *Gateway) respondStatusSubscriptionError(c *gin.Context, err error, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		apierror.Respond(c, errorStatus(err), message, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(errorStatus(err))
+	_ = statusSubscriptionTemplate.Execute(c.Writer, gin.H{
+		"Message": localizeText(c, message) + ": " + localizeText(c, err.Error()),
+	})
+}
+
+// 状态页事件管理相关处理函数
+func (g *Gateway) listStatusIncidents(c *gin.Context) {
+	filter := &models.StatusIncidentFilter{Open: c.Query("open") == "true", Limit: 100}
+	if kind := c.Query("kind"); kind != "" {
+		k := models.StatusIncidentKind(kind)
+		if !k.IsValid() {
+			apierror.Respond(c, http.StatusBadRequest, "请求参数无效", "无效的事件类型: "+kind)
+			return
+		}
+		filter.Kind = &k
+	}
+
+	incidents, err := g.serviceManager.StatusPage().ListIncidents(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取状态页事件列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  incidents,
+		"total": len(incidents),
+	})
+}
+
+func (g *Gateway) getStatusIncident(c *gin.Context) {
+	incident, err := g.serviceManager.StatusPage().GetIncident(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取状态页事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": incident,
+	})
+}
+
+func (g *Gateway) createStatusIncident(c *gin.Context) {
+	var req models.StatusIncidentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	incident, err := g.serviceManager.StatusPage().CreateIncident(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "发布状态页事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "状态页事件发布成功",
+		"data":    incident,
+	})
+}
+
+func (g *Gateway) updateStatusIncident(c *gin.Context) {
+	var req models.StatusIncidentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	incident, err := g.serviceManager.StatusPage().UpdateIncident(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新状态页事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "状态页事件更新成功",
+		"data":    incident,
+	})
+}
+
+func (g *Gateway) postStatusIncidentUpdate(c *gin.Context) {
+	var req models.StatusIncidentUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	incident, err := g.serviceManager.StatusPage().PostUpdate(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "发布状态页事件进展失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "状态页事件进展发布成功",
+		"data":    incident,
+	})
+}
+
+func (g *Gateway) deleteStatusIncident(c *gin.Context) {
+	if err := g.serviceManager.StatusPage().DeleteIncident(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除状态页事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "状态页事件删除成功",
+	})
+}
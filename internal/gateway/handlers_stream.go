@@ -0,0 +1,197 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// ndjsonFlushRows 流式导出每写入多少行刷新一次响应
+const ndjsonFlushRows = 200
+
+// streamNDJSON 以 NDJSON（每行一个 JSON 对象）格式输出 stream 逐条产生的记录，边读取边刷新，不在内存中缓存结果集。
+// 写出第一行前出错时返回普通错误响应；之后响应头已发送，追加一行 {"error": ...} 告知客户端导出不完整
+func (g *Gateway) streamNDJSON(c *gin.Context, message string, stream func(write func(v interface{}) error) error) {
+	// 导出耗时与数据量相关，取消服务端的写超时，客户端断开时写入失败并结束读取
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	encoder := json.NewEncoder(c.Writer)
+	rows := 0
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("X-Content-Type-Options", "nosniff")
+			c.Status(http.StatusOK)
+		}
+	}
+
+	err := stream(func(v interface{}) error {
+		start()
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		rows++
+		if rows%ndjsonFlushRows == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		g.logger.WithError(err).WithField("rows", rows).Error(message)
+		if !started {
+			apierror.Respond(c, errorStatus(err), message, err.Error())
+			return
+		}
+		_ = encoder.Encode(gin.H{"error": message + ": " + err.Error()})
+	}
+
+	start()
+	c.Writer.Flush()
+}
+
+// streamAlerts 流式导出告警，过滤参数与告警列表相同，忽略分页参数
+func (g *Gateway) streamAlerts(c *gin.Context) {
+	filter := parseAlertFilter(c)
+	g.streamNDJSON(c, "导出告警失败", func(write func(v interface{}) error) error {
+		return g.serviceManager.Alert().Stream(c.Request.Context(), filter, func(alert *models.Alert) error {
+			return write(alert)
+		})
+	})
+}
+
+// streamTickets 流式导出工单
+func (g *Gateway) streamTickets(c *gin.Context) {
+	filter := &models.TicketFilter{}
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.TicketStatus(statusStr)
+		if status.IsValid() {
+			filter.Status = &status
+		}
+	}
+
+	if priorityStr := c.Query("priority"); priorityStr != "" {
+		priority := models.TicketPriority(priorityStr)
+		if priority.IsValid() {
+			filter.Priority = &priority
+		}
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		ticketType := models.TicketType(typeStr)
+		if ticketType.IsValid() {
+			filter.Type = &ticketType
+		}
+	}
+
+	if sourceStr := c.Query("source"); sourceStr != "" {
+		source := models.TicketSource(sourceStr)
+		if source.IsValid() {
+			filter.Source = &source
+		}
+	}
+
+	if category := c.Query("category"); category != "" {
+		filter.Category = &category
+	}
+
+	if reporterID := c.Query("reporter_id"); reporterID != "" {
+		filter.ReporterID = &reporterID
+	}
+
+	if assigneeID := c.Query("assignee_id"); assigneeID != "" {
+		filter.AssigneeID = &assigneeID
+	}
+
+	if keyword := c.Query("keyword"); keyword != "" {
+		filter.Keyword = &keyword
+	}
+
+	if createdStartStr := c.Query("created_start"); createdStartStr != "" {
+		createdStart, err := time.Parse(time.RFC3339, createdStartStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.CreatedStart = &createdStart
+	}
+
+	if createdEndStr := c.Query("created_end"); createdEndStr != "" {
+		createdEnd, err := time.Parse(time.RFC3339, createdEndStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.CreatedEnd = &createdEnd
+	}
+
+	if query := c.Query("q"); query != "" {
+		filter.Query = &query
+	}
+
+	g.streamNDJSON(c, "导出工单失败", func(write func(v interface{}) error) error {
+		return g.serviceManager.Ticket().Stream(c.Request.Context(), filter, func(ticket *models.Ticket) error {
+			return write(ticket)
+		})
+	})
+}
+
+// streamAuditLogs 流式导出审计日志
+func (g *Gateway) streamAuditLogs(c *gin.Context) {
+	filter := &models.AuditLogFilter{}
+	if userID := c.Query("user_id"); userID != "" {
+		filter.UserID = &userID
+	}
+
+	if action := c.Query("action"); action != "" {
+		filter.Action = &action
+	}
+
+	if resourceType := c.Query("resource_type"); resourceType != "" {
+		filter.ResourceType = &resourceType
+	}
+
+	if resourceID := c.Query("resource_id"); resourceID != "" {
+		filter.ResourceID = &resourceID
+	}
+
+	if successStr := c.Query("success"); successStr != "" {
+		success, err := strconv.ParseBool(successStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "success 参数无效", err.Error())
+			return
+		}
+		filter.Success = &success
+	}
+
+	if startTimeStr := c.Query("start_time"); startTimeStr != "" {
+		startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.StartTime = &startTime
+	}
+
+	if endTimeStr := c.Query("end_time"); endTimeStr != "" {
+		endTime, err := time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.EndTime = &endTime
+	}
+
+	g.streamNDJSON(c, "导出审计日志失败", func(write func(v interface{}) error) error {
+		return g.serviceManager.AuditLog().Stream(c.Request.Context(), filter, func(log *models.AuditLog) error {
+			return write(log)
+		})
+	})
+}
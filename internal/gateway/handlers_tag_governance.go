@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 标签治理相关处理函数，重命名、合并和删除标签的任务涉及大量记录，由后台 Worker 分批执行
+func (g *Gateway) listTagUsage(c *gin.Context) {
+	var maxUsage int64
+	if maxUsageStr := c.Query("max_usage"); maxUsageStr != "" {
+		parsed, err := strconv.ParseInt(maxUsageStr, 10, 64)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "请求参数验证失败", "max_usage 必须为整数")
+			return
+		}
+		maxUsage = parsed
+	}
+
+	usage, err := g.serviceManager.TagGovernance().Usage(c.Request.Context(), maxUsage)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签使用情况失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  usage,
+		"total": len(usage),
+	})
+}
+
+func (g *Gateway) createTagJob(c *gin.Context) {
+	var req models.TagJobRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	job, err := g.serviceManager.TagGovernance().CreateJob(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建标签治理任务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "标签治理任务已创建",
+		"data":    job,
+	})
+}
+
+func (g *Gateway) listTagJobs(c *gin.Context) {
+	jobs, err := g.serviceManager.TagGovernance().ListJobs(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签治理任务列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  jobs,
+		"total": len(jobs),
+	})
+}
+
+func (g *Gateway) getTagJob(c *gin.Context) {
+	job, err := g.serviceManager.TagGovernance().GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取标签治理任务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": job,
+	})
+}
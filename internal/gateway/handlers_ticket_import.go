@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 工单导入相关处理函数
+func (g *Gateway) uploadTicketImport(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请上传导入文件", err.Error())
+		return
+	}
+	if fileHeader.Size > models.TicketImportMaxSize {
+		apierror.Respond(c, http.StatusRequestEntityTooLarge, "导入文件过大", fmt.Sprintf("导入文件不能超过 %d MB", models.TicketImportMaxSize>>20))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取导入文件失败", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.TicketImportMaxSize))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取导入文件失败", err.Error())
+		return
+	}
+
+	job, err := g.serviceManager.TicketImport().Upload(c.Request.Context(), fileHeader.Filename, data, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "上传工单导入文件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "工单导入文件已上传，请确认列映射后开始导入",
+		"data":    job,
+	})
+}
+
+func (g *Gateway) startTicketImport(c *gin.Context) {
+	// 请求体可以为空，此时使用上传时推荐的列映射
+	var req models.TicketImportStartRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	job, err := g.serviceManager.TicketImport().Start(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "开始工单导入失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "工单导入任务已开始",
+		"data":    job,
+	})
+}
+
+func (g *Gateway) listTicketImports(c *gin.Context) {
+	jobs, err := g.serviceManager.TicketImport().ListJobs(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单导入任务列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  jobs,
+		"total": len(jobs),
+	})
+}
+
+func (g *Gateway) getTicketImport(c *gin.Context) {
+	job, err := g.serviceManager.TicketImport().GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单导入任务失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": job,
+	})
+}
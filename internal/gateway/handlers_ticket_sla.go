@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 工单SLA暂停相关处理函数
+func (g *Gateway) getTicketSLAClock(c *gin.Context) {
+	clock, err := g.serviceManager.TicketSLA().Clock(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单SLA状态失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": clock,
+	})
+}
+
+func (g *Gateway) pauseTicketSLA(c *gin.Context) {
+	ticketID := c.Param("id")
+
+	var req models.TicketSLAPauseRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	pause, err := g.serviceManager.TicketSLA().Pause(c.Request.Context(), ticketID, c.GetString("user_id"), &req)
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("暂停工单SLA失败")
+		apierror.Respond(c, errorStatus(err), "暂停工单SLA失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "SLA计时已暂停",
+		"data":    pause,
+	})
+}
+
+func (g *Gateway) resumeTicketSLA(c *gin.Context) {
+	ticketID := c.Param("id")
+
+	pause, err := g.serviceManager.TicketSLA().Resume(c.Request.Context(), ticketID, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("恢复工单SLA失败")
+		apierror.Respond(c, errorStatus(err), "恢复工单SLA失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SLA计时已恢复",
+		"data":    pause,
+	})
+}
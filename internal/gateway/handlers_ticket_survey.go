@@ -0,0 +1,126 @@
+package gateway
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// ticketSurveyPage 评价页面。与操作链接一样，打开链接只展示表单，由报告人提交后才记录评价
+var ticketSurveyPage = template.Must(template.New("ticket-survey").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Pulse</title></head>
+<body>
+{{if .Error}}<p>{{.Error}}</p>{{else}}{{with .View}}
+<h3>{{.TicketNumber}} {{.TicketTitle}}</h3>
+{{if .RespondedAt}}<p>{{$.Done}}</p>{{else}}
+<form method="post">
+<p>{{range $.Scores}}<label><input type="radio" name="score" value="{{.}}" required> {{.}}</label> {{end}}</p>
+<p><textarea name="comment" rows="4" cols="40" maxlength="2000" placeholder="{{$.Comment}}"></textarea></p>
+<button type="submit">{{$.Label}}</button>
+</form>{{end}}
+{{end}}{{end}}
+</body>
+</html>`))
+
+// 工单满意度调查相关处理函数
+func (g *Gateway) viewTicketSurvey(c *gin.Context) {
+	view, err := g.serviceManager.TicketSurvey().View(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		g.respondTicketSurveyError(c, err, "打开评价链接失败")
+		return
+	}
+
+	g.respondTicketSurvey(c, view)
+}
+
+func (g *Gateway) respondTicketSurveyForm(c *gin.Context) {
+	var req models.TicketSurveyResponse
+	if err := c.ShouldBind(&req); err != nil {
+		g.respondTicketSurveyError(c, models.ErrInvalidInput, "提交评价失败")
+		return
+	}
+
+	view, err := g.serviceManager.TicketSurvey().Respond(c.Request.Context(), c.Param("token"), &req)
+	if err != nil {
+		g.respondTicketSurveyError(c, err, "提交评价失败")
+		return
+	}
+
+	g.respondTicketSurvey(c, view)
+}
+
+// respondTicketSurvey 浏览器访问时返回页面，其他客户端返回 JSON
+func (g *Gateway) respondTicketSurvey(c *gin.Context, view *models.TicketSurveyView) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		c.JSON(http.StatusOK, gin.H{
+			"data": view,
+		})
+		return
+	}
+
+	scores := make([]int, 0, models.TicketSurveyMaxScore)
+	for score := models.TicketSurveyMinScore; score <= models.TicketSurveyMaxScore; score++ {
+		scores = append(scores, score)
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = ticketSurveyPage.Execute(c.Writer, gin.H{
+		"View":    view,
+		"Scores":  scores,
+		"Comment": localizeText(c, "评价意见（可选）"),
+		"Label":   localizeText(c, "提交评价"),
+		"Done":    localizeText(c, "感谢您的评价"),
+	})
+}
+
+func (g *Gateway) respondTicketSurveyError(c *gin.Context, err error, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		apierror.Respond(c, errorStatus(err), message, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(errorStatus(err))
+	_ = ticketSurveyPage.Execute(c.Writer, gin.H{
+		"Error": localizeText(c, message) + ": " + localizeText(c, err.Error()),
+	})
+}
+
+func (g *Gateway) getTicketCSAT(c *gin.Context) {
+	filter := &models.TicketSurveyFilter{GroupBy: models.TicketSurveyGroupBy(c.Query("group_by"))}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.From = from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.To = to
+	}
+	if team := c.Query("team"); team != "" {
+		filter.Team = &team
+	}
+
+	report, err := g.serviceManager.TicketSurvey().CSAT(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取工单满意度统计失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
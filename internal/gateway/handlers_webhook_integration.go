@@ -0,0 +1,341 @@
+package gateway
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/pkg/validation"
+)
+
+// maxIngestBodySize 告警接入请求体大小上限
+const maxIngestBodySize = 1 << 20
+
+// 告警接入集成相关处理函数
+func (g *Gateway) listWebhookIntegrations(c *gin.Context) {
+	integrations, err := g.serviceManager.WebhookIntegration().List(c.Request.Context())
+	if err != nil {
+		g.logger.WithError(err).Error("获取Webhook接入列表失败")
+		apierror.Respond(c, errorStatus(err), "获取Webhook接入列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"integrations": integrations,
+		"total":        len(integrations),
+	})
+}
+
+func (g *Gateway) createWebhookIntegration(c *gin.Context) {
+	var req models.WebhookIntegrationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	integration := &models.WebhookIntegration{Enabled: true}
+	req.ApplyTo(integration)
+	if userID := c.GetString("user_id"); userID != "" {
+		integration.CreatedBy = &userID
+	}
+
+	result, err := g.serviceManager.WebhookIntegration().Create(c.Request.Context(), integration)
+	if err != nil {
+		g.logger.WithError(err).WithField("name", integration.Name).Error("创建Webhook接入失败")
+		apierror.Respond(c, errorStatus(err), "创建Webhook接入失败", err.Error())
+		return
+	}
+
+	// 密钥只在创建时返回一次
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook接入创建成功",
+		"data":    result,
+	})
+}
+
+func (g *Gateway) getWebhookIntegration(c *gin.Context) {
+	id := c.Param("id")
+
+	integration, err := g.serviceManager.WebhookIntegration().Get(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取Webhook接入失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": integration,
+	})
+}
+
+func (g *Gateway) updateWebhookIntegration(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.WebhookIntegrationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	integration, err := g.serviceManager.WebhookIntegration().Get(c.Request.Context(), id)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取Webhook接入失败", err.Error())
+		return
+	}
+
+	req.ApplyTo(integration)
+	if err := g.serviceManager.WebhookIntegration().Update(c.Request.Context(), integration); err != nil {
+		g.logger.WithError(err).WithField("integration_id", id).Error("更新Webhook接入失败")
+		apierror.Respond(c, errorStatus(err), "更新Webhook接入失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook接入更新成功",
+		"data":    integration,
+	})
+}
+
+func (g *Gateway) deleteWebhookIntegration(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := g.serviceManager.WebhookIntegration().Delete(c.Request.Context(), id); err != nil {
+		g.logger.WithError(err).WithField("integration_id", id).Error("删除Webhook接入失败")
+		apierror.Respond(c, errorStatus(err), "删除Webhook接入失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook接入删除成功",
+	})
+}
+
+func (g *Gateway) rotateWebhookIntegrationSecret(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := g.serviceManager.WebhookIntegration().RotateSecret(c.Request.Context(), id)
+	if err != nil {
+		g.logger.WithError(err).WithField("integration_id", id).Error("重置Webhook接入密钥失败")
+		apierror.Respond(c, errorStatus(err), "重置Webhook接入密钥失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook接入密钥已重置",
+		"data":    result,
+	})
+}
+
+// ingestAlert 接收外部系统推送的告警，请求须使用集成密钥签名
+func (g *Gateway) ingestAlert(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		apierror.Respond(c, http.StatusBadRequest, "Webhook接入ID不能为空", nil)
+		return
+	}
+
+	// 签名基于原始请求体计算，先完整读取再解析
+	body, ok := readIngestBody(c)
+	if !ok {
+		return
+	}
+	if g.maintenance.Enabled() {
+		g.bufferIngest(c, &models.BufferedIngest{
+			Kind:          models.BufferedIngestAlert,
+			IntegrationID: id,
+			Signature:     c.GetHeader(signature.HeaderSignature),
+			Timestamp:     c.GetHeader(signature.HeaderTimestamp),
+			Body:          body,
+		})
+		return
+	}
+
+	integration, err := g.serviceManager.WebhookIntegration().Verify(c.Request.Context(), id, c.ClientIP(),
+		c.GetHeader(signature.HeaderSignature), c.GetHeader(signature.HeaderTimestamp), body)
+	if err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).WithField("integration_id", id).Error("Webhook请求校验失败")
+		}
+		apierror.Respond(c, status, "Webhook请求校验失败", err.Error())
+		return
+	}
+
+	// 未指定来源时使用集成配置的来源
+	// 签名校验通过后的失败均计入接入失败，便于自监控发现上游推送格式异常；格式错误的载荷记入集成的拒绝记录
+	req := models.AlertCreateRequest{Source: integration.Source}
+	rejections := g.serviceManager.IngestRejection()
+	if err := decodeIngestAlert(body, rejections.Mode(), &req); err != nil {
+		g.recordIngest(false)
+		rejections.Record(c.Request.Context(), integration.ID, models.IngestEndpointAlerts, c.ClientIP(), body, err)
+		respondIngestSchemaError(c, err)
+		return
+	}
+
+	alert := newIngestedAlert(&req, integration)
+	if err := g.serviceManager.Alert().Create(c.Request.Context(), alert); err != nil {
+		g.recordIngest(false)
+		g.logger.WithError(err).WithField("integration_id", id).Error("创建告警失败")
+		apierror.Respond(c, errorStatus(err), "创建告警失败", err.Error())
+		return
+	}
+	g.recordIngest(true)
+
+	g.logger.WithField("alert_id", alert.ID).WithField("integration_id", id).Info("接入告警创建成功")
+	c.JSON(http.StatusCreated, alert)
+}
+
+// ingestCustomWebhook 接收任意结构的载荷，按集成配置的载荷映射转换为告警。
+// 请求可以使用集成密钥签名，也可以在 Authorization: Bearer 头或 token 查询参数中直接携带集成密钥
+func (g *Gateway) ingestCustomWebhook(c *gin.Context) {
+	id := c.Param("integrationID")
+
+	body, ok := readIngestBody(c)
+	if !ok {
+		return
+	}
+
+	signatureHeader := c.GetHeader(signature.HeaderSignature)
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if g.maintenance.Enabled() {
+		g.bufferIngest(c, &models.BufferedIngest{
+			Kind:          models.BufferedIngestCustom,
+			IntegrationID: id,
+			Signature:     signatureHeader,
+			Timestamp:     c.GetHeader(signature.HeaderTimestamp),
+			Token:         token,
+			Body:          body,
+		})
+		return
+	}
+
+	integrations := g.serviceManager.WebhookIntegration()
+	var integration *models.WebhookIntegration
+	var err error
+	if signatureHeader != "" {
+		integration, err = integrations.Verify(c.Request.Context(), id, c.ClientIP(),
+			signatureHeader, c.GetHeader(signature.HeaderTimestamp), body)
+	} else {
+		integration, err = integrations.VerifyToken(c.Request.Context(), id, c.ClientIP(), token)
+	}
+	if err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).WithField("integration_id", id).Error("Webhook请求校验失败")
+		}
+		apierror.Respond(c, status, "Webhook请求校验失败", err.Error())
+		return
+	}
+
+	rejections := g.serviceManager.IngestRejection()
+	result, err := integrations.IngestCustom(c.Request.Context(), integration, body)
+	if err != nil {
+		g.recordIngest(false)
+		rejections.Record(c.Request.Context(), integration.ID, models.IngestEndpointCustom, c.ClientIP(), body, err)
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			g.logger.WithError(err).WithField("integration_id", id).Error("处理自定义Webhook载荷失败")
+		}
+		apierror.Respond(c, status, "处理自定义Webhook载荷失败", err.Error())
+		return
+	}
+	g.recordIngest(len(result.Errors) == 0)
+	if len(result.Errors) > 0 {
+		// 宽松模式下部分告警转换失败时载荷仍被接受，失败的条目同样记入拒绝记录
+		rejections.Record(c.Request.Context(), integration.ID, models.IngestEndpointCustom, c.ClientIP(), body,
+			models.NewIngestSchemaError(models.IngestRejectMappingError, "%s", strings.Join(result.Errors, "; ")))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook载荷处理完成",
+		"data":    result,
+	})
+}
+
+// previewWebhookIntegrationMapping 使用示例载荷预览载荷映射的转换结果
+func (g *Gateway) previewWebhookIntegrationMapping(c *gin.Context) {
+	var req models.WebhookMappingPreviewRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	preview, err := g.serviceManager.WebhookIntegration().PreviewMapping(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "预览载荷映射失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": preview,
+	})
+}
+
+// getWebhookIntegrationRejections 获取集成最近被拒绝的接入请求和按原因的拒绝次数，用于排查上游推送格式异常
+func (g *Gateway) getWebhookIntegrationRejections(c *gin.Context) {
+	var reason *models.IngestRejectReason
+	if value := c.Query("reason"); value != "" {
+		r := models.IngestRejectReason(value)
+		reason = &r
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	report, err := g.serviceManager.IngestRejection().Report(c.Request.Context(), c.Param("id"), reason, limit)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取接入拒绝记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// respondIngestSchemaError 返回接入载荷校验错误，字段校验失败时返回字段级错误
+func respondIngestSchemaError(c *gin.Context, err error) {
+	if fields := validation.FieldErrors(err, apierror.LocaleFromContext(c)); len(fields) > 0 {
+		apierror.RespondCode(c, apierror.CodeValidationFailed, "请求数据验证失败", fields)
+		return
+	}
+	var schemaErr *models.IngestSchemaError
+	if errors.As(err, &schemaErr) && schemaErr.Reason == models.IngestRejectInvalidValue {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", schemaErr.Detail)
+		return
+	}
+	apierror.Respond(c, http.StatusBadRequest, "请求参数无效", err.Error())
+}
+
+// newIngestedAlert 根据接入请求创建告警，注解中记录来源集成
+func newIngestedAlert(req *models.AlertCreateRequest, integration *models.WebhookIntegration) *models.Alert {
+	alert := newAlertFromRequest(req)
+	if alert.Annotations == nil {
+		alert.Annotations = map[string]string{}
+	}
+	alert.Annotations["integration_id"] = integration.ID
+	return alert
+}
+
+// readIngestBody 读取接入请求体，超过大小上限时返回 413
+func readIngestBody(c *gin.Context) ([]byte, bool) {
+	return readLimitedBody(c, maxIngestBodySize)
+}
+
+// readLimitedBody 读取请求体，超过 limit 字节时返回 413
+func readLimitedBody(c *gin.Context, limit int) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "读取请求体失败", err.Error())
+		return nil, false
+	}
+	if len(body) > limit {
+		apierror.RespondCode(c, apierror.CodePayloadTooLarge, "", gin.H{"max_bytes": limit})
+		return nil, false
+	}
+	return body, true
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 出站 Webhook 订阅相关处理函数
+func (g *Gateway) listWebhookSubscriptions(c *gin.Context) {
+	subscriptions, err := g.serviceManager.WebhookSubscription().List(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取Webhook订阅列表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  subscriptions,
+		"total": len(subscriptions),
+	})
+}
+
+func (g *Gateway) createWebhookSubscription(c *gin.Context) {
+	var req models.WebhookSubscriptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := g.serviceManager.WebhookSubscription().Create(c.Request.Context(), &req, c.GetString("user_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "创建Webhook订阅失败", err.Error())
+		return
+	}
+
+	// 签名密钥只在创建时返回一次
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook订阅创建成功",
+		"data":    result,
+	})
+}
+
+func (g *Gateway) getWebhookSubscription(c *gin.Context) {
+	subscription, err := g.serviceManager.WebhookSubscription().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取Webhook订阅失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": subscription,
+	})
+}
+
+func (g *Gateway) updateWebhookSubscription(c *gin.Context) {
+	var req models.WebhookSubscriptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subscription, err := g.serviceManager.WebhookSubscription().Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "更新Webhook订阅失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook订阅更新成功",
+		"data":    subscription,
+	})
+}
+
+func (g *Gateway) deleteWebhookSubscription(c *gin.Context) {
+	if err := g.serviceManager.WebhookSubscription().Delete(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Respond(c, errorStatus(err), "删除Webhook订阅失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook订阅删除成功",
+	})
+}
+
+func (g *Gateway) rotateWebhookSubscriptionSecret(c *gin.Context) {
+	result, err := g.serviceManager.WebhookSubscription().RotateSecret(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "重置Webhook订阅密钥失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook订阅密钥已重置",
+		"data":    result,
+	})
+}
+
+// listWebhookDeliveries 订阅的投递记录，用于排查接收端没有收到事件的问题
+func (g *Gateway) listWebhookDeliveries(c *gin.Context) {
+	filter := &models.WebhookDeliveryFilter{Page: 1, PageSize: 20}
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.WebhookDeliveryStatus(statusStr)
+		filter.Status = &status
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	deliveries, total, err := g.serviceManager.WebhookSubscription().ListDeliveries(c.Request.Context(), c.Param("id"), filter)
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "获取Webhook投递记录失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"total": total,
+	})
+}
+
+func (g *Gateway) redeliverWebhookDelivery(c *gin.Context) {
+	delivery, err := g.serviceManager.WebhookSubscription().Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		apierror.Respond(c, errorStatus(err), "重新投递Webhook事件失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Webhook事件已重新加入投递队列",
+		"data":    delivery,
+	})
+}
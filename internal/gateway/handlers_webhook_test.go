@@ -42,6 +42,238 @@ func (m *MockServiceManager) Config() service.ConfigService {
 	return nil
 }
 
+func (m *MockServiceManager) CustomField() service.CustomFieldService {
+	return nil
+}
+
+func (m *MockServiceManager) AttachmentScan() service.AttachmentScanService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeReview() service.KnowledgeReviewService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeComment() service.KnowledgeCommentService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeSchedule() service.KnowledgeScheduleService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeTransfer() service.KnowledgeTransferService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeACL() service.KnowledgeACLService {
+	return nil
+}
+
+func (m *MockServiceManager) WebhookIntegration() service.WebhookIntegrationService {
+	return nil
+}
+
+func (m *MockServiceManager) KeyRotation() service.KeyRotationService {
+	return nil
+}
+
+func (m *MockServiceManager) FeatureFlag() service.FeatureFlagService {
+	return nil
+}
+
+func (m *MockServiceManager) Dashboard() service.DashboardService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertTimeline() service.AlertTimelineService {
+	return nil
+}
+
+func (m *MockServiceManager) Postmortem() service.PostmortemService {
+	return nil
+}
+
+func (m *MockServiceManager) Remediation() service.RemediationService {
+	return nil
+}
+
+func (m *MockServiceManager) ChatOps() service.ChatOpsService {
+	return nil
+}
+
+func (m *MockServiceManager) ITSM() service.ITSMService {
+	return nil
+}
+
+func (m *MockServiceManager) Heartbeat() service.HeartbeatService {
+	return nil
+}
+
+func (m *MockServiceManager) Kubernetes() service.KubernetesService {
+	return nil
+}
+
+func (m *MockServiceManager) ServiceCatalog() service.ServiceCatalogService {
+	return nil
+}
+
+func (m *MockServiceManager) SLO() service.SLOService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertAutoResolve() service.AlertAutoResolveService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertSnooze() service.AlertSnoozeService {
+	return nil
+}
+
+func (m *MockServiceManager) NotificationCalendar() service.NotificationCalendarService {
+	return nil
+}
+
+func (m *MockServiceManager) Localization() service.LocalizationService {
+	return nil
+}
+
+func (m *MockServiceManager) TagGovernance() service.TagGovernanceService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertPartition() service.AlertPartitionService {
+	return nil
+}
+
+func (m *MockServiceManager) DataRetention() service.DataRetentionService {
+	return nil
+}
+
+func (m *MockServiceManager) AuditLog() service.AuditLogService {
+	return nil
+}
+
+func (m *MockServiceManager) Impersonation() service.ImpersonationService {
+	return nil
+}
+
+func (m *MockServiceManager) Metric() service.MetricService {
+	return nil
+}
+
+func (m *MockServiceManager) TicketSLA() service.TicketSLAService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertSeverityRule() service.AlertSeverityRuleService {
+	return nil
+}
+
+func (m *MockServiceManager) StatusPage() service.StatusPageService {
+	return nil
+}
+
+func (m *MockServiceManager) ServiceHealth() service.ServiceHealthService {
+	return nil
+}
+
+func (m *MockServiceManager) RuleForecast() service.RuleForecastService {
+	return nil
+}
+
+func (m *MockServiceManager) ConfigApply() service.ConfigApplyService {
+	return nil
+}
+
+func (m *MockServiceManager) Backup() service.BackupService {
+	return nil
+}
+
+func (m *MockServiceManager) Assignment() service.AssignmentService {
+	return nil
+}
+
+func (m *MockServiceManager) Handover() service.HandoverService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertActionLink() service.AlertActionLinkService {
+	return nil
+}
+
+func (m *MockServiceManager) Push() service.PushService {
+	return nil
+}
+
+func (m *MockServiceManager) Inbox() service.InboxService {
+	return nil
+}
+
+func (m *MockServiceManager) QuietHours() service.QuietHoursService {
+	return nil
+}
+
+func (m *MockServiceManager) ChangeEvent() service.ChangeEventService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertReplay() service.AlertReplayService {
+	return nil
+}
+
+func (m *MockServiceManager) WebhookSubscription() service.WebhookSubscriptionService {
+	return nil
+}
+
+func (m *MockServiceManager) GraphQL() service.GraphQLService {
+	return nil
+}
+
+func (m *MockServiceManager) TicketImport() service.TicketImportService {
+	return nil
+}
+
+func (m *MockServiceManager) AlertStats() service.AlertStatsService {
+	return nil
+}
+
+func (m *MockServiceManager) LabelCardinality() service.LabelCardinalityService {
+	return nil
+}
+
+func (m *MockServiceManager) LabelAutocomplete() service.LabelAutocompleteService {
+	return nil
+}
+
+func (m *MockServiceManager) TicketSurvey() service.TicketSurveyService {
+	return nil
+}
+
+func (m *MockServiceManager) KnowledgeFeedback() service.KnowledgeFeedbackService {
+	return nil
+}
+
+func (m *MockServiceManager) Approval() service.ApprovalService {
+	return nil
+}
+
+func (m *MockServiceManager) IngestRejection() service.IngestRejectionService {
+	return nil
+}
+
+func (m *MockServiceManager) AsyncTask() service.AsyncTaskService {
+	return nil
+}
+
+func (m *MockServiceManager) QueryCache() service.QueryCacheService {
+	return nil
+}
+
+func (m *MockServiceManager) NotificationChannel() service.NotificationChannelService {
+	return nil
+}
+
 func (m *MockServiceManager) User() service.UserService {
 	return nil
 }
@@ -189,7 +421,7 @@ func TestListWebhooks(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "获取Webhook列表失败")
+		assert.Contains(t, response["message"], "获取Webhook列表失败")
 
 		mockService.AssertExpectations(t)
 	})
@@ -239,7 +471,24 @@ func TestCreateWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "请求参数无效")
+		assert.Contains(t, response["message"], "请求参数无效")
+	})
+
+	t.Run("按Accept-Language返回英文错误", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9,zh-CN;q=0.8")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "en-US", w.Header().Get("Content-Language"))
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "INVALID_REQUEST", response["code"])
+		assert.Equal(t, "Invalid request parameters", response["message"])
 	})
 
 	t.Run("服务层返回错误", func(t *testing.T) {
@@ -262,7 +511,9 @@ func TestCreateWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "创建Webhook失败", response["error"])
+		assert.Equal(t, "INTERNAL_ERROR", response["code"])
+		assert.Equal(t, "创建Webhook失败", response["message"])
+		assert.Equal(t, "创建失败", response["details"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -314,7 +565,7 @@ func TestGetWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "Webhook不存在")
+		assert.Contains(t, response["message"], "Webhook不存在")
 
 		mockService.AssertExpectations(t)
 	})
@@ -334,7 +585,7 @@ func TestGetWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "获取Webhook详情失败")
+		assert.Contains(t, response["message"], "获取Webhook详情失败")
 	})
 }
 
@@ -384,7 +635,7 @@ func TestUpdateWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "Webhook ID格式无效")
+		assert.Contains(t, response["message"], "Webhook ID格式无效")
 	})
 }
 
@@ -423,7 +674,7 @@ func TestDeleteWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "删除Webhook失败")
+		assert.Contains(t, response["message"], "删除Webhook失败")
 	})
 }
 
@@ -497,7 +748,7 @@ func TestTriggerWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "触发Webhook失败")
+		assert.Contains(t, response["message"], "触发Webhook失败")
 	})
 
 	t.Run("触发失败", func(t *testing.T) {
@@ -520,7 +771,7 @@ func TestTriggerWebhook(t *testing.T) {
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response["error"], "触发Webhook失败")
+		assert.Contains(t, response["message"], "触发Webhook失败")
 
 		mockService.AssertExpectations(t)
 	})
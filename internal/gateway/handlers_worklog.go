@@ -0,0 +1,210 @@
+package gateway
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+// 工作日志相关处理函数
+func (g *Gateway) listTicketWorklogs(c *gin.Context) {
+	// 获取工单ID
+	ticketID := c.Param("id")
+	if ticketID == "" {
+		apierror.Respond(c, http.StatusBadRequest, "工单ID不能为空", "请提供有效的工单ID")
+		return
+	}
+
+	worklogs, workTime, err := g.serviceManager.Ticket().GetWorklogs(c.Request.Context(), ticketID)
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("获取工作日志失败")
+		apierror.Respond(c, errorStatus(err), "获取工作日志失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"worklogs":  worklogs,
+		"work_time": workTime,
+	})
+}
+
+func (g *Gateway) createTicketWorklog(c *gin.Context) {
+	// 获取工单ID
+	ticketID := c.Param("id")
+	if ticketID == "" {
+		apierror.Respond(c, http.StatusBadRequest, "工单ID不能为空", "请提供有效的工单ID")
+		return
+	}
+
+	// 解析请求体
+	var req models.TicketWorklogRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 验证请求数据
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	worklog := &models.TicketWorklog{
+		TicketID:        ticketID,
+		UserID:          c.GetString("user_id"),
+		Description:     req.Description,
+		DurationMinutes: req.DurationMinutes,
+		Billable:        true,
+	}
+	applyWorklogRequest(worklog, &req)
+
+	workTime, err := g.serviceManager.Ticket().AddWorklog(c.Request.Context(), worklog)
+	if err != nil {
+		g.logger.WithError(err).WithField("ticket_id", ticketID).Error("登记工作日志失败")
+		apierror.Respond(c, errorStatus(err), "登记工作日志失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("worklog_id", worklog.ID).WithField("ticket_id", ticketID).Info("工作日志登记成功")
+	c.JSON(http.StatusCreated, gin.H{
+		"data":      worklog,
+		"work_time": workTime,
+	})
+}
+
+func (g *Gateway) updateTicketWorklog(c *gin.Context) {
+	// 获取工单ID和工作日志ID，工作日志不属于该工单时返回 404
+	ticketID := c.Param("id")
+	worklogID := c.Param("worklog_id")
+	if worklogID == "" {
+		apierror.Respond(c, http.StatusBadRequest, "工作日志ID不能为空", "请提供有效的工作日志ID")
+		return
+	}
+
+	// 解析请求体
+	var req models.TicketWorklogRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 验证请求数据
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	worklog := &models.TicketWorklog{
+		ID:              worklogID,
+		TicketID:        ticketID,
+		UserID:          c.GetString("user_id"),
+		Description:     req.Description,
+		DurationMinutes: req.DurationMinutes,
+		Billable:        true,
+	}
+	applyWorklogRequest(worklog, &req)
+
+	workTime, err := g.serviceManager.Ticket().UpdateWorklog(c.Request.Context(), worklog)
+	if err != nil {
+		g.logger.WithError(err).WithField("worklog_id", worklogID).Error("更新工作日志失败")
+		apierror.Respond(c, errorStatus(err), "更新工作日志失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("worklog_id", worklogID).Info("工作日志更新成功")
+	c.JSON(http.StatusOK, gin.H{
+		"data":      worklog,
+		"work_time": workTime,
+	})
+}
+
+func (g *Gateway) deleteTicketWorklog(c *gin.Context) {
+	// 获取工单ID和工作日志ID，工作日志不属于该工单时返回 404
+	ticketID := c.Param("id")
+	worklogID := c.Param("worklog_id")
+	if worklogID == "" {
+		apierror.Respond(c, http.StatusBadRequest, "工作日志ID不能为空", "请提供有效的工作日志ID")
+		return
+	}
+
+	workTime, err := g.serviceManager.Ticket().DeleteWorklog(c.Request.Context(), ticketID, worklogID, c.GetString("user_id"))
+	if err != nil {
+		g.logger.WithError(err).WithField("worklog_id", worklogID).Error("删除工作日志失败")
+		apierror.Respond(c, errorStatus(err), "删除工作日志失败", err.Error())
+		return
+	}
+
+	g.logger.WithField("worklog_id", worklogID).Info("工作日志删除成功")
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "工作日志删除成功",
+		"id":        worklogID,
+		"work_time": workTime,
+	})
+}
+
+func (g *Gateway) getTimesheet(c *gin.Context) {
+	// 默认统计最近7天
+	now := time.Now()
+	filter := &models.TimesheetFilter{
+		StartTime: now.AddDate(0, 0, -7),
+		EndTime:   now,
+		GroupBy:   c.DefaultQuery("group_by", "user"),
+	}
+
+	// 解析时间范围
+	if startTimeStr := c.Query("start_time"); startTimeStr != "" {
+		startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "开始时间格式无效", err.Error())
+			return
+		}
+		filter.StartTime = startTime
+	}
+
+	if endTimeStr := c.Query("end_time"); endTimeStr != "" {
+		endTime, err := time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "结束时间格式无效", err.Error())
+			return
+		}
+		filter.EndTime = endTime
+	}
+
+	// 解析过滤参数
+	if userID := c.Query("user_id"); userID != "" {
+		filter.UserID = &userID
+	}
+
+	if teamID := c.Query("team_id"); teamID != "" {
+		filter.TeamID = &teamID
+	}
+
+	if err := filter.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "请求数据验证失败", err.Error())
+		return
+	}
+
+	timesheet, err := g.serviceManager.Ticket().GetTimesheet(c.Request.Context(), filter)
+	if err != nil {
+		g.logger.WithError(err).Error("获取工时报表失败")
+		apierror.Respond(c, errorStatus(err), "获取工时报表失败", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, timesheet)
+}
+
+// applyWorklogRequest 将请求中的可选字段应用到工作日志
+func applyWorklogRequest(worklog *models.TicketWorklog, req *models.TicketWorklogRequest) {
+	if req.WorkType != nil {
+		worklog.WorkType = *req.WorkType
+	}
+	if req.StartedAt != nil {
+		worklog.StartedAt = *req.StartedAt
+	}
+	if req.Billable != nil {
+		worklog.Billable = *req.Billable
+	}
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+	"pulse/internal/service"
+)
+
+// fakeWorklogRepository 内存中的工作日志，汇总工时时同步工单实际工时
+type fakeWorklogRepository struct {
+	repository.TicketRepository
+	worklogs    map[string]*models.TicketWorklog
+	actualHours map[string]float64
+}
+
+func (r *fakeWorklogRepository) GetWorklog(ctx context.Context, id string) (*models.TicketWorklog, error) {
+	worklog := r.worklogs[id]
+	if worklog == nil {
+		return nil, models.ErrWorklogNotFound
+	}
+	copied := *worklog
+	return &copied, nil
+}
+
+func (r *fakeWorklogRepository) UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) error {
+	copied := *worklog
+	r.worklogs[worklog.ID] = &copied
+	return nil
+}
+
+func (r *fakeWorklogRepository) DeleteWorklog(ctx context.Context, id string) error {
+	delete(r.worklogs, id)
+	return nil
+}
+
+func (r *fakeWorklogRepository) SyncWorkTime(ctx context.Context, ticketID string) (*models.TicketWorkTime, error) {
+	workTime := &models.TicketWorkTime{TicketID: ticketID}
+	for _, worklog := range r.worklogs {
+		if worklog.TicketID == ticketID {
+			workTime.EntryCount++
+			workTime.ActualTime += worklog.GetDuration()
+		}
+	}
+	r.actualHours[ticketID] = workTime.ActualTime.Hours()
+	return workTime, nil
+}
+
+type worklogRepositoryManager struct {
+	repository.RepositoryManager
+	tickets *fakeWorklogRepository
+}
+
+func (m *worklogRepositoryManager) Ticket() repository.TicketRepository { return m.tickets }
+
+type worklogServiceManager struct {
+	*MockServiceManager
+	tickets service.TicketService
+}
+
+func (m *worklogServiceManager) Ticket() service.TicketService { return m.tickets }
+
+func setupWorklogHandlerTest() (*gin.Engine, *fakeWorklogRepository) {
+	gin.SetMode(gin.TestMode)
+	startedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	repo := &fakeWorklogRepository{
+		worklogs: map[string]*models.TicketWorklog{
+			"w1": {ID: "w1", TicketID: "t1", UserID: "u1", Description: "排查数据库连接", WorkType: models.WorklogTypeInvestigation, StartedAt: startedAt, DurationMinutes: 60, Billable: true},
+			"w2": {ID: "w2", TicketID: "t1", UserID: "u2", Description: "修复配置", WorkType: models.WorklogTypeDevelopment, StartedAt: startedAt, DurationMinutes: 30, Billable: true},
+		},
+		actualHours: map[string]float64{"t1": 1.5},
+	}
+	tickets := service.NewTicketService(&worklogRepositoryManager{tickets: repo}, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	gateway := &Gateway{
+		logger:         logrus.New(),
+		serviceManager: &worklogServiceManager{MockServiceManager: &MockServiceManager{}, tickets: tickets},
+	}
+
+	router := gin.New()
+	// 以请求头模拟认证中间件写入的当前用户
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User-ID"))
+		c.Next()
+	})
+	v1 := router.Group("/api/v1")
+	{
+		v1.PUT("/tickets/:id/worklogs/:worklog_id", gateway.updateTicketWorklog)
+		v1.DELETE("/tickets/:id/worklogs/:worklog_id", gateway.deleteTicketWorklog)
+	}
+	return router, repo
+}
+
+func TestUpdateTicketWorklog(t *testing.T) {
+	update := func(router *gin.Engine, path, userID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"duration_minutes": 90, "description": "排查数据库连接池"})
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("只有登记人可以修改", func(t *testing.T) {
+		router, repo := setupWorklogHandlerTest()
+		w := update(router, "/api/v1/tickets/t1/worklogs/w1", "u2")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, 60, repo.worklogs["w1"].DurationMinutes)
+
+		w = update(router, "/api/v1/tickets/t1/worklogs/w1", "u1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 90, repo.worklogs["w1"].DurationMinutes)
+		assert.Equal(t, 2.0, repo.actualHours["t1"])
+	})
+
+	t.Run("工作日志不属于路径中的工单", func(t *testing.T) {
+		router, repo := setupWorklogHandlerTest()
+		w := update(router, "/api/v1/tickets/t2/worklogs/w1", "u1")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "t1", repo.worklogs["w1"].TicketID)
+		assert.Equal(t, 60, repo.worklogs["w1"].DurationMinutes)
+	})
+}
+
+func TestDeleteTicketWorklog(t *testing.T) {
+	remove := func(router *gin.Engine, path, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set("X-User-ID", userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("只有登记人可以删除", func(t *testing.T) {
+		router, repo := setupWorklogHandlerTest()
+		w := remove(router, "/api/v1/tickets/t1/worklogs/w1", "u2")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, repo.worklogs, "w1")
+	})
+
+	t.Run("工作日志不属于路径中的工单", func(t *testing.T) {
+		router, repo := setupWorklogHandlerTest()
+		w := remove(router, "/api/v1/tickets/t2/worklogs/w1", "u1")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, repo.worklogs, "w1")
+	})
+
+	t.Run("删除后同步工单实际工时", func(t *testing.T) {
+		router, repo := setupWorklogHandlerTest()
+		w := remove(router, "/api/v1/tickets/t1/worklogs/w1", "u1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, repo.worklogs, "w1")
+		assert.Equal(t, 0.5, repo.actualHours["t1"])
+
+		var response struct {
+			WorkTime models.TicketWorkTime `json:"work_time"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(1), response.WorkTime.EntryCount)
+		assert.Equal(t, 30*time.Minute, response.WorkTime.ActualTime)
+	})
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/validation"
+)
+
+// decodeIngestAlert 按校验模式解析标准告警接入载荷。严格模式下拒绝未知字段和 JSON 之后的多余内容；
+// 两种模式都按字段的 binding 规则和 AlertCreateRequest.Validate 校验。失败时返回 *models.IngestSchemaError
+func decodeIngestAlert(body []byte, mode models.IngestSchemaMode, req *models.AlertCreateRequest) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if mode == models.IngestSchemaStrict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(req); err != nil {
+		return classifyIngestJSONError(err)
+	}
+	if mode == models.IngestSchemaStrict {
+		if _, err := decoder.Token(); err != io.EOF {
+			return models.NewIngestSchemaError(models.IngestRejectInvalidJSON, "请求体在 JSON 之后包含多余内容")
+		}
+	}
+
+	validation.RegisterGinValidators()
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		reason := models.IngestRejectInvalidValue
+		var fieldErrs validator.ValidationErrors
+		if errors.As(err, &fieldErrs) {
+			for _, fieldErr := range fieldErrs {
+				if fieldErr.Tag() == "required" {
+					reason = models.IngestRejectMissingField
+					break
+				}
+			}
+		}
+		return &models.IngestSchemaError{Reason: reason, Detail: err.Error(), Cause: err}
+	}
+	if err := req.Validate(); err != nil {
+		return &models.IngestSchemaError{Reason: models.IngestRejectInvalidValue, Detail: err.Error(), Cause: err}
+	}
+	return nil
+}
+
+// classifyIngestJSONError 按 JSON 解析错误的类型确定拒绝原因
+func classifyIngestJSONError(err error) *models.IngestSchemaError {
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr):
+		return &models.IngestSchemaError{
+			Reason: models.IngestRejectTypeMismatch,
+			Detail: fmt.Sprintf("字段 %s 应为 %s 类型，实际为 %s", typeErr.Field, typeErr.Type, typeErr.Value),
+			Cause:  err,
+		}
+	case errors.As(err, &timeErr):
+		return &models.IngestSchemaError{Reason: models.IngestRejectTypeMismatch, Detail: "时间字段应为 RFC 3339 格式: " + err.Error(), Cause: err}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return &models.IngestSchemaError{Reason: models.IngestRejectUnknownField, Detail: "未知字段 " + field, Cause: err}
+	case err == io.EOF:
+		return models.NewIngestSchemaError(models.IngestRejectInvalidJSON, "请求体为空")
+	default:
+		return &models.IngestSchemaError{Reason: models.IngestRejectInvalidJSON, Detail: "请求体不是合法的JSON: " + err.Error(), Cause: err}
+	}
+}
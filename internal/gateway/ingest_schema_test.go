@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestDecodeIngestAlert(t *testing.T) {
+	const valid = `{"data_source_id": "ds-1", "name": "CPU高", "description": "CPU使用率超过90%",
+		"severity": "high", "expression": "cpu > 90"`
+
+	decode := func(mode models.IngestSchemaMode, body string) (*models.AlertCreateRequest, *models.IngestSchemaError) {
+		req := &models.AlertCreateRequest{Source: models.AlertSourceGrafana}
+		err := decodeIngestAlert([]byte(body), mode, req)
+		if err == nil {
+			return req, nil
+		}
+		var schemaErr *models.IngestSchemaError
+		require.True(t, errors.As(err, &schemaErr), "校验失败应返回载荷校验错误: %v", err)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+		return nil, schemaErr
+	}
+
+	// 宽松模式忽略未知字段和多余内容，严格模式拒绝
+	req, schemaErr := decode(models.IngestSchemaLenient, valid+`, "team": "web"}`)
+	require.Nil(t, schemaErr)
+	assert.Equal(t, models.AlertSourceGrafana, req.Source)
+	_, schemaErr = decode(models.IngestSchemaStrict, valid+`, "team": "web"}`)
+	require.NotNil(t, schemaErr)
+	assert.Equal(t, models.IngestRejectUnknownField, schemaErr.Reason)
+	assert.Contains(t, schemaErr.Detail, "team")
+	_, schemaErr = decode(models.IngestSchemaStrict, valid+`} {}`)
+	require.NotNil(t, schemaErr)
+	assert.Equal(t, models.IngestRejectInvalidJSON, schemaErr.Reason)
+	_, schemaErr = decode(models.IngestSchemaStrict, valid+`}`)
+	assert.Nil(t, schemaErr)
+
+	cases := []struct {
+		name   string
+		body   string
+		reason models.IngestRejectReason
+	}{
+		{"非法JSON", `{"name": `, models.IngestRejectInvalidJSON},
+		{"空请求体", ``, models.IngestRejectInvalidJSON},
+		{"类型不匹配", valid + `, "value": "high"}`, models.IngestRejectTypeMismatch},
+		{"时间格式错误", valid + `, "starts_at": "yesterday"}`, models.IngestRejectTypeMismatch},
+		{"缺少必填字段", `{"name": "CPU高", "severity": "high"}`, models.IngestRejectMissingField},
+		{"无效的枚举值", valid + `, "severity": "urgent"}`, models.IngestRejectInvalidValue},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, schemaErr := decode(models.IngestSchemaLenient, tc.body)
+			require.NotNil(t, schemaErr)
+			assert.Equal(t, tc.reason, schemaErr.Reason)
+		})
+	}
+}
@@ -3,11 +3,12 @@ package gateway
 import (
 	"fmt"
 	"log"
-	"net/http"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
 )
 
 // 日志中间件
@@ -31,9 +32,7 @@ func LoggerMiddleware() gin.HandlerFunc {
 func RecoveryMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		log.Printf("Panic recovered: %v", recovered)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Internal server error",
-		})
+		apierror.RespondCode(c, apierror.CodeInternalError, "", nil)
 	})
 }
 
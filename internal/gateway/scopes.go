@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"fmt"
+
+	"pulse/internal/middleware"
+)
+
+// routeScopes API Key 访问接口需要的权限范围，按最长的路由模板匹配。
+// 未声明的路由（如系统管理接口）只允许拥有全部权限的 API Key 访问
+var routeScopes = middleware.ScopeTable{
+	// 告警转发器只需 alerts:ingest 创建告警，处理告警需要 alerts:write
+	{Route: "/api/v1/alerts", Read: "alerts:read", Write: "alerts:ingest"},
+	{Route: "/api/v1/alerts/:id", Read: "alerts:read", Write: "alerts:write"},
+	{Route: "/api/v1/snoozes", Read: "alerts:read", Write: "alerts:write"},
+	{Route: "/api/v1/labels", Read: "alerts:read"},
+	{Route: "/api/v1/dashboard", Read: "dashboard:read"},
+	{Route: "/api/v1/tickets", Read: "tickets:read", Write: "tickets:write"},
+	{Route: "/api/v1/knowledge", Read: "knowledge:read", Write: "knowledge:write"},
+	{Route: "/api/v1/knowledge-categories", Read: "knowledge:read", Write: "knowledge:write"},
+	{Route: "/api/v1/knowledge-comments", Read: "knowledge:read", Write: "knowledge:write"},
+	{Route: "/api/v1/knowledge-reviews", Read: "knowledge:read", Write: "knowledge:write"},
+	{Route: "/api/v1/change-events", Read: "changes:read", Write: "changes:write"},
+	{Route: "/api/v1/heartbeats", Read: "heartbeats:read", Write: "heartbeats:write"},
+	{Route: "/api/v1/services", Read: "services:read", Write: "services:write"},
+	{Route: "/api/v1/metrics", Read: "metrics:read"},
+	{Route: "/api/v1/ingest/remote-write", Write: "metrics:write"},
+}
+
+// SetAPIKeys 注册机器访问使用的 API Key，权限范围须为已声明的权限范围，需在 SetupRoutes 之前调用
+func (g *Gateway) SetAPIKeys(keys map[string]middleware.APIKeyPrincipal) error {
+	for _, principal := range keys {
+		for _, scope := range principal.Scopes {
+			if !routeScopes.Valid(scope) {
+				return fmt.Errorf("用户 %s 的 API Key 权限范围 %s 无效", principal.UserID, scope)
+			}
+		}
+	}
+	for key, principal := range keys {
+		g.authService.AddAPIKey(key, principal)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"pulse/internal/middleware"
+)
+
+func TestRouteScopes_MatchRegisteredRoutes(t *testing.T) {
+	g := NewGateway(logrus.New(), nil, &MockServiceManager{})
+	g.SetupRoutes()
+
+	// 每条权限范围声明都对应已注册的路由，避免路由改名后声明失效
+	for _, rs := range routeScopes {
+		found := false
+		for _, route := range g.router.Routes() {
+			if route.Path == rs.Route || strings.HasPrefix(route.Path, rs.Route+"/") {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "权限范围声明的路由 %s 未注册", rs.Route)
+	}
+}
+
+func TestGateway_SetAPIKeys(t *testing.T) {
+	g := NewGateway(logrus.New(), nil, &MockServiceManager{})
+
+	err := g.SetAPIKeys(map[string]middleware.APIKeyPrincipal{
+		"k1": {UserID: "svc-forwarder", Scopes: []string{"alerts:ingest"}},
+		"k2": {UserID: "svc-bot", Scopes: []string{"knowledge:read", "knowledge:write"}},
+	})
+	assert.NoError(t, err)
+	principal, err := g.authService.ValidateAPIKey("k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alerts:ingest"}, principal.Scopes)
+
+	err = g.SetAPIKeys(map[string]middleware.APIKeyPrincipal{"k3": {UserID: "svc-x", Scopes: []string{"alert:read"}}})
+	assert.Error(t, err)
+	_, err = g.authService.ValidateAPIKey("k3")
+	assert.Error(t, err)
+}
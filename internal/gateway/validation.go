@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/validation"
+)
+
+// bindJSON 解析并校验请求体，失败时返回 400 响应并返回 false
+// 校验失败时 details 为字段级错误列表，请求体无法解析时为解析错误
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	validation.RegisterGinValidators()
+
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	if fields := validation.FieldErrors(err, apierror.LocaleFromContext(c)); len(fields) > 0 {
+		apierror.RespondCode(c, apierror.CodeValidationFailed, "请求数据验证失败", fields)
+		return false
+	}
+
+	apierror.Respond(c, http.StatusBadRequest, "请求参数无效", err.Error())
+	return false
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/rules", func(c *gin.Context) {
+		var req models.RuleCreateRequest
+		if !bindJSON(c, &req) {
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	post := func(body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/rules", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		if w.Body.Len() > 0 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	t.Run("字段校验失败返回字段错误列表", func(t *testing.T) {
+		w, resp := post(`{
+			"data_source_id": "ds-1", "name": "cpu", "description": "cpu usage",
+			"type": "metric", "severity": "urgent", "expression": "up == 0",
+			"labels": {"__name__": "up"}, "evaluation_interval": 1000
+		}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_FAILED", resp["code"])
+
+		details, ok := resp["details"].([]interface{})
+		require.True(t, ok)
+		fields := make(map[string]string)
+		for _, d := range details {
+			item := d.(map[string]interface{})
+			fields[item["field"].(string)] = item["rule"].(string)
+		}
+		assert.Equal(t, map[string]string{
+			"severity":            "enum",
+			"labels":              "labels",
+			"evaluation_interval": "duration",
+		}, fields)
+	})
+
+	t.Run("字段类型错误", func(t *testing.T) {
+		w, resp := post(`{"name": 123}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_FAILED", resp["code"])
+	})
+
+	t.Run("请求体无法解析", func(t *testing.T) {
+		w, resp := post(`{invalid`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_REQUEST", resp["code"])
+	})
+}
@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// webUIContentSecurityPolicy 前端页面的内容安全策略，Ant Design 运行时注入样式，需要允许内联样式
+const webUIContentSecurityPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; font-src 'self' data:"
+
+// registerWebUIRoutes 注册未匹配路由的处理：API 和接入端点返回 JSON 404，其余 GET/HEAD 请求交给前端
+func (g *Gateway) registerWebUIRoutes() {
+	g.router.NoRoute(func(c *gin.Context) {
+		if g.webUI == nil || !isWebUIRequest(c.Request) {
+			apierror.RespondCode(c, apierror.CodeNotFound, "", nil)
+			return
+		}
+		c.Header("Content-Security-Policy", webUIContentSecurityPolicy)
+		g.webUI.ServeHTTP(c.Writer, c.Request)
+	})
+}
+
+// isWebUIRequest 判断请求是否为前端页面或静态资源
+func isWebUIRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	p := r.URL.Path
+	return p != "/api" && !strings.HasPrefix(p, "/api/") && !strings.HasPrefix(p, "/health/")
+}
@@ -0,0 +1,106 @@
+// Package itsm 实现与外部 ITSM 系统（Jira、ServiceNow）的工单同步客户端
+//
+// 出站方向由同步 Worker 调用 CreateIssue、UpdateIssue 和 AddComment 推送平台工单，
+// 入站方向由外部系统的 Webhook 推送变更，ParseWebhook 将各系统的负载解析为统一的事件。
+package itsm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"pulse/internal/models"
+)
+
+// CommentMarker 平台同步到外部系统的评论前缀，入站时忽略带该前缀的评论，避免评论回环
+const CommentMarker = "[Pulse]"
+
+// maxErrorBodySize 错误信息中保留的响应体最大字节数
+const maxErrorBodySize = 2 << 10
+
+// Client 外部 ITSM 系统客户端
+type Client interface {
+	// CreateIssue 创建外部工单，返回外部工单的ID、键和访问地址
+	CreateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) (*models.ITSMRemoteIssue, error)
+	// UpdateIssue 更新外部工单的字段和状态
+	UpdateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) error
+	// AddComment 向外部工单添加评论，外部系统不返回评论ID时返回空
+	AddComment(ctx context.Context, issue *models.ITSMRemoteIssue, body string) (string, error)
+	// ParseWebhook 解析外部系统推送的 Webhook 负载
+	ParseWebhook(body []byte) (*models.ITSMWebhookEvent, error)
+}
+
+// New 根据连接器类型创建客户端
+func New(connector *models.ITSMConnector, httpClient *http.Client) (Client, error) {
+	switch connector.Provider {
+	case models.ITSMProviderJira:
+		return NewJiraClient(connector, httpClient), nil
+	case models.ITSMProviderServiceNow:
+		return NewServiceNowClient(connector, httpClient), nil
+	default:
+		return nil, fmt.Errorf("不支持的ITSM系统 %s", connector.Provider)
+	}
+}
+
+// IsSyncedComment 评论是否由平台同步到外部系统
+func IsSyncedComment(body string) bool {
+	return strings.HasPrefix(strings.TrimSpace(body), CommentMarker)
+}
+
+// requester 发送 JSON 请求的公共实现
+type requester struct {
+	baseURL  string
+	username string
+	token    string
+	bearer   bool
+	client   *http.Client
+}
+
+// do 发送请求，payload 不为空时序列化为请求体，out 不为空时解析响应体
+func (r *requester) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.bearer {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	} else {
+		req.SetBasicAuth(r.username, r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 %s %s 失败: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("%s %s 返回错误状态 %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析 %s %s 响应失败: %w", method, path, err)
+	}
+	return nil
+}
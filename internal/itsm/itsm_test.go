@@ -0,0 +1,165 @@
+package itsm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestJiraClient_CreateAndTransition(t *testing.T) {
+	var transitioned string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot@example.com", username)
+		assert.Equal(t, "token", password)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			var payload map[string]map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			fields := payload["fields"]
+			assert.Equal(t, "磁盘已满", fields["summary"])
+			assert.Equal(t, map[string]interface{}{"key": "OPS"}, fields["project"])
+			assert.Equal(t, map[string]interface{}{"name": "Task"}, fields["issuetype"])
+			assert.Equal(t, map[string]interface{}{"name": "High"}, fields["priority"])
+			assert.Equal(t, "web-01", fields["customfield_10010"])
+			w.Write([]byte(`{"id": "10001", "key": "OPS-1"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/OPS-1":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/OPS-1":
+			w.Write([]byte(`{"fields": {"status": {"name": "To Do"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/OPS-1/transitions":
+			w.Write([]byte(`{"transitions": [{"id": "21", "to": {"name": "In Progress"}}, {"id": "31", "to": {"name": "Done"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/OPS-1/transitions":
+			var payload map[string]map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			transitioned = payload["transition"]["id"]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(&models.ITSMConnector{
+		Provider: models.ITSMProviderJira,
+		BaseURL:  server.URL,
+		Username: "bot@example.com",
+		APIToken: "token",
+		Project:  "OPS",
+	}, server.Client())
+	require.NoError(t, err)
+
+	issue, err := client.CreateIssue(context.Background(), &models.ITSMRemoteIssue{
+		Title:     "磁盘已满",
+		Status:    "In Progress",
+		Priority:  "High",
+		IssueType: "Task",
+		Fields:    map[string]interface{}{"customfield_10010": "web-01"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "10001", issue.ID)
+	assert.Equal(t, "OPS-1", issue.Key)
+	assert.Equal(t, server.URL+"/browse/OPS-1", issue.URL)
+	assert.Equal(t, "21", transitioned)
+
+	// 没有到目标状态的流转时返回错误
+	err = client.UpdateIssue(context.Background(), &models.ITSMRemoteIssue{Key: "OPS-1", Status: "Blocked"})
+	assert.Error(t, err)
+}
+
+func TestJiraClient_ParseWebhook(t *testing.T) {
+	client := NewJiraClient(&models.ITSMConnector{BaseURL: "https://jira.example.com/"}, http.DefaultClient)
+
+	event, err := client.ParseWebhook([]byte(`{
+		"timestamp": 1700000000000,
+		"webhookEvent": "comment_created",
+		"issue": {"id": "10001", "key": "OPS-1", "fields": {
+			"summary": "磁盘已满", "description": null,
+			"status": {"name": "Done"}, "priority": {"name": "Highest"},
+			"updated": "2024-01-02T10:00:00.000+0800"}},
+		"comment": {"id": "500", "body": "已扩容", "author": {"displayName": "Bob"},
+			"created": "2024-01-02T10:00:00.000+0800"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "OPS-1", event.Issue.Key)
+	assert.Equal(t, "https://jira.example.com/browse/OPS-1", event.Issue.URL)
+	assert.Equal(t, "Done", event.Issue.Status)
+	assert.Equal(t, "Highest", event.Issue.Priority)
+	assert.True(t, event.Issue.UpdatedAt.Equal(time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)))
+	require.NotNil(t, event.Comment)
+	assert.Equal(t, "500", event.Comment.ID)
+	assert.Equal(t, "Bob", event.Comment.Author)
+
+	_, err = client.ParseWebhook([]byte(`{"webhookEvent": "project_created"}`))
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestServiceNowClient(t *testing.T) {
+	var patched map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/incident":
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "2", payload["state"])
+			assert.Equal(t, "1", payload["priority"])
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"result": {"sys_id": "abc", "number": "INC0010001"}}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/now/table/incident/abc":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			w.Write([]byte(`{"result": {}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"message": "denied"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(&models.ITSMConnector{
+		Provider: models.ITSMProviderServiceNow,
+		BaseURL:  server.URL,
+		Username: "admin",
+		APIToken: "token",
+		Project:  "incident",
+	}, server.Client())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	issue, err := client.CreateIssue(ctx, &models.ITSMRemoteIssue{Title: "磁盘已满", Status: "2", Priority: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", issue.ID)
+	assert.Equal(t, "INC0010001", issue.Key)
+	assert.Contains(t, issue.URL, "incident.do")
+
+	_, err = client.AddComment(ctx, issue, CommentMarker+" alice: 已处理")
+	require.NoError(t, err)
+	assert.Equal(t, "[Pulse] alice: 已处理", patched["comments"])
+
+	err = client.UpdateIssue(ctx, &models.ITSMRemoteIssue{ID: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+
+	event, err := client.ParseWebhook([]byte(`{"sys_id": "abc", "number": "INC0010001", "state": "6",
+		"sys_updated_on": "2024-01-02 02:00:00", "comment": {"id": "c1", "author": "bob", "body": "已恢复"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "6", event.Issue.Status)
+	assert.True(t, event.Issue.UpdatedAt.Equal(time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)))
+	require.NotNil(t, event.Comment)
+	assert.Equal(t, "已恢复", event.Comment.Body)
+}
+
+func TestIsSyncedComment(t *testing.T) {
+	assert.True(t, IsSyncedComment("  [Pulse] alice: 已处理"))
+	assert.False(t, IsSyncedComment("已处理 [Pulse]"))
+}
@@ -0,0 +1,224 @@
+package itsm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// jiraTimeLayout Jira REST API 和 Webhook 中的时间格式
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// JiraClient Jira REST API v2 客户端，连接器项目为 Jira 项目键
+// 配置了用户名时使用 Basic 认证（Jira Cloud 的邮箱和 API 令牌），否则使用个人访问令牌
+type JiraClient struct {
+	requester
+	project string
+}
+
+// NewJiraClient 创建 Jira 客户端
+func NewJiraClient(connector *models.ITSMConnector, httpClient *http.Client) *JiraClient {
+	return &JiraClient{
+		requester: requester{
+			baseURL:  strings.TrimRight(connector.BaseURL, "/"),
+			username: connector.Username,
+			token:    connector.APIToken,
+			bearer:   connector.Username == "",
+			client:   httpClient,
+		},
+		project: connector.Project,
+	}
+}
+
+// CreateIssue 创建 Jira 问题，创建后按映射的状态执行流转
+func (c *JiraClient) CreateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) (*models.ITSMRemoteIssue, error) {
+	fields := c.fields(issue)
+	fields["project"] = map[string]string{"key": c.project}
+	if issue.IssueType != "" {
+		fields["issuetype"] = map[string]string{"name": issue.IssueType}
+	}
+
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, fmt.Errorf("创建Jira问题失败: %w", err)
+	}
+
+	result := *issue
+	result.ID = created.ID
+	result.Key = created.Key
+	result.URL = c.baseURL + "/browse/" + created.Key
+
+	if err := c.transition(ctx, created.Key, issue.Status); err != nil {
+		return &result, err
+	}
+	return &result, nil
+}
+
+// UpdateIssue 更新 Jira 问题字段，状态变化通过工作流流转实现
+func (c *JiraClient) UpdateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(issue.Key)
+	if err := c.do(ctx, http.MethodPut, path, map[string]interface{}{"fields": c.fields(issue)}, nil); err != nil {
+		return fmt.Errorf("更新Jira问题失败: %w", err)
+	}
+	return c.transition(ctx, issue.Key, issue.Status)
+}
+
+// AddComment 添加 Jira 评论
+func (c *JiraClient) AddComment(ctx context.Context, issue *models.ITSMRemoteIssue, body string) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(issue.Key) + "/comment"
+	if err := c.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &created); err != nil {
+		return "", fmt.Errorf("添加Jira评论失败: %w", err)
+	}
+	return created.ID, nil
+}
+
+// fields 构建问题字段，未映射的优先级不提交
+func (c *JiraClient) fields(issue *models.ITSMRemoteIssue) map[string]interface{} {
+	fields := map[string]interface{}{
+		"summary":     issue.Title,
+		"description": issue.Description,
+	}
+	if issue.Priority != "" {
+		fields["priority"] = map[string]string{"name": issue.Priority}
+	}
+	for name, value := range issue.Fields {
+		fields[name] = value
+	}
+	return fields
+}
+
+// transition 将问题流转到目标状态，已处于目标状态时不做处理
+func (c *JiraClient) transition(ctx context.Context, key, status string) error {
+	if status == "" {
+		return nil
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(key)
+	var current struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := c.do(ctx, http.MethodGet, path+"?fields=status", nil, &current); err != nil {
+		return fmt.Errorf("获取Jira问题状态失败: %w", err)
+	}
+	if strings.EqualFold(current.Fields.Status.Name, status) {
+		return nil
+	}
+
+	var available struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(ctx, http.MethodGet, path+"/transitions", nil, &available); err != nil {
+		return fmt.Errorf("获取Jira问题流转失败: %w", err)
+	}
+
+	for _, transition := range available.Transitions {
+		if strings.EqualFold(transition.To.Name, status) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			if err := c.do(ctx, http.MethodPost, path+"/transitions", payload, nil); err != nil {
+				return fmt.Errorf("流转Jira问题失败: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Jira问题 %s 没有从 %s 到 %s 的可用流转", key, current.Fields.Status.Name, status)
+}
+
+// jiraWebhook Jira Webhook 负载，只解析同步需要的字段
+type jiraWebhook struct {
+	Timestamp    int64  `json:"timestamp"`
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        struct {
+		ID     string `json:"id"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string  `json:"summary"`
+			Description *string `json:"description"`
+			Updated     string  `json:"updated"`
+			Status      *struct {
+				Name string `json:"name"`
+			} `json:"status"`
+			Priority *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+		} `json:"fields"`
+	} `json:"issue"`
+	Comment *struct {
+		ID     string `json:"id"`
+		Body   string `json:"body"`
+		Author struct {
+			DisplayName string `json:"displayName"`
+		} `json:"author"`
+		Created string `json:"created"`
+	} `json:"comment"`
+}
+
+// ParseWebhook 解析 Jira 问题和评论事件（jira:issue_updated、comment_created 等）
+func (c *JiraClient) ParseWebhook(body []byte) (*models.ITSMWebhookEvent, error) {
+	var payload jiraWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: 解析Jira Webhook失败: %v", models.ErrInvalidInput, err)
+	}
+	if payload.Issue.ID == "" {
+		return nil, fmt.Errorf("%w: Jira Webhook缺少问题信息", models.ErrInvalidInput)
+	}
+
+	fields := payload.Issue.Fields
+	event := &models.ITSMWebhookEvent{
+		Issue: models.ITSMRemoteIssue{
+			ID:    payload.Issue.ID,
+			Key:   payload.Issue.Key,
+			URL:   c.baseURL + "/browse/" + payload.Issue.Key,
+			Title: fields.Summary,
+		},
+	}
+	if fields.Description != nil {
+		event.Issue.Description = *fields.Description
+	}
+	if fields.Status != nil {
+		event.Issue.Status = fields.Status.Name
+	}
+	if fields.Priority != nil {
+		event.Issue.Priority = fields.Priority.Name
+	}
+
+	event.Issue.UpdatedAt = time.UnixMilli(payload.Timestamp)
+	if updated, err := time.Parse(jiraTimeLayout, fields.Updated); err == nil {
+		event.Issue.UpdatedAt = updated
+	}
+
+	if payload.Comment != nil {
+		comment := &models.ITSMRemoteComment{
+			ID:     payload.Comment.ID,
+			Author: payload.Comment.Author.DisplayName,
+			Body:   payload.Comment.Body,
+		}
+		if created, err := time.Parse(jiraTimeLayout, payload.Comment.Created); err == nil {
+			comment.CreatedAt = created
+		}
+		event.Comment = comment
+	}
+
+	return event, nil
+}
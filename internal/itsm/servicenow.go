@@ -0,0 +1,162 @@
+package itsm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// serviceNowTimeLayout ServiceNow 系统字段的时间格式（UTC）
+const serviceNowTimeLayout = "2006-01-02 15:04:05"
+
+// ServiceNowClient ServiceNow Table API 客户端，连接器项目为表名（如 incident）
+type ServiceNowClient struct {
+	requester
+	table string
+}
+
+// NewServiceNowClient 创建 ServiceNow 客户端
+func NewServiceNowClient(connector *models.ITSMConnector, httpClient *http.Client) *ServiceNowClient {
+	return &ServiceNowClient{
+		requester: requester{
+			baseURL:  strings.TrimRight(connector.BaseURL, "/"),
+			username: connector.Username,
+			token:    connector.APIToken,
+			client:   httpClient,
+		},
+		table: connector.Project,
+	}
+}
+
+// serviceNowRecord Table API 返回的记录
+type serviceNowRecord struct {
+	Result struct {
+		SysID        string `json:"sys_id"`
+		Number       string `json:"number"`
+		SysUpdatedOn string `json:"sys_updated_on"`
+	} `json:"result"`
+}
+
+// CreateIssue 创建 ServiceNow 记录
+func (c *ServiceNowClient) CreateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) (*models.ITSMRemoteIssue, error) {
+	var record serviceNowRecord
+	if err := c.do(ctx, http.MethodPost, "/api/now/table/"+url.PathEscape(c.table), c.fields(issue), &record); err != nil {
+		return nil, fmt.Errorf("创建ServiceNow记录失败: %w", err)
+	}
+
+	result := *issue
+	result.ID = record.Result.SysID
+	result.Key = record.Result.Number
+	result.URL = c.recordURL(record.Result.SysID)
+	return &result, nil
+}
+
+// UpdateIssue 更新 ServiceNow 记录，状态直接写入 state 字段
+func (c *ServiceNowClient) UpdateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) error {
+	if err := c.do(ctx, http.MethodPatch, c.recordPath(issue.ID), c.fields(issue), nil); err != nil {
+		return fmt.Errorf("更新ServiceNow记录失败: %w", err)
+	}
+	return nil
+}
+
+// AddComment 通过 comments 日志字段添加评论，Table API 不返回评论ID
+func (c *ServiceNowClient) AddComment(ctx context.Context, issue *models.ITSMRemoteIssue, body string) (string, error) {
+	if err := c.do(ctx, http.MethodPatch, c.recordPath(issue.ID), map[string]string{"comments": body}, nil); err != nil {
+		return "", fmt.Errorf("添加ServiceNow评论失败: %w", err)
+	}
+	return "", nil
+}
+
+// fields 构建记录字段，未映射的状态和优先级不提交
+func (c *ServiceNowClient) fields(issue *models.ITSMRemoteIssue) map[string]interface{} {
+	fields := map[string]interface{}{
+		"short_description": issue.Title,
+		"description":       issue.Description,
+	}
+	if issue.Status != "" {
+		fields["state"] = issue.Status
+	}
+	if issue.Priority != "" {
+		fields["priority"] = issue.Priority
+	}
+	for name, value := range issue.Fields {
+		fields[name] = value
+	}
+	return fields
+}
+
+func (c *ServiceNowClient) recordPath(sysID string) string {
+	return "/api/now/table/" + url.PathEscape(c.table) + "/" + url.PathEscape(sysID)
+}
+
+func (c *ServiceNowClient) recordURL(sysID string) string {
+	return c.baseURL + "/nav_to.do?uri=" + url.QueryEscape(c.table+".do?sys_id="+sysID)
+}
+
+// serviceNowWebhook ServiceNow 业务规则通过 Outbound REST 推送的负载
+//
+//	{"sys_id": "...", "number": "INC0010001", "short_description": "...", "description": "...",
+//	 "state": "2", "priority": "3", "sys_updated_on": "2024-01-01 10:00:00",
+//	 "comment": {"id": "...", "author": "...", "body": "...", "created": "2024-01-01 10:00:00"}}
+type serviceNowWebhook struct {
+	SysID            string `json:"sys_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+	State            string `json:"state"`
+	Priority         string `json:"priority"`
+	SysUpdatedOn     string `json:"sys_updated_on"`
+	Comment          *struct {
+		ID      string `json:"id"`
+		Author  string `json:"author"`
+		Body    string `json:"body"`
+		Created string `json:"created"`
+	} `json:"comment"`
+}
+
+// ParseWebhook 解析 ServiceNow 记录变更负载
+func (c *ServiceNowClient) ParseWebhook(body []byte) (*models.ITSMWebhookEvent, error) {
+	var payload serviceNowWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: 解析ServiceNow Webhook失败: %v", models.ErrInvalidInput, err)
+	}
+	if payload.SysID == "" {
+		return nil, fmt.Errorf("%w: ServiceNow Webhook缺少 sys_id", models.ErrInvalidInput)
+	}
+
+	event := &models.ITSMWebhookEvent{
+		Issue: models.ITSMRemoteIssue{
+			ID:          payload.SysID,
+			Key:         payload.Number,
+			URL:         c.recordURL(payload.SysID),
+			Title:       payload.ShortDescription,
+			Description: payload.Description,
+			Status:      payload.State,
+			Priority:    payload.Priority,
+			UpdatedAt:   time.Now(),
+		},
+	}
+	if updated, err := time.Parse(serviceNowTimeLayout, payload.SysUpdatedOn); err == nil {
+		event.Issue.UpdatedAt = updated
+	}
+
+	if payload.Comment != nil && payload.Comment.Body != "" {
+		comment := &models.ITSMRemoteComment{
+			ID:     payload.Comment.ID,
+			Author: payload.Comment.Author,
+			Body:   payload.Comment.Body,
+		}
+		if created, err := time.Parse(serviceNowTimeLayout, payload.Comment.Created); err == nil {
+			comment.CreatedAt = created
+		}
+		event.Comment = comment
+	}
+
+	return event, nil
+}
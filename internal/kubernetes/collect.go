@@ -0,0 +1,359 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// FindingKind 异常的来源
+type FindingKind string
+
+const (
+	FindingKindEvent FindingKind = "event" // Warning 事件
+	FindingKindNode  FindingKind = "node"  // 节点状况
+	FindingKindPod   FindingKind = "pod"   // Pod 状况
+)
+
+// podWaitingReasons 容器处于等待状态且需要人工介入的原因
+var podWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// nodePressureConditions 为 True 时表示节点异常的状况
+var nodePressureConditions = []string{"MemoryPressure", "DiskPressure", "PIDPressure", "NetworkUnavailable"}
+
+// eventSeverities 需要提高级别的事件原因，其余 Warning 事件为中级
+var eventSeverities = map[string]models.AlertSeverity{
+	"OOMKilling":          models.AlertSeverityHigh,
+	"SystemOOM":           models.AlertSeverityHigh,
+	"Evicted":             models.AlertSeverityHigh,
+	"NodeNotReady":        models.AlertSeverityHigh,
+	"FreeDiskSpaceFailed": models.AlertSeverityHigh,
+}
+
+// Finding 一项集群异常，同一数据源内 Key 唯一，对应一条告警
+type Finding struct {
+	Key          string
+	Kind         FindingKind
+	ObjectKind   string
+	Namespace    string
+	Name         string
+	WorkloadKind string
+	Workload     string
+	Node         string
+	Reason       string
+	Message      string
+	Severity     models.AlertSeverity
+	Count        int32
+	Since        time.Time
+}
+
+// Labels 告警标签，空值不输出
+func (f *Finding) Labels() map[string]string {
+	labels := map[string]string{
+		"kind":   f.ObjectKind,
+		"name":   f.Name,
+		"reason": f.Reason,
+	}
+	for key, value := range map[string]string{
+		"namespace":     f.Namespace,
+		"workload":      f.Workload,
+		"workload_kind": f.WorkloadKind,
+		"node":          f.Node,
+	} {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// Object 异常资源的可读名称
+func (f *Finding) Object() string {
+	if f.Namespace == "" {
+		return f.ObjectKind + "/" + f.Name
+	}
+	return f.ObjectKind + "/" + f.Namespace + "/" + f.Name
+}
+
+// Source 采集所需的只读接口，由 Client 实现
+type Source interface {
+	ListWarningEvents(ctx context.Context, namespace string) ([]Event, error)
+	ListNodes(ctx context.Context) ([]Node, error)
+	ListPods(ctx context.Context, namespace string) ([]Pod, error)
+}
+
+// CollectOptions 采集选项
+type CollectOptions struct {
+	// Namespaces 采集事件和 Pod 的命名空间，为空时采集全部命名空间。节点不区分命名空间，始终采集
+	Namespaces []string
+	// EventWindow 只采集最后发生时间在该时间窗口内的事件
+	EventWindow time.Duration
+	// PodNotReadyAfter Pod 未就绪或无法调度超过该时长才视为异常，避免发布过程中误报
+	PodNotReadyAfter time.Duration
+	Now              time.Time
+}
+
+// Collect 采集集群中的异常，结果按 Key 排序
+func Collect(ctx context.Context, src Source, opts CollectOptions) ([]Finding, error) {
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	nodes, err := src.ListNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取节点列表失败: %w", err)
+	}
+
+	var pods []Pod
+	var events []Event
+	for _, namespace := range namespaces {
+		nsPods, err := src.ListPods(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("获取Pod列表失败: %w", err)
+		}
+		pods = append(pods, nsPods...)
+
+		nsEvents, err := src.ListWarningEvents(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("获取事件列表失败: %w", err)
+		}
+		events = append(events, nsEvents...)
+	}
+
+	findings := NodeFindings(nodes)
+	podFindings := PodFindings(pods, opts.Now, opts.PodNotReadyAfter)
+	findings = append(findings, podFindings...)
+	findings = append(findings, EventFindings(events, pods, podFindings, opts.Now.Add(-opts.EventWindow))...)
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Key < findings[j].Key })
+	return findings, nil
+}
+
+// NodeFindings 未就绪或存在资源压力的节点，每个异常状况一项
+func NodeFindings(nodes []Node) []Finding {
+	var findings []Finding
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			finding := Finding{
+				Key:        "node:" + node.Metadata.Name + ":" + condition.Type,
+				Kind:       FindingKindNode,
+				ObjectKind: "Node",
+				Name:       node.Metadata.Name,
+				Node:       node.Metadata.Name,
+				Reason:     condition.Type,
+				Message:    condition.Message,
+				Count:      1,
+			}
+			if condition.LastTransitionTime != nil {
+				finding.Since = *condition.LastTransitionTime
+			}
+
+			switch {
+			case condition.Type == "Ready" && condition.Status != "True":
+				finding.Reason = "NotReady"
+				if condition.Status == "Unknown" {
+					finding.Reason = "NodeStatusUnknown"
+				}
+				finding.Severity = models.AlertSeverityCritical
+			case condition.Status == "True" && containsString(nodePressureConditions, condition.Type):
+				finding.Severity = models.AlertSeverityHigh
+			default:
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// PodFindings 容器启动失败、长时间无法调度或未就绪的 Pod，每个 Pod 一项
+// 已失败的 Pod 通常属于已结束的 Job，由 Job 的事件反映，不单独采集
+func PodFindings(pods []Pod, now time.Time, notReadyAfter time.Duration) []Finding {
+	var findings []Finding
+	for _, pod := range pods {
+		if pod.Status.Phase == "Failed" || pod.Status.Phase == "Succeeded" {
+			continue
+		}
+
+		finding, ok := podFinding(&pod, now, notReadyAfter)
+		if !ok {
+			continue
+		}
+		finding.Key = "pod:" + pod.Metadata.Namespace + "/" + pod.Metadata.Name
+		finding.Kind = FindingKindPod
+		finding.ObjectKind = "Pod"
+		finding.Namespace = pod.Metadata.Namespace
+		finding.Name = pod.Metadata.Name
+		finding.Node = pod.Spec.NodeName
+		finding.WorkloadKind, finding.Workload = PodWorkload(&pod)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// podFinding 判断 Pod 是否异常，容器启动失败优先于调度和就绪状况
+func podFinding(pod *Pod, now time.Time, notReadyAfter time.Duration) (Finding, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && podWaitingReasons[status.State.Waiting.Reason] {
+			return Finding{
+				Reason:   status.State.Waiting.Reason,
+				Message:  fmt.Sprintf("容器 %s: %s", status.Name, status.State.Waiting.Message),
+				Severity: models.AlertSeverityHigh,
+				Count:    status.RestartCount,
+				Since:    pod.Metadata.CreationTimestamp,
+			}, true
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != "False" || condition.LastTransitionTime == nil ||
+			now.Sub(*condition.LastTransitionTime) < notReadyAfter {
+			continue
+		}
+
+		switch {
+		case condition.Type == "PodScheduled" && condition.Reason == "Unschedulable":
+			return Finding{
+				Reason:   "Unschedulable",
+				Message:  condition.Message,
+				Severity: models.AlertSeverityHigh,
+				Count:    1,
+				Since:    *condition.LastTransitionTime,
+			}, true
+		case condition.Type == "Ready" && pod.Status.Phase == "Running":
+			return Finding{
+				Reason:   "NotReady",
+				Message:  strings.TrimSpace(condition.Reason + " " + condition.Message),
+				Severity: models.AlertSeverityMedium,
+				Count:    1,
+				Since:    *condition.LastTransitionTime,
+			}, true
+		}
+	}
+
+	return Finding{}, false
+}
+
+// EventFindings 时间窗口内的 Warning 事件，同一资源的同一原因合并为一项
+// 已作为 Pod 状况采集的 Pod 不再采集其事件，避免同一问题产生两条告警
+func EventFindings(events []Event, pods []Pod, podFindings []Finding, since time.Time) []Finding {
+	workloads := make(map[string][2]string, len(pods))
+	for i := range pods {
+		kind, name := PodWorkload(&pods[i])
+		workloads[pods[i].Metadata.Namespace+"/"+pods[i].Metadata.Name] = [2]string{kind, name}
+	}
+	reported := make(map[string]bool, len(podFindings))
+	for _, finding := range podFindings {
+		reported[finding.Namespace+"/"+finding.Name] = true
+	}
+
+	merged := make(map[string]*Finding)
+	var keys []string
+	for _, event := range events {
+		lastSeen := event.LastSeen()
+		if event.Type != "Warning" || lastSeen.Before(since) {
+			continue
+		}
+
+		object := event.InvolvedObject
+		if object.Namespace == "" {
+			object.Namespace = event.Metadata.Namespace
+		}
+		objectKey := object.Namespace + "/" + object.Name
+		if object.Kind == "Pod" && reported[objectKey] {
+			continue
+		}
+
+		key := "event:" + object.Kind + ":" + objectKey + ":" + event.Reason
+		if object.Kind == "Node" {
+			key = "event:Node:" + object.Name + ":" + event.Reason
+			object.Namespace = ""
+		}
+
+		if existing, ok := merged[key]; ok {
+			existing.Count += event.Occurrences()
+			if lastSeen.After(existing.Since) {
+				existing.Message = event.Message
+				existing.Since = lastSeen
+			}
+			continue
+		}
+
+		finding := &Finding{
+			Key:        key,
+			Kind:       FindingKindEvent,
+			ObjectKind: object.Kind,
+			Namespace:  object.Namespace,
+			Name:       object.Name,
+			Reason:     event.Reason,
+			Message:    event.Message,
+			Severity:   models.AlertSeverityMedium,
+			Count:      event.Occurrences(),
+			Since:      lastSeen,
+		}
+		if severity, ok := eventSeverities[event.Reason]; ok {
+			finding.Severity = severity
+		}
+		switch object.Kind {
+		case "Pod":
+			if workload, ok := workloads[objectKey]; ok {
+				finding.WorkloadKind, finding.Workload = workload[0], workload[1]
+			}
+		case "Node":
+			finding.Node = object.Name
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob":
+			finding.WorkloadKind, finding.Workload = object.Kind, object.Name
+		}
+		if finding.Node == "" && event.Source.Host != "" {
+			finding.Node = event.Source.Host
+		}
+
+		merged[key] = finding
+		keys = append(keys, key)
+	}
+
+	findings := make([]Finding, 0, len(keys))
+	for _, key := range keys {
+		findings = append(findings, *merged[key])
+	}
+	return findings
+}
+
+// PodWorkload 根据属主推断 Pod 所属的工作负载，Deployment 创建的 ReplicaSet 归属到 Deployment
+// 没有控制器的 Pod 返回自身
+func PodWorkload(pod *Pod) (string, string) {
+	for _, owner := range pod.Metadata.OwnerReferences {
+		if !owner.Controller {
+			continue
+		}
+		if owner.Kind == "ReplicaSet" {
+			if hash := pod.Metadata.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+				return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+			}
+		}
+		return owner.Kind, owner.Name
+	}
+	return "Pod", pod.Metadata.Name
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"pulse/internal/models"
+)
+
+// 集群内服务账号凭据的挂载位置
+const (
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// ErrNotInCluster 进程不在 Kubernetes 集群内运行
+var ErrNotInCluster = errors.New("未检测到集群内环境，缺少 KUBERNETES_SERVICE_HOST 或 KUBERNETES_SERVICE_PORT")
+
+// Config API Server 连接配置
+type Config struct {
+	Host     string
+	Token    string
+	Username string
+	Password string
+	// CAData、CertData 和 KeyData 为 PEM 编码的证书和私钥
+	CAData   []byte
+	CertData []byte
+	KeyData  []byte
+	Insecure bool
+}
+
+// ConfigFromDataSource 根据数据源配置生成连接配置
+// 优先使用 kubeconfig，其次为集群内服务账号，否则使用 URL 和 Bearer Token
+func ConfigFromDataSource(config *models.DataSourceConfig) (*Config, error) {
+	var (
+		cfg *Config
+		err error
+	)
+
+	switch {
+	case config.Kubeconfig != nil && strings.TrimSpace(*config.Kubeconfig) != "":
+		kubeContext := ""
+		if config.KubeContext != nil {
+			kubeContext = *config.KubeContext
+		}
+		cfg, err = ParseKubeconfig([]byte(*config.Kubeconfig), kubeContext)
+	case config.InCluster:
+		cfg, err = InClusterConfig()
+	default:
+		cfg = &Config{Host: config.URL}
+		if config.Token != nil {
+			cfg.Token = *config.Token
+		} else if config.Username != nil && config.Password != nil {
+			cfg.Username = *config.Username
+			cfg.Password = *config.Password
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 显式配置的 URL 覆盖 kubeconfig 和集群内的地址，便于通过代理访问
+	if config.URL != "" {
+		cfg.Host = config.URL
+	}
+	if cfg.Host == "" {
+		return nil, errors.New("API Server 地址不能为空")
+	}
+	return cfg, nil
+}
+
+// InClusterConfig 使用 Pod 挂载的服务账号连接所在集群
+func InClusterConfig() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, ErrNotInCluster
+	}
+
+	token, err := os.ReadFile(serviceAccountTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取服务账号令牌失败: %w", err)
+	}
+	ca, err := os.ReadFile(serviceAccountCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取集群CA证书失败: %w", err)
+	}
+
+	return &Config{
+		Host:   "https://" + net.JoinHostPort(host, port),
+		Token:  strings.TrimSpace(string(token)),
+		CAData: ca,
+	}, nil
+}
+
+// kubeconfig kubeconfig 文件中用到的字段
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			Username              string `yaml:"username"`
+			Password              string `yaml:"password"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Exec                  *struct {
+				Command string `yaml:"command"`
+			} `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// ParseKubeconfig 解析 kubeconfig 内容，kubeContext 为空时使用 current-context
+// 不支持 exec 等凭据插件，平台进程中通常没有对应的命令行工具
+func ParseKubeconfig(data []byte, kubeContext string) (*Config, error) {
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("解析kubeconfig失败: %w", err)
+	}
+
+	if kubeContext == "" {
+		kubeContext = kc.CurrentContext
+	}
+	if kubeContext == "" && len(kc.Contexts) == 1 {
+		kubeContext = kc.Contexts[0].Name
+	}
+
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == kubeContext {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig 中不存在上下文 %q", kubeContext)
+	}
+
+	cfg := &Config{}
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		cfg.Host = c.Cluster.Server
+		cfg.Insecure = c.Cluster.InsecureSkipTLSVerify
+		ca, err := loadData(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("读取集群 %s 的CA证书失败: %w", clusterName, err)
+		}
+		cfg.CAData = ca
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig 中不存在集群 %q", clusterName)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		if u.User.Exec != nil {
+			return nil, fmt.Errorf("用户 %s 使用 exec 凭据插件，请改用服务账号令牌或客户端证书", userName)
+		}
+
+		cfg.Token = u.User.Token
+		if cfg.Token == "" && u.User.TokenFile != "" {
+			token, err := os.ReadFile(u.User.TokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取用户 %s 的令牌文件失败: %w", userName, err)
+			}
+			cfg.Token = strings.TrimSpace(string(token))
+		}
+		cfg.Username = u.User.Username
+		cfg.Password = u.User.Password
+
+		var err error
+		if cfg.CertData, err = loadData(u.User.ClientCertificateData, u.User.ClientCertificate); err != nil {
+			return nil, fmt.Errorf("读取用户 %s 的客户端证书失败: %w", userName, err)
+		}
+		if cfg.KeyData, err = loadData(u.User.ClientKeyData, u.User.ClientKey); err != nil {
+			return nil, fmt.Errorf("读取用户 %s 的客户端私钥失败: %w", userName, err)
+		}
+		break
+	}
+
+	return cfg, nil
+}
+
+// loadData 读取 base64 编码的内联数据，为空时读取文件
+func loadData(data, file string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	return nil, nil
+}
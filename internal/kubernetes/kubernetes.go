@@ -0,0 +1,217 @@
+// Package kubernetes 实现 Kubernetes 数据源的 API Server 客户端和告警采集
+//
+// 客户端只读取事件、节点和 Pod，直接调用 core/v1 REST API，不依赖 client-go。
+// Collect 将 Warning 事件以及异常的节点、Pod 状况转换为 Finding，由同步服务对比上一轮结果触发或解决告警。
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// listPageSize 列表请求每页的资源数量
+const listPageSize = 500
+
+// maxErrorBodySize 错误信息中保留的响应体最大字节数
+const maxErrorBodySize = 2 << 10
+
+// Client API Server 只读客户端
+type Client struct {
+	host     string
+	token    string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewClient 根据连接配置创建客户端
+func NewClient(cfg *Config, timeout time.Duration) (*Client, error) {
+	host := strings.TrimRight(cfg.Host, "/")
+	if _, err := url.Parse(host); err != nil {
+		return nil, fmt.Errorf("无效的API Server地址: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.Insecure, // #nosec G402 -- 由 kubeconfig 的 insecure-skip-tls-verify 显式开启
+	}
+	if len(cfg.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.CAData) {
+			return nil, errors.New("解析集群CA证书失败")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(cfg.CertData) > 0 || len(cfg.KeyData) > 0 {
+		cert, err := tls.X509KeyPair(cfg.CertData, cfg.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &Client{
+		host:     host,
+		token:    cfg.Token,
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+// Ready 检查 API Server 是否就绪，旧版本集群没有 /readyz 时使用 /healthz
+func (c *Client) Ready(ctx context.Context) error {
+	err := c.get(ctx, "/readyz", nil, nil)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		err = c.get(ctx, "/healthz", nil, nil)
+	}
+	return err
+}
+
+// Version 获取 API Server 版本
+func (c *Client) Version(ctx context.Context) (*VersionInfo, error) {
+	var version VersionInfo
+	if err := c.get(ctx, "/version", nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// ListWarningEvents 获取 Warning 类型的事件，namespace 为空时获取全部命名空间
+func (c *Client) ListWarningEvents(ctx context.Context, namespace string) ([]Event, error) {
+	query := url.Values{"fieldSelector": {"type=Warning"}}
+	var events []Event
+	err := c.list(ctx, namespacedPath(namespace, "events"), query, func(items json.RawMessage) error {
+		var page []Event
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		events = append(events, page...)
+		return nil
+	})
+	return events, err
+}
+
+// ListNodes 获取全部节点
+func (c *Client) ListNodes(ctx context.Context) ([]Node, error) {
+	var nodes []Node
+	err := c.list(ctx, "/api/v1/nodes", url.Values{}, func(items json.RawMessage) error {
+		var page []Node
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		nodes = append(nodes, page...)
+		return nil
+	})
+	return nodes, err
+}
+
+// ListPods 获取未正常结束的 Pod，namespace 为空时获取全部命名空间
+func (c *Client) ListPods(ctx context.Context, namespace string) ([]Pod, error) {
+	query := url.Values{"fieldSelector": {"status.phase!=Succeeded"}}
+	var pods []Pod
+	err := c.list(ctx, namespacedPath(namespace, "pods"), query, func(items json.RawMessage) error {
+		var page []Pod
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		pods = append(pods, page...)
+		return nil
+	})
+	return pods, err
+}
+
+// namespacedPath 生成命名空间下资源的列表路径
+func namespacedPath(namespace, resource string) string {
+	if namespace == "" {
+		return "/api/v1/" + resource
+	}
+	return "/api/v1/namespaces/" + url.PathEscape(namespace) + "/" + resource
+}
+
+// list 分页获取资源列表，每页的 items 交给 appendPage 解析
+func (c *Client) list(ctx context.Context, path string, query url.Values, appendPage func(json.RawMessage) error) error {
+	query.Set("limit", fmt.Sprint(listPageSize))
+	for {
+		var page struct {
+			Metadata struct {
+				Continue string `json:"continue"`
+			} `json:"metadata"`
+			Items json.RawMessage `json:"items"`
+		}
+		if err := c.get(ctx, path, query, &page); err != nil {
+			return err
+		}
+		if len(page.Items) > 0 {
+			if err := appendPage(page.Items); err != nil {
+				return fmt.Errorf("解析 %s 响应失败: %w", path, err)
+			}
+		}
+		if page.Metadata.Continue == "" {
+			return nil
+		}
+		query.Set("continue", page.Metadata.Continue)
+	}
+}
+
+// StatusError API Server 返回的错误状态
+type StatusError struct {
+	Path string
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s 返回错误状态 %d: %s", e.Path, e.Code, e.Body)
+}
+
+// get 发送 GET 请求，out 不为空时解析响应体
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	target := c.host + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 GET %s 失败: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return &StatusError{Path: path, Code: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析 GET %s 响应失败: %w", path, err)
+	}
+	return nil
+}
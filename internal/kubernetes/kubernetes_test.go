@@ -0,0 +1,252 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestParseKubeconfig(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com:6443
+    certificate-authority-data: ` + base64.StdEncoding.EncodeToString([]byte("ca-pem")) + `
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com:6443
+    insecure-skip-tls-verify: true
+contexts:
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: reader
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: sso
+users:
+- name: reader
+  user:
+    token: prod-token
+- name: sso
+  user:
+    exec:
+      command: kubelogin
+`)
+
+	cfg, err := ParseKubeconfig(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com:6443", cfg.Host)
+	assert.Equal(t, "prod-token", cfg.Token)
+	assert.Equal(t, []byte("ca-pem"), cfg.CAData)
+	assert.False(t, cfg.Insecure)
+
+	_, err = ParseKubeconfig(data, "dev")
+	assert.ErrorContains(t, err, "exec")
+
+	_, err = ParseKubeconfig(data, "staging")
+	assert.ErrorContains(t, err, "staging")
+}
+
+func TestConfigFromDataSource_URLOverridesKubeconfig(t *testing.T) {
+	kubeconfig := `
+current-context: prod
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: reader
+users:
+- name: reader
+  user:
+    token: prod-token
+`
+	cfg, err := ConfigFromDataSource(&models.DataSourceConfig{
+		URL:        "https://proxy.example.com/k8s/prod",
+		Kubeconfig: &kubeconfig,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://proxy.example.com/k8s/prod", cfg.Host)
+	assert.Equal(t, "prod-token", cfg.Token)
+}
+
+func TestClient_ListPaginates(t *testing.T) {
+	var pages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "/api/v1/namespaces/payments/events", r.URL.Path)
+		assert.Equal(t, "type=Warning", r.URL.Query().Get("fieldSelector"))
+
+		pages++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("continue") == "" {
+			_, _ = w.Write([]byte(`{"metadata":{"continue":"next"},"items":[{"reason":"BackOff","type":"Warning"}]}`))
+			return
+		}
+		assert.Equal(t, "next", r.URL.Query().Get("continue"))
+		_, _ = w.Write([]byte(`{"metadata":{},"items":[{"reason":"Unhealthy","type":"Warning"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{Host: server.URL, Token: "secret"}, time.Second)
+	require.NoError(t, err)
+
+	events, err := client.ListWarningEvents(context.Background(), "payments")
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Unhealthy", events[1].Reason)
+}
+
+func TestClient_ReadyFallsBackToHealthz(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			_, _ = w.Write([]byte("ok"))
+		case "/version":
+			_, _ = w.Write([]byte(`{"gitVersion":"v1.15.3","platform":"linux/amd64"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{Host: server.URL}, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, client.Ready(context.Background()))
+
+	version, err := client.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.15.3", version.GitVersion)
+}
+
+// fakeSource 固定返回资源列表
+type fakeSource struct {
+	events []Event
+	nodes  []Node
+	pods   []Pod
+}
+
+func (s *fakeSource) ListWarningEvents(ctx context.Context, namespace string) ([]Event, error) {
+	return s.events, nil
+}
+
+func (s *fakeSource) ListNodes(ctx context.Context) ([]Node, error) {
+	return s.nodes, nil
+}
+
+func (s *fakeSource) ListPods(ctx context.Context, namespace string) ([]Pod, error) {
+	return s.pods, nil
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	longAgo := now.Add(-time.Hour)
+	justNow := now.Add(-time.Minute)
+
+	node := Node{Metadata: ObjectMeta{Name: "node-1"}}
+	node.Status.Conditions = []Condition{
+		{Type: "Ready", Status: "Unknown", Message: "Kubelet stopped posting node status.", LastTransitionTime: &longAgo},
+		{Type: "DiskPressure", Status: "True", LastTransitionTime: &longAgo},
+		{Type: "MemoryPressure", Status: "False"},
+	}
+
+	crashing := Pod{Metadata: ObjectMeta{
+		Name:            "api-7d9f8c6b5-x2k4p",
+		Namespace:       "payments",
+		Labels:          map[string]string{"pod-template-hash": "7d9f8c6b5"},
+		OwnerReferences: []OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f8c6b5", Controller: true}},
+	}}
+	crashing.Spec.NodeName = "node-2"
+	crashing.Status.Phase = "Running"
+	crashing.Status.ContainerStatuses = []ContainerStatus{{Name: "api", RestartCount: 7}}
+	crashing.Status.ContainerStatuses[0].State.Waiting = &ContainerStateWaiting{
+		Reason: "CrashLoopBackOff", Message: "back-off 5m0s restarting failed container",
+	}
+
+	// 刚刚变为未就绪，尚未超过阈值
+	rolling := Pod{Metadata: ObjectMeta{
+		Name:            "web-0",
+		Namespace:       "payments",
+		OwnerReferences: []OwnerReference{{Kind: "StatefulSet", Name: "web", Controller: true}},
+	}}
+	rolling.Status.Phase = "Running"
+	rolling.Status.Conditions = []Condition{{Type: "Ready", Status: "False", LastTransitionTime: &justNow}}
+
+	src := &fakeSource{
+		nodes: []Node{node},
+		pods:  []Pod{crashing, rolling},
+		events: []Event{
+			{
+				Metadata:       ObjectMeta{Namespace: "payments"},
+				InvolvedObject: ObjectReference{Kind: "Pod", Namespace: "payments", Name: "api-7d9f8c6b5-x2k4p"},
+				Reason:         "BackOff", Type: "Warning", Count: 12, LastTimestamp: &justNow,
+			},
+			{
+				Metadata:       ObjectMeta{Namespace: "payments"},
+				InvolvedObject: ObjectReference{Kind: "Pod", Namespace: "payments", Name: "web-0"},
+				Reason:         "Unhealthy", Message: "Readiness probe failed", Type: "Warning", Count: 2, LastTimestamp: &justNow,
+			},
+			{
+				Metadata:       ObjectMeta{Namespace: "payments"},
+				InvolvedObject: ObjectReference{Kind: "Pod", Namespace: "payments", Name: "web-0"},
+				Reason:         "Unhealthy", Message: "Readiness probe failed: timeout", Type: "Warning", Count: 3, LastTimestamp: &now,
+			},
+			{
+				Metadata:       ObjectMeta{Namespace: "payments"},
+				InvolvedObject: ObjectReference{Kind: "Pod", Namespace: "payments", Name: "web-0"},
+				Reason:         "FailedMount", Type: "Warning", LastTimestamp: &longAgo,
+			},
+		},
+	}
+
+	findings, err := Collect(context.Background(), src, CollectOptions{
+		EventWindow:      15 * time.Minute,
+		PodNotReadyAfter: 5 * time.Minute,
+		Now:              now,
+	})
+	require.NoError(t, err)
+
+	byKey := make(map[string]Finding)
+	for _, finding := range findings {
+		byKey[finding.Key] = finding
+	}
+	require.Len(t, byKey, 4)
+
+	ready := byKey["node:node-1:Ready"]
+	assert.Equal(t, "NodeStatusUnknown", ready.Reason)
+	assert.Equal(t, models.AlertSeverityCritical, ready.Severity)
+	assert.Equal(t, models.AlertSeverityHigh, byKey["node:node-1:DiskPressure"].Severity)
+
+	pod := byKey["pod:payments/api-7d9f8c6b5-x2k4p"]
+	assert.Equal(t, "CrashLoopBackOff", pod.Reason)
+	assert.Equal(t, int32(7), pod.Count)
+	assert.Equal(t, map[string]string{
+		"kind": "Pod", "name": "api-7d9f8c6b5-x2k4p", "reason": "CrashLoopBackOff", "namespace": "payments",
+		"workload": "api", "workload_kind": "Deployment", "node": "node-2",
+	}, pod.Labels())
+
+	// 已采集 Pod 状况的 BackOff 事件不重复采集，同一原因的事件合并
+	unhealthy := byKey["event:Pod:payments/web-0:Unhealthy"]
+	assert.Equal(t, int32(5), unhealthy.Count)
+	assert.Equal(t, "Readiness probe failed: timeout", unhealthy.Message)
+	assert.Equal(t, "web", unhealthy.Workload)
+	assert.Equal(t, "StatefulSet", unhealthy.WorkloadKind)
+}
@@ -0,0 +1,138 @@
+package kubernetes
+
+import "time"
+
+// 以下类型只包含告警采集用到的字段，与 Kubernetes core/v1 API 的 JSON 结构一致
+
+// ObjectMeta 资源元数据
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	UID               string            `json:"uid,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	OwnerReferences   []OwnerReference  `json:"ownerReferences,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+}
+
+// OwnerReference 资源的属主
+type OwnerReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Controller bool   `json:"controller,omitempty"`
+}
+
+// ObjectReference 事件关联的资源
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// EventSource 事件的上报组件
+type EventSource struct {
+	Component string `json:"component,omitempty"`
+	Host      string `json:"host,omitempty"`
+}
+
+// Event 集群事件
+type Event struct {
+	Metadata       ObjectMeta      `json:"metadata"`
+	InvolvedObject ObjectReference `json:"involvedObject"`
+	Reason         string          `json:"reason"`
+	Message        string          `json:"message"`
+	Type           string          `json:"type"`
+	Count          int32           `json:"count,omitempty"`
+	Source         EventSource     `json:"source,omitempty"`
+	FirstTimestamp *time.Time      `json:"firstTimestamp,omitempty"`
+	LastTimestamp  *time.Time      `json:"lastTimestamp,omitempty"`
+	EventTime      *time.Time      `json:"eventTime,omitempty"`
+	Series         *struct {
+		Count            int32      `json:"count"`
+		LastObservedTime *time.Time `json:"lastObservedTime,omitempty"`
+	} `json:"series,omitempty"`
+}
+
+// LastSeen 事件最后一次发生的时间，兼容 events.k8s.io 写入的新格式
+func (e *Event) LastSeen() time.Time {
+	switch {
+	case e.Series != nil && e.Series.LastObservedTime != nil:
+		return *e.Series.LastObservedTime
+	case e.LastTimestamp != nil:
+		return *e.LastTimestamp
+	case e.EventTime != nil:
+		return *e.EventTime
+	case e.FirstTimestamp != nil:
+		return *e.FirstTimestamp
+	default:
+		return e.Metadata.CreationTimestamp
+	}
+}
+
+// Occurrences 事件发生的次数
+func (e *Event) Occurrences() int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	if e.Count > 0 {
+		return e.Count
+	}
+	return 1
+}
+
+// Condition 节点或 Pod 的状况
+type Condition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// Node 集群节点
+type Node struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Unschedulable bool `json:"unschedulable,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []Condition `json:"conditions,omitempty"`
+	} `json:"status"`
+}
+
+// ContainerStateWaiting 容器等待启动的原因
+type ContainerStateWaiting struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ContainerStatus 容器状态
+type ContainerStatus struct {
+	Name         string `json:"name"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+	State        struct {
+		Waiting *ContainerStateWaiting `json:"waiting,omitempty"`
+	} `json:"state"`
+}
+
+// Pod 容器组
+type Pod struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName string `json:"nodeName,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string            `json:"phase"`
+		Reason            string            `json:"reason,omitempty"`
+		Message           string            `json:"message,omitempty"`
+		Conditions        []Condition       `json:"conditions,omitempty"`
+		ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty"`
+	} `json:"status"`
+}
+
+// VersionInfo API Server 版本
+type VersionInfo struct {
+	GitVersion string `json:"gitVersion"`
+	Platform   string `json:"platform"`
+}
@@ -0,0 +1,272 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	// ErrLockLost 持有期间失去锁：锁已过期被其他实例获取，或与 Redis 失联超过租约有效期
+	ErrLockLost = errors.New("lock lost")
+	// ErrStaleToken 隔离令牌已过期，锁已被更新的持有者获取
+	ErrStaleToken = errors.New("stale fencing token")
+)
+
+// DefaultReleaseTimeout Hold 结束后释放锁的超时时间
+const DefaultReleaseTimeout = 2 * time.Second
+
+// leaseSafetyMargin 本地判断租约到期时预留的时钟误差，续期在到期前这段时间内仍未成功即视为失去锁
+const leaseSafetyMargin = 500 * time.Millisecond
+
+// 获取锁并递增隔离令牌，锁已被持有时返回 0
+var acquireLeaseScript = redis.NewScript(`
+	if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+		return redis.call("incr", KEYS[2])
+	end
+	return 0
+`)
+
+// 仅在锁仍由自己持有时续期
+var extendLeaseScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("pexpire", KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// 仅在锁仍由自己持有时删除
+var releaseLeaseScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("del", KEYS[1])
+	end
+	return 0
+`)
+
+// Lease 持有中的锁租约。每次获取锁都会分配单调递增的隔离令牌（fencing token），
+// 下游写入携带令牌并通过 CheckToken 校验，可拒绝已失去锁的旧持有者的迟到写入
+type Lease struct {
+	lock  *RedisLock
+	key   string
+	value string
+	token int64
+	ttl   time.Duration
+
+	mu sync.Mutex
+	// validUntil 本地估计的租约到期时间，按续期请求发出的时间计算，偏保守
+	validUntil time.Time
+	released   bool
+}
+
+// AcquireLease 获取锁租约，锁已被其他持有者持有时返回 ErrLockNotAcquired
+func (r *RedisLock) AcquireLease(ctx context.Context, key string, ttl time.Duration) (*Lease, error) {
+	value := generateLockValue()
+	start := time.Now()
+
+	token, err := acquireLeaseScript.Run(ctx, r.client, []string{r.prefix + key, r.fenceKey(key)}, value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if token == 0 {
+		return nil, ErrLockNotAcquired
+	}
+
+	return &Lease{
+		lock:       r,
+		key:        key,
+		value:      value,
+		token:      token,
+		ttl:        ttl,
+		validUntil: start.Add(ttl),
+	}, nil
+}
+
+// CheckToken 校验隔离令牌是否仍是该锁最新的令牌，锁已被更新的持有者获取时返回 ErrStaleToken
+func (r *RedisLock) CheckToken(ctx context.Context, key string, token int64) error {
+	current, err := r.client.Get(ctx, r.fenceKey(key)).Int64()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get fencing token: %w", err)
+	}
+	if current > token {
+		return ErrStaleToken
+	}
+	return nil
+}
+
+// fenceKey 获取锁的隔离令牌计数器键，计数器不设过期时间，保证令牌在锁过期后仍单调递增
+func (r *RedisLock) fenceKey(key string) string {
+	return r.prefix + key + ":fence"
+}
+
+// Key 获取锁的键（不含前缀）
+func (l *Lease) Key() string {
+	return l.key
+}
+
+// Token 获取本次获取锁分配的隔离令牌
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Valid 按本地估计检查租约是否仍然有效
+func (l *Lease) Valid() bool {
+	return l.remaining() > 0
+}
+
+// Extend 续期租约，锁已过期或已被其他持有者获取时返回 ErrLockNotHeld
+func (l *Lease) Extend(ctx context.Context) error {
+	start := time.Now()
+	result, err := extendLeaseScript.Run(ctx, l.lock.client, []string{l.lock.prefix + l.key}, l.value, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to extend lock: %w", err)
+	}
+	if result == 0 {
+		l.expire()
+		return ErrLockNotHeld
+	}
+
+	l.mu.Lock()
+	l.validUntil = start.Add(l.ttl)
+	l.mu.Unlock()
+	return nil
+}
+
+// Release 释放租约，只删除自己持有的锁；锁已过期或已被其他持有者获取时返回 ErrLockNotHeld
+func (l *Lease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.released {
+		l.mu.Unlock()
+		return nil
+	}
+	l.released = true
+	l.mu.Unlock()
+
+	result, err := releaseLeaseScript.Run(ctx, l.lock.client, []string{l.lock.prefix + l.key}, l.value).Int64()
+	l.expire()
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	if result == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// KeepAlive 启动看门狗，每隔 interval 续期一次作为持有者心跳，返回的 ctx 在失去锁时以 ErrLockLost 取消。
+// 续期被拒绝时立即取消；续期因 Redis 不可达失败时持续重试，直到本地估计的租约到期前 leaseSafetyMargin 仍未成功才取消，
+// 此时锁可能即将被其他实例获取，调用方应停止处理。调用返回的 stop 结束看门狗，不释放锁
+func (l *Lease) KeepAlive(ctx context.Context, interval time.Duration) (context.Context, func()) {
+	if interval <= 0 || interval >= l.ttl {
+		interval = l.ttl / 3
+	}
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		deadline := time.NewTimer(l.remaining() - leaseSafetyMargin)
+		defer deadline.Stop()
+
+		for {
+			select {
+			case <-leaseCtx.Done():
+				return
+			case <-deadline.C:
+				cancel(ErrLockLost)
+				return
+			case <-ticker.C:
+				remaining := l.remaining() - leaseSafetyMargin
+				if remaining <= 0 {
+					cancel(ErrLockLost)
+					return
+				}
+				extendCtx, extendCancel := context.WithTimeout(leaseCtx, minDuration(interval, remaining))
+				err := l.Extend(extendCtx)
+				extendCancel()
+				if errors.Is(err, ErrLockNotHeld) {
+					cancel(ErrLockLost)
+					return
+				}
+				if err == nil {
+					if !deadline.Stop() {
+						select {
+						case <-deadline.C:
+						default:
+						}
+					}
+					deadline.Reset(l.remaining() - leaseSafetyMargin)
+				}
+			}
+		}
+	}()
+
+	return leaseCtx, func() {
+		cancel(context.Canceled)
+		<-done
+	}
+}
+
+// remaining 获取本地估计的租约剩余时间
+func (l *Lease) remaining() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Until(l.validUntil)
+}
+
+// expire 将租约标记为本地已过期
+func (l *Lease) expire() {
+	l.mu.Lock()
+	l.validUntil = time.Time{}
+	l.mu.Unlock()
+}
+
+type tokenContextKey struct{}
+
+// TokenFromContext 获取 Hold 执行期间的隔离令牌，不在 Hold 中执行时返回 false
+func TokenFromContext(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(int64)
+	return token, ok
+}
+
+// Hold 获取锁并在持有期间执行 fn，适用于执行时间可能超过 TTL 的长任务。执行期间由看门狗按 RenewInterval 续期，
+// 失去锁时取消 fn 的 ctx 并返回 ErrLockLost；fn 结束后释放锁。隔离令牌可通过 TokenFromContext 获取。
+// 锁已被其他持有者持有时不执行 fn，返回 ErrLockNotAcquired
+func (r *RedisLock) Hold(ctx context.Context, key string, fn func(ctx context.Context) error, opts ...LockOption) error {
+	options := applyLockOptions(opts...)
+
+	lease, err := r.AcquireLease(ctx, key, options.TTL)
+	if err != nil {
+		return err
+	}
+	leaseCtx, stop := lease.KeepAlive(ctx, options.RenewInterval)
+
+	err = fn(context.WithValue(leaseCtx, tokenContextKey{}, lease.Token()))
+	lost := errors.Is(context.Cause(leaseCtx), ErrLockLost)
+	stop()
+
+	// 释放锁不受调用方 ctx 取消的影响，避免锁一直保留到过期
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultReleaseTimeout)
+	defer cancel()
+	if releaseErr := lease.Release(releaseCtx); releaseErr != nil && !errors.Is(releaseErr, ErrLockNotHeld) && err == nil {
+		err = releaseErr
+	}
+
+	if lost {
+		return errors.Join(ErrLockLost, err)
+	}
+	return err
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
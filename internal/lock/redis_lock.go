@@ -127,25 +127,25 @@ func (r *RedisLock) GetTTL(ctx context.Context, key string) (time.Duration, erro
 	return ttl, nil
 }
 
-// RedisMutex Redis分布式互斥锁
+// RedisMutex Redis分布式互斥锁，基于锁租约实现，只释放和续期自己持有的锁
 type RedisMutex struct {
-	lock   *RedisLock
-	key    string
-	value  string
-	opts   *LockOptions
-	mu     sync.Mutex
-	locked bool
-	cancel context.CancelFunc
+	lock  *RedisLock
+	key   string
+	opts  *LockOptions
+	mu    sync.Mutex
+	lease *Lease
+	// lost 自动续期时失去锁后被取消
+	lost context.Context
+	stop func()
 }
 
 // NewRedisMutex 创建Redis分布式互斥锁
 func NewRedisMutex(client *redis.Client, key string, opts ...LockOption) *RedisMutex {
 	options := applyLockOptions(opts...)
 	return &RedisMutex{
-		lock:  NewRedisLock(client, "mutex:"),
-		key:   key,
-		value: generateLockValue(),
-		opts:  options,
+		lock: NewRedisLock(client, "mutex:"),
+		key:  key,
+		opts: options,
 	}
 }
 
@@ -154,27 +154,20 @@ func (m *RedisMutex) Lock(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
-	if m.locked {
+	if m.lease != nil {
 		return errors.New("mutex already locked")
 	}
 	
 	retries := 0
 	for {
-		acquired, err := m.lock.Acquire(ctx, m.key, m.opts.TTL)
-		if err != nil {
-			return err
-		}
-		
-		if acquired {
-			m.locked = true
-			
-			// 启动自动续期
-			if m.opts.AutoRenew {
-				m.startAutoRenew(ctx)
-			}
-			
+		lease, err := m.lock.AcquireLease(ctx, m.key, m.opts.TTL)
+		if err == nil {
+			m.hold(lease)
 			return nil
 		}
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return err
+		}
 		
 		// 检查重试次数
 		if retries >= m.opts.MaxRetries {
@@ -198,25 +191,20 @@ func (m *RedisMutex) TryLock(ctx context.Context) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
-	if m.locked {
+	if m.lease != nil {
 		return false, errors.New("mutex already locked")
 	}
 	
-	acquired, err := m.lock.Acquire(ctx, m.key, m.opts.TTL)
+	lease, err := m.lock.AcquireLease(ctx, m.key, m.opts.TTL)
+	if errors.Is(err, ErrLockNotAcquired) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 	
-	if acquired {
-		m.locked = true
-		
-		// 启动自动续期
-		if m.opts.AutoRenew {
-			m.startAutoRenew(ctx)
-		}
-	}
-	
-	return acquired, nil
+	m.hold(lease)
+	return true, nil
 }
 
 // Unlock 解锁
@@ -224,23 +212,20 @@ func (m *RedisMutex) Unlock(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
-	if !m.locked {
+	if m.lease == nil {
 		return ErrLockNotHeld
 	}
 	
 	// 停止自动续期
-	if m.cancel != nil {
-		m.cancel()
-		m.cancel = nil
+	if m.stop != nil {
+		m.stop()
+		m.stop = nil
 	}
 	
-	err := m.lock.Release(ctx, m.key)
-	if err != nil {
-		return err
-	}
-	
-	m.locked = false
-	return nil
+	lease := m.lease
+	m.lease = nil
+	m.lost = nil
+	return lease.Release(ctx)
 }
 
 // IsLocked 检查是否已锁定
@@ -248,34 +233,44 @@ func (m *RedisMutex) IsLocked(ctx context.Context) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
-	if !m.locked {
+	if m.lease == nil {
 		return false, nil
 	}
 	
 	return m.lock.IsLocked(ctx, m.key)
 }
 
-// startAutoRenew 启动自动续期
-func (m *RedisMutex) startAutoRenew(ctx context.Context) {
-	ctx, cancel := context.WithCancel(ctx)
-	m.cancel = cancel
+// Token 获取当前持有的锁的隔离令牌，未加锁时返回 false
+func (m *RedisMutex) Token() (int64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	
-	go func() {
-		ticker := time.NewTicker(m.opts.RenewInterval)
-		defer ticker.Stop()
-		
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := m.lock.Extend(ctx, m.key, m.opts.TTL); err != nil {
-					// 续期失败，停止自动续期
-					return
-				}
-			}
-		}
-	}()
+	if m.lease == nil {
+		return 0, false
+	}
+	return m.lease.Token(), true
+}
+
+// Lost 返回失去锁时关闭的通道，未开启自动续期时按租约到期时间关闭，未加锁时返回 nil
+func (m *RedisMutex) Lost() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	if m.lost == nil {
+		return nil
+	}
+	return m.lost.Done()
+}
+
+// hold 记录已获取的租约，开启自动续期时启动看门狗。看门狗不使用加锁时的 ctx，
+// 由 Unlock 停止，避免加锁请求结束后续期随之停止
+func (m *RedisMutex) hold(lease *Lease) {
+	m.lease = lease
+	if m.opts.AutoRenew {
+		m.lost, m.stop = lease.KeepAlive(context.Background(), m.opts.RenewInterval)
+		return
+	}
+	m.lost, m.stop = context.WithTimeout(context.Background(), lease.remaining())
 }
 
 // generateLockValue 生成锁值
@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"pulse/internal/pkg/apierror"
 )
 
 // JWTClaims JWT声明结构
@@ -16,27 +17,48 @@ type JWTClaims struct {
 	Username string   `json:"username"`
 	Roles    []string `json:"roles"`
 	Email    string   `json:"email"`
+	// 模拟登录令牌中执行模拟的管理员和权限范围，普通令牌为空
+	ImpersonatorID     string `json:"impersonator_id,omitempty"`
+	ImpersonatorName   string `json:"impersonator_name,omitempty"`
+	ImpersonationScope string `json:"impersonation_scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Impersonator 执行模拟登录的管理员
+type Impersonator struct {
+	ID    string
+	Name  string
+	Scope string
+}
+
 // AuthService 认证服务接口
 type AuthService interface {
 	GenerateToken(userID, username, email string, roles []string) (string, error)
+	// GenerateImpersonationToken 生成以用户身份访问的模拟登录令牌，令牌中记录执行模拟的管理员
+	GenerateImpersonationToken(impersonator Impersonator, userID, username, email string, roles []string, expiresAt time.Time) (string, error)
 	ValidateToken(tokenString string) (*JWTClaims, error)
-	ValidateAPIKey(apiKey string) (string, error)
+	ValidateAPIKey(apiKey string) (*APIKeyPrincipal, error)
+	// AddAPIKey 注册 API Key，需在处理请求之前调用
+	AddAPIKey(apiKey string, principal APIKeyPrincipal)
+}
+
+// APIKeyPrincipal API Key 对应的用户和权限范围
+type APIKeyPrincipal struct {
+	UserID string
+	Scopes []string
 }
 
 // JWTAuthService JWT认证服务实现
 type JWTAuthService struct {
 	secret  []byte
-	apiKeys map[string]string // apiKey -> userID
+	apiKeys map[string]APIKeyPrincipal
 }
 
 // NewJWTAuthService 创建JWT认证服务
 func NewJWTAuthService(secret string, expiration time.Duration) *JWTAuthService {
 	return &JWTAuthService{
 		secret:  []byte(secret),
-		apiKeys: make(map[string]string), // 初始化空的API Keys映射
+		apiKeys: make(map[string]APIKeyPrincipal), // 初始化空的API Keys映射
 	}
 }
 
@@ -62,6 +84,31 @@ func (j *JWTAuthService) GenerateToken(userID, username, email string, roles []s
 	return token.SignedString(j.secret)
 }
 
+// GenerateImpersonationToken 生成模拟登录令牌，有效期由调用方限定
+func (j *JWTAuthService) GenerateImpersonationToken(impersonator Impersonator, userID, username, email string, roles []string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:             userID,
+		Username:           username,
+		Roles:              roles,
+		Email:              email,
+		ImpersonatorID:     impersonator.ID,
+		ImpersonatorName:   impersonator.Name,
+		ImpersonationScope: impersonator.Scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "alert-management-platform",
+			Subject:   userID,
+			Audience:  []string{"alert-management-api"},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.secret)
+}
+
 // ValidateToken 验证JWT Token
 func (j *JWTAuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -83,11 +130,19 @@ func (j *JWTAuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 }
 
 // ValidateAPIKey 验证API Key
-func (j *JWTAuthService) ValidateAPIKey(apiKey string) (string, error) {
-	if userID, exists := j.apiKeys[apiKey]; exists {
-		return userID, nil
+func (j *JWTAuthService) ValidateAPIKey(apiKey string) (*APIKeyPrincipal, error) {
+	if principal, exists := j.apiKeys[apiKey]; exists {
+		return &principal, nil
+	}
+	return nil, fmt.Errorf("invalid API key")
+}
+
+// AddAPIKey 注册API Key，未指定权限范围时拥有全部权限
+func (j *JWTAuthService) AddAPIKey(apiKey string, principal APIKeyPrincipal) {
+	if len(principal.Scopes) == 0 {
+		principal.Scopes = []string{ScopeAll}
 	}
-	return "", fmt.Errorf("invalid API key")
+	j.apiKeys[apiKey] = principal
 }
 
 // JWTAuthMiddleware JWT认证中间件
@@ -96,10 +151,7 @@ func JWTAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		// 从Authorization头获取token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "missing_authorization_header",
-				"message": "Authorization header is required",
-			})
+			apierror.RespondCode(c, apierror.CodeMissingAuthorizationHeader, "", nil)
 			c.Abort()
 			return
 		}
@@ -107,10 +159,7 @@ func JWTAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		// 检查Bearer token格式
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "invalid_authorization_format",
-				"message": "Authorization header must be in format: Bearer <token>",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAuthorizationFormat, "", nil)
 			c.Abort()
 			return
 		}
@@ -118,11 +167,7 @@ func JWTAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		tokenString := parts[1]
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "invalid_token",
-				"message": "Invalid or expired token",
-				"details": err.Error(),
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidToken, "", err.Error())
 			c.Abort()
 			return
 		}
@@ -144,26 +189,21 @@ func APIKeyAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		// 从X-API-Key头获取API Key
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "missing_api_key",
-				"message": "X-API-Key header is required",
-			})
+			apierror.RespondCode(c, apierror.CodeMissingAPIKey, "", nil)
 			c.Abort()
 			return
 		}
 
-		userID, err := authService.ValidateAPIKey(apiKey)
+		principal, err := authService.ValidateAPIKey(apiKey)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "invalid_api_key",
-				"message": "Invalid API key",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAPIKey, "", nil)
 			c.Abort()
 			return
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", userID)
+		c.Set("user_id", principal.UserID)
+		c.Set("api_key_scopes", principal.Scopes)
 		c.Set("auth_method", "api_key")
 
 		c.Next()
@@ -195,8 +235,9 @@ func OptionalAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		// 尝试API Key认证
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey != "" {
-			if userID, err := authService.ValidateAPIKey(apiKey); err == nil {
-				c.Set("user_id", userID)
+			if principal, err := authService.ValidateAPIKey(apiKey); err == nil {
+				c.Set("user_id", principal.UserID)
+				c.Set("api_key_scopes", principal.Scopes)
 				c.Set("auth_method", "api_key")
 				c.Next()
 				return
@@ -234,8 +275,9 @@ func RequireAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		// 尝试API Key认证
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey != "" {
-			if userID, err := authService.ValidateAPIKey(apiKey); err == nil {
-				c.Set("user_id", userID)
+			if principal, err := authService.ValidateAPIKey(apiKey); err == nil {
+				c.Set("user_id", principal.UserID)
+				c.Set("api_key_scopes", principal.Scopes)
 				c.Set("auth_method", "api_key")
 				c.Next()
 				return
@@ -243,10 +285,7 @@ func RequireAuthMiddleware(authService AuthService) gin.HandlerFunc {
 		}
 
 		// 认证失败
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "authentication_required",
-			"message": "Valid JWT token or API key is required",
-		})
+		apierror.RespondCode(c, apierror.CodeAuthenticationRequired, "", nil)
 		c.Abort()
 	}
 }
\ No newline at end of file
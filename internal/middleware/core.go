@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+
+	"pulse/internal/pkg/apierror"
 )
 
 // RequestIDMiddleware 请求ID中间件
@@ -275,11 +278,7 @@ func RecoveryMiddleware(config RecoveryConfig) gin.HandlerFunc {
 				config.Logger.WithFields(logFields).Error("Panic recovered")
 
 				// 返回错误响应
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":      "internal_server_error",
-					"message":    "An internal server error occurred",
-					"request_id": requestID,
-				})
+				apierror.RespondCode(c, apierror.CodeInternalError, "", nil)
 
 				c.Abort()
 			}
@@ -291,15 +290,49 @@ func RecoveryMiddleware(config RecoveryConfig) gin.HandlerFunc {
 
 // TimeoutConfig 超时中间件配置
 type TimeoutConfig struct {
+	// Timeout 未按路由类别配置时限时使用的处理时限
 	Timeout time.Duration
-	Message string
+	// ReadTimeout、WriteTimeout 和 ExportTimeout 分别为读请求（GET、HEAD）、写请求和导出导入类请求的处理时限，
+	// 为 0 时使用 Timeout。时限写入请求 context，仓储层查询随请求结束一起取消
+	ReadTimeout   time.Duration
+	WriteTimeout  time.Duration
+	ExportTimeout time.Duration
+	// ExportRoutes 导出导入类请求的路由模板，如 /api/v1/tickets/:id/attachments/:attachment_id/download
+	ExportRoutes []string
+	Message      string
+	// SkipPaths 不限制处理时间的路径，如长时间运行的流式导出
+	SkipPaths []string
+}
+
+// timeoutFor 按路由类别获取请求的处理时限
+func (config TimeoutConfig) timeoutFor(c *gin.Context) time.Duration {
+	timeout := config.WriteTimeout
+	route := c.FullPath()
+	switch {
+	case route != "" && slices.Contains(config.ExportRoutes, route):
+		timeout = config.ExportTimeout
+	case c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead:
+		timeout = config.ReadTimeout
+	}
+	if timeout <= 0 {
+		return config.Timeout
+	}
+	return timeout
 }
 
 // TimeoutMiddleware 超时中间件
 func TimeoutMiddleware(config TimeoutConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, skipPath := range config.SkipPaths {
+			if path == skipPath {
+				c.Next()
+				return
+			}
+		}
+
 		// 创建带超时的context
-		ctx, cancel := context.WithTimeout(c.Request.Context(), config.Timeout)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), config.timeoutFor(c))
 		defer cancel()
 
 		// 替换请求的context
@@ -319,10 +352,7 @@ func TimeoutMiddleware(config TimeoutConfig) gin.HandlerFunc {
 			return
 		case <-ctx.Done():
 			// 超时
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error":   "request_timeout",
-				"message": config.Message,
-			})
+			apierror.RespondCode(c, apierror.CodeRequestTimeout, "", config.Message)
 			c.Abort()
 			return
 		}
@@ -333,7 +363,8 @@ func TimeoutMiddleware(config TimeoutConfig) gin.HandlerFunc {
 func HealthCheckMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 为健康检查端点设置特殊标记
-		if c.Request.URL.Path == "/health" || c.Request.URL.Path == "/status" {
+		path := c.Request.URL.Path
+		if path == "/health" || path == "/status" || strings.HasPrefix(path, "/health/") {
 			c.Set("skip_auth", true)
 			c.Set("skip_rate_limit", true)
 		}
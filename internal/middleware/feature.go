@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// featureCheckerKey 上下文中功能开关检查函数的键
+const featureCheckerKey = "feature_checker"
+
+// FeatureChecker 判断指定功能是否对当前请求启用
+type FeatureChecker func(c *gin.Context, key string) bool
+
+// FeatureFlagMiddleware 将功能开关检查函数注入请求上下文，处理函数通过 FeatureEnabled 按开关切换新旧实现
+func FeatureFlagMiddleware(checker FeatureChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(featureCheckerKey, checker)
+		c.Next()
+	}
+}
+
+// RequireFeatureMiddleware 功能未对当前请求启用时返回404，对未放量的用户隐藏灰度中的接口
+func RequireFeatureMiddleware(checker FeatureChecker, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !checker(c, key) {
+			apierror.RespondCode(c, apierror.CodeNotFound, "", gin.H{"feature": key})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FeatureEnabled 判断功能是否对当前请求启用，未注册 FeatureFlagMiddleware 时视为未启用
+func FeatureEnabled(c *gin.Context, key string) bool {
+	value, ok := c.Get(featureCheckerKey)
+	if !ok {
+		return false
+	}
+	checker, ok := value.(FeatureChecker)
+	return ok && checker(c, key)
+}
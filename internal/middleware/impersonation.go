@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+const (
+	// ImpersonatedByHeader 模拟登录会话的响应头，值为执行模拟的管理员，前端据此显示模拟登录提示
+	ImpersonatedByHeader = "X-Impersonated-By"
+	// ImpersonationExpiresHeader 模拟登录令牌的过期时间（RFC 3339）
+	ImpersonationExpiresHeader = "X-Impersonation-Expires"
+
+	impersonationScopeReadOnly = "read_only"
+)
+
+// ImpersonationAuditor 记录模拟登录会话中的请求，在请求处理完成后调用，被拒绝的请求同样记录
+type ImpersonationAuditor func(c *gin.Context, claims *JWTClaims)
+
+// ImpersonationMiddleware 模拟登录会话的响应头中标明执行模拟的管理员和令牌过期时间，
+// 只读会话拒绝写请求，请求处理完成后调用 auditor 记录审计日志。需注册在认证中间件之后
+func ImpersonationMiddleware(auditor ImpersonationAuditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := ImpersonationClaims(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Header(ImpersonatedByHeader, claims.ImpersonatorName)
+		if claims.ExpiresAt != nil {
+			c.Header(ImpersonationExpiresHeader, claims.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+
+		if claims.ImpersonationScope == impersonationScopeReadOnly && isMutatingMethod(c.Request.Method) {
+			apierror.RespondCode(c, apierror.CodeImpersonationReadOnly, "", nil)
+			c.Abort()
+		} else {
+			c.Next()
+		}
+
+		if auditor != nil {
+			auditor(c, claims)
+		}
+	}
+}
+
+// ImpersonationClaims 获取模拟登录令牌的声明，不是模拟登录会话的请求返回 false
+func ImpersonationClaims(c *gin.Context) (*JWTClaims, bool) {
+	value, ok := c.Get("jwt_claims")
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*JWTClaims)
+	if !ok || claims.ImpersonatorID == "" {
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authService := NewJWTAuthService("test-secret", time.Hour)
+	var audited []int
+	router := gin.New()
+	router.Use(RequireAuthMiddleware(authService))
+	router.Use(ImpersonationMiddleware(func(c *gin.Context, claims *JWTClaims) {
+		assert.Equal(t, "admin-1", claims.ImpersonatorID)
+		audited = append(audited, c.Writer.Status())
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/alerts", ok)
+	router.POST("/api/v1/alerts", ok)
+
+	serve := func(method, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/api/v1/alerts", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 普通令牌不受影响，也不记录审计日志
+	token, err := authService.GenerateToken("u1", "alice", "alice@example.com", []string{"viewer"})
+	require.NoError(t, err)
+	w := serve(http.MethodPost, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(ImpersonatedByHeader))
+	assert.Empty(t, audited)
+
+	// 只读的模拟登录会话可以读取，写请求被拒绝，两者均记录审计日志
+	expiresAt := time.Now().Add(30 * time.Minute)
+	token, err = authService.GenerateImpersonationToken(Impersonator{ID: "admin-1", Name: "root", Scope: "read_only"},
+		"u1", "alice", "alice@example.com", []string{"viewer"}, expiresAt)
+	require.NoError(t, err)
+	w = serve(http.MethodGet, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "root", w.Header().Get(ImpersonatedByHeader))
+	assert.Equal(t, expiresAt.UTC().Format(time.RFC3339), w.Header().Get(ImpersonationExpiresHeader))
+	w = serve(http.MethodPost, token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "IMPERSONATION_READ_ONLY")
+	assert.Equal(t, []int{http.StatusOK, http.StatusForbidden}, audited)
+
+	// 可写的会话允许写请求
+	token, err = authService.GenerateImpersonationToken(Impersonator{ID: "admin-1", Name: "root", Scope: "read_write"},
+		"u1", "alice", "alice@example.com", []string{"viewer"}, expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, token).Code)
+	assert.Len(t, audited, 3)
+}
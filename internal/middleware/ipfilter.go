@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/ipfilter"
+)
+
+// IPFilterConfig 来源IP过滤配置
+type IPFilterConfig struct {
+	Filter *ipfilter.Filter
+	Logger *logrus.Logger
+}
+
+// IPFilterMiddleware 来源IP过滤中间件，拒绝黑名单中或不在白名单中的请求
+// 客户端IP取自 gin 的 ClientIP，部署在代理之后时需正确配置可信代理
+func IPFilterMiddleware(config IPFilterConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientIP := c.ClientIP()
+		if config.Filter.Allowed(clientIP) {
+			c.Next()
+			return
+		}
+
+		if config.Logger != nil {
+			config.Logger.WithFields(logrus.Fields{
+				"client_ip":  clientIP,
+				"path":       c.Request.URL.Path,
+				"request_id": c.GetString("request_id"),
+			}).Warn("Request rejected by IP filter")
+		}
+
+		apierror.RespondCode(c, apierror.CodeIPNotAllowed, "", gin.H{
+			"client_ip": clientIP,
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/pkg/ipfilter"
+)
+
+func TestIPFilterMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	filter, err := ipfilter.New([]string{"10.0.0.0/8"}, []string{"10.0.0.66"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(IPFilterMiddleware(IPFilterConfig{Filter: filter}))
+	router.POST("/api/v1/ingest/:id/alerts", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"10.1.2.3:5000", http.StatusCreated},
+		{"10.0.0.66:5000", http.StatusForbidden},
+		{"203.0.113.9:5000", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ingest/prom/alerts", nil)
+		req.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.want, w.Code, tt.remoteAddr)
+		if tt.want == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), `"code":"IP_NOT_ALLOWED"`)
+		}
+	}
+}
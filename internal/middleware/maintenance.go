@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/maintenance"
+)
+
+// MaintenanceConfig 维护模式中间件配置
+type MaintenanceConfig struct {
+	Mode *maintenance.Mode
+	// SkipPaths 维护期间仍放行的路由（gin 路由模板），如关闭维护模式的接口和自行暂存请求的告警接入接口
+	SkipPaths []string
+}
+
+// MaintenanceMiddleware 维护模式下拒绝写请求，返回 503 和 Retry-After，读请求不受影响
+func MaintenanceMiddleware(config MaintenanceConfig) gin.HandlerFunc {
+	skip := make(map[string]bool, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if !config.Mode.Enabled() || !isMutatingMethod(c.Request.Method) || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		status := config.Mode.Status()
+		c.Header("Retry-After", strconv.Itoa(status.RetryAfter))
+		apierror.RespondCode(c, apierror.CodeMaintenanceMode, "", gin.H{
+			"reason":      status.Reason,
+			"retry_after": status.RetryAfter,
+		})
+		c.Abort()
+	}
+}
+
+// isMutatingMethod 是否为会修改数据的请求方法
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
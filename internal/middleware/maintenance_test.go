@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/pkg/maintenance"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mode := maintenance.New(2 * time.Minute)
+	router := gin.New()
+	router.Use(MaintenanceMiddleware(MaintenanceConfig{
+		Mode:      mode,
+		SkipPaths: []string{"/api/v1/ingest/:id/alerts"},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/alerts", ok)
+	router.POST("/api/v1/alerts", ok)
+	router.POST("/api/v1/ingest/:id/alerts", ok)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/alerts").Code)
+
+	_, err := mode.Enable(context.Background(), "数据库升级", "admin")
+	require.NoError(t, err)
+
+	w := serve(http.MethodPost, "/api/v1/alerts")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "120", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"MAINTENANCE_MODE"`)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/alerts").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/ingest/prom/alerts").Code)
+}
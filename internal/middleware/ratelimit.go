@@ -2,130 +2,453 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"net/http"
+	"math"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+
+	"pulse/internal/pkg/apierror"
 )
 
+// RateLimitPolicy 令牌桶限流策略，每个 Window 补充 Limit 个令牌，桶容量为 Burst
+type RateLimitPolicy struct {
+	Limit  int           // 每个时间窗口补充的令牌数
+	Window time.Duration // 时间窗口
+	Burst  int           // 桶容量，为 0 时等于 Limit
+}
+
+// capacity 返回桶容量
+func (p RateLimitPolicy) capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// ratePerMillisecond 返回每毫秒补充的令牌数
+func (p RateLimitPolicy) ratePerMillisecond() float64 {
+	return float64(p.Limit) / float64(p.Window.Milliseconds())
+}
+
+// header 返回 RateLimit-Policy 响应头的值
+func (p RateLimitPolicy) header() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", p.Limit, int(p.Window.Seconds()), p.capacity())
+}
+
+// RouteRateLimitPolicy 路由类别的限流策略，匹配的请求使用独立的令牌桶
+type RouteRateLimitPolicy struct {
+	Class        string   // 路由类别名称，用于区分令牌桶
+	Methods      []string // 匹配的请求方法，为空时匹配所有方法
+	PathPrefixes []string // 匹配的路由前缀
+	Policy       RateLimitPolicy
+}
+
+// matches 判断请求是否属于该路由类别
+func (r RouteRateLimitPolicy) matches(method, path string) bool {
+	if len(r.Methods) > 0 {
+		matched := false
+		for _, m := range r.Methods {
+			if strings.EqualFold(m, method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, prefix := range r.PathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitResult 单次限流检查结果
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // 被拒绝时距离下一个可用令牌的时间
+	Reset      time.Duration // 距离令牌桶补满的时间
+}
+
+// RateLimiter 限流器
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error)
+}
+
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
-	RedisClient    *redis.Client
-	Logger         *logrus.Logger
-	KeyPrefix      string        // Redis键前缀
-	DefaultLimit   int           // 默认限制次数
-	DefaultWindow  time.Duration // 默认时间窗口
-	SkipSuccessful bool          // 是否跳过成功请求的计数
-	KeyGenerator   func(*gin.Context) string // 自定义键生成器
+	Limiter       RateLimiter
+	Logger        *logrus.Logger
+	KeyPrefix     string                    // 限流键前缀
+	DefaultPolicy RateLimitPolicy           // 未匹配路由类别时使用的策略
+	Routes        []RouteRateLimitPolicy    // 路由类别策略，按顺序匹配第一个
+	KeyGenerator  func(*gin.Context) string // 自定义限流主体，默认依次使用 API Key、用户ID、客户端IP
 }
 
-// DefaultRateLimitConfig 默认限流配置
+// DefaultRateLimitConfig 默认限流配置，Redis 不可用时使用单实例内存限流
 func DefaultRateLimitConfig(redisClient *redis.Client) RateLimitConfig {
+	var limiter RateLimiter
+	if redisClient != nil {
+		limiter = NewRedisRateLimiter(redisClient)
+	} else {
+		limiter = NewMemoryRateLimiter()
+	}
+
 	return RateLimitConfig{
-		RedisClient:    redisClient,
-		KeyPrefix:      "rate_limit:",
-		DefaultLimit:   100,
-		DefaultWindow:  time.Minute,
-		SkipSuccessful: false,
-		KeyGenerator: func(c *gin.Context) string {
-			return c.ClientIP()
+		Limiter:       limiter,
+		KeyPrefix:     "rate_limit:",
+		DefaultPolicy: RateLimitPolicy{Limit: 100, Window: time.Minute, Burst: 200},
+		Routes: []RouteRateLimitPolicy{
+			{
+				// Webhook 接入由外部系统调用，流量突发时优先保护告警处理链路
+				Class:        "webhook",
+				Methods:      []string{"POST"},
+				PathPrefixes: []string{"/api/v1/ingest", "/api/v1/webhooks"},
+				Policy:       RateLimitPolicy{Limit: 300, Window: time.Minute, Burst: 60},
+			},
+			{
+				// 登录和令牌相关接口防止暴力破解
+				Class:        "auth",
+				Methods:      []string{"POST"},
+				PathPrefixes: []string{"/api/v1/auth"},
+				Policy:       RateLimitPolicy{Limit: 10, Window: time.Minute, Burst: 10},
+			},
+			{
+				// 导入导出开销较大
+				Class:        "bulk",
+				PathPrefixes: []string{"/api/v1/knowledge/import", "/api/v1/knowledge/export"},
+				Policy:       RateLimitPolicy{Limit: 10, Window: time.Minute, Burst: 5},
+			},
 		},
+		KeyGenerator: DefaultRateLimitKey,
+	}
+}
+
+// SetRoutePolicy 覆盖指定路由类别的限流策略
+func (c *RateLimitConfig) SetRoutePolicy(class string, policy RateLimitPolicy) {
+	for i := range c.Routes {
+		if c.Routes[i].Class == class {
+			c.Routes[i].Policy = policy
+		}
+	}
+}
+
+// DefaultRateLimitKey 生成默认的限流主体：已通过 API Key 认证的请求按 Key 计数，其次为已认证用户，最后为客户端IP
+// 未经认证的 X-API-Key 请求头不参与计数，避免轮换伪造 Key 绕过限流；API Key 只保存摘要，避免明文出现在 Redis 中
+func DefaultRateLimitKey(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" && c.GetString("auth_method") == "api_key" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
 	}
+	return "ip:" + c.ClientIP()
 }
 
-// RateLimitMiddleware 限流中间件
+// RateLimitMiddleware 限流中间件，按路由类别和限流主体分别使用令牌桶
+// 响应头遵循 IETF RateLimit 头字段草案，被限流时返回 429 和 Retry-After
 func RateLimitMiddleware(config RateLimitConfig) gin.HandlerFunc {
+	if config.KeyGenerator == nil {
+		config.KeyGenerator = DefaultRateLimitKey
+	}
+
 	return func(c *gin.Context) {
-		// 检查是否跳过限流
-		if c.GetBool("skip_rate_limit") {
-			c.Next()
-			return
-		}
+		rateLimit(c, &config)
+	}
+}
 
-		// 生成限流键
-		key := config.KeyPrefix + config.KeyGenerator(c)
-		limit := config.DefaultLimit
-		window := config.DefaultWindow
-
-		// 检查限流
-		allowed, remaining, resetTime, err := checkRateLimit(config.RedisClient, key, limit, window)
-		if err != nil {
-			if config.Logger != nil {
-				config.Logger.WithFields(logrus.Fields{
-					"error":      err,
-					"key":        key,
-					"request_id": c.GetString("request_id"),
-				}).Error("Rate limit check failed")
-			}
-			// 限流检查失败时，允许请求通过（fail-open策略）
+// DynamicRateLimit 可在运行时更新策略的限流中间件，用于配置热更新
+type DynamicRateLimit struct {
+	config  atomic.Pointer[RateLimitConfig]
+	enabled atomic.Bool
+}
+
+// NewDynamicRateLimit 创建可热更新的限流中间件，初始为启用状态
+func NewDynamicRateLimit(config RateLimitConfig) *DynamicRateLimit {
+	if config.KeyGenerator == nil {
+		config.KeyGenerator = DefaultRateLimitKey
+	}
+
+	d := &DynamicRateLimit{}
+	d.config.Store(&config)
+	d.enabled.Store(true)
+	return d
+}
+
+// Update 基于当前配置的副本修改限流策略，修改后原子替换，进行中的请求不受影响
+func (d *DynamicRateLimit) Update(fn func(config *RateLimitConfig)) {
+	next := *d.config.Load()
+	next.Routes = append([]RouteRateLimitPolicy(nil), next.Routes...)
+	fn(&next)
+	d.config.Store(&next)
+}
+
+// SetEnabled 启用或关闭限流
+func (d *DynamicRateLimit) SetEnabled(enabled bool) {
+	d.enabled.Store(enabled)
+}
+
+// Config 返回当前限流配置
+func (d *DynamicRateLimit) Config() RateLimitConfig {
+	return *d.config.Load()
+}
+
+// Middleware 返回限流中间件
+func (d *DynamicRateLimit) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.enabled.Load() {
 			c.Next()
 			return
 		}
+		rateLimit(c, d.config.Load())
+	}
+}
 
-		// 设置限流相关的响应头
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetTime, 10))
-
-		if !allowed {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "rate_limit_exceeded",
-				"message": "Rate limit exceeded. Please try again later.",
-				"retry_after": resetTime - time.Now().Unix(),
-			})
-			c.Abort()
-			return
+// rateLimit 执行一次限流检查
+func rateLimit(c *gin.Context, config *RateLimitConfig) {
+	// 检查是否跳过限流
+	if c.GetBool("skip_rate_limit") {
+		c.Next()
+		return
+	}
+
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+
+	class, policy := "default", config.DefaultPolicy
+	for _, route := range config.Routes {
+		if route.matches(c.Request.Method, path) {
+			class, policy = route.Class, route.Policy
+			break
 		}
+	}
 
+	key := config.KeyPrefix + class + ":" + config.KeyGenerator(c)
+	result, err := config.Limiter.Allow(c.Request.Context(), key, policy)
+	if err != nil {
+		if config.Logger != nil {
+			config.Logger.WithFields(logrus.Fields{
+				"error":      err,
+				"key":        key,
+				"request_id": c.GetString("request_id"),
+			}).Error("Rate limit check failed")
+		}
+		// 限流检查失败时，允许请求通过（fail-open策略）
 		c.Next()
+		return
 	}
+
+	c.Header("RateLimit-Policy", policy.header())
+	c.Header("RateLimit-Limit", strconv.Itoa(policy.capacity()))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		retryAfter := ceilSeconds(result.RetryAfter)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		apierror.RespondCode(c, apierror.CodeRateLimitExceeded, "", gin.H{
+			"retry_after": retryAfter,
+		})
+		c.Abort()
+		return
+	}
+
+	c.Next()
 }
 
-// checkRateLimit 检查限流状态
-func checkRateLimit(redisClient *redis.Client, key string, limit int, window time.Duration) (allowed bool, remaining int, resetTime int64, err error) {
-	ctx := context.Background()
-	now := time.Now()
-	windowStart := now.Truncate(window)
-	resetTime = windowStart.Add(window).Unix()
-
-	// 使用Lua脚本确保原子性
-	luaScript := `
-		local key = KEYS[1]
-		local window_start = ARGV[1]
-		local limit = tonumber(ARGV[2])
-		local ttl = tonumber(ARGV[3])
-		
-		-- 清理过期的计数
-		redis.call('ZREMRANGEBYSCORE', key, 0, window_start - 1)
-		
-		-- 获取当前计数
-		local current = redis.call('ZCARD', key)
-		
-		if current < limit then
-			-- 添加当前请求
-			redis.call('ZADD', key, window_start, window_start)
-			redis.call('EXPIRE', key, ttl)
-			return {1, limit - current - 1}
-		else
-			return {0, 0}
-		end
-	`
-
-	result, err := redisClient.Eval(ctx, luaScript, []string{key}, windowStart.Unix(), limit, int(window.Seconds())).Result()
+// ceilSeconds 向上取整为秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// tokenBucketScript 原子地补充并消费令牌
+// 返回 {是否允许, 剩余令牌, 重试等待毫秒, 补满等待毫秒}
+var tokenBucketScript = redis.NewScript(`
+	local key = KEYS[1]
+	local capacity = tonumber(ARGV[1])
+	local rate = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+	local tokens = tonumber(bucket[1])
+	local ts = tonumber(bucket[2])
+	if tokens == nil or ts == nil then
+		tokens = capacity
+		ts = now
+	end
+
+	if now > ts then
+		tokens = math.min(capacity, tokens + (now - ts) * rate)
+		ts = now
+	end
+
+	local allowed = 0
+	local retry_after = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	else
+		retry_after = math.ceil((1 - tokens) / rate)
+	end
+
+	local reset = math.ceil((capacity - tokens) / rate)
+	redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', ts)
+	redis.call('PEXPIRE', key, reset + 1000)
+
+	return {allowed, math.floor(tokens), retry_after, reset}
+`)
+
+// redisRateLimiter 基于 Redis 的分布式令牌桶限流器
+type redisRateLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimiter 创建基于 Redis 的限流器，多个网关实例共享令牌桶
+func NewRedisRateLimiter(client *redis.Client) RateLimiter {
+	return &redisRateLimiter{client: client}
+}
+
+// Allow 尝试消费一个令牌
+func (l *redisRateLimiter) Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error) {
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{key},
+		policy.capacity(), policy.ratePerMillisecond(), time.Now().UnixMilli()).Int64Slice()
 	if err != nil {
-		return false, 0, resetTime, err
+		return nil, fmt.Errorf("执行限流脚本失败: %w", err)
 	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("限流脚本返回值无效: %v", values)
+	}
+
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// memorySweepInterval 内存限流器清理令牌桶的最小间隔
+const memorySweepInterval = time.Minute
 
-	results := result.([]interface{})
-	allowed = results[0].(int64) == 1
-	remaining = int(results[1].(int64))
+// memoryBucket 内存令牌桶
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // 令牌补满的时间，此后的令牌桶与新建的等价，可以清理
+}
+
+// memoryRateLimiter 单实例内存令牌桶限流器
+type memoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	now       func() time.Time
+	lastSweep time.Time
+}
 
-	return allowed, remaining, resetTime, nil
+// NewMemoryRateLimiter 创建内存限流器，仅适用于单实例部署或 Redis 不可用时
+func NewMemoryRateLimiter() RateLimiter {
+	return &memoryRateLimiter{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow 尝试消费一个令牌
+func (l *memoryRateLimiter) Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	capacity := float64(policy.capacity())
+	rate := policy.ratePerMillisecond()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacity, last: now}
+		l.buckets[key] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed.Milliseconds())*rate)
+		bucket.last = now
+	}
+
+	result := &RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1-bucket.tokens)/rate)) * time.Millisecond
+	}
+	result.Remaining = int(math.Floor(bucket.tokens))
+	result.Reset = time.Duration(math.Ceil((capacity-bucket.tokens)/rate)) * time.Millisecond
+	bucket.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep 定期清理已补满的令牌桶，避免限流主体（如客户端IP）不断变化时内存无限增长
+func (l *memoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < memorySweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if !now.Before(bucket.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Availability 后端可用性状态，由 Redis 可用性探测器实现
+type Availability interface {
+	Available() bool
+	ReportFailure(err error)
+}
+
+// fallbackRateLimiter Redis 不可用时降级为单实例内存限流的限流器
+type fallbackRateLimiter struct {
+	primary      RateLimiter
+	fallback     RateLimiter
+	availability Availability
+}
+
+// NewFallbackRateLimiter 创建可降级限流器，Redis 不可用或执行失败时使用 fallback，恢复后自动切回
+func NewFallbackRateLimiter(primary, fallback RateLimiter, availability Availability) RateLimiter {
+	return &fallbackRateLimiter{
+		primary:      primary,
+		fallback:     fallback,
+		availability: availability,
+	}
+}
+
+// Allow 尝试消费一个令牌
+func (l *fallbackRateLimiter) Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error) {
+	if l.primary != nil && l.availability != nil && l.availability.Available() {
+		result, err := l.primary.Allow(ctx, key, policy)
+		if err == nil {
+			return result, nil
+		}
+		l.availability.ReportFailure(err)
+	}
+	return l.fallback.Allow(ctx, key, policy)
 }
 
 // CircuitBreakerState 熔断器状态
@@ -212,10 +535,7 @@ func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
 	}
 	if cb.fallbackResponse == nil {
 		cb.fallbackResponse = func(c *gin.Context) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error":   "service_unavailable",
-				"message": "Service is temporarily unavailable. Please try again later.",
-			})
+			apierror.RespondCode(c, apierror.CodeServiceUnavailable, "", nil)
 		}
 	}
 
@@ -0,0 +1,289 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLimiter 记录限流键并委托给内存限流器
+type recordingLimiter struct {
+	RateLimiter
+	keys []string
+	err  error
+}
+
+func (l *recordingLimiter) Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error) {
+	l.keys = append(l.keys, key)
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.RateLimiter.Allow(ctx, key, policy)
+}
+
+func newRateLimitRouter(config RateLimitConfig, before ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(before...)
+	router.Use(RateLimitMiddleware(config))
+	router.GET("/api/v1/alerts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/webhooks/:id/trigger", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// withAuthMethod 模拟认证中间件写入的认证方式
+func withAuthMethod(method string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("auth_method", method)
+		c.Next()
+	}
+}
+
+func TestMemoryRateLimiter_Refill(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := &memoryRateLimiter{
+		buckets: make(map[string]*memoryBucket),
+		now:     func() time.Time { return now },
+	}
+	policy := RateLimitPolicy{Limit: 2, Window: time.Second}
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(context.Background(), "k", policy)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, err := limiter.Allow(context.Background(), "k", policy)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.Equal(t, time.Second, result.Reset)
+
+	// 半秒后补充一个令牌
+	now = now.Add(500 * time.Millisecond)
+	result, err = limiter.Allow(context.Background(), "k", policy)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryRateLimiter_Sweep(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	limiter := &memoryRateLimiter{
+		buckets: make(map[string]*memoryBucket),
+		now:     func() time.Time { return now },
+	}
+	policy := RateLimitPolicy{Limit: 2, Window: time.Minute}
+	allow := func(key string) {
+		_, err := limiter.Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+	}
+
+	allow("ip:10.0.0.1")
+	now = start.Add(50 * time.Second)
+	allow("ip:10.0.0.2")
+	allow("ip:10.0.0.2")
+	require.Len(t, limiter.buckets, 2)
+
+	// 10.0.0.1 已补满被清理，10.0.0.2 仍在补充中需要保留
+	now = start.Add(memorySweepInterval)
+	allow("ip:10.0.0.3")
+	assert.NotContains(t, limiter.buckets, "ip:10.0.0.1")
+	assert.Contains(t, limiter.buckets, "ip:10.0.0.2")
+	assert.Contains(t, limiter.buckets, "ip:10.0.0.3")
+}
+
+// stubAvailability 可控的可用性状态
+type stubAvailability struct {
+	available bool
+}
+
+func (s *stubAvailability) Available() bool { return s.available }
+
+func (s *stubAvailability) ReportFailure(err error) { s.available = false }
+
+func TestFallbackRateLimiter(t *testing.T) {
+	policy := RateLimitPolicy{Limit: 10, Window: time.Second}
+	primary := &recordingLimiter{RateLimiter: NewMemoryRateLimiter(), err: errors.New("connection refused")}
+	fallback := &recordingLimiter{RateLimiter: NewMemoryRateLimiter()}
+	availability := &stubAvailability{available: true}
+	limiter := NewFallbackRateLimiter(primary, fallback, availability)
+
+	// Redis 执行失败时上报故障并使用内存限流
+	result, err := limiter.Allow(context.Background(), "k", policy)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.False(t, availability.available)
+
+	// 降级期间不再访问 Redis
+	_, err = limiter.Allow(context.Background(), "k", policy)
+	require.NoError(t, err)
+	assert.Len(t, primary.keys, 1)
+	assert.Len(t, fallback.keys, 2)
+
+	// 恢复后切回 Redis
+	primary.err = nil
+	availability.available = true
+	_, err = limiter.Allow(context.Background(), "k", policy)
+	require.NoError(t, err)
+	assert.Len(t, primary.keys, 2)
+	assert.Len(t, fallback.keys, 2)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Run("返回标准限流头并在超限时返回429", func(t *testing.T) {
+		router := newRateLimitRouter(RateLimitConfig{
+			Limiter:       NewMemoryRateLimiter(),
+			KeyPrefix:     "test:",
+			DefaultPolicy: RateLimitPolicy{Limit: 1, Window: time.Minute},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "1;w=60;burst=1", w.Header().Get("RateLimit-Policy"))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"code":"RATE_LIMIT_EXCEEDED"`)
+		assert.Contains(t, w.Body.String(), `"retry_after":60`)
+	})
+
+	t.Run("路由类别使用独立令牌桶", func(t *testing.T) {
+		limiter := &recordingLimiter{RateLimiter: NewMemoryRateLimiter()}
+		router := newRateLimitRouter(RateLimitConfig{
+			Limiter:       limiter,
+			KeyPrefix:     "test:",
+			DefaultPolicy: RateLimitPolicy{Limit: 100, Window: time.Minute},
+			Routes: []RouteRateLimitPolicy{{
+				Class:        "webhook",
+				Methods:      []string{http.MethodPost},
+				PathPrefixes: []string{"/api/v1/webhooks"},
+				Policy:       RateLimitPolicy{Limit: 1, Window: time.Minute},
+			}},
+		}, withAuthMethod("api_key"))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/w1/trigger", nil)
+		req.Header.Set("X-API-Key", "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		// 其他路由不受 webhook 限流影响
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+		req.Header.Set("X-API-Key", "secret")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		require.Len(t, limiter.keys, 3)
+		assert.Regexp(t, `^test:webhook:key:[0-9a-f]{16}$`, limiter.keys[0])
+		assert.NotContains(t, limiter.keys[0], "secret")
+		assert.Regexp(t, `^test:default:key:`, limiter.keys[2])
+	})
+
+	t.Run("未认证的API Key请求头按客户端IP计数", func(t *testing.T) {
+		limiter := &recordingLimiter{RateLimiter: NewMemoryRateLimiter()}
+		router := newRateLimitRouter(RateLimitConfig{
+			Limiter:       limiter,
+			KeyPrefix:     "test:",
+			DefaultPolicy: RateLimitPolicy{Limit: 1, Window: time.Minute},
+		})
+
+		// 轮换伪造的 Key 不能获得新的令牌桶
+		codes := make([]int, 0, 2)
+		for _, apiKey := range []string{"junk-1", "junk-2"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+			req.Header.Set("X-API-Key", apiKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes = append(codes, w.Code)
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+		assert.Equal(t, []string{"test:default:ip:192.0.2.1", "test:default:ip:192.0.2.1"}, limiter.keys)
+	})
+
+	t.Run("限流器异常时放行", func(t *testing.T) {
+		router := newRateLimitRouter(RateLimitConfig{
+			Limiter:       &recordingLimiter{err: errors.New("redis unavailable")},
+			DefaultPolicy: RateLimitPolicy{Limit: 1, Window: time.Minute},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestDefaultRateLimitKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "10.0.0.1:1234"
+
+	assert.Equal(t, "ip:10.0.0.1", DefaultRateLimitKey(c))
+
+	c.Set("user_id", "user-1")
+	assert.Equal(t, "user:user-1", DefaultRateLimitKey(c))
+
+	// 只有通过 API Key 认证的请求才按 Key 计数
+	c.Request.Header.Set("X-API-Key", "secret")
+	assert.Equal(t, "user:user-1", DefaultRateLimitKey(c))
+	c.Set("auth_method", "api_key")
+	assert.Regexp(t, `^key:[0-9a-f]{16}$`, DefaultRateLimitKey(c))
+}
+
+func TestDynamicRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dynamic := NewDynamicRateLimit(RateLimitConfig{
+		Limiter:       NewMemoryRateLimiter(),
+		KeyPrefix:     "test:",
+		DefaultPolicy: RateLimitPolicy{Limit: 1, Window: time.Minute},
+	})
+	router := gin.New()
+	router.Use(dynamic.Middleware())
+	router.GET("/api/v1/alerts", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+	assert.Equal(t, http.StatusTooManyRequests, request().Code)
+
+	// 关闭限流后直接放行
+	dynamic.SetEnabled(false)
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+
+	// 热更新策略后使用新的桶容量
+	dynamic.SetEnabled(true)
+	dynamic.Update(func(c *RateLimitConfig) {
+		c.KeyPrefix = "reloaded:"
+		c.DefaultPolicy = RateLimitPolicy{Limit: 5, Window: time.Minute}
+	})
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+}
@@ -1,10 +1,11 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
 )
 
 // Permission 权限定义
@@ -165,20 +166,14 @@ func RequirePermissionMiddleware(rbacService RBACService, resource string, actio
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "authentication_required",
-				"message": "User authentication is required",
-			})
+			apierror.RespondCode(c, apierror.CodeAuthenticationRequired, "", nil)
 			c.Abort()
 			return
 		}
 
 		userIDStr, ok := userID.(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "invalid_user_context",
-				"message": "Invalid user context",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAuthContext, "", nil)
 			c.Abort()
 			return
 		}
@@ -186,19 +181,13 @@ func RequirePermissionMiddleware(rbacService RBACService, resource string, actio
 		// 检查权限
 		hasPermission, err := rbacService.HasPermission(userIDStr, resource, action)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "permission_check_failed",
-				"message": "Failed to check permissions",
-				"details": err.Error(),
-			})
+			apierror.RespondCode(c, apierror.CodePermissionCheckFailed, "", err.Error())
 			c.Abort()
 			return
 		}
 
 		if !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "insufficient_permissions",
-				"message": "Insufficient permissions to access this resource",
+			apierror.RespondCode(c, apierror.CodeInsufficientPermissions, "", gin.H{
 				"required": gin.H{
 					"resource": resource,
 					"action":   action,
@@ -218,20 +207,14 @@ func RequireRoleMiddleware(rbacService RBACService, requiredRoles ...string) gin
 		// 获取用户角色
 		userRoles, exists := c.Get("user_roles")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "authentication_required",
-				"message": "User authentication is required",
-			})
+			apierror.RespondCode(c, apierror.CodeAuthenticationRequired, "", nil)
 			c.Abort()
 			return
 		}
 
 		userRolesList, ok := userRoles.([]string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "invalid_role_context",
-				"message": "Invalid role context",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAuthContext, "", nil)
 			c.Abort()
 			return
 		}
@@ -246,9 +229,7 @@ func RequireRoleMiddleware(rbacService RBACService, requiredRoles ...string) gin
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   "insufficient_role",
-			"message": "Insufficient role to access this resource",
+		apierror.RespondCode(c, apierror.CodeInsufficientRole, "", gin.H{
 			"required": requiredRoles,
 			"current":  userRolesList,
 		})
@@ -262,20 +243,14 @@ func RequireAnyPermissionMiddleware(rbacService RBACService, permissions []Permi
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "authentication_required",
-				"message": "User authentication is required",
-			})
+			apierror.RespondCode(c, apierror.CodeAuthenticationRequired, "", nil)
 			c.Abort()
 			return
 		}
 
 		userIDStr, ok := userID.(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "invalid_user_context",
-				"message": "Invalid user context",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAuthContext, "", nil)
 			c.Abort()
 			return
 		}
@@ -289,9 +264,7 @@ func RequireAnyPermissionMiddleware(rbacService RBACService, permissions []Permi
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   "insufficient_permissions",
-			"message": "Insufficient permissions to access this resource",
+		apierror.RespondCode(c, apierror.CodeInsufficientPermissions, "", gin.H{
 			"required": permissions,
 		})
 		c.Abort()
@@ -329,20 +302,14 @@ func DynamicPermissionMiddleware(rbacService RBACService) gin.HandlerFunc {
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "authentication_required",
-				"message": "User authentication is required",
-			})
+			apierror.RespondCode(c, apierror.CodeAuthenticationRequired, "", nil)
 			c.Abort()
 			return
 		}
 
 		userIDStr, ok := userID.(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "invalid_user_context",
-				"message": "Invalid user context",
-			})
+			apierror.RespondCode(c, apierror.CodeInvalidAuthContext, "", nil)
 			c.Abort()
 			return
 		}
@@ -354,19 +321,13 @@ func DynamicPermissionMiddleware(rbacService RBACService) gin.HandlerFunc {
 		// 检查权限
 		hasPermission, err := rbacService.HasPermission(userIDStr, resource, action)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "permission_check_failed",
-				"message": "Failed to check permissions",
-				"details": err.Error(),
-			})
+			apierror.RespondCode(c, apierror.CodePermissionCheckFailed, "", err.Error())
 			c.Abort()
 			return
 		}
 
 		if !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "insufficient_permissions",
-				"message": "Insufficient permissions to access this resource",
+			apierror.RespondCode(c, apierror.CodeInsufficientPermissions, "", gin.H{
 				"required": gin.H{
 					"resource": resource,
 					"action":   action,
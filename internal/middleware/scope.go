@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// ScopeAll 拥有全部权限的 API Key 权限范围，未配置权限范围的 API Key 拥有全部权限
+const ScopeAll = "*"
+
+// RouteScope 路由需要的 API Key 权限范围，权限范围格式为 资源:操作，如 alerts:read
+type RouteScope struct {
+	// Route 路由模板，匹配该路由及其下的所有路由，如 /api/v1/alerts 匹配 /api/v1/alerts/:id/comments
+	Route string
+	// Read 读请求需要的权限范围，Write 写请求需要的权限范围，为空时只允许拥有全部权限的 API Key 访问
+	Read  string
+	Write string
+}
+
+// ScopeTable 按路由声明的 API Key 权限范围
+type ScopeTable []RouteScope
+
+// Required 获取请求需要的权限范围，按最长的路由模板匹配，未声明的路由返回 ScopeAll
+func (t ScopeTable) Required(method, route string) string {
+	var matched *RouteScope
+	for i := range t {
+		rs := &t[i]
+		if route != rs.Route && !strings.HasPrefix(route, rs.Route+"/") {
+			continue
+		}
+		if matched == nil || len(rs.Route) > len(matched.Route) {
+			matched = rs
+		}
+	}
+
+	scope := ""
+	if matched != nil {
+		scope = matched.Read
+		if isMutatingMethod(method) {
+			scope = matched.Write
+		}
+	}
+	if scope == "" {
+		return ScopeAll
+	}
+	return scope
+}
+
+// Valid 检查权限范围是否为已声明的权限范围、资源的全部操作（资源:*）或 ScopeAll
+func (t ScopeTable) Valid(scope string) bool {
+	if scope == ScopeAll {
+		return true
+	}
+	for _, rs := range t {
+		for _, declared := range []string{rs.Read, rs.Write} {
+			if declared == "" {
+				continue
+			}
+			resource, _, _ := strings.Cut(declared, ":")
+			if scope == declared || scope == resource+":*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope 检查授予的权限范围是否包含需要的权限范围，资源:* 包含该资源的所有操作
+func HasScope(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, scope := range granted {
+		if scope == ScopeAll || scope == required || (required != ScopeAll && scope == resource+":*") {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyScopeMiddleware 按路由声明的权限范围校验 API Key 请求，JWT 认证的用户不受影响。
+// 需注册在认证中间件之后
+func APIKeyScopeMiddleware(table ScopeTable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != "api_key" {
+			c.Next()
+			return
+		}
+
+		required := table.Required(c.Request.Method, c.FullPath())
+		if !HasScope(c.GetStringSlice("api_key_scopes"), required) {
+			apierror.RespondCode(c, apierror.CodeInsufficientScope, "", gin.H{"required_scope": required})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
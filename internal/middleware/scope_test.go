@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScopes = ScopeTable{
+	{Route: "/api/v1/alerts", Read: "alerts:read", Write: "alerts:ingest"},
+	{Route: "/api/v1/alerts/:id", Read: "alerts:read", Write: "alerts:write"},
+	{Route: "/api/v1/knowledge", Read: "knowledge:read", Write: "knowledge:write"},
+}
+
+func TestScopeTable_Required(t *testing.T) {
+	assert.Equal(t, "alerts:ingest", testScopes.Required(http.MethodPost, "/api/v1/alerts"))
+	assert.Equal(t, "alerts:read", testScopes.Required(http.MethodGet, "/api/v1/alerts/stats"))
+	// 按最长的路由模板匹配
+	assert.Equal(t, "alerts:write", testScopes.Required(http.MethodPost, "/api/v1/alerts/:id/comments"))
+	assert.Equal(t, "knowledge:read", testScopes.Required(http.MethodGet, "/api/v1/knowledge/:id"))
+	// 未声明的路由只允许拥有全部权限的 API Key
+	assert.Equal(t, ScopeAll, testScopes.Required(http.MethodGet, "/api/v1/knowledge-export"))
+	assert.Equal(t, ScopeAll, testScopes.Required(http.MethodGet, "/api/v1/admin/jobs"))
+
+	assert.True(t, testScopes.Valid("alerts:ingest"))
+	assert.True(t, testScopes.Valid("knowledge:*"))
+	assert.True(t, testScopes.Valid(ScopeAll))
+	assert.False(t, testScopes.Valid("tickets:read"))
+
+	assert.True(t, HasScope([]string{"alerts:*"}, "alerts:write"))
+	assert.False(t, HasScope([]string{"alerts:*"}, "knowledge:read"))
+	assert.False(t, HasScope([]string{"alerts:*"}, ScopeAll))
+}
+
+func TestAPIKeyScopeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authService := NewJWTAuthService("test-secret", time.Hour)
+	authService.AddAPIKey("forwarder-key", APIKeyPrincipal{UserID: "svc-forwarder", Scopes: []string{"alerts:ingest"}})
+	authService.AddAPIKey("bot-key", APIKeyPrincipal{UserID: "svc-bot", Scopes: []string{"knowledge:read"}})
+	authService.AddAPIKey("legacy-key", APIKeyPrincipal{UserID: "svc-legacy"})
+
+	router := gin.New()
+	router.Use(RequireAuthMiddleware(authService))
+	router.Use(APIKeyScopeMiddleware(testScopes))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/api/v1/alerts", ok)
+	router.POST("/api/v1/alerts/:id/comments", ok)
+	router.GET("/api/v1/knowledge/:id", ok)
+	router.GET("/api/v1/admin/jobs", ok)
+
+	serve := func(method, path, header, value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(header, value)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/alerts", "X-API-Key", "forwarder-key").Code)
+	w := serve(http.MethodPost, "/api/v1/alerts/a1/comments", "X-API-Key", "forwarder-key")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "alerts:write")
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/knowledge/k1", "X-API-Key", "bot-key").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/api/v1/alerts", "X-API-Key", "bot-key").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/admin/jobs", "X-API-Key", "bot-key").Code)
+
+	// 未配置权限范围的 API Key 拥有全部权限
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/admin/jobs", "X-API-Key", "legacy-key").Code)
+
+	// JWT 认证的用户不受权限范围限制
+	token, err := authService.GenerateToken("u1", "alice", "alice@example.com", []string{"viewer"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/admin/jobs", "Authorization", "Bearer "+token).Code)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddleware_RouteClasses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(TimeoutMiddleware(TimeoutConfig{
+		Timeout:       time.Minute,
+		ReadTimeout:   5 * time.Second,
+		ExportTimeout: 120 * time.Second,
+		ExportRoutes:  []string{"/api/v1/tasks/:id/result"},
+	}))
+	// 返回请求 context 剩余的处理时间
+	remaining := func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if !ok {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Second).String())
+	}
+	router.GET("/api/v1/alerts", remaining)
+	router.POST("/api/v1/alerts", remaining)
+	router.GET("/api/v1/tasks/:id/result", remaining)
+
+	serve := func(method, path string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "5s", serve(http.MethodGet, "/api/v1/alerts"))
+	assert.Equal(t, "2m0s", serve(http.MethodGet, "/api/v1/tasks/abc/result"))
+	// 未配置写请求时限时使用默认时限
+	assert.Equal(t, "1m0s", serve(http.MethodPost, "/api/v1/alerts"))
+}
@@ -39,6 +39,20 @@ const (
 	AlertSourceZabbix     AlertSource = "zabbix"     // Zabbix
 	AlertSourceCustom     AlertSource = "custom"     // 自定义
 	AlertSourceSystem     AlertSource = "system"     // 系统
+	AlertSourceSelf       AlertSource = "self"       // 平台自监控
+	AlertSourceHeartbeat  AlertSource = "heartbeat"  // 心跳监控
+	AlertSourceKubernetes AlertSource = "kubernetes" // Kubernetes 事件和状态
+	AlertSourceForecast   AlertSource = "forecast"   // 规则预测
+)
+
+// 告警自动解决相关的注解
+const (
+	// AlertAnnotationAutoResolveAfter 告警或规则上配置的自动解决 TTL，如 30m、4h，为 0 时不自动解决
+	AlertAnnotationAutoResolveAfter = "auto_resolve_after"
+	// AlertAnnotationResolveReason 告警的解决原因
+	AlertAnnotationResolveReason = "resolve_reason"
+	// AlertResolveReasonTimeout 超过 TTL 未再次触发而自动解决
+	AlertResolveReasonTimeout = "timeout"
 )
 
 // Alert 告警模型
@@ -60,6 +74,13 @@ type Alert struct {
 	EndsAt          *time.Time             `json:"ends_at,omitempty" db:"ends_at"`
 	LastEvalAt      time.Time              `json:"last_eval_at" db:"last_eval_at"`
 	EvalCount       int64                  `json:"eval_count" db:"eval_count"`
+	// OccurrenceCount 未解决期间的触发次数，指纹相同的告警再次触发时累加而不是创建新告警
+	OccurrenceCount int64                  `json:"occurrence_count" db:"occurrence_count"`
+	LastSeenAt      time.Time              `json:"last_seen_at" db:"last_seen_at"`
+	// TriageScore 外部分诊服务给出的优先级评分（0-100），未评分或评分服务不可用时为空
+	TriageScore     *float64               `json:"triage_score,omitempty" db:"triage_score"`
+	// TriageAssignee 外部分诊服务建议的处理人
+	TriageAssignee  *string                `json:"triage_assignee,omitempty" db:"triage_assignee"`
 	Fingerprint     string                 `json:"fingerprint" db:"fingerprint"`
 	GeneratorURL    *string                `json:"generator_url,omitempty" db:"generator_url"`
 	SilenceID       *string                `json:"silence_id,omitempty" db:"silence_id"`
@@ -78,9 +99,9 @@ type AlertCreateRequest struct {
 	DataSourceID string            `json:"data_source_id" binding:"required"`
 	Name         string            `json:"name" binding:"required,min=1,max=200"`
 	Description  string            `json:"description" binding:"required,min=1,max=1000"`
-	Severity     AlertSeverity     `json:"severity" binding:"required"`
-	Source       AlertSource       `json:"source" binding:"required"`
-	Labels       map[string]string `json:"labels,omitempty"`
+	Severity     AlertSeverity     `json:"severity" binding:"required,enum"`
+	Source       AlertSource       `json:"source" binding:"required,enum"`
+	Labels       map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
 	Annotations  map[string]string `json:"annotations,omitempty"`
 	Value        *float64          `json:"value,omitempty"`
 	Threshold    *float64          `json:"threshold,omitempty"`
@@ -93,9 +114,9 @@ type AlertCreateRequest struct {
 type AlertUpdateRequest struct {
 	Name         *string            `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
 	Description  *string            `json:"description,omitempty" binding:"omitempty,min=1,max=1000"`
-	Severity     *AlertSeverity     `json:"severity,omitempty"`
-	Status       *AlertStatus       `json:"status,omitempty"`
-	Labels       map[string]string  `json:"labels,omitempty"`
+	Severity     *AlertSeverity     `json:"severity,omitempty" binding:"omitempty,enum"`
+	Status       *AlertStatus       `json:"status,omitempty" binding:"omitempty,enum"`
+	Labels       map[string]string  `json:"labels,omitempty" binding:"omitempty,labels"`
 	Annotations  map[string]string  `json:"annotations,omitempty"`
 	Value        *float64           `json:"value,omitempty"`
 	Threshold    *float64           `json:"threshold,omitempty"`
@@ -117,7 +138,7 @@ type AlertResolveRequest struct {
 
 // AlertSilenceRequest 静默告警请求
 type AlertSilenceRequest struct {
-	Duration time.Duration `json:"duration" binding:"required"`
+	Duration time.Duration `json:"duration" binding:"required,duration=1m"`
 	Comment  *string       `json:"comment,omitempty" binding:"omitempty,max=500"`
 }
 
@@ -132,6 +153,8 @@ type AlertFilter struct {
 	Labels       map[string]string `json:"labels,omitempty"`
 	StartTime    *time.Time     `json:"start_time,omitempty"`
 	EndTime      *time.Time     `json:"end_time,omitempty"`
+	HideSnoozedFor *string      `json:"-"` // 不返回该用户暂停中的告警
+	Query        *string        `json:"q,omitempty"` // 过滤表达式，如 severity in (critical,warning) and labels.cluster="prod"
 	Page         int            `json:"page" binding:"min=1"`
 	PageSize     int            `json:"page_size" binding:"min=1,max=100"`
 	SortBy       *string        `json:"sort_by,omitempty"`
@@ -254,13 +277,19 @@ func (s AlertStatus) IsValid() bool {
 // IsValid 检查告警来源是否有效
 func (s AlertSource) IsValid() bool {
 	switch s {
-	case AlertSourcePrometheus, AlertSourceGrafana, AlertSourceZabbix, AlertSourceCustom, AlertSourceSystem:
+	case AlertSourcePrometheus, AlertSourceGrafana, AlertSourceZabbix, AlertSourceCustom, AlertSourceSystem, AlertSourceSelf,
+		AlertSourceHeartbeat, AlertSourceKubernetes, AlertSourceForecast:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsInternal 检查告警来源是否为平台内部生成，外部接入不允许使用
+func (s AlertSource) IsInternal() bool {
+	return s == AlertSourceSelf || s == AlertSourceHeartbeat || s == AlertSourceKubernetes || s == AlertSourceForecast
+}
+
 // GetSeverityLevel 获取严重级别的数值（用于排序）
 func (s AlertSeverity) GetSeverityLevel() int {
 	switch s {
@@ -368,7 +397,7 @@ func (req *AlertCreateRequest) Validate() error {
 		return errors.New("无效的告警严重级别")
 	}
 	
-	if !req.Source.IsValid() {
+	if !req.Source.IsValid() || req.Source.IsInternal() {
 		return errors.New("无效的告警来源")
 	}
 	
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AlertActionLinkUse 已使用的告警操作链接，每个链接令牌只能使用一次
+type AlertActionLinkUse struct {
+	TokenID string    `json:"token_id" db:"token_id"`
+	AlertID string    `json:"alert_id" db:"alert_id"`
+	UserID  string    `json:"user_id" db:"user_id"`
+	Action  string    `json:"action" db:"action"`
+	UsedAt  time.Time `json:"used_at" db:"used_at"`
+}
+
+// AlertActionLinkView 告警操作链接对应的操作和告警，用于在执行前展示确认页面和返回执行结果
+type AlertActionLinkView struct {
+	Action    string        `json:"action"`
+	AlertID   string        `json:"alert_id"`
+	AlertName string        `json:"alert_name"`
+	Severity  AlertSeverity `json:"severity"`
+	Status    AlertStatus   `json:"status"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Used      bool          `json:"used"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AlertHistoryActionOccurred 重复触发在告警历史中的动作名称，按采样间隔记录
+const AlertHistoryActionOccurred = "occurred"
+
+// AlertOccurrence 未解决的告警再次触发后的累计结果
+type AlertOccurrence struct {
+	AlertID string    `json:"alert_id" db:"id"`
+	Count   int64     `json:"occurrence_count" db:"occurrence_count"`
+	SeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+	// Sampled 本次触发距上次采样超过采样间隔，需要记入告警历史
+	Sampled bool `json:"sampled" db:"sampled"`
+}
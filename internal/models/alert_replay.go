@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertReplayMaxSpan 单次回放的最长时间范围
+	AlertReplayMaxSpan = 31 * 24 * time.Hour
+	// AlertReplayDefaultLimit 未指定时最多回放的告警数
+	AlertReplayDefaultLimit = 500
+	// AlertReplayMaxLimit 单次最多回放的告警数
+	AlertReplayMaxLimit = 5000
+)
+
+// AlertReplayOutcome 回放中接收人收到通知的结果
+type AlertReplayOutcome string
+
+const (
+	AlertReplayNotified   AlertReplayOutcome = "notified"   // 立即通知
+	AlertReplayDeferred   AlertReplayOutcome = "deferred"   // 非工作时间延迟到工作时间汇总发送
+	AlertReplaySuppressed AlertReplayOutcome = "suppressed" // 接收人免打扰，不通知
+)
+
+// AlertReplayUnrouted 告警没有接收人的原因
+type AlertReplayUnrouted string
+
+const (
+	AlertReplayNoTeam       AlertReplayUnrouted = "no_team"       // 未归属到团队
+	AlertReplayNoPolicy     AlertReplayUnrouted = "no_policy"     // 团队没有启用的分派策略
+	AlertReplayNoCandidates AlertReplayUnrouted = "no_candidates" // 分派策略没有可分派的成员
+)
+
+// AlertReplayRequest 告警回放请求，按当前的服务目录、分派策略、通知日历和接收人的免打扰设置试运行，
+// 不修改告警，也不发送通知
+type AlertReplayRequest struct {
+	From time.Time `json:"from" binding:"required"`
+	To   time.Time `json:"to" binding:"required"`
+	// Query 过滤表达式，与告警列表的 q 参数相同，如 severity in (critical,high) and labels.cluster="prod"
+	Query  string            `json:"query"`
+	Labels map[string]string `json:"labels"`
+	// Limit 最多回放的告警数，按触发时间从新到旧
+	Limit int `json:"limit" binding:"omitempty,min=1"`
+}
+
+// Validate 验证告警回放请求
+func (r *AlertReplayRequest) Validate() error {
+	if !r.To.After(r.From) {
+		return fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidInput)
+	}
+	if r.To.Sub(r.From) > AlertReplayMaxSpan {
+		return fmt.Errorf("%w: 回放时间范围不能超过 %d 天", ErrInvalidInput, int(AlertReplayMaxSpan.Hours()/24))
+	}
+	if r.Limit == 0 {
+		r.Limit = AlertReplayDefaultLimit
+	}
+	if r.Limit < 0 || r.Limit > AlertReplayMaxLimit {
+		return fmt.Errorf("%w: 回放告警数必须在1到%d之间", ErrInvalidInput, AlertReplayMaxLimit)
+	}
+	r.Query = strings.TrimSpace(r.Query)
+	return nil
+}
+
+// AlertReplayReceiver 回放中告警的接收人
+type AlertReplayReceiver struct {
+	UserID   string             `json:"user_id"`
+	Username string             `json:"username"`
+	Outcome  AlertReplayOutcome `json:"outcome"`
+	// DeliverAt 延迟通知的发送时间
+	DeliverAt *time.Time `json:"deliver_at,omitempty"`
+	// Push 接收人的推送偏好是否包含该级别的告警
+	Push bool `json:"push"`
+}
+
+// AlertReplayResult 单个告警的回放结果，告警的服务和团队按当前服务目录重新归属
+type AlertReplayResult struct {
+	AlertID   string                `json:"alert_id"`
+	Name      string                `json:"name"`
+	Severity  AlertSeverity         `json:"severity"`
+	StartsAt  time.Time             `json:"starts_at"`
+	Service   string                `json:"service,omitempty"`
+	Team      string                `json:"team,omitempty"`
+	Strategy  AssignmentStrategy    `json:"strategy,omitempty"`
+	Receivers []AlertReplayReceiver `json:"receivers"`
+	Unrouted  AlertReplayUnrouted   `json:"unrouted,omitempty"`
+	// OriginalAssignee 告警创建时分派的处理人，Changed 表示当前配置下不会再通知该处理人
+	OriginalAssignee string `json:"original_assignee,omitempty"`
+	Changed          bool   `json:"changed"`
+}
+
+// AlertReplayReceiverSummary 按接收人汇总的回放结果
+type AlertReplayReceiverSummary struct {
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Notified   int    `json:"notified"`
+	Deferred   int    `json:"deferred"`
+	Suppressed int    `json:"suppressed"`
+}
+
+// AlertReplayReport 告警回放报告
+type AlertReplayReport struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Replayed int       `json:"replayed"`
+	// Truncated 时间范围内的告警超过 Limit，只回放了最新的部分
+	Truncated   bool                         `json:"truncated"`
+	Unrouted    int                          `json:"unrouted"`
+	Changed     int                          `json:"changed"`
+	Receivers   []AlertReplayReceiverSummary `json:"receivers"`
+	Alerts      []AlertReplayResult          `json:"alerts"`
+	GeneratedAt time.Time                    `json:"generated_at"`
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertAnnotationOriginalSeverity 告警被重新分级前的级别
+	AlertAnnotationOriginalSeverity = "original_severity"
+	// AlertAnnotationSeverityRule 重新分级告警的规则名称
+	AlertAnnotationSeverityRule = "severity_rule"
+
+	// maxAlertSeverityRuleWindows 每条重新分级规则最多配置的时段数
+	maxAlertSeverityRuleWindows = 14
+	// maxAlertSeverityRuleMatchers 每条重新分级规则最多配置的标签匹配条件数
+	maxAlertSeverityRuleMatchers = 20
+)
+
+// alertSeverityLevels 按严重程度升序排列的告警级别，用于按级数升级或降级
+var alertSeverityLevels = []AlertSeverity{
+	AlertSeverityInfo,
+	AlertSeverityLow,
+	AlertSeverityMedium,
+	AlertSeverityHigh,
+	AlertSeverityCritical,
+}
+
+// AlertSeverityRuleWindow 重新分级规则生效的时段，结束时刻早于开始时刻时跨越午夜，Days 为时段开始的日期是星期几
+type AlertSeverityRuleWindow struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"` // 开始时刻，如 22:00
+	End   string   `json:"end"`   // 结束时刻，如 08:00
+}
+
+// quietHoursWindow 转换为相同语义的免打扰时段以复用时段判断
+func (w *AlertSeverityRuleWindow) quietHoursWindow() *QuietHoursWindow {
+	return &QuietHoursWindow{Days: w.Days, Start: w.Start, End: w.End}
+}
+
+// AlertSeverityRule 告警重新分级规则，在告警接入时、分派和通知路由之前调整告警级别。
+// 标签、服务等级和时段条件都满足时匹配，未配置的条件不限制；规则按 Position 升序匹配，第一条匹配的规则生效
+type AlertSeverityRule struct {
+	ID          string `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	Position    int    `json:"position" db:"position"`
+	// Matchers 告警标签需要全部等于的值，如 env=staging
+	Matchers map[string]string `json:"matchers" db:"matchers"`
+	// ServiceTiers 告警归属的服务等级，告警未归属到服务时不匹配
+	ServiceTiers []ServiceTier `json:"service_tiers" db:"service_tiers"`
+	// Timezone 时段所在的 IANA 时区名，为空时使用 UTC
+	Timezone string                    `json:"timezone" db:"timezone"`
+	Windows  []AlertSeverityRuleWindow `json:"windows" db:"windows"`
+	// Severity 匹配后设置的级别，为空时按 Adjust 调整
+	Severity *AlertSeverity `json:"severity,omitempty" db:"severity"`
+	// Adjust 匹配后调整的级数，正数升级、负数降级，超出范围时取 critical 或 info
+	Adjust    int       `json:"adjust" db:"adjust"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedBy *string   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate 验证重新分级规则，至少需要一个匹配条件，且必须且只能指定目标级别或调整级数之一
+func (r *AlertSeverityRule) Validate() error {
+	if r.Name == "" || len(r.Name) > 200 {
+		return fmt.Errorf("%w: 规则名称不能为空且不能超过200个字符", ErrInvalidInput)
+	}
+	if len(r.Description) > 1000 {
+		return fmt.Errorf("%w: 规则描述不能超过1000个字符", ErrInvalidInput)
+	}
+	if len(r.Matchers) == 0 && len(r.ServiceTiers) == 0 && len(r.Windows) == 0 {
+		return fmt.Errorf("%w: 至少需要配置标签、服务等级或时段条件之一", ErrInvalidInput)
+	}
+	if len(r.Matchers) > maxAlertSeverityRuleMatchers {
+		return fmt.Errorf("%w: 标签匹配条件不能超过 %d 个", ErrInvalidInput, maxAlertSeverityRuleMatchers)
+	}
+	for key := range r.Matchers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: 标签匹配条件的标签名不能为空", ErrInvalidInput)
+		}
+	}
+	for _, tier := range r.ServiceTiers {
+		if !tier.IsValid() {
+			return fmt.Errorf("%w: 无效的服务等级 %s", ErrInvalidInput, tier)
+		}
+	}
+	if _, err := (&BusinessHours{Timezone: r.Timezone}).location(); err != nil {
+		return fmt.Errorf("%w: 无效的时区 %q", ErrInvalidInput, r.Timezone)
+	}
+	if len(r.Windows) > maxAlertSeverityRuleWindows {
+		return fmt.Errorf("%w: 时段不能超过 %d 个", ErrInvalidInput, maxAlertSeverityRuleWindows)
+	}
+	for i := range r.Windows {
+		if err := r.Windows[i].quietHoursWindow().Validate(); err != nil {
+			return err
+		}
+	}
+
+	if (r.Severity != nil) == (r.Adjust != 0) {
+		return fmt.Errorf("%w: 必须且只能指定目标级别或调整级数之一", ErrInvalidInput)
+	}
+	if r.Severity != nil && !r.Severity.IsValid() {
+		return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, *r.Severity)
+	}
+	if r.Adjust < -(len(alertSeverityLevels)-1) || r.Adjust > len(alertSeverityLevels)-1 {
+		return fmt.Errorf("%w: 调整级数必须在 -%d 到 %d 之间", ErrInvalidInput, len(alertSeverityLevels)-1, len(alertSeverityLevels)-1)
+	}
+	return nil
+}
+
+// Matches 判断规则在 now 时是否匹配告警，tier 为告警归属服务的等级，未归属时为空
+func (r *AlertSeverityRule) Matches(labels map[string]string, tier ServiceTier, now time.Time) bool {
+	for key, value := range r.Matchers {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	if len(r.ServiceTiers) > 0 {
+		matched := false
+		for _, t := range r.ServiceTiers {
+			if t == tier {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.Windows) == 0 {
+		return true
+	}
+	loc, err := (&BusinessHours{Timezone: r.Timezone}).location()
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	for i := range r.Windows {
+		if r.Windows[i].quietHoursWindow().contains(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply 返回按规则调整后的级别，无法识别的级别不调整
+func (r *AlertSeverityRule) Apply(severity AlertSeverity) AlertSeverity {
+	if r.Severity != nil {
+		return *r.Severity
+	}
+
+	level := -1
+	for i, s := range alertSeverityLevels {
+		if s == severity {
+			level = i
+		}
+	}
+	if level < 0 {
+		return severity
+	}
+	level += r.Adjust
+	if level < 0 {
+		level = 0
+	}
+	if level >= len(alertSeverityLevels) {
+		level = len(alertSeverityLevels) - 1
+	}
+	return alertSeverityLevels[level]
+}
+
+// AlertSeverityRuleRequest 创建或更新告警重新分级规则请求
+type AlertSeverityRuleRequest struct {
+	Name         string                    `json:"name" binding:"required,max=200"`
+	Description  string                    `json:"description" binding:"max=1000"`
+	Position     int                       `json:"position"`
+	Matchers     map[string]string         `json:"matchers,omitempty"`
+	ServiceTiers []ServiceTier             `json:"service_tiers,omitempty"`
+	Timezone     string                    `json:"timezone"`
+	Windows      []AlertSeverityRuleWindow `json:"windows,omitempty"`
+	Severity     *AlertSeverity            `json:"severity,omitempty"`
+	Adjust       int                       `json:"adjust"`
+	Enabled      *bool                     `json:"enabled,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到规则
+func (req *AlertSeverityRuleRequest) ApplyTo(r *AlertSeverityRule) {
+	r.Name = strings.TrimSpace(req.Name)
+	r.Description = strings.TrimSpace(req.Description)
+	r.Position = req.Position
+	r.Matchers = req.Matchers
+	if r.Matchers == nil {
+		r.Matchers = map[string]string{}
+	}
+	r.ServiceTiers = req.ServiceTiers
+	if r.ServiceTiers == nil {
+		r.ServiceTiers = []ServiceTier{}
+	}
+	r.Timezone = strings.TrimSpace(req.Timezone)
+	r.Windows = req.Windows
+	if r.Windows == nil {
+		r.Windows = []AlertSeverityRuleWindow{}
+	}
+	r.Severity = req.Severity
+	r.Adjust = req.Adjust
+	if req.Enabled != nil {
+		r.Enabled = *req.Enabled
+	}
+}
+
+// AlertSeverityRuleTestRequest 试算告警重新分级请求，Time 为空时按当前时间匹配时段
+type AlertSeverityRuleTestRequest struct {
+	Labels   map[string]string `json:"labels"`
+	Severity AlertSeverity     `json:"severity" binding:"required"`
+	Time     *time.Time        `json:"time,omitempty"`
+}
+
+// Validate 验证试算请求
+func (r *AlertSeverityRuleTestRequest) Validate() error {
+	if !r.Severity.IsValid() {
+		return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, r.Severity)
+	}
+	return nil
+}
+
+// AlertSeverityRuleTestResult 试算告警重新分级结果，没有匹配的规则时 Rule 为空、级别不变。
+// ServiceTier 为按标签归属到的服务的等级
+type AlertSeverityRuleTestResult struct {
+	Severity         AlertSeverity      `json:"severity"`
+	OriginalSeverity AlertSeverity      `json:"original_severity"`
+	Service          string             `json:"service,omitempty"`
+	ServiceTier      ServiceTier        `json:"service_tier,omitempty"`
+	Rule             *AlertSeverityRule `json:"rule,omitempty"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertSnoozeMaxDuration 单次暂停的最长时间
+const AlertSnoozeMaxDuration = 7 * 24 * time.Hour
+
+// AlertSnooze 用户对告警的暂停，只对创建暂停的用户生效
+// 暂停期间告警不出现在该用户的默认告警列表中，也不向该用户发送通知
+type AlertSnooze struct {
+	ID     string `json:"id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	// AlertID 暂停的告警，为空时暂停标签匹配 Matchers 的一组告警
+	AlertID   *string           `json:"alert_id,omitempty" db:"alert_id"`
+	Matchers  map[string]string `json:"matchers,omitempty" db:"matchers"`
+	Reason    string            `json:"reason" db:"reason"`
+	ExpiresAt time.Time         `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// Matches 判断暂停是否覆盖告警，告警标签包含全部匹配条件时覆盖
+func (s *AlertSnooze) Matches(alert *Alert) bool {
+	if s.AlertID != nil {
+		return *s.AlertID == alert.ID
+	}
+	if len(s.Matchers) == 0 {
+		return false
+	}
+	for key, value := range s.Matchers {
+		if alert.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Active 判断暂停在 now 时是否仍然生效
+func (s *AlertSnooze) Active(now time.Time) bool {
+	return now.Before(s.ExpiresAt)
+}
+
+// AlertSnoozeRequest 创建告警暂停请求，AlertID 和 Matchers 必须且只能指定一个
+type AlertSnoozeRequest struct {
+	AlertID         *string           `json:"alert_id,omitempty"`
+	Matchers        map[string]string `json:"matchers,omitempty"`
+	DurationSeconds int               `json:"duration_seconds"`
+	Reason          string            `json:"reason"`
+}
+
+// Duration 暂停时长
+func (r *AlertSnoozeRequest) Duration() time.Duration {
+	return time.Duration(r.DurationSeconds) * time.Second
+}
+
+// Validate 验证创建告警暂停请求
+func (r *AlertSnoozeRequest) Validate() error {
+	hasAlert := r.AlertID != nil && strings.TrimSpace(*r.AlertID) != ""
+	if hasAlert == (len(r.Matchers) > 0) {
+		return fmt.Errorf("%w: 必须且只能指定告警ID或标签匹配条件之一", ErrInvalidInput)
+	}
+	for key := range r.Matchers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: 标签匹配条件的标签名不能为空", ErrInvalidInput)
+		}
+	}
+	if duration := r.Duration(); duration < time.Minute || duration > AlertSnoozeMaxDuration {
+		return fmt.Errorf("%w: 暂停时长必须在 1 分钟到 %s 之间", ErrInvalidInput, AlertSnoozeMaxDuration)
+	}
+	if len(r.Reason) > 500 {
+		return fmt.Errorf("%w: 暂停原因不能超过500个字符", ErrInvalidInput)
+	}
+	return nil
+}
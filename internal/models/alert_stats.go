@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// AlertStatsDefaultSpan 未指定时间范围时统计最近的时长
+	AlertStatsDefaultSpan = 24 * time.Hour
+	// AlertStatsMaxSpan 单次统计的最长时间范围
+	AlertStatsMaxSpan = 366 * 24 * time.Hour
+)
+
+// AlertStatsInterval 告警趋势的时间粒度
+type AlertStatsInterval string
+
+const (
+	AlertStatsIntervalHour AlertStatsInterval = "hour"
+	AlertStatsIntervalDay  AlertStatsInterval = "day"
+	AlertStatsIntervalWeek AlertStatsInterval = "week"
+)
+
+// IsValid 检查时间粒度是否有效
+func (i AlertStatsInterval) IsValid() bool {
+	switch i {
+	case AlertStatsIntervalHour, AlertStatsIntervalDay, AlertStatsIntervalWeek:
+		return true
+	}
+	return false
+}
+
+// AlertStatsRow 小时汇总表中的一行，按告警开始时间所在的小时、严重级别、状态、规则和团队统计告警数。
+// 没有规则或没有 team 标签的告警对应的 RuleID 或 Team 为空字符串
+type AlertStatsRow struct {
+	Bucket   time.Time     `json:"bucket" db:"bucket"`
+	Severity AlertSeverity `json:"severity" db:"severity"`
+	Status   AlertStatus   `json:"status" db:"status"`
+	RuleID   string        `json:"rule_id" db:"rule_id"`
+	Team     string        `json:"team" db:"team"`
+	Count    int64         `json:"count" db:"count"`
+}
+
+// AlertStatsQuery 告警统计查询，按告警开始时间统计 [From, To) 范围内的告警，时间按小时对齐
+type AlertStatsQuery struct {
+	From     time.Time
+	To       time.Time
+	RuleID   *string
+	Team     *string
+	Severity *AlertSeverity
+	// Interval 趋势的时间粒度，只用于趋势查询
+	Interval AlertStatsInterval
+}
+
+// Normalize 填充默认值并验证查询。未指定时统计最近 24 小时，开始时间向下、结束时间向上对齐到整点
+func (q *AlertStatsQuery) Normalize(now time.Time) error {
+	if q.To.IsZero() {
+		q.To = now
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-AlertStatsDefaultSpan)
+	}
+	q.From = q.From.UTC().Truncate(time.Hour)
+	if to := q.To.UTC().Truncate(time.Hour); to.Before(q.To) {
+		q.To = to.Add(time.Hour)
+	} else {
+		q.To = to
+	}
+
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidInput)
+	}
+	if q.To.Sub(q.From) > AlertStatsMaxSpan {
+		return fmt.Errorf("%w: 统计时间范围不能超过 %d 天", ErrInvalidInput, int(AlertStatsMaxSpan.Hours()/24))
+	}
+	if q.Severity != nil && !q.Severity.IsValid() {
+		return fmt.Errorf("%w: 无效的严重级别 %q", ErrInvalidInput, *q.Severity)
+	}
+	if q.Interval == "" {
+		q.Interval = AlertStatsIntervalHour
+	}
+	if !q.Interval.IsValid() {
+		return fmt.Errorf("%w: 时间粒度只能为 hour、day 或 week", ErrInvalidInput)
+	}
+	return nil
+}
+
+// AlertStatsSummary 告警统计汇总，来自小时汇总表。ByRule 和 ByTeam 中空字符串的键表示没有规则或没有团队
+type AlertStatsSummary struct {
+	From       time.Time               `json:"from"`
+	To         time.Time               `json:"to"`
+	Total      int64                   `json:"total"`
+	BySeverity map[AlertSeverity]int64 `json:"by_severity"`
+	ByStatus   map[AlertStatus]int64   `json:"by_status"`
+	ByRule     map[string]int64        `json:"by_rule"`
+	ByTeam     map[string]int64        `json:"by_team"`
+	// MaterializedAt 汇总表最近一次更新时处理到的告警更新时间，之后变化的告警尚未计入
+	MaterializedAt *time.Time `json:"materialized_at"`
+}
+
+// AlertStatsTrendPoint 告警趋势点，Timestamp 为时间粒度的起点
+type AlertStatsTrendPoint struct {
+	Timestamp  time.Time               `json:"timestamp"`
+	Count      int64                   `json:"count"`
+	BySeverity map[AlertSeverity]int64 `json:"by_severity"`
+}
+
+// AlertStatsTrend 告警趋势
+type AlertStatsTrend struct {
+	From           time.Time               `json:"from"`
+	To             time.Time               `json:"to"`
+	Interval       AlertStatsInterval      `json:"interval"`
+	Points         []*AlertStatsTrendPoint `json:"points"`
+	MaterializedAt *time.Time              `json:"materialized_at"`
+}
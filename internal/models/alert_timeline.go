@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertTimelineEventType 告警时间线事件类型
+type AlertTimelineEventType string
+
+const (
+	AlertTimelineStateChange  AlertTimelineEventType = "state_change" // 状态变更
+	AlertTimelineNotification AlertTimelineEventType = "notification" // 通知发送
+	AlertTimelineComment      AlertTimelineEventType = "comment"      // 评论
+	AlertTimelineTicket       AlertTimelineEventType = "ticket"       // 关联工单
+	AlertTimelineSilence      AlertTimelineEventType = "silence"      // 静默
+	AlertTimelineRemediation  AlertTimelineEventType = "remediation"  // 修复动作
+	AlertTimelineOccurrence   AlertTimelineEventType = "occurrence"   // 重复触发（按采样间隔记录）
+)
+
+// AlertHistoryActionCommented 评论在告警历史中的动作名称
+const AlertHistoryActionCommented = "commented"
+
+// 指派处理人和静默在告警历史中的动作名称
+const (
+	AlertHistoryActionAssigned = "assigned"
+	AlertHistoryActionSilenced = "silenced"
+)
+
+// AlertTimelineEvent 告警时间线事件
+type AlertTimelineEvent struct {
+	Type      AlertTimelineEventType `json:"type"`
+	Action    string                 `json:"action"`
+	Timestamp time.Time              `json:"timestamp"`
+	UserID    *string                `json:"user_id,omitempty"`
+	Comment   *string                `json:"comment,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// AlertTimeline 告警时间线，按时间升序合并告警的状态变更、通知、评论、关联工单、静默和修复动作记录
+type AlertTimeline struct {
+	AlertID string                `json:"alert_id"`
+	Events  []*AlertTimelineEvent `json:"events"`
+}
+
+// AlertSilence 告警静默规则
+type AlertSilence struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Comment   *string   `json:"comment,omitempty" db:"comment"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// 匹配条件，仅在创建静默规则时写入
+	Matchers map[string]string `json:"matchers,omitempty" db:"-"`
+}
+
+// AlertCommentRequest 告警评论请求
+type AlertCommentRequest struct {
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+// Validate 验证告警评论请求
+func (req *AlertCommentRequest) Validate() error {
+	if strings.TrimSpace(req.Comment) == "" {
+		return fmt.Errorf("%w: 评论内容不能为空", ErrInvalidInput)
+	}
+	return nil
+}
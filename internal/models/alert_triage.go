@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AlertTriageRequest 提交给外部分诊服务的评分请求
+type AlertTriageRequest struct {
+	Alert *Alert `json:"alert"`
+}
+
+// AlertTriage 外部分诊服务返回的评分结果
+type AlertTriage struct {
+	// Score 优先级评分（0-100），越高越优先处理
+	Score float64 `json:"score"`
+	// Assignee 建议的处理人用户ID，可为空
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// Validate 验证评分结果
+func (t *AlertTriage) Validate() error {
+	if math.IsNaN(t.Score) || t.Score < 0 || t.Score > 100 {
+		return fmt.Errorf("%w: 分诊评分 %v 超出 0-100 范围", ErrInvalidInput, t.Score)
+	}
+	t.Assignee = strings.TrimSpace(t.Assignee)
+	return nil
+}
+
+// Apply 将评分结果保存到告警上
+func (t *AlertTriage) Apply(alert *Alert) {
+	score := t.Score
+	alert.TriageScore = &score
+	if t.Assignee != "" {
+		assignee := t.Assignee
+		alert.TriageAssignee = &assignee
+	}
+}
@@ -0,0 +1,248 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 审批请求的限制
+const (
+	// ApprovalMaxReason 申请理由和审批意见的最大长度
+	ApprovalMaxReason = 1000
+	// ApprovalMaxBulkTickets 批量关闭工单单次最多的工单数
+	ApprovalMaxBulkTickets = 500
+	// ApprovalDefaultListLimit 审批请求列表默认返回的数量
+	ApprovalDefaultListLimit = 50
+	// ApprovalMaxListLimit 审批请求列表最多返回的数量
+	ApprovalMaxListLimit = 200
+)
+
+// ApprovalAction 需要审批的敏感操作
+type ApprovalAction string
+
+const (
+	ApprovalActionDataSourceDelete ApprovalAction = "datasource.delete"  // 删除数据源，参数为 DataSourceDeleteParams
+	ApprovalActionRulesDisableAll  ApprovalAction = "rules.disable_all"  // 禁用全部生效中的规则，没有参数
+	ApprovalActionTicketsBulkClose ApprovalAction = "tickets.bulk_close" // 批量关闭工单，参数为 TicketBulkCloseParams
+)
+
+// IsValid 检查操作是否有效
+func (a ApprovalAction) IsValid() bool {
+	switch a {
+	case ApprovalActionDataSourceDelete, ApprovalActionRulesDisableAll, ApprovalActionTicketsBulkClose:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalStatus 审批请求状态
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending   ApprovalStatus = "pending"   // 等待审批
+	ApprovalStatusApproved  ApprovalStatus = "approved"  // 已批准，正在执行
+	ApprovalStatusRejected  ApprovalStatus = "rejected"  // 已驳回
+	ApprovalStatusCancelled ApprovalStatus = "cancelled" // 申请人已撤回
+	ApprovalStatusExpired   ApprovalStatus = "expired"   // 超过有效期未审批
+	ApprovalStatusExecuted  ApprovalStatus = "executed"  // 已执行
+	ApprovalStatusFailed    ApprovalStatus = "failed"    // 执行失败
+)
+
+// IsValid 检查状态是否有效
+func (s ApprovalStatus) IsValid() bool {
+	switch s {
+	case ApprovalStatusPending, ApprovalStatusApproved, ApprovalStatusRejected, ApprovalStatusCancelled,
+		ApprovalStatusExpired, ApprovalStatusExecuted, ApprovalStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalAuditResource 审批请求在审计日志中的资源类型
+const ApprovalAuditResource = "approval_request"
+
+// 审批请求在审计日志中的操作
+const (
+	ApprovalAuditSubmit  = "approval.submit"
+	ApprovalAuditApprove = "approval.approve"
+	ApprovalAuditReject  = "approval.reject"
+	ApprovalAuditCancel  = "approval.cancel"
+	ApprovalAuditExpire  = "approval.expire"
+	ApprovalAuditExecute = "approval.execute"
+)
+
+// ApprovalRequest 敏感操作的审批请求。配置为需要审批的操作先保存为待审批请求，由申请人以外的管理员批准后执行；
+// 不需要审批的操作提交后立即执行，同样保留请求和审计日志
+type ApprovalRequest struct {
+	ID          string          `json:"id" db:"id"`
+	Action      ApprovalAction  `json:"action" db:"action"`
+	Params      json.RawMessage `json:"params,omitempty" db:"params"`
+	Reason      string          `json:"reason" db:"reason"`
+	Status      ApprovalStatus  `json:"status" db:"status"`
+	RequesterID string          `json:"requester_id" db:"requester_id"`
+	// RequiresApproval 提交时该操作是否配置为需要审批
+	RequiresApproval bool            `json:"requires_approval" db:"requires_approval"`
+	ApproverID       *string         `json:"approver_id,omitempty" db:"approver_id"`
+	DecisionComment  string          `json:"decision_comment,omitempty" db:"decision_comment"`
+	Result           json.RawMessage `json:"result,omitempty" db:"result"`
+	Error            string          `json:"error,omitempty" db:"error"`
+	ExpiresAt        time.Time       `json:"expires_at" db:"expires_at"`
+	DecidedAt        *time.Time      `json:"decided_at,omitempty" db:"decided_at"`
+	ExecutedAt       *time.Time      `json:"executed_at,omitempty" db:"executed_at"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+}
+
+// IsPending 检查请求是否等待审批
+func (r *ApprovalRequest) IsPending() bool {
+	return r.Status == ApprovalStatusPending
+}
+
+// DataSourceDeleteParams 删除数据源的参数
+type DataSourceDeleteParams struct {
+	ID string `json:"id"`
+}
+
+// TicketBulkCloseParams 批量关闭工单的参数
+type TicketBulkCloseParams struct {
+	IDs []string `json:"ids"`
+}
+
+// ApprovalSubmitRequest 提交敏感操作请求
+type ApprovalSubmitRequest struct {
+	Action ApprovalAction  `json:"action" binding:"required"`
+	Params json.RawMessage `json:"params"`
+	Reason string          `json:"reason"`
+}
+
+// Validate 验证操作和参数，并规范化参数
+func (r *ApprovalSubmitRequest) Validate() error {
+	if !r.Action.IsValid() {
+		return fmt.Errorf("%w: 不支持的操作 %s", ErrInvalidInput, r.Action)
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if utf8.RuneCountInString(r.Reason) > ApprovalMaxReason {
+		return fmt.Errorf("%w: 申请理由不能超过%d个字符", ErrInvalidInput, ApprovalMaxReason)
+	}
+
+	var params interface{}
+	switch r.Action {
+	case ApprovalActionDataSourceDelete:
+		var p DataSourceDeleteParams
+		if err := decodeApprovalParams(r.Params, &p); err != nil {
+			return err
+		}
+		p.ID = strings.TrimSpace(p.ID)
+		if p.ID == "" {
+			return fmt.Errorf("%w: 数据源ID不能为空", ErrInvalidInput)
+		}
+		params = p
+	case ApprovalActionTicketsBulkClose:
+		var p TicketBulkCloseParams
+		if err := decodeApprovalParams(r.Params, &p); err != nil {
+			return err
+		}
+		p.IDs = uniqueNonEmpty(p.IDs)
+		if len(p.IDs) == 0 {
+			return fmt.Errorf("%w: 工单ID不能为空", ErrInvalidInput)
+		}
+		if len(p.IDs) > ApprovalMaxBulkTickets {
+			return fmt.Errorf("%w: 单次最多关闭%d个工单", ErrInvalidInput, ApprovalMaxBulkTickets)
+		}
+		params = p
+	default:
+		r.Params = nil
+		return nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("序列化操作参数失败: %w", err)
+	}
+	r.Params = data
+	return nil
+}
+
+// decodeApprovalParams 解析操作参数
+func decodeApprovalParams(data json.RawMessage, dest interface{}) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: 操作参数不能为空", ErrInvalidInput)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("%w: 操作参数格式无效: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// uniqueNonEmpty 去除空白和重复的值，保持原有顺序
+func uniqueNonEmpty(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+// ApprovalDecisionRequest 批准或驳回审批请求
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// Validate 验证审批意见
+func (r *ApprovalDecisionRequest) Validate() error {
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > ApprovalMaxReason {
+		return fmt.Errorf("%w: 审批意见不能超过%d个字符", ErrInvalidInput, ApprovalMaxReason)
+	}
+	return nil
+}
+
+// ApprovalDecision 审批请求的状态变更，只有处于 From 状态的请求会被更新
+type ApprovalDecision struct {
+	From       ApprovalStatus
+	To         ApprovalStatus
+	ApproverID *string
+	Comment    string
+}
+
+// ApprovalOutcome 审批请求的执行结果
+type ApprovalOutcome struct {
+	Status ApprovalStatus
+	Result json.RawMessage
+	Error  string
+}
+
+// ApprovalFilter 审批请求列表过滤条件
+type ApprovalFilter struct {
+	Status      *ApprovalStatus `form:"status"`
+	Action      *ApprovalAction `form:"action"`
+	RequesterID *string         `form:"requester_id"`
+	Limit       int             `form:"limit"`
+}
+
+// Validate 验证过滤条件并补充默认值
+func (f *ApprovalFilter) Validate() error {
+	if f.Status != nil && !f.Status.IsValid() {
+		return fmt.Errorf("%w: 无效的审批状态 %s", ErrInvalidInput, *f.Status)
+	}
+	if f.Action != nil && !f.Action.IsValid() {
+		return fmt.Errorf("%w: 不支持的操作 %s", ErrInvalidInput, *f.Action)
+	}
+	if f.Limit <= 0 {
+		f.Limit = ApprovalDefaultListLimit
+	}
+	if f.Limit > ApprovalMaxListLimit {
+		f.Limit = ApprovalMaxListLimit
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AssigneeAnnotation 告警没有处理人字段，自动分派的处理人写入该注解
+	AssigneeAnnotation = "assignee"
+	// AssignmentPolicyMaxMembers 单个分派策略允许的最多成员数
+	AssignmentPolicyMaxMembers = 200
+	// AssignmentMemberMaxSkills 单个成员允许的最多技能标签数
+	AssignmentMemberMaxSkills = 50
+)
+
+// AssignmentStrategy 分派策略
+type AssignmentStrategy string
+
+const (
+	AssignmentStrategyRoundRobin AssignmentStrategy = "round_robin" // 轮询
+	AssignmentStrategyLeastLoad  AssignmentStrategy = "least_load"  // 未关闭工单最少者优先
+	AssignmentStrategySkillMatch AssignmentStrategy = "skill_match" // 技能标签匹配最多者优先，相同时负载最少者优先
+)
+
+// IsValid 检查分派策略是否有效
+func (s AssignmentStrategy) IsValid() bool {
+	switch s {
+	case AssignmentStrategyRoundRobin, AssignmentStrategyLeastLoad, AssignmentStrategySkillMatch:
+		return true
+	}
+	return false
+}
+
+// AssignmentMember 分派策略成员
+type AssignmentMember struct {
+	UserID string `json:"user_id"`
+	// Skills 技能标签，与工单的 tags、告警和工单的标签值或 key=value 匹配，不区分大小写
+	Skills []string `json:"skills"`
+}
+
+// SkillScore 返回成员技能与工单标签、告警或工单标签的匹配数
+func (m *AssignmentMember) SkillScore(tags []string, labels map[string]string) int {
+	terms := make(map[string]bool, len(tags)+len(labels)*2)
+	for _, tag := range tags {
+		terms[strings.ToLower(tag)] = true
+	}
+	for key, value := range labels {
+		terms[strings.ToLower(value)] = true
+		terms[strings.ToLower(key+"="+value)] = true
+	}
+
+	score := 0
+	for _, skill := range m.Skills {
+		if terms[strings.ToLower(skill)] {
+			score++
+		}
+	}
+	return score
+}
+
+// AssignmentPolicy 团队的自动分派策略，自动创建的工单和告警以及升级重新分派时按策略选择处理人
+type AssignmentPolicy struct {
+	ID        string             `json:"id" db:"id"`
+	Team      string             `json:"team" db:"team"` // 对应工单的 team_name 和告警的 team 标签
+	Strategy  AssignmentStrategy `json:"strategy" db:"strategy"`
+	Members   []AssignmentMember `json:"members" db:"members"`
+	Enabled   bool               `json:"enabled" db:"enabled"`
+	CreatedBy string             `json:"created_by" db:"created_by"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// AssignmentPolicyRequest 创建或更新分派策略请求
+type AssignmentPolicyRequest struct {
+	Team     string             `json:"team"`
+	Strategy AssignmentStrategy `json:"strategy"`
+	Members  []AssignmentMember `json:"members"`
+	Enabled  *bool              `json:"enabled,omitempty"`
+}
+
+// Validate 验证分派策略请求
+func (r *AssignmentPolicyRequest) Validate() error {
+	if strings.TrimSpace(r.Team) == "" || len(r.Team) > 100 {
+		return fmt.Errorf("%w: 团队不能为空且不能超过100个字符", ErrInvalidInput)
+	}
+	if !r.Strategy.IsValid() {
+		return fmt.Errorf("%w: 无效的分派策略 %q", ErrInvalidInput, r.Strategy)
+	}
+	if len(r.Members) == 0 || len(r.Members) > AssignmentPolicyMaxMembers {
+		return fmt.Errorf("%w: 成员数必须在1到%d之间", ErrInvalidInput, AssignmentPolicyMaxMembers)
+	}
+	seen := make(map[string]bool, len(r.Members))
+	for _, member := range r.Members {
+		userID := strings.TrimSpace(member.UserID)
+		if userID == "" {
+			return fmt.Errorf("%w: 成员用户ID不能为空", ErrInvalidInput)
+		}
+		if seen[userID] {
+			return fmt.Errorf("%w: 成员 %s 重复", ErrInvalidInput, userID)
+		}
+		seen[userID] = true
+		if len(member.Skills) > AssignmentMemberMaxSkills {
+			return fmt.Errorf("%w: 成员技能标签不能超过%d个", ErrInvalidInput, AssignmentMemberMaxSkills)
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求应用到分派策略，未提供启用状态时保持不变
+func (r *AssignmentPolicyRequest) ApplyTo(p *AssignmentPolicy) {
+	p.Team = strings.TrimSpace(r.Team)
+	p.Strategy = r.Strategy
+	p.Members = make([]AssignmentMember, 0, len(r.Members))
+	for _, member := range r.Members {
+		p.Members = append(p.Members, AssignmentMember{
+			UserID: strings.TrimSpace(member.UserID),
+			Skills: normalizeFlagTargets(member.Skills),
+		})
+	}
+	if r.Enabled != nil {
+		p.Enabled = *r.Enabled
+	}
+}
+
+// AssignmentTarget 待分派的工单或告警，用于技能匹配和排除当前处理人
+type AssignmentTarget struct {
+	Tags   []string
+	Labels map[string]string
+	// Exclude 不参与分派的用户，升级重新分派时排除当前处理人
+	Exclude string
+}
+
+// AssignmentPreview 按分派策略预览的处理人，不推进轮询计数。轮询策略下任一候选成员都可能被分派
+type AssignmentPreview struct {
+	PolicyID string             `json:"policy_id"`
+	Team     string             `json:"team"`
+	Strategy AssignmentStrategy `json:"strategy"`
+	UserIDs  []string           `json:"user_ids"`
+}
+
+// TicketAutoAssignRequest 按分派策略重新分派工单请求
+type TicketAutoAssignRequest struct {
+	// Team 为空时使用工单所属服务的负责团队
+	Team string `json:"team" binding:"max=100"`
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 异步任务的限制
+const (
+	// AsyncTaskMaxDataSources 批量检查数据源单次最多的数据源数
+	AsyncTaskMaxDataSources = 200
+	// AsyncTaskDefaultListLimit 异步任务列表默认返回的数量
+	AsyncTaskDefaultListLimit = 50
+)
+
+// AsyncTaskType 异步任务类型
+type AsyncTaskType string
+
+const (
+	AsyncTaskKnowledgeExport       AsyncTaskType = "knowledge.export"         // 导出知识库，参数为 KnowledgeExportParams，结果为 zip 文件
+	AsyncTaskDataSourceHealthCheck AsyncTaskType = "datasources.health_check" // 批量检查数据源连接，参数为 DataSourceHealthCheckParams
+)
+
+// IsValid 检查任务类型是否有效
+func (t AsyncTaskType) IsValid() bool {
+	switch t {
+	case AsyncTaskKnowledgeExport, AsyncTaskDataSourceHealthCheck:
+		return true
+	default:
+		return false
+	}
+}
+
+// AsyncTaskStatus 异步任务状态
+type AsyncTaskStatus string
+
+const (
+	AsyncTaskStatusPending   AsyncTaskStatus = "pending"   // 等待执行
+	AsyncTaskStatusRunning   AsyncTaskStatus = "running"   // 执行中
+	AsyncTaskStatusSucceeded AsyncTaskStatus = "succeeded" // 已完成
+	AsyncTaskStatusFailed    AsyncTaskStatus = "failed"    // 执行失败
+)
+
+// IsFinished 检查任务是否已结束
+func (s AsyncTaskStatus) IsFinished() bool {
+	return s == AsyncTaskStatusSucceeded || s == AsyncTaskStatusFailed
+}
+
+// AsyncTask 在后台执行的耗时操作，提交后由 Worker 领取执行，调用方轮询任务获取进度和结果。
+// 结果为文件时通过 ResultURL 下载，结束超过保留时间的任务连同结果文件一起清理
+type AsyncTask struct {
+	ID     string          `json:"id" db:"id"`
+	Type   AsyncTaskType   `json:"type" db:"type"`
+	Params json.RawMessage `json:"params,omitempty" db:"params"`
+	Status AsyncTaskStatus `json:"status" db:"status"`
+	// Total 和 Processed 反映执行进度，任务开始执行前为 0
+	Total     int64           `json:"total" db:"total"`
+	Processed int64           `json:"processed" db:"processed"`
+	Result    json.RawMessage `json:"result,omitempty" db:"result"`
+	// ResultFileName 结果文件名，结果不是文件时为空
+	ResultFileName string `json:"result_file_name,omitempty" db:"result_file_name"`
+	ResultFileType string `json:"-" db:"result_file_type"`
+	ResultFileSize int64  `json:"result_file_size,omitempty" db:"result_file_size"`
+	// ResultURL 结果文件的下载地址，由接口层填充
+	ResultURL  string     `json:"result_url,omitempty" db:"-"`
+	Error      string     `json:"error,omitempty" db:"error"`
+	CreatedBy  string     `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Progress 任务进度百分比，总数未知时已结束的任务为 100，其余为 0
+func (t *AsyncTask) Progress() int {
+	if t.Status.IsFinished() {
+		return 100
+	}
+	if t.Total <= 0 {
+		return 0
+	}
+	progress := int(t.Processed * 100 / t.Total)
+	if progress > 99 {
+		progress = 99
+	}
+	return progress
+}
+
+// HasResultFile 检查任务是否已完成且有结果文件
+func (t *AsyncTask) HasResultFile() bool {
+	return t.Status == AsyncTaskStatusSucceeded && t.ResultFileName != ""
+}
+
+// MarshalJSON 在任务字段之外输出进度百分比
+func (t AsyncTask) MarshalJSON() ([]byte, error) {
+	type asyncTask AsyncTask
+	return json.Marshal(struct {
+		asyncTask
+		Progress int `json:"progress"`
+	}{asyncTask(t), t.Progress()})
+}
+
+// AsyncTaskFile 异步任务的结果文件
+type AsyncTaskFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// AsyncTaskOutcome 异步任务的执行结果，Result 为结果摘要，File 为结果文件，执行失败时 Error 不为空
+type AsyncTaskOutcome struct {
+	Status    AsyncTaskStatus
+	Processed int64
+	Result    json.RawMessage
+	File      *AsyncTaskFile
+	Error     string
+}
+
+// KnowledgeExportParams 导出知识库的参数，CategoryIDs 为空时导出全部分类
+type KnowledgeExportParams struct {
+	CategoryIDs []string `json:"category_ids,omitempty"`
+}
+
+// DataSourceHealthCheckParams 批量检查数据源连接的参数，IDs 为空时检查全部启用的数据源
+type DataSourceHealthCheckParams struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+// DataSourceHealthCheckResult 单个数据源的连接检查结果
+type DataSourceHealthCheckResult struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AsyncTaskRequest 提交异步任务请求
+type AsyncTaskRequest struct {
+	Type   AsyncTaskType   `json:"type" binding:"required"`
+	Params json.RawMessage `json:"params"`
+}
+
+// Validate 验证任务类型和参数，并规范化参数
+func (r *AsyncTaskRequest) Validate() error {
+	var params interface{}
+	switch r.Type {
+	case AsyncTaskKnowledgeExport:
+		var p KnowledgeExportParams
+		if err := decodeAsyncTaskParams(r.Params, &p); err != nil {
+			return err
+		}
+		p.CategoryIDs = uniqueNonEmpty(p.CategoryIDs)
+		params = p
+	case AsyncTaskDataSourceHealthCheck:
+		var p DataSourceHealthCheckParams
+		if err := decodeAsyncTaskParams(r.Params, &p); err != nil {
+			return err
+		}
+		p.IDs = uniqueNonEmpty(p.IDs)
+		if len(p.IDs) > AsyncTaskMaxDataSources {
+			return fmt.Errorf("%w: 单次最多检查%d个数据源", ErrInvalidInput, AsyncTaskMaxDataSources)
+		}
+		params = p
+	default:
+		return fmt.Errorf("%w: 不支持的任务类型 %s", ErrInvalidInput, r.Type)
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("序列化任务参数失败: %w", err)
+	}
+	r.Params = data
+	return nil
+}
+
+// decodeAsyncTaskParams 解析任务参数，参数可以为空
+func decodeAsyncTaskParams(data json.RawMessage, dest interface{}) error {
+	if len(data) == 0 || strings.TrimSpace(string(data)) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("%w: 任务参数格式无效: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog 用户操作审计日志
+type AuditLog struct {
+	ID           string          `json:"id" db:"id"`
+	UserID       *string         `json:"user_id,omitempty" db:"user_id"`
+	Action       string          `json:"action" db:"action"`
+	ResourceType *string         `json:"resource_type,omitempty" db:"resource_type"`
+	ResourceID   *string         `json:"resource_id,omitempty" db:"resource_id"`
+	IPAddress    *string         `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent    *string         `json:"user_agent,omitempty" db:"user_agent"`
+	RequestID    *string         `json:"request_id,omitempty" db:"request_id"`
+	OldValues    json.RawMessage `json:"old_values,omitempty" db:"old_values"`
+	NewValues    json.RawMessage `json:"new_values,omitempty" db:"new_values"`
+	Changes      json.RawMessage `json:"changes,omitempty" db:"changes"`
+	Success      bool            `json:"success" db:"success"`
+	ErrorMessage *string         `json:"error_message,omitempty" db:"error_message"`
+	Metadata     json.RawMessage `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditLogFilter 审计日志过滤器
+type AuditLogFilter struct {
+	UserID       *string    `json:"user_id,omitempty" form:"user_id"`
+	Action       *string    `json:"action,omitempty" form:"action"`
+	ResourceType *string    `json:"resource_type,omitempty" form:"resource_type"`
+	ResourceID   *string    `json:"resource_id,omitempty" form:"resource_id"`
+	Success      *bool      `json:"success,omitempty" form:"success"`
+	StartTime    *time.Time `json:"start_time,omitempty" form:"start_time"`
+	EndTime      *time.Time `json:"end_time,omitempty" form:"end_time"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackupFormatVersion 当前的备份包格式版本，备份包内容结构变化时递增
+const BackupFormatVersion = 2
+
+// BackupFormatVersionAssignmentPolicies 开始包含团队分派策略的备份包格式版本，更早的备份包恢复时没有分派策略
+const BackupFormatVersionAssignmentPolicies = 2
+
+// BackupMaxSize 上传恢复的备份包最大大小
+const BackupMaxSize = 200 << 20
+
+// 备份包中的文件
+const (
+	BackupManifestFile    = "manifest.json"
+	BackupUsersFile       = "users.json"
+	BackupDataSourcesFile = "data_sources.json"
+	BackupRulesFile       = "rules.json"
+	BackupRoutesFile      = "routes.json"
+	BackupTemplatesFile   = "templates.json"
+	BackupAssignmentFile  = "assignment_policies.json"
+)
+
+// 备份的资源类型
+const (
+	BackupKindUser       = "user"
+	BackupKindDataSource = "data_source"
+	BackupKindRule       = "rule"
+	BackupKindRoute      = "route"
+	BackupKindTemplate   = "template"
+	BackupKindAssignment = "assignment_policy"
+)
+
+// BackupManifest 备份包清单
+type BackupManifest struct {
+	FormatVersion int            `json:"format_version"`
+	PulseVersion  string         `json:"pulse_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	CreatedBy     string         `json:"created_by"`
+	Counts        map[string]int `json:"counts"`
+}
+
+// CheckCompatibility 检查备份包能否恢复到当前版本的实例。
+// 格式版本必须受支持；Pulse 版本的主版本号必须相同，且备份不能来自更新的次版本
+func (m *BackupManifest) CheckCompatibility(currentVersion string) error {
+	if m.FormatVersion <= 0 || m.FormatVersion > BackupFormatVersion {
+		return fmt.Errorf("%w: 备份包格式版本 %d，当前支持 %d", ErrBackupIncompatible, m.FormatVersion, BackupFormatVersion)
+	}
+
+	backupMajor, backupMinor, okBackup := parseMajorMinor(m.PulseVersion)
+	currentMajor, currentMinor, okCurrent := parseMajorMinor(currentVersion)
+	if !okBackup || !okCurrent {
+		if m.PulseVersion != currentVersion {
+			return fmt.Errorf("%w: 备份来自版本 %q，当前版本 %q", ErrBackupIncompatible, m.PulseVersion, currentVersion)
+		}
+		return nil
+	}
+	if backupMajor != currentMajor || backupMinor > currentMinor {
+		return fmt.Errorf("%w: 备份来自版本 %s，当前版本 %s", ErrBackupIncompatible, m.PulseVersion, currentVersion)
+	}
+	return nil
+}
+
+// parseMajorMinor 解析 "v1.2.3" 或 "1.2" 形式版本号的主版本号和次版本号
+func parseMajorMinor(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// BackupUser 备份中的用户，密码哈希使用备份口令加密
+type BackupUser struct {
+	User
+	PasswordHash string `json:"password_hash"`
+}
+
+// BackupExportRequest 导出备份请求
+type BackupExportRequest struct {
+	Passphrase string `json:"passphrase" binding:"required,min=12"`
+}
+
+// BackupSkippedItem 恢复时跳过的资源
+type BackupSkippedItem struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// BackupRestoreResult 备份恢复结果
+type BackupRestoreResult struct {
+	Manifest *BackupManifest     `json:"manifest"`
+	Restored map[string]int      `json:"restored"`
+	Skipped  []BackupSkippedItem `json:"skipped"`
+}
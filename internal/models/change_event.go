@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 告警关联变更相关的注解
+const (
+	// AlertAnnotationPossibleCause 告警触发前不久同一服务上的变更摘要，作为可能原因展示
+	AlertAnnotationPossibleCause = "possible_cause"
+	// AlertAnnotationChangeEventID 关联的变更事件 ID
+	AlertAnnotationChangeEventID = "change_event_id"
+)
+
+// ChangeEventType 变更类型
+type ChangeEventType string
+
+const (
+	ChangeEventTypeDeployment  ChangeEventType = "deployment"   // 发布部署
+	ChangeEventTypeConfig      ChangeEventType = "config"       // 配置变更
+	ChangeEventTypeFeatureFlag ChangeEventType = "feature_flag" // 功能开关切换
+)
+
+// IsValid 检查变更类型是否有效
+func (t ChangeEventType) IsValid() bool {
+	switch t {
+	case ChangeEventTypeDeployment, ChangeEventTypeConfig, ChangeEventTypeFeatureFlag:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChangeEvent 变更事件，由 CI/CD 或运维人员登记，告警触发前窗口内同一服务的变更视为可能原因
+type ChangeEvent struct {
+	ID string `json:"id" db:"id"`
+	// Service 受影响的服务名，与告警的 service 标签匹配
+	Service     string          `json:"service" db:"service"`
+	Type        ChangeEventType `json:"type" db:"type"`
+	Title       string          `json:"title" db:"title"`
+	Description string          `json:"description" db:"description"`
+	// Version 发布的版本、配置的修订号或功能开关的新状态
+	Version string `json:"version" db:"version"`
+	// Source 登记变更的系统，如 github-actions、argocd
+	Source     string    `json:"source" db:"source"`
+	URL        *string   `json:"url,omitempty" db:"url"`
+	CreatedBy  *string   `json:"created_by,omitempty" db:"created_by"`
+	OccurredAt time.Time `json:"occurred_at" db:"occurred_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Summary 变更摘要，写入告警的 possible_cause 注解，如 deployment checkout v1.4.2: 升级支付 SDK
+func (e *ChangeEvent) Summary() string {
+	summary := fmt.Sprintf("%s %s", e.Type, e.Service)
+	if e.Version != "" {
+		summary += " " + e.Version
+	}
+	return summary + ": " + e.Title
+}
+
+// ChangeEventRequest 登记变更事件请求
+type ChangeEventRequest struct {
+	Service     string          `json:"service" binding:"required"`
+	Type        ChangeEventType `json:"type" binding:"required"`
+	Title       string          `json:"title" binding:"required,max=255"`
+	Description string          `json:"description"`
+	Version     string          `json:"version" binding:"max=100"`
+	Source      string          `json:"source" binding:"max=100"`
+	URL         *string         `json:"url,omitempty"`
+	// OccurredAt 变更发生的时间，为空时使用登记时间
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
+}
+
+// Validate 验证登记变更事件请求
+func (r *ChangeEventRequest) Validate() error {
+	r.Service = strings.TrimSpace(r.Service)
+	if !catalogServiceNamePattern.MatchString(r.Service) {
+		return fmt.Errorf("%w: 无效的服务名称 %q", ErrInvalidInput, r.Service)
+	}
+	if !r.Type.IsValid() {
+		return fmt.Errorf("%w: 无效的变更类型 %s", ErrInvalidInput, r.Type)
+	}
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		return fmt.Errorf("%w: 变更标题不能为空", ErrInvalidInput)
+	}
+	if r.URL != nil && *r.URL != "" {
+		u, err := url.Parse(*r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: 无效的变更链接 %q", ErrInvalidInput, *r.URL)
+		}
+	}
+	return nil
+}
+
+// ChangeEventFilter 变更事件过滤器
+type ChangeEventFilter struct {
+	Service  *string          `json:"service,omitempty"`
+	Type     *ChangeEventType `json:"type,omitempty"`
+	From     *time.Time       `json:"from,omitempty"`
+	To       *time.Time       `json:"to,omitempty"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
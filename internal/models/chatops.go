@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChatPlatform 聊天平台
+type ChatPlatform string
+
+const (
+	ChatPlatformSlack    ChatPlatform = "slack"    // Slack
+	ChatPlatformDingTalk ChatPlatform = "dingtalk" // 钉钉
+)
+
+// IsValid 检查聊天平台是否有效
+func (p ChatPlatform) IsValid() bool {
+	switch p {
+	case ChatPlatformSlack, ChatPlatformDingTalk:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChatUserMapping 聊天平台用户与平台用户的映射，聊天命令以映射到的平台用户身份执行
+type ChatUserMapping struct {
+	ID         string       `json:"id" db:"id"`
+	Platform   ChatPlatform `json:"platform" db:"platform"`
+	ChatUserID string       `json:"chat_user_id" db:"chat_user_id"`
+	UserID     string       `json:"user_id" db:"user_id"`
+	CreatedBy  string       `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// ChatUserMappingRequest 绑定聊天平台用户请求
+type ChatUserMappingRequest struct {
+	Platform   ChatPlatform `json:"platform" binding:"required"`
+	ChatUserID string       `json:"chat_user_id" binding:"required,max=100"`
+	UserID     string       `json:"user_id" binding:"required"`
+}
+
+// Validate 验证绑定聊天平台用户请求
+func (req *ChatUserMappingRequest) Validate() error {
+	if !req.Platform.IsValid() {
+		return fmt.Errorf("%w: 不支持的聊天平台 %s", ErrInvalidInput, req.Platform)
+	}
+	if strings.TrimSpace(req.ChatUserID) == "" {
+		return fmt.Errorf("%w: 聊天平台用户ID不能为空", ErrInvalidInput)
+	}
+	if strings.TrimSpace(req.UserID) == "" {
+		return fmt.Errorf("%w: 平台用户ID不能为空", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ChatCommandAction 聊天命令动作
+type ChatCommandAction string
+
+const (
+	ChatCommandAck     ChatCommandAction = "ack"     // 确认告警
+	ChatCommandResolve ChatCommandAction = "resolve" // 解决告警
+	ChatCommandAssign  ChatCommandAction = "assign"  // 指派告警处理人
+	ChatCommandSilence ChatCommandAction = "silence" // 静默告警
+	ChatCommandHelp    ChatCommandAction = "help"    // 帮助
+)
+
+// IsValid 检查聊天命令动作是否有效
+func (a ChatCommandAction) IsValid() bool {
+	switch a {
+	case ChatCommandAck, ChatCommandResolve, ChatCommandAssign, ChatCommandSilence, ChatCommandHelp:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChatCommandUsage 聊天命令用法说明
+const ChatCommandUsage = "可用命令:\n" +
+	"/ack <告警ID> 确认告警\n" +
+	"/resolve <告警ID> 解决告警\n" +
+	"/assign <告警ID> @用户 指派处理人\n" +
+	"/silence <告警ID> <时长，如 30m、2h> 静默告警"
+
+// ChatCommand 解析后的聊天命令
+type ChatCommand struct {
+	Action   ChatCommandAction
+	AlertID  string
+	Assignee string
+	Duration time.Duration
+}
+
+// ParseChatCommand 解析聊天命令文本，支持 "/ack <id>" 和 "ack <id>" 两种形式
+// 指派对象保留原始写法（@username 或 Slack 的 <@U123|name>），由调用方解析到平台用户
+func ParseChatCommand(text string) (*ChatCommand, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return &ChatCommand{Action: ChatCommandHelp}, nil
+	}
+
+	cmd := &ChatCommand{Action: ChatCommandAction(strings.ToLower(strings.TrimPrefix(fields[0], "/")))}
+	args := fields[1:]
+
+	switch cmd.Action {
+	case ChatCommandHelp:
+		return cmd, nil
+	case ChatCommandAck, ChatCommandResolve:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%w: 用法 /%s <告警ID>", ErrInvalidInput, cmd.Action)
+		}
+	case ChatCommandAssign:
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%w: 用法 /assign <告警ID> @用户", ErrInvalidInput)
+		}
+		cmd.Assignee = args[1]
+	case ChatCommandSilence:
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%w: 用法 /silence <告警ID> <时长>", ErrInvalidInput)
+		}
+		duration, err := time.ParseDuration(args[1])
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("%w: 无效的静默时长 %s", ErrInvalidInput, args[1])
+		}
+		cmd.Duration = duration
+	default:
+		return nil, fmt.Errorf("%w: 未知命令 %s", ErrInvalidInput, fields[0])
+	}
+
+	cmd.AlertID = args[0]
+	return cmd, nil
+}
@@ -0,0 +1,317 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigBundleVersion 声明式配置文件格式版本，pulsectl 导出的规则文件使用相同格式
+const ConfigBundleVersion = "pulse/v1"
+
+// 声明式配置的资源类型
+const (
+	ConfigKindDataSource = "data_source"
+	ConfigKindRule       = "rule"
+	ConfigKindRoute      = "route"
+	ConfigKindSilence    = "silence"
+)
+
+// 声明式配置的变更动作
+const (
+	ConfigActionCreate    = "create"
+	ConfigActionUpdate    = "update"
+	ConfigActionDelete    = "delete"
+	ConfigActionUnchanged = "unchanged"
+)
+
+// ConfigBundle 声明式配置，数据源、规则和通知路由按名称与现有配置对比，告警暂停按标签匹配条件对比。
+// 省略（null）的资源类型不做任何变更；开启 prune 时，列出的资源类型中配置文件未包含的资源将被删除，
+// 空列表表示删除该类型的全部资源
+type ConfigBundle struct {
+	Version     string                        `json:"version"`
+	DataSources []DataSourceSpec              `json:"data_sources"`
+	Rules       []RuleSpec                    `json:"rules"`
+	Routes      []NotificationCalendarRequest `json:"routes"`
+	Silences    []SilenceSpec                 `json:"silences"`
+}
+
+// Validate 验证配置文件版本和资源名称，同一类型的资源名称不能重复
+func (b *ConfigBundle) Validate() error {
+	if b.Version != "" && b.Version != ConfigBundleVersion {
+		return fmt.Errorf("%w: 不支持的配置版本 %q，应为 %q", ErrInvalidInput, b.Version, ConfigBundleVersion)
+	}
+
+	names := func(kind string, count int, name func(i int) string) error {
+		seen := make(map[string]bool, count)
+		for i := 0; i < count; i++ {
+			n := name(i)
+			if strings.TrimSpace(n) == "" {
+				return fmt.Errorf("%w: 第 %d 个 %s 缺少名称", ErrInvalidInput, i+1, kind)
+			}
+			if seen[n] {
+				return fmt.Errorf("%w: %s 名称 %q 重复", ErrInvalidInput, kind, n)
+			}
+			seen[n] = true
+		}
+		return nil
+	}
+	if err := names(ConfigKindDataSource, len(b.DataSources), func(i int) string { return b.DataSources[i].Name }); err != nil {
+		return err
+	}
+	if err := names(ConfigKindRule, len(b.Rules), func(i int) string { return b.Rules[i].Name }); err != nil {
+		return err
+	}
+	if err := names(ConfigKindRoute, len(b.Routes), func(i int) string { return b.Routes[i].Name }); err != nil {
+		return err
+	}
+	for i, silence := range b.Silences {
+		if len(silence.Matchers) == 0 {
+			return fmt.Errorf("%w: 第 %d 个 %s 缺少标签匹配条件", ErrInvalidInput, i+1, ConfigKindSilence)
+		}
+	}
+	return names(ConfigKindSilence, len(b.Silences), func(i int) string { return b.Silences[i].Key() })
+}
+
+// DataSourceSpec 可移植的数据源定义，不包含 ID、健康检查结果和时间戳等运行时字段。
+// 密码、Token 等敏感字段可以使用 API 返回的脱敏值，更新时还原为已保存的值
+type DataSourceSpec struct {
+	Name           string           `json:"name"`
+	Description    string           `json:"description"`
+	Type           DataSourceType   `json:"type"`
+	Status         DataSourceStatus `json:"status,omitempty"`
+	Config         DataSourceConfig `json:"config"`
+	Tags           []string         `json:"tags,omitempty"`
+	Version        *string          `json:"version,omitempty"`
+	HealthCheckURL *string          `json:"health_check_url,omitempty"`
+}
+
+// NewDataSourceSpec 由数据源生成可移植的定义
+func NewDataSourceSpec(ds *DataSource) DataSourceSpec {
+	return DataSourceSpec{
+		Name:           ds.Name,
+		Description:    ds.Description,
+		Type:           ds.Type,
+		Status:         ds.Status,
+		Config:         ds.Config,
+		Tags:           ds.Tags,
+		Version:        ds.Version,
+		HealthCheckURL: ds.HealthCheckURL,
+	}
+}
+
+// ApplyTo 将定义写入数据源，ds 为已存在的数据源或新数据源。未指定状态时保留现有状态，新数据源为 active
+func (s DataSourceSpec) ApplyTo(ds *DataSource) error {
+	ds.Name = s.Name
+	ds.Description = s.Description
+	ds.Type = s.Type
+	if s.Status != "" {
+		ds.Status = s.Status
+	} else if ds.Status == "" {
+		ds.Status = DataSourceStatusActive
+	}
+	ds.Config = s.Config
+	ds.Tags = s.Tags
+	if ds.Tags == nil {
+		ds.Tags = []string{}
+	}
+	ds.Version = s.Version
+	ds.HealthCheckURL = s.HealthCheckURL
+	if err := ds.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// RuleSpec 可移植的规则定义，不包含 ID、评估统计和时间戳等运行时字段。
+// 数据源按名称引用以便在不同环境间迁移，未指定名称时使用数据源ID
+type RuleSpec struct {
+	Name               string            `json:"name"`
+	Description        string            `json:"description"`
+	Type               RuleType          `json:"type"`
+	Severity           AlertSeverity     `json:"severity"`
+	Status             RuleStatus        `json:"status,omitempty"`
+	Enabled            bool              `json:"enabled"`
+	DataSource         string            `json:"data_source,omitempty"`
+	DataSourceID       string            `json:"data_source_id,omitempty"`
+	Expression         string            `json:"expression"`
+	Conditions         []RuleCondition   `json:"conditions,omitempty"`
+	Actions            []RuleAction      `json:"actions,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	Annotations        map[string]string `json:"annotations,omitempty"`
+	EvaluationInterval string            `json:"evaluation_interval"`
+	For                string            `json:"for,omitempty"`
+	KeepFiringFor      string            `json:"keep_firing_for,omitempty"`
+	Threshold          *float64          `json:"threshold,omitempty"`
+	RecoveryThreshold  *float64          `json:"recovery_threshold,omitempty"`
+	NoDataState        *string           `json:"no_data_state,omitempty"`
+	ExecErrState       *string           `json:"exec_err_state,omitempty"`
+}
+
+// NewRuleSpec 由规则生成可移植的定义，dataSource 为数据源名称，为空时只保留数据源ID
+func NewRuleSpec(rule *Rule, dataSource string) RuleSpec {
+	spec := RuleSpec{
+		Name:               rule.Name,
+		Description:        rule.Description,
+		Type:               rule.Type,
+		Severity:           rule.Severity,
+		Status:             rule.Status,
+		Enabled:            rule.Enabled,
+		DataSource:         dataSource,
+		Expression:         rule.Expression,
+		Conditions:         rule.Conditions,
+		Actions:            rule.Actions,
+		Labels:             rule.Labels,
+		Annotations:        rule.Annotations,
+		EvaluationInterval: rule.EvaluationInterval.String(),
+		For:                formatOptionalDuration(rule.ForDuration),
+		KeepFiringFor:      formatOptionalDuration(rule.KeepFiringFor),
+		Threshold:          rule.Threshold,
+		RecoveryThreshold:  rule.RecoveryThreshold,
+		NoDataState:        rule.NoDataState,
+		ExecErrState:       rule.ExecErrState,
+	}
+	if dataSource == "" {
+		spec.DataSourceID = rule.DataSourceID
+	}
+	return spec
+}
+
+// ApplyTo 将定义写入规则，rule 为已存在的规则或新规则，dataSourceID 为解析后的数据源ID
+func (s RuleSpec) ApplyTo(rule *Rule, dataSourceID string) error {
+	interval, err := time.ParseDuration(s.EvaluationInterval)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的评估间隔 %q", ErrInvalidInput, s.EvaluationInterval)
+	}
+	forDuration, err := parseOptionalDuration(s.For)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的持续时间 %q", ErrInvalidInput, s.For)
+	}
+	keepFiringFor, err := parseOptionalDuration(s.KeepFiringFor)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的保持触发时间 %q", ErrInvalidInput, s.KeepFiringFor)
+	}
+
+	rule.Name = s.Name
+	rule.Description = s.Description
+	rule.Type = s.Type
+	rule.Severity = s.Severity
+	rule.Status = s.Status
+	if rule.Status == "" {
+		rule.Status = RuleStatusActive
+	}
+	rule.Enabled = s.Enabled
+	rule.DataSourceID = dataSourceID
+	rule.Expression = s.Expression
+	rule.Conditions = s.Conditions
+	rule.Actions = s.Actions
+	rule.Labels = s.Labels
+	rule.Annotations = s.Annotations
+	rule.EvaluationInterval = interval
+	rule.ForDuration = forDuration
+	rule.KeepFiringFor = keepFiringFor
+	rule.Threshold = s.Threshold
+	rule.RecoveryThreshold = s.RecoveryThreshold
+	rule.NoDataState = s.NoDataState
+	rule.ExecErrState = s.ExecErrState
+	if err := rule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// SilenceSpec 声明式的告警暂停，只对执行配置的用户生效，标签匹配条件相同的暂停视为同一个
+type SilenceSpec struct {
+	Matchers  map[string]string `json:"matchers"`
+	Reason    string            `json:"reason,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// NewSilenceSpec 由告警暂停生成声明式定义，过期时间精确到秒
+func NewSilenceSpec(snooze *AlertSnooze) SilenceSpec {
+	return SilenceSpec{
+		Matchers:  snooze.Matchers,
+		Reason:    snooze.Reason,
+		ExpiresAt: snooze.ExpiresAt.UTC().Truncate(time.Second),
+	}
+}
+
+// Key 标签匹配条件按标签名排序后的文本，作为告警暂停的名称
+func (s SilenceSpec) Key() string {
+	pairs := make([]string, 0, len(s.Matchers))
+	for key, value := range s.Matchers {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Validate 验证告警暂停定义，过期时间必须在 now 之后且不超过最长暂停时间
+func (s SilenceSpec) Validate(now time.Time) error {
+	req := AlertSnoozeRequest{Matchers: s.Matchers, DurationSeconds: int(time.Minute / time.Second), Reason: s.Reason}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if !s.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: 过期时间 %s 已过", ErrInvalidInput, s.ExpiresAt.Format(time.RFC3339))
+	}
+	if s.ExpiresAt.Sub(now) > AlertSnoozeMaxDuration {
+		return fmt.Errorf("%w: 过期时间不能晚于当前时间 %s 之后", ErrInvalidInput, AlertSnoozeMaxDuration)
+	}
+	return nil
+}
+
+// ConfigApplyOptions 执行声明式配置的选项
+type ConfigApplyOptions struct {
+	// DryRun 只计算变更，不写入
+	DryRun bool
+	// Prune 删除配置文件未包含的资源，只作用于配置文件中列出的资源类型
+	Prune bool
+}
+
+// ConfigFieldDiff 单个字段的变更，敏感字段的值已脱敏
+type ConfigFieldDiff struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// ConfigChange 单个资源的变更
+type ConfigChange struct {
+	Kind   string            `json:"kind"`
+	Name   string            `json:"name"`
+	Action string            `json:"action"`
+	Diff   []ConfigFieldDiff `json:"diff,omitempty"`
+}
+
+// ConfigApplyIssue 配置校验失败的资源
+type ConfigApplyIssue struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// ConfigApplyResult 执行声明式配置的结果，存在校验失败的资源时不做任何变更
+type ConfigApplyResult struct {
+	DryRun  bool               `json:"dry_run"`
+	Changes []ConfigChange     `json:"changes"`
+	Summary map[string]int     `json:"summary"`
+	Issues  []ConfigApplyIssue `json:"issues,omitempty"`
+}
+
+// formatOptionalDuration 格式化可选的时长，0 输出为空
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// parseOptionalDuration 解析可选的时长，空字符串为 0
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,491 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CustomFieldResource 自定义字段适用的资源类型。
+// 只支持创建和更新时会校验自定义字段值的资源，告警和知识库没有保存自定义字段值，不能定义字段
+type CustomFieldResource string
+
+const (
+	CustomFieldResourceTicket CustomFieldResource = "ticket" // 工单
+)
+
+// CustomFieldType 自定义字段类型
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText        CustomFieldType = "text"         // 单行文本
+	CustomFieldTypeTextarea    CustomFieldType = "textarea"     // 多行文本
+	CustomFieldTypeNumber      CustomFieldType = "number"       // 数字
+	CustomFieldTypeBoolean     CustomFieldType = "boolean"      // 布尔
+	CustomFieldTypeSelect      CustomFieldType = "select"       // 单选
+	CustomFieldTypeMultiSelect CustomFieldType = "multi_select" // 多选
+	CustomFieldTypeDate        CustomFieldType = "date"         // 日期
+	CustomFieldTypeURL         CustomFieldType = "url"          // 链接
+)
+
+// customFieldKeyPattern 字段键格式：小写字母开头，仅包含小写字母、数字和下划线
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,99}$`)
+
+// CustomFieldValidation 自定义字段校验规则
+type CustomFieldValidation struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Pattern   *string  `json:"pattern,omitempty"`
+}
+
+// CustomFieldDefinition 自定义字段定义
+type CustomFieldDefinition struct {
+	ID           string                `json:"id" db:"id"`
+	Resource     CustomFieldResource   `json:"resource" db:"resource"`
+	Key          string                `json:"key" db:"field_key"`
+	Name         string                `json:"name" db:"name"`
+	Description  *string               `json:"description,omitempty" db:"description"`
+	Type         CustomFieldType       `json:"type" db:"field_type"`
+	Options      []string              `json:"options,omitempty" db:"options"`
+	Validation   CustomFieldValidation `json:"validation" db:"validation"`
+	DefaultValue interface{}           `json:"default_value,omitempty" db:"default_value"`
+	Required     bool                  `json:"required" db:"required"`
+	Enabled      bool                  `json:"enabled" db:"enabled"`
+	SortOrder    int                   `json:"sort_order" db:"sort_order"`
+	CreatedBy    *string               `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time            `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// CustomFieldDefinitionCreateRequest 创建自定义字段定义请求
+type CustomFieldDefinitionCreateRequest struct {
+	Resource     CustomFieldResource    `json:"resource" binding:"required"`
+	Key          string                 `json:"key" binding:"required,min=1,max=100"`
+	Name         string                 `json:"name" binding:"required,min=1,max=200"`
+	Description  *string                `json:"description,omitempty"`
+	Type         CustomFieldType        `json:"type" binding:"required"`
+	Options      []string               `json:"options,omitempty"`
+	Validation   *CustomFieldValidation `json:"validation,omitempty"`
+	DefaultValue interface{}            `json:"default_value,omitempty"`
+	Required     bool                   `json:"required"`
+	Enabled      *bool                  `json:"enabled,omitempty"`
+	SortOrder    int                    `json:"sort_order"`
+}
+
+// CustomFieldDefinitionUpdateRequest 更新自定义字段定义请求
+// 字段键、资源类型和字段类型创建后不可修改，避免已有数据失效
+type CustomFieldDefinitionUpdateRequest struct {
+	Name         *string                `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	Description  *string                `json:"description,omitempty"`
+	Options      *[]string              `json:"options,omitempty"`
+	Validation   *CustomFieldValidation `json:"validation,omitempty"`
+	DefaultValue interface{}            `json:"default_value,omitempty"`
+	Required     *bool                  `json:"required,omitempty"`
+	Enabled      *bool                  `json:"enabled,omitempty"`
+	SortOrder    *int                   `json:"sort_order,omitempty"`
+}
+
+// CustomFieldDefinitionFilter 自定义字段定义过滤器
+type CustomFieldDefinitionFilter struct {
+	Resource *CustomFieldResource `json:"resource,omitempty"`
+	Enabled  *bool                `json:"enabled,omitempty"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+}
+
+// CustomFieldDefinitionList 自定义字段定义列表
+type CustomFieldDefinitionList struct {
+	Definitions []*CustomFieldDefinition `json:"definitions"`
+	Total       int64                    `json:"total"`
+	Page        int                      `json:"page"`
+	PageSize    int                      `json:"page_size"`
+}
+
+// IsValid 检查资源类型是否有效
+func (r CustomFieldResource) IsValid() bool {
+	switch r {
+	case CustomFieldResourceTicket:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid 检查字段类型是否有效
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldTypeText, CustomFieldTypeTextarea, CustomFieldTypeNumber, CustomFieldTypeBoolean,
+		CustomFieldTypeSelect, CustomFieldTypeMultiSelect, CustomFieldTypeDate, CustomFieldTypeURL:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasOptions 字段类型是否需要可选值
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldTypeSelect || t == CustomFieldTypeMultiSelect
+}
+
+// Validate 验证自定义字段定义
+func (d *CustomFieldDefinition) Validate() error {
+	if !d.Resource.IsValid() {
+		return errors.New("无效的资源类型")
+	}
+
+	if !customFieldKeyPattern.MatchString(d.Key) {
+		return errors.New("字段键必须以小写字母开头，且只能包含小写字母、数字和下划线")
+	}
+
+	if strings.TrimSpace(d.Name) == "" {
+		return errors.New("字段名称不能为空")
+	}
+
+	if !d.Type.IsValid() {
+		return errors.New("无效的字段类型")
+	}
+
+	if d.Type.HasOptions() {
+		if len(d.Options) == 0 {
+			return errors.New("选择类型字段必须提供可选值")
+		}
+		seen := make(map[string]bool, len(d.Options))
+		for _, option := range d.Options {
+			if strings.TrimSpace(option) == "" {
+				return errors.New("可选值不能为空")
+			}
+			if seen[option] {
+				return fmt.Errorf("可选值重复: %s", option)
+			}
+			seen[option] = true
+		}
+	} else if len(d.Options) > 0 {
+		return errors.New("仅选择类型字段支持可选值")
+	}
+
+	v := d.Validation
+	if v.MinLength != nil && *v.MinLength < 0 {
+		return errors.New("最小长度不能小于0")
+	}
+	if v.MinLength != nil && v.MaxLength != nil && *v.MinLength > *v.MaxLength {
+		return errors.New("最小长度不能大于最大长度")
+	}
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		return errors.New("最小值不能大于最大值")
+	}
+	if v.Pattern != nil {
+		if _, err := regexp.Compile(*v.Pattern); err != nil {
+			return fmt.Errorf("无效的正则表达式: %w", err)
+		}
+	}
+
+	if d.DefaultValue != nil {
+		if err := d.ValidateValue(d.DefaultValue); err != nil {
+			return fmt.Errorf("默认值无效: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateValue 按字段定义校验单个字段值
+func (d *CustomFieldDefinition) ValidateValue(value interface{}) error {
+	if value == nil {
+		if d.Required {
+			return errors.New("不能为空")
+		}
+		return nil
+	}
+
+	switch d.Type {
+	case CustomFieldTypeText, CustomFieldTypeTextarea:
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("必须为字符串")
+		}
+		return d.validateString(s)
+
+	case CustomFieldTypeNumber:
+		n, ok := toFloat64(value)
+		if !ok {
+			return errors.New("必须为数字")
+		}
+		if d.Validation.Min != nil && n < *d.Validation.Min {
+			return fmt.Errorf("不能小于 %v", *d.Validation.Min)
+		}
+		if d.Validation.Max != nil && n > *d.Validation.Max {
+			return fmt.Errorf("不能大于 %v", *d.Validation.Max)
+		}
+
+	case CustomFieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return errors.New("必须为布尔值")
+		}
+
+	case CustomFieldTypeSelect:
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("必须为字符串")
+		}
+		if !d.hasOption(s) {
+			return fmt.Errorf("不在可选值范围内: %s", s)
+		}
+
+	case CustomFieldTypeMultiSelect:
+		values, ok := toStringSlice(value)
+		if !ok {
+			return errors.New("必须为字符串数组")
+		}
+		if d.Required && len(values) == 0 {
+			return errors.New("至少选择一项")
+		}
+		for _, s := range values {
+			if !d.hasOption(s) {
+				return fmt.Errorf("不在可选值范围内: %s", s)
+			}
+		}
+
+	case CustomFieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("必须为日期字符串")
+		}
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				return errors.New("日期格式无效，应为 YYYY-MM-DD 或 RFC3339")
+			}
+		}
+
+	case CustomFieldTypeURL:
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("必须为字符串")
+		}
+		u, err := url.ParseRequestURI(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("必须为有效的 http/https 链接")
+		}
+		return d.validateString(s)
+
+	default:
+		return fmt.Errorf("不支持的字段类型: %s", d.Type)
+	}
+
+	return nil
+}
+
+// validateString 校验字符串长度和正则
+func (d *CustomFieldDefinition) validateString(s string) error {
+	if d.Required && strings.TrimSpace(s) == "" {
+		return errors.New("不能为空")
+	}
+
+	length := len([]rune(s))
+	if d.Validation.MinLength != nil && length < *d.Validation.MinLength {
+		return fmt.Errorf("长度不能小于 %d", *d.Validation.MinLength)
+	}
+	if d.Validation.MaxLength != nil && length > *d.Validation.MaxLength {
+		return fmt.Errorf("长度不能超过 %d", *d.Validation.MaxLength)
+	}
+
+	if d.Validation.Pattern != nil && s != "" {
+		re, err := regexp.Compile(*d.Validation.Pattern)
+		if err != nil {
+			return fmt.Errorf("无效的正则表达式: %w", err)
+		}
+		if !re.MatchString(s) {
+			return errors.New("格式不匹配")
+		}
+	}
+
+	return nil
+}
+
+// hasOption 检查值是否在可选值中
+func (d *CustomFieldDefinition) hasOption(value string) bool {
+	for _, option := range d.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCustomFields 按字段定义校验自定义字段
+// 未定义任何字段时不做约束，以兼容存量数据
+func ValidateCustomFields(definitions []*CustomFieldDefinition, values map[string]interface{}) error {
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	defined := make(map[string]*CustomFieldDefinition, len(definitions))
+	for _, def := range definitions {
+		if !def.Enabled {
+			continue
+		}
+		defined[def.Key] = def
+	}
+
+	for key := range values {
+		if _, ok := defined[key]; !ok {
+			return fmt.Errorf("%w: 未定义的自定义字段 %s", ErrInvalidInput, key)
+		}
+	}
+
+	for key, def := range defined {
+		value, ok := values[key]
+		if !ok && !def.Required {
+			continue
+		}
+		if err := def.ValidateValue(value); err != nil {
+			return fmt.Errorf("%w: 自定义字段 %s %s", ErrInvalidInput, def.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ApplyCustomFieldDefaults 为未填写的字段填充默认值
+func ApplyCustomFieldDefaults(definitions []*CustomFieldDefinition, values map[string]interface{}) map[string]interface{} {
+	for _, def := range definitions {
+		if !def.Enabled || def.DefaultValue == nil {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]interface{})
+		}
+		if _, ok := values[def.Key]; !ok {
+			values[def.Key] = def.DefaultValue
+		}
+	}
+	return values
+}
+
+// Validate 验证创建自定义字段定义请求
+func (req *CustomFieldDefinitionCreateRequest) Validate() error {
+	def := req.ToDefinition()
+	return def.Validate()
+}
+
+// ToDefinition 将创建请求转换为字段定义
+func (req *CustomFieldDefinitionCreateRequest) ToDefinition() *CustomFieldDefinition {
+	def := &CustomFieldDefinition{
+		Resource:     req.Resource,
+		Key:          req.Key,
+		Name:         req.Name,
+		Description:  req.Description,
+		Type:         req.Type,
+		Options:      req.Options,
+		DefaultValue: req.DefaultValue,
+		Required:     req.Required,
+		Enabled:      true,
+		SortOrder:    req.SortOrder,
+	}
+	if req.Validation != nil {
+		def.Validation = *req.Validation
+	}
+	if req.Enabled != nil {
+		def.Enabled = *req.Enabled
+	}
+	return def
+}
+
+// ApplyTo 将更新请求应用到字段定义
+func (req *CustomFieldDefinitionUpdateRequest) ApplyTo(def *CustomFieldDefinition) {
+	if req.Name != nil {
+		def.Name = *req.Name
+	}
+	if req.Description != nil {
+		def.Description = req.Description
+	}
+	if req.Options != nil {
+		def.Options = *req.Options
+	}
+	if req.Validation != nil {
+		def.Validation = *req.Validation
+	}
+	if req.DefaultValue != nil {
+		def.DefaultValue = req.DefaultValue
+	}
+	if req.Required != nil {
+		def.Required = *req.Required
+	}
+	if req.Enabled != nil {
+		def.Enabled = *req.Enabled
+	}
+	if req.SortOrder != nil {
+		def.SortOrder = *req.SortOrder
+	}
+}
+
+// GetDisplayName 获取显示名称
+func (t CustomFieldType) GetDisplayName() string {
+	switch t {
+	case CustomFieldTypeText:
+		return "单行文本"
+	case CustomFieldTypeTextarea:
+		return "多行文本"
+	case CustomFieldTypeNumber:
+		return "数字"
+	case CustomFieldTypeBoolean:
+		return "布尔"
+	case CustomFieldTypeSelect:
+		return "单选"
+	case CustomFieldTypeMultiSelect:
+		return "多选"
+	case CustomFieldTypeDate:
+		return "日期"
+	case CustomFieldTypeURL:
+		return "链接"
+	default:
+		return string(t)
+	}
+}
+
+// toFloat64 将JSON数值转换为float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toStringSlice 将JSON数组转换为字符串切片
+func toStringSlice(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// DashboardSummary 首页仪表盘汇总数据，一次请求返回首页所需的全部统计
+type DashboardSummary struct {
+	ActiveAlerts    DashboardAlertSummary  `json:"active_alerts"`
+	OpenTickets     DashboardTicketSummary `json:"open_tickets"`
+	SLA             DashboardSLASummary    `json:"sla"`
+	RecentIncidents []*DashboardIncident   `json:"recent_incidents"`
+	TopRules        []*DashboardRuleStat   `json:"top_rules"`
+	GeneratedAt     time.Time              `json:"generated_at"`
+}
+
+// DashboardAlertSummary 活跃告警（触发中和已确认）统计
+type DashboardAlertSummary struct {
+	Total      int64                   `json:"total"`
+	BySeverity map[AlertSeverity]int64 `json:"by_severity"`
+}
+
+// DashboardTicketSummary 未关闭工单统计
+type DashboardTicketSummary struct {
+	Total      int64                    `json:"total"`
+	ByPriority map[TicketPriority]int64 `json:"by_priority"`
+}
+
+// DashboardSLASummary 未关闭工单的SLA风险统计
+type DashboardSLASummary struct {
+	// AtRisk 距SLA截止时间不足风险窗口的工单数
+	AtRisk int64 `json:"at_risk"`
+
+	// Breached 已超过SLA截止时间的工单数
+	Breached int64 `json:"breached"`
+}
+
+// DashboardIncident 最近的事件类工单
+type DashboardIncident struct {
+	ID          string         `json:"id" db:"id"`
+	Number      string         `json:"number" db:"number"`
+	Title       string         `json:"title" db:"title"`
+	Status      TicketStatus   `json:"status" db:"status"`
+	Priority    TicketPriority `json:"priority" db:"priority"`
+	AssigneeID  *string        `json:"assignee_id,omitempty" db:"assignee_id"`
+	SLADeadline *time.Time     `json:"sla_deadline,omitempty" db:"sla_deadline"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// DashboardRuleStat 统计窗口内产生告警最多的规则
+type DashboardRuleStat struct {
+	RuleID     string        `json:"rule_id" db:"rule_id"`
+	Name       string        `json:"name" db:"name"`
+	Severity   AlertSeverity `json:"severity" db:"severity"`
+	AlertCount int64         `json:"alert_count" db:"alert_count"`
+}
@@ -6,6 +6,10 @@ import (
 	"strings"
 	"encoding/json"
 	"net/url"
+
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/redact"
 )
 
 // DataSourceType 数据源类型
@@ -21,6 +25,7 @@ const (
 	DataSourceTypeKafka      DataSourceType = "kafka"      // Kafka
 	DataSourceTypeGrafana    DataSourceType = "grafana"    // Grafana
 	DataSourceTypeZabbix     DataSourceType = "zabbix"     // Zabbix
+	DataSourceTypeKubernetes DataSourceType = "kubernetes" // Kubernetes
 	DataSourceTypeCustom     DataSourceType = "custom"     // 自定义
 )
 
@@ -51,6 +56,21 @@ type DataSourceConfig struct {
 	Measurement      *string           `json:"measurement,omitempty"`
 	Index            *string           `json:"index,omitempty"`
 	Topic            *string           `json:"topic,omitempty"`
+	// RetryPolicy HTTP 类数据源的超时和重试策略，未配置的字段沿用 HTTP_CLIENT_* 默认策略
+	RetryPolicy      *httpclient.Policy `json:"retry_policy,omitempty"`
+	// 出站连接选项：代理地址（加密存储）、直连和额外信任的 CA 证书，未配置时沿用 HTTP_CLIENT_* 全局选项
+	ProxyURL           *string         `json:"proxy_url,omitempty"`
+	DisableProxy       bool            `json:"disable_proxy,omitempty"`
+	CACert             *string         `json:"ca_cert,omitempty"`
+	InsecureSkipVerify bool            `json:"insecure_skip_verify,omitempty"`
+	// 双向 TLS 认证的 PEM 格式客户端证书和私钥（加密存储），用于 Prometheus、Elasticsearch、Kafka 等要求客户端证书的端点
+	ClientCert         *string         `json:"client_cert,omitempty"`
+	ClientKey          *string         `json:"client_key,omitempty"`
+	// Kubernetes 连接配置：优先使用 kubeconfig，其次为集群内服务账号，否则使用 URL 和 Token
+	Kubeconfig       *string           `json:"kubeconfig,omitempty"`
+	KubeContext      *string           `json:"kube_context,omitempty"`
+	InCluster        bool              `json:"in_cluster,omitempty"`
+	Namespaces       []string          `json:"namespaces,omitempty"` // 为空时采集全部命名空间
 }
 
 // DataSource 数据源模型
@@ -73,6 +93,8 @@ type DataSource struct {
 	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
 	DeletedAt       *time.Time        `json:"deleted_at,omitempty" db:"deleted_at"`
+	// CircuitBreaker 健康检查和查询的熔断状态，只在数据源详情中返回
+	CircuitBreaker  *breaker.Status   `json:"circuit_breaker,omitempty" db:"-"`
 }
 
 // DataSourceHealthStatus 数据源健康状态
@@ -243,7 +265,7 @@ func (t DataSourceType) IsValid() bool {
 	case DataSourceTypePrometheus, DataSourceTypeInfluxDB, DataSourceTypeElastic,
 		 DataSourceTypeMySQL, DataSourceTypePostgreSQL, DataSourceTypeRedis,
 		 DataSourceTypeKafka, DataSourceTypeGrafana, DataSourceTypeZabbix,
-		 DataSourceTypeCustom:
+		 DataSourceTypeKubernetes, DataSourceTypeCustom:
 		return true
 	default:
 		return false
@@ -264,6 +286,10 @@ func (s DataSourceStatus) IsValid() bool {
 // Validate 验证数据源配置
 func (c *DataSourceConfig) Validate() error {
 	if strings.TrimSpace(c.URL) == "" {
+		// Kubernetes 数据源的 API Server 地址可以来自 kubeconfig 或集群内环境变量
+		if c.InCluster || (c.Kubeconfig != nil && strings.TrimSpace(*c.Kubeconfig) != "") {
+			return c.validateOptions()
+		}
 		return errors.New("数据源URL不能为空")
 	}
 	
@@ -272,11 +298,126 @@ func (c *DataSourceConfig) Validate() error {
 		return errors.New("无效的URL格式: " + err.Error())
 	}
 	
+	return c.validateOptions()
+}
+
+// HTTPPolicy 返回数据源的超时和重试策略，策略未配置超时时间时使用 Timeout
+func (c *DataSourceConfig) HTTPPolicy() httpclient.Policy {
+	var policy httpclient.Policy
+	if c.RetryPolicy != nil {
+		policy = *c.RetryPolicy
+	}
+	if policy.Timeout == 0 && c.Timeout != nil {
+		policy.Timeout = *c.Timeout
+	}
+	return policy
+}
+
+// TransportOptions 返回数据源的出站连接选项
+func (c *DataSourceConfig) TransportOptions() httpclient.Options {
+	options := httpclient.Options{
+		DisableProxy:       c.DisableProxy,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.ProxyURL != nil {
+		options.ProxyURL = strings.TrimSpace(*c.ProxyURL)
+	}
+	if c.CACert != nil {
+		options.CACert = strings.TrimSpace(*c.CACert)
+	}
+	if c.ClientCert != nil {
+		options.ClientCert = strings.TrimSpace(*c.ClientCert)
+	}
+	if c.ClientKey != nil {
+		options.ClientKey = strings.TrimSpace(*c.ClientKey)
+	}
+	return options
+}
+
+// Redacted 返回用于 API 响应的副本，密码、Token、kubeconfig 和客户端私钥替换为 redact.Mask，
+// URL 和代理地址中的密码、敏感请求头和参数同样隐藏，错误信息按已知格式脱敏
+func (d *DataSource) Redacted() *DataSource {
+	if d == nil {
+		return nil
+	}
+	redacted := *d
+	redacted.Config = d.Config.Redacted()
+	if d.ErrorMessage != nil {
+		message := redact.String(*d.ErrorMessage)
+		redacted.ErrorMessage = &message
+	}
+	return &redacted
+}
+
+// Redacted 返回敏感信息已隐藏的配置副本
+func (c DataSourceConfig) Redacted() DataSourceConfig {
+	c.URL = redact.URL(c.URL)
+	c.Password = redact.Value(c.Password)
+	c.Token = redact.Value(c.Token)
+	c.Kubeconfig = redact.Value(c.Kubeconfig)
+	c.ClientKey = redact.Value(c.ClientKey)
+	if c.ProxyURL != nil {
+		proxyURL := redact.URL(*c.ProxyURL)
+		c.ProxyURL = &proxyURL
+	}
+	c.Headers = redact.Headers(c.Headers)
+	c.Parameters = redact.Parameters(c.Parameters)
+	return c
+}
+
+// HasRedacted 判断配置中是否包含未修改的脱敏值
+func (c *DataSourceConfig) HasRedacted() bool {
+	values := []string{c.URL}
+	for _, value := range []*string{c.Password, c.Token, c.Kubeconfig, c.ClientKey, c.ProxyURL} {
+		if value != nil {
+			values = append(values, *value)
+		}
+	}
+	for _, value := range c.Headers {
+		values = append(values, value)
+	}
+	for _, value := range c.Parameters {
+		if s, ok := value.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return redact.Masked(values...)
+}
+
+// RestoreRedacted 将更新请求中未修改的脱敏值（redact.Mask 或脱敏后的 URL）还原为已保存的值
+func (c *DataSourceConfig) RestoreRedacted(stored *DataSourceConfig) {
+	c.URL = redact.RestoreURL(c.URL, stored.URL)
+	c.Password = redact.Restore(c.Password, stored.Password)
+	c.Token = redact.Restore(c.Token, stored.Token)
+	c.Kubeconfig = redact.Restore(c.Kubeconfig, stored.Kubeconfig)
+	c.ClientKey = redact.Restore(c.ClientKey, stored.ClientKey)
+	if c.ProxyURL != nil && stored.ProxyURL != nil {
+		proxyURL := redact.RestoreURL(*c.ProxyURL, *stored.ProxyURL)
+		c.ProxyURL = &proxyURL
+	}
+	redact.RestoreHeaders(c.Headers, stored.Headers)
+	redact.RestoreParameters(c.Parameters, stored.Parameters)
+}
+
+// validateOptions 验证URL以外的配置项
+func (c *DataSourceConfig) validateOptions() error {
 	// 验证超时时间
 	if c.Timeout != nil && *c.Timeout <= 0 {
 		return errors.New("超时时间必须大于0")
 	}
 	
+	// 验证重试策略
+	if c.RetryPolicy != nil {
+		if err := c.RetryPolicy.Validate(); err != nil {
+			return errors.New("无效的重试策略: " + err.Error())
+		}
+	}
+
+	// 验证代理、CA证书和客户端证书
+	if err := c.TransportOptions().Validate(); err != nil {
+		return errors.New("无效的连接选项: " + err.Error())
+	}
+	
 	// 验证最大连接数
 	if c.MaxConnections != nil && *c.MaxConnections <= 0 {
 		return errors.New("最大连接数必须大于0")
@@ -438,6 +579,8 @@ func (t DataSourceType) GetDisplayName() string {
 		return "Grafana"
 	case DataSourceTypeZabbix:
 		return "Zabbix"
+	case DataSourceTypeKubernetes:
+		return "Kubernetes"
 	case DataSourceTypeCustom:
 		return "自定义"
 	default:
@@ -466,6 +609,8 @@ func (t DataSourceType) GetDefaultPort() int {
 		return 3000
 	case DataSourceTypeZabbix:
 		return 10051
+	case DataSourceTypeKubernetes:
+		return 6443
 	default:
 		return 80
 	}
@@ -0,0 +1,35 @@
+package models
+
+// KeyRotationResult 加密字段密钥轮换结果
+type KeyRotationResult struct {
+	ActiveVersion string   `json:"active_version"`
+	Scanned       int      `json:"scanned"`
+	Rotated       int      `json:"rotated"`
+	Skipped       int      `json:"skipped"` // 轮换期间被并发修改的记录，下次执行时重试
+	Failed        int      `json:"failed"`
+	FailedIDs     []string `json:"failed_ids,omitempty"`
+}
+
+// KeyRotationReport 各类加密数据的密钥轮换结果
+type KeyRotationReport struct {
+	ActiveVersion       string             `json:"active_version"`
+	DataSources         *KeyRotationResult `json:"data_sources"`
+	WebhookIntegrations *KeyRotationResult `json:"webhook_integrations"`
+	Webhooks            *KeyRotationResult `json:"webhooks"`
+	Users               *KeyRotationResult `json:"users"`
+	// WebhookSubscriptions 出站 Webhook 订阅密钥
+	WebhookSubscriptions *KeyRotationResult `json:"webhook_subscriptions"`
+	// NotificationChannels 团队通知渠道的地址和密钥
+	NotificationChannels *KeyRotationResult `json:"notification_channels"`
+}
+
+// Failed 轮换失败的记录总数
+func (r *KeyRotationReport) Failed() int {
+	failed := 0
+	for _, result := range []*KeyRotationResult{r.DataSources, r.WebhookIntegrations, r.Webhooks, r.Users, r.WebhookSubscriptions, r.NotificationChannels} {
+		if result != nil {
+			failed += result.Failed
+		}
+	}
+	return failed
+}
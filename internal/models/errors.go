@@ -2,50 +2,253 @@ package models
 
 import "errors"
 
+// 错误分类，仓储层和服务层返回的具体错误都归属于其中一类，
+// 调用方可以通过 errors.Is(err, ErrNotFound) 等判断错误类型而不必比较错误文本
+var (
+	ErrNotFound           = errors.New("资源不存在")
+	ErrConflict           = errors.New("资源冲突")
+	ErrPreconditionFailed = errors.New("前置条件不满足")
+)
+
+// kindError 归属于某一错误分类的具体错误
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+// Unwrap 返回错误所属分类，使 errors.Is 在匹配错误本身之外也能匹配分类
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// NewNotFoundError 创建归类为 ErrNotFound 的错误
+func NewNotFoundError(message string) error {
+	return &kindError{kind: ErrNotFound, message: message}
+}
+
+// NewConflictError 创建归类为 ErrConflict 的错误
+func NewConflictError(message string) error {
+	return &kindError{kind: ErrConflict, message: message}
+}
+
+// NewPreconditionFailedError 创建归类为 ErrPreconditionFailed 的错误
+func NewPreconditionFailedError(message string) error {
+	return &kindError{kind: ErrPreconditionFailed, message: message}
+}
+
 // 通用错误定义
 var (
 	// 用户相关错误
-	ErrUserNotFound     = errors.New("用户不存在")
-	ErrUserExists       = errors.New("用户已存在")
-	ErrInvalidPassword  = errors.New("密码无效")
-	ErrUserDisabled     = errors.New("用户已禁用")
+	ErrUserNotFound       = NewNotFoundError("用户不存在")
+	ErrUserExists         = NewConflictError("用户已存在")
+	ErrInvalidPassword    = errors.New("密码无效")
+	ErrUserDisabled       = NewPreconditionFailedError("用户已禁用")
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+
+	// 会话和令牌相关错误
+	ErrSessionNotFound      = NewNotFoundError("会话不存在")
+	ErrRefreshTokenNotFound = NewNotFoundError("刷新令牌不存在")
 
 	// 数据源相关错误
-	ErrDataSourceNotFound = errors.New("数据源不存在")
-	ErrDataSourceExists   = errors.New("数据源已存在")
+	ErrDataSourceNotFound = NewNotFoundError("数据源不存在")
+	ErrDataSourceExists   = NewConflictError("数据源已存在")
 	ErrDataSourceOffline  = errors.New("数据源离线")
 
 	// 规则相关错误
-	ErrRuleNotFound     = errors.New("规则不存在")
-	ErrRuleExists       = errors.New("规则已存在")
-	ErrRuleDisabled     = errors.New("规则已禁用")
-	ErrRuleEvalFailed   = errors.New("规则评估失败")
+	ErrRuleNotFound   = NewNotFoundError("规则不存在")
+	ErrRuleExists     = NewConflictError("规则已存在")
+	ErrRuleDisabled   = NewPreconditionFailedError("规则已禁用")
+	ErrRuleEvalFailed = errors.New("规则评估失败")
 
 	// 告警相关错误
-	ErrAlertNotFound    = errors.New("告警不存在")
-	ErrAlertExists      = errors.New("告警已存在")
-	ErrAlertResolved    = errors.New("告警已解决")
+	ErrAlertNotFound        = NewNotFoundError("告警不存在")
+	ErrAlertExists          = NewConflictError("告警已存在")
+	ErrAlertResolved        = NewPreconditionFailedError("告警已解决")
+	ErrAlertNotFiring       = NewPreconditionFailedError("只能确认正在触发的告警")
+	ErrAlertSilenceNotFound = NewNotFoundError("静默规则不存在")
 
 	// 工单相关错误
-	ErrTicketNotFound   = errors.New("工单不存在")
-	ErrTicketExists     = errors.New("工单已存在")
-	ErrTicketClosed     = errors.New("工单已关闭")
+	ErrTicketNotFound     = NewNotFoundError("工单不存在")
+	ErrTicketExists       = NewConflictError("工单已存在")
+	ErrTicketClosed       = NewPreconditionFailedError("工单已关闭")
+	ErrTicketSLANotFound  = NewNotFoundError("工单SLA不存在")
+	ErrTicketSLAPaused    = NewConflictError("工单SLA计时已暂停")
+	ErrTicketSLANotPaused = NewConflictError("工单SLA计时未暂停")
+	ErrWorklogNotFound    = NewNotFoundError("工作日志不存在")
+	ErrAttachmentNotFound = NewNotFoundError("附件不存在")
+	ErrAttachmentNotClean = NewPreconditionFailedError("附件未通过安全扫描，禁止下载")
+
+	// 工单导入相关错误
+	ErrTicketImportJobNotFound = NewNotFoundError("工单导入任务不存在")
+	ErrTicketImportJobStarted  = NewConflictError("工单导入任务已开始，不能再修改列映射")
+
+	// 工单满意度调查相关错误
+	ErrTicketSurveyNotFound  = NewNotFoundError("满意度调查不存在")
+	ErrTicketSurveyResponded = NewConflictError("已提交过评价")
+	ErrTicketSurveyExpired   = NewPreconditionFailedError("评价链接已过期")
+
+	// 审批请求相关错误
+	ErrApprovalNotFound   = NewNotFoundError("审批请求不存在")
+	ErrApprovalNotPending = NewPreconditionFailedError("审批请求已处理")
+	ErrApprovalExpired    = NewPreconditionFailedError("审批请求已过期")
+	ErrApprovalRequired   = NewPreconditionFailedError("该操作需要审批，请提交审批请求")
+
+	// 异步任务相关错误
+	ErrAsyncTaskNotFound       = NewNotFoundError("异步任务不存在")
+	ErrAsyncTaskResultNotReady = NewPreconditionFailedError("异步任务尚未完成或没有结果文件")
+
+	// 工单引用知识库文章相关错误
+	ErrTicketKnowledgeLinkNotFound  = NewNotFoundError("工单未引用该知识库文章")
+	ErrTicketKnowledgeLinkExists    = NewConflictError("工单已引用该知识库文章")
+	ErrKnowledgeFeedbackNotPrompted = NewPreconditionFailedError("工单解决后才能评价引用的文章")
 
 	// 知识库相关错误
-	ErrKnowledgeNotFound = errors.New("知识库文章不存在")
-	ErrKnowledgeExists   = errors.New("知识库文章已存在")
-	ErrVersionNotFound   = errors.New("版本不存在")
+	ErrKnowledgeNotFound             = NewNotFoundError("知识库文章不存在")
+	ErrKnowledgeExists               = NewConflictError("知识库文章已存在")
+	ErrVersionNotFound               = NewNotFoundError("版本不存在")
+	ErrKnowledgeInvalidStatus        = NewPreconditionFailedError("知识库文章状态不允许该操作")
+	ErrKnowledgeReviewNotFound       = NewNotFoundError("知识库审核请求不存在")
+	ErrKnowledgeReviewDecided        = NewConflictError("已审核过该知识库文章")
+	ErrKnowledgeReviewPolicyNotFound = NewNotFoundError("知识库审核策略不存在")
+	ErrKnowledgeReviewPolicyExists   = NewConflictError("该分类已存在审核策略")
+	ErrCommentNotFound               = NewNotFoundError("评论不存在")
+	ErrKnowledgeCategoryNotFound     = NewNotFoundError("知识分类不存在")
+
+	// 自定义字段相关错误
+	ErrCustomFieldNotFound = NewNotFoundError("自定义字段不存在")
+	ErrCustomFieldExists   = NewConflictError("自定义字段已存在")
+
+	// 通知相关错误
+	ErrNotificationTemplateNotFound = NewNotFoundError("通知模板不存在")
+	ErrNotificationNotFound         = NewNotFoundError("通知不存在")
+
+	// 告警接入相关错误
+	ErrWebhookIntegrationNotFound = NewNotFoundError("告警接入集成不存在")
+	ErrWebhookIntegrationDisabled = NewPreconditionFailedError("告警接入集成已禁用")
+	ErrInvalidSignature           = errors.New("请求签名无效")
+	ErrSourceIPNotAllowed         = errors.New("来源IP不允许访问")
+
+	// 功能开关相关错误
+	ErrFeatureFlagNotFound = NewNotFoundError("功能开关不存在")
+	ErrFeatureFlagExists   = NewConflictError("功能开关已存在")
+
+	// 事后复盘相关错误
+	ErrPostmortemNotFound           = NewNotFoundError("复盘不存在")
+	ErrPostmortemPublished          = NewPreconditionFailedError("复盘已发布，不能修改")
+	ErrPostmortemActionItemNotFound = NewNotFoundError("复盘改进项不存在")
+
+	// 自动修复相关错误
+	ErrRemediationNotFound   = NewNotFoundError("修复动作执行记录不存在")
+	ErrRemediationNotPending = NewPreconditionFailedError("修复动作不在等待审批状态")
+
+	// ChatOps 相关错误
+	ErrChatUserMappingNotFound = NewNotFoundError("聊天用户未绑定平台用户")
+	ErrChatUserMappingExists   = NewConflictError("聊天用户已绑定平台用户")
+
+	// ITSM 同步相关错误
+	ErrITSMConnectorNotFound  = NewNotFoundError("ITSM连接器不存在")
+	ErrITSMConnectorDisabled  = NewPreconditionFailedError("ITSM连接器已禁用")
+	ErrITSMTicketLinkNotFound = NewNotFoundError("工单未关联外部工单")
+
+	// 心跳监控相关错误
+	ErrHeartbeatNotFound = NewNotFoundError("心跳检查不存在")
+	ErrHeartbeatExists   = NewConflictError("心跳检查名称已存在")
+
+	// 服务目录相关错误
+	ErrCatalogServiceNotFound = NewNotFoundError("服务不存在")
+	ErrCatalogServiceExists   = NewConflictError("服务名称已存在")
+	ErrCatalogServiceInUse    = NewPreconditionFailedError("服务正在使用中")
+
+	// SLO 相关错误
+	ErrSLONotFound = NewNotFoundError("SLO不存在")
+	ErrSLOExists   = NewConflictError("SLO名称已存在")
+
+	// 告警暂停相关错误
+	ErrAlertSnoozeNotFound = NewNotFoundError("告警暂停不存在")
+
+	// 告警操作链接相关错误
+	ErrActionLinkUsed     = NewConflictError("操作链接已使用")
+	ErrActionLinkDisabled = NewPreconditionFailedError("未启用告警操作链接")
+
+	// 移动端推送相关错误
+	ErrPushDeviceNotFound     = NewNotFoundError("推送设备不存在")
+	ErrPushPreferenceNotFound = NewNotFoundError("推送偏好未设置")
+	ErrPushPlatformDisabled   = NewPreconditionFailedError("未配置该平台的推送服务")
+	ErrNoPushDevice           = NewPreconditionFailedError("接收人没有可用的推送设备")
+
+	// 变更事件相关错误
+	ErrChangeEventNotFound = NewNotFoundError("变更事件不存在")
+
+	// 免打扰相关错误
+	ErrQuietHoursNotFound = NewNotFoundError("免打扰设置不存在")
+
+	// 出站 Webhook 订阅相关错误
+	ErrWebhookSubscriptionNotFound = NewNotFoundError("Webhook订阅不存在")
+	ErrWebhookDeliveryNotFound     = NewNotFoundError("Webhook投递记录不存在")
+
+	// 通知日历相关错误
+	ErrNotificationCalendarNotFound = NewNotFoundError("通知日历不存在")
+	ErrNotificationCalendarExists   = NewConflictError("相同团队和地区的通知日历已存在")
+
+	// 团队通知渠道相关错误
+	ErrNotificationChannelNotFound = NewNotFoundError("通知渠道不存在")
+	ErrNotificationChannelExists   = NewConflictError("团队中已存在同名的通知渠道")
+
+	// 告警重新分级规则相关错误
+	ErrAlertSeverityRuleNotFound = NewNotFoundError("告警重新分级规则不存在")
+	ErrAlertSeverityRuleExists   = NewConflictError("同名的告警重新分级规则已存在")
+
+	// 状态页相关错误
+	ErrStatusIncidentNotFound   = NewNotFoundError("状态页事件不存在")
+	ErrStatusSubscriberNotFound = NewNotFoundError("订阅不存在")
+	ErrStatusSubscribeDisabled  = NewPreconditionFailedError("未启用状态页订阅")
+
+	// 内置指标存储相关错误
+	ErrMetricNoData = NewNotFoundError("查询结果为空，指标尚未上报或标签匹配条件不匹配")
+
+	// 语言偏好相关错误
+	ErrLanguagePreferenceNotFound = NewNotFoundError("语言偏好未设置")
+
+	// 标签治理相关错误
+	ErrTagJobNotFound = NewNotFoundError("标签治理任务不存在")
+	ErrTagInUse       = NewConflictError("目标标签已被使用，请使用合并")
+
+	// 分派策略相关错误
+	ErrAssignmentPolicyNotFound = NewNotFoundError("分派策略不存在")
+	ErrAssignmentPolicyExists   = NewConflictError("团队的分派策略已存在")
+	ErrNoAssignee               = NewPreconditionFailedError("团队没有可分派的处理人")
+
+	// 交班报告相关错误
+	ErrHandoverScheduleNotFound = NewNotFoundError("交接排班不存在")
+	ErrHandoverScheduleExists   = NewConflictError("团队的交接排班已存在")
+	ErrHandoverReportNotFound   = NewNotFoundError("交班报告不存在")
+	ErrHandoverReportExists     = NewConflictError("该交接时刻的交班报告已生成")
+
+	// 声明式配置相关错误
+	ErrConfigBundleInvalid = &kindError{kind: ErrInvalidInput, message: "声明式配置校验失败"}
+
+	// 配置备份相关错误
+	ErrBackupInvalid          = &kindError{kind: ErrInvalidInput, message: "备份包无效"}
+	ErrBackupSignatureInvalid = &kindError{kind: ErrInvalidInput, message: "备份包签名校验失败，口令错误或备份包已被篡改"}
+	ErrBackupIncompatible     = NewPreconditionFailedError("备份包版本与当前实例不兼容")
+	ErrBackupTargetNotEmpty   = NewConflictError("当前实例已有规则、数据源或通知路由，备份只能恢复到全新实例")
 
 	// 权限相关错误
-	ErrPermissionDenied  = errors.New("权限不足")
-	ErrInvalidToken      = errors.New("无效的令牌")
-	ErrTokenExpired      = errors.New("令牌已过期")
+	ErrPermissionDenied           = errors.New("权限不足")
+	ErrInvalidToken               = errors.New("无效的令牌")
+	ErrTokenExpired               = errors.New("令牌已过期")
+	ErrPermissionGroupNotFound    = NewNotFoundError("权限组不存在")
+	ErrPermissionOverrideNotFound = NewNotFoundError("权限覆盖不存在")
 
 	// 通用错误
-	ErrInvalidInput      = errors.New("输入参数无效")
-	ErrInternalError     = errors.New("内部服务器错误")
-	ErrDatabaseError     = errors.New("数据库错误")
-	ErrNetworkError      = errors.New("网络错误")
-	ErrTimeout           = errors.New("操作超时")
-	ErrNotImplemented    = errors.New("功能未实现")
-)
\ No newline at end of file
+	ErrInvalidInput   = errors.New("输入参数无效")
+	ErrInternalError  = errors.New("内部服务器错误")
+	ErrDatabaseError  = errors.New("数据库错误")
+	ErrNetworkError   = errors.New("网络错误")
+	ErrTimeout        = errors.New("操作超时")
+	ErrNotImplemented = errors.New("功能未实现")
+)
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HandoverMaxShifts 每天最多的班次交接时刻数
+const HandoverMaxShifts = 24
+
+// HandoverSchedule 团队的交接排班，到达每天的交接时刻时生成上一班次的交班报告
+type HandoverSchedule struct {
+	ID       string `json:"id" db:"id"`
+	Team     string `json:"team" db:"team"`
+	Timezone string `json:"timezone" db:"timezone"` // IANA 时区名，为空时使用 UTC
+	// ShiftStarts 每天的交接时刻，格式为 HH:MM
+	ShiftStarts []string `json:"shift_starts" db:"shift_starts"`
+	// ChannelType 和 Recipient 为团队频道，ChannelType 为空时只保存知识库草稿
+	ChannelType    *NotificationType `json:"channel_type,omitempty" db:"channel_type"`
+	Recipient      string            `json:"recipient" db:"recipient"`
+	Enabled        bool              `json:"enabled" db:"enabled"`
+	LastHandoverAt *time.Time        `json:"last_handover_at,omitempty" db:"last_handover_at"`
+	CreatedBy      string            `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// location 排班所在时区
+func (s *HandoverSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// LastBoundary 返回不晚于 now 的最近一个交接时刻，未配置交接时刻或时区无效时 ok 为 false
+func (s *HandoverSchedule) LastBoundary(now time.Time) (boundary time.Time, ok bool) {
+	loc, err := s.location()
+	if err != nil {
+		return boundary, false
+	}
+	local := now.In(loc)
+	for i := 0; i <= 1; i++ {
+		year, month, day := local.AddDate(0, 0, -i).Date()
+		for _, start := range s.ShiftStarts {
+			clock, err := time.Parse(businessClockLayout, start)
+			if err != nil {
+				continue
+			}
+			candidate := time.Date(year, month, day, clock.Hour(), clock.Minute(), 0, 0, loc)
+			if !candidate.After(local) && (!ok || candidate.After(boundary)) {
+				boundary, ok = candidate, true
+			}
+		}
+		if ok {
+			return boundary, true
+		}
+	}
+	return boundary, false
+}
+
+// PreviousBoundary 返回 boundary 之前的上一个交接时刻，即以 boundary 结束的班次的开始时刻
+func (s *HandoverSchedule) PreviousBoundary(boundary time.Time) (time.Time, bool) {
+	return s.LastBoundary(boundary.Add(-time.Second))
+}
+
+// HandoverScheduleRequest 创建或更新交接排班请求
+type HandoverScheduleRequest struct {
+	Team        string            `json:"team"`
+	Timezone    string            `json:"timezone"`
+	ShiftStarts []string          `json:"shift_starts"`
+	ChannelType *NotificationType `json:"channel_type,omitempty"`
+	Recipient   string            `json:"recipient"`
+	Enabled     *bool             `json:"enabled,omitempty"`
+}
+
+// Validate 验证交接排班请求
+func (r *HandoverScheduleRequest) Validate() error {
+	if strings.TrimSpace(r.Team) == "" || len(r.Team) > 100 {
+		return fmt.Errorf("%w: 团队不能为空且不能超过100个字符", ErrInvalidInput)
+	}
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return fmt.Errorf("%w: 无效的时区 %q", ErrInvalidInput, r.Timezone)
+		}
+	}
+	if len(r.ShiftStarts) == 0 || len(r.ShiftStarts) > HandoverMaxShifts {
+		return fmt.Errorf("%w: 交接时刻数必须在1到%d之间", ErrInvalidInput, HandoverMaxShifts)
+	}
+	for _, start := range r.ShiftStarts {
+		if _, err := time.Parse(businessClockLayout, start); err != nil {
+			return fmt.Errorf("%w: 无效的交接时刻 %q，格式应为 HH:MM", ErrInvalidInput, start)
+		}
+	}
+	if r.ChannelType != nil {
+		switch *r.ChannelType {
+		case NotificationTypeEmail, NotificationTypeSMS, NotificationTypeDingTalk,
+			NotificationTypeWeChat, NotificationTypeSlack, NotificationTypeWebhook:
+		default:
+			return fmt.Errorf("%w: 无效的通知类型 %q", ErrInvalidInput, *r.ChannelType)
+		}
+		if strings.TrimSpace(r.Recipient) == "" || len(r.Recipient) > 500 {
+			return fmt.Errorf("%w: 团队频道不能为空且不能超过500个字符", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求应用到交接排班，交接时刻去重并排序，未提供启用状态时保持不变
+func (r *HandoverScheduleRequest) ApplyTo(s *HandoverSchedule) {
+	s.Team = strings.TrimSpace(r.Team)
+	s.Timezone = r.Timezone
+	s.ShiftStarts = normalizeFlagTargets(r.ShiftStarts)
+	sort.Strings(s.ShiftStarts)
+	s.ChannelType = r.ChannelType
+	s.Recipient = ""
+	if r.ChannelType != nil {
+		s.Recipient = strings.TrimSpace(r.Recipient)
+	}
+	if r.Enabled != nil {
+		s.Enabled = *r.Enabled
+	}
+}
+
+// HandoverScope 交班报告的统计范围。告警按 team 标签归属团队；工单和事件按团队负责服务的 service: 标签
+// 或团队成员（分派策略成员）为处理人归属团队；待处理事项包括成员负责的复盘改进项和团队告警待审批的修复动作
+type HandoverScope struct {
+	Team        string
+	ServiceTags []string
+	Members     []string
+	Since       time.Time
+	Limit       int
+}
+
+// HandoverAlert 交班报告中未恢复的严重告警
+type HandoverAlert struct {
+	ID       string        `json:"id" db:"id"`
+	Name     string        `json:"name" db:"name"`
+	Severity AlertSeverity `json:"severity" db:"severity"`
+	Status   AlertStatus   `json:"status" db:"status"`
+	Service  string        `json:"service,omitempty" db:"service"`
+	StartsAt time.Time     `json:"starts_at" db:"starts_at"`
+}
+
+// HandoverTicket 交班报告中的工单或事件
+type HandoverTicket struct {
+	ID          string         `json:"id" db:"id"`
+	Number      string         `json:"number" db:"number"`
+	Title       string         `json:"title" db:"title"`
+	Status      TicketStatus   `json:"status" db:"status"`
+	Priority    TicketPriority `json:"priority" db:"priority"`
+	AssigneeID  *string        `json:"assignee_id,omitempty" db:"assignee_id"`
+	SLADeadline *time.Time     `json:"sla_deadline,omitempty" db:"sla_deadline"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// 交班报告待处理事项类型
+const (
+	HandoverActionItem        = "action_item" // 复盘改进项
+	HandoverActionRemediation = "remediation" // 待审批的修复动作
+)
+
+// HandoverAction 交班报告中的待处理事项
+type HandoverAction struct {
+	Kind       string     `json:"kind" db:"kind"`
+	ID         string     `json:"id" db:"id"`
+	Title      string     `json:"title" db:"title"`
+	AssigneeID *string    `json:"assignee_id,omitempty" db:"assignee_id"`
+	DueDate    *time.Time `json:"due_date,omitempty" db:"due_date"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// HandoverSummary 交班报告内容
+type HandoverSummary struct {
+	CriticalAlerts    []*HandoverAlert  `json:"critical_alerts"`
+	InProgressTickets []*HandoverTicket `json:"in_progress_tickets"`
+	Incidents         []*HandoverTicket `json:"incidents"`
+	PendingActions    []*HandoverAction `json:"pending_actions"`
+	// CSAT 班次内发出的工单满意度调查统计，班次内没有发出调查时为空
+	CSAT *TicketCSAT `json:"csat,omitempty"`
+}
+
+// HandoverReport 交班报告
+type HandoverReport struct {
+	ID          string          `json:"id" db:"id"`
+	ScheduleID  *string         `json:"schedule_id,omitempty" db:"schedule_id"`
+	Team        string          `json:"team" db:"team"`
+	ShiftStart  time.Time       `json:"shift_start" db:"shift_start"`
+	ShiftEnd    time.Time       `json:"shift_end" db:"shift_end"`
+	Summary     HandoverSummary `json:"summary" db:"summary"`
+	KnowledgeID *string         `json:"knowledge_id,omitempty" db:"knowledge_id"`
+	Notified    bool            `json:"notified" db:"notified"`
+	CreatedBy   *string         `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// HandoverGenerateRequest 手动生成交班报告请求
+type HandoverGenerateRequest struct {
+	Team string `json:"team" binding:"required,max=100"`
+	// Since 班次开始时间，为空时从团队上一份报告的结束时间开始，没有报告时为 12 小时前
+	Since *time.Time `json:"since,omitempty"`
+	// Notify 是否发送到排班配置的团队频道
+	Notify bool `json:"notify"`
+}
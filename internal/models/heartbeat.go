@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// HeartbeatMinInterval 心跳检查允许的最短上报周期
+	HeartbeatMinInterval = time.Minute
+	// HeartbeatMaxInterval 心跳检查允许的最长上报周期
+	HeartbeatMaxInterval = 31 * 24 * time.Hour
+	// HeartbeatMaxGrace 心跳检查允许的最长宽限时间
+	HeartbeatMaxGrace = 7 * 24 * time.Hour
+)
+
+// HeartbeatStatus 心跳检查状态
+type HeartbeatStatus string
+
+const (
+	HeartbeatStatusNew    HeartbeatStatus = "new"    // 尚未收到上报
+	HeartbeatStatusUp     HeartbeatStatus = "up"     // 按时上报
+	HeartbeatStatusDown   HeartbeatStatus = "down"   // 超时未上报，已触发告警
+	HeartbeatStatusPaused HeartbeatStatus = "paused" // 已暂停，不检查超时
+)
+
+// IsValid 检查心跳检查状态是否有效
+func (s HeartbeatStatus) IsValid() bool {
+	switch s {
+	case HeartbeatStatusNew, HeartbeatStatusUp, HeartbeatStatusDown, HeartbeatStatusPaused:
+		return true
+	default:
+		return false
+	}
+}
+
+// Heartbeat 心跳检查（Dead Man's Switch），外部定时任务按周期上报，
+// 超过上报周期加宽限时间仍未上报时平台触发告警
+type Heartbeat struct {
+	ID              string            `json:"id" db:"id"`
+	Name            string            `json:"name" db:"name"`
+	Description     string            `json:"description" db:"description"`
+	Token           string            `json:"token" db:"token"`
+	IntervalSeconds int               `json:"interval_seconds" db:"interval_seconds"`
+	GraceSeconds    int               `json:"grace_seconds" db:"grace_seconds"`
+	Severity        AlertSeverity     `json:"severity" db:"severity"`
+	Labels          map[string]string `json:"labels" db:"labels"`
+	Status          HeartbeatStatus   `json:"status" db:"status"`
+	LastPingAt      *time.Time        `json:"last_ping_at,omitempty" db:"last_ping_at"`
+	ExpectedAt      time.Time         `json:"expected_at" db:"expected_at"`
+	PingCount       int64             `json:"ping_count" db:"ping_count"`
+	CreatedBy       string            `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Interval 上报周期
+func (h *Heartbeat) Interval() time.Duration {
+	return time.Duration(h.IntervalSeconds) * time.Second
+}
+
+// Grace 宽限时间
+func (h *Heartbeat) Grace() time.Duration {
+	return time.Duration(h.GraceSeconds) * time.Second
+}
+
+// NextExpectedAt 以 from 为最近一次上报时间计算的最晚上报时间
+func (h *Heartbeat) NextExpectedAt(from time.Time) time.Time {
+	return from.Add(h.Interval() + h.Grace())
+}
+
+// Paused 心跳检查是否已暂停
+func (h *Heartbeat) Paused() bool {
+	return h.Status == HeartbeatStatusPaused
+}
+
+// HeartbeatRequest 创建或更新心跳检查请求
+type HeartbeatRequest struct {
+	Name            string            `json:"name" binding:"required,max=200"`
+	Description     string            `json:"description,omitempty" binding:"max=1000"`
+	IntervalSeconds int               `json:"interval_seconds" binding:"required"`
+	GraceSeconds    int               `json:"grace_seconds"`
+	Severity        AlertSeverity     `json:"severity,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
+	Paused          bool              `json:"paused"`
+}
+
+// Validate 验证心跳检查请求
+func (r *HeartbeatRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: 心跳检查名称不能为空", ErrInvalidInput)
+	}
+	interval := time.Duration(r.IntervalSeconds) * time.Second
+	if interval < HeartbeatMinInterval || interval > HeartbeatMaxInterval {
+		return fmt.Errorf("%w: 上报周期必须在 %s 到 %s 之间", ErrInvalidInput, HeartbeatMinInterval, HeartbeatMaxInterval)
+	}
+	grace := time.Duration(r.GraceSeconds) * time.Second
+	if grace < 0 || grace > HeartbeatMaxGrace {
+		return fmt.Errorf("%w: 宽限时间必须在 0 到 %s 之间", ErrInvalidInput, HeartbeatMaxGrace)
+	}
+	if r.Severity != "" && !r.Severity.IsValid() {
+		return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, r.Severity)
+	}
+	return nil
+}
+
+// ApplyTo 将请求内容应用到心跳检查，未指定告警级别时使用 high
+func (r *HeartbeatRequest) ApplyTo(h *Heartbeat) {
+	h.Name = strings.TrimSpace(r.Name)
+	h.Description = r.Description
+	h.IntervalSeconds = r.IntervalSeconds
+	h.GraceSeconds = r.GraceSeconds
+	h.Severity = r.Severity
+	if h.Severity == "" {
+		h.Severity = AlertSeverityHigh
+	}
+	h.Labels = r.Labels
+	if h.Labels == nil {
+		h.Labels = map[string]string{}
+	}
+}
+
+// HeartbeatState 心跳检查的当前状态
+type HeartbeatState struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Status      HeartbeatStatus `json:"status"`
+	LastPingAt  *time.Time      `json:"last_ping_at,omitempty"`
+	ExpectedAt  time.Time       `json:"expected_at"`
+	LateSeconds int64           `json:"late_seconds"`
+}
+
+// HeartbeatStatusList 心跳检查状态列表，异常的检查排在前面
+type HeartbeatStatusList struct {
+	Items  []*HeartbeatState       `json:"items"`
+	Counts map[HeartbeatStatus]int `json:"counts"`
+}
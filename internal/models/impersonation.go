@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ImpersonationDefaultDuration 模拟登录令牌的默认有效期
+	ImpersonationDefaultDuration = 30 * time.Minute
+	// ImpersonationMaxDuration 模拟登录令牌的最长有效期
+	ImpersonationMaxDuration = time.Hour
+)
+
+// ImpersonationScope 模拟登录令牌的权限范围
+type ImpersonationScope string
+
+const (
+	ImpersonationScopeReadOnly  ImpersonationScope = "read_only"  // 只能执行读请求，默认范围
+	ImpersonationScopeReadWrite ImpersonationScope = "read_write" // 可以以用户身份执行修改操作
+)
+
+// 模拟登录的审计操作
+const (
+	AuditActionImpersonationStart   = "impersonation.start"
+	AuditActionImpersonationRequest = "impersonation.request"
+)
+
+// ImpersonationRequest 管理员模拟用户登录请求，用于复现用户反馈的权限和可见性问题
+type ImpersonationRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	// Reason 模拟登录的原因，如工单编号，记录在审计日志中
+	Reason          string             `json:"reason" binding:"required,max=500"`
+	DurationMinutes int                `json:"duration_minutes,omitempty"`
+	Scope           ImpersonationScope `json:"scope,omitempty"`
+}
+
+// Validate 验证模拟登录请求，未指定有效期和权限范围时使用默认值
+func (r *ImpersonationRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" {
+		return fmt.Errorf("%w: 模拟登录原因不能为空", ErrInvalidInput)
+	}
+	if r.DurationMinutes < 0 || time.Duration(r.DurationMinutes)*time.Minute > ImpersonationMaxDuration {
+		return fmt.Errorf("%w: 模拟登录有效期不能超过 %d 分钟", ErrInvalidInput, int(ImpersonationMaxDuration.Minutes()))
+	}
+	switch r.Scope {
+	case "":
+		r.Scope = ImpersonationScopeReadOnly
+	case ImpersonationScopeReadOnly, ImpersonationScopeReadWrite:
+	default:
+		return fmt.Errorf("%w: 无效的权限范围 %s", ErrInvalidInput, r.Scope)
+	}
+	return nil
+}
+
+// Duration 模拟登录令牌的有效期
+func (r *ImpersonationRequest) Duration() time.Duration {
+	if r.DurationMinutes == 0 {
+		return ImpersonationDefaultDuration
+	}
+	return time.Duration(r.DurationMinutes) * time.Minute
+}
+
+// ImpersonationSession 模拟登录会话，令牌以被模拟用户的身份和角色访问接口，
+// 请求和响应中带有执行模拟的管理员，会话期间的请求均记录审计日志
+type ImpersonationSession struct {
+	Token     string `json:"token,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	// User 被模拟的用户
+	User             *User              `json:"user"`
+	ImpersonatorID   string             `json:"impersonator_id"`
+	ImpersonatorName string             `json:"impersonator_name"`
+	Reason           string             `json:"reason"`
+	Scope            ImpersonationScope `json:"scope"`
+	ExpiresAt        time.Time          `json:"expires_at"`
+}
+
+// ImpersonatedRequest 模拟登录会话中的一次请求，用于审计
+type ImpersonatedRequest struct {
+	ImpersonatorID string
+	UserID         string
+	Method         string
+	Path           string
+	Status         int
+	IPAddress      string
+	RequestID      string
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// InboxKind 站内通知类型
+type InboxKind string
+
+const (
+	InboxKindAssigned         InboxKind = "assigned"          // 告警或工单分派给用户
+	InboxKindMentioned        InboxKind = "mentioned"         // 用户在评论中被 @ 提及
+	InboxKindSLABreach        InboxKind = "sla_breach"        // 用户处理的工单超出 SLA 截止时间
+	InboxKindFeedbackRequest  InboxKind = "feedback_request"  // 用户解决的工单引用了知识库文章，请用户评价文章是否有用
+	InboxKindKnowledgeFlagged InboxKind = "knowledge_flagged" // 用户的知识库文章被评价为需要更新
+)
+
+// 站内通知关联的资源类型
+const (
+	InboxResourceAlert     = "alert"
+	InboxResourceTicket    = "ticket"
+	InboxResourceKnowledge = "knowledge"
+)
+
+// InboxItem 用户站内通知收件箱中的一条通知，Seq 在全部通知中单调递增，用于实时推送的断点续传
+type InboxItem struct {
+	ID           string    `json:"id" db:"id"`
+	Seq          int64     `json:"seq" db:"seq"`
+	UserID       string    `json:"user_id" db:"user_id"`
+	Kind         InboxKind `json:"kind" db:"kind"`
+	Title        string    `json:"title" db:"title"`
+	Body         string    `json:"body" db:"body"`
+	ResourceType string    `json:"resource_type" db:"resource_type"`
+	ResourceID   string    `json:"resource_id" db:"resource_id"`
+	// ActorID 触发通知的用户，系统触发时为空
+	ActorID *string `json:"actor_id,omitempty" db:"actor_id"`
+	// DedupeKey 同一用户相同去重键的通知只投递一次，如同一工单的 SLA 超时
+	DedupeKey *string    `json:"-" db:"dedupe_key"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// MessageKey 多语言消息标识，投递时按接收人的语言渲染 <MessageKey>.title 作为标题，
+	// Body 为空时渲染 <MessageKey>.body 作为内容
+	MessageKey  string                 `json:"-" db:"-"`
+	MessageData map[string]interface{} `json:"-" db:"-"`
+}
+
+// InboxFilter 站内通知列表过滤条件，Before 为上一页最后一条通知的 Seq
+type InboxFilter struct {
+	UnreadOnly bool
+	Before     int64
+	Limit      int
+}
+
+// InboxList 站内通知列表及未读数
+type InboxList struct {
+	Items  []*InboxItem `json:"items"`
+	Unread int          `json:"unread"`
+}
+
+// InboxReadRequest 将站内通知标记为已读请求
+type InboxReadRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=500"`
+}
+
+// InboxSLABreach 超出 SLA 截止时间且尚未通知处理人的工单
+type InboxSLABreach struct {
+	TicketID    string    `db:"id"`
+	Number      string    `db:"number"`
+	Title       string    `db:"title"`
+	AssigneeID  string    `db:"assignee_id"`
+	SLADeadline time.Time `db:"sla_deadline"`
+}
+
+// SLABreachDedupeKey 返回工单 SLA 超时通知的去重键
+func SLABreachDedupeKey(ticketID string) string {
+	return string(InboxKindSLABreach) + ":" + ticketID
+}
+
+// mentionPattern 匹配 @用户名，@ 前不能是字母、数字或点，避免把邮箱地址当作提及
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+
+// ParseMentions 解析文本中 @ 提及的用户名，去重并保持出现顺序
+func ParseMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
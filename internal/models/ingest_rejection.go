@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// IngestSchemaMode 告警接入载荷的校验模式
+type IngestSchemaMode string
+
+const (
+	// IngestSchemaLenient 忽略未知字段，自定义 Webhook 中单条告警转换失败时其余告警照常处理
+	IngestSchemaLenient IngestSchemaMode = "lenient"
+	// IngestSchemaStrict 拒绝未知字段和多余内容，自定义 Webhook 中任一告警转换失败时拒绝整个载荷
+	IngestSchemaStrict IngestSchemaMode = "strict"
+)
+
+// IsValid 检查校验模式是否有效
+func (m IngestSchemaMode) IsValid() bool {
+	return m == IngestSchemaLenient || m == IngestSchemaStrict
+}
+
+// IngestEndpoint 告警接入端点
+type IngestEndpoint string
+
+const (
+	IngestEndpointAlerts IngestEndpoint = "alerts" // 标准告警接入 /api/v1/ingest/:id/alerts
+	IngestEndpointCustom IngestEndpoint = "custom" // 自定义 Webhook /api/v1/webhooks/custom/:id
+)
+
+// IngestRejectReason 接入载荷被拒绝的原因
+type IngestRejectReason string
+
+const (
+	IngestRejectInvalidJSON  IngestRejectReason = "invalid_json"  // 不是合法的 JSON 或包含多余内容
+	IngestRejectUnknownField IngestRejectReason = "unknown_field" // 严格模式下包含未知字段
+	IngestRejectTypeMismatch IngestRejectReason = "type_mismatch" // 字段类型不匹配
+	IngestRejectMissingField IngestRejectReason = "missing_field" // 缺少必填字段
+	IngestRejectInvalidValue IngestRejectReason = "invalid_value" // 字段值无效
+	IngestRejectMappingError IngestRejectReason = "mapping_error" // 自定义 Webhook 载荷映射转换失败
+)
+
+// IngestRejectionPayloadMaxLength 拒绝记录中保存的原始载荷的最大长度
+const IngestRejectionPayloadMaxLength = 4096
+
+// IngestSchemaError 接入载荷校验错误，按 ErrInvalidInput 处理，Cause 为底层的解析或校验错误
+type IngestSchemaError struct {
+	Reason IngestRejectReason
+	Detail string
+	Cause  error
+}
+
+// Error 实现 error 接口
+func (e *IngestSchemaError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidInput, e.Detail)
+}
+
+// Unwrap 返回 ErrInvalidInput 和底层错误
+func (e *IngestSchemaError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{ErrInvalidInput}
+	}
+	return []error{ErrInvalidInput, e.Cause}
+}
+
+// NewIngestSchemaError 创建接入载荷校验错误
+func NewIngestSchemaError(reason IngestRejectReason, format string, args ...interface{}) *IngestSchemaError {
+	return &IngestSchemaError{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// IngestRejection 一次被拒绝的接入请求，按采样率保存原始载荷，用于排查上游推送格式异常
+type IngestRejection struct {
+	ID            string             `json:"id" db:"id"`
+	IntegrationID string             `json:"integration_id" db:"integration_id"`
+	Endpoint      IngestEndpoint     `json:"endpoint" db:"endpoint"`
+	Reason        IngestRejectReason `json:"reason" db:"reason"`
+	Detail        string             `json:"detail" db:"detail"`
+	// Payload 原始载荷，未被采样时为空，超出长度时截断
+	Payload     *string   `json:"payload,omitempty" db:"payload"`
+	PayloadSize int       `json:"payload_size" db:"payload_size"`
+	ClientIP    string    `json:"client_ip" db:"client_ip"`
+	ReceivedAt  time.Time `json:"received_at" db:"received_at"`
+}
+
+// IngestRejectionFilter 拒绝记录查询条件
+type IngestRejectionFilter struct {
+	IntegrationID string
+	Reason        *IngestRejectReason
+	Limit         int
+}
+
+// IngestRejectionCount 按原因统计的拒绝次数
+type IngestRejectionCount struct {
+	Reason IngestRejectReason `json:"reason" db:"reason"`
+	Count  int64              `json:"count" db:"count"`
+}
+
+// IngestRejectionReport 集成的拒绝记录和按原因的统计
+type IngestRejectionReport struct {
+	IntegrationID string                  `json:"integration_id"`
+	Mode          IngestSchemaMode        `json:"mode"`
+	Counts        []*IngestRejectionCount `json:"counts"`
+	Rejections    []*IngestRejection      `json:"rejections"`
+}
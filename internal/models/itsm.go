@@ -0,0 +1,342 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ITSMProvider 外部 ITSM 系统类型
+type ITSMProvider string
+
+const (
+	ITSMProviderJira       ITSMProvider = "jira"       // Jira
+	ITSMProviderServiceNow ITSMProvider = "servicenow" // ServiceNow
+)
+
+// IsValid 检查 ITSM 系统类型是否有效
+func (p ITSMProvider) IsValid() bool {
+	switch p {
+	case ITSMProviderJira, ITSMProviderServiceNow:
+		return true
+	default:
+		return false
+	}
+}
+
+// ITSMConflictStrategy 平台和外部系统在两次同步之间都修改了工单时的冲突处理策略
+type ITSMConflictStrategy string
+
+const (
+	ITSMConflictLocalWins  ITSMConflictStrategy = "local_wins"  // 以平台为准
+	ITSMConflictRemoteWins ITSMConflictStrategy = "remote_wins" // 以外部系统为准
+	ITSMConflictLatestWins ITSMConflictStrategy = "latest_wins" // 以最后修改的一方为准
+)
+
+// IsValid 检查冲突处理策略是否有效
+func (s ITSMConflictStrategy) IsValid() bool {
+	switch s {
+	case ITSMConflictLocalWins, ITSMConflictRemoteWins, ITSMConflictLatestWins:
+		return true
+	default:
+		return false
+	}
+}
+
+// PreferRemote 双方都有修改时是否采用外部系统的修改
+func (s ITSMConflictStrategy) PreferRemote(localUpdatedAt, remoteUpdatedAt time.Time) bool {
+	switch s {
+	case ITSMConflictRemoteWins:
+		return true
+	case ITSMConflictLatestWins:
+		return remoteUpdatedAt.After(localUpdatedAt)
+	default:
+		return false
+	}
+}
+
+// itsmStatusOrder 外部状态反向映射到平台状态时的匹配顺序，多个平台状态映射到同一外部状态时取先出现的
+var itsmStatusOrder = []TicketStatus{
+	TicketStatusOpen, TicketStatusAssigned, TicketStatusInProgress, TicketStatusPending,
+	TicketStatusResolved, TicketStatusClosed, TicketStatusCancelled,
+}
+
+// itsmPriorityOrder 外部优先级反向映射到平台优先级时的匹配顺序
+var itsmPriorityOrder = []TicketPriority{
+	TicketPriorityLow, TicketPriorityMedium, TicketPriorityHigh, TicketPriorityCritical, TicketPriorityUrgent,
+}
+
+// ITSMFieldMapping 平台工单字段到外部系统字段的映射
+type ITSMFieldMapping struct {
+	// IssueType Jira 问题类型，ServiceNow 不使用
+	IssueType string `json:"issue_type,omitempty"`
+	// Status 平台工单状态到外部状态的映射（Jira 状态名称或 ServiceNow state 值）
+	Status map[string]string `json:"status,omitempty"`
+	// Priority 平台工单优先级到外部优先级的映射
+	Priority map[string]string `json:"priority,omitempty"`
+	// CustomFields 平台自定义字段键到外部字段名的映射，只从平台同步到外部系统
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+}
+
+// DefaultITSMFieldMapping 外部系统的默认字段映射
+func DefaultITSMFieldMapping(provider ITSMProvider) ITSMFieldMapping {
+	if provider == ITSMProviderServiceNow {
+		return ITSMFieldMapping{
+			Status: map[string]string{
+				string(TicketStatusOpen):       "1",
+				string(TicketStatusAssigned):   "2",
+				string(TicketStatusInProgress): "2",
+				string(TicketStatusPending):    "3",
+				string(TicketStatusResolved):   "6",
+				string(TicketStatusClosed):     "7",
+				string(TicketStatusCancelled):  "8",
+			},
+			Priority: map[string]string{
+				string(TicketPriorityLow):      "4",
+				string(TicketPriorityMedium):   "3",
+				string(TicketPriorityHigh):     "2",
+				string(TicketPriorityCritical): "1",
+				string(TicketPriorityUrgent):   "1",
+			},
+		}
+	}
+
+	return ITSMFieldMapping{
+		IssueType: "Task",
+		Status: map[string]string{
+			string(TicketStatusOpen):       "To Do",
+			string(TicketStatusAssigned):   "To Do",
+			string(TicketStatusInProgress): "In Progress",
+			string(TicketStatusPending):    "In Progress",
+			string(TicketStatusResolved):   "Done",
+			string(TicketStatusClosed):     "Done",
+			string(TicketStatusCancelled):  "Done",
+		},
+		Priority: map[string]string{
+			string(TicketPriorityLow):      "Low",
+			string(TicketPriorityMedium):   "Medium",
+			string(TicketPriorityHigh):     "High",
+			string(TicketPriorityCritical): "Highest",
+			string(TicketPriorityUrgent):   "Highest",
+		},
+	}
+}
+
+// RemoteStatus 平台工单状态对应的外部状态，未映射时返回空
+func (m ITSMFieldMapping) RemoteStatus(status TicketStatus) string {
+	return m.Status[string(status)]
+}
+
+// LocalStatus 外部状态对应的平台工单状态，比较时忽略大小写
+func (m ITSMFieldMapping) LocalStatus(remote string) (TicketStatus, bool) {
+	for _, status := range itsmStatusOrder {
+		if value, ok := m.Status[string(status)]; ok && strings.EqualFold(value, remote) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// RemotePriority 平台工单优先级对应的外部优先级，未映射时返回空
+func (m ITSMFieldMapping) RemotePriority(priority TicketPriority) string {
+	return m.Priority[string(priority)]
+}
+
+// LocalPriority 外部优先级对应的平台工单优先级，比较时忽略大小写
+func (m ITSMFieldMapping) LocalPriority(remote string) (TicketPriority, bool) {
+	for _, priority := range itsmPriorityOrder {
+		if value, ok := m.Priority[string(priority)]; ok && strings.EqualFold(value, remote) {
+			return priority, true
+		}
+	}
+	return "", false
+}
+
+// Validate 验证字段映射只引用平台已有的状态和优先级
+func (m ITSMFieldMapping) Validate() error {
+	for status := range m.Status {
+		if !TicketStatus(status).IsValid() {
+			return fmt.Errorf("%w: 状态映射包含无效的工单状态 %s", ErrInvalidInput, status)
+		}
+	}
+	for priority := range m.Priority {
+		if !TicketPriority(priority).IsValid() {
+			return fmt.Errorf("%w: 优先级映射包含无效的工单优先级 %s", ErrInvalidInput, priority)
+		}
+	}
+	return nil
+}
+
+// ITSMConnector 外部 ITSM 同步连接器，每个连接器对应外部系统中的一个项目（Jira 项目或 ServiceNow 表）
+// API 令牌和 Webhook 密钥加密存储，不在 JSON 中返回
+type ITSMConnector struct {
+	ID               string               `json:"id" db:"id"`
+	Name             string               `json:"name" db:"name"`
+	Provider         ITSMProvider         `json:"provider" db:"provider"`
+	BaseURL          string               `json:"base_url" db:"base_url"`
+	Username         string               `json:"username" db:"username"`
+	APIToken         string               `json:"-" db:"api_token"`
+	Project          string               `json:"project" db:"project"`
+	TicketTypes      []string             `json:"ticket_types" db:"ticket_types"`
+	FieldMapping     ITSMFieldMapping     `json:"field_mapping" db:"field_mapping"`
+	ConflictStrategy ITSMConflictStrategy `json:"conflict_strategy" db:"conflict_strategy"`
+	SyncComments     bool                 `json:"sync_comments" db:"sync_comments"`
+	SyncUserID       string               `json:"sync_user_id" db:"sync_user_id"`
+	WebhookSecret    string               `json:"-" db:"webhook_secret"`
+	Enabled          bool                 `json:"enabled" db:"enabled"`
+	LastSyncedAt     *time.Time           `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	CreatedBy        string               `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// SyncsTicketType 连接器是否同步该类型的工单，未限制类型时同步全部工单
+func (c *ITSMConnector) SyncsTicketType(ticketType TicketType) bool {
+	if len(c.TicketTypes) == 0 {
+		return true
+	}
+	for _, t := range c.TicketTypes {
+		if t == string(ticketType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 验证 ITSM 连接器
+func (c *ITSMConnector) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: 连接器名称不能为空", ErrInvalidInput)
+	}
+	if !c.Provider.IsValid() {
+		return fmt.Errorf("%w: 不支持的ITSM系统 %s", ErrInvalidInput, c.Provider)
+	}
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: 无效的ITSM系统地址", ErrInvalidInput)
+	}
+	if strings.TrimSpace(c.Project) == "" {
+		return fmt.Errorf("%w: 项目不能为空", ErrInvalidInput)
+	}
+	if c.APIToken == "" {
+		return fmt.Errorf("%w: API令牌不能为空", ErrInvalidInput)
+	}
+	if c.SyncUserID == "" {
+		return fmt.Errorf("%w: 同步用户不能为空", ErrInvalidInput)
+	}
+	if !c.ConflictStrategy.IsValid() {
+		return fmt.Errorf("%w: 无效的冲突处理策略 %s", ErrInvalidInput, c.ConflictStrategy)
+	}
+	for _, t := range c.TicketTypes {
+		if !TicketType(t).IsValid() {
+			return fmt.Errorf("%w: 无效的工单类型 %s", ErrInvalidInput, t)
+		}
+	}
+	return c.FieldMapping.Validate()
+}
+
+// ITSMConnectorRequest 创建或更新 ITSM 连接器请求
+type ITSMConnectorRequest struct {
+	Name             string               `json:"name" binding:"required,max=200"`
+	Provider         ITSMProvider         `json:"provider" binding:"required"`
+	BaseURL          string               `json:"base_url" binding:"required,url"`
+	Username         string               `json:"username,omitempty"`
+	APIToken         string               `json:"api_token,omitempty"`
+	Project          string               `json:"project" binding:"required,max=100"`
+	TicketTypes      []string             `json:"ticket_types,omitempty"`
+	FieldMapping     *ITSMFieldMapping    `json:"field_mapping,omitempty"`
+	ConflictStrategy ITSMConflictStrategy `json:"conflict_strategy,omitempty"`
+	SyncComments     *bool                `json:"sync_comments,omitempty"`
+	SyncUserID       string               `json:"sync_user_id" binding:"required"`
+	Enabled          *bool                `json:"enabled,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到连接器，更新时未提供 API 令牌则保留原令牌，未提供字段映射时使用默认映射
+func (r *ITSMConnectorRequest) ApplyTo(c *ITSMConnector) {
+	c.Name = strings.TrimSpace(r.Name)
+	c.Provider = r.Provider
+	c.BaseURL = strings.TrimRight(r.BaseURL, "/")
+	c.Username = r.Username
+	if r.APIToken != "" {
+		c.APIToken = r.APIToken
+	}
+	c.Project = strings.TrimSpace(r.Project)
+	c.TicketTypes = r.TicketTypes
+	if c.TicketTypes == nil {
+		c.TicketTypes = []string{}
+	}
+	if r.FieldMapping != nil {
+		c.FieldMapping = *r.FieldMapping
+	} else {
+		c.FieldMapping = DefaultITSMFieldMapping(r.Provider)
+	}
+	c.ConflictStrategy = r.ConflictStrategy
+	if c.ConflictStrategy == "" {
+		c.ConflictStrategy = ITSMConflictLatestWins
+	}
+	if r.SyncComments != nil {
+		c.SyncComments = *r.SyncComments
+	}
+	c.SyncUserID = r.SyncUserID
+	if r.Enabled != nil {
+		c.Enabled = *r.Enabled
+	}
+}
+
+// ITSMConnectorSecret 创建连接器后返回的连接器和明文 Webhook 密钥
+type ITSMConnectorSecret struct {
+	Connector     *ITSMConnector `json:"connector"`
+	WebhookSecret string         `json:"webhook_secret"`
+}
+
+// ITSMTicketLink 平台工单与外部工单的关联及同步状态
+type ITSMTicketLink struct {
+	ID              string     `json:"id" db:"id"`
+	ConnectorID     string     `json:"connector_id" db:"connector_id"`
+	TicketID        string     `json:"ticket_id" db:"ticket_id"`
+	RemoteID        string     `json:"remote_id" db:"remote_id"`
+	RemoteKey       string     `json:"remote_key" db:"remote_key"`
+	RemoteURL       string     `json:"remote_url" db:"remote_url"`
+	LastSyncedAt    time.Time  `json:"last_synced_at" db:"last_synced_at"`
+	RemoteUpdatedAt *time.Time `json:"remote_updated_at,omitempty" db:"remote_updated_at"`
+	LastError       *string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ITSMRemoteIssue 外部系统中的工单，出站时由字段映射生成，入站时由 Webhook 解析
+type ITSMRemoteIssue struct {
+	ID          string
+	Key         string
+	URL         string
+	Title       string
+	Description string
+	Status      string
+	Priority    string
+	IssueType   string
+	Fields      map[string]interface{}
+	UpdatedAt   time.Time
+}
+
+// ITSMRemoteComment 外部系统中的工单评论
+type ITSMRemoteComment struct {
+	ID        string
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// ITSMWebhookEvent 外部系统 Webhook 推送的工单变更
+type ITSMWebhookEvent struct {
+	Issue   ITSMRemoteIssue
+	Comment *ITSMRemoteComment
+}
+
+// ITSMWebhookResult Webhook 处理结果
+type ITSMWebhookResult struct {
+	TicketID      string `json:"ticket_id,omitempty"`
+	FieldsApplied bool   `json:"fields_applied"`
+	Conflict      bool   `json:"conflict"`
+	CommentAdded  bool   `json:"comment_added"`
+	IgnoredReason string `json:"ignored_reason,omitempty"`
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"strings"
 	"encoding/json"
+
+	"pulse/internal/pkg/slug"
 )
 
 // KnowledgeType 知识类型
@@ -30,6 +32,7 @@ const (
 	KnowledgeStatusPublished KnowledgeStatus = "published" // 已发布
 	KnowledgeStatusArchived  KnowledgeStatus = "archived"  // 已归档
 	KnowledgeStatusExpired   KnowledgeStatus = "expired"   // 已过期
+	KnowledgeStatusScheduled KnowledgeStatus = "scheduled" // 定时发布
 )
 
 // KnowledgeVisibility 知识可见性
@@ -162,6 +165,8 @@ type Knowledge struct {
 	Featured      bool                 `json:"featured" db:"featured"`
 	RelatedIDs    []string             `json:"related_ids" db:"related_ids"`
 	ExpiresAt    *time.Time           `json:"expires_at,omitempty" db:"expires_at"`
+	ExpiryWarnedAt *time.Time         `json:"-" db:"expiry_warned_at"`
+	PublishAt    *time.Time           `json:"publish_at,omitempty" db:"publish_at"`
 	PublishedAt  *time.Time           `json:"published_at,omitempty" db:"published_at"`
 	ArchivedAt   *time.Time           `json:"archived_at,omitempty" db:"archived_at"`
 	ReviewedAt   *time.Time           `json:"reviewed_at,omitempty" db:"reviewed_at"`
@@ -192,6 +197,7 @@ type KnowledgeCreateRequest struct {
 	Metadata     *map[string]interface{} `json:"metadata,omitempty"`
 	RelatedIDs   []string               `json:"related_ids,omitempty"`
 	ExpiresAt    *time.Time             `json:"expires_at,omitempty"`
+	PublishAt    *time.Time             `json:"publish_at,omitempty"`
 }
 
 // KnowledgeUpdateRequest 更新知识请求
@@ -215,6 +221,7 @@ type KnowledgeUpdateRequest struct {
 	Metadata     *map[string]interface{} `json:"metadata,omitempty"`
 	RelatedIDs   *[]string              `json:"related_ids,omitempty"`
 	ExpiresAt    *time.Time             `json:"expires_at,omitempty"`
+	PublishAt    *time.Time             `json:"publish_at,omitempty"`
 	ChangeLog    *string                `json:"change_log,omitempty"`
 }
 
@@ -223,6 +230,17 @@ type KnowledgePublishRequest struct {
 	Comment *string `json:"comment,omitempty"`
 }
 
+// KnowledgeScheduleRequest 定时发布请求
+type KnowledgeScheduleRequest struct {
+	PublishAt time.Time  `json:"publish_at" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// KnowledgeExpiryRequest 设置过期时间请求，为空表示永不过期
+type KnowledgeExpiryRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
 // KnowledgeReviewRequest 审核知识请求
 type KnowledgeReviewRequest struct {
 	Approved bool    `json:"approved"`
@@ -259,6 +277,29 @@ type KnowledgeFilter struct {
 	PageSize     int                  `json:"page_size" binding:"min=1,max=100"`
 	SortBy       *string              `json:"sort_by,omitempty"`
 	SortOrder    *string              `json:"sort_order,omitempty"` // asc, desc
+	ViewerID     *string              `json:"-"`                    // 设置后按访问控制过滤，管理员不设置
+}
+
+// KnowledgeLookupKey 知识库文章的查找方式
+type KnowledgeLookupKey string
+
+const (
+	KnowledgeLookupByID   KnowledgeLookupKey = "id"   // 按ID查找
+	KnowledgeLookupBySlug KnowledgeLookupKey = "slug" // 按Slug查找
+)
+
+// IsValid 检查查找方式是否有效
+func (k KnowledgeLookupKey) IsValid() bool {
+	return k == KnowledgeLookupByID || k == KnowledgeLookupBySlug
+}
+
+// KnowledgeSlugLookup 按Slug查找文章的结果
+// 命中改名前的旧Slug时 Moved 为 true，Slug 为文章当前的Slug，调用方应重定向
+type KnowledgeSlugLookup struct {
+	KnowledgeID string     `json:"knowledge_id" db:"knowledge_id"`
+	Slug        string     `json:"slug" db:"slug"`
+	Moved       bool       `json:"moved"`
+	Knowledge   *Knowledge `json:"knowledge,omitempty"`
 }
 
 // KnowledgeList 知识列表响应
@@ -286,6 +327,24 @@ type KnowledgeStats struct {
 	AvgRating      float64                        `json:"avg_rating"`
 }
 
+// KnowledgeTOCEntry 知识文章目录项
+type KnowledgeTOCEntry struct {
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Anchor string `json:"anchor"`
+}
+
+// KnowledgeRendered 知识文章渲染结果
+type KnowledgeRendered struct {
+	KnowledgeID string              `json:"knowledge_id"`
+	Title       string              `json:"title"`
+	Format      KnowledgeFormat     `json:"format"`
+	Version     string              `json:"version"`
+	HTML        string              `json:"html"`
+	TOC         []KnowledgeTOCEntry `json:"toc"`
+	RenderedAt  time.Time           `json:"rendered_at"`
+}
+
 // KnowledgeSearchResult 知识搜索结果
 type KnowledgeSearchResult struct {
 	Knowledge  []*Knowledge `json:"knowledge"`
@@ -388,7 +447,7 @@ func (t KnowledgeType) IsValid() bool {
 func (s KnowledgeStatus) IsValid() bool {
 	switch s {
 	case KnowledgeStatusDraft, KnowledgeStatusReview, KnowledgeStatusPublished,
-		 KnowledgeStatusArchived, KnowledgeStatusExpired:
+		 KnowledgeStatusArchived, KnowledgeStatusExpired, KnowledgeStatusScheduled:
 		return true
 	default:
 		return false
@@ -484,6 +543,11 @@ func (k *Knowledge) IsExpired() bool {
 	return time.Now().After(*k.ExpiresAt)
 }
 
+// CanSchedule 检查知识是否可以设置定时发布（草稿或已定时）
+func (k *Knowledge) CanSchedule() bool {
+	return k.Status == KnowledgeStatusDraft || k.Status == KnowledgeStatusScheduled
+}
+
 // IsPublic 检查知识是否公开
 func (k *Knowledge) IsPublic() bool {
 	return k.Visibility == KnowledgeVisibilityPublic
@@ -552,27 +616,13 @@ func (k *Knowledge) GetReadingTime() int {
 	return readingTime
 }
 
-// GenerateSlug 生成URL友好的slug
+// GenerateSlug 生成URL友好的slug，中文标题转写为拼音
 func (k *Knowledge) GenerateSlug() string {
 	if k.Slug != "" {
 		return k.Slug
 	}
-	
-	// 简单的slug生成逻辑
-	slug := strings.ToLower(k.Title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "_", "-")
-	
-	// 移除特殊字符
-	allowed := "abcdefghijklmnopqrstuvwxyz0123456789-"
-	var result strings.Builder
-	for _, char := range slug {
-		if strings.ContainsRune(allowed, char) {
-			result.WriteRune(char)
-		}
-	}
-	
-	return result.String()
+
+	return slug.Make(k.Title)
 }
 
 // MarshalTags 序列化标签为JSON
@@ -697,6 +747,8 @@ func (s KnowledgeStatus) GetDisplayName() string {
 		return "已归档"
 	case KnowledgeStatusExpired:
 		return "已过期"
+	case KnowledgeStatusScheduled:
+		return "定时发布"
 	default:
 		return string(s)
 	}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KnowledgeACLResourceType 访问控制的资源类型
+type KnowledgeACLResourceType string
+
+const (
+	KnowledgeACLResourceArticle  KnowledgeACLResourceType = "article"  // 文章
+	KnowledgeACLResourceCategory KnowledgeACLResourceType = "category" // 分类
+)
+
+// KnowledgeACLPrincipalType 访问控制的授权对象类型
+type KnowledgeACLPrincipalType string
+
+const (
+	KnowledgeACLPrincipalRole KnowledgeACLPrincipalType = "role" // 用户角色
+	KnowledgeACLPrincipalTeam KnowledgeACLPrincipalType = "team" // 团队（用户的 team 字段）
+	KnowledgeACLPrincipalUser KnowledgeACLPrincipalType = "user" // 单个用户
+)
+
+// KnowledgeACLPermission 访问权限，写权限包含读权限
+type KnowledgeACLPermission string
+
+const (
+	KnowledgeACLPermissionRead  KnowledgeACLPermission = "read"  // 读
+	KnowledgeACLPermissionWrite KnowledgeACLPermission = "write" // 读写
+)
+
+// KnowledgeACLMaxEntries 单个资源的最大授权条目数
+const KnowledgeACLMaxEntries = 200
+
+// KnowledgeACLEntry 知识库访问控制条目
+// 文章有授权条目时只按文章条目判断；否则按所属分类的条目判断；两者都没有时不限制
+// 文章作者和管理员始终拥有读写权限
+type KnowledgeACLEntry struct {
+	ID            string                    `json:"id" db:"id"`
+	ResourceType  KnowledgeACLResourceType  `json:"resource_type" db:"resource_type"`
+	ResourceID    string                    `json:"resource_id" db:"resource_id"`
+	PrincipalType KnowledgeACLPrincipalType `json:"principal_type" db:"principal_type"`
+	PrincipalID   string                    `json:"principal_id" db:"principal_id"`
+	Permission    KnowledgeACLPermission    `json:"permission" db:"permission"`
+	CreatedBy     *string                   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time                 `json:"created_at" db:"created_at"`
+}
+
+// KnowledgeACLEntryRequest 授权条目请求
+type KnowledgeACLEntryRequest struct {
+	PrincipalType KnowledgeACLPrincipalType `json:"principal_type" binding:"required"`
+	PrincipalID   string                    `json:"principal_id" binding:"required"`
+	Permission    KnowledgeACLPermission    `json:"permission" binding:"required"`
+}
+
+// KnowledgeACLRequest 设置访问控制请求，整体替换已有条目，空列表表示取消限制
+type KnowledgeACLRequest struct {
+	Entries []KnowledgeACLEntryRequest `json:"entries"`
+}
+
+// Validate 验证授权条目
+func (r *KnowledgeACLEntryRequest) Validate() error {
+	switch r.PrincipalType {
+	case KnowledgeACLPrincipalRole:
+		if !UserRole(r.PrincipalID).IsValid() {
+			return errors.New("无效的用户角色")
+		}
+	case KnowledgeACLPrincipalTeam, KnowledgeACLPrincipalUser:
+		if strings.TrimSpace(r.PrincipalID) == "" {
+			return errors.New("授权对象不能为空")
+		}
+	default:
+		return errors.New("无效的授权对象类型")
+	}
+
+	if !r.Permission.IsValid() {
+		return errors.New("无效的访问权限")
+	}
+
+	return nil
+}
+
+// Validate 验证访问控制请求
+func (r *KnowledgeACLRequest) Validate() error {
+	if len(r.Entries) > KnowledgeACLMaxEntries {
+		return fmt.Errorf("授权条目不能超过 %d 条", KnowledgeACLMaxEntries)
+	}
+
+	for i := range r.Entries {
+		if err := r.Entries[i].Validate(); err != nil {
+			return fmt.Errorf("第 %d 条授权: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// IsValid 检查资源类型是否有效
+func (t KnowledgeACLResourceType) IsValid() bool {
+	return t == KnowledgeACLResourceArticle || t == KnowledgeACLResourceCategory
+}
+
+// IsValid 检查访问权限是否有效
+func (p KnowledgeACLPermission) IsValid() bool {
+	return p == KnowledgeACLPermissionRead || p == KnowledgeACLPermissionWrite
+}
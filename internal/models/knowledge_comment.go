@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KnowledgeCommentStatus 评论状态
+type KnowledgeCommentStatus string
+
+const (
+	KnowledgeCommentStatusVisible KnowledgeCommentStatus = "visible" // 可见
+	KnowledgeCommentStatusHidden  KnowledgeCommentStatus = "hidden"  // 已被管理员隐藏
+)
+
+// KnowledgeCommentMaxLength 评论内容最大长度
+const KnowledgeCommentMaxLength = 10000
+
+// knowledgeCommentReactions 允许的表情回应
+var knowledgeCommentReactions = map[string]bool{
+	"+1": true, "-1": true, "laugh": true, "heart": true,
+	"hooray": true, "confused": true, "rocket": true, "eyes": true,
+}
+
+// KnowledgeComment 知识库文章评论
+type KnowledgeComment struct {
+	ID               string                 `json:"id" db:"id"`
+	KnowledgeID      string                 `json:"knowledge_id" db:"knowledge_id"`
+	ParentID         *string                `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID         string                 `json:"author_id" db:"author_id"`
+	Content          string                 `json:"content" db:"content"`
+	Status           KnowledgeCommentStatus `json:"status" db:"status"`
+	FlagCount        int                    `json:"flag_count" db:"flag_count"`
+	ModeratedBy      *string                `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt      *time.Time             `json:"moderated_at,omitempty" db:"moderated_at"`
+	ModerationReason *string                `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	Reactions        map[string]int         `json:"reactions" db:"-"`
+	Deleted          bool                   `json:"deleted" db:"-"`
+	EditedAt         *time.Time             `json:"edited_at,omitempty" db:"edited_at"`
+	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at" db:"updated_at"`
+	DeletedAt        *time.Time             `json:"-" db:"deleted_at"`
+	Replies          []*KnowledgeComment    `json:"replies,omitempty" db:"-"`
+}
+
+// Redact 隐藏已删除或被隐藏评论的内容，保留在讨论串中的位置
+func (c *KnowledgeComment) Redact() {
+	if c.DeletedAt != nil {
+		c.Deleted = true
+		c.Content = ""
+		return
+	}
+	if c.Status == KnowledgeCommentStatusHidden {
+		c.Content = ""
+	}
+}
+
+// KnowledgeCommentCreateRequest 创建评论请求
+type KnowledgeCommentCreateRequest struct {
+	Content  string  `json:"content" binding:"required"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// KnowledgeCommentUpdateRequest 编辑评论请求
+type KnowledgeCommentUpdateRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// KnowledgeCommentReactionRequest 表情回应请求
+type KnowledgeCommentReactionRequest struct {
+	Reaction string `json:"reaction" binding:"required"`
+}
+
+// KnowledgeCommentFlagRequest 举报评论请求
+type KnowledgeCommentFlagRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+// KnowledgeCommentModerateRequest 审核评论请求
+type KnowledgeCommentModerateRequest struct {
+	Status KnowledgeCommentStatus `json:"status" binding:"required"`
+	Reason *string                `json:"reason,omitempty"`
+}
+
+// ValidateKnowledgeCommentContent 验证评论内容
+func ValidateKnowledgeCommentContent(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("%w: 评论内容不能为空", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(content) > KnowledgeCommentMaxLength {
+		return fmt.Errorf("%w: 评论内容不能超过%d个字符", ErrInvalidInput, KnowledgeCommentMaxLength)
+	}
+	return nil
+}
+
+// IsValidKnowledgeCommentReaction 检查表情回应是否有效
+func IsValidKnowledgeCommentReaction(reaction string) bool {
+	return knowledgeCommentReactions[reaction]
+}
+
+// IsValid 检查评论状态是否有效
+func (s KnowledgeCommentStatus) IsValid() bool {
+	return s == KnowledgeCommentStatusVisible || s == KnowledgeCommentStatusHidden
+}
+
+// BuildKnowledgeCommentTree 将按时间排序的评论列表组装为讨论串
+// 父评论不在列表中的回复作为顶层评论返回
+func BuildKnowledgeCommentTree(comments []*KnowledgeComment) []*KnowledgeComment {
+	index := make(map[string]*KnowledgeComment, len(comments))
+	for _, comment := range comments {
+		index[comment.ID] = comment
+	}
+
+	roots := make([]*KnowledgeComment, 0)
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := index[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+		roots = append(roots, comment)
+	}
+
+	return roots
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// 重复检测默认参数
+const (
+	DefaultKnowledgeDuplicateThreshold = 0.4 // 默认相似度阈值
+	DefaultKnowledgeDuplicateLimit     = 5   // 默认返回的候选数量
+	MaxKnowledgeDuplicateLimit         = 20  // 最大返回的候选数量
+)
+
+// 标题和内容在综合相似度中的权重
+const (
+	KnowledgeDuplicateTitleWeight   = 0.6
+	KnowledgeDuplicateContentWeight = 0.4
+)
+
+// KnowledgeWarningPossibleDuplicate 可能重复的警告代码
+const KnowledgeWarningPossibleDuplicate = "possible_duplicate"
+
+// KnowledgeDuplicateCheckRequest 重复检测请求
+type KnowledgeDuplicateCheckRequest struct {
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	ExcludeID *string  `json:"exclude_id,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty" binding:"omitempty,gt=0,lte=1"`
+	Limit     int      `json:"limit,omitempty" binding:"omitempty,min=1,max=20"`
+	ViewerID  *string  `json:"-"` // 设置后只返回当前用户可见的文章
+}
+
+// Validate 验证重复检测请求并填充默认值
+func (req *KnowledgeDuplicateCheckRequest) Validate() error {
+	if strings.TrimSpace(req.Title) == "" && strings.TrimSpace(req.Content) == "" {
+		return errors.New("标题和内容不能同时为空")
+	}
+
+	if req.Threshold == nil {
+		threshold := DefaultKnowledgeDuplicateThreshold
+		req.Threshold = &threshold
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = DefaultKnowledgeDuplicateLimit
+	}
+	if req.Limit > MaxKnowledgeDuplicateLimit {
+		req.Limit = MaxKnowledgeDuplicateLimit
+	}
+
+	return nil
+}
+
+// KnowledgeDuplicateCandidate 可能重复的知识文章
+type KnowledgeDuplicateCandidate struct {
+	ID                string          `json:"id" db:"id"`
+	Title             string          `json:"title" db:"title"`
+	Status            KnowledgeStatus `json:"status" db:"status"`
+	TitleSimilarity   float64         `json:"title_similarity" db:"title_similarity"`
+	ContentSimilarity float64         `json:"content_similarity" db:"content_similarity"`
+	Similarity        float64         `json:"similarity" db:"similarity"`
+}
+
+// KnowledgeWarning 知识库操作警告，不阻断操作
+type KnowledgeWarning struct {
+	Code       string                         `json:"code"`
+	Message    string                         `json:"message"`
+	Duplicates []*KnowledgeDuplicateCandidate `json:"duplicates,omitempty"`
+}
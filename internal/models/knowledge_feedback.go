@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KnowledgeFeedbackMaxComment 知识库文章有用性反馈意见的最大长度
+const KnowledgeFeedbackMaxComment = 1000
+
+// TicketKnowledgeLink 工单处理过程中引用的知识库文章。工单解决时提示解决人评价文章是否有用，
+// 评价汇总为文章的质量分
+type TicketKnowledgeLink struct {
+	ID          string `json:"id" db:"id"`
+	TicketID    string `json:"ticket_id" db:"ticket_id"`
+	KnowledgeID string `json:"knowledge_id" db:"knowledge_id"`
+	LinkedBy    string `json:"linked_by" db:"linked_by"`
+	// ResolverID 工单解决时被提示评价的用户
+	ResolverID *string    `json:"resolver_id,omitempty" db:"resolver_id"`
+	PromptedAt *time.Time `json:"prompted_at,omitempty" db:"prompted_at"`
+	Useful     *bool      `json:"useful,omitempty" db:"useful"`
+	Comment    string     `json:"comment,omitempty" db:"comment"`
+	RatedAt    *time.Time `json:"rated_at,omitempty" db:"rated_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+
+	// 查询时关联的工单和文章信息
+	TicketNumber   string `json:"ticket_number,omitempty" db:"-"`
+	KnowledgeTitle string `json:"knowledge_title,omitempty" db:"-"`
+}
+
+// TicketKnowledgeLinkRequest 为工单关联知识库文章请求
+type TicketKnowledgeLinkRequest struct {
+	KnowledgeID string `json:"knowledge_id" binding:"required"`
+}
+
+// KnowledgeFeedbackRequest 评价工单引用的文章是否有用请求
+type KnowledgeFeedbackRequest struct {
+	Useful  *bool  `json:"useful" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+// Validate 验证反馈
+func (r *KnowledgeFeedbackRequest) Validate() error {
+	if r.Useful == nil {
+		return fmt.Errorf("%w: 必须评价文章是否有用", ErrInvalidInput)
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > KnowledgeFeedbackMaxComment {
+		return fmt.Errorf("%w: 反馈意见不能超过%d个字符", ErrInvalidInput, KnowledgeFeedbackMaxComment)
+	}
+	return nil
+}
+
+// KnowledgeQuality 文章的有用性统计。Score 为平滑后的有用比例 (有用数+1)/(评价数+2)，
+// 没有评价时为 0.5，搜索结果按质量分提升排序
+type KnowledgeQuality struct {
+	KnowledgeID    string  `json:"knowledge_id" db:"id"`
+	Title          string  `json:"title" db:"title"`
+	AuthorID       string  `json:"author_id" db:"author_id"`
+	UsefulCount    int64   `json:"useful_count" db:"useful_count"`
+	NotUsefulCount int64   `json:"not_useful_count" db:"not_useful_count"`
+	Score          float64 `json:"score" db:"quality_score"`
+	// Flagged 评价数达到下限且质量分低于阈值，需要作者更新
+	Flagged bool `json:"flagged" db:"-"`
+}
+
+// KnowledgeQualityScore 按有用和无用评价数计算质量分
+func KnowledgeQualityScore(useful, notUseful int64) float64 {
+	return float64(useful+1) / float64(useful+notUseful+2)
+}
+
+// KnowledgeQualityPolicy 判定文章需要更新的条件
+type KnowledgeQualityPolicy struct {
+	// Threshold 质量分低于该值的文章需要更新
+	Threshold float64
+	// MinFeedback 评价数达到该值后才判定，避免少量评价误判
+	MinFeedback int64
+}
+
+// Flagged 判断文章是否需要更新
+func (p KnowledgeQualityPolicy) Flagged(q *KnowledgeQuality) bool {
+	if q == nil {
+		return false
+	}
+	return q.UsefulCount+q.NotUsefulCount >= p.MinFeedback && q.Score < p.Threshold
+}
+
+// KnowledgeQualityFilter 文章质量列表过滤条件
+type KnowledgeQualityFilter struct {
+	// FlaggedOnly 只返回需要更新的文章
+	FlaggedOnly bool
+	Policy      KnowledgeQualityPolicy
+	Limit       int
+	// ViewerID 非空时只返回该用户有权阅读的文章
+	ViewerID *string
+}
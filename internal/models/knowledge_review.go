@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KnowledgeReviewDecision 审核决定
+type KnowledgeReviewDecision string
+
+const (
+	KnowledgeReviewDecisionApproved KnowledgeReviewDecision = "approved" // 通过
+	KnowledgeReviewDecisionRejected KnowledgeReviewDecision = "rejected" // 驳回
+)
+
+// KnowledgeReviewSubmissionStatus 审核提交状态
+type KnowledgeReviewSubmissionStatus string
+
+const (
+	KnowledgeReviewSubmissionPending   KnowledgeReviewSubmissionStatus = "pending"   // 待审核
+	KnowledgeReviewSubmissionApproved  KnowledgeReviewSubmissionStatus = "approved"  // 已通过
+	KnowledgeReviewSubmissionRejected  KnowledgeReviewSubmissionStatus = "rejected"  // 已驳回
+	KnowledgeReviewSubmissionCancelled KnowledgeReviewSubmissionStatus = "cancelled" // 已取消
+)
+
+// KnowledgeReviewPolicy 知识审核策略
+// CategoryID 为空时作为默认策略，分类策略优先于默认策略
+type KnowledgeReviewPolicy struct {
+	ID                string    `json:"id" db:"id"`
+	Name              string    `json:"name" db:"name"`
+	Description       *string   `json:"description,omitempty" db:"description"`
+	CategoryID        *string   `json:"category_id,omitempty" db:"category_id"`
+	RequiredApprovals int       `json:"required_approvals" db:"required_approvals"`
+	ReviewerIDs       []string  `json:"reviewer_ids" db:"reviewer_ids"`
+	ReviewerRoles     []string  `json:"reviewer_roles" db:"reviewer_roles"`
+	AllowSelfReview   bool      `json:"allow_self_review" db:"allow_self_review"`
+	Enabled           bool      `json:"enabled" db:"enabled"`
+	CreatedBy         *string   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultKnowledgeReviewPolicy 未配置策略时使用的默认策略：任意一名非作者审核通过即可
+func DefaultKnowledgeReviewPolicy() *KnowledgeReviewPolicy {
+	return &KnowledgeReviewPolicy{
+		Name:              "默认审核策略",
+		RequiredApprovals: 1,
+		ReviewerIDs:       []string{},
+		ReviewerRoles:     []string{},
+		Enabled:           true,
+	}
+}
+
+// Validate 验证审核策略
+func (p *KnowledgeReviewPolicy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: 策略名称不能为空", ErrInvalidInput)
+	}
+	if p.CategoryID != nil && strings.TrimSpace(*p.CategoryID) == "" {
+		return fmt.Errorf("%w: 分类ID不能为空字符串", ErrInvalidInput)
+	}
+	if p.RequiredApprovals < 1 {
+		return fmt.Errorf("%w: 所需审核通过人数至少为1", ErrInvalidInput)
+	}
+	// 指定审核人时，审核人数量必须满足通过人数
+	if len(p.ReviewerRoles) == 0 && len(p.ReviewerIDs) > 0 && len(p.ReviewerIDs) < p.RequiredApprovals {
+		return fmt.Errorf("%w: 指定审核人数量少于所需审核通过人数", ErrInvalidInput)
+	}
+	for _, role := range p.ReviewerRoles {
+		if !UserRole(role).IsValid() {
+			return fmt.Errorf("%w: 无效的审核角色 %s", ErrInvalidInput, role)
+		}
+	}
+	return nil
+}
+
+// KnowledgeReviewPolicyRequest 创建/更新审核策略请求
+type KnowledgeReviewPolicyRequest struct {
+	Name              string   `json:"name" binding:"required,max=200"`
+	Description       *string  `json:"description,omitempty"`
+	CategoryID        *string  `json:"category_id,omitempty"`
+	RequiredApprovals int      `json:"required_approvals" binding:"required,min=1"`
+	ReviewerIDs       []string `json:"reviewer_ids,omitempty"`
+	ReviewerRoles     []string `json:"reviewer_roles,omitempty"`
+	AllowSelfReview   bool     `json:"allow_self_review"`
+	Enabled           *bool    `json:"enabled,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到策略
+func (r *KnowledgeReviewPolicyRequest) ApplyTo(p *KnowledgeReviewPolicy) {
+	p.Name = r.Name
+	p.Description = r.Description
+	p.CategoryID = r.CategoryID
+	p.RequiredApprovals = r.RequiredApprovals
+	p.ReviewerIDs = r.ReviewerIDs
+	if p.ReviewerIDs == nil {
+		p.ReviewerIDs = []string{}
+	}
+	p.ReviewerRoles = r.ReviewerRoles
+	if p.ReviewerRoles == nil {
+		p.ReviewerRoles = []string{}
+	}
+	p.AllowSelfReview = r.AllowSelfReview
+	if r.Enabled != nil {
+		p.Enabled = *r.Enabled
+	}
+}
+
+// KnowledgeReviewSubmission 知识审核提交，提交审核时记录当时生效的策略
+type KnowledgeReviewSubmission struct {
+	ID                string                          `json:"id" db:"id"`
+	KnowledgeID       string                          `json:"knowledge_id" db:"knowledge_id"`
+	KnowledgeTitle    string                          `json:"knowledge_title,omitempty" db:"knowledge_title"`
+	PolicyID          *string                         `json:"policy_id,omitempty" db:"policy_id"`
+	RequiredApprovals int                             `json:"required_approvals" db:"required_approvals"`
+	ReviewerIDs       []string                        `json:"reviewer_ids" db:"reviewer_ids"`
+	ReviewerRoles     []string                        `json:"reviewer_roles" db:"reviewer_roles"`
+	AllowSelfReview   bool                            `json:"allow_self_review" db:"allow_self_review"`
+	Status            KnowledgeReviewSubmissionStatus `json:"status" db:"status"`
+	SubmittedBy       string                          `json:"submitted_by" db:"submitted_by"`
+	SubmittedAt       time.Time                       `json:"submitted_at" db:"submitted_at"`
+	CompletedAt       *time.Time                      `json:"completed_at,omitempty" db:"completed_at"`
+	Approvals         int                             `json:"approvals" db:"-"`
+	Decisions         []*KnowledgeReviewRecord        `json:"decisions,omitempty" db:"-"`
+}
+
+// NewKnowledgeReviewSubmission 根据策略创建审核提交
+func NewKnowledgeReviewSubmission(knowledgeID, submittedBy string, policy *KnowledgeReviewPolicy) *KnowledgeReviewSubmission {
+	req := &KnowledgeReviewSubmission{
+		KnowledgeID:       knowledgeID,
+		RequiredApprovals: policy.RequiredApprovals,
+		ReviewerIDs:       policy.ReviewerIDs,
+		ReviewerRoles:     policy.ReviewerRoles,
+		AllowSelfReview:   policy.AllowSelfReview,
+		Status:            KnowledgeReviewSubmissionPending,
+		SubmittedBy:       submittedBy,
+	}
+	if policy.ID != "" {
+		req.PolicyID = &policy.ID
+	}
+	if req.ReviewerIDs == nil {
+		req.ReviewerIDs = []string{}
+	}
+	if req.ReviewerRoles == nil {
+		req.ReviewerRoles = []string{}
+	}
+	return req
+}
+
+// CanReview 判断用户是否可以审核该提交
+// 未指定审核人和角色时，任意用户均可审核
+func (r *KnowledgeReviewSubmission) CanReview(userID string, role UserRole) bool {
+	if userID == "" {
+		return false
+	}
+	if userID == r.SubmittedBy && !r.AllowSelfReview {
+		return false
+	}
+	if len(r.ReviewerIDs) == 0 && len(r.ReviewerRoles) == 0 {
+		return true
+	}
+	for _, id := range r.ReviewerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	for _, reviewerRole := range r.ReviewerRoles {
+		if UserRole(reviewerRole) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPending 是否待审核
+func (r *KnowledgeReviewSubmission) IsPending() bool {
+	return r.Status == KnowledgeReviewSubmissionPending
+}
+
+// KnowledgeReviewRecord 审核人的审核记录
+type KnowledgeReviewRecord struct {
+	ID           string                  `json:"id" db:"id"`
+	SubmissionID string                  `json:"submission_id" db:"submission_id"`
+	ReviewerID   string                  `json:"reviewer_id" db:"reviewer_id"`
+	Decision     KnowledgeReviewDecision `json:"decision" db:"decision"`
+	Comment      *string                 `json:"comment,omitempty" db:"comment"`
+	CreatedAt    time.Time               `json:"created_at" db:"created_at"`
+}
+
+// KnowledgeReviewQueueFilter 待审核队列过滤器
+type KnowledgeReviewQueueFilter struct {
+	ReviewerID   string   `json:"reviewer_id"`
+	ReviewerRole UserRole `json:"reviewer_role"`
+	Page         int      `json:"page"`
+	PageSize     int      `json:"page_size"`
+}
+
+// KnowledgeReviewQueue 待审核队列
+type KnowledgeReviewQueue struct {
+	Submissions []*KnowledgeReviewSubmission `json:"submissions"`
+	Total       int64                        `json:"total"`
+	Page        int                          `json:"page"`
+	PageSize    int                          `json:"page_size"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// KnowledgeImportFormat 知识库导入格式
+type KnowledgeImportFormat string
+
+const (
+	KnowledgeImportFormatMarkdown   KnowledgeImportFormat = "markdown"   // 带 front-matter 的 Markdown 压缩包
+	KnowledgeImportFormatConfluence KnowledgeImportFormat = "confluence" // Confluence 空间 XML 导出
+)
+
+// KnowledgeImportMaxSize 导入文件最大大小
+const KnowledgeImportMaxSize = 100 << 20
+
+// IsValid 检查导入格式是否有效
+func (f KnowledgeImportFormat) IsValid() bool {
+	return f == KnowledgeImportFormatMarkdown || f == KnowledgeImportFormatConfluence
+}
+
+// KnowledgeImportItem 单篇文章的导入结果
+type KnowledgeImportItem struct {
+	Path  string `json:"path"`
+	Title string `json:"title,omitempty"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// KnowledgeImportResult 知识库导入结果
+type KnowledgeImportResult struct {
+	Format            KnowledgeImportFormat  `json:"format"`
+	Total             int                    `json:"total"`
+	Imported          int                    `json:"imported"`
+	Failed            int                    `json:"failed"`
+	CreatedCategories []string               `json:"created_categories"`
+	Items             []*KnowledgeImportItem `json:"items"`
+}
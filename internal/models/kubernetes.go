@@ -0,0 +1,9 @@
+package models
+
+// KubernetesSyncResult Kubernetes 数据源单次采集结果
+type KubernetesSyncResult struct {
+	DataSourceID string `json:"data_source_id"`
+	Findings     int    `json:"findings"`
+	Fired        int    `json:"fired"`
+	Resolved     int    `json:"resolved"`
+}
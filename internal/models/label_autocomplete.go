@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 标签自动补全返回数量
+const (
+	LabelSuggestionDefaultLimit = 20
+	LabelSuggestionMaxLimit     = 200
+)
+
+// LabelSuggestion 一个标签键或标签值，Count 为最近的告警中带有该标签键或值的告警数
+type LabelSuggestion struct {
+	Value string `json:"value" db:"value"`
+	Count int64  `json:"count" db:"count"`
+}
+
+// LabelSuggestionQuery 标签自动补全查询，Prefix 按前缀过滤（不区分大小写）
+type LabelSuggestionQuery struct {
+	Prefix string `form:"prefix"`
+	Limit  int    `form:"limit"`
+}
+
+// Normalize 去掉前缀两端的空白，并将返回数量限制在默认值和上限之间
+func (q *LabelSuggestionQuery) Normalize() error {
+	q.Prefix = strings.TrimSpace(q.Prefix)
+	if len(q.Prefix) > 200 {
+		return fmt.Errorf("%w: 前缀不能超过200个字符", ErrInvalidInput)
+	}
+	if q.Limit <= 0 {
+		q.Limit = LabelSuggestionDefaultLimit
+	}
+	if q.Limit > LabelSuggestionMaxLimit {
+		q.Limit = LabelSuggestionMaxLimit
+	}
+	return nil
+}
+
+// LabelSuggestions 标签自动补全结果，按告警数降序排列
+type LabelSuggestions struct {
+	// Key 查询标签值时为标签键
+	Key string `json:"key,omitempty"`
+	// Window 统计的时间窗口
+	Window string             `json:"window"`
+	Items  []*LabelSuggestion `json:"items"`
+}
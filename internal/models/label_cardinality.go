@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// LabelGuardAnnotation 记录被丢弃或规范化的标签的告警注解，值形如 request_id:dropped,path:normalized
+const LabelGuardAnnotation = "label_guard"
+
+// LabelGuardAction 标签基数超过上限时的处理方式
+type LabelGuardAction string
+
+const (
+	// LabelGuardActionReport 只在基数报告中标记，不修改标签
+	LabelGuardActionReport LabelGuardAction = "report"
+	// LabelGuardActionDrop 丢弃标签
+	LabelGuardActionDrop LabelGuardAction = "drop"
+	// LabelGuardActionNormalize 将标签值中的数字、UUID、十六进制串和 IP 替换为占位符
+	LabelGuardActionNormalize LabelGuardAction = "normalize"
+)
+
+// IsValid 检查处理方式是否有效
+func (a LabelGuardAction) IsValid() bool {
+	switch a {
+	case LabelGuardActionReport, LabelGuardActionDrop, LabelGuardActionNormalize:
+		return true
+	}
+	return false
+}
+
+// LabelCardinalityKey 当前统计窗口内一个标签键在本实例上的基数
+type LabelCardinalityKey struct {
+	Key string `json:"key"`
+	// DistinctValues 不同值的数量，超过上限后停止计数，此时为上限加一
+	DistinctValues int  `json:"distinct_values"`
+	Exceeded       bool `json:"exceeded"`
+	// Alerts 带有该标签的告警数
+	Alerts int64 `json:"alerts"`
+	// Action 对该标签生效的处理方式，未处理时为空
+	Action LabelGuardAction `json:"action,omitempty"`
+	// Guarded 被丢弃或规范化的告警数
+	Guarded int64    `json:"guarded"`
+	Samples []string `json:"samples"`
+}
+
+// LabelCardinalityStat 已存储告警中一个标签键的基数
+type LabelCardinalityStat struct {
+	Key            string `json:"key" db:"key"`
+	DistinctValues int64  `json:"distinct_values" db:"distinct_values"`
+	Alerts         int64  `json:"alerts" db:"alerts"`
+}
+
+// LabelCardinalityReport 标签基数报告。Keys 为本实例在当前窗口内观察到的入站告警标签，
+// Stored 为窗口内创建并已存储的全部告警的标签，两者均按不同值数量降序取前 N 个
+type LabelCardinalityReport struct {
+	WindowStart time.Time              `json:"window_start"`
+	Window      string                 `json:"window"`
+	Limit       int                    `json:"limit"`
+	Action      LabelGuardAction       `json:"action"`
+	Keys        []*LabelCardinalityKey `json:"keys"`
+	// UntrackedKeys 跟踪的标签键数量达到上限后未跟踪的标签键出现次数
+	UntrackedKeys int64                   `json:"untracked_keys"`
+	Stored        []*LabelCardinalityStat `json:"stored"`
+}
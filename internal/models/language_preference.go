@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LanguageScope 语言偏好的作用范围
+type LanguageScope string
+
+const (
+	LanguageScopeUser LanguageScope = "user" // 用户
+	LanguageScopeTeam LanguageScope = "team" // 团队，与告警的 team 标签对应
+)
+
+// LanguagePreference 用户或团队的语言偏好
+type LanguagePreference struct {
+	Scope LanguageScope `json:"scope" db:"scope"`
+	// Subject 用户ID或团队名
+	Subject   string    `json:"subject" db:"subject"`
+	Language  string    `json:"language" db:"language"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LanguagePreferenceRequest 设置语言偏好请求
+type LanguagePreferenceRequest struct {
+	Language string `json:"language"`
+}
+
+// Validate 验证语言偏好请求，语言是否受支持由已加载的消息目录决定
+func (r *LanguagePreferenceRequest) Validate() error {
+	r.Language = strings.TrimSpace(r.Language)
+	if r.Language == "" || len(r.Language) > 20 {
+		return fmt.Errorf("%w: 语言不能为空且不能超过20个字符", ErrInvalidInput)
+	}
+	return nil
+}
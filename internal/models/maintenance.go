@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// IngestBufferTopic 维护期间暂存告警接入请求的队列主题
+const IngestBufferTopic = "ingest.buffered"
+
+// 暂存的告警接入请求类型
+const (
+	BufferedIngestAlert  = "alert"  // 标准告警接入 /api/v1/ingest/:id/alerts
+	BufferedIngestCustom = "custom" // 自定义 Webhook /api/v1/webhooks/custom/:integrationID
+)
+
+// BufferedIngest 维护期间暂存的告警接入请求，维护结束后按接收时间校验签名并处理
+type BufferedIngest struct {
+	Kind          string    `json:"kind"`
+	IntegrationID string    `json:"integration_id"`
+	ClientIP      string    `json:"client_ip"`
+	Signature     string    `json:"signature,omitempty"`
+	Timestamp     string    `json:"timestamp,omitempty"`
+	Token         string    `json:"token,omitempty"`
+	Body          []byte    `json:"body"`
+	ReceivedAt    time.Time `json:"received_at"`
+}
+
+// MaintenanceModeRequest 开启维护模式请求
+type MaintenanceModeRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricSeries 内置指标存储中的时间序列，由指标名和标签唯一确定
+type MetricSeries struct {
+	ID     int64             `json:"id" db:"id"`
+	Metric string            `json:"metric" db:"metric"`
+	Labels map[string]string `json:"labels" db:"labels"`
+	// Fingerprint 指标名和排序后的标签的哈希，用于写入时查找已有的序列
+	Fingerprint string    `json:"-" db:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+
+	// Samples 写入时为待写入的样本，查询时为最新的样本
+	Samples []MetricSample `json:"samples,omitempty" db:"-"`
+}
+
+// MetricSample 时间序列的样本
+type MetricSample struct {
+	SeriesID  int64     `json:"-" db:"series_id"`
+	Timestamp time.Time `json:"timestamp" db:"ts"`
+	Value     float64   `json:"value" db:"value"`
+}
+
+// MetricFingerprint 计算时间序列的指纹，标签按名称排序，与标签顺序无关
+func MetricFingerprint(metric string, labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(metric))
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RemoteWriteResult 远程写入的处理结果
+type RemoteWriteResult struct {
+	Series  int `json:"series"`
+	Samples int `json:"samples"`
+	// Forwarded 样本已转发到配置的后端，未在本地存储
+	Forwarded bool `json:"forwarded"`
+}
+
+// MetricMatchOp 标签匹配方式
+type MetricMatchOp string
+
+const (
+	MetricMatchEqual     MetricMatchOp = "="  // 等于
+	MetricMatchNotEqual  MetricMatchOp = "!=" // 不等于
+	MetricMatchRegexp    MetricMatchOp = "=~" // 正则匹配
+	MetricMatchNotRegexp MetricMatchOp = "!~" // 正则不匹配
+)
+
+// MetricMatcher 标签匹配条件，标签不存在时按空字符串匹配
+type MetricMatcher struct {
+	Name  string        `json:"name"`
+	Op    MetricMatchOp `json:"op"`
+	Value string        `json:"value"`
+}
+
+// MetricAggregation 多个序列的聚合方式
+type MetricAggregation string
+
+const (
+	MetricAggregationSum   MetricAggregation = "sum"
+	MetricAggregationAvg   MetricAggregation = "avg"
+	MetricAggregationMin   MetricAggregation = "min"
+	MetricAggregationMax   MetricAggregation = "max"
+	MetricAggregationCount MetricAggregation = "count"
+)
+
+// MetricQuery 内置指标存储支持的即时查询，语法为 PromQL 的子集：
+// 序列选择器 metric{label="value",...}，可用 sum、avg、min、max、count 聚合为单个值
+type MetricQuery struct {
+	Aggregation MetricAggregation `json:"aggregation,omitempty"`
+	Metric      string            `json:"metric"`
+	Matchers    []MetricMatcher   `json:"matchers,omitempty"`
+}
+
+var (
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*`)
+)
+
+// ParseMetricQuery 解析即时查询表达式，如 max(node_load1{job="node",instance=~"web-.*"})
+func ParseMetricQuery(expr string) (*MetricQuery, error) {
+	s := strings.TrimSpace(expr)
+	query := &MetricQuery{}
+
+	if open := strings.Index(s, "("); open > 0 {
+		agg := MetricAggregation(strings.TrimSpace(s[:open]))
+		switch agg {
+		case MetricAggregationSum, MetricAggregationAvg, MetricAggregationMin, MetricAggregationMax, MetricAggregationCount:
+		default:
+			return nil, fmt.Errorf("%w: 不支持的聚合函数 %s", ErrInvalidInput, agg)
+		}
+		if !strings.HasSuffix(s, ")") {
+			return nil, fmt.Errorf("%w: 聚合函数缺少右括号", ErrInvalidInput)
+		}
+		query.Aggregation = agg
+		s = strings.TrimSpace(s[open+1 : len(s)-1])
+	}
+
+	query.Metric = metricNamePattern.FindString(s)
+	if query.Metric == "" {
+		return nil, fmt.Errorf("%w: 查询表达式缺少指标名", ErrInvalidInput)
+	}
+	s = strings.TrimSpace(s[len(query.Metric):])
+	if s == "" {
+		return query, nil
+	}
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("%w: 无法解析的查询表达式 %q", ErrInvalidInput, expr)
+	}
+
+	matchers, err := parseMetricMatchers(s[1 : len(s)-1])
+	if err != nil {
+		return nil, err
+	}
+	query.Matchers = matchers
+	return query, nil
+}
+
+// parseMetricMatchers 解析花括号内逗号分隔的标签匹配条件
+func parseMetricMatchers(s string) ([]MetricMatcher, error) {
+	var matchers []MetricMatcher
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return matchers, nil
+		}
+
+		var m MetricMatcher
+		m.Name = labelNamePattern.FindString(s)
+		if m.Name == "" {
+			return nil, fmt.Errorf("%w: 无效的标签名 %q", ErrInvalidInput, s)
+		}
+		s = strings.TrimSpace(s[len(m.Name):])
+
+		for _, op := range []MetricMatchOp{MetricMatchRegexp, MetricMatchNotRegexp, MetricMatchNotEqual, MetricMatchEqual} {
+			if strings.HasPrefix(s, string(op)) {
+				m.Op = op
+				break
+			}
+		}
+		if m.Op == "" {
+			return nil, fmt.Errorf("%w: 标签 %s 缺少匹配操作符", ErrInvalidInput, m.Name)
+		}
+		s = strings.TrimSpace(s[len(m.Op):])
+
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 标签 %s 的值须用引号括起", ErrInvalidInput, m.Name)
+		}
+		if m.Value, err = strconv.Unquote(quoted); err != nil {
+			return nil, fmt.Errorf("%w: 标签 %s 的值无效", ErrInvalidInput, m.Name)
+		}
+		if m.Op == MetricMatchRegexp || m.Op == MetricMatchNotRegexp {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return nil, fmt.Errorf("%w: 标签 %s 的正则表达式无效: %v", ErrInvalidInput, m.Name, err)
+			}
+		}
+		matchers = append(matchers, m)
+
+		s = strings.TrimSpace(s[len(quoted):])
+		if s != "" {
+			if s[0] != ',' {
+				return nil, fmt.Errorf("%w: 标签匹配条件之间须用逗号分隔", ErrInvalidInput)
+			}
+			s = s[1:]
+		}
+	}
+}
+
+// Aggregate 按查询的聚合方式计算各序列最新样本的值，未聚合的查询须只匹配一个序列
+func (q *MetricQuery) Aggregate(values []float64) (float64, error) {
+	if q.Aggregation == MetricAggregationCount {
+		return float64(len(values)), nil
+	}
+	if len(values) == 0 {
+		return 0, ErrMetricNoData
+	}
+
+	switch q.Aggregation {
+	case "":
+		if len(values) > 1 {
+			return 0, fmt.Errorf("%w: 查询返回 %d 个序列，需要聚合为单个序列", ErrInvalidInput, len(values))
+		}
+		return values[0], nil
+	case MetricAggregationSum, MetricAggregationAvg:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		if q.Aggregation == MetricAggregationAvg {
+			return sum / float64(len(values)), nil
+		}
+		return sum, nil
+	case MetricAggregationMin, MetricAggregationMax:
+		result := values[0]
+		for _, v := range values[1:] {
+			if (q.Aggregation == MetricAggregationMin && v < result) || (q.Aggregation == MetricAggregationMax && v > result) {
+				result = v
+			}
+		}
+		return result, nil
+	}
+	return 0, fmt.Errorf("%w: 不支持的聚合函数 %s", ErrInvalidInput, q.Aggregation)
+}
+
+// MetricQueryResult 即时查询的结果
+type MetricQueryResult struct {
+	Query  string          `json:"query"`
+	Time   time.Time       `json:"time"`
+	Value  float64         `json:"value"`
+	Series []*MetricSeries `json:"series"`
+}
@@ -26,6 +26,7 @@ const (
 	NotificationTypeWeChat   NotificationType = "wechat"
 	NotificationTypeSlack    NotificationType = "slack"
 	NotificationTypeWebhook  NotificationType = "webhook"
+	NotificationTypePush     NotificationType = "push"
 )
 
 // Notification 通知记录
@@ -43,6 +44,13 @@ type Notification struct {
 	SentAt      *time.Time         `json:"sent_at,omitempty" db:"sent_at"`
 	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+
+	// MessageKey 多语言消息标识，设置后发送时按接收人的语言渲染 <MessageKey>.subject 作为标题，
+	// Content 为空时渲染 <MessageKey>.content 作为内容
+	MessageKey  string                 `json:"-" db:"-"`
+	MessageData map[string]interface{} `json:"-" db:"-"`
+	// Team 没有关联告警的团队通知按该团队的语言渲染 MessageKey
+	Team string `json:"-" db:"-"`
 }
 
 // NotificationTemplate 通知模板
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+	"unicode/utf8"
+)
+
+// NotificationProviderResponseMaxLength 投递记录中保存的渠道响应的最大长度
+const NotificationProviderResponseMaxLength = 1024
+
+// NotificationAttemptStatus 通知投递结果
+type NotificationAttemptStatus string
+
+const (
+	NotificationAttemptSent   NotificationAttemptStatus = "sent"   // 渠道已接受
+	NotificationAttemptFailed NotificationAttemptStatus = "failed" // 投递失败
+)
+
+// NotificationAttempt 通知的一次投递记录，每次发送和重发都会追加一条，用于排查"没有收到通知"的问题
+type NotificationAttempt struct {
+	ID             string           `json:"id" db:"id"`
+	NotificationID string           `json:"notification_id" db:"notification_id"`
+	AlertID        *string          `json:"alert_id,omitempty" db:"alert_id"`
+	Channel        NotificationType `json:"channel" db:"channel"`
+	// Target 投递目标，Webhook 地址中的密码已脱敏
+	Target string `json:"target" db:"target"`
+	// PayloadHash 投递内容的 SHA-256，用于核对重发的内容是否与原通知一致
+	PayloadHash string                    `json:"payload_hash" db:"payload_hash"`
+	Status      NotificationAttemptStatus `json:"status" db:"status"`
+	// ProviderResponse 渠道返回的响应，如 Webhook 的状态码和响应体，超出长度时截断
+	ProviderResponse *string `json:"provider_response,omitempty" db:"provider_response"`
+	Error            *string `json:"error,omitempty" db:"error"`
+	DurationMs       int64   `json:"duration_ms" db:"duration_ms"`
+	// ResentBy 手动重发的用户，自动发送时为空
+	ResentBy    *string   `json:"resent_by,omitempty" db:"resent_by"`
+	AttemptedAt time.Time `json:"attempted_at" db:"attempted_at"`
+}
+
+// NotificationDelivery 通知及其全部投递记录，投递记录按时间正序排列
+type NotificationDelivery struct {
+	Notification *Notification          `json:"notification"`
+	Attempts     []*NotificationAttempt `json:"attempts"`
+}
+
+// NotificationPayloadHash 计算通知投递内容的 SHA-256
+func NotificationPayloadHash(notification *Notification) string {
+	hash := sha256.New()
+	for _, part := range []string{string(notification.Type), notification.Recipient, notification.Subject, notification.Content} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// TruncateProviderResponse 截断渠道响应，避免投递记录保存过大的响应体
+func TruncateProviderResponse(response string) string {
+	if len(response) <= NotificationProviderResponseMaxLength {
+		return response
+	}
+	// 在 UTF-8 字符边界处截断
+	end := NotificationProviderResponseMaxLength
+	for end > 0 && !utf8.RuneStart(response[end]) {
+		end--
+	}
+	return response[:end] + "..."
+}
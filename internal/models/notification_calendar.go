@@ -0,0 +1,296 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// RegionLabel 标记告警所在地区的标签名，与 TeamLabel 一起用于选择通知日历
+	RegionLabel = "region"
+	// holidayLayout 节假日日期格式
+	holidayLayout = "2006-01-02"
+	// businessClockLayout 工作时间的时刻格式
+	businessClockLayout = "15:04"
+)
+
+// BusinessHours 工作时间，工单 SLA 的 business_hours 使用相同的结构
+type BusinessHours struct {
+	// Timezone IANA 时区名，为空时使用 UTC
+	Timezone string `json:"timezone"`
+	// Days 工作日，如 monday、tuesday
+	Days  []string `json:"days"`
+	Start string   `json:"start"` // 开始时刻，如 09:00
+	End   string   `json:"end"`   // 结束时刻，如 18:00
+}
+
+// Validate 验证工作时间
+func (h *BusinessHours) Validate() error {
+	if _, err := h.location(); err != nil {
+		return fmt.Errorf("%w: 无效的时区 %q", ErrInvalidInput, h.Timezone)
+	}
+	if len(h.Days) == 0 {
+		return fmt.Errorf("%w: 工作日不能为空", ErrInvalidInput)
+	}
+	for _, day := range h.Days {
+		if _, ok := parseWeekday(day); !ok {
+			return fmt.Errorf("%w: 无效的工作日 %q", ErrInvalidInput, day)
+		}
+	}
+	start, err := time.Parse(businessClockLayout, h.Start)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的开始时刻 %q，格式应为 HH:MM", ErrInvalidInput, h.Start)
+	}
+	end, err := time.Parse(businessClockLayout, h.End)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的结束时刻 %q，格式应为 HH:MM", ErrInvalidInput, h.End)
+	}
+	if !start.Before(end) {
+		return fmt.Errorf("%w: 开始时刻必须早于结束时刻", ErrInvalidInput)
+	}
+	return nil
+}
+
+// location 工作时间所在时区
+func (h *BusinessHours) location() (*time.Location, error) {
+	if h.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(h.Timezone)
+}
+
+// isWorkday 判断星期几是否为工作日
+func (h *BusinessHours) isWorkday(weekday time.Weekday) bool {
+	for _, day := range h.Days {
+		if d, ok := parseWeekday(day); ok && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeekday 解析星期名，不区分大小写
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// BusinessCalendar 工作日历，由工作时间和节假日组成
+type BusinessCalendar struct {
+	BusinessHours BusinessHours `json:"business_hours"`
+	// Holidays 节假日，格式为 YYYY-MM-DD，节假日全天为非工作时间
+	Holidays []string `json:"holidays"`
+}
+
+// Validate 验证工作日历
+func (c *BusinessCalendar) Validate() error {
+	if err := c.BusinessHours.Validate(); err != nil {
+		return err
+	}
+	for _, holiday := range c.Holidays {
+		if _, err := time.Parse(holidayLayout, holiday); err != nil {
+			return fmt.Errorf("%w: 无效的节假日 %q，格式应为 YYYY-MM-DD", ErrInvalidInput, holiday)
+		}
+	}
+	return nil
+}
+
+// isHoliday 判断日期是否为节假日
+func (c *BusinessCalendar) isHoliday(date string) bool {
+	for _, holiday := range c.Holidays {
+		if holiday == date {
+			return true
+		}
+	}
+	return false
+}
+
+// openAt 返回 day 所在日期的工作时间，非工作日或节假日时 ok 为 false
+func (c *BusinessCalendar) openAt(day time.Time) (start, end time.Time, ok bool) {
+	if !c.BusinessHours.isWorkday(day.Weekday()) || c.isHoliday(day.Format(holidayLayout)) {
+		return start, end, false
+	}
+	startClock, err := time.Parse(businessClockLayout, c.BusinessHours.Start)
+	if err != nil {
+		return start, end, false
+	}
+	endClock, err := time.Parse(businessClockLayout, c.BusinessHours.End)
+	if err != nil {
+		return start, end, false
+	}
+	year, month, date := day.Date()
+	start = time.Date(year, month, date, startClock.Hour(), startClock.Minute(), 0, 0, day.Location())
+	end = time.Date(year, month, date, endClock.Hour(), endClock.Minute(), 0, 0, day.Location())
+	return start, end, true
+}
+
+// InBusinessHours 判断时间是否在工作时间内，时区无效时视为工作时间
+func (c *BusinessCalendar) InBusinessHours(t time.Time) bool {
+	loc, err := c.BusinessHours.location()
+	if err != nil {
+		return true
+	}
+	local := t.In(loc)
+	start, end, ok := c.openAt(local)
+	return ok && !local.Before(start) && local.Before(end)
+}
+
+// NextBusinessStart 返回 t 之后最近的工作时间开始时刻，t 在工作时间内时返回 t；
+// 一年内没有工作时间时返回 t
+func (c *BusinessCalendar) NextBusinessStart(t time.Time) time.Time {
+	if c.InBusinessHours(t) {
+		return t
+	}
+	loc, err := c.BusinessHours.location()
+	if err != nil {
+		return t
+	}
+	local := t.In(loc)
+	for i := 0; i <= 366; i++ {
+		start, _, ok := c.openAt(local.AddDate(0, 0, i))
+		if ok && start.After(local) {
+			return start
+		}
+	}
+	return t
+}
+
+// BusinessCalendar 解析 SLA 的工作时间和节假日，未配置工作时间时 ok 为 false
+func (s *TicketSLA) BusinessCalendar() (calendar *BusinessCalendar, ok bool, err error) {
+	if len(s.BusinessHours) == 0 {
+		return nil, false, nil
+	}
+	data, err := json.Marshal(s.BusinessHours)
+	if err != nil {
+		return nil, false, fmt.Errorf("序列化工作时间失败: %w", err)
+	}
+	calendar = &BusinessCalendar{Holidays: s.Holidays}
+	if err := json.Unmarshal(data, &calendar.BusinessHours); err != nil {
+		return nil, false, fmt.Errorf("%w: 工作时间格式错误: %v", ErrInvalidInput, err)
+	}
+	if err := calendar.Validate(); err != nil {
+		return nil, false, err
+	}
+	return calendar, true, nil
+}
+
+// NotificationCalendar 通知日历，非工作时间内指定级别告警的通知延迟到下一个工作时间开始时汇总发送
+type NotificationCalendar struct {
+	ID     string `json:"id" db:"id"`
+	Name   string `json:"name" db:"name"`
+	Team   string `json:"team" db:"team"`     // 匹配告警的 team 标签，为空时匹配任意团队
+	Region string `json:"region" db:"region"` // 匹配告警的 region 标签，为空时匹配任意地区
+	BusinessCalendar
+	// DeferSeverities 非工作时间延迟通知的告警级别，严重告警始终立即通知
+	DeferSeverities []AlertSeverity `json:"defer_severities" db:"defer_severities"`
+	CreatedBy       string          `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Match 返回日历对告警标签的匹配程度，团队匹配计 2 分、地区匹配计 1 分；不匹配时返回 -1
+func (c *NotificationCalendar) Match(labels map[string]string) int {
+	score := 0
+	if c.Team != "" {
+		if labels[TeamLabel] != c.Team {
+			return -1
+		}
+		score += 2
+	}
+	if c.Region != "" {
+		if labels[RegionLabel] != c.Region {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// ShouldDefer 判断 now 时指定级别告警的通知是否延迟
+func (c *NotificationCalendar) ShouldDefer(severity AlertSeverity, now time.Time) bool {
+	if severity == AlertSeverityCritical {
+		return false
+	}
+	for _, s := range c.DeferSeverities {
+		if s == severity {
+			return !c.InBusinessHours(now)
+		}
+	}
+	return false
+}
+
+// NotificationCalendarRequest 创建或更新通知日历请求
+type NotificationCalendarRequest struct {
+	Name   string `json:"name"`
+	Team   string `json:"team"`
+	Region string `json:"region"`
+	BusinessCalendar
+	DeferSeverities []AlertSeverity `json:"defer_severities"`
+}
+
+// Validate 验证通知日历请求
+func (r *NotificationCalendarRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" || len(r.Name) > 100 {
+		return fmt.Errorf("%w: 日历名称不能为空且不能超过100个字符", ErrInvalidInput)
+	}
+	if len(r.Team) > 100 || len(r.Region) > 100 {
+		return fmt.Errorf("%w: 团队和地区不能超过100个字符", ErrInvalidInput)
+	}
+	if err := r.BusinessCalendar.Validate(); err != nil {
+		return err
+	}
+	for _, severity := range r.DeferSeverities {
+		if !severity.IsValid() {
+			return fmt.Errorf("%w: 无效的告警级别 %q", ErrInvalidInput, severity)
+		}
+		if severity == AlertSeverityCritical {
+			return fmt.Errorf("%w: 严重告警始终立即通知，不能延迟", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求应用到通知日历
+func (r *NotificationCalendarRequest) ApplyTo(c *NotificationCalendar) {
+	c.Name = strings.TrimSpace(r.Name)
+	c.Team = strings.TrimSpace(r.Team)
+	c.Region = strings.TrimSpace(r.Region)
+	c.BusinessCalendar = r.BusinessCalendar
+	if c.Holidays == nil {
+		c.Holidays = []string{}
+	}
+	c.DeferSeverities = r.DeferSeverities
+	if c.DeferSeverities == nil {
+		c.DeferSeverities = []AlertSeverity{}
+	}
+}
+
+// NewNotificationCalendarRequest 由通知日历生成请求，用于声明式配置的导出和对比
+func NewNotificationCalendarRequest(c *NotificationCalendar) NotificationCalendarRequest {
+	return NotificationCalendarRequest{
+		Name:             c.Name,
+		Team:             c.Team,
+		Region:           c.Region,
+		BusinessCalendar: c.BusinessCalendar,
+		DeferSeverities:  c.DeferSeverities,
+	}
+}
+
+// DeferredNotification 延迟到工作时间汇总发送的通知
+type DeferredNotification struct {
+	ID         string           `json:"id" db:"id"`
+	CalendarID string           `json:"calendar_id" db:"calendar_id"`
+	AlertID    *string          `json:"alert_id,omitempty" db:"alert_id"`
+	Type       NotificationType `json:"type" db:"type"`
+	Recipient  string           `json:"recipient" db:"recipient"`
+	Subject    string           `json:"subject" db:"subject"`
+	Content    string           `json:"content" db:"content"`
+	DeliverAt  time.Time        `json:"deliver_at" db:"deliver_at"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NotificationChannelTypes 团队可以自行配置的通知渠道类型
+var NotificationChannelTypes = []NotificationType{
+	NotificationTypeEmail,
+	NotificationTypeDingTalk,
+	NotificationTypeWeChat,
+	NotificationTypeSlack,
+	NotificationTypeWebhook,
+}
+
+// NotificationChannelSMTP 邮件渠道的 SMTP 服务器，登录密码保存在渠道的 Secret 中
+type NotificationChannelSMTP struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	From     string `json:"from"`
+	TLS      bool   `json:"tls"`
+}
+
+// NotificationChannel 团队的通知渠道，团队告警的通知优先使用团队启用的同类型渠道，没有时使用配置文件中的全局渠道。
+// 机器人和 Webhook 地址通常包含访问令牌，与钉钉加签密钥、SMTP 密码一样加密存储，查询时不返回
+type NotificationChannel struct {
+	ID   string           `json:"id" db:"id"`
+	Name string           `json:"name" db:"name"`
+	Team string           `json:"team" db:"team"`
+	Type NotificationType `json:"type" db:"type"`
+	// URL 钉钉、企业微信、Slack 机器人地址或 Webhook 地址
+	URL string `json:"-" db:"url"`
+	// Secret 钉钉机器人的加签密钥或 SMTP 登录密码
+	Secret string                   `json:"-" db:"secret"`
+	SMTP   *NotificationChannelSMTP `json:"smtp,omitempty" db:"smtp"`
+	// URLHost 地址的主机名，便于识别渠道而不暴露地址中的令牌
+	URLHost   string    `json:"url_host,omitempty" db:"-"`
+	HasSecret bool      `json:"has_secret" db:"-"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedBy *string   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate 验证通知渠道，邮件渠道需要 SMTP 服务器和发件人，其他渠道需要 HTTP 地址
+func (c *NotificationChannel) Validate() error {
+	if c.Name == "" || len(c.Name) > 200 {
+		return fmt.Errorf("%w: 渠道名称不能为空且不能超过200个字符", ErrInvalidInput)
+	}
+	if c.Team == "" || len(c.Team) > 100 {
+		return fmt.Errorf("%w: 团队不能为空且不能超过100个字符", ErrInvalidInput)
+	}
+	if !IsNotificationChannelType(c.Type) {
+		return fmt.Errorf("%w: 不支持的渠道类型 %s", ErrInvalidInput, c.Type)
+	}
+
+	if c.Type == NotificationTypeEmail {
+		if c.SMTP == nil || c.SMTP.Host == "" {
+			return fmt.Errorf("%w: 邮件渠道需要配置 SMTP 服务器", ErrInvalidInput)
+		}
+		if c.SMTP.Port < 0 || c.SMTP.Port > 65535 {
+			return fmt.Errorf("%w: 无效的 SMTP 端口 %d", ErrInvalidInput, c.SMTP.Port)
+		}
+		if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+			return fmt.Errorf("%w: 无效的发件人 %q", ErrInvalidInput, c.SMTP.From)
+		}
+		return nil
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s 渠道需要有效的 HTTP 地址", ErrInvalidInput, c.Type)
+	}
+	return nil
+}
+
+// Redact 清除渠道的地址和密钥，只保留地址的主机名和是否配置了密钥
+func (c *NotificationChannel) Redact() {
+	c.URLHost = ""
+	if u, err := url.Parse(c.URL); err == nil {
+		c.URLHost = u.Hostname()
+	}
+	c.HasSecret = c.Secret != ""
+	c.URL, c.Secret = "", ""
+}
+
+// IsNotificationChannelType 检查团队是否可以配置该类型的通知渠道
+func IsNotificationChannelType(t NotificationType) bool {
+	for _, channelType := range NotificationChannelTypes {
+		if t == channelType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannelRequest 创建或更新团队通知渠道请求。更新时 URL 和 Secret 为空表示保持不变，
+// ClearSecret 为 true 时清除已保存的密钥
+type NotificationChannelRequest struct {
+	Name        string                   `json:"name" binding:"required,max=200"`
+	Team        string                   `json:"team" binding:"required,max=100"`
+	Type        NotificationType         `json:"type" binding:"required"`
+	URL         string                   `json:"url"`
+	Secret      string                   `json:"secret"`
+	ClearSecret bool                     `json:"clear_secret"`
+	SMTP        *NotificationChannelSMTP `json:"smtp,omitempty"`
+	Enabled     *bool                    `json:"enabled,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到渠道
+func (r *NotificationChannelRequest) ApplyTo(c *NotificationChannel) {
+	c.Name = strings.TrimSpace(r.Name)
+	c.Team = strings.TrimSpace(r.Team)
+	c.Type = r.Type
+	if u := strings.TrimSpace(r.URL); u != "" {
+		c.URL = u
+	}
+	switch {
+	case r.ClearSecret:
+		c.Secret = ""
+	case r.Secret != "":
+		c.Secret = r.Secret
+	}
+	c.SMTP = nil
+	if r.SMTP != nil {
+		smtp := *r.SMTP
+		smtp.Host = strings.TrimSpace(smtp.Host)
+		smtp.From = strings.TrimSpace(smtp.From)
+		c.SMTP = &smtp
+	}
+	if c.Type == NotificationTypeEmail {
+		c.URL = ""
+	}
+	if r.Enabled != nil {
+		c.Enabled = *r.Enabled
+	}
+}
+
+// NotificationChannelFilter 团队通知渠道过滤器
+type NotificationChannelFilter struct {
+	Team string           `form:"team"`
+	Type NotificationType `form:"type"`
+}
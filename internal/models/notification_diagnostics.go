@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// ChannelTestStep 通知渠道测试的检查步骤
+type ChannelTestStep string
+
+const (
+	ChannelTestStepDNS      ChannelTestStep = "dns"      // 解析渠道地址
+	ChannelTestStepTLS      ChannelTestStep = "tls"      // TLS 握手和证书校验
+	ChannelTestStepAuth     ChannelTestStep = "auth"     // 渠道凭据或签名校验
+	ChannelTestStepProvider ChannelTestStep = "provider" // 渠道接受测试消息
+)
+
+// ChannelTestStatus 检查步骤的结果
+type ChannelTestStatus string
+
+const (
+	ChannelTestOK      ChannelTestStatus = "ok"
+	ChannelTestFailed  ChannelTestStatus = "failed"
+	ChannelTestSkipped ChannelTestStatus = "skipped" // 渠道不涉及该步骤，或前面的步骤失败
+)
+
+// ChannelTestRequest 通知渠道测试请求
+type ChannelTestRequest struct {
+	// Recipient 测试消息的接收者，钉钉、企业微信和 Slack 为空时使用配置的机器人地址
+	Recipient string `json:"recipient"`
+}
+
+// ChannelTestCheck 通知渠道测试的一个检查步骤
+type ChannelTestCheck struct {
+	Step       ChannelTestStep   `json:"step"`
+	Status     ChannelTestStatus `json:"status"`
+	Detail     string            `json:"detail,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+}
+
+// ChannelTestResult 通知渠道测试结果，按 DNS、TLS、认证、渠道响应的顺序列出各步骤的检查结果
+type ChannelTestResult struct {
+	Channel   NotificationType `json:"channel"`
+	Recipient string           `json:"recipient"`
+	// TestMode 渠道是否处于测试模式，测试模式下的普通通知只记录投递、不实际发送
+	TestMode bool                `json:"test_mode"`
+	Success  bool                `json:"success"`
+	Checks   []*ChannelTestCheck `json:"checks"`
+	// Response 渠道返回的响应，超出长度时截断
+	Response string    `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	TestedAt time.Time `json:"tested_at"`
+}
+
+// Check 获取指定步骤的检查结果，没有该步骤时返回 nil
+func (r *ChannelTestResult) Check(step ChannelTestStep) *ChannelTestCheck {
+	for _, check := range r.Checks {
+		if check.Step == step {
+			return check
+		}
+	}
+	return nil
+}
+
+// IsNotificationType 判断是否为支持的通知类型
+func IsNotificationType(t NotificationType) bool {
+	switch t {
+	case NotificationTypeEmail, NotificationTypeSMS, NotificationTypeDingTalk, NotificationTypeWeChat,
+		NotificationTypeSlack, NotificationTypeWebhook, NotificationTypePush:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 按 created_at 按月分区的表，月分区名为 <表名>_pYYYYMM，默认分区为 <表名>_default
+const (
+	PartitionedTableAlerts         = "alerts"
+	PartitionedTableAlertHistories = "alert_histories"
+)
+
+// partitionMonthLayout 月分区名中的月份格式
+const partitionMonthLayout = "200601"
+
+// TablePartition 表的分区，月分区包含 [From, To) 范围内创建的记录
+type TablePartition struct {
+	Table   string    `json:"table"`
+	Name    string    `json:"name"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Default bool      `json:"default"` // 默认分区，保存超出月分区范围的记录
+}
+
+// MonthlyPartition 返回 t 所在月份的分区
+func MonthlyPartition(table string, t time.Time) *TablePartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return &TablePartition{
+		Table: table,
+		Name:  table + "_p" + from.Format(partitionMonthLayout),
+		From:  from,
+		To:    from.AddDate(0, 1, 0),
+	}
+}
+
+// ParseTablePartition 根据分区名解析分区，不是按月分区命名的分区返回 false
+func ParseTablePartition(table, name string) (*TablePartition, bool) {
+	if name == table+"_default" {
+		return &TablePartition{Table: table, Name: name, Default: true}, true
+	}
+	month, ok := strings.CutPrefix(name, table+"_p")
+	if !ok {
+		return nil, false
+	}
+	from, err := time.Parse(partitionMonthLayout, month)
+	if err != nil {
+		return nil, false
+	}
+	return MonthlyPartition(table, from), true
+}
+
+// PartitionMaintenanceResult 分区维护结果
+type PartitionMaintenanceResult struct {
+	Created []string `json:"created"` // 新建的月分区
+	Dropped []string `json:"dropped"` // 删除的过期月分区
+	// Purged 过期分区仍有未解决告警时保留分区，只删除其中已解决和已删除的告警，为删除的告警数量
+	Purged int64 `json:"purged"`
+}
@@ -0,0 +1,327 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PostmortemStatus 复盘状态
+type PostmortemStatus string
+
+const (
+	PostmortemStatusDraft     PostmortemStatus = "draft"     // 草稿
+	PostmortemStatusInReview  PostmortemStatus = "in_review" // 评审中
+	PostmortemStatusPublished PostmortemStatus = "published" // 已发布
+)
+
+// IsValid 检查复盘状态是否有效
+func (s PostmortemStatus) IsValid() bool {
+	switch s {
+	case PostmortemStatusDraft, PostmortemStatusInReview, PostmortemStatusPublished:
+		return true
+	default:
+		return false
+	}
+}
+
+// PostmortemActionItemStatus 复盘改进项状态
+type PostmortemActionItemStatus string
+
+const (
+	PostmortemActionItemOpen      PostmortemActionItemStatus = "open"      // 进行中
+	PostmortemActionItemDone      PostmortemActionItemStatus = "done"      // 已完成
+	PostmortemActionItemCancelled PostmortemActionItemStatus = "cancelled" // 已取消
+)
+
+// IsValid 检查改进项状态是否有效
+func (s PostmortemActionItemStatus) IsValid() bool {
+	switch s {
+	case PostmortemActionItemOpen, PostmortemActionItemDone, PostmortemActionItemCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ActionItemStatusFromTicket 根据跟踪工单状态推导改进项状态
+func ActionItemStatusFromTicket(status TicketStatus) PostmortemActionItemStatus {
+	switch status {
+	case TicketStatusResolved, TicketStatusClosed:
+		return PostmortemActionItemDone
+	case TicketStatusCancelled:
+		return PostmortemActionItemCancelled
+	default:
+		return PostmortemActionItemOpen
+	}
+}
+
+// Postmortem 事件复盘
+type Postmortem struct {
+	ID                string                    `json:"id" db:"id"`
+	Title             string                    `json:"title" db:"title"`
+	Status            PostmortemStatus          `json:"status" db:"status"`
+	Template          string                    `json:"template" db:"template"`
+	IncidentID        *string                   `json:"incident_id,omitempty" db:"incident_id"`
+	AlertIDs          []string                  `json:"alert_ids" db:"alert_ids"`
+	Summary           string                    `json:"summary" db:"summary"`
+	Impact            string                    `json:"impact" db:"impact"`
+	RootCause         string                    `json:"root_cause" db:"root_cause"`
+	Timeline          []PostmortemTimelineEntry `json:"timeline" db:"timeline"`
+	IncidentStartedAt *time.Time                `json:"incident_started_at,omitempty" db:"incident_started_at"`
+	IncidentEndedAt   *time.Time                `json:"incident_ended_at,omitempty" db:"incident_ended_at"`
+	KnowledgeID       *string                   `json:"knowledge_id,omitempty" db:"knowledge_id"`
+	PublishedAt       *time.Time                `json:"published_at,omitempty" db:"published_at"`
+	CreatedBy         string                    `json:"created_by" db:"created_by"`
+	UpdatedBy         *string                   `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt         time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time                 `json:"updated_at" db:"updated_at"`
+
+	// 详情接口返回的改进项及完成进度
+	ActionItems []*PostmortemActionItem `json:"action_items,omitempty" db:"-"`
+	Progress    *PostmortemProgress     `json:"progress,omitempty" db:"-"`
+}
+
+// PostmortemTimelineEntry 复盘时间线条目
+type PostmortemTimelineEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Description string    `json:"description"`
+	Source      string    `json:"source,omitempty"` // manual, alert, ticket
+}
+
+// PostmortemActionItem 复盘改进项，创建时生成跟踪工单，完成情况随工单状态同步
+type PostmortemActionItem struct {
+	ID           string                     `json:"id" db:"id"`
+	PostmortemID string                     `json:"postmortem_id" db:"postmortem_id"`
+	Title        string                     `json:"title" db:"title"`
+	Description  string                     `json:"description" db:"description"`
+	Priority     TicketPriority             `json:"priority" db:"priority"`
+	AssigneeID   *string                    `json:"assignee_id,omitempty" db:"assignee_id"`
+	DueDate      *time.Time                 `json:"due_date,omitempty" db:"due_date"`
+	TicketID     *string                    `json:"ticket_id,omitempty" db:"ticket_id"`
+	Status       PostmortemActionItemStatus `json:"status" db:"status"`
+	CompletedAt  *time.Time                 `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt    time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time                  `json:"updated_at" db:"updated_at"`
+}
+
+// PostmortemProgress 改进项完成进度，已取消的改进项不计入
+type PostmortemProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Percent   int `json:"percent"`
+}
+
+// NewPostmortemProgress 统计改进项完成进度
+func NewPostmortemProgress(items []*PostmortemActionItem) *PostmortemProgress {
+	progress := &PostmortemProgress{}
+	for _, item := range items {
+		switch item.Status {
+		case PostmortemActionItemCancelled:
+			continue
+		case PostmortemActionItemDone:
+			progress.Completed++
+		}
+		progress.Total++
+	}
+	if progress.Total > 0 {
+		progress.Percent = progress.Completed * 100 / progress.Total
+	}
+	return progress
+}
+
+// PostmortemTemplate 复盘模板，创建复盘时预填各章节的提纲
+type PostmortemTemplate struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Summary     string `json:"summary"`
+	Impact      string `json:"impact"`
+	RootCause   string `json:"root_cause"`
+}
+
+// DefaultPostmortemTemplate 未指定模板时使用的模板
+const DefaultPostmortemTemplate = "standard"
+
+// postmortemTemplates 内置复盘模板
+var postmortemTemplates = []PostmortemTemplate{
+	{
+		Key:         "standard",
+		Name:        "标准复盘",
+		Description: "适用于大多数生产事件",
+		Summary:     "## 事件概述\n\n- 发生了什么：\n- 如何发现：\n- 如何恢复：\n",
+		Impact:      "## 影响范围\n\n- 受影响的服务：\n- 受影响的用户/租户：\n- 持续时间：\n- 业务损失：\n",
+		RootCause:   "## 根因分析\n\n- 直接原因：\n- 根本原因（5 Whys）：\n- 为什么没有更早发现：\n",
+	},
+	{
+		Key:         "security",
+		Name:        "安全事件复盘",
+		Description: "适用于数据泄露、入侵等安全事件",
+		Summary:     "## 事件概述\n\n- 攻击/泄露路径：\n- 发现方式：\n- 处置措施：\n",
+		Impact:      "## 影响范围\n\n- 涉及的数据类型和数量：\n- 涉及的系统和账号：\n- 是否需要对外通报：\n",
+		RootCause:   "## 根因分析\n\n- 被利用的漏洞或配置缺陷：\n- 防护措施失效原因：\n",
+	},
+	{
+		Key:         "brief",
+		Name:        "简要复盘",
+		Description: "适用于影响较小、原因明确的事件",
+		Summary:     "## 概述\n\n",
+		Impact:      "## 影响\n\n",
+		RootCause:   "## 原因\n\n",
+	},
+}
+
+// PostmortemTemplates 返回所有内置复盘模板
+func PostmortemTemplates() []PostmortemTemplate {
+	templates := make([]PostmortemTemplate, len(postmortemTemplates))
+	copy(templates, postmortemTemplates)
+	return templates
+}
+
+// GetPostmortemTemplate 根据键获取内置复盘模板
+func GetPostmortemTemplate(key string) (PostmortemTemplate, bool) {
+	for _, template := range postmortemTemplates {
+		if template.Key == key {
+			return template, true
+		}
+	}
+	return PostmortemTemplate{}, false
+}
+
+// PostmortemCreateRequest 创建复盘请求，未填写的章节使用模板提纲
+type PostmortemCreateRequest struct {
+	Title             string                    `json:"title" binding:"required,min=1,max=200"`
+	Template          string                    `json:"template,omitempty"`
+	IncidentID        *string                   `json:"incident_id,omitempty"`
+	AlertIDs          []string                  `json:"alert_ids,omitempty" binding:"omitempty,max=100"`
+	Summary           *string                   `json:"summary,omitempty"`
+	Impact            *string                   `json:"impact,omitempty"`
+	RootCause         *string                   `json:"root_cause,omitempty"`
+	Timeline          []PostmortemTimelineEntry `json:"timeline,omitempty"`
+	IncidentStartedAt *time.Time                `json:"incident_started_at,omitempty"`
+	IncidentEndedAt   *time.Time                `json:"incident_ended_at,omitempty"`
+}
+
+// PostmortemUpdateRequest 更新复盘请求，未提供的字段保持不变
+type PostmortemUpdateRequest struct {
+	Title             *string                   `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
+	Status            *PostmortemStatus         `json:"status,omitempty"`
+	AlertIDs          []string                  `json:"alert_ids,omitempty" binding:"omitempty,max=100"`
+	Summary           *string                   `json:"summary,omitempty"`
+	Impact            *string                   `json:"impact,omitempty"`
+	RootCause         *string                   `json:"root_cause,omitempty"`
+	Timeline          []PostmortemTimelineEntry `json:"timeline,omitempty"`
+	IncidentStartedAt *time.Time                `json:"incident_started_at,omitempty"`
+	IncidentEndedAt   *time.Time                `json:"incident_ended_at,omitempty"`
+}
+
+// ApplyTo 将更新内容应用到复盘
+func (r *PostmortemUpdateRequest) ApplyTo(p *Postmortem) {
+	if r.Title != nil {
+		p.Title = strings.TrimSpace(*r.Title)
+	}
+	if r.Status != nil {
+		p.Status = *r.Status
+	}
+	if r.AlertIDs != nil {
+		p.AlertIDs = r.AlertIDs
+	}
+	if r.Summary != nil {
+		p.Summary = *r.Summary
+	}
+	if r.Impact != nil {
+		p.Impact = *r.Impact
+	}
+	if r.RootCause != nil {
+		p.RootCause = *r.RootCause
+	}
+	if r.Timeline != nil {
+		p.Timeline = r.Timeline
+	}
+	if r.IncidentStartedAt != nil {
+		p.IncidentStartedAt = r.IncidentStartedAt
+	}
+	if r.IncidentEndedAt != nil {
+		p.IncidentEndedAt = r.IncidentEndedAt
+	}
+}
+
+// Validate 验证复盘
+func (p *Postmortem) Validate() error {
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("%w: 复盘标题不能为空", ErrInvalidInput)
+	}
+	if !p.Status.IsValid() {
+		return fmt.Errorf("%w: 无效的复盘状态", ErrInvalidInput)
+	}
+	if _, ok := GetPostmortemTemplate(p.Template); !ok {
+		return fmt.Errorf("%w: 复盘模板不存在", ErrInvalidInput)
+	}
+	if p.IncidentStartedAt != nil && p.IncidentEndedAt != nil && p.IncidentEndedAt.Before(*p.IncidentStartedAt) {
+		return fmt.Errorf("%w: 事件结束时间不能早于开始时间", ErrInvalidInput)
+	}
+	return nil
+}
+
+// PostmortemActionItemRequest 创建或更新复盘改进项请求
+type PostmortemActionItemRequest struct {
+	Title       *string                     `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
+	Description *string                     `json:"description,omitempty"`
+	Priority    *TicketPriority             `json:"priority,omitempty" binding:"omitempty,enum"`
+	AssigneeID  *string                     `json:"assignee_id,omitempty"`
+	DueDate     *time.Time                  `json:"due_date,omitempty"`
+	Status      *PostmortemActionItemStatus `json:"status,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到改进项
+func (r *PostmortemActionItemRequest) ApplyTo(item *PostmortemActionItem) {
+	if r.Title != nil {
+		item.Title = strings.TrimSpace(*r.Title)
+	}
+	if r.Description != nil {
+		item.Description = *r.Description
+	}
+	if r.Priority != nil {
+		item.Priority = *r.Priority
+	}
+	if r.AssigneeID != nil {
+		item.AssigneeID = r.AssigneeID
+	}
+	if r.DueDate != nil {
+		item.DueDate = r.DueDate
+	}
+	if r.Status != nil {
+		item.Status = *r.Status
+	}
+}
+
+// Validate 验证改进项
+func (item *PostmortemActionItem) Validate() error {
+	if strings.TrimSpace(item.Title) == "" {
+		return fmt.Errorf("%w: 改进项标题不能为空", ErrInvalidInput)
+	}
+	if !item.Priority.IsValid() {
+		return fmt.Errorf("%w: 无效的改进项优先级", ErrInvalidInput)
+	}
+	if !item.Status.IsValid() {
+		return fmt.Errorf("%w: 无效的改进项状态", ErrInvalidInput)
+	}
+	return nil
+}
+
+// PostmortemFilter 复盘查询过滤器
+type PostmortemFilter struct {
+	Status     *PostmortemStatus `json:"status,omitempty"`
+	IncidentID *string           `json:"incident_id,omitempty"`
+	AlertID    *string           `json:"alert_id,omitempty"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+}
+
+// PostmortemList 复盘列表
+type PostmortemList struct {
+	Postmortems []*Postmortem `json:"postmortems"`
+	Total       int64         `json:"total"`
+	Page        int           `json:"page"`
+	PageSize    int           `json:"page_size"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PushPlatform 移动端推送平台
+type PushPlatform string
+
+const (
+	PushPlatformFCM  PushPlatform = "fcm"  // Firebase Cloud Messaging，Android 和接入 FCM 的 iOS 应用
+	PushPlatformAPNs PushPlatform = "apns" // Apple Push Notification service
+)
+
+// IsValid 检查推送平台是否有效
+func (p PushPlatform) IsValid() bool {
+	switch p {
+	case PushPlatformFCM, PushPlatformAPNs:
+		return true
+	default:
+		return false
+	}
+}
+
+// PushDevice 用户注册的推送设备，同一设备令牌只属于最后注册它的用户
+type PushDevice struct {
+	ID       string       `json:"id" db:"id"`
+	UserID   string       `json:"user_id" db:"user_id"`
+	Platform PushPlatform `json:"platform" db:"platform"`
+	// Token 推送服务签发的设备令牌，不在接口中返回
+	Token      string    `json:"-" db:"token"`
+	Name       string    `json:"name" db:"name"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// PushDeviceRequest 注册推送设备请求，应用每次启动或令牌更新时调用
+type PushDeviceRequest struct {
+	Platform PushPlatform `json:"platform" binding:"required"`
+	Token    string       `json:"token" binding:"required,max=1024"`
+	Name     string       `json:"name" binding:"max=100"`
+}
+
+// Validate 验证注册推送设备请求
+func (req *PushDeviceRequest) Validate() error {
+	if !req.Platform.IsValid() {
+		return fmt.Errorf("%w: 不支持的推送平台 %s", ErrInvalidInput, req.Platform)
+	}
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" {
+		return fmt.Errorf("%w: 设备令牌不能为空", ErrInvalidInput)
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	return nil
+}
+
+// PushPreference 用户的推送偏好，只推送所选级别的告警
+type PushPreference struct {
+	UserID     string          `json:"user_id" db:"user_id"`
+	Severities []AlertSeverity `json:"severities" db:"severities"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Allows 检查偏好是否推送该级别的告警
+func (p *PushPreference) Allows(severity AlertSeverity) bool {
+	for _, s := range p.Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// PushPreferenceRequest 设置推送偏好请求，级别为空时不推送任何告警
+type PushPreferenceRequest struct {
+	Severities []AlertSeverity `json:"severities"`
+}
+
+// Validate 验证推送偏好请求并去除重复的级别
+func (req *PushPreferenceRequest) Validate() error {
+	seen := make(map[AlertSeverity]bool, len(req.Severities))
+	severities := make([]AlertSeverity, 0, len(req.Severities))
+	for _, severity := range req.Severities {
+		if !severity.IsValid() {
+			return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, severity)
+		}
+		if !seen[severity] {
+			seen[severity] = true
+			severities = append(severities, severity)
+		}
+	}
+	req.Severities = severities
+	return nil
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxQuietHoursWindows 每个用户最多配置的免打扰时段数
+const maxQuietHoursWindows = 14
+
+// QuietHoursWindow 免打扰时段，结束时刻早于开始时刻时跨越午夜，Days 为时段开始的日期是星期几
+type QuietHoursWindow struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"` // 开始时刻，如 23:00
+	End   string   `json:"end"`   // 结束时刻，如 07:00
+	// AllowSeverities 时段内仍然通知的告警级别，为空时时段内不发送任何通知
+	AllowSeverities []AlertSeverity `json:"allow_severities"`
+}
+
+// Validate 验证免打扰时段
+func (w *QuietHoursWindow) Validate() error {
+	if len(w.Days) == 0 {
+		return fmt.Errorf("%w: 免打扰时段的星期不能为空", ErrInvalidInput)
+	}
+	for _, day := range w.Days {
+		if _, ok := parseWeekday(day); !ok {
+			return fmt.Errorf("%w: 无效的星期 %q", ErrInvalidInput, day)
+		}
+	}
+	start, err := time.Parse(businessClockLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的开始时刻 %q，格式应为 HH:MM", ErrInvalidInput, w.Start)
+	}
+	end, err := time.Parse(businessClockLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的结束时刻 %q，格式应为 HH:MM", ErrInvalidInput, w.End)
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("%w: 开始时刻和结束时刻不能相同", ErrInvalidInput)
+	}
+	return validateAllowSeverities(w.AllowSeverities)
+}
+
+// contains 判断本地时间是否在免打扰时段内
+func (w *QuietHoursWindow) contains(local time.Time) bool {
+	start, err := time.Parse(businessClockLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(businessClockLayout, w.End)
+	if err != nil {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	if startMinute < endMinute {
+		return w.onDay(local.Weekday()) && minute >= startMinute && minute < endMinute
+	}
+	// 跨越午夜的时段，午夜之后的部分属于前一天开始的时段
+	if minute >= startMinute {
+		return w.onDay(local.Weekday())
+	}
+	return minute < endMinute && w.onDay((local.Weekday()+6)%7)
+}
+
+// onDay 判断时段是否在星期几开始
+func (w *QuietHoursWindow) onDay(weekday time.Weekday) bool {
+	for _, day := range w.Days {
+		if d, ok := parseWeekday(day); ok && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// QuietHours 用户的免打扰设置，由每周的免打扰时段和临时开启的免打扰组成
+type QuietHours struct {
+	UserID string `json:"user_id" db:"user_id"`
+	// Timezone IANA 时区名，为空时使用 UTC
+	Timezone string             `json:"timezone" db:"timezone"`
+	Windows  []QuietHoursWindow `json:"windows" db:"windows"`
+	// DNDUntil 临时免打扰的截止时间，期间只通知 DNDAllowSeverities 中级别的告警
+	DNDUntil           *time.Time      `json:"dnd_until,omitempty" db:"dnd_until"`
+	DNDAllowSeverities []AlertSeverity `json:"dnd_allow_severities" db:"dnd_allow_severities"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Suppresses 判断 now 时是否不向用户发送该级别告警的通知，severity 为空表示与告警无关的通知。
+// 临时免打扰和所在的免打扰时段都允许该级别时才发送，时区无效时只按临时免打扰判断
+func (q *QuietHours) Suppresses(severity AlertSeverity, now time.Time) bool {
+	if q.DNDUntil != nil && now.Before(*q.DNDUntil) && !severityAllowed(q.DNDAllowSeverities, severity) {
+		return true
+	}
+
+	loc, err := (&BusinessHours{Timezone: q.Timezone}).location()
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	for i := range q.Windows {
+		if q.Windows[i].contains(local) && !severityAllowed(q.Windows[i].AllowSeverities, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// severityAllowed 判断告警级别是否在允许的级别中
+func severityAllowed(allowed []AlertSeverity, severity AlertSeverity) bool {
+	for _, s := range allowed {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAllowSeverities 验证免打扰期间允许通知的告警级别
+func validateAllowSeverities(severities []AlertSeverity) error {
+	for _, severity := range severities {
+		if !severity.IsValid() {
+			return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, severity)
+		}
+	}
+	return nil
+}
+
+// QuietHoursRequest 设置每周免打扰时段请求
+type QuietHoursRequest struct {
+	Timezone string             `json:"timezone"`
+	Windows  []QuietHoursWindow `json:"windows"`
+}
+
+// Validate 验证设置免打扰时段请求
+func (r *QuietHoursRequest) Validate() error {
+	r.Timezone = strings.TrimSpace(r.Timezone)
+	if _, err := (&BusinessHours{Timezone: r.Timezone}).location(); err != nil {
+		return fmt.Errorf("%w: 无效的时区 %q", ErrInvalidInput, r.Timezone)
+	}
+	if len(r.Windows) > maxQuietHoursWindows {
+		return fmt.Errorf("%w: 免打扰时段不能超过 %d 个", ErrInvalidInput, maxQuietHoursWindows)
+	}
+	for i := range r.Windows {
+		if err := r.Windows[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DNDRequest 临时开启免打扰请求
+type DNDRequest struct {
+	// Minutes 免打扰持续的分钟数，最长 7 天
+	Minutes         int             `json:"minutes" binding:"required,min=1,max=10080"`
+	AllowSeverities []AlertSeverity `json:"allow_severities"`
+}
+
+// Validate 验证临时开启免打扰请求
+func (r *DNDRequest) Validate() error {
+	if r.Minutes <= 0 {
+		return fmt.Errorf("%w: 免打扰时长必须大于 0", ErrInvalidInput)
+	}
+	return validateAllowSeverities(r.AllowSeverities)
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 修复动作类型
+const (
+	RemediationActionHTTP = "http" // 调用 HTTP 接口
+	RemediationActionAWX  = "awx"  // 启动 Ansible Tower/AWX 作业模板
+	RemediationActionSalt = "salt" // 通过 salt-api 执行 SaltStack 命令
+)
+
+// IsRemediation 检查动作是否为修复动作
+func (a *RuleAction) IsRemediation() bool {
+	switch a.Type {
+	case RemediationActionHTTP, RemediationActionAWX, RemediationActionSalt:
+		return true
+	default:
+		return false
+	}
+}
+
+// DisplayName 动作展示名称，未命名时使用类型和目标
+func (a *RuleAction) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Type + ":" + a.Target
+}
+
+// StringParameter 获取字符串参数
+func (a *RuleAction) StringParameter(key string) string {
+	value, ok := a.Parameters[key].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// validateRemediation 验证修复动作的目标和参数
+func (a *RuleAction) validateRemediation() error {
+	switch a.Type {
+	case RemediationActionHTTP:
+		u, err := url.Parse(a.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("HTTP 修复动作的目标必须是 http 或 https 地址")
+		}
+		if method := a.StringParameter("method"); method != "" {
+			switch strings.ToUpper(method) {
+			case "GET", "POST", "PUT", "PATCH", "DELETE":
+			default:
+				return errors.New("HTTP 修复动作的请求方法无效")
+			}
+		}
+	case RemediationActionAWX:
+		if _, err := strconv.Atoi(a.Target); err != nil {
+			return errors.New("AWX 修复动作的目标必须是作业模板ID")
+		}
+	case RemediationActionSalt:
+		if a.StringParameter("function") == "" {
+			return errors.New("Salt 修复动作必须指定执行函数 function")
+		}
+	}
+	return nil
+}
+
+// RemediationStatus 修复动作执行状态
+type RemediationStatus string
+
+const (
+	RemediationStatusPendingApproval RemediationStatus = "pending_approval" // 等待审批
+	RemediationStatusQueued          RemediationStatus = "queued"           // 等待执行
+	RemediationStatusRunning         RemediationStatus = "running"          // 执行中
+	RemediationStatusSucceeded       RemediationStatus = "succeeded"        // 执行成功
+	RemediationStatusFailed          RemediationStatus = "failed"           // 执行失败
+	RemediationStatusRejected        RemediationStatus = "rejected"         // 审批拒绝
+)
+
+// IsFinal 检查执行状态是否为终态
+func (s RemediationStatus) IsFinal() bool {
+	switch s {
+	case RemediationStatusSucceeded, RemediationStatusFailed, RemediationStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// 修复动作执行结果在告警历史中的动作名称
+const (
+	AlertHistoryActionRemediationSucceeded = "remediation_succeeded"
+	AlertHistoryActionRemediationFailed    = "remediation_failed"
+	AlertHistoryActionRemediationRejected  = "remediation_rejected"
+)
+
+// RemediationExecution 修复动作执行记录，动作配置在安排时快照保存，规则修改不影响已安排的执行
+type RemediationExecution struct {
+	ID          string            `json:"id" db:"id"`
+	AlertID     string            `json:"alert_id" db:"alert_id"`
+	RuleID      string            `json:"rule_id" db:"rule_id"`
+	ActionIndex int               `json:"action_index" db:"action_index"`
+	ActionName  string            `json:"action_name" db:"action_name"`
+	ActionType  string            `json:"action_type" db:"action_type"`
+	Action      RuleAction        `json:"action" db:"action"`
+	Status      RemediationStatus `json:"status" db:"status"`
+	Output      string            `json:"output" db:"output"`
+	Error       *string           `json:"error,omitempty" db:"error"`
+	ApprovedBy  *string           `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt  *time.Time        `json:"approved_at,omitempty" db:"approved_at"`
+	StartedAt   *time.Time        `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty" db:"finished_at"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// RemediationResult 修复动作执行结果
+type RemediationResult struct {
+	Output string `json:"output"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionResource 适用数据保留策略的资源，与表名相同
+type RetentionResource string
+
+const (
+	RetentionResourceAlerts         RetentionResource = "alerts"          // 告警，只清理已解决和已删除的告警
+	RetentionResourceAlertHistories RetentionResource = "alert_histories" // 告警历史
+	RetentionResourceNotifications  RetentionResource = "notifications"   // 通知记录，只清理已发送和发送失败的通知
+)
+
+// RetentionResources 归档任务依次处理的资源
+var RetentionResources = []RetentionResource{
+	RetentionResourceAlerts, RetentionResourceAlertHistories, RetentionResourceNotifications,
+}
+
+// Valid 判断是否为支持的资源
+func (r RetentionResource) Valid() bool {
+	for _, resource := range RetentionResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// RetentionPolicy 资源的保留策略，RetentionDays 为 0 表示不清理
+type RetentionPolicy struct {
+	Resource       RetentionResource `json:"resource"`
+	RetentionDays  int               `json:"retention_days"`
+	ArchiveEnabled bool              `json:"archive_enabled"`
+}
+
+// Cutoff 早于该时间的记录已超出保留期
+func (p *RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -p.RetentionDays)
+}
+
+// RetentionStatus 资源的保留状态
+type RetentionStatus struct {
+	RetentionPolicy
+	Cutoff *time.Time `json:"cutoff,omitempty"`
+	// ExpiredRecords 已超出保留期、等待归档清理的记录数
+	ExpiredRecords int64      `json:"expired_records"`
+	OldestRecordAt *time.Time `json:"oldest_record_at,omitempty"`
+	Archives       int64      `json:"archives"`
+	// ArchivedRecords 已归档的记录数
+	ArchivedRecords int64      `json:"archived_records"`
+	LastArchivedAt  *time.Time `json:"last_archived_at,omitempty"`
+}
+
+// ArchiveRecord 待归档的记录，Data 为整行的 JSON
+type ArchiveRecord struct {
+	ID        string    `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	Data      []byte    `db:"data"`
+}
+
+// DataArchive 保存在对象存储中的归档文件，包含创建时间在 WindowStart 和 WindowEnd 之间（含）的记录
+type DataArchive struct {
+	ID          string            `json:"id" db:"id"`
+	Resource    RetentionResource `json:"resource" db:"resource"`
+	ObjectKey   string            `json:"object_key" db:"object_key"`
+	WindowStart time.Time         `json:"window_start" db:"window_start"`
+	WindowEnd   time.Time         `json:"window_end" db:"window_end"`
+	RecordCount int64             `json:"record_count" db:"record_count"`
+	SizeBytes   int64             `json:"size_bytes" db:"size_bytes"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+}
+
+// DataArchiveFilter 归档文件过滤条件，From 和 To 筛选与时间窗口有交集的归档
+type DataArchiveFilter struct {
+	Resource *RetentionResource `json:"resource,omitempty"`
+	From     *time.Time         `json:"from,omitempty"`
+	To       *time.Time         `json:"to,omitempty"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// DataArchiveRestore 从归档恢复的时间窗口，ExpiresAt 之前窗口内的记录不会被重新归档
+type DataArchiveRestore struct {
+	ID              string            `json:"id" db:"id"`
+	Resource        RetentionResource `json:"resource" db:"resource"`
+	WindowStart     time.Time         `json:"window_start" db:"window_start"`
+	WindowEnd       time.Time         `json:"window_end" db:"window_end"`
+	Archives        int               `json:"archives" db:"archives"`
+	RestoredRecords int64             `json:"restored_records" db:"restored_records"`
+	ExpiresAt       time.Time         `json:"expires_at" db:"expires_at"`
+	CreatedBy       string            `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+}
+
+// DataArchiveRestoreRequest 恢复归档请求，恢复创建时间在 [From, To) 内的记录
+type DataArchiveRestoreRequest struct {
+	Resource RetentionResource `json:"resource"`
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+}
+
+// Validate 验证恢复归档请求
+func (r *DataArchiveRestoreRequest) Validate() error {
+	if !r.Resource.Valid() {
+		return fmt.Errorf("%w: 不支持的资源 %q", ErrInvalidInput, r.Resource)
+	}
+	if r.From.IsZero() || r.To.IsZero() || !r.From.Before(r.To) {
+		return fmt.Errorf("%w: 恢复时间窗口无效，from 必须早于 to", ErrInvalidInput)
+	}
+	return nil
+}
+
+// RetentionRunResult 一次归档清理的结果
+type RetentionRunResult struct {
+	Archives int   `json:"archives"`
+	Archived int64 `json:"archived"`
+	Deleted  int64 `json:"deleted"`
+}
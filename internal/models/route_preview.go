@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RouteStep 路由试运行经过的步骤
+type RouteStep string
+
+const (
+	RouteStepService     RouteStep = "service"               // 按服务目录归属服务
+	RouteStepTeam        RouteStep = "team"                  // 确定负责团队
+	RouteStepAssignment  RouteStep = "assignment_policy"     // 按团队的分派策略选择接收人
+	RouteStepCalendar    RouteStep = "notification_calendar" // 按通知日历判断是否延迟通知
+	RouteStepDeduplicate RouteStep = "deduplicate"           // 按指纹合并到已有告警
+)
+
+// RoutePreviewRequest 路由试运行请求，按样例告警预览当前配置下的通知路由，不创建告警也不发送通知
+type RoutePreviewRequest struct {
+	Name     string            `json:"name" binding:"required"`
+	Severity AlertSeverity     `json:"severity" binding:"required"`
+	Labels   map[string]string `json:"labels"`
+	// DataSourceID 和 Expression 与告警名称一起生成告警指纹，用于判断样例告警是否会合并到已有告警
+	DataSourceID string `json:"data_source_id,omitempty"`
+	Expression   string `json:"expression,omitempty"`
+}
+
+// Validate 验证路由试运行请求
+func (r *RoutePreviewRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("%w: 告警名称不能为空", ErrInvalidInput)
+	}
+	if !r.Severity.IsValid() {
+		return fmt.Errorf("%w: 无效的告警级别 %s", ErrInvalidInput, r.Severity)
+	}
+	return nil
+}
+
+// RoutePreviewStep 路由经过的一个步骤，Matched 表示该步骤是否匹配到配置，Detail 说明匹配结果
+type RoutePreviewStep struct {
+	Step    RouteStep `json:"step"`
+	Matched bool      `json:"matched"`
+	Detail  string    `json:"detail"`
+}
+
+// RoutePreview 样例告警的路由试运行结果，说明告警会通知谁以及没有通知的原因
+type RoutePreview struct {
+	// Labels 按服务目录归属后的告警标签
+	Labels   map[string]string  `json:"labels"`
+	Route    []RoutePreviewStep `json:"route"`
+	Service  string             `json:"service,omitempty"`
+	Team     string             `json:"team,omitempty"`
+	Strategy AssignmentStrategy `json:"strategy,omitempty"`
+	// Calendar 匹配的通知日历名称
+	Calendar  string                `json:"calendar,omitempty"`
+	Receivers []AlertReplayReceiver `json:"receivers"`
+	Unrouted  AlertReplayUnrouted   `json:"unrouted,omitempty"`
+	// GroupKey 告警指纹，指纹相同的告警合并为同一个告警，ExistingAlert 为相同指纹的已有告警
+	GroupKey      string `json:"group_key"`
+	ExistingAlert *Alert `json:"existing_alert,omitempty"`
+	// Silence 已有告警的静默规则，静默期间不会重新通知
+	Silence *AlertSilence `json:"silence,omitempty"`
+	// Snoozes 接收人暂停了匹配样例告警的通知
+	Snoozes     []*AlertSnooze `json:"snoozes"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
@@ -51,10 +51,12 @@ type RuleCondition struct {
 
 // RuleAction 规则动作
 type RuleAction struct {
-	Type       string                 `json:"type"`       // 动作类型: alert, webhook, email, sms
-	Target     string                 `json:"target"`     // 目标地址
+	Type       string                 `json:"type"`       // 动作类型: alert, webhook, email, sms, http, awx, salt
+	Name       string                 `json:"name,omitempty"`       // 动作名称，用于执行记录展示
+	Target     string                 `json:"target"`     // 目标地址，修复动作为 URL、AWX 作业模板ID或 Salt 目标
 	Template   *string                `json:"template,omitempty"`   // 模板
 	Parameters map[string]interface{} `json:"parameters,omitempty"` // 参数
+	RequireApproval bool              `json:"require_approval,omitempty"` // 修复动作是否需要人工审批后执行
 }
 
 // Rule 规则模型
@@ -95,15 +97,15 @@ type RuleCreateRequest struct {
 	DataSourceID       string            `json:"data_source_id" binding:"required"`
 	Name               string            `json:"name" binding:"required,min=1,max=200"`
 	Description        string            `json:"description" binding:"required,min=1,max=1000"`
-	Type               RuleType          `json:"type" binding:"required"`
-	Severity           AlertSeverity     `json:"severity" binding:"required"`
+	Type               RuleType          `json:"type" binding:"required,enum"`
+	Severity           AlertSeverity     `json:"severity" binding:"required,enum"`
 	Expression         string            `json:"expression" binding:"required"`
 	Conditions         []RuleCondition   `json:"conditions,omitempty"`
 	Actions            []RuleAction      `json:"actions,omitempty"`
-	Labels             map[string]string `json:"labels,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
 	Annotations        map[string]string `json:"annotations,omitempty"`
-	EvaluationInterval time.Duration     `json:"evaluation_interval" binding:"required"`
-	ForDuration        time.Duration     `json:"for_duration"`
+	EvaluationInterval time.Duration     `json:"evaluation_interval" binding:"required,duration=1s"`
+	ForDuration        time.Duration     `json:"for_duration" binding:"omitempty,duration"`
 	Threshold          *float64          `json:"threshold,omitempty"`
 	RecoveryThreshold  *float64          `json:"recovery_threshold,omitempty"`
 	NoDataState        *string           `json:"no_data_state,omitempty"`
@@ -114,16 +116,16 @@ type RuleCreateRequest struct {
 type RuleUpdateRequest struct {
 	Name               *string            `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
 	Description        *string            `json:"description,omitempty" binding:"omitempty,min=1,max=1000"`
-	Type               *RuleType          `json:"type,omitempty"`
-	Status             *RuleStatus        `json:"status,omitempty"`
-	Severity           *AlertSeverity     `json:"severity,omitempty"`
+	Type               *RuleType          `json:"type,omitempty" binding:"omitempty,enum"`
+	Status             *RuleStatus        `json:"status,omitempty" binding:"omitempty,enum"`
+	Severity           *AlertSeverity     `json:"severity,omitempty" binding:"omitempty,enum"`
 	Expression         *string            `json:"expression,omitempty"`
 	Conditions         *[]RuleCondition   `json:"conditions,omitempty"`
 	Actions            *[]RuleAction      `json:"actions,omitempty"`
-	Labels             *map[string]string `json:"labels,omitempty"`
+	Labels             *map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
 	Annotations        *map[string]string `json:"annotations,omitempty"`
-	EvaluationInterval *time.Duration     `json:"evaluation_interval,omitempty"`
-	ForDuration        *time.Duration     `json:"for_duration,omitempty"`
+	EvaluationInterval *time.Duration     `json:"evaluation_interval,omitempty" binding:"omitempty,duration=1s"`
+	ForDuration        *time.Duration     `json:"for_duration,omitempty" binding:"omitempty,duration"`
 	Threshold          *float64           `json:"threshold,omitempty"`
 	RecoveryThreshold  *float64           `json:"recovery_threshold,omitempty"`
 	NoDataState        *string            `json:"no_data_state,omitempty"`
@@ -326,7 +328,8 @@ func (a *RuleAction) Validate() error {
 		return errors.New("动作类型不能为空")
 	}
 	
-	validTypes := []string{"alert", "webhook", "email", "sms", "dingtalk", "wechat", "slack"}
+	validTypes := []string{"alert", "webhook", "email", "sms", "dingtalk", "wechat", "slack",
+		RemediationActionHTTP, RemediationActionAWX, RemediationActionSalt}
 	valid := false
 	for _, validType := range validTypes {
 		if a.Type == validType {
@@ -342,6 +345,10 @@ func (a *RuleAction) Validate() error {
 		return errors.New("目标地址不能为空")
 	}
 	
+	if a.IsRemediation() {
+		return a.validateRemediation()
+	}
+	
 	return nil
 }
 
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// RuleEvaluationLag 规则的评估延迟。计划评估时间为上次评估时间加评估间隔，
+// 评估开始时间晚于计划时间的部分为延迟，延迟达到一个评估间隔即错过了一次评估
+type RuleEvaluationLag struct {
+	RuleID             string        `json:"rule_id" db:"id"`
+	RuleName           string        `json:"rule_name" db:"name"`
+	EvaluationInterval time.Duration `json:"evaluation_interval" db:"evaluation_interval"`
+	// LastEvalAt 和 LastScheduledAt 上次评估的实际开始时间和计划时间，LastDelayMs 为两者的差值
+	LastEvalAt      *time.Time `json:"last_eval_at,omitempty" db:"last_eval_at"`
+	LastScheduledAt *time.Time `json:"last_scheduled_at,omitempty" db:"last_eval_scheduled_at"`
+	LastDelayMs     int64      `json:"last_delay_ms" db:"last_eval_delay_ms"`
+	// MissedEvals 连续错过评估周期的评估次数，按时评估后清零
+	MissedEvals int `json:"missed_evals" db:"missed_evals"`
+	// OverdueMs 当前已超过下次计划评估时间的毫秒数，评估器停止工作时持续增长
+	OverdueMs int64 `json:"overdue_ms" db:"-"`
+}
+
+// SetOverdue 按当前时间计算已超过下次计划评估时间的毫秒数，从未评估或未设置评估间隔时为 0
+func (l *RuleEvaluationLag) SetOverdue(now time.Time) {
+	l.OverdueMs = 0
+	if l.LastEvalAt == nil || l.EvaluationInterval <= 0 {
+		return
+	}
+	if overdue := now.Sub(l.LastEvalAt.Add(l.EvaluationInterval)); overdue > 0 {
+		l.OverdueMs = overdue.Milliseconds()
+	}
+}
+
+// BudgetUsed 上次评估延迟占评估间隔的百分比，评估间隔即每条规则的延迟预算，超过 100 表示错过了评估
+func (l *RuleEvaluationLag) BudgetUsed() float64 {
+	if l.EvaluationInterval <= 0 {
+		return 0
+	}
+	return float64(l.LastDelayMs) * 100 / float64(l.EvaluationInterval.Milliseconds())
+}
+
+// Missing 检查规则是否持续错过评估：连续错过 threshold 次，或已超过计划时间 threshold 个评估间隔仍未评估
+func (l *RuleEvaluationLag) Missing(threshold int) bool {
+	if threshold <= 0 {
+		return false
+	}
+	if l.MissedEvals >= threshold {
+		return true
+	}
+	return l.EvaluationInterval > 0 && l.OverdueMs >= int64(threshold)*l.EvaluationInterval.Milliseconds()
+}
+
+// RuleEvaluationLagReport 启用规则的评估延迟汇总，Rules 按当前逾期时间和上次延迟倒序排列
+type RuleEvaluationLagReport struct {
+	Total int `json:"total"`
+	// Late 上次评估有延迟的规则数，Missed 上次评估错过了评估周期的规则数，Overdue 当前已逾期未评估的规则数
+	Late         int                  `json:"late"`
+	Missed       int                  `json:"missed"`
+	Overdue      int                  `json:"overdue"`
+	MaxDelayMs   int64                `json:"max_delay_ms"`
+	MaxOverdueMs int64                `json:"max_overdue_ms"`
+	Rules        []*RuleEvaluationLag `json:"rules"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+}
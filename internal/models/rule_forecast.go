@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 规则预测相关的注解
+const (
+	// RuleAnnotationForecast 规则的预测方法，为 linear 或 holt_winters 时开启预测
+	RuleAnnotationForecast = "forecast"
+	// RuleAnnotationForecastLookback 预测使用的历史范围，如 6h
+	RuleAnnotationForecastLookback = "forecast_lookback"
+	// RuleAnnotationForecastHorizon 预测范围，预测在该时间内达到阈值时告警，如 24h
+	RuleAnnotationForecastHorizon = "forecast_horizon"
+	// RuleAnnotationForecastSeason holt_winters 的季节周期，如 24h，未配置时只考虑趋势
+	RuleAnnotationForecastSeason = "forecast_season"
+	// RuleAnnotationForecastSeverity 预测告警的级别
+	RuleAnnotationForecastSeverity = "forecast_severity"
+
+	// AlertAnnotationForecastMethod 预测告警使用的预测方法
+	AlertAnnotationForecastMethod = "forecast_method"
+	// AlertAnnotationForecastBreachAt 预测达到阈值的时间
+	AlertAnnotationForecastBreachAt = "forecast_breach_at"
+)
+
+// ForecastMethod 预测方法
+type ForecastMethod string
+
+const (
+	ForecastMethodLinear      ForecastMethod = "linear"       // 最小二乘线性回归
+	ForecastMethodHoltWinters ForecastMethod = "holt_winters" // 指数平滑，配置季节周期时叠加季节分量
+)
+
+// IsValid 检查预测方法是否有效
+func (m ForecastMethod) IsValid() bool {
+	return m == ForecastMethodLinear || m == ForecastMethodHoltWinters
+}
+
+// RuleForecastSpec 规则的预测配置，由规则的注解和阈值确定
+type RuleForecastSpec struct {
+	Method   ForecastMethod
+	Lookback time.Duration
+	Horizon  time.Duration
+	Season   time.Duration
+	Severity AlertSeverity
+	// Threshold 规则阈值，Below 为 true 时表示低于阈值触发（如剩余空间），否则为高于阈值触发（如使用率）
+	Threshold float64
+	Below     bool
+}
+
+// ParseRuleForecast 解析规则的预测配置，规则未配置 forecast 注解时返回 nil。
+// 规则条件中使用 lt 或 lte 时按低于阈值预测，否则按高于阈值预测；未配置的注解使用传入的默认值
+func ParseRuleForecast(rule *Rule, lookback, horizon time.Duration, severity AlertSeverity) (*RuleForecastSpec, error) {
+	method := ForecastMethod(strings.TrimSpace(rule.Annotations[RuleAnnotationForecast]))
+	if method == "" {
+		return nil, nil
+	}
+	if !method.IsValid() {
+		return nil, fmt.Errorf("%w: 无效的预测方法 %s", ErrInvalidInput, method)
+	}
+	if rule.Threshold == nil {
+		return nil, fmt.Errorf("%w: 预测需要规则配置阈值", ErrInvalidInput)
+	}
+
+	spec := &RuleForecastSpec{
+		Method:    method,
+		Lookback:  lookback,
+		Horizon:   horizon,
+		Severity:  severity,
+		Threshold: *rule.Threshold,
+	}
+	for key, target := range map[string]*time.Duration{
+		RuleAnnotationForecastLookback: &spec.Lookback,
+		RuleAnnotationForecastHorizon:  &spec.Horizon,
+		RuleAnnotationForecastSeason:   &spec.Season,
+	} {
+		raw := strings.TrimSpace(rule.Annotations[key])
+		if raw == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(raw)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("%w: 注解 %s 的时长 %q 无效", ErrInvalidInput, key, raw)
+		}
+		*target = duration
+	}
+	if spec.Season > 0 && method != ForecastMethodHoltWinters {
+		return nil, fmt.Errorf("%w: 只有 holt_winters 支持季节周期", ErrInvalidInput)
+	}
+	if raw := strings.TrimSpace(rule.Annotations[RuleAnnotationForecastSeverity]); raw != "" {
+		spec.Severity = AlertSeverity(raw)
+		if !spec.Severity.IsValid() {
+			return nil, fmt.Errorf("%w: 无效的预测告警级别 %s", ErrInvalidInput, raw)
+		}
+	}
+	for _, condition := range rule.Conditions {
+		if condition.Operator == RuleOperatorLT || condition.Operator == RuleOperatorLTE {
+			spec.Below = true
+			break
+		}
+	}
+	return spec, nil
+}
+
+// Breached 检查数值是否已达到阈值
+func (s *RuleForecastSpec) Breached(value float64) bool {
+	if s.Below {
+		return value <= s.Threshold
+	}
+	return value >= s.Threshold
+}
+
+// RuleForecast 规则的一次预测结果
+type RuleForecast struct {
+	RuleID    string         `json:"rule_id"`
+	Method    ForecastMethod `json:"method"`
+	Current   float64        `json:"current"`
+	Threshold float64        `json:"threshold"`
+	Below     bool           `json:"below"`
+	Samples   int            `json:"samples"`
+	// Breached 当前值已达到阈值，由规则本身告警，不再预测
+	Breached bool `json:"breached"`
+	// BreachAt 预测范围内达到阈值的时间，预测范围内不会达到阈值时为空
+	BreachAt    *time.Time `json:"breach_at,omitempty"`
+	Horizon     string     `json:"horizon"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+}
+
+// BreachIn 距离预测达到阈值的时间，预测范围内不会达到阈值时返回 0
+func (f *RuleForecast) BreachIn() time.Duration {
+	if f.BreachAt == nil {
+		return 0
+	}
+	return f.BreachAt.Sub(f.EvaluatedAt)
+}
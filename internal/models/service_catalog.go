@@ -0,0 +1,238 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ServiceLabel 标记告警和工单所属服务的标签名
+	ServiceLabel = "service"
+	// TeamLabel 标记告警和工单负责团队的标签名
+	TeamLabel = "team"
+	// ServiceTicketTagPrefix 工单所属服务的标签前缀，工单的 labels 不持久化，服务归属同时写入 tags 以便统计
+	ServiceTicketTagPrefix = "service:"
+
+	// CatalogServiceMaxDependencies 单个服务允许的最多依赖数
+	CatalogServiceMaxDependencies = 50
+	// CatalogServiceMaxSelectors 单个服务允许的最多标签选择器数
+	CatalogServiceMaxSelectors = 20
+	// CatalogServiceDefaultSLOTarget 未指定时的可用性目标（百分比）
+	CatalogServiceDefaultSLOTarget = 99.9
+)
+
+// catalogServiceNamePattern 服务名称会写入告警标签，只允许字母、数字和 . _ -
+var catalogServiceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// ServiceTier 服务等级，tier1 最重要
+type ServiceTier string
+
+const (
+	ServiceTier1 ServiceTier = "tier1" // 核心业务，故障直接影响用户
+	ServiceTier2 ServiceTier = "tier2" // 重要业务
+	ServiceTier3 ServiceTier = "tier3" // 内部业务
+	ServiceTier4 ServiceTier = "tier4" // 实验或非关键业务
+)
+
+// IsValid 检查服务等级是否有效
+func (t ServiceTier) IsValid() bool {
+	switch t {
+	case ServiceTier1, ServiceTier2, ServiceTier3, ServiceTier4:
+		return true
+	default:
+		return false
+	}
+}
+
+// LabelSelector 标签选择器，所有标签都匹配时选中。值支持 path.Match 通配符，如 api-*
+type LabelSelector map[string]string
+
+// Matches 检查标签是否满足选择器，空选择器不匹配任何标签
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for key, pattern := range s {
+		value, ok := labels[key]
+		if !ok {
+			return false
+		}
+		if matched, err := path.Match(pattern, value); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// validate 检查选择器的通配符是否合法
+func (s LabelSelector) validate() error {
+	if len(s) == 0 {
+		return fmt.Errorf("%w: 标签选择器不能为空", ErrInvalidInput)
+	}
+	for key, pattern := range s {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: 标签 %s 的匹配模式 %q 无效", ErrInvalidInput, key, pattern)
+		}
+	}
+	return nil
+}
+
+// CatalogService 服务目录中的服务，告警和工单按标签选择器归属到服务，
+// 用于按服务统计 SLO 和按负责团队分派
+type CatalogService struct {
+	ID             string          `json:"id" db:"id"`
+	Name           string          `json:"name" db:"name"`
+	Description    string          `json:"description" db:"description"`
+	OwnerTeam      string          `json:"owner_team" db:"owner_team"`
+	Tier           ServiceTier     `json:"tier" db:"tier"`
+	Dependencies   []string        `json:"dependencies" db:"dependencies"`
+	LabelSelectors []LabelSelector `json:"label_selectors" db:"label_selectors"`
+	SLOTarget      float64         `json:"slo_target" db:"slo_target"`
+	CreatedBy      string          `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Match 返回与标签匹配的选择器中标签最多的数量，用于在多个服务匹配时选择最具体的服务；不匹配时返回 0
+func (s *CatalogService) Match(labels map[string]string) int {
+	best := 0
+	for _, selector := range s.LabelSelectors {
+		if len(selector) > best && selector.Matches(labels) {
+			best = len(selector)
+		}
+	}
+	return best
+}
+
+// DependsOn 检查服务是否直接依赖指定服务
+func (s *CatalogService) DependsOn(name string) bool {
+	for _, dependency := range s.Dependencies {
+		if dependency == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CatalogServiceRequest 创建或更新服务请求
+type CatalogServiceRequest struct {
+	Name           string          `json:"name" binding:"required,max=100"`
+	Description    string          `json:"description,omitempty" binding:"max=2000"`
+	OwnerTeam      string          `json:"owner_team" binding:"required,max=100"`
+	Tier           ServiceTier     `json:"tier,omitempty"`
+	Dependencies   []string        `json:"dependencies,omitempty"`
+	LabelSelectors []LabelSelector `json:"label_selectors,omitempty" binding:"omitempty,dive,labels"`
+	SLOTarget      float64         `json:"slo_target,omitempty"`
+}
+
+// Validate 验证服务请求，依赖的服务是否存在和是否成环由服务层检查
+func (r *CatalogServiceRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if !catalogServiceNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: 服务名称只能包含字母、数字和 . _ -，且不超过100个字符", ErrInvalidInput)
+	}
+	if strings.TrimSpace(r.OwnerTeam) == "" {
+		return fmt.Errorf("%w: 负责团队不能为空", ErrInvalidInput)
+	}
+	if r.Tier != "" && !r.Tier.IsValid() {
+		return fmt.Errorf("%w: 无效的服务等级 %s", ErrInvalidInput, r.Tier)
+	}
+	if r.SLOTarget != 0 && (r.SLOTarget <= 0 || r.SLOTarget >= 100) {
+		return fmt.Errorf("%w: 可用性目标必须大于 0 且小于 100", ErrInvalidInput)
+	}
+	if len(r.Dependencies) > CatalogServiceMaxDependencies {
+		return fmt.Errorf("%w: 依赖服务不能超过 %d 个", ErrInvalidInput, CatalogServiceMaxDependencies)
+	}
+	for _, dependency := range r.Dependencies {
+		if strings.TrimSpace(dependency) == name {
+			return fmt.Errorf("%w: 服务不能依赖自身", ErrInvalidInput)
+		}
+	}
+	if len(r.LabelSelectors) > CatalogServiceMaxSelectors {
+		return fmt.Errorf("%w: 标签选择器不能超过 %d 个", ErrInvalidInput, CatalogServiceMaxSelectors)
+	}
+	for _, selector := range r.LabelSelectors {
+		if err := selector.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求内容应用到服务，未指定等级时使用 tier3，未指定可用性目标时使用 99.9
+func (r *CatalogServiceRequest) ApplyTo(s *CatalogService) {
+	s.Name = strings.TrimSpace(r.Name)
+	s.Description = r.Description
+	s.OwnerTeam = strings.TrimSpace(r.OwnerTeam)
+	s.Tier = r.Tier
+	if s.Tier == "" {
+		s.Tier = ServiceTier3
+	}
+	s.SLOTarget = r.SLOTarget
+	if s.SLOTarget == 0 {
+		s.SLOTarget = CatalogServiceDefaultSLOTarget
+	}
+
+	s.Dependencies = make([]string, 0, len(r.Dependencies))
+	seen := make(map[string]bool, len(r.Dependencies))
+	for _, dependency := range r.Dependencies {
+		dependency = strings.TrimSpace(dependency)
+		if dependency != "" && !seen[dependency] {
+			seen[dependency] = true
+			s.Dependencies = append(s.Dependencies, dependency)
+		}
+	}
+
+	s.LabelSelectors = r.LabelSelectors
+	if s.LabelSelectors == nil {
+		s.LabelSelectors = []LabelSelector{}
+	}
+}
+
+// CatalogServiceDetail 服务详情，包含依赖该服务的其他服务
+type CatalogServiceDetail struct {
+	*CatalogService
+	Dependents []string `json:"dependents"`
+}
+
+// ServiceAlertWindow 归属到服务的告警的持续区间，用于计算可用性
+type ServiceAlertWindow struct {
+	Severity   AlertSeverity `db:"severity"`
+	StartsAt   time.Time     `db:"starts_at"`
+	ResolvedAt *time.Time    `db:"resolved_at"`
+}
+
+// ServiceTicketStats 归属到服务的工单统计
+type ServiceTicketStats struct {
+	Total int64 `json:"total" db:"total"`
+	Open  int64 `json:"open" db:"open"`
+}
+
+// ServiceSLO 服务在统计窗口内的可用性。critical 和 high 告警持续的时间视为不可用，
+// 多个告警重叠的时间只计算一次
+type ServiceSLO struct {
+	ServiceID   string      `json:"service_id"`
+	Name        string      `json:"name"`
+	OwnerTeam   string      `json:"owner_team"`
+	Tier        ServiceTier `json:"tier"`
+	WindowStart time.Time   `json:"window_start"`
+	WindowEnd   time.Time   `json:"window_end"`
+	// Target 可用性目标（百分比）
+	Target float64 `json:"target"`
+	// Availability 实际可用性（百分比）
+	Availability float64 `json:"availability"`
+	// ErrorBudgetSeconds 窗口内允许的不可用时长
+	ErrorBudgetSeconds int64 `json:"error_budget_seconds"`
+	// DowntimeSeconds 窗口内的不可用时长
+	DowntimeSeconds int64 `json:"downtime_seconds"`
+	// ErrorBudgetRemaining 剩余错误预算占比（百分比），超出预算时为负数
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	AlertCount           int     `json:"alert_count"`
+	OpenAlerts           int     `json:"open_alerts"`
+	// MTTRSeconds 窗口内已解决告警的平均解决时长
+	MTTRSeconds int64              `json:"mttr_seconds"`
+	Tickets     ServiceTicketStats `json:"tickets"`
+}
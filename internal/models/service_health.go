@@ -0,0 +1,105 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// ServiceHealthMajorWeight 活跃告警的最高权重达到该值时服务完全不可用
+	ServiceHealthMajorWeight = 100
+	// ServiceHealthPartialWeight 活跃告警的最高权重达到该值时服务部分不可用，低于该值但大于 0 时性能下降
+	ServiceHealthPartialWeight = 50
+)
+
+// ServiceAlertCount 归属到服务的活跃告警按级别的数量
+type ServiceAlertCount struct {
+	Service  string        `db:"service"`
+	Severity AlertSeverity `db:"severity"`
+	Count    int64         `db:"count"`
+}
+
+// ServiceHealth 服务的健康度，由服务当前活跃告警中权重最高的级别决定。
+// Score 为 100 减去最高权重，100 表示没有影响健康度的告警
+type ServiceHealth struct {
+	ServiceID string               `json:"service_id"`
+	Name      string               `json:"name"`
+	OwnerTeam string               `json:"owner_team"`
+	Tier      ServiceTier          `json:"tier"`
+	State     StatusComponentState `json:"state"`
+	Score     int                  `json:"score"`
+	// WorstSeverity 影响健康度的告警中最高的级别，没有时为空
+	WorstSeverity AlertSeverity           `json:"worst_severity,omitempty"`
+	ActiveAlerts  int64                   `json:"active_alerts"`
+	BySeverity    map[AlertSeverity]int64 `json:"by_severity"`
+	EvaluatedAt   time.Time               `json:"evaluated_at"`
+}
+
+// NewServiceHealth 根据活跃告警数量和级别权重计算服务健康度，权重为 0 的级别只计数不影响健康度
+func NewServiceHealth(service *CatalogService, counts []*ServiceAlertCount, weights map[AlertSeverity]int, now time.Time) *ServiceHealth {
+	health := &ServiceHealth{
+		ServiceID:   service.ID,
+		Name:        service.Name,
+		OwnerTeam:   service.OwnerTeam,
+		Tier:        service.Tier,
+		BySeverity:  map[AlertSeverity]int64{},
+		EvaluatedAt: now,
+	}
+
+	worst := 0
+	for _, count := range counts {
+		health.ActiveAlerts += count.Count
+		health.BySeverity[count.Severity] += count.Count
+		weight := weights[count.Severity]
+		if weight > worst || (weight == worst && weight > 0 &&
+			count.Severity.GetSeverityLevel() > health.WorstSeverity.GetSeverityLevel()) {
+			worst = weight
+			health.WorstSeverity = count.Severity
+		}
+	}
+
+	health.Score = 100 - worst
+	switch {
+	case worst >= ServiceHealthMajorWeight:
+		health.State = StatusComponentMajorOutage
+	case worst >= ServiceHealthPartialWeight:
+		health.State = StatusComponentPartialOutage
+	case worst > 0:
+		health.State = StatusComponentDegradedPerformance
+	default:
+		health.State = StatusComponentOperational
+	}
+	return health
+}
+
+// ServiceHealthRollup 全部服务的健康度汇总，供状态页和看板使用
+type ServiceHealthRollup struct {
+	// State 所有服务中最差的状态
+	State StatusComponentState `json:"state"`
+	// Counts 各状态的服务数量
+	Counts      map[StatusComponentState]int `json:"counts"`
+	Services    []*ServiceHealth             `json:"services"`
+	EvaluatedAt time.Time                    `json:"evaluated_at"`
+}
+
+// NewServiceHealthRollup 汇总服务健康度，服务按状态从差到好、再按名称排序
+func NewServiceHealthRollup(services []*ServiceHealth, now time.Time) *ServiceHealthRollup {
+	rollup := &ServiceHealthRollup{
+		State:       StatusComponentOperational,
+		Counts:      map[StatusComponentState]int{},
+		Services:    services,
+		EvaluatedAt: now,
+	}
+	for _, service := range services {
+		rollup.State = rollup.State.Worse(service.State)
+		rollup.Counts[service.State]++
+	}
+	sort.SliceStable(rollup.Services, func(i, j int) bool {
+		a, b := rollup.Services[i], rollup.Services[j]
+		if statusComponentStateRanks[a.State] != statusComponentStateRanks[b.State] {
+			return statusComponentStateRanks[a.State] > statusComponentStateRanks[b.State]
+		}
+		return a.Name < b.Name
+	})
+	return rollup
+}
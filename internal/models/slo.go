@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// SLOWindowPlaceholder SLI 查询中的时间窗口占位符，计算时替换为 5m、1h、30d 等 PromQL 时长
+	SLOWindowPlaceholder = "{{.window}}"
+
+	// SLODefaultWindowDays 未指定时的 SLO 统计窗口天数
+	SLODefaultWindowDays = 30
+	// SLOMaxWindowDays SLO 统计窗口的最大天数
+	SLOMaxWindowDays = 90
+)
+
+// SLOBudgetStatus 错误预算状态
+type SLOBudgetStatus string
+
+const (
+	SLOBudgetStatusUnknown   SLOBudgetStatus = "unknown"   // 尚未计算或查询失败
+	SLOBudgetStatusHealthy   SLOBudgetStatus = "healthy"   // 剩余预算充足
+	SLOBudgetStatusWarning   SLOBudgetStatus = "warning"   // 剩余预算不足 25%
+	SLOBudgetStatusExhausted SLOBudgetStatus = "exhausted" // 预算已耗尽
+)
+
+// SLO 服务等级目标，SLI 为错误请求数与总请求数之比，通过 Prometheus 数据源查询。
+// 创建时自动生成多窗口错误预算消耗速率告警规则
+type SLO struct {
+	ID           string  `json:"id" db:"id"`
+	Name         string  `json:"name" db:"name"`
+	Description  string  `json:"description" db:"description"`
+	ServiceID    string  `json:"service_id" db:"service_id"`
+	DataSourceID string  `json:"data_source_id" db:"data_source_id"`
+	ErrorQuery   string  `json:"error_query" db:"error_query"`
+	TotalQuery   string  `json:"total_query" db:"total_query"`
+	Target       float64 `json:"target" db:"target"`
+	WindowDays   int     `json:"window_days" db:"window_days"`
+	Enabled      bool    `json:"enabled" db:"enabled"`
+	// 自动生成的告警规则，page 为快速消耗（critical），ticket 为缓慢消耗（high）
+	PageRuleID   *string `json:"page_rule_id,omitempty" db:"page_rule_id"`
+	TicketRuleID *string `json:"ticket_rule_id,omitempty" db:"ticket_rule_id"`
+	// 最近一次错误预算计算结果
+	Budget    SLOBudget `json:"budget" db:"-"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ErrorBudget 统计窗口内允许的错误比例
+func (s *SLO) ErrorBudget() float64 {
+	return 1 - s.Target/100
+}
+
+// Window 统计窗口
+func (s *SLO) Window() time.Duration {
+	return time.Duration(s.WindowDays) * 24 * time.Hour
+}
+
+// SLOBudget 错误预算计算结果
+type SLOBudget struct {
+	Status SLOBudgetStatus `json:"status" db:"budget_status"`
+	// ErrorRatio 统计窗口内的错误比例
+	ErrorRatio *float64 `json:"error_ratio,omitempty" db:"error_ratio"`
+	// Remaining 剩余错误预算占比（百分比），超出预算时为负数
+	Remaining *float64 `json:"remaining,omitempty" db:"budget_remaining"`
+	// BurnRate 最近 1 小时的错误预算消耗速率，1 表示恰好在统计窗口结束时耗尽
+	BurnRate    *float64   `json:"burn_rate,omitempty" db:"burn_rate"`
+	EvaluatedAt *time.Time `json:"evaluated_at,omitempty" db:"evaluated_at"`
+	LastError   *string    `json:"last_error,omitempty" db:"last_error"`
+}
+
+// SLORequest 创建或更新 SLO 请求
+type SLORequest struct {
+	Name         string  `json:"name" binding:"required,max=100"`
+	Description  string  `json:"description,omitempty" binding:"max=1000"`
+	ServiceID    string  `json:"service_id" binding:"required"`
+	DataSourceID string  `json:"data_source_id" binding:"required"`
+	ErrorQuery   string  `json:"error_query" binding:"required"`
+	TotalQuery   string  `json:"total_query" binding:"required"`
+	Target       float64 `json:"target" binding:"required"`
+	WindowDays   int     `json:"window_days,omitempty"`
+	Disabled     bool    `json:"disabled"`
+}
+
+// Validate 验证 SLO 请求，数据源类型和服务是否存在由服务层检查
+func (r *SLORequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: SLO名称不能为空", ErrInvalidInput)
+	}
+	if r.Target <= 0 || r.Target >= 100 {
+		return fmt.Errorf("%w: 目标必须大于 0 且小于 100", ErrInvalidInput)
+	}
+	if r.WindowDays < 0 || r.WindowDays > SLOMaxWindowDays {
+		return fmt.Errorf("%w: 统计窗口必须在 1 到 %d 天之间", ErrInvalidInput, SLOMaxWindowDays)
+	}
+	for field, query := range map[string]string{"错误请求查询": r.ErrorQuery, "总请求查询": r.TotalQuery} {
+		if !strings.Contains(query, SLOWindowPlaceholder) {
+			return fmt.Errorf("%w: %s必须包含时间窗口占位符 %s，如 sum(rate(http_requests_total[%s]))",
+				ErrInvalidInput, field, SLOWindowPlaceholder, SLOWindowPlaceholder)
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求内容应用到 SLO，未指定统计窗口时使用 30 天
+func (r *SLORequest) ApplyTo(s *SLO) {
+	s.Name = strings.TrimSpace(r.Name)
+	s.Description = r.Description
+	s.ServiceID = r.ServiceID
+	s.DataSourceID = r.DataSourceID
+	s.ErrorQuery = strings.TrimSpace(r.ErrorQuery)
+	s.TotalQuery = strings.TrimSpace(r.TotalQuery)
+	s.Target = r.Target
+	s.WindowDays = r.WindowDays
+	if s.WindowDays == 0 {
+		s.WindowDays = SLODefaultWindowDays
+	}
+	s.Enabled = !r.Disabled
+}
+
+// ServiceSLOBudgets 服务下全部 SLO 的错误预算
+type ServiceSLOBudgets struct {
+	ServiceID   string `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	OwnerTeam   string `json:"owner_team"`
+	SLOs        []*SLO `json:"slos"`
+	// Status 服务下最差的错误预算状态
+	Status SLOBudgetStatus `json:"status"`
+}
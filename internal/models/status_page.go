@@ -0,0 +1,379 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// StatusIncidentMaxComponents 单个状态页事件最多影响的组件数
+	StatusIncidentMaxComponents = 50
+	// StatusIncidentMaxMessage 事件进展说明的最大长度
+	StatusIncidentMaxMessage = 5000
+	// StatusSubscriberMaxComponents 订阅者最多订阅的组件数
+	StatusSubscriberMaxComponents = 50
+)
+
+// StatusIncidentKind 状态页事件类型
+type StatusIncidentKind string
+
+const (
+	StatusIncidentKindIncident    StatusIncidentKind = "incident"    // 故障
+	StatusIncidentKindMaintenance StatusIncidentKind = "maintenance" // 计划维护
+)
+
+// IsValid 检查事件类型是否有效
+func (k StatusIncidentKind) IsValid() bool {
+	return k == StatusIncidentKindIncident || k == StatusIncidentKindMaintenance
+}
+
+// StatusIncidentStatus 状态页事件进展，故障和计划维护使用不同的进展
+type StatusIncidentStatus string
+
+const (
+	StatusIncidentInvestigating StatusIncidentStatus = "investigating" // 故障排查中
+	StatusIncidentIdentified    StatusIncidentStatus = "identified"    // 已定位原因
+	StatusIncidentMonitoring    StatusIncidentStatus = "monitoring"    // 已修复，观察中
+	StatusIncidentResolved      StatusIncidentStatus = "resolved"      // 已解决
+
+	StatusMaintenanceScheduled  StatusIncidentStatus = "scheduled"   // 维护已计划
+	StatusMaintenanceInProgress StatusIncidentStatus = "in_progress" // 维护进行中
+	StatusMaintenanceCompleted  StatusIncidentStatus = "completed"   // 维护已完成
+)
+
+// ValidFor 检查进展是否适用于事件类型
+func (s StatusIncidentStatus) ValidFor(kind StatusIncidentKind) bool {
+	switch s {
+	case StatusIncidentInvestigating, StatusIncidentIdentified, StatusIncidentMonitoring, StatusIncidentResolved:
+		return kind == StatusIncidentKindIncident
+	case StatusMaintenanceScheduled, StatusMaintenanceInProgress, StatusMaintenanceCompleted:
+		return kind == StatusIncidentKindMaintenance
+	default:
+		return false
+	}
+}
+
+// IsClosed 检查事件是否已结束，结束的事件不再影响组件状态
+func (s StatusIncidentStatus) IsClosed() bool {
+	return s == StatusIncidentResolved || s == StatusMaintenanceCompleted
+}
+
+// StatusImpact 故障对组件的影响程度
+type StatusImpact string
+
+const (
+	StatusImpactMinor    StatusImpact = "minor"    // 性能下降
+	StatusImpactMajor    StatusImpact = "major"    // 部分不可用
+	StatusImpactCritical StatusImpact = "critical" // 完全不可用
+)
+
+// IsValid 检查影响程度是否有效
+func (i StatusImpact) IsValid() bool {
+	switch i {
+	case StatusImpactMinor, StatusImpactMajor, StatusImpactCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusComponentState 状态页组件状态
+type StatusComponentState string
+
+const (
+	StatusComponentOperational         StatusComponentState = "operational"          // 正常
+	StatusComponentUnderMaintenance    StatusComponentState = "under_maintenance"    // 维护中
+	StatusComponentDegradedPerformance StatusComponentState = "degraded_performance" // 性能下降
+	StatusComponentPartialOutage       StatusComponentState = "partial_outage"       // 部分不可用
+	StatusComponentMajorOutage         StatusComponentState = "major_outage"         // 完全不可用
+)
+
+// statusComponentStateRanks 组件状态的严重程度，多个事件影响同一组件时取最严重的状态
+var statusComponentStateRanks = map[StatusComponentState]int{
+	StatusComponentOperational:         0,
+	StatusComponentUnderMaintenance:    1,
+	StatusComponentDegradedPerformance: 2,
+	StatusComponentPartialOutage:       3,
+	StatusComponentMajorOutage:         4,
+}
+
+// Worse 返回两个状态中更严重的一个
+func (s StatusComponentState) Worse(other StatusComponentState) StatusComponentState {
+	if statusComponentStateRanks[other] > statusComponentStateRanks[s] {
+		return other
+	}
+	return s
+}
+
+// StatusIncident 状态页上公开的故障或计划维护，影响的组件为服务目录中的服务名称。
+// 进展说明按时间记录在 StatusIncidentUpdate 中，事件的进展为最近一次说明的进展
+type StatusIncident struct {
+	ID         string               `json:"id" db:"id"`
+	Kind       StatusIncidentKind   `json:"kind" db:"kind"`
+	Title      string               `json:"title" db:"title"`
+	Status     StatusIncidentStatus `json:"status" db:"status"`
+	Impact     StatusImpact         `json:"impact,omitempty" db:"impact"`
+	Components []string             `json:"components" db:"components"`
+	// TicketID 关联的内部事件工单，不在公开状态页展示
+	TicketID       *string    `json:"ticket_id,omitempty" db:"ticket_id"`
+	ScheduledStart *time.Time `json:"scheduled_start,omitempty" db:"scheduled_start"`
+	ScheduledEnd   *time.Time `json:"scheduled_end,omitempty" db:"scheduled_end"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedBy      string     `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Updates 进展说明，按发布时间倒序
+	Updates []*StatusIncidentUpdate `json:"updates,omitempty" db:"-"`
+}
+
+// ComponentState 事件对受影响组件造成的状态，已结束的事件和尚未开始的维护不影响组件
+func (i *StatusIncident) ComponentState() StatusComponentState {
+	switch {
+	case i.Status.IsClosed(), i.Status == StatusMaintenanceScheduled:
+		return StatusComponentOperational
+	case i.Kind == StatusIncidentKindMaintenance:
+		return StatusComponentUnderMaintenance
+	case i.Impact == StatusImpactCritical:
+		return StatusComponentMajorOutage
+	case i.Impact == StatusImpactMajor:
+		return StatusComponentPartialOutage
+	default:
+		return StatusComponentDegradedPerformance
+	}
+}
+
+// Public 去除内部信息后的事件，用于公开状态页
+func (i *StatusIncident) Public() *StatusIncident {
+	copied := *i
+	copied.TicketID = nil
+	copied.CreatedBy = ""
+	updates := make([]*StatusIncidentUpdate, 0, len(i.Updates))
+	for _, update := range i.Updates {
+		u := *update
+		u.CreatedBy = ""
+		updates = append(updates, &u)
+	}
+	copied.Updates = updates
+	return &copied
+}
+
+// StatusIncidentUpdate 状态页事件的进展说明。Notify 为 true 时由 Worker 邮件通知订阅者，
+// NotifiedAt 为发送时间，发送前先设置以保证多实例时只发送一次
+type StatusIncidentUpdate struct {
+	ID         string               `json:"id" db:"id"`
+	IncidentID string               `json:"incident_id" db:"incident_id"`
+	Status     StatusIncidentStatus `json:"status" db:"status"`
+	Message    string               `json:"message" db:"message"`
+	Notify     bool                 `json:"-" db:"notify"`
+	NotifiedAt *time.Time           `json:"-" db:"notified_at"`
+	CreatedBy  string               `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time            `json:"created_at" db:"created_at"`
+}
+
+// StatusIncidentRequest 创建或更新状态页事件请求。创建时 Message 作为第一条进展说明，
+// Status 为空时故障为 investigating、计划维护为 scheduled；更新时只修改标题、影响和计划时间等基本信息
+type StatusIncidentRequest struct {
+	Kind           StatusIncidentKind   `json:"kind"`
+	Title          string               `json:"title" binding:"required,max=200"`
+	Status         StatusIncidentStatus `json:"status"`
+	Impact         StatusImpact         `json:"impact"`
+	Components     []string             `json:"components"`
+	TicketID       *string              `json:"ticket_id,omitempty"`
+	ScheduledStart *time.Time           `json:"scheduled_start,omitempty"`
+	ScheduledEnd   *time.Time           `json:"scheduled_end,omitempty"`
+	Message        string               `json:"message"`
+	// Notify 是否邮件通知订阅者，默认通知
+	Notify *bool `json:"notify,omitempty"`
+}
+
+// Normalize 去除首尾空白并填充默认值
+func (r *StatusIncidentRequest) Normalize() {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Message = strings.TrimSpace(r.Message)
+	if r.Kind == "" {
+		r.Kind = StatusIncidentKindIncident
+	}
+	if r.Status == "" {
+		r.Status = StatusIncidentInvestigating
+		if r.Kind == StatusIncidentKindMaintenance {
+			r.Status = StatusMaintenanceScheduled
+		}
+	}
+	components := make([]string, 0, len(r.Components))
+	seen := make(map[string]bool, len(r.Components))
+	for _, component := range r.Components {
+		component = strings.TrimSpace(component)
+		if component != "" && !seen[component] {
+			seen[component] = true
+			components = append(components, component)
+		}
+	}
+	r.Components = components
+}
+
+// Validate 验证请求，故障需要指定影响程度，计划维护需要指定计划时间
+func (r *StatusIncidentRequest) Validate() error {
+	if !r.Kind.IsValid() {
+		return fmt.Errorf("%w: 无效的事件类型 %s", ErrInvalidInput, r.Kind)
+	}
+	if r.Title == "" || utf8.RuneCountInString(r.Title) > 200 {
+		return fmt.Errorf("%w: 事件标题不能为空且不能超过200个字符", ErrInvalidInput)
+	}
+	if !r.Status.ValidFor(r.Kind) {
+		return fmt.Errorf("%w: 进展 %s 不适用于%s类型的事件", ErrInvalidInput, r.Status, r.Kind)
+	}
+	if len(r.Components) == 0 {
+		return fmt.Errorf("%w: 至少需要指定一个受影响的组件", ErrInvalidInput)
+	}
+	if len(r.Components) > StatusIncidentMaxComponents {
+		return fmt.Errorf("%w: 受影响的组件不能超过 %d 个", ErrInvalidInput, StatusIncidentMaxComponents)
+	}
+	if utf8.RuneCountInString(r.Message) > StatusIncidentMaxMessage {
+		return fmt.Errorf("%w: 进展说明不能超过%d个字符", ErrInvalidInput, StatusIncidentMaxMessage)
+	}
+
+	switch r.Kind {
+	case StatusIncidentKindIncident:
+		if !r.Impact.IsValid() {
+			return fmt.Errorf("%w: 无效的影响程度 %s", ErrInvalidInput, r.Impact)
+		}
+	case StatusIncidentKindMaintenance:
+		if r.Impact != "" {
+			return fmt.Errorf("%w: 计划维护不能指定影响程度", ErrInvalidInput)
+		}
+		if r.ScheduledStart == nil || r.ScheduledEnd == nil {
+			return fmt.Errorf("%w: 计划维护需要指定开始和结束时间", ErrInvalidInput)
+		}
+		if !r.ScheduledEnd.After(*r.ScheduledStart) {
+			return fmt.Errorf("%w: 维护结束时间必须晚于开始时间", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// ApplyTo 将请求中的基本信息应用到事件，不修改类型和进展
+func (r *StatusIncidentRequest) ApplyTo(incident *StatusIncident) {
+	incident.Title = r.Title
+	incident.Impact = r.Impact
+	incident.Components = r.Components
+	incident.TicketID = r.TicketID
+	incident.ScheduledStart = r.ScheduledStart
+	incident.ScheduledEnd = r.ScheduledEnd
+}
+
+// StatusIncidentUpdateRequest 发布事件进展请求
+type StatusIncidentUpdateRequest struct {
+	Status  StatusIncidentStatus `json:"status" binding:"required"`
+	Message string               `json:"message" binding:"required"`
+	// Impact 同时调整影响程度，只适用于故障
+	Impact *StatusImpact `json:"impact,omitempty"`
+	// Notify 是否邮件通知订阅者，默认通知
+	Notify *bool `json:"notify,omitempty"`
+}
+
+// Validate 验证进展说明
+func (r *StatusIncidentUpdateRequest) Validate(kind StatusIncidentKind) error {
+	r.Message = strings.TrimSpace(r.Message)
+	if !r.Status.ValidFor(kind) {
+		return fmt.Errorf("%w: 进展 %s 不适用于%s类型的事件", ErrInvalidInput, r.Status, kind)
+	}
+	if r.Message == "" || utf8.RuneCountInString(r.Message) > StatusIncidentMaxMessage {
+		return fmt.Errorf("%w: 进展说明不能为空且不能超过%d个字符", ErrInvalidInput, StatusIncidentMaxMessage)
+	}
+	if r.Impact != nil {
+		if kind != StatusIncidentKindIncident {
+			return fmt.Errorf("%w: 计划维护不能指定影响程度", ErrInvalidInput)
+		}
+		if !r.Impact.IsValid() {
+			return fmt.Errorf("%w: 无效的影响程度 %s", ErrInvalidInput, *r.Impact)
+		}
+	}
+	return nil
+}
+
+// StatusIncidentFilter 状态页事件列表过滤条件
+type StatusIncidentFilter struct {
+	Kind *StatusIncidentKind
+	// Open 只返回未结束的事件
+	Open bool
+	// ClosedSince 同时返回该时间之后结束的事件，Open 为 true 时有效
+	ClosedSince *time.Time
+	Limit       int
+}
+
+// StatusSubscriber 状态页邮件订阅者，确认邮件中的链接后才接收通知，Components 为空时订阅全部组件。
+// 确认和退订链接中的令牌为订阅者 ID 的签名，不需要保存
+type StatusSubscriber struct {
+	ID          string     `json:"id" db:"id"`
+	Email       string     `json:"email" db:"email"`
+	Components  []string   `json:"components" db:"components"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Subscribed 检查订阅者是否订阅了受影响组件中的任意一个
+func (s *StatusSubscriber) Subscribed(components []string) bool {
+	if len(s.Components) == 0 {
+		return true
+	}
+	for _, component := range components {
+		for _, subscribed := range s.Components {
+			if component == subscribed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StatusSubscribeRequest 订阅状态页更新请求，状态页以表单提交
+type StatusSubscribeRequest struct {
+	Email      string   `json:"email" form:"email" binding:"required"`
+	Components []string `json:"components" form:"components"`
+}
+
+// Validate 验证订阅请求，邮箱地址统一转为小写
+func (r *StatusSubscribeRequest) Validate() error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(r.Email))
+	if err != nil || len(addr.Address) > 255 {
+		return fmt.Errorf("%w: 无效的邮箱地址", ErrInvalidInput)
+	}
+	r.Email = strings.ToLower(addr.Address)
+	if len(r.Components) > StatusSubscriberMaxComponents {
+		return fmt.Errorf("%w: 订阅的组件不能超过 %d 个", ErrInvalidInput, StatusSubscriberMaxComponents)
+	}
+	return nil
+}
+
+// StatusSubscriptionView 确认和退订链接展示的订阅信息
+type StatusSubscriptionView struct {
+	Email      string   `json:"email"`
+	Components []string `json:"components"`
+	Confirmed  bool     `json:"confirmed"`
+}
+
+// StatusComponent 状态页上的组件，对应服务目录中的服务
+type StatusComponent struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	State       StatusComponentState `json:"state"`
+}
+
+// StatusPage 公开状态页内容，由服务目录和状态页事件生成
+type StatusPage struct {
+	Title string `json:"title"`
+	// State 全部组件中最严重的状态
+	State      StatusComponentState `json:"state"`
+	Components []*StatusComponent   `json:"components"`
+	// Incidents 未结束的故障
+	Incidents []*StatusIncident `json:"incidents"`
+	// Maintenances 计划中和进行中的维护
+	Maintenances []*StatusIncident `json:"maintenances"`
+	// History 最近结束的故障和维护
+	History     []*StatusIncident `json:"history"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertTagsLabel 告警标签所在的标签名，多个标签以逗号分隔，如 tags=db,payments
+const AlertTagsLabel = "tags"
+
+// TagResource 带标签的资源类型
+type TagResource string
+
+const (
+	TagResourceAlert     TagResource = "alert"     // 告警，标签保存在 tags 标签中
+	TagResourceTicket    TagResource = "ticket"    // 工单
+	TagResourceKnowledge TagResource = "knowledge" // 知识库文章
+)
+
+// TagResources 标签治理任务依次处理的资源类型
+var TagResources = []TagResource{TagResourceAlert, TagResourceTicket, TagResourceKnowledge}
+
+// TagUsage 标签在各类资源中的使用次数
+type TagUsage struct {
+	Tag       string `json:"tag" db:"tag"`
+	Alerts    int64  `json:"alerts" db:"alerts"`
+	Tickets   int64  `json:"tickets" db:"tickets"`
+	Knowledge int64  `json:"knowledge" db:"knowledge"`
+	Total     int64  `json:"total" db:"total"`
+	// Defined 是否为知识库标签目录中定义的标签，已定义但未被使用的标签为孤立标签
+	Defined bool `json:"defined" db:"defined"`
+}
+
+// TagJobType 标签治理任务类型
+type TagJobType string
+
+const (
+	TagJobTypeRename TagJobType = "rename" // 重命名，目标标签不能已被使用
+	TagJobTypeMerge  TagJobType = "merge"  // 合并到已有标签
+	TagJobTypeDelete TagJobType = "delete" // 从所有资源中删除
+)
+
+// TagJobStatus 标签治理任务状态
+type TagJobStatus string
+
+const (
+	TagJobStatusPending   TagJobStatus = "pending"   // 等待执行
+	TagJobStatusRunning   TagJobStatus = "running"   // 执行中
+	TagJobStatusCompleted TagJobStatus = "completed" // 已完成
+	TagJobStatusFailed    TagJobStatus = "failed"    // 执行失败
+)
+
+// TagJob 标签治理任务，Total 和 Processed 反映执行进度
+type TagJob struct {
+	ID         string       `json:"id" db:"id"`
+	Type       TagJobType   `json:"type" db:"type"`
+	Tag        string       `json:"tag" db:"tag"`
+	Target     string       `json:"target,omitempty" db:"target"`
+	Status     TagJobStatus `json:"status" db:"status"`
+	Total      int64        `json:"total" db:"total"`
+	Processed  int64        `json:"processed" db:"processed"`
+	Error      string       `json:"error,omitempty" db:"error"`
+	CreatedBy  string       `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time   `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Replacement 任务执行时用于替换原标签的标签，删除任务为空
+func (j *TagJob) Replacement() string {
+	if j.Type == TagJobTypeDelete {
+		return ""
+	}
+	return j.Target
+}
+
+// TagJobRequest 创建标签治理任务请求
+type TagJobRequest struct {
+	Type   TagJobType `json:"type"`
+	Tag    string     `json:"tag"`
+	Target string     `json:"target"`
+}
+
+// Validate 验证标签治理任务请求
+func (r *TagJobRequest) Validate() error {
+	r.Tag = strings.TrimSpace(r.Tag)
+	r.Target = strings.TrimSpace(r.Target)
+
+	switch r.Type {
+	case TagJobTypeRename, TagJobTypeMerge:
+		if err := validateTagName(r.Target); err != nil {
+			return err
+		}
+		if r.Target == r.Tag {
+			return fmt.Errorf("%w: 目标标签不能与原标签相同", ErrInvalidInput)
+		}
+	case TagJobTypeDelete:
+		r.Target = ""
+	default:
+		return fmt.Errorf("%w: 无效的任务类型 %q", ErrInvalidInput, r.Type)
+	}
+
+	return validateTagName(r.Tag)
+}
+
+// validateTagName 验证标签名，告警标签以逗号分隔，标签名不能包含逗号
+func validateTagName(tag string) error {
+	if tag == "" || len(tag) > 255 {
+		return fmt.Errorf("%w: 标签不能为空且不能超过255个字符", ErrInvalidInput)
+	}
+	if strings.Contains(tag, ",") {
+		return fmt.Errorf("%w: 标签不能包含逗号", ErrInvalidInput)
+	}
+	return nil
+}
@@ -64,6 +64,7 @@ const (
 	TicketSourceEmail     TicketSource = "email"     // 邮件
 	TicketSourceWebhook   TicketSource = "webhook"   // Webhook
 	TicketSourceScheduled TicketSource = "scheduled" // 定时任务
+	TicketSourceImport    TicketSource = "import"    // 从文件批量导入
 )
 
 // TicketComment 工单评论
@@ -92,9 +93,38 @@ type TicketAttachment struct {
 	MimeType         string    `json:"mime_type" db:"mime_type"`
 	FilePath         string    `json:"file_path" db:"file_path"`
 	UploadBy         string    `json:"upload_by" db:"upload_by"`
+	ScanStatus       AttachmentScanStatus `json:"scan_status" db:"scan_status"`
+	ScanResult       *string              `json:"scan_result,omitempty" db:"scan_result"`
+	ScannedAt        *time.Time           `json:"scanned_at,omitempty" db:"scanned_at"`
+	// ScanAttempts 已尝试扫描的次数，扫描器暂时不可用时保持待扫描并在 ScanRetryAt 之后重试
+	ScanAttempts     int                  `json:"scan_attempts" db:"scan_attempts"`
+	ScanRetryAt      *time.Time           `json:"scan_retry_at,omitempty" db:"scan_retry_at"`
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
+// AttachmentScanStatus 附件扫描状态
+type AttachmentScanStatus string
+
+const (
+	AttachmentScanStatusPending  AttachmentScanStatus = "pending"  // 待扫描
+	AttachmentScanStatusScanning AttachmentScanStatus = "scanning" // 扫描中
+	AttachmentScanStatusClean    AttachmentScanStatus = "clean"    // 安全
+	AttachmentScanStatusInfected AttachmentScanStatus = "infected" // 已感染，已隔离
+	AttachmentScanStatusError    AttachmentScanStatus = "error"    // 无法扫描（文件不可读或多次重试仍失败）
+)
+
+// IsDownloadable 附件是否允许下载
+// 启用扫描时仅允许下载扫描通过的附件；未启用扫描时仅拦截已感染附件
+func (a *TicketAttachment) IsDownloadable(scanEnabled bool) bool {
+	if a.ScanStatus == AttachmentScanStatusInfected {
+		return false
+	}
+	if !scanEnabled {
+		return true
+	}
+	return a.ScanStatus == AttachmentScanStatusClean
+}
+
 // TicketHistory 工单历史记录
 type TicketHistory struct {
 	ID        string                 `json:"id" db:"id"`
@@ -204,13 +234,13 @@ type Ticket struct {
 type TicketCreateRequest struct {
 	Title          string            `json:"title" binding:"required,min=1,max=200"`
 	Description    string            `json:"description" binding:"required,min=1,max=5000"`
-	Type           TicketType        `json:"type" binding:"required"`
-	Priority       TicketPriority    `json:"priority" binding:"required"`
-	Severity       TicketSeverity    `json:"severity" binding:"required"`
+	Type           TicketType        `json:"type" binding:"required,enum"`
+	Priority       TicketPriority    `json:"priority" binding:"required,enum"`
+	Severity       TicketSeverity    `json:"severity" binding:"required,enum"`
 	Category       *string           `json:"category,omitempty"`
 	Subcategory    *string           `json:"subcategory,omitempty"`
 	Tags           []string          `json:"tags,omitempty"`
-	Labels         map[string]string `json:"labels,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
 	AlertID        *string           `json:"alert_id,omitempty"`
 	RuleID         *string           `json:"rule_id,omitempty"`
 	DataSourceID   *string           `json:"data_source_id,omitempty"`
@@ -228,13 +258,13 @@ type TicketCreateRequest struct {
 type TicketUpdateRequest struct {
 	Title          *string            `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
 	Description    *string            `json:"description,omitempty" binding:"omitempty,min=1,max=5000"`
-	Status         *TicketStatus      `json:"status,omitempty"`
-	Priority       *TicketPriority    `json:"priority,omitempty"`
-	Severity       *TicketSeverity    `json:"severity,omitempty"`
+	Status         *TicketStatus      `json:"status,omitempty" binding:"omitempty,enum"`
+	Priority       *TicketPriority    `json:"priority,omitempty" binding:"omitempty,enum"`
+	Severity       *TicketSeverity    `json:"severity,omitempty" binding:"omitempty,enum"`
 	Category       *string            `json:"category,omitempty"`
 	Subcategory    *string            `json:"subcategory,omitempty"`
 	Tags           *[]string          `json:"tags,omitempty"`
-	Labels         *map[string]string `json:"labels,omitempty"`
+	Labels         *map[string]string `json:"labels,omitempty" binding:"omitempty,labels"`
 	AssigneeID     *string            `json:"assignee_id,omitempty"`
 	TeamID         *string            `json:"team_id,omitempty"`
 	DueDate        *time.Time         `json:"due_date,omitempty"`
@@ -286,6 +316,7 @@ type TicketFilter struct {
 	DueDateEnd     *time.Time      `json:"due_date_end,omitempty"`
 	DueSoon        *bool           `json:"due_soon,omitempty"`
 	Overdue        *bool           `json:"overdue,omitempty"`
+	Query          *string         `json:"q,omitempty"` // 过滤表达式，如 priority in (high,urgent) and tags = db
 	Page           int             `json:"page" binding:"min=1"`
 	PageSize       int             `json:"page_size" binding:"min=1,max=100"`
 	SortBy         *string         `json:"sort_by,omitempty"`
@@ -462,7 +493,7 @@ func (s TicketSeverity) IsValid() bool {
 func (s TicketSource) IsValid() bool {
 	switch s {
 	case TicketSourceManual, TicketSourceAlert, TicketSourceAPI,
-		 TicketSourceEmail, TicketSourceWebhook, TicketSourceScheduled:
+		 TicketSourceEmail, TicketSourceWebhook, TicketSourceScheduled, TicketSourceImport:
 		return true
 	default:
 		return false
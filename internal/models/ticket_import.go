@@ -0,0 +1,210 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pulse/internal/pkg/tabular"
+)
+
+// 工单导入的限制
+const (
+	// TicketImportMaxSize 导入文件最大大小
+	TicketImportMaxSize = 20 << 20
+	// TicketImportMaxRows 单个文件最多导入的工单数
+	TicketImportMaxRows = 50000
+	// TicketImportMaxErrors 导入任务最多记录的行错误数，超出的错误只计入失败数
+	TicketImportMaxErrors = 1000
+)
+
+// TicketImportField 导入时可以映射的工单字段
+type TicketImportField string
+
+const (
+	TicketImportFieldTitle       TicketImportField = "title"       // 标题，必须映射
+	TicketImportFieldDescription TicketImportField = "description" // 描述
+	TicketImportFieldNumber      TicketImportField = "number"      // 原系统中的工单编号，为空时自动生成
+	TicketImportFieldType        TicketImportField = "type"        // 类型，为空时为 incident
+	TicketImportFieldStatus      TicketImportField = "status"      // 状态，为空时为 open
+	TicketImportFieldPriority    TicketImportField = "priority"    // 优先级，为空时为 medium
+	TicketImportFieldSeverity    TicketImportField = "severity"    // 严重程度
+	TicketImportFieldCategory    TicketImportField = "category"    // 分类
+	TicketImportFieldSubcategory TicketImportField = "subcategory" // 子分类
+	TicketImportFieldTags        TicketImportField = "tags"        // 标签，以逗号或分号分隔
+	TicketImportFieldReporter    TicketImportField = "reporter"    // 报告人的用户名或邮箱，为空时为导入人
+	TicketImportFieldAssignee    TicketImportField = "assignee"    // 处理人的用户名或邮箱
+	TicketImportFieldDueDate     TicketImportField = "due_date"    // 截止时间，RFC 3339、日期或 Excel 日期
+)
+
+// TicketImportCustomFieldPrefix 映射到自定义字段的字段名前缀，如 custom_fields.region
+const TicketImportCustomFieldPrefix = "custom_fields."
+
+// ticketImportFieldAliases 各字段对应的常见表头，用于推荐列映射，比较时忽略大小写、空格和下划线
+var ticketImportFieldAliases = map[TicketImportField][]string{
+	TicketImportFieldTitle:       {"title", "subject", "summary", "标题", "主题"},
+	TicketImportFieldDescription: {"description", "body", "details", "content", "描述", "内容"},
+	TicketImportFieldNumber:      {"number", "ticketnumber", "ticketid", "key", "id", "编号", "工单编号"},
+	TicketImportFieldType:        {"type", "tickettype", "issuetype", "类型"},
+	TicketImportFieldStatus:      {"status", "state", "状态"},
+	TicketImportFieldPriority:    {"priority", "优先级"},
+	TicketImportFieldSeverity:    {"severity", "严重程度"},
+	TicketImportFieldCategory:    {"category", "分类"},
+	TicketImportFieldSubcategory: {"subcategory", "子分类"},
+	TicketImportFieldTags:        {"tags", "labels", "标签"},
+	TicketImportFieldReporter:    {"reporter", "requester", "requestor", "createdby", "报告人", "提交人"},
+	TicketImportFieldAssignee:    {"assignee", "owner", "assignedto", "agent", "处理人", "负责人"},
+	TicketImportFieldDueDate:     {"duedate", "due", "deadline", "截止时间", "截止日期"},
+}
+
+// IsValid 检查导入字段是否有效，自定义字段需要以 custom_fields. 开头
+func (f TicketImportField) IsValid() bool {
+	if name, ok := strings.CutPrefix(string(f), TicketImportCustomFieldPrefix); ok {
+		return name != ""
+	}
+	_, ok := ticketImportFieldAliases[f]
+	return ok
+}
+
+// SuggestTicketImportMapping 根据表头推荐列映射，每一列最多映射到一个字段
+func SuggestTicketImportMapping(headers []string) map[string]string {
+	normalize := func(s string) string {
+		s = strings.ToLower(strings.TrimSpace(s))
+		return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(s)
+	}
+
+	// 按字段名排序，保证结果稳定
+	fields := make([]string, 0, len(ticketImportFieldAliases))
+	for field := range ticketImportFieldAliases {
+		fields = append(fields, string(field))
+	}
+	sort.Strings(fields)
+
+	mapping := make(map[string]string)
+	used := make(map[string]bool)
+	for _, field := range fields {
+		for _, alias := range ticketImportFieldAliases[TicketImportField(field)] {
+			for _, header := range headers {
+				if header != "" && !used[header] && normalize(header) == alias {
+					mapping[field] = header
+					used[header] = true
+					break
+				}
+			}
+			if _, ok := mapping[field]; ok {
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// TicketImportStatus 工单导入任务状态
+type TicketImportStatus string
+
+const (
+	TicketImportStatusAwaitingMapping TicketImportStatus = "awaiting_mapping" // 已上传，等待确认列映射
+	TicketImportStatusPending         TicketImportStatus = "pending"          // 等待执行
+	TicketImportStatusRunning         TicketImportStatus = "running"          // 执行中
+	TicketImportStatusCompleted       TicketImportStatus = "completed"        // 已完成，部分行可能导入失败
+	TicketImportStatusFailed          TicketImportStatus = "failed"           // 执行失败
+)
+
+// TicketImportRowError 行错误，Row 为数据在文件中的行号，表头为第 1 行
+type TicketImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// TicketImportJob 工单导入任务。上传后先确认列映射再开始执行，试运行只校验不创建工单，
+// 可以修改列映射后再次开始。Total、Processed、Imported、Failed 反映执行进度
+type TicketImportJob struct {
+	ID       string             `json:"id" db:"id"`
+	FileName string             `json:"file_name" db:"file_name"`
+	Format   tabular.Format     `json:"format" db:"format"`
+	Status   TicketImportStatus `json:"status" db:"status"`
+	// Headers 文件的表头
+	Headers []string `json:"headers" db:"headers"`
+	// Mapping 工单字段到表头的映射，上传时为推荐的映射
+	Mapping map[string]string `json:"mapping" db:"mapping"`
+	DryRun  bool              `json:"dry_run" db:"dry_run"`
+	// Content 上传的文件内容，只在执行时读取
+	Content   []byte `json:"-" db:"content"`
+	Total     int    `json:"total" db:"total"`
+	Processed int    `json:"processed" db:"processed"`
+	Imported  int    `json:"imported" db:"imported"`
+	Failed    int    `json:"failed" db:"failed"`
+	// Errors 行错误，最多记录 TicketImportMaxErrors 条
+	Errors     []*TicketImportRowError `json:"errors" db:"errors"`
+	Error      string                  `json:"error,omitempty" db:"error"`
+	CreatedBy  string                  `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time               `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time              `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Startable 是否可以确认列映射并开始执行，试运行完成后可以再次开始
+func (j *TicketImportJob) Startable() bool {
+	return j.Status == TicketImportStatusAwaitingMapping ||
+		(j.DryRun && j.Status == TicketImportStatusCompleted)
+}
+
+// AddError 记录行错误并计入失败数
+func (j *TicketImportJob) AddError(rowErr *TicketImportRowError) {
+	j.Failed++
+	if len(j.Errors) < TicketImportMaxErrors {
+		j.Errors = append(j.Errors, rowErr)
+	}
+}
+
+// TicketImportStartRequest 确认列映射并开始导入的请求
+type TicketImportStartRequest struct {
+	// Mapping 工单字段到表头的映射，为空时使用上传时推荐的映射
+	Mapping map[string]string `json:"mapping"`
+	// DryRun 只校验每一行，不创建工单
+	DryRun bool `json:"dry_run"`
+}
+
+// Validate 验证列映射，标题必须映射，映射的表头必须存在，同一列不能映射到多个字段
+func (r *TicketImportStartRequest) Validate(headers []string) error {
+	exists := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		if header != "" {
+			exists[header] = true
+		}
+	}
+
+	fields := make([]string, 0, len(r.Mapping))
+	for field, header := range r.Mapping {
+		if header == "" {
+			delete(r.Mapping, field)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	used := make(map[string]string)
+	for _, field := range fields {
+		header := r.Mapping[field]
+		if !TicketImportField(field).IsValid() {
+			return fmt.Errorf("%w: 不支持的工单字段 %q", ErrInvalidInput, field)
+		}
+		if !exists[header] {
+			return fmt.Errorf("%w: 字段 %s 映射的列 %q 不存在", ErrInvalidInput, field, header)
+		}
+		if other, ok := used[header]; ok {
+			return fmt.Errorf("%w: 列 %q 同时映射到 %s 和 %s", ErrInvalidInput, header, other, field)
+		}
+		used[header] = field
+	}
+
+	if _, ok := r.Mapping[string(TicketImportFieldTitle)]; !ok {
+		return fmt.Errorf("%w: 必须映射标题列", ErrInvalidInput)
+	}
+	return nil
+}
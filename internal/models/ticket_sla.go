@@ -0,0 +1,151 @@
+package models
+
+import (
+	"time"
+)
+
+// TicketSLAStatusPaused SLA计时已暂停，暂停期间不判定超时
+const TicketSLAStatusPaused TicketSLAStatus = "paused"
+
+// TicketSLAPauseSource SLA暂停来源
+type TicketSLAPauseSource string
+
+const (
+	TicketSLAPauseSourceStatus TicketSLAPauseSource = "status" // 工单进入等待状态时自动暂停
+	TicketSLAPauseSourceManual TicketSLAPauseSource = "manual" // 处理人手动暂停
+)
+
+// TicketSLAPause 工单SLA暂停区间，ResumedAt 为空表示仍在暂停中，每个工单同一时间最多一个
+type TicketSLAPause struct {
+	ID        string               `json:"id" db:"id"`
+	TicketID  string               `json:"ticket_id" db:"ticket_id"`
+	Source    TicketSLAPauseSource `json:"source" db:"source"`
+	Reason    *string              `json:"reason,omitempty" db:"reason"`
+	PausedBy  *string              `json:"paused_by,omitempty" db:"paused_by"`
+	PausedAt  time.Time            `json:"paused_at" db:"paused_at"`
+	ResumedBy *string              `json:"resumed_by,omitempty" db:"resumed_by"`
+	ResumedAt *time.Time           `json:"resumed_at,omitempty" db:"resumed_at"`
+}
+
+// Duration 返回暂停时长，仍在暂停中时计算到 now
+func (p *TicketSLAPause) Duration(now time.Time) time.Duration {
+	end := now
+	if p.ResumedAt != nil {
+		end = *p.ResumedAt
+	}
+	if end.Before(p.PausedAt) {
+		return 0
+	}
+	return end.Sub(p.PausedAt)
+}
+
+// TicketSLAPausedDuration 计算暂停区间与 [from, to) 重叠的总时长，仍在暂停中的区间计算到 to
+func TicketSLAPausedDuration(pauses []*TicketSLAPause, from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, p := range pauses {
+		start, end := p.PausedAt, to
+		if p.ResumedAt != nil && p.ResumedAt.Before(to) {
+			end = *p.ResumedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// TicketSLAPauseRequest 手动暂停SLA计时请求
+type TicketSLAPauseRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// TicketSLAClock 工单SLA计时状态。Deadline 为顺延暂停时长后的截止时间，暂停中时按当前时刻顺延；
+// 响应和解决耗时不含暂停时长，尚未响应或解决时为空
+type TicketSLAClock struct {
+	TicketID          string            `json:"ticket_id"`
+	Status            TicketSLAStatus   `json:"status,omitempty"`
+	Deadline          *time.Time        `json:"deadline,omitempty"`
+	RemainingSeconds  int64             `json:"remaining_seconds"`
+	Paused            bool              `json:"paused"`
+	PausedSeconds     int64             `json:"paused_seconds"`
+	ElapsedSeconds    int64             `json:"elapsed_seconds"`
+	ResponseSeconds   *int64            `json:"response_seconds,omitempty"`
+	ResolutionSeconds *int64            `json:"resolution_seconds,omitempty"`
+	Pauses            []*TicketSLAPause `json:"pauses"`
+}
+
+// TicketSLAAtRiskWithin 截止时间前这段时间内的工单视为即将超时
+const TicketSLAAtRiskWithin = 2 * time.Hour
+
+// NewTicketSLAClock 根据工单和暂停区间计算 now 时刻的SLA计时状态
+func NewTicketSLAClock(ticket *Ticket, pauses []*TicketSLAPause, now time.Time) *TicketSLAClock {
+	if pauses == nil {
+		pauses = []*TicketSLAPause{}
+	}
+	clock := &TicketSLAClock{TicketID: ticket.ID, Pauses: pauses}
+
+	var open *TicketSLAPause
+	for _, p := range pauses {
+		if p.ResumedAt == nil {
+			open = p
+		}
+	}
+	clock.Paused = open != nil
+
+	// 解决或关闭后计时停止
+	end := now
+	if ticket.ResolvedAt != nil {
+		end = *ticket.ResolvedAt
+	} else if ticket.ClosedAt != nil {
+		end = *ticket.ClosedAt
+	}
+	paused := TicketSLAPausedDuration(pauses, ticket.CreatedAt, end)
+	clock.PausedSeconds = int64(paused.Seconds())
+	clock.ElapsedSeconds = int64((end.Sub(ticket.CreatedAt) - paused).Seconds())
+	clock.ResponseSeconds = ticketSLAActiveSeconds(pauses, ticket.CreatedAt, ticket.FirstResponseAt)
+	if ticket.ResolvedAt != nil {
+		clock.ResolutionSeconds = ticketSLAActiveSeconds(pauses, ticket.CreatedAt, ticket.ResolvedAt)
+	} else {
+		clock.ResolutionSeconds = ticketSLAActiveSeconds(pauses, ticket.CreatedAt, ticket.ClosedAt)
+	}
+
+	if ticket.SLADeadline == nil {
+		return clock
+	}
+	// 已保存的截止时间包含已结束的暂停，进行中的暂停按当前时刻顺延
+	deadline := *ticket.SLADeadline
+	if open != nil {
+		deadline = deadline.Add(open.Duration(now))
+	}
+	clock.Deadline = &deadline
+
+	switch {
+	case clock.Paused:
+		clock.Status = TicketSLAStatusPaused
+	case end.After(deadline):
+		clock.Status = TicketSLAStatusBreached
+	case deadline.Sub(end) <= TicketSLAAtRiskWithin:
+		clock.Status = TicketSLAStatusAtRisk
+	default:
+		clock.Status = TicketSLAStatusOnTrack
+	}
+	if deadline.After(end) {
+		clock.RemainingSeconds = int64(deadline.Sub(end).Seconds())
+	}
+	return clock
+}
+
+// ticketSLAActiveSeconds 返回 from 到 at 之间不含暂停时长的秒数，at 为空时返回空
+func ticketSLAActiveSeconds(pauses []*TicketSLAPause, from time.Time, at *time.Time) *int64 {
+	if at == nil {
+		return nil
+	}
+	seconds := int64((at.Sub(from) - TicketSLAPausedDuration(pauses, from, *at)).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+	return &seconds
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 工单满意度评价的限制
+const (
+	TicketSurveyMinScore = 1
+	TicketSurveyMaxScore = 5
+	// TicketSurveySatisfiedScore 计为满意的最低评分，CSAT 为满意评价占全部评价的百分比
+	TicketSurveySatisfiedScore = 4
+	// TicketSurveyMaxComment 评价意见的最大长度
+	TicketSurveyMaxComment = 2000
+)
+
+// TicketSurvey 工单关闭后发给报告人的满意度调查。团队和处理人在发送时从工单快照，
+// 之后工单转派不影响统计；令牌只保存哈希，原始令牌只出现在评价链接中
+type TicketSurvey struct {
+	ID          string     `json:"id" db:"id"`
+	TicketID    string     `json:"ticket_id" db:"ticket_id"`
+	ReporterID  string     `json:"reporter_id" db:"reporter_id"`
+	AssigneeID  *string    `json:"assignee_id,omitempty" db:"assignee_id"`
+	Team        string     `json:"team" db:"team"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	Score       *int       `json:"score,omitempty" db:"score"`
+	Comment     string     `json:"comment,omitempty" db:"comment"`
+	SentAt      time.Time  `json:"sent_at" db:"sent_at"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+}
+
+// TicketSurveyView 评价链接展示的调查信息，不包含处理人和团队
+type TicketSurveyView struct {
+	TicketNumber string     `json:"ticket_number"`
+	TicketTitle  string     `json:"ticket_title"`
+	Score        *int       `json:"score,omitempty"`
+	Comment      string     `json:"comment,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+}
+
+// TicketSurveyResponse 报告人提交的评价，评价页面以表单提交
+type TicketSurveyResponse struct {
+	Score   int    `json:"score" form:"score"`
+	Comment string `json:"comment" form:"comment"`
+}
+
+// Validate 验证评价
+func (r *TicketSurveyResponse) Validate() error {
+	if r.Score < TicketSurveyMinScore || r.Score > TicketSurveyMaxScore {
+		return fmt.Errorf("%w: 评分必须在%d到%d之间", ErrInvalidInput, TicketSurveyMinScore, TicketSurveyMaxScore)
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > TicketSurveyMaxComment {
+		return fmt.Errorf("%w: 评价意见不能超过%d个字符", ErrInvalidInput, TicketSurveyMaxComment)
+	}
+	return nil
+}
+
+// TicketSurveyGroupBy CSAT 统计的分组方式
+type TicketSurveyGroupBy string
+
+const (
+	TicketSurveyGroupByTeam  TicketSurveyGroupBy = "team"
+	TicketSurveyGroupByAgent TicketSurveyGroupBy = "agent"
+)
+
+// TicketSurveyFilter CSAT 统计范围，按调查发送时间统计 [From, To) 内的调查
+type TicketSurveyFilter struct {
+	From    time.Time
+	To      time.Time
+	Team    *string
+	GroupBy TicketSurveyGroupBy
+}
+
+// Validate 验证统计范围，未指定时统计最近 30 天，按团队分组
+func (f *TicketSurveyFilter) Validate(now time.Time) error {
+	if f.To.IsZero() {
+		f.To = now
+	}
+	if f.From.IsZero() {
+		f.From = f.To.AddDate(0, 0, -30)
+	}
+	if !f.To.After(f.From) {
+		return fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidInput)
+	}
+	if f.GroupBy == "" {
+		f.GroupBy = TicketSurveyGroupByTeam
+	}
+	if f.GroupBy != TicketSurveyGroupByTeam && f.GroupBy != TicketSurveyGroupByAgent {
+		return fmt.Errorf("%w: 分组方式只能为 team 或 agent", ErrInvalidInput)
+	}
+	return nil
+}
+
+// TicketCSAT 一组调查的满意度统计
+type TicketCSAT struct {
+	// Key 团队名或处理人ID，总计时为空
+	Key       string `json:"key" db:"key"`
+	Sent      int64  `json:"sent" db:"sent"`
+	Responses int64  `json:"responses" db:"responses"`
+	Satisfied int64  `json:"satisfied" db:"satisfied"`
+	ScoreSum  int64  `json:"-" db:"score_sum"`
+	// Average 平均评分，没有评价时为 0
+	Average float64 `json:"average" db:"-"`
+	// CSAT 满意评价占全部评价的百分比，没有评价时为 0
+	CSAT float64 `json:"csat" db:"-"`
+	// ResponseRate 已评价的调查占已发送调查的百分比
+	ResponseRate float64 `json:"response_rate" db:"-"`
+}
+
+// Add 累加另一组调查的计数
+func (c *TicketCSAT) Add(other *TicketCSAT) {
+	c.Sent += other.Sent
+	c.Responses += other.Responses
+	c.Satisfied += other.Satisfied
+	c.ScoreSum += other.ScoreSum
+}
+
+// Finalize 根据计数计算平均评分和百分比
+func (c *TicketCSAT) Finalize() {
+	c.Average, c.CSAT, c.ResponseRate = 0, 0, 0
+	if c.Responses > 0 {
+		c.Average = float64(c.ScoreSum) / float64(c.Responses)
+		c.CSAT = float64(c.Satisfied) * 100 / float64(c.Responses)
+	}
+	if c.Sent > 0 {
+		c.ResponseRate = float64(c.Responses) * 100 / float64(c.Sent)
+	}
+}
+
+// TicketCSATReport CSAT 统计结果
+type TicketCSATReport struct {
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	GroupBy TicketSurveyGroupBy `json:"group_by"`
+	Total   *TicketCSAT         `json:"total"`
+	Groups  []*TicketCSAT       `json:"groups"`
+}
@@ -44,6 +44,10 @@ type User struct {
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// 加密存储邮箱和手机号时的盲索引，用于等值查询
+	EmailIndex *string `json:"-" db:"email_index"`
+	PhoneIndex *string `json:"-" db:"phone_index"`
 }
 
 // UserCreateRequest 创建用户请求
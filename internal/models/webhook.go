@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"pulse/internal/pkg/redact"
 )
 
 // WebhookStatus Webhook状态
@@ -47,6 +49,37 @@ type Webhook struct {
 	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
 }
 
+// Redacted 返回用于 API 响应的副本，密钥和敏感请求头替换为 redact.Mask，URL 中的密码同样隐藏
+func (w *Webhook) Redacted() *Webhook {
+	if w == nil {
+		return nil
+	}
+	redacted := *w
+	redacted.URL = redact.URL(w.URL)
+	redacted.Secret = redact.Value(w.Secret)
+	redacted.Headers = redact.Headers(w.Headers)
+	return &redacted
+}
+
+// HasRedacted 判断是否包含未修改的脱敏值
+func (w *Webhook) HasRedacted() bool {
+	values := []string{w.URL}
+	if w.Secret != nil {
+		values = append(values, *w.Secret)
+	}
+	for _, value := range w.Headers {
+		values = append(values, value)
+	}
+	return redact.Masked(values...)
+}
+
+// RestoreRedacted 将更新请求中未修改的脱敏值还原为已保存的值
+func (w *Webhook) RestoreRedacted(stored *Webhook) {
+	w.URL = redact.RestoreURL(w.URL, stored.URL)
+	w.Secret = redact.Restore(w.Secret, stored.Secret)
+	redact.RestoreHeaders(w.Headers, stored.Headers)
+}
+
 // WebhookFilter Webhook查询过滤器
 type WebhookFilter struct {
 	Name      *string        `json:"name,omitempty"`
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WebhookIntegration 告警接入集成，外部系统通过共享密钥签名向平台推送告警
+// 密钥仅在创建和重置时返回一次，存储时加密
+type WebhookIntegration struct {
+	ID           string      `json:"id" db:"id"`
+	Name         string      `json:"name" db:"name"`
+	Description  *string     `json:"description,omitempty" db:"description"`
+	Source       AlertSource `json:"source" db:"source"`
+	Secret       string      `json:"-" db:"secret"`
+	AllowedCIDRs []string    `json:"allowed_cidrs" db:"allowed_cidrs"`
+	Enabled      bool        `json:"enabled" db:"enabled"`
+	// PayloadMapping 自定义 Webhook 的载荷映射，配置后可通过 /api/v1/webhooks/custom/:id 推送任意结构的载荷
+	PayloadMapping *WebhookPayloadMapping `json:"payload_mapping,omitempty" db:"payload_mapping"`
+	LastReceivedAt *time.Time             `json:"last_received_at,omitempty" db:"last_received_at"`
+	CreatedBy      *string                `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookIntegrationRequest 创建或更新告警接入集成请求
+type WebhookIntegrationRequest struct {
+	Name         string      `json:"name" binding:"required,max=200"`
+	Description  *string     `json:"description,omitempty"`
+	Source       AlertSource `json:"source" binding:"required,enum"`
+	AllowedCIDRs []string    `json:"allowed_cidrs,omitempty" binding:"omitempty,max=100"`
+	Enabled      *bool       `json:"enabled,omitempty"`
+	// PayloadMapping 为空时集成只接收标准格式的告警
+	PayloadMapping *WebhookPayloadMapping `json:"payload_mapping,omitempty"`
+}
+
+// ApplyTo 将请求内容应用到集成
+func (r *WebhookIntegrationRequest) ApplyTo(i *WebhookIntegration) {
+	i.Name = strings.TrimSpace(r.Name)
+	i.Description = r.Description
+	i.Source = r.Source
+	i.AllowedCIDRs = r.AllowedCIDRs
+	if i.AllowedCIDRs == nil {
+		i.AllowedCIDRs = []string{}
+	}
+	if r.Enabled != nil {
+		i.Enabled = *r.Enabled
+	}
+	i.PayloadMapping = r.PayloadMapping
+}
+
+// Validate 验证告警接入集成
+func (i *WebhookIntegration) Validate() error {
+	if i.Name == "" {
+		return fmt.Errorf("%w: 集成名称不能为空", ErrInvalidInput)
+	}
+	if !i.Source.IsValid() || i.Source.IsInternal() {
+		return fmt.Errorf("%w: 无效的告警来源", ErrInvalidInput)
+	}
+	if i.PayloadMapping != nil {
+		if err := i.PayloadMapping.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookIntegrationSecret 创建或重置密钥后返回的集成和明文密钥
+type WebhookIntegrationSecret struct {
+	Integration *WebhookIntegration `json:"integration"`
+	Secret      string              `json:"secret"`
+}
+
+// WebhookPayloadMapping 自定义 Webhook 载荷到告警字段的映射
+//
+// 路径使用 JSONPath，$ 为整个载荷，@ 为 AlertsPath 选出的单条告警；未配置 AlertsPath 时整个载荷是一条告警，@ 与 $ 相同。
+// 模板中的 ${路径} 替换为路径的值，如 "${@.host} 磁盘使用率 ${@.value}%"。
+// 状态映射为 resolved 的告警解决同一指纹的未解决告警，其他值均视为 firing
+type WebhookPayloadMapping struct {
+	AlertsPath   string              `json:"alerts_path,omitempty"`
+	Name         WebhookFieldMapping `json:"name"`
+	Description  WebhookFieldMapping `json:"description,omitempty"`
+	Severity     WebhookFieldMapping `json:"severity"`
+	Status       WebhookFieldMapping `json:"status,omitempty"`
+	Fingerprint  WebhookFieldMapping `json:"fingerprint,omitempty"`
+	Expression   WebhookFieldMapping `json:"expression,omitempty"`
+	Value        WebhookFieldMapping `json:"value,omitempty"`
+	StartsAt     WebhookFieldMapping `json:"starts_at,omitempty"`
+	GeneratorURL WebhookFieldMapping `json:"generator_url,omitempty"`
+	// LabelsPath 指向一个对象，对象的字段全部作为标签，Labels 中的同名标签优先
+	LabelsPath  string                         `json:"labels_path,omitempty"`
+	Labels      map[string]WebhookFieldMapping `json:"labels,omitempty"`
+	Annotations map[string]WebhookFieldMapping `json:"annotations,omitempty"`
+}
+
+// Validate 验证映射的必填字段，路径语法由服务层编译时检查
+func (m *WebhookPayloadMapping) Validate() error {
+	if m.Name.IsEmpty() {
+		return fmt.Errorf("%w: 载荷映射必须配置告警名称", ErrInvalidInput)
+	}
+	if m.Severity.IsEmpty() {
+		return fmt.Errorf("%w: 载荷映射必须配置告警级别", ErrInvalidInput)
+	}
+	return nil
+}
+
+// WebhookFieldMapping 单个字段的取值方式，按 Template、Path 的顺序取值，
+// 取到的值在 Values 中时替换为对应的值，结果为空时使用 Default
+type WebhookFieldMapping struct {
+	Path     string            `json:"path,omitempty"`
+	Template string            `json:"template,omitempty"`
+	Values   map[string]string `json:"values,omitempty"`
+	Default  string            `json:"default,omitempty"`
+}
+
+// IsEmpty 是否未配置任何取值方式
+func (f WebhookFieldMapping) IsEmpty() bool {
+	return f.Path == "" && f.Template == "" && f.Default == ""
+}
+
+// CustomWebhookResult 自定义 Webhook 载荷的处理结果
+type CustomWebhookResult struct {
+	Received int `json:"received"`
+	Created  int `json:"created"`
+	Updated  int `json:"updated"`
+	Resolved int `json:"resolved"`
+	// Skipped 已解决且没有对应未解决告警的条目
+	Skipped int `json:"skipped"`
+	// Errors 映射失败的条目，不影响其他条目
+	Errors []string `json:"errors,omitempty"`
+}
+
+// WebhookMappingPreviewRequest 预览载荷映射请求
+type WebhookMappingPreviewRequest struct {
+	Mapping WebhookPayloadMapping `json:"mapping"`
+	Payload json.RawMessage       `json:"payload" binding:"required"`
+}
+
+// WebhookMappingPreview 载荷映射预览结果，映射为 resolved 的告警状态为 resolved
+type WebhookMappingPreview struct {
+	Alerts []*Alert `json:"alerts"`
+	Errors []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 出站 Webhook 订阅的工单事件，告警事件见 WebhookEventAlertCreated 等
+const (
+	WebhookEventTicketCreated  WebhookEvent = "ticket.created"
+	WebhookEventTicketUpdated  WebhookEvent = "ticket.updated"
+	WebhookEventTicketAssigned WebhookEvent = "ticket.assigned"
+	WebhookEventTicketClosed   WebhookEvent = "ticket.closed"
+)
+
+// SubscribableWebhookEvents 出站 Webhook 可以订阅的事件
+var SubscribableWebhookEvents = []WebhookEvent{
+	WebhookEventAlertCreated,
+	WebhookEventAlertUpdated,
+	WebhookEventAlertAcknowledged,
+	WebhookEventAlertResolved,
+	WebhookEventTicketCreated,
+	WebhookEventTicketUpdated,
+	WebhookEventTicketAssigned,
+	WebhookEventTicketClosed,
+}
+
+// 出站 Webhook 订阅的限制
+const (
+	// WebhookSubscriptionMaxAttempts 单个事件最多投递的次数上限
+	WebhookSubscriptionMaxAttempts = 20
+	// WebhookSubscriptionMaxEvents 单个订阅最多订阅的事件模式数
+	WebhookSubscriptionMaxEvents = 20
+)
+
+// WebhookSubscription 出站 Webhook 订阅，订阅的告警和工单事件按发生顺序逐个投递到 URL，
+// 请求按 signature 包的规则使用订阅密钥签名。密钥仅在创建和重置时返回一次，存储时加密
+type WebhookSubscription struct {
+	ID     string `json:"id" db:"id"`
+	Name   string `json:"name" db:"name"`
+	URL    string `json:"url" db:"url"`
+	Secret string `json:"-" db:"secret"`
+	// Events 订阅的事件模式，如 alert.*、ticket.created，* 订阅全部事件
+	Events  []string `json:"events" db:"events"`
+	Enabled bool     `json:"enabled" db:"enabled"`
+	// MaxAttempts 单个事件最多投递的次数，为 0 时使用全局配置
+	MaxAttempts int `json:"max_attempts" db:"max_attempts"`
+	// DisableAfterFailures 连续多少个事件投递失败后停用订阅，为 0 时使用全局配置
+	DisableAfterFailures int `json:"disable_after_failures" db:"disable_after_failures"`
+	// ConsecutiveFailures 连续投递失败的事件数，投递成功或重新启用后清零
+	ConsecutiveFailures int `json:"consecutive_failures" db:"consecutive_failures"`
+	// DisabledReason 因连续投递失败被自动停用的原因
+	DisabledReason  *string    `json:"disabled_reason,omitempty" db:"disabled_reason"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty" db:"last_delivered_at"`
+	CreatedBy       *string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes 判断订阅是否包含事件
+func (s *WebhookSubscription) Subscribes(event WebhookEvent) bool {
+	for _, pattern := range s.Events {
+		if webhookEventMatches(pattern, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookEventMatches 判断事件是否匹配事件模式，模式为 *、<资源>.* 或完整的事件名
+func webhookEventMatches(pattern string, event WebhookEvent) bool {
+	if pattern == "*" || pattern == string(event) {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(string(event), prefix+".")
+	}
+	return false
+}
+
+// WebhookSubscriptionRequest 创建或更新出站 Webhook 订阅请求
+type WebhookSubscriptionRequest struct {
+	Name                 string   `json:"name" binding:"required,max=200"`
+	URL                  string   `json:"url" binding:"required"`
+	Events               []string `json:"events" binding:"required,min=1"`
+	Enabled              *bool    `json:"enabled,omitempty"`
+	MaxAttempts          int      `json:"max_attempts" binding:"omitempty,min=0"`
+	DisableAfterFailures int      `json:"disable_after_failures" binding:"omitempty,min=0"`
+}
+
+// ApplyTo 将请求内容应用到订阅
+func (r *WebhookSubscriptionRequest) ApplyTo(s *WebhookSubscription) {
+	s.Name = strings.TrimSpace(r.Name)
+	s.URL = strings.TrimSpace(r.URL)
+	s.Events = make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		s.Events = append(s.Events, strings.TrimSpace(event))
+	}
+	if r.Enabled != nil {
+		s.Enabled = *r.Enabled
+	}
+	s.MaxAttempts = r.MaxAttempts
+	s.DisableAfterFailures = r.DisableAfterFailures
+}
+
+// Validate 验证出站 Webhook 订阅
+func (s *WebhookSubscription) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: 订阅名称不能为空", ErrInvalidInput)
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: 无效的订阅地址 %q", ErrInvalidInput, s.URL)
+	}
+	if len(s.Events) == 0 || len(s.Events) > WebhookSubscriptionMaxEvents {
+		return fmt.Errorf("%w: 订阅的事件数必须在1到%d之间", ErrInvalidInput, WebhookSubscriptionMaxEvents)
+	}
+	for _, pattern := range s.Events {
+		if !validWebhookEventPattern(pattern) {
+			return fmt.Errorf("%w: 无效的订阅事件 %q", ErrInvalidInput, pattern)
+		}
+	}
+	if s.MaxAttempts < 0 || s.MaxAttempts > WebhookSubscriptionMaxAttempts {
+		return fmt.Errorf("%w: 最多投递次数不能超过%d", ErrInvalidInput, WebhookSubscriptionMaxAttempts)
+	}
+	if s.DisableAfterFailures < 0 {
+		return fmt.Errorf("%w: 停用前的连续失败数不能为负数", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validWebhookEventPattern 事件模式至少匹配一个可订阅的事件
+func validWebhookEventPattern(pattern string) bool {
+	for _, event := range SubscribableWebhookEvents {
+		if webhookEventMatches(pattern, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscriptionSecret 创建或重置密钥后返回的订阅和明文密钥
+type WebhookSubscriptionSecret struct {
+	Subscription *WebhookSubscription `json:"subscription"`
+	Secret       string               `json:"secret"`
+}
+
+// WebhookEventPayload 投递给订阅的事件内容
+type WebhookEventPayload struct {
+	// ID 事件 ID，同一事件投递给不同订阅时相同，重试时不变，接收端可据此去重
+	ID         string       `json:"id"`
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	// Data 事件关联的告警或工单
+	Data interface{} `json:"data"`
+}
+
+// WebhookDeliveryStatus 事件投递状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // 等待投递或重试
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // 接收端返回 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // 达到最多投递次数仍失败
+)
+
+// WebhookDelivery 事件投递记录，同一订阅的事件按 Seq 顺序投递，前一个事件完成前不投递后续事件
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	SubscriptionID string                `json:"subscription_id" db:"subscription_id"`
+	Seq            int64                 `json:"seq" db:"seq"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	Event          WebhookEvent          `json:"event" db:"event"`
+	Payload        string                `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	// LastStatusCode 最近一次投递的 HTTP 状态码，请求未发出或无响应时为空
+	LastStatusCode *int       `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastResponse   *string    `json:"last_response,omitempty" db:"last_response"`
+	LastError      *string    `json:"last_error,omitempty" db:"last_error"`
+	LastDurationMs int64      `json:"last_duration_ms" db:"last_duration_ms"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDeliveryFilter 事件投递记录过滤器
+type WebhookDeliveryFilter struct {
+	Status   *WebhookDeliveryStatus `json:"status,omitempty"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// WorklogType 工作日志类型
+type WorklogType string
+
+const (
+	WorklogTypeInvestigation WorklogType = "investigation" // 排查
+	WorklogTypeDevelopment   WorklogType = "development"   // 开发
+	WorklogTypeTesting       WorklogType = "testing"       // 测试
+	WorklogTypeDocumentation WorklogType = "documentation" // 文档
+	WorklogTypeCommunication WorklogType = "communication" // 沟通
+	WorklogTypeOther         WorklogType = "other"         // 其他
+)
+
+// TicketWorklog 工单工作日志
+type TicketWorklog struct {
+	ID              string      `json:"id" db:"id"`
+	TicketID        string      `json:"ticket_id" db:"ticket_id"`
+	UserID          string      `json:"user_id" db:"worker_id"`
+	Description     string      `json:"description" db:"description"`
+	WorkType        WorklogType `json:"work_type" db:"work_type"`
+	StartedAt       time.Time   `json:"started_at" db:"start_time"`
+	EndedAt         *time.Time  `json:"ended_at,omitempty" db:"end_time"`
+	DurationMinutes int         `json:"duration_minutes" db:"duration_minutes"`
+	Billable        bool        `json:"billable" db:"billable"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// TicketWorklogRequest 创建/更新工作日志请求
+type TicketWorklogRequest struct {
+	DurationMinutes int          `json:"duration_minutes" binding:"required,min=1"`
+	Description     string       `json:"description" binding:"required,min=1,max=2000"`
+	WorkType        *WorklogType `json:"work_type,omitempty"`
+	StartedAt       *time.Time   `json:"started_at,omitempty"`
+	Billable        *bool        `json:"billable,omitempty"`
+}
+
+// TicketWorkTime 工单工时汇总
+type TicketWorkTime struct {
+	TicketID   string        `json:"ticket_id"`
+	EntryCount int64         `json:"entry_count"`
+	WorkTime   time.Duration `json:"work_time"`   // 计费工时
+	ActualTime time.Duration `json:"actual_time"` // 全部登记工时
+}
+
+// TimesheetFilter 工时报表过滤器
+type TimesheetFilter struct {
+	UserID    *string   `json:"user_id,omitempty"`
+	TeamID    *string   `json:"team_id,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	GroupBy   string    `json:"group_by"` // user, team
+}
+
+// TimesheetEntry 工时报表条目
+type TimesheetEntry struct {
+	UserID          *string `json:"user_id,omitempty"`
+	TeamID          *string `json:"team_id,omitempty"`
+	TicketCount     int64   `json:"ticket_count"`
+	EntryCount      int64   `json:"entry_count"`
+	TotalMinutes    int64   `json:"total_minutes"`
+	BillableMinutes int64   `json:"billable_minutes"`
+}
+
+// Timesheet 工时报表
+type Timesheet struct {
+	StartTime       time.Time         `json:"start_time"`
+	EndTime         time.Time         `json:"end_time"`
+	GroupBy         string            `json:"group_by"`
+	Entries         []*TimesheetEntry `json:"entries"`
+	TotalMinutes    int64             `json:"total_minutes"`
+	BillableMinutes int64             `json:"billable_minutes"`
+}
+
+// IsValid 检查工作日志类型是否有效
+func (t WorklogType) IsValid() bool {
+	switch t {
+	case WorklogTypeInvestigation, WorklogTypeDevelopment, WorklogTypeTesting,
+		WorklogTypeDocumentation, WorklogTypeCommunication, WorklogTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate 验证工作日志
+func (w *TicketWorklog) Validate() error {
+	if strings.TrimSpace(w.TicketID) == "" {
+		return errors.New("工单ID不能为空")
+	}
+
+	if strings.TrimSpace(w.UserID) == "" {
+		return errors.New("登记人不能为空")
+	}
+
+	if strings.TrimSpace(w.Description) == "" {
+		return errors.New("工作内容不能为空")
+	}
+
+	if w.DurationMinutes <= 0 {
+		return errors.New("工作时长必须大于0")
+	}
+
+	if w.DurationMinutes > 24*60 {
+		return errors.New("单条工作时长不能超过24小时")
+	}
+
+	if !w.WorkType.IsValid() {
+		return errors.New("无效的工作日志类型")
+	}
+
+	if w.StartedAt.IsZero() {
+		return errors.New("开始时间不能为空")
+	}
+
+	if w.StartedAt.After(time.Now()) {
+		return errors.New("开始时间不能晚于当前时间")
+	}
+
+	return nil
+}
+
+// Validate 验证工作日志请求
+func (req *TicketWorklogRequest) Validate() error {
+	if req.DurationMinutes <= 0 {
+		return errors.New("工作时长必须大于0")
+	}
+
+	if req.DurationMinutes > 24*60 {
+		return errors.New("单条工作时长不能超过24小时")
+	}
+
+	if strings.TrimSpace(req.Description) == "" {
+		return errors.New("工作内容不能为空")
+	}
+
+	if len(req.Description) > 2000 {
+		return errors.New("工作内容长度不能超过2000个字符")
+	}
+
+	if req.WorkType != nil && !req.WorkType.IsValid() {
+		return errors.New("无效的工作日志类型")
+	}
+
+	return nil
+}
+
+// Validate 验证工时报表过滤器
+func (f *TimesheetFilter) Validate() error {
+	if f.StartTime.IsZero() || f.EndTime.IsZero() {
+		return errors.New("报表时间范围不能为空")
+	}
+
+	if !f.EndTime.After(f.StartTime) {
+		return errors.New("结束时间必须晚于开始时间")
+	}
+
+	if f.EndTime.Sub(f.StartTime) > 366*24*time.Hour {
+		return errors.New("报表时间范围不能超过一年")
+	}
+
+	switch f.GroupBy {
+	case "", "user", "team":
+	default:
+		return errors.New("无效的分组方式")
+	}
+
+	return nil
+}
+
+// GetDuration 获取工作时长
+func (w *TicketWorklog) GetDuration() time.Duration {
+	return time.Duration(w.DurationMinutes) * time.Minute
+}
+
+// GetDisplayName 获取显示名称
+func (t WorklogType) GetDisplayName() string {
+	switch t {
+	case WorklogTypeInvestigation:
+		return "排查"
+	case WorklogTypeDevelopment:
+		return "开发"
+	case WorklogTypeTesting:
+		return "测试"
+	case WorklogTypeDocumentation:
+		return "文档"
+	case WorklogTypeCommunication:
+		return "沟通"
+	case WorklogTypeOther:
+		return "其他"
+	default:
+		return string(t)
+	}
+}
@@ -0,0 +1,23 @@
+package monitor
+
+import "context"
+
+// optionalCheck 非关键依赖的健康检查，不健康时降级而不是让整体状态变为不健康
+type optionalCheck struct {
+	HealthCheck
+}
+
+// NewOptionalCheck 包装非关键依赖的健康检查
+// 依赖故障时服务仍可降级运行（如 Redis、外部数据源），就绪检查不应因此摘除实例
+func NewOptionalCheck(check HealthCheck) HealthCheck {
+	return &optionalCheck{HealthCheck: check}
+}
+
+// Check 执行健康检查，将不健康结果降级
+func (o *optionalCheck) Check(ctx context.Context) HealthResult {
+	result := o.HealthCheck.Check(ctx)
+	if result.Status == HealthStatusUnhealthy {
+		result.Status = HealthStatusDegraded
+	}
+	return result
+}
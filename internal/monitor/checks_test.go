@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pulse/internal/models"
+)
+
+// stubCheck 返回固定结果的健康检查
+type stubCheck struct {
+	status HealthStatus
+}
+
+func (s *stubCheck) Name() string { return "stub" }
+
+func (s *stubCheck) Check(ctx context.Context) HealthResult {
+	return HealthResult{Status: s.status}
+}
+
+// stubDataSourceTester 按ID返回连接测试结果的数据源服务
+type stubDataSourceTester struct {
+	dataSources []*models.DataSource
+	failing     map[string]bool
+}
+
+func (s *stubDataSourceTester) List(ctx context.Context, filter *models.DataSourceFilter) ([]*models.DataSource, int64, error) {
+	return s.dataSources, int64(len(s.dataSources)), nil
+}
+
+func (s *stubDataSourceTester) TestConnection(ctx context.Context, id string) error {
+	if s.failing[id] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestOptionalCheck(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, HealthStatusDegraded, NewOptionalCheck(&stubCheck{status: HealthStatusUnhealthy}).Check(ctx).Status)
+	assert.Equal(t, HealthStatusHealthy, NewOptionalCheck(&stubCheck{status: HealthStatusHealthy}).Check(ctx).Status)
+	assert.Equal(t, "stub", NewOptionalCheck(&stubCheck{}).Name())
+}
+
+func TestDataSourceHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	tester := &stubDataSourceTester{
+		dataSources: []*models.DataSource{
+			{ID: "prom", Name: "prometheus", Type: models.DataSourceTypePrometheus},
+			{ID: "es", Name: "elasticsearch", Type: models.DataSourceTypeElastic},
+		},
+		failing: map[string]bool{},
+	}
+	check := NewDataSourceHealthCheck("datasources", tester)
+
+	assert.Equal(t, HealthStatusHealthy, check.Check(ctx).Status)
+
+	tester.failing["es"] = true
+	result := check.Check(ctx)
+	assert.Equal(t, HealthStatusDegraded, result.Status)
+	assert.Equal(t, false, result.Details["es"].(map[string]interface{})["healthy"])
+
+	tester.failing["prom"] = true
+	assert.Equal(t, HealthStatusUnhealthy, check.Check(ctx).Status)
+
+	tester.dataSources = nil
+	assert.Equal(t, HealthStatusHealthy, check.Check(ctx).Status)
+}
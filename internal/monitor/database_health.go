@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Pinger 可探测连接的数据库连接池，*sql.DB 和 *sqlx.DB 均满足
+type Pinger interface {
+	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+// DatabaseHealthCheck 数据库健康检查
+type DatabaseHealthCheck struct {
+	name string
+	db   Pinger
+}
+
+// NewDatabaseHealthCheck 创建数据库健康检查
+func NewDatabaseHealthCheck(name string, db Pinger) *DatabaseHealthCheck {
+	return &DatabaseHealthCheck{
+		name: name,
+		db:   db,
+	}
+}
+
+// Name 返回健康检查名称
+func (d *DatabaseHealthCheck) Name() string {
+	return d.name
+}
+
+// Check 执行数据库健康检查，连接池已满且有请求在等待时标记为降级
+func (d *DatabaseHealthCheck) Check(ctx context.Context) HealthResult {
+	start := time.Now()
+	result := HealthResult{
+		Timestamp: start,
+		Details:   make(map[string]interface{}),
+	}
+
+	if err := d.db.PingContext(ctx); err != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Message = fmt.Sprintf("Database ping failed: %v", err)
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	stats := d.db.Stats()
+	result.Details["pool_stats"] = map[string]interface{}{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+	}
+
+	if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+		result.Status = HealthStatusDegraded
+		result.Message = "Database connection pool exhausted"
+	} else {
+		result.Status = HealthStatusHealthy
+		result.Message = "Database is healthy"
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// dataSourceCheckPageSize 每次检查的活跃数据源数量上限
+const dataSourceCheckPageSize = 100
+
+// DataSourceTester 数据源查询和连接测试，由数据源服务实现
+type DataSourceTester interface {
+	List(ctx context.Context, filter *models.DataSourceFilter) ([]*models.DataSource, int64, error)
+	TestConnection(ctx context.Context, id string) error
+}
+
+// DataSourceHealthCheck 活跃数据源连接健康检查
+type DataSourceHealthCheck struct {
+	name   string
+	tester DataSourceTester
+}
+
+// NewDataSourceHealthCheck 创建数据源健康检查
+func NewDataSourceHealthCheck(name string, tester DataSourceTester) *DataSourceHealthCheck {
+	return &DataSourceHealthCheck{
+		name:   name,
+		tester: tester,
+	}
+}
+
+// Name 返回健康检查名称
+func (d *DataSourceHealthCheck) Name() string {
+	return d.name
+}
+
+// Check 并发测试所有活跃数据源的连接，任一失败时标记为降级，全部失败时标记为不健康
+func (d *DataSourceHealthCheck) Check(ctx context.Context) HealthResult {
+	start := time.Now()
+	result := HealthResult{
+		Timestamp: start,
+		Details:   make(map[string]interface{}),
+	}
+
+	status := models.DataSourceStatusActive
+	dataSources, _, err := d.tester.List(ctx, &models.DataSourceFilter{
+		Status:   &status,
+		Page:     1,
+		PageSize: dataSourceCheckPageSize,
+	})
+	if err != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Message = fmt.Sprintf("Failed to list data sources: %v", err)
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	for _, ds := range dataSources {
+		wg.Add(1)
+		go func(ds *models.DataSource) {
+			defer wg.Done()
+
+			err := d.tester.TestConnection(ctx, ds.ID)
+			detail := map[string]interface{}{"name": ds.Name, "type": ds.Type, "healthy": err == nil}
+			if err != nil {
+				detail["error"] = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+			}
+			result.Details[ds.ID] = detail
+		}(ds)
+	}
+	wg.Wait()
+
+	switch {
+	case len(dataSources) == 0:
+		result.Status = HealthStatusHealthy
+		result.Message = "No active data sources"
+	case failed == len(dataSources):
+		result.Status = HealthStatusUnhealthy
+		result.Message = "All active data sources are unreachable"
+	case failed > 0:
+		result.Status = HealthStatusDegraded
+		result.Message = fmt.Sprintf("%d of %d active data sources are unreachable", failed, len(dataSources))
+	default:
+		result.Status = HealthStatusHealthy
+		result.Message = "All active data sources are reachable"
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"strings"
+	"time"
+)
+
+// startTime 进程启动时间，用于计算运行时长
+var startTime = time.Now()
+
+// RuntimeStats 进程运行时概况
+type RuntimeStats struct {
+	GoVersion     string        `json:"go_version"`
+	NumCPU        int           `json:"num_cpu"`
+	GOMAXPROCS    int           `json:"gomaxprocs"`
+	NumGoroutine  int           `json:"num_goroutine"`
+	Uptime        time.Duration `json:"uptime"`
+	HeapAlloc     uint64        `json:"heap_alloc"`
+	HeapInuse     uint64        `json:"heap_inuse"`
+	HeapObjects   uint64        `json:"heap_objects"`
+	Sys           uint64        `json:"sys"`
+	NumGC         uint32        `json:"num_gc"`
+	LastGC        time.Time     `json:"last_gc"`
+	PauseTotal    time.Duration `json:"pause_total"`
+	GCCPUFraction float64       `json:"gc_cpu_fraction"`
+}
+
+// snapshotProfiles 可下载快照的 profile 及其文件扩展名，goroutine 为文本格式的完整调用栈
+var snapshotProfiles = map[string]string{
+	"goroutine":    "txt",
+	"heap":         "pb.gz",
+	"allocs":       "pb.gz",
+	"threadcreate": "pb.gz",
+	"block":        "pb.gz",
+	"mutex":        "pb.gz",
+}
+
+// DiagnosticsHandler 运行时诊断 HTTP 处理器：pprof、expvar、运行时概况和 profile 快照下载，
+// 所有端点挂载在 prefix 下，调用方负责访问控制
+type DiagnosticsHandler struct {
+	prefix string
+	mux    *http.ServeMux
+}
+
+// NewDiagnosticsHandler 创建挂载在 prefix（如 /api/v1/admin/debug）下的诊断处理器：
+//
+//	{prefix}/pprof/          pprof 索引和各 profile
+//	{prefix}/vars            expvar 变量
+//	{prefix}/runtime         运行时概况
+//	{prefix}/snapshot/{name} 下载 goroutine、heap 等 profile 快照
+func NewDiagnosticsHandler(prefix string) *DiagnosticsHandler {
+	prefix = strings.TrimRight(prefix, "/")
+	h := &DiagnosticsHandler{prefix: prefix, mux: http.NewServeMux()}
+	h.mux.HandleFunc(prefix+"/pprof/", h.handlePprof)
+	h.mux.HandleFunc(prefix+"/pprof/cmdline", pprof.Cmdline)
+	h.mux.HandleFunc(prefix+"/pprof/profile", pprof.Profile)
+	h.mux.HandleFunc(prefix+"/pprof/symbol", pprof.Symbol)
+	h.mux.HandleFunc(prefix+"/pprof/trace", pprof.Trace)
+	h.mux.Handle(prefix+"/vars", expvar.Handler())
+	h.mux.HandleFunc(prefix+"/runtime", h.handleRuntime)
+	h.mux.HandleFunc(prefix+"/snapshot/", h.handleSnapshot)
+	return h
+}
+
+// ServeHTTP 分发诊断请求
+func (h *DiagnosticsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.mux.ServeHTTP(w, r)
+}
+
+// handlePprof 返回 pprof 索引页或指定名称的 profile。
+// pprof.Index 只识别 /debug/pprof/ 前缀，挂载在其他前缀下时按名称单独处理
+func (h *DiagnosticsHandler) handlePprof(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, h.prefix+"/pprof/")
+	if name == "" {
+		pprof.Index(w, r)
+		return
+	}
+	if rpprof.Lookup(name) == nil {
+		http.Error(w, "unknown profile: "+name, http.StatusNotFound)
+		return
+	}
+	pprof.Handler(name).ServeHTTP(w, r)
+}
+
+// handleRuntime 返回运行时概况
+func (h *DiagnosticsHandler) handleRuntime(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(CollectRuntimeStats()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleSnapshot 以附件形式下载 profile 快照，文件名包含主机名和时间便于归档
+func (h *DiagnosticsHandler) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, h.prefix+"/snapshot/")
+	ext, ok := snapshotProfiles[name]
+	profile := rpprof.Lookup(name)
+	if !ok || profile == nil {
+		http.Error(w, "unknown profile: "+name, http.StatusNotFound)
+		return
+	}
+
+	// goroutine 输出完整调用栈文本，其他 profile 输出 go tool pprof 可读取的 protobuf 格式
+	debugLevel := 0
+	contentType := "application/octet-stream"
+	if name == "goroutine" {
+		debugLevel = 2
+		contentType = "text/plain; charset=utf-8"
+	}
+	if name == "heap" && r.URL.Query().Get("gc") == "1" {
+		runtime.GC()
+	}
+
+	host, _ := os.Hostname()
+	filename := fmt.Sprintf("%s-%s-%s.%s", name, host, time.Now().UTC().Format("20060102T150405Z"), ext)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := profile.WriteTo(w, debugLevel); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// CollectRuntimeStats 采集运行时概况
+func CollectRuntimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+
+	return RuntimeStats{
+		GoVersion:     runtime.Version(),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumGoroutine:  runtime.NumGoroutine(),
+		Uptime:        time.Since(startTime),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		HeapObjects:   mem.HeapObjects,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		LastGC:        gc.LastGC,
+		PauseTotal:    gc.PauseTotal,
+		GCCPUFraction: mem.GCCPUFraction,
+	}
+}
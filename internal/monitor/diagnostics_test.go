@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticsHandler(t *testing.T) {
+	handler := NewDiagnosticsHandler("/api/v1/admin/debug/")
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/v1/admin/debug/pprof/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine")
+
+	w = get("/api/v1/admin/debug/pprof/goroutine?debug=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine profile")
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/admin/debug/pprof/unknown").Code)
+
+	w = get("/api/v1/admin/debug/runtime")
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats RuntimeStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Positive(t, stats.NumGoroutine)
+	assert.NotEmpty(t, stats.GoVersion)
+
+	w = get("/api/v1/admin/debug/vars")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "memstats")
+
+	w = get("/api/v1/admin/debug/snapshot/goroutine")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Disposition"), `attachment; filename="goroutine-`))
+	assert.Contains(t, w.Body.String(), "goroutine ")
+
+	w = get("/api/v1/admin/debug/snapshot/heap")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".pb.gz")
+	assert.NotZero(t, w.Body.Len())
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/admin/debug/snapshot/cpu").Code)
+}
@@ -222,6 +222,11 @@ func (h *HealthHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
 		
 		metrics["checks"].(map[string]interface{})[name] = checkMetrics
 	}
+
+	// 连接池、慢查询等运行指标
+	for name, value := range h.monitor.GetMetrics() {
+		metrics[name] = value
+	}
 	
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(metrics)
@@ -57,8 +57,12 @@ type HealthMonitor struct {
 	running  bool
 	cancel   context.CancelFunc
 	callbacks []HealthCallback
+	metrics  map[string]MetricsFunc
 }
 
+// MetricsFunc 返回一组运行指标，在 /health/metrics 请求时调用
+type MetricsFunc func() map[string]interface{}
+
 // HealthCallback 健康状态变化回调
 type HealthCallback func(name string, oldResult, newResult HealthResult)
 
@@ -67,6 +71,7 @@ func NewHealthMonitor(interval, timeout time.Duration) *HealthMonitor {
 	return &HealthMonitor{
 		checks:   make(map[string]HealthCheck),
 		results:  make(map[string]HealthResult),
+		metrics:  make(map[string]MetricsFunc),
 		interval: interval,
 		timeout:  timeout,
 	}
@@ -94,6 +99,29 @@ func (h *HealthMonitor) AddCallback(callback HealthCallback) {
 	h.callbacks = append(h.callbacks, callback)
 }
 
+// AddMetrics 添加运行指标，/health/metrics 以 name 为键输出
+func (h *HealthMonitor) AddMetrics(name string, fn MetricsFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.metrics[name] = fn
+}
+
+// GetMetrics 收集所有运行指标
+func (h *HealthMonitor) GetMetrics() map[string]interface{} {
+	h.mu.RLock()
+	fns := make(map[string]MetricsFunc, len(h.metrics))
+	for name, fn := range h.metrics {
+		fns[name] = fn
+	}
+	h.mu.RUnlock()
+
+	metrics := make(map[string]interface{}, len(fns))
+	for name, fn := range fns {
+		metrics[name] = fn()
+	}
+	return metrics
+}
+
 // Start 启动健康监控
 func (h *HealthMonitor) Start(ctx context.Context) {
 	h.mu.Lock()
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 队列键名约定，与 queue.RedisQueue 保持一致：待消费消息在 queue:<topic>，
+// 处理中在 queue:<topic>:processing，死信在 queue:<topic>:dead
+const (
+	queueKeyPrefix        = "queue:"
+	queueProcessingSuffix = ":processing"
+	queueDeadSuffix       = ":dead"
+	queueDelayedKey       = "queue:delayed"
+)
+
+// QueueLagHealthCheck 消息队列消费延迟健康检查
+// 以每个主题最早一条待消费消息的等待时长作为消费延迟，只读不写，不影响业务队列
+type QueueLagHealthCheck struct {
+	name        string
+	redisClient *redis.Client
+	maxLag      time.Duration
+}
+
+// NewQueueLagHealthCheck 创建消费延迟健康检查，任一主题延迟超过 maxLag 时标记为降级
+func NewQueueLagHealthCheck(name string, redisClient *redis.Client, maxLag time.Duration) *QueueLagHealthCheck {
+	return &QueueLagHealthCheck{
+		name:        name,
+		redisClient: redisClient,
+		maxLag:      maxLag,
+	}
+}
+
+// Name 返回健康检查名称
+func (q *QueueLagHealthCheck) Name() string {
+	return q.name
+}
+
+// Check 执行消费延迟检查
+func (q *QueueLagHealthCheck) Check(ctx context.Context) HealthResult {
+	start := time.Now()
+	result := HealthResult{
+		Timestamp: start,
+		Details:   make(map[string]interface{}),
+	}
+
+	topics, err := q.topics(ctx)
+	if err != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Message = fmt.Sprintf("Failed to list queues: %v", err)
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var maxLag time.Duration
+	var lagging []string
+	for _, topic := range topics {
+		key := queueKeyPrefix + topic
+		pending, err := q.redisClient.LLen(ctx, key).Result()
+		if err != nil {
+			result.Status = HealthStatusUnhealthy
+			result.Message = fmt.Sprintf("Failed to get queue length: %v", err)
+			result.Error = err
+			result.Duration = time.Since(start)
+			return result
+		}
+		processing, _ := q.redisClient.LLen(ctx, key+queueProcessingSuffix).Result()
+		dead, _ := q.redisClient.LLen(ctx, key+queueDeadSuffix).Result()
+
+		// 消息从左侧入队、右侧出队，最右侧为最早的待消费消息
+		var lag time.Duration
+		if pending > 0 {
+			if oldest, err := q.redisClient.LIndex(ctx, key, -1).Result(); err == nil {
+				var msg struct {
+					CreatedAt time.Time `json:"created_at"`
+				}
+				if json.Unmarshal([]byte(oldest), &msg) == nil && !msg.CreatedAt.IsZero() {
+					lag = time.Since(msg.CreatedAt)
+				}
+			}
+		}
+
+		result.Details[topic] = map[string]interface{}{
+			"pending":     pending,
+			"processing":  processing,
+			"dead":        dead,
+			"lag_seconds": lag.Seconds(),
+		}
+		if lag > maxLag {
+			maxLag = lag
+		}
+		if q.maxLag > 0 && lag > q.maxLag {
+			lagging = append(lagging, topic)
+		}
+	}
+
+	result.Details["max_lag_seconds"] = maxLag.Seconds()
+	if len(lagging) > 0 {
+		result.Status = HealthStatusDegraded
+		result.Message = fmt.Sprintf("Consumer lag exceeds %s on: %s", q.maxLag, strings.Join(lagging, ", "))
+	} else {
+		result.Status = HealthStatusHealthy
+		result.Message = "Queue consumers are keeping up"
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// topics 列出所有待消费队列的主题
+func (q *QueueLagHealthCheck) topics(ctx context.Context) ([]string, error) {
+	var topics []string
+	iter := q.redisClient.Scan(ctx, 0, queueKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if key == queueDelayedKey ||
+			strings.HasSuffix(key, queueProcessingSuffix) ||
+			strings.HasSuffix(key, queueDeadSuffix) {
+			continue
+		}
+		topics = append(topics, strings.TrimPrefix(key, queueKeyPrefix))
+	}
+	return topics, iter.Err()
+}
@@ -0,0 +1,223 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+)
+
+// 自监控告警的公共属性
+const (
+	// SelfAlertDataSourceID 自监控告警不关联外部数据源，使用固定标识
+	SelfAlertDataSourceID = "self"
+
+	// selfAlertFingerprintPrefix 自监控告警指纹前缀，每条内置规则对应唯一指纹
+	selfAlertFingerprintPrefix = "self:"
+)
+
+// SelfRuleResult 自监控规则评估结果
+type SelfRuleResult struct {
+	// Firing 是否触发告警
+	Firing bool
+
+	// Value 当前观测值
+	Value float64
+
+	// Threshold 触发阈值
+	Threshold float64
+
+	// Message 告警描述
+	Message string
+}
+
+// SelfRule 平台自监控内置规则
+type SelfRule interface {
+	// Name 返回规则名称，同时作为告警名称和指纹
+	Name() string
+
+	// Severity 返回触发时的告警级别
+	Severity() models.AlertSeverity
+
+	// Evaluate 评估规则
+	Evaluate(ctx context.Context) (SelfRuleResult, error)
+}
+
+// SelfAlertFinder 按指纹查询告警，由告警仓储实现
+type SelfAlertFinder interface {
+	GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error)
+}
+
+// SelfAlertSink 告警写入，由告警服务实现，自监控告警与外部告警走同一处理流程
+type SelfAlertSink interface {
+	Create(ctx context.Context, alert *models.Alert) error
+	Update(ctx context.Context, alert *models.Alert) error
+}
+
+// SelfMonitor 平台自监控器，定期评估内置规则并生成来源为 self 的告警
+type SelfMonitor struct {
+	rules    []SelfRule
+	finder   SelfAlertFinder
+	sink     SelfAlertSink
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+}
+
+// NewSelfMonitor 创建平台自监控器
+func NewSelfMonitor(finder SelfAlertFinder, sink SelfAlertSink, interval, timeout time.Duration, logger *zap.Logger) *SelfMonitor {
+	return &SelfMonitor{
+		finder:   finder,
+		sink:     sink,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+	}
+}
+
+// AddRule 添加自监控规则
+func (m *SelfMonitor) AddRule(rule SelfRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, rule)
+}
+
+// Start 启动自监控，阻塞直到上下文取消或调用 Stop
+func (m *SelfMonitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.mu.Lock()
+			m.running = false
+			m.mu.Unlock()
+			return
+		case <-ticker.C:
+			m.RunOnce(ctx)
+		}
+	}
+}
+
+// Stop 停止自监控
+func (m *SelfMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.running = false
+}
+
+// RunOnce 评估所有规则，触发时创建或重新打开告警，恢复时自动解决告警
+func (m *SelfMonitor) RunOnce(ctx context.Context) {
+	m.mu.Lock()
+	rules := make([]SelfRule, len(m.rules))
+	copy(rules, m.rules)
+	m.mu.Unlock()
+
+	for _, rule := range rules {
+		ruleCtx, cancel := context.WithTimeout(ctx, m.timeout)
+		if err := m.evaluate(ruleCtx, rule); err != nil {
+			m.logger.Warn("自监控规则评估失败", zap.Error(err), zap.String("rule", rule.Name()))
+		}
+		cancel()
+	}
+}
+
+// evaluate 评估单条规则并同步告警状态
+func (m *SelfMonitor) evaluate(ctx context.Context, rule SelfRule) error {
+	result, err := rule.Evaluate(ctx)
+	if err != nil {
+		return fmt.Errorf("评估规则失败: %w", err)
+	}
+
+	fingerprint := selfAlertFingerprintPrefix + rule.Name()
+	existing, err := m.finder.GetByFingerprint(ctx, fingerprint)
+	if err != nil {
+		if !errors.Is(err, models.ErrAlertNotFound) {
+			return fmt.Errorf("查询自监控告警失败: %w", err)
+		}
+		existing = nil
+	}
+
+	now := time.Now()
+	switch {
+	case result.Firing && existing == nil:
+		alert := &models.Alert{
+			DataSourceID: SelfAlertDataSourceID,
+			Name:         rule.Name(),
+			Description:  result.Message,
+			Severity:     rule.Severity(),
+			Status:       models.AlertStatusFiring,
+			Source:       models.AlertSourceSelf,
+			Labels:       map[string]string{"source": string(models.AlertSourceSelf), "rule": rule.Name()},
+			Annotations:  map[string]string{},
+			Value:        &result.Value,
+			Threshold:    &result.Threshold,
+			Expression:   rule.Name(),
+			StartsAt:     now,
+			Fingerprint:  fingerprint,
+		}
+		if err := m.sink.Create(ctx, alert); err != nil {
+			return fmt.Errorf("创建自监控告警失败: %w", err)
+		}
+		m.logger.Warn("自监控告警触发", zap.String("rule", rule.Name()), zap.String("message", result.Message))
+
+	case result.Firing && existing.Status == models.AlertStatusResolved:
+		// 同一规则的告警指纹唯一，再次触发时重新打开已解决的告警
+		existing.Status = models.AlertStatusFiring
+		existing.Description = result.Message
+		existing.Value = &result.Value
+		existing.Threshold = &result.Threshold
+		existing.StartsAt = now
+		existing.EndsAt = nil
+		existing.ResolvedAt = nil
+		existing.ResolvedBy = nil
+		existing.AckedAt = nil
+		existing.AckedBy = nil
+		delete(existing.Annotations, models.AlertAnnotationResolveReason)
+		existing.LastEvalAt = now
+		existing.EvalCount++
+		if err := m.sink.Update(ctx, existing); err != nil {
+			return fmt.Errorf("重新触发自监控告警失败: %w", err)
+		}
+		m.logger.Warn("自监控告警再次触发", zap.String("rule", rule.Name()), zap.String("message", result.Message))
+
+	case !result.Firing && existing != nil && existing.Status != models.AlertStatusResolved:
+		existing.Status = models.AlertStatusResolved
+		existing.Value = &result.Value
+		existing.EndsAt = &now
+		existing.ResolvedAt = &now
+		existing.LastEvalAt = now
+		existing.EvalCount++
+		if err := m.sink.Update(ctx, existing); err != nil {
+			return fmt.Errorf("解决自监控告警失败: %w", err)
+		}
+		m.logger.Info("自监控告警已恢复", zap.String("rule", rule.Name()))
+	}
+
+	return nil
+}
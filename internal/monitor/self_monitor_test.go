@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+)
+
+// memoryAlertStore 按指纹保存告警的内存告警存储
+type memoryAlertStore struct {
+	alerts  map[string]*models.Alert
+	creates int
+	updates int
+}
+
+func newMemoryAlertStore() *memoryAlertStore {
+	return &memoryAlertStore{alerts: make(map[string]*models.Alert)}
+}
+
+func (s *memoryAlertStore) GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	alert, ok := s.alerts[fingerprint]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	copied := *alert
+	return &copied, nil
+}
+
+func (s *memoryAlertStore) Create(ctx context.Context, alert *models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+	s.creates++
+	copied := *alert
+	s.alerts[alert.Fingerprint] = &copied
+	return nil
+}
+
+func (s *memoryAlertStore) Update(ctx context.Context, alert *models.Alert) error {
+	s.updates++
+	copied := *alert
+	s.alerts[alert.Fingerprint] = &copied
+	return nil
+}
+
+// stubBacklogSource 返回固定数量待评估规则的规则仓储
+type stubBacklogSource struct {
+	backlog int
+}
+
+func (s *stubBacklogSource) GetRulesForEvaluation(ctx context.Context) ([]*models.Rule, error) {
+	return make([]*models.Rule, s.backlog), nil
+}
+
+// stubNotificationStats 返回固定发送结果的通知仓储
+type stubNotificationStats struct {
+	sent, failed int64
+}
+
+func (s *stubNotificationStats) GetSentCount(ctx context.Context, start, end time.Time) (int64, error) {
+	return s.sent, nil
+}
+
+func (s *stubNotificationStats) GetFailedCount(ctx context.Context, start, end time.Time) (int64, error) {
+	return s.failed, nil
+}
+
+func TestSelfMonitor_FiresAndResolves(t *testing.T) {
+	store := newMemoryAlertStore()
+	backlog := &stubBacklogSource{backlog: 150}
+	m := NewSelfMonitor(store, store, time.Minute, time.Second, zap.NewNop())
+	m.AddRule(NewEvaluationBacklogRule(backlog, 100))
+	ctx := context.Background()
+
+	m.RunOnce(ctx)
+	alert := store.alerts["self:"+SelfRuleEvaluationBacklog]
+	require.NotNil(t, alert)
+	assert.Equal(t, models.AlertSourceSelf, alert.Source)
+	assert.Equal(t, models.AlertStatusFiring, alert.Status)
+	assert.Equal(t, 150.0, *alert.Value)
+
+	// 持续触发时不重复创建告警
+	m.RunOnce(ctx)
+	assert.Equal(t, 1, store.creates)
+	assert.Equal(t, 0, store.updates)
+
+	backlog.backlog = 10
+	m.RunOnce(ctx)
+	alert = store.alerts["self:"+SelfRuleEvaluationBacklog]
+	assert.Equal(t, models.AlertStatusResolved, alert.Status)
+	assert.NotNil(t, alert.ResolvedAt)
+
+	// 再次触发时重新打开同一指纹的告警
+	backlog.backlog = 200
+	m.RunOnce(ctx)
+	alert = store.alerts["self:"+SelfRuleEvaluationBacklog]
+	assert.Equal(t, models.AlertStatusFiring, alert.Status)
+	assert.Nil(t, alert.ResolvedAt)
+	assert.Equal(t, 1, store.creates)
+	assert.Equal(t, 2, store.updates)
+}
+
+func TestIngestFailureRule(t *testing.T) {
+	stats := NewIngestStats()
+	rule := NewIngestFailureRule(stats, 3)
+	ctx := context.Background()
+
+	stats.RecordSuccess()
+	stats.RecordFailure()
+	stats.RecordFailure()
+	result, err := rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+
+	stats.RecordFailure()
+	stats.RecordFailure()
+	stats.RecordFailure()
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Firing)
+	assert.Equal(t, 3.0, result.Value)
+
+	// 每次评估后计数清零
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+}
+
+func TestNotificationFailureRule(t *testing.T) {
+	stats := &stubNotificationStats{sent: 2, failed: 8}
+	rule := NewNotificationFailureRule(stats, 15*time.Minute, 0.2, 20)
+	ctx := context.Background()
+
+	// 样本数不足时不触发
+	result, err := rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+
+	stats.sent, stats.failed = 90, 10
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+	assert.InDelta(t, 0.1, result.Value, 1e-9)
+
+	stats.sent, stats.failed = 70, 30
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Firing)
+}
+
+func TestHealthCheckRule(t *testing.T) {
+	hm := NewHealthMonitor(time.Minute, time.Second)
+	check := &stubCheck{status: HealthStatusHealthy}
+	hm.AddCheck(check)
+	rule := NewHealthCheckRule(SelfRuleDatabaseHealth, hm, "stub")
+	ctx := context.Background()
+
+	// 尚无检查结果时不触发
+	result, err := rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+
+	hm.runChecks(ctx)
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+
+	check.status = HealthStatusUnhealthy
+	hm.runChecks(ctx)
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Firing)
+}
+
+// stubLagSource 返回固定规则评估延迟的规则服务
+type stubLagSource struct {
+	lags []*models.RuleEvaluationLag
+}
+
+func (s *stubLagSource) EvaluationLag(ctx context.Context) (*models.RuleEvaluationLagReport, error) {
+	report := &models.RuleEvaluationLagReport{Total: len(s.lags), Rules: s.lags}
+	for _, lag := range s.lags {
+		lag.SetOverdue(time.Now())
+		if lag.OverdueMs > report.MaxOverdueMs {
+			report.MaxOverdueMs = lag.OverdueMs
+		}
+	}
+	return report, nil
+}
+
+func TestEvaluationMissedRule(t *testing.T) {
+	ctx := context.Background()
+	recent := time.Now().Add(-30 * time.Second)
+	stalled := time.Now().Add(-10 * time.Minute)
+	source := &stubLagSource{lags: []*models.RuleEvaluationLag{
+		{RuleName: "cpu", EvaluationInterval: time.Minute, LastEvalAt: &recent, LastDelayMs: 90000, MissedEvals: 2},
+		{RuleName: "disk", EvaluationInterval: time.Minute, LastEvalAt: &recent},
+		{RuleName: "never", EvaluationInterval: time.Minute},
+	}}
+	rule := NewEvaluationMissedRule(source, 3)
+
+	// 偶尔错过评估不触发
+	result, err := rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Firing)
+
+	// 连续错过达到阈值
+	source.lags[0].MissedEvals = 3
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Firing)
+	assert.Equal(t, 1.0, result.Value)
+	assert.Contains(t, result.Message, "cpu")
+
+	// 评估器停止工作，规则逾期达到阈值个评估间隔
+	source.lags[0].MissedEvals = 0
+	source.lags[1].LastEvalAt = &stalled
+	result, err = rule.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Firing)
+	assert.Contains(t, result.Message, "disk")
+	assert.NotContains(t, result.Message, "cpu")
+}
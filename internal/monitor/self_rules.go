@@ -0,0 +1,257 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// 内置自监控规则名称
+const (
+	SelfRuleIngestFailures      = "pulse_ingest_failures"
+	SelfRuleEvaluationBacklog   = "pulse_evaluation_backlog"
+	SelfRuleEvaluationMissed    = "pulse_rule_evaluation_missed"
+	SelfRuleNotificationFailure = "pulse_notification_failure_rate"
+	SelfRuleDatabaseHealth      = "pulse_database_unhealthy"
+	SelfRuleRedisHealth         = "pulse_redis_unhealthy"
+)
+
+// IngestStats 告警接入计数，由接入接口记录，自监控每个周期读取并清零
+type IngestStats struct {
+	total  atomic.Int64
+	failed atomic.Int64
+}
+
+// NewIngestStats 创建告警接入计数
+func NewIngestStats() *IngestStats {
+	return &IngestStats{}
+}
+
+// RecordSuccess 记录一次接入成功
+func (s *IngestStats) RecordSuccess() {
+	s.total.Add(1)
+}
+
+// RecordFailure 记录一次接入失败
+func (s *IngestStats) RecordFailure() {
+	s.total.Add(1)
+	s.failed.Add(1)
+}
+
+// Reset 返回自上次清零以来的接入总数和失败数并清零
+func (s *IngestStats) Reset() (total, failed int64) {
+	return s.total.Swap(0), s.failed.Swap(0)
+}
+
+// ingestFailureRule 告警接入失败规则
+type ingestFailureRule struct {
+	stats     *IngestStats
+	threshold int
+}
+
+// NewIngestFailureRule 创建告警接入失败规则，单个周期内失败次数达到阈值时触发
+func NewIngestFailureRule(stats *IngestStats, threshold int) SelfRule {
+	return &ingestFailureRule{stats: stats, threshold: threshold}
+}
+
+func (r *ingestFailureRule) Name() string { return SelfRuleIngestFailures }
+
+func (r *ingestFailureRule) Severity() models.AlertSeverity { return models.AlertSeverityHigh }
+
+func (r *ingestFailureRule) Evaluate(ctx context.Context) (SelfRuleResult, error) {
+	total, failed := r.stats.Reset()
+	return SelfRuleResult{
+		Firing:    failed >= int64(r.threshold),
+		Value:     float64(failed),
+		Threshold: float64(r.threshold),
+		Message:   fmt.Sprintf("告警接入失败 %d 次（共 %d 次请求），阈值 %d", failed, total, r.threshold),
+	}, nil
+}
+
+// EvaluationBacklogSource 查询待评估规则，由规则仓储实现
+type EvaluationBacklogSource interface {
+	GetRulesForEvaluation(ctx context.Context) ([]*models.Rule, error)
+}
+
+// evaluationBacklogRule 规则评估积压规则
+type evaluationBacklogRule struct {
+	source    EvaluationBacklogSource
+	threshold int
+}
+
+// NewEvaluationBacklogRule 创建规则评估积压规则，已到期未评估的规则数量达到阈值时触发
+func NewEvaluationBacklogRule(source EvaluationBacklogSource, threshold int) SelfRule {
+	return &evaluationBacklogRule{source: source, threshold: threshold}
+}
+
+func (r *evaluationBacklogRule) Name() string { return SelfRuleEvaluationBacklog }
+
+func (r *evaluationBacklogRule) Severity() models.AlertSeverity { return models.AlertSeverityHigh }
+
+func (r *evaluationBacklogRule) Evaluate(ctx context.Context) (SelfRuleResult, error) {
+	rules, err := r.source.GetRulesForEvaluation(ctx)
+	if err != nil {
+		return SelfRuleResult{}, fmt.Errorf("获取待评估规则失败: %w", err)
+	}
+
+	backlog := len(rules)
+	return SelfRuleResult{
+		Firing:    backlog >= r.threshold,
+		Value:     float64(backlog),
+		Threshold: float64(r.threshold),
+		Message:   fmt.Sprintf("待评估规则积压 %d 条，阈值 %d", backlog, r.threshold),
+	}, nil
+}
+
+// EvaluationLagSource 汇总规则评估延迟，由规则服务实现
+type EvaluationLagSource interface {
+	EvaluationLag(ctx context.Context) (*models.RuleEvaluationLagReport, error)
+}
+
+// evaluationMissedRule 规则持续错过评估规则
+type evaluationMissedRule struct {
+	source    EvaluationLagSource
+	threshold int
+}
+
+// NewEvaluationMissedRule 创建规则持续错过评估规则，任一规则连续错过 threshold 次评估，
+// 或超过计划时间 threshold 个评估间隔仍未评估时触发，用于在告警悄然停止之前发现评估器过载
+func NewEvaluationMissedRule(source EvaluationLagSource, threshold int) SelfRule {
+	return &evaluationMissedRule{source: source, threshold: threshold}
+}
+
+func (r *evaluationMissedRule) Name() string { return SelfRuleEvaluationMissed }
+
+func (r *evaluationMissedRule) Severity() models.AlertSeverity { return models.AlertSeverityHigh }
+
+func (r *evaluationMissedRule) Evaluate(ctx context.Context) (SelfRuleResult, error) {
+	report, err := r.source.EvaluationLag(ctx)
+	if err != nil {
+		return SelfRuleResult{}, fmt.Errorf("获取规则评估延迟失败: %w", err)
+	}
+
+	var missing []string
+	for _, lag := range report.Rules {
+		if lag.Missing(r.threshold) {
+			missing = append(missing, lag.RuleName)
+		}
+	}
+	message := fmt.Sprintf("%d 条规则持续错过评估（连续错过或逾期达到 %d 个评估间隔）", len(missing), r.threshold)
+	if len(missing) > 0 {
+		message += fmt.Sprintf("，最大逾期 %s：%s", time.Duration(report.MaxOverdueMs)*time.Millisecond, strings.Join(truncateNames(missing, 5), "、"))
+	}
+	return SelfRuleResult{
+		Firing:    len(missing) > 0,
+		Value:     float64(len(missing)),
+		Threshold: 1,
+		Message:   message,
+	}, nil
+}
+
+// truncateNames 只保留前 limit 个名称，其余以数量代替
+func truncateNames(names []string, limit int) []string {
+	if len(names) <= limit {
+		return names
+	}
+	return append(names[:limit:limit], fmt.Sprintf("等 %d 条", len(names)))
+}
+
+// NotificationStatsSource 统计通知发送结果，由通知仓储实现
+type NotificationStatsSource interface {
+	GetSentCount(ctx context.Context, start, end time.Time) (int64, error)
+	GetFailedCount(ctx context.Context, start, end time.Time) (int64, error)
+}
+
+// notificationFailureRule 通知失败率规则
+type notificationFailureRule struct {
+	source     NotificationStatsSource
+	window     time.Duration
+	maxRate    float64
+	minSamples int
+}
+
+// NewNotificationFailureRule 创建通知失败率规则
+// 统计窗口内失败率达到 maxRate 时触发，发送总数少于 minSamples 时不触发，避免少量失败造成误报
+func NewNotificationFailureRule(source NotificationStatsSource, window time.Duration, maxRate float64, minSamples int) SelfRule {
+	return &notificationFailureRule{
+		source:     source,
+		window:     window,
+		maxRate:    maxRate,
+		minSamples: minSamples,
+	}
+}
+
+func (r *notificationFailureRule) Name() string { return SelfRuleNotificationFailure }
+
+func (r *notificationFailureRule) Severity() models.AlertSeverity { return models.AlertSeverityHigh }
+
+func (r *notificationFailureRule) Evaluate(ctx context.Context) (SelfRuleResult, error) {
+	end := time.Now()
+	start := end.Add(-r.window)
+
+	sent, err := r.source.GetSentCount(ctx, start, end)
+	if err != nil {
+		return SelfRuleResult{}, fmt.Errorf("获取通知发送数量失败: %w", err)
+	}
+	failed, err := r.source.GetFailedCount(ctx, start, end)
+	if err != nil {
+		return SelfRuleResult{}, fmt.Errorf("获取通知失败数量失败: %w", err)
+	}
+
+	total := sent + failed
+	var rate float64
+	if total > 0 {
+		rate = float64(failed) / float64(total)
+	}
+
+	return SelfRuleResult{
+		Firing:    total >= int64(r.minSamples) && rate >= r.maxRate,
+		Value:     rate,
+		Threshold: r.maxRate,
+		Message:   fmt.Sprintf("最近 %s 通知失败率 %.1f%%（失败 %d / 共 %d），阈值 %.1f%%", r.window, rate*100, failed, total, r.maxRate*100),
+	}, nil
+}
+
+// healthCheckRule 依赖组件健康规则
+type healthCheckRule struct {
+	name      string
+	monitor   *HealthMonitor
+	checkName string
+}
+
+// NewHealthCheckRule 创建依赖组件健康规则，读取健康监控器中指定检查的最近结果，非健康时触发
+// 可选检查的故障会被降级为 degraded，因此降级同样视为触发
+func NewHealthCheckRule(name string, monitor *HealthMonitor, checkName string) SelfRule {
+	return &healthCheckRule{
+		name:      name,
+		monitor:   monitor,
+		checkName: checkName,
+	}
+}
+
+func (r *healthCheckRule) Name() string { return r.name }
+
+func (r *healthCheckRule) Severity() models.AlertSeverity { return models.AlertSeverityCritical }
+
+func (r *healthCheckRule) Evaluate(ctx context.Context) (SelfRuleResult, error) {
+	result, ok := r.monitor.GetResult(r.checkName)
+	if !ok || result.Status == HealthStatusUnknown {
+		return SelfRuleResult{Message: fmt.Sprintf("%s 尚无健康检查结果", r.checkName)}, nil
+	}
+
+	firing := result.Status == HealthStatusUnhealthy || result.Status == HealthStatusDegraded
+	value := 0.0
+	if firing {
+		value = 1
+	}
+	return SelfRuleResult{
+		Firing:    firing,
+		Value:     value,
+		Threshold: 1,
+		Message:   fmt.Sprintf("%s 健康状态为 %s: %s", r.checkName, result.Status, result.Message),
+	}, nil
+}
@@ -0,0 +1,53 @@
+// Package objectstore 对象存储，保存数据归档等文件，支持本地目录和 S3 兼容存储
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pulse/internal/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("对象不存在")
+
+// Store 对象存储接口
+type Store interface {
+	// Put 保存对象，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get 读取对象，不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// New 根据文件存储配置创建对象存储
+func New(cfg config.FileStorageConfig) (Store, error) {
+	switch cfg.Type {
+	case "", "local":
+		if cfg.LocalPath == "" {
+			return nil, fmt.Errorf("本地存储目录不能为空")
+		}
+		return NewLocalStore(cfg.LocalPath), nil
+	case "s3":
+		if cfg.S3.Bucket == "" {
+			return nil, fmt.Errorf("S3存储桶不能为空")
+		}
+		return NewS3Store(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("不支持的对象存储类型: %s", cfg.Type)
+	}
+}
+
+// validateKey 验证对象键，不能为空、以 / 开头或包含 . 和 .. 路径段
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("无效的对象键 %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("无效的对象键 %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package actionlink
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignAndParse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(Claims{AlertID: "a1", UserID: "u1", Action: ActionAck, ExpiresAt: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	claims, err := signer.Parse(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "a1", claims.AlertID)
+	assert.Equal(t, "u1", claims.UserID)
+	assert.Equal(t, ActionAck, claims.Action)
+	assert.Len(t, claims.ID, 32)
+
+	// 每次签发的令牌 ID 不同
+	other, err := signer.Sign(Claims{AlertID: "a1", UserID: "u1", Action: ActionAck, ExpiresAt: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged, err := NewSigner("secret").Sign(Claims{ID: claims.ID, AlertID: "a2", UserID: "u1", Action: ActionResolve, ExpiresAt: claims.ExpiresAt})
+	require.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time
+		want  error
+	}{
+		{"已过期", token, now.Add(time.Hour), ErrExpired},
+		{"密钥错误", mustSign(t, NewSigner("other"), now), now, ErrInvalidToken},
+		{"载荷被篡改", forgedPayload + "." + sig, now, ErrInvalidToken},
+		{"缺少签名", payload, now, ErrInvalidToken},
+		{"签名格式错误", payload + ".!!", now, ErrInvalidToken},
+		{"不支持的操作", mustSignClaims(t, signer, Claims{AlertID: "a1", UserID: "u1", Action: "delete", ExpiresAt: now.Add(time.Hour).Unix()}), now, ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Parse(tt.token, tt.now)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func mustSign(t *testing.T, signer *Signer, now time.Time) string {
+	return mustSignClaims(t, signer, Claims{AlertID: "a1", UserID: "u1", Action: ActionAck, ExpiresAt: now.Add(time.Hour).Unix()})
+}
+
+func mustSignClaims(t *testing.T, signer *Signer, claims Claims) string {
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+	return token
+}
@@ -0,0 +1,109 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", LocaleZhCN},
+		{"en-US", LocaleEnUS},
+		{"en-GB,en;q=0.8", LocaleEnUS},
+		{"zh-TW", LocaleZhCN},
+		{"fr-FR,en;q=0.5,zh;q=0.9", LocaleZhCN},
+		{"zh;q=0,en", LocaleEnUS},
+		{"fr-FR,de", LocaleZhCN},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header), tt.header)
+	}
+}
+
+func TestCodeStatus(t *testing.T) {
+	// 每个错误码都需要中英文消息
+	for code, def := range catalog {
+		assert.NotEmpty(t, def.messages[LocaleZhCN], code)
+		assert.NotEmpty(t, def.messages[LocaleEnUS], code)
+	}
+
+	assert.Equal(t, http.StatusNotFound, CodeNotFound.Status())
+	assert.Equal(t, http.StatusInternalServerError, Code("UNKNOWN").Status())
+	assert.Equal(t, CodeConflict, CodeFromStatus(http.StatusConflict))
+	assert.Equal(t, CodeInvalidRequest, CodeFromStatus(http.StatusUnprocessableEntity))
+	assert.Equal(t, CodeInternalError, CodeFromStatus(http.StatusBadGateway))
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	respond := func(acceptLanguage string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, Response) {
+		router := gin.New()
+		router.GET("/", func(c *gin.Context) {
+			c.Set("request_id", "req-1")
+			handler(c)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	t.Run("中文消息和错误原因", func(t *testing.T) {
+		w, resp := respond("zh-CN", func(c *gin.Context) {
+			Respond(c, http.StatusNotFound, "告警不存在", errors.New("告警不存在"))
+		})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, CodeNotFound, resp.Code)
+		assert.Equal(t, "告警不存在", resp.Message)
+		assert.Equal(t, "告警不存在", resp.Details)
+		assert.Equal(t, "req-1", resp.TraceID)
+	})
+
+	t.Run("翻译消息和补充说明", func(t *testing.T) {
+		w, resp := respond("en", func(c *gin.Context) {
+			Respond(c, http.StatusBadRequest, "告警ID不能为空", "请提供有效的告警ID")
+		})
+
+		assert.Equal(t, "en-US", w.Header().Get("Content-Language"))
+		assert.Equal(t, "Alert ID is required", resp.Message)
+		assert.Equal(t, "Please provide a valid alert ID", resp.Details)
+	})
+
+	t.Run("无翻译时使用错误码默认消息", func(t *testing.T) {
+		_, resp := respond("en-US", func(c *gin.Context) {
+			Respond(c, http.StatusConflict, "未登记的消息", "")
+		})
+
+		assert.Equal(t, CodeConflict, resp.Code)
+		assert.Equal(t, "Resource conflict", resp.Message)
+		assert.Nil(t, resp.Details)
+	})
+
+	t.Run("指定错误码", func(t *testing.T) {
+		w, resp := respond("", func(c *gin.Context) {
+			RespondCode(c, CodeInvalidToken, "", nil)
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, CodeInvalidToken, resp.Code)
+		assert.Equal(t, "令牌无效或已过期", resp.Message)
+	})
+}
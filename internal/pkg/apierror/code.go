@@ -0,0 +1,145 @@
+// Package apierror 定义 API 统一错误响应格式、错误码目录和错误消息本地化
+//
+// 所有错误响应都使用同一结构：
+//
+//	{"code": "NOT_FOUND", "message": "告警不存在", "details": "...", "trace_id": "..."}
+//
+// code 供客户端程序判断错误类型，message 按请求的 Accept-Language 本地化，
+// details 携带错误原因等补充信息，trace_id 与响应头 X-Request-ID 一致便于排查日志。
+package apierror
+
+import "net/http"
+
+// Code 机器可读的错误码
+type Code string
+
+// 错误码目录
+const (
+	// 请求错误
+	CodeInvalidRequest     Code = "INVALID_REQUEST"
+	CodeValidationFailed   Code = "VALIDATION_FAILED"
+	CodePayloadTooLarge    Code = "PAYLOAD_TOO_LARGE"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeConflict           Code = "CONFLICT"
+	CodePreconditionFailed Code = "PRECONDITION_FAILED"
+	CodeRequestTimeout     Code = "REQUEST_TIMEOUT"
+	CodeRateLimitExceeded  Code = "RATE_LIMIT_EXCEEDED"
+
+	// 认证和授权错误
+	CodeAuthenticationRequired     Code = "AUTHENTICATION_REQUIRED"
+	CodeMissingAuthorizationHeader Code = "MISSING_AUTHORIZATION_HEADER"
+	CodeInvalidAuthorizationFormat Code = "INVALID_AUTHORIZATION_FORMAT"
+	CodeInvalidToken               Code = "INVALID_TOKEN"
+	CodeMissingAPIKey              Code = "MISSING_API_KEY"
+	CodeInvalidAPIKey              Code = "INVALID_API_KEY"
+	CodeForbidden                  Code = "FORBIDDEN"
+	CodeInsufficientPermissions    Code = "INSUFFICIENT_PERMISSIONS"
+	CodeInsufficientRole           Code = "INSUFFICIENT_ROLE"
+	CodeIPNotAllowed               Code = "IP_NOT_ALLOWED"
+	CodeInvalidSignature           Code = "INVALID_SIGNATURE"
+	CodeImpersonationReadOnly      Code = "IMPERSONATION_READ_ONLY"
+	CodeInsufficientScope          Code = "INSUFFICIENT_SCOPE"
+
+	// 服务端错误
+	CodeInternalError         Code = "INTERNAL_ERROR"
+	CodeInvalidAuthContext    Code = "INVALID_AUTH_CONTEXT"
+	CodePermissionCheckFailed Code = "PERMISSION_CHECK_FAILED"
+	CodeNotImplemented        Code = "NOT_IMPLEMENTED"
+	CodeServiceUnavailable    Code = "SERVICE_UNAVAILABLE"
+	CodeMaintenanceMode       Code = "MAINTENANCE_MODE"
+)
+
+// definition 错误码对应的HTTP状态码和各语言的默认消息
+type definition struct {
+	status   int
+	messages map[Locale]string
+}
+
+var catalog = map[Code]definition{
+	CodeInvalidRequest:     {http.StatusBadRequest, messages("请求参数无效", "Invalid request parameters")},
+	CodeValidationFailed:   {http.StatusBadRequest, messages("请求数据验证失败", "Request validation failed")},
+	CodePayloadTooLarge:    {http.StatusRequestEntityTooLarge, messages("请求内容过大", "Request payload too large")},
+	CodeNotFound:           {http.StatusNotFound, messages("资源不存在", "Resource not found")},
+	CodeConflict:           {http.StatusConflict, messages("资源冲突", "Resource conflict")},
+	CodePreconditionFailed: {http.StatusPreconditionFailed, messages("前置条件不满足", "Precondition failed")},
+	CodeRequestTimeout:     {http.StatusRequestTimeout, messages("请求超时", "Request timeout")},
+	CodeRateLimitExceeded:  {http.StatusTooManyRequests, messages("请求过于频繁，请稍后重试", "Rate limit exceeded, please try again later")},
+
+	CodeAuthenticationRequired:     {http.StatusUnauthorized, messages("需要身份认证", "Authentication is required")},
+	CodeMissingAuthorizationHeader: {http.StatusUnauthorized, messages("缺少Authorization请求头", "Authorization header is required")},
+	CodeInvalidAuthorizationFormat: {http.StatusUnauthorized, messages("Authorization请求头格式应为: Bearer <token>", "Authorization header must be in format: Bearer <token>")},
+	CodeInvalidToken:               {http.StatusUnauthorized, messages("令牌无效或已过期", "Invalid or expired token")},
+	CodeMissingAPIKey:              {http.StatusUnauthorized, messages("缺少X-API-Key请求头", "X-API-Key header is required")},
+	CodeInvalidAPIKey:              {http.StatusUnauthorized, messages("API Key无效", "Invalid API key")},
+	CodeForbidden:                  {http.StatusForbidden, messages("禁止访问", "Access forbidden")},
+	CodeInsufficientPermissions:    {http.StatusForbidden, messages("权限不足，无法访问该资源", "Insufficient permissions to access this resource")},
+	CodeInsufficientRole:           {http.StatusForbidden, messages("角色不足，无法访问该资源", "Insufficient role to access this resource")},
+	CodeIPNotAllowed:               {http.StatusForbidden, messages("来源IP不允许访问", "Source IP is not allowed")},
+	CodeInvalidSignature:           {http.StatusUnauthorized, messages("请求签名无效", "Invalid request signature")},
+	CodeImpersonationReadOnly:      {http.StatusForbidden, messages("只读的模拟登录会话不支持修改操作", "Read-only impersonation sessions cannot modify data")},
+	CodeInsufficientScope:          {http.StatusForbidden, messages("API Key 的权限范围不足，无法访问该接口", "The API key does not have the scope required by this endpoint")},
+
+	CodeInternalError:         {http.StatusInternalServerError, messages("服务器内部错误", "Internal server error")},
+	CodeInvalidAuthContext:    {http.StatusInternalServerError, messages("认证上下文无效", "Invalid authentication context")},
+	CodePermissionCheckFailed: {http.StatusInternalServerError, messages("权限检查失败", "Failed to check permissions")},
+	CodeNotImplemented:        {http.StatusNotImplemented, messages("功能未实现", "Not implemented")},
+	CodeServiceUnavailable:    {http.StatusServiceUnavailable, messages("服务暂时不可用，请稍后重试", "Service is temporarily unavailable, please try again later")},
+	CodeMaintenanceMode:       {http.StatusServiceUnavailable, messages("系统维护中，暂不支持修改操作", "The system is under maintenance and read-only, please try again later")},
+}
+
+func messages(zh, en string) map[Locale]string {
+	return map[Locale]string{LocaleZhCN: zh, LocaleEnUS: en}
+}
+
+// Status 返回错误码对应的HTTP状态码，未知错误码视为服务器内部错误
+func (c Code) Status() int {
+	if def, ok := catalog[c]; ok {
+		return def.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message 返回错误码在指定语言下的默认消息
+func (c Code) Message(locale Locale) string {
+	def, ok := catalog[c]
+	if !ok {
+		def = catalog[CodeInternalError]
+	}
+	if msg, ok := def.messages[locale]; ok {
+		return msg
+	}
+	return def.messages[DefaultLocale]
+}
+
+// CodeFromStatus 根据HTTP状态码推导通用错误码
+func CodeFromStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeAuthenticationRequired
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusRequestTimeout:
+		return CodeRequestTimeout
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return CodeRateLimitExceeded
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+
+	if status >= 400 && status < 500 {
+		return CodeInvalidRequest
+	}
+	return CodeInternalError
+}
@@ -0,0 +1,70 @@
+package apierror
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale 错误消息语言
+type Locale string
+
+// 支持的语言
+const (
+	LocaleZhCN Locale = "zh-CN"
+	LocaleEnUS Locale = "en-US"
+
+	// DefaultLocale 未指定或不支持请求的语言时使用的语言
+	DefaultLocale = LocaleZhCN
+)
+
+// ParseAcceptLanguage 根据 Accept-Language 请求头选择语言
+// 按权重从高到低匹配，只比较主语言标签（en-GB 视为 en-US），都不支持时返回 DefaultLocale
+func ParseAcceptLanguage(header string) Locale {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+
+	// 权重相同时保持请求头中的顺序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, c := range candidates {
+		primary, _, _ := strings.Cut(c.tag, "-")
+		switch primary {
+		case "zh":
+			return LocaleZhCN
+		case "en":
+			return LocaleEnUS
+		case "*":
+			return DefaultLocale
+		}
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,358 @@
+package apierror
+
+// translations 处理函数错误消息的英文翻译，以中文消息为键
+var translations = map[string]string{
+	// 通用
+	"请求参数无效":   "Invalid request parameters",
+	"请求数据验证失败": "Request validation failed",
+	"功能未实现":    "Not implemented",
+	"开始时间格式无效": "Invalid start time format",
+	"结束时间格式无效": "Invalid end time format",
+	"无效的资源类型":  "Invalid resource type",
+
+	// 补充说明
+	"请提供有效的告警ID":       "Please provide a valid alert ID",
+	"指定的告警ID不存在":       "The specified alert ID does not exist",
+	"请提供有效的规则ID":       "Please provide a valid rule ID",
+	"请提供有效的工单ID":       "Please provide a valid ticket ID",
+	"请提供有效的工作日志ID":     "Please provide a valid worklog ID",
+	"请提供有效的工单ID和附件ID":  "Please provide a valid ticket ID and attachment ID",
+	"附件不属于该工单":         "The attachment does not belong to this ticket",
+	"请提供有效的知识库条目ID":    "Please provide a valid knowledge article ID",
+	"请通过参数q提供搜索关键词":    "Please provide the search query via the q parameter",
+	"请提供有效的自定义字段ID":    "Please provide a valid custom field ID",
+	"资源类型必须是 ticket":   "Resource type must be ticket",
+	"请提供有效的Webhook ID": "Please provide a valid webhook ID",
+	"指定的Webhook ID不存在": "The specified webhook ID does not exist",
+
+	// 告警
+	"告警ID不能为空": "Alert ID is required",
+	"告警不存在":    "Alert not found",
+	"获取告警列表失败": "Failed to list alerts",
+	"获取告警失败":   "Failed to get alert",
+	"获取告警详情失败": "Failed to get alert details",
+	"创建告警失败":   "Failed to create alert",
+	"更新告警失败":   "Failed to update alert",
+	"删除告警失败":   "Failed to delete alert",
+	"确认告警失败":   "Failed to acknowledge alert",
+	"解决告警失败":   "Failed to resolve alert",
+
+	// 规则
+	"规则ID不能为空": "Rule ID is required",
+	"获取规则列表失败": "Failed to list rules",
+	"获取规则失败":   "Failed to get rule",
+	"创建规则失败":   "Failed to create rule",
+	"更新规则失败":   "Failed to update rule",
+	"删除规则失败":   "Failed to delete rule",
+	"启用规则失败":   "Failed to enable rule",
+	"禁用规则失败":   "Failed to disable rule",
+
+	// 数据源
+	"数据源ID不能为空": "Data source ID is required",
+	"获取数据源列表失败": "Failed to list data sources",
+	"获取数据源失败":   "Failed to get data source",
+	"创建数据源失败":   "Failed to create data source",
+	"更新数据源失败":   "Failed to update data source",
+	"删除数据源失败":   "Failed to delete data source",
+	"测试数据源连接失败": "Failed to test data source connection",
+
+	// 工单、工作日志和附件
+	"工单ID不能为空":   "Ticket ID is required",
+	"工作日志ID不能为空": "Worklog ID is required",
+	"获取工作日志失败":   "Failed to get worklogs",
+	"登记工作日志失败":   "Failed to add worklog",
+	"更新工作日志失败":   "Failed to update worklog",
+	"删除工作日志失败":   "Failed to delete worklog",
+	"获取工时报表失败":   "Failed to get timesheet",
+	"附件ID不能为空":   "Attachment ID is required",
+	"附件不存在":      "Attachment not found",
+	"附件暂不可下载":    "Attachment is not available for download",
+	"获取附件失败":     "Failed to get attachment",
+
+	// 知识库
+	"知识库条目ID不能为空": "Knowledge article ID is required",
+	"知识库条目不存在":    "Knowledge article not found",
+	"获取知识库列表失败":   "Failed to list knowledge articles",
+	"获取知识库条目失败":   "Failed to get knowledge article",
+	"创建知识库条目失败":   "Failed to create knowledge article",
+	"搜索关键词不能为空":   "Search query is required",
+	"搜索知识库失败":     "Failed to search knowledge base",
+	"渲染知识库条目失败":   "Failed to render knowledge article",
+	"获取知识库附件失败":   "Failed to get knowledge attachment",
+	"检测相似文章失败":    "Failed to check for similar articles",
+	"请上传导入文件":     "Import file is required",
+	"读取导入文件失败":    "Failed to read import file",
+	"导入文件过大":      "Import file is too large",
+	"导入知识库失败":     "Failed to import knowledge base",
+	"导出知识库失败":     "Failed to export knowledge base",
+	"无权访问该知识库条目":  "Access to this knowledge article is denied",
+	"获取访问控制失败":    "Failed to get access control",
+	"设置访问控制失败":    "Failed to set access control",
+	"设置定时发布失败":    "Failed to schedule publishing",
+	"取消定时发布失败":    "Failed to cancel scheduled publishing",
+	"更新过期时间失败":    "Failed to update expiry time",
+
+	// 知识库审核
+	"提交知识库审核失败":  "Failed to submit knowledge article for review",
+	"审核知识库条目失败":  "Failed to review knowledge article",
+	"获取审核状态失败":   "Failed to get review status",
+	"获取待审核队列失败":  "Failed to get pending review queue",
+	"获取审核策略列表失败": "Failed to list review policies",
+	"获取审核策略失败":   "Failed to get review policy",
+	"创建审核策略失败":   "Failed to create review policy",
+	"更新审核策略失败":   "Failed to update review policy",
+	"删除审核策略失败":   "Failed to delete review policy",
+
+	// 知识库评论
+	"获取评论列表失败": "Failed to list comments",
+	"发表评论失败":   "Failed to post comment",
+	"编辑评论失败":   "Failed to edit comment",
+	"删除评论失败":   "Failed to delete comment",
+	"更新表情回应失败": "Failed to update reaction",
+	"举报评论失败":   "Failed to flag comment",
+	"审核评论失败":   "Failed to moderate comment",
+	"获取举报评论失败": "Failed to get flagged comments",
+
+	// 自定义字段
+	"自定义字段ID不能为空": "Custom field ID is required",
+	"获取自定义字段列表失败": "Failed to list custom fields",
+	"获取自定义字段失败":   "Failed to get custom field",
+	"获取自定义字段表单失败": "Failed to get custom field schema",
+	"创建自定义字段失败":   "Failed to create custom field",
+	"更新自定义字段失败":   "Failed to update custom field",
+	"删除自定义字段失败":   "Failed to delete custom field",
+
+	// Webhook
+	"Webhook ID不能为空": "Webhook ID is required",
+	"Webhook ID格式无效": "Invalid webhook ID format",
+	"Webhook不存在":     "Webhook not found",
+	"获取Webhook列表失败":  "Failed to list webhooks",
+	"获取Webhook详情失败":  "Failed to get webhook details",
+	"创建Webhook失败":    "Failed to create webhook",
+	"更新Webhook失败":    "Failed to update webhook",
+	"删除Webhook失败":    "Failed to delete webhook",
+	"触发Webhook失败":    "Failed to trigger webhook",
+
+	// Webhook 接入
+	"Webhook接入ID不能为空":     "Webhook integration ID is required",
+	"获取Webhook接入列表失败":     "Failed to list webhook integrations",
+	"获取Webhook接入失败":       "Failed to get webhook integration",
+	"创建Webhook接入失败":       "Failed to create webhook integration",
+	"更新Webhook接入失败":       "Failed to update webhook integration",
+	"删除Webhook接入失败":       "Failed to delete webhook integration",
+	"重置Webhook接入密钥失败":     "Failed to rotate webhook integration secret",
+	"Webhook请求校验失败":       "Webhook request verification failed",
+	"读取请求体失败":             "Failed to read request body",
+	"缺少签名或时间戳":            "Signature or timestamp is missing",
+	"时间戳无效或已过期":           "Timestamp is invalid or expired",
+	"签名不匹配":               "Signature mismatch",
+	"请求签名无效":              "Invalid request signature",
+	"日志级别无效":              "Invalid log level",
+	"模块未设置日志级别":           "No log level is set for the module",
+	"后台任务不存在":             "Background job not found",
+	"后台任务未启用":             "Background job is not scheduled",
+	"后台任务未在执行":            "Background job is not running",
+	"读取声明式配置失败":           "Failed to read configuration bundle",
+	"声明式配置过大":             "Configuration bundle is too large",
+	"声明式配置解析失败":           "Failed to parse configuration bundle",
+	"声明式配置校验失败":           "Configuration bundle validation failed",
+	"应用声明式配置失败":           "Failed to apply configuration bundle",
+	"导出配置备份失败":            "Failed to export configuration backup",
+	"请提供备份口令":             "Backup passphrase is required",
+	"请上传备份文件":             "Backup file is required",
+	"备份文件过大":              "Backup file is too large",
+	"读取备份文件失败":            "Failed to read backup file",
+	"恢复配置备份失败":            "Failed to restore configuration backup",
+	"开启维护模式失败":            "Failed to enable maintenance mode",
+	"关闭维护模式失败":            "Failed to disable maintenance mode",
+	"获取分派策略列表失败":          "Failed to list assignment policies",
+	"获取分派策略失败":            "Failed to get assignment policy",
+	"创建分派策略失败":            "Failed to create assignment policy",
+	"更新分派策略失败":            "Failed to update assignment policy",
+	"删除分派策略失败":            "Failed to delete assignment policy",
+	"工单自动分派失败":            "Failed to auto-assign ticket",
+	"获取交接排班列表失败":          "Failed to list handover schedules",
+	"获取交接排班失败":            "Failed to get handover schedule",
+	"创建交接排班失败":            "Failed to create handover schedule",
+	"更新交接排班失败":            "Failed to update handover schedule",
+	"删除交接排班失败":            "Failed to delete handover schedule",
+	"获取交班报告列表失败":          "Failed to list handover reports",
+	"获取交班报告失败":            "Failed to get handover report",
+	"生成交班报告失败":            "Failed to generate handover report",
+	"打开告警操作链接失败":          "Failed to open alert action link",
+	"执行告警操作失败":            "Failed to perform alert action",
+	"确认告警":                "Acknowledge alert",
+	"解决告警":                "Resolve alert",
+	"暂停通知":                "Snooze notifications",
+	"操作已完成":               "Action completed",
+	"操作链接已使用":             "This action link has already been used",
+	"未启用告警操作链接":           "Alert action links are not enabled",
+	"只能确认正在触发的告警":         "Only firing alerts can be acknowledged",
+	"获取推送设备列表失败":          "Failed to list push devices",
+	"注册推送设备失败":            "Failed to register push device",
+	"删除推送设备失败":            "Failed to delete push device",
+	"获取推送偏好失败":            "Failed to get push preferences",
+	"设置推送偏好失败":            "Failed to update push preferences",
+	"获取站内通知失败":            "Failed to get notifications",
+	"获取未读通知数失败":           "Failed to get unread notification count",
+	"标记通知已读失败":            "Failed to mark notifications as read",
+	"站内通知实时推送失败":          "Notification stream failed",
+	"获取免打扰设置失败":           "Failed to get quiet hours",
+	"设置免打扰时段失败":           "Failed to update quiet hours",
+	"清除免打扰设置失败":           "Failed to clear quiet hours",
+	"开启免打扰失败":             "Failed to start do-not-disturb",
+	"结束免打扰失败":             "Failed to end do-not-disturb",
+	"获取变更事件列表失败":          "Failed to list change events",
+	"登记变更事件失败":            "Failed to register change event",
+	"获取变更事件失败":            "Failed to get change event",
+	"删除变更事件失败":            "Failed to delete change event",
+	"获取告警关联变更失败":          "Failed to get changes related to the alert",
+	"告警回放失败":              "Alert replay failed",
+	"获取通知投递记录失败":          "Failed to get notification delivery attempts",
+	"重发通知失败":              "Failed to resend notification",
+	"获取Webhook订阅列表失败":     "Failed to list webhook subscriptions",
+	"创建Webhook订阅失败":       "Failed to create webhook subscription",
+	"获取Webhook订阅失败":       "Failed to get webhook subscription",
+	"更新Webhook订阅失败":       "Failed to update webhook subscription",
+	"删除Webhook订阅失败":       "Failed to delete webhook subscription",
+	"重置Webhook订阅密钥失败":     "Failed to rotate webhook subscription secret",
+	"获取Webhook投递记录失败":     "Failed to list webhook deliveries",
+	"重新投递Webhook事件失败":     "Failed to redeliver webhook event",
+	"上传工单导入文件失败":          "Failed to upload ticket import file",
+	"开始工单导入失败":            "Failed to start ticket import",
+	"获取工单导入任务列表失败":        "Failed to list ticket import jobs",
+	"获取工单导入任务失败":          "Failed to get ticket import job",
+	"获取告警统计失败":            "Failed to get alert statistics",
+	"获取告警趋势失败":            "Failed to get alert trend",
+	"获取标签基数报告失败":          "Failed to get label cardinality report",
+	"获取标签键失败":             "Failed to get label keys",
+	"获取标签值失败":             "Failed to get label values",
+	"获取工单满意度统计失败":         "Failed to get ticket satisfaction statistics",
+	"打开评价链接失败":            "Failed to open survey link",
+	"提交评价失败":              "Failed to submit rating",
+	"评价意见（可选）":            "Comments (optional)",
+	"提交评价":                "Submit rating",
+	"感谢您的评价":              "Thank you for your feedback",
+	"满意度调查不存在":            "Survey not found",
+	"已提交过评价":              "You have already rated this ticket",
+	"评价链接已过期":             "This survey link has expired",
+	"获取工单引用的文章失败":         "Failed to get knowledge articles linked to ticket",
+	"引用知识库文章失败":           "Failed to link knowledge article",
+	"引用成功":                "Knowledge article linked",
+	"取消引用知识库文章失败":         "Failed to unlink knowledge article",
+	"已取消引用":               "Knowledge article unlinked",
+	"获取工单SLA状态失败":         "Failed to get ticket SLA status",
+	"暂停工单SLA失败":           "Failed to pause ticket SLA",
+	"SLA计时已暂停":            "SLA clock paused",
+	"恢复工单SLA失败":           "Failed to resume ticket SLA",
+	"SLA计时已恢复":            "SLA clock resumed",
+	"工单SLA计时已暂停":          "Ticket SLA clock is already paused",
+	"工单SLA计时未暂停":          "Ticket SLA clock is not paused",
+	"评价引用文章失败":            "Failed to rate linked knowledge article",
+	"评价成功":                "Rating submitted",
+	"获取待评价的文章失败":          "Failed to get knowledge articles pending rating",
+	"获取文章质量失败":            "Failed to get knowledge article quality",
+	"工单未引用该知识库文章":         "Ticket does not link this knowledge article",
+	"工单已引用该知识库文章":         "Ticket already links this knowledge article",
+	"工单解决后才能评价引用的文章":      "Linked knowledge articles can be rated only after the ticket is resolved",
+	"获取审批请求列表失败":          "Failed to list approval requests",
+	"提交敏感操作失败":            "Failed to submit sensitive operation",
+	"操作已执行":               "Operation executed",
+	"操作已提交审批":             "Operation submitted for approval",
+	"获取审批请求失败":            "Failed to get approval request",
+	"获取审批记录失败":            "Failed to get approval history",
+	"批准审批请求失败":            "Failed to approve request",
+	"已批准":                 "Request approved",
+	"驳回审批请求失败":            "Failed to reject request",
+	"已驳回":                 "Request rejected",
+	"撤回审批请求失败":            "Failed to cancel request",
+	"已撤回":                 "Request cancelled",
+	"审批请求不存在":             "Approval request not found",
+	"审批请求已处理":             "Approval request has already been processed",
+	"审批请求已过期":             "Approval request has expired",
+	"该操作需要审批，请提交审批请求":     "This operation requires approval, please submit an approval request",
+	"测试通知渠道失败":            "Failed to test notification channel",
+	"获取接入拒绝记录失败":          "Failed to get rejected ingest payloads",
+	"提交异步任务失败":            "Failed to submit async task",
+	"获取异步任务列表失败":          "Failed to list async tasks",
+	"获取异步任务失败":            "Failed to get async task",
+	"下载异步任务结果失败":          "Failed to download async task result",
+	"清除查询结果缓存失败":          "Failed to clear query result cache",
+	"获取规则评估延迟失败":          "Failed to get rule evaluation lag",
+	"路由试运行失败":             "Failed to test alert route",
+	"获取告警重新分级规则列表失败":      "Failed to list alert severity rules",
+	"获取告警重新分级规则失败":        "Failed to get alert severity rule",
+	"创建告警重新分级规则失败":        "Failed to create alert severity rule",
+	"告警重新分级规则创建成功":        "Alert severity rule created",
+	"更新告警重新分级规则失败":        "Failed to update alert severity rule",
+	"告警重新分级规则更新成功":        "Alert severity rule updated",
+	"删除告警重新分级规则失败":        "Failed to delete alert severity rule",
+	"告警重新分级规则删除成功":        "Alert severity rule deleted",
+	"试算告警重新分级失败":          "Failed to test alert severity rules",
+	"告警重新分级规则不存在":         "Alert severity rule not found",
+	"同名的告警重新分级规则已存在":      "An alert severity rule with this name already exists",
+	"获取状态页失败":             "Failed to get status page",
+	"状态页令牌无效":             "Invalid status page token",
+	"当前故障":                "Current incidents",
+	"计划维护":                "Scheduled maintenance",
+	"最近的事件":               "Recent history",
+	"订阅邮件通知":              "Subscribe to email updates",
+	"运行正常":                "Operational",
+	"维护中":                 "Under maintenance",
+	"性能下降":                "Degraded performance",
+	"部分不可用":               "Partial outage",
+	"完全不可用":               "Major outage",
+	"订阅状态页失败":             "Failed to subscribe to status page",
+	"请查收确认邮件，确认后开始接收通知":   "Please check your email and confirm your subscription to start receiving updates",
+	"打开订阅链接失败":            "Failed to open subscription link",
+	"确认订阅失败":              "Failed to confirm subscription",
+	"订阅已确认":               "Subscription confirmed",
+	"确认订阅":                "Confirm subscription",
+	"退订失败":                "Failed to unsubscribe",
+	"已退订":                 "Unsubscribed",
+	"退订":                  "Unsubscribe",
+	"订阅不存在":               "Subscription not found",
+	"未启用状态页订阅":            "Status page subscriptions are not enabled",
+	"获取状态页事件列表失败":         "Failed to list status page incidents",
+	"获取状态页事件失败":           "Failed to get status page incident",
+	"发布状态页事件失败":           "Failed to publish status page incident",
+	"状态页事件发布成功":           "Status page incident published",
+	"更新状态页事件失败":           "Failed to update status page incident",
+	"状态页事件更新成功":           "Status page incident updated",
+	"发布状态页事件进展失败":         "Failed to post status page incident update",
+	"状态页事件进展发布成功":         "Status page incident update posted",
+	"删除状态页事件失败":           "Failed to delete status page incident",
+	"状态页事件删除成功":           "Status page incident deleted",
+	"状态页事件不存在":            "Status page incident not found",
+	"获取服务健康度失败":           "Failed to get service health",
+	"获取服务健康度汇总失败":         "Failed to get service health rollup",
+	"获取规则预测失败":            "Failed to get rule forecast",
+	"获取通知渠道列表失败":          "Failed to list notification channels",
+	"获取通知渠道失败":            "Failed to get notification channel",
+	"创建通知渠道失败":            "Failed to create notification channel",
+	"更新通知渠道失败":            "Failed to update notification channel",
+	"删除通知渠道失败":            "Failed to delete notification channel",
+	"模拟用户登录失败":            "Failed to impersonate user",
+	"模拟登录会话中不能模拟其他用户":     "Cannot impersonate another user from an impersonation session",
+	"不支持的压缩方式，请使用 snappy": "Unsupported content encoding, use snappy",
+	"写入远程指标失败":            "Failed to write remote metrics",
+	"查询表达式不能为空":           "Query expression is required",
+	"查询时间格式无效":            "Invalid query time format",
+	"查询指标失败":              "Failed to query metrics",
+	"告警已解决":               "The alert is already resolved",
+	"无效的令牌":               "Invalid token",
+	"令牌已过期":               "Token expired",
+}
+
+// Localize 返回消息在指定语言下的文本，没有对应翻译时返回 false
+func Localize(message string, locale Locale) (string, bool) {
+	if locale == LocaleZhCN {
+		return message, true
+	}
+	if locale == LocaleEnUS {
+		if translated, ok := translations[message]; ok {
+			return translated, true
+		}
+	}
+	return "", false
+}
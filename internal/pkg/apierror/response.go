@@ -0,0 +1,85 @@
+package apierror
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pulse/internal/pkg/redact"
+)
+
+// Response 统一错误响应
+type Response struct {
+	Code    Code        `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	TraceID string      `json:"trace_id,omitempty"`
+}
+
+// Respond 返回指定状态码的错误响应，错误码由状态码推导
+// message 为中文消息，按请求语言翻译，无对应翻译时使用错误码的默认消息；details 为空时省略
+func Respond(c *gin.Context, status int, message string, details interface{}) {
+	write(c, status, CodeFromStatus(status), message, details)
+}
+
+// RespondCode 返回指定错误码的错误响应，message 为空时使用错误码的默认消息
+func RespondCode(c *gin.Context, code Code, message string, details interface{}) {
+	write(c, code.Status(), code, message, details)
+}
+
+func write(c *gin.Context, status int, code Code, message string, details interface{}) {
+	locale := LocaleFromContext(c)
+
+	localized, ok := "", false
+	if message != "" {
+		localized, ok = Localize(message, locale)
+	}
+	if !ok {
+		localized = code.Message(locale)
+	}
+
+	c.Header("Content-Language", string(locale))
+	c.JSON(status, Response{
+		Code:    code,
+		Message: localized,
+		Details: normalizeDetails(details, locale),
+		TraceID: traceID(c),
+	})
+}
+
+// LocaleFromContext 获取当前请求的错误消息语言
+func LocaleFromContext(c *gin.Context) Locale {
+	if c.Request == nil {
+		return DefaultLocale
+	}
+	return ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// normalizeDetails 将 error 转为文本并翻译已知的说明文字，空字符串视为无补充信息。
+// 错误原因可能包含连接串中的密码或令牌，按已知格式脱敏
+func normalizeDetails(details interface{}, locale Locale) interface{} {
+	switch d := details.(type) {
+	case nil:
+		return nil
+	case error:
+		return redact.Error(d)
+	case string:
+		if d == "" {
+			return nil
+		}
+		if localized, ok := Localize(d, locale); ok {
+			return localized
+		}
+		return redact.String(d)
+	}
+	return details
+}
+
+// traceID 获取请求ID，与响应头 X-Request-ID 保持一致
+func traceID(c *gin.Context) string {
+	if id := c.GetString("request_id"); id != "" {
+		return id
+	}
+	if c.Request != nil {
+		return c.GetHeader("X-Request-ID")
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 备份包为 zip 压缩包，除签名文件外的每个文件都参与签名。
+// 签名文件记录口令派生参数和签名值，签名为对按名称排序的 "文件名 + SHA-256 摘要" 列表计算的 HMAC-SHA256，
+// 任何文件被修改、增加或删除都会导致校验失败
+const (
+	// SignatureFile 签名文件名
+	SignatureFile = "SIGNATURE.json"
+
+	// MinPassphraseLength 口令的最小长度
+	MinPassphraseLength = 12
+
+	// 压缩包限制，防止恶意压缩包耗尽内存
+	MaxFiles    = 64
+	MaxFileSize = 100 << 20
+
+	signatureVersion = 1
+	kdfIterations    = 210000
+	saltSize         = 16
+	keySize          = 32
+)
+
+var (
+	// ErrPassphraseTooShort 口令过短
+	ErrPassphraseTooShort = fmt.Errorf("口令长度不能少于 %d 个字符", MinPassphraseLength)
+	// ErrMissingSignature 备份包缺少签名文件
+	ErrMissingSignature = errors.New("备份包缺少签名文件")
+	// ErrSignatureMismatch 签名不匹配，口令错误或备份包已被篡改
+	ErrSignatureMismatch = errors.New("备份包签名不匹配")
+)
+
+// signature 签名文件内容
+type signature struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	HMAC       string `json:"hmac"`
+}
+
+// Key 由口令派生的签名密钥和加密密钥
+type Key struct {
+	salt       []byte
+	iterations int
+	signing    []byte
+	encryption []byte
+}
+
+// NewKey 使用随机盐由口令派生密钥，用于创建新的备份包
+func NewKey(passphrase string) (*Key, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, ErrPassphraseTooShort
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return deriveKey(passphrase, salt, kdfIterations), nil
+}
+
+// deriveKey 使用 PBKDF2-SHA256 派生签名密钥和加密密钥
+func deriveKey(passphrase string, salt []byte, iterations int) *Key {
+	derived := pbkdf2.Key([]byte(passphrase), salt, iterations, 2*keySize, sha256.New)
+	return &Key{
+		salt:       salt,
+		iterations: iterations,
+		signing:    derived[:keySize],
+		encryption: derived[keySize:],
+	}
+}
+
+// EncryptionKey 用于加密备份包中敏感字段的 32 字节密钥
+func (k *Key) EncryptionKey() []byte {
+	return k.encryption
+}
+
+// sign 计算文件列表的签名
+func (k *Key) sign(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mac := hmac.New(sha256.New, k.signing)
+	for _, name := range names {
+		digest := sha256.Sum256(files[name])
+		mac.Write([]byte(name))
+		mac.Write([]byte{0})
+		mac.Write([]byte(hex.EncodeToString(digest[:])))
+		mac.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Write 写出签名后的备份包
+func Write(w io.Writer, key *Key, files map[string][]byte) error {
+	if _, exists := files[SignatureFile]; exists {
+		return fmt.Errorf("文件名 %s 为签名文件保留", SignatureFile)
+	}
+
+	sig, err := json.MarshalIndent(signature{
+		Version:    signatureVersion,
+		KDF:        "pbkdf2-sha256",
+		Iterations: key.iterations,
+		Salt:       hex.EncodeToString(key.salt),
+		HMAC:       key.sign(files),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := zip.NewWriter(w)
+	for _, name := range append(names, SignatureFile) {
+		content := sig
+		if name != SignatureFile {
+			content = files[name]
+		}
+		fw, err := writer.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(content); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// Read 读取备份包并使用口令校验签名，返回除签名文件外的全部文件和派生的密钥
+func Read(data []byte, passphrase string) (map[string][]byte, *Key, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取备份包失败: %w", err)
+	}
+	if len(reader.File) > MaxFiles {
+		return nil, nil, fmt.Errorf("备份包文件数量超过限制 %d", MaxFiles)
+	}
+
+	files := make(map[string][]byte, len(reader.File))
+	var sigData []byte
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if path.Clean(file.Name) != file.Name {
+			return nil, nil, fmt.Errorf("备份包包含无效的文件名 %q", file.Name)
+		}
+		if _, exists := files[file.Name]; exists {
+			return nil, nil, fmt.Errorf("备份包包含重复的文件 %q", file.Name)
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file.Name == SignatureFile {
+			sigData = content
+			continue
+		}
+		files[file.Name] = content
+	}
+	if sigData == nil {
+		return nil, nil, ErrMissingSignature
+	}
+
+	var sig signature
+	if err := json.Unmarshal(sigData, &sig); err != nil {
+		return nil, nil, fmt.Errorf("解析签名文件失败: %w", err)
+	}
+	if sig.Version != signatureVersion {
+		return nil, nil, fmt.Errorf("不支持的签名版本 %d", sig.Version)
+	}
+	salt, err := hex.DecodeString(sig.Salt)
+	if err != nil || len(salt) == 0 || sig.Iterations <= 0 || sig.Iterations > 10*kdfIterations {
+		return nil, nil, errors.New("签名文件的密钥派生参数无效")
+	}
+
+	key := deriveKey(passphrase, salt, sig.Iterations)
+	if !hmac.Equal([]byte(sig.HMAC), []byte(key.sign(files))) {
+		return nil, nil, ErrSignatureMismatch
+	}
+	return files, key, nil
+}
+
+// readZipFile 读取压缩包中的单个文件，超过 MaxFileSize 时返回错误
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > MaxFileSize {
+		return nil, fmt.Errorf("文件 %s 超过大小限制", file.Name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取文件 %s 失败: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取文件 %s 失败: %w", file.Name, err)
+	}
+	if len(content) > MaxFileSize {
+		return nil, fmt.Errorf("文件 %s 超过大小限制", file.Name)
+	}
+	return content, nil
+}
@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassphrase = "correct horse battery"
+
+func writeArchive(t *testing.T, files map[string][]byte) ([]byte, *Key) {
+	t.Helper()
+	key, err := NewKey(testPassphrase)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, key, files))
+	return buf.Bytes(), key
+}
+
+// rezip 重新打包备份包，modify 可修改、增加或删除文件
+func rezip(t *testing.T, data []byte, modify func(files map[string][]byte)) []byte {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		content, err := readZipFile(file)
+		require.NoError(t, err)
+		files[file.Name] = content
+	}
+	modify(files)
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	files := map[string][]byte{
+		"manifest.json": []byte(`{"format_version":1}`),
+		"rules.json":    []byte(`[]`),
+	}
+	data, key := writeArchive(t, files)
+
+	read, readKey, err := Read(data, testPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, files, read)
+	assert.Equal(t, key.EncryptionKey(), readKey.EncryptionKey())
+	assert.Len(t, key.EncryptionKey(), 32)
+
+	// 相同口令每次使用不同的盐
+	other, err := NewKey(testPassphrase)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.EncryptionKey(), other.EncryptionKey())
+}
+
+func TestBackup_Verification(t *testing.T) {
+	data, _ := writeArchive(t, map[string][]byte{
+		"manifest.json": []byte(`{"format_version":1}`),
+		"rules.json":    []byte(`[]`),
+	})
+
+	_, _, err := Read(data, "wrong passphrase!")
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	tampered := rezip(t, data, func(files map[string][]byte) { files["rules.json"] = []byte(`[{}]`) })
+	_, _, err = Read(tampered, testPassphrase)
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	added := rezip(t, data, func(files map[string][]byte) { files["users.json"] = []byte(`[]`) })
+	_, _, err = Read(added, testPassphrase)
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	removed := rezip(t, data, func(files map[string][]byte) { delete(files, "rules.json") })
+	_, _, err = Read(removed, testPassphrase)
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	unsigned := rezip(t, data, func(files map[string][]byte) { delete(files, SignatureFile) })
+	_, _, err = Read(unsigned, testPassphrase)
+	assert.ErrorIs(t, err, ErrMissingSignature)
+
+	_, _, err = Read([]byte("not a zip"), testPassphrase)
+	assert.Error(t, err)
+
+	_, err = NewKey("short")
+	assert.ErrorIs(t, err, ErrPassphraseTooShort)
+}
@@ -0,0 +1,242 @@
+// Package breaker 为外部依赖（数据源、Webhook 等）调用提供熔断保护。
+// 连续失败达到阈值后熔断器打开，在冷却时间内直接拒绝调用；冷却结束后进入半开状态放行少量探测调用，
+// 探测成功则关闭熔断器，失败则重新打开。单次调用设置超时，远端挂起时调用方不会被一直阻塞
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen 熔断器打开时拒绝调用返回的错误
+var ErrOpen = errors.New("熔断器已打开，暂停调用")
+
+// State 熔断器状态
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Config 熔断器配置
+type Config struct {
+	// FailureThreshold 连续失败多少次后打开熔断器，0 表示不熔断
+	FailureThreshold int
+	// OpenTimeout 熔断器打开后多久进入半开状态
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls 半开状态下同时放行的探测调用数
+	HalfOpenMaxCalls int
+	// CallTimeout 单次调用的超时时间，0 表示只使用调用方的 context
+	CallTimeout time.Duration
+}
+
+// Status 熔断器状态快照
+type Status struct {
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Breaker 熔断器，并发安全
+type Breaker struct {
+	config Config
+	now    func() time.Time
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	halfOpenCalls       int
+	openedAt            time.Time
+	lastError           string
+}
+
+// New 创建熔断器
+func New(config Config) *Breaker {
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	return &Breaker{config: config, now: time.Now, state: StateClosed}
+}
+
+// Do 在熔断器保护下执行 fn。熔断器打开时直接返回 ErrOpen；
+// 超过 CallTimeout 或 ctx 结束时立即返回，不等待 fn 退出，fn 应在 ctx 取消后尽快返回
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return fn(ctx)
+	}
+	if err := b.acquire(); err != nil {
+		return err
+	}
+
+	err := b.call(ctx, fn)
+	if err != nil && ctx.Err() != nil {
+		// 调用方取消或超时不代表远端不可用，不计入失败
+		b.abort()
+		return err
+	}
+	b.release(err)
+	return err
+}
+
+// call 在独立的 goroutine 中执行 fn，避免不响应 context 的调用一直占用调用方
+func (b *Breaker) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b.config.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.config.CallTimeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// acquire 判断是否允许调用，冷却时间结束时把打开状态转为半开
+func (b *Breaker) acquire() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if b.now().Sub(b.openedAt) < b.config.OpenTimeout {
+			return ErrOpen
+		}
+		b.state = StateHalfOpen
+		b.halfOpenCalls = 0
+	}
+
+	if b.state == StateHalfOpen {
+		if b.halfOpenCalls >= b.config.HalfOpenMaxCalls {
+			return ErrOpen
+		}
+		b.halfOpenCalls++
+	}
+	return nil
+}
+
+// abort 放弃本次调用的结果，只归还半开状态的探测名额
+func (b *Breaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.halfOpenCalls--
+	}
+}
+
+// release 根据调用结果更新状态
+func (b *Breaker) release(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.halfOpenCalls--
+	}
+
+	if err == nil {
+		b.state = StateClosed
+		b.consecutiveFailures = 0
+		b.lastError = ""
+		return
+	}
+
+	b.consecutiveFailures++
+	b.lastError = err.Error()
+
+	if b.config.FailureThreshold <= 0 {
+		return
+	}
+	if b.state == StateHalfOpen || b.consecutiveFailures >= b.config.FailureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Status 返回熔断器当前状态，冷却时间已结束的打开状态报告为半开
+func (b *Breaker) Status() Status {
+	if b == nil {
+		return Status{State: StateClosed}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		LastError:           b.lastError,
+	}
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		status.State = StateHalfOpen
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// Registry 按名称管理熔断器，同一名称共享同一个熔断器
+type Registry struct {
+	config Config
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry 创建熔断器注册表
+func NewRegistry(config Config) *Registry {
+	return &Registry{config: config, breakers: make(map[string]*Breaker)}
+}
+
+// Get 获取名称对应的熔断器，不存在时创建。注册表为 nil 时返回 nil，调用不受熔断保护
+func (r *Registry) Get(name string) *Breaker {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = New(r.config)
+		r.breakers[name] = b
+	}
+	return b
+}
+
+// Status 返回名称对应熔断器的状态，尚未调用过的名称报告为关闭
+func (r *Registry) Status(name string) Status {
+	if r == nil {
+		return Status{State: StateClosed}
+	}
+
+	r.mu.Lock()
+	b := r.breakers[name]
+	r.mu.Unlock()
+	return b.Status()
+}
+
+// Remove 移除名称对应的熔断器，用于数据源或 Webhook 删除后释放状态
+func (r *Registry) Remove(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.breakers, name)
+}
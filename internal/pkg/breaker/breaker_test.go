@@ -0,0 +1,149 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRemote = errors.New("remote unavailable")
+
+func fail(ctx context.Context) error    { return errRemote }
+func succeed(ctx context.Context) error { return nil }
+
+func newTestBreaker(config Config) (*Breaker, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New(config)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(Config{FailureThreshold: 3, OpenTimeout: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, b.Do(context.Background(), fail), errRemote)
+	}
+	assert.Equal(t, StateClosed, b.Status().State)
+
+	// 成功调用重置连续失败计数
+	require.NoError(t, b.Do(context.Background(), succeed))
+	assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, b.Do(context.Background(), fail), errRemote)
+	}
+	status := b.Status()
+	assert.Equal(t, StateOpen, status.State)
+	assert.Equal(t, 3, status.ConsecutiveFailures)
+	assert.Equal(t, errRemote.Error(), status.LastError)
+	assert.NotNil(t, status.OpenedAt)
+
+	called := false
+	err := b.Do(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.False(t, called)
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	assert.ErrorIs(t, b.Do(context.Background(), fail), errRemote)
+	assert.Equal(t, StateOpen, b.Status().State)
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.Status().State)
+
+	// 探测失败重新打开
+	assert.ErrorIs(t, b.Do(context.Background(), fail), errRemote)
+	assert.Equal(t, StateOpen, b.Status().State)
+	assert.ErrorIs(t, b.Do(context.Background(), succeed), ErrOpen)
+
+	// 探测成功关闭
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Do(context.Background(), succeed))
+	assert.Equal(t, StateClosed, b.Status().State)
+	assert.Nil(t, b.Status().OpenedAt)
+}
+
+func TestBreaker_HalfOpenLimitsConcurrentProbes(t *testing.T) {
+	b, now := newTestBreaker(Config{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenMaxCalls: 1})
+
+	assert.ErrorIs(t, b.Do(context.Background(), fail), errRemote)
+	*now = now.Add(time.Minute)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Do(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-finish
+			return nil
+		})
+	}()
+
+	<-started
+	assert.ErrorIs(t, b.Do(context.Background(), succeed), ErrOpen)
+	close(finish)
+	require.NoError(t, <-done)
+	assert.Equal(t, StateClosed, b.Status().State)
+}
+
+func TestBreaker_CallTimeoutReleasesCaller(t *testing.T) {
+	b := New(Config{FailureThreshold: 1, OpenTimeout: time.Minute, CallTimeout: 20 * time.Millisecond})
+
+	block := make(chan struct{})
+	defer close(block)
+
+	start := time.Now()
+	err := b.Do(context.Background(), func(ctx context.Context) error {
+		// 模拟不响应 context 的调用
+		<-block
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StateOpen, b.Status().State)
+}
+
+func TestBreaker_CallerCancelNotCounted(t *testing.T) {
+	b := New(Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := b.Do(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StateClosed, b.Status().State)
+	assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	assert.Equal(t, StateClosed, r.Status("datasource:1").State)
+	assert.Same(t, r.Get("datasource:1"), r.Get("datasource:1"))
+
+	assert.ErrorIs(t, r.Get("datasource:1").Do(context.Background(), fail), errRemote)
+	assert.Equal(t, StateOpen, r.Status("datasource:1").State)
+	assert.Equal(t, StateClosed, r.Status("datasource:2").State)
+
+	r.Remove("datasource:1")
+	assert.Equal(t, StateClosed, r.Status("datasource:1").State)
+
+	// nil 注册表不做熔断保护
+	var nilRegistry *Registry
+	assert.ErrorIs(t, nilRegistry.Get("x").Do(context.Background(), fail), errRemote)
+	assert.Equal(t, StateClosed, nilRegistry.Status("x").State)
+}
@@ -0,0 +1,70 @@
+// Package dataloader 合并同一请求中对同一类数据的多次按键加载，避免关联查询产生 N+1 次数据库访问
+//
+// Load 只登记键并返回取值函数，第一次调用取值函数时把已登记但尚未加载的键一次性交给批量函数。
+// Loader 缓存每个键的结果，只应在单个请求内使用。
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc 批量加载一组键，结果中没有的键视为不存在，返回错误时这一批键都得到该错误
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader 按键批量加载并缓存结果
+type Loader[K comparable, V any] struct {
+	mu      sync.Mutex
+	batch   BatchFunc[K, V]
+	pending []K
+	results map[K]*result[V]
+}
+
+// result 一个键的加载结果
+type result[V any] struct {
+	done  bool
+	value V
+	err   error
+}
+
+// New 创建 Loader
+func New[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{batch: batch, results: map[K]*result[V]{}}
+}
+
+// Load 登记要加载的键，返回的函数在第一次调用时触发批量加载
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{}
+		l.results[key] = r
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !r.done {
+			l.dispatch(ctx)
+		}
+		return r.value, r.err
+	}
+}
+
+// dispatch 加载所有已登记的键，调用方需持有锁
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.batch(ctx, keys)
+	for _, key := range keys {
+		r := l.results[key]
+		r.done = true
+		r.err = err
+		if err == nil {
+			r.value = values[key]
+		}
+	}
+}
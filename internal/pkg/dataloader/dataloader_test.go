@@ -0,0 +1,64 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_BatchesPendingKeys(t *testing.T) {
+	ctx := context.Background()
+	var batches [][]string
+	loader := New(func(ctx context.Context, keys []string) (map[string]int, error) {
+		batches = append(batches, keys)
+		values := map[string]int{}
+		for _, key := range keys {
+			if key != "missing" {
+				values[key] = len(key)
+			}
+		}
+		return values, nil
+	})
+
+	a := loader.Load(ctx, "a")
+	bb := loader.Load(ctx, "bb")
+	again := loader.Load(ctx, "a")
+	missing := loader.Load(ctx, "missing")
+
+	value, err := bb()
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+	value, _ = a()
+	assert.Equal(t, 1, value)
+	value, _ = again()
+	assert.Equal(t, 1, value)
+	value, err = missing()
+	require.NoError(t, err)
+	assert.Zero(t, value)
+
+	// 已加载的键直接使用缓存，新键单独成批
+	value, _ = loader.Load(ctx, "a")()
+	assert.Equal(t, 1, value)
+	value, _ = loader.Load(ctx, "ccc")()
+	assert.Equal(t, 3, value)
+
+	assert.Equal(t, [][]string{{"a", "bb", "missing"}, {"ccc"}}, batches)
+}
+
+func TestLoader_BatchError(t *testing.T) {
+	ctx := context.Background()
+	loader := New(func(ctx context.Context, keys []int) (map[int]string, error) {
+		return nil, errors.New("db down")
+	})
+
+	first := loader.Load(ctx, 1)
+	second := loader.Load(ctx, 2)
+
+	_, err := first()
+	assert.EqualError(t, err, "db down")
+	_, err = second()
+	assert.EqualError(t, err, "db down")
+}
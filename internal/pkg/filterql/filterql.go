@@ -0,0 +1,341 @@
+// Package filterql 实现列表接口的过滤表达式，解析后生成参数化的 SQL 条件
+//
+// 示例：
+//
+//	severity in (critical, warning) and labels.cluster = "prod" and created_at > now-24h
+//
+// 语法：
+//
+//	expr       = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "not" factor | "(" expr ")" | comparison
+//	comparison = field op value | field [ "not" ] "in" "(" value { "," value } ")"
+//	op         = "=" | "!=" | ">" | ">=" | "<" | "<=" | "~" | "!~"
+//
+// 关键字不区分大小写。值可以是带引号的字符串、不含空白和运算符的单词、数字，
+// 或 now、now-24h、now+30m 这样的相对时间，相对时间的单位支持 s、m、h、d、w。
+// ~ 表示包含（不区分大小写），!~ 表示不包含。
+package filterql
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxExprLength 表达式的最大长度
+	maxExprLength = 2000
+	// maxComparisons 表达式中比较的最大数量
+	maxComparisons = 50
+)
+
+// Op 比较运算符
+type Op string
+
+const (
+	OpEq          Op = "="
+	OpNe          Op = "!="
+	OpGt          Op = ">"
+	OpGte         Op = ">="
+	OpLt          Op = "<"
+	OpLte         Op = "<="
+	OpContains    Op = "~"
+	OpNotContains Op = "!~"
+	OpIn          Op = "in"
+	OpNotIn       Op = "not in"
+)
+
+// Node 表达式节点，为 *And、*Or、*Not 或 *Comparison
+type Node interface {
+	node()
+}
+
+// And 所有子表达式都成立
+type And struct {
+	Nodes []Node
+}
+
+// Or 任一子表达式成立
+type Or struct {
+	Nodes []Node
+}
+
+// Not 子表达式不成立
+type Not struct {
+	Node Node
+}
+
+// Comparison 字段与值的比较，in 和 not in 有多个值
+type Comparison struct {
+	Field  string
+	Op     Op
+	Values []string
+}
+
+func (*And) node()        {}
+func (*Or) node()         {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token 词法单元，pos 为在表达式中的位置（从 1 开始）
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// isKeyword 判断单词是否为关键字，带引号的字符串不是关键字
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// Parse 解析过滤表达式
+func Parse(expr string) (Node, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("过滤表达式不能为空")
+	}
+	if len(expr) > maxExprLength {
+		return nil, fmt.Errorf("过滤表达式不能超过%d个字符", maxExprLength)
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("过滤表达式第 %d 个字符处多余的 %q", t.pos, t.text)
+	}
+	return node, nil
+}
+
+// tokenize 将表达式拆分为词法单元
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i + 1})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i + 1})
+			i++
+		case c == '"' || c == '\'':
+			var value strings.Builder
+			start := i
+			for i++; i < len(expr) && expr[i] != c; i++ {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					i++
+				}
+				value.WriteByte(expr[i])
+			}
+			if i >= len(expr) {
+				return nil, fmt.Errorf("过滤表达式第 %d 个字符处的字符串缺少结束引号", start+1)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), pos: start + 1})
+			i++
+		case strings.IndexByte("=!<>~", c) >= 0:
+			op := string(c)
+			if i+1 < len(expr) {
+				switch two := expr[i : i+2]; two {
+				case "!=", "!~", "<=", ">=":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("过滤表达式第 %d 个字符处无效的运算符 !", i+1)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i + 1})
+			i += len(op)
+		default:
+			start := i
+			for i < len(expr) && !isDelimiter(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expr[start:i], pos: start + 1})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr) + 1}), nil
+}
+
+// isDelimiter 判断字符是否结束一个单词
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\n\r(),\"'=!<>~", c) >= 0
+}
+
+// parser 递归下降解析器
+type parser struct {
+	tokens      []token
+	pos         int
+	comparisons int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseOr expr = term { "or" term }
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{node}
+	for p.peek().isKeyword("or") {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &Or{Nodes: nodes}, nil
+}
+
+// parseAnd term = factor { "and" factor }
+func (p *parser) parseAnd() (Node, error) {
+	node, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{node}
+	for p.peek().isKeyword("and") {
+		p.next()
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &And{Nodes: nodes}, nil
+}
+
+// parseFactor factor = "not" factor | "(" expr ")" | comparison
+func (p *parser) parseFactor() (Node, error) {
+	t := p.peek()
+	switch {
+	case t.isKeyword("not"):
+		p.next()
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Node: node}, nil
+	case t.kind == tokenLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, fmt.Errorf("过滤表达式第 %d 个字符处缺少 )", t.pos)
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison comparison = field op value | field [ "not" ] "in" "(" value { "," value } ")"
+func (p *parser) parseComparison() (Node, error) {
+	field := p.next()
+	if field.kind != tokenWord || isReserved(field) {
+		return nil, fmt.Errorf("过滤表达式第 %d 个字符处缺少字段名", field.pos)
+	}
+
+	p.comparisons++
+	if p.comparisons > maxComparisons {
+		return nil, fmt.Errorf("过滤表达式的条件不能超过%d个", maxComparisons)
+	}
+
+	cmp := &Comparison{Field: field.text}
+	t := p.next()
+	switch {
+	case t.kind == tokenOp:
+		cmp.Op = Op(t.text)
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = []string{value}
+		return cmp, nil
+	case t.isKeyword("in"):
+		cmp.Op = OpIn
+	case t.isKeyword("not") && p.peek().isKeyword("in"):
+		p.next()
+		cmp.Op = OpNotIn
+	default:
+		return nil, fmt.Errorf("过滤表达式第 %d 个字符处字段 %s 后缺少运算符", t.pos, field.text)
+	}
+
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, fmt.Errorf("过滤表达式第 %d 个字符处 in 后缺少 (", t.pos)
+	}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = append(cmp.Values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return cmp, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("过滤表达式第 %d 个字符处缺少 , 或 )", t.pos)
+		}
+	}
+}
+
+// parseValue 解析值，关键字需要加引号才能作为值
+func (p *parser) parseValue() (string, error) {
+	t := p.next()
+	if t.kind == tokenString || (t.kind == tokenWord && !isReserved(t)) {
+		return t.text, nil
+	}
+	return "", fmt.Errorf("过滤表达式第 %d 个字符处缺少值", t.pos)
+}
+
+// isReserved 判断单词是否为关键字
+func isReserved(t token) bool {
+	return t.isKeyword("and") || t.isKeyword("or") || t.isKeyword("not") || t.isKeyword("in")
+}
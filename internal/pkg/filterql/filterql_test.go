@@ -0,0 +1,99 @@
+package filterql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = &Schema{
+	Fields: map[string]Field{
+		"severity":   {Column: "severity", Type: FieldString},
+		"name":       {Column: "name", Type: FieldString},
+		"value":      {Column: "value", Type: FieldNumber},
+		"created_at": {Column: "created_at", Type: FieldTime},
+		"tags":       {Column: "tags", Type: FieldTags},
+	},
+	Maps: map[string]string{"labels": "labels"},
+}
+
+func TestParse(t *testing.T) {
+	node, err := Parse(`severity IN (critical, 'warning') and (labels.cluster="prod" or not name ~ "db") and value>=90`)
+	require.NoError(t, err)
+
+	assert.Equal(t, &And{Nodes: []Node{
+		&Comparison{Field: "severity", Op: OpIn, Values: []string{"critical", "warning"}},
+		&Or{Nodes: []Node{
+			&Comparison{Field: "labels.cluster", Op: OpEq, Values: []string{"prod"}},
+			&Not{Node: &Comparison{Field: "name", Op: OpContains, Values: []string{"db"}}},
+		}},
+		&Comparison{Field: "value", Op: OpGte, Values: []string{"90"}},
+	}}, node)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"severity",
+		"severity =",
+		"severity = critical and",
+		"severity in critical",
+		"severity in (critical",
+		"(severity = critical",
+		"severity = critical)",
+		`name = "db`,
+		"severity ! critical",
+		"and = 1",
+		"severity = in",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestSchema_Compile(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	where, args, err := testSchema.Compile(
+		`severity in (critical,warning) and labels.cluster="prod" and created_at>now-24h`, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, "(severity = ANY($3) AND labels @> $4::jsonb AND created_at > $5)", where)
+	assert.Equal(t, []interface{}{
+		pq.Array([]string{"critical", "warning"}), `{"cluster":"prod"}`, now.Add(-24 * time.Hour),
+	}, args)
+
+	// 值为空或使用 ~ 时按键的值比较
+	where, args, err = testSchema.Compile(`labels.team not in (db, web) or labels.team = "" or labels.host ~ web`, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, "(NOT COALESCE(labels @> ANY($1::jsonb[]), false) OR COALESCE(labels ->> $2, '') = $3 OR "+
+		"CAST(COALESCE(labels ->> $4, '') AS TEXT) ILIKE $5)", where)
+	assert.Equal(t, []interface{}{
+		pq.Array([]string{`{"team":"db"}`, `{"team":"web"}`}), "team", "", "host", "%web%",
+	}, args)
+
+	where, args, err = testSchema.Compile(`not (name ~ "50%" or tags != db) and created_at <= 2024-01-01`, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, "(NOT COALESCE((CAST(name AS TEXT) ILIKE $1 OR NOT COALESCE(tags::jsonb ? $2, false)), false) AND created_at <= $3)", where)
+	assert.Equal(t, []interface{}{`%50\%%`, "db", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, args)
+}
+
+func TestSchema_Compile_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, expr := range []string{
+		"unknown = 1",
+		"labels. = x",
+		"value = high",
+		"value ~ 1",
+		"created_at in (now)",
+		"created_at > yesterday",
+		"created_at > now-1y",
+		"tags > db",
+		"name > db",
+	} {
+		_, _, err := testSchema.Compile(expr, 1, now)
+		assert.Error(t, err, expr)
+	}
+}
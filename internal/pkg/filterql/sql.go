@@ -0,0 +1,282 @@
+package filterql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// FieldType 字段类型，决定值的解析方式和可用的运算符
+type FieldType int
+
+const (
+	FieldString FieldType = iota // 字符串，支持 = != ~ !~ in 和 not in
+	FieldNumber                  // 数字，支持比较运算符、in 和 not in
+	FieldTime                    // 时间，支持比较运算符，值为 RFC3339、YYYY-MM-DD 或相对时间
+	FieldTags                    // JSON 字符串数组，= 表示包含标签，in 表示包含任一标签
+)
+
+// Field 可过滤的字段
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// Schema 列表接口可过滤的字段
+type Schema struct {
+	Fields map[string]Field
+	// Maps JSONB 键值对字段，通过“前缀.键”访问，如 labels.cluster，缺少的键视为空字符串。
+	// =、!=、in 和 not in 使用 @> 包含查询以命中 GIN 索引
+	Maps map[string]string
+}
+
+// Compile 解析过滤表达式并转换为 SQL 条件，参数占位符从 $argIndex 开始，now 为相对时间的基准
+func (s *Schema) Compile(expr string, argIndex int, now time.Time) (string, []interface{}, error) {
+	node, err := Parse(expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.Where(node, argIndex, now)
+}
+
+// Where 将表达式转换为 SQL 条件，参数占位符从 $argIndex 开始，now 为相对时间的基准
+func (s *Schema) Where(node Node, argIndex int, now time.Time) (string, []interface{}, error) {
+	b := &sqlBuilder{schema: s, argIndex: argIndex, now: now}
+	where, err := b.build(node)
+	if err != nil {
+		return "", nil, err
+	}
+	return where, b.args, nil
+}
+
+// sqlBuilder 生成 SQL 条件并收集参数
+type sqlBuilder struct {
+	schema   *Schema
+	argIndex int
+	args     []interface{}
+	now      time.Time
+}
+
+// arg 添加参数并返回占位符
+func (b *sqlBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	b.argIndex++
+	return fmt.Sprintf("$%d", b.argIndex-1)
+}
+
+func (b *sqlBuilder) build(node Node) (string, error) {
+	switch n := node.(type) {
+	case *And:
+		return b.join(n.Nodes, " AND ")
+	case *Or:
+		return b.join(n.Nodes, " OR ")
+	case *Not:
+		inner, err := b.build(n.Node)
+		if err != nil {
+			return "", err
+		}
+		// 字段为 NULL 时条件为 NULL，取反后视为成立
+		return "NOT COALESCE(" + inner + ", false)", nil
+	case *Comparison:
+		return b.comparison(n)
+	}
+	return "", fmt.Errorf("不支持的过滤表达式节点 %T", node)
+}
+
+func (b *sqlBuilder) join(nodes []Node, sep string) (string, error) {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		part, err := b.build(node)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return "(" + strings.Join(parts, sep) + ")", nil
+}
+
+// mapField 查找“前缀.键”形式的 Maps 字段
+func (s *Schema) mapField(name string) (column, key string, ok bool) {
+	dot := strings.IndexByte(name, '.')
+	if dot <= 0 || dot == len(name)-1 {
+		return "", "", false
+	}
+	column, ok = s.Maps[name[:dot]]
+	return column, name[dot+1:], ok
+}
+
+func (b *sqlBuilder) comparison(cmp *Comparison) (string, error) {
+	if column, key, ok := b.schema.mapField(cmp.Field); ok {
+		return b.mapComparison(column, key, cmp)
+	}
+
+	field, ok := b.schema.Fields[cmp.Field]
+	if !ok {
+		return "", fmt.Errorf("不支持按字段 %s 过滤", cmp.Field)
+	}
+	column := field.Column
+
+	switch field.Type {
+	case FieldString:
+		return b.stringComparison(column, cmp)
+	case FieldNumber:
+		values := make([]float64, 0, len(cmp.Values))
+		for _, v := range cmp.Values {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return "", fmt.Errorf("字段 %s 的值 %q 不是数字", cmp.Field, v)
+			}
+			values = append(values, f)
+		}
+		switch cmp.Op {
+		case OpIn:
+			return fmt.Sprintf("%s = ANY(%s)", column, b.arg(pq.Array(values))), nil
+		case OpNotIn:
+			return fmt.Sprintf("NOT COALESCE(%s = ANY(%s), false)", column, b.arg(pq.Array(values))), nil
+		}
+		return b.ordered(column, cmp, values[0])
+	case FieldTime:
+		if cmp.Op == OpIn || cmp.Op == OpNotIn {
+			return "", fmt.Errorf("时间字段 %s 不支持运算符 %s", cmp.Field, cmp.Op)
+		}
+		t, err := parseTime(cmp.Values[0], b.now)
+		if err != nil {
+			return "", fmt.Errorf("字段 %s 的值无效: %v", cmp.Field, err)
+		}
+		return b.ordered(column, cmp, t)
+	case FieldTags:
+		var condition string
+		switch cmp.Op {
+		case OpEq, OpNe:
+			condition = fmt.Sprintf("COALESCE(%s::jsonb ? %s, false)", column, b.arg(cmp.Values[0]))
+		case OpIn, OpNotIn:
+			condition = fmt.Sprintf("COALESCE(%s::jsonb ?| %s, false)", column, b.arg(pq.Array(cmp.Values)))
+		default:
+			return "", fmt.Errorf("标签字段 %s 不支持运算符 %s", cmp.Field, cmp.Op)
+		}
+		if cmp.Op == OpNe || cmp.Op == OpNotIn {
+			condition = "NOT " + condition
+		}
+		return condition, nil
+	}
+	return "", fmt.Errorf("字段 %s 的类型无效", cmp.Field)
+}
+
+// mapComparison Maps 字段的比较。值都不为空时 =、!=、in 和 not in 使用 @> 包含查询，
+// 其余情况按字符串比较键的值，缺少的键视为空字符串
+func (b *sqlBuilder) mapComparison(column, key string, cmp *Comparison) (string, error) {
+	containment := cmp.Op == OpEq || cmp.Op == OpNe || cmp.Op == OpIn || cmp.Op == OpNotIn
+	for _, v := range cmp.Values {
+		if v == "" {
+			containment = false
+		}
+	}
+	if !containment {
+		return b.stringComparison(fmt.Sprintf("COALESCE(%s ->> %s, '')", column, b.arg(key)), cmp)
+	}
+
+	objects := make([]string, 0, len(cmp.Values))
+	for _, v := range cmp.Values {
+		object, err := json.Marshal(map[string]string{key: v})
+		if err != nil {
+			return "", fmt.Errorf("序列化字段 %s 的值失败: %v", cmp.Field, err)
+		}
+		objects = append(objects, string(object))
+	}
+
+	var condition string
+	if cmp.Op == OpEq || cmp.Op == OpNe {
+		condition = fmt.Sprintf("%s @> %s::jsonb", column, b.arg(objects[0]))
+	} else {
+		condition = fmt.Sprintf("%s @> ANY(%s::jsonb[])", column, b.arg(pq.Array(objects)))
+	}
+	if cmp.Op == OpNe || cmp.Op == OpNotIn {
+		condition = "NOT COALESCE(" + condition + ", false)"
+	}
+	return condition, nil
+}
+
+func (b *sqlBuilder) stringComparison(column string, cmp *Comparison) (string, error) {
+	switch cmp.Op {
+	case OpEq:
+		return fmt.Sprintf("%s = %s", column, b.arg(cmp.Values[0])), nil
+	case OpNe:
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", column, b.arg(cmp.Values[0])), nil
+	case OpContains:
+		return fmt.Sprintf("CAST(%s AS TEXT) ILIKE %s", column, b.arg("%"+escapeLike(cmp.Values[0])+"%")), nil
+	case OpNotContains:
+		return fmt.Sprintf("COALESCE(CAST(%s AS TEXT), '') NOT ILIKE %s", column, b.arg("%"+escapeLike(cmp.Values[0])+"%")), nil
+	case OpIn:
+		return fmt.Sprintf("%s = ANY(%s)", column, b.arg(pq.Array(cmp.Values))), nil
+	case OpNotIn:
+		return fmt.Sprintf("NOT COALESCE(%s = ANY(%s), false)", column, b.arg(pq.Array(cmp.Values))), nil
+	}
+	return "", fmt.Errorf("字符串字段 %s 不支持运算符 %s", cmp.Field, cmp.Op)
+}
+
+// ordered 数字和时间字段的比较
+func (b *sqlBuilder) ordered(column string, cmp *Comparison, value interface{}) (string, error) {
+	switch cmp.Op {
+	case OpEq, OpGt, OpGte, OpLt, OpLte:
+		return fmt.Sprintf("%s %s %s", column, cmp.Op, b.arg(value)), nil
+	case OpNe:
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", column, b.arg(value)), nil
+	}
+	return "", fmt.Errorf("字段 %s 不支持运算符 %s", cmp.Field, cmp.Op)
+}
+
+// escapeLike 转义 LIKE 模式中的特殊字符
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// parseTime 解析时间值，支持 now、now-24h、now+30m、RFC3339 和 YYYY-MM-DD
+func parseTime(value string, now time.Time) (time.Time, error) {
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "now") {
+		rest := lower[len("now"):]
+		if rest == "" {
+			return now, nil
+		}
+		sign := rest[0]
+		if sign != '-' && sign != '+' {
+			return time.Time{}, fmt.Errorf("无效的相对时间 %q", value)
+		}
+		d, err := parseDuration(rest[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("无效的相对时间 %q", value)
+		}
+		if sign == '-' {
+			d = -d
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无效的时间 %q，应为 RFC3339、YYYY-MM-DD 或 now-24h 这样的相对时间", value)
+}
+
+// parseDuration 解析时长，在 time.ParseDuration 的基础上支持天（d）和周（w）
+func parseDuration(value string) (time.Duration, error) {
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		count, err := strconv.Atoi(value[:n-1])
+		if err != nil {
+			return 0, err
+		}
+		day := 24 * time.Hour
+		if value[n-1] == 'w' {
+			day *= 7
+		}
+		return time.Duration(count) * day, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,453 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Request GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response GraphQL 响应，查询无法执行时 Data 为空
+type Response struct {
+	Data   *OrderedMap `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error 查询错误，Path 为出错字段在结果中的路径
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Location 查询中的位置，行列从 1 开始
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// OrderedMap 按查询中字段的顺序输出的对象
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]interface{}{}}
+}
+
+// Set 设置字段的值，新字段追加在末尾
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get 获取字段的值
+func (m *OrderedMap) Get(key string) interface{} {
+	return m.values[key]
+}
+
+// Keys 按顺序返回字段名
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// MarshalJSON 按字段顺序输出 JSON 对象
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Execute 解析、校验并执行查询，语法和校验错误时 Data 为空
+func (s *Schema) Execute(ctx context.Context, req *Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if verr := s.validate(req.Query, doc, op); verr != nil {
+		return &Response{Errors: []*Error{verr}}
+	}
+
+	variables, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{ctx: ctx, source: req.Query, doc: doc, variables: variables}
+	data := newOrderedMap()
+	e.run(&objectTask{object: s.query, selections: op.selections, out: data})
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// selectOperation 按名称选择操作，只有一个操作时可以不指定名称
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("查询中有多个操作，需要指定 operationName")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("操作 %s 不存在", name)
+}
+
+// coerceVariables 按变量定义转换请求中的变量，缺少的变量使用默认值
+func coerceVariables(op *operation, input map[string]interface{}) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	for _, def := range op.variables {
+		scalar, _ := inputScalar(def.typ)
+		raw, ok := input[def.name]
+		if !ok && def.defaultValue != nil {
+			value, _ := literalValue(scalar, def.defaultValue)
+			variables[def.name] = value
+			continue
+		}
+		if raw == nil {
+			if def.typ.nonNull {
+				return nil, fmt.Errorf("变量 $%s 不能为空", def.name)
+			}
+			if ok {
+				variables[def.name] = nil
+			}
+			continue
+		}
+		value, err := scalar.ParseValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("变量 $%s 不正确：%s", def.name, err)
+		}
+		variables[def.name] = value
+	}
+	return variables, nil
+}
+
+// executor 一次查询的执行状态
+type executor struct {
+	ctx       context.Context
+	source    string
+	doc       *document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+// objectTask 待执行的对象，字段值写入 out
+type objectTask struct {
+	object     *Object
+	source     interface{}
+	selections []selection
+	out        *OrderedMap
+	path       []interface{}
+}
+
+// fieldTask 同一层中待求值的字段
+type fieldTask struct {
+	parent *objectTask
+	key    string
+	field  *Field
+	nodes  []*fieldNode
+	value  interface{}
+	err    error
+}
+
+// run 按层执行：先解析一层的所有字段，再对其中的 Thunk 求值，最后补全结果并收集下一层的对象
+func (e *executor) run(root *objectTask) {
+	level := []*objectTask{root}
+	for len(level) > 0 {
+		var tasks []*fieldTask
+		for _, obj := range level {
+			fields := e.collectFields(obj.object, obj.selections)
+			for _, key := range fields.keys {
+				nodes := fields.nodes[key]
+				if nodes[0].name == "__typename" {
+					obj.out.Set(key, obj.object.Name)
+					continue
+				}
+
+				// 先占位，保证结果字段按查询顺序输出
+				obj.out.Set(key, nil)
+				task := &fieldTask{parent: obj, key: key, field: obj.object.Field(nodes[0].name), nodes: nodes}
+				task.value, task.err = e.resolve(obj, task.field, nodes[0])
+				tasks = append(tasks, task)
+			}
+		}
+
+		for _, task := range tasks {
+			if thunk, ok := task.value.(Thunk); ok && task.err == nil {
+				task.value, task.err = thunk()
+			}
+		}
+
+		var next []*objectTask
+		for _, task := range tasks {
+			path := appendPath(task.parent.path, task.key)
+			if task.err != nil {
+				e.addError(task.err.Error(), task.nodes[0], path)
+				continue
+			}
+			var selections []selection
+			for _, node := range task.nodes {
+				selections = append(selections, node.selections...)
+			}
+			value := e.complete(task.field.Type, task.value, selections, task.nodes[0], path, &next)
+			task.parent.out.Set(task.key, value)
+		}
+		level = next
+	}
+}
+
+// resolve 调用字段的解析函数，没有解析函数时读取父对象的同名字段
+func (e *executor) resolve(obj *objectTask, field *Field, node *fieldNode) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("解析字段 %s 失败: %v", field.Name, r)
+		}
+	}()
+
+	args := map[string]interface{}{}
+	for _, def := range field.Args {
+		if def.DefaultValue != nil {
+			args[def.Name] = def.DefaultValue
+		}
+	}
+	for _, arg := range node.args {
+		if value, ok := e.argumentValue(namedType(field.arg(arg.name).Type).(*Scalar), arg.value); ok {
+			args[arg.name] = value
+		}
+	}
+
+	if field.Resolve == nil {
+		return defaultResolve(obj.source, field.Name)
+	}
+	return field.Resolve(ResolveParams{Context: e.ctx, Source: obj.source, Args: args})
+}
+
+// argumentValue 计算参数值，引用了未提供的变量时返回 false
+func (e *executor) argumentValue(scalar *Scalar, value *valueNode) (interface{}, bool) {
+	if value.kind == valueVariable {
+		v, ok := e.variables[value.text]
+		return v, ok
+	}
+	v, _ := literalValue(scalar, value)
+	return v, true
+}
+
+// complete 按字段类型补全解析结果，对象类型加入下一层待执行
+func (e *executor) complete(typ Type, value interface{}, selections []selection, node *fieldNode, path []interface{}, next *[]*objectTask) interface{} {
+	if nonNull, ok := typ.(*NonNull); ok {
+		completed := e.complete(nonNull.OfType, value, selections, node, path, next)
+		if completed == nil {
+			e.addError(fmt.Sprintf("字段 %s 的类型为 %s，不能为 null", node.name, typ), node, path)
+		}
+		return completed
+	}
+
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch t := typ.(type) {
+	case *List:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			e.addError(fmt.Sprintf("字段 %s 的类型为列表，解析结果为 %s", node.name, v.Type()), node, path)
+			return nil
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = e.complete(t.OfType, v.Index(i).Interface(), selections, node, appendPath(path, i), next)
+		}
+		return items
+	case *Scalar:
+		serialized, err := t.Serialize(v.Interface())
+		if err != nil {
+			e.addError(err.Error(), node, path)
+			return nil
+		}
+		return serialized
+	case *Object:
+		out := newOrderedMap()
+		*next = append(*next, &objectTask{object: t, source: value, selections: selections, out: out, path: path})
+		return out
+	}
+	return nil
+}
+
+// collectedFields 按结果键合并后的字段
+type collectedFields struct {
+	keys  []string
+	nodes map[string][]*fieldNode
+}
+
+// collectFields 展开片段并按 @include 和 @skip 过滤，同一结果键的字段合并
+func (e *executor) collectFields(object *Object, selections []selection) *collectedFields {
+	fields := &collectedFields{nodes: map[string][]*fieldNode{}}
+	var collect func(selections []selection)
+	collect = func(selections []selection) {
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *fieldNode:
+				if !e.shouldInclude(sel.directives) {
+					continue
+				}
+				key := sel.responseKey()
+				if _, exists := fields.nodes[key]; !exists {
+					fields.keys = append(fields.keys, key)
+				}
+				fields.nodes[key] = append(fields.nodes[key], sel)
+			case *fragmentSpread:
+				if e.shouldInclude(sel.directives) {
+					collect(e.doc.fragments[sel.name].selections)
+				}
+			case *inlineFragment:
+				if e.shouldInclude(sel.directives) {
+					collect(sel.selections)
+				}
+			}
+		}
+	}
+	collect(selections)
+	return fields
+}
+
+// shouldInclude 计算 @include(if:) 和 @skip(if:)
+func (e *executor) shouldInclude(directives []*directiveNode) bool {
+	for _, directive := range directives {
+		value, _ := e.argumentValue(Boolean, directive.args[0].value)
+		condition, _ := value.(bool)
+		if directive.name == "include" && !condition {
+			return false
+		}
+		if directive.name == "skip" && condition {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *executor) addError(message string, node *fieldNode, path []interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message:   message,
+		Locations: []Location{locate(e.source, node.pos)},
+		Path:      path,
+	})
+}
+
+// appendPath 复制路径后追加，避免兄弟字段共用底层数组
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, elem)
+}
+
+// jsonFieldIndex 缓存结构体类型的 json 字段名到字段下标的映射
+var jsonFieldIndex sync.Map
+
+// defaultResolve 按 json 标签读取结构体字段，或按键名读取 map
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	v := reflect.ValueOf(source)
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return value.Interface(), nil
+	case reflect.Struct:
+		index, ok := jsonFieldIndex.Load(v.Type())
+		if !ok {
+			index, _ = jsonFieldIndex.LoadOrStore(v.Type(), structJSONFields(v.Type()))
+		}
+		if i, ok := index.(map[string][]int)[name]; ok {
+			return v.FieldByIndex(i).Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("无法从 %T 读取字段 %s", source, name)
+}
+
+// structJSONFields 收集结构体的 json 字段名，包括嵌入结构体的字段
+func structJSONFields(t reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for embedded, index := range structJSONFields(f.Type) {
+				if _, exists := fields[embedded]; !exists {
+					fields[embedded] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
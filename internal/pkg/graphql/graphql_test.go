@@ -0,0 +1,228 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAuthor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testPost struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	AuthorID  string     `json:"author_id"`
+	Score     *float64   `json:"score,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Tags      []string   `json:"tags"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// newTestSchema 文章和作者，作者字段按层批量加载，batches 记录每批加载的作者 ID
+func newTestSchema(posts []*testPost, batches *[][]string) *Schema {
+	author := NewObject("Author", "作者",
+		&Field{Name: "id", Type: NewNonNull(ID)},
+		&Field{Name: "name", Type: String},
+	)
+
+	var pending []string
+	loaded := map[string]*testAuthor{}
+	post := NewObject("Post", "文章",
+		&Field{Name: "id", Type: NewNonNull(ID)},
+		&Field{Name: "title", Type: String, Description: "标题"},
+		&Field{Name: "score", Type: Float},
+		&Field{Name: "created_at", Type: Time},
+		&Field{Name: "closed_at", Type: Time},
+		&Field{Name: "tags", Type: NewList(NewNonNull(String))},
+		&Field{Name: "author", Type: author, Resolve: func(p ResolveParams) (interface{}, error) {
+			id := p.Source.(*testPost).AuthorID
+			pending = append(pending, id)
+			return Thunk(func() (interface{}, error) {
+				if len(pending) > 0 {
+					*batches = append(*batches, pending)
+					for _, id := range pending {
+						loaded[id] = &testAuthor{ID: id, Name: "user-" + id}
+					}
+					pending = nil
+				}
+				return loaded[id], nil
+			}), nil
+		}},
+	)
+
+	query := NewObject("Query", "",
+		&Field{Name: "posts", Type: NewList(post), Args: []*Argument{
+			{Name: "limit", Type: Int, DefaultValue: 10},
+		}, Resolve: func(p ResolveParams) (interface{}, error) {
+			limit := p.Args["limit"].(int)
+			if limit > len(posts) {
+				limit = len(posts)
+			}
+			return posts[:limit], nil
+		}},
+		&Field{Name: "post", Type: post, Args: []*Argument{
+			{Name: "id", Type: NewNonNull(ID)},
+		}, Resolve: func(p ResolveParams) (interface{}, error) {
+			for _, post := range posts {
+				if post.ID == p.Args["id"] {
+					return post, nil
+				}
+			}
+			return nil, fmt.Errorf("文章 %s 不存在", p.Args["id"])
+		}},
+	)
+	return NewSchema(query)
+}
+
+func testPosts() []*testPost {
+	score := 4.5
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []*testPost{
+		{ID: "p1", Title: "first", AuthorID: "u1", Score: &score, CreatedAt: created, Tags: []string{"a"}},
+		{ID: "p2", Title: "second", AuthorID: "u2", CreatedAt: created},
+		{ID: "p3", Title: "third", AuthorID: "u1", CreatedAt: created},
+	}
+}
+
+func marshal(t *testing.T, resp *Response) string {
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExecute_NestedFieldsAreBatchedPerLevel(t *testing.T) {
+	var batches [][]string
+	schema := newTestSchema(testPosts(), &batches)
+
+	resp := schema.Execute(context.Background(), &Request{Query: `
+		# 每篇文章的作者在同一批中加载
+		query Posts($limit: Int = 3) {
+			posts(limit: $limit) {
+				__typename
+				name: title
+				...Meta
+				author { id name }
+			}
+		}
+		fragment Meta on Post { score created_at tags }`})
+
+	assert.Equal(t, `{"data":{"posts":[`+
+		`{"__typename":"Post","name":"first","score":4.5,"created_at":"2024-01-02T03:04:05Z","tags":["a"],"author":{"id":"u1","name":"user-u1"}},`+
+		`{"__typename":"Post","name":"second","score":null,"created_at":"2024-01-02T03:04:05Z","tags":null,"author":{"id":"u2","name":"user-u2"}},`+
+		`{"__typename":"Post","name":"third","score":null,"created_at":"2024-01-02T03:04:05Z","tags":null,"author":{"id":"u1","name":"user-u1"}}]}}`,
+		marshal(t, resp))
+	assert.Equal(t, [][]string{{"u1", "u2", "u1"}}, batches)
+}
+
+func TestExecute_VariablesDirectivesAndFieldErrors(t *testing.T) {
+	var batches [][]string
+	schema := newTestSchema(testPosts(), &batches)
+
+	resp := schema.Execute(context.Background(), &Request{
+		Query: `query One($id: ID!, $withAuthor: Boolean!) {
+			post(id: $id) { id author @include(if: $withAuthor) { name } }
+			missing: post(id: "nope") { id }
+		}`,
+		Variables: map[string]interface{}{"id": "p2", "withAuthor": false},
+	})
+
+	assert.Equal(t, `{"data":{"post":{"id":"p2"},"missing":null},`+
+		`"errors":[{"message":"文章 nope 不存在","locations":[{"line":3,"column":4}],"path":["missing"]}]}`,
+		marshal(t, resp))
+	assert.Empty(t, batches)
+
+	resp = schema.Execute(context.Background(), &Request{
+		Query:     `query One($id: ID!) { post(id: $id) { id } }`,
+		Variables: map[string]interface{}{},
+	})
+	require.Len(t, resp.Errors, 1)
+	assert.Nil(t, resp.Data)
+	assert.Equal(t, "变量 $id 不能为空", resp.Errors[0].Message)
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	schema := newTestSchema(testPosts(), &[][]string{})
+
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{`mutation { posts { id } }`, "第 1 行第 1 列处仅支持 query 操作，不支持 mutation"},
+		{`{ posts { id `, "第 1 行第 14 列处查询意外结束，缺少名称"},
+		{`{ posts { body } }`, "类型 Post 没有字段 body"},
+		{`{ posts }`, "字段 posts 的类型为 [Post]，必须选择子字段"},
+		{`{ posts { title { x } } }`, "字段 title 的类型为 String，不能选择子字段"},
+		{`{ post { id } }`, "字段 Query.post缺少必填参数 id"},
+		{`{ posts(limit: "ten") { id } }`, "字段 Query.posts的参数 limit 不正确：需要 32 位整数"},
+		{`{ posts(offset: 1) { id } }`, "字段 Query.posts没有参数 offset"},
+		{`query($n: String) { posts(limit: $n) { id } }`, "变量 $n 的类型 String 不能用于 Int 类型的参数 limit"},
+		{`{ posts(limit: $n) { id } }`, "变量 $n 没有定义"},
+		{`{ posts { ...A } } fragment A on Post { ...B } fragment B on Post { ...A }`, "片段 A 循环引用"},
+		{`{ posts { ...A } } fragment A on Author { id }`, "片段 A 的类型 Author 与 Post 不符"},
+		{`{ posts { id id: title } }`, "结果字段 id 同时对应 id 和 title"},
+		{`{ posts { id @defer } }`, "不支持指令 @defer"},
+		{`{ posts { author { id } } } { posts { id } }`, "查询中有多个操作，需要指定 operationName"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), &Request{Query: tt.query})
+			require.Len(t, resp.Errors, 1)
+			assert.Nil(t, resp.Data)
+			assert.Contains(t, resp.Errors[0].Message, tt.message)
+		})
+	}
+}
+
+func TestExecute_MaxDepth(t *testing.T) {
+	node := NewObject("Node", "", &Field{Name: "id", Type: ID})
+	node.AddFields(&Field{Name: "parent", Type: node})
+	schema := NewSchema(NewObject("Query", "", &Field{Name: "node", Type: node}))
+
+	query := "{ node { id } }"
+	for i := 0; i < maxDepth; i++ {
+		query = "{ node { parent" + query[len("{ node"):] + " }"
+	}
+	resp := schema.Execute(context.Background(), &Request{Query: query})
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "查询嵌套不能超过10层")
+}
+
+func TestSchema_SDL(t *testing.T) {
+	schema := newTestSchema(nil, &[][]string{})
+
+	assert.Equal(t, `"RFC 3339 格式的时间"
+scalar Time
+
+type Query {
+  posts(limit: Int = 10): [Post]
+  post(id: ID!): Post
+}
+
+"文章"
+type Post {
+  id: ID!
+  "标题"
+  title: String
+  score: Float
+  created_at: Time
+  closed_at: Time
+  tags: [String!]
+  author: Author
+}
+
+"作者"
+type Author {
+  id: ID!
+  name: String
+}
+`, schema.SDL())
+}
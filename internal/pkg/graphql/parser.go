@@ -0,0 +1,734 @@
+// Package graphql 实现 GraphQL 查询的一个子集，供前端一次请求获取嵌套数据
+//
+// 示例：
+//
+//	query Alert($id: ID!) {
+//	  alert(id: $id) {
+//	    name
+//	    severity
+//	    tickets { number status comments { content } }
+//	  }
+//	}
+//
+// 支持 query 操作、变量及默认值、别名、参数、命名片段和内联片段、@include 和 @skip 指令，
+// 不支持 mutation、subscription、内省查询，参数值只能是标量。
+//
+// 执行按层进行：先调用同一层所有字段的解析函数，解析函数可以返回 Thunk 延迟取值，
+// 整层收集完后再依次求值，配合 dataloader 可以把同一层的关联查询合并为一次批量查询。
+// 字段出错时该字段为 null 并在 errors 中记录错误，不向上传播。
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxQueryLength 查询文本的最大长度
+	maxQueryLength = 32 * 1024
+	// maxDepth 选择集的最大嵌套深度
+	maxDepth = 10
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token 词法单元，pos 为在查询中的字节偏移
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is 判断是否为指定的标点
+func (t token) is(punct string) bool {
+	return t.kind == tokenPunct && t.text == punct
+}
+
+// document 解析后的查询文档
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation 查询操作
+type operation struct {
+	name       string
+	variables  []*variableDef
+	selections []selection
+	pos        int
+}
+
+// variableDef 变量定义
+type variableDef struct {
+	name         string
+	typ          *typeRef
+	defaultValue *valueNode
+	pos          int
+}
+
+// typeRef 变量的类型引用，elem 不为空时为列表类型
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// selection 选择集中的一项，为 *fieldNode、*fragmentSpread 或 *inlineFragment
+type selection interface {
+	selection()
+}
+
+// fieldNode 字段选择
+type fieldNode struct {
+	alias      string
+	name       string
+	args       []*argumentNode
+	directives []*directiveNode
+	selections []selection
+	pos        int
+}
+
+// responseKey 字段在结果中的键名，有别名时使用别名
+func (f *fieldNode) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// fragmentSpread 命名片段展开 ...Name
+type fragmentSpread struct {
+	name       string
+	directives []*directiveNode
+	pos        int
+}
+
+// inlineFragment 内联片段 ... on Type { }
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directiveNode
+	selections    []selection
+	pos           int
+}
+
+func (*fieldNode) selection()      {}
+func (*fragmentSpread) selection() {}
+func (*inlineFragment) selection() {}
+
+// fragment 命名片段定义
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []selection
+	pos           int
+}
+
+// argumentNode 参数
+type argumentNode struct {
+	name  string
+	value *valueNode
+	pos   int
+}
+
+// directiveNode 指令
+type directiveNode struct {
+	name string
+	args []*argumentNode
+	pos  int
+}
+
+// valueKind 字面值类型
+type valueKind int
+
+const (
+	valueVariable valueKind = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueNull
+	valueEnum
+)
+
+// valueNode 参数的字面值或变量引用，变量引用的 text 为变量名
+type valueNode struct {
+	kind valueKind
+	text string
+	pos  int
+}
+
+// parse 解析查询文本
+func parse(source string) (*document, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("查询不能为空")
+	}
+	if len(source) > maxQueryLength {
+		return nil, fmt.Errorf("查询不能超过%d个字符", maxQueryLength)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{source: source, tokens: tokens}
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.peek().kind != tokenEOF {
+		t := p.peek()
+		switch {
+		case t.is("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{selections: selections, pos: t.pos})
+		case t.kind == tokenName && t.text == "query":
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case t.kind == tokenName && (t.text == "mutation" || t.text == "subscription"):
+			return nil, p.errorf(t.pos, "仅支持 query 操作，不支持 %s", t.text)
+		case t.kind == tokenName && t.text == "fragment":
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.fragments[frag.name]; exists {
+				return nil, p.errorf(frag.pos, "片段 %s 重复定义", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.errorf(t.pos, "意外的 %q", t.text)
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("查询中没有操作")
+	}
+	return doc, nil
+}
+
+// tokenize 将查询拆分为词法单元，逗号和 # 开头的注释视为空白
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(source) && source[i] != '\n' && source[i] != '\r' {
+				i++
+			}
+		case c == '.':
+			if !strings.HasPrefix(source[i:], "...") {
+				return nil, fmt.Errorf("%s处意外的 \".\"", location(source, i))
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: "...", pos: i})
+			i += 3
+		case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), pos: i})
+			i++
+		case c == '_' || isLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenName, text: source[start:i], pos: start})
+		case c == '-' || isDigit(c):
+			t, next, err := scanNumber(source, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = next
+		case c == '"':
+			t, next, err := scanString(source, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = next
+		default:
+			return nil, fmt.Errorf("%s处意外的字符 %q", location(source, i), c)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// scanNumber 读取整数或浮点数
+func scanNumber(source string, start int) (token, int, error) {
+	i := start
+	if source[i] == '-' {
+		i++
+	}
+	digits := i
+	for i < len(source) && isDigit(source[i]) {
+		i++
+	}
+	if i == digits {
+		return token{}, 0, fmt.Errorf("%s处的数字格式不正确", location(source, start))
+	}
+
+	kind := tokenInt
+	if i < len(source) && source[i] == '.' {
+		kind = tokenFloat
+		i++
+		fraction := i
+		for i < len(source) && isDigit(source[i]) {
+			i++
+		}
+		if i == fraction {
+			return token{}, 0, fmt.Errorf("%s处的数字格式不正确", location(source, start))
+		}
+	}
+	if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+		kind = tokenFloat
+		i++
+		if i < len(source) && (source[i] == '+' || source[i] == '-') {
+			i++
+		}
+		exponent := i
+		for i < len(source) && isDigit(source[i]) {
+			i++
+		}
+		if i == exponent {
+			return token{}, 0, fmt.Errorf("%s处的数字格式不正确", location(source, start))
+		}
+	}
+	if i < len(source) && (source[i] == '_' || isLetter(source[i]) || source[i] == '.') {
+		return token{}, 0, fmt.Errorf("%s处的数字格式不正确", location(source, start))
+	}
+	return token{kind: kind, text: source[start:i], pos: start}, i, nil
+}
+
+// scanString 读取带引号的字符串并处理转义，不支持块字符串
+func scanString(source string, start int) (token, int, error) {
+	if strings.HasPrefix(source[start:], `"""`) {
+		return token{}, 0, fmt.Errorf("%s处不支持块字符串", location(source, start))
+	}
+
+	var sb strings.Builder
+	for i := start + 1; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case c == '"':
+			return token{kind: tokenString, text: sb.String(), pos: start}, i + 1, nil
+		case c == '\n' || c == '\r':
+			return token{}, 0, fmt.Errorf("%s处的字符串没有结束", location(source, start))
+		case c == '\\' && i+1 < len(source):
+			i++
+			switch source[i] {
+			case '"', '\\', '/':
+				sb.WriteByte(source[i])
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				var r rune
+				if i+4 >= len(source) {
+					return token{}, 0, fmt.Errorf("%s处的转义字符不正确", location(source, i-1))
+				}
+				if _, err := fmt.Sscanf(source[i+1:i+5], "%04x", &r); err != nil {
+					return token{}, 0, fmt.Errorf("%s处的转义字符不正确", location(source, i-1))
+				}
+				sb.WriteRune(r)
+				i += 4
+			default:
+				return token{}, 0, fmt.Errorf("%s处的转义字符不正确", location(source, i-1))
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return token{}, 0, fmt.Errorf("%s处的字符串没有结束", location(source, start))
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// location 将字节偏移转换为行列描述，行列从 1 开始
+func location(source string, pos int) string {
+	loc := locate(source, pos)
+	return fmt.Sprintf("第 %d 行第 %d 列", loc.Line, loc.Column)
+}
+
+// locate 计算字节偏移所在的行列
+func locate(source string, pos int) Location {
+	line, column := 1, 1
+	for i := 0; i < pos && i < len(source); i++ {
+		if source[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return Location{Line: line, Column: column}
+}
+
+// parser 递归下降解析器
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("%s处"+format, append([]interface{}{location(p.source, pos)}, args...)...)
+}
+
+// expect 读取指定的标点
+func (p *parser) expect(punct string) (token, error) {
+	t := p.next()
+	if !t.is(punct) {
+		return t, p.unexpected(t, fmt.Sprintf("%q", punct))
+	}
+	return t, nil
+}
+
+// expectName 读取名称
+func (p *parser) expectName() (token, error) {
+	t := p.next()
+	if t.kind != tokenName {
+		return t, p.unexpected(t, "名称")
+	}
+	return t, nil
+}
+
+func (p *parser) unexpected(t token, want string) error {
+	if t.kind == tokenEOF {
+		return p.errorf(t.pos, "查询意外结束，缺少%s", want)
+	}
+	return p.errorf(t.pos, "需要%s，实际为 %q", want, t.text)
+}
+
+// parseOperation 解析 query [Name] [VariableDefinitions] SelectionSet
+func (p *parser) parseOperation() (*operation, error) {
+	start := p.next()
+	op := &operation{pos: start.pos}
+	if p.peek().kind == tokenName {
+		op.name = p.next().text
+	}
+
+	if p.peek().is("(") {
+		p.next()
+		for !p.peek().is(")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, def)
+		}
+		p.next()
+	}
+
+	if p.peek().is("@") {
+		return nil, p.errorf(p.peek().pos, "操作上不支持指令")
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// parseVariableDefinition 解析 $name: Type [= default]
+func (p *parser) parseVariableDefinition() (*variableDef, error) {
+	start, err := p.expect("$")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseTypeRef()
+	if err != nil {
+		return nil, err
+	}
+
+	def := &variableDef{name: name.text, typ: typ, pos: start.pos}
+	if p.peek().is("=") {
+		p.next()
+		value, err := p.parseValue(true)
+		if err != nil {
+			return nil, err
+		}
+		def.defaultValue = value
+	}
+	return def, nil
+}
+
+// parseTypeRef 解析 Name、[Type] 和 Type!
+func (p *parser) parseTypeRef() (*typeRef, error) {
+	var typ *typeRef
+	if p.peek().is("[") {
+		p.next()
+		elem, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		typ = &typeRef{elem: elem}
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		typ = &typeRef{name: name.text}
+	}
+
+	if p.peek().is("!") {
+		p.next()
+		typ.nonNull = true
+	}
+	return typ, nil
+}
+
+// parseFragment 解析 fragment Name on Type SelectionSet
+func (p *parser) parseFragment() (*fragment, error) {
+	p.next()
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name.text == "on" {
+		return nil, p.errorf(name.pos, "片段名不能为 on")
+	}
+	if on := p.next(); on.kind != tokenName || on.text != "on" {
+		return nil, p.unexpected(on, "\"on\"")
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name.text, typeCondition: typeCondition.text, selections: selections, pos: name.pos}, nil
+}
+
+// parseSelectionSet 解析 { Selection... }
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if p.peek().is("}") {
+		return nil, p.errorf(p.peek().pos, "选择集不能为空")
+	}
+
+	var selections []selection
+	for !p.peek().is("}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	p.next()
+	return selections, nil
+}
+
+// parseSelection 解析字段、片段展开或内联片段
+func (p *parser) parseSelection() (selection, error) {
+	if p.peek().is("...") {
+		start := p.next()
+		if t := p.peek(); t.kind == tokenName && t.text != "on" {
+			p.next()
+			directives, err := p.parseDirectives()
+			if err != nil {
+				return nil, err
+			}
+			return &fragmentSpread{name: t.text, directives: directives, pos: start.pos}, nil
+		}
+
+		inline := &inlineFragment{pos: start.pos}
+		if t := p.peek(); t.kind == tokenName && t.text == "on" {
+			p.next()
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			inline.typeCondition = typeCondition.text
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		inline.directives = directives
+		inline.selections = selections
+		return inline, nil
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	field := &fieldNode{name: name.text, pos: name.pos}
+	if p.peek().is(":") {
+		p.next()
+		actual, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		field.alias = field.name
+		field.name = actual.text
+	}
+
+	if field.args, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+	if field.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek().is("{") {
+		if field.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+// parseArguments 解析 (name: value, ...)，没有括号时返回空
+func (p *parser) parseArguments(constant bool) ([]*argumentNode, error) {
+	if !p.peek().is("(") {
+		return nil, nil
+	}
+	p.next()
+	if p.peek().is(")") {
+		return nil, p.errorf(p.peek().pos, "参数列表不能为空")
+	}
+
+	var args []*argumentNode
+	seen := map[string]bool{}
+	for !p.peek().is(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if seen[name.text] {
+			return nil, p.errorf(name.pos, "参数 %s 重复", name.text)
+		}
+		seen[name.text] = true
+		if _, err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &argumentNode{name: name.text, value: value, pos: name.pos})
+	}
+	p.next()
+	return args, nil
+}
+
+// parseDirectives 解析 @name(args) ...
+func (p *parser) parseDirectives() ([]*directiveNode, error) {
+	var directives []*directiveNode
+	for p.peek().is("@") {
+		start := p.next()
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.parseArguments(false)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directiveNode{name: name.text, args: args, pos: start.pos})
+	}
+	return directives, nil
+}
+
+// parseValue 解析参数值，constant 为 true 时不允许引用变量
+func (p *parser) parseValue(constant bool) (*valueNode, error) {
+	t := p.next()
+	switch {
+	case t.is("$"):
+		if constant {
+			return nil, p.errorf(t.pos, "默认值中不能引用变量")
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		return &valueNode{kind: valueVariable, text: name.text, pos: t.pos}, nil
+	case t.kind == tokenInt:
+		return &valueNode{kind: valueInt, text: t.text, pos: t.pos}, nil
+	case t.kind == tokenFloat:
+		return &valueNode{kind: valueFloat, text: t.text, pos: t.pos}, nil
+	case t.kind == tokenString:
+		return &valueNode{kind: valueString, text: t.text, pos: t.pos}, nil
+	case t.kind == tokenName && (t.text == "true" || t.text == "false"):
+		return &valueNode{kind: valueBoolean, text: t.text, pos: t.pos}, nil
+	case t.kind == tokenName && t.text == "null":
+		return &valueNode{kind: valueNull, pos: t.pos}, nil
+	case t.kind == tokenName:
+		return &valueNode{kind: valueEnum, text: t.text, pos: t.pos}, nil
+	case t.is("[") || t.is("{"):
+		return nil, p.errorf(t.pos, "参数值只能是标量，不支持列表和对象")
+	default:
+		return nil, p.unexpected(t, "参数值")
+	}
+}
@@ -0,0 +1,372 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type 字段或参数的类型，为 *Scalar、*Object、*List 或 *NonNull
+type Type interface {
+	String() string
+}
+
+// Scalar 标量类型，ParseValue 为空的标量不能用作参数
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize 将解析函数返回的值转换为输出值，传入的值已去掉指针
+	Serialize func(value interface{}) (interface{}, error)
+	// ParseValue 将变量或字面值转换为参数值，数字统一以 float64 传入
+	ParseValue func(value interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object 对象类型，字段可以在创建后追加，以便类型之间相互引用
+type Object struct {
+	Name        string
+	Description string
+	fields      []*Field
+	byName      map[string]*Field
+}
+
+// NewObject 创建对象类型
+func NewObject(name, description string, fields ...*Field) *Object {
+	o := &Object{Name: name, Description: description, byName: map[string]*Field{}}
+	o.AddFields(fields...)
+	return o
+}
+
+// AddFields 追加字段，同名字段覆盖之前的定义
+func (o *Object) AddFields(fields ...*Field) {
+	for _, field := range fields {
+		if existing, ok := o.byName[field.Name]; ok {
+			for i, f := range o.fields {
+				if f == existing {
+					o.fields[i] = field
+				}
+			}
+		} else {
+			o.fields = append(o.fields, field)
+		}
+		o.byName[field.Name] = field
+	}
+}
+
+// Field 按名称查找字段
+func (o *Object) Field(name string) *Field {
+	return o.byName[name]
+}
+
+func (o *Object) String() string { return o.Name }
+
+// List 列表类型
+type List struct {
+	OfType Type
+}
+
+// NewList 创建列表类型
+func NewList(ofType Type) *List { return &List{OfType: ofType} }
+
+func (l *List) String() string { return "[" + l.OfType.String() + "]" }
+
+// NonNull 非空类型
+type NonNull struct {
+	OfType Type
+}
+
+// NewNonNull 创建非空类型
+func NewNonNull(ofType Type) *NonNull { return &NonNull{OfType: ofType} }
+
+func (n *NonNull) String() string { return n.OfType.String() + "!" }
+
+// Field 对象的字段
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	// Resolve 为空时按 json 标签读取结构体字段，或按键名读取 map
+	Resolve ResolveFunc
+}
+
+// arg 按名称查找参数定义
+func (f *Field) arg(name string) *Argument {
+	for _, arg := range f.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+// Argument 字段参数，只能是标量或非空标量
+type Argument struct {
+	Name         string
+	Description  string
+	Type         Type
+	DefaultValue interface{}
+}
+
+// ResolveParams 解析函数的参数
+type ResolveParams struct {
+	Context context.Context
+	// Source 父对象的值，Query 的字段为 nil
+	Source interface{}
+	// Args 已按类型转换的参数，未传且没有默认值的参数不在其中
+	Args map[string]interface{}
+}
+
+// ResolveFunc 字段解析函数，可以返回 Thunk 延迟取值
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// Thunk 延迟取值，同一层的字段全部解析完后才求值
+type Thunk func() (interface{}, error)
+
+// Schema 查询的类型系统
+type Schema struct {
+	query *Object
+}
+
+// NewSchema 以 Query 对象为入口创建 Schema
+func NewSchema(query *Object) *Schema {
+	return &Schema{query: query}
+}
+
+// namedType 去掉 List 和 NonNull 后的类型
+func namedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *List:
+			t = wrapped.OfType
+		case *NonNull:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// SDL 以 Schema 定义语言输出类型定义，供前端生成类型和查看文档
+func (s *Schema) SDL() string {
+	var objects []*Object
+	scalars := map[string]*Scalar{}
+	seen := map[string]bool{}
+
+	var visit func(o *Object)
+	visit = func(o *Object) {
+		if seen[o.Name] {
+			return
+		}
+		seen[o.Name] = true
+		objects = append(objects, o)
+		for _, field := range o.fields {
+			for _, arg := range field.Args {
+				if scalar, ok := namedType(arg.Type).(*Scalar); ok {
+					scalars[scalar.Name] = scalar
+				}
+			}
+			switch t := namedType(field.Type).(type) {
+			case *Scalar:
+				scalars[t.Name] = t
+			case *Object:
+				visit(t)
+			}
+		}
+	}
+	visit(s.query)
+
+	var sb strings.Builder
+	names := make([]string, 0, len(scalars))
+	for name := range scalars {
+		if !isBuiltinScalar(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeDescription(&sb, "", scalars[name].Description)
+		fmt.Fprintf(&sb, "scalar %s\n\n", name)
+	}
+
+	for i, o := range objects {
+		writeDescription(&sb, "", o.Description)
+		fmt.Fprintf(&sb, "type %s {\n", o.Name)
+		for _, field := range o.fields {
+			writeDescription(&sb, "  ", field.Description)
+			sb.WriteString("  " + field.Name)
+			if len(field.Args) > 0 {
+				args := make([]string, 0, len(field.Args))
+				for _, arg := range field.Args {
+					def := arg.Name + ": " + arg.Type.String()
+					if arg.DefaultValue != nil {
+						def += " = " + formatDefault(arg.DefaultValue)
+					}
+					args = append(args, def)
+				}
+				sb.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sb.WriteString(": " + field.Type.String() + "\n")
+		}
+		sb.WriteString("}\n")
+		if i < len(objects)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeDescription(sb *strings.Builder, indent, description string) {
+	if description != "" {
+		sb.WriteString(indent + strconv.Quote(description) + "\n")
+	}
+}
+
+func formatDefault(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+func isBuiltinScalar(name string) bool {
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+// 内置标量
+var (
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return v.Int(), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return v.Uint(), nil
+			}
+			return nil, fmt.Errorf("无法将 %T 转换为 Int", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			f, ok := value.(float64)
+			if !ok || f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+				return nil, fmt.Errorf("需要 32 位整数")
+			}
+			return int(f), nil
+		},
+	}
+
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			switch v.Kind() {
+			case reflect.Float32, reflect.Float64:
+				return v.Float(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(v.Int()), nil
+			}
+			return nil, fmt.Errorf("无法将 %T 转换为 Float", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			f, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("需要数字")
+			}
+			return f, nil
+		},
+	}
+
+	String = &Scalar{
+		Name: "String",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			if v.Kind() == reflect.String {
+				return v.String(), nil
+			}
+			return nil, fmt.Errorf("无法将 %T 转换为 String", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("需要字符串")
+			}
+			return s, nil
+		},
+	}
+
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			if v.Kind() == reflect.Bool {
+				return v.Bool(), nil
+			}
+			return nil, fmt.Errorf("无法将 %T 转换为 Boolean", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			b, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("需要布尔值")
+			}
+			return b, nil
+		},
+	}
+
+	ID = &Scalar{
+		Name: "ID",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			switch v.Kind() {
+			case reflect.String:
+				return v.String(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return strconv.FormatInt(v.Int(), 10), nil
+			}
+			return nil, fmt.Errorf("无法将 %T 转换为 ID", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			switch v := value.(type) {
+			case string:
+				return v, nil
+			case float64:
+				if v == math.Trunc(v) {
+					return strconv.FormatInt(int64(v), 10), nil
+				}
+			}
+			return nil, fmt.Errorf("需要字符串或整数")
+		},
+	}
+
+	// Time RFC 3339 格式的时间，只用于输出
+	Time = &Scalar{
+		Name:        "Time",
+		Description: "RFC 3339 格式的时间",
+		Serialize: func(value interface{}) (interface{}, error) {
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("无法将 %T 转换为 Time", value)
+			}
+			return t.Format(time.RFC3339Nano), nil
+		},
+	}
+
+	// JSON 原样输出的任意 JSON 值，用于标签、注解等结构不固定的字段，只用于输出
+	JSON = &Scalar{
+		Name:        "JSON",
+		Description: "任意 JSON 值",
+		Serialize: func(value interface{}) (interface{}, error) {
+			return value, nil
+		},
+	}
+)
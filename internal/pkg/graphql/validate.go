@@ -0,0 +1,253 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// validator 在执行前检查查询与 Schema 是否匹配，发现第一个错误即返回
+type validator struct {
+	schema *Schema
+	doc    *document
+	source string
+	op     *operation
+}
+
+// validate 检查操作的字段、参数、片段、变量和嵌套深度
+func (s *Schema) validate(source string, doc *document, op *operation) *Error {
+	v := &validator{schema: s, doc: doc, source: source, op: op}
+
+	seen := map[string]bool{}
+	for _, def := range op.variables {
+		if seen[def.name] {
+			return v.errorf(def.pos, "变量 $%s 重复定义", def.name)
+		}
+		seen[def.name] = true
+		scalar, err := inputScalar(def.typ)
+		if err != nil {
+			return v.errorf(def.pos, "变量 $%s 的类型 %s %s", def.name, def.typ, err)
+		}
+		if def.defaultValue != nil {
+			if _, err := literalValue(scalar, def.defaultValue); err != nil {
+				return v.errorf(def.defaultValue.pos, "变量 $%s 的默认值不正确：%s", def.name, err)
+			}
+		}
+	}
+
+	return v.selectionSet(s.query, op.selections, 1, nil, map[string]string{})
+}
+
+func (v *validator) errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{locate(v.source, pos)}}
+}
+
+// selectionSet 检查选择集，fragments 为正在展开的片段，用于发现循环引用，
+// keys 记录同一层结果键对应的字段名，片段展开后与所在选择集共用
+func (v *validator) selectionSet(parent *Object, selections []selection, depth int, fragments []string, keys map[string]string) *Error {
+	if depth > maxDepth {
+		return v.errorf(selectionPos(selections[0]), "查询嵌套不能超过%d层", maxDepth)
+	}
+
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if err := v.field(parent, sel, depth, keys); err != nil {
+				return err
+			}
+		case *fragmentSpread:
+			if err := v.directives(sel.directives); err != nil {
+				return err
+			}
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				return v.errorf(sel.pos, "片段 %s 不存在", sel.name)
+			}
+			for _, name := range fragments {
+				if name == sel.name {
+					return v.errorf(sel.pos, "片段 %s 循环引用", sel.name)
+				}
+			}
+			if frag.typeCondition != parent.Name {
+				return v.errorf(sel.pos, "片段 %s 的类型 %s 与 %s 不符", sel.name, frag.typeCondition, parent.Name)
+			}
+			if err := v.selectionSet(parent, frag.selections, depth, append(fragments, sel.name), keys); err != nil {
+				return err
+			}
+		case *inlineFragment:
+			if err := v.directives(sel.directives); err != nil {
+				return err
+			}
+			if sel.typeCondition != "" && sel.typeCondition != parent.Name {
+				return v.errorf(sel.pos, "内联片段的类型 %s 与 %s 不符", sel.typeCondition, parent.Name)
+			}
+			if err := v.selectionSet(parent, sel.selections, depth, fragments, keys); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// field 检查字段是否存在、参数是否正确以及子选择集
+func (v *validator) field(parent *Object, node *fieldNode, depth int, keys map[string]string) *Error {
+	if err := v.directives(node.directives); err != nil {
+		return err
+	}
+
+	key := node.responseKey()
+	if name, ok := keys[key]; ok && name != node.name {
+		return v.errorf(node.pos, "结果字段 %s 同时对应 %s 和 %s", key, name, node.name)
+	}
+	keys[key] = node.name
+
+	if node.name == "__typename" {
+		if len(node.args) > 0 || node.selections != nil {
+			return v.errorf(node.pos, "字段 __typename 不能有参数和子字段")
+		}
+		return nil
+	}
+
+	field := parent.Field(node.name)
+	if field == nil {
+		return v.errorf(node.pos, "类型 %s 没有字段 %s", parent.Name, node.name)
+	}
+	if err := v.arguments(field.Args, node.args, fmt.Sprintf("字段 %s.%s", parent.Name, node.name), node.pos); err != nil {
+		return err
+	}
+
+	object, isObject := namedType(field.Type).(*Object)
+	switch {
+	case isObject && node.selections == nil:
+		return v.errorf(node.pos, "字段 %s 的类型为 %s，必须选择子字段", node.name, field.Type)
+	case !isObject && node.selections != nil:
+		return v.errorf(node.pos, "字段 %s 的类型为 %s，不能选择子字段", node.name, field.Type)
+	case isObject:
+		return v.selectionSet(object, node.selections, depth+1, nil, map[string]string{})
+	}
+	return nil
+}
+
+// directives 只支持 @include(if:) 和 @skip(if:)
+func (v *validator) directives(directives []*directiveNode) *Error {
+	for _, directive := range directives {
+		if directive.name != "include" && directive.name != "skip" {
+			return v.errorf(directive.pos, "不支持指令 @%s", directive.name)
+		}
+		args := []*Argument{{Name: "if", Type: NewNonNull(Boolean)}}
+		if err := v.arguments(args, directive.args, "指令 @"+directive.name, directive.pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arguments 检查参数名、必填参数、字面值类型以及变量的定义和类型
+func (v *validator) arguments(defs []*Argument, args []*argumentNode, owner string, pos int) *Error {
+	for _, arg := range args {
+		var def *Argument
+		for _, d := range defs {
+			if d.Name == arg.name {
+				def = d
+			}
+		}
+		if def == nil {
+			return v.errorf(arg.pos, "%s没有参数 %s", owner, arg.name)
+		}
+
+		scalar := namedType(def.Type).(*Scalar)
+		_, required := def.Type.(*NonNull)
+		if arg.value.kind == valueVariable {
+			variable := v.variable(arg.value.text)
+			if variable == nil {
+				return v.errorf(arg.value.pos, "变量 $%s 没有定义", arg.value.text)
+			}
+			if variable.typ.name != scalar.Name ||
+				(required && !variable.typ.nonNull && variable.defaultValue == nil) {
+				return v.errorf(arg.value.pos, "变量 $%s 的类型 %s 不能用于 %s 类型的参数 %s",
+					arg.value.text, variable.typ, def.Type, arg.name)
+			}
+			continue
+		}
+
+		if arg.value.kind == valueNull && required {
+			return v.errorf(arg.value.pos, "%s的参数 %s 不能为 null", owner, arg.name)
+		}
+		if _, err := literalValue(scalar, arg.value); err != nil {
+			return v.errorf(arg.value.pos, "%s的参数 %s 不正确：%s", owner, arg.name, err)
+		}
+	}
+
+	for _, def := range defs {
+		if _, required := def.Type.(*NonNull); !required || def.DefaultValue != nil {
+			continue
+		}
+		found := false
+		for _, arg := range args {
+			found = found || arg.name == def.Name
+		}
+		if !found {
+			return v.errorf(pos, "%s缺少必填参数 %s", owner, def.Name)
+		}
+	}
+	return nil
+}
+
+func (v *validator) variable(name string) *variableDef {
+	for _, def := range v.op.variables {
+		if def.name == name {
+			return def
+		}
+	}
+	return nil
+}
+
+// inputScalar 变量类型只能是内置标量或非空内置标量
+func inputScalar(typ *typeRef) (*Scalar, error) {
+	if typ.elem != nil {
+		return nil, fmt.Errorf("不支持列表类型")
+	}
+	for _, scalar := range []*Scalar{Int, Float, String, Boolean, ID} {
+		if scalar.Name == typ.name {
+			return scalar, nil
+		}
+	}
+	return nil, fmt.Errorf("不是可用的参数类型")
+}
+
+// literalValue 将字面值转换为参数值，数字先转换为 float64 再交给标量处理
+func literalValue(scalar *Scalar, value *valueNode) (interface{}, error) {
+	var raw interface{}
+	switch value.kind {
+	case valueNull:
+		return nil, nil
+	case valueInt, valueFloat:
+		f, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("数字 %s 超出范围", value.text)
+		}
+		raw = f
+	case valueString:
+		raw = value.text
+	case valueBoolean:
+		raw = value.text == "true"
+	default:
+		return nil, fmt.Errorf("不能使用枚举值 %s", value.text)
+	}
+
+	if scalar.ParseValue == nil {
+		return nil, fmt.Errorf("类型 %s 不能用作参数", scalar.Name)
+	}
+	return scalar.ParseValue(raw)
+}
+
+func selectionPos(sel selection) int {
+	switch sel := sel.(type) {
+	case *fieldNode:
+		return sel.pos
+	case *fragmentSpread:
+		return sel.pos
+	case *inlineFragment:
+		return sel.pos
+	}
+	return 0
+}
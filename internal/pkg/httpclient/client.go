@@ -0,0 +1,204 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxDrainSize 重试前读取并丢弃的响应体最大字节数，读完响应体后连接才能复用
+const maxDrainSize = 64 << 10
+
+// Factory HTTP 客户端工厂，按策略和连接选项创建客户端，连接选项相同的客户端共用同一个连接池
+type Factory struct {
+	defaults        Policy
+	options         Options
+	channelPolicies map[string]Policy
+	channelOptions  map[string]Options
+
+	mu         sync.Mutex
+	transports map[Options]http.RoundTripper
+}
+
+// NewFactory 创建客户端工厂，defaults 中未配置的字段使用 DefaultPolicy，options 为全局连接选项，
+// channelPolicies 和 channelOptions 为按通知渠道覆盖的策略和连接选项。全局连接选项无效时返回错误
+func NewFactory(defaults Policy, options Options, channelPolicies map[string]Policy, channelOptions map[string]Options) (*Factory, error) {
+	f := &Factory{
+		defaults:        defaults.Merge(DefaultPolicy()),
+		options:         options,
+		channelPolicies: channelPolicies,
+		channelOptions:  channelOptions,
+		transports:      make(map[Options]http.RoundTripper),
+	}
+	if _, err := f.transport(Options{}); err != nil {
+		return nil, err
+	}
+	for channel, channelOptions := range channelOptions {
+		if _, err := f.transport(channelOptions); err != nil {
+			return nil, fmt.Errorf("通知渠道 %s 的连接选项无效: %w", channel, err)
+		}
+	}
+	return f, nil
+}
+
+// Resolve 按顺序合并策略，靠前的策略优先，最后使用工厂的默认策略。工厂为 nil 时使用 DefaultPolicy
+func (f *Factory) Resolve(policies ...Policy) Policy {
+	resolved := Policy{}
+	for _, policy := range policies {
+		resolved = resolved.Merge(policy)
+	}
+	if f == nil {
+		return resolved.Merge(DefaultPolicy())
+	}
+	return resolved.Merge(f.defaults)
+}
+
+// Client 创建使用全局连接选项、按合并后的策略超时和重试的客户端
+func (f *Factory) Client(policies ...Policy) *http.Client {
+	// 全局连接选项在创建工厂时已验证
+	client, _ := f.ClientWithOptions(Options{}, policies...)
+	return client
+}
+
+// ClientWithOptions 创建使用集成连接选项的客户端，集成未配置的选项沿用全局选项，选项无效时返回错误
+func (f *Factory) ClientWithOptions(options Options, policies ...Policy) (*http.Client, error) {
+	transport, err := f.transport(options)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: &retryTransport{base: transport, policy: f.Resolve(policies...)}}, nil
+}
+
+// Channel 创建通知渠道使用的客户端
+func (f *Factory) Channel(channel string) (*http.Client, error) {
+	if f == nil {
+		return f.Client(), nil
+	}
+	return f.ClientWithOptions(f.channelOptions[channel], f.channelPolicies[channel])
+}
+
+// TLSConfig 创建非 HTTP 协议连接使用的 TLS 配置，集成未配置的选项沿用全局选项，未配置证书相关选项时返回 nil
+func (f *Factory) TLSConfig(options Options) (*tls.Config, error) {
+	if f == nil {
+		return options.TLSConfig()
+	}
+	return options.merge(f.options).TLSConfig()
+}
+
+// ChannelTLSConfig 创建通知渠道使用的 TLS 配置，用于单独检查渠道地址的 TLS 握手，未配置证书相关选项时返回 nil
+func (f *Factory) ChannelTLSConfig(channel string) (*tls.Config, error) {
+	if f == nil {
+		return f.TLSConfig(Options{})
+	}
+	return f.TLSConfig(f.channelOptions[channel])
+}
+
+// transport 获取连接选项对应的 transport，相同选项复用同一个 transport。工厂为 nil 时使用 http.DefaultTransport
+func (f *Factory) transport(options Options) (http.RoundTripper, error) {
+	if f == nil {
+		if options == (Options{}) {
+			return http.DefaultTransport, nil
+		}
+		f = &Factory{transports: make(map[Options]http.RoundTripper)}
+	}
+
+	resolved := options.merge(f.options)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if transport, ok := f.transports[resolved]; ok {
+		return transport, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := resolved.apply(transport); err != nil {
+		return nil, err
+	}
+	f.transports[resolved] = transport
+	return transport, nil
+}
+
+// retryTransport 按策略为每次尝试设置超时，连接错误、超时和可重试的状态码按指数退避重试。
+// 请求体不可重放（未设置 GetBody）的请求不重试
+type retryTransport struct {
+	base   http.RoundTripper
+	policy Policy
+}
+
+// RoundTrip 执行请求
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := t.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if t.policy.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, t.policy.Timeout)
+		}
+		resp, err := t.base.RoundTrip(attemptReq.WithContext(attemptCtx))
+
+		retry := attempt < t.policy.MaxAttempts && ctx.Err() == nil && replayable(req) &&
+			(err != nil || t.policy.retryableStatus(resp.StatusCode))
+		if !retry {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// 超时覆盖读取响应体，关闭响应体时释放
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		if resp != nil {
+			_, _ = io.CopyN(io.Discard, resp.Body, maxDrainSize)
+			resp.Body.Close()
+		}
+		cancel()
+
+		timer := time.NewTimer(t.policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attemptRequest 返回第 attempt 次尝试的请求，重试时重新获取请求体
+func (t *retryTransport) attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+	return retryReq, nil
+}
+
+// replayable 判断请求体是否可以重放
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// cancelBody 关闭响应体时取消本次尝试的超时 context
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
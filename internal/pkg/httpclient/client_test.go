@@ -0,0 +1,162 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_MergeAndValidate(t *testing.T) {
+	merged := Policy{MaxAttempts: 5, RetryableStatusCodes: []int{}}.Merge(DefaultPolicy())
+	assert.Equal(t, 30*time.Second, merged.Timeout)
+	assert.Equal(t, 5, merged.MaxAttempts)
+	assert.Empty(t, merged.RetryableStatusCodes)
+	assert.NotNil(t, merged.RetryableStatusCodes)
+	require.NoError(t, merged.Validate())
+
+	assert.Error(t, Policy{MaxAttempts: 11}.Validate())
+	assert.Error(t, Policy{Jitter: 1.5}.Validate())
+	assert.Error(t, Policy{RetryableStatusCodes: []int{42}}.Validate())
+}
+
+func TestPolicy_Set(t *testing.T) {
+	var policy Policy
+	require.NoError(t, policy.Set("timeout", "5s"))
+	require.NoError(t, policy.Set("max_attempts", "3"))
+	require.NoError(t, policy.Set("jitter", "0.5"))
+	require.NoError(t, policy.Set("retryable_status_codes", "429|503"))
+
+	assert.Equal(t, Policy{Timeout: 5 * time.Second, MaxAttempts: 3, Jitter: 0.5, RetryableStatusCodes: []int{429, 503}}, policy)
+	assert.Error(t, policy.Set("unknown", "1"))
+	assert.Error(t, policy.Set("timeout", "soon"))
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(3))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		d := policy.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 100*time.Millisecond)
+	}
+}
+
+func TestFactory_RetriesRetryableStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, Options{}, nil, nil)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+
+	resp, err := factory.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestFactory_StopsAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(Policy{MaxAttempts: 2, InitialBackoff: time.Millisecond}, Options{}, nil, nil)
+	require.NoError(t, err)
+
+	resp, err := factory.Client().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// 不在重试列表中的状态码不重试
+	calls.Store(0)
+	resp, err = factory.Client(Policy{RetryableStatusCodes: []int{}}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestFactory_AttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(Policy{}, Options{}, map[string]Policy{
+		"webhook": {Timeout: 50 * time.Millisecond, MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}, nil)
+	require.NoError(t, err)
+	client, err := factory.Channel("webhook")
+	require.NoError(t, err)
+
+	// 使用渠道策略的单次超时，第一次尝试超时后由第二次尝试返回
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFactory_CallerCancelStopsRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	factory, err := NewFactory(Policy{MaxAttempts: 5, InitialBackoff: time.Second}, Options{}, nil, nil)
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = factory.Client().Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNilFactory(t *testing.T) {
+	var factory *Factory
+	assert.Equal(t, DefaultPolicy(), factory.Resolve())
+	assert.Equal(t, 5*time.Second, factory.Resolve(Policy{Timeout: 5 * time.Second}).Timeout)
+	client, err := factory.Channel("email")
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
@@ -0,0 +1,206 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options 出站连接选项：代理、服务端证书校验和客户端证书。集成未配置的选项沿用全局选项
+type Options struct {
+	// ProxyURL 代理地址，支持 http、https 和 socks5，可包含认证信息
+	ProxyURL string `json:"proxy_url,omitempty"`
+	// NoProxy 不经过代理的主机，逗号分隔，格式同 NO_PROXY 环境变量，只对全局代理生效
+	NoProxy string `json:"-"`
+	// DisableProxy 不使用代理直连，忽略全局代理和 HTTP_PROXY 等环境变量
+	DisableProxy bool `json:"disable_proxy,omitempty"`
+	// CACert 额外信任的 PEM 格式 CA 证书，与系统根证书一起用于校验服务端证书
+	CACert string `json:"ca_cert,omitempty"`
+	// CAFile 额外信任的 CA 证书文件路径，只能通过服务配置指定
+	CAFile string `json:"-"`
+	// InsecureSkipVerify 跳过服务端证书校验，仅用于测试环境
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+	// ClientCert 和 ClientKey 为双向 TLS 认证使用的 PEM 格式客户端证书和私钥，只对配置的集成生效
+	ClientCert string `json:"-"`
+	ClientKey  string `json:"-"`
+}
+
+// Validate 验证连接选项
+func (o Options) Validate() error {
+	if o.ProxyURL != "" {
+		if _, err := parseProxyURL(o.ProxyURL); err != nil {
+			return err
+		}
+	}
+	if o.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(o.CACert)) {
+		return errors.New("CA证书不是有效的PEM格式")
+	}
+	if _, err := o.clientCertificate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Set 按字段名设置连接选项，用于解析环境变量中的选项配置，字段名为 proxy_url、disable_proxy、ca_file、insecure_skip_verify
+func (o *Options) Set(field, value string) error {
+	value = strings.TrimSpace(value)
+	var err error
+	switch strings.TrimSpace(field) {
+	case "proxy_url":
+		o.ProxyURL = value
+		_, err = parseProxyURL(value)
+	case "disable_proxy":
+		o.DisableProxy, err = strconv.ParseBool(value)
+	case "ca_file":
+		o.CAFile = value
+	case "insecure_skip_verify":
+		o.InsecureSkipVerify, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("未知的连接选项字段: %s", field)
+	}
+	if err != nil {
+		return fmt.Errorf("连接选项字段 %s 的值无效: %w", field, err)
+	}
+	return nil
+}
+
+// merge 用全局选项补全集成未配置的选项，CA 证书叠加信任
+func (o Options) merge(global Options) Options {
+	if o.DisableProxy {
+		o.ProxyURL = ""
+	} else if o.ProxyURL == "" {
+		o.ProxyURL = global.ProxyURL
+		o.NoProxy = global.NoProxy
+		o.DisableProxy = global.DisableProxy
+	}
+	if o.CAFile == "" {
+		o.CAFile = global.CAFile
+	} else if global.CAFile != "" {
+		o.CAFile = global.CAFile + "," + o.CAFile
+	}
+	if o.CACert == "" {
+		o.CACert = global.CACert
+	} else if global.CACert != "" {
+		o.CACert = global.CACert + "\n" + o.CACert
+	}
+	o.InsecureSkipVerify = o.InsecureSkipVerify || global.InsecureSkipVerify
+	return o
+}
+
+// apply 按选项配置 transport 的代理和 TLS
+func (o Options) apply(transport *http.Transport) error {
+	switch {
+	case o.DisableProxy:
+		transport.Proxy = nil
+	case o.ProxyURL != "":
+		if _, err := parseProxyURL(o.ProxyURL); err != nil {
+			return err
+		}
+		proxy := (&httpproxy.Config{HTTPProxy: o.ProxyURL, HTTPSProxy: o.ProxyURL, NoProxy: o.NoProxy}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	default:
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	tlsConfig, err := o.TLSConfig()
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
+
+// TLSConfig 按选项创建 TLS 配置，用于非 HTTP 协议的连接。未配置证书相关选项时返回 nil，使用默认配置
+func (o Options) TLSConfig() (*tls.Config, error) {
+	if o.CACert == "" && o.CAFile == "" && !o.InsecureSkipVerify && o.ClientCert == "" && o.ClientKey == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: o.InsecureSkipVerify} // #nosec G402 -- 由配置显式开启
+	if o.CACert != "" || o.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if o.CACert != "" && !pool.AppendCertsFromPEM([]byte(o.CACert)) {
+			return nil, errors.New("CA证书不是有效的PEM格式")
+		}
+		for _, file := range strings.Split(o.CAFile, ",") {
+			if file == "" {
+				continue
+			}
+			pem, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("读取CA证书文件失败: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA证书文件 %s 不是有效的PEM格式", file)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+	cert, err := o.clientCertificate()
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+	return tlsConfig, nil
+}
+
+// ClientCertificateInfo 解析客户端证书，返回证书信息用于检查有效期。未配置客户端证书时返回 nil
+func (o Options) ClientCertificateInfo() (*x509.Certificate, error) {
+	cert, err := o.clientCertificate()
+	if err != nil || cert == nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(cert.Certificate[0])
+}
+
+// clientCertificate 解析客户端证书和私钥，证书和私钥必须同时配置
+func (o Options) clientCertificate() (*tls.Certificate, error) {
+	if o.ClientCert == "" && o.ClientKey == "" {
+		return nil, nil
+	}
+	if o.ClientCert == "" || o.ClientKey == "" {
+		return nil, errors.New("客户端证书和私钥必须同时配置")
+	}
+	cert, err := tls.X509KeyPair([]byte(o.ClientCert), []byte(o.ClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("客户端证书或私钥无效: %w", err)
+	}
+	return &cert, nil
+}
+
+// parseProxyURL 解析代理地址
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("无效的代理地址: %s", redactURL(raw))
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("不支持的代理协议: %s", u.Scheme)
+	}
+}
+
+// redactURL 隐藏地址中的密码
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid>"
+	}
+	return u.Redacted()
+}
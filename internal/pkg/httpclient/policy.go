@@ -0,0 +1,157 @@
+// Package httpclient 为数据源、通知渠道等外部集成创建带超时和重试策略的 HTTP 客户端。
+// 连接选项（代理、CA 证书）相同的客户端共用同一个连接池，超时和重试由每个集成的策略决定，
+// 未配置的字段沿用全局默认策略和全局连接选项
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAttemptsLimit 单次调用的最大尝试次数上限
+const maxAttemptsLimit = 10
+
+// Policy 超时和重试策略，零值字段表示沿用默认策略
+type Policy struct {
+	// Timeout 单次尝试的超时时间，包括读取响应体
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// MaxAttempts 最大尝试次数（含首次请求），1 表示不重试
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// InitialBackoff 首次重试前的等待时间，之后每次翻倍
+	InitialBackoff time.Duration `json:"initial_backoff,omitempty"`
+	// MaxBackoff 重试等待时间的上限
+	MaxBackoff time.Duration `json:"max_backoff,omitempty"`
+	// Jitter 等待时间随机减少的最大比例，取值 0 到 1，避免大量请求同时重试
+	Jitter float64 `json:"jitter,omitempty"`
+	// RetryableStatusCodes 需要重试的响应状态码，连接错误和超时总是重试
+	RetryableStatusCodes []int `json:"retryable_status_codes,omitempty"`
+}
+
+// DefaultPolicy 未配置策略时使用的默认策略：30 秒超时，不重试
+func DefaultPolicy() Policy {
+	return Policy{
+		Timeout:              30 * time.Second,
+		MaxAttempts:          1,
+		InitialBackoff:       500 * time.Millisecond,
+		MaxBackoff:           10 * time.Second,
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+	}
+}
+
+// Merge 用 fallback 补全未配置的字段
+func (p Policy) Merge(fallback Policy) Policy {
+	if p.Timeout == 0 {
+		p.Timeout = fallback.Timeout
+	}
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = fallback.MaxAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = fallback.InitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = fallback.MaxBackoff
+	}
+	if p.Jitter == 0 {
+		p.Jitter = fallback.Jitter
+	}
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = fallback.RetryableStatusCodes
+	}
+	return p
+}
+
+// Validate 验证策略
+func (p Policy) Validate() error {
+	if p.Timeout < 0 {
+		return errors.New("超时时间不能为负数")
+	}
+	if p.MaxAttempts < 0 || p.MaxAttempts > maxAttemptsLimit {
+		return fmt.Errorf("最大尝试次数必须在 0 到 %d 之间", maxAttemptsLimit)
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("重试等待时间不能为负数")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New("抖动比例必须在 0 到 1 之间")
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("无效的重试状态码: %d", code)
+		}
+	}
+	return nil
+}
+
+// Set 按字段名设置策略，用于解析环境变量中的策略配置。
+// 字段名与 JSON 字段相同，状态码以 | 分隔，如 retryable_status_codes=429|503
+func (p *Policy) Set(field, value string) error {
+	value = strings.TrimSpace(value)
+	var err error
+	switch strings.TrimSpace(field) {
+	case "timeout":
+		p.Timeout, err = time.ParseDuration(value)
+	case "max_attempts":
+		p.MaxAttempts, err = strconv.Atoi(value)
+	case "initial_backoff":
+		p.InitialBackoff, err = time.ParseDuration(value)
+	case "max_backoff":
+		p.MaxBackoff, err = time.ParseDuration(value)
+	case "jitter":
+		p.Jitter, err = strconv.ParseFloat(value, 64)
+	case "retryable_status_codes":
+		p.RetryableStatusCodes, err = ParseStatusCodes(value, "|")
+	default:
+		return fmt.Errorf("未知的重试策略字段: %s", field)
+	}
+	if err != nil {
+		return fmt.Errorf("重试策略字段 %s 的值 %q 无效: %w", field, value, err)
+	}
+	return nil
+}
+
+// ParseStatusCodes 解析以 sep 分隔的状态码列表，空字符串返回空列表（不重试任何状态码）
+func ParseStatusCodes(value, sep string) ([]int, error) {
+	codes := []int{}
+	for _, item := range strings.Split(value, sep) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		code, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// retryableStatus 判断状态码是否需要重试
+func (p Policy) retryableStatus(code int) bool {
+	for _, retryable := range p.RetryableStatusCodes {
+		if code == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 第 retry 次重试（从 1 开始）前的等待时间
+func (p Policy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
@@ -0,0 +1,151 @@
+// Package i18n 通知消息模板和枚举显示名的多语言支持
+//
+// 每种语言对应一个消息目录，键为消息标识，值为 text/template 模板。枚举显示名使用
+// enum.<枚举名>.<取值> 作为键，如 enum.alert_severity.critical。请求的语言没有对应
+// 消息时回退到默认语言，默认语言也没有时返回消息标识本身。
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// enumPrefix 枚举显示名的键前缀
+const enumPrefix = "enum."
+
+// Catalog 一种语言的消息目录，键为消息标识，值为消息模板
+type Catalog map[string]string
+
+// Bundle 所有语言的消息目录，模板在加载时解析
+type Bundle struct {
+	fallback  apierror.Locale
+	catalogs  map[apierror.Locale]Catalog
+	templates map[apierror.Locale]map[string]*template.Template
+}
+
+// NewBundle 依次从 loaders 加载消息目录，后加载的消息覆盖先加载的同名消息
+func NewBundle(loaders ...Loader) (*Bundle, error) {
+	b := &Bundle{
+		fallback:  apierror.DefaultLocale,
+		catalogs:  make(map[apierror.Locale]Catalog),
+		templates: make(map[apierror.Locale]map[string]*template.Template),
+	}
+
+	for _, loader := range loaders {
+		catalogs, err := loader.Load()
+		if err != nil {
+			return nil, err
+		}
+		for locale, catalog := range catalogs {
+			if err := b.add(locale, catalog); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, ok := b.catalogs[b.fallback]; !ok {
+		return nil, fmt.Errorf("缺少默认语言 %s 的消息目录", b.fallback)
+	}
+	return b, nil
+}
+
+// add 合并一种语言的消息目录并解析模板
+func (b *Bundle) add(locale apierror.Locale, catalog Catalog) error {
+	if b.catalogs[locale] == nil {
+		b.catalogs[locale] = make(Catalog)
+		b.templates[locale] = make(map[string]*template.Template)
+	}
+	for key, text := range catalog {
+		tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return fmt.Errorf("解析 %s 消息 %s 失败: %w", locale, key, err)
+		}
+		b.catalogs[locale][key] = text
+		b.templates[locale][key] = tmpl
+	}
+	return nil
+}
+
+// Locales 返回已加载的语言，按名称排序
+func (b *Bundle) Locales() []apierror.Locale {
+	locales := make([]apierror.Locale, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Match 将语言标签匹配到已加载的语言，不区分大小写，没有完全相同的语言时按主语言标签匹配（en 匹配 en-US）
+func (b *Bundle) Match(tag string) (apierror.Locale, bool) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", false
+	}
+	locales := b.Locales()
+	for _, locale := range locales {
+		if strings.EqualFold(string(locale), tag) {
+			return locale, true
+		}
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	for _, locale := range locales {
+		localePrimary, _, _ := strings.Cut(string(locale), "-")
+		if strings.EqualFold(localePrimary, primary) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Message 使用 data 渲染消息模板，依次查找 locale 和默认语言，都没有时返回 key
+func (b *Bundle) Message(locale apierror.Locale, key string, data interface{}) string {
+	tmpl, ok := b.templates[locale][key]
+	if !ok {
+		if tmpl, ok = b.templates[b.fallback][key]; !ok {
+			return key
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return key
+	}
+	return buf.String()
+}
+
+// EnumName 返回枚举取值的显示名，没有对应消息时返回取值本身
+func (b *Bundle) EnumName(locale apierror.Locale, enum, value string) string {
+	key := enumPrefix + enum + "." + value
+	if name := b.Message(locale, key, nil); name != key {
+		return name
+	}
+	return value
+}
+
+// Enums 返回所有枚举的显示名，按枚举名和取值组织，locale 缺少的显示名使用默认语言
+func (b *Bundle) Enums(locale apierror.Locale) map[string]map[string]string {
+	enums := make(map[string]map[string]string)
+	for _, catalog := range []Catalog{b.catalogs[b.fallback], b.catalogs[locale]} {
+		for key, text := range catalog {
+			rest, ok := strings.CutPrefix(key, enumPrefix)
+			if !ok {
+				continue
+			}
+			enum, value, ok := strings.Cut(rest, ".")
+			if !ok {
+				continue
+			}
+			if enums[enum] == nil {
+				enums[enum] = make(map[string]string)
+			}
+			enums[enum][value] = text
+		}
+	}
+	return enums
+}
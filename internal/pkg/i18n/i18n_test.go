@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/pkg/apierror"
+)
+
+func TestBundledCatalogsHaveSameKeys(t *testing.T) {
+	b, err := NewBundle(BundledLoader())
+	require.NoError(t, err)
+	assert.Equal(t, []apierror.Locale{apierror.LocaleEnUS, apierror.LocaleZhCN}, b.Locales())
+
+	for key := range b.catalogs[apierror.LocaleZhCN] {
+		assert.Contains(t, b.catalogs[apierror.LocaleEnUS], key)
+	}
+	assert.Equal(t, len(b.catalogs[apierror.LocaleZhCN]), len(b.catalogs[apierror.LocaleEnUS]))
+}
+
+func TestBundle_Message(t *testing.T) {
+	b, err := NewBundle(BundledLoader())
+	require.NoError(t, err)
+
+	data := map[string]interface{}{"Title": "Redis 连接池"}
+	assert.Equal(t, "知识库文章《Redis 连接池》即将过期", b.Message(apierror.LocaleZhCN, "notification.knowledge_expiry.subject", data))
+	assert.Equal(t, `Knowledge article "Redis 连接池" is about to expire`, b.Message(apierror.LocaleEnUS, "notification.knowledge_expiry.subject", data))
+	assert.Equal(t, "unknown.key", b.Message(apierror.LocaleEnUS, "unknown.key", nil))
+
+	assert.Equal(t, "Critical", b.EnumName(apierror.LocaleEnUS, "alert_severity", "critical"))
+	assert.Equal(t, "custom", b.EnumName(apierror.LocaleEnUS, "alert_severity", "custom"))
+	assert.Equal(t, "处理中", b.Enums(apierror.LocaleZhCN)["ticket_status"]["in_progress"])
+}
+
+func TestDirLoader_OverridesAndAddsLocales(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ja-JP.json"), []byte(`{"enum.alert_severity.critical": "重大"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en-US.json"), []byte(`{"enum.alert_severity.low": "Minor"}`), 0o644))
+
+	b, err := NewBundle(BundledLoader(), DirLoader(dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Minor", b.EnumName(apierror.LocaleEnUS, "alert_severity", "low"))
+	assert.Equal(t, "High", b.EnumName(apierror.LocaleEnUS, "alert_severity", "high"))
+
+	// 新增语言缺少的消息回退到默认语言
+	ja, ok := b.Match("ja")
+	require.True(t, ok)
+	assert.Equal(t, "重大", b.EnumName(ja, "alert_severity", "critical"))
+	assert.Equal(t, "高", b.EnumName(ja, "alert_severity", "high"))
+
+	_, ok = b.Match("fr-FR")
+	assert.False(t, ok)
+	locale, ok := b.Match("EN")
+	require.True(t, ok)
+	assert.Equal(t, apierror.LocaleEnUS, locale)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en-US.json"), []byte(`{"bad": "{{.Title"}`), 0o644))
+	_, err = NewBundle(BundledLoader(), DirLoader(dir))
+	assert.Error(t, err)
+}
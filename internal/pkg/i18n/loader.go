@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// bundled 内置的 zh-CN 和 en-US 消息目录
+//
+//go:embed catalogs/*.json
+var bundled embed.FS
+
+// Loader 消息目录加载器，返回按语言组织的消息目录
+type Loader interface {
+	Load() (map[apierror.Locale]Catalog, error)
+}
+
+// LoaderFunc 将函数适配为 Loader
+type LoaderFunc func() (map[apierror.Locale]Catalog, error)
+
+// Load 调用函数加载消息目录
+func (f LoaderFunc) Load() (map[apierror.Locale]Catalog, error) {
+	return f()
+}
+
+// BundledLoader 加载编译进二进制的内置消息目录
+func BundledLoader() Loader {
+	return LoaderFunc(func() (map[apierror.Locale]Catalog, error) {
+		return loadFS(bundled, "catalogs")
+	})
+}
+
+// DirLoader 加载目录中的 <语言>.json 消息目录，如 ja-JP.json，用于覆盖内置消息或增加新语言。
+// dir 为空时不加载任何消息
+func DirLoader(dir string) Loader {
+	return LoaderFunc(func() (map[apierror.Locale]Catalog, error) {
+		if dir == "" {
+			return nil, nil
+		}
+		return loadFS(os.DirFS(dir), ".")
+	})
+}
+
+// loadFS 加载文件系统目录中的所有 JSON 消息目录，文件名（不含扩展名）为语言
+func loadFS(fsys fs.FS, dir string) (map[apierror.Locale]Catalog, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取消息目录失败: %w", err)
+	}
+
+	catalogs := make(map[apierror.Locale]Catalog)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("读取消息目录 %s 失败: %w", name, err)
+		}
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("解析消息目录 %s 失败: %w", name, err)
+		}
+		catalogs[apierror.Locale(strings.TrimSuffix(name, ".json"))] = catalog
+	}
+
+	return catalogs, nil
+}
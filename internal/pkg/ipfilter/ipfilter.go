@@ -0,0 +1,94 @@
+// Package ipfilter 按 CIDR 白名单和黑名单过滤来源IP
+package ipfilter
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Filter 来源IP过滤器，黑名单优先于白名单，白名单为空时允许所有未被拒绝的地址
+type Filter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// New 根据白名单和黑名单创建过滤器，条目可以是 CIDR 或单个IP
+func New(allow, deny []string) (*Filter, error) {
+	allowNets, err := ParseCIDRs(allow)
+	if err != nil {
+		return nil, fmt.Errorf("解析IP白名单失败: %w", err)
+	}
+	denyNets, err := ParseCIDRs(deny)
+	if err != nil {
+		return nil, fmt.Errorf("解析IP黑名单失败: %w", err)
+	}
+
+	return &Filter{allow: allowNets, deny: denyNets}, nil
+}
+
+// Empty 判断过滤器是否未配置任何规则
+func (f *Filter) Empty() bool {
+	return f == nil || (len(f.allow) == 0 && len(f.deny) == 0)
+}
+
+// Allowed 判断IP是否允许访问，无法解析的地址一律拒绝
+func (f *Filter) Allowed(ip string) bool {
+	if f.Empty() {
+		return true
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if contains(f.deny, parsed) {
+		return false
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	return contains(f.allow, parsed)
+}
+
+// ParseCIDRs 解析 CIDR 列表，单个IP视为 /32 或 /128
+func ParseCIDRs(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("无效的IP地址: %s", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("无效的CIDR: %s", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package ipfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Allowed(t *testing.T) {
+	filter, err := New([]string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"}, []string{"10.0.0.5"})
+	require.NoError(t, err)
+
+	assert.True(t, filter.Allowed("10.1.2.3"))
+	assert.True(t, filter.Allowed("192.168.1.10"))
+	assert.True(t, filter.Allowed("2001:db8::1"))
+	assert.False(t, filter.Allowed("10.0.0.5"), "黑名单优先")
+	assert.False(t, filter.Allowed("192.168.1.11"))
+	assert.False(t, filter.Allowed("not-an-ip"))
+
+	denyOnly, err := New(nil, []string{"203.0.113.0/24"})
+	require.NoError(t, err)
+	assert.True(t, denyOnly.Allowed("198.51.100.1"))
+	assert.False(t, denyOnly.Allowed("203.0.113.7"))
+
+	var empty *Filter
+	assert.True(t, empty.Allowed("203.0.113.7"))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New([]string{"10.0.0.0/33"}, nil)
+	assert.Error(t, err)
+
+	_, err = New(nil, []string{"bad"})
+	assert.Error(t, err)
+}
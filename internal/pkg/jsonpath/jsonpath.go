@@ -0,0 +1,206 @@
+// Package jsonpath 实现 JSONPath 的常用子集，用于从任意结构的 JSON 载荷中取值
+//
+// 支持的语法：
+//
+//	$            整个文档
+//	@            当前节点，如告警列表中的一条告警
+//	.name        对象字段，字段名包含特殊字符时使用 ['name'] 或 ["name"]
+//	[0] [-1]     数组下标，负数从末尾开始
+//	[*] .*       数组的全部元素或对象的全部字段值
+//
+// 不支持递归下降（..）、过滤表达式和切片。
+// 文档应使用 encoding/json 解析为 interface{}，数字建议使用 json.Number 以保留精度。
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stepKind 路径步骤类型
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+// step 路径中的一步
+type step struct {
+	kind  stepKind
+	field string
+	index int
+}
+
+// Path 编译后的路径
+type Path struct {
+	expr    string
+	current bool
+	steps   []step
+}
+
+// Compile 编译路径表达式
+func Compile(expr string) (*Path, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("路径不能为空")
+	}
+
+	p := &Path{expr: expr}
+	switch expr[0] {
+	case '$':
+	case '@':
+		p.current = true
+	default:
+		return nil, fmt.Errorf("路径 %s 必须以 $ 或 @ 开头", expr)
+	}
+
+	for i := 1; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			i++
+			if i < len(expr) && expr[i] == '.' {
+				return nil, fmt.Errorf("路径 %s 不支持递归下降", expr)
+			}
+			if i < len(expr) && expr[i] == '*' {
+				p.steps = append(p.steps, step{kind: stepWildcard})
+				i++
+				continue
+			}
+			start := i
+			for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+				if expr[i] == ' ' || expr[i] == '\t' {
+					return nil, fmt.Errorf("路径 %s 的字段名包含空白，请使用 ['name']", expr)
+				}
+				i++
+			}
+			if start == i {
+				return nil, fmt.Errorf("路径 %s 第 %d 个字符处缺少字段名", expr, start+1)
+			}
+			p.steps = append(p.steps, step{kind: stepField, field: expr[start:i]})
+		case '[':
+			end, s, err := parseBracket(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			p.steps = append(p.steps, s)
+			i = end
+		default:
+			return nil, fmt.Errorf("路径 %s 第 %d 个字符无效", expr, i+1)
+		}
+	}
+	return p, nil
+}
+
+// parseBracket 解析从 start 处的 [ 开始的一步，返回 ] 之后的位置
+func parseBracket(expr string, start int) (int, step, error) {
+	i := start + 1
+	if i >= len(expr) {
+		return 0, step{}, fmt.Errorf("路径 %s 缺少 ]", expr)
+	}
+
+	switch quote := expr[i]; quote {
+	case '\'', '"':
+		var field strings.Builder
+		for i++; i < len(expr) && expr[i] != quote; i++ {
+			if expr[i] == '\\' && i+1 < len(expr) {
+				i++
+			}
+			field.WriteByte(expr[i])
+		}
+		if i+1 >= len(expr) || expr[i+1] != ']' {
+			return 0, step{}, fmt.Errorf("路径 %s 的字段名缺少结束引号或 ]", expr)
+		}
+		return i + 2, step{kind: stepField, field: field.String()}, nil
+	case '*':
+		if i+1 >= len(expr) || expr[i+1] != ']' {
+			return 0, step{}, fmt.Errorf("路径 %s 缺少 ]", expr)
+		}
+		return i + 2, step{kind: stepWildcard}, nil
+	}
+
+	end := strings.IndexByte(expr[i:], ']')
+	if end < 0 {
+		return 0, step{}, fmt.Errorf("路径 %s 缺少 ]", expr)
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(expr[i : i+end]))
+	if err != nil {
+		return 0, step{}, fmt.Errorf("路径 %s 的下标 %q 无效", expr, expr[i:i+end])
+	}
+	return i + end + 1, step{kind: stepIndex, index: index}, nil
+}
+
+// String 返回路径表达式
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Select 返回匹配的全部节点，root 为整个文档，current 为 @ 指向的节点
+func (p *Path) Select(root, current interface{}) []interface{} {
+	nodes := []interface{}{root}
+	if p.current {
+		nodes[0] = current
+	}
+
+	for _, s := range p.steps {
+		next := make([]interface{}, 0, len(nodes))
+		for _, node := range nodes {
+			next = s.apply(node, next)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// Get 返回第一个匹配的节点，没有匹配时 ok 为 false
+func (p *Path) Get(root, current interface{}) (value interface{}, ok bool) {
+	nodes := p.Select(root, current)
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return nodes[0], true
+}
+
+// apply 对单个节点执行一步，结果追加到 out
+func (s step) apply(node interface{}, out []interface{}) []interface{} {
+	switch s.kind {
+	case stepField:
+		if object, ok := node.(map[string]interface{}); ok {
+			if value, ok := object[s.field]; ok {
+				out = append(out, value)
+			}
+		}
+	case stepIndex:
+		if array, ok := node.([]interface{}); ok {
+			index := s.index
+			if index < 0 {
+				index += len(array)
+			}
+			if index >= 0 && index < len(array) {
+				out = append(out, array[index])
+			}
+		}
+	case stepWildcard:
+		switch value := node.(type) {
+		case []interface{}:
+			out = append(out, value...)
+		case map[string]interface{}:
+			// 按字段名排序，保证结果稳定
+			keys := make([]string, 0, len(value))
+			for key := range value {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				out = append(out, value[key])
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+	"receiver": "ops",
+	"alerts": [
+		{"status": "firing", "labels": {"host": "web-1", "app.kubernetes.io/name": "api"}, "value": 95.5},
+		{"status": "resolved", "labels": {"host": "web-2"}}
+	]
+}`
+
+func TestPath_Select(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(testDocument), &doc))
+	first := doc.(map[string]interface{})["alerts"].([]interface{})[0]
+
+	tests := []struct {
+		expr string
+		want []interface{}
+	}{
+		{"$.receiver", []interface{}{"ops"}},
+		{"$.alerts[*].status", []interface{}{"firing", "resolved"}},
+		{"$.alerts[-1].labels.host", []interface{}{"web-2"}},
+		{"$['alerts'][0]['labels'][\"app.kubernetes.io/name\"]", []interface{}{"api"}},
+		{"@.value", []interface{}{95.5}},
+		{"@.labels.*", []interface{}{"api", "web-1"}},
+		{"$.alerts[5]", nil},
+		{"$.missing.field", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := Compile(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Select(doc, first))
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "alerts", "$..host", "$.", "$.alerts[", "$.alerts[x]", "$['host", "$.a b"} {
+		_, err := Compile(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,168 @@
+package knowledgeio
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// 压缩包限制，防止恶意压缩包耗尽内存
+const (
+	MaxBundleFiles    = 2000
+	MaxBundleFileSize = 5 << 20 // 单个文件 5MB
+)
+
+// ReadMarkdownBundle 读取 Markdown 压缩包
+// 无法解析的文件记录在 FileError 中，不影响其他文件
+func ReadMarkdownBundle(data []byte) ([]*Document, []*FileError, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+
+	var docs []*Document
+	var fileErrors []*FileError
+	count := 0
+	for _, file := range reader.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if file.FileInfo().IsDir() || isHiddenPath(name) || !isMarkdownFile(name) {
+			continue
+		}
+
+		count++
+		if count > MaxBundleFiles {
+			return nil, nil, fmt.Errorf("压缩包文件数量超过限制 %d", MaxBundleFiles)
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			fileErrors = append(fileErrors, &FileError{Path: name, Err: err})
+			continue
+		}
+
+		doc, err := ParseMarkdown(name, content)
+		if err != nil {
+			fileErrors = append(fileErrors, &FileError{Path: name, Err: err})
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, fileErrors, nil
+}
+
+// WriteMarkdownBundle 将文档写入 Markdown 压缩包，按分类分目录存放
+func WriteMarkdownBundle(w io.Writer, docs []*Document) error {
+	writer := zip.NewWriter(w)
+	used := make(map[string]int)
+
+	for _, doc := range docs {
+		name := SafeFileName(doc.Slug)
+		if doc.Slug == "" {
+			name = SafeFileName(doc.Title)
+		}
+		dir := ""
+		if doc.Category != "" {
+			dir = SafeFileName(doc.Category) + "/"
+		}
+
+		// 同名文件追加序号
+		filePath := dir + name + ".md"
+		if n := used[filePath]; n > 0 {
+			used[filePath] = n + 1
+			filePath = fmt.Sprintf("%s%s-%d.md", dir, name, n+1)
+		} else {
+			used[filePath] = 1
+		}
+
+		content, err := FormatMarkdownDocument(doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", doc.Title, err)
+		}
+
+		entry, err := writer.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("写入压缩包失败: %w", err)
+		}
+		if _, err := entry.Write(content); err != nil {
+			return fmt.Errorf("写入压缩包失败: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("写入压缩包失败: %w", err)
+	}
+
+	return nil
+}
+
+// SafeFileName 将标题转换为可用作文件名的字符串
+func SafeFileName(name string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), r < 0x20, r == ' ', r == '\t':
+			if !lastDash {
+				b.WriteRune('-')
+				lastDash = true
+			}
+		default:
+			b.WriteRune(r)
+			lastDash = false
+		}
+	}
+
+	result := strings.Trim(b.String(), "-.")
+	if result == "" {
+		return "untitled"
+	}
+	return result
+}
+
+// readZipFile 读取压缩包内文件，超过大小限制时报错
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > MaxBundleFileSize {
+		return nil, fmt.Errorf("文件大小超过限制 %d 字节", MaxBundleFileSize)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer rc.Close()
+
+	// 声明的大小可能被伪造，读取时再次限制
+	content, err := io.ReadAll(io.LimitReader(rc, MaxBundleFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	if len(content) > MaxBundleFileSize {
+		return nil, fmt.Errorf("文件大小超过限制 %d 字节", MaxBundleFileSize)
+	}
+
+	return content, nil
+}
+
+// isMarkdownFile 是否为 Markdown 文件
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	default:
+		return false
+	}
+}
+
+// isHiddenPath 是否为隐藏文件或系统生成的目录
+func isHiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
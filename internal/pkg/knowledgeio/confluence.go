@@ -0,0 +1,194 @@
+package knowledgeio
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// confluenceEntitiesFile Confluence 空间导出压缩包中的实体文件
+const confluenceEntitiesFile = "entities.xml"
+
+// MaxConfluenceEntitiesSize entities.xml 的最大大小
+const MaxConfluenceEntitiesSize = 200 << 20
+
+// confluenceObject entities.xml 中的 Hibernate 实体
+type confluenceObject struct {
+	Class      string               `xml:"class,attr"`
+	ID         string               `xml:"id"`
+	Properties []confluenceProperty `xml:"property"`
+}
+
+// confluenceProperty 实体属性，引用其他实体时只包含 id
+type confluenceProperty struct {
+	Name  string `xml:"name,attr"`
+	Class string `xml:"class,attr"`
+	ID    string `xml:"id"`
+	Value string `xml:",chardata"`
+}
+
+// property 获取属性值
+func (o *confluenceObject) property(name string) (string, bool) {
+	for _, p := range o.Properties {
+		if p.Name == name {
+			if p.ID != "" {
+				return strings.TrimSpace(p.ID), true
+			}
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// ReadConfluenceExport 读取 Confluence 空间导出
+// 支持完整的导出压缩包和单独的 entities.xml，只导入当前版本的页面，
+// 空间名称作为分类，页面标签作为标签，正文保留 Confluence 存储格式（XHTML）
+func ReadConfluenceExport(data []byte) ([]*Document, error) {
+	if bytes.HasPrefix(data, []byte("PK")) {
+		entities, err := readConfluenceEntities(data)
+		if err != nil {
+			return nil, err
+		}
+		data = entities
+	}
+
+	objects, err := decodeConfluenceObjects(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*confluenceObject)
+	var pages []*confluenceObject
+	bodies := make(map[string]string)
+	labels := make(map[string]string)
+	var labellings []*confluenceObject
+	for _, obj := range objects {
+		byID[obj.Class+":"+obj.ID] = obj
+		switch obj.Class {
+		case "Page":
+			pages = append(pages, obj)
+		case "BodyContent":
+			if pageID, ok := obj.property("content"); ok {
+				body, _ := obj.property("body")
+				bodies[pageID] = body
+			}
+		case "Label":
+			if name, ok := obj.property("name"); ok {
+				labels[obj.ID] = strings.TrimSpace(name)
+			}
+		case "Labelling":
+			labellings = append(labellings, obj)
+		}
+	}
+
+	pageLabels := make(map[string][]string)
+	for _, l := range labellings {
+		pageID, _ := l.property("content")
+		labelID, _ := l.property("label")
+		if name := labels[labelID]; name != "" {
+			pageLabels[pageID] = append(pageLabels[pageID], name)
+		}
+	}
+
+	var docs []*Document
+	for _, page := range pages {
+		// 历史版本带有 originalVersion，草稿和已删除页面状态不是 current
+		if _, ok := page.property("originalVersion"); ok {
+			continue
+		}
+		if status, ok := page.property("contentStatus"); ok && strings.TrimSpace(status) != "current" {
+			continue
+		}
+
+		title, _ := page.property("title")
+		body := bodies[page.ID]
+		if strings.TrimSpace(title) == "" || strings.TrimSpace(body) == "" {
+			continue
+		}
+
+		category := ""
+		if spaceID, ok := page.property("space"); ok {
+			if space := byID["Space:"+spaceID]; space != nil {
+				category, _ = space.property("name")
+			}
+		}
+
+		tags := pageLabels[page.ID]
+		sort.Strings(tags)
+
+		docs = append(docs, &Document{
+			Path:     "confluence/page/" + page.ID,
+			Title:    strings.TrimSpace(title),
+			Category: strings.TrimSpace(category),
+			Tags:     tags,
+			Format:   FormatHTML,
+			Content:  body,
+		})
+	}
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Title < docs[j].Title })
+	return docs, nil
+}
+
+// decodeConfluenceObjects 流式解析 entities.xml 中的所有实体
+func decodeConfluenceObjects(r io.Reader) ([]*confluenceObject, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	var objects []*confluenceObject
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 Confluence XML 失败: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "object" {
+			continue
+		}
+
+		var obj confluenceObject
+		if err := decoder.DecodeElement(&obj, &start); err != nil {
+			return nil, fmt.Errorf("解析 Confluence 实体失败: %w", err)
+		}
+		obj.ID = strings.TrimSpace(obj.ID)
+		objects = append(objects, &obj)
+	}
+
+	return objects, nil
+}
+
+// readConfluenceEntities 从导出压缩包中读取 entities.xml
+func readConfluenceEntities(data []byte) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if path.Base(file.Name) != confluenceEntitiesFile {
+			continue
+		}
+		if file.UncompressedSize64 > MaxConfluenceEntitiesSize {
+			return nil, fmt.Errorf("%s 大小超过限制", confluenceEntitiesFile)
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("打开 %s 失败: %w", confluenceEntitiesFile, err)
+		}
+		defer rc.Close()
+
+		return io.ReadAll(io.LimitReader(rc, MaxConfluenceEntitiesSize))
+	}
+
+	return nil, fmt.Errorf("压缩包中未找到 %s", confluenceEntitiesFile)
+}
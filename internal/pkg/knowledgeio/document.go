@@ -0,0 +1,193 @@
+// Package knowledgeio 知识库文章的导入导出格式
+//
+// 支持带 front-matter 的 Markdown 压缩包（导入和导出）以及 Confluence 空间 XML 导出（仅导入），
+// 解析结果统一为 Document，由上层服务负责写入数据库。
+package knowledgeio
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 文档内容格式
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Document 导入导出的知识文章
+type Document struct {
+	Path     string   // 在压缩包中的路径，导入时用于错误定位
+	Title    string   // 标题
+	Slug     string   // 短链接标识
+	Category string   // 分类名称
+	Tags     []string // 标签
+	Type     string   // 知识类型
+	Language string   // 语言
+	Summary  string   // 摘要
+	Format   string   // 内容格式
+	Content  string   // 正文
+}
+
+// FileError 单个文件的解析错误
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error 实现 error 接口
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// frontMatter Markdown 文件头部的 YAML 元数据
+type frontMatter struct {
+	Title    string     `yaml:"title,omitempty"`
+	Slug     string     `yaml:"slug,omitempty"`
+	Category string     `yaml:"category,omitempty"`
+	Tags     stringList `yaml:"tags,omitempty"`
+	Type     string     `yaml:"type,omitempty"`
+	Language string     `yaml:"language,omitempty"`
+	Summary  string     `yaml:"summary,omitempty"`
+}
+
+// stringList 同时支持 YAML 列表和逗号分隔的字符串
+type stringList []string
+
+// UnmarshalYAML 实现 yaml.Unmarshaler
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*l = splitList(value.Value)
+		return nil
+	case yaml.SequenceNode:
+		var items []string
+		if err := value.Decode(&items); err != nil {
+			return err
+		}
+		*l = splitList(strings.Join(items, ","))
+		return nil
+	default:
+		return fmt.Errorf("标签格式无效")
+	}
+}
+
+// splitList 拆分逗号分隔的列表并去除空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseMarkdown 解析带 front-matter 的 Markdown 文件
+// 缺少标题时依次使用第一个一级标题和文件名，缺少分类时使用所在目录名
+func ParseMarkdown(filePath string, data []byte) (*Document, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var meta frontMatter
+	body := text
+	if strings.HasPrefix(text, "---\n") {
+		rest := text[len("---\n"):]
+		end := strings.Index(rest, "\n---\n")
+		switch {
+		case strings.HasPrefix(rest, "---\n"):
+			end = 0
+			body = rest[len("---\n"):]
+		case end >= 0:
+			body = rest[end+len("\n---\n"):]
+		case strings.HasSuffix(rest, "\n---"):
+			end = len(rest) - len("\n---")
+			body = ""
+		default:
+			return nil, fmt.Errorf("front-matter 缺少结束标记")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+			return nil, fmt.Errorf("解析 front-matter 失败: %w", err)
+		}
+	}
+
+	doc := &Document{
+		Path:     filePath,
+		Title:    strings.TrimSpace(meta.Title),
+		Slug:     strings.TrimSpace(meta.Slug),
+		Category: strings.TrimSpace(meta.Category),
+		Tags:     meta.Tags,
+		Type:     strings.TrimSpace(meta.Type),
+		Language: strings.TrimSpace(meta.Language),
+		Summary:  strings.TrimSpace(meta.Summary),
+		Format:   FormatMarkdown,
+		Content:  strings.TrimLeft(body, "\n"),
+	}
+
+	if doc.Title == "" {
+		doc.Title = firstHeading(doc.Content)
+	}
+	if doc.Title == "" {
+		doc.Title = strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+	}
+	if doc.Category == "" {
+		if dir := path.Dir(filePath); dir != "." && dir != "/" {
+			doc.Category = path.Base(dir)
+		}
+	}
+
+	if strings.TrimSpace(doc.Content) == "" {
+		return nil, fmt.Errorf("文章内容为空")
+	}
+
+	return doc, nil
+}
+
+// FormatMarkdownDocument 将文档序列化为带 front-matter 的 Markdown
+func FormatMarkdownDocument(doc *Document) ([]byte, error) {
+	meta := frontMatter{
+		Title:    doc.Title,
+		Slug:     doc.Slug,
+		Category: doc.Category,
+		Tags:     doc.Tags,
+		Type:     doc.Type,
+		Language: doc.Language,
+		Summary:  doc.Summary,
+	}
+
+	header, err := yaml.Marshal(&meta)
+	if err != nil {
+		return nil, fmt.Errorf("序列化 front-matter 失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	buf.WriteString(doc.Content)
+	if !strings.HasSuffix(doc.Content, "\n") {
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// firstHeading 返回第一个一级标题
+func firstHeading(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return ""
+}
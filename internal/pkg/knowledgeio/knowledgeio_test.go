@@ -0,0 +1,154 @@
+package knowledgeio
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarkdown_FrontMatter(t *testing.T) {
+	data := []byte("---\ntitle: 数据库连接池耗尽\ncategory: 数据库\ntags: mysql, 连接池\ntype: runbook\n---\n\n# 现象\n\n连接数打满")
+
+	doc, err := ParseMarkdown("ops/db.md", data)
+	require.NoError(t, err)
+	assert.Equal(t, "数据库连接池耗尽", doc.Title)
+	assert.Equal(t, "数据库", doc.Category)
+	assert.Equal(t, []string{"mysql", "连接池"}, doc.Tags)
+	assert.Equal(t, "runbook", doc.Type)
+	assert.Equal(t, "# 现象\n\n连接数打满", doc.Content)
+}
+
+func TestParseMarkdown_Fallbacks(t *testing.T) {
+	doc, err := ParseMarkdown("网络/dns.md", []byte("# DNS 解析失败\n\n检查 resolv.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "DNS 解析失败", doc.Title)
+	assert.Equal(t, "网络", doc.Category)
+
+	doc, err = ParseMarkdown("readme.md", []byte("没有标题"))
+	require.NoError(t, err)
+	assert.Equal(t, "readme", doc.Title)
+	assert.Empty(t, doc.Category)
+
+	_, err = ParseMarkdown("broken.md", []byte("---\ntitle: x\n正文"))
+	assert.Error(t, err)
+}
+
+func TestMarkdownBundle_RoundTrip(t *testing.T) {
+	docs := []*Document{
+		{Title: "重启服务", Category: "运维", Tags: []string{"systemd"}, Content: "systemctl restart app"},
+		{Title: "重启服务", Category: "运维", Content: "第二篇同名文章"},
+		{Title: "a/b: c", Content: "特殊字符标题"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMarkdownBundle(&buf, docs))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"运维/重启服务.md", "运维/重启服务-2.md", "a-b-c.md"}, names)
+
+	parsed, fileErrors, err := ReadMarkdownBundle(buf.Bytes())
+	require.NoError(t, err)
+	assert.Empty(t, fileErrors)
+	require.Len(t, parsed, 3)
+	assert.Equal(t, "重启服务", parsed[0].Title)
+	assert.Equal(t, "运维", parsed[0].Category)
+	assert.Equal(t, []string{"systemd"}, parsed[0].Tags)
+	assert.Equal(t, "systemctl restart app\n", parsed[0].Content)
+	assert.Equal(t, "a/b: c", parsed[2].Title)
+}
+
+func TestReadMarkdownBundle_SkipsAndReportsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	files := map[string]string{
+		"__MACOSX/._a.md": "junk",
+		"image.png":       "binary",
+		"empty.md":        "---\ntitle: 空\n---\n",
+		"ok.md":           "# 正常\n\n内容",
+	}
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	docs, fileErrors, err := ReadMarkdownBundle(buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "正常", docs[0].Title)
+	require.Len(t, fileErrors, 1)
+	assert.Equal(t, "empty.md", fileErrors[0].Path)
+}
+
+func TestReadConfluenceExport(t *testing.T) {
+	entities := `<?xml version="1.0" encoding="UTF-8"?>
+<hibernate-generic datetime="2024-01-01 00:00:00">
+<object class="Space" package="com.atlassian.confluence.spaces">
+  <id name="id">1</id>
+  <property name="name"><![CDATA[运维手册]]></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+  <id name="id">10</id>
+  <property name="title"><![CDATA[磁盘告警处理]]></property>
+  <property name="space" class="Space" package="com.atlassian.confluence.spaces"><id name="id">1</id></property>
+  <property name="contentStatus"><![CDATA[current]]></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+  <id name="id">11</id>
+  <property name="title"><![CDATA[磁盘告警处理]]></property>
+  <property name="originalVersion" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+  <property name="contentStatus"><![CDATA[current]]></property>
+</object>
+<object class="BodyContent" package="com.atlassian.confluence.core">
+  <id name="id">100</id>
+  <property name="body"><![CDATA[<p>清理 /var/log</p>]]></property>
+  <property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="BodyContent" package="com.atlassian.confluence.core">
+  <id name="id">101</id>
+  <property name="body"><![CDATA[<p>旧版本</p>]]></property>
+  <property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">11</id></property>
+</object>
+<object class="Label" package="com.atlassian.confluence.labels">
+  <id name="id">200</id>
+  <property name="name"><![CDATA[disk]]></property>
+</object>
+<object class="Labelling" package="com.atlassian.confluence.labels">
+  <id name="id">300</id>
+  <property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+  <property name="label" class="Label" package="com.atlassian.confluence.labels"><id name="id">200</id></property>
+</object>
+</hibernate-generic>`
+
+	docs, err := ReadConfluenceExport([]byte(entities))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "磁盘告警处理", docs[0].Title)
+	assert.Equal(t, "运维手册", docs[0].Category)
+	assert.Equal(t, []string{"disk"}, docs[0].Tags)
+	assert.Equal(t, FormatHTML, docs[0].Format)
+	assert.Equal(t, "<p>清理 /var/log</p>", docs[0].Content)
+
+	// 完整导出压缩包
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	w, err := writer.Create("entities.xml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(entities))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	docs, err = ReadConfluenceExport(buf.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, docs, 1)
+}
@@ -0,0 +1,176 @@
+// Package loglevel 管理运行时可调整的日志级别：全局级别和按模块覆盖的级别。
+// 模块即 zap 日志器名称（logger.Named），worker 同时匹配 worker 和 worker.slo，匹配最长的模块名生效
+package loglevel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// modulePattern 模块名格式，与 zap 日志器名称一致，以 . 分隔层级
+var modulePattern = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+
+// Levels 全局和按模块的日志级别
+type Levels struct {
+	// floor 所有级别中最低的级别，作为底层 core 的级别，按模块的精确过滤由 NewCore 完成
+	floor zap.AtomicLevel
+
+	mu       sync.RWMutex
+	global   zapcore.Level
+	modules  map[string]zapcore.Level
+	onChange []func(zapcore.Level)
+}
+
+// Snapshot 当前的日志级别配置
+type Snapshot struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// New 创建日志级别，level 为初始的全局级别
+func New(level zapcore.Level) *Levels {
+	return &Levels{
+		floor:   zap.NewAtomicLevelAt(level),
+		global:  level,
+		modules: make(map[string]zapcore.Level),
+	}
+}
+
+// AtomicLevel 返回底层 core 使用的级别，用于 zap.Config.Level
+func (l *Levels) AtomicLevel() zap.AtomicLevel {
+	return l.floor
+}
+
+// Parse 解析级别名称：debug、info、warn、error、dpanic、panic、fatal
+func Parse(name string) (zapcore.Level, error) {
+	level, err := zapcore.ParseLevel(strings.TrimSpace(name))
+	if err != nil {
+		return level, fmt.Errorf("无效的日志级别: %s", name)
+	}
+	return level, nil
+}
+
+// Global 返回全局级别
+func (l *Levels) Global() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.global
+}
+
+// SetGlobal 设置全局级别，并通知 OnChange 注册的回调
+func (l *Levels) SetGlobal(level zapcore.Level) {
+	l.mu.Lock()
+	l.global = level
+	l.updateFloor()
+	callbacks := append([]func(zapcore.Level){}, l.onChange...)
+	l.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(level)
+	}
+}
+
+// OnChange 注册全局级别变更回调，用于同步 logrus 等其他日志器的级别
+func (l *Levels) OnChange(fn func(zapcore.Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onChange = append(l.onChange, fn)
+}
+
+// SetModule 设置模块的级别，覆盖全局级别
+func (l *Levels) SetModule(module string, level zapcore.Level) error {
+	if !modulePattern.MatchString(module) {
+		return fmt.Errorf("无效的模块名: %s", module)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modules[module] = level
+	l.updateFloor()
+	return nil
+}
+
+// ResetModule 删除模块的级别，恢复使用全局级别，模块未设置级别时返回 false
+func (l *Levels) ResetModule(module string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.modules[module]; !ok {
+		return false
+	}
+	delete(l.modules, module)
+	l.updateFloor()
+	return true
+}
+
+// Snapshot 返回当前的日志级别配置
+func (l *Levels) Snapshot() Snapshot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	modules := make(map[string]string, len(l.modules))
+	for module, level := range l.modules {
+		modules[module] = level.String()
+	}
+	return Snapshot{Level: l.global.String(), Modules: modules}
+}
+
+// Enabled 判断名为 name 的日志器是否记录 level 级别的日志
+func (l *Levels) Enabled(name string, level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return level >= l.levelFor(name)
+}
+
+// levelFor 返回日志器生效的级别，调用方持有读锁
+func (l *Levels) levelFor(name string) zapcore.Level {
+	for name != "" {
+		if level, ok := l.modules[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.global
+}
+
+// updateFloor 更新底层 core 的级别为所有级别中的最低者，调用方持有写锁
+func (l *Levels) updateFloor() {
+	floor := l.global
+	for _, level := range l.modules {
+		if level < floor {
+			floor = level
+		}
+	}
+	l.floor.SetLevel(floor)
+}
+
+// core 按日志器名称过滤级别的 zapcore.Core
+type core struct {
+	zapcore.Core
+	levels *Levels
+}
+
+// NewCore 包装 zapcore.Core，按日志器名称匹配模块级别过滤日志。
+// 底层 core 的级别须为 AtomicLevel，用法：zap.WrapCore(levels.NewCore)
+func (l *Levels) NewCore(c zapcore.Core) zapcore.Core {
+	return &core{Core: c, levels: l}
+}
+
+// With 添加上下文字段
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check 按日志器名称对应的级别判断是否记录
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.Enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
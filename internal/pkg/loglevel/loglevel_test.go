@@ -0,0 +1,66 @@
+package loglevel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevels_ModuleOverrides(t *testing.T) {
+	levels := New(zapcore.InfoLevel)
+	require.NoError(t, levels.SetModule("worker", zapcore.DebugLevel))
+	require.NoError(t, levels.SetModule("worker.slo", zapcore.ErrorLevel))
+	assert.Error(t, levels.SetModule("Worker SLO", zapcore.DebugLevel))
+
+	assert.True(t, levels.Enabled("worker.notification", zapcore.DebugLevel))
+	assert.False(t, levels.Enabled("worker.slo", zapcore.WarnLevel))
+	assert.False(t, levels.Enabled("service", zapcore.DebugLevel))
+	assert.True(t, levels.Enabled("", zapcore.InfoLevel))
+	assert.Equal(t, zapcore.DebugLevel, levels.AtomicLevel().Level())
+
+	assert.True(t, levels.ResetModule("worker"))
+	assert.False(t, levels.ResetModule("worker"))
+	assert.False(t, levels.Enabled("worker.notification", zapcore.DebugLevel))
+	assert.Equal(t, zapcore.InfoLevel, levels.AtomicLevel().Level())
+
+	assert.Equal(t, Snapshot{Level: "info", Modules: map[string]string{"worker.slo": "error"}}, levels.Snapshot())
+}
+
+func TestLevels_Core(t *testing.T) {
+	levels := New(zapcore.InfoLevel)
+	observed, logs := observer.New(levels.AtomicLevel())
+	logger := zap.New(levels.NewCore(observed))
+
+	var changed []zapcore.Level
+	levels.OnChange(func(level zapcore.Level) { changed = append(changed, level) })
+
+	logger.Named("worker").Debug("hidden")
+	require.NoError(t, levels.SetModule("worker", zapcore.DebugLevel))
+	logger.Named("worker").Named("slo").Debug("worker debug")
+	logger.Named("service").Debug("hidden")
+	logger.Named("service").Info("service info")
+
+	levels.SetGlobal(zapcore.ErrorLevel)
+	logger.Named("service").Warn("hidden")
+	logger.Named("worker").With(zap.String("k", "v")).Info("worker info")
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"worker debug", "service info", "worker info"}, messages)
+	assert.Equal(t, []zapcore.Level{zapcore.ErrorLevel}, changed)
+}
+
+func TestParse(t *testing.T) {
+	level, err := Parse(" debug ")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, level)
+
+	_, err = Parse("verbose")
+	assert.Error(t, err)
+}
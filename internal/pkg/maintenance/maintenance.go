@@ -0,0 +1,189 @@
+// Package maintenance 管理维护（只读）模式。维护期间网关拒绝写请求，告警接入请求暂存到队列，
+// 维护结束后再处理，便于在不停机的情况下维护数据库。
+// 配置了共享存储时状态在实例间同步，任一实例切换维护模式后其他实例在下次同步时跟随切换
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stateKey 共享存储中维护状态的键
+const stateKey = "maintenance:state"
+
+// stateTTL 共享存储中维护状态的保留时间，每次同步时刷新
+const stateTTL = 7 * 24 * time.Hour
+
+// Store 共享存储，cache.Cache 满足该接口
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
+
+// Status 维护模式状态
+type Status struct {
+	Enabled   bool       `json:"enabled"`
+	Reason    string     `json:"reason,omitempty"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	Since     *time.Time `json:"since,omitempty"`
+	// RetryAfter 建议客户端重试的等待秒数
+	RetryAfter int `json:"retry_after"`
+}
+
+// Mode 维护模式，nil 视为未开启
+type Mode struct {
+	retryAfter time.Duration
+
+	mu       sync.RWMutex
+	status   Status
+	store    Store
+	onChange []func(Status)
+}
+
+// New 创建维护模式，retryAfter 为维护期间返回给客户端的建议重试等待时间
+func New(retryAfter time.Duration) *Mode {
+	return &Mode{retryAfter: retryAfter}
+}
+
+// SetStore 设置共享存储，需在 Enable、Disable 和 Watch 之前调用
+func (m *Mode) SetStore(store Store) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
+// Enabled 是否处于维护模式
+func (m *Mode) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.Enabled
+}
+
+// RetryAfter 维护期间返回给客户端的建议重试等待时间
+func (m *Mode) RetryAfter() time.Duration {
+	if m == nil {
+		return 0
+	}
+	return m.retryAfter
+}
+
+// Status 返回当前状态
+func (m *Mode) Status() Status {
+	if m == nil {
+		return Status{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshot()
+}
+
+// OnChange 注册状态变更回调，本实例切换和从共享存储同步到的变更都会触发
+func (m *Mode) OnChange(fn func(Status)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = append(m.onChange, fn)
+}
+
+// Enable 开启维护模式，已开启时只更新原因
+func (m *Mode) Enable(ctx context.Context, reason, updatedBy string) (Status, error) {
+	since := time.Now()
+	if current := m.Status(); current.Enabled && current.Since != nil {
+		since = *current.Since
+	}
+	return m.update(ctx, Status{Enabled: true, Reason: reason, UpdatedBy: updatedBy, Since: &since})
+}
+
+// Disable 关闭维护模式
+func (m *Mode) Disable(ctx context.Context, updatedBy string) (Status, error) {
+	now := time.Now()
+	return m.update(ctx, Status{Enabled: false, UpdatedBy: updatedBy, Since: &now})
+}
+
+// update 写入共享存储后应用到本实例，写入失败时不切换，避免与其他实例状态不一致
+func (m *Mode) update(ctx context.Context, status Status) (Status, error) {
+	m.mu.RLock()
+	store := m.store
+	m.mu.RUnlock()
+
+	if store != nil {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return Status{}, err
+		}
+		if err := store.Set(ctx, stateKey, string(data), stateTTL); err != nil {
+			return Status{}, fmt.Errorf("保存维护模式状态失败: %w", err)
+		}
+	}
+	m.apply(status)
+	return m.Status(), nil
+}
+
+// Sync 从共享存储读取状态并应用到本实例，共享存储中没有状态时保持不变
+func (m *Mode) Sync(ctx context.Context) error {
+	m.mu.RLock()
+	store := m.store
+	m.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	data, err := store.Get(ctx, stateKey)
+	if err != nil {
+		return fmt.Errorf("读取维护模式状态失败: %w", err)
+	}
+	if data == "" {
+		return nil
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		return fmt.Errorf("解析维护模式状态失败: %w", err)
+	}
+	m.apply(status)
+	return nil
+}
+
+// Watch 定期从共享存储同步状态，直到 ctx 取消；同步失败时保持当前状态并交给 onError 处理
+func (m *Mode) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Sync(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// apply 应用状态，开关发生变化时通知回调
+func (m *Mode) apply(status Status) {
+	m.mu.Lock()
+	changed := m.status.Enabled != status.Enabled
+	m.status = status
+	snapshot := m.snapshot()
+	callbacks := append([]func(Status){}, m.onChange...)
+	m.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, fn := range callbacks {
+		fn(snapshot)
+	}
+}
+
+func (m *Mode) snapshot() Status {
+	status := m.status
+	status.RetryAfter = int(m.retryAfter.Seconds())
+	return status
+}
@@ -0,0 +1,73 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore 进程内共享存储，模拟多个实例共用的 Redis
+type memoryStore struct {
+	values map[string]string
+	err    error
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (string, error) {
+	return s.values[key], nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.values[key] = value.(string)
+	return nil
+}
+
+func TestMode(t *testing.T) {
+	ctx := context.Background()
+	var nilMode *Mode
+	assert.False(t, nilMode.Enabled())
+
+	store := &memoryStore{values: map[string]string{}}
+	a, b := New(time.Minute), New(time.Minute)
+	a.SetStore(store)
+	b.SetStore(store)
+
+	var changes []bool
+	b.OnChange(func(status Status) { changes = append(changes, status.Enabled) })
+
+	status, err := a.Enable(ctx, "数据库升级", "admin")
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 60, status.RetryAfter)
+	since := *status.Since
+
+	// 其他实例同步后跟随切换，重复同步不再触发回调
+	require.NoError(t, b.Sync(ctx))
+	require.NoError(t, b.Sync(ctx))
+	assert.True(t, b.Enabled())
+	assert.Equal(t, "数据库升级", b.Status().Reason)
+	assert.Equal(t, []bool{true}, changes)
+
+	// 已开启时更新原因不改变开始时间
+	status, err = a.Enable(ctx, "清理索引", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, since, *status.Since)
+
+	_, err = b.Disable(ctx, "ops")
+	require.NoError(t, err)
+	require.NoError(t, a.Sync(ctx))
+	assert.False(t, a.Enabled())
+	assert.Equal(t, []bool{true, false}, changes)
+
+	// 共享存储写入失败时不切换
+	store.err = errors.New("redis down")
+	_, err = a.Enable(ctx, "", "admin")
+	assert.Error(t, err)
+	assert.False(t, a.Enabled())
+}
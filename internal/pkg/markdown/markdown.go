@@ -0,0 +1,627 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	hrPattern          = regexp.MustCompile(`^ {0,3}((\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$`)
+	fencePattern       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	unorderedPattern   = regexp.MustCompile(`^( {0,3})[-*+][ \t]+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^( {0,3})(\d{1,9})[.)][ \t]+(.*)$`)
+	tableDelimPattern  = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	blockquotePattern  = regexp.MustCompile(`^ {0,3}>[ ]?(.*)$`)
+	taskItemPattern    = regexp.MustCompile(`^\[([ xX])\][ \t]+(.*)$`)
+	autolinkURLPattern = regexp.MustCompile(`^<(https?://[^\s<>]+|mailto:[^\s<>]+)>`)
+)
+
+// ToHTML 将 Markdown 转换为 HTML
+// 原始 HTML 一律转义，输出仅包含渲染器生成的标签，需配合 Sanitize 使用以解析图片和生成目录
+func ToHTML(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"))
+	return b.String()
+}
+
+// renderBlocks 渲染块级元素
+func renderBlocks(b *strings.Builder, lines []string) {
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			i = renderFence(b, lines, i)
+
+		case headingPattern.MatchString(trimmed) && leadingSpaces(line) < 4:
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := len(m[1])
+			b.WriteString("<h" + string(rune('0'+level)) + ">")
+			b.WriteString(renderInline(m[2]))
+			b.WriteString("</h" + string(rune('0'+level)) + ">\n")
+			i++
+
+		case hrPattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case blockquotePattern.MatchString(line):
+			var quoted []string
+			for i < len(lines) {
+				m := blockquotePattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					// 惰性续行
+					if strings.TrimSpace(lines[i]) == "" || isBlockStart(lines[i]) {
+						break
+					}
+					quoted = append(quoted, lines[i])
+				} else {
+					quoted = append(quoted, m[1])
+				}
+				i++
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case unorderedPattern.MatchString(line) || orderedPattern.MatchString(line):
+			i = renderList(b, lines, i)
+
+		case i+1 < len(lines) && strings.Contains(line, "|") && tableDelimPattern.MatchString(strings.TrimSpace(lines[i+1])):
+			i = renderTable(b, lines, i)
+
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !isBlockStart(lines[i])) {
+				para = append(para, lines[i])
+				i++
+			}
+			b.WriteString("<p>")
+			b.WriteString(renderInline(joinParagraph(para)))
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// isBlockStart 判断行是否开始一个新的块级元素
+func isBlockStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return fencePattern.MatchString(line) ||
+		(headingPattern.MatchString(trimmed) && leadingSpaces(line) < 4) ||
+		hrPattern.MatchString(line) ||
+		blockquotePattern.MatchString(line) ||
+		unorderedPattern.MatchString(line) ||
+		orderedPattern.MatchString(line)
+}
+
+// renderFence 渲染围栏代码块
+func renderFence(b *strings.Builder, lines []string, start int) int {
+	m := fencePattern.FindStringSubmatch(lines[start])
+	fence := m[1]
+	lang := m[2]
+
+	var code []string
+	i := start + 1
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence[:3]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+		i++
+	}
+
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+	}
+	b.WriteString(">")
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	if len(code) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList 渲染有序/无序列表
+func renderList(b *strings.Builder, lines []string, start int) int {
+	ordered := orderedPattern.MatchString(lines[start]) && !unorderedPattern.MatchString(lines[start])
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		m := orderedPattern.FindStringSubmatch(lines[start])
+		if m[2] != "1" {
+			b.WriteString(`<ol start="` + strings.TrimLeft(m[2], "0") + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	i := start
+	for i < len(lines) {
+		var content string
+		var indent int
+		if ordered {
+			m := orderedPattern.FindStringSubmatch(lines[i])
+			if m == nil {
+				break
+			}
+			indent = len(m[1]) + len(m[2]) + 2
+			content = m[3]
+		} else {
+			m := unorderedPattern.FindStringSubmatch(lines[i])
+			if m == nil {
+				break
+			}
+			indent = len(m[1]) + 2
+			content = m[2]
+		}
+		i++
+
+		// 收集缩进的续行和子列表
+		item := []string{content}
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// 空行后仍有缩进内容则继续属于当前项
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= indent && strings.TrimSpace(lines[i+1]) != "" {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) >= indent || (leadingSpaces(line) >= 2 && (unorderedPattern.MatchString(strings.TrimLeft(line, " ")) || orderedPattern.MatchString(strings.TrimLeft(line, " ")))) {
+				item = append(item, dedent(line, indent))
+				i++
+				continue
+			}
+			if isBlockStart(line) {
+				break
+			}
+			// 惰性续行
+			item = append(item, line)
+			i++
+		}
+
+		b.WriteString("<li>")
+		if m := taskItemPattern.FindStringSubmatch(item[0]); m != nil {
+			checked := ""
+			if m[1] != " " {
+				checked = " checked"
+			}
+			b.WriteString(`<input type="checkbox" disabled` + checked + `> `)
+			item[0] = m[2]
+		}
+		if len(item) == 1 {
+			b.WriteString(renderInline(item[0]))
+		} else {
+			// 首段作为内联内容，其余按块渲染
+			first := 0
+			for first < len(item) && item[first] != "" && !isBlockStart(item[first]) {
+				first++
+			}
+			if first == 0 {
+				first = 1
+			}
+			b.WriteString(renderInline(joinParagraph(item[:first])))
+			if first < len(item) {
+				b.WriteString("\n")
+				renderBlocks(b, item[first:])
+			}
+		}
+		b.WriteString("</li>\n")
+
+		// 空行后紧跟同类列表项时继续
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) {
+			next := lines[i+1]
+			if (ordered && orderedPattern.MatchString(next)) || (!ordered && unorderedPattern.MatchString(next)) {
+				i++
+			}
+		}
+	}
+
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderTable 渲染 GFM 表格
+func renderTable(b *strings.Builder, lines []string, start int) int {
+	header := splitTableRow(lines[start])
+	aligns := tableAligns(splitTableRow(lines[start+1]))
+
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for idx, cell := range header {
+		b.WriteString("<th" + alignAttr(aligns, idx) + ">" + renderInline(cell) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n")
+
+	i := start + 2
+	if i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|") {
+		b.WriteString("<tbody>\n")
+		for i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|") {
+			cells := splitTableRow(lines[i])
+			b.WriteString("<tr>")
+			for idx := range header {
+				cell := ""
+				if idx < len(cells) {
+					cell = cells[idx]
+				}
+				b.WriteString("<td" + alignAttr(aligns, idx) + ">" + renderInline(cell) + "</td>")
+			}
+			b.WriteString("</tr>\n")
+			i++
+		}
+		b.WriteString("</tbody>\n")
+	}
+
+	b.WriteString("</table>\n")
+	return i
+}
+
+// splitTableRow 拆分表格行
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	cells = append(cells, strings.TrimSpace(cell.String()))
+	return cells
+}
+
+// tableAligns 解析表格对齐方式
+func tableAligns(cells []string) []string {
+	aligns := make([]string, len(cells))
+	for i, cell := range cells {
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns[i] = "center"
+		case right:
+			aligns[i] = "right"
+		case left:
+			aligns[i] = "left"
+		}
+	}
+	return aligns
+}
+
+// alignAttr 生成对齐属性
+func alignAttr(aligns []string, idx int) string {
+	if idx < len(aligns) && aligns[idx] != "" {
+		return ` align="` + aligns[idx] + `"`
+	}
+	return ""
+}
+
+// renderInline 渲染行内元素
+func renderInline(s string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			next := s[i+1]
+			if next == '\n' {
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+			if strings.IndexByte("\\`*_{}[]()#+-.!|~<>", next) >= 0 {
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+			b.WriteString("\\")
+			i++
+
+		case c == '`':
+			n := countRun(s, i, '`')
+			end := strings.Index(s[i+n:], strings.Repeat("`", n))
+			if end < 0 {
+				b.WriteString(strings.Repeat("`", n))
+				i += n
+				continue
+			}
+			code := s[i+n : i+n+end]
+			if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			i += n + end + n
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, dest, title, n, ok := parseLink(s[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(plainText(text)) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">")
+				i += 1 + n
+				continue
+			}
+			b.WriteString("!")
+			i++
+
+		case c == '[':
+			if text, dest, title, n, ok := parseLink(s[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">" + renderInline(text) + "</a>")
+				i += n
+				continue
+			}
+			b.WriteString("[")
+			i++
+
+		case c == '<':
+			if m := autolinkURLPattern.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + html.EscapeString(strings.TrimPrefix(m[1], "mailto:")) + "</a>")
+				i += len(m[0])
+				continue
+			}
+			b.WriteString("&lt;")
+			i++
+
+		case c == '~' && strings.HasPrefix(s[i:], "~~"):
+			if end := findCloser(s, i+2, "~~"); end > 0 {
+				b.WriteString("<del>" + renderInline(s[i+2:end]) + "</del>")
+				i = end + 2
+				continue
+			}
+			b.WriteString("~~")
+			i += 2
+
+		case c == '*' || c == '_':
+			n := countRun(s, i, c)
+			// 单词内部的下划线不作为强调
+			if c == '_' && i > 0 && isWordChar(s[i-1]) {
+				b.WriteString(strings.Repeat("_", n))
+				i += n
+				continue
+			}
+			if n >= 2 {
+				delim := strings.Repeat(string(c), 2)
+				if end := findCloser(s, i+2, delim); end > 0 && end > i+2 {
+					b.WriteString("<strong>" + renderInline(s[i+2:end]) + "</strong>")
+					i = end + 2
+					continue
+				}
+			}
+			if end := findCloser(s, i+1, string(c)); end > 0 && end > i+1 && s[i+1] != ' ' {
+				b.WriteString("<em>" + renderInline(s[i+1:end]) + "</em>")
+				i = end + 1
+				continue
+			}
+			b.WriteString(strings.Repeat(string(c), n))
+			i += n
+
+		case c == '\n':
+			// 行尾两个空格表示硬换行
+			if strings.HasSuffix(b.String(), "  ") {
+				trimmed := strings.TrimRight(b.String(), " ")
+				b.Reset()
+				b.WriteString(trimmed)
+				b.WriteString("<br>\n")
+			} else {
+				b.WriteString("\n")
+			}
+			i++
+
+		default:
+			// 普通文本按段输出，避免拆分多字节字符
+			j := i + 1
+			for j < len(s) && strings.IndexByte("\\`![<~*_\n", s[j]) < 0 {
+				j++
+			}
+			b.WriteString(html.EscapeString(s[i:j]))
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// parseLink 解析 [text](dest "title")，返回消耗的字节数
+func parseLink(s string) (text, dest, title string, n int, ok bool) {
+	if len(s) == 0 || s[0] != '[' {
+		return
+	}
+
+	depth := 0
+	closeBracket := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeBracket = i
+			}
+		}
+		if closeBracket >= 0 {
+			break
+		}
+	}
+	if closeBracket < 0 || closeBracket+1 >= len(s) || s[closeBracket+1] != '(' {
+		return
+	}
+
+	rest := s[closeBracket+2:]
+	closeParen := -1
+	parens := 0
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '\\' {
+			i++
+			continue
+		}
+		if rest[i] == '(' {
+			parens++
+		}
+		if rest[i] == ')' {
+			if parens == 0 {
+				closeParen = i
+				break
+			}
+			parens--
+		}
+	}
+	if closeParen < 0 {
+		return
+	}
+
+	inner := strings.TrimSpace(rest[:closeParen])
+	if strings.HasPrefix(inner, "<") {
+		if end := strings.Index(inner, ">"); end > 0 {
+			dest = inner[1:end]
+			inner = strings.TrimSpace(inner[end+1:])
+		}
+	} else if idx := strings.IndexAny(inner, " \n"); idx >= 0 {
+		dest = inner[:idx]
+		inner = strings.TrimSpace(inner[idx:])
+	} else {
+		dest = inner
+		inner = ""
+	}
+
+	if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+		title = inner[1 : len(inner)-1]
+	}
+
+	return s[1:closeBracket], dest, title, closeBracket + 2 + closeParen + 1, true
+}
+
+// findCloser 查找闭合分隔符位置
+func findCloser(s string, from int, delim string) int {
+	for i := from; i <= len(s)-len(delim); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '`' {
+			n := countRun(s, i, '`')
+			if end := strings.Index(s[i+n:], strings.Repeat("`", n)); end >= 0 {
+				i += n + end + n - 1
+				continue
+			}
+		}
+		if strings.HasPrefix(s[i:], delim) && i > from-1 && s[i-1] != ' ' {
+			// 闭合符后不能紧跟同一字符，避免 ** 被拆成两个 *
+			if len(delim) == 1 && i+1 < len(s) && s[i+1] == delim[0] {
+				i++
+				continue
+			}
+			if delim[0] == '_' && i+len(delim) < len(s) && isWordChar(s[i+len(delim)]) {
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// plainText 去除 Markdown 标记，用于 alt 和目录文本
+func plainText(s string) string {
+	replacer := strings.NewReplacer("**", "", "__", "", "~~", "", "`", "", "*", "", "\\", "")
+	s = replacer.Replace(s)
+	// [text](url) -> text
+	for {
+		text, _, _, n, ok := findLink(s)
+		if !ok {
+			break
+		}
+		s = s[:n[0]] + text + s[n[1]:]
+	}
+	return strings.TrimSpace(s)
+}
+
+// findLink 查找第一个链接，返回文本及其在原串中的范围
+func findLink(s string) (string, string, string, [2]int, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '[' {
+			continue
+		}
+		start := i
+		if i > 0 && s[i-1] == '!' {
+			start = i - 1
+		}
+		if text, dest, title, n, ok := parseLink(s[i:]); ok {
+			return text, dest, title, [2]int{start, i + n}, true
+		}
+	}
+	return "", "", "", [2]int{}, false
+}
+
+// joinParagraph 合并段落行，保留行尾硬换行标记
+func joinParagraph(lines []string) string {
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		trimmed[i] = strings.TrimLeft(line, " ")
+	}
+	return strings.TrimRight(strings.Join(trimmed, "\n"), " ")
+}
+
+// countRun 统计连续相同字符的数量
+func countRun(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// leadingSpaces 统计行首空格数
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// dedent 去除指定数量的行首空格
+func dedent(line string, n int) string {
+	spaces := leadingSpaces(line)
+	if spaces < n {
+		n = spaces
+	}
+	return line[n:]
+}
+
+// isWordChar 是否为单词字符
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTML_Blocks(t *testing.T) {
+	src := "# 标题\n\n段落 **加粗** 和 *斜体* 以及 `code`\n\n- 第一项\n- 第二项\n\n1. 一\n2. 二\n\n> 引用\n\n```go\nfmt.Println(\"<hi>\")\n```\n\n---\n"
+
+	out := ToHTML(src)
+
+	assert.Contains(t, out, "<h1>标题</h1>")
+	assert.Contains(t, out, "<p>段落 <strong>加粗</strong> 和 <em>斜体</em> 以及 <code>code</code></p>")
+	assert.Contains(t, out, "<ul>\n<li>第一项</li>\n<li>第二项</li>\n</ul>")
+	assert.Contains(t, out, "<ol>\n<li>一</li>\n<li>二</li>\n</ol>")
+	assert.Contains(t, out, "<blockquote>\n<p>引用</p>\n</blockquote>")
+	assert.Contains(t, out, `<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)`)
+	assert.Contains(t, out, "<hr>")
+}
+
+func TestToHTML_Table(t *testing.T) {
+	src := "| 名称 | 值 |\n|:---|---:|\n| a | 1 |\n"
+
+	out := ToHTML(src)
+
+	assert.Contains(t, out, `<th align="left">名称</th><th align="right">值</th>`)
+	assert.Contains(t, out, `<td align="left">a</td><td align="right">1</td>`)
+}
+
+func TestToHTML_LinksAndImages(t *testing.T) {
+	out := ToHTML(`见 [文档](https://example.com "说明") 和 ![架构图](arch.png)`)
+
+	assert.Contains(t, out, `<a href="https://example.com" title="说明">文档</a>`)
+	assert.Contains(t, out, `<img src="arch.png" alt="架构图">`)
+}
+
+func TestRender_EscapesRawHTML(t *testing.T) {
+	doc, err := Render("<script>alert(1)</script>\n\n[x](javascript:alert(1))", Options{})
+	require.NoError(t, err)
+
+	assert.NotContains(t, doc.HTML, "<script>")
+	assert.NotContains(t, doc.HTML, "javascript:")
+	assert.Contains(t, doc.HTML, "&lt;script&gt;")
+}
+
+func TestSanitize_RemovesDangerousContent(t *testing.T) {
+	input := `<p onclick="x()">ok<script>alert(1)</script></p><a href=" java	script:alert(1)">bad</a><img src="data:image/png;base64,AAA"><iframe src="https://evil"></iframe><a href="https://example.com">good</a>`
+
+	doc, err := Sanitize(input, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<p>ok</p><a>bad</a><img><a href="https://example.com" rel="nofollow noopener noreferrer" target="_blank">good</a>`, doc.HTML)
+}
+
+func TestSanitize_ClosesUnbalancedTags(t *testing.T) {
+	doc, err := Sanitize("<ul><li><strong>未闭合", Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "<ul><li><strong>未闭合</strong></li></ul>", doc.HTML)
+}
+
+func TestRender_TOC(t *testing.T) {
+	src := "# 概述\n\n## 安装步骤\n\n### 依赖\n\n#### 细节\n\n## 安装步骤\n"
+
+	doc, err := Render(src, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []TOCEntry{
+		{Level: 1, Text: "概述", Anchor: "概述"},
+		{Level: 2, Text: "安装步骤", Anchor: "安装步骤"},
+		{Level: 3, Text: "依赖", Anchor: "依赖"},
+		{Level: 2, Text: "安装步骤", Anchor: "安装步骤-1"},
+	}, doc.TOC)
+	assert.Contains(t, doc.HTML, `<h4 id="细节">细节</h4>`)
+	assert.Contains(t, doc.HTML, `<h2 id="安装步骤-1">安装步骤</h2>`)
+}
+
+func TestRender_ImageResolver(t *testing.T) {
+	resolver := func(src string) (string, bool) {
+		if src == "arch.png" {
+			return "/api/v1/knowledge/k1/attachments/a1/raw", true
+		}
+		return "", false
+	}
+
+	doc, err := Render("![架构](arch.png) ![外部](https://cdn.example.com/x.png) ![坏](javascript:x)", Options{ImageResolver: resolver})
+	require.NoError(t, err)
+
+	assert.Contains(t, doc.HTML, `<img src="/api/v1/knowledge/k1/attachments/a1/raw" alt="架构">`)
+	assert.Contains(t, doc.HTML, `<img src="https://cdn.example.com/x.png" alt="外部">`)
+	assert.Contains(t, doc.HTML, `<img alt="坏">`)
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Getting Started":    "getting-started",
+		"  API -- 参考 ":       "api-参考",
+		"Step 1: Install!":   "step-1-install",
+		"???":                "",
+		"snake_case heading": "snake-case-heading",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, Slugify(input), strings.TrimSpace(input))
+	}
+}
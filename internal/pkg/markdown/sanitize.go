@@ -0,0 +1,361 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// TOCEntry 目录项
+type TOCEntry struct {
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Anchor string `json:"anchor"`
+}
+
+// Document 渲染结果
+type Document struct {
+	HTML string     `json:"html"`
+	TOC  []TOCEntry `json:"toc"`
+}
+
+// ImageResolver 将文章中的图片引用解析为可访问的地址
+// 返回 ok=false 时保留原始地址（仍需通过协议白名单）
+type ImageResolver func(src string) (resolved string, ok bool)
+
+// Options 渲染选项
+type Options struct {
+	// ImageResolver 图片地址解析器
+	ImageResolver ImageResolver
+	// MaxTOCLevel 目录收录的最大标题级别，默认 3
+	MaxTOCLevel int
+}
+
+// allowedTags 允许的标签及其属性
+var allowedTags = map[string][]string{
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"p": nil, "br": nil, "hr": nil, "blockquote": nil, "pre": nil,
+	"code": {"class"}, "em": nil, "strong": nil, "del": nil, "s": nil, "b": nil, "i": nil, "u": nil,
+	"sub": nil, "sup": nil, "kbd": nil, "mark": nil, "span": nil, "div": nil,
+	"ul": nil, "ol": {"start"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+	"a":     {"href", "title"},
+	"img":   {"src", "alt", "title", "width", "height"},
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil,
+	"th":    {"align", "colspan", "rowspan"},
+	"td":    {"align", "colspan", "rowspan"},
+	"input": {"type", "checked", "disabled"},
+}
+
+// voidTags 自闭合标签
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true, "input": true}
+
+// droppedTags 连同内容一起丢弃的标签
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "math": true, "textarea": true,
+	"select": true, "button": true, "form": true, "head": true, "title": true,
+}
+
+// Render 渲染 Markdown 并清洗输出
+func Render(src string, opts Options) (*Document, error) {
+	return Sanitize(ToHTML(src), opts)
+}
+
+// Sanitize 按白名单清洗 HTML，生成标题锚点和目录，并解析图片地址
+func Sanitize(input string, opts Options) (*Document, error) {
+	maxLevel := opts.MaxTOCLevel
+	if maxLevel <= 0 {
+		maxLevel = 3
+	}
+
+	var (
+		out      strings.Builder
+		toc      = make([]TOCEntry, 0)
+		slugs    = make(map[string]int)
+		open     []string
+		dropping string
+		depth    int
+
+		heading      string
+		headingText  strings.Builder
+		headingStart int
+	)
+
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(input))
+	for {
+		tt := tokenizer.Next()
+		if tt == xhtml.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, fmt.Errorf("解析HTML失败: %w", err)
+			}
+			break
+		}
+
+		token := tokenizer.Token()
+		name := token.Data
+
+		// 丢弃危险标签及其全部内容
+		if dropping != "" {
+			switch {
+			case tt == xhtml.StartTagToken && name == dropping:
+				depth++
+			case tt == xhtml.EndTagToken && name == dropping:
+				depth--
+				if depth == 0 {
+					dropping = ""
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case xhtml.TextToken:
+			out.WriteString(html.EscapeString(token.Data))
+			if heading != "" {
+				headingText.WriteString(token.Data)
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if droppedTags[name] {
+				if tt == xhtml.StartTagToken {
+					dropping = name
+					depth = 1
+				}
+				continue
+			}
+			attrs, ok := allowedTags[name]
+			if !ok {
+				continue
+			}
+			if name == "input" && attrValue(token, "type") != "checkbox" {
+				continue
+			}
+
+			out.WriteString("<" + name)
+			for _, attr := range token.Attr {
+				if attr.Namespace != "" || !contains(attrs, attr.Key) {
+					continue
+				}
+				value, ok := sanitizeAttr(name, attr.Key, attr.Val, opts)
+				if !ok {
+					continue
+				}
+				if value == "" && (attr.Key == "checked" || attr.Key == "disabled") {
+					out.WriteString(" " + attr.Key)
+					continue
+				}
+				out.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+			}
+			if name == "a" && isExternal(attrValue(token, "href")) {
+				out.WriteString(` rel="nofollow noopener noreferrer" target="_blank"`)
+			}
+
+			if isHeading(name) && heading == "" {
+				heading = name
+				headingText.Reset()
+				// 锚点依赖标题文本，在标题结束时插入
+				headingStart = out.Len()
+			}
+			out.WriteString(">")
+
+			if !voidTags[name] && tt == xhtml.StartTagToken {
+				open = append(open, name)
+			}
+
+		case xhtml.EndTagToken:
+			if _, ok := allowedTags[name]; !ok || voidTags[name] {
+				continue
+			}
+			// 仅闭合已打开的标签，保证输出结构完整
+			idx := lastIndex(open, name)
+			if idx < 0 {
+				continue
+			}
+			for i := len(open) - 1; i >= idx; i-- {
+				out.WriteString("</" + open[i] + ">")
+			}
+			open = open[:idx]
+
+			if name == heading {
+				text := strings.Join(strings.Fields(headingText.String()), " ")
+				anchor := uniqueSlug(slugs, Slugify(text))
+
+				// 在标题开始标签中插入锚点
+				rendered := out.String()
+				out.Reset()
+				out.WriteString(rendered[:headingStart] + ` id="` + html.EscapeString(anchor) + `"` + rendered[headingStart:])
+
+				level, _ := strconv.Atoi(name[1:])
+				if level <= maxLevel && text != "" {
+					toc = append(toc, TOCEntry{Level: level, Text: text, Anchor: anchor})
+				}
+				heading = ""
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+
+	return &Document{HTML: out.String(), TOC: toc}, nil
+}
+
+// Slugify 将标题文本转换为锚点
+// 保留字母、数字（含中文），空白和连字符合并为单个连字符
+func Slugify(text string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			lastDash = false
+		case r == '-' || r == '_' || unicode.IsSpace(r):
+			if !lastDash && b.Len() > 0 {
+				b.WriteByte('-')
+				lastDash = true
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// uniqueSlug 为重复锚点追加序号
+func uniqueSlug(slugs map[string]int, slug string) string {
+	if slug == "" {
+		slug = "section"
+	}
+	count, exists := slugs[slug]
+	slugs[slug] = count + 1
+	if !exists {
+		return slug
+	}
+	candidate := slug + "-" + strconv.Itoa(count)
+	for {
+		if _, taken := slugs[candidate]; !taken {
+			slugs[candidate] = 1
+			return candidate
+		}
+		count++
+		candidate = slug + "-" + strconv.Itoa(count)
+	}
+}
+
+// sanitizeAttr 校验属性值
+func sanitizeAttr(tag, key, value string, opts Options) (string, bool) {
+	switch key {
+	case "href":
+		return value, isSafeURL(value, false)
+	case "src":
+		if tag == "img" && opts.ImageResolver != nil {
+			if resolved, ok := opts.ImageResolver(value); ok {
+				return resolved, true
+			}
+		}
+		return value, isSafeURL(value, true)
+	case "class":
+		// 仅保留代码高亮语言标记
+		var classes []string
+		for _, class := range strings.Fields(value) {
+			if strings.HasPrefix(class, "language-") {
+				classes = append(classes, class)
+			}
+		}
+		return strings.Join(classes, " "), len(classes) > 0
+	case "start", "width", "height", "colspan", "rowspan":
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		return strconv.Itoa(n), err == nil && n >= 0
+	case "align":
+		switch value {
+		case "left", "right", "center":
+			return value, true
+		}
+		return "", false
+	case "type":
+		return value, value == "checkbox"
+	case "checked", "disabled":
+		return "", true
+	}
+	return value, true
+}
+
+// isSafeURL 校验URL协议白名单
+func isSafeURL(raw string, image bool) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return false
+	}
+	// 去除控制字符后再判断协议，防止 "java\tscript:" 绕过
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, raw)
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		// 相对地址，排除 "//host" 之外的协议伪装
+		return !strings.Contains(strings.SplitN(cleaned, "/", 2)[0], ":")
+	case "http", "https":
+		return true
+	case "mailto":
+		return !image
+	}
+	return false
+}
+
+// isExternal 是否为外部链接
+func isExternal(href string) bool {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https" || strings.HasPrefix(href, "//")
+}
+
+// isHeading 是否为标题标签
+func isHeading(tag string) bool {
+	return len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6'
+}
+
+// attrValue 获取属性值
+func attrValue(token xhtml.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// contains 判断切片是否包含元素
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lastIndex 查找最后一次出现的位置
+func lastIndex(list []string, s string) int {
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i] == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// core 写入日志前脱敏消息和字段的 zapcore.Core
+type core struct {
+	zapcore.Core
+}
+
+// NewCore 包装 zapcore.Core，敏感字段名的值替换为 Mask，消息、字符串和错误字段按 String 脱敏。
+// 用法：zap.WrapCore(redact.NewCore)
+func NewCore(c zapcore.Core) zapcore.Core {
+	return &core{Core: c}
+}
+
+// With 添加脱敏后的上下文字段
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(Fields(fields))}
+}
+
+// Check 判断是否记录日志，记录时由本 core 写入
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 脱敏后写入日志
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = String(ent.Message)
+	return c.Core.Write(ent, Fields(fields))
+}
+
+// Fields 返回脱敏后的日志字段
+func Fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = Field(field)
+	}
+	return redacted
+}
+
+// Field 脱敏单个日志字段：敏感字段名的字符串值替换为 Mask，其他字符串、Stringer 和错误按 String 脱敏
+func Field(field zapcore.Field) zapcore.Field {
+	switch field.Type {
+	case zapcore.StringType:
+		if IsSensitiveKey(field.Key) && field.String != "" {
+			return zap.String(field.Key, Mask)
+		}
+		return zap.String(field.Key, String(field.String))
+	case zapcore.ByteStringType:
+		if b, ok := field.Interface.([]byte); ok {
+			if IsSensitiveKey(field.Key) {
+				return zap.String(field.Key, Mask)
+			}
+			return zap.String(field.Key, String(string(b)))
+		}
+	case zapcore.StringerType:
+		if s, ok := field.Interface.(fmt.Stringer); ok {
+			return zap.String(field.Key, String(safeString(s)))
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			return zap.String(field.Key, Error(err))
+		}
+	}
+	return field
+}
+
+// safeString 调用 String 方法，nil 指针等导致的 panic 按 zap 的方式输出
+func safeString(s fmt.Stringer) (str string) {
+	defer func() {
+		if r := recover(); r != nil {
+			str = fmt.Sprintf("<PANIC=%v>", r)
+		}
+	}()
+	return s.String()
+}
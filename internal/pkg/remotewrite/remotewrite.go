@@ -0,0 +1,228 @@
+// Package remotewrite 解析 Prometheus remote-write 1.0 协议的请求体
+//
+// 请求体为 snappy 块格式压缩的 protobuf 编码 WriteRequest：
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; ... }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; ... }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+//
+// 只解析标签和样本，元数据、exemplar 和原生直方图被忽略。
+package remotewrite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// HeaderVersion remote-write 协议版本请求头
+	HeaderVersion = "X-Prometheus-Remote-Write-Version"
+	// Version 支持的协议版本
+	Version = "0.1.0"
+	// ContentType 请求体的内容类型
+	ContentType = "application/x-protobuf"
+	// ContentEncoding 请求体的压缩方式
+	ContentEncoding = "snappy"
+
+	// MetricNameLabel 保存指标名的标签
+	MetricNameLabel = "__name__"
+)
+
+// ErrInvalidMessage protobuf 消息格式错误
+var ErrInvalidMessage = errors.New("无效的 remote-write 消息")
+
+// Label 时间序列的标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 时间序列的样本，时间戳为 Unix 毫秒
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// TimeSeries 一个时间序列的标签和样本
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// Metric 返回时间序列的指标名
+func (ts *TimeSeries) Metric() string {
+	for _, label := range ts.Labels {
+		if label.Name == MetricNameLabel {
+			return label.Value
+		}
+	}
+	return ""
+}
+
+// Decode 解压并解析 remote-write 请求体，解压后超过 maxSize 字节时返回 ErrTooLarge
+func Decode(body []byte, maxSize int) ([]TimeSeries, error) {
+	data, err := DecodeSnappy(body, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(data)
+}
+
+// Encode 将时间序列编码为 snappy 压缩的 remote-write 请求体
+func Encode(series []TimeSeries) []byte {
+	return EncodeSnappy(Marshal(series))
+}
+
+// Unmarshal 解析未压缩的 WriteRequest
+func Unmarshal(data []byte) ([]TimeSeries, error) {
+	var series []TimeSeries
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+		ts, err := unmarshalTimeSeries(value)
+		if err != nil {
+			return err
+		}
+		series = append(series, ts)
+		return nil
+	})
+	return series, err
+}
+
+func unmarshalTimeSeries(data []byte) (TimeSeries, error) {
+	var ts TimeSeries
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			label, err := unmarshalLabel(value)
+			if err != nil {
+				return err
+			}
+			ts.Labels = append(ts.Labels, label)
+		case 2:
+			sample, err := unmarshalSample(value)
+			if err != nil {
+				return err
+			}
+			ts.Samples = append(ts.Samples, sample)
+		}
+		return nil
+	})
+	return ts, err
+}
+
+func unmarshalLabel(data []byte) (Label, error) {
+	var label Label
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			label.Name = string(value)
+		case 2:
+			label.Value = string(value)
+		}
+		return nil
+	})
+	return label, err
+}
+
+func unmarshalSample(data []byte) (Sample, error) {
+	var sample Sample
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return sample, fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return sample, fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+			}
+			sample.Value = math.Float64frombits(v)
+			data = data[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return sample, fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+			}
+			sample.Timestamp = int64(v)
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return sample, fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+	return sample, nil
+}
+
+// walk 遍历消息的字段，长度分隔类型的字段以内容调用 fn，其他类型的字段以 nil 调用 fn
+func walk(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(data)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidMessage, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if err := fn(num, typ, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal 将时间序列编码为未压缩的 WriteRequest
+func Marshal(series []TimeSeries) []byte {
+	var data []byte
+	for _, ts := range series {
+		var tsData []byte
+		for _, label := range ts.Labels {
+			var labelData []byte
+			labelData = protowire.AppendTag(labelData, 1, protowire.BytesType)
+			labelData = protowire.AppendString(labelData, label.Name)
+			labelData = protowire.AppendTag(labelData, 2, protowire.BytesType)
+			labelData = protowire.AppendString(labelData, label.Value)
+			tsData = protowire.AppendTag(tsData, 1, protowire.BytesType)
+			tsData = protowire.AppendBytes(tsData, labelData)
+		}
+		for _, sample := range ts.Samples {
+			var sampleData []byte
+			sampleData = protowire.AppendTag(sampleData, 1, protowire.Fixed64Type)
+			sampleData = protowire.AppendFixed64(sampleData, math.Float64bits(sample.Value))
+			sampleData = protowire.AppendTag(sampleData, 2, protowire.VarintType)
+			sampleData = protowire.AppendVarint(sampleData, uint64(sample.Timestamp))
+			tsData = protowire.AppendTag(tsData, 2, protowire.BytesType)
+			tsData = protowire.AppendBytes(tsData, sampleData)
+		}
+		data = protowire.AppendTag(data, 1, protowire.BytesType)
+		data = protowire.AppendBytes(data, tsData)
+	}
+	return data
+}
@@ -0,0 +1,64 @@
+package remotewrite
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSnappy(t *testing.T) {
+	// "abcabcabcabcX"：3 字节字面量、1 字节偏移复制 9 字节（与输出重叠）、1 字节字面量
+	src := []byte{13, 2 << 2, 'a', 'b', 'c', (9-4)<<2 | tagCopy1, 3, 0 << 2, 'X'}
+	out, err := DecodeSnappy(src, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "abcabcabcabcX", string(out))
+
+	// 2 字节偏移复制
+	src = []byte{6, 2 << 2, 'x', 'y', 'z', (3-1)<<2 | tagCopy2, 3, 0}
+	out, err = DecodeSnappy(src, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "xyzxyz", string(out))
+
+	// 长度与内容不符、偏移越界均为格式错误
+	_, err = DecodeSnappy([]byte{5, 0 << 2, 'a'}, 1024)
+	assert.ErrorIs(t, err, ErrCorrupt)
+	_, err = DecodeSnappy([]byte{4, 0 << 2, 'a', (3-1)<<2 | tagCopy2, 2, 0}, 1024)
+	assert.ErrorIs(t, err, ErrCorrupt)
+
+	// 解压后的长度超过上限时不分配内存直接返回
+	_, err = DecodeSnappy([]byte{0x80, 0x80, 0x40}, 1024)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	// 编码结果可以还原，覆盖不同长度的字面量和分块
+	for _, size := range []int{0, 1, 59, 60, 255, 256, 70000} {
+		data := bytes.Repeat([]byte("pulse"), size)[:size]
+		out, err := DecodeSnappy(EncodeSnappy(data), 1<<20)
+		require.NoError(t, err)
+		assert.Equal(t, data, out, "size %d", size)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	series := []TimeSeries{
+		{
+			Labels:  []Label{{Name: MetricNameLabel, Value: "node_load1"}, {Name: "instance", Value: "web-1"}},
+			Samples: []Sample{{Value: 0.75, Timestamp: 1700000000000}, {Value: 1.5, Timestamp: 1700000015000}},
+		},
+		{
+			Labels:  []Label{{Name: MetricNameLabel, Value: "up"}},
+			Samples: []Sample{{Value: 1, Timestamp: 1700000000000}},
+		},
+	}
+
+	decoded, err := Decode(Encode(series), 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, series, decoded)
+	assert.Equal(t, "node_load1", decoded[0].Metric())
+
+	// 截断的消息
+	data := Marshal(series)
+	_, err = Unmarshal(data[:len(data)-3])
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
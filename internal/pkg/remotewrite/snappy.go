@@ -0,0 +1,125 @@
+package remotewrite
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// snappy 块格式的元素类型，见 https://github.com/google/snappy/blob/main/format_description.txt
+const (
+	tagLiteral = 0x00
+	tagCopy1   = 0x01
+	tagCopy2   = 0x02
+	tagCopy4   = 0x03
+)
+
+// ErrCorrupt snappy 压缩数据格式错误
+var ErrCorrupt = errors.New("snappy 压缩数据格式错误")
+
+// ErrTooLarge 解压后的数据超过大小上限
+var ErrTooLarge = errors.New("解压后的数据超过大小上限")
+
+// DecodeSnappy 解压 snappy 块格式（非分帧格式）的数据，remote-write 请求体使用块格式。
+// 解压后的长度记录在数据头部，超过 maxSize 时在分配内存前返回 ErrTooLarge
+func DecodeSnappy(src []byte, maxSize int) ([]byte, error) {
+	length, n := binary.Uvarint(src)
+	if n <= 0 || length > uint64(^uint32(0)) {
+		return nil, ErrCorrupt
+	}
+	if length > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: %d 字节，上限 %d 字节", ErrTooLarge, length, maxSize)
+	}
+
+	dst := make([]byte, 0, length)
+	s := n
+	for s < len(src) {
+		tag := src[s]
+		var offset, size int
+		switch tag & 0x03 {
+		case tagLiteral:
+			size = int(tag >> 2)
+			s++
+			// 60~63 表示长度另外用 1~4 个字节存储
+			if size >= 60 {
+				extra := size - 59
+				if s+extra > len(src) {
+					return nil, ErrCorrupt
+				}
+				var v uint32
+				for i := 0; i < extra; i++ {
+					v |= uint32(src[s+i]) << (8 * i)
+				}
+				size = int(v)
+				s += extra
+			}
+			size++
+			if size <= 0 || size > len(src)-s || size > int(length)-len(dst) {
+				return nil, ErrCorrupt
+			}
+			dst = append(dst, src[s:s+size]...)
+			s += size
+			continue
+		case tagCopy1:
+			if s+2 > len(src) {
+				return nil, ErrCorrupt
+			}
+			size = 4 + int(tag>>2)&0x07
+			offset = int(tag&0xe0)<<3 | int(src[s+1])
+			s += 2
+		case tagCopy2:
+			if s+3 > len(src) {
+				return nil, ErrCorrupt
+			}
+			size = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[s+1:]))
+			s += 3
+		case tagCopy4:
+			if s+5 > len(src) {
+				return nil, ErrCorrupt
+			}
+			size = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[s+1:]))
+			s += 5
+		}
+
+		if offset <= 0 || offset > len(dst) || size > int(length)-len(dst) {
+			return nil, ErrCorrupt
+		}
+		// 复制的区间可能与输出重叠（如重复的字节），需逐字节复制
+		start := len(dst) - offset
+		for i := 0; i < size; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+
+	if len(dst) != int(length) {
+		return nil, ErrCorrupt
+	}
+	return dst, nil
+}
+
+// EncodeSnappy 将数据编码为 snappy 块格式。只输出字面量元素不做压缩，
+// 任何 snappy 实现都能解压，用于转发和测试等不关心压缩率的场景
+func EncodeSnappy(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)+len(src)/60+16), uint64(len(src)))
+	for len(src) > 0 {
+		chunk := src
+		// 按 64KB 分块，每块的长度用 2 个字节存储
+		if len(chunk) > 1<<16 {
+			chunk = chunk[:1<<16]
+		}
+		size := len(chunk) - 1
+		switch {
+		case size < 60:
+			dst = append(dst, byte(size)<<2|tagLiteral)
+		case size < 1<<8:
+			dst = append(dst, 60<<2|tagLiteral, byte(size))
+		default:
+			dst = append(dst, 61<<2|tagLiteral, byte(size), byte(size>>8))
+		}
+		dst = append(dst, chunk...)
+		src = src[len(chunk):]
+	}
+	return dst
+}
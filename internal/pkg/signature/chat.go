@@ -0,0 +1,92 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSlackSignature Slack 请求签名头
+	HeaderSlackSignature = "X-Slack-Signature"
+	// HeaderSlackTimestamp Slack 请求时间戳头
+	HeaderSlackTimestamp = "X-Slack-Request-Timestamp"
+	// HeaderDingTalkSign 钉钉机器人回调签名头
+	HeaderDingTalkSign = "sign"
+	// HeaderDingTalkTimestamp 钉钉机器人回调时间戳头，单位毫秒
+	HeaderDingTalkTimestamp = "timestamp"
+
+	// DingTalkTolerance 钉钉要求回调时间戳与当前时间相差不超过 1 小时
+	DingTalkTolerance = time.Hour
+
+	slackVersion = "v0"
+)
+
+// SignSlack 按 Slack v0 规则计算签名: "v0=" + hex(HMAC-SHA256("v0:<时间戳>:<请求体>"))
+func SignSlack(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(slackVersion + ":" + strconv.FormatInt(timestamp, 10) + ":"))
+	mac.Write(body)
+	return slackVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySlack 校验 Slack 斜杠命令请求，时间戳为 Unix 秒，允许偏差 DefaultTolerance
+func VerifySlack(secret, signatureHeader, timestampHeader string, body []byte, now time.Time) error {
+	if signatureHeader == "" || timestampHeader == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if !withinTolerance(now, time.Unix(timestamp, 0), DefaultTolerance) {
+		return ErrInvalidTimestamp
+	}
+
+	if !strings.HasPrefix(signatureHeader, slackVersion+"=") {
+		return ErrSignatureMismatch
+	}
+	if !hmac.Equal([]byte(signatureHeader), []byte(SignSlack(secret, timestamp, body))) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+// SignDingTalk 按钉钉机器人规则计算签名: base64(HMAC-SHA256("<毫秒时间戳>\n<密钥>"))
+func SignDingTalk(secret string, timestampMillis int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestampMillis, 10) + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDingTalk 校验钉钉机器人回调请求，签名不覆盖请求体，时间戳允许偏差 DingTalkTolerance
+func VerifyDingTalk(secret, signHeader, timestampHeader string, now time.Time) error {
+	if signHeader == "" || timestampHeader == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if !withinTolerance(now, time.UnixMilli(timestamp), DingTalkTolerance) {
+		return ErrInvalidTimestamp
+	}
+
+	if !hmac.Equal([]byte(signHeader), []byte(SignDingTalk(secret, timestamp))) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+func withinTolerance(now, timestamp time.Time, tolerance time.Duration) bool {
+	skew := now.Sub(timestamp)
+	return skew <= tolerance && skew >= -tolerance
+}
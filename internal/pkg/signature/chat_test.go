@@ -0,0 +1,32 @@
+package signature
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySlack(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Fack&text=a1&user_id=U123")
+	valid := SignSlack("secret", now.Unix(), body)
+
+	assert.NoError(t, VerifySlack("secret", valid, "1700000000", body, now))
+	assert.Equal(t, ErrMissingSignature, VerifySlack("secret", "", "1700000000", body, now))
+	assert.Equal(t, ErrInvalidTimestamp, VerifySlack("secret", valid, "1700000000", body, now.Add(10*time.Minute)))
+	assert.Equal(t, ErrSignatureMismatch, VerifySlack("other", valid, "1700000000", body, now))
+	assert.Equal(t, ErrSignatureMismatch, VerifySlack("secret", valid, "1700000000", []byte("text=a2"), now))
+}
+
+func TestVerifyDingTalk(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	valid := SignDingTalk("secret", now.UnixMilli())
+
+	assert.NoError(t, VerifyDingTalk("secret", valid, timestamp, now.Add(30*time.Minute)))
+	assert.Equal(t, ErrMissingSignature, VerifyDingTalk("secret", valid, "", now))
+	assert.Equal(t, ErrInvalidTimestamp, VerifyDingTalk("secret", valid, timestamp, now.Add(2*time.Hour)))
+	assert.Equal(t, ErrSignatureMismatch, VerifyDingTalk("other", valid, timestamp, now))
+}
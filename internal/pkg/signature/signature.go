@@ -0,0 +1,85 @@
+// Package signature 实现 Webhook 请求的 HMAC-SHA256 签名和校验
+//
+// 签名内容为 "<时间戳>.<请求体>"，时间戳为 Unix 秒，签名头格式为：
+//
+//	X-Pulse-Timestamp: 1700000000
+//	X-Pulse-Signature: sha256=<hex>
+//
+// 校验时要求时间戳在允许的偏差范围内，防止截获的请求被重放。
+// 另外提供 Slack 斜杠命令和钉钉机器人回调请求的签名校验，见 chat.go。
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSignature 签名请求头
+	HeaderSignature = "X-Pulse-Signature"
+	// HeaderTimestamp 时间戳请求头
+	HeaderTimestamp = "X-Pulse-Timestamp"
+	// DefaultTolerance 默认允许的时间偏差
+	DefaultTolerance = 5 * time.Minute
+
+	scheme = "sha256="
+)
+
+var (
+	// ErrMissingSignature 缺少签名或时间戳
+	ErrMissingSignature = errors.New("缺少签名或时间戳")
+	// ErrInvalidTimestamp 时间戳格式错误或超出允许偏差
+	ErrInvalidTimestamp = errors.New("时间戳无效或已过期")
+	// ErrSignatureMismatch 签名不匹配
+	ErrSignatureMismatch = errors.New("签名不匹配")
+)
+
+// Sign 计算请求签名，返回签名头的值
+func Sign(secret string, timestamp int64, body []byte) string {
+	return scheme + hex.EncodeToString(digest(secret, timestamp, body))
+}
+
+// Verify 校验签名头和时间戳头，tolerance 为 0 时使用 DefaultTolerance
+func Verify(secret, signatureHeader, timestampHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	if signatureHeader == "" || timestampHeader == "" {
+		return ErrMissingSignature
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > tolerance || skew < -tolerance {
+		return ErrInvalidTimestamp
+	}
+
+	if !strings.HasPrefix(signatureHeader, scheme) {
+		return ErrSignatureMismatch
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signatureHeader, scheme))
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+
+	if !hmac.Equal(actual, digest(secret, timestamp, body)) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+func digest(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,39 @@
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"name":"cpu high"}`)
+	valid := Sign("secret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		want      error
+	}{
+		{"签名正确", "secret", valid, "1700000000", body, nil},
+		{"缺少签名", "secret", "", "1700000000", body, ErrMissingSignature},
+		{"缺少时间戳", "secret", valid, "", body, ErrMissingSignature},
+		{"时间戳格式错误", "secret", valid, "abc", body, ErrInvalidTimestamp},
+		{"时间戳过期", "secret", Sign("secret", now.Unix()-600, body), "1699999400", body, ErrInvalidTimestamp},
+		{"密钥错误", "other", valid, "1700000000", body, ErrSignatureMismatch},
+		{"请求体被篡改", "secret", valid, "1700000000", []byte(`{"name":"ok"}`), ErrSignatureMismatch},
+		{"签名格式错误", "secret", "md5=abc", "1700000000", body, ErrSignatureMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, now, 0)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package slug 生成 URL 友好的短链接标识
+//
+// 英文和数字转为小写保留，常用汉字转写为不带声调的拼音，其他字符作为分隔符，
+// 连续分隔符合并为一个连字符。
+package slug
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength slug 的最大长度
+const MaxLength = 100
+
+// pinyin 汉字到拼音的索引
+var pinyin = buildPinyinIndex()
+
+// buildPinyinIndex 构建汉字到拼音的索引
+func buildPinyinIndex() map[rune]string {
+	index := make(map[rune]string, 4000)
+	for _, entry := range pinyinTable {
+		for _, r := range entry.chars {
+			index[r] = entry.syllable
+		}
+	}
+	return index
+}
+
+// Make 根据标题或用户指定的 slug 生成规范的 slug，无法转写任何字符时返回空字符串
+func Make(title string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	write := func(s string) {
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(s)
+	}
+
+	// 先分解组合字符，去掉重音符号后保留基本字母（é -> e）
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(unicode.ToLower(r)))
+		case unicode.Is(unicode.Mn, r):
+			// 组合音标，忽略
+		case pinyin[r] != "":
+			// 每个汉字的拼音之间用连字符分隔，便于阅读
+			pendingDash = true
+			write(pinyin[r])
+			pendingDash = true
+		default:
+			pendingDash = true
+		}
+	}
+
+	return truncate(b.String())
+}
+
+// WithSuffix 为冲突的 slug 追加序号，保证总长度不超过 MaxLength
+func WithSuffix(base string, n int) string {
+	suffix := fmt.Sprintf("-%d", n)
+	if len(base)+len(suffix) > MaxLength {
+		base = strings.TrimRight(base[:MaxLength-len(suffix)], "-")
+	}
+	return base + suffix
+}
+
+// truncate 截断到最大长度，尽量在连字符处断开
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+
+	s = s[:MaxLength]
+	if i := strings.LastIndexByte(s, '-'); i > MaxLength/2 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, "-")
+}
@@ -0,0 +1,43 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Redis Connection Pool", "redis-connection-pool"},
+		{"  MySQL 主从延迟  ", "mysql-zhu-cong-yan-chi"},
+		{"数据库连接池耗尽", "shu-ju-ku-lian-jie-chi-hao-jin"},
+		{"K8s/Pod: CrashLoopBackOff!!", "k8s-pod-crashloopbackoff"},
+		{"Café résumé", "cafe-resume"},
+		{"重启服务v2", "zhong-qi-fu-wu-v2"},
+		{"！！！", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Make(tt.title), tt.title)
+	}
+}
+
+func TestMake_Truncate(t *testing.T) {
+	got := Make(strings.Repeat("磁盘告警 ", 40))
+	assert.LessOrEqual(t, len(got), MaxLength)
+	assert.False(t, strings.HasSuffix(got, "-"))
+	assert.True(t, strings.HasPrefix(got, "ci-pan-gao-jing-ci-pan"))
+}
+
+func TestWithSuffix(t *testing.T) {
+	assert.Equal(t, "disk-alert-2", WithSuffix("disk-alert", 2))
+
+	long := strings.Repeat("a", MaxLength)
+	got := WithSuffix(long, 12)
+	assert.Len(t, got, MaxLength)
+	assert.True(t, strings.HasSuffix(got, "-12"))
+}
@@ -0,0 +1,229 @@
+// Package spa 提供单页应用的静态文件服务：不存在的前端路由回退到 index.html，
+// 带哈希的构建产物长期缓存，文本类资源按需 gzip 压缩并缓存压缩结果
+package spa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IndexFile 单页应用入口文件
+const IndexFile = "index.html"
+
+// 缓存策略
+const (
+	// cacheImmutable 构建产物文件名带内容哈希，内容变化时文件名随之变化
+	cacheImmutable = "public, max-age=31536000, immutable"
+	// cacheRevalidate 入口文件每次使用前向服务端确认，保证发布后立即加载新版本
+	cacheRevalidate = "no-cache"
+	// cacheDefault public 目录下的其他静态文件
+	cacheDefault = "public, max-age=3600"
+)
+
+// minGzipSize 小于该字节数的文件不压缩
+const minGzipSize = 1024
+
+// ErrNoIndex 文件系统中没有 index.html，通常是前端尚未构建
+var ErrNoIndex = errors.New("spa: index.html not found")
+
+// Options 静态文件服务选项
+type Options struct {
+	// ImmutablePrefix 带内容哈希的构建产物目录，默认为 Vite 的 assets/
+	ImmutablePrefix string
+}
+
+// Handler 单页应用静态文件处理器
+type Handler struct {
+	fsys            fs.FS
+	immutablePrefix string
+
+	mu    sync.RWMutex
+	files map[string]*file
+}
+
+// file 已读取的文件内容，嵌入的文件不会变化，读取一次后缓存
+type file struct {
+	name        string
+	content     []byte
+	gzipped     []byte
+	etag        string
+	contentType string
+}
+
+// New 创建单页应用处理器，fsys 的根目录为前端构建输出目录，缺少 index.html 时返回 ErrNoIndex
+func New(fsys fs.FS, opts Options) (*Handler, error) {
+	if _, err := fs.Stat(fsys, IndexFile); err != nil {
+		return nil, ErrNoIndex
+	}
+	if opts.ImmutablePrefix == "" {
+		opts.ImmutablePrefix = "assets/"
+	}
+	return &Handler{
+		fsys:            fsys,
+		immutablePrefix: strings.TrimPrefix(opts.ImmutablePrefix, "/"),
+		files:           make(map[string]*file),
+	}, nil
+}
+
+// ServeHTTP 返回静态文件，找不到且路径没有扩展名时视为前端路由返回 index.html
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = IndexFile
+	}
+
+	f, err := h.open(name)
+	if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+		f, err = h.open(IndexFile)
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	h.serve(w, r, f)
+}
+
+// serve 按缓存策略和压缩协商返回文件
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, f *file) {
+	header := w.Header()
+	switch {
+	case f.name == IndexFile:
+		header.Set("Cache-Control", cacheRevalidate)
+	case strings.HasPrefix(f.name, h.immutablePrefix):
+		header.Set("Cache-Control", cacheImmutable)
+	default:
+		header.Set("Cache-Control", cacheDefault)
+	}
+	header.Set("Content-Type", f.contentType)
+
+	content, etag := f.content, f.etag
+	if f.gzipped != nil {
+		header.Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			content, etag = f.gzipped, strings.TrimSuffix(f.etag, `"`)+`-gzip"`
+			header.Set("Content-Encoding", "gzip")
+		}
+	}
+	header.Set("ETag", etag)
+
+	// 嵌入的文件没有修改时间，由 ETag 处理条件请求
+	http.ServeContent(w, r, f.name, time.Time{}, bytes.NewReader(content))
+}
+
+// open 读取文件并缓存，目录视为不存在
+func (h *Handler) open(name string) (*file, error) {
+	h.mu.RLock()
+	f, ok := h.files[name]
+	h.mu.RUnlock()
+	if ok {
+		return f, nil
+	}
+
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fs.ErrNotExist
+	}
+	content, err := fs.ReadFile(h.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	f = &file{
+		name:        name,
+		content:     content,
+		etag:        `"` + hex.EncodeToString(sum[:8]) + `"`,
+		contentType: contentType(name, content),
+	}
+	if len(content) >= minGzipSize && compressible(f.contentType) {
+		f.gzipped = h.gzipped(name, content)
+	}
+
+	h.mu.Lock()
+	h.files[name] = f
+	h.mu.Unlock()
+	return f, nil
+}
+
+// gzipped 返回压缩后的内容，构建时生成的 .gz 文件优先，压缩后没有变小时返回 nil
+func (h *Handler) gzipped(name string, content []byte) []byte {
+	if precompressed, err := fs.ReadFile(h.fsys, name+".gz"); err == nil {
+		return precompressed
+	}
+
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if _, err := zw.Write(content); err != nil {
+		return nil
+	}
+	if err := zw.Close(); err != nil || buf.Len() >= len(content) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// contentType 按扩展名确定内容类型，未知扩展名时按内容检测
+func contentType(name string, content []byte) string {
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct
+	}
+	return http.DetectContentType(content)
+}
+
+// compressible 判断内容类型是否值得压缩，图片和字体等已压缩的格式除外
+func compressible(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "+xml"), strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	switch mediaType {
+	case "application/javascript", "application/json", "application/xml", "application/wasm",
+		"application/manifest+json", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// acceptsGzip 判断客户端是否接受 gzip 编码，q=0 表示拒绝
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if !strings.EqualFold(coding, "gzip") && coding != "*" {
+			continue
+		}
+		if value, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			return err == nil && q > 0
+		}
+		return true
+	}
+	return false
+}
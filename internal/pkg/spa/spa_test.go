@@ -0,0 +1,96 @@
+package spa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":          {Data: []byte(`<!doctype html><div id="root"></div>`)},
+		"assets/index-abc.js": {Data: []byte(strings.Repeat("console.log('pulse');\n", 200))},
+		"assets/logo-def.png": {Data: bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 512)},
+		"logo-icon.svg":       {Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+		"assets/nested/.keep": {Data: nil},
+	}
+}
+
+func serve(t *testing.T, h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewRequiresIndex(t *testing.T) {
+	_, err := New(fstest.MapFS{".gitkeep": {}}, Options{})
+	assert.ErrorIs(t, err, ErrNoIndex)
+}
+
+func TestHandlerFallbackAndCaching(t *testing.T) {
+	h, err := New(testFS(), Options{})
+	require.NoError(t, err)
+
+	// 前端路由回退到入口文件，且不长期缓存
+	for _, target := range []string{"/", "/alerts/123", "/assets/nested"} {
+		rec := serve(t, h, http.MethodGet, target, nil)
+		require.Equal(t, http.StatusOK, rec.Code, target)
+		assert.Contains(t, rec.Body.String(), `id="root"`, target)
+		assert.Equal(t, cacheRevalidate, rec.Header().Get("Cache-Control"), target)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/html", target)
+	}
+
+	// 带扩展名的缺失文件返回 404，避免把 HTML 当作脚本返回
+	assert.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, "/assets/missing.js", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(t, h, http.MethodPost, "/", nil).Code)
+
+	rec := serve(t, h, http.MethodGet, "/assets/index-abc.js", nil)
+	assert.Equal(t, cacheImmutable, rec.Header().Get("Cache-Control"))
+	assert.Equal(t, cacheDefault, serve(t, h, http.MethodGet, "/logo-icon.svg", nil).Header().Get("Cache-Control"))
+
+	// ETag 命中时返回 304
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	rec = serve(t, h, http.MethodGet, "/assets/index-abc.js", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestHandlerGzip(t *testing.T) {
+	h, err := New(testFS(), Options{})
+	require.NoError(t, err)
+
+	rec := serve(t, h, http.MethodGet, "/assets/index-abc.js", http.Header{"Accept-Encoding": {"br, gzip;q=0.8"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	assert.True(t, strings.HasSuffix(rec.Header().Get("ETag"), `-gzip"`))
+	zr, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, testFS()["assets/index-abc.js"].Data, body)
+
+	// 客户端拒绝 gzip 时返回原始内容
+	rec = serve(t, h, http.MethodGet, "/assets/index-abc.js", http.Header{"Accept-Encoding": {"gzip;q=0"}})
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, testFS()["assets/index-abc.js"].Data, rec.Body.Bytes())
+
+	// 图片和小文件不压缩
+	rec = serve(t, h, http.MethodGet, "/assets/logo-def.png", http.Header{"Accept-Encoding": {"gzip"}})
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	rec = serve(t, h, http.MethodGet, "/logo-icon.svg", http.Header{"Accept-Encoding": {"gzip"}})
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+}
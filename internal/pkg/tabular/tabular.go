@@ -0,0 +1,144 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Format 表格文件格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"  // 逗号分隔，UTF-8 编码，可以带 BOM
+	FormatXLSX Format = "xlsx" // Excel 工作簿，只读取第一个工作表
+)
+
+// IsValid 检查表格格式是否有效
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+// DetectFormat 根据文件扩展名判断格式，扩展名无法识别时按内容判断，zip 压缩包视为 XLSX
+func DetectFormat(fileName string, data []byte) Format {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv", ".txt":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	}
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return FormatXLSX
+	}
+	return FormatCSV
+}
+
+// Row 数据行，Line 为行在文件中的行号，表头为第 1 行
+type Row struct {
+	Line   int
+	Values []string
+}
+
+// Value 获取第 i 列的值，超出范围时返回空字符串
+func (r Row) Value(i int) string {
+	if i < 0 || i >= len(r.Values) {
+		return ""
+	}
+	return r.Values[i]
+}
+
+// Table 读取的表格，第一个非空行为表头，之后的空行被跳过
+type Table struct {
+	Headers []string
+	Rows    []Row
+}
+
+// Column 获取表头所在的列，不存在时返回 -1
+func (t *Table) Column(header string) int {
+	for i, h := range t.Headers {
+		if h == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// ErrTooManyRows 数据行数超过限制
+var ErrTooManyRows = errors.New("数据行数超过限制")
+
+// Read 读取表格，maxRows 为数据行数上限，0 表示不限制。
+// 表头去掉首尾空白，不能为空表格，也不能有重复的表头
+func Read(format Format, data []byte, maxRows int) (*Table, error) {
+	var rows []Row
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(data, maxRows)
+	case FormatXLSX:
+		rows, err = readXLSX(data, maxRows)
+	default:
+		return nil, fmt.Errorf("不支持的表格格式 %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("表格为空，缺少表头")
+	}
+
+	table := &Table{Headers: make([]string, len(rows[0].Values)), Rows: rows[1:]}
+	seen := make(map[string]bool, len(table.Headers))
+	for i, header := range rows[0].Values {
+		header = strings.TrimSpace(header)
+		if header != "" && seen[header] {
+			return nil, fmt.Errorf("表头 %q 重复", header)
+		}
+		seen[header] = true
+		table.Headers[i] = header
+	}
+	return table, nil
+}
+
+// readCSV 读取 CSV，各行的列数可以不同，跳过空行
+func readCSV(data []byte, maxRows int) ([]Row, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		if maxRows > 0 && len(rows) > maxRows {
+			return nil, fmt.Errorf("%w %d", ErrTooManyRows, maxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{Line: line, Values: record})
+	}
+	return rows, nil
+}
+
+// isBlank 是否所有单元格都为空
+func isBlank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead_CSV(t *testing.T) {
+	data := "\xef\xbb\xbf Title ,Description,Priority\n" +
+		"Disk full,\"line one\nline two\",high\n" +
+		",,\n" +
+		"Login broken,,\n" +
+		"Short row\n"
+
+	table, err := Read(FormatCSV, []byte(data), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Title", "Description", "Priority"}, table.Headers)
+	require.Len(t, table.Rows, 3)
+	assert.Equal(t, Row{Line: 2, Values: []string{"Disk full", "line one\nline two", "high"}}, table.Rows[0])
+	assert.Equal(t, 5, table.Rows[1].Line)
+	assert.Equal(t, 6, table.Rows[2].Line)
+	assert.Equal(t, "", table.Rows[2].Value(2))
+	assert.Equal(t, 2, table.Column("Priority"))
+	assert.Equal(t, -1, table.Column("Owner"))
+}
+
+func TestRead_Errors(t *testing.T) {
+	_, err := Read(FormatCSV, []byte("\n\n"), 0)
+	assert.EqualError(t, err, "表格为空，缺少表头")
+
+	_, err = Read(FormatCSV, []byte("title,title\na,b\n"), 0)
+	assert.EqualError(t, err, `表头 "title" 重复`)
+
+	_, err = Read(FormatCSV, []byte("title\na\nb\nc\n"), 2)
+	assert.ErrorIs(t, err, ErrTooManyRows)
+
+	_, err = Read(FormatXLSX, []byte("title\na\n"), 0)
+	assert.Error(t, err)
+}
+
+// buildXLSX 生成只包含必需文件的最小工作簿，第二个工作表排在第一位
+func buildXLSX(t *testing.T) []byte {
+	files := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Tickets" sheetId="2" r:id="rId2"/><sheet name="Other" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Title</t></si><si><t>Due</t></si><si><r><t>Disk </t></r><r><t>full</t></r></si><si><t>Urgent</t></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="s"><v>3</v></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>45292.5</v></c><c r="D2" t="b"><v>1</v></c></row>
+<row r="3"><c r="A3"/></row>
+<row r="5"><c r="C5" t="inlineStr"><is><t>only C</t></is></c></row>
+</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestRead_XLSX(t *testing.T) {
+	data := buildXLSX(t)
+	assert.Equal(t, FormatXLSX, DetectFormat("export.bin", data))
+
+	table, err := Read(FormatXLSX, data, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Title", "Due", "", "Urgent"}, table.Headers)
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, Row{Line: 2, Values: []string{"Disk full", "45292.5", "", "TRUE"}}, table.Rows[0])
+	assert.Equal(t, Row{Line: 5, Values: []string{"", "", "only C"}}, table.Rows[1])
+
+	due, ok := SerialTime(table.Rows[0].Value(1))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), due)
+	_, ok = SerialTime("2024-01-01")
+	assert.False(t, ok)
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatCSV, DetectFormat("tickets.CSV", nil))
+	assert.Equal(t, FormatXLSX, DetectFormat("tickets.xlsx", nil))
+	assert.Equal(t, FormatCSV, DetectFormat("tickets", []byte("title\n")))
+}
@@ -0,0 +1,281 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxXLSXPartSize 工作簿中单个 XML 文件解压后的最大大小，防止压缩炸弹
+const maxXLSXPartSize = 200 << 20
+
+// maxXLSXColumns 工作表的最大列数，与 Excel 的上限 XFD 一致
+const maxXLSXColumns = 16384
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 共享字符串或内联字符串，富文本由多个 r 组成
+type xlsxText struct {
+	T *string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	if t.T != nil {
+		return *t.T
+	}
+	var sb strings.Builder
+	for _, r := range t.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxRow struct {
+	R     int `xml:"r,attr"`
+	Cells []struct {
+		Ref    string    `xml:"r,attr"`
+		Type   string    `xml:"t,attr"`
+		Value  string    `xml:"v"`
+		Inline *xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// readXLSX 读取工作簿的第一个工作表，日期等数字格式不做转换，按单元格中保存的原始值返回
+func readXLSX(data []byte, maxRows int) ([]Row, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("读取 XLSX 失败: %w", err)
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	sheet, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("读取 XLSX 失败: 缺少工作表 %s", sheetPath)
+	}
+
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst xlsxSharedStrings
+		if err := decodeXLSXPart(file, &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i := range sst.Items {
+			shared[i] = sst.Items[i].String()
+		}
+	}
+
+	rc, err := sheet.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取 XLSX 失败: %w", err)
+	}
+	defer rc.Close()
+
+	var rows []Row
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize))
+	line := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析工作表失败: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, fmt.Errorf("解析工作表失败: %w", err)
+		}
+		line++
+		if row.R > 0 {
+			line = row.R
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			col := len(values)
+			if cell.Ref != "" {
+				if col, err = columnIndex(cell.Ref); err != nil {
+					return nil, fmt.Errorf("解析工作表第 %d 行失败: %w", line, err)
+				}
+			}
+			value, err := cellValue(cell.Type, cell.Value, cell.Inline, shared)
+			if err != nil {
+				return nil, fmt.Errorf("解析工作表第 %d 行第 %d 个单元格失败: %w", line, i+1, err)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			if col < len(values) {
+				values[col] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+		if isBlank(values) {
+			continue
+		}
+		if maxRows > 0 && len(rows) > maxRows {
+			return nil, fmt.Errorf("%w %d", ErrTooManyRows, maxRows)
+		}
+		rows = append(rows, Row{Line: line, Values: values})
+	}
+	return rows, nil
+}
+
+// firstSheetPath 按工作簿中的顺序找到第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("读取 XLSX 失败: 缺少 xl/workbook.xml，文件可能不是 Excel 工作簿")
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("读取 XLSX 失败: 工作簿中没有工作表")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		// 目标路径相对于 xl/，以 / 开头时为包内的绝对路径
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXLSXPart 解析工作簿中的 XML 文件
+func decodeXLSXPart(file *zip.File, v interface{}) error {
+	if file.UncompressedSize64 > maxXLSXPartSize {
+		return fmt.Errorf("读取 XLSX 失败: %s 超过大小限制", file.Name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("读取 XLSX 失败: %w", err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", file.Name, err)
+	}
+	return nil
+}
+
+// cellValue 按单元格类型取值，布尔值转换为 TRUE 或 FALSE
+func cellValue(cellType, value string, inline *xlsxText, shared []string) (string, error) {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || i < 0 || i >= len(shared) {
+			return "", fmt.Errorf("无效的共享字符串索引 %q", value)
+		}
+		return shared[i], nil
+	case "inlineStr":
+		if inline == nil {
+			return "", nil
+		}
+		return inline.String(), nil
+	case "b":
+		if strings.TrimSpace(value) == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		return value, nil
+	}
+}
+
+// columnIndex 将单元格引用（如 AB12）的列转换为从 0 开始的列号
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, c := range ref {
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		n++
+		if col > maxXLSXColumns {
+			return 0, fmt.Errorf("单元格引用 %q 超出列数限制", ref)
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("无效的单元格引用 %q", ref)
+	}
+	return col - 1, nil
+}
+
+// excelEpoch Excel 1900 日期系统的零点，取 1899-12-30 以抵消 Excel 把 1900 年视为闰年的错误
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// maxExcelSerial Excel 支持的最大日期 9999-12-31 对应的序列号
+const maxExcelSerial = 2958466
+
+// SerialTime 将 XLSX 中以序列号保存的日期时间转换为 UTC 时间，整数部分为天数，小数部分为一天中的时间
+func SerialTime(value string) (time.Time, bool) {
+	serial, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || serial <= 0 || serial >= maxExcelSerial {
+		return time.Time{}, false
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second), true
+}
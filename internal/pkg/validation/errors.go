@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"pulse/internal/pkg/apierror"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ruleMessages 各校验规则的错误消息，%s 为规则参数
+var ruleMessages = map[string]map[apierror.Locale]string{
+	"required": {apierror.LocaleZhCN: "不能为空", apierror.LocaleEnUS: "is required"},
+	"min":      {apierror.LocaleZhCN: "长度或数值不能小于 %s", apierror.LocaleEnUS: "must be at least %s"},
+	"max":      {apierror.LocaleZhCN: "长度或数值不能大于 %s", apierror.LocaleEnUS: "must be at most %s"},
+	"len":      {apierror.LocaleZhCN: "长度必须为 %s", apierror.LocaleEnUS: "must have length %s"},
+	"gt":       {apierror.LocaleZhCN: "必须大于 %s", apierror.LocaleEnUS: "must be greater than %s"},
+	"gte":      {apierror.LocaleZhCN: "不能小于 %s", apierror.LocaleEnUS: "must be greater than or equal to %s"},
+	"lt":       {apierror.LocaleZhCN: "必须小于 %s", apierror.LocaleEnUS: "must be less than %s"},
+	"lte":      {apierror.LocaleZhCN: "不能大于 %s", apierror.LocaleEnUS: "must be less than or equal to %s"},
+	"oneof":    {apierror.LocaleZhCN: "必须是以下值之一: %s", apierror.LocaleEnUS: "must be one of: %s"},
+	"email":    {apierror.LocaleZhCN: "必须是有效的邮箱地址", apierror.LocaleEnUS: "must be a valid email address"},
+	"url":      {apierror.LocaleZhCN: "必须是有效的URL", apierror.LocaleEnUS: "must be a valid URL"},
+	"uuid":     {apierror.LocaleZhCN: "必须是有效的UUID", apierror.LocaleEnUS: "must be a valid UUID"},
+	"enum":     {apierror.LocaleZhCN: "取值无效", apierror.LocaleEnUS: "has an invalid value"},
+	"duration": {apierror.LocaleZhCN: "必须是大于 0 的有效时长", apierror.LocaleEnUS: "must be a positive duration"},
+	// duration 规则带最小值参数时使用
+	"duration_min": {apierror.LocaleZhCN: "必须是不小于 %s 的有效时长", apierror.LocaleEnUS: "must be a valid duration of at least %s"},
+	"labels": {
+		apierror.LocaleZhCN: fmt.Sprintf("标签名必须匹配 [a-zA-Z_][a-zA-Z0-9_]* 且不能以 __ 开头，最多 %d 个标签，标签值不超过 %d 个字符", MaxLabels, MaxLabelValueLength),
+		apierror.LocaleEnUS: fmt.Sprintf("label names must match [a-zA-Z_][a-zA-Z0-9_]* and must not start with __, with at most %d labels and values up to %d characters", MaxLabels, MaxLabelValueLength),
+	},
+	"type": {apierror.LocaleZhCN: "类型无效，应为 %s", apierror.LocaleEnUS: "must be of type %s"},
+}
+
+// defaultMessages 未登记规则的错误消息
+var defaultMessages = map[apierror.Locale]string{
+	apierror.LocaleZhCN: "未通过 %s 校验",
+	apierror.LocaleEnUS: "failed on the %s rule",
+}
+
+// FieldErrors 将请求绑定错误转换为字段级错误列表，不是字段错误时返回 nil
+func FieldErrors(err error, locale apierror.Locale) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, newFieldError(fieldPath(fe.Namespace()), fe.Tag(), fe.Param(), locale))
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{newFieldError(typeErr.Field, "type", typeErr.Type.String(), locale)}
+	}
+
+	return nil
+}
+
+func newFieldError(field, rule, param string, locale apierror.Locale) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: ruleMessage(rule, param, locale),
+	}
+}
+
+// ruleMessage 返回规则在指定语言下的错误消息
+func ruleMessage(rule, param string, locale apierror.Locale) string {
+	if rule == "duration" && param != "" {
+		rule = "duration_min"
+	}
+
+	messages, ok := ruleMessages[rule]
+	if !ok {
+		return fmt.Sprintf(localized(defaultMessages, locale), rule)
+	}
+
+	message := localized(messages, locale)
+	if strings.Contains(message, "%s") {
+		return fmt.Sprintf(message, param)
+	}
+	return message
+}
+
+func localized(messages map[apierror.Locale]string, locale apierror.Locale) string {
+	if msg, ok := messages[locale]; ok {
+		return msg
+	}
+	return messages[apierror.DefaultLocale]
+}
+
+// fieldPath 去掉命名空间中的结构体名，如 RuleCreateRequest.labels -> labels
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
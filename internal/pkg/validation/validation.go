@@ -0,0 +1,154 @@
+// Package validation 注册请求校验使用的自定义规则，并将校验错误转换为字段级错误列表
+//
+// 自定义规则：
+//
+//	enum       字段类型实现 IsValid() bool，用于告警级别、状态等枚举
+//	duration   time.Duration 或时长字符串，必须大于 0；带参数时表示最小值，如 duration=1m
+//	labels     标签映射，标签名需符合 Prometheus 规范且不能以 __ 开头，值长度和标签数量有限制
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// 标签限制
+const (
+	MaxLabels           = 64
+	MaxLabelValueLength = 1024
+)
+
+var (
+	labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	durationType     = reflect.TypeOf(time.Duration(0))
+	registerGinOnce  sync.Once
+)
+
+// enumValue 可校验取值的枚举类型
+type enumValue interface {
+	IsValid() bool
+}
+
+// Register 在校验器上注册自定义规则，并使用 JSON 字段名报告错误
+func Register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	validations := map[string]validator.Func{
+		"enum":     validateEnum,
+		"duration": validateDuration,
+		"labels":   validateLabels,
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterGinValidators 在 gin 默认的绑定校验器上注册自定义规则，重复调用只注册一次
+func RegisterGinValidators() {
+	registerGinOnce.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			// 规则名固定且合法，注册不会失败
+			_ = Register(v)
+		}
+	})
+}
+
+// IsValidLabelName 检查标签名是否合法
+func IsValidLabelName(name string) bool {
+	return labelNamePattern.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+// jsonFieldName 返回字段的 JSON 名称，忽略的字段返回空字符串
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// validateEnum 校验枚举取值
+func validateEnum(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.CanInterface() {
+		if v, ok := field.Interface().(enumValue); ok {
+			return v.IsValid()
+		}
+	}
+	if field.CanAddr() {
+		if v, ok := field.Addr().Interface().(enumValue); ok {
+			return v.IsValid()
+		}
+	}
+	return false
+}
+
+// validateDuration 校验时长，参数为可选的最小值
+func validateDuration(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	var d time.Duration
+	switch {
+	case field.Type() == durationType:
+		d = time.Duration(field.Int())
+	case field.Kind() == reflect.String:
+		parsed, err := time.ParseDuration(field.String())
+		if err != nil {
+			return false
+		}
+		d = parsed
+	default:
+		return false
+	}
+
+	if fl.Param() == "" {
+		return d > 0
+	}
+
+	min, err := time.ParseDuration(fl.Param())
+	if err != nil {
+		panic("validation: duration 规则参数无效: " + fl.Param())
+	}
+	return d >= min
+}
+
+// validateLabels 校验标签映射
+func validateLabels(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Map || field.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	if field.Len() > MaxLabels {
+		return false
+	}
+
+	iter := field.MapRange()
+	for iter.Next() {
+		if !IsValidLabelName(iter.Key().String()) {
+			return false
+		}
+
+		value := iter.Value()
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.String && utf8.RuneCountInString(value.String()) > MaxLabelValueLength {
+			return false
+		}
+	}
+
+	return true
+}
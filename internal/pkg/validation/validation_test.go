@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+)
+
+type testRequest struct {
+	Severity models.AlertSeverity   `json:"severity" binding:"required,enum"`
+	Priority *models.TicketPriority `json:"priority,omitempty" binding:"omitempty,enum"`
+	Interval time.Duration          `json:"interval" binding:"required,duration=1s"`
+	Timeout  string                 `json:"timeout,omitempty" binding:"omitempty,duration"`
+	Labels   map[string]string      `json:"labels,omitempty" binding:"omitempty,labels"`
+	Name     string                 `json:"name" binding:"required,max=5"`
+}
+
+func newTestValidator(t *testing.T) *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	require.NoError(t, Register(v))
+	return v
+}
+
+func TestCustomRules(t *testing.T) {
+	v := newTestValidator(t)
+	valid := func() testRequest {
+		return testRequest{
+			Severity: models.AlertSeverityHigh,
+			Interval: time.Minute,
+			Timeout:  "30s",
+			Labels:   map[string]string{"env": "prod", "team_name": "sre"},
+			Name:     "cpu",
+		}
+	}
+
+	req := valid()
+	assert.NoError(t, v.Struct(req))
+
+	tests := []struct {
+		name   string
+		mutate func(r *testRequest)
+		field  string
+		rule   string
+	}{
+		{"无效的告警级别", func(r *testRequest) { r.Severity = "urgent" }, "severity", "enum"},
+		{"无效的枚举指针", func(r *testRequest) { p := models.TicketPriority("asap"); r.Priority = &p }, "priority", "enum"},
+		{"时长小于最小值", func(r *testRequest) { r.Interval = 500 * time.Millisecond }, "interval", "duration"},
+		{"无法解析的时长字符串", func(r *testRequest) { r.Timeout = "5 minutes" }, "timeout", "duration"},
+		{"非法标签名", func(r *testRequest) { r.Labels["1env"] = "prod" }, "labels", "labels"},
+		{"保留标签名", func(r *testRequest) { r.Labels["__name__"] = "up" }, "labels", "labels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(&req)
+
+			fields := FieldErrors(v.Struct(req), apierror.LocaleZhCN)
+			require.Len(t, fields, 1)
+			assert.Equal(t, tt.field, fields[0].Field)
+			assert.Equal(t, tt.rule, fields[0].Rule)
+		})
+	}
+}
+
+func TestFieldErrors_Messages(t *testing.T) {
+	v := newTestValidator(t)
+
+	err := v.Struct(testRequest{Severity: "bad", Interval: time.Millisecond, Name: "too long name"})
+	fields := FieldErrors(err, apierror.LocaleEnUS)
+	require.Len(t, fields, 3)
+
+	assert.Equal(t, FieldError{Field: "severity", Rule: "enum", Message: "has an invalid value"}, fields[0])
+	assert.Equal(t, FieldError{Field: "interval", Rule: "duration", Param: "1s", Message: "must be a valid duration of at least 1s"}, fields[1])
+	assert.Equal(t, FieldError{Field: "name", Rule: "max", Param: "5", Message: "must be at most 5"}, fields[2])
+
+	zh := FieldErrors(err, apierror.LocaleZhCN)
+	assert.Equal(t, "长度或数值不能大于 5", zh[2].Message)
+}
@@ -0,0 +1,238 @@
+// Package prometheus 实现 Prometheus 数据源的查询客户端
+//
+// SLO 计算使用 /api/v1/query 即时查询，只需要单个数值，查询结果为向量时要求只有一个样本；
+// 规则预测使用 /api/v1/query_range 区间查询获取表达式的近期历史，同样要求结果只有一个序列。
+// 兼容 Prometheus HTTP API 的 Thanos、VictoriaMetrics、Mimir 等同样适用。
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// maxErrorBodySize 错误信息中保留的响应体最大字节数
+const maxErrorBodySize = 2 << 10
+
+// ErrNoData 查询没有返回样本，通常表示指标尚未产生或标签选择器不匹配
+var ErrNoData = errors.New("查询结果为空")
+
+// Client Prometheus 查询客户端
+type Client struct {
+	baseURL  string
+	token    string
+	username string
+	password string
+	headers  map[string]string
+	client   *http.Client
+}
+
+// NewClient 根据数据源连接配置创建客户端，请求的超时和重试由 httpClient 控制
+func NewClient(cfg *models.DataSourceConfig, httpClient *http.Client) (*Client, error) {
+	baseURL := strings.TrimRight(cfg.URL, "/")
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("无效的Prometheus地址: %s", cfg.URL)
+	}
+
+	client := &Client{
+		baseURL: baseURL,
+		headers: cfg.Headers,
+		client:  httpClient,
+	}
+	if cfg.Token != nil {
+		client.token = *cfg.Token
+	}
+	if cfg.Username != nil {
+		client.username = *cfg.Username
+	}
+	if cfg.Password != nil {
+		client.password = *cfg.Password
+	}
+	return client, nil
+}
+
+// queryResponse /api/v1/query 响应
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// sample 向量中的一个样本
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+// series 矩阵中的一个序列
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
+
+// Query 执行即时查询并返回唯一的数值，at 为零值时使用服务端当前时间。
+// 结果为空时返回 ErrNoData，结果包含多个序列时返回错误，查询应使用 sum 等聚合为单个序列
+func (c *Client) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	params := url.Values{"query": {expr}}
+	if !at.IsZero() {
+		params.Set("time", formatTime(at))
+	}
+
+	result, err := c.do(ctx, "/api/v1/query", params)
+	if err != nil {
+		return 0, err
+	}
+	return parseResult(result.Data.ResultType, result.Data.Result)
+}
+
+// QueryRange 执行区间查询并返回唯一序列在 [start, end] 内按 step 对齐的样本，按时间升序。
+// 结果为空时返回 ErrNoData，结果包含多个序列时返回错误；NaN 和 ±Inf 样本原样返回，由调用方处理
+func (c *Client) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]models.MetricSample, error) {
+	params := url.Values{
+		"query": {expr},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+
+	result, err := c.do(ctx, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("不支持的区间查询结果类型 %s", result.Data.ResultType)
+	}
+
+	var matrix []series
+	if err := json.Unmarshal(result.Data.Result, &matrix); err != nil {
+		return nil, fmt.Errorf("解析矩阵结果失败: %w", err)
+	}
+	switch len(matrix) {
+	case 0:
+		return nil, ErrNoData
+	case 1:
+	default:
+		return nil, fmt.Errorf("查询返回 %d 个序列，需要聚合为单个序列", len(matrix))
+	}
+
+	samples := make([]models.MetricSample, 0, len(matrix[0].Values))
+	for _, value := range matrix[0].Values {
+		timestamp, ok := value[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("无效的样本时间 %v", value[0])
+		}
+		number, err := parseValue(value)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, models.MetricSample{
+			Timestamp: time.Unix(0, int64(timestamp*1e9)),
+			Value:     number,
+		})
+	}
+	if len(samples) == 0 {
+		return nil, ErrNoData
+	}
+	return samples, nil
+}
+
+// do 以表单方式请求查询接口并解析响应，查询失败时返回服务端的错误信息
+func (c *Client) do(ctx context.Context, path string, params url.Values) (*queryResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("创建查询请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求Prometheus失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取查询结果失败: %w", err)
+	}
+
+	var result queryResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		if len(body) > maxErrorBodySize {
+			body = body[:maxErrorBodySize]
+		}
+		return nil, fmt.Errorf("解析查询结果失败（HTTP %d）: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("查询失败（%s）: %s", result.ErrorType, result.Error)
+	}
+	return &result, nil
+}
+
+// formatTime 将时间格式化为 Prometheus API 使用的秒级 Unix 时间戳
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 3, 64)
+}
+
+// parseResult 从标量或向量结果中取出数值
+func parseResult(resultType string, raw json.RawMessage) (float64, error) {
+	switch resultType {
+	case "scalar":
+		var value [2]interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return 0, fmt.Errorf("解析标量结果失败: %w", err)
+		}
+		return parseValue(value)
+	case "vector":
+		var samples []sample
+		if err := json.Unmarshal(raw, &samples); err != nil {
+			return 0, fmt.Errorf("解析向量结果失败: %w", err)
+		}
+		switch len(samples) {
+		case 0:
+			return 0, ErrNoData
+		case 1:
+			return parseValue(samples[0].Value)
+		default:
+			return 0, fmt.Errorf("查询返回 %d 个序列，需要聚合为单个序列", len(samples))
+		}
+	default:
+		return 0, fmt.Errorf("不支持的查询结果类型 %s", resultType)
+	}
+}
+
+// parseValue 解析 [时间戳, "数值"] 形式的样本值，数值可能是 NaN 或 ±Inf
+func parseValue(value [2]interface{}) (float64, error) {
+	text, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("无效的样本值 %v", value[1])
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的样本值 %q", text)
+	}
+	return number, nil
+}
@@ -0,0 +1,107 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestClient_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/prom/api/v1/query", r.URL.Path)
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "reader", username)
+		assert.Equal(t, "secret", password)
+		require.NoError(t, r.ParseForm())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("query") {
+		case "ratio":
+			assert.Equal(t, "1700000000.000", r.PostForm.Get("time"))
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.0012"]}]}}`))
+		case "empty":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case "by_code":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"code":"500"},"value":[1700000000,"1"]},{"metric":{"code":"503"},"value":[1700000000,"2"]}]}}`))
+		case "scalar(1)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"1"]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	username, password := "reader", "secret"
+	client, err := NewClient(&models.DataSourceConfig{URL: server.URL + "/prom/", Username: &username, Password: &password}, &http.Client{Timeout: time.Second})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := client.Query(ctx, "ratio", time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0012, value)
+
+	value, err = client.Query(ctx, "scalar(1)", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+
+	_, err = client.Query(ctx, "empty", time.Time{})
+	assert.ErrorIs(t, err, ErrNoData)
+
+	_, err = client.Query(ctx, "by_code", time.Time{})
+	assert.ErrorContains(t, err, "2 个序列")
+
+	_, err = client.Query(ctx, "sum(", time.Time{})
+	assert.ErrorContains(t, err, "parse error")
+}
+
+func TestClient_QueryRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("query") {
+		case "disk_used":
+			assert.Equal(t, "1700000000.000", r.PostForm.Get("start"))
+			assert.Equal(t, "1700000600.000", r.PostForm.Get("end"))
+			assert.Equal(t, "300", r.PostForm.Get("step"))
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{},"values":[[1700000000,"10"],[1700000300,"12.5"],[1700000600,"15"]]}]}}`))
+		case "empty":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"mount":"/"},"values":[[1700000000,"1"]]},{"metric":{"mount":"/data"},"values":[[1700000000,"2"]]}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	token := "token"
+	client, err := NewClient(&models.DataSourceConfig{URL: server.URL, Token: &token}, &http.Client{Timeout: time.Second})
+	require.NoError(t, err)
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+
+	samples, err := client.QueryRange(ctx, "disk_used", start, start.Add(10*time.Minute), 5*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.True(t, samples[1].Timestamp.Equal(start.Add(5*time.Minute)))
+	assert.Equal(t, 12.5, samples[1].Value)
+
+	_, err = client.QueryRange(ctx, "empty", start, start.Add(time.Hour), time.Minute)
+	assert.ErrorIs(t, err, ErrNoData)
+
+	_, err = client.QueryRange(ctx, "disk_used_by_mount", start, start.Add(time.Hour), time.Minute)
+	assert.ErrorContains(t, err, "2 个序列")
+}
@@ -0,0 +1,164 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionEndpoint = "https://api.push.apple.com"
+	apnsSandboxEndpoint    = "https://api.sandbox.push.apple.com"
+
+	// apnsTokenTTL 认证令牌的复用时长，APNs 要求令牌在 20 到 60 分钟之间更新
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNsClient Apple Push Notification service 客户端，使用 .p8 密钥签发的认证令牌通过 HTTP/2 推送
+type APNsClient struct {
+	endpoint string
+	keyID    string
+	teamID   string
+	topic    string
+	key      *ecdsa.PrivateKey
+	client   *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsClient 从认证密钥文件创建 APNs 客户端，topic 为应用的 Bundle ID
+func NewAPNsClient(keyFile, keyID, teamID, topic string, production bool, httpClient *http.Client) (*APNsClient, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取 APNs 认证密钥失败: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 APNs 认证密钥失败: %w", err)
+	}
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, fmt.Errorf("APNs 需要配置密钥 ID、团队 ID 和 Bundle ID")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	endpoint := apnsSandboxEndpoint
+	if production {
+		endpoint = apnsProductionEndpoint
+	}
+	return &APNsClient{
+		endpoint: endpoint,
+		keyID:    keyID,
+		teamID:   teamID,
+		topic:    topic,
+		key:      key,
+		client:   httpClient,
+	}, nil
+}
+
+// Send 发送推送，相同折叠 ID 的推送替换设备上已有的通知
+func (c *APNsClient) Send(ctx context.Context, token string, message *Message) error {
+	authToken, err := c.authToken()
+	if err != nil {
+		return err
+	}
+
+	aps := map[string]interface{}{
+		"alert": map[string]string{
+			"title": message.Title,
+			"body":  truncate(message.Body),
+		},
+		"sound": "default",
+	}
+	if message.CollapseKey != "" {
+		aps["thread-id"] = message.CollapseKey
+	}
+	payload := map[string]interface{}{"aps": aps}
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 APNs 推送失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 APNs 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", c.topic)
+	req.Header.Set("apns-push-type", "alert")
+	if message.Critical {
+		req.Header.Set("apns-priority", "10")
+	} else {
+		req.Header.Set("apns-priority", "5")
+	}
+	if message.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", message.CollapseKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送 APNs 推送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if resp.StatusCode == http.StatusGone {
+		// 设备令牌已不再有效
+		return ErrUnregistered
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		var result struct {
+			Reason string `json:"reason"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if json.Unmarshal(data, &result) == nil && result.Reason == "BadDeviceToken" {
+			return ErrUnregistered
+		}
+		return fmt.Errorf("APNs 推送失败，状态码: %d, 响应: %s", resp.StatusCode, string(data))
+	}
+	return responseError("APNs", resp)
+}
+
+// authToken 获取认证令牌，超过复用时长后重新签发
+func (c *APNsClient) authToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.token != "" && now.Sub(c.issuedAt) < apnsTokenTTL {
+		return c.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": c.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = c.keyID
+	signed, err := token.SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("签发 APNs 认证令牌失败: %w", err)
+	}
+	c.token = signed
+	c.issuedAt = now
+	return c.token, nil
+}
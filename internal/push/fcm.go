@@ -0,0 +1,196 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmEndpoint = "https://fcm.googleapis.com"
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// fcmCredentials Firebase 服务账号密钥
+type fcmCredentials struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMClient Firebase Cloud Messaging HTTP v1 客户端，使用服务账号换取的访问令牌认证
+type FCMClient struct {
+	endpoint    string
+	projectID   string
+	credentials fcmCredentials
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMClient 从服务账号密钥文件创建 FCM 客户端，projectID 为空时使用服务账号所属项目
+func NewFCMClient(credentialsFile, projectID string, httpClient *http.Client) (*FCMClient, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取 FCM 服务账号密钥失败: %w", err)
+	}
+	var credentials fcmCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("解析 FCM 服务账号密钥失败: %w", err)
+	}
+	if credentials.ClientEmail == "" || credentials.TokenURI == "" {
+		return nil, fmt.Errorf("FCM 服务账号密钥缺少 client_email 或 token_uri")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("解析 FCM 服务账号私钥失败: %w", err)
+	}
+	if projectID == "" {
+		projectID = credentials.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("未配置 FCM 项目 ID")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &FCMClient{
+		endpoint:    fcmEndpoint,
+		projectID:   projectID,
+		credentials: credentials,
+		key:         key,
+		client:      httpClient,
+	}, nil
+}
+
+// Send 发送推送，Android 使用折叠键和通知标签替换已有通知，iOS 设备经 FCM 转发时使用 APNs 折叠 ID
+func (c *FCMClient) Send(ctx context.Context, token string, message *Message) error {
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	priority, apnsPriority := "normal", "5"
+	if message.Critical {
+		priority, apnsPriority = "high", "10"
+	}
+	android := map[string]interface{}{"priority": priority}
+	apnsHeaders := map[string]string{"apns-priority": apnsPriority}
+	if message.CollapseKey != "" {
+		android["collapse_key"] = message.CollapseKey
+		android["notification"] = map[string]string{"tag": message.CollapseKey}
+		apnsHeaders["apns-collapse-id"] = message.CollapseKey
+	}
+	payload := map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": message.Title,
+				"body":  truncate(message.Body),
+			},
+			"data":    message.Data,
+			"android": android,
+			"apns":    map[string]interface{}{"headers": apnsHeaders},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 FCM 推送失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/v1/projects/%s/messages:send", c.endpoint, url.PathEscape(c.projectID)), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 FCM 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送 FCM 推送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		// 令牌对应的应用实例已不存在，错误码为 UNREGISTERED
+		return ErrUnregistered
+	case resp.StatusCode == http.StatusUnauthorized:
+		c.mu.Lock()
+		c.accessToken = ""
+		c.mu.Unlock()
+	}
+	return responseError("FCM", resp)
+}
+
+// token 获取访问令牌，过期前一分钟重新使用服务账号签名换取
+func (c *FCMClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.accessToken != "" && now.Before(c.expiresAt.Add(-time.Minute)) {
+		return c.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   c.credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   c.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("签名 FCM 访问令牌请求失败: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.credentials.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("创建 FCM 访问令牌请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("获取 FCM 访问令牌失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError("FCM 访问令牌", resp)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析 FCM 访问令牌失败: %w", err)
+	}
+	c.accessToken = result.AccessToken
+	c.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
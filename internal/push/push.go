@@ -0,0 +1,89 @@
+// Package push 实现移动端推送通知的 FCM 和 APNs 客户端
+//
+// 同一告警指纹的推送使用相同的折叠键，设备上已有的通知会被更新而不是重复展示。
+// 推送服务返回设备令牌已失效时返回 ErrUnregistered，调用方应删除该设备。
+package push
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+// maxErrorBodySize 错误信息中保留的响应体最大字节数
+const maxErrorBodySize = 2 << 10
+
+// maxBodyRunes 推送内容的最大字符数，FCM 和 APNs 的负载均限制为 4KB
+const maxBodyRunes = 1000
+
+// ErrUnregistered 设备令牌已失效，应用已卸载或令牌已更新
+var ErrUnregistered = errors.New("推送设备令牌已失效")
+
+// Message 推送消息
+type Message struct {
+	Title string
+	Body  string
+	// CollapseKey 折叠键，相同折叠键的推送在设备上只保留最新一条
+	CollapseKey string
+	// Critical 高优先级推送，设备处于省电模式时也立即送达
+	Critical bool
+	// Data 随推送下发给应用的自定义数据
+	Data map[string]string
+}
+
+// Provider 推送服务客户端
+type Provider interface {
+	// Send 向设备令牌发送推送，令牌失效时返回 ErrUnregistered
+	Send(ctx context.Context, token string, message *Message) error
+}
+
+// Providers 按设备平台区分的推送服务客户端，只包含配置了凭据的平台
+type Providers map[models.PushPlatform]Provider
+
+// New 根据配置创建已配置凭据的推送服务客户端
+func New(cfg config.PushConfig, httpClient *http.Client) (Providers, error) {
+	providers := Providers{}
+	if cfg.FCMCredentialsFile != "" {
+		client, err := NewFCMClient(cfg.FCMCredentialsFile, cfg.FCMProjectID, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		providers[models.PushPlatformFCM] = client
+	}
+	if cfg.APNsKeyFile != "" {
+		client, err := NewAPNsClient(cfg.APNsKeyFile, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, cfg.APNsProduction, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		providers[models.PushPlatformAPNs] = client
+	}
+	return providers, nil
+}
+
+// CollapseKey 根据告警指纹生成折叠键，APNs 的折叠 ID 不能超过 64 字节，因此使用指纹的摘要
+func CollapseKey(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:16])
+}
+
+// truncate 截断超长的推送内容
+func truncate(body string) string {
+	runes := []rune(body)
+	if len(runes) <= maxBodyRunes {
+		return body
+	}
+	return string(runes[:maxBodyRunes-1]) + "…"
+}
+
+// responseError 构造推送服务返回的错误
+func responseError(service string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fmt.Errorf("%s 推送失败，状态码: %d, 响应: %s", service, resp.StatusCode, string(body))
+}
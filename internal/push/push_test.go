@@ -0,0 +1,163 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestFCMClient_Send(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var tokenRequests int32
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			atomic.AddInt32(&tokenRequests, 1)
+			require.NoError(t, r.ParseForm())
+			assertion, err := jwt.Parse(r.PostForm.Get("assertion"), func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+			require.NoError(t, err)
+			claims := assertion.Claims.(jwt.MapClaims)
+			assert.Equal(t, "pulse@example.iam.gserviceaccount.com", claims["iss"])
+			assert.Equal(t, fcmScope, claims["scope"])
+			_, _ = w.Write([]byte(`{"access_token":"at-1","expires_in":3600}`))
+		case "/v1/projects/pulse-prod/messages:send":
+			assert.Equal(t, "Bearer at-1", r.Header.Get("Authorization"))
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			message := body["message"].(map[string]interface{})
+			if message["token"] == "stale" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			sent = message
+			_, _ = w.Write([]byte(`{"name":"projects/pulse-prod/messages/1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"project_id":   "pulse-dev",
+		"client_email": "pulse@example.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+	client, err := NewFCMClient(writeFile(t, "fcm.json", credentials), "pulse-prod", server.Client())
+	require.NoError(t, err)
+	client.endpoint = server.URL
+
+	message := &Message{Title: "HighErrorRate", Body: "checkout 错误率过高", CollapseKey: CollapseKey("fp-1"), Critical: true,
+		Data: map[string]string{"alert_id": "a1"}}
+	require.NoError(t, client.Send(context.Background(), "device-1", message))
+	android := sent["android"].(map[string]interface{})
+	assert.Equal(t, message.CollapseKey, android["collapse_key"])
+	assert.Equal(t, "high", android["priority"])
+	assert.Equal(t, message.CollapseKey, android["notification"].(map[string]interface{})["tag"])
+	headers := sent["apns"].(map[string]interface{})["headers"].(map[string]interface{})
+	assert.Equal(t, message.CollapseKey, headers["apns-collapse-id"])
+	assert.Equal(t, "a1", sent["data"].(map[string]interface{})["alert_id"])
+
+	// 访问令牌在有效期内复用，令牌失效的设备返回 ErrUnregistered
+	assert.ErrorIs(t, client.Send(context.Background(), "stale", message), ErrUnregistered)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestAPNsClient_Send(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyFile := writeFile(t, "AuthKey.p8", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	var headers http.Header
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "large":
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			_, _ = w.Write([]byte(`{"reason":"PayloadTooLarge"}`))
+		default:
+			headers = r.Header.Clone()
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewAPNsClient(keyFile, "KEY123", "TEAM123", "com.example.pulse", false, server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, apnsSandboxEndpoint, client.endpoint)
+	client.endpoint = server.URL
+
+	message := &Message{Title: "HighErrorRate", Body: "checkout 错误率过高", CollapseKey: CollapseKey("fp-1"),
+		Data: map[string]string{"alert_id": "a1"}}
+	require.NoError(t, client.Send(context.Background(), "device-1", message))
+	assert.Equal(t, "com.example.pulse", headers.Get("apns-topic"))
+	assert.Equal(t, message.CollapseKey, headers.Get("apns-collapse-id"))
+	assert.Equal(t, "5", headers.Get("apns-priority"))
+	assert.Equal(t, "a1", payload["alert_id"])
+
+	authToken, err := jwt.Parse(strings.TrimPrefix(headers.Get("Authorization"), "bearer "),
+		func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+	require.NoError(t, err)
+	assert.Equal(t, "KEY123", authToken.Header["kid"])
+	assert.Equal(t, "TEAM123", authToken.Claims.(jwt.MapClaims)["iss"])
+
+	assert.ErrorIs(t, client.Send(context.Background(), "gone", message), ErrUnregistered)
+	assert.ErrorIs(t, client.Send(context.Background(), "bad", message), ErrUnregistered)
+	err = client.Send(context.Background(), "large", message)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnregistered)
+}
+
+func TestNew(t *testing.T) {
+	providers, err := New(config.PushConfig{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, providers)
+
+	_, err = New(config.PushConfig{APNsKeyFile: filepath.Join(t.TempDir(), "missing.p8")}, nil)
+	assert.Error(t, err)
+
+	// 折叠键长度固定，同一指纹得到相同的折叠键
+	assert.Len(t, CollapseKey(strings.Repeat("x", 200)), 32)
+	assert.Equal(t, CollapseKey("fp-1"), CollapseKey("fp-1"))
+	assert.NotEqual(t, CollapseKey("fp-1"), CollapseKey("fp-2"))
+	assert.Nil(t, providers[models.PushPlatformFCM])
+}
@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Availability 后端可用性状态，由 Redis 可用性探测器实现
+type Availability interface {
+	Available() bool
+	ReportFailure(err error)
+}
+
+// FallbackQueue 在 Redis 不可用时自动降级到内存队列的实现
+// 发布时优先写入 Redis，失败或降级期间写入内存队列；订阅同时注册在两个后端上，
+// 保证降级期间发布的消息仍能在本实例内被消费
+type FallbackQueue struct {
+	primary      Queue
+	fallback     *MemoryQueue
+	availability Availability
+	logger       *zap.Logger
+}
+
+// NewFallbackQueue 创建可降级队列，primary 为 nil 时始终使用内存队列
+func NewFallbackQueue(primary Queue, availability Availability, logger *zap.Logger) *FallbackQueue {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &FallbackQueue{
+		primary:      primary,
+		fallback:     NewMemoryQueue(logger),
+		availability: availability,
+		logger:       logger,
+	}
+}
+
+// Degraded 返回当前是否处于降级模式
+func (q *FallbackQueue) Degraded() bool {
+	return q.primary == nil || q.availability == nil || !q.availability.Available()
+}
+
+// publish 优先发布到 Redis，失败时上报故障并发布到内存队列
+func (q *FallbackQueue) publish(fn func(p Producer) error) error {
+	if !q.Degraded() {
+		err := fn(q.primary)
+		if err == nil {
+			return nil
+		}
+		q.availability.ReportFailure(err)
+		q.logger.Warn("Failed to publish to redis queue, falling back to memory queue", zap.Error(err))
+	}
+	return fn(q.fallback)
+}
+
+// Publish 发布消息
+func (q *FallbackQueue) Publish(ctx context.Context, topic string, payload []byte, opts ...PublishOption) error {
+	return q.publish(func(p Producer) error {
+		return p.Publish(ctx, topic, payload, opts...)
+	})
+}
+
+// PublishWithDelay 延迟发布消息
+func (q *FallbackQueue) PublishWithDelay(ctx context.Context, topic string, payload []byte, delay time.Duration, opts ...PublishOption) error {
+	return q.publish(func(p Producer) error {
+		return p.PublishWithDelay(ctx, topic, payload, delay, opts...)
+	})
+}
+
+// PublishBatch 批量发布消息
+func (q *FallbackQueue) PublishBatch(ctx context.Context, messages []*Message) error {
+	return q.publish(func(p Producer) error {
+		return p.PublishBatch(ctx, messages)
+	})
+}
+
+// Subscribe 订阅主题，同时在 Redis 和内存队列上注册处理器
+func (q *FallbackQueue) Subscribe(ctx context.Context, topic string, handler Handler, opts ...SubscribeOption) error {
+	if q.primary != nil {
+		if err := q.primary.Subscribe(ctx, topic, handler, opts...); err != nil {
+			return err
+		}
+	}
+	return q.fallback.Subscribe(ctx, topic, handler, opts...)
+}
+
+// Unsubscribe 取消订阅
+func (q *FallbackQueue) Unsubscribe(topic string) error {
+	if q.primary != nil {
+		if err := q.primary.Unsubscribe(topic); err != nil {
+			return err
+		}
+	}
+	return q.fallback.Unsubscribe(topic)
+}
+
+// Start 启动消费者
+func (q *FallbackQueue) Start(ctx context.Context) error {
+	if q.primary != nil {
+		if err := q.primary.Start(ctx); err != nil {
+			return err
+		}
+	}
+	return q.fallback.Start(ctx)
+}
+
+// Stop 停止消费者
+func (q *FallbackQueue) Stop() error {
+	if q.primary != nil {
+		if err := q.primary.Stop(); err != nil {
+			return err
+		}
+	}
+	return q.fallback.Stop()
+}
+
+// Close 关闭队列
+func (q *FallbackQueue) Close() error {
+	return q.Stop()
+}
+
+// Health 获取队列健康状态
+func (q *FallbackQueue) Health(ctx context.Context) map[string]interface{} {
+	health := map[string]interface{}{
+		"status":   "healthy",
+		"degraded": q.Degraded(),
+		"memory":   q.fallback.Health(ctx),
+	}
+	if q.Degraded() {
+		health["status"] = "degraded"
+	} else {
+		health["redis"] = q.primary.Health(ctx)
+	}
+	return health
+}
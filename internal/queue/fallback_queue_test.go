@@ -0,0 +1,34 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackQueue_DegradedUsesMemory(t *testing.T) {
+	q := NewFallbackQueue(nil, nil, nil)
+	defer q.Close()
+
+	received := make(chan *Message, 1)
+	require.NoError(t, q.Subscribe(context.Background(), "alerts", func(ctx context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	}))
+	require.NoError(t, q.Start(context.Background()))
+	require.NoError(t, q.Publish(context.Background(), "alerts", []byte("payload")))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, []byte("payload"), msg.Payload)
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+
+	health := q.Health(context.Background())
+	assert.Equal(t, "degraded", health["status"])
+	assert.Equal(t, true, health["degraded"])
+}
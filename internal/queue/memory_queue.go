@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memoryQueueBuffer 每个主题的内存缓冲区大小
+const memoryQueueBuffer = 1024
+
+// MemoryQueue 进程内消息队列实现，用于 Redis 不可用时的降级
+// 消息只在当前实例内投递，不持久化，进程退出时未处理的消息丢失
+type MemoryQueue struct {
+	logger      *zap.Logger
+	topics      map[string]chan *Message
+	subscribers map[string]*subscriber
+	mu          sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	running     bool
+}
+
+// NewMemoryQueue 创建内存消息队列
+func NewMemoryQueue(logger *zap.Logger) *MemoryQueue {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &MemoryQueue{
+		logger:      logger,
+		topics:      make(map[string]chan *Message),
+		subscribers: make(map[string]*subscriber),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// topic 获取主题的缓冲通道，不存在时创建
+func (q *MemoryQueue) topic(name string) chan *Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ch, ok := q.topics[name]
+	if !ok {
+		ch = make(chan *Message, memoryQueueBuffer)
+		q.topics[name] = ch
+	}
+	return ch
+}
+
+// Publish 发布消息
+func (q *MemoryQueue) Publish(ctx context.Context, topic string, payload []byte, opts ...PublishOption) error {
+	return q.PublishWithDelay(ctx, topic, payload, 0, opts...)
+}
+
+// PublishWithDelay 延迟发布消息
+func (q *MemoryQueue) PublishWithDelay(ctx context.Context, topic string, payload []byte, delay time.Duration, opts ...PublishOption) error {
+	options := applyPublishOptions(opts...)
+
+	msg := &Message{
+		ID:        uuid.New().String(),
+		Topic:     topic,
+		Payload:   payload,
+		Headers:   options.Headers,
+		Metadata:  options.Metadata,
+		MaxRetry:  options.MaxRetry,
+		Delay:     delay,
+		CreatedAt: time.Now(),
+	}
+	if delay > 0 {
+		scheduledAt := msg.CreatedAt.Add(delay)
+		msg.ScheduledAt = &scheduledAt
+	}
+
+	return q.enqueue(ctx, msg)
+}
+
+// PublishBatch 批量发布消息
+func (q *MemoryQueue) PublishBatch(ctx context.Context, messages []*Message) error {
+	for _, msg := range messages {
+		if msg.ID == "" {
+			msg.ID = uuid.New().String()
+		}
+		if msg.CreatedAt.IsZero() {
+			msg.CreatedAt = time.Now()
+		}
+		if err := q.enqueue(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue 投递消息，延迟消息在到期后投递，缓冲区满时返回错误
+func (q *MemoryQueue) enqueue(ctx context.Context, msg *Message) error {
+	ch := q.topic(msg.Topic)
+
+	if msg.ScheduledAt != nil {
+		if wait := time.Until(*msg.ScheduledAt); wait > 0 {
+			q.wg.Add(1)
+			go func() {
+				defer q.wg.Done()
+				timer := time.NewTimer(wait)
+				defer timer.Stop()
+
+				select {
+				case <-q.ctx.Done():
+				case <-timer.C:
+					if err := q.enqueue(q.ctx, msg); err != nil {
+						q.logger.Error("Failed to deliver delayed message",
+							zap.String("topic", msg.Topic), zap.String("message_id", msg.ID), zap.Error(err))
+					}
+				}
+			}()
+			return nil
+		}
+	}
+
+	select {
+	case ch <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return fmt.Errorf("memory queue for topic %s is full", msg.Topic)
+	}
+}
+
+// Subscribe 订阅主题
+func (q *MemoryQueue) Subscribe(ctx context.Context, topic string, handler Handler, opts ...SubscribeOption) error {
+	options := applySubscribeOptions(opts...)
+	ch := q.topic(topic)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.subscribers[topic]; exists {
+		return fmt.Errorf("topic %s already subscribed", topic)
+	}
+
+	subCtx, cancel := context.WithCancel(q.ctx)
+	sub := &subscriber{
+		topic:   topic,
+		handler: handler,
+		options: options,
+		cancel:  cancel,
+	}
+	q.subscribers[topic] = sub
+
+	for i := 0; i < options.Concurrency; i++ {
+		q.wg.Add(1)
+		go q.consume(subCtx, sub, ch)
+	}
+
+	return nil
+}
+
+// Unsubscribe 取消订阅
+func (q *MemoryQueue) Unsubscribe(topic string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sub, exists := q.subscribers[topic]
+	if !exists {
+		return fmt.Errorf("topic %s not subscribed", topic)
+	}
+
+	sub.cancel()
+	delete(q.subscribers, topic)
+	return nil
+}
+
+// Start 启动消费者
+func (q *MemoryQueue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running = true
+	return nil
+}
+
+// Stop 停止消费者
+func (q *MemoryQueue) Stop() error {
+	q.mu.Lock()
+	q.running = false
+	q.mu.Unlock()
+
+	q.cancel()
+	q.wg.Wait()
+	return nil
+}
+
+// Close 关闭队列
+func (q *MemoryQueue) Close() error {
+	return q.Stop()
+}
+
+// Health 获取队列健康状态
+func (q *MemoryQueue) Health(ctx context.Context) map[string]interface{} {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	pending := make(map[string]int, len(q.topics))
+	for name, ch := range q.topics {
+		pending[name] = len(ch)
+	}
+
+	return map[string]interface{}{
+		"status":           "healthy",
+		"backend":          "memory",
+		"running":          q.running,
+		"subscriber_count": len(q.subscribers),
+		"pending":          pending,
+	}
+}
+
+// consume 消费主题消息，处理失败时按重试次数重新投递
+func (q *MemoryQueue) consume(ctx context.Context, sub *subscriber, ch chan *Message) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-ch:
+			msgCtx := ctx
+			var cancel context.CancelFunc
+			if sub.options.AckTimeout > 0 {
+				msgCtx, cancel = context.WithTimeout(ctx, sub.options.AckTimeout)
+			}
+			err := sub.handler(msgCtx, msg)
+			if cancel != nil {
+				cancel()
+			}
+
+			if err == nil {
+				continue
+			}
+
+			q.logger.Error("Message handler failed",
+				zap.String("topic", msg.Topic), zap.String("message_id", msg.ID), zap.Error(err))
+			if msg.Retry < msg.MaxRetry {
+				msg.Retry++
+				scheduledAt := time.Now().Add(time.Duration(msg.Retry) * sub.options.RetryDelay)
+				msg.ScheduledAt = &scheduledAt
+				if err := q.enqueue(ctx, msg); err != nil {
+					q.logger.Error("Failed to schedule retry message",
+						zap.String("topic", msg.Topic), zap.String("message_id", msg.ID), zap.Error(err))
+				}
+			} else {
+				q.logger.Error("Message exceeded max retry count, dropped",
+					zap.String("topic", msg.Topic), zap.String("message_id", msg.ID), zap.Int("retry_count", msg.Retry))
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 可用性探测默认参数
+const (
+	DefaultProbeInterval = 10 * time.Second
+	DefaultProbeTimeout  = 2 * time.Second
+)
+
+// Availability 跟踪 Redis 可用性
+// 后台定期探测连接，不可用时依赖方切换到内存实现，恢复后自动切回 Redis
+type Availability struct {
+	ping     func(ctx context.Context) error
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	available bool
+	lastErr   error
+	changedAt time.Time
+	listeners []func(available bool)
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewAvailability 创建可用性探测器，ping 为对 Redis 的探测函数
+// 创建时同步探测一次，确定初始状态，初次探测失败时同样记录降级日志
+func NewAvailability(ping func(ctx context.Context) error, interval time.Duration, logger *zap.Logger) *Availability {
+	if interval <= 0 {
+		interval = DefaultProbeInterval
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	a := &Availability{
+		ping:      ping,
+		interval:  interval,
+		timeout:   DefaultProbeTimeout,
+		logger:    logger,
+		available: true,
+		changedAt: time.Now(),
+		stop:      make(chan struct{}),
+	}
+	a.Probe(context.Background())
+
+	return a
+}
+
+// Available 返回 Redis 当前是否可用，未配置 Redis（nil）时返回 false
+func (a *Availability) Available() bool {
+	if a == nil {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.available
+}
+
+// Configured 返回是否配置了 Redis
+func (a *Availability) Configured() bool {
+	return a != nil
+}
+
+// OnChange 注册可用性变化回调
+func (a *Availability) OnChange(fn func(available bool)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.listeners = append(a.listeners, fn)
+}
+
+// Probe 立即探测一次并更新状态
+func (a *Availability) Probe(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	err := a.ping(ctx)
+	a.set(err == nil, err)
+	return err == nil
+}
+
+// ReportFailure 由依赖方在 Redis 操作失败时调用，立即进入降级模式，等待后台探测恢复
+func (a *Availability) ReportFailure(err error) {
+	if a == nil || err == nil {
+		return
+	}
+	a.set(false, err)
+}
+
+// Start 启动后台探测，ctx 取消或调用 Stop 后退出
+func (a *Availability) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.stop:
+				return
+			case <-ticker.C:
+				a.Probe(ctx)
+			}
+		}
+	}()
+}
+
+// Stop 停止后台探测
+func (a *Availability) Stop() {
+	a.stopOnce.Do(func() { close(a.stop) })
+}
+
+// Status 返回用于健康检查的状态信息
+func (a *Availability) Status() map[string]interface{} {
+	if a == nil {
+		return map[string]interface{}{"status": "disabled"}
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	status := map[string]interface{}{
+		"status": "up",
+		"since":  a.changedAt,
+	}
+	if !a.available {
+		status["status"] = "down"
+		if a.lastErr != nil {
+			status["error"] = a.lastErr.Error()
+		}
+	}
+	return status
+}
+
+// set 更新状态，状态变化时记录日志并通知回调
+func (a *Availability) set(available bool, err error) {
+	a.mu.Lock()
+	changed := a.available != available
+	a.available = available
+	a.lastErr = err
+	if changed {
+		a.changedAt = time.Now()
+	}
+	listeners := a.listeners
+	a.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if available {
+		a.logger.Info("Redis connection restored, leaving degraded mode")
+	} else {
+		a.logger.Warn("Redis unavailable, running in degraded mode", zap.Error(err))
+	}
+	for _, fn := range listeners {
+		fn(available)
+	}
+}
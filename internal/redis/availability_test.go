@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailability(t *testing.T) {
+	var pingErr error
+	a := NewAvailability(func(ctx context.Context) error { return pingErr }, 0, nil)
+	assert.True(t, a.Available())
+	assert.Equal(t, "up", a.Status()["status"])
+
+	var changes []bool
+	a.OnChange(func(available bool) { changes = append(changes, available) })
+
+	a.ReportFailure(errors.New("connection refused"))
+	assert.False(t, a.Available())
+	assert.Equal(t, "down", a.Status()["status"])
+	assert.Equal(t, "connection refused", a.Status()["error"])
+
+	pingErr = errors.New("still down")
+	assert.False(t, a.Probe(context.Background()))
+
+	pingErr = nil
+	assert.True(t, a.Probe(context.Background()))
+	assert.Equal(t, []bool{false, true}, changes)
+}
+
+func TestAvailability_Nil(t *testing.T) {
+	var a *Availability
+	assert.False(t, a.Configured())
+	assert.False(t, a.Available())
+	assert.Equal(t, "disabled", a.Status()["status"])
+	a.ReportFailure(errors.New("ignored"))
+}
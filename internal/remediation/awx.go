@@ -0,0 +1,158 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+)
+
+// AWXExecutor 启动 Ansible Tower/AWX 作业模板的修复动作执行器
+// 目标为作业模板ID，参数 extra_vars 与告警信息（pulse_alert）一起传给作业
+type AWXExecutor struct {
+	baseURL      string
+	token        string
+	pollInterval time.Duration
+	client       *http.Client
+}
+
+// NewAWXExecutor 创建 AWX 修复动作执行器
+func NewAWXExecutor(baseURL, token string, pollInterval time.Duration, client *http.Client) *AWXExecutor {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &AWXExecutor{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		token:        token,
+		pollInterval: pollInterval,
+		client:       client,
+	}
+}
+
+// Type 执行器处理的动作类型
+func (e *AWXExecutor) Type() string {
+	return models.RemediationActionAWX
+}
+
+// awxJob AWX 作业状态
+type awxJob struct {
+	ID     int    `json:"id"`
+	Job    int    `json:"job"`
+	Status string `json:"status"`
+	Failed bool   `json:"failed"`
+}
+
+// Execute 启动作业模板并等待作业结束，作业输出作为执行日志
+func (e *AWXExecutor) Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error) {
+	extraVars := make(map[string]interface{})
+	if vars, ok := action.Parameters["extra_vars"].(map[string]interface{}); ok {
+		for key, value := range vars {
+			extraVars[key] = value
+		}
+	}
+	extraVars["pulse_alert"] = alertVars(alert)
+
+	payload, err := json.Marshal(map[string]interface{}{"extra_vars": extraVars})
+	if err != nil {
+		return nil, fmt.Errorf("序列化AWX作业参数失败: %w", err)
+	}
+
+	var launched awxJob
+	launchURL := fmt.Sprintf("%s/api/v2/job_templates/%s/launch/", e.baseURL, action.Target)
+	if err := e.do(ctx, http.MethodPost, launchURL, payload, &launched); err != nil {
+		return nil, fmt.Errorf("启动AWX作业模板失败: %w", err)
+	}
+	jobID := launched.Job
+	if jobID == 0 {
+		jobID = launched.ID
+	}
+
+	job, err := e.wait(ctx, jobID)
+	if err != nil {
+		return &models.RemediationResult{Output: fmt.Sprintf("AWX job %d", jobID)}, err
+	}
+
+	output := fmt.Sprintf("AWX job %d %s\n%s", jobID, job.Status, e.stdout(ctx, jobID))
+	result := &models.RemediationResult{Output: truncate(output)}
+	if job.Status != "successful" {
+		return result, fmt.Errorf("AWX作业 %d 执行失败: %s", jobID, job.Status)
+	}
+	return result, nil
+}
+
+// wait 轮询作业状态直到作业结束
+func (e *AWXExecutor) wait(ctx context.Context, jobID int) (*awxJob, error) {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	jobURL := fmt.Sprintf("%s/api/v2/jobs/%d/", e.baseURL, jobID)
+	for {
+		var job awxJob
+		if err := e.do(ctx, http.MethodGet, jobURL, nil, &job); err != nil {
+			return nil, fmt.Errorf("获取AWX作业状态失败: %w", err)
+		}
+		switch job.Status {
+		case "successful", "failed", "error", "canceled":
+			return &job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("等待AWX作业 %d 结束超时: %w", jobID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// stdout 获取作业输出，失败时返回空字符串
+func (e *AWXExecutor) stdout(ctx context.Context, jobID int) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/api/v2/jobs/%d/stdout/?format=txt", e.baseURL, jobID), nil)
+	if err != nil {
+		return ""
+	}
+	e.authorize(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputSize+1))
+	return string(body)
+}
+
+// do 发送 AWX API 请求并解析 JSON 响应
+func (e *AWXExecutor) do(ctx context.Context, method, url string, payload []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	e.authorize(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("AWX返回错误状态 %d: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (e *AWXExecutor) authorize(req *http.Request) {
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+}
@@ -0,0 +1,81 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"pulse/internal/models"
+)
+
+// HTTPExecutor 调用 HTTP 接口的修复动作执行器
+// 参数: method（默认 POST）、headers、body（支持模板，默认为告警信息 JSON）
+type HTTPExecutor struct {
+	client *http.Client
+}
+
+// NewHTTPExecutor 创建 HTTP 修复动作执行器
+func NewHTTPExecutor(client *http.Client) *HTTPExecutor {
+	return &HTTPExecutor{client: client}
+}
+
+// Type 执行器处理的动作类型
+func (e *HTTPExecutor) Type() string {
+	return models.RemediationActionHTTP
+}
+
+// Execute 发送 HTTP 请求，2xx 视为成功
+func (e *HTTPExecutor) Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error) {
+	target, err := render(action.Target, alert)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(action.StringParameter("method"))
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body string
+	if tmpl := action.StringParameter("body"); tmpl != "" {
+		if body, err = render(tmpl, alert); err != nil {
+			return nil, err
+		}
+	} else if method != http.MethodGet {
+		data, err := json.Marshal(map[string]interface{}{"alert": alertVars(alert)})
+		if err != nil {
+			return nil, fmt.Errorf("序列化告警信息失败: %w", err)
+		}
+		body = string(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if headers, ok := action.Parameters["headers"].(map[string]interface{}); ok {
+		for key, value := range headers {
+			if s, ok := value.(string); ok {
+				req.Header.Set(key, s)
+			}
+		}
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputSize+1))
+	output := truncate(fmt.Sprintf("%s %s -> %d\n%s", method, target, resp.StatusCode, respBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &models.RemediationResult{Output: output}, fmt.Errorf("HTTP接口返回错误状态 %d", resp.StatusCode)
+	}
+
+	return &models.RemediationResult{Output: output}, nil
+}
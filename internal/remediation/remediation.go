@@ -0,0 +1,100 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+// maxOutputSize 执行日志保存的最大字节数，超出部分截断
+const maxOutputSize = 64 << 10
+
+// Executor 修复动作执行器接口
+type Executor interface {
+	// Type 执行器处理的动作类型
+	Type() string
+	// Execute 针对告警执行修复动作，返回执行日志
+	Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error)
+}
+
+// Runner 按动作类型分发到对应的执行器
+type Runner struct {
+	executors map[string]Executor
+}
+
+// NewRunner 创建执行器集合
+func NewRunner(executors ...Executor) *Runner {
+	runner := &Runner{executors: make(map[string]Executor)}
+	for _, executor := range executors {
+		runner.executors[executor.Type()] = executor
+	}
+	return runner
+}
+
+// New 根据配置创建执行器集合，HTTP 执行器始终可用，AWX 和 Salt 在配置了地址时可用
+func New(cfg config.RemediationConfig) *Runner {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	executors := []Executor{NewHTTPExecutor(client)}
+	if cfg.AWXURL != "" {
+		executors = append(executors, NewAWXExecutor(cfg.AWXURL, cfg.AWXToken, cfg.AWXPollInterval, client))
+	}
+	if cfg.SaltAPIURL != "" {
+		executors = append(executors, NewSaltExecutor(cfg.SaltAPIURL, cfg.SaltUsername, cfg.SaltPassword, cfg.SaltEauth, client))
+	}
+	return NewRunner(executors...)
+}
+
+// Execute 执行修复动作
+func (r *Runner) Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error) {
+	executor, ok := r.executors[action.Type]
+	if !ok {
+		return nil, fmt.Errorf("未配置 %s 类型的修复动作执行器", action.Type)
+	}
+	return executor.Execute(ctx, action, alert)
+}
+
+// templateData 修复动作参数模板可引用的数据
+type templateData struct {
+	Alert  *models.Alert
+	Labels map[string]string
+}
+
+// render 使用告警数据渲染参数模板，例如 {{.Labels.instance}}
+func render(text string, alert *models.Alert) (string, error) {
+	tmpl, err := template.New("action").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析参数模板失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData{Alert: alert, Labels: alert.Labels}); err != nil {
+		return "", fmt.Errorf("渲染参数模板失败: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// truncate 截断过长的执行日志
+func truncate(output string) string {
+	if len(output) <= maxOutputSize {
+		return output
+	}
+	return output[:maxOutputSize] + "\n...(输出已截断)"
+}
+
+// alertVars 传递给外部自动化平台的告警信息
+func alertVars(alert *models.Alert) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          alert.ID,
+		"name":        alert.Name,
+		"severity":    alert.Severity,
+		"status":      alert.Status,
+		"labels":      alert.Labels,
+		"fingerprint": alert.Fingerprint,
+	}
+}
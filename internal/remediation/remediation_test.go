@@ -0,0 +1,138 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func testAlert() *models.Alert {
+	return &models.Alert{
+		ID:       "a1",
+		Name:     "disk_full",
+		Severity: models.AlertSeverityCritical,
+		Labels:   map[string]string{"instance": "web-01"},
+	}
+}
+
+func TestHTTPExecutor(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("cleaned"))
+	}))
+	defer server.Close()
+
+	runner := NewRunner(NewHTTPExecutor(server.Client()))
+	action := models.RuleAction{
+		Type:   models.RemediationActionHTTP,
+		Target: server.URL + "/cleanup",
+		Parameters: map[string]interface{}{
+			"headers": map[string]interface{}{"X-Token": "secret"},
+			"body":    `{"host":"{{.Labels.instance}}"}`,
+		},
+	}
+
+	result, err := runner.Execute(context.Background(), action, testAlert())
+	require.NoError(t, err)
+	assert.Equal(t, `{"host":"web-01"}`, body)
+	assert.Contains(t, result.Output, "cleaned")
+
+	action.Target = server.URL + "/fail"
+	result, err = runner.Execute(context.Background(), action, testAlert())
+	assert.Error(t, err)
+	assert.Contains(t, result.Output, "502")
+}
+
+func TestAWXExecutor(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v2/job_templates/42/launch/":
+			var payload map[string]map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "nginx", payload["extra_vars"]["service"])
+			assert.NotNil(t, payload["extra_vars"]["pulse_alert"])
+			w.Write([]byte(`{"job": 7}`))
+		case "/api/v2/jobs/7/":
+			if polls.Add(1) < 2 {
+				w.Write([]byte(`{"id": 7, "status": "running"}`))
+				return
+			}
+			w.Write([]byte(`{"id": 7, "status": "successful"}`))
+		case "/api/v2/jobs/7/stdout/":
+			w.Write([]byte("PLAY RECAP ok=3"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewAWXExecutor(server.URL, "token", time.Millisecond, server.Client())
+	result, err := executor.Execute(context.Background(), models.RuleAction{
+		Type:       models.RemediationActionAWX,
+		Target:     "42",
+		Parameters: map[string]interface{}{"extra_vars": map[string]interface{}{"service": "nginx"}},
+	}, testAlert())
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "AWX job 7 successful")
+	assert.Contains(t, result.Output, "PLAY RECAP")
+}
+
+func TestSaltExecutor(t *testing.T) {
+	var lowstate []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/run", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&lowstate))
+		if lowstate[0]["tgt"] == "missing" {
+			w.Write([]byte(`{"return": [{}]}`))
+			return
+		}
+		w.Write([]byte(`{"return": [{"web-01": true}]}`))
+	}))
+	defer server.Close()
+
+	executor := NewSaltExecutor(server.URL, "pulse", "pw", "pam", server.Client())
+	action := models.RuleAction{
+		Type:   models.RemediationActionSalt,
+		Target: "{{.Labels.instance}}",
+		Parameters: map[string]interface{}{
+			"function": "service.restart",
+			"args":     []interface{}{"nginx"},
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), action, testAlert())
+	require.NoError(t, err)
+	assert.Equal(t, "web-01", lowstate[0]["tgt"])
+	assert.Equal(t, "service.restart", lowstate[0]["fun"])
+	assert.Equal(t, "pam", lowstate[0]["eauth"])
+	assert.Contains(t, result.Output, "web-01")
+
+	action.Target = "missing"
+	_, err = executor.Execute(context.Background(), action, testAlert())
+	assert.Error(t, err)
+}
+
+func TestRunner_UnconfiguredExecutor(t *testing.T) {
+	runner := NewRunner()
+	_, err := runner.Execute(context.Background(), models.RuleAction{Type: models.RemediationActionAWX}, testAlert())
+	assert.ErrorContains(t, err, "未配置")
+}
@@ -0,0 +1,109 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"pulse/internal/models"
+)
+
+// SaltExecutor 通过 salt-api 执行 SaltStack 命令的修复动作执行器
+// 目标为 minion 匹配表达式（支持模板），参数: function（必填）、args（支持模板）、tgt_type（默认 glob）
+type SaltExecutor struct {
+	baseURL  string
+	username string
+	password string
+	eauth    string
+	client   *http.Client
+}
+
+// NewSaltExecutor 创建 Salt 修复动作执行器
+func NewSaltExecutor(baseURL, username, password, eauth string, client *http.Client) *SaltExecutor {
+	return &SaltExecutor{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		eauth:    eauth,
+		client:   client,
+	}
+}
+
+// Type 执行器处理的动作类型
+func (e *SaltExecutor) Type() string {
+	return models.RemediationActionSalt
+}
+
+// Execute 调用 salt-api /run 执行命令，没有 minion 返回结果时视为失败
+func (e *SaltExecutor) Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error) {
+	target, err := render(action.Target, alert)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0)
+	if values, ok := action.Parameters["args"].([]interface{}); ok {
+		for _, value := range values {
+			arg, err := render(fmt.Sprint(value), alert)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+	}
+
+	targetType := action.StringParameter("tgt_type")
+	if targetType == "" {
+		targetType = "glob"
+	}
+
+	payload, err := json.Marshal([]map[string]interface{}{{
+		"client":   "local",
+		"tgt":      target,
+		"tgt_type": targetType,
+		"fun":      action.StringParameter("function"),
+		"arg":      args,
+		"username": e.username,
+		"password": e.password,
+		"eauth":    e.eauth,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("序列化Salt命令失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/run", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建Salt请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求salt-api失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputSize+1))
+	if resp.StatusCode != http.StatusOK {
+		return &models.RemediationResult{Output: truncate(string(body))},
+			fmt.Errorf("salt-api返回错误状态 %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Return []map[string]interface{} `json:"return"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return &models.RemediationResult{Output: truncate(string(body))}, fmt.Errorf("解析salt-api响应失败: %w", err)
+	}
+
+	output := truncate(string(body))
+	if len(result.Return) == 0 || len(result.Return[0]) == 0 {
+		return &models.RemediationResult{Output: output}, fmt.Errorf("目标 %s 没有 minion 返回结果", target)
+	}
+	return &models.RemediationResult{Output: output}, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// alertActionLinkRepository 告警操作链接使用记录仓储实现
+type alertActionLinkRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAlertActionLinkRepository 创建告警操作链接使用记录仓储实例
+func NewAlertActionLinkRepository(db *sqlx.DB) AlertActionLinkRepository {
+	return &alertActionLinkRepository{db: db}
+}
+
+// NewAlertActionLinkRepositoryWithTx 创建带事务的告警操作链接使用记录仓储实例
+func NewAlertActionLinkRepositoryWithTx(tx *sqlx.Tx) AlertActionLinkRepository {
+	return &alertActionLinkRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *alertActionLinkRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Claim 记录链接使用，令牌已使用时返回 ErrActionLinkUsed，多实例并发使用同一链接时只有一个成功
+func (r *alertActionLinkRepository) Claim(ctx context.Context, use *models.AlertActionLinkUse) error {
+	use.UsedAt = time.Now()
+
+	query := `
+		INSERT INTO alert_action_link_uses (token_id, alert_id, user_id, action, used_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query, use.TokenID, use.AlertID, use.UserID, use.Action, use.UsedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrActionLinkUsed
+		}
+		return fmt.Errorf("记录操作链接使用失败: %w", err)
+	}
+
+	return nil
+}
+
+// Release 删除链接使用记录，操作执行失败时调用，链接可以再次使用
+func (r *alertActionLinkRepository) Release(ctx context.Context, tokenID string) error {
+	if _, err := r.getExecutor().ExecContext(ctx, `DELETE FROM alert_action_link_uses WHERE token_id = $1`, tokenID); err != nil {
+		return fmt.Errorf("删除操作链接使用记录失败: %w", err)
+	}
+	return nil
+}
+
+// IsUsed 检查链接是否已使用
+func (r *alertActionLinkRepository) IsUsed(ctx context.Context, tokenID string) (bool, error) {
+	var exists int
+	err := r.getExecutor().QueryRowxContext(ctx, `SELECT 1 FROM alert_action_link_uses WHERE token_id = $1`, tokenID).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("查询操作链接使用记录失败: %w", err)
+	}
+	return true, nil
+}
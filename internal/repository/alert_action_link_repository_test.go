@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestAlertActionLinkRepository_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAlertActionLinkRepository(sqlx.NewDb(db, "postgres"))
+	use := &models.AlertActionLinkUse{TokenID: "t1", AlertID: "a1", UserID: "u1", Action: "ack"}
+
+	mock.ExpectExec(`INSERT INTO alert_action_link_uses`).
+		WithArgs("t1", "a1", "u1", "ack", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Claim(context.Background(), use))
+	assert.False(t, use.UsedAt.IsZero())
+
+	// 同一令牌再次占用时返回已使用
+	mock.ExpectExec(`INSERT INTO alert_action_link_uses`).
+		WillReturnError(&pq.Error{Code: "23505"})
+	assert.ErrorIs(t, repo.Claim(context.Background(), use), models.ErrActionLinkUsed)
+
+	mock.ExpectQuery(`SELECT 1 FROM alert_action_link_uses WHERE token_id = \$1`).
+		WithArgs("t2").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+	used, err := repo.IsUsed(context.Background(), "t2")
+	require.NoError(t, err)
+	assert.False(t, used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,26 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"pulse/internal/models"
 )
 
 // alertRepository 告警仓储实现
 type alertRepository struct {
-	db *sqlx.DB
-	tx *sqlx.Tx
+	db     *sqlx.DB
+	tx     *sqlx.Tx
+	reader Reader
 }
 
 // NewAlertRepository 创建告警仓储实例
 func NewAlertRepository(db *sqlx.DB) AlertRepository {
+	return NewAlertRepositoryWithReader(db, db)
+}
+
+// NewAlertRepositoryWithReader 创建告警仓储实例，列表和统计查询使用 reader（通常为只读副本）
+func NewAlertRepositoryWithReader(db *sqlx.DB, reader Reader) AlertRepository {
 	return &alertRepository{
-		db: db,
+		db:     db,
+		reader: reader,
 	}
 }
 
@@ -42,6 +51,14 @@ func (r *alertRepository) getExecutor() sqlx.ExtContext {
 	return r.db
 }
 
+// readExecutor 获取只读查询执行器，事务内使用事务保证读到未提交的写入
+func (r *alertRepository) readExecutor() Reader {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.reader
+}
+
 // Create 创建告警
 func (r *alertRepository) Create(ctx context.Context, alert *models.Alert) error {
 	// 生成告警ID
@@ -59,6 +76,14 @@ func (r *alertRepository) Create(ctx context.Context, alert *models.Alert) error
 		alert.Status = models.AlertStatusFiring
 	}
 
+	// 首次触发
+	if alert.OccurrenceCount == 0 {
+		alert.OccurrenceCount = 1
+	}
+	if alert.LastSeenAt.IsZero() {
+		alert.LastSeenAt = now
+	}
+
 	// 序列化标签和注解
 	labelsJSON, err := json.Marshal(alert.Labels)
 	if err != nil {
@@ -75,12 +100,14 @@ func (r *alertRepository) Create(ctx context.Context, alert *models.Alert) error
 			id, rule_id, data_source_id, name, description, severity, status, source,
 			labels, annotations, value, threshold, expression, starts_at, ends_at,
 			last_eval_at, eval_count, fingerprint, generator_url,
+			occurrence_count, last_seen_at, triage_score, triage_assignee,
 			silence_id, acked_by, acked_at, resolved_by, resolved_at,
 			created_at, updated_at
 		) VALUES (
 			:id, :rule_id, :data_source_id, :name, :description, :severity, :status, :source,
 			:labels, :annotations, :value, :threshold, :expression, :starts_at, :ends_at,
 			:last_eval_at, :eval_count, :fingerprint, :generator_url,
+			:occurrence_count, :last_seen_at, :triage_score, :triage_assignee,
 			:silence_id, :acked_by, :acked_at, :resolved_by, :resolved_at,
 			:created_at, :updated_at
 		)`
@@ -148,7 +175,7 @@ func (r *alertRepository) CleanupExpired(ctx context.Context) (int64, error) {
 func (r *alertRepository) GetActiveCount(ctx context.Context) (int64, error) {
 	var count int64
 	query := `SELECT COUNT(*) FROM alerts WHERE status IN ('firing', 'pending') AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	err := r.readExecutor().GetContext(ctx, &count, query)
 	if err != nil {
 		return 0, fmt.Errorf("获取活跃告警数量失败: %w", err)
 	}
@@ -159,13 +186,70 @@ func (r *alertRepository) GetActiveCount(ctx context.Context) (int64, error) {
 func (r *alertRepository) GetCriticalCount(ctx context.Context) (int64, error) {
 	var count int64
 	query := `SELECT COUNT(*) FROM alerts WHERE severity = 'critical' AND status IN ('firing', 'pending') AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	err := r.readExecutor().GetContext(ctx, &count, query)
 	if err != nil {
 		return 0, fmt.Errorf("获取严重告警数量失败: %w", err)
 	}
 	return count, nil
 }
 
+// GetLabelCardinality 统计 since 之后创建的告警中各标签键的不同值数量
+func (r *alertRepository) GetLabelCardinality(ctx context.Context, since time.Time, limit int) ([]*models.LabelCardinalityStat, error) {
+	query := `
+		SELECT l.key, COUNT(DISTINCT l.value) AS distinct_values, COUNT(*) AS alerts
+		FROM alerts a, jsonb_each_text(a.labels) AS l(key, value)
+		WHERE a.deleted_at IS NULL AND a.created_at >= $1
+		GROUP BY l.key
+		ORDER BY distinct_values DESC, l.key
+		LIMIT $2`
+
+	var stats []*models.LabelCardinalityStat
+	if err := r.readExecutor().SelectContext(ctx, &stats, query, since, limit); err != nil {
+		return nil, fmt.Errorf("统计告警标签基数失败: %w", err)
+	}
+	return stats, nil
+}
+
+// ListLabelKeys 统计 since 之后创建的告警中出现的标签键及告警数，按前缀过滤，按告警数降序返回
+func (r *alertRepository) ListLabelKeys(ctx context.Context, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error) {
+	sqlQuery := `
+		SELECT l.key AS value, COUNT(*) AS count
+		FROM alerts a, jsonb_object_keys(a.labels) AS l(key)
+		WHERE a.deleted_at IS NULL AND a.created_at >= $1 AND l.key ILIKE $2
+		GROUP BY l.key
+		ORDER BY count DESC, l.key
+		LIMIT $3`
+
+	suggestions := []*models.LabelSuggestion{}
+	if err := r.readExecutor().SelectContext(ctx, &suggestions, sqlQuery, since, likePrefix(query.Prefix), query.Limit); err != nil {
+		return nil, fmt.Errorf("统计告警标签键失败: %w", err)
+	}
+	return suggestions, nil
+}
+
+// ListLabelValues 统计 since 之后创建的告警中标签键 key 的不同值及告警数，按前缀过滤，按告警数降序返回
+func (r *alertRepository) ListLabelValues(ctx context.Context, key string, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error) {
+	sqlQuery := `
+		SELECT a.labels->>$1 AS value, COUNT(*) AS count
+		FROM alerts a
+		WHERE a.deleted_at IS NULL AND a.created_at >= $2
+		  AND a.labels->>$1 IS NOT NULL AND a.labels->>$1 ILIKE $3
+		GROUP BY 1
+		ORDER BY count DESC, value
+		LIMIT $4`
+
+	suggestions := []*models.LabelSuggestion{}
+	if err := r.readExecutor().SelectContext(ctx, &suggestions, sqlQuery, key, since, likePrefix(query.Prefix), query.Limit); err != nil {
+		return nil, fmt.Errorf("统计告警标签值失败: %w", err)
+	}
+	return suggestions, nil
+}
+
+// likePrefix 将前缀转换为 LIKE 模式，转义其中的通配符
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
+
 // GetByID 根据ID获取告警
 func (r *alertRepository) GetByID(ctx context.Context, id string) (*models.Alert, error) {
 	var alert models.Alert
@@ -175,6 +259,7 @@ func (r *alertRepository) GetByID(ctx context.Context, id string) (*models.Alert
 		SELECT id, rule_id, data_source_id, name, description, severity, status, source,
 		       labels, annotations, value, threshold, expression, starts_at, ends_at,
 		       last_eval_at, eval_count, fingerprint, generator_url,
+		       occurrence_count, last_seen_at, triage_score, triage_assignee,
 		       silence_id, acked_by, acked_at, resolved_by, resolved_at,
 		       created_at, updated_at, deleted_at
 		FROM alerts 
@@ -186,12 +271,13 @@ func (r *alertRepository) GetByID(ctx context.Context, id string) (*models.Alert
 		&alert.Severity, &alert.Status, &alert.Source, &labelsJSON, &annotationsJSON,
 		&alert.Value, &alert.Threshold, &alert.Expression, &alert.StartsAt, &alert.EndsAt,
 		&alert.LastEvalAt, &alert.EvalCount, &alert.Fingerprint, &alert.GeneratorURL,
+		&alert.OccurrenceCount, &alert.LastSeenAt, &alert.TriageScore, &alert.TriageAssignee,
 		&alert.SilenceID, &alert.AckedBy, &alert.AckedAt, &alert.ResolvedBy, &alert.ResolvedAt,
 		&alert.CreatedAt, &alert.UpdatedAt, &alert.DeletedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("告警不存在")
+			return nil, models.ErrAlertNotFound
 		}
 		return nil, fmt.Errorf("获取告警失败: %w", err)
 	}
@@ -275,7 +361,7 @@ func (r *alertRepository) Update(ctx context.Context, alert *models.Alert) error
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
 	}
 
 	return nil
@@ -296,7 +382,7 @@ func (r *alertRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在")
+		return models.ErrAlertNotFound
 	}
 
 	return nil
@@ -322,7 +408,7 @@ func (r *alertRepository) SoftDelete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
 	}
 
 	return nil
@@ -345,7 +431,88 @@ func (r *alertRepository) List(ctx context.Context, filter *models.AlertFilter)
 		filter.PageSize = 100
 	}
 
-	// 构建查询条件
+	whereClause, args, err := alertListWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+	argIndex := len(args) + 1
+
+	// 获取总数
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM alerts %s", whereClause)
+	var total int64
+	err = r.readExecutor().GetContext(ctx, &total, countQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警总数失败: %w", err)
+	}
+
+	// 获取告警列表
+	offset := (filter.Page - 1) * filter.PageSize
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM alerts %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, alertListColumns, whereClause, alertListOrderBy(filter), argIndex, argIndex+1)
+
+	args = append(args, filter.PageSize, offset)
+
+	rows, err := r.readExecutor().QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*models.Alert
+	for rows.Next() {
+		alert, err := scanAlertListRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历告警数据失败: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+
+	return &models.AlertList{
+		Alerts:     alerts,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// alertListColumns 告警列表查询的列，与 scanAlertListRow 的扫描顺序一致
+const alertListColumns = `id, rule_id, data_source_id, name, description, severity, status, source,
+		       labels, annotations, value, threshold, expression, starts_at, ends_at,
+		       last_eval_at, eval_count, fingerprint, generator_url,
+		       occurrence_count, last_seen_at, triage_score, triage_assignee,
+		       silence_id, acked_by, acked_at, resolved_by, resolved_at,
+		       created_at, updated_at`
+
+// alertSortFields 告警列表可排序的字段
+var alertSortFields = map[string]bool{
+	"starts_at": true, "created_at": true, "updated_at": true, "last_seen_at": true,
+	"occurrence_count": true, "triage_score": true,
+}
+
+// alertListOrderBy 构建告警列表的排序，默认按开始时间降序；未评分告警的 triage_score 为空，始终排在最后
+func alertListOrderBy(filter *models.AlertFilter) string {
+	if filter.SortBy == nil || !alertSortFields[*filter.SortBy] {
+		return "starts_at DESC"
+	}
+	order := "DESC"
+	if filter.SortOrder != nil && *filter.SortOrder == "asc" {
+		order = "ASC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, starts_at DESC", *filter.SortBy, order)
+}
+
+// alertListWhere 构建告警列表的 WHERE 子句，参数占位符从 $1 开始
+func alertListWhere(filter *models.AlertFilter) (string, []interface{}, error) {
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
@@ -401,96 +568,117 @@ func (r *alertRepository) List(ctx context.Context, filter *models.AlertFilter)
 		argIndex++
 	}
 
-	// 处理标签过滤
+	// 处理标签过滤，使用 @> 包含查询以命中 labels 的 GIN 索引
 	if len(filter.Labels) > 0 {
-		for key, value := range filter.Labels {
-			conditions = append(conditions, fmt.Sprintf("labels ->> $%d = $%d", argIndex, argIndex+1))
-			args = append(args, key, value)
-			argIndex += 2
+		labels, err := json.Marshal(filter.Labels)
+		if err != nil {
+			return "", nil, fmt.Errorf("序列化标签过滤条件失败: %w", err)
+		}
+		conditions = append(conditions, labelsContainCondition(argIndex))
+		args = append(args, string(labels))
+		argIndex++
+	}
+
+	if filter.HideSnoozedFor != nil {
+		conditions = append(conditions, snoozedCondition(argIndex))
+		args = append(args, *filter.HideSnoozedFor)
+		argIndex++
+	}
+
+	// 处理过滤表达式
+	if filter.Query != nil && *filter.Query != "" {
+		where, queryArgs, err := compileFilterQuery(alertFilterSchema, *filter.Query, argIndex)
+		if err != nil {
+			return "", nil, err
 		}
+		conditions = append(conditions, where)
+		args = append(args, queryArgs...)
 	}
 
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
+	return whereClause, args, nil
+}
 
-	// 获取总数
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM alerts %s", whereClause)
-	var total int64
-	err := r.db.GetContext(ctx, &total, countQuery, args...)
+// scanAlertListRow 扫描 alertListColumns 对应的一行告警
+func scanAlertListRow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Alert, error) {
+	var alert models.Alert
+	var labelsJSON, annotationsJSON string
+
+	err := scanner.Scan(
+		&alert.ID, &alert.RuleID, &alert.DataSourceID, &alert.Name, &alert.Description,
+		&alert.Severity, &alert.Status, &alert.Source, &labelsJSON, &annotationsJSON,
+		&alert.Value, &alert.Threshold, &alert.Expression, &alert.StartsAt, &alert.EndsAt,
+		&alert.LastEvalAt, &alert.EvalCount, &alert.Fingerprint, &alert.GeneratorURL,
+		&alert.OccurrenceCount, &alert.LastSeenAt, &alert.TriageScore, &alert.TriageAssignee,
+		&alert.SilenceID, &alert.AckedBy, &alert.AckedAt, &alert.ResolvedBy, &alert.ResolvedAt,
+		&alert.CreatedAt, &alert.UpdatedAt,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("获取告警总数失败: %w", err)
+		return nil, fmt.Errorf("扫描告警数据失败: %w", err)
 	}
 
-	// 获取告警列表
-	offset := (filter.Page - 1) * filter.PageSize
-	listQuery := fmt.Sprintf(`
-		SELECT id, rule_id, data_source_id, name, description, severity, status, source,
-		       labels, annotations, value, threshold, expression, starts_at, ends_at,
-		       last_eval_at, eval_count, fingerprint, generator_url,
-		       silence_id, acked_by, acked_at, resolved_by, resolved_at,
-		       created_at, updated_at
-		FROM alerts %s
-		ORDER BY starts_at DESC
-		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+	// 反序列化标签和注解
+	if labelsJSON != "" {
+		if err := json.Unmarshal([]byte(labelsJSON), &alert.Labels); err != nil {
+			return nil, fmt.Errorf("反序列化标签失败: %w", err)
+		}
+	}
+	if annotationsJSON != "" {
+		if err := json.Unmarshal([]byte(annotationsJSON), &alert.Annotations); err != nil {
+			return nil, fmt.Errorf("反序列化注解失败: %w", err)
+		}
+	}
+	return &alert, nil
+}
 
-	args = append(args, filter.PageSize, offset)
+// ListByIDs 批量获取告警，不存在或已删除的告警不在结果中
+func (r *alertRepository) ListByIDs(ctx context.Context, ids []string) ([]*models.Alert, error) {
+	query := fmt.Sprintf("SELECT %s FROM alerts WHERE id = ANY($1) AND deleted_at IS NULL", alertListColumns)
 
-	rows, err := r.db.QueryContext(ctx, listQuery, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
-		return nil, fmt.Errorf("获取告警列表失败: %w", err)
+		return nil, fmt.Errorf("批量获取告警失败: %w", err)
 	}
 	defer rows.Close()
 
 	var alerts []*models.Alert
 	for rows.Next() {
-		var alert models.Alert
-		var labelsJSON, annotationsJSON string
-
-		err := rows.Scan(
-			&alert.ID, &alert.RuleID, &alert.DataSourceID, &alert.Name, &alert.Description,
-			&alert.Severity, &alert.Status, &alert.Source, &labelsJSON, &annotationsJSON,
-			&alert.Value, &alert.Threshold, &alert.Expression, &alert.StartsAt, &alert.EndsAt,
-			&alert.LastEvalAt, &alert.EvalCount, &alert.Fingerprint, &alert.GeneratorURL,
-			&alert.SilenceID, &alert.AckedBy, &alert.AckedAt, &alert.ResolvedBy, &alert.ResolvedAt,
-			&alert.CreatedAt, &alert.UpdatedAt,
-		)
+		alert, err := scanAlertListRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("扫描告警数据失败: %w", err)
+			return nil, err
 		}
-
-		// 反序列化标签和注解
-		if labelsJSON != "" {
-			err = json.Unmarshal([]byte(labelsJSON), &alert.Labels)
-			if err != nil {
-				return nil, fmt.Errorf("反序列化标签失败: %w", err)
-			}
-		}
-
-		if annotationsJSON != "" {
-			err = json.Unmarshal([]byte(annotationsJSON), &alert.Annotations)
-			if err != nil {
-				return nil, fmt.Errorf("反序列化注解失败: %w", err)
-			}
-		}
-
-		alerts = append(alerts, &alert)
+		alerts = append(alerts, alert)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("遍历告警数据失败: %w", err)
 	}
+	return alerts, nil
+}
 
-	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+// Stream 按列表的过滤条件和排序逐条读取全部告警，忽略分页参数，fn 返回错误时停止读取
+func (r *alertRepository) Stream(ctx context.Context, filter *models.AlertFilter, fn func(*models.Alert) error) error {
+	if filter == nil {
+		filter = &models.AlertFilter{}
+	}
+	whereClause, args, err := alertListWhere(filter)
+	if err != nil {
+		return err
+	}
 
-	return &models.AlertList{
-		Alerts:     alerts,
-		Total:      total,
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	query := fmt.Sprintf("SELECT %s FROM alerts %s ORDER BY %s", alertListColumns, whereClause, alertListOrderBy(filter))
+	return streamCursor(ctx, r.readExecutor(), query, args, func(rows *sqlx.Rows) error {
+		alert, err := scanAlertListRow(rows)
+		if err != nil {
+			return err
+		}
+		return fn(alert)
+	})
 }
 
 // Count 获取告警总数
@@ -546,11 +734,29 @@ func (r *alertRepository) Count(ctx context.Context, filter *models.AlertFilter)
 
 		// 处理标签过滤
 		if len(filter.Labels) > 0 {
-			for key, value := range filter.Labels {
-				conditions = append(conditions, fmt.Sprintf("labels ->> $%d = $%d", argIndex, argIndex+1))
-				args = append(args, key, value)
-				argIndex += 2
+			labels, err := json.Marshal(filter.Labels)
+			if err != nil {
+				return 0, fmt.Errorf("序列化标签过滤条件失败: %w", err)
+			}
+			conditions = append(conditions, labelsContainCondition(argIndex))
+			args = append(args, string(labels))
+			argIndex++
+		}
+
+		if filter.HideSnoozedFor != nil {
+			conditions = append(conditions, snoozedCondition(argIndex))
+			args = append(args, *filter.HideSnoozedFor)
+			argIndex++
+		}
+
+		if filter.Query != nil && *filter.Query != "" {
+			where, queryArgs, err := compileFilterQuery(alertFilterSchema, *filter.Query, argIndex)
+			if err != nil {
+				return 0, err
 			}
+			conditions = append(conditions, where)
+			args = append(args, queryArgs...)
+			argIndex += len(queryArgs)
 		}
 	}
 
@@ -561,7 +767,7 @@ func (r *alertRepository) Count(ctx context.Context, filter *models.AlertFilter)
 
 	query := fmt.Sprintf("SELECT COUNT(*) FROM alerts %s", whereClause)
 	var count int64
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := r.readExecutor().GetContext(ctx, &count, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("获取告警总数失败: %w", err)
 	}
@@ -569,6 +775,19 @@ func (r *alertRepository) Count(ctx context.Context, filter *models.AlertFilter)
 	return count, nil
 }
 
+// labelsContainCondition 告警标签包含 $argIndex 中全部键值对的查询条件，参数为 JSON 对象
+func labelsContainCondition(argIndex int) string {
+	return fmt.Sprintf("labels @> $%d::jsonb", argIndex)
+}
+
+// snoozedCondition 排除 $argIndex 用户暂停中的告警的查询条件
+func snoozedCondition(argIndex int) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM alert_snoozes s
+		WHERE s.user_id = $%d AND s.expires_at > NOW()
+		  AND (s.alert_id = alerts.id OR (s.alert_id IS NULL AND alerts.labels @> s.matchers)))`, argIndex)
+}
+
 // Exists 检查告警是否存在
 func (r *alertRepository) Exists(ctx context.Context, id string) (bool, error) {
 	var count int
@@ -589,6 +808,7 @@ func (r *alertRepository) GetByFingerprint(ctx context.Context, fingerprint stri
 		SELECT id, rule_id, data_source_id, name, description, severity, status, source,
 		       labels, annotations, value, threshold, expression, starts_at, ends_at,
 		       last_eval_at, eval_count, fingerprint, generator_url,
+		       occurrence_count, last_seen_at, triage_score, triage_assignee,
 		       silence_id, acked_by, acked_at, resolved_by, resolved_at,
 		       created_at, updated_at, deleted_at
 		FROM alerts 
@@ -600,12 +820,13 @@ func (r *alertRepository) GetByFingerprint(ctx context.Context, fingerprint stri
 		&alert.Severity, &alert.Status, &alert.Source, &labelsJSON, &annotationsJSON,
 		&alert.Value, &alert.Threshold, &alert.Expression, &alert.StartsAt, &alert.EndsAt,
 		&alert.LastEvalAt, &alert.EvalCount, &alert.Fingerprint, &alert.GeneratorURL,
+		&alert.OccurrenceCount, &alert.LastSeenAt, &alert.TriageScore, &alert.TriageAssignee,
 		&alert.SilenceID, &alert.AckedBy, &alert.AckedAt, &alert.ResolvedBy, &alert.ResolvedAt,
 		&alert.CreatedAt, &alert.UpdatedAt, &alert.DeletedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("告警不存在")
+			return nil, models.ErrAlertNotFound
 		}
 		return nil, fmt.Errorf("获取告警失败: %w", err)
 	}
@@ -628,6 +849,110 @@ func (r *alertRepository) GetByFingerprint(ctx context.Context, fingerprint stri
 	return &alert, nil
 }
 
+// GetOpenByFingerprint 获取指纹相同的未解决告警，有多个时返回最近创建的
+func (r *alertRepository) GetOpenByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM alerts
+		WHERE fingerprint = $1 AND status <> $2 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1`, alertListColumns)
+
+	alert, err := scanAlertListRow(r.getExecutor().QueryRowxContext(ctx, query, fingerprint, models.AlertStatusResolved))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAlertNotFound
+		}
+		return nil, err
+	}
+	return alert, nil
+}
+
+// RecordOccurrence 累加未解决告警的触发次数并更新最近触发时间、评估时间和当前值，value 为空时保留原值。
+// 首次重复触发或距上次采样超过 sampleInterval 时更新采样时间并返回 Sampled，并发触发时只有一次被采样
+func (r *alertRepository) RecordOccurrence(ctx context.Context, id string, at time.Time, value *float64, sampleInterval time.Duration) (*models.AlertOccurrence, error) {
+	query := `
+		UPDATE alerts SET
+			occurrence_count = occurrence_count + 1,
+			last_seen_at = GREATEST(last_seen_at, $2),
+			last_eval_at = $2,
+			eval_count = eval_count + 1,
+			value = COALESCE($3, value),
+			occurrence_sampled_at = CASE
+				WHEN occurrence_sampled_at IS NULL OR occurrence_sampled_at <= $2 - make_interval(secs => $4) THEN $2
+				ELSE occurrence_sampled_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status <> $5 AND deleted_at IS NULL
+		RETURNING id, occurrence_count, last_seen_at, occurrence_sampled_at = $2 AS sampled`
+
+	var occurrence models.AlertOccurrence
+	err := r.getExecutor().QueryRowxContext(ctx, query, id, at, value, sampleInterval.Seconds(), models.AlertStatusResolved).
+		StructScan(&occurrence)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAlertNotFound
+		}
+		return nil, fmt.Errorf("记录告警重复触发失败: %w", err)
+	}
+	return &occurrence, nil
+}
+
+// ListStale 获取最后评估时间早于 before 的未解决告警（触发中或已确认），按最后评估时间升序
+func (r *alertRepository) ListStale(ctx context.Context, before time.Time, offset, limit int) ([]*models.Alert, error) {
+	query := `
+		SELECT id, rule_id, data_source_id, name, description, severity, status, source,
+		       labels, annotations, value, threshold, expression, starts_at, ends_at,
+		       last_eval_at, eval_count, fingerprint, generator_url,
+		       silence_id, acked_by, acked_at, resolved_by, resolved_at,
+		       created_at, updated_at
+		FROM alerts
+		WHERE status IN ($1, $2) AND last_eval_at < $3 AND deleted_at IS NULL
+		ORDER BY last_eval_at, id
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query,
+		models.AlertStatusFiring, models.AlertStatusAcked, before, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("获取过期告警失败: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*models.Alert
+	for rows.Next() {
+		var alert models.Alert
+		var labelsJSON, annotationsJSON string
+
+		err := rows.Scan(
+			&alert.ID, &alert.RuleID, &alert.DataSourceID, &alert.Name, &alert.Description,
+			&alert.Severity, &alert.Status, &alert.Source, &labelsJSON, &annotationsJSON,
+			&alert.Value, &alert.Threshold, &alert.Expression, &alert.StartsAt, &alert.EndsAt,
+			&alert.LastEvalAt, &alert.EvalCount, &alert.Fingerprint, &alert.GeneratorURL,
+			&alert.SilenceID, &alert.AckedBy, &alert.AckedAt, &alert.ResolvedBy, &alert.ResolvedAt,
+			&alert.CreatedAt, &alert.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描告警数据失败: %w", err)
+		}
+
+		if labelsJSON != "" {
+			if err := json.Unmarshal([]byte(labelsJSON), &alert.Labels); err != nil {
+				return nil, fmt.Errorf("反序列化标签失败: %w", err)
+			}
+		}
+		if annotationsJSON != "" {
+			if err := json.Unmarshal([]byte(annotationsJSON), &alert.Annotations); err != nil {
+				return nil, fmt.Errorf("反序列化注解失败: %w", err)
+			}
+		}
+
+		alerts = append(alerts, &alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历告警数据失败: %w", err)
+	}
+
+	return alerts, nil
+}
+
 // Acknowledge 确认告警
 func (r *alertRepository) Acknowledge(ctx context.Context, id, userID string, comment *string) error {
 	now := time.Now()
@@ -650,7 +975,7 @@ func (r *alertRepository) Acknowledge(ctx context.Context, id, userID string, co
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
 	}
 
 	// 如果有评论，记录到历史中
@@ -684,7 +1009,7 @@ func (r *alertRepository) Resolve(ctx context.Context, id, userID string, commen
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
 	}
 
 	// 如果有评论，记录到历史中
@@ -705,7 +1030,7 @@ func (r *alertRepository) Silence(ctx context.Context, id, silenceID string, dur
 			updated_at = $3
 		WHERE id = $4 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, models.AlertStatusSilenced, silenceID, now, id)
+	result, err := r.getExecutor().ExecContext(ctx, query, models.AlertStatusSilenced, silenceID, now, id)
 	if err != nil {
 		return fmt.Errorf("静默告警失败: %w", err)
 	}
@@ -716,7 +1041,7 @@ func (r *alertRepository) Silence(ctx context.Context, id, silenceID string, dur
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
 	}
 
 	return nil
@@ -732,7 +1057,7 @@ func (r *alertRepository) Unsilence(ctx context.Context, id string) error {
 			updated_at = $2
 		WHERE id = $3 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, models.AlertStatusFiring, now, id)
+	result, err := r.getExecutor().ExecContext(ctx, query, models.AlertStatusFiring, now, id)
 	if err != nil {
 		return fmt.Errorf("取消静默告警失败: %w", err)
 	}
@@ -743,7 +1068,54 @@ func (r *alertRepository) Unsilence(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("告警不存在或已被删除")
+		return models.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// GetSilence 根据ID获取静默规则
+func (r *alertRepository) GetSilence(ctx context.Context, silenceID string) (*models.AlertSilence, error) {
+	var silence models.AlertSilence
+	query := `
+		SELECT id, name, comment, starts_at, ends_at, active, created_by, created_at
+		FROM alert_silences
+		WHERE id = $1`
+
+	err := r.readExecutor().GetContext(ctx, &silence, query, silenceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrAlertSilenceNotFound
+		}
+		return nil, fmt.Errorf("获取静默规则失败: %w", err)
+	}
+
+	return &silence, nil
+}
+
+// CreateSilence 创建静默规则
+func (r *alertRepository) CreateSilence(ctx context.Context, silence *models.AlertSilence) error {
+	if silence.ID == "" {
+		silence.ID = uuid.New().String()
+	}
+	silence.CreatedAt = time.Now()
+
+	matchers, err := json.Marshal(silence.Matchers)
+	if err != nil {
+		return fmt.Errorf("序列化静默匹配条件失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_silences (
+			id, name, comment, matchers, starts_at, ends_at, active, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		silence.ID, silence.Name, silence.Comment, matchers, silence.StartsAt, silence.EndsAt,
+		silence.Active, silence.CreatedBy, silence.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建静默规则失败: %w", err)
 	}
 
 	return nil
@@ -791,7 +1163,7 @@ func (r *alertRepository) GetStats(ctx context.Context, filter *models.AlertFilt
 	// 获取总数
 	totalQuery := fmt.Sprintf("SELECT COUNT(*) FROM alerts %s", whereClause)
 	var total int64
-	err := r.db.GetContext(ctx, &total, totalQuery, args...)
+	err := r.readExecutor().GetContext(ctx, &total, totalQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("获取告警总数失败: %w", err)
 	}
@@ -802,7 +1174,7 @@ func (r *alertRepository) GetStats(ctx context.Context, filter *models.AlertFilt
 		FROM alerts %s 
 		GROUP BY severity`, whereClause)
 
-	bySeverityRows, err := r.db.QueryContext(ctx, bySeverityQuery, args...)
+	bySeverityRows, err := r.readExecutor().QueryContext(ctx, bySeverityQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("按严重级别统计失败: %w", err)
 	}
@@ -825,7 +1197,7 @@ func (r *alertRepository) GetStats(ctx context.Context, filter *models.AlertFilt
 		FROM alerts %s 
 		GROUP BY status`, whereClause)
 
-	byStatusRows, err := r.db.QueryContext(ctx, byStatusQuery, args...)
+	byStatusRows, err := r.readExecutor().QueryContext(ctx, byStatusQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("按状态统计失败: %w", err)
 	}
@@ -848,7 +1220,7 @@ func (r *alertRepository) GetStats(ctx context.Context, filter *models.AlertFilt
 		FROM alerts %s 
 		GROUP BY source`, whereClause)
 
-	bySourceRows, err := r.db.QueryContext(ctx, bySourceQuery, args...)
+	bySourceRows, err := r.readExecutor().QueryContext(ctx, bySourceQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("按来源统计失败: %w", err)
 	}
@@ -905,7 +1277,7 @@ func (r *alertRepository) GetTrend(ctx context.Context, start, end time.Time, in
 		GROUP BY %s
 		ORDER BY timestamp`, timeGroup, whereClause, timeGroup)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("获取告警趋势失败: %w", err)
 	}
@@ -928,24 +1300,28 @@ func (r *alertRepository) GetTrend(ctx context.Context, start, end time.Time, in
 	return trend, nil
 }
 
-// BatchCreate 批量创建告警
+// alertInsertColumns 批量创建告警时写入的列
+var alertInsertColumns = []string{
+	"id", "rule_id", "data_source_id", "name", "description", "severity", "status", "source",
+	"labels", "annotations", "value", "threshold", "expression", "starts_at", "ends_at",
+	"last_eval_at", "eval_count", "fingerprint", "generator_url",
+	"silence_id", "acked_by", "acked_at", "resolved_by", "resolved_at",
+	"created_at", "updated_at",
+}
+
+// BatchCreate 批量创建告警，告警风暴时大量告警在一个事务中通过 COPY 或多行 VALUES 写入
 func (r *alertRepository) BatchCreate(ctx context.Context, alerts []*models.Alert) error {
 	if len(alerts) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("开始事务失败: %w", err)
-	}
-	defer tx.Rollback()
-
+	now := time.Now()
+	rows := make([][]interface{}, 0, len(alerts))
 	for _, alert := range alerts {
 		if alert.ID == "" {
 			alert.ID = uuid.New().String()
 		}
 
-		now := time.Now()
 		alert.CreatedAt = now
 		alert.UpdatedAt = now
 
@@ -964,32 +1340,23 @@ func (r *alertRepository) BatchCreate(ctx context.Context, alerts []*models.Aler
 			return fmt.Errorf("序列化注解失败: %w", err)
 		}
 
-		query := `
-			INSERT INTO alerts (
-				id, rule_id, data_source_id, name, description, severity, status, source,
-				labels, annotations, value, threshold, expression, starts_at, ends_at,
-				last_eval_at, eval_count, fingerprint, generator_url,
-				silence_id, acked_by, acked_at, resolved_by, resolved_at,
-				created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
-				$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26
-			)`
-
-		_, err = tx.ExecContext(ctx, query,
+		rows = append(rows, []interface{}{
 			alert.ID, alert.RuleID, alert.DataSourceID, alert.Name, alert.Description,
 			alert.Severity, alert.Status, alert.Source, string(labelsJSON), string(annotationsJSON),
 			alert.Value, alert.Threshold, alert.Expression, alert.StartsAt, alert.EndsAt,
 			alert.LastEvalAt, alert.EvalCount, alert.Fingerprint, alert.GeneratorURL,
 			alert.SilenceID, alert.AckedBy, alert.AckedAt, alert.ResolvedBy, alert.ResolvedAt,
 			alert.CreatedAt, alert.UpdatedAt,
-		)
-		if err != nil {
-			return fmt.Errorf("批量创建告警失败: %w", err)
-		}
+		})
 	}
 
-	return tx.Commit()
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		return bulkInsert(ctx, tx, "alerts", alertInsertColumns, rows)
+	})
+	if err != nil {
+		return fmt.Errorf("批量创建告警失败: %w", err)
+	}
+	return nil
 }
 
 // BatchUpdate 批量更新告警
@@ -1094,7 +1461,7 @@ func (r *alertRepository) BatchDelete(ctx context.Context, ids []string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("没有找到要删除的告警")
+		return models.ErrAlertNotFound
 	}
 
 	return nil
@@ -1102,17 +1469,43 @@ func (r *alertRepository) BatchDelete(ctx context.Context, ids []string) error {
 
 // GetHistory 获取告警历史记录
 func (r *alertRepository) GetHistory(ctx context.Context, alertID string) ([]*models.AlertHistory, error) {
-	var histories []*models.AlertHistory
 	query := `
 		SELECT id, alert_id, action, old_value, new_value, user_id, comment, created_at
 		FROM alert_histories 
 		WHERE alert_id = $1 
 		ORDER BY created_at DESC`
 
-	err := r.db.SelectContext(ctx, &histories, query, alertID)
+	rows, err := r.readExecutor().QueryContext(ctx, query, alertID)
 	if err != nil {
 		return nil, fmt.Errorf("获取告警历史记录失败: %w", err)
 	}
+	defer rows.Close()
+
+	histories := []*models.AlertHistory{}
+	for rows.Next() {
+		var history models.AlertHistory
+		var oldValueJSON, newValueJSON sql.NullString
+		if err := rows.Scan(&history.ID, &history.AlertID, &history.Action, &oldValueJSON, &newValueJSON,
+			&history.UserID, &history.Comment, &history.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描告警历史记录失败: %w", err)
+		}
+
+		// 旧值和新值为 JSON 对象，未记录时为 null
+		if oldValueJSON.Valid {
+			if err := json.Unmarshal([]byte(oldValueJSON.String), &history.OldValue); err != nil {
+				return nil, fmt.Errorf("反序列化旧值失败: %w", err)
+			}
+		}
+		if newValueJSON.Valid {
+			if err := json.Unmarshal([]byte(newValueJSON.String), &history.NewValue); err != nil {
+				return nil, fmt.Errorf("反序列化新值失败: %w", err)
+			}
+		}
+		histories = append(histories, &history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历告警历史记录失败: %w", err)
+	}
 
 	return histories, nil
 }
@@ -1203,7 +1596,7 @@ func (r *alertRepository) BatchAcknowledge(ctx context.Context, ids []string, us
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("没有找到要确认的告警")
+		return models.ErrAlertNotFound
 	}
 
 	// 为每个告警添加历史记录
@@ -1264,7 +1657,7 @@ func (r *alertRepository) BatchResolve(ctx context.Context, ids []string, userID
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("没有找到要解决的告警")
+		return models.ErrAlertNotFound
 	}
 
 	// 为每个告警添加历史记录
@@ -72,6 +72,10 @@ func TestAlertRepository_Create(t *testing.T) {
 		alert.EvalCount,
 		alert.Fingerprint,
 		alert.GeneratorURL,
+		int64(1),         // occurrence_count
+		sqlmock.AnyArg(), // last_seen_at
+		alert.TriageScore,
+		alert.TriageAssignee,
 		alert.SilenceID,
 		alert.AckedBy,
 		alert.AckedAt,
@@ -107,6 +111,7 @@ func TestAlertRepository_GetByID(t *testing.T) {
 		"id", "rule_id", "data_source_id", "name", "description", "severity", "status", "source",
 		"labels", "annotations", "value", "threshold", "expression", "starts_at", "ends_at",
 		"last_eval_at", "eval_count", "fingerprint", "generator_url",
+		"occurrence_count", "last_seen_at", "triage_score", "triage_assignee",
 		"silence_id", "acked_by", "acked_at", "resolved_by", "resolved_at",
 		"created_at", "updated_at", "deleted_at",
 	}).AddRow(
@@ -114,6 +119,7 @@ func TestAlertRepository_GetByID(t *testing.T) {
 		expectedAlert.Severity, expectedAlert.Status, expectedAlert.Source,
 		"{}", "{}", (*float64)(nil), (*float64)(nil), "test-expression", time.Now(), (*time.Time)(nil),
 		time.Now(), int64(1), "test-fingerprint", (*string)(nil),
+		int64(3), time.Now(), float64(87.5), "u1",
 		(*string)(nil), (*string)(nil), (*time.Time)(nil), (*string)(nil), (*time.Time)(nil),
 		time.Now(), time.Now(), (*time.Time)(nil),
 	)
@@ -125,6 +131,46 @@ func TestAlertRepository_GetByID(t *testing.T) {
 	assert.Equal(t, expectedAlert.ID, alert.ID)
 	assert.Equal(t, expectedAlert.Name, alert.Name)
 	assert.Equal(t, expectedAlert.Severity, alert.Severity)
+	assert.Equal(t, int64(3), alert.OccurrenceCount)
+	require.NotNil(t, alert.TriageScore)
+	assert.Equal(t, 87.5, *alert.TriageScore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_RecordOccurrence(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	at := time.Now()
+	value := 97.5
+	mock.ExpectQuery(`UPDATE alerts SET\s+occurrence_count = occurrence_count \+ 1,.+WHERE id = \$1 AND status <> \$5 AND deleted_at IS NULL\s+RETURNING id, occurrence_count, last_seen_at, occurrence_sampled_at = \$2 AS sampled`).
+		WithArgs("a1", at, &value, float64(300), models.AlertStatusResolved).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "occurrence_count", "last_seen_at", "sampled"}).AddRow("a1", int64(5), at, true))
+
+	occurrence, err := repo.RecordOccurrence(context.Background(), "a1", at, &value, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), occurrence.Count)
+	assert.True(t, occurrence.Sampled)
+
+	// 告警在查询后被解决
+	mock.ExpectQuery(`UPDATE alerts SET`).
+		WithArgs("a2", at, nil, float64(300), models.AlertStatusResolved).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "occurrence_count", "last_seen_at", "sampled"}))
+	_, err = repo.RecordOccurrence(context.Background(), "a2", at, nil, 5*time.Minute)
+	assert.ErrorIs(t, err, models.ErrAlertNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_GetOpenByFingerprintNotFound(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT .+ FROM alerts\s+WHERE fingerprint = \$1 AND status <> \$2 AND deleted_at IS NULL\s+ORDER BY created_at DESC\s+LIMIT 1`).
+		WithArgs("fp", models.AlertStatusResolved).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetOpenByFingerprint(context.Background(), "fp")
+	assert.ErrorIs(t, err, models.ErrAlertNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -267,4 +313,197 @@ func TestAlertRepository_CleanupExpired(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// Helper functions are now in test_helpers.go
\ No newline at end of file
+func TestAlertRepository_ListStale(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	now := time.Now()
+	before := now.Add(-4 * time.Hour)
+	rows := sqlmock.NewRows([]string{
+		"id", "rule_id", "data_source_id", "name", "description", "severity", "status", "source",
+		"labels", "annotations", "value", "threshold", "expression", "starts_at", "ends_at",
+		"last_eval_at", "eval_count", "fingerprint", "generator_url",
+		"silence_id", "acked_by", "acked_at", "resolved_by", "resolved_at",
+		"created_at", "updated_at",
+	}).AddRow(
+		"a1", nil, "integration-1", "Disk full", "Disk full", models.AlertSeverityHigh, models.AlertStatusFiring,
+		models.AlertSourceCustom, `{"host":"db-1"}`, `{"auto_resolve_after":"2h"}`, nil, nil, "webhook:zabbix",
+		now.Add(-6*time.Hour), nil, now.Add(-5*time.Hour), int64(3), "webhook:abc", nil,
+		nil, nil, nil, nil, nil, now.Add(-6*time.Hour), now.Add(-5*time.Hour),
+	)
+
+	mock.ExpectQuery(`SELECT .+ FROM alerts\s+WHERE status IN \(\$1, \$2\) AND last_eval_at < \$3 AND deleted_at IS NULL\s+ORDER BY last_eval_at, id\s+LIMIT \$4 OFFSET \$5`).
+		WithArgs(models.AlertStatusFiring, models.AlertStatusAcked, before, 100, 0).
+		WillReturnRows(rows)
+
+	alerts, err := repo.ListStale(context.Background(), before, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "db-1", alerts[0].Labels["host"])
+	assert.Equal(t, "2h", alerts[0].Annotations[models.AlertAnnotationAutoResolveAfter])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Helper functions are now in test_helpers.go
+func TestAlertRepository_GetHistory(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	alertID := uuid.New().String()
+	userID := uuid.New().String()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "alert_id", "action", "old_value", "new_value", "user_id", "comment", "created_at",
+	}).
+		AddRow("h2", alertID, "commented", "null", "null", userID, "正在排查", now).
+		AddRow("h1", alertID, "updated", `{"status":"firing"}`, `{"status":"acked"}`, nil, nil, now.Add(-time.Minute))
+
+	mock.ExpectQuery(`SELECT .+ FROM alert_histories\s+WHERE alert_id = \$1`).
+		WithArgs(alertID).
+		WillReturnRows(rows)
+
+	histories, err := repo.GetHistory(context.Background(), alertID)
+	require.NoError(t, err)
+	require.Len(t, histories, 2)
+	assert.Equal(t, "正在排查", *histories[0].Comment)
+	assert.Nil(t, histories[0].OldValue)
+	assert.Equal(t, "acked", histories[1].NewValue["status"])
+	assert.Nil(t, histories[1].UserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_GetSilence_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT .+ FROM alert_silences\s+WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetSilence(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrAlertSilenceNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_CreateSilence(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	now := time.Now()
+	silence := &models.AlertSilence{
+		Name:      "chatops",
+		StartsAt:  now,
+		EndsAt:    now.Add(2 * time.Hour),
+		Active:    true,
+		CreatedBy: "u1",
+		Matchers:  map[string]string{"fingerprint": "abc"},
+	}
+
+	mock.ExpectExec(`INSERT INTO alert_silences`).
+		WithArgs(sqlmock.AnyArg(), "chatops", nil, []byte(`{"fingerprint":"abc"}`),
+			silence.StartsAt, silence.EndsAt, true, "u1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateSilence(context.Background(), silence))
+	assert.NotEmpty(t, silence.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_CountWithLabelsAndQuery(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	status := models.AlertStatusFiring
+	query := `severity in (critical,high) and labels.cluster = "prod"`
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM alerts WHERE deleted_at IS NULL AND status = \$1 AND labels @> \$2::jsonb AND \(severity = ANY\(\$3\) AND labels @> \$4::jsonb\)`).
+		WithArgs(status, `{"env":"prod"}`, sqlmock.AnyArg(), `{"cluster":"prod"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(7)))
+
+	count, err := repo.Count(context.Background(), &models.AlertFilter{
+		Status: &status, Labels: map[string]string{"env": "prod"}, Query: &query,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), count)
+
+	invalid := "severity in critical"
+	_, err = repo.Count(context.Background(), &models.AlertFilter{Query: &invalid})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_BatchCreate(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	alerts := []*models.Alert{
+		{Name: "a1", Severity: models.AlertSeverityCritical, Labels: map[string]string{"env": "prod"}},
+		{Name: "a2", Severity: models.AlertSeverityLow},
+	}
+
+	// 少量告警使用一条多行 VALUES 语句
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO alerts \(id, rule_id, .*, updated_at\) VALUES \(\$1, .*, \$26\), \(\$27, .*, \$52\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.BatchCreate(context.Background(), alerts))
+	assert.NotEmpty(t, alerts[0].ID)
+	assert.Equal(t, models.AlertStatusFiring, alerts[1].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_BatchCreateCopy(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	alerts := make([]*models.Alert, bulkCopyMinRows)
+	for i := range alerts {
+		alerts[i] = &models.Alert{Name: "storm", Severity: models.AlertSeverityCritical}
+	}
+
+	// 告警风暴时使用 COPY 导入
+	mock.ExpectBegin()
+	copyStmt := mock.ExpectPrepare(`COPY "alerts" \("id", "rule_id", .*\) FROM STDIN`)
+	for range alerts {
+		copyStmt.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	copyStmt.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, int64(len(alerts))))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.BatchCreate(context.Background(), alerts))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_ListLabelValues(t *testing.T) {
+	repo, mock, cleanup := setupAlertRepositoryTest(t)
+	defer cleanup()
+
+	since := time.Now().Add(-7 * 24 * time.Hour)
+	// 前缀中的通配符按字面匹配
+	mock.ExpectQuery(`SELECT a.labels->>\$1 AS value, COUNT\(\*\) AS count\s+FROM alerts a`).
+		WithArgs("service", since, `pay\_%`, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).
+			AddRow("pay_api", int64(12)).
+			AddRow("pay_worker", int64(3)))
+
+	values, err := repo.ListLabelValues(context.Background(), "service", since, &models.LabelSuggestionQuery{Prefix: "pay_", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	assert.Equal(t, "pay_api", values[0].Value)
+	assert.Equal(t, int64(12), values[0].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertListOrderBy(t *testing.T) {
+	sortBy := func(field, order string) *models.AlertFilter {
+		return &models.AlertFilter{SortBy: &field, SortOrder: &order}
+	}
+
+	assert.Equal(t, "starts_at DESC", alertListOrderBy(&models.AlertFilter{}))
+	// 未评分的告警排在最后
+	assert.Equal(t, "triage_score DESC NULLS LAST, starts_at DESC", alertListOrderBy(sortBy("triage_score", "desc")))
+	assert.Equal(t, "occurrence_count ASC NULLS LAST, starts_at DESC", alertListOrderBy(sortBy("occurrence_count", "asc")))
+	// 不支持的字段按默认排序，避免拼接任意 SQL
+	assert.Equal(t, "starts_at DESC", alertListOrderBy(sortBy("name; DROP TABLE alerts", "asc")))
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+const alertSeverityRuleColumns = `id, name, description, position, matchers, service_tiers, timezone, windows,
+		severity, adjust, enabled, created_by, created_at, updated_at`
+
+// alertSeverityRuleRepository 告警重新分级规则仓储实现
+type alertSeverityRuleRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAlertSeverityRuleRepository 创建告警重新分级规则仓储实例
+func NewAlertSeverityRuleRepository(db *sqlx.DB) AlertSeverityRuleRepository {
+	return &alertSeverityRuleRepository{db: db}
+}
+
+// NewAlertSeverityRuleRepositoryWithTx 创建带事务的告警重新分级规则仓储实例
+func NewAlertSeverityRuleRepositoryWithTx(tx *sqlx.Tx) AlertSeverityRuleRepository {
+	return &alertSeverityRuleRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *alertSeverityRuleRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 创建重新分级规则
+func (r *alertSeverityRuleRepository) Create(ctx context.Context, rule *models.AlertSeverityRule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	matchers, tiers, windows, err := encodeAlertSeverityRule(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO alert_severity_rules (
+			id, name, description, position, matchers, service_tiers, timezone, windows,
+			severity, adjust, enabled, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		rule.ID, rule.Name, rule.Description, rule.Position, matchers, tiers, rule.Timezone, windows,
+		rule.Severity, rule.Adjust, rule.Enabled, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrAlertSeverityRuleExists
+		}
+		return fmt.Errorf("创建告警重新分级规则失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取重新分级规则
+func (r *alertSeverityRuleRepository) GetByID(ctx context.Context, id string) (*models.AlertSeverityRule, error) {
+	query := `SELECT ` + alertSeverityRuleColumns + ` FROM alert_severity_rules WHERE id = $1`
+
+	rule, err := scanAlertSeverityRule(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrAlertSeverityRuleNotFound
+		}
+		return nil, fmt.Errorf("获取告警重新分级规则失败: %w", err)
+	}
+	return rule, nil
+}
+
+// List 获取全部重新分级规则，按匹配顺序排列
+func (r *alertSeverityRuleRepository) List(ctx context.Context) ([]*models.AlertSeverityRule, error) {
+	query := `SELECT ` + alertSeverityRuleColumns + ` FROM alert_severity_rules ORDER BY position, created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警重新分级规则列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.AlertSeverityRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertSeverityRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描告警重新分级规则失败: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Update 更新重新分级规则
+func (r *alertSeverityRuleRepository) Update(ctx context.Context, rule *models.AlertSeverityRule) error {
+	rule.UpdatedAt = time.Now()
+
+	matchers, tiers, windows, err := encodeAlertSeverityRule(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE alert_severity_rules SET
+			name = $1,
+			description = $2,
+			position = $3,
+			matchers = $4,
+			service_tiers = $5,
+			timezone = $6,
+			windows = $7,
+			severity = $8,
+			adjust = $9,
+			enabled = $10,
+			updated_at = $11
+		WHERE id = $12`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		rule.Name, rule.Description, rule.Position, matchers, tiers, rule.Timezone, windows,
+		rule.Severity, rule.Adjust, rule.Enabled, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrAlertSeverityRuleExists
+		}
+		return fmt.Errorf("更新告警重新分级规则失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAlertSeverityRuleNotFound
+	}
+	return nil
+}
+
+// Delete 删除重新分级规则
+func (r *alertSeverityRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM alert_severity_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除告警重新分级规则失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAlertSeverityRuleNotFound
+	}
+	return nil
+}
+
+// encodeAlertSeverityRule 序列化规则的标签匹配条件、服务等级和时段
+func encodeAlertSeverityRule(rule *models.AlertSeverityRule) (matchers, tiers, windows []byte, err error) {
+	if matchers, err = json.Marshal(rule.Matchers); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化标签匹配条件失败: %w", err)
+	}
+	if tiers, err = json.Marshal(rule.ServiceTiers); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化服务等级失败: %w", err)
+	}
+	if windows, err = json.Marshal(rule.Windows); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化生效时段失败: %w", err)
+	}
+	return matchers, tiers, windows, nil
+}
+
+// scanAlertSeverityRule 扫描 alertSeverityRuleColumns 对应的一行规则
+func scanAlertSeverityRule(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AlertSeverityRule, error) {
+	var rule models.AlertSeverityRule
+	var matchers, tiers, windows []byte
+	if err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Description, &rule.Position, &matchers, &tiers, &rule.Timezone, &windows,
+		&rule.Severity, &rule.Adjust, &rule.Enabled, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	rule.Matchers = map[string]string{}
+	rule.ServiceTiers = []models.ServiceTier{}
+	rule.Windows = []models.AlertSeverityRuleWindow{}
+	if err := json.Unmarshal(matchers, &rule.Matchers); err != nil {
+		return nil, fmt.Errorf("解析标签匹配条件失败: %w", err)
+	}
+	if err := json.Unmarshal(tiers, &rule.ServiceTiers); err != nil {
+		return nil, fmt.Errorf("解析服务等级失败: %w", err)
+	}
+	if err := json.Unmarshal(windows, &rule.Windows); err != nil {
+		return nil, fmt.Errorf("解析生效时段失败: %w", err)
+	}
+	return &rule, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestAlertSeverityRuleRepository_CreateDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAlertSeverityRuleRepository(sqlx.NewDb(db, "postgres"))
+
+	// 规则名称重复
+	mock.ExpectExec(`INSERT INTO alert_severity_rules`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.Create(context.Background(), &models.AlertSeverityRule{
+		Name:     "staging",
+		Matchers: map[string]string{"env": "staging"},
+		Adjust:   -1,
+	})
+	assert.ErrorIs(t, err, models.ErrAlertSeverityRuleExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertSeverityRuleRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAlertSeverityRuleRepository(sqlx.NewDb(db, "postgres"))
+
+	now := time.Now()
+	columns := []string{"id", "name", "description", "position", "matchers", "service_tiers", "timezone", "windows",
+		"severity", "adjust", "enabled", "created_by", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .+ FROM alert_severity_rules ORDER BY position, created_at`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("r1", "staging", "", 0, []byte(`{"env":"staging"}`), []byte(`[]`), "", []byte(`[]`),
+				"info", 0, true, nil, now, now).
+			AddRow("r2", "tier1-night", "", 1, []byte(`{}`), []byte(`["tier1"]`), "Asia/Shanghai",
+				[]byte(`[{"days":["monday"],"start":"22:00","end":"08:00"}]`), nil, 1, true, "u1", now, now))
+
+	rules, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "staging", rules[0].Matchers["env"])
+	assert.Equal(t, models.AlertSeverityInfo, *rules[0].Severity)
+	assert.Nil(t, rules[1].Severity)
+	assert.Equal(t, []models.ServiceTier{models.ServiceTier1}, rules[1].ServiceTiers)
+	assert.Equal(t, "22:00", rules[1].Windows[0].Start)
+	assert.Equal(t, "u1", *rules[1].CreatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertSeverityRuleRepository_DeleteNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAlertSeverityRuleRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`DELETE FROM alert_severity_rules WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrAlertSeverityRuleNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// alertSnoozeRepository 告警暂停仓储实现
+type alertSnoozeRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAlertSnoozeRepository 创建告警暂停仓储实例
+func NewAlertSnoozeRepository(db *sqlx.DB) AlertSnoozeRepository {
+	return &alertSnoozeRepository{db: db}
+}
+
+// NewAlertSnoozeRepositoryWithTx 创建带事务的告警暂停仓储实例
+func NewAlertSnoozeRepositoryWithTx(tx *sqlx.Tx) AlertSnoozeRepository {
+	return &alertSnoozeRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *alertSnoozeRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const alertSnoozeColumns = `id, user_id, alert_id, matchers, reason, expires_at, created_at`
+
+// Create 创建告警暂停
+func (r *alertSnoozeRepository) Create(ctx context.Context, snooze *models.AlertSnooze) error {
+	if snooze.ID == "" {
+		snooze.ID = uuid.New().String()
+	}
+	if snooze.Matchers == nil {
+		snooze.Matchers = map[string]string{}
+	}
+
+	matchers, err := json.Marshal(snooze.Matchers)
+	if err != nil {
+		return fmt.Errorf("序列化标签匹配条件失败: %w", err)
+	}
+	snooze.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO alert_snoozes (` + alertSnoozeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		snooze.ID, snooze.UserID, snooze.AlertID, matchers, snooze.Reason, snooze.ExpiresAt, snooze.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建告警暂停失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取告警暂停
+func (r *alertSnoozeRepository) GetByID(ctx context.Context, id string) (*models.AlertSnooze, error) {
+	query := `SELECT ` + alertSnoozeColumns + ` FROM alert_snoozes WHERE id = $1`
+	snooze, err := scanAlertSnooze(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrAlertSnoozeNotFound
+		}
+		return nil, fmt.Errorf("获取告警暂停失败: %w", err)
+	}
+	return snooze, nil
+}
+
+// ListActiveByUser 获取用户在 now 时仍然生效的告警暂停，按到期时间排序
+func (r *alertSnoozeRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.AlertSnooze, error) {
+	query := `
+		SELECT ` + alertSnoozeColumns + `
+		FROM alert_snoozes
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY expires_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警暂停列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	snoozes := make([]*models.AlertSnooze, 0)
+	for rows.Next() {
+		snooze, err := scanAlertSnooze(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描告警暂停失败: %w", err)
+		}
+		snoozes = append(snoozes, snooze)
+	}
+
+	return snoozes, rows.Err()
+}
+
+// Delete 删除告警暂停
+func (r *alertSnoozeRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM alert_snoozes WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除告警暂停失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAlertSnoozeNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpired 删除 before 之前到期的告警暂停，返回删除的数量
+func (r *alertSnoozeRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM alert_snoozes WHERE expires_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理到期的告警暂停失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanAlertSnooze(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AlertSnooze, error) {
+	var snooze models.AlertSnooze
+	var matchers []byte
+
+	err := scanner.Scan(
+		&snooze.ID, &snooze.UserID, &snooze.AlertID, &matchers, &snooze.Reason, &snooze.ExpiresAt, &snooze.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snooze.Matchers = map[string]string{}
+	if len(matchers) > 0 {
+		if err := json.Unmarshal(matchers, &snooze.Matchers); err != nil {
+			return nil, fmt.Errorf("解析标签匹配条件失败: %w", err)
+		}
+	}
+
+	return &snooze, nil
+}
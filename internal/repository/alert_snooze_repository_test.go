@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestAlertSnoozeRepository_ListActiveByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAlertSnoozeRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM alert_snoozes\s+WHERE user_id = \$1 AND expires_at > \$2\s+ORDER BY expires_at`).
+		WithArgs("u1", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "alert_id", "matchers", "reason", "expires_at", "created_at"}).
+			AddRow("s1", "u1", "a1", []byte(`{}`), "排查中", now.Add(time.Hour), now).
+			AddRow("s2", "u1", nil, []byte(`{"service":"checkout"}`), "", now.Add(2*time.Hour), now))
+
+	snoozes, err := repo.ListActiveByUser(context.Background(), "u1", now)
+	require.NoError(t, err)
+	require.Len(t, snoozes, 2)
+
+	require.NotNil(t, snoozes[0].AlertID)
+	assert.Equal(t, "a1", *snoozes[0].AlertID)
+	assert.Nil(t, snoozes[1].AlertID)
+	assert.Equal(t, map[string]string{"service": "checkout"}, snoozes[1].Matchers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertSnoozeRepository_DeleteNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAlertSnoozeRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`DELETE FROM alert_snoozes WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrAlertSnoozeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
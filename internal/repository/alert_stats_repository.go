@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// alertStatsRepository 告警统计小时汇总仓储实现
+type alertStatsRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAlertStatsRepository 创建告警统计仓储实例
+func NewAlertStatsRepository(db *sqlx.DB) AlertStatsRepository {
+	return &alertStatsRepository{db: db}
+}
+
+// NewAlertStatsRepositoryWithTx 创建带事务的告警统计仓储实例
+func NewAlertStatsRepositoryWithTx(tx *sqlx.Tx) AlertStatsRepository {
+	return &alertStatsRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *alertStatsRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// alertStatsBucketExpr 告警开始时间按 UTC 对齐到小时，与会话时区无关
+const alertStatsBucketExpr = `date_trunc('hour', starts_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'`
+
+// Refresh 重算自上次汇总以来有告警变化的小时。锁定汇总进度行，多个实例同时汇总时依次执行；
+// 告警更新时间晚于 watermark - overlap 的告警所在的小时整体删除后按 alerts 表重新统计，
+// 首次汇总时重建全部小时。返回写入的汇总行数
+func (r *alertStatsRepository) Refresh(ctx context.Context, overlap time.Duration) (int, error) {
+	var refreshed int
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		var watermark sql.NullTime
+		err := tx.QueryRowxContext(ctx, `SELECT watermark FROM alert_stats_rollup_state WHERE id = 1 FOR UPDATE`).Scan(&watermark)
+		if err != nil {
+			return fmt.Errorf("获取告警统计汇总进度失败: %w", err)
+		}
+
+		var bucketFilter string
+		var args []interface{}
+		if watermark.Valid {
+			var buckets []time.Time
+			query := `SELECT DISTINCT ` + alertStatsBucketExpr + ` FROM alerts WHERE updated_at > $1 AND updated_at <= NOW()`
+			if err := sqlx.SelectContext(ctx, tx, &buckets, query, watermark.Time.Add(-overlap)); err != nil {
+				return fmt.Errorf("查询变化的告警统计小时失败: %w", err)
+			}
+			if len(buckets) == 0 {
+				return r.advanceWatermark(ctx, tx)
+			}
+
+			values := make([]string, len(buckets))
+			first, last := buckets[0], buckets[0]
+			for i, bucket := range buckets {
+				values[i] = bucket.UTC().Format(time.RFC3339)
+				if bucket.Before(first) {
+					first = bucket
+				}
+				if bucket.After(last) {
+					last = bucket
+				}
+			}
+			// 开始时间范围条件让重新统计可以使用 starts_at 索引
+			bucketFilter = ` AND starts_at >= $2 AND starts_at < $3 AND ` + alertStatsBucketExpr + ` = ANY($1::timestamptz[])`
+			args = append(args, pq.StringArray(values), first, last.Add(time.Hour))
+
+			if _, err := tx.ExecContext(ctx, `DELETE FROM alert_stats_hourly WHERE bucket = ANY($1::timestamptz[])`, args[0]); err != nil {
+				return fmt.Errorf("删除告警统计小时汇总失败: %w", err)
+			}
+		} else if _, err := tx.ExecContext(ctx, `DELETE FROM alert_stats_hourly`); err != nil {
+			return fmt.Errorf("删除告警统计小时汇总失败: %w", err)
+		}
+
+		insert := `
+			INSERT INTO alert_stats_hourly (bucket, severity, status, rule_id, team, count, updated_at)
+			SELECT ` + alertStatsBucketExpr + `, severity::text, status::text,
+				COALESCE(rule_id::text, ''), COALESCE(labels->>'team', ''), COUNT(*), NOW()
+			FROM alerts
+			WHERE deleted_at IS NULL` + bucketFilter + `
+			GROUP BY 1, 2, 3, 4, 5`
+		result, err := tx.ExecContext(ctx, insert, args...)
+		if err != nil {
+			return fmt.Errorf("写入告警统计小时汇总失败: %w", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil {
+			refreshed = int(rows)
+		}
+
+		return r.advanceWatermark(ctx, tx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return refreshed, nil
+}
+
+// advanceWatermark 将汇总进度推进到当前事务开始的时间
+func (r *alertStatsRepository) advanceWatermark(ctx context.Context, tx *sqlx.Tx) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE alert_stats_rollup_state SET watermark = NOW(), updated_at = NOW() WHERE id = 1`); err != nil {
+		return fmt.Errorf("更新告警统计汇总进度失败: %w", err)
+	}
+	return nil
+}
+
+// GetWatermark 获取汇总进度，尚未汇总过时返回 nil
+func (r *alertStatsRepository) GetWatermark(ctx context.Context) (*time.Time, error) {
+	var watermark sql.NullTime
+	err := r.getExecutor().QueryRowxContext(ctx, `SELECT watermark FROM alert_stats_rollup_state WHERE id = 1`).Scan(&watermark)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("获取告警统计汇总进度失败: %w", err)
+	}
+	if !watermark.Valid {
+		return nil, nil
+	}
+	return &watermark.Time, nil
+}
+
+// buildAlertStatsConditions 构建汇总表的查询条件
+func buildAlertStatsConditions(query *models.AlertStatsQuery) (string, []interface{}) {
+	conditions := []string{"bucket >= $1", "bucket < $2"}
+	args := []interface{}{query.From, query.To}
+
+	if query.RuleID != nil {
+		args = append(args, *query.RuleID)
+		conditions = append(conditions, fmt.Sprintf("rule_id = $%d", len(args)))
+	}
+	if query.Team != nil {
+		args = append(args, *query.Team)
+		conditions = append(conditions, fmt.Sprintf("team = $%d", len(args)))
+	}
+	if query.Severity != nil {
+		args = append(args, string(*query.Severity))
+		conditions = append(conditions, fmt.Sprintf("severity = $%d", len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// Summary 按严重级别、状态、规则和团队汇总时间范围内的告警数
+func (r *alertStatsRepository) Summary(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error) {
+	where, args := buildAlertStatsConditions(query)
+	sqlQuery := `
+		SELECT severity, status, rule_id, team, SUM(count) AS count
+		FROM alert_stats_hourly
+		WHERE ` + where + `
+		GROUP BY severity, status, rule_id, team`
+
+	var rows []*models.AlertStatsRow
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &rows, sqlQuery, args...); err != nil {
+		return nil, fmt.Errorf("查询告警统计失败: %w", err)
+	}
+	return rows, nil
+}
+
+// Trend 按时间粒度和严重级别汇总告警数，时间粒度按 UTC 对齐
+func (r *alertStatsRepository) Trend(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error) {
+	where, args := buildAlertStatsConditions(query)
+	args = append(args, string(query.Interval))
+	sqlQuery := fmt.Sprintf(`
+		SELECT date_trunc($%d, bucket AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket, severity, SUM(count) AS count
+		FROM alert_stats_hourly
+		WHERE %s
+		GROUP BY 1, 2
+		ORDER BY 1, 2`, len(args), where)
+
+	var rows []*models.AlertStatsRow
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &rows, sqlQuery, args...); err != nil {
+		return nil, fmt.Errorf("查询告警趋势失败: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertStatsRepository_Refresh(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAlertStatsRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	watermark := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	// 首次汇总重建全部小时
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT watermark FROM alert_stats_rollup_state WHERE id = 1 FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"watermark"}).AddRow(nil))
+	mock.ExpectExec(`DELETE FROM alert_stats_hourly$`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO alert_stats_hourly .+ WHERE deleted_at IS NULL\s+GROUP BY`).
+		WithArgs().WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectExec(`UPDATE alert_stats_rollup_state SET watermark = NOW\(\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rows, err := repo.Refresh(ctx, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 42, rows)
+
+	// 之后只重算有告警变化的小时，回看 overlap
+	buckets := []time.Time{
+		time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 28, 23, 0, 0, 0, time.UTC),
+	}
+	hours := pq.StringArray{"2024-03-01T10:00:00Z", "2024-02-28T23:00:00Z"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT watermark FROM alert_stats_rollup_state WHERE id = 1 FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"watermark"}).AddRow(watermark))
+	mock.ExpectQuery(`SELECT DISTINCT .+ FROM alerts WHERE updated_at > \$1`).
+		WithArgs(watermark.Add(-5 * time.Minute)).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket"}).AddRow(buckets[0]).AddRow(buckets[1]))
+	mock.ExpectExec(`DELETE FROM alert_stats_hourly WHERE bucket = ANY\(\$1::timestamptz\[\]\)`).
+		WithArgs(hours).WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec(`INSERT INTO alert_stats_hourly .+ AND starts_at >= \$2 AND starts_at < \$3 AND .+ = ANY\(\$1::timestamptz\[\]\)`).
+		WithArgs(hours, buckets[1], buckets[0].Add(time.Hour)).WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec(`UPDATE alert_stats_rollup_state SET watermark = NOW\(\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rows, err = repo.Refresh(ctx, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 7, rows)
+
+	// 没有变化时只推进进度
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT watermark FROM alert_stats_rollup_state WHERE id = 1 FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"watermark"}).AddRow(watermark))
+	mock.ExpectQuery(`SELECT DISTINCT .+ FROM alerts`).WillReturnRows(sqlmock.NewRows([]string{"bucket"}))
+	mock.ExpectExec(`UPDATE alert_stats_rollup_state SET watermark = NOW\(\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rows, err = repo.Refresh(ctx, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, rows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// approvalRepository 敏感操作审批请求仓储实现，每次状态变更与审计日志在同一事务中写入
+type approvalRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewApprovalRepository 创建审批请求仓储实例
+func NewApprovalRepository(db *sqlx.DB) ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+// NewApprovalRepositoryWithTx 创建带事务的审批请求仓储实例
+func NewApprovalRepositoryWithTx(tx *sqlx.Tx) ApprovalRepository {
+	return &approvalRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *approvalRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const approvalColumns = `id, action, params, reason, status, requester_id, requires_approval, approver_id,
+		decision_comment, result, error, expires_at, decided_at, executed_at, created_at`
+
+// Create 保存审批请求并记录提交审计日志
+func (r *approvalRepository) Create(ctx context.Context, request *models.ApprovalRequest, audit *models.AuditLog) error {
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO approval_requests (id, action, params, reason, status, requester_id, requires_approval, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING created_at`
+
+		err := tx.QueryRowxContext(ctx, query, request.ID, request.Action, nullJSON(request.Params), request.Reason,
+			request.Status, request.RequesterID, request.RequiresApproval, request.ExpiresAt).Scan(&request.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("创建审批请求失败: %w", err)
+		}
+		return insertAuditLog(ctx, tx, audit)
+	})
+}
+
+// GetByID 获取审批请求
+func (r *approvalRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	err := sqlx.GetContext(ctx, r.getExecutor(), &request, `SELECT `+approvalColumns+` FROM approval_requests WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrApprovalNotFound
+		}
+		return nil, fmt.Errorf("获取审批请求失败: %w", err)
+	}
+	return &request, nil
+}
+
+// List 按创建时间倒序获取审批请求
+func (r *approvalRepository) List(ctx context.Context, filter *models.ApprovalFilter) ([]*models.ApprovalRequest, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.RequesterID != nil {
+		args = append(args, *filter.RequesterID)
+		conditions = append(conditions, fmt.Sprintf("requester_id = $%d", len(args)))
+	}
+
+	query := `SELECT ` + approvalColumns + ` FROM approval_requests`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", len(args))
+
+	var requests []*models.ApprovalRequest
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &requests, query, args...); err != nil {
+		return nil, fmt.Errorf("查询审批请求失败: %w", err)
+	}
+	return requests, nil
+}
+
+// Decide 将处于 decision.From 状态的请求变更为 decision.To 并记录审计日志，
+// 请求已被其他人处理时返回 false，不记录审计日志
+func (r *approvalRepository) Decide(ctx context.Context, id string, decision *models.ApprovalDecision, audit *models.AuditLog) (bool, error) {
+	updated := false
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE approval_requests
+			SET status = $3, approver_id = COALESCE($4, approver_id), decision_comment = $5, decided_at = NOW()
+			WHERE id = $1 AND status = $2`
+
+		result, err := tx.ExecContext(ctx, query, id, decision.From, decision.To, decision.ApproverID, decision.Comment)
+		if err != nil {
+			return fmt.Errorf("更新审批请求失败: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("获取更新结果失败: %w", err)
+		}
+		if rows == 0 {
+			return nil
+		}
+		updated = true
+		return insertAuditLog(ctx, tx, audit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// Complete 保存已批准请求的执行结果并记录审计日志
+func (r *approvalRepository) Complete(ctx context.Context, id string, outcome *models.ApprovalOutcome, audit *models.AuditLog) error {
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE approval_requests
+			SET status = $3, result = $4, error = $5, executed_at = NOW()
+			WHERE id = $1 AND status = $2`
+
+		result, err := tx.ExecContext(ctx, query, id, models.ApprovalStatusApproved, outcome.Status, nullJSON(outcome.Result), outcome.Error)
+		if err != nil {
+			return fmt.Errorf("保存审批请求执行结果失败: %w", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return models.ErrApprovalNotPending
+		}
+		return insertAuditLog(ctx, tx, audit)
+	})
+}
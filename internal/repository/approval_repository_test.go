@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestApprovalRepository_CreateWritesAuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewApprovalRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	userID := "user-1"
+	resourceType := models.ApprovalAuditResource
+	request := &models.ApprovalRequest{
+		ID: "req-1", Action: models.ApprovalActionRulesDisableAll, Status: models.ApprovalStatusPending,
+		RequesterID: userID, RequiresApproval: true, ExpiresAt: now.Add(time.Hour),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO approval_requests`).
+		WithArgs("req-1", models.ApprovalActionRulesDisableAll, nil, "", models.ApprovalStatusPending, userID, true, now.Add(time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectExec(`INSERT INTO user_audit_logs`).
+		WithArgs(&userID, models.ApprovalAuditSubmit, &resourceType, sqlmock.AnyArg(), nil, true, nil, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Create(context.Background(), request, &models.AuditLog{
+		UserID: &userID, Action: models.ApprovalAuditSubmit, ResourceType: &resourceType, ResourceID: &request.ID, Success: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, request.CreatedAt.Equal(now))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApprovalRepository_DecideOnlyFromPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewApprovalRepository(sqlx.NewDb(db, "postgres"))
+	approver := "user-2"
+	decision := &models.ApprovalDecision{
+		From: models.ApprovalStatusPending, To: models.ApprovalStatusApproved, ApproverID: &approver,
+	}
+	audit := &models.AuditLog{UserID: &approver, Action: models.ApprovalAuditApprove, Success: true}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE approval_requests .+ WHERE id = \$1 AND status = \$2`).
+		WithArgs("req-1", models.ApprovalStatusPending, models.ApprovalStatusApproved, &approver, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO user_audit_logs`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	updated, err := repo.Decide(context.Background(), "req-1", decision, audit)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	// 请求已被其他人处理时不记录审计日志
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE approval_requests`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	updated, err = repo.Decide(context.Background(), "req-1", decision, audit)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// assignmentPolicyRepository 分派策略仓储实现
+type assignmentPolicyRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAssignmentPolicyRepository 创建分派策略仓储实例
+func NewAssignmentPolicyRepository(db *sqlx.DB) AssignmentPolicyRepository {
+	return &assignmentPolicyRepository{db: db}
+}
+
+// NewAssignmentPolicyRepositoryWithTx 创建带事务的分派策略仓储实例
+func NewAssignmentPolicyRepositoryWithTx(tx *sqlx.Tx) AssignmentPolicyRepository {
+	return &assignmentPolicyRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *assignmentPolicyRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const assignmentPolicyColumns = `id, team, strategy, members, enabled, created_by, created_at, updated_at`
+
+// Create 创建分派策略
+func (r *assignmentPolicyRepository) Create(ctx context.Context, policy *models.AssignmentPolicy) error {
+	if policy.ID == "" {
+		policy.ID = uuid.New().String()
+	}
+
+	members, err := marshalAssignmentMembers(policy)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	query := `
+		INSERT INTO assignment_policies (` + assignmentPolicyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		policy.ID, policy.Team, policy.Strategy, members, policy.Enabled,
+		policy.CreatedBy, policy.CreatedAt, policy.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrAssignmentPolicyExists
+		}
+		return fmt.Errorf("创建分派策略失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取分派策略
+func (r *assignmentPolicyRepository) GetByID(ctx context.Context, id string) (*models.AssignmentPolicy, error) {
+	query := `SELECT ` + assignmentPolicyColumns + ` FROM assignment_policies WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// GetByTeam 根据团队获取分派策略
+func (r *assignmentPolicyRepository) GetByTeam(ctx context.Context, team string) (*models.AssignmentPolicy, error) {
+	query := `SELECT ` + assignmentPolicyColumns + ` FROM assignment_policies WHERE team = $1`
+	return r.get(ctx, query, team)
+}
+
+func (r *assignmentPolicyRepository) get(ctx context.Context, query string, arg interface{}) (*models.AssignmentPolicy, error) {
+	policy, err := scanAssignmentPolicy(r.getExecutor().QueryRowxContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrAssignmentPolicyNotFound
+		}
+		return nil, fmt.Errorf("获取分派策略失败: %w", err)
+	}
+	return policy, nil
+}
+
+// List 获取全部分派策略，按团队排序
+func (r *assignmentPolicyRepository) List(ctx context.Context) ([]*models.AssignmentPolicy, error) {
+	query := `SELECT ` + assignmentPolicyColumns + ` FROM assignment_policies ORDER BY team`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取分派策略列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*models.AssignmentPolicy, 0)
+	for rows.Next() {
+		policy, err := scanAssignmentPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描分派策略失败: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// Update 更新分派策略
+func (r *assignmentPolicyRepository) Update(ctx context.Context, policy *models.AssignmentPolicy) error {
+	members, err := marshalAssignmentMembers(policy)
+	if err != nil {
+		return err
+	}
+
+	policy.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE assignment_policies SET
+			team = $1,
+			strategy = $2,
+			members = $3,
+			enabled = $4,
+			updated_at = $5
+		WHERE id = $6`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		policy.Team, policy.Strategy, members, policy.Enabled, policy.UpdatedAt, policy.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrAssignmentPolicyExists
+		}
+		return fmt.Errorf("更新分派策略失败: %w", err)
+	}
+
+	return checkAssignmentPolicyAffected(result)
+}
+
+// Delete 删除分派策略
+func (r *assignmentPolicyRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM assignment_policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除分派策略失败: %w", err)
+	}
+
+	return checkAssignmentPolicyAffected(result)
+}
+
+// NextRoundRobin 原子递增轮询计数，多实例并发分派时不会选中同一成员
+func (r *assignmentPolicyRepository) NextRoundRobin(ctx context.Context, id string) (int64, error) {
+	query := `
+		UPDATE assignment_policies SET rr_counter = rr_counter + 1
+		WHERE id = $1
+		RETURNING rr_counter`
+
+	var counter int64
+	if err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(&counter); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, models.ErrAssignmentPolicyNotFound
+		}
+		return 0, fmt.Errorf("更新轮询计数失败: %w", err)
+	}
+
+	return counter, nil
+}
+
+// OpenTicketCounts 统计用户未解决、未关闭且未取消的工单数
+func (r *assignmentPolicyRepository) OpenTicketCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT assignee_id, COUNT(*)
+		FROM tickets
+		WHERE assignee_id = ANY($1) AND status NOT IN ` + dashboardClosedTicketStatuses + ` AND deleted_at IS NULL
+		GROUP BY assignee_id`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("统计处理人工单数失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("扫描处理人工单数失败: %w", err)
+		}
+		counts[userID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// marshalAssignmentMembers 序列化分派策略成员
+func marshalAssignmentMembers(policy *models.AssignmentPolicy) ([]byte, error) {
+	if policy.Members == nil {
+		policy.Members = []models.AssignmentMember{}
+	}
+	members, err := json.Marshal(policy.Members)
+	if err != nil {
+		return nil, fmt.Errorf("序列化分派策略成员失败: %w", err)
+	}
+	return members, nil
+}
+
+// checkAssignmentPolicyAffected 没有更新任何行时返回分派策略不存在
+func checkAssignmentPolicyAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrAssignmentPolicyNotFound
+	}
+
+	return nil
+}
+
+// scanAssignmentPolicy 扫描分派策略
+func scanAssignmentPolicy(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AssignmentPolicy, error) {
+	var policy models.AssignmentPolicy
+	var members []byte
+
+	err := scanner.Scan(
+		&policy.ID, &policy.Team, &policy.Strategy, &members, &policy.Enabled,
+		&policy.CreatedBy, &policy.CreatedAt, &policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Members = []models.AssignmentMember{}
+	if len(members) > 0 {
+		if err := json.Unmarshal(members, &policy.Members); err != nil {
+			return nil, fmt.Errorf("解析分派策略成员失败: %w", err)
+		}
+	}
+
+	return &policy, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestAssignmentPolicyRepository_CreateAndGetByTeam(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentPolicyRepository(sqlx.NewDb(db, "postgres"))
+
+	policy := &models.AssignmentPolicy{
+		Team:      "sre",
+		Strategy:  models.AssignmentStrategySkillMatch,
+		Members:   []models.AssignmentMember{{UserID: "u1", Skills: []string{"mysql"}}},
+		Enabled:   true,
+		CreatedBy: "admin",
+	}
+
+	mock.ExpectExec(`INSERT INTO assignment_policies`).
+		WithArgs(sqlmock.AnyArg(), "sre", models.AssignmentStrategySkillMatch,
+			[]byte(`[{"user_id":"u1","skills":["mysql"]}]`), true, "admin", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), policy))
+	assert.NotEmpty(t, policy.ID)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM assignment_policies WHERE team = \$1`).
+		WithArgs("sre").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "team", "strategy", "members", "enabled", "created_by", "created_at", "updated_at",
+		}).AddRow(policy.ID, "sre", "skill_match", []byte(`[{"user_id":"u1","skills":["mysql"]}]`), true, "admin", now, now))
+
+	got, err := repo.GetByTeam(context.Background(), "sre")
+	require.NoError(t, err)
+	assert.Equal(t, models.AssignmentStrategySkillMatch, got.Strategy)
+	assert.Equal(t, policy.Members, got.Members)
+
+	mock.ExpectQuery(`SELECT .+ FROM assignment_policies WHERE team = \$1`).
+		WithArgs("dba").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetByTeam(context.Background(), "dba")
+	assert.ErrorIs(t, err, models.ErrAssignmentPolicyNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignmentPolicyRepository_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentPolicyRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO assignment_policies`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.Create(context.Background(), &models.AssignmentPolicy{Team: "sre", Strategy: models.AssignmentStrategyRoundRobin})
+	assert.ErrorIs(t, err, models.ErrAssignmentPolicyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignmentPolicyRepository_NextRoundRobinAndOpenTicketCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentPolicyRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`UPDATE assignment_policies SET rr_counter = rr_counter \+ 1`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"rr_counter"}).AddRow(7))
+
+	counter, err := repo.NextRoundRobin(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), counter)
+
+	mock.ExpectQuery(`SELECT assignee_id, COUNT\(\*\)\s+FROM tickets`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"assignee_id", "count"}).AddRow("u1", 3))
+
+	counts, err := repo.OpenTicketCounts(context.Background(), []string{"u1", "u2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"u1": 3}, counts)
+
+	// 没有用户时不查询
+	counts, err = repo.OpenTicketCounts(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// asyncTaskRepository 异步任务仓储实现
+type asyncTaskRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewAsyncTaskRepository 创建异步任务仓储实例
+func NewAsyncTaskRepository(db *sqlx.DB) AsyncTaskRepository {
+	return &asyncTaskRepository{db: db}
+}
+
+// NewAsyncTaskRepositoryWithTx 创建带事务的异步任务仓储实例
+func NewAsyncTaskRepositoryWithTx(tx *sqlx.Tx) AsyncTaskRepository {
+	return &asyncTaskRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *asyncTaskRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// asyncTaskColumns 任务列表和详情查询的列，不包含结果文件内容
+const asyncTaskColumns = `id, type, params, status, total, processed, result, result_file_name, result_file_type,
+		result_file_size, error, created_by, created_at, started_at, finished_at`
+
+// Create 保存待执行的异步任务
+func (r *asyncTaskRepository) Create(ctx context.Context, task *models.AsyncTask) error {
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	task.Status = models.AsyncTaskStatusPending
+
+	query := `
+		INSERT INTO async_tasks (id, type, params, status, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	err := sqlx.GetContext(ctx, r.getExecutor(), &task.CreatedAt, query,
+		task.ID, task.Type, nullJSON(task.Params), task.Status, task.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("创建异步任务失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 获取异步任务
+func (r *asyncTaskRepository) GetByID(ctx context.Context, id string) (*models.AsyncTask, error) {
+	var task models.AsyncTask
+	err := sqlx.GetContext(ctx, r.getExecutor(), &task, `SELECT `+asyncTaskColumns+` FROM async_tasks WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAsyncTaskNotFound
+		}
+		return nil, fmt.Errorf("获取异步任务失败: %w", err)
+	}
+	return &task, nil
+}
+
+// ListByCreator 按提交时间倒序获取用户提交的异步任务
+func (r *asyncTaskRepository) ListByCreator(ctx context.Context, createdBy string, limit int) ([]*models.AsyncTask, error) {
+	query := `SELECT ` + asyncTaskColumns + ` FROM async_tasks WHERE created_by = $1 ORDER BY created_at DESC LIMIT $2`
+
+	var tasks []*models.AsyncTask
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &tasks, query, createdBy, limit); err != nil {
+		return nil, fmt.Errorf("查询异步任务失败: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetResultFile 获取已完成任务的结果文件
+func (r *asyncTaskRepository) GetResultFile(ctx context.Context, id string) (*models.AsyncTaskFile, error) {
+	query := `
+		SELECT result_file_name, result_file_type, result_file
+		FROM async_tasks
+		WHERE id = $1 AND status = $2 AND result_file IS NOT NULL`
+
+	var file models.AsyncTaskFile
+	err := r.getExecutor().QueryRowxContext(ctx, query, id, models.AsyncTaskStatusSucceeded).Scan(&file.Name, &file.ContentType, &file.Data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAsyncTaskResultNotReady
+		}
+		return nil, fmt.Errorf("获取异步任务结果文件失败: %w", err)
+	}
+	return &file, nil
+}
+
+// ClaimPending 领取最早提交的待执行任务并标记为执行中，没有待执行任务时返回 nil。
+// 多个实例同时领取时跳过已被锁定的任务，同一任务只会被一个实例执行
+func (r *asyncTaskRepository) ClaimPending(ctx context.Context) (*models.AsyncTask, error) {
+	query := `
+		UPDATE async_tasks SET status = $1, started_at = NOW()
+		WHERE id = (
+			SELECT id FROM async_tasks WHERE status = $2
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + asyncTaskColumns
+
+	var task models.AsyncTask
+	err := sqlx.GetContext(ctx, r.getExecutor(), &task, query, models.AsyncTaskStatusRunning, models.AsyncTaskStatusPending)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("领取异步任务失败: %w", err)
+	}
+	return &task, nil
+}
+
+// UpdateProgress 更新执行中任务的进度
+func (r *asyncTaskRepository) UpdateProgress(ctx context.Context, id string, total, processed int64) error {
+	query := `UPDATE async_tasks SET total = $2, processed = $3 WHERE id = $1 AND status = $4`
+	if _, err := r.getExecutor().ExecContext(ctx, query, id, total, processed, models.AsyncTaskStatusRunning); err != nil {
+		return fmt.Errorf("更新异步任务进度失败: %w", err)
+	}
+	return nil
+}
+
+// Finish 保存执行中任务的执行结果，任务已不在执行中（如已被判定超时）时返回 ErrAsyncTaskNotFound
+func (r *asyncTaskRepository) Finish(ctx context.Context, id string, outcome *models.AsyncTaskOutcome) error {
+	var fileName, fileType string
+	var fileData []byte
+	var fileSize int64
+	if outcome.File != nil {
+		fileName, fileType, fileData = outcome.File.Name, outcome.File.ContentType, outcome.File.Data
+		fileSize = int64(len(fileData))
+	}
+
+	query := `
+		UPDATE async_tasks
+		SET status = $2, processed = GREATEST(processed, $3), total = GREATEST(total, $3), result = $4,
+			result_file = $5, result_file_name = $6, result_file_type = $7, result_file_size = $8,
+			error = $9, finished_at = NOW()
+		WHERE id = $1 AND status = $10`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, id, outcome.Status, outcome.Processed, nullJSON(outcome.Result),
+		fileData, fileName, fileType, fileSize, outcome.Error, models.AsyncTaskStatusRunning)
+	if err != nil {
+		return fmt.Errorf("保存异步任务结果失败: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAsyncTaskNotFound
+	}
+	return nil
+}
+
+// FailStale 将 startedBefore 之前开始仍在执行中的任务标记为失败
+func (r *asyncTaskRepository) FailStale(ctx context.Context, startedBefore time.Time, reason string) (int64, error) {
+	query := `
+		UPDATE async_tasks SET status = $1, error = $2, finished_at = NOW()
+		WHERE status = $3 AND started_at < $4`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, models.AsyncTaskStatusFailed, reason, models.AsyncTaskStatusRunning, startedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("标记超时异步任务失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteFinished 删除 finishedBefore 之前结束的任务及其结果文件
+func (r *asyncTaskRepository) DeleteFinished(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM async_tasks WHERE finished_at < $1`, finishedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("清理异步任务失败: %w", err)
+	}
+	return result.RowsAffected()
+}
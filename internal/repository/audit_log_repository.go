@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// auditLogRepository 审计日志仓储实现
+type auditLogRepository struct {
+	db     *sqlx.DB
+	tx     *sqlx.Tx
+	reader Reader
+}
+
+// NewAuditLogRepository 创建审计日志仓储实例
+func NewAuditLogRepository(db *sqlx.DB) AuditLogRepository {
+	return NewAuditLogRepositoryWithReader(db, db)
+}
+
+// NewAuditLogRepositoryWithReader 创建审计日志仓储实例，写入使用 db，查询使用 reader（通常为只读副本）
+func NewAuditLogRepositoryWithReader(db *sqlx.DB, reader Reader) AuditLogRepository {
+	return &auditLogRepository{db: db, reader: reader}
+}
+
+// NewAuditLogRepositoryWithTx 创建带事务的审计日志仓储实例
+func NewAuditLogRepositoryWithTx(tx *sqlx.Tx) AuditLogRepository {
+	return &auditLogRepository{tx: tx}
+}
+
+// getExecutor 获取写入执行器（事务或普通连接）
+func (r *auditLogRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// readExecutor 获取只读查询执行器
+func (r *auditLogRepository) readExecutor() Reader {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.reader
+}
+
+const auditLogColumns = `id, user_id, action, resource_type, resource_id, ip_address::text, user_agent, request_id,
+		old_values, new_values, changes, success, error_message, metadata, created_at`
+
+// Create 写入一条审计日志，用于不在业务变更事务中的操作，如模拟登录
+func (r *auditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	return insertAuditLog(ctx, r.getExecutor(), log)
+}
+
+// Stream 按创建时间倒序逐条读取符合条件的审计日志，fn 返回错误时停止读取
+func (r *auditLogRepository) Stream(ctx context.Context, filter *models.AuditLogFilter, fn func(*models.AuditLog) error) error {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter != nil {
+		if filter.UserID != nil {
+			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
+			args = append(args, *filter.UserID)
+			argIndex++
+		}
+
+		if filter.Action != nil {
+			conditions = append(conditions, fmt.Sprintf("action = $%d", argIndex))
+			args = append(args, *filter.Action)
+			argIndex++
+		}
+
+		if filter.ResourceType != nil {
+			conditions = append(conditions, fmt.Sprintf("resource_type = $%d", argIndex))
+			args = append(args, *filter.ResourceType)
+			argIndex++
+		}
+
+		if filter.ResourceID != nil {
+			conditions = append(conditions, fmt.Sprintf("resource_id = $%d", argIndex))
+			args = append(args, *filter.ResourceID)
+			argIndex++
+		}
+
+		if filter.Success != nil {
+			conditions = append(conditions, fmt.Sprintf("success = $%d", argIndex))
+			args = append(args, *filter.Success)
+			argIndex++
+		}
+
+		if filter.StartTime != nil {
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
+			args = append(args, *filter.StartTime)
+			argIndex++
+		}
+
+		if filter.EndTime != nil {
+			conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argIndex))
+			args = append(args, *filter.EndTime)
+		}
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM user_audit_logs %s ORDER BY created_at DESC", auditLogColumns, whereClause)
+	return streamCursor(ctx, r.readExecutor(), query, args, func(rows *sqlx.Rows) error {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		return fn(log)
+	})
+}
+
+// scanAuditLog 扫描 auditLogColumns 对应的一行审计日志
+func scanAuditLog(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AuditLog, error) {
+	var log models.AuditLog
+	var oldValues, newValues, changes, metadata []byte
+	err := scanner.Scan(
+		&log.ID, &log.UserID, &log.Action, &log.ResourceType, &log.ResourceID, &log.IPAddress,
+		&log.UserAgent, &log.RequestID, &oldValues, &newValues, &changes, &log.Success,
+		&log.ErrorMessage, &metadata, &log.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("扫描审计日志失败: %w", err)
+	}
+
+	log.OldValues = rawJSON(oldValues)
+	log.NewValues = rawJSON(newValues)
+	log.Changes = rawJSON(changes)
+	log.Metadata = rawJSON(metadata)
+	return &log, nil
+}
+
+// rawJSON 复制 JSONB 列的值，列为 NULL 时返回 nil
+func rawJSON(data []byte) json.RawMessage {
+	if data == nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), data...)
+}
+
+// nullJSON 将空的 JSON 值写为 NULL
+func nullJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return []byte(data)
+}
+
+// insertAuditLog 写入一条审计日志，调用方在业务变更的事务中调用，审计记录与变更同时提交
+func insertAuditLog(ctx context.Context, exec sqlx.ExtContext, log *models.AuditLog) error {
+	query := `
+		INSERT INTO user_audit_logs (user_id, action, resource_type, resource_id, new_values, success, error_message, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8::jsonb, '{}'::jsonb))`
+
+	_, err := exec.ExecContext(ctx, query, log.UserID, log.Action, log.ResourceType, log.ResourceID,
+		nullJSON(log.NewValues), log.Success, log.ErrorMessage, nullJSON(log.Metadata))
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
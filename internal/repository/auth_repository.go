@@ -113,7 +113,7 @@ func (r *authRepository) GetSession(ctx context.Context, sessionID string) (*mod
 	err := sqlx.GetContext(ctx, r.getExecutor(), &session, query, sessionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("会话不存在")
+			return nil, models.ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("获取用户会话失败: %w", err)
 	}
@@ -133,7 +133,7 @@ func (r *authRepository) GetSessionByToken(ctx context.Context, sessionToken str
 	err := sqlx.GetContext(ctx, r.getExecutor(), &session, query, sessionToken)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("会话不存在")
+			return nil, models.ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("获取用户会话失败: %w", err)
 	}
@@ -196,7 +196,7 @@ func (r *authRepository) UpdateSessionLastActivity(ctx context.Context, sessionI
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("会话不存在")
+		return models.ErrSessionNotFound
 	}
 
 	return nil
@@ -217,7 +217,7 @@ func (r *authRepository) DeleteSession(ctx context.Context, sessionID string) er
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("会话不存在")
+		return models.ErrSessionNotFound
 	}
 
 	return nil
@@ -292,7 +292,7 @@ func (r *authRepository) GetRefreshToken(ctx context.Context, tokenStr string) (
 	err := sqlx.GetContext(ctx, r.getExecutor(), &token, query, tokenStr)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("刷新令牌不存在")
+			return nil, models.ErrRefreshTokenNotFound
 		}
 		return nil, fmt.Errorf("获取刷新令牌失败: %w", err)
 	}
@@ -311,7 +311,7 @@ func (r *authRepository) GetRefreshTokenByID(ctx context.Context, tokenID string
 	err := sqlx.GetContext(ctx, r.getExecutor(), &token, query, tokenID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("刷新令牌不存在")
+			return nil, models.ErrRefreshTokenNotFound
 		}
 		return nil, fmt.Errorf("获取刷新令牌失败: %w", err)
 	}
@@ -373,7 +373,7 @@ func (r *authRepository) RevokeRefreshToken(ctx context.Context, tokenStr string
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("刷新令牌不存在或已被撤销")
+		return models.ErrRefreshTokenNotFound
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	// bulkInsertMaxParams PostgreSQL 单条语句的参数数量上限
+	bulkInsertMaxParams = 65535
+	// bulkCopyMinRows 行数达到该值时使用 COPY 导入，行数较少时多行 VALUES 的开销更小
+	bulkCopyMinRows = 500
+)
+
+// bulkInsert 在事务中批量插入记录，rows 中每行的值与 columns 一一对应。
+// 行数达到 bulkCopyMinRows 时使用 COPY FROM STDIN 导入，否则按参数上限分块使用多行 VALUES 插入
+func bulkInsert(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) >= bulkCopyMinRows {
+		return copyIn(ctx, tx, table, columns, rows)
+	}
+	return insertValues(ctx, tx, table, columns, rows)
+}
+
+// insertValues 按参数上限分块，每块使用一条多行 VALUES 的 INSERT 语句
+func insertValues(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]interface{}) error {
+	chunkSize := bulkInsertMaxParams / len(columns)
+	for start := 0; start < len(rows); start += chunkSize {
+		end := min(start+chunkSize, len(rows))
+
+		var query strings.Builder
+		fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for i, row := range rows[start:end] {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteByte('(')
+			for j, value := range row {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				args = append(args, value)
+				fmt.Fprintf(&query, "$%d", len(args))
+			}
+			query.WriteByte(')')
+		}
+
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyIn 使用 COPY FROM STDIN 导入，只能在事务中执行
+func copyIn(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]interface{}) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+	// 不带参数的 Exec 结束 COPY 并写入缓冲的数据
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
+
+// inTx 在事务中执行 fn。仓储已在事务中时直接使用该事务，由调用方提交；否则开启新事务，fn 成功后提交
+func inTx(ctx context.Context, db *sqlx.DB, tx *sqlx.Tx, fn func(tx *sqlx.Tx) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
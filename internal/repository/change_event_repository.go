@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// changeEventColumns 变更事件查询的列
+const changeEventColumns = `id, service, type, title, description, version, source, url, created_by, occurred_at, created_at`
+
+// changeEventRepository 变更事件仓储实现
+type changeEventRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewChangeEventRepository 创建变更事件仓储实例
+func NewChangeEventRepository(db *sqlx.DB) ChangeEventRepository {
+	return &changeEventRepository{db: db}
+}
+
+// NewChangeEventRepositoryWithTx 创建带事务的变更事件仓储实例
+func NewChangeEventRepositoryWithTx(tx *sqlx.Tx) ChangeEventRepository {
+	return &changeEventRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *changeEventRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 登记变更事件
+func (r *changeEventRepository) Create(ctx context.Context, event *models.ChangeEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	event.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO change_events (id, service, type, title, description, version, source, url, created_by, occurred_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		event.ID, event.Service, event.Type, event.Title, event.Description, event.Version, event.Source,
+		event.URL, event.CreatedBy, event.OccurredAt, event.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("登记变更事件失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取变更事件
+func (r *changeEventRepository) GetByID(ctx context.Context, id string) (*models.ChangeEvent, error) {
+	event := &models.ChangeEvent{}
+	err := sqlx.GetContext(ctx, r.getExecutor(), event, `SELECT `+changeEventColumns+` FROM change_events WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrChangeEventNotFound
+		}
+		return nil, fmt.Errorf("获取变更事件失败: %w", err)
+	}
+	return event, nil
+}
+
+// List 分页获取变更事件，按发生时间倒序排列
+func (r *changeEventRepository) List(ctx context.Context, filter *models.ChangeEventFilter) ([]*models.ChangeEvent, int64, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 1000 {
+		filter.PageSize = 1000
+	}
+
+	var conditions []string
+	var args []interface{}
+	if filter.Service != nil {
+		args = append(args, *filter.Service)
+		conditions = append(conditions, fmt.Sprintf("service = $%d", len(args)))
+	}
+	if filter.Type != nil {
+		args = append(args, *filter.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &total, `SELECT COUNT(*) FROM change_events`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("统计变更事件失败: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM change_events%s ORDER BY occurred_at DESC LIMIT $%d OFFSET $%d`,
+		changeEventColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	events := make([]*models.ChangeEvent, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &events, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("获取变更事件列表失败: %w", err)
+	}
+	return events, total, nil
+}
+
+// Delete 删除变更事件
+func (r *changeEventRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM change_events WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除变更事件失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrChangeEventNotFound
+	}
+	return nil
+}
+
+// ListForService 获取服务在 [from, to] 内发生的变更，按发生时间倒序
+func (r *changeEventRepository) ListForService(ctx context.Context, service string, from, to time.Time, limit int) ([]*models.ChangeEvent, error) {
+	query := `
+		SELECT ` + changeEventColumns + `
+		FROM change_events
+		WHERE service = $1 AND occurred_at >= $2 AND occurred_at <= $3
+		ORDER BY occurred_at DESC
+		LIMIT $4`
+
+	events := make([]*models.ChangeEvent, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &events, query, service, from, to, limit); err != nil {
+		return nil, fmt.Errorf("获取服务的变更事件失败: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var changeEventTestColumns = []string{"id", "service", "type", "title", "description", "version", "source", "url", "created_by", "occurred_at", "created_at"}
+
+func TestChangeEventRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewChangeEventRepository(sqlx.NewDb(db, "postgres"))
+	service := "checkout"
+	deployment := models.ChangeEventTypeDeployment
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM change_events WHERE service = \$1 AND type = \$2`).
+		WithArgs(service, deployment).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM change_events WHERE service = \$1 AND type = \$2 ORDER BY occurred_at DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(service, deployment, 20, 0).
+		WillReturnRows(sqlmock.NewRows(changeEventTestColumns).
+			AddRow("c1", service, "deployment", "升级支付 SDK", "", "v1.4.2", "argocd", nil, nil, now, now))
+
+	events, total, err := repo.List(context.Background(), &models.ChangeEventFilter{Service: &service, Type: &deployment})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, events, 1)
+	assert.Equal(t, "v1.4.2", events[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeEventRepository_GetAndDeleteNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewChangeEventRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`FROM change_events WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(changeEventTestColumns))
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrChangeEventNotFound)
+
+	mock.ExpectExec(`DELETE FROM change_events WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), models.ErrChangeEventNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// chatUserMappingRepository 聊天用户映射仓储实现
+type chatUserMappingRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewChatUserMappingRepository 创建聊天用户映射仓储实例
+func NewChatUserMappingRepository(db *sqlx.DB) ChatUserMappingRepository {
+	return &chatUserMappingRepository{db: db}
+}
+
+// NewChatUserMappingRepositoryWithTx 创建带事务的聊天用户映射仓储实例
+func NewChatUserMappingRepositoryWithTx(tx *sqlx.Tx) ChatUserMappingRepository {
+	return &chatUserMappingRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *chatUserMappingRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const chatUserMappingColumns = `id, platform, chat_user_id, user_id, created_by, created_at, updated_at`
+
+// Create 绑定聊天平台用户
+func (r *chatUserMappingRepository) Create(ctx context.Context, mapping *models.ChatUserMapping) error {
+	if mapping.ID == "" {
+		mapping.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	mapping.CreatedAt = now
+	mapping.UpdatedAt = now
+
+	query := `
+		INSERT INTO chat_user_mappings (
+			id, platform, chat_user_id, user_id, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		mapping.ID, mapping.Platform, mapping.ChatUserID, mapping.UserID,
+		mapping.CreatedBy, mapping.CreatedAt, mapping.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrChatUserMappingExists
+		}
+		return fmt.Errorf("绑定聊天用户失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByChatUser 根据聊天平台和聊天用户ID获取映射
+func (r *chatUserMappingRepository) GetByChatUser(ctx context.Context, platform models.ChatPlatform, chatUserID string) (*models.ChatUserMapping, error) {
+	query := `
+		SELECT ` + chatUserMappingColumns + `
+		FROM chat_user_mappings
+		WHERE platform = $1 AND chat_user_id = $2`
+
+	mapping, err := scanChatUserMapping(r.getExecutor().QueryRowxContext(ctx, query, platform, chatUserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrChatUserMappingNotFound
+		}
+		return nil, fmt.Errorf("获取聊天用户映射失败: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// List 获取聊天用户映射，platform 为空时返回全部平台
+func (r *chatUserMappingRepository) List(ctx context.Context, platform models.ChatPlatform) ([]*models.ChatUserMapping, error) {
+	query := `
+		SELECT ` + chatUserMappingColumns + `
+		FROM chat_user_mappings
+		WHERE ($1 = '' OR platform = $1)
+		ORDER BY platform, chat_user_id`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, platform)
+	if err != nil {
+		return nil, fmt.Errorf("获取聊天用户映射列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := make([]*models.ChatUserMapping, 0)
+	for rows.Next() {
+		mapping, err := scanChatUserMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描聊天用户映射失败: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+// Delete 解除聊天平台用户绑定
+func (r *chatUserMappingRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM chat_user_mappings WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("解除聊天用户绑定失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrChatUserMappingNotFound
+	}
+
+	return nil
+}
+
+// scanChatUserMapping 扫描聊天用户映射
+func scanChatUserMapping(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.ChatUserMapping, error) {
+	var mapping models.ChatUserMapping
+	err := scanner.Scan(
+		&mapping.ID, &mapping.Platform, &mapping.ChatUserID, &mapping.UserID,
+		&mapping.CreatedBy, &mapping.CreatedAt, &mapping.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
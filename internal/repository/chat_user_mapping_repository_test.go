@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var chatUserMappingTestColumns = []string{
+	"id", "platform", "chat_user_id", "user_id", "created_by", "created_at", "updated_at",
+}
+
+func TestChatUserMappingRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewChatUserMappingRepository(sqlx.NewDb(db, "postgres"))
+	mapping := &models.ChatUserMapping{
+		Platform:   models.ChatPlatformSlack,
+		ChatUserID: "U123",
+		UserID:     "u1",
+		CreatedBy:  "admin",
+	}
+
+	mock.ExpectExec(`INSERT INTO chat_user_mappings`).
+		WithArgs(sqlmock.AnyArg(), models.ChatPlatformSlack, "U123", "u1", "admin", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Create(context.Background(), mapping))
+	assert.NotEmpty(t, mapping.ID)
+
+	mock.ExpectExec(`INSERT INTO chat_user_mappings`).
+		WillReturnError(&pq.Error{Code: "23505"})
+	err = repo.Create(context.Background(), &models.ChatUserMapping{Platform: models.ChatPlatformSlack, ChatUserID: "U123"})
+	assert.ErrorIs(t, err, models.ErrChatUserMappingExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChatUserMappingRepository_GetByChatUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewChatUserMappingRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM chat_user_mappings\s+WHERE platform = \$1 AND chat_user_id = \$2`).
+		WithArgs(models.ChatPlatformDingTalk, "staff-1").
+		WillReturnRows(sqlmock.NewRows(chatUserMappingTestColumns).
+			AddRow("m1", "dingtalk", "staff-1", "u1", "admin", now, now))
+
+	mapping, err := repo.GetByChatUser(context.Background(), models.ChatPlatformDingTalk, "staff-1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", mapping.UserID)
+
+	mock.ExpectQuery(`SELECT .+ FROM chat_user_mappings`).
+		WithArgs(models.ChatPlatformDingTalk, "unknown").
+		WillReturnRows(sqlmock.NewRows(chatUserMappingTestColumns))
+
+	_, err = repo.GetByChatUser(context.Background(), models.ChatPlatformDingTalk, "unknown")
+	assert.ErrorIs(t, err, models.ErrChatUserMappingNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChatUserMappingRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewChatUserMappingRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`DELETE FROM chat_user_mappings WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrChatUserMappingNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
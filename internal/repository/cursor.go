@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// streamCursorName 流式查询使用的服务端游标名称，游标只在所属事务内可见
+	streamCursorName = "pulse_stream_cursor"
+	// streamFetchSize 每次从游标读取的行数
+	streamFetchSize = 1000
+)
+
+// txBeginner 可开启事务的只读查询执行器，*sqlx.DB 和数据库层的读路由器均满足该接口
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// streamCursor 通过服务端游标分批读取 query 的结果并逐行调用 fn，内存占用与结果总数无关。
+// reader 为事务时在该事务内声明游标，否则开启只读事务；reader 不支持事务时退化为普通查询
+func streamCursor(ctx context.Context, reader Reader, query string, args []interface{}, fn func(rows *sqlx.Rows) error) error {
+	if tx, ok := reader.(*sqlx.Tx); ok {
+		return fetchCursor(ctx, tx, query, args, fn)
+	}
+
+	beginner, ok := reader.(txBeginner)
+	if !ok {
+		rows, err := reader.QueryxContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("执行流式查询失败: %w", err)
+		}
+		defer rows.Close()
+		return eachRow(rows, fn)
+	}
+
+	tx, err := beginner.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("开启流式查询事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fetchCursor(ctx, tx, query, args, fn); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// fetchCursor 在事务内声明游标，每次读取 streamFetchSize 行直到读完
+func fetchCursor(ctx context.Context, tx *sqlx.Tx, query string, args []interface{}, fn func(rows *sqlx.Rows) error) error {
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", streamCursorName, query)
+	if _, err := tx.ExecContext(ctx, declare, args...); err != nil {
+		return fmt.Errorf("声明游标失败: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", streamFetchSize, streamCursorName)
+	for {
+		rows, err := tx.QueryxContext(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("读取游标失败: %w", err)
+		}
+
+		count := 0
+		err = eachRow(rows, func(rows *sqlx.Rows) error {
+			count++
+			return fn(rows)
+		})
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if count < streamFetchSize {
+			break
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "CLOSE "+streamCursorName); err != nil {
+		return fmt.Errorf("关闭游标失败: %w", err)
+	}
+	return nil
+}
+
+// eachRow 对每一行调用 fn
+func eachRow(rows *sqlx.Rows, fn func(rows *sqlx.Rows) error) error {
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历查询结果失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func auditLogRows() *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "user_id", "action", "resource_type", "resource_id", "ip_address", "user_agent", "request_id",
+		"old_values", "new_values", "changes", "success", "error_message", "metadata", "created_at",
+	}).
+		AddRow("l1", "u1", "login", nil, nil, "10.0.0.1", "curl", nil, nil, nil, nil, true, nil, []byte(`{}`), now).
+		AddRow("l2", "u1", "update", "alert", "a1", nil, nil, nil, []byte(`{"status":"firing"}`), nil, nil, false, "denied", nil, now)
+}
+
+func TestAuditLogRepository_Stream(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAuditLogRepository(sqlx.NewDb(db, "postgres"))
+
+	userID := "u1"
+	mock.ExpectBegin()
+	mock.ExpectExec(`DECLARE pulse_stream_cursor NO SCROLL CURSOR FOR SELECT .+ FROM user_audit_logs WHERE user_id = \$1 ORDER BY created_at DESC`).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FETCH FORWARD 1000 FROM pulse_stream_cursor`).WillReturnRows(auditLogRows())
+	mock.ExpectExec(`CLOSE pulse_stream_cursor`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var logs []*models.AuditLog
+	err = repo.Stream(context.Background(), &models.AuditLogFilter{UserID: &userID}, func(log *models.AuditLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "10.0.0.1", *logs[0].IPAddress)
+	assert.JSONEq(t, `{"status":"firing"}`, string(logs[1].OldValues))
+	assert.Nil(t, logs[1].Metadata)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditLogRepository_StreamStopsOnCallbackError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAuditLogRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DECLARE pulse_stream_cursor`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FETCH FORWARD 1000 FROM pulse_stream_cursor`).WillReturnRows(auditLogRows())
+	mock.ExpectRollback()
+
+	// 客户端断开等写入失败时停止读取并回滚只读事务
+	errWrite := errors.New("broken pipe")
+	calls := 0
+	err = repo.Stream(context.Background(), nil, func(log *models.AuditLog) error {
+		calls++
+		return errWrite
+	})
+	assert.ErrorIs(t, err, errWrite)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
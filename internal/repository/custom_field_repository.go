@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// customFieldRepository 自定义字段定义仓储实现
+type customFieldRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewCustomFieldRepository 创建自定义字段定义仓储实例
+func NewCustomFieldRepository(db *sqlx.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: db,
+	}
+}
+
+// NewCustomFieldRepositoryWithTx 创建带事务的自定义字段定义仓储实例
+func NewCustomFieldRepositoryWithTx(tx *sqlx.Tx) CustomFieldRepository {
+	return &customFieldRepository{
+		tx: tx,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *customFieldRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const customFieldColumns = `id, resource, field_key, name, description, field_type, options,
+		       validation, default_value, required, enabled, sort_order, created_by,
+		       created_at, updated_at`
+
+// Create 创建自定义字段定义
+func (r *customFieldRepository) Create(ctx context.Context, def *models.CustomFieldDefinition) error {
+	if def.ID == "" {
+		def.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	def.CreatedAt = now
+	def.UpdatedAt = now
+
+	optionsJSON, validationJSON, defaultJSON, err := marshalCustomFieldJSON(def)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO custom_field_definitions (
+			id, resource, field_key, name, description, field_type, options,
+			validation, default_value, required, enabled, sort_order, created_by,
+			created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		def.ID, def.Resource, def.Key, def.Name, def.Description, def.Type, optionsJSON,
+		validationJSON, defaultJSON, def.Required, def.Enabled, def.SortOrder, def.CreatedBy,
+		def.CreatedAt, def.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrCustomFieldExists
+		}
+		return fmt.Errorf("创建自定义字段定义失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取自定义字段定义
+func (r *customFieldRepository) GetByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error) {
+	query := `
+		SELECT ` + customFieldColumns + `
+		FROM custom_field_definitions
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	def, err := scanCustomField(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrCustomFieldNotFound
+		}
+		return nil, fmt.Errorf("获取自定义字段定义失败: %w", err)
+	}
+
+	return def, nil
+}
+
+// GetByKey 根据资源类型和字段键获取自定义字段定义
+func (r *customFieldRepository) GetByKey(ctx context.Context, resource models.CustomFieldResource, key string) (*models.CustomFieldDefinition, error) {
+	query := `
+		SELECT ` + customFieldColumns + `
+		FROM custom_field_definitions
+		WHERE resource = $1 AND field_key = $2 AND deleted_at IS NULL`
+
+	def, err := scanCustomField(r.getExecutor().QueryRowxContext(ctx, query, resource, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrCustomFieldNotFound
+		}
+		return nil, fmt.Errorf("获取自定义字段定义失败: %w", err)
+	}
+
+	return def, nil
+}
+
+// Update 更新自定义字段定义
+func (r *customFieldRepository) Update(ctx context.Context, def *models.CustomFieldDefinition) error {
+	def.UpdatedAt = time.Now()
+
+	optionsJSON, validationJSON, defaultJSON, err := marshalCustomFieldJSON(def)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE custom_field_definitions SET
+			name = $1,
+			description = $2,
+			options = $3,
+			validation = $4,
+			default_value = $5,
+			required = $6,
+			enabled = $7,
+			sort_order = $8,
+			updated_at = $9
+		WHERE id = $10 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		def.Name, def.Description, optionsJSON, validationJSON, defaultJSON,
+		def.Required, def.Enabled, def.SortOrder, def.UpdatedAt, def.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新自定义字段定义失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrCustomFieldNotFound
+	}
+
+	return nil
+}
+
+// Delete 软删除自定义字段定义
+func (r *customFieldRepository) Delete(ctx context.Context, id string) error {
+	query := `
+		UPDATE custom_field_definitions SET
+			deleted_at = $1,
+			updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除自定义字段定义失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrCustomFieldNotFound
+	}
+
+	return nil
+}
+
+// List 获取自定义字段定义列表
+func (r *customFieldRepository) List(ctx context.Context, filter *models.CustomFieldDefinitionFilter) (*models.CustomFieldDefinitionList, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	argIndex := 1
+
+	if filter == nil {
+		filter = &models.CustomFieldDefinitionFilter{}
+	}
+
+	if filter.Resource != nil {
+		conditions = append(conditions, fmt.Sprintf("resource = $%d", argIndex))
+		args = append(args, *filter.Resource)
+		argIndex++
+	}
+
+	if filter.Enabled != nil {
+		conditions = append(conditions, fmt.Sprintf("enabled = $%d", argIndex))
+		args = append(args, *filter.Enabled)
+		argIndex++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM custom_field_definitions " + whereClause
+	if err := r.getExecutor().QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("获取自定义字段定义总数失败: %w", err)
+	}
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM custom_field_definitions
+		%s
+		ORDER BY resource, sort_order, created_at
+		LIMIT $%d OFFSET $%d`, customFieldColumns, whereClause, argIndex, argIndex+1)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取自定义字段定义列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	definitions := make([]*models.CustomFieldDefinition, 0)
+	for rows.Next() {
+		def, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描自定义字段定义数据失败: %w", err)
+		}
+		definitions = append(definitions, def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历自定义字段定义数据失败: %w", err)
+	}
+
+	return &models.CustomFieldDefinitionList{
+		Definitions: definitions,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+	}, nil
+}
+
+// ListByResource 获取资源类型下启用的自定义字段定义
+func (r *customFieldRepository) ListByResource(ctx context.Context, resource models.CustomFieldResource) ([]*models.CustomFieldDefinition, error) {
+	query := `
+		SELECT ` + customFieldColumns + `
+		FROM custom_field_definitions
+		WHERE resource = $1 AND enabled = true AND deleted_at IS NULL
+		ORDER BY sort_order, created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, resource)
+	if err != nil {
+		return nil, fmt.Errorf("获取自定义字段定义失败: %w", err)
+	}
+	defer rows.Close()
+
+	definitions := make([]*models.CustomFieldDefinition, 0)
+	for rows.Next() {
+		def, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描自定义字段定义数据失败: %w", err)
+		}
+		definitions = append(definitions, def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历自定义字段定义数据失败: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// ExistsByKey 检查资源类型下字段键是否已存在
+func (r *customFieldRepository) ExistsByKey(ctx context.Context, resource models.CustomFieldResource, key string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM custom_field_definitions WHERE resource = $1 AND field_key = $2 AND deleted_at IS NULL`
+	err := r.getExecutor().QueryRowxContext(ctx, query, resource, key).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("检查自定义字段是否存在失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// marshalCustomFieldJSON 序列化自定义字段定义中的JSON字段
+func marshalCustomFieldJSON(def *models.CustomFieldDefinition) (string, string, []byte, error) {
+	options := def.Options
+	if options == nil {
+		options = []string{}
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("序列化可选值失败: %w", err)
+	}
+
+	validationJSON, err := json.Marshal(def.Validation)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("序列化校验规则失败: %w", err)
+	}
+
+	var defaultJSON []byte
+	if def.DefaultValue != nil {
+		defaultJSON, err = json.Marshal(def.DefaultValue)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("序列化默认值失败: %w", err)
+		}
+	}
+
+	return string(optionsJSON), string(validationJSON), defaultJSON, nil
+}
+
+// scanCustomField 扫描自定义字段定义
+func scanCustomField(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.CustomFieldDefinition, error) {
+	var def models.CustomFieldDefinition
+	var optionsJSON, validationJSON, defaultJSON sql.NullString
+
+	err := scanner.Scan(
+		&def.ID, &def.Resource, &def.Key, &def.Name, &def.Description, &def.Type, &optionsJSON,
+		&validationJSON, &defaultJSON, &def.Required, &def.Enabled, &def.SortOrder, &def.CreatedBy,
+		&def.CreatedAt, &def.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if optionsJSON.Valid && optionsJSON.String != "" {
+		if err := json.Unmarshal([]byte(optionsJSON.String), &def.Options); err != nil {
+			return nil, fmt.Errorf("反序列化可选值失败: %w", err)
+		}
+	}
+
+	if validationJSON.Valid && validationJSON.String != "" {
+		if err := json.Unmarshal([]byte(validationJSON.String), &def.Validation); err != nil {
+			return nil, fmt.Errorf("反序列化校验规则失败: %w", err)
+		}
+	}
+
+	if defaultJSON.Valid && defaultJSON.String != "" {
+		if err := json.Unmarshal([]byte(defaultJSON.String), &def.DefaultValue); err != nil {
+			return nil, fmt.Errorf("反序列化默认值失败: %w", err)
+		}
+	}
+
+	return &def, nil
+}
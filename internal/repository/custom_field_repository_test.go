@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var customFieldTestColumns = []string{
+	"id", "resource", "field_key", "name", "description", "field_type", "options",
+	"validation", "default_value", "required", "enabled", "sort_order", "created_by",
+	"created_at", "updated_at",
+}
+
+func TestCustomFieldRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewCustomFieldRepository(sqlxDB)
+
+	def := &models.CustomFieldDefinition{
+		Resource: models.CustomFieldResourceTicket,
+		Key:      "environment",
+		Name:     "环境",
+		Type:     models.CustomFieldTypeSelect,
+		Options:  []string{"prod", "staging"},
+		Required: true,
+		Enabled:  true,
+	}
+
+	mock.ExpectExec(`INSERT INTO custom_field_definitions`).
+		WithArgs(
+			sqlmock.AnyArg(), def.Resource, def.Key, def.Name, sqlmock.AnyArg(), def.Type,
+			`["prod","staging"]`, sqlmock.AnyArg(), sqlmock.AnyArg(), true, true, 0, sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(context.Background(), def)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, def.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCustomFieldRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewCustomFieldRepository(sqlxDB)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM custom_field_definitions`).
+		WithArgs("field-1").
+		WillReturnRows(sqlmock.NewRows(customFieldTestColumns).AddRow(
+			"field-1", "ticket", "environment", "环境", nil, "select", `["prod","staging"]`,
+			`{"max_length":10}`, `"prod"`, true, true, 1, nil, now, now,
+		))
+
+	def, err := repo.GetByID(context.Background(), "field-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CustomFieldResourceTicket, def.Resource)
+	assert.Equal(t, []string{"prod", "staging"}, def.Options)
+	require.NotNil(t, def.Validation.MaxLength)
+	assert.Equal(t, 10, *def.Validation.MaxLength)
+	assert.Equal(t, "prod", def.DefaultValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCustomFieldRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewCustomFieldRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT (.+) FROM custom_field_definitions`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	def, err := repo.GetByID(context.Background(), "missing")
+	assert.Nil(t, def)
+	assert.ErrorIs(t, err, models.ErrCustomFieldNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCustomFieldRepository_ListByResource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewCustomFieldRepository(sqlxDB)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM custom_field_definitions\s+WHERE resource = \$1 AND enabled = true`).
+		WithArgs(models.CustomFieldResourceTicket).
+		WillReturnRows(sqlmock.NewRows(customFieldTestColumns).
+			AddRow("field-1", "ticket", "environment", "环境", nil, "select", `["prod"]`, `{}`, nil, true, true, 1, nil, now, now).
+			AddRow("field-2", "ticket", "customer", "客户", nil, "text", `[]`, `{}`, nil, false, true, 2, nil, now, now))
+
+	definitions, err := repo.ListByResource(context.Background(), models.CustomFieldResourceTicket)
+	require.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, "environment", definitions[0].Key)
+	assert.Nil(t, definitions[1].DefaultValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCustomFieldRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewCustomFieldRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE custom_field_definitions SET`).
+		WithArgs(sqlmock.AnyArg(), "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrCustomFieldNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
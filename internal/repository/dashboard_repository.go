@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// 仪表盘统计口径
+const (
+	// dashboardActiveAlertStatuses 活跃告警状态
+	dashboardActiveAlertStatuses = `('firing', 'acked')`
+
+	// dashboardClosedTicketStatuses 视为已关闭的工单状态
+	dashboardClosedTicketStatuses = `('resolved', 'closed', 'cancelled')`
+)
+
+// dashboardRepository 首页仪表盘统计仓储实现，所有查询均为只读
+type dashboardRepository struct {
+	reader Reader
+}
+
+// NewDashboardRepository 创建仪表盘统计仓储实例
+func NewDashboardRepository(db *sqlx.DB) DashboardRepository {
+	return NewDashboardRepositoryWithReader(db)
+}
+
+// NewDashboardRepositoryWithReader 创建仪表盘统计仓储实例，查询使用 reader（通常为只读副本）
+func NewDashboardRepositoryWithReader(reader Reader) DashboardRepository {
+	return &dashboardRepository{reader: reader}
+}
+
+// NewDashboardRepositoryWithTx 创建带事务的仪表盘统计仓储实例
+func NewDashboardRepositoryWithTx(tx *sqlx.Tx) DashboardRepository {
+	return &dashboardRepository{reader: tx}
+}
+
+// GetActiveAlertsBySeverity 按严重级别统计活跃告警
+func (r *dashboardRepository) GetActiveAlertsBySeverity(ctx context.Context) (map[models.AlertSeverity]int64, error) {
+	query := `
+		SELECT severity, COUNT(*)
+		FROM alerts
+		WHERE status IN ` + dashboardActiveAlertStatuses + ` AND deleted_at IS NULL
+		GROUP BY severity`
+
+	rows, err := r.reader.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("统计活跃告警失败: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[models.AlertSeverity]int64)
+	for rows.Next() {
+		var severity models.AlertSeverity
+		var count int64
+		if err := rows.Scan(&severity, &count); err != nil {
+			return nil, fmt.Errorf("扫描活跃告警统计失败: %w", err)
+		}
+		result[severity] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历活跃告警统计失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetOpenTicketsByPriority 按优先级统计未关闭工单
+func (r *dashboardRepository) GetOpenTicketsByPriority(ctx context.Context) (map[models.TicketPriority]int64, error) {
+	query := `
+		SELECT priority, COUNT(*)
+		FROM tickets
+		WHERE status NOT IN ` + dashboardClosedTicketStatuses + ` AND deleted_at IS NULL
+		GROUP BY priority`
+
+	rows, err := r.reader.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("统计未关闭工单失败: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[models.TicketPriority]int64)
+	for rows.Next() {
+		var priority models.TicketPriority
+		var count int64
+		if err := rows.Scan(&priority, &count); err != nil {
+			return nil, fmt.Errorf("扫描未关闭工单统计失败: %w", err)
+		}
+		result[priority] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历未关闭工单统计失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetSLASummary 统计未关闭工单中SLA已超时和即将超时的数量，SLA暂停中的工单不计入
+func (r *dashboardRepository) GetSLASummary(ctx context.Context, atRiskWithin time.Duration) (*models.DashboardSLASummary, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE sla_deadline < $1) AS breached,
+			COUNT(*) FILTER (WHERE sla_deadline >= $1 AND sla_deadline < $2) AS at_risk
+		FROM tickets
+		WHERE sla_deadline IS NOT NULL
+		  AND status NOT IN ` + dashboardClosedTicketStatuses + `
+		  AND deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM ticket_sla_pauses p WHERE p.ticket_id = tickets.id AND p.resumed_at IS NULL)`
+
+	now := time.Now()
+	var summary models.DashboardSLASummary
+	if err := r.reader.QueryRowxContext(ctx, query, now, now.Add(atRiskWithin)).Scan(&summary.Breached, &summary.AtRisk); err != nil {
+		return nil, fmt.Errorf("统计SLA风险失败: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// GetRecentIncidents 获取最近创建的事件类工单
+func (r *dashboardRepository) GetRecentIncidents(ctx context.Context, limit int) ([]*models.DashboardIncident, error) {
+	query := `
+		SELECT id, number, title, status, priority, assignee_id, sla_deadline, created_at
+		FROM tickets
+		WHERE type = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	incidents := []*models.DashboardIncident{}
+	if err := r.reader.SelectContext(ctx, &incidents, query, models.TicketTypeIncident, limit); err != nil {
+		return nil, fmt.Errorf("获取最近事件失败: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// GetTopRules 获取统计窗口内产生告警最多的规则
+func (r *dashboardRepository) GetTopRules(ctx context.Context, since time.Time, limit int) ([]*models.DashboardRuleStat, error) {
+	query := `
+		SELECT r.id AS rule_id, r.name, r.severity, COUNT(a.id) AS alert_count
+		FROM alerts a
+		JOIN rules r ON r.id = a.rule_id
+		WHERE a.starts_at >= $1 AND a.deleted_at IS NULL AND r.deleted_at IS NULL
+		GROUP BY r.id, r.name, r.severity
+		ORDER BY alert_count DESC, r.name
+		LIMIT $2`
+
+	rules := []*models.DashboardRuleStat{}
+	if err := r.reader.SelectContext(ctx, &rules, query, since, limit); err != nil {
+		return nil, fmt.Errorf("获取告警最多的规则失败: %w", err)
+	}
+
+	return rules, nil
+}
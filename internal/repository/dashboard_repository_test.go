@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestDashboardRepository_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDashboardRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT severity, COUNT\(\*\)\s+FROM alerts\s+WHERE status IN \('firing', 'acked'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"severity", "count"}).
+			AddRow("critical", 2).
+			AddRow("high", 5))
+
+	alerts, err := repo.GetActiveAlertsBySeverity(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.AlertSeverity]int64{
+		models.AlertSeverityCritical: 2,
+		models.AlertSeverityHigh:     5,
+	}, alerts)
+
+	mock.ExpectQuery(`SELECT priority, COUNT\(\*\)\s+FROM tickets\s+WHERE status NOT IN`).
+		WillReturnRows(sqlmock.NewRows([]string{"priority", "count"}).AddRow("urgent", 1))
+
+	tickets, err := repo.GetOpenTicketsByPriority(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tickets[models.TicketPriorityUrgent])
+
+	mock.ExpectQuery(`FILTER \(WHERE sla_deadline < \$1\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"breached", "at_risk"}).AddRow(3, 4))
+
+	sla, err := repo.GetSLASummary(ctx, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &models.DashboardSLASummary{AtRisk: 4, Breached: 3}, sla)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDashboardRepository_Lists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDashboardRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM tickets\s+WHERE type = \$1 .+ ORDER BY created_at DESC\s+LIMIT \$2`).
+		WithArgs(models.TicketTypeIncident, 5).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "number", "title", "status", "priority", "assignee_id", "sla_deadline", "created_at",
+		}).AddRow("t1", "INC-1", "数据库主从切换", "open", "urgent", nil, nil, now))
+
+	incidents, err := repo.GetRecentIncidents(ctx, 5)
+	require.NoError(t, err)
+	require.Len(t, incidents, 1)
+	assert.Equal(t, "INC-1", incidents[0].Number)
+	assert.Equal(t, models.TicketPriorityUrgent, incidents[0].Priority)
+
+	since := now.Add(-24 * time.Hour)
+	mock.ExpectQuery(`FROM alerts a\s+JOIN rules r ON r.id = a.rule_id`).
+		WithArgs(since, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"rule_id", "name", "severity", "alert_count"}).
+			AddRow("r1", "cpu_high", "high", 42))
+
+	rules, err := repo.GetTopRules(ctx, since, 5)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, int64(42), rules[0].AlertCount)
+
+	// 无数据时返回空列表，便于前端直接渲染
+	mock.ExpectQuery(`FROM alerts a`).
+		WithArgs(since, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"rule_id", "name", "severity", "alert_count"}))
+
+	rules, err = repo.GetTopRules(ctx, since, 5)
+	require.NoError(t, err)
+	assert.NotNil(t, rules)
+	assert.Empty(t, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
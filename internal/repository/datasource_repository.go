@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -18,29 +21,43 @@ import (
 
 	"pulse/internal/models"
 	"pulse/internal/crypto"
+	"pulse/internal/kubernetes"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/redact"
 )
 
+// kafkaTLSAlertWait Kafka 连接测试在 TLS 握手后等待服务端拒绝客户端证书的时间
+const kafkaTLSAlertWait = 200 * time.Millisecond
+
 // dataSourceRepository 数据源仓储实现
 type dataSourceRepository struct {
 	db *sqlx.DB
 	tx *sqlx.Tx
 	encryptionService crypto.EncryptionService
+	httpClients       *httpclient.Factory
 }
 
-// NewDataSourceRepository 创建新的数据源仓储实例
+// NewDataSourceRepository 创建新的数据源仓储实例，连接测试使用默认的超时和重试策略
 func NewDataSourceRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) DataSourceRepository {
+	return NewDataSourceRepositoryWithHTTPClients(db, encryptionService, nil)
+}
+
+// NewDataSourceRepositoryWithHTTPClients 创建新的数据源仓储实例，连接测试的 HTTP 客户端由 httpClients 按数据源的重试策略创建
+func NewDataSourceRepositoryWithHTTPClients(db *sqlx.DB, encryptionService crypto.EncryptionService, httpClients *httpclient.Factory) DataSourceRepository {
 	return &dataSourceRepository{
 		db: db,
 		encryptionService: encryptionService,
+		httpClients:       httpClients,
 	}
 }
 
 // NewDataSourceRepositoryWithTx 创建带事务的数据源仓储实例
-func NewDataSourceRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) DataSourceRepository {
+func NewDataSourceRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService, httpClients *httpclient.Factory) DataSourceRepository {
 	return &dataSourceRepository{
 		db: nil, // 事务模式下不使用db
 		tx: tx,
 		encryptionService: encryptionService,
+		httpClients:       httpClients,
 	}
 }
 
@@ -123,6 +140,9 @@ func (r *dataSourceRepository) Create(ctx context.Context, dataSource *models.Da
 		}
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrDataSourceExists
+		}
 		return fmt.Errorf("创建数据源失败: %w", err)
 	}
 
@@ -704,6 +724,13 @@ func (r *dataSourceRepository) TestConnection(ctx context.Context, dataSource *m
 		ResponseTime: 0,
 		Metadata:     make(map[string]interface{}),
 	}
+	// 驱动返回的错误可能包含连接串中的密码，返回和保存前脱敏
+	defer func() {
+		if result.Error != nil {
+			errorMsg := redact.String(*result.Error)
+			result.Error = &errorMsg
+		}
+	}()
 
 	// 解密配置（如果需要）
 	config := dataSource.Config
@@ -720,60 +747,89 @@ func (r *dataSourceRepository) TestConnection(ctx context.Context, dataSource *m
 		config = configCopy
 	}
 
-	// 设置超时
-	timeout := 30 * time.Second
-	if config.Timeout != nil {
-		timeout = *config.Timeout
+	// 数据库类连接测试使用策略的单次超时，HTTP 类连接测试由客户端控制每次尝试的超时和重试，并使用数据源的代理和CA证书
+	policy := r.httpClients.Resolve(config.HTTPPolicy())
+	client, err := r.httpClients.ClientWithOptions(config.TransportOptions(), policy)
+	if err != nil {
+		errorMsg := err.Error()
+		result.Error = &errorMsg
+		result.Message = "连接选项无效"
+		return result, nil
 	}
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	// 客户端证书过期时双向认证必然失败，直接返回明确的错误
+	if cert, err := config.TransportOptions().ClientCertificateInfo(); err == nil && cert != nil {
+		result.Metadata["client_cert_expires_at"] = cert.NotAfter
+		if time.Now().After(cert.NotAfter) {
+			errorMsg := fmt.Sprintf("客户端证书已于 %s 过期", cert.NotAfter.Format(time.RFC3339))
+			result.Error = &errorMsg
+			result.Message = "客户端证书已过期"
+			return result, nil
+		}
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
 	defer cancel()
 
 	// 根据数据源类型进行连接测试
 	switch dataSource.Type {
 	case models.DataSourceTypeMySQL:
-		err := r.testMySQLConnection(ctx, &config, result)
+		err := r.testMySQLConnection(timeoutCtx, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "MySQL连接失败"
 		}
 	case models.DataSourceTypePostgreSQL:
-		err := r.testPostgreSQLConnection(ctx, &config, result)
+		err := r.testPostgreSQLConnection(timeoutCtx, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "PostgreSQL连接失败"
 		}
 	case models.DataSourceTypeRedis:
-		err := r.testRedisConnection(ctx, &config, result)
+		err := r.testRedisConnection(timeoutCtx, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "Redis连接失败"
 		}
 	case models.DataSourceTypePrometheus:
-		err := r.testPrometheusConnection(ctx, &config, result)
+		err := r.testPrometheusConnection(ctx, client, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "Prometheus连接失败"
 		}
 	case models.DataSourceTypeInfluxDB:
-		err := r.testInfluxDBConnection(ctx, &config, result)
+		err := r.testInfluxDBConnection(ctx, client, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "InfluxDB连接失败"
 		}
 	case models.DataSourceTypeElastic:
-		err := r.testElasticsearchConnection(ctx, &config, result)
+		err := r.testElasticsearchConnection(ctx, client, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
 			result.Message = "Elasticsearch连接失败"
 		}
+	case models.DataSourceTypeKafka:
+		err := r.testKafkaConnection(timeoutCtx, &config, result)
+		if err != nil {
+			errorMsg := err.Error()
+			result.Error = &errorMsg
+			result.Message = "Kafka连接失败"
+		}
+	case models.DataSourceTypeKubernetes:
+		err := r.testKubernetesConnection(timeoutCtx, &config, policy.Timeout, result)
+		if err != nil {
+			errorMsg := err.Error()
+			result.Error = &errorMsg
+			result.Message = "Kubernetes API Server连接失败"
+		}
 	default:
-		err := r.testHTTPConnection(ctx, &config, result)
+		err := r.testHTTPConnection(ctx, client, &config, result)
 		if err != nil {
 			errorMsg := err.Error()
 			result.Error = &errorMsg
@@ -919,14 +975,10 @@ func (r *dataSourceRepository) testRedisConnection(ctx context.Context, config *
 }
 
 // testPrometheusConnection 测试Prometheus连接
-func (r *dataSourceRepository) testPrometheusConnection(ctx context.Context, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
+func (r *dataSourceRepository) testPrometheusConnection(ctx context.Context, client *http.Client, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
 	// 构建健康检查URL
 	healthURL := strings.TrimSuffix(config.URL, "/") + "/-/healthy"
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
@@ -959,14 +1011,10 @@ func (r *dataSourceRepository) testPrometheusConnection(ctx context.Context, con
 }
 
 // testInfluxDBConnection 测试InfluxDB连接
-func (r *dataSourceRepository) testInfluxDBConnection(ctx context.Context, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
+func (r *dataSourceRepository) testInfluxDBConnection(ctx context.Context, client *http.Client, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
 	// 构建健康检查URL
 	healthURL := strings.TrimSuffix(config.URL, "/") + "/health"
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
@@ -994,14 +1042,10 @@ func (r *dataSourceRepository) testInfluxDBConnection(ctx context.Context, confi
 }
 
 // testElasticsearchConnection 测试Elasticsearch连接
-func (r *dataSourceRepository) testElasticsearchConnection(ctx context.Context, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
+func (r *dataSourceRepository) testElasticsearchConnection(ctx context.Context, client *http.Client, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
 	// 构建健康检查URL
 	healthURL := strings.TrimSuffix(config.URL, "/") + "/_cluster/health"
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
@@ -1026,12 +1070,105 @@ func (r *dataSourceRepository) testElasticsearchConnection(ctx context.Context,
 	return nil
 }
 
-// testHTTPConnection 测试通用HTTP连接
-func (r *dataSourceRepository) testHTTPConnection(ctx context.Context, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// testKubernetesConnection 测试 Kubernetes API Server 连接，检查就绪状态并获取版本
+func (r *dataSourceRepository) testKubernetesConnection(ctx context.Context, config *models.DataSourceConfig, timeout time.Duration, result *models.DataSourceTestResult) error {
+	clientConfig, err := kubernetes.ConfigFromDataSource(config)
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewClient(clientConfig, timeout)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Ready(ctx); err != nil {
+		return fmt.Errorf("API Server未就绪: %w", err)
+	}
+
+	version, err := client.Version(ctx)
+	if err == nil {
+		result.Version = &version.GitVersion
+		result.Metadata["version"] = version.GitVersion
+		result.Metadata["platform"] = version.Platform
+	}
+	result.Metadata["host"] = clientConfig.Host
+
+	return nil
+}
+
+// testKafkaConnection 测试Kafka连接，依次连接所有 broker。
+// URL 协议为 ssl、sasl_ssl 或配置了证书选项时使用 TLS 连接，完成握手以验证服务端证书和客户端证书
+func (r *dataSourceRepository) testKafkaConnection(ctx context.Context, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
+	scheme, hosts := "", config.URL
+	if i := strings.Index(hosts, "://"); i >= 0 {
+		scheme, hosts = strings.ToLower(hosts[:i]), hosts[i+3:]
+	}
+	hosts = strings.TrimRight(hosts, "/")
+
+	tlsConfig, err := r.httpClients.TLSConfig(config.TransportOptions())
+	if err != nil {
+		return err
+	}
+	if tlsConfig == nil && (scheme == "ssl" || scheme == "sasl_ssl" || scheme == "kafka+ssl") {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	brokers := 0
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "9092")
+		}
+		if err := testKafkaBroker(ctx, host, tlsConfig); err != nil {
+			return fmt.Errorf("连接broker %s 失败: %w", host, err)
+		}
+		brokers++
+	}
+	if brokers == 0 {
+		return errors.New("Kafka URL中没有broker地址")
 	}
 
+	result.Metadata["brokers"] = brokers
+	result.Metadata["tls"] = tlsConfig != nil
+	return nil
+}
+
+// testKafkaBroker 连接单个 broker，使用 TLS 时完成握手
+func testKafkaBroker(ctx context.Context, addr string, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	conn, err := (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("TLS握手失败: %w", err)
+	}
+	defer conn.Close()
+
+	// TLS 1.3 下服务端在握手完成后才校验客户端证书，校验失败时通过告警关闭连接，
+	// 短暂读取以捕获该告警，broker 不会主动发送数据，读取超时说明证书已被接受
+	if err := conn.SetReadDeadline(time.Now().Add(kafkaTLSAlertWait)); err != nil {
+		return err
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil
+		}
+		return fmt.Errorf("TLS认证失败: %w", err)
+	}
+	return nil
+}
+
+// testHTTPConnection 测试通用HTTP连接
+func (r *dataSourceRepository) testHTTPConnection(ctx context.Context, client *http.Client, config *models.DataSourceConfig, result *models.DataSourceTestResult) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", config.URL, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
@@ -1354,4 +1491,97 @@ func (r *dataSourceRepository) BatchHealthCheck(ctx context.Context, ids []strin
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *dataSourceRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// RotateEncryptionKeys 将所有数据源配置中的敏感字段重新加密为当前主密钥版本
+// 按ID分批处理，只更新密码和Token字段；更新时校验配置未被并发修改，被修改的数据源跳过，下次执行时重试
+func (r *dataSourceRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptionService.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+	exec := r.getExecutor()
+
+	selectQuery := `
+		SELECT id, COALESCE(auth_config::text, '{}') AS config
+		FROM data_sources
+		WHERE deleted_at IS NULL AND id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `
+		UPDATE data_sources SET auth_config = auth_config || $1::jsonb, updated_at = NOW()
+		WHERE id = $2 AND auth_config = $3::jsonb AND deleted_at IS NULL`
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID     string `db:"id"`
+			Config string `db:"config"`
+		}
+		if err := sqlx.SelectContext(ctx, exec, &rows, selectQuery, lastID, batchSize); err != nil {
+			return nil, fmt.Errorf("查询数据源配置失败: %w", err)
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			var config models.DataSourceConfig
+			if err := json.Unmarshal([]byte(row.Config), &config); err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+
+			changed, err := rotator.RotateDataSourceConfig(&config)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			patch := make(map[string]string)
+			if config.Password != nil {
+				patch["password"] = *config.Password
+			}
+			if config.Token != nil {
+				patch["token"] = *config.Token
+			}
+			patchJSON, err := json.Marshal(patch)
+			if err != nil {
+				return nil, fmt.Errorf("序列化配置失败: %w", err)
+			}
+
+			res, err := exec.ExecContext(ctx, updateQuery, string(patchJSON), row.ID, row.Config)
+			if err != nil {
+				return nil, fmt.Errorf("更新数据源配置失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
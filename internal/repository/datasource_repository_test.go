@@ -2,16 +2,28 @@ package repository
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"database/sql"
+	"encoding/pem"
 	"errors"
+	"io"
+	"math/big"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	testifymock "github.com/stretchr/testify/mock"
 
+	"pulse/internal/crypto"
 	"pulse/internal/models"
 )
 
@@ -739,4 +751,164 @@ func TestDataSourceRepository_List(t *testing.T) {
 			assert.NoError(t, sqlMock.ExpectationsWereMet())
 		})
 	}
-}
\ No newline at end of file
+}
+func TestDataSourceRepository_RotateEncryptionKeys(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{Keys: "v1:first,v2:second", LegacyKey: "jwt-secret"})
+	assert.NoError(t, err)
+
+	legacy, err := crypto.NewAESEncryptionService("jwt-secret").Encrypt("legacy-password")
+	assert.NoError(t, err)
+	current, err := keyManager.Encrypt("current-password")
+	assert.NoError(t, err)
+
+	legacyConfig := `{"url":"http://prometheus:9090","password":"` + legacy + `"}`
+	currentConfig := `{"url":"http://prometheus:9090","password":"` + current + `"}`
+	conflictConfig := `{"url":"http://prometheus:9090","token":"` + legacy + `"}`
+	brokenConfig := `{"url":"http://prometheus:9090","token":"enc:v1:v0:AAAA:AAAA"}`
+
+	mock.ExpectQuery(`SELECT id, COALESCE\(auth_config::text, '\{\}'\) AS config FROM data_sources`).
+		WithArgs("", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "config"}).
+			AddRow("ds-1", legacyConfig).
+			AddRow("ds-2", currentConfig).
+			AddRow("ds-3", conflictConfig).
+			AddRow("ds-4", brokenConfig))
+	mock.ExpectExec(`UPDATE data_sources SET auth_config = auth_config \|\| \$1::jsonb`).
+		WithArgs(sqlmock.AnyArg(), "ds-1", legacyConfig).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE data_sources SET auth_config = auth_config \|\| \$1::jsonb`).
+		WithArgs(sqlmock.AnyArg(), "ds-3", conflictConfig).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id, COALESCE\(auth_config::text, '\{\}'\) AS config FROM data_sources`).
+		WithArgs("ds-4", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "config"}))
+
+	repo := NewDataSourceRepository(db, keyManager)
+	result, err := repo.RotateEncryptionKeys(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.KeyRotationResult{
+		ActiveVersion: "v2",
+		Scanned:       4,
+		Rotated:       1,
+		Skipped:       1,
+		Failed:        1,
+		FailedIDs:     []string{"ds-4"},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataSourceRepository_RotateEncryptionKeys_Unsupported(t *testing.T) {
+	db, _ := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewDataSourceRepository(db, new(MockEncryptionService))
+	_, err := repo.RotateEncryptionKeys(context.Background(), 10)
+	assert.Error(t, err)
+}
+
+// testCertificate 生成由 parent 签发的证书，parent 为 nil 时生成自签名 CA
+func testCertificate(t *testing.T, parent *tls.Certificate, template *x509.Certificate) (tls.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	require.NoError(t, err)
+	cert.Leaf, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, certPEM, keyPEM
+}
+
+func TestDataSourceRepository_TestConnection_KafkaMutualTLS(t *testing.T) {
+	now := time.Now()
+	ca, caPEM, _ := testCertificate(t, nil, &x509.Certificate{
+		SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test-ca"},
+		NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour),
+		IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign,
+	})
+	server, _, _ := testCertificate(t, &ca, &x509.Certificate{
+		SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "broker"},
+		NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour),
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	_, clientCertPEM, clientKeyPEM := testCertificate(t, &ca, &x509.Certificate{
+		SerialNumber: big.NewInt(3), Subject: pkix.Name{CommonName: "pulse"},
+		NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	_, expiredCertPEM, expiredKeyPEM := testCertificate(t, &ca, &x509.Certificate{
+		SerialNumber: big.NewInt(4), Subject: pkix.Name{CommonName: "pulse"},
+		NotBefore: now.Add(-2 * time.Hour), NotAfter: now.Add(-time.Hour),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{server},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	kafka := func(certPEM, keyPEM *string) *models.DataSource {
+		return &models.DataSource{Type: models.DataSourceTypeKafka, Config: models.DataSourceConfig{
+			URL:        "ssl://" + listener.Addr().String(),
+			CACert:     &caPEM,
+			ClientCert: certPEM,
+			ClientKey:  keyPEM,
+		}}
+	}
+	repo := NewDataSourceRepository(nil, nil)
+	ctx := context.Background()
+
+	result, err := repo.TestConnection(ctx, kafka(&clientCertPEM, &clientKeyPEM))
+	require.NoError(t, err)
+	assert.True(t, result.Success, "%v", result.Error)
+	assert.Equal(t, true, result.Metadata["tls"])
+
+	// 未配置客户端证书时服务端拒绝连接
+	result, err = repo.TestConnection(ctx, kafka(nil, nil))
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "Kafka连接失败", result.Message)
+
+	result, err = repo.TestConnection(ctx, kafka(&expiredCertPEM, &expiredKeyPEM))
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "客户端证书已过期", result.Message)
+
+	// 证书和私钥不匹配
+	result, err = repo.TestConnection(ctx, kafka(&clientCertPEM, &expiredKeyPEM))
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "连接选项无效", result.Message)
+}
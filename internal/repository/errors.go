@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolationCode PostgreSQL 唯一约束冲突错误码
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation 判断数据库错误是否为唯一约束冲突
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// featureFlagRepository 功能开关仓储实现
+type featureFlagRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewFeatureFlagRepository 创建功能开关仓储实例
+func NewFeatureFlagRepository(db *sqlx.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+// NewFeatureFlagRepositoryWithTx 创建带事务的功能开关仓储实例
+func NewFeatureFlagRepositoryWithTx(tx *sqlx.Tx) FeatureFlagRepository {
+	return &featureFlagRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *featureFlagRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const featureFlagColumns = `id, flag_key, description, enabled, rollout_percentage, tenants, users,
+		       created_by, updated_by, created_at, updated_at`
+
+// Create 创建功能开关
+func (r *featureFlagRepository) Create(ctx context.Context, flag *models.FeatureFlag) error {
+	if flag.ID == "" {
+		flag.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	flag.CreatedAt = now
+	flag.UpdatedAt = now
+
+	query := `
+		INSERT INTO feature_flags (
+			id, flag_key, description, enabled, rollout_percentage, tenants, users,
+			created_by, updated_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		flag.ID, flag.Key, flag.Description, flag.Enabled, flag.RolloutPercentage,
+		pq.Array(flag.Tenants), pq.Array(flag.Users), flag.CreatedBy, flag.UpdatedBy,
+		flag.CreatedAt, flag.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrFeatureFlagExists
+		}
+		return fmt.Errorf("创建功能开关失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByKey 根据键获取功能开关
+func (r *featureFlagRepository) GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	query := `
+		SELECT ` + featureFlagColumns + `
+		FROM feature_flags
+		WHERE flag_key = $1`
+
+	flag, err := scanFeatureFlag(r.getExecutor().QueryRowxContext(ctx, query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrFeatureFlagNotFound
+		}
+		return nil, fmt.Errorf("获取功能开关失败: %w", err)
+	}
+
+	return flag, nil
+}
+
+// List 获取全部功能开关
+func (r *featureFlagRepository) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	query := `
+		SELECT ` + featureFlagColumns + `
+		FROM feature_flags
+		ORDER BY flag_key`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取功能开关列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	flags := make([]*models.FeatureFlag, 0)
+	for rows.Next() {
+		flag, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描功能开关失败: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, rows.Err()
+}
+
+// Update 更新功能开关，键不可修改
+func (r *featureFlagRepository) Update(ctx context.Context, flag *models.FeatureFlag) error {
+	flag.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE feature_flags SET
+			description = $1,
+			enabled = $2,
+			rollout_percentage = $3,
+			tenants = $4,
+			users = $5,
+			updated_by = $6,
+			updated_at = $7
+		WHERE flag_key = $8`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		flag.Description, flag.Enabled, flag.RolloutPercentage,
+		pq.Array(flag.Tenants), pq.Array(flag.Users), flag.UpdatedBy, flag.UpdatedAt, flag.Key,
+	)
+	if err != nil {
+		return fmt.Errorf("更新功能开关失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
+
+// Delete 删除功能开关
+func (r *featureFlagRepository) Delete(ctx context.Context, key string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM feature_flags WHERE flag_key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("删除功能开关失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
+
+// scanFeatureFlag 扫描功能开关
+func scanFeatureFlag(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	var tenants, users pq.StringArray
+
+	err := scanner.Scan(
+		&flag.ID, &flag.Key, &flag.Description, &flag.Enabled, &flag.RolloutPercentage,
+		&tenants, &users, &flag.CreatedBy, &flag.UpdatedBy, &flag.CreatedAt, &flag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	flag.Tenants = []string(tenants)
+	if flag.Tenants == nil {
+		flag.Tenants = []string{}
+	}
+	flag.Users = []string(users)
+	if flag.Users == nil {
+		flag.Users = []string{}
+	}
+
+	return &flag, nil
+}
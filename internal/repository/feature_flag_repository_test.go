@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestFeatureFlagRepository_CreateAndGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFeatureFlagRepository(sqlx.NewDb(db, "postgres"))
+
+	flag := &models.FeatureFlag{
+		Key:               "rule_engine_v2",
+		Enabled:           true,
+		RolloutPercentage: 20,
+		Tenants:           []string{"sre"},
+		Users:             []string{},
+	}
+
+	mock.ExpectExec(`INSERT INTO feature_flags`).
+		WithArgs(sqlmock.AnyArg(), "rule_engine_v2", nil, true, 20,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), flag))
+	assert.NotEmpty(t, flag.ID)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM feature_flags WHERE flag_key = \$1`).
+		WithArgs("rule_engine_v2").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "flag_key", "description", "enabled", "rollout_percentage", "tenants", "users",
+			"created_by", "updated_by", "created_at", "updated_at",
+		}).AddRow(flag.ID, "rule_engine_v2", nil, true, 20, "{sre}", "{}", nil, nil, now, now))
+
+	got, err := repo.GetByKey(context.Background(), "rule_engine_v2")
+	require.NoError(t, err)
+	assert.Equal(t, 20, got.RolloutPercentage)
+	assert.Equal(t, []string{"sre"}, got.Tenants)
+	assert.Equal(t, []string{}, got.Users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeatureFlagRepository_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFeatureFlagRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO feature_flags`).
+		WillReturnError(&pq.Error{Code: uniqueViolationCode})
+
+	err = repo.Create(context.Background(), &models.FeatureFlag{Key: "rule_engine_v2"})
+	assert.ErrorIs(t, err, models.ErrFeatureFlagExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeatureFlagRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFeatureFlagRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`UPDATE feature_flags SET`).
+		WithArgs(nil, false, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(context.Background(), &models.FeatureFlag{Key: "missing"})
+	assert.ErrorIs(t, err, models.ErrFeatureFlagNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/filterql"
+)
+
+// alertFilterSchema 告警列表过滤表达式可用的字段
+var alertFilterSchema = &filterql.Schema{
+	Fields: map[string]filterql.Field{
+		"id":               {Column: "id", Type: filterql.FieldString},
+		"rule_id":          {Column: "rule_id", Type: filterql.FieldString},
+		"data_source_id":   {Column: "data_source_id", Type: filterql.FieldString},
+		"name":             {Column: "name", Type: filterql.FieldString},
+		"description":      {Column: "description", Type: filterql.FieldString},
+		"severity":         {Column: "severity", Type: filterql.FieldString},
+		"status":           {Column: "status", Type: filterql.FieldString},
+		"source":           {Column: "source", Type: filterql.FieldString},
+		"fingerprint":      {Column: "fingerprint", Type: filterql.FieldString},
+		"acked_by":         {Column: "acked_by", Type: filterql.FieldString},
+		"resolved_by":      {Column: "resolved_by", Type: filterql.FieldString},
+		"value":            {Column: "value", Type: filterql.FieldNumber},
+		"threshold":        {Column: "threshold", Type: filterql.FieldNumber},
+		"eval_count":       {Column: "eval_count", Type: filterql.FieldNumber},
+		"occurrence_count": {Column: "occurrence_count", Type: filterql.FieldNumber},
+		"triage_score":     {Column: "triage_score", Type: filterql.FieldNumber},
+		"triage_assignee":  {Column: "triage_assignee", Type: filterql.FieldString},
+		"starts_at":        {Column: "starts_at", Type: filterql.FieldTime},
+		"ends_at":          {Column: "ends_at", Type: filterql.FieldTime},
+		"last_eval_at":     {Column: "last_eval_at", Type: filterql.FieldTime},
+		"last_seen_at":     {Column: "last_seen_at", Type: filterql.FieldTime},
+		"acked_at":         {Column: "acked_at", Type: filterql.FieldTime},
+		"resolved_at":      {Column: "resolved_at", Type: filterql.FieldTime},
+		"created_at":       {Column: "created_at", Type: filterql.FieldTime},
+		"updated_at":       {Column: "updated_at", Type: filterql.FieldTime},
+	},
+	Maps: map[string]string{
+		"labels":      "labels",
+		"annotations": "annotations",
+	},
+}
+
+// ticketFilterSchema 工单列表过滤表达式可用的字段
+var ticketFilterSchema = &filterql.Schema{
+	Fields: map[string]filterql.Field{
+		"id":           {Column: "id", Type: filterql.FieldString},
+		"number":       {Column: "number", Type: filterql.FieldString},
+		"title":        {Column: "title", Type: filterql.FieldString},
+		"description":  {Column: "description", Type: filterql.FieldString},
+		"status":       {Column: "status", Type: filterql.FieldString},
+		"priority":     {Column: "priority", Type: filterql.FieldString},
+		"category":     {Column: "category", Type: filterql.FieldString},
+		"type":         {Column: "type", Type: filterql.FieldString},
+		"source":       {Column: "source", Type: filterql.FieldString},
+		"reporter_id":  {Column: "reporter_id", Type: filterql.FieldString},
+		"assignee_id":  {Column: "assignee_id", Type: filterql.FieldString},
+		"tags":         {Column: "tags", Type: filterql.FieldTags},
+		"due_date":     {Column: "due_date", Type: filterql.FieldTime},
+		"sla_deadline": {Column: "sla_deadline", Type: filterql.FieldTime},
+		"resolved_at":  {Column: "resolved_at", Type: filterql.FieldTime},
+		"closed_at":    {Column: "closed_at", Type: filterql.FieldTime},
+		"created_at":   {Column: "created_at", Type: filterql.FieldTime},
+		"updated_at":   {Column: "updated_at", Type: filterql.FieldTime},
+	},
+	Maps: map[string]string{
+		"custom_fields": "custom_fields",
+	},
+}
+
+// compileFilterQuery 将列表接口的过滤表达式转换为 SQL 条件，表达式无效时返回 ErrInvalidInput
+func compileFilterQuery(schema *filterql.Schema, query string, argIndex int) (string, []interface{}, error) {
+	where, args, err := schema.Compile(query, argIndex, time.Now())
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	return where, args, nil
+}
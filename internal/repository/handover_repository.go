@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// handoverRepository 交班报告仓储实现
+type handoverRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewHandoverRepository 创建交班报告仓储实例
+func NewHandoverRepository(db *sqlx.DB) HandoverRepository {
+	return &handoverRepository{db: db}
+}
+
+// NewHandoverRepositoryWithTx 创建带事务的交班报告仓储实例
+func NewHandoverRepositoryWithTx(tx *sqlx.Tx) HandoverRepository {
+	return &handoverRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *handoverRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const (
+	handoverScheduleColumns = `id, team, timezone, shift_starts, channel_type, recipient, enabled,
+		last_handover_at, created_by, created_at, updated_at`
+	handoverReportColumns = `id, schedule_id, team, shift_start, shift_end, summary, knowledge_id,
+		notified, created_by, created_at`
+)
+
+// CreateSchedule 创建交接排班
+func (r *handoverRepository) CreateSchedule(ctx context.Context, schedule *models.HandoverSchedule) error {
+	if schedule.ID == "" {
+		schedule.ID = uuid.New().String()
+	}
+
+	shiftStarts, err := marshalShiftStarts(schedule)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	query := `
+		INSERT INTO handover_schedules (` + handoverScheduleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		schedule.ID, schedule.Team, schedule.Timezone, shiftStarts, schedule.ChannelType, schedule.Recipient,
+		schedule.Enabled, schedule.LastHandoverAt, schedule.CreatedBy, schedule.CreatedAt, schedule.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrHandoverScheduleExists
+		}
+		return fmt.Errorf("创建交接排班失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetSchedule 根据ID获取交接排班
+func (r *handoverRepository) GetSchedule(ctx context.Context, id string) (*models.HandoverSchedule, error) {
+	query := `SELECT ` + handoverScheduleColumns + ` FROM handover_schedules WHERE id = $1`
+	return r.getSchedule(ctx, query, id)
+}
+
+// GetScheduleByTeam 根据团队获取交接排班
+func (r *handoverRepository) GetScheduleByTeam(ctx context.Context, team string) (*models.HandoverSchedule, error) {
+	query := `SELECT ` + handoverScheduleColumns + ` FROM handover_schedules WHERE team = $1`
+	return r.getSchedule(ctx, query, team)
+}
+
+func (r *handoverRepository) getSchedule(ctx context.Context, query string, arg interface{}) (*models.HandoverSchedule, error) {
+	schedule, err := scanHandoverSchedule(r.getExecutor().QueryRowxContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrHandoverScheduleNotFound
+		}
+		return nil, fmt.Errorf("获取交接排班失败: %w", err)
+	}
+	return schedule, nil
+}
+
+// ListSchedules 获取交接排班列表，按团队排序，enabledOnly 为 true 时只返回已启用的排班
+func (r *handoverRepository) ListSchedules(ctx context.Context, enabledOnly bool) ([]*models.HandoverSchedule, error) {
+	query := `SELECT ` + handoverScheduleColumns + ` FROM handover_schedules`
+	if enabledOnly {
+		query += ` WHERE enabled = TRUE`
+	}
+	query += ` ORDER BY team`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取交接排班列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]*models.HandoverSchedule, 0)
+	for rows.Next() {
+		schedule, err := scanHandoverSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描交接排班失败: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// UpdateSchedule 更新交接排班
+func (r *handoverRepository) UpdateSchedule(ctx context.Context, schedule *models.HandoverSchedule) error {
+	shiftStarts, err := marshalShiftStarts(schedule)
+	if err != nil {
+		return err
+	}
+
+	schedule.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE handover_schedules SET
+			team = $1,
+			timezone = $2,
+			shift_starts = $3,
+			channel_type = $4,
+			recipient = $5,
+			enabled = $6,
+			updated_at = $7
+		WHERE id = $8`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		schedule.Team, schedule.Timezone, shiftStarts, schedule.ChannelType, schedule.Recipient,
+		schedule.Enabled, schedule.UpdatedAt, schedule.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrHandoverScheduleExists
+		}
+		return fmt.Errorf("更新交接排班失败: %w", err)
+	}
+
+	return checkHandoverAffected(result, models.ErrHandoverScheduleNotFound)
+}
+
+// DeleteSchedule 删除交接排班，已生成的交班报告保留
+func (r *handoverRepository) DeleteSchedule(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM handover_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除交接排班失败: %w", err)
+	}
+
+	return checkHandoverAffected(result, models.ErrHandoverScheduleNotFound)
+}
+
+// MarkScheduleRun 记录排班最近一次生成交班报告的交接时刻，只会向后推进
+func (r *handoverRepository) MarkScheduleRun(ctx context.Context, id string, boundary time.Time) error {
+	query := `
+		UPDATE handover_schedules SET last_handover_at = $2
+		WHERE id = $1 AND (last_handover_at IS NULL OR last_handover_at < $2)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, id, boundary); err != nil {
+		return fmt.Errorf("更新交接排班执行时间失败: %w", err)
+	}
+	return nil
+}
+
+// CreateReport 创建交班报告，同一排班的同一交接时刻已有报告时返回 ErrHandoverReportExists
+func (r *handoverRepository) CreateReport(ctx context.Context, report *models.HandoverReport) error {
+	if report.ID == "" {
+		report.ID = uuid.New().String()
+	}
+
+	summary, err := json.Marshal(report.Summary)
+	if err != nil {
+		return fmt.Errorf("序列化交班报告失败: %w", err)
+	}
+
+	report.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO handover_reports (` + handoverReportColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		report.ID, report.ScheduleID, report.Team, report.ShiftStart, report.ShiftEnd, summary,
+		report.KnowledgeID, report.Notified, report.CreatedBy, report.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrHandoverReportExists
+		}
+		return fmt.Errorf("创建交班报告失败: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateReport 更新交班报告的知识库草稿和通知状态
+func (r *handoverRepository) UpdateReport(ctx context.Context, report *models.HandoverReport) error {
+	query := `UPDATE handover_reports SET knowledge_id = $1, notified = $2 WHERE id = $3`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, report.KnowledgeID, report.Notified, report.ID)
+	if err != nil {
+		return fmt.Errorf("更新交班报告失败: %w", err)
+	}
+
+	return checkHandoverAffected(result, models.ErrHandoverReportNotFound)
+}
+
+// GetReport 根据ID获取交班报告
+func (r *handoverRepository) GetReport(ctx context.Context, id string) (*models.HandoverReport, error) {
+	query := `SELECT ` + handoverReportColumns + ` FROM handover_reports WHERE id = $1`
+
+	report, err := scanHandoverReport(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrHandoverReportNotFound
+		}
+		return nil, fmt.Errorf("获取交班报告失败: %w", err)
+	}
+	return report, nil
+}
+
+// ListReports 获取交班报告列表，按班次结束时间倒序，team 为空时返回所有团队
+func (r *handoverRepository) ListReports(ctx context.Context, team string, limit int) ([]*models.HandoverReport, error) {
+	query := `
+		SELECT ` + handoverReportColumns + `
+		FROM handover_reports
+		WHERE ($1 = '' OR team = $1)
+		ORDER BY shift_end DESC
+		LIMIT $2`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, team, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取交班报告列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]*models.HandoverReport, 0)
+	for rows.Next() {
+		report, err := scanHandoverReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描交班报告失败: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// Summarize 汇总团队当前未恢复的严重告警、处理中的工单、班次内新发生的事件和待处理事项，每类最多 scope.Limit 条
+func (r *handoverRepository) Summarize(ctx context.Context, scope models.HandoverScope) (*models.HandoverSummary, error) {
+	summary := &models.HandoverSummary{
+		CriticalAlerts:    []*models.HandoverAlert{},
+		InProgressTickets: []*models.HandoverTicket{},
+		Incidents:         []*models.HandoverTicket{},
+		PendingActions:    []*models.HandoverAction{},
+	}
+
+	alertQuery := `
+		SELECT id, name, severity, status, COALESCE(labels->>'service', '') AS service, starts_at
+		FROM alerts
+		WHERE labels->>'team' = $1
+		  AND severity = 'critical'
+		  AND status IN ('firing', 'acked')
+		  AND deleted_at IS NULL
+		ORDER BY starts_at
+		LIMIT $2`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &summary.CriticalAlerts, alertQuery, scope.Team, scope.Limit); err != nil {
+		return nil, fmt.Errorf("获取团队严重告警失败: %w", err)
+	}
+
+	tags, members := pq.Array(scope.ServiceTags), pq.Array(scope.Members)
+	if len(scope.ServiceTags) > 0 || len(scope.Members) > 0 {
+		ticketQuery := `
+			SELECT id, number, title, status, priority, assignee_id, sla_deadline, created_at
+			FROM tickets
+			WHERE (tags && $1::varchar[] OR assignee_id = ANY($2))
+			  AND status NOT IN ` + dashboardClosedTicketStatuses + `
+			  AND deleted_at IS NULL
+			ORDER BY priority, created_at
+			LIMIT $3`
+		if err := sqlx.SelectContext(ctx, r.getExecutor(), &summary.InProgressTickets, ticketQuery, tags, members, scope.Limit); err != nil {
+			return nil, fmt.Errorf("获取团队处理中工单失败: %w", err)
+		}
+
+		incidentQuery := `
+			SELECT id, number, title, status, priority, assignee_id, sla_deadline, created_at
+			FROM tickets
+			WHERE (tags && $1::varchar[] OR assignee_id = ANY($2))
+			  AND type = $3
+			  AND created_at >= $4
+			  AND deleted_at IS NULL
+			ORDER BY created_at
+			LIMIT $5`
+		if err := sqlx.SelectContext(ctx, r.getExecutor(), &summary.Incidents, incidentQuery,
+			tags, members, models.TicketTypeIncident, scope.Since, scope.Limit); err != nil {
+			return nil, fmt.Errorf("获取团队事件失败: %w", err)
+		}
+	}
+
+	actionQuery := `
+		SELECT '` + models.HandoverActionItem + `' AS kind, id, title, assignee_id, due_date, created_at
+		FROM postmortem_action_items
+		WHERE status = 'open' AND assignee_id = ANY($1)
+		UNION ALL
+		SELECT '` + models.HandoverActionRemediation + `' AS kind, e.id, e.action_name AS title,
+		       NULL::uuid AS assignee_id, NULL::timestamptz AS due_date, e.created_at
+		FROM remediation_executions e
+		JOIN alerts a ON a.id = e.alert_id
+		WHERE e.status = 'pending_approval' AND a.labels->>'team' = $2
+		ORDER BY created_at
+		LIMIT $3`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &summary.PendingActions, actionQuery, members, scope.Team, scope.Limit); err != nil {
+		return nil, fmt.Errorf("获取团队待处理事项失败: %w", err)
+	}
+
+	return summary, nil
+}
+
+// marshalShiftStarts 序列化交接时刻
+func marshalShiftStarts(schedule *models.HandoverSchedule) ([]byte, error) {
+	if schedule.ShiftStarts == nil {
+		schedule.ShiftStarts = []string{}
+	}
+	shiftStarts, err := json.Marshal(schedule.ShiftStarts)
+	if err != nil {
+		return nil, fmt.Errorf("序列化交接时刻失败: %w", err)
+	}
+	return shiftStarts, nil
+}
+
+// checkHandoverAffected 没有更新任何行时返回 notFound
+func checkHandoverAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// scanHandoverSchedule 扫描交接排班
+func scanHandoverSchedule(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.HandoverSchedule, error) {
+	var schedule models.HandoverSchedule
+	var shiftStarts []byte
+
+	err := scanner.Scan(
+		&schedule.ID, &schedule.Team, &schedule.Timezone, &shiftStarts, &schedule.ChannelType,
+		&schedule.Recipient, &schedule.Enabled, &schedule.LastHandoverAt, &schedule.CreatedBy,
+		&schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shiftStarts) > 0 {
+		if err := json.Unmarshal(shiftStarts, &schedule.ShiftStarts); err != nil {
+			return nil, fmt.Errorf("解析交接时刻失败: %w", err)
+		}
+	}
+
+	return &schedule, nil
+}
+
+// scanHandoverReport 扫描交班报告
+func scanHandoverReport(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.HandoverReport, error) {
+	var report models.HandoverReport
+	var summary []byte
+
+	err := scanner.Scan(
+		&report.ID, &report.ScheduleID, &report.Team, &report.ShiftStart, &report.ShiftEnd, &summary,
+		&report.KnowledgeID, &report.Notified, &report.CreatedBy, &report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(summary) > 0 {
+		if err := json.Unmarshal(summary, &report.Summary); err != nil {
+			return nil, fmt.Errorf("解析交班报告失败: %w", err)
+		}
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestHandoverRepository_CreateAndGetScheduleByTeam(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHandoverRepository(sqlx.NewDb(db, "postgres"))
+
+	channel := models.NotificationTypeSlack
+	schedule := &models.HandoverSchedule{
+		Team:        "sre",
+		Timezone:    "Asia/Shanghai",
+		ShiftStarts: []string{"09:00", "21:00"},
+		ChannelType: &channel,
+		Recipient:   "#sre-oncall",
+		Enabled:     true,
+		CreatedBy:   "admin",
+	}
+
+	mock.ExpectExec(`INSERT INTO handover_schedules`).
+		WithArgs(sqlmock.AnyArg(), "sre", "Asia/Shanghai", []byte(`["09:00","21:00"]`), &channel, "#sre-oncall",
+			true, nil, "admin", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateSchedule(context.Background(), schedule))
+	assert.NotEmpty(t, schedule.ID)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM handover_schedules WHERE team = \$1`).
+		WithArgs("sre").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "team", "timezone", "shift_starts", "channel_type", "recipient", "enabled",
+			"last_handover_at", "created_by", "created_at", "updated_at",
+		}).AddRow(schedule.ID, "sre", "Asia/Shanghai", []byte(`["09:00","21:00"]`), "slack", "#sre-oncall", true,
+			nil, "admin", now, now))
+
+	got, err := repo.GetScheduleByTeam(context.Background(), "sre")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"09:00", "21:00"}, got.ShiftStarts)
+	require.NotNil(t, got.ChannelType)
+	assert.Equal(t, models.NotificationTypeSlack, *got.ChannelType)
+	assert.Nil(t, got.LastHandoverAt)
+
+	mock.ExpectQuery(`SELECT .+ FROM handover_schedules WHERE team = \$1`).
+		WithArgs("dba").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetScheduleByTeam(context.Background(), "dba")
+	assert.ErrorIs(t, err, models.ErrHandoverScheduleNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandoverRepository_CreateReport_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHandoverRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO handover_reports`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	scheduleID := "s1"
+	err = repo.CreateReport(context.Background(), &models.HandoverReport{ScheduleID: &scheduleID, Team: "sre", ShiftEnd: time.Now()})
+	assert.ErrorIs(t, err, models.ErrHandoverReportExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandoverRepository_Summarize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHandoverRepository(sqlx.NewDb(db, "postgres"))
+
+	now := time.Now()
+	since := now.Add(-12 * time.Hour)
+	scope := models.HandoverScope{
+		Team: "sre", ServiceTags: []string{"service:checkout"}, Members: []string{"u1"}, Since: since, Limit: 10,
+	}
+
+	mock.ExpectQuery(`SELECT .+ FROM alerts\s+WHERE labels->>'team' = \$1\s+AND severity = 'critical'`).
+		WithArgs("sre", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "severity", "status", "service", "starts_at"}).
+			AddRow("a1", "HighErrorRate", "critical", "firing", "checkout", now))
+	ticketColumns := []string{"id", "number", "title", "status", "priority", "assignee_id", "sla_deadline", "created_at"}
+	mock.ExpectQuery(`SELECT .+ FROM tickets\s+WHERE \(tags && \$1::varchar\[\] OR assignee_id = ANY\(\$2\)\)\s+AND status NOT IN`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 10).
+		WillReturnRows(sqlmock.NewRows(ticketColumns).AddRow("t1", "T-1", "支付超时", "in_progress", "high", "u1", nil, now))
+	mock.ExpectQuery(`SELECT .+ FROM tickets\s+WHERE .+AND type = \$3\s+AND created_at >= \$4`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), models.TicketTypeIncident, since, 10).
+		WillReturnRows(sqlmock.NewRows(ticketColumns))
+	mock.ExpectQuery(`FROM postmortem_action_items .+UNION ALL.+FROM remediation_executions e`).
+		WithArgs(sqlmock.AnyArg(), "sre", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "id", "title", "assignee_id", "due_date", "created_at"}).
+			AddRow(models.HandoverActionRemediation, "e1", "重启服务", nil, nil, now))
+
+	summary, err := repo.Summarize(context.Background(), scope)
+	require.NoError(t, err)
+	require.Len(t, summary.CriticalAlerts, 1)
+	assert.Equal(t, "checkout", summary.CriticalAlerts[0].Service)
+	require.Len(t, summary.InProgressTickets, 1)
+	assert.Equal(t, "u1", *summary.InProgressTickets[0].AssigneeID)
+	assert.Empty(t, summary.Incidents)
+	require.Len(t, summary.PendingActions, 1)
+	assert.Equal(t, models.HandoverActionRemediation, summary.PendingActions[0].Kind)
+
+	// 团队没有负责的服务和成员时不查询工单
+	mock.ExpectQuery(`FROM alerts`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`FROM postmortem_action_items`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	summary, err = repo.Summarize(context.Background(), models.HandoverScope{Team: "web", Since: since, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, summary.InProgressTickets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
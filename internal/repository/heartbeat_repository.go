@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// heartbeatRepository 心跳检查仓储实现
+type heartbeatRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewHeartbeatRepository 创建心跳检查仓储实例
+func NewHeartbeatRepository(db *sqlx.DB) HeartbeatRepository {
+	return &heartbeatRepository{db: db}
+}
+
+// NewHeartbeatRepositoryWithTx 创建带事务的心跳检查仓储实例
+func NewHeartbeatRepositoryWithTx(tx *sqlx.Tx) HeartbeatRepository {
+	return &heartbeatRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *heartbeatRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const heartbeatColumns = `id, name, description, token, interval_seconds, grace_seconds, severity, labels,
+	status, last_ping_at, expected_at, ping_count, created_by, created_at, updated_at`
+
+// Create 创建心跳检查
+func (r *heartbeatRepository) Create(ctx context.Context, heartbeat *models.Heartbeat) error {
+	if heartbeat.ID == "" {
+		heartbeat.ID = uuid.New().String()
+	}
+
+	labels, err := json.Marshal(heartbeat.Labels)
+	if err != nil {
+		return fmt.Errorf("序列化标签失败: %w", err)
+	}
+
+	now := time.Now()
+	heartbeat.CreatedAt = now
+	heartbeat.UpdatedAt = now
+
+	query := `
+		INSERT INTO heartbeats (
+			id, name, description, token, interval_seconds, grace_seconds, severity, labels,
+			status, expected_at, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		heartbeat.ID, heartbeat.Name, heartbeat.Description, heartbeat.Token, heartbeat.IntervalSeconds,
+		heartbeat.GraceSeconds, heartbeat.Severity, labels, heartbeat.Status, heartbeat.ExpectedAt,
+		heartbeat.CreatedBy, heartbeat.CreatedAt, heartbeat.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrHeartbeatExists
+		}
+		return fmt.Errorf("创建心跳检查失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取心跳检查
+func (r *heartbeatRepository) GetByID(ctx context.Context, id string) (*models.Heartbeat, error) {
+	query := `SELECT ` + heartbeatColumns + ` FROM heartbeats WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// GetByToken 根据上报令牌获取心跳检查
+func (r *heartbeatRepository) GetByToken(ctx context.Context, token string) (*models.Heartbeat, error) {
+	query := `SELECT ` + heartbeatColumns + ` FROM heartbeats WHERE token = $1`
+	return r.get(ctx, query, token)
+}
+
+func (r *heartbeatRepository) get(ctx context.Context, query string, args ...interface{}) (*models.Heartbeat, error) {
+	heartbeat, err := scanHeartbeat(r.getExecutor().QueryRowxContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrHeartbeatNotFound
+		}
+		return nil, fmt.Errorf("获取心跳检查失败: %w", err)
+	}
+	return heartbeat, nil
+}
+
+// List 获取全部心跳检查，按名称排序
+func (r *heartbeatRepository) List(ctx context.Context) ([]*models.Heartbeat, error) {
+	query := `SELECT ` + heartbeatColumns + ` FROM heartbeats ORDER BY name`
+	return r.list(ctx, query)
+}
+
+// ListOverdue 获取最晚上报时间已过且尚未告警的心跳检查
+func (r *heartbeatRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.Heartbeat, error) {
+	query := `
+		SELECT ` + heartbeatColumns + `
+		FROM heartbeats
+		WHERE status IN ('new', 'up') AND expected_at < $1
+		ORDER BY expected_at
+		LIMIT $2`
+	return r.list(ctx, query, now, limit)
+}
+
+func (r *heartbeatRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Heartbeat, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取心跳检查列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	heartbeats := make([]*models.Heartbeat, 0)
+	for rows.Next() {
+		heartbeat, err := scanHeartbeat(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描心跳检查失败: %w", err)
+		}
+		heartbeats = append(heartbeats, heartbeat)
+	}
+
+	return heartbeats, rows.Err()
+}
+
+// Update 更新心跳检查的配置、状态和最晚上报时间，上报令牌和上报记录不可修改
+func (r *heartbeatRepository) Update(ctx context.Context, heartbeat *models.Heartbeat) error {
+	labels, err := json.Marshal(heartbeat.Labels)
+	if err != nil {
+		return fmt.Errorf("序列化标签失败: %w", err)
+	}
+
+	heartbeat.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE heartbeats SET
+			name = $2, description = $3, interval_seconds = $4, grace_seconds = $5, severity = $6,
+			labels = $7, status = $8, expected_at = $9, updated_at = $10
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		heartbeat.ID, heartbeat.Name, heartbeat.Description, heartbeat.IntervalSeconds, heartbeat.GraceSeconds,
+		heartbeat.Severity, labels, heartbeat.Status, heartbeat.ExpectedAt, heartbeat.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrHeartbeatExists
+		}
+		return fmt.Errorf("更新心跳检查失败: %w", err)
+	}
+
+	return checkHeartbeatAffected(result)
+}
+
+// Delete 删除心跳检查
+func (r *heartbeatRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM heartbeats WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除心跳检查失败: %w", err)
+	}
+
+	return checkHeartbeatAffected(result)
+}
+
+// RecordPing 记录一次上报，未暂停的心跳检查恢复为正常状态
+func (r *heartbeatRepository) RecordPing(ctx context.Context, id string, at, expectedAt time.Time) error {
+	query := `
+		UPDATE heartbeats SET
+			last_ping_at = $2,
+			expected_at = $3,
+			ping_count = ping_count + 1,
+			status = CASE WHEN status = 'paused' THEN status ELSE 'up' END,
+			updated_at = $2
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, id, at, expectedAt)
+	if err != nil {
+		return fmt.Errorf("记录心跳上报失败: %w", err)
+	}
+
+	return checkHeartbeatAffected(result)
+}
+
+// MarkDown 将超时的心跳检查标记为异常。expectedAt 为检查时读取的最晚上报时间，
+// 期间收到上报或被暂停时不修改，返回是否标记成功
+func (r *heartbeatRepository) MarkDown(ctx context.Context, id string, expectedAt time.Time) (bool, error) {
+	query := `
+		UPDATE heartbeats SET status = 'down', updated_at = $3
+		WHERE id = $1 AND expected_at = $2 AND status IN ('new', 'up')`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, id, expectedAt, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("标记心跳检查异常失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// checkHeartbeatAffected 没有更新任何行时返回心跳检查不存在
+func checkHeartbeatAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrHeartbeatNotFound
+	}
+
+	return nil
+}
+
+// scanHeartbeat 扫描心跳检查
+func scanHeartbeat(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Heartbeat, error) {
+	var heartbeat models.Heartbeat
+	var labels []byte
+
+	err := scanner.Scan(
+		&heartbeat.ID, &heartbeat.Name, &heartbeat.Description, &heartbeat.Token, &heartbeat.IntervalSeconds,
+		&heartbeat.GraceSeconds, &heartbeat.Severity, &labels, &heartbeat.Status, &heartbeat.LastPingAt,
+		&heartbeat.ExpectedAt, &heartbeat.PingCount, &heartbeat.CreatedBy, &heartbeat.CreatedAt, &heartbeat.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	heartbeat.Labels = map[string]string{}
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &heartbeat.Labels); err != nil {
+			return nil, fmt.Errorf("解析标签失败: %w", err)
+		}
+	}
+
+	return &heartbeat, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var heartbeatTestColumns = []string{
+	"id", "name", "description", "token", "interval_seconds", "grace_seconds", "severity", "labels",
+	"status", "last_ping_at", "expected_at", "ping_count", "created_by", "created_at", "updated_at",
+}
+
+func TestHeartbeatRepository_CreateDuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHeartbeatRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO heartbeats`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.Create(context.Background(), &models.Heartbeat{Name: "nightly-backup", Labels: map[string]string{}})
+	assert.ErrorIs(t, err, models.ErrHeartbeatExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHeartbeatRepository_GetByToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHeartbeatRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM heartbeats WHERE token = \$1`).
+		WithArgs("tok").
+		WillReturnRows(sqlmock.NewRows(heartbeatTestColumns).AddRow(
+			"h1", "nightly-backup", "", "tok", 3600, 300, "high", []byte(`{"team":"dba"}`),
+			"up", now, now.Add(time.Hour), 12, "admin", now, now))
+
+	heartbeat, err := repo.GetByToken(context.Background(), "tok")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, heartbeat.Interval())
+	assert.Equal(t, map[string]string{"team": "dba"}, heartbeat.Labels)
+	assert.Equal(t, models.HeartbeatStatusUp, heartbeat.Status)
+
+	mock.ExpectQuery(`SELECT .+ FROM heartbeats WHERE token = \$1`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(heartbeatTestColumns))
+
+	_, err = repo.GetByToken(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrHeartbeatNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHeartbeatRepository_MarkDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHeartbeatRepository(sqlx.NewDb(db, "postgres"))
+	expectedAt := time.Now().Add(-time.Minute)
+
+	mock.ExpectExec(`UPDATE heartbeats SET status = 'down'.+WHERE id = \$1 AND expected_at = \$2 AND status IN \('new', 'up'\)`).
+		WithArgs("h1", expectedAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	marked, err := repo.MarkDown(context.Background(), "h1", expectedAt)
+	require.NoError(t, err)
+	assert.True(t, marked)
+
+	// 检查期间收到上报，最晚上报时间已变化
+	mock.ExpectExec(`UPDATE heartbeats SET status = 'down'`).
+		WithArgs("h1", expectedAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	marked, err = repo.MarkDown(context.Background(), "h1", expectedAt)
+	require.NoError(t, err)
+	assert.False(t, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHeartbeatRepository_RecordPingNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHeartbeatRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectExec(`UPDATE heartbeats SET`).
+		WithArgs("h1", now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.RecordPing(context.Background(), "h1", now, now.Add(time.Hour))
+	assert.ErrorIs(t, err, models.ErrHeartbeatNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// defaultInboxLimit 站内通知列表默认返回的条数
+const defaultInboxLimit = 50
+
+// inboxColumns 站内通知查询的列
+const inboxColumns = `id, seq, user_id, kind, title, body, resource_type, resource_id, actor_id, dedupe_key, read_at, created_at`
+
+// inboxRepository 站内通知收件箱仓储实现
+type inboxRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewInboxRepository 创建站内通知收件箱仓储实例
+func NewInboxRepository(db *sqlx.DB) InboxRepository {
+	return &inboxRepository{db: db}
+}
+
+// NewInboxRepositoryWithTx 创建带事务的站内通知收件箱仓储实例
+func NewInboxRepositoryWithTx(tx *sqlx.Tx) InboxRepository {
+	return &inboxRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *inboxRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 投递站内通知，同一用户相同去重键的通知已存在时不重复投递并返回 false
+func (r *inboxRepository) Create(ctx context.Context, item *models.InboxItem) (bool, error) {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO inbox_items (id, user_id, kind, title, body, resource_type, resource_id, actor_id, dedupe_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id, dedupe_key) DO NOTHING
+		RETURNING seq, created_at`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query,
+		item.ID, item.UserID, item.Kind, item.Title, item.Body, item.ResourceType, item.ResourceID,
+		item.ActorID, item.DedupeKey, time.Now(),
+	).Scan(&item.Seq, &item.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("创建站内通知失败: %w", err)
+	}
+	return true, nil
+}
+
+// List 获取用户的站内通知，按 Seq 倒序分页
+func (r *inboxRepository) List(ctx context.Context, userID string, filter *models.InboxFilter) ([]*models.InboxItem, error) {
+	query := `SELECT ` + inboxColumns + ` FROM inbox_items WHERE user_id = $1`
+	args := []interface{}{userID}
+	limit := defaultInboxLimit
+	if filter != nil {
+		if filter.UnreadOnly {
+			query += ` AND read_at IS NULL`
+		}
+		if filter.Before > 0 {
+			args = append(args, filter.Before)
+			query += fmt.Sprintf(` AND seq < $%d`, len(args))
+		}
+		if filter.Limit > 0 {
+			limit = filter.Limit
+		}
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(` ORDER BY seq DESC LIMIT $%d`, len(args))
+
+	items := []*models.InboxItem{}
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &items, query, args...); err != nil {
+		return nil, fmt.Errorf("获取站内通知列表失败: %w", err)
+	}
+	return items, nil
+}
+
+// ListAfter 获取 Seq 大于 after 的通知，按 Seq 升序，用于实时推送
+func (r *inboxRepository) ListAfter(ctx context.Context, userID string, after int64, limit int) ([]*models.InboxItem, error) {
+	query := `SELECT ` + inboxColumns + ` FROM inbox_items WHERE user_id = $1 AND seq > $2 ORDER BY seq LIMIT $3`
+
+	var items []*models.InboxItem
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &items, query, userID, after, limit); err != nil {
+		return nil, fmt.Errorf("获取新的站内通知失败: %w", err)
+	}
+	return items, nil
+}
+
+// LatestSeq 获取用户最新一条通知的 Seq，没有通知时返回 0
+func (r *inboxRepository) LatestSeq(ctx context.Context, userID string) (int64, error) {
+	var seq int64
+	query := `SELECT COALESCE(MAX(seq), 0) FROM inbox_items WHERE user_id = $1`
+	if err := r.getExecutor().QueryRowxContext(ctx, query, userID).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("获取最新站内通知失败: %w", err)
+	}
+	return seq, nil
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *inboxRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM inbox_items WHERE user_id = $1 AND read_at IS NULL`
+	if err := r.getExecutor().QueryRowxContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("统计未读站内通知失败: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead 将用户的通知标记为已读，其他用户的通知和已读通知不受影响
+func (r *inboxRepository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	query := `UPDATE inbox_items SET read_at = $1 WHERE user_id = $2 AND id = ANY($3) AND read_at IS NULL`
+	result, err := r.getExecutor().ExecContext(ctx, query, time.Now(), userID, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("标记站内通知已读失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读
+func (r *inboxRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	query := `UPDATE inbox_items SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL`
+	result, err := r.getExecutor().ExecContext(ctx, query, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("标记站内通知已读失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ListSLABreaches 获取已超出 SLA 截止时间、仍未解决且尚未通知当前处理人的工单，按截止时间升序，SLA暂停中的工单不算超时
+func (r *inboxRepository) ListSLABreaches(ctx context.Context, now time.Time, limit int) ([]*models.InboxSLABreach, error) {
+	query := `
+		SELECT t.id, t.number, t.title, t.assignee_id, t.sla_deadline
+		FROM tickets t
+		WHERE t.sla_deadline < $1
+		  AND t.assignee_id IS NOT NULL
+		  AND t.status NOT IN ('resolved', 'closed', 'cancelled')
+		  AND t.deleted_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM ticket_sla_pauses p
+			WHERE p.ticket_id = t.id AND p.resumed_at IS NULL
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM inbox_items i
+			WHERE i.user_id = t.assignee_id AND i.dedupe_key = $2 || t.id::text
+		  )
+		ORDER BY t.sla_deadline
+		LIMIT $3`
+
+	var breaches []*models.InboxSLABreach
+	prefix := models.SLABreachDedupeKey("")
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &breaches, query, now, prefix, limit); err != nil {
+		return nil, fmt.Errorf("获取SLA超时工单失败: %w", err)
+	}
+	return breaches, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestInboxRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInboxRepository(sqlx.NewDb(db, "postgres"))
+	key := models.SLABreachDedupeKey("t1")
+	item := &models.InboxItem{UserID: "u1", Kind: models.InboxKindSLABreach, Title: "T-1 超出 SLA",
+		ResourceType: models.InboxResourceTicket, ResourceID: "t1", DedupeKey: &key}
+
+	mock.ExpectQuery(`INSERT INTO inbox_items .+ON CONFLICT \(user_id, dedupe_key\) DO NOTHING`).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "created_at"}).AddRow(42, time.Now()))
+	created, err := repo.Create(context.Background(), item)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(42), item.Seq)
+
+	// 相同去重键的通知已存在时不返回行
+	mock.ExpectQuery(`INSERT INTO inbox_items`).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "created_at"}))
+	created, err = repo.Create(context.Background(), &models.InboxItem{UserID: "u1", DedupeKey: &key})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInboxRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`FROM inbox_items WHERE user_id = \$1 AND read_at IS NULL AND seq < \$2 ORDER BY seq DESC LIMIT \$3`).
+		WithArgs("u1", int64(10), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq", "user_id", "kind", "title"}).AddRow("n1", 9, "u1", "mentioned", "alice 提到了你"))
+
+	items, err := repo.List(context.Background(), "u1", &models.InboxFilter{UnreadOnly: true, Before: 10, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, models.InboxKindMentioned, items[0].Kind)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
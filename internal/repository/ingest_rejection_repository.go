@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// ingestRejectionRepository 告警接入拒绝记录仓储实现
+type ingestRejectionRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewIngestRejectionRepository 创建告警接入拒绝记录仓储实例
+func NewIngestRejectionRepository(db *sqlx.DB) IngestRejectionRepository {
+	return &ingestRejectionRepository{db: db}
+}
+
+// NewIngestRejectionRepositoryWithTx 创建带事务的告警接入拒绝记录仓储实例
+func NewIngestRejectionRepositoryWithTx(tx *sqlx.Tx) IngestRejectionRepository {
+	return &ingestRejectionRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *ingestRejectionRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 保存拒绝记录，并在同一事务中删除该集成超出 keep 条的较早记录，keep 不大于 0 时不清理
+func (r *ingestRejectionRepository) Create(ctx context.Context, rejection *models.IngestRejection, keep int) error {
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO ingest_rejections (integration_id, endpoint, reason, detail, payload, payload_size, client_ip, received_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`
+
+		err := tx.QueryRowxContext(ctx, query, rejection.IntegrationID, rejection.Endpoint, rejection.Reason, rejection.Detail,
+			rejection.Payload, rejection.PayloadSize, rejection.ClientIP, rejection.ReceivedAt).Scan(&rejection.ID)
+		if err != nil {
+			return fmt.Errorf("保存接入拒绝记录失败: %w", err)
+		}
+		if keep <= 0 {
+			return nil
+		}
+
+		query = `
+			DELETE FROM ingest_rejections
+			WHERE integration_id = $1 AND id NOT IN (
+				SELECT id FROM ingest_rejections WHERE integration_id = $1 ORDER BY received_at DESC LIMIT $2
+			)`
+		if _, err := tx.ExecContext(ctx, query, rejection.IntegrationID, keep); err != nil {
+			return fmt.Errorf("清理接入拒绝记录失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// List 按接收时间倒序获取拒绝记录
+func (r *ingestRejectionRepository) List(ctx context.Context, filter *models.IngestRejectionFilter) ([]*models.IngestRejection, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.IntegrationID != "" {
+		args = append(args, filter.IntegrationID)
+		conditions = append(conditions, fmt.Sprintf("integration_id = $%d", len(args)))
+	}
+	if filter.Reason != nil {
+		args = append(args, *filter.Reason)
+		conditions = append(conditions, fmt.Sprintf("reason = $%d", len(args)))
+	}
+
+	query := `
+		SELECT id, integration_id, endpoint, reason, detail, payload, payload_size, client_ip, received_at
+		FROM ingest_rejections`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY received_at DESC LIMIT $%d", len(args))
+
+	var rejections []*models.IngestRejection
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &rejections, query, args...); err != nil {
+		return nil, fmt.Errorf("查询接入拒绝记录失败: %w", err)
+	}
+	return rejections, nil
+}
+
+// CountByReason 按原因统计集成在 since 之后的拒绝次数，次数多的在前
+func (r *ingestRejectionRepository) CountByReason(ctx context.Context, integrationID string, since time.Time) ([]*models.IngestRejectionCount, error) {
+	query := `
+		SELECT reason, COUNT(*) AS count
+		FROM ingest_rejections
+		WHERE integration_id = $1 AND received_at >= $2
+		GROUP BY reason
+		ORDER BY count DESC, reason`
+
+	var counts []*models.IngestRejectionCount
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &counts, query, integrationID, since); err != nil {
+		return nil, fmt.Errorf("统计接入拒绝记录失败: %w", err)
+	}
+	return counts, nil
+}
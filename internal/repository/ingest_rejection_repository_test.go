@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestIngestRejectionRepository_CreateKeepsLatest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIngestRejectionRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rejection := &models.IngestRejection{
+		IntegrationID: "int-1", Endpoint: models.IngestEndpointAlerts, Reason: models.IngestRejectUnknownField,
+		Detail: "未知字段 \"team\"", PayloadSize: 42, ClientIP: "10.0.0.1", ReceivedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO ingest_rejections`).
+		WithArgs("int-1", models.IngestEndpointAlerts, models.IngestRejectUnknownField, rejection.Detail, nil, 42, "10.0.0.1", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rej-1"))
+	mock.ExpectExec(`DELETE FROM ingest_rejections\s+WHERE integration_id = \$1 AND id NOT IN`).
+		WithArgs("int-1", 100).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Create(context.Background(), rejection, 100))
+	assert.Equal(t, "rej-1", rejection.ID)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"pulse/internal/models"
@@ -39,6 +40,9 @@ type UserRepository interface {
 	BatchCreate(ctx context.Context, users []*models.User) error
 	BatchUpdate(ctx context.Context, users []*models.User) error
 	BatchDelete(ctx context.Context, ids []string) error
+
+	// 密钥轮换
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
 }
 
 // AlertRepository 告警仓储接口
@@ -52,21 +56,36 @@ type AlertRepository interface {
 	
 	// 查询操作
 	List(ctx context.Context, filter *models.AlertFilter) (*models.AlertList, error)
+	Stream(ctx context.Context, filter *models.AlertFilter, fn func(*models.Alert) error) error
 	Count(ctx context.Context, filter *models.AlertFilter) (int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
 	GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error)
+	// GetOpenByFingerprint 获取指纹相同的未解决告警，没有时返回 ErrAlertNotFound
+	GetOpenByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error)
+	// RecordOccurrence 累加未解决告警的触发次数，告警已解决或不存在时返回 ErrAlertNotFound
+	RecordOccurrence(ctx context.Context, id string, at time.Time, value *float64, sampleInterval time.Duration) (*models.AlertOccurrence, error)
+	ListByIDs(ctx context.Context, ids []string) ([]*models.Alert, error)
+	ListStale(ctx context.Context, before time.Time, offset, limit int) ([]*models.Alert, error)
 	
 	// 告警状态管理
 	Acknowledge(ctx context.Context, id, userID string, comment *string) error
 	Resolve(ctx context.Context, id, userID string, comment *string) error
 	Silence(ctx context.Context, id string, silenceID string, duration time.Duration) error
 	Unsilence(ctx context.Context, id string) error
+	GetSilence(ctx context.Context, silenceID string) (*models.AlertSilence, error)
+	CreateSilence(ctx context.Context, silence *models.AlertSilence) error
 	
 	// 告警统计
 	GetStats(ctx context.Context, filter *models.AlertFilter) (*models.AlertStats, error)
 	GetTrend(ctx context.Context, start, end time.Time, interval string) ([]*models.AlertTrendPoint, error)
 	GetActiveCount(ctx context.Context) (int64, error)
 	GetCriticalCount(ctx context.Context) (int64, error)
+	// GetLabelCardinality 统计 since 之后创建的告警中各标签键的不同值数量，按数量降序返回前 limit 个
+	GetLabelCardinality(ctx context.Context, since time.Time, limit int) ([]*models.LabelCardinalityStat, error)
+	// ListLabelKeys 统计 since 之后创建的告警中出现的标签键及告警数，按前缀过滤，按告警数降序返回
+	ListLabelKeys(ctx context.Context, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error)
+	// ListLabelValues 统计 since 之后创建的告警中标签键 key 的不同值及告警数，按前缀过滤，按告警数降序返回
+	ListLabelValues(ctx context.Context, key string, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error)
 	
 	// 告警历史
 	GetHistory(ctx context.Context, alertID string) ([]*models.AlertHistory, error)
@@ -97,6 +116,7 @@ type RuleRepository interface {
 	Count(ctx context.Context, filter *models.RuleFilter) (int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
 	GetByName(ctx context.Context, name string) (*models.Rule, error)
+	ListByIDs(ctx context.Context, ids []string) ([]*models.Rule, error)
 	
 	// 规则状态管理
 	Activate(ctx context.Context, id string) error
@@ -109,6 +129,8 @@ type RuleRepository interface {
 	GetActiveRules(ctx context.Context) ([]*models.Rule, error)
 	GetRulesForEvaluation(ctx context.Context) ([]*models.Rule, error)
 	UpdateLastEvaluation(ctx context.Context, id string, evalTime time.Time, result bool, error string) error
+	// ListEvaluationLag 获取启用规则的评估延迟
+	ListEvaluationLag(ctx context.Context) ([]*models.RuleEvaluationLag, error)
 	IncrementEvaluationCount(ctx context.Context, id string) error
 	IncrementAlertCount(ctx context.Context, id string) error
 	
@@ -167,6 +189,9 @@ type DataSourceRepository interface {
 	BatchCreate(ctx context.Context, dataSources []*models.DataSource) error
 	BatchUpdate(ctx context.Context, dataSources []*models.DataSource) error
 	BatchHealthCheck(ctx context.Context, ids []string) error
+
+	// 密钥轮换
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
 }
 
 // TicketRepository 工单仓储接口
@@ -180,9 +205,11 @@ type TicketRepository interface {
 	
 	// 查询操作
 	List(ctx context.Context, filter *models.TicketFilter) (*models.TicketList, error)
+	Stream(ctx context.Context, filter *models.TicketFilter, fn func(*models.Ticket) error) error
 	Count(ctx context.Context, filter *models.TicketFilter) (int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
 	GetByAlertID(ctx context.Context, alertID string) ([]*models.Ticket, error)
+	ListByAlertIDs(ctx context.Context, alertIDs []string) ([]*models.Ticket, error)
 	
 	// 工单状态管理
 	Assign(ctx context.Context, id, assigneeID string) error
@@ -196,6 +223,7 @@ type TicketRepository interface {
 	// 工单评论
 	AddComment(ctx context.Context, comment *models.TicketComment) error
 	GetComments(ctx context.Context, ticketID string) ([]*models.TicketComment, error)
+	ListCommentsByTicketIDs(ctx context.Context, ticketIDs []string) ([]*models.TicketComment, error)
 	UpdateComment(ctx context.Context, comment *models.TicketComment) error
 	DeleteComment(ctx context.Context, id string) error
 	
@@ -203,6 +231,22 @@ type TicketRepository interface {
 	AddAttachment(ctx context.Context, attachment *models.TicketAttachment) error
 	GetAttachments(ctx context.Context, ticketID string) ([]*models.TicketAttachment, error)
 	DeleteAttachment(ctx context.Context, id string) error
+	GetAttachment(ctx context.Context, id string) (*models.TicketAttachment, error)
+
+	// 附件安全扫描
+	ListScannableAttachments(ctx context.Context, now, staleBefore time.Time, limit int) ([]*models.TicketAttachment, error)
+	ClaimAttachmentScan(ctx context.Context, id string, now, staleBefore time.Time) (bool, error)
+	RetryAttachmentScan(ctx context.Context, id string, scanResult string, retryAt time.Time) error
+	UpdateAttachmentScanResult(ctx context.Context, id string, status models.AttachmentScanStatus, scanResult *string, filePath string) error
+	
+	// 工单工作日志
+	AddWorklog(ctx context.Context, worklog *models.TicketWorklog) error
+	GetWorklogs(ctx context.Context, ticketID string) ([]*models.TicketWorklog, error)
+	GetWorklog(ctx context.Context, id string) (*models.TicketWorklog, error)
+	UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) error
+	DeleteWorklog(ctx context.Context, id string) error
+	SyncWorkTime(ctx context.Context, ticketID string) (*models.TicketWorkTime, error)
+	GetTimesheet(ctx context.Context, filter *models.TimesheetFilter) (*models.Timesheet, error)
 	
 	// 工单历史
 	GetHistory(ctx context.Context, ticketID string) ([]*models.TicketHistory, error)
@@ -236,6 +280,7 @@ type TicketRepository interface {
 type KnowledgeRepository interface {
 	// 基础CRUD操作
 	Create(ctx context.Context, knowledge *models.Knowledge) error
+	Get(ctx context.Context, key models.KnowledgeLookupKey, value string) (*models.Knowledge, error)
 	GetByID(ctx context.Context, id string) (*models.Knowledge, error)
 	GetBySlug(ctx context.Context, slug string) (*models.Knowledge, error)
 	Update(ctx context.Context, knowledge *models.Knowledge) error
@@ -249,6 +294,11 @@ type KnowledgeRepository interface {
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
 	Search(ctx context.Context, query string, filter *models.KnowledgeFilter) (*models.KnowledgeSearchResult, error)
 	
+	// slug 管理
+	UpdateSlug(ctx context.Context, id, slug string) error
+	GetSlugOwner(ctx context.Context, slug string) (string, error)
+	ResolveSlugRedirect(ctx context.Context, slug string) (*models.KnowledgeSlugLookup, error)
+
 	// 知识状态管理
 	UpdateStatus(ctx context.Context, id string, status models.KnowledgeStatus) error
 	Publish(ctx context.Context, id, publisherID string) error
@@ -309,6 +359,17 @@ type KnowledgeRepository interface {
 	BatchPublish(ctx context.Context, ids []string, publisherID string) error
 	BatchArchive(ctx context.Context, ids []string) error
 	
+	// 重复检测
+	FindSimilar(ctx context.Context, req *models.KnowledgeDuplicateCheckRequest) ([]*models.KnowledgeDuplicateCandidate, error)
+
+	// 定时发布与过期提醒
+	SchedulePublish(ctx context.Context, id string, publishAt time.Time) error
+	CancelSchedule(ctx context.Context, id string) error
+	PublishDue(ctx context.Context, now time.Time) ([]string, error)
+	UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error
+	ListExpiring(ctx context.Context, before time.Time, limit int) ([]*models.Knowledge, error)
+	MarkExpiryWarned(ctx context.Context, id string, warnedAt time.Time) error
+
 	// 清理操作
 	CleanupExpired(ctx context.Context) (int64, error)
 	CleanupDrafts(ctx context.Context, before time.Time) (int64, error)
@@ -356,6 +417,554 @@ type WebhookRepository interface {
 	// 清理操作
 	CleanupLogs(ctx context.Context, before time.Time) (int64, error)
 	CleanupInactive(ctx context.Context, before time.Time) (int64, error)
+
+	// 密钥轮换
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
+}
+
+// CustomFieldRepository 自定义字段定义仓储接口
+type CustomFieldRepository interface {
+	// 基础CRUD操作
+	Create(ctx context.Context, def *models.CustomFieldDefinition) error
+	GetByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error)
+	GetByKey(ctx context.Context, resource models.CustomFieldResource, key string) (*models.CustomFieldDefinition, error)
+	Update(ctx context.Context, def *models.CustomFieldDefinition) error
+	Delete(ctx context.Context, id string) error
+
+	// 查询操作
+	List(ctx context.Context, filter *models.CustomFieldDefinitionFilter) (*models.CustomFieldDefinitionList, error)
+	ListByResource(ctx context.Context, resource models.CustomFieldResource) ([]*models.CustomFieldDefinition, error)
+	ExistsByKey(ctx context.Context, resource models.CustomFieldResource, key string) (bool, error)
+}
+
+// KnowledgeReviewRepository 知识库审核仓储接口
+type KnowledgeReviewRepository interface {
+	// 审核策略管理
+	CreatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error
+	GetPolicy(ctx context.Context, id string) (*models.KnowledgeReviewPolicy, error)
+	GetPolicyForCategory(ctx context.Context, categoryID *string) (*models.KnowledgeReviewPolicy, error)
+	UpdatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error
+	DeletePolicy(ctx context.Context, id string) error
+	ListPolicies(ctx context.Context) ([]*models.KnowledgeReviewPolicy, error)
+	ExistsPolicyForCategory(ctx context.Context, categoryID *string, excludeID string) (bool, error)
+
+	// 审核提交与记录
+	CreateSubmission(ctx context.Context, req *models.KnowledgeReviewSubmission) error
+	GetPendingSubmission(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error)
+	GetLatestSubmission(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error)
+	CompleteSubmission(ctx context.Context, id string, status models.KnowledgeReviewSubmissionStatus) error
+	AddRecord(ctx context.Context, record *models.KnowledgeReviewRecord) error
+	GetRecords(ctx context.Context, submissionID string) ([]*models.KnowledgeReviewRecord, error)
+	ListPendingForReviewer(ctx context.Context, filter *models.KnowledgeReviewQueueFilter) (*models.KnowledgeReviewQueue, error)
+}
+
+// KnowledgeCommentRepository 知识库评论仓储接口
+type KnowledgeCommentRepository interface {
+	Create(ctx context.Context, comment *models.KnowledgeComment) error
+	GetByID(ctx context.Context, id string) (*models.KnowledgeComment, error)
+	UpdateContent(ctx context.Context, id, content string) error
+	SoftDelete(ctx context.Context, id string) error
+	ListByKnowledge(ctx context.Context, knowledgeID string) ([]*models.KnowledgeComment, error)
+
+	// 审核与互动
+	SetStatus(ctx context.Context, id string, status models.KnowledgeCommentStatus, moderatorID string, reason *string) error
+	ListFlagged(ctx context.Context, limit int) ([]*models.KnowledgeComment, error)
+	AddReaction(ctx context.Context, commentID, userID, reaction string) error
+	RemoveReaction(ctx context.Context, commentID, userID, reaction string) error
+	Flag(ctx context.Context, commentID, userID string, reason *string) (bool, error)
+}
+
+// KnowledgeACLRepository 知识库访问控制仓储接口
+type KnowledgeACLRepository interface {
+	ListEntries(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID string) ([]*models.KnowledgeACLEntry, error)
+	ReplaceEntries(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID string, entries []*models.KnowledgeACLEntry) error
+	HasAccess(ctx context.Context, knowledgeID, userID string, permission models.KnowledgeACLPermission) (bool, error)
+}
+
+// WebhookIntegrationRepository 告警接入集成仓储接口
+type WebhookIntegrationRepository interface {
+	Create(ctx context.Context, integration *models.WebhookIntegration) error
+	GetByID(ctx context.Context, id string) (*models.WebhookIntegration, error)
+	List(ctx context.Context) ([]*models.WebhookIntegration, error)
+	Update(ctx context.Context, integration *models.WebhookIntegration) error
+	UpdateSecret(ctx context.Context, id, secret string) error
+	Delete(ctx context.Context, id string) error
+	TouchLastReceived(ctx context.Context, id string, at time.Time) error
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
+}
+
+// FeatureFlagRepository 功能开关仓储接口
+type FeatureFlagRepository interface {
+	Create(ctx context.Context, flag *models.FeatureFlag) error
+	GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	List(ctx context.Context) ([]*models.FeatureFlag, error)
+	Update(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, key string) error
+}
+
+// DashboardRepository 首页仪表盘统计仓储接口
+type DashboardRepository interface {
+	GetActiveAlertsBySeverity(ctx context.Context) (map[models.AlertSeverity]int64, error)
+	GetOpenTicketsByPriority(ctx context.Context) (map[models.TicketPriority]int64, error)
+	GetSLASummary(ctx context.Context, atRiskWithin time.Duration) (*models.DashboardSLASummary, error)
+	GetRecentIncidents(ctx context.Context, limit int) ([]*models.DashboardIncident, error)
+	GetTopRules(ctx context.Context, since time.Time, limit int) ([]*models.DashboardRuleStat, error)
+}
+
+// PostmortemRepository 事件复盘仓储接口
+type PostmortemRepository interface {
+	Create(ctx context.Context, postmortem *models.Postmortem) error
+	GetByID(ctx context.Context, id string) (*models.Postmortem, error)
+	List(ctx context.Context, filter *models.PostmortemFilter) (*models.PostmortemList, error)
+	Update(ctx context.Context, postmortem *models.Postmortem) error
+	Delete(ctx context.Context, id string) error
+
+	// 改进项
+	CreateActionItem(ctx context.Context, item *models.PostmortemActionItem) error
+	GetActionItem(ctx context.Context, postmortemID, itemID string) (*models.PostmortemActionItem, error)
+	ListActionItems(ctx context.Context, postmortemID string) ([]*models.PostmortemActionItem, error)
+	UpdateActionItem(ctx context.Context, item *models.PostmortemActionItem) error
+	DeleteActionItem(ctx context.Context, postmortemID, itemID string) error
+}
+
+// RemediationRepository 修复动作执行记录仓储接口
+type RemediationRepository interface {
+	ListAlertsToSchedule(ctx context.Context, since time.Time, limit int) ([]string, error)
+	Create(ctx context.Context, execution *models.RemediationExecution) (bool, error)
+	GetByID(ctx context.Context, id string) (*models.RemediationExecution, error)
+	ListByAlert(ctx context.Context, alertID string) ([]*models.RemediationExecution, error)
+	ClaimQueued(ctx context.Context, limit int) ([]*models.RemediationExecution, error)
+	Finish(ctx context.Context, execution *models.RemediationExecution) error
+	Decide(ctx context.Context, id string, approved bool, userID string) (*models.RemediationExecution, error)
+}
+
+// ChatUserMappingRepository 聊天用户映射仓储接口
+type ChatUserMappingRepository interface {
+	Create(ctx context.Context, mapping *models.ChatUserMapping) error
+	GetByChatUser(ctx context.Context, platform models.ChatPlatform, chatUserID string) (*models.ChatUserMapping, error)
+	List(ctx context.Context, platform models.ChatPlatform) ([]*models.ChatUserMapping, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ITSMRepository 外部 ITSM 同步仓储接口
+type ITSMRepository interface {
+	CreateConnector(ctx context.Context, connector *models.ITSMConnector) error
+	GetConnector(ctx context.Context, id string) (*models.ITSMConnector, error)
+	ListConnectors(ctx context.Context, enabledOnly bool) ([]*models.ITSMConnector, error)
+	UpdateConnector(ctx context.Context, connector *models.ITSMConnector) error
+	DeleteConnector(ctx context.Context, id string) error
+	TouchConnectorSynced(ctx context.Context, id string, at time.Time) error
+
+	ListTicketsToSync(ctx context.Context, connector *models.ITSMConnector, limit int) ([]string, error)
+	GetLink(ctx context.Context, connectorID, ticketID string) (*models.ITSMTicketLink, error)
+	GetLinkByRemote(ctx context.Context, connectorID, remoteID string) (*models.ITSMTicketLink, error)
+	ListLinksByTicket(ctx context.Context, ticketID string) ([]*models.ITSMTicketLink, error)
+	SaveLink(ctx context.Context, link *models.ITSMTicketLink) error
+
+	ListSyncedCommentIDs(ctx context.Context, connectorID, ticketID string) (map[string]bool, error)
+	RemoteCommentSynced(ctx context.Context, connectorID, remoteCommentID string) (bool, error)
+	LinkComment(ctx context.Context, connectorID, ticketID, commentID string, remoteCommentID *string) error
+}
+
+// HeartbeatRepository 心跳检查仓储接口
+type HeartbeatRepository interface {
+	Create(ctx context.Context, heartbeat *models.Heartbeat) error
+	GetByID(ctx context.Context, id string) (*models.Heartbeat, error)
+	GetByToken(ctx context.Context, token string) (*models.Heartbeat, error)
+	List(ctx context.Context) ([]*models.Heartbeat, error)
+	Update(ctx context.Context, heartbeat *models.Heartbeat) error
+	Delete(ctx context.Context, id string) error
+
+	RecordPing(ctx context.Context, id string, at, expectedAt time.Time) error
+	ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.Heartbeat, error)
+	MarkDown(ctx context.Context, id string, expectedAt time.Time) (bool, error)
+}
+
+// ServiceCatalogRepository 服务目录仓储接口
+type ServiceCatalogRepository interface {
+	Create(ctx context.Context, service *models.CatalogService) error
+	GetByID(ctx context.Context, id string) (*models.CatalogService, error)
+	GetByName(ctx context.Context, name string) (*models.CatalogService, error)
+	List(ctx context.Context) ([]*models.CatalogService, error)
+	Update(ctx context.Context, service *models.CatalogService) error
+	Delete(ctx context.Context, id string) error
+
+	ListAlertWindows(ctx context.Context, name string, since, until time.Time) ([]*models.ServiceAlertWindow, error)
+	CountActiveAlerts(ctx context.Context, name string) ([]*models.ServiceAlertCount, error)
+	GetTicketStats(ctx context.Context, name string, since time.Time) (*models.ServiceTicketStats, error)
+}
+
+// SLORepository SLO 仓储接口
+type SLORepository interface {
+	Create(ctx context.Context, slo *models.SLO) error
+	GetByID(ctx context.Context, id string) (*models.SLO, error)
+	List(ctx context.Context, serviceID string) ([]*models.SLO, error)
+	ListEnabled(ctx context.Context) ([]*models.SLO, error)
+	Update(ctx context.Context, slo *models.SLO) error
+	UpdateBudget(ctx context.Context, id string, budget *models.SLOBudget) error
+	Delete(ctx context.Context, id string) error
+}
+
+// AlertSnoozeRepository 告警暂停仓储接口
+type AlertSnoozeRepository interface {
+	Create(ctx context.Context, snooze *models.AlertSnooze) error
+	GetByID(ctx context.Context, id string) (*models.AlertSnooze, error)
+	ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.AlertSnooze, error)
+	Delete(ctx context.Context, id string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NotificationRoutingRepository 通知路由仓储接口，包含通知日历和延迟通知
+type NotificationRoutingRepository interface {
+	CreateCalendar(ctx context.Context, calendar *models.NotificationCalendar) error
+	GetCalendar(ctx context.Context, id string) (*models.NotificationCalendar, error)
+	ListCalendars(ctx context.Context) ([]*models.NotificationCalendar, error)
+	UpdateCalendar(ctx context.Context, calendar *models.NotificationCalendar) error
+	DeleteCalendar(ctx context.Context, id string) error
+
+	CreateDeferred(ctx context.Context, notification *models.DeferredNotification) error
+	ListDueDeferred(ctx context.Context, now time.Time, limit int) ([]*models.DeferredNotification, error)
+	DeleteDeferred(ctx context.Context, ids []string) error
+}
+
+// LanguagePreferenceRepository 语言偏好仓储接口
+type LanguagePreferenceRepository interface {
+	Get(ctx context.Context, scope models.LanguageScope, subject string) (*models.LanguagePreference, error)
+	Set(ctx context.Context, pref *models.LanguagePreference) error
+	Delete(ctx context.Context, scope models.LanguageScope, subject string) error
+}
+
+// TagRepository 标签治理仓储接口，统计和替换告警、工单、知识库文章中的标签，并保存标签治理任务
+type TagRepository interface {
+	Usage(ctx context.Context, maxUsage int64, limit int) ([]*models.TagUsage, error)
+	CountTagged(ctx context.Context, tag string) (int64, error)
+	ReplaceTag(ctx context.Context, resource models.TagResource, from, to string, limit int) (int64, error)
+	ReplaceDefinition(ctx context.Context, from, to string) error
+
+	CreateJob(ctx context.Context, job *models.TagJob) error
+	GetJob(ctx context.Context, id string) (*models.TagJob, error)
+	ListJobs(ctx context.Context, limit int) ([]*models.TagJob, error)
+	ClaimPendingJob(ctx context.Context) (*models.TagJob, error)
+	UpdateJob(ctx context.Context, job *models.TagJob) error
+}
+
+// PartitionRepository 分区仓储接口，管理告警表和告警历史表的月分区
+type PartitionRepository interface {
+	Create(ctx context.Context, partition *models.TablePartition) (bool, error)
+	List(ctx context.Context, table string) ([]*models.TablePartition, error)
+	Drop(ctx context.Context, partition *models.TablePartition) error
+	HasActiveAlerts(ctx context.Context, partition *models.TablePartition) (bool, error)
+	PurgeInactiveAlerts(ctx context.Context, partition *models.TablePartition) (int64, error)
+	HasRows(ctx context.Context, partition *models.TablePartition) (bool, error)
+}
+
+// RetentionRepository 数据保留仓储接口，归档、清理和恢复超出保留期的记录
+type RetentionRepository interface {
+	ExpiredStats(ctx context.Context, resource models.RetentionResource, cutoff time.Time) (int64, *time.Time, error)
+	ListExpired(ctx context.Context, resource models.RetentionResource, cutoff time.Time, limit int) ([]*models.ArchiveRecord, error)
+	DeleteRecords(ctx context.Context, resource models.RetentionResource, ids []string) (int64, error)
+	RestoreRecords(ctx context.Context, resource models.RetentionResource, records []json.RawMessage, from, to time.Time) (int64, error)
+
+	CreateArchive(ctx context.Context, archive *models.DataArchive) error
+	ListArchives(ctx context.Context, filter *models.DataArchiveFilter) ([]*models.DataArchive, int64, error)
+	ArchiveStats(ctx context.Context, resource models.RetentionResource) (*models.RetentionStatus, error)
+	CreateRestore(ctx context.Context, restore *models.DataArchiveRestore) error
+}
+
+// AuditLogRepository 审计日志仓储接口
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *models.AuditLog) error
+	Stream(ctx context.Context, filter *models.AuditLogFilter, fn func(*models.AuditLog) error) error
+}
+
+// AssignmentPolicyRepository 分派策略仓储接口
+type AssignmentPolicyRepository interface {
+	Create(ctx context.Context, policy *models.AssignmentPolicy) error
+	GetByID(ctx context.Context, id string) (*models.AssignmentPolicy, error)
+	GetByTeam(ctx context.Context, team string) (*models.AssignmentPolicy, error)
+	List(ctx context.Context) ([]*models.AssignmentPolicy, error)
+	Update(ctx context.Context, policy *models.AssignmentPolicy) error
+	Delete(ctx context.Context, id string) error
+	// NextRoundRobin 原子递增轮询计数并返回递增后的值
+	NextRoundRobin(ctx context.Context, id string) (int64, error)
+	// OpenTicketCounts 统计用户未关闭的工单数，没有未关闭工单的用户不在结果中
+	OpenTicketCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+// AlertActionLinkRepository 告警操作链接使用记录仓储接口
+type AlertActionLinkRepository interface {
+	// Claim 记录链接使用，令牌已使用时返回 models.ErrActionLinkUsed
+	Claim(ctx context.Context, use *models.AlertActionLinkUse) error
+	Release(ctx context.Context, tokenID string) error
+	IsUsed(ctx context.Context, tokenID string) (bool, error)
+}
+
+// PushRepository 移动端推送设备和推送偏好仓储接口
+type PushRepository interface {
+	UpsertDevice(ctx context.Context, device *models.PushDevice) error
+	ListDevices(ctx context.Context, userID string) ([]*models.PushDevice, error)
+	DeleteDevice(ctx context.Context, id, userID string) error
+	DeleteDeviceByToken(ctx context.Context, token string) error
+
+	GetPreference(ctx context.Context, userID string) (*models.PushPreference, error)
+	SetPreference(ctx context.Context, pref *models.PushPreference) error
+}
+
+// InboxRepository 站内通知收件箱仓储接口
+type InboxRepository interface {
+	// Create 投递站内通知，同一用户相同去重键的通知已存在时不重复投递并返回 false
+	Create(ctx context.Context, item *models.InboxItem) (bool, error)
+	List(ctx context.Context, userID string, filter *models.InboxFilter) ([]*models.InboxItem, error)
+	// ListAfter 获取 Seq 大于 after 的通知，按 Seq 升序，用于实时推送
+	ListAfter(ctx context.Context, userID string, after int64, limit int) ([]*models.InboxItem, error)
+	// LatestSeq 获取用户最新一条通知的 Seq，没有通知时返回 0
+	LatestSeq(ctx context.Context, userID string) (int64, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	// MarkRead 将用户的通知标记为已读，返回本次标记的条数
+	MarkRead(ctx context.Context, userID string, ids []string) (int64, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+
+	// ListSLABreaches 获取已超出 SLA 截止时间、仍未解决且尚未通知处理人的工单
+	ListSLABreaches(ctx context.Context, now time.Time, limit int) ([]*models.InboxSLABreach, error)
+}
+
+// QuietHoursRepository 用户免打扰设置仓储接口
+type QuietHoursRepository interface {
+	Get(ctx context.Context, userID string) (*models.QuietHours, error)
+	Set(ctx context.Context, quiet *models.QuietHours) error
+	Delete(ctx context.Context, userID string) error
+}
+
+// ChangeEventRepository 变更事件仓储接口
+type ChangeEventRepository interface {
+	Create(ctx context.Context, event *models.ChangeEvent) error
+	GetByID(ctx context.Context, id string) (*models.ChangeEvent, error)
+	List(ctx context.Context, filter *models.ChangeEventFilter) ([]*models.ChangeEvent, int64, error)
+	Delete(ctx context.Context, id string) error
+
+	// ListForService 获取服务在 [from, to] 内发生的变更，按发生时间倒序
+	ListForService(ctx context.Context, service string, from, to time.Time, limit int) ([]*models.ChangeEvent, error)
+}
+
+// NotificationAttemptRepository 通知投递记录仓储接口
+type NotificationAttemptRepository interface {
+	Create(ctx context.Context, attempt *models.NotificationAttempt) error
+	// ListByNotification 获取通知的投递记录，按投递时间正序
+	ListByNotification(ctx context.Context, notificationID string) ([]*models.NotificationAttempt, error)
+	// ListByAlert 获取告警关联的全部通知的投递记录，按投递时间正序
+	ListByAlert(ctx context.Context, alertID string) ([]*models.NotificationAttempt, error)
+}
+
+// NotificationChannelRepository 团队通知渠道仓储接口，查询返回的地址和密钥为明文
+type NotificationChannelRepository interface {
+	Create(ctx context.Context, channel *models.NotificationChannel) error
+	GetByID(ctx context.Context, id string) (*models.NotificationChannel, error)
+	// FindEnabled 获取团队启用的指定类型渠道，没有时返回 ErrNotificationChannelNotFound
+	FindEnabled(ctx context.Context, team string, channelType models.NotificationType) (*models.NotificationChannel, error)
+	List(ctx context.Context, filter *models.NotificationChannelFilter) ([]*models.NotificationChannel, error)
+	Update(ctx context.Context, channel *models.NotificationChannel) error
+	Delete(ctx context.Context, id string) error
+	// RotateEncryptionKeys 使用当前主密钥重新加密渠道地址和密钥
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
+}
+
+// MetricRepository 内置指标存储仓储接口
+type MetricRepository interface {
+	// Write 写入时间序列的样本，序列不存在时创建，相同时间戳的样本覆盖已有的值，返回写入的样本数
+	Write(ctx context.Context, series []*models.MetricSeries) (int, error)
+	// Latest 获取匹配查询的序列在 (at-lookback, at] 内的最新样本
+	Latest(ctx context.Context, query *models.MetricQuery, at time.Time, lookback time.Duration) ([]*models.MetricSeries, error)
+	// DeleteBefore 删除 before 之前的样本和此后没有再上报的序列，返回删除的样本数
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TicketSLAPauseRepository 工单SLA暂停仓储接口
+type TicketSLAPauseRepository interface {
+	// Pause 开始暂停，工单已有进行中的暂停时返回 ErrTicketSLAPaused
+	Pause(ctx context.Context, pause *models.TicketSLAPause) error
+	// Resume 结束进行中的暂停并将工单的SLA截止时间顺延暂停的时长，source 不为空时只结束该来源的暂停，
+	// 没有进行中的暂停时返回 ErrTicketSLANotPaused
+	Resume(ctx context.Context, ticketID string, source models.TicketSLAPauseSource, resumedBy *string, at time.Time) (*models.TicketSLAPause, error)
+	// ListByTicket 获取工单的全部暂停区间，按暂停时间升序
+	ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketSLAPause, error)
+}
+
+// AlertSeverityRuleRepository 告警重新分级规则仓储接口
+type AlertSeverityRuleRepository interface {
+	// Create 创建规则，名称重复时返回 ErrAlertSeverityRuleExists
+	Create(ctx context.Context, rule *models.AlertSeverityRule) error
+	GetByID(ctx context.Context, id string) (*models.AlertSeverityRule, error)
+	// List 获取全部规则，按 position 和创建时间升序
+	List(ctx context.Context) ([]*models.AlertSeverityRule, error)
+	Update(ctx context.Context, rule *models.AlertSeverityRule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// StatusPageRepository 状态页事件和订阅者仓储接口
+type StatusPageRepository interface {
+	// CreateIncident 创建事件及其第一条进展说明
+	CreateIncident(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error
+	GetIncident(ctx context.Context, id string) (*models.StatusIncident, error)
+	// ListIncidents 获取事件，按创建时间倒序，不包含进展说明
+	ListIncidents(ctx context.Context, filter *models.StatusIncidentFilter) ([]*models.StatusIncident, error)
+	// UpdateIncident 更新事件的标题、影响程度、组件和计划时间
+	UpdateIncident(ctx context.Context, incident *models.StatusIncident) error
+	// AddUpdate 发布进展说明，同时更新事件的进展、影响程度和结束时间
+	AddUpdate(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error
+	DeleteIncident(ctx context.Context, id string) error
+	// ListUpdates 获取事件的进展说明，按发布时间倒序
+	ListUpdates(ctx context.Context, incidentIDs []string) ([]*models.StatusIncidentUpdate, error)
+	// ListPendingUpdates 获取需要通知且尚未通知的进展说明，按发布时间升序
+	ListPendingUpdates(ctx context.Context, limit int) ([]*models.StatusIncidentUpdate, error)
+	// MarkUpdateNotified 标记进展说明已通知，其他实例已标记时返回 false
+	MarkUpdateNotified(ctx context.Context, id string, at time.Time) (bool, error)
+
+	// UpsertSubscriber 创建订阅者，邮箱已订阅但未确认时更新订阅的组件并沿用原 ID；
+	// 邮箱已确认订阅时不修改并返回 false
+	UpsertSubscriber(ctx context.Context, subscriber *models.StatusSubscriber) (bool, error)
+	GetSubscriber(ctx context.Context, id string) (*models.StatusSubscriber, error)
+	ConfirmSubscriber(ctx context.Context, id string, at time.Time) error
+	DeleteSubscriber(ctx context.Context, id string) error
+	// ListConfirmedSubscribers 获取已确认的订阅者
+	ListConfirmedSubscribers(ctx context.Context) ([]*models.StatusSubscriber, error)
+}
+
+// WebhookSubscriptionRepository 出站 Webhook 订阅仓储接口
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *models.WebhookSubscription) error
+	// GetByID 获取订阅，返回的密钥为明文
+	GetByID(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	// List 获取全部订阅，不返回密钥
+	List(ctx context.Context) ([]*models.WebhookSubscription, error)
+	// ListEnabled 获取启用的订阅，不返回密钥
+	ListEnabled(ctx context.Context) ([]*models.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *models.WebhookSubscription) error
+	UpdateSecret(ctx context.Context, id, secret string) error
+	Delete(ctx context.Context, id string) error
+	// Claim 获取订阅的投递租约，保证同一订阅同时只有一个实例在投递
+	Claim(ctx context.Context, id string, until time.Time) (bool, error)
+	Release(ctx context.Context, id string) error
+
+	// CreateDelivery 追加待投递的事件，写入 delivery.Seq
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, subscriptionID, id string) (*models.WebhookDelivery, error)
+	// NextDelivery 获取订阅中最早的待投递事件，没有时返回 nil
+	NextDelivery(ctx context.Context, subscriptionID string) (*models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID string, filter *models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error)
+
+	// RotateEncryptionKeys 使用当前主密钥重新加密订阅密钥
+	RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
+}
+
+// TicketImportRepository 工单导入任务仓储接口
+type TicketImportRepository interface {
+	CreateJob(ctx context.Context, job *models.TicketImportJob) error
+	GetJob(ctx context.Context, id string) (*models.TicketImportJob, error)
+	ListJobs(ctx context.Context, limit int) ([]*models.TicketImportJob, error)
+	// StartJob 保存列映射并将任务置为待执行，任务已开始时返回 ErrTicketImportJobStarted
+	StartJob(ctx context.Context, id string, mapping map[string]string, dryRun bool) error
+	// ClaimPendingJob 领取待执行任务，返回的任务包含文件内容，没有时返回 nil
+	ClaimPendingJob(ctx context.Context) (*models.TicketImportJob, error)
+	UpdateJob(ctx context.Context, job *models.TicketImportJob) error
+}
+
+// AlertStatsRepository 告警统计小时汇总仓储接口
+type AlertStatsRepository interface {
+	// Refresh 重算自上次汇总以来有告警变化的小时，overlap 为回看的时长，返回写入的汇总行数
+	Refresh(ctx context.Context, overlap time.Duration) (int, error)
+	// GetWatermark 获取汇总进度，尚未汇总过时返回 nil
+	GetWatermark(ctx context.Context) (*time.Time, error)
+	Summary(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error)
+	Trend(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error)
+}
+
+// TicketSurveyRepository 工单满意度调查仓储接口
+type TicketSurveyRepository interface {
+	// ListUnsurveyed 获取 since 之后关闭且尚未发送调查的工单
+	ListUnsurveyed(ctx context.Context, since time.Time, limit int) ([]*models.Ticket, error)
+	// Create 创建调查，工单已有调查时返回 false
+	Create(ctx context.Context, survey *models.TicketSurvey) (bool, error)
+	Delete(ctx context.Context, id string) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.TicketSurvey, error)
+	// Respond 记录评价，调查已评价时返回 ErrTicketSurveyResponded
+	Respond(ctx context.Context, id string, score int, comment string) error
+	// CSAT 按过滤条件的分组方式统计调查
+	CSAT(ctx context.Context, filter *models.TicketSurveyFilter) ([]*models.TicketCSAT, error)
+}
+
+// KnowledgeFeedbackRepository 工单引用知识库文章及有用性反馈仓储接口
+type KnowledgeFeedbackRepository interface {
+	// Link 为工单关联文章，已关联时返回 ErrTicketKnowledgeLinkExists
+	Link(ctx context.Context, link *models.TicketKnowledgeLink) error
+	Unlink(ctx context.Context, ticketID, knowledgeID string) error
+	Get(ctx context.Context, ticketID, knowledgeID string) (*models.TicketKnowledgeLink, error)
+	ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketKnowledgeLink, error)
+	ListPending(ctx context.Context, userID string, limit int) ([]*models.TicketKnowledgeLink, error)
+	// MarkPrompted 将工单尚未提示过的引用标记为已提示解决人评价，返回本次标记的引用
+	MarkPrompted(ctx context.Context, ticketID, resolverID string) ([]*models.TicketKnowledgeLink, error)
+	// RecordFeedback 保存评价并重算文章的质量分，返回评价前后的文章质量
+	RecordFeedback(ctx context.Context, linkID string, useful bool, comment string) (*models.KnowledgeQuality, *models.KnowledgeQuality, error)
+	ListQuality(ctx context.Context, filter *models.KnowledgeQualityFilter) ([]*models.KnowledgeQuality, error)
+}
+
+// ApprovalRepository 敏感操作审批请求仓储接口，状态变更与审计日志在同一事务中写入
+type ApprovalRepository interface {
+	Create(ctx context.Context, request *models.ApprovalRequest, audit *models.AuditLog) error
+	GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error)
+	List(ctx context.Context, filter *models.ApprovalFilter) ([]*models.ApprovalRequest, error)
+	// Decide 变更处于 decision.From 状态的请求，请求已被其他人处理时返回 false
+	Decide(ctx context.Context, id string, decision *models.ApprovalDecision, audit *models.AuditLog) (bool, error)
+	// Complete 保存已批准请求的执行结果
+	Complete(ctx context.Context, id string, outcome *models.ApprovalOutcome, audit *models.AuditLog) error
+}
+
+// IngestRejectionRepository 告警接入拒绝记录仓储接口
+type IngestRejectionRepository interface {
+	// Create 保存拒绝记录，并删除该集成超出 keep 条的较早记录
+	Create(ctx context.Context, rejection *models.IngestRejection, keep int) error
+	List(ctx context.Context, filter *models.IngestRejectionFilter) ([]*models.IngestRejection, error)
+	// CountByReason 按原因统计集成在 since 之后的拒绝次数
+	CountByReason(ctx context.Context, integrationID string, since time.Time) ([]*models.IngestRejectionCount, error)
+}
+
+// AsyncTaskRepository 异步任务仓储接口
+type AsyncTaskRepository interface {
+	Create(ctx context.Context, task *models.AsyncTask) error
+	GetByID(ctx context.Context, id string) (*models.AsyncTask, error)
+	ListByCreator(ctx context.Context, createdBy string, limit int) ([]*models.AsyncTask, error)
+	// GetResultFile 获取任务的结果文件，没有结果文件时返回 ErrAsyncTaskResultNotReady
+	GetResultFile(ctx context.Context, id string) (*models.AsyncTaskFile, error)
+	// ClaimPending 领取最早提交的待执行任务并标记为执行中，没有待执行任务时返回 nil
+	ClaimPending(ctx context.Context) (*models.AsyncTask, error)
+	UpdateProgress(ctx context.Context, id string, total, processed int64) error
+	// Finish 保存执行中任务的执行结果
+	Finish(ctx context.Context, id string, outcome *models.AsyncTaskOutcome) error
+	// FailStale 将 startedBefore 之前开始仍在执行中的任务标记为失败
+	FailStale(ctx context.Context, startedBefore time.Time, reason string) (int64, error)
+	// DeleteFinished 删除 finishedBefore 之前结束的任务及其结果文件
+	DeleteFinished(ctx context.Context, finishedBefore time.Time) (int64, error)
+}
+
+// HandoverRepository 交班报告仓储接口
+type HandoverRepository interface {
+	CreateSchedule(ctx context.Context, schedule *models.HandoverSchedule) error
+	GetSchedule(ctx context.Context, id string) (*models.HandoverSchedule, error)
+	GetScheduleByTeam(ctx context.Context, team string) (*models.HandoverSchedule, error)
+	ListSchedules(ctx context.Context, enabledOnly bool) ([]*models.HandoverSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *models.HandoverSchedule) error
+	DeleteSchedule(ctx context.Context, id string) error
+	// MarkScheduleRun 记录排班最近一次生成交班报告的交接时刻
+	MarkScheduleRun(ctx context.Context, id string, boundary time.Time) error
+
+	// CreateReport 同一排班的同一交接时刻已有报告时返回 models.ErrHandoverReportExists
+	CreateReport(ctx context.Context, report *models.HandoverReport) error
+	UpdateReport(ctx context.Context, report *models.HandoverReport) error
+	GetReport(ctx context.Context, id string) (*models.HandoverReport, error)
+	ListReports(ctx context.Context, team string, limit int) ([]*models.HandoverReport, error)
+
+	// Summarize 汇总团队交班报告内容
+	Summarize(ctx context.Context, scope models.HandoverScope) (*models.HandoverSummary, error)
 }
 
 // PermissionRepository 权限仓储接口
@@ -422,6 +1031,48 @@ type RepositoryManager interface {
 	Auth() AuthRepository
 	Webhook() WebhookRepository
 	Notification() NotificationRepository
+	CustomField() CustomFieldRepository
+	KnowledgeReview() KnowledgeReviewRepository
+	KnowledgeComment() KnowledgeCommentRepository
+	KnowledgeACL() KnowledgeACLRepository
+	WebhookIntegration() WebhookIntegrationRepository
+	FeatureFlag() FeatureFlagRepository
+	Dashboard() DashboardRepository
+	Postmortem() PostmortemRepository
+	Remediation() RemediationRepository
+	ChatUserMapping() ChatUserMappingRepository
+	ITSM() ITSMRepository
+	Heartbeat() HeartbeatRepository
+	ServiceCatalog() ServiceCatalogRepository
+	SLO() SLORepository
+	AlertSnooze() AlertSnoozeRepository
+	NotificationRouting() NotificationRoutingRepository
+	LanguagePreference() LanguagePreferenceRepository
+	Tag() TagRepository
+	Partition() PartitionRepository
+	Retention() RetentionRepository
+	AuditLog() AuditLogRepository
+	AssignmentPolicy() AssignmentPolicyRepository
+	Handover() HandoverRepository
+	AlertActionLink() AlertActionLinkRepository
+	Push() PushRepository
+	Inbox() InboxRepository
+	QuietHours() QuietHoursRepository
+	ChangeEvent() ChangeEventRepository
+	NotificationAttempt() NotificationAttemptRepository
+	WebhookSubscription() WebhookSubscriptionRepository
+	TicketImport() TicketImportRepository
+	AlertStats() AlertStatsRepository
+	TicketSurvey() TicketSurveyRepository
+	KnowledgeFeedback() KnowledgeFeedbackRepository
+	Approval() ApprovalRepository
+	IngestRejection() IngestRejectionRepository
+	AsyncTask() AsyncTaskRepository
+	NotificationChannel() NotificationChannelRepository
+	Metric() MetricRepository
+	TicketSLAPause() TicketSLAPauseRepository
+	AlertSeverityRule() AlertSeverityRuleRepository
+	StatusPage() StatusPageRepository
 
 	// 事务管理
 	BeginTx(ctx context.Context) (RepositoryManager, error)
@@ -0,0 +1,468 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+// itsmRepository 外部 ITSM 同步仓储实现
+type itsmRepository struct {
+	db                *sqlx.DB
+	tx                *sqlx.Tx
+	encryptionService crypto.EncryptionService
+}
+
+// NewITSMRepository 创建外部 ITSM 同步仓储实例
+func NewITSMRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) ITSMRepository {
+	return &itsmRepository{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// NewITSMRepositoryWithTx 创建带事务的外部 ITSM 同步仓储实例
+func NewITSMRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) ITSMRepository {
+	return &itsmRepository{
+		tx:                tx,
+		encryptionService: encryptionService,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *itsmRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const itsmConnectorColumns = `id, name, provider, base_url, username, api_token, webhook_secret, project,
+		       ticket_types, field_mapping, conflict_strategy, sync_comments, sync_user_id, enabled,
+		       last_synced_at, created_by, created_at, updated_at`
+
+const itsmTicketLinkColumns = `id, connector_id, ticket_id, remote_id, remote_key, remote_url, last_synced_at,
+		       remote_updated_at, last_error, created_at, updated_at`
+
+// CreateConnector 创建连接器，API 令牌和 Webhook 密钥加密后存储
+func (r *itsmRepository) CreateConnector(ctx context.Context, connector *models.ITSMConnector) error {
+	if connector.ID == "" {
+		connector.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	connector.CreatedAt = now
+	connector.UpdatedAt = now
+
+	apiToken, webhookSecret, err := r.encryptSecrets(connector)
+	if err != nil {
+		return err
+	}
+	fieldMapping, err := json.Marshal(connector.FieldMapping)
+	if err != nil {
+		return fmt.Errorf("序列化字段映射失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO itsm_connectors (
+			id, name, provider, base_url, username, api_token, webhook_secret, project,
+			ticket_types, field_mapping, conflict_strategy, sync_comments, sync_user_id, enabled,
+			created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		connector.ID, connector.Name, connector.Provider, connector.BaseURL, connector.Username,
+		apiToken, webhookSecret, connector.Project, pq.Array(connector.TicketTypes), fieldMapping,
+		connector.ConflictStrategy, connector.SyncComments, connector.SyncUserID, connector.Enabled,
+		connector.CreatedBy, connector.CreatedAt, connector.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建ITSM连接器失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetConnector 根据ID获取连接器，返回的令牌和密钥为明文
+func (r *itsmRepository) GetConnector(ctx context.Context, id string) (*models.ITSMConnector, error) {
+	query := `
+		SELECT ` + itsmConnectorColumns + `
+		FROM itsm_connectors
+		WHERE id = $1`
+
+	connector, err := scanITSMConnector(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrITSMConnectorNotFound
+		}
+		return nil, fmt.Errorf("获取ITSM连接器失败: %w", err)
+	}
+
+	if err := r.decryptSecrets(connector); err != nil {
+		return nil, err
+	}
+	return connector, nil
+}
+
+// ListConnectors 获取连接器列表，enabledOnly 为 true 时只返回启用的连接器，返回的令牌和密钥为明文
+func (r *itsmRepository) ListConnectors(ctx context.Context, enabledOnly bool) ([]*models.ITSMConnector, error) {
+	query := `
+		SELECT ` + itsmConnectorColumns + `
+		FROM itsm_connectors
+		WHERE ($1 = FALSE OR enabled = TRUE)
+		ORDER BY created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, enabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("获取ITSM连接器列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	connectors := make([]*models.ITSMConnector, 0)
+	for rows.Next() {
+		connector, err := scanITSMConnector(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描ITSM连接器失败: %w", err)
+		}
+		if err := r.decryptSecrets(connector); err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, connector)
+	}
+
+	return connectors, rows.Err()
+}
+
+// UpdateConnector 更新连接器，Webhook 密钥不可修改
+func (r *itsmRepository) UpdateConnector(ctx context.Context, connector *models.ITSMConnector) error {
+	connector.UpdatedAt = time.Now()
+
+	apiToken, err := r.encryptionService.Encrypt(connector.APIToken)
+	if err != nil {
+		return fmt.Errorf("加密API令牌失败: %w", err)
+	}
+	fieldMapping, err := json.Marshal(connector.FieldMapping)
+	if err != nil {
+		return fmt.Errorf("序列化字段映射失败: %w", err)
+	}
+
+	query := `
+		UPDATE itsm_connectors SET
+			name = $1,
+			provider = $2,
+			base_url = $3,
+			username = $4,
+			api_token = $5,
+			project = $6,
+			ticket_types = $7,
+			field_mapping = $8,
+			conflict_strategy = $9,
+			sync_comments = $10,
+			sync_user_id = $11,
+			enabled = $12,
+			updated_at = $13
+		WHERE id = $14`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		connector.Name, connector.Provider, connector.BaseURL, connector.Username, apiToken,
+		connector.Project, pq.Array(connector.TicketTypes), fieldMapping, connector.ConflictStrategy,
+		connector.SyncComments, connector.SyncUserID, connector.Enabled, connector.UpdatedAt, connector.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新ITSM连接器失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrITSMConnectorNotFound
+	}
+
+	return nil
+}
+
+// DeleteConnector 删除连接器，工单关联和评论关联随之删除
+func (r *itsmRepository) DeleteConnector(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM itsm_connectors WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除ITSM连接器失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrITSMConnectorNotFound
+	}
+
+	return nil
+}
+
+// TouchConnectorSynced 记录连接器最近一次同步时间
+func (r *itsmRepository) TouchConnectorSynced(ctx context.Context, id string, at time.Time) error {
+	_, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE itsm_connectors SET last_synced_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return fmt.Errorf("更新ITSM连接器同步时间失败: %w", err)
+	}
+	return nil
+}
+
+// ListTicketsToSync 获取需要推送到外部系统的工单ID：连接器创建后新建且尚未关联的工单，
+// 以及上次同步后有修改的已关联工单
+func (r *itsmRepository) ListTicketsToSync(ctx context.Context, connector *models.ITSMConnector, limit int) ([]string, error) {
+	query := `
+		SELECT t.id
+		FROM tickets t
+		LEFT JOIN itsm_ticket_links l ON l.ticket_id = t.id AND l.connector_id = $1
+		WHERE t.deleted_at IS NULL
+		  AND (cardinality($2::text[]) = 0 OR t.type = ANY($2))
+		  AND ((l.id IS NULL AND t.created_at >= $3) OR t.updated_at > l.last_synced_at)
+		ORDER BY t.updated_at
+		LIMIT $4`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query,
+		connector.ID, pq.Array(connector.TicketTypes), connector.CreatedAt, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取待同步工单失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("扫描待同步工单失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetLink 获取工单在连接器下的外部工单关联
+func (r *itsmRepository) GetLink(ctx context.Context, connectorID, ticketID string) (*models.ITSMTicketLink, error) {
+	query := `
+		SELECT ` + itsmTicketLinkColumns + `
+		FROM itsm_ticket_links
+		WHERE connector_id = $1 AND ticket_id = $2`
+
+	return r.getLink(ctx, query, connectorID, ticketID)
+}
+
+// GetLinkByRemote 根据外部工单ID获取关联
+func (r *itsmRepository) GetLinkByRemote(ctx context.Context, connectorID, remoteID string) (*models.ITSMTicketLink, error) {
+	query := `
+		SELECT ` + itsmTicketLinkColumns + `
+		FROM itsm_ticket_links
+		WHERE connector_id = $1 AND remote_id = $2`
+
+	return r.getLink(ctx, query, connectorID, remoteID)
+}
+
+func (r *itsmRepository) getLink(ctx context.Context, query string, args ...interface{}) (*models.ITSMTicketLink, error) {
+	link, err := scanITSMTicketLink(r.getExecutor().QueryRowxContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrITSMTicketLinkNotFound
+		}
+		return nil, fmt.Errorf("获取外部工单关联失败: %w", err)
+	}
+	return link, nil
+}
+
+// ListLinksByTicket 获取工单的全部外部工单关联
+func (r *itsmRepository) ListLinksByTicket(ctx context.Context, ticketID string) ([]*models.ITSMTicketLink, error) {
+	query := `
+		SELECT ` + itsmTicketLinkColumns + `
+		FROM itsm_ticket_links
+		WHERE ticket_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("获取外部工单关联失败: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]*models.ITSMTicketLink, 0)
+	for rows.Next() {
+		link, err := scanITSMTicketLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描外部工单关联失败: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// SaveLink 创建或更新外部工单关联
+func (r *itsmRepository) SaveLink(ctx context.Context, link *models.ITSMTicketLink) error {
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
+	}
+	link.UpdatedAt = now
+
+	query := `
+		INSERT INTO itsm_ticket_links (
+			id, connector_id, ticket_id, remote_id, remote_key, remote_url, last_synced_at,
+			remote_updated_at, last_error, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (connector_id, ticket_id) DO UPDATE SET
+			remote_id = EXCLUDED.remote_id,
+			remote_key = EXCLUDED.remote_key,
+			remote_url = EXCLUDED.remote_url,
+			last_synced_at = EXCLUDED.last_synced_at,
+			remote_updated_at = EXCLUDED.remote_updated_at,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		link.ID, link.ConnectorID, link.TicketID, link.RemoteID, link.RemoteKey, link.RemoteURL,
+		link.LastSyncedAt, link.RemoteUpdatedAt, link.LastError, link.CreatedAt, link.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("保存外部工单关联失败: %w", err)
+	}
+
+	return nil
+}
+
+// ListSyncedCommentIDs 获取工单在连接器下已同步的平台评论ID
+func (r *itsmRepository) ListSyncedCommentIDs(ctx context.Context, connectorID, ticketID string) (map[string]bool, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx,
+		`SELECT comment_id FROM itsm_comment_links WHERE connector_id = $1 AND ticket_id = $2`,
+		connectorID, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("获取已同步评论失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("扫描已同步评论失败: %w", err)
+		}
+		ids[id] = true
+	}
+
+	return ids, rows.Err()
+}
+
+// RemoteCommentSynced 外部评论是否已同步到平台
+func (r *itsmRepository) RemoteCommentSynced(ctx context.Context, connectorID, remoteCommentID string) (bool, error) {
+	var exists bool
+	err := sqlx.GetContext(ctx, r.getExecutor(), &exists,
+		`SELECT EXISTS(SELECT 1 FROM itsm_comment_links WHERE connector_id = $1 AND remote_comment_id = $2)`,
+		connectorID, remoteCommentID)
+	if err != nil {
+		return false, fmt.Errorf("检查外部评论是否已同步失败: %w", err)
+	}
+	return exists, nil
+}
+
+// LinkComment 记录平台评论与外部评论的对应关系，外部系统不返回评论ID时 remoteCommentID 为空
+func (r *itsmRepository) LinkComment(ctx context.Context, connectorID, ticketID, commentID string, remoteCommentID *string) error {
+	query := `
+		INSERT INTO itsm_comment_links (connector_id, comment_id, ticket_id, remote_comment_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING`
+
+	_, err := r.getExecutor().ExecContext(ctx, query, connectorID, commentID, ticketID, remoteCommentID, time.Now())
+	if err != nil {
+		return fmt.Errorf("记录评论同步关系失败: %w", err)
+	}
+	return nil
+}
+
+// encryptSecrets 加密连接器的 API 令牌和 Webhook 密钥
+func (r *itsmRepository) encryptSecrets(connector *models.ITSMConnector) (string, string, error) {
+	apiToken, err := r.encryptionService.Encrypt(connector.APIToken)
+	if err != nil {
+		return "", "", fmt.Errorf("加密API令牌失败: %w", err)
+	}
+	webhookSecret, err := r.encryptionService.Encrypt(connector.WebhookSecret)
+	if err != nil {
+		return "", "", fmt.Errorf("加密Webhook密钥失败: %w", err)
+	}
+	return apiToken, webhookSecret, nil
+}
+
+// decryptSecrets 解密连接器的 API 令牌和 Webhook 密钥
+func (r *itsmRepository) decryptSecrets(connector *models.ITSMConnector) error {
+	var err error
+	if connector.APIToken, err = r.encryptionService.Decrypt(connector.APIToken); err != nil {
+		return fmt.Errorf("解密API令牌失败: %w", err)
+	}
+	if connector.WebhookSecret, err = r.encryptionService.Decrypt(connector.WebhookSecret); err != nil {
+		return fmt.Errorf("解密Webhook密钥失败: %w", err)
+	}
+	return nil
+}
+
+// scanITSMConnector 扫描 ITSM 连接器
+func scanITSMConnector(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.ITSMConnector, error) {
+	var connector models.ITSMConnector
+	var ticketTypes pq.StringArray
+	var fieldMapping []byte
+
+	err := scanner.Scan(
+		&connector.ID, &connector.Name, &connector.Provider, &connector.BaseURL, &connector.Username,
+		&connector.APIToken, &connector.WebhookSecret, &connector.Project, &ticketTypes, &fieldMapping,
+		&connector.ConflictStrategy, &connector.SyncComments, &connector.SyncUserID, &connector.Enabled,
+		&connector.LastSyncedAt, &connector.CreatedBy, &connector.CreatedAt, &connector.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	connector.TicketTypes = []string(ticketTypes)
+	if connector.TicketTypes == nil {
+		connector.TicketTypes = []string{}
+	}
+	if len(fieldMapping) > 0 {
+		if err := json.Unmarshal(fieldMapping, &connector.FieldMapping); err != nil {
+			return nil, fmt.Errorf("解析字段映射失败: %w", err)
+		}
+	}
+
+	return &connector, nil
+}
+
+// scanITSMTicketLink 扫描外部工单关联
+func scanITSMTicketLink(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.ITSMTicketLink, error) {
+	var link models.ITSMTicketLink
+	err := scanner.Scan(
+		&link.ID, &link.ConnectorID, &link.TicketID, &link.RemoteID, &link.RemoteKey, &link.RemoteURL,
+		&link.LastSyncedAt, &link.RemoteUpdatedAt, &link.LastError, &link.CreatedAt, &link.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
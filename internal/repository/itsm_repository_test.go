@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+var itsmConnectorTestColumns = []string{
+	"id", "name", "provider", "base_url", "username", "api_token", "webhook_secret", "project",
+	"ticket_types", "field_mapping", "conflict_strategy", "sync_comments", "sync_user_id", "enabled",
+	"last_synced_at", "created_by", "created_at", "updated_at",
+}
+
+func TestITSMRepository_ConnectorSecretsEncrypted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	encryption := crypto.NewAESEncryptionService("test-key")
+	repo := NewITSMRepository(sqlx.NewDb(db, "postgres"), encryption)
+
+	connector := &models.ITSMConnector{
+		Name:             "ops jira",
+		Provider:         models.ITSMProviderJira,
+		BaseURL:          "https://jira.example.com",
+		APIToken:         "api-token",
+		WebhookSecret:    "hook-secret",
+		Project:          "OPS",
+		TicketTypes:      []string{"incident"},
+		FieldMapping:     models.DefaultITSMFieldMapping(models.ITSMProviderJira),
+		ConflictStrategy: models.ITSMConflictLatestWins,
+		SyncUserID:       "u1",
+		Enabled:          true,
+		CreatedBy:        "admin",
+	}
+
+	mock.ExpectExec(`INSERT INTO itsm_connectors`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.CreateConnector(context.Background(), connector))
+	assert.NotEmpty(t, connector.ID)
+
+	apiToken, err := encryption.Encrypt("api-token")
+	require.NoError(t, err)
+	webhookSecret, err := encryption.Encrypt("hook-secret")
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM itsm_connectors\s+WHERE id = \$1`).
+		WithArgs(connector.ID).
+		WillReturnRows(sqlmock.NewRows(itsmConnectorTestColumns).AddRow(
+			connector.ID, "ops jira", "jira", "https://jira.example.com", "", apiToken, webhookSecret, "OPS",
+			"{incident}", []byte(`{"issue_type":"Bug","status":{"open":"Backlog"}}`), "latest_wins", true, "u1", true,
+			nil, "admin", now, now))
+
+	got, err := repo.GetConnector(context.Background(), connector.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "api-token", got.APIToken)
+	assert.Equal(t, "hook-secret", got.WebhookSecret)
+	assert.Equal(t, []string{"incident"}, got.TicketTypes)
+	assert.Equal(t, "Bug", got.FieldMapping.IssueType)
+	assert.Equal(t, "Backlog", got.FieldMapping.RemoteStatus(models.TicketStatusOpen))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestITSMRepository_ListTicketsToSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewITSMRepository(sqlx.NewDb(db, "postgres"), crypto.NewAESEncryptionService("test-key"))
+	connector := &models.ITSMConnector{ID: "c1", TicketTypes: []string{}, CreatedAt: time.Now()}
+
+	mock.ExpectQuery(`SELECT t.id\s+FROM tickets t\s+LEFT JOIN itsm_ticket_links l .+ t.updated_at > l.last_synced_at`).
+		WithArgs("c1", sqlmock.AnyArg(), connector.CreatedAt, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("t1").AddRow("t2"))
+
+	ids, err := repo.ListTicketsToSync(context.Background(), connector, 50)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestITSMRepository_GetLinkByRemote_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewITSMRepository(sqlx.NewDb(db, "postgres"), crypto.NewAESEncryptionService("test-key"))
+
+	mock.ExpectQuery(`SELECT .+ FROM itsm_ticket_links\s+WHERE connector_id = \$1 AND remote_id = \$2`).
+		WithArgs("c1", "10001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetLinkByRemote(context.Background(), "c1", "10001")
+	assert.ErrorIs(t, err, models.ErrITSMTicketLinkNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
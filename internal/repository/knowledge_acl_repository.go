@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// knowledgeACLRepository 知识库访问控制仓储实现
+type knowledgeACLRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewKnowledgeACLRepository 创建知识库访问控制仓储实例
+func NewKnowledgeACLRepository(db *sqlx.DB) KnowledgeACLRepository {
+	return &knowledgeACLRepository{
+		db: db,
+	}
+}
+
+// NewKnowledgeACLRepositoryWithTx 创建带事务的知识库访问控制仓储实例
+func NewKnowledgeACLRepositoryWithTx(tx *sqlx.Tx) KnowledgeACLRepository {
+	return &knowledgeACLRepository{
+		tx: tx,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *knowledgeACLRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// knowledgeACLCondition 生成按访问控制过滤 knowledge_articles 的 SQL 条件
+// viewer 为当前用户 ID 的占位符（如 $3），writeOnly 为 true 时只认可写权限。
+// 规则：作者始终可访问；文章有授权条目时按文章条目判断，否则按所属分类条目判断，都没有时不限制
+func knowledgeACLCondition(viewer string, writeOnly bool) string {
+	permission := ""
+	if writeOnly {
+		permission = " AND e.permission = 'write'"
+	}
+
+	match := func(resourceType, resourceID string) string {
+		return fmt.Sprintf(`EXISTS (SELECT 1 FROM knowledge_acl_entries e JOIN users u ON u.id::text = %[1]s
+				WHERE e.resource_type = '%[2]s' AND e.resource_id = %[3]s%[4]s
+				  AND ((e.principal_type = 'user' AND e.principal_id = u.id::text)
+				    OR (e.principal_type = 'role' AND e.principal_id = u.role::text)
+				    OR (e.principal_type = 'team' AND e.principal_id = u.team)))`,
+			viewer, resourceType, resourceID, permission)
+	}
+	restricted := func(resourceType, resourceID string) string {
+		return fmt.Sprintf(`EXISTS (SELECT 1 FROM knowledge_acl_entries e WHERE e.resource_type = '%s' AND e.resource_id = %s)`,
+			resourceType, resourceID)
+	}
+
+	articleID := "knowledge_articles.id::text"
+	categoryID := "knowledge_articles.category_id::text"
+
+	return fmt.Sprintf(`(knowledge_articles.author_id::text = %s OR CASE
+			WHEN %s THEN %s
+			WHEN knowledge_articles.category_id IS NOT NULL AND %s THEN %s
+			ELSE TRUE END)`,
+		viewer,
+		restricted(string(models.KnowledgeACLResourceArticle), articleID),
+		match(string(models.KnowledgeACLResourceArticle), articleID),
+		restricted(string(models.KnowledgeACLResourceCategory), categoryID),
+		match(string(models.KnowledgeACLResourceCategory), categoryID))
+}
+
+// ListEntries 获取资源的授权条目
+func (r *knowledgeACLRepository) ListEntries(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID string) ([]*models.KnowledgeACLEntry, error) {
+	query := `
+		SELECT id, resource_type, resource_id, principal_type, principal_id, permission, created_by, created_at
+		FROM knowledge_acl_entries
+		WHERE resource_type = $1 AND resource_id = $2
+		ORDER BY principal_type, principal_id, permission`
+
+	entries := make([]*models.KnowledgeACLEntry, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &entries, query, resourceType, resourceID); err != nil {
+		return nil, fmt.Errorf("获取访问控制条目失败: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ReplaceEntries 整体替换资源的授权条目，应在事务中调用
+func (r *knowledgeACLRepository) ReplaceEntries(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID string, entries []*models.KnowledgeACLEntry) error {
+	_, err := r.getExecutor().ExecContext(ctx,
+		`DELETE FROM knowledge_acl_entries WHERE resource_type = $1 AND resource_id = $2`,
+		resourceType, resourceID)
+	if err != nil {
+		return fmt.Errorf("删除访问控制条目失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO knowledge_acl_entries (
+			id, resource_type, resource_id, principal_type, principal_id, permission, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (resource_type, resource_id, principal_type, principal_id, permission) DO NOTHING`
+
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.ID == "" {
+			entry.ID = uuid.New().String()
+		}
+		entry.ResourceType = resourceType
+		entry.ResourceID = resourceID
+		entry.CreatedAt = now
+
+		_, err := r.getExecutor().ExecContext(ctx, query,
+			entry.ID, entry.ResourceType, entry.ResourceID, entry.PrincipalType,
+			entry.PrincipalID, entry.Permission, entry.CreatedBy, entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("创建访问控制条目失败: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// HasAccess 检查用户对文章是否有指定权限，文章不存在时返回 false
+func (r *knowledgeACLRepository) HasAccess(ctx context.Context, knowledgeID, userID string, permission models.KnowledgeACLPermission) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM knowledge_articles
+			WHERE knowledge_articles.id::text = $1 AND deleted_at IS NULL AND %s
+		)`, knowledgeACLCondition("$2", permission == models.KnowledgeACLPermissionWrite))
+
+	var allowed bool
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &allowed, query, knowledgeID, userID); err != nil {
+		return false, fmt.Errorf("检查知识库访问权限失败: %w", err)
+	}
+
+	return allowed, nil
+}
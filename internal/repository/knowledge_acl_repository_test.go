@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestKnowledgeACLRepository_ReplaceEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeACLRepository(sqlxDB)
+
+	entries := []*models.KnowledgeACLEntry{
+		{PrincipalType: models.KnowledgeACLPrincipalTeam, PrincipalID: "sre", Permission: models.KnowledgeACLPermissionWrite},
+		{PrincipalType: models.KnowledgeACLPrincipalRole, PrincipalID: "operator", Permission: models.KnowledgeACLPermissionRead},
+	}
+
+	mock.ExpectExec(`DELETE FROM knowledge_acl_entries WHERE resource_type = \$1 AND resource_id = \$2`).
+		WithArgs(models.KnowledgeACLResourceCategory, "category-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO knowledge_acl_entries`).
+		WithArgs(sqlmock.AnyArg(), models.KnowledgeACLResourceCategory, "category-1",
+			models.KnowledgeACLPrincipalTeam, "sre", models.KnowledgeACLPermissionWrite, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO knowledge_acl_entries`).
+		WithArgs(sqlmock.AnyArg(), models.KnowledgeACLResourceCategory, "category-1",
+			models.KnowledgeACLPrincipalRole, "operator", models.KnowledgeACLPermissionRead, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.ReplaceEntries(context.Background(), models.KnowledgeACLResourceCategory, "category-1", entries)
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries[0].ID)
+	assert.Equal(t, "category-1", entries[1].ResourceID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeACLRepository_HasAccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeACLRepository(sqlxDB)
+
+	// 写权限检查只认可 write 条目
+	mock.ExpectQuery(`SELECT EXISTS .+knowledge_acl_entries.+e.permission = 'write'`).
+		WithArgs("knowledge-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	allowed, err := repo.HasAccess(context.Background(), "knowledge-1", "user-1", models.KnowledgeACLPermissionWrite)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_List_WithViewer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	viewerID := "user-1"
+	filter := &models.KnowledgeFilter{
+		Page:     1,
+		PageSize: 10,
+		ViewerID: &viewerID,
+	}
+
+	// 列表和总数都要按访问控制过滤，避免受限文章泄露
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM knowledge_articles WHERE deleted_at IS NULL AND \(knowledge_articles.author_id::text = \$1 OR .+knowledge_acl_entries`).
+		WithArgs(viewerID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles WHERE deleted_at IS NULL AND .+knowledge_acl_entries.+ LIMIT \$2 OFFSET \$3`).
+		WithArgs(viewerID, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := repo.List(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeACLCondition(t *testing.T) {
+	read := knowledgeACLCondition("$4", false)
+	assert.Contains(t, read, "knowledge_articles.author_id::text = $4")
+	assert.Contains(t, read, "e.resource_type = 'article' AND e.resource_id = knowledge_articles.id::text")
+	assert.Contains(t, read, "e.resource_type = 'category' AND e.resource_id = knowledge_articles.category_id::text")
+	assert.NotContains(t, read, "e.permission")
+
+	write := knowledgeACLCondition("$4", true)
+	assert.Contains(t, write, "e.permission = 'write'")
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// knowledgeCommentRepository 知识库评论仓储实现
+type knowledgeCommentRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewKnowledgeCommentRepository 创建知识库评论仓储实例
+func NewKnowledgeCommentRepository(db *sqlx.DB) KnowledgeCommentRepository {
+	return &knowledgeCommentRepository{
+		db: db,
+	}
+}
+
+// NewKnowledgeCommentRepositoryWithTx 创建带事务的知识库评论仓储实例
+func NewKnowledgeCommentRepositoryWithTx(tx *sqlx.Tx) KnowledgeCommentRepository {
+	return &knowledgeCommentRepository{
+		tx: tx,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *knowledgeCommentRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const knowledgeCommentColumns = `c.id, c.knowledge_id, c.parent_id, c.author_id, c.content, c.status,
+		       c.flag_count, c.moderated_by, c.moderated_at, c.moderation_reason, c.edited_at,
+		       c.created_at, c.updated_at, c.deleted_at,
+		       (SELECT COALESCE(json_object_agg(x.reaction, x.cnt), '{}')::text
+		        FROM (SELECT reaction, COUNT(*) AS cnt FROM knowledge_comment_reactions
+		              WHERE comment_id = c.id GROUP BY reaction) x)`
+
+// Create 创建评论
+func (r *knowledgeCommentRepository) Create(ctx context.Context, comment *models.KnowledgeComment) error {
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+	if comment.Status == "" {
+		comment.Status = models.KnowledgeCommentStatusVisible
+	}
+	if comment.Reactions == nil {
+		comment.Reactions = map[string]int{}
+	}
+
+	query := `
+		INSERT INTO knowledge_comments (
+			id, knowledge_id, parent_id, author_id, content, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		comment.ID, comment.KnowledgeID, comment.ParentID, comment.AuthorID, comment.Content,
+		comment.Status, comment.CreatedAt, comment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建评论失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取评论
+func (r *knowledgeCommentRepository) GetByID(ctx context.Context, id string) (*models.KnowledgeComment, error) {
+	query := `
+		SELECT ` + knowledgeCommentColumns + `
+		FROM knowledge_comments c
+		WHERE c.id = $1 AND c.deleted_at IS NULL`
+
+	comment, err := scanKnowledgeComment(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+
+	return comment, nil
+}
+
+// UpdateContent 编辑评论内容
+func (r *knowledgeCommentRepository) UpdateContent(ctx context.Context, id, content string) error {
+	now := time.Now()
+	query := `
+		UPDATE knowledge_comments SET
+			content = $1,
+			edited_at = $2,
+			updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`
+
+	return r.execAffectingComment(ctx, "编辑评论失败", query, content, now, id)
+}
+
+// SoftDelete 软删除评论，保留记录以维持讨论串结构
+func (r *knowledgeCommentRepository) SoftDelete(ctx context.Context, id string) error {
+	query := `
+		UPDATE knowledge_comments SET
+			deleted_at = $1,
+			updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	return r.execAffectingComment(ctx, "删除评论失败", query, time.Now(), id)
+}
+
+// SetStatus 设置评论审核状态
+func (r *knowledgeCommentRepository) SetStatus(ctx context.Context, id string, status models.KnowledgeCommentStatus, moderatorID string, reason *string) error {
+	now := time.Now()
+	query := `
+		UPDATE knowledge_comments SET
+			status = $1,
+			moderated_by = $2,
+			moderated_at = $3,
+			moderation_reason = $4,
+			updated_at = $3
+		WHERE id = $5 AND deleted_at IS NULL`
+
+	return r.execAffectingComment(ctx, "更新评论状态失败", query, status, moderatorID, now, reason, id)
+}
+
+// ListByKnowledge 获取文章的全部评论（按时间排序）
+// 已删除的评论仅在仍有未删除回复时返回，用于保持讨论串完整
+func (r *knowledgeCommentRepository) ListByKnowledge(ctx context.Context, knowledgeID string) ([]*models.KnowledgeComment, error) {
+	query := `
+		SELECT ` + knowledgeCommentColumns + `
+		FROM knowledge_comments c
+		WHERE c.knowledge_id = $1
+		  AND (c.deleted_at IS NULL OR EXISTS (
+			SELECT 1 FROM knowledge_comments reply
+			WHERE reply.parent_id = c.id AND reply.deleted_at IS NULL
+		  ))
+		ORDER BY c.created_at`
+
+	return r.queryComments(ctx, query, knowledgeID)
+}
+
+// ListFlagged 获取被举报的评论，按举报次数倒序
+func (r *knowledgeCommentRepository) ListFlagged(ctx context.Context, limit int) ([]*models.KnowledgeComment, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT ` + knowledgeCommentColumns + `
+		FROM knowledge_comments c
+		WHERE c.flag_count > 0 AND c.deleted_at IS NULL AND c.status = $1
+		ORDER BY c.flag_count DESC, c.created_at
+		LIMIT $2`
+
+	return r.queryComments(ctx, query, models.KnowledgeCommentStatusVisible, limit)
+}
+
+// AddReaction 添加表情回应，重复回应忽略
+func (r *knowledgeCommentRepository) AddReaction(ctx context.Context, commentID, userID, reaction string) error {
+	query := `
+		INSERT INTO knowledge_comment_reactions (comment_id, user_id, reaction, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (comment_id, user_id, reaction) DO NOTHING`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, commentID, userID, reaction, time.Now()); err != nil {
+		return fmt.Errorf("添加表情回应失败: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveReaction 取消表情回应
+func (r *knowledgeCommentRepository) RemoveReaction(ctx context.Context, commentID, userID, reaction string) error {
+	query := `DELETE FROM knowledge_comment_reactions WHERE comment_id = $1 AND user_id = $2 AND reaction = $3`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, commentID, userID, reaction); err != nil {
+		return fmt.Errorf("取消表情回应失败: %w", err)
+	}
+
+	return nil
+}
+
+// Flag 举报评论，返回是否为该用户首次举报
+func (r *knowledgeCommentRepository) Flag(ctx context.Context, commentID, userID string, reason *string) (bool, error) {
+	query := `
+		INSERT INTO knowledge_comment_flags (comment_id, user_id, reason, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (comment_id, user_id) DO NOTHING`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, commentID, userID, reason, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("举报评论失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取举报结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	updateQuery := `UPDATE knowledge_comments SET flag_count = flag_count + 1 WHERE id = $1`
+	if _, err := r.getExecutor().ExecContext(ctx, updateQuery, commentID); err != nil {
+		return false, fmt.Errorf("更新举报次数失败: %w", err)
+	}
+
+	return true, nil
+}
+
+// execAffectingComment 执行更新语句，未影响任何行时返回评论不存在
+func (r *knowledgeCommentRepository) execAffectingComment(ctx context.Context, action, query string, args ...interface{}) error {
+	result, err := r.getExecutor().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// queryComments 查询评论列表
+func (r *knowledgeCommentRepository) queryComments(ctx context.Context, query string, args ...interface{}) ([]*models.KnowledgeComment, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取评论列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]*models.KnowledgeComment, 0)
+	for rows.Next() {
+		comment, err := scanKnowledgeComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描评论数据失败: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	return comments, nil
+}
+
+// scanKnowledgeComment 扫描评论
+func scanKnowledgeComment(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.KnowledgeComment, error) {
+	var comment models.KnowledgeComment
+	var reactionsJSON sql.NullString
+
+	err := scanner.Scan(
+		&comment.ID, &comment.KnowledgeID, &comment.ParentID, &comment.AuthorID, &comment.Content, &comment.Status,
+		&comment.FlagCount, &comment.ModeratedBy, &comment.ModeratedAt, &comment.ModerationReason, &comment.EditedAt,
+		&comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &reactionsJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Reactions = map[string]int{}
+	if reactionsJSON.Valid && reactionsJSON.String != "" {
+		if err := json.Unmarshal([]byte(reactionsJSON.String), &comment.Reactions); err != nil {
+			return nil, fmt.Errorf("反序列化表情回应失败: %w", err)
+		}
+	}
+
+	return &comment, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestKnowledgeCommentRepository_Flag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeCommentRepository(sqlxDB)
+
+	mock.ExpectExec(`INSERT INTO knowledge_comment_flags`).
+		WithArgs("comment-1", "user-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE knowledge_comments SET flag_count = flag_count \+ 1`).
+		WithArgs("comment-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	added, err := repo.Flag(context.Background(), "comment-1", "user-1", nil)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// 同一用户重复举报不再增加计数
+	mock.ExpectExec(`INSERT INTO knowledge_comment_flags`).
+		WithArgs("comment-1", "user-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	added, err = repo.Flag(context.Background(), "comment-1", "user-1", nil)
+	require.NoError(t, err)
+	assert.False(t, added)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeCommentRepository_UpdateContent_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeCommentRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE knowledge_comments SET`).
+		WithArgs("新内容", sqlmock.AnyArg(), "comment-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateContent(context.Background(), "comment-1", "新内容")
+	assert.ErrorIs(t, err, models.ErrCommentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBuildKnowledgeCommentTree(t *testing.T) {
+	root := "c1"
+	missing := "gone"
+	comments := []*models.KnowledgeComment{
+		{ID: "c1"},
+		{ID: "c2", ParentID: &root},
+		{ID: "c3", ParentID: &missing},
+		{ID: "c4", ParentID: &root},
+	}
+
+	tree := models.BuildKnowledgeCommentTree(comments)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "c1", tree[0].ID)
+	assert.Equal(t, "c3", tree[1].ID)
+	require.Len(t, tree[0].Replies, 2)
+	assert.Equal(t, "c2", tree[0].Replies[0].ID)
+	assert.Equal(t, "c4", tree[0].Replies[1].ID)
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// knowledgeFeedbackRepository 工单引用知识库文章及有用性反馈仓储实现
+type knowledgeFeedbackRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewKnowledgeFeedbackRepository 创建知识库有用性反馈仓储实例
+func NewKnowledgeFeedbackRepository(db *sqlx.DB) KnowledgeFeedbackRepository {
+	return &knowledgeFeedbackRepository{db: db}
+}
+
+// NewKnowledgeFeedbackRepositoryWithTx 创建带事务的知识库有用性反馈仓储实例
+func NewKnowledgeFeedbackRepositoryWithTx(tx *sqlx.Tx) KnowledgeFeedbackRepository {
+	return &knowledgeFeedbackRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *knowledgeFeedbackRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// ticketKnowledgeLinkColumns 引用查询的列，与 scanTicketKnowledgeLink 的扫描顺序一致，l 为引用表、t 为工单表、k 为文章表
+const ticketKnowledgeLinkColumns = `l.id, l.ticket_id, l.knowledge_id, l.linked_by, l.resolver_id, l.prompted_at,
+		l.useful, l.comment, l.rated_at, l.created_at, t.number, COALESCE(k.title, '')`
+
+// ticketKnowledgeLinkJoins 引用查询关联的工单和文章，文章被删除后引用仍然保留
+const ticketKnowledgeLinkJoins = `
+		FROM ticket_knowledge_links l
+		JOIN tickets t ON t.id = l.ticket_id
+		LEFT JOIN knowledge_articles k ON k.id::text = l.knowledge_id`
+
+// scanTicketKnowledgeLink 扫描 ticketKnowledgeLinkColumns 对应的一行引用
+func scanTicketKnowledgeLink(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.TicketKnowledgeLink, error) {
+	var link models.TicketKnowledgeLink
+	err := scanner.Scan(
+		&link.ID, &link.TicketID, &link.KnowledgeID, &link.LinkedBy, &link.ResolverID, &link.PromptedAt,
+		&link.Useful, &link.Comment, &link.RatedAt, &link.CreatedAt, &link.TicketNumber, &link.KnowledgeTitle,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("扫描工单引用文章失败: %w", err)
+	}
+	return &link, nil
+}
+
+// queryLinks 执行引用查询并扫描全部行
+func (r *knowledgeFeedbackRepository) queryLinks(ctx context.Context, query string, args ...interface{}) ([]*models.TicketKnowledgeLink, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询工单引用文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*models.TicketKnowledgeLink
+	for rows.Next() {
+		link, err := scanTicketKnowledgeLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询工单引用文章失败: %w", err)
+	}
+	return links, nil
+}
+
+// Link 为工单关联文章，已关联时返回 ErrTicketKnowledgeLinkExists
+func (r *knowledgeFeedbackRepository) Link(ctx context.Context, link *models.TicketKnowledgeLink) error {
+	query := `
+		INSERT INTO ticket_knowledge_links (ticket_id, knowledge_id, linked_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (ticket_id, knowledge_id) DO NOTHING
+		RETURNING id, created_at`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query, link.TicketID, link.KnowledgeID, link.LinkedBy).
+		Scan(&link.ID, &link.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrTicketKnowledgeLinkExists
+	}
+	if err != nil {
+		return fmt.Errorf("关联知识库文章失败: %w", err)
+	}
+	return nil
+}
+
+// Unlink 取消工单对文章的引用，已评价的引用一并删除并重算文章质量分
+func (r *knowledgeFeedbackRepository) Unlink(ctx context.Context, ticketID, knowledgeID string) error {
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM ticket_knowledge_links WHERE ticket_id = $1 AND knowledge_id = $2`, ticketID, knowledgeID)
+		if err != nil {
+			return fmt.Errorf("取消引用知识库文章失败: %w", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return models.ErrTicketKnowledgeLinkNotFound
+		}
+		_, err = r.refreshQuality(ctx, tx, knowledgeID)
+		return err
+	})
+}
+
+// Get 获取工单对文章的引用
+func (r *knowledgeFeedbackRepository) Get(ctx context.Context, ticketID, knowledgeID string) (*models.TicketKnowledgeLink, error) {
+	query := `SELECT ` + ticketKnowledgeLinkColumns + ticketKnowledgeLinkJoins + `
+		WHERE l.ticket_id = $1 AND l.knowledge_id = $2`
+
+	link, err := scanTicketKnowledgeLink(r.getExecutor().QueryRowxContext(ctx, query, ticketID, knowledgeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrTicketKnowledgeLinkNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListByTicket 获取工单引用的全部文章，按引用时间升序
+func (r *knowledgeFeedbackRepository) ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketKnowledgeLink, error) {
+	query := `SELECT ` + ticketKnowledgeLinkColumns + ticketKnowledgeLinkJoins + `
+		WHERE l.ticket_id = $1
+		ORDER BY l.created_at`
+	return r.queryLinks(ctx, query, ticketID)
+}
+
+// ListPending 获取提示用户评价但尚未评价的引用，按提示时间倒序
+func (r *knowledgeFeedbackRepository) ListPending(ctx context.Context, userID string, limit int) ([]*models.TicketKnowledgeLink, error) {
+	query := `SELECT ` + ticketKnowledgeLinkColumns + ticketKnowledgeLinkJoins + `
+		WHERE l.resolver_id = $1 AND l.prompted_at IS NOT NULL AND l.rated_at IS NULL
+		ORDER BY l.prompted_at DESC
+		LIMIT $2`
+	return r.queryLinks(ctx, query, userID, limit)
+}
+
+// MarkPrompted 将工单尚未提示过的引用标记为已提示解决人评价，返回本次标记的引用。
+// 只标记一次，工单重新打开后再次解决不重复提示
+func (r *knowledgeFeedbackRepository) MarkPrompted(ctx context.Context, ticketID, resolverID string) ([]*models.TicketKnowledgeLink, error) {
+	var ids []string
+	query := `
+		UPDATE ticket_knowledge_links
+		SET resolver_id = $2, prompted_at = NOW()
+		WHERE ticket_id = $1 AND prompted_at IS NULL
+		RETURNING id`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &ids, query, ticketID, resolverID); err != nil {
+		return nil, fmt.Errorf("标记引用文章待评价失败: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return r.queryLinks(ctx, `SELECT `+ticketKnowledgeLinkColumns+ticketKnowledgeLinkJoins+`
+		WHERE l.id = ANY($1::uuid[])
+		ORDER BY l.created_at`, pq.StringArray(ids))
+}
+
+// RecordFeedback 保存评价并重算文章的质量分，返回评价前后的文章质量
+func (r *knowledgeFeedbackRepository) RecordFeedback(ctx context.Context, linkID string, useful bool, comment string) (*models.KnowledgeQuality, *models.KnowledgeQuality, error) {
+	var before, after *models.KnowledgeQuality
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		var knowledgeID string
+		query := `
+			UPDATE ticket_knowledge_links
+			SET useful = $2, comment = $3, rated_at = NOW()
+			WHERE id = $1
+			RETURNING knowledge_id`
+		if err := tx.QueryRowxContext(ctx, query, linkID, useful, comment).Scan(&knowledgeID); err != nil {
+			if err == sql.ErrNoRows {
+				return models.ErrTicketKnowledgeLinkNotFound
+			}
+			return fmt.Errorf("保存文章有用性评价失败: %w", err)
+		}
+
+		// 锁定文章行，并发评价同一文章时依次重算
+		var quality models.KnowledgeQuality
+		err := sqlx.GetContext(ctx, tx, &quality, `
+			SELECT id, title, author_id, useful_count, not_useful_count, quality_score
+			FROM knowledge_articles WHERE id::text = $1 FOR UPDATE`, knowledgeID)
+		if err == sql.ErrNoRows {
+			return models.ErrKnowledgeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("获取文章质量失败: %w", err)
+		}
+		before = &quality
+
+		after, err = r.refreshQuality(ctx, tx, knowledgeID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// refreshQuality 按全部评价重算文章的有用数、无用数和质量分，文章不存在时返回 nil
+func (r *knowledgeFeedbackRepository) refreshQuality(ctx context.Context, tx *sqlx.Tx, knowledgeID string) (*models.KnowledgeQuality, error) {
+	var quality models.KnowledgeQuality
+	err := sqlx.GetContext(ctx, tx, &quality, `
+		WITH counts AS (
+			SELECT COUNT(*) FILTER (WHERE useful) AS useful_count,
+			       COUNT(*) FILTER (WHERE NOT useful) AS not_useful_count
+			FROM ticket_knowledge_links
+			WHERE knowledge_id = $1 AND rated_at IS NOT NULL
+		)
+		UPDATE knowledge_articles k
+		SET useful_count = c.useful_count,
+		    not_useful_count = c.not_useful_count,
+		    quality_score = (c.useful_count + 1)::double precision / (c.useful_count + c.not_useful_count + 2)
+		FROM counts c
+		WHERE k.id::text = $1
+		RETURNING k.id, k.title, k.author_id, k.useful_count, k.not_useful_count, k.quality_score`, knowledgeID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("更新文章质量分失败: %w", err)
+	}
+	return &quality, nil
+}
+
+// ListQuality 获取有评价的文章的质量，按质量分升序，FlaggedOnly 时只返回需要更新的文章，ViewerID 非空时按访问控制过滤
+func (r *knowledgeFeedbackRepository) ListQuality(ctx context.Context, filter *models.KnowledgeQualityFilter) ([]*models.KnowledgeQuality, error) {
+	query := `
+		SELECT id, title, author_id, useful_count, not_useful_count, quality_score
+		FROM knowledge_articles
+		WHERE deleted_at IS NULL AND useful_count + not_useful_count > 0`
+	args := []interface{}{}
+	if filter.FlaggedOnly {
+		query += ` AND useful_count + not_useful_count >= $1 AND quality_score < $2`
+		args = append(args, filter.Policy.MinFeedback, filter.Policy.Threshold)
+	}
+	if filter.ViewerID != nil {
+		args = append(args, *filter.ViewerID)
+		query += " AND " + knowledgeACLCondition(fmt.Sprintf("$%d", len(args)), false)
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY quality_score ASC, not_useful_count DESC LIMIT $%d`, len(args))
+
+	var qualities []*models.KnowledgeQuality
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &qualities, query, args...); err != nil {
+		return nil, fmt.Errorf("查询文章质量失败: %w", err)
+	}
+	return qualities, nil
+}
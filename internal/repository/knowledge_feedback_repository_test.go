@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var ticketKnowledgeLinkMockColumns = []string{
+	"id", "ticket_id", "knowledge_id", "linked_by", "resolver_id", "prompted_at",
+	"useful", "comment", "rated_at", "created_at", "number", "title",
+}
+
+var knowledgeQualityMockColumns = []string{"id", "title", "author_id", "useful_count", "not_useful_count", "quality_score"}
+
+func TestKnowledgeFeedbackRepository_MarkPrompted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewKnowledgeFeedbackRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// 只标记尚未提示过的引用
+	mock.ExpectQuery(`UPDATE ticket_knowledge_links\s+SET resolver_id = \$2, prompted_at = NOW\(\)\s+WHERE ticket_id = \$1 AND prompted_at IS NULL`).
+		WithArgs("ticket-1", "user-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("link-1"))
+	mock.ExpectQuery(`FROM ticket_knowledge_links l .+ WHERE l.id = ANY\(\$1::uuid\[\]\)`).
+		WillReturnRows(sqlmock.NewRows(ticketKnowledgeLinkMockColumns).
+			AddRow("link-1", "ticket-1", "kb-1", "user-1", "user-2", now, nil, "", nil, now, "T-1", "重启网关"))
+
+	links, err := repo.MarkPrompted(ctx, "ticket-1", "user-2")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "重启网关", links[0].KnowledgeTitle)
+	assert.Equal(t, "user-2", *links[0].ResolverID)
+	assert.Nil(t, links[0].Useful)
+
+	// 重复解决时没有需要提示的引用
+	mock.ExpectQuery(`UPDATE ticket_knowledge_links`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	links, err = repo.MarkPrompted(ctx, "ticket-1", "user-2")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeFeedbackRepository_RecordFeedback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewKnowledgeFeedbackRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ticket_knowledge_links\s+SET useful = \$2, comment = \$3, rated_at = NOW\(\)`).
+		WithArgs("link-1", false, "步骤过时").
+		WillReturnRows(sqlmock.NewRows([]string{"knowledge_id"}).AddRow("kb-1"))
+	mock.ExpectQuery(`FROM knowledge_articles WHERE id::text = \$1 FOR UPDATE`).
+		WithArgs("kb-1").
+		WillReturnRows(sqlmock.NewRows(knowledgeQualityMockColumns).AddRow("kb-1", "重启网关", "author-1", 1, 1, 0.5))
+	mock.ExpectQuery(`UPDATE knowledge_articles k\s+SET useful_count = c.useful_count`).
+		WithArgs("kb-1").
+		WillReturnRows(sqlmock.NewRows(knowledgeQualityMockColumns).AddRow("kb-1", "重启网关", "author-1", 1, 2, 0.4))
+	mock.ExpectCommit()
+
+	before, after, err := repo.RecordFeedback(context.Background(), "link-1", false, "步骤过时")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), before.NotUsefulCount)
+	assert.Equal(t, int64(2), after.NotUsefulCount)
+	assert.Equal(t, "author-1", after.AuthorID)
+	assert.InDelta(t, models.KnowledgeQualityScore(1, 2), after.Score, 0.001)
+
+	// 引用不存在时回滚
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ticket_knowledge_links`).WillReturnRows(sqlmock.NewRows([]string{"knowledge_id"}))
+	mock.ExpectRollback()
+
+	_, _, err = repo.RecordFeedback(context.Background(), "missing", true, "")
+	assert.ErrorIs(t, err, models.ErrTicketKnowledgeLinkNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeFeedbackRepository_ListQualityFlaggedOnly(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewKnowledgeFeedbackRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`useful_count \+ not_useful_count >= \$1 AND quality_score < \$2 ORDER BY quality_score ASC, not_useful_count DESC LIMIT \$3`).
+		WithArgs(int64(3), 0.4, 20).
+		WillReturnRows(sqlmock.NewRows(knowledgeQualityMockColumns).AddRow("kb-1", "重启网关", "author-1", 0, 4, 1.0/6))
+
+	qualities, err := repo.ListQuality(context.Background(), &models.KnowledgeQualityFilter{
+		FlaggedOnly: true,
+		Policy:      models.KnowledgeQualityPolicy{Threshold: 0.4, MinFeedback: 3},
+		Limit:       20,
+	})
+	require.NoError(t, err)
+	require.Len(t, qualities, 1)
+	assert.Equal(t, "kb-1", qualities[0].KnowledgeID)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeFeedbackRepository_ListQualityViewerScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewKnowledgeFeedbackRepository(sqlx.NewDb(db, "postgres"))
+	viewerID := "user-1"
+
+	// 只返回查看人有权阅读的文章
+	mock.ExpectQuery(`useful_count \+ not_useful_count > 0 AND \(knowledge_articles.author_id::text = \$1 OR .+ LIMIT \$2`).
+		WithArgs(viewerID, 20).
+		WillReturnRows(sqlmock.NewRows(knowledgeQualityMockColumns).AddRow("kb-1", "重启网关", "author-1", 1, 1, 0.5))
+
+	qualities, err := repo.ListQuality(context.Background(), &models.KnowledgeQualityFilter{Limit: 20, ViewerID: &viewerID})
+	require.NoError(t, err)
+	require.Len(t, qualities, 1)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -71,19 +71,22 @@ func (r *knowledgeRepository) Create(ctx context.Context, article *models.Knowle
 		INSERT INTO knowledge_articles (
 			id, title, content, summary, category_id, status, type, language,
 			author_id, reviewer_id, tags, metadata, version, view_count, like_count,
-			is_featured, visibility, created_at, updated_at
+			is_featured, visibility, created_at, updated_at, slug
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 		)`
 
 	_, err = r.getExecutor().ExecContext(ctx, query,
 		article.ID, article.Title, article.Content, article.Summary, article.CategoryID,
 		article.Status, article.Type, article.Language, article.AuthorID, article.ReviewerID,
 		string(tagsJSON), string(metadataJSON), article.Version, article.ViewCount, article.LikeCount,
-		article.IsFeatured, article.Visibility, article.CreatedAt, article.UpdatedAt,
+		article.IsFeatured, article.Visibility, article.CreatedAt, article.UpdatedAt, article.Slug,
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrKnowledgeExists
+		}
 		return fmt.Errorf("创建知识库文章失败: %w", err)
 	}
 
@@ -98,14 +101,14 @@ func (r *knowledgeRepository) Unarchive(ctx context.Context, id string) error {
 	err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(&currentStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("知识库不存在")
+			return models.ErrKnowledgeNotFound
 		}
 		return fmt.Errorf("获取知识库状态失败: %w", err)
 	}
 	
 	// 只有归档状态的知识库才能取消归档
 	if currentStatus != models.KnowledgeStatusArchived {
-		return fmt.Errorf("只有归档状态的知识库才能取消归档")
+		return fmt.Errorf("%w: 只有归档状态的知识库才能取消归档", models.ErrKnowledgeInvalidStatus)
 	}
 	
 	// 更新状态为已发布
@@ -122,44 +125,32 @@ func (r *knowledgeRepository) Unarchive(ctx context.Context, id string) error {
 	return nil
 }
 
-// GetByID 根据ID获取知识库文章
-func (r *knowledgeRepository) GetByID(ctx context.Context, id string) (*models.KnowledgeArticle, error) {
-	var article models.KnowledgeArticle
-	var tagsJSON, metadataJSON string
+// knowledgeArticleColumns 单篇文章查询的列，JSON 列以字符串读取后反序列化
+const knowledgeArticleColumns = `id, title, COALESCE(slug, '') AS slug, content, summary, category_id,
+		       status, type, language, author_id, reviewer_id,
+		       tags AS tags_json, metadata AS metadata_json, version, view_count, like_count,
+		       is_featured, visibility, publish_at, published_at, reviewed_at, expires_at,
+		       created_at, updated_at`
 
-	query := `
-		SELECT id, title, content, summary, category_id, status, type, language,
-		       author_id, reviewer_id, tags, metadata, version, view_count, like_count,
-		       is_featured, visibility, created_at, updated_at, published_at, reviewed_at
-		FROM knowledge_articles
-		WHERE slug = $1 AND deleted_at IS NULL`
+// knowledgeRow knowledge_articles 表的行映射
+type knowledgeRow struct {
+	models.Knowledge
+	TagsJSON     sql.NullString `db:"tags_json"`
+	MetadataJSON sql.NullString `db:"metadata_json"`
+}
 
-	err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(
-		&article.ID, &article.Title, &article.Content, &article.Summary, &article.CategoryID,
-		&article.Status, &article.Type, &article.Language, &article.AuthorID, &article.ReviewerID,
-		&tagsJSON, &metadataJSON, &article.Version, &article.ViewCount, &article.LikeCount,
-		&article.IsFeatured, &article.Visibility, &article.CreatedAt, &article.UpdatedAt, &article.PublishedAt, &article.ReviewedAt,
-	)
+// toModel 反序列化 JSON 列并返回文章模型
+func (row *knowledgeRow) toModel() (*models.Knowledge, error) {
+	article := row.Knowledge
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("知识库文章不存在")
-		}
-		return nil, fmt.Errorf("获取知识库文章失败: %w", err)
-	}
-
-	// 反序列化标签
-	if tagsJSON != "" {
-		err = json.Unmarshal([]byte(tagsJSON), &article.Tags)
-		if err != nil {
+	if row.TagsJSON.Valid && row.TagsJSON.String != "" {
+		if err := json.Unmarshal([]byte(row.TagsJSON.String), &article.Tags); err != nil {
 			return nil, fmt.Errorf("反序列化标签失败: %w", err)
 		}
 	}
 
-	// 反序列化元数据
-	if metadataJSON != "" {
-		err = json.Unmarshal([]byte(metadataJSON), &article.Metadata)
-		if err != nil {
+	if row.MetadataJSON.Valid && row.MetadataJSON.String != "" {
+		if err := json.Unmarshal([]byte(row.MetadataJSON.String), &article.Metadata); err != nil {
 			return nil, fmt.Errorf("反序列化元数据失败: %w", err)
 		}
 	}
@@ -167,48 +158,36 @@ func (r *knowledgeRepository) GetByID(ctx context.Context, id string) (*models.K
 	return &article, nil
 }
 
-// GetBySlug 根据Slug获取知识库文章
-func (r *knowledgeRepository) GetBySlug(ctx context.Context, slug string) (*models.KnowledgeArticle, error) {
-	var article models.KnowledgeArticle
-	var tagsJSON, metadataJSON string
-
-	query := `
-		SELECT id, title, content, summary, category_id, status, type, language,
-		       author_id, reviewer_id, tags, metadata, version, view_count, like_count,
-		       is_featured, visibility, published_at, created_at, updated_at
-		FROM knowledge_articles 
-		WHERE slug = $1 AND deleted_at IS NULL`
+// Get 按ID或Slug获取未删除的知识库文章
+func (r *knowledgeRepository) Get(ctx context.Context, key models.KnowledgeLookupKey, value string) (*models.KnowledgeArticle, error) {
+	if !key.IsValid() {
+		return nil, fmt.Errorf("%w: 不支持的查找方式 %s", models.ErrInvalidInput, key)
+	}
 
-	err := r.getExecutor().QueryRowxContext(ctx, query, slug).Scan(
-		&article.ID, &article.Title, &article.Content, &article.Summary, &article.CategoryID,
-		&article.Status, &article.Type, &article.Language, &article.AuthorID, &article.ReviewerID,
-		&tagsJSON, &metadataJSON, &article.Version, &article.ViewCount, &article.LikeCount,
-		&article.IsFeatured, &article.Visibility, &article.PublishedAt, &article.CreatedAt, &article.UpdatedAt,
-	)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM knowledge_articles
+		WHERE %s = $1 AND deleted_at IS NULL`, knowledgeArticleColumns, key)
 
-	if err != nil {
+	var row knowledgeRow
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &row, query, value); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("知识库文章不存在")
+			return nil, models.ErrKnowledgeNotFound
 		}
 		return nil, fmt.Errorf("获取知识库文章失败: %w", err)
 	}
 
-	// 反序列化标签和元数据
-	if tagsJSON != "" {
-		err = json.Unmarshal([]byte(tagsJSON), &article.Tags)
-		if err != nil {
-			return nil, fmt.Errorf("反序列化标签失败: %w", err)
-		}
-	}
+	return row.toModel()
+}
 
-	if metadataJSON != "" {
-		err = json.Unmarshal([]byte(metadataJSON), &article.Metadata)
-		if err != nil {
-			return nil, fmt.Errorf("反序列化元数据失败: %w", err)
-		}
-	}
+// GetByID 根据ID获取知识库文章
+func (r *knowledgeRepository) GetByID(ctx context.Context, id string) (*models.KnowledgeArticle, error) {
+	return r.Get(ctx, models.KnowledgeLookupByID, id)
+}
 
-	return &article, nil
+// GetBySlug 根据Slug获取知识库文章
+func (r *knowledgeRepository) GetBySlug(ctx context.Context, slug string) (*models.KnowledgeArticle, error) {
+	return r.Get(ctx, models.KnowledgeLookupBySlug, slug)
 }
 
 // Update 更新知识库文章
@@ -286,7 +265,7 @@ func (r *knowledgeRepository) Update(ctx context.Context, article *models.Knowle
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("知识库文章不存在")
+		return models.ErrKnowledgeNotFound
 	}
 
 	return nil
@@ -384,6 +363,12 @@ func (r *knowledgeRepository) List(ctx context.Context, filter *models.Knowledge
 			}
 			conditions = append(conditions, "("+strings.Join(tagConditions, " OR ")+")")
 		}
+
+		if filter.ViewerID != nil {
+			conditions = append(conditions, knowledgeACLCondition(fmt.Sprintf("$%d", argIndex), false))
+			args = append(args, *filter.ViewerID)
+			argIndex++
+		}
 	}
 
 	whereClause := ""
@@ -509,6 +494,12 @@ func (r *knowledgeRepository) Count(ctx context.Context, filter *models.Knowledg
 			args = append(args, *filter.Visibility)
 			argIndex++
 		}
+
+		if filter.ViewerID != nil {
+			conditions = append(conditions, knowledgeACLCondition(fmt.Sprintf("$%d", argIndex), false))
+			args = append(args, *filter.ViewerID)
+			argIndex++
+		}
 	}
 
 	whereClause := ""
@@ -537,15 +528,80 @@ func (r *knowledgeRepository) Exists(ctx context.Context, id string) (bool, erro
 	return exists, nil
 }
 
-// ExistsBySlug 检查Slug是否存在
+// ExistsBySlug 检查Slug是否存在，文章改名前使用过的旧Slug也视为已占用
 func (r *knowledgeRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM knowledge_articles WHERE slug = $1 AND deleted_at IS NULL`
-	err := sqlx.GetContext(ctx, r.db, &count, query, slug)
+	var exists bool
+	query := `
+		SELECT EXISTS(SELECT 1 FROM knowledge_articles WHERE slug = $1 AND deleted_at IS NULL)
+		    OR EXISTS(SELECT 1 FROM knowledge_slug_history WHERE slug = $1)`
+	err := sqlx.GetContext(ctx, r.getExecutor(), &exists, query, slug)
 	if err != nil {
 		return false, fmt.Errorf("检查Slug是否存在失败: %w", err)
 	}
-	return count > 0, nil
+	return exists, nil
+}
+
+// UpdateSlug 修改文章Slug，原Slug记入历史用于重定向
+// 新Slug是本文章以前用过的旧Slug时从历史中移除
+func (r *knowledgeRepository) UpdateSlug(ctx context.Context, id, slug string) error {
+	query := `
+		WITH old AS (
+			SELECT slug FROM knowledge_articles WHERE id = $1 AND deleted_at IS NULL
+		), updated AS (
+			UPDATE knowledge_articles SET slug = $2, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL AND slug IS DISTINCT FROM $2
+		), reclaimed AS (
+			DELETE FROM knowledge_slug_history WHERE slug = $2 AND knowledge_id = $1
+		)
+		INSERT INTO knowledge_slug_history (slug, knowledge_id, created_at)
+		SELECT old.slug, $1, NOW() FROM old
+		WHERE old.slug IS NOT NULL AND old.slug <> '' AND old.slug <> $2
+		ON CONFLICT (slug) DO UPDATE SET knowledge_id = EXCLUDED.knowledge_id, created_at = EXCLUDED.created_at`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, id, slug); err != nil {
+		return fmt.Errorf("更新Slug失败: %w", err)
+	}
+	return nil
+}
+
+// GetSlugOwner 获取当前或曾经使用该Slug的文章ID，未被使用时返回空字符串
+func (r *knowledgeRepository) GetSlugOwner(ctx context.Context, slug string) (string, error) {
+	query := `
+		SELECT id::text FROM knowledge_articles WHERE slug = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT knowledge_id::text FROM knowledge_slug_history WHERE slug = $1
+		LIMIT 1`
+
+	var owner string
+	err := sqlx.GetContext(ctx, r.getExecutor(), &owner, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("获取Slug所属文章失败: %w", err)
+	}
+	return owner, nil
+}
+
+// ResolveSlugRedirect 根据旧Slug查找文章的当前Slug
+func (r *knowledgeRepository) ResolveSlugRedirect(ctx context.Context, slug string) (*models.KnowledgeSlugLookup, error) {
+	query := `
+		SELECT a.id::text AS knowledge_id, a.slug
+		FROM knowledge_slug_history h
+		JOIN knowledge_articles a ON a.id = h.knowledge_id
+		WHERE h.slug = $1 AND a.deleted_at IS NULL`
+
+	var lookup models.KnowledgeSlugLookup
+	err := sqlx.GetContext(ctx, r.getExecutor(), &lookup, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeNotFound
+		}
+		return nil, fmt.Errorf("查找Slug重定向失败: %w", err)
+	}
+
+	lookup.Moved = true
+	return &lookup, nil
 }
 
 // UpdateStatus 更新文章状态
@@ -593,7 +649,7 @@ func (r *knowledgeRepository) Approve(ctx context.Context, id, reviewerID string
 			updated_at = $4
 		WHERE id = $5 AND deleted_at IS NULL AND status = $6`
 	
-	result, err := r.db.ExecContext(ctx, query, 
+	result, err := r.getExecutor().ExecContext(ctx, query, 
 		models.KnowledgeStatusPublished, reviewerID, comment, now, id, models.KnowledgeStatusReview)
 	if err != nil {
 		return fmt.Errorf("审批知识库文章失败: %w", err)
@@ -605,7 +661,7 @@ func (r *knowledgeRepository) Approve(ctx context.Context, id, reviewerID string
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("文章不存在或状态不正确")
+		return fmt.Errorf("%w: 文章不存在或状态不正确", models.ErrKnowledgeInvalidStatus)
 	}
 	
 	return nil
@@ -624,7 +680,7 @@ func (r *knowledgeRepository) Reject(ctx context.Context, id, reviewerID string,
 			updated_at = $4
 		WHERE id = $5 AND deleted_at IS NULL AND status = $6`
 	
-	result, err := r.db.ExecContext(ctx, query, 
+	result, err := r.getExecutor().ExecContext(ctx, query, 
 		models.KnowledgeStatusDraft, reviewerID, comment, now, id, models.KnowledgeStatusReview)
 	if err != nil {
 		return fmt.Errorf("拒绝知识库文章失败: %w", err)
@@ -636,7 +692,7 @@ func (r *knowledgeRepository) Reject(ctx context.Context, id, reviewerID string,
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("文章不存在或状态不正确")
+		return fmt.Errorf("%w: 文章不存在或状态不正确", models.ErrKnowledgeInvalidStatus)
 	}
 	
 	return nil
@@ -852,7 +908,7 @@ func (r *knowledgeRepository) GetVersion(ctx context.Context, knowledgeID, versi
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("文章版本不存在")
+			return nil, models.ErrVersionNotFound
 		}
 		return nil, fmt.Errorf("获取文章版本失败: %w", err)
 	}
@@ -1894,6 +1950,9 @@ func (r *knowledgeRepository) GetCategory(ctx context.Context, id string) (*mode
 		&category.Color, &category.IsActive, &category.CreatedAt, &category.UpdatedAt,
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeCategoryNotFound
+		}
 		return nil, fmt.Errorf("获取知识分类失败: %w", err)
 	}
 	
@@ -2064,6 +2123,13 @@ func (r *knowledgeRepository) Search(ctx context.Context, query string, filter *
 			args = append(args, *filter.AuthorID)
 			argIndex++
 		}
+
+		// 受限文章不出现在搜索结果中
+		if filter.ViewerID != nil {
+			conditions = append(conditions, knowledgeACLCondition("$"+fmt.Sprintf("%d", argIndex), false))
+			args = append(args, *filter.ViewerID)
+			argIndex++
+		}
 	}
 	
 	// 构建查询
@@ -2074,8 +2140,8 @@ func (r *knowledgeRepository) Search(ctx context.Context, query string, filter *
 		FROM knowledge_articles
 		WHERE ` + strings.Join(conditions, " AND ")
 	
-	// 排序
-	orderBy := "ORDER BY created_at DESC"
+	// 排序，默认按质量分提升工单解决中被评价为有用的文章，质量分相同时按创建时间
+	orderBy := "ORDER BY quality_score DESC, created_at DESC"
 	if filter != nil && filter.SortBy != nil {
 		switch *filter.SortBy {
 		case "title":
@@ -2165,14 +2231,14 @@ func (r *knowledgeRepository) SubmitForReview(ctx context.Context, id string) er
 	err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(&currentStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("知识库不存在")
+			return models.ErrKnowledgeNotFound
 		}
 		return fmt.Errorf("获取知识库状态失败: %w", err)
 	}
 	
 	// 只有草稿状态的知识库才能提交审核
 	if currentStatus != models.KnowledgeStatusDraft {
-		return fmt.Errorf("只有草稿状态的知识库才能提交审核")
+		return fmt.Errorf("%w: 只有草稿状态的知识库才能提交审核", models.ErrKnowledgeInvalidStatus)
 	}
 	
 	// 更新状态为审核中
@@ -2181,10 +2247,230 @@ func (r *knowledgeRepository) SubmitForReview(ctx context.Context, id string) er
 		SET status = $1, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $2 AND deleted_at IS NULL`
 	
-	_, err = r.db.ExecContext(ctx, updateQuery, models.KnowledgeStatusReview, id)
+	_, err = r.getExecutor().ExecContext(ctx, updateQuery, models.KnowledgeStatusReview, id)
 	if err != nil {
 		return fmt.Errorf("提交审核失败: %w", err)
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+// SchedulePublish 设置定时发布，仅草稿或已定时的文章可设置
+func (r *knowledgeRepository) SchedulePublish(ctx context.Context, id string, publishAt time.Time) error {
+	query := `
+		UPDATE knowledge_articles SET
+			status = $1,
+			publish_at = $2,
+			updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL AND status IN ($5, $1)`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		models.KnowledgeStatusScheduled, publishAt, time.Now(), id, models.KnowledgeStatusDraft)
+	if err != nil {
+		return fmt.Errorf("设置定时发布失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeInvalidStatus
+	}
+
+	return nil
+}
+
+// CancelSchedule 取消定时发布，文章恢复为草稿
+func (r *knowledgeRepository) CancelSchedule(ctx context.Context, id string) error {
+	query := `
+		UPDATE knowledge_articles SET
+			status = $1,
+			publish_at = NULL,
+			updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL AND status = $4`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		models.KnowledgeStatusDraft, time.Now(), id, models.KnowledgeStatusScheduled)
+	if err != nil {
+		return fmt.Errorf("取消定时发布失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeInvalidStatus
+	}
+
+	return nil
+}
+
+// PublishDue 发布到期的定时文章，返回已发布的文章ID
+func (r *knowledgeRepository) PublishDue(ctx context.Context, now time.Time) ([]string, error) {
+	query := `
+		UPDATE knowledge_articles SET
+			status = $1,
+			published_at = publish_at,
+			publish_at = NULL,
+			updated_at = $2
+		WHERE status = $3 AND publish_at <= $2 AND deleted_at IS NULL
+		RETURNING id`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query,
+		models.KnowledgeStatusPublished, now, models.KnowledgeStatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("发布定时文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("扫描文章ID失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历文章ID失败: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateExpiry 更新过期时间，并重置过期提醒以便新的过期时间再次提醒
+func (r *knowledgeRepository) UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error {
+	query := `
+		UPDATE knowledge_articles SET
+			expires_at = $1,
+			expiry_warned_at = NULL,
+			updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, expiresAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("更新过期时间失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeNotFound
+	}
+
+	return nil
+}
+
+// ListExpiring 获取在指定时间前过期且尚未提醒的已发布文章
+func (r *knowledgeRepository) ListExpiring(ctx context.Context, before time.Time, limit int) ([]*models.Knowledge, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `
+		SELECT id, title, author_id, status, expires_at
+		FROM knowledge_articles
+		WHERE status = $1 AND deleted_at IS NULL
+		  AND expires_at IS NOT NULL AND expires_at > CURRENT_TIMESTAMP AND expires_at <= $2
+		  AND expiry_warned_at IS NULL
+		ORDER BY expires_at
+		LIMIT $3`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, models.KnowledgeStatusPublished, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取即将过期文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]*models.Knowledge, 0)
+	for rows.Next() {
+		var article models.Knowledge
+		if err := rows.Scan(&article.ID, &article.Title, &article.AuthorID, &article.Status, &article.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("扫描文章数据失败: %w", err)
+		}
+		articles = append(articles, &article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历文章数据失败: %w", err)
+	}
+
+	return articles, nil
+}
+
+// MarkExpiryWarned 记录过期提醒已发送
+func (r *knowledgeRepository) MarkExpiryWarned(ctx context.Context, id string, warnedAt time.Time) error {
+	query := `UPDATE knowledge_articles SET expiry_warned_at = $1 WHERE id = $2`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, warnedAt, id); err != nil {
+		return fmt.Errorf("记录过期提醒失败: %w", err)
+	}
+
+	return nil
+}
+
+// FindSimilar 按标题和内容的三元组相似度查找可能重复的文章
+// 仅比较内容前 2000 个字符，与相似度索引保持一致
+func (r *knowledgeRepository) FindSimilar(ctx context.Context, req *models.KnowledgeDuplicateCheckRequest) ([]*models.KnowledgeDuplicateCandidate, error) {
+	titleWeight, contentWeight := models.KnowledgeDuplicateTitleWeight, models.KnowledgeDuplicateContentWeight
+	switch {
+	case strings.TrimSpace(req.Title) == "":
+		titleWeight, contentWeight = 0, 1
+	case strings.TrimSpace(req.Content) == "":
+		titleWeight, contentWeight = 1, 0
+	}
+
+	threshold := models.DefaultKnowledgeDuplicateThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = models.DefaultKnowledgeDuplicateLimit
+	}
+
+	excludeID := ""
+	if req.ExcludeID != nil {
+		excludeID = *req.ExcludeID
+	}
+
+	args := []interface{}{req.Title, req.Content, titleWeight, contentWeight, excludeID, threshold, limit}
+	aclCondition := ""
+	if req.ViewerID != nil {
+		aclCondition = "AND " + knowledgeACLCondition("$8", false)
+		args = append(args, *req.ViewerID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, title, status, title_similarity, content_similarity,
+		       ($3 * title_similarity + $4 * content_similarity) AS similarity
+		FROM (
+			SELECT id, title, status,
+			       similarity(title, $1) AS title_similarity,
+			       similarity(LEFT(content, 2000), LEFT($2, 2000)) AS content_similarity
+			FROM knowledge_articles
+			WHERE deleted_at IS NULL
+			  AND ($5 = '' OR id::text <> $5)
+			  AND (title %% $1 OR LEFT(content, 2000) %% LEFT($2, 2000))
+			  %s
+		) candidates
+		WHERE ($3 * title_similarity + $4 * content_similarity) >= $6
+		ORDER BY similarity DESC
+		LIMIT $7`, aclCondition)
+
+	candidates := make([]*models.KnowledgeDuplicateCandidate, 0)
+	err := sqlx.SelectContext(ctx, r.getExecutor(), &candidates, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查找相似文章失败: %w", err)
+	}
+
+	return candidates, nil
+}
@@ -39,9 +39,10 @@ func TestKnowledgeRepository_Create(t *testing.T) {
 		Tags:       []string{"tag1", "tag2"},
 		Keywords:   []string{"keyword1", "keyword2"},
 		Metadata:   map[string]interface{}{"key": "value"},
+		Slug:       "ce-shi-zhi-shi",
 	}
 
-	// Mock INSERT query - 匹配实际Create方法的19个字段
+	// Mock INSERT query - 匹配实际Create方法的20个字段
 	mock.ExpectExec(`INSERT INTO knowledge_articles`).WithArgs(
 		knowledge.ID, knowledge.Title, knowledge.Content, knowledge.Summary,
 		knowledge.CategoryID, knowledge.Status, knowledge.Type, knowledge.Language,
@@ -50,6 +51,7 @@ func TestKnowledgeRepository_Create(t *testing.T) {
 		"1", knowledge.ViewCount, knowledge.LikeCount, // version is set to "1" by Create method
 		knowledge.IsFeatured, knowledge.Visibility,
 		sqlmock.AnyArg(), sqlmock.AnyArg(), // created_at, updated_at
+		knowledge.Slug,
 	).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.Create(context.Background(), knowledge)
@@ -57,6 +59,17 @@ func TestKnowledgeRepository_Create(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// knowledgeArticleRows 构造单篇文章查询返回的行，列名与 knowledgeArticleColumns 一致
+func knowledgeArticleRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "title", "slug", "content", "summary", "category_id",
+		"status", "type", "language", "author_id", "reviewer_id",
+		"tags_json", "metadata_json", "version", "view_count", "like_count",
+		"is_featured", "visibility", "publish_at", "published_at", "reviewed_at", "expires_at",
+		"created_at", "updated_at",
+	})
+}
+
 func TestKnowledgeRepository_GetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -71,30 +84,87 @@ func TestKnowledgeRepository_GetByID(t *testing.T) {
 
 	tagsJSON, _ := json.Marshal(tags)
 	metadataJSON, _ := json.Marshal(metadata)
-
-	// 匹配实际GetByID方法的查询字段
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "content", "summary", "category_id", "status", "type", "language",
-		"author_id", "reviewer_id", "tags", "metadata", "version", "view_count", "like_count",
-		"is_featured", "visibility", "created_at", "updated_at", "published_at", "reviewed_at",
-	}).AddRow(
-		knowledgeID, "测试知识", "测试内容", "测试摘要", "category-1", models.KnowledgeStatusDraft,
-		models.KnowledgeTypeArticle, "zh-CN", "author-1", nil, string(tagsJSON), string(metadataJSON),
-		"1.0", 100, 10, true, models.KnowledgeVisibilityPublic, time.Now(), time.Now(), nil, nil,
+	publishedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	rows := knowledgeArticleRows().AddRow(
+		knowledgeID, "测试知识", "ce-shi-zhi-shi", "测试内容", "测试摘要", "category-1",
+		models.KnowledgeStatusPublished, models.KnowledgeTypeArticle, "zh-CN", "author-1", nil,
+		string(tagsJSON), string(metadataJSON), "1.0", 100, 10,
+		true, models.KnowledgeVisibilityPublic, nil, publishedAt, nil, nil,
+		time.Now(), time.Now(),
 	)
 
-	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles WHERE .+ AND deleted_at IS NULL`).WithArgs(knowledgeID).WillReturnRows(rows)
+	// 必须按ID查询，而不是按slug
+	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles\s+WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(knowledgeID).WillReturnRows(rows)
 
 	knowledge, err := repo.GetByID(context.Background(), knowledgeID)
 	assert.NoError(t, err)
 	assert.NotNil(t, knowledge)
 	assert.Equal(t, knowledgeID, knowledge.ID)
 	assert.Equal(t, "测试知识", knowledge.Title)
+	assert.Equal(t, "ce-shi-zhi-shi", knowledge.Slug)
+	assert.Equal(t, "测试内容", knowledge.Content)
+	assert.Equal(t, "测试摘要", *knowledge.Summary)
+	assert.Equal(t, "author-1", knowledge.AuthorID)
+	assert.Nil(t, knowledge.ReviewerID)
+	assert.Equal(t, models.KnowledgeStatusPublished, knowledge.Status)
+	assert.Equal(t, int64(100), knowledge.ViewCount)
+	assert.Equal(t, publishedAt, *knowledge.PublishedAt)
+	assert.Nil(t, knowledge.ReviewedAt)
 	assert.Equal(t, tags, knowledge.Tags)
 	assert.Equal(t, metadata, knowledge.Metadata)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestKnowledgeRepository_GetBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	rows := knowledgeArticleRows().AddRow(
+		"knowledge-1", "磁盘告警", "ci-pan-gao-jing", "清理日志", nil, nil,
+		models.KnowledgeStatusDraft, models.KnowledgeTypeRunbook, "zh-CN", "author-1", "reviewer-1",
+		nil, nil, "1", 0, 0,
+		false, models.KnowledgeVisibilityInternal, nil, nil, nil, nil,
+		time.Now(), time.Now(),
+	)
+
+	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles\s+WHERE slug = \$1 AND deleted_at IS NULL`).WithArgs("ci-pan-gao-jing").WillReturnRows(rows)
+
+	knowledge, err := repo.GetBySlug(context.Background(), "ci-pan-gao-jing")
+	require.NoError(t, err)
+	assert.Equal(t, "knowledge-1", knowledge.ID)
+	assert.Equal(t, "ci-pan-gao-jing", knowledge.Slug)
+	assert.Equal(t, "reviewer-1", *knowledge.ReviewerID)
+	assert.Nil(t, knowledge.Summary)
+	assert.Nil(t, knowledge.Tags)
+	assert.Equal(t, models.KnowledgeTypeRunbook, knowledge.Type)
+
+	// 未找到时返回统一的哨兵错误
+	mock.ExpectQuery(`WHERE slug = \$1`).WithArgs("missing").WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetBySlug(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrKnowledgeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_Get_InvalidKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	// 查找键会拼入SQL，非法值必须在查询前拒绝
+	_, err = repo.Get(context.Background(), models.KnowledgeLookupKey("title; DROP TABLE x"), "v")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestKnowledgeRepository_GetByID_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -768,7 +838,7 @@ func TestKnowledgeRepository_Search(t *testing.T) {
 		time.Now(), time.Now(), time.Now(),
 	)
 
-	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles WHERE deleted_at IS NULL AND \(title ILIKE \$1 OR content ILIKE \$1\) ORDER BY created_at DESC LIMIT \$2 OFFSET \$3`).WithArgs(
+	mock.ExpectQuery(`SELECT .+ FROM knowledge_articles WHERE deleted_at IS NULL AND \(title ILIKE \$1 OR content ILIKE \$1\) ORDER BY quality_score DESC, created_at DESC LIMIT \$2 OFFSET \$3`).WithArgs(
 		"%测试%", 10, 0,
 	).WillReturnRows(rows)
 
@@ -825,4 +895,110 @@ func TestKnowledgeRepository_ErrorHandling(t *testing.T) {
 	})
 
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+func TestKnowledgeRepository_SchedulePublish_InvalidStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	publishAt := time.Now().Add(time.Hour)
+	mock.ExpectExec(`UPDATE knowledge_articles SET`).WithArgs(
+		models.KnowledgeStatusScheduled, publishAt, sqlmock.AnyArg(), "knowledge-1", models.KnowledgeStatusDraft,
+	).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.SchedulePublish(context.Background(), "knowledge-1", publishAt)
+	assert.ErrorIs(t, err, models.ErrKnowledgeInvalidStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_PublishDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	now := time.Now()
+	mock.ExpectQuery(`UPDATE knowledge_articles SET (.+) RETURNING id`).WithArgs(
+		models.KnowledgeStatusPublished, now, models.KnowledgeStatusScheduled,
+	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("knowledge-1").AddRow("knowledge-2"))
+
+	ids, err := repo.PublishDue(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"knowledge-1", "knowledge-2"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_FindSimilar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	req := &models.KnowledgeDuplicateCheckRequest{Title: "数据库连接池耗尽排查"}
+	require.NoError(t, req.Validate())
+
+	// 只有标题时全部权重落在标题相似度上
+	rows := sqlmock.NewRows([]string{"id", "title", "status", "title_similarity", "content_similarity", "similarity"}).
+		AddRow("knowledge-1", "数据库连接池耗尽处理", models.KnowledgeStatusPublished, 0.72, 0.0, 0.72)
+	mock.ExpectQuery(`SELECT (.+) FROM \(`).WithArgs(
+		req.Title, "", 1.0, 0.0, "", models.DefaultKnowledgeDuplicateThreshold, models.DefaultKnowledgeDuplicateLimit,
+	).WillReturnRows(rows)
+
+	candidates, err := repo.FindSimilar(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "knowledge-1", candidates[0].ID)
+	assert.InDelta(t, 0.72, candidates[0].Similarity, 0.001)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_UpdateSlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	// 原slug写入历史表，新slug从历史表中移除
+	mock.ExpectExec(`WITH old AS .+UPDATE knowledge_articles SET slug = \$2.+DELETE FROM knowledge_slug_history.+INSERT INTO knowledge_slug_history`).
+		WithArgs("knowledge-1", "ci-pan-gao-jing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateSlug(context.Background(), "knowledge-1", "ci-pan-gao-jing")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeRepository_ResolveSlugRedirect(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT a.id::text AS knowledge_id, a.slug\s+FROM knowledge_slug_history h`).
+		WithArgs("old-slug").
+		WillReturnRows(sqlmock.NewRows([]string{"knowledge_id", "slug"}).AddRow("knowledge-1", "new-slug"))
+
+	lookup, err := repo.ResolveSlugRedirect(context.Background(), "old-slug")
+	require.NoError(t, err)
+	assert.True(t, lookup.Moved)
+	assert.Equal(t, "new-slug", lookup.Slug)
+
+	mock.ExpectQuery(`FROM knowledge_slug_history h`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.ResolveSlugRedirect(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrKnowledgeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
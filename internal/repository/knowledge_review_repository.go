@@ -0,0 +1,508 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// knowledgeReviewRepository 知识库审核仓储实现
+type knowledgeReviewRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewKnowledgeReviewRepository 创建知识库审核仓储实例
+func NewKnowledgeReviewRepository(db *sqlx.DB) KnowledgeReviewRepository {
+	return &knowledgeReviewRepository{
+		db: db,
+	}
+}
+
+// NewKnowledgeReviewRepositoryWithTx 创建带事务的知识库审核仓储实例
+func NewKnowledgeReviewRepositoryWithTx(tx *sqlx.Tx) KnowledgeReviewRepository {
+	return &knowledgeReviewRepository{
+		tx: tx,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *knowledgeReviewRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const knowledgeReviewPolicyColumns = `id, name, description, category_id, required_approvals, reviewer_ids,
+		       reviewer_roles, allow_self_review, enabled, created_by, created_at, updated_at`
+
+const knowledgeReviewSubmissionColumns = `r.id, r.knowledge_id, COALESCE(k.title, ''), r.policy_id, r.required_approvals,
+		       r.reviewer_ids, r.reviewer_roles, r.allow_self_review, r.status, r.submitted_by,
+		       r.submitted_at, r.completed_at,
+		       (SELECT COUNT(*) FROM knowledge_review_records d WHERE d.submission_id = r.id AND d.decision = 'approved')`
+
+// CreatePolicy 创建审核策略
+func (r *knowledgeReviewRepository) CreatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error {
+	if policy.ID == "" {
+		policy.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	query := `
+		INSERT INTO knowledge_review_policies (
+			id, name, description, category_id, required_approvals, reviewer_ids,
+			reviewer_roles, allow_self_review, enabled, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		policy.ID, policy.Name, policy.Description, policy.CategoryID, policy.RequiredApprovals,
+		pq.Array(policy.ReviewerIDs), pq.Array(policy.ReviewerRoles), policy.AllowSelfReview,
+		policy.Enabled, policy.CreatedBy, policy.CreatedAt, policy.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrKnowledgeReviewPolicyExists
+		}
+		return fmt.Errorf("创建审核策略失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetPolicy 根据ID获取审核策略
+func (r *knowledgeReviewRepository) GetPolicy(ctx context.Context, id string) (*models.KnowledgeReviewPolicy, error) {
+	query := `
+		SELECT ` + knowledgeReviewPolicyColumns + `
+		FROM knowledge_review_policies
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	policy, err := scanKnowledgeReviewPolicy(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeReviewPolicyNotFound
+		}
+		return nil, fmt.Errorf("获取审核策略失败: %w", err)
+	}
+
+	return policy, nil
+}
+
+// GetPolicyForCategory 获取分类生效的审核策略，分类未配置时返回默认策略
+func (r *knowledgeReviewRepository) GetPolicyForCategory(ctx context.Context, categoryID *string) (*models.KnowledgeReviewPolicy, error) {
+	query := `
+		SELECT ` + knowledgeReviewPolicyColumns + `
+		FROM knowledge_review_policies
+		WHERE enabled = true AND deleted_at IS NULL
+		  AND (category_id = $1 OR category_id IS NULL)
+		ORDER BY category_id IS NULL
+		LIMIT 1`
+
+	policy, err := scanKnowledgeReviewPolicy(r.getExecutor().QueryRowxContext(ctx, query, categoryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeReviewPolicyNotFound
+		}
+		return nil, fmt.Errorf("获取审核策略失败: %w", err)
+	}
+
+	return policy, nil
+}
+
+// UpdatePolicy 更新审核策略
+func (r *knowledgeReviewRepository) UpdatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE knowledge_review_policies SET
+			name = $1,
+			description = $2,
+			category_id = $3,
+			required_approvals = $4,
+			reviewer_ids = $5,
+			reviewer_roles = $6,
+			allow_self_review = $7,
+			enabled = $8,
+			updated_at = $9
+		WHERE id = $10 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		policy.Name, policy.Description, policy.CategoryID, policy.RequiredApprovals,
+		pq.Array(policy.ReviewerIDs), pq.Array(policy.ReviewerRoles), policy.AllowSelfReview,
+		policy.Enabled, policy.UpdatedAt, policy.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新审核策略失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeReviewPolicyNotFound
+	}
+
+	return nil
+}
+
+// DeletePolicy 软删除审核策略
+func (r *knowledgeReviewRepository) DeletePolicy(ctx context.Context, id string) error {
+	query := `
+		UPDATE knowledge_review_policies SET
+			deleted_at = $1,
+			updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除审核策略失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeReviewPolicyNotFound
+	}
+
+	return nil
+}
+
+// ListPolicies 获取审核策略列表
+func (r *knowledgeReviewRepository) ListPolicies(ctx context.Context) ([]*models.KnowledgeReviewPolicy, error) {
+	query := `
+		SELECT ` + knowledgeReviewPolicyColumns + `
+		FROM knowledge_review_policies
+		WHERE deleted_at IS NULL
+		ORDER BY category_id IS NULL DESC, created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取审核策略列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*models.KnowledgeReviewPolicy, 0)
+	for rows.Next() {
+		policy, err := scanKnowledgeReviewPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描审核策略数据失败: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历审核策略数据失败: %w", err)
+	}
+
+	return policies, nil
+}
+
+// ExistsPolicyForCategory 检查分类是否已配置审核策略，excludeID 用于更新时排除自身
+func (r *knowledgeReviewRepository) ExistsPolicyForCategory(ctx context.Context, categoryID *string, excludeID string) (bool, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM knowledge_review_policies
+		WHERE deleted_at IS NULL
+		  AND category_id IS NOT DISTINCT FROM $1
+		  AND id::text <> $2`
+	err := r.getExecutor().QueryRowxContext(ctx, query, categoryID, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("检查审核策略是否存在失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CreateSubmission 创建审核提交
+func (r *knowledgeReviewRepository) CreateSubmission(ctx context.Context, req *models.KnowledgeReviewSubmission) error {
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+	req.SubmittedAt = time.Now()
+
+	query := `
+		INSERT INTO knowledge_review_submissions (
+			id, knowledge_id, policy_id, required_approvals, reviewer_ids, reviewer_roles,
+			allow_self_review, status, submitted_by, submitted_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		req.ID, req.KnowledgeID, req.PolicyID, req.RequiredApprovals,
+		pq.Array(req.ReviewerIDs), pq.Array(req.ReviewerRoles), req.AllowSelfReview,
+		req.Status, req.SubmittedBy, req.SubmittedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建审核提交失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingSubmission 获取文章当前待审核提交，事务中会锁定该提交以串行化审核操作
+func (r *knowledgeReviewRepository) GetPendingSubmission(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error) {
+	query := `
+		SELECT ` + knowledgeReviewSubmissionColumns + `
+		FROM knowledge_review_submissions r
+		LEFT JOIN knowledge_articles k ON k.id = r.knowledge_id
+		WHERE r.knowledge_id = $1 AND r.status = $2
+		FOR UPDATE OF r`
+
+	req, err := scanKnowledgeReviewSubmission(r.getExecutor().QueryRowxContext(ctx, query, knowledgeID, models.KnowledgeReviewSubmissionPending))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeReviewNotFound
+		}
+		return nil, fmt.Errorf("获取审核提交失败: %w", err)
+	}
+
+	return req, nil
+}
+
+// GetLatestSubmission 获取文章最近一次审核提交
+func (r *knowledgeReviewRepository) GetLatestSubmission(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error) {
+	query := `
+		SELECT ` + knowledgeReviewSubmissionColumns + `
+		FROM knowledge_review_submissions r
+		LEFT JOIN knowledge_articles k ON k.id = r.knowledge_id
+		WHERE r.knowledge_id = $1
+		ORDER BY r.submitted_at DESC
+		LIMIT 1`
+
+	req, err := scanKnowledgeReviewSubmission(r.getExecutor().QueryRowxContext(ctx, query, knowledgeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrKnowledgeReviewNotFound
+		}
+		return nil, fmt.Errorf("获取审核提交失败: %w", err)
+	}
+
+	return req, nil
+}
+
+// CompleteSubmission 结束审核提交，仅更新待审核状态的请求
+func (r *knowledgeReviewRepository) CompleteSubmission(ctx context.Context, id string, status models.KnowledgeReviewSubmissionStatus) error {
+	query := `
+		UPDATE knowledge_review_submissions SET
+			status = $1,
+			completed_at = $2
+		WHERE id = $3 AND status = $4`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, status, time.Now(), id, models.KnowledgeReviewSubmissionPending)
+	if err != nil {
+		return fmt.Errorf("更新审核提交状态失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeReviewNotFound
+	}
+
+	return nil
+}
+
+// AddRecord 添加审核记录，同一审核人对同一请求只能审核一次
+func (r *knowledgeReviewRepository) AddRecord(ctx context.Context, record *models.KnowledgeReviewRecord) error {
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	record.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO knowledge_review_records (id, submission_id, reviewer_id, decision, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (submission_id, reviewer_id) DO NOTHING`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		record.ID, record.SubmissionID, record.ReviewerID, record.Decision, record.Comment, record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("添加审核记录失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取添加结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrKnowledgeReviewDecided
+	}
+
+	return nil
+}
+
+// GetRecords 获取审核提交的审核记录
+func (r *knowledgeReviewRepository) GetRecords(ctx context.Context, submissionID string) ([]*models.KnowledgeReviewRecord, error) {
+	query := `
+		SELECT id, submission_id, reviewer_id, decision, comment, created_at
+		FROM knowledge_review_records
+		WHERE submission_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("获取审核记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*models.KnowledgeReviewRecord, 0)
+	for rows.Next() {
+		var record models.KnowledgeReviewRecord
+		if err := rows.Scan(&record.ID, &record.SubmissionID, &record.ReviewerID, &record.Decision,
+			&record.Comment, &record.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描审核记录失败: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历审核记录失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// ListPendingForReviewer 获取审核人的待审核队列
+// 包括未限定审核人的请求、指定该用户或其角色的请求，排除已审核和不允许自审的请求
+func (r *knowledgeReviewRepository) ListPendingForReviewer(ctx context.Context, filter *models.KnowledgeReviewQueueFilter) (*models.KnowledgeReviewQueue, error) {
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	whereClause := `
+		WHERE r.status = $1
+		  AND (r.allow_self_review OR r.submitted_by::text <> $2)
+		  AND (
+			(cardinality(r.reviewer_ids) = 0 AND cardinality(r.reviewer_roles) = 0)
+			OR $2 = ANY(r.reviewer_ids::text[])
+			OR $3 = ANY(r.reviewer_roles)
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM knowledge_review_records d
+			WHERE d.submission_id = r.id AND d.reviewer_id::text = $2
+		  )`
+	args := []interface{}{models.KnowledgeReviewSubmissionPending, filter.ReviewerID, string(filter.ReviewerRole)}
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM knowledge_review_submissions r " + whereClause
+	if err := r.getExecutor().QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("获取待审核数量失败: %w", err)
+	}
+
+	query := `
+		SELECT ` + knowledgeReviewSubmissionColumns + `
+		FROM knowledge_review_submissions r
+		LEFT JOIN knowledge_articles k ON k.id = r.knowledge_id
+		` + whereClause + `
+		ORDER BY r.submitted_at
+		LIMIT $4 OFFSET $5`
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取待审核队列失败: %w", err)
+	}
+	defer rows.Close()
+
+	requests := make([]*models.KnowledgeReviewSubmission, 0)
+	for rows.Next() {
+		req, err := scanKnowledgeReviewSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描审核提交失败: %w", err)
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历审核提交失败: %w", err)
+	}
+
+	return &models.KnowledgeReviewQueue{
+		Submissions: requests,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+	}, nil
+}
+
+// scanKnowledgeReviewPolicy 扫描审核策略
+func scanKnowledgeReviewPolicy(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.KnowledgeReviewPolicy, error) {
+	var policy models.KnowledgeReviewPolicy
+	var reviewerIDs, reviewerRoles pq.StringArray
+
+	err := scanner.Scan(
+		&policy.ID, &policy.Name, &policy.Description, &policy.CategoryID, &policy.RequiredApprovals,
+		&reviewerIDs, &reviewerRoles, &policy.AllowSelfReview, &policy.Enabled, &policy.CreatedBy,
+		&policy.CreatedAt, &policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.ReviewerIDs = []string(reviewerIDs)
+	policy.ReviewerRoles = []string(reviewerRoles)
+	if policy.ReviewerIDs == nil {
+		policy.ReviewerIDs = []string{}
+	}
+	if policy.ReviewerRoles == nil {
+		policy.ReviewerRoles = []string{}
+	}
+
+	return &policy, nil
+}
+
+// scanKnowledgeReviewSubmission 扫描审核提交
+func scanKnowledgeReviewSubmission(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.KnowledgeReviewSubmission, error) {
+	var req models.KnowledgeReviewSubmission
+	var reviewerIDs, reviewerRoles pq.StringArray
+
+	err := scanner.Scan(
+		&req.ID, &req.KnowledgeID, &req.KnowledgeTitle, &req.PolicyID, &req.RequiredApprovals,
+		&reviewerIDs, &reviewerRoles, &req.AllowSelfReview, &req.Status, &req.SubmittedBy,
+		&req.SubmittedAt, &req.CompletedAt, &req.Approvals,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ReviewerIDs = []string(reviewerIDs)
+	req.ReviewerRoles = []string(reviewerRoles)
+	if req.ReviewerIDs == nil {
+		req.ReviewerIDs = []string{}
+	}
+	if req.ReviewerRoles == nil {
+		req.ReviewerRoles = []string{}
+	}
+
+	return &req, nil
+}
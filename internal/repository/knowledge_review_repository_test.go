@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var knowledgeReviewPolicyTestColumns = []string{
+	"id", "name", "description", "category_id", "required_approvals", "reviewer_ids",
+	"reviewer_roles", "allow_self_review", "enabled", "created_by", "created_at", "updated_at",
+}
+
+func TestKnowledgeReviewRepository_GetPolicyForCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeReviewRepository(sqlxDB)
+
+	categoryID := "category-1"
+	now := time.Now()
+	rows := sqlmock.NewRows(knowledgeReviewPolicyTestColumns).
+		AddRow("policy-1", "运维手册审核", nil, categoryID, 2, "{user-1,user-2}",
+			"{admin}", false, true, nil, now, now)
+
+	mock.ExpectQuery(`SELECT (.+) FROM knowledge_review_policies`).
+		WithArgs(&categoryID).
+		WillReturnRows(rows)
+
+	policy, err := repo.GetPolicyForCategory(context.Background(), &categoryID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, policy.RequiredApprovals)
+	assert.Equal(t, []string{"user-1", "user-2"}, policy.ReviewerIDs)
+	assert.Equal(t, []string{"admin"}, policy.ReviewerRoles)
+	assert.Nil(t, policy.CreatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeReviewRepository_GetPolicyForCategory_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeReviewRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT (.+) FROM knowledge_review_policies`).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetPolicyForCategory(context.Background(), nil)
+	assert.ErrorIs(t, err, models.ErrKnowledgeReviewPolicyNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeReviewRepository_AddRecord_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeReviewRepository(sqlxDB)
+
+	record := &models.KnowledgeReviewRecord{
+		SubmissionID: "submission-1",
+		ReviewerID:   "user-1",
+		Decision:     models.KnowledgeReviewDecisionApproved,
+	}
+
+	mock.ExpectExec(`INSERT INTO knowledge_review_records`).
+		WithArgs(sqlmock.AnyArg(), "submission-1", "user-1", models.KnowledgeReviewDecisionApproved,
+			sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.AddRecord(context.Background(), record)
+	assert.ErrorIs(t, err, models.ErrKnowledgeReviewDecided)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeReviewRepository_CompleteSubmission_NotPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewKnowledgeReviewRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE knowledge_review_submissions SET`).
+		WithArgs(models.KnowledgeReviewSubmissionApproved, sqlmock.AnyArg(), "submission-1", models.KnowledgeReviewSubmissionPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.CompleteSubmission(context.Background(), "submission-1", models.KnowledgeReviewSubmissionApproved)
+	assert.ErrorIs(t, err, models.ErrKnowledgeReviewNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKnowledgeReviewSubmission_CanReview(t *testing.T) {
+	policy := &models.KnowledgeReviewPolicy{
+		ID:                "policy-1",
+		RequiredApprovals: 2,
+		ReviewerIDs:       []string{"user-2"},
+		ReviewerRoles:     []string{string(models.UserRoleAdmin)},
+	}
+	submission := models.NewKnowledgeReviewSubmission("knowledge-1", "author", policy)
+
+	assert.True(t, submission.CanReview("user-2", models.UserRoleViewer))
+	assert.True(t, submission.CanReview("user-3", models.UserRoleAdmin))
+	assert.False(t, submission.CanReview("user-4", models.UserRoleOperator))
+	assert.False(t, submission.CanReview("author", models.UserRoleAdmin))
+
+	open := models.NewKnowledgeReviewSubmission("knowledge-1", "author", models.DefaultKnowledgeReviewPolicy())
+	assert.True(t, open.CanReview("anyone", models.UserRoleGuest))
+	assert.Nil(t, open.PolicyID)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// languagePreferenceRepository 语言偏好仓储实现
+type languagePreferenceRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewLanguagePreferenceRepository 创建语言偏好仓储实例
+func NewLanguagePreferenceRepository(db *sqlx.DB) LanguagePreferenceRepository {
+	return &languagePreferenceRepository{db: db}
+}
+
+// NewLanguagePreferenceRepositoryWithTx 创建带事务的语言偏好仓储实例
+func NewLanguagePreferenceRepositoryWithTx(tx *sqlx.Tx) LanguagePreferenceRepository {
+	return &languagePreferenceRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *languagePreferenceRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Get 获取用户或团队的语言偏好
+func (r *languagePreferenceRepository) Get(ctx context.Context, scope models.LanguageScope, subject string) (*models.LanguagePreference, error) {
+	query := `SELECT scope, subject, language, updated_at FROM language_preferences WHERE scope = $1 AND subject = $2`
+
+	var pref models.LanguagePreference
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &pref, query, scope, subject); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrLanguagePreferenceNotFound
+		}
+		return nil, fmt.Errorf("获取语言偏好失败: %w", err)
+	}
+	return &pref, nil
+}
+
+// Set 设置用户或团队的语言偏好，已设置时覆盖
+func (r *languagePreferenceRepository) Set(ctx context.Context, pref *models.LanguagePreference) error {
+	pref.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO language_preferences (scope, subject, language, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, subject) DO UPDATE SET language = EXCLUDED.language, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, pref.Scope, pref.Subject, pref.Language, pref.UpdatedAt); err != nil {
+		return fmt.Errorf("设置语言偏好失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除用户或团队的语言偏好
+func (r *languagePreferenceRepository) Delete(ctx context.Context, scope models.LanguageScope, subject string) error {
+	result, err := r.getExecutor().ExecContext(ctx,
+		`DELETE FROM language_preferences WHERE scope = $1 AND subject = $2`, scope, subject)
+	if err != nil {
+		return fmt.Errorf("删除语言偏好失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrLanguagePreferenceNotFound
+	}
+
+	return nil
+}
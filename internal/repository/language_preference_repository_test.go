@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestLanguagePreferenceRepository_SetAndGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLanguagePreferenceRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	mock.ExpectExec(`INSERT INTO language_preferences .+ ON CONFLICT \(scope, subject\) DO UPDATE`).
+		WithArgs(models.LanguageScopeTeam, "payments", "en-US", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Set(ctx, &models.LanguagePreference{
+		Scope: models.LanguageScopeTeam, Subject: "payments", Language: "en-US",
+	}))
+
+	mock.ExpectQuery(`SELECT scope, subject, language, updated_at FROM language_preferences WHERE scope = \$1 AND subject = \$2`).
+		WithArgs(models.LanguageScopeUser, "u1").
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "subject", "language", "updated_at"}))
+	_, err = repo.Get(ctx, models.LanguageScopeUser, "u1")
+	assert.ErrorIs(t, err, models.ErrLanguagePreferenceNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
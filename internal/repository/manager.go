@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"pulse/internal/crypto"
+	"pulse/internal/pkg/httpclient"
 )
 
 // repositoryManager 仓储管理器实现
@@ -12,6 +13,8 @@ type repositoryManager struct {
 	db *sqlx.DB
 	tx *sqlx.Tx
 	encryptionService crypto.EncryptionService
+	encryptUserPII    bool
+	httpClients       *httpclient.Factory
 
 	// 仓储实例
 	userRepo       UserRepository
@@ -24,23 +27,117 @@ type repositoryManager struct {
 	authRepo         AuthRepository
 	webhookRepo      WebhookRepository
 	notificationRepo NotificationRepository
+	customFieldRepo  CustomFieldRepository
+	knowledgeReviewRepo KnowledgeReviewRepository
+	knowledgeCommentRepo KnowledgeCommentRepository
+	knowledgeACLRepo     KnowledgeACLRepository
+	webhookIntegrationRepo WebhookIntegrationRepository
+	featureFlagRepo        FeatureFlagRepository
+	dashboardRepo          DashboardRepository
+	postmortemRepo         PostmortemRepository
+	remediationRepo        RemediationRepository
+	chatUserMappingRepo    ChatUserMappingRepository
+	itsmRepo               ITSMRepository
+	heartbeatRepo          HeartbeatRepository
+	serviceCatalogRepo     ServiceCatalogRepository
+	sloRepo                SLORepository
+	alertSnoozeRepo        AlertSnoozeRepository
+	notificationRoutingRepo NotificationRoutingRepository
+	languagePreferenceRepo  LanguagePreferenceRepository
+	tagRepo                 TagRepository
+	partitionRepo           PartitionRepository
+	retentionRepo           RetentionRepository
+	auditLogRepo            AuditLogRepository
+	assignmentPolicyRepo    AssignmentPolicyRepository
+	handoverRepo            HandoverRepository
+	alertActionLinkRepo     AlertActionLinkRepository
+	pushRepo                PushRepository
+	inboxRepo               InboxRepository
+	quietHoursRepo          QuietHoursRepository
+	changeEventRepo         ChangeEventRepository
+	notificationAttemptRepo NotificationAttemptRepository
+	webhookSubscriptionRepo WebhookSubscriptionRepository
+	ticketImportRepo        TicketImportRepository
+	alertStatsRepo          AlertStatsRepository
+	ticketSurveyRepo        TicketSurveyRepository
+	knowledgeFeedbackRepo   KnowledgeFeedbackRepository
+	approvalRepo            ApprovalRepository
+	ingestRejectionRepo     IngestRejectionRepository
+	asyncTaskRepo           AsyncTaskRepository
+	notificationChannelRepo NotificationChannelRepository
+	metricRepo              MetricRepository
+	ticketSLAPauseRepo      TicketSLAPauseRepository
+	alertSeverityRuleRepo   AlertSeverityRuleRepository
+	statusPageRepo          StatusPageRepository
 }
 
 // NewRepositoryManager 创建新的仓储管理器
 func NewRepositoryManager(db *sqlx.DB, encryptionService crypto.EncryptionService) RepositoryManager {
+	return NewRepositoryManagerWithReader(db, db, encryptionService, false, nil)
+}
+
+// NewRepositoryManagerWithReader 创建新的仓储管理器，告警、工单和规则的列表与统计查询使用 reader
+// encryptUserPII 为 true 时用户邮箱和手机号加密存储，要求加密服务支持盲索引；
+// httpClients 创建数据源连接测试的 HTTP 客户端，为 nil 时使用默认的超时和重试策略
+func NewRepositoryManagerWithReader(db *sqlx.DB, reader Reader, encryptionService crypto.EncryptionService, encryptUserPII bool, httpClients *httpclient.Factory) RepositoryManager {
+	fieldEncryptor, _ := encryptionService.(crypto.FieldEncryptor)
 	return &repositoryManager{
 		db: db,
 		encryptionService: encryptionService,
-		userRepo:       NewUserRepository(db),
-		alertRepo:      NewAlertRepository(db),
-		ruleRepo:       NewRuleRepository(db),
-		dataSourceRepo: NewDataSourceRepository(db, encryptionService),
-		ticketRepo:     NewTicketRepository(db),
+		encryptUserPII:    encryptUserPII,
+		httpClients:       httpClients,
+		userRepo:       NewUserRepository(db, fieldEncryptor, encryptUserPII),
+		alertRepo:      NewAlertRepositoryWithReader(db, reader),
+		ruleRepo:       NewRuleRepositoryWithReader(db, reader),
+		dataSourceRepo: NewDataSourceRepositoryWithHTTPClients(db, encryptionService, httpClients),
+		ticketRepo:     NewTicketRepositoryWithReader(db, reader),
 		knowledgeRepo:  NewKnowledgeRepository(db),
 		permissionRepo:   NewPermissionRepository(db),
 		authRepo:         NewAuthRepository(db),
-		webhookRepo:      NewWebhookRepository(db),
+		webhookRepo:      NewWebhookRepository(db, encryptionService),
 		notificationRepo: NewNotificationRepository(db),
+		customFieldRepo:  NewCustomFieldRepository(db),
+		knowledgeReviewRepo: NewKnowledgeReviewRepository(db),
+		knowledgeCommentRepo: NewKnowledgeCommentRepository(db),
+		knowledgeACLRepo:     NewKnowledgeACLRepository(db),
+		webhookIntegrationRepo: NewWebhookIntegrationRepository(db, encryptionService),
+		featureFlagRepo:        NewFeatureFlagRepository(db),
+		dashboardRepo:          NewDashboardRepositoryWithReader(reader),
+		postmortemRepo:         NewPostmortemRepository(db),
+		remediationRepo:        NewRemediationRepository(db),
+		chatUserMappingRepo:    NewChatUserMappingRepository(db),
+		itsmRepo:               NewITSMRepository(db, encryptionService),
+		heartbeatRepo:          NewHeartbeatRepository(db),
+		serviceCatalogRepo:     NewServiceCatalogRepository(db),
+		sloRepo:                NewSLORepository(db),
+		alertSnoozeRepo:        NewAlertSnoozeRepository(db),
+		notificationRoutingRepo: NewNotificationRoutingRepository(db),
+		languagePreferenceRepo:  NewLanguagePreferenceRepository(db),
+		tagRepo:                 NewTagRepository(db),
+		partitionRepo:           NewPartitionRepository(db),
+		retentionRepo:           NewRetentionRepository(db),
+		auditLogRepo:            NewAuditLogRepositoryWithReader(db, reader),
+		assignmentPolicyRepo:    NewAssignmentPolicyRepository(db),
+		handoverRepo:            NewHandoverRepository(db),
+		alertActionLinkRepo:     NewAlertActionLinkRepository(db),
+		pushRepo:                NewPushRepository(db),
+		inboxRepo:               NewInboxRepository(db),
+		quietHoursRepo:          NewQuietHoursRepository(db),
+		changeEventRepo:         NewChangeEventRepository(db),
+		notificationAttemptRepo: NewNotificationAttemptRepository(db),
+		webhookSubscriptionRepo: NewWebhookSubscriptionRepository(db, encryptionService),
+		ticketImportRepo:        NewTicketImportRepository(db),
+		alertStatsRepo:          NewAlertStatsRepository(db),
+		ticketSurveyRepo:        NewTicketSurveyRepository(db),
+		knowledgeFeedbackRepo:   NewKnowledgeFeedbackRepository(db),
+		approvalRepo:            NewApprovalRepository(db),
+		ingestRejectionRepo:     NewIngestRejectionRepository(db),
+		asyncTaskRepo:           NewAsyncTaskRepository(db),
+		notificationChannelRepo: NewNotificationChannelRepository(db, encryptionService),
+		metricRepo:              NewMetricRepository(db),
+		ticketSLAPauseRepo:      NewTicketSLAPauseRepository(db),
+		alertSeverityRuleRepo:   NewAlertSeverityRuleRepository(db),
+		statusPageRepo:          NewStatusPageRepository(db),
 	}
 }
 
@@ -94,6 +191,216 @@ func (r *repositoryManager) Notification() NotificationRepository {
 	return r.notificationRepo
 }
 
+// CustomField 获取自定义字段定义仓储
+func (r *repositoryManager) CustomField() CustomFieldRepository {
+	return r.customFieldRepo
+}
+
+// KnowledgeReview 获取知识库审核仓储
+func (r *repositoryManager) KnowledgeReview() KnowledgeReviewRepository {
+	return r.knowledgeReviewRepo
+}
+
+// KnowledgeComment 获取知识库评论仓储
+func (r *repositoryManager) KnowledgeComment() KnowledgeCommentRepository {
+	return r.knowledgeCommentRepo
+}
+
+// KnowledgeACL 获取知识库访问控制仓储
+func (r *repositoryManager) KnowledgeACL() KnowledgeACLRepository {
+	return r.knowledgeACLRepo
+}
+
+// WebhookIntegration 获取告警接入集成仓储
+func (r *repositoryManager) WebhookIntegration() WebhookIntegrationRepository {
+	return r.webhookIntegrationRepo
+}
+
+// FeatureFlag 获取功能开关仓储
+func (r *repositoryManager) FeatureFlag() FeatureFlagRepository {
+	return r.featureFlagRepo
+}
+
+// Dashboard 获取首页仪表盘统计仓储
+func (r *repositoryManager) Dashboard() DashboardRepository {
+	return r.dashboardRepo
+}
+
+// Postmortem 获取事件复盘仓储
+func (r *repositoryManager) Postmortem() PostmortemRepository {
+	return r.postmortemRepo
+}
+
+// Remediation 获取修复动作执行记录仓储
+func (r *repositoryManager) Remediation() RemediationRepository {
+	return r.remediationRepo
+}
+
+// ChatUserMapping 获取聊天用户映射仓储
+func (r *repositoryManager) ChatUserMapping() ChatUserMappingRepository {
+	return r.chatUserMappingRepo
+}
+
+// ITSM 获取外部 ITSM 同步仓储
+func (r *repositoryManager) ITSM() ITSMRepository {
+	return r.itsmRepo
+}
+
+// Heartbeat 获取心跳检查仓储
+func (r *repositoryManager) Heartbeat() HeartbeatRepository {
+	return r.heartbeatRepo
+}
+
+// ServiceCatalog 获取服务目录仓储
+func (r *repositoryManager) ServiceCatalog() ServiceCatalogRepository {
+	return r.serviceCatalogRepo
+}
+
+// SLO 获取 SLO 仓储
+func (r *repositoryManager) SLO() SLORepository {
+	return r.sloRepo
+}
+
+// AlertSnooze 获取告警暂停仓储
+func (r *repositoryManager) AlertSnooze() AlertSnoozeRepository {
+	return r.alertSnoozeRepo
+}
+
+// NotificationRouting 获取通知路由仓储
+func (r *repositoryManager) NotificationRouting() NotificationRoutingRepository {
+	return r.notificationRoutingRepo
+}
+
+// LanguagePreference 获取语言偏好仓储
+func (r *repositoryManager) LanguagePreference() LanguagePreferenceRepository {
+	return r.languagePreferenceRepo
+}
+
+// Tag 获取标签治理仓储
+func (r *repositoryManager) Tag() TagRepository {
+	return r.tagRepo
+}
+
+// Partition 获取分区仓储
+func (r *repositoryManager) Partition() PartitionRepository {
+	return r.partitionRepo
+}
+
+// Retention 获取数据保留仓储
+func (r *repositoryManager) Retention() RetentionRepository {
+	return r.retentionRepo
+}
+
+// AuditLog 获取审计日志仓储
+func (r *repositoryManager) AuditLog() AuditLogRepository {
+	return r.auditLogRepo
+}
+
+// AssignmentPolicy 获取分派策略仓储
+func (r *repositoryManager) AssignmentPolicy() AssignmentPolicyRepository {
+	return r.assignmentPolicyRepo
+}
+
+// Handover 获取交班报告仓储
+func (r *repositoryManager) Handover() HandoverRepository {
+	return r.handoverRepo
+}
+
+// AlertActionLink 获取告警操作链接使用记录仓储
+func (r *repositoryManager) AlertActionLink() AlertActionLinkRepository {
+	return r.alertActionLinkRepo
+}
+
+// Push 获取移动端推送仓储
+func (r *repositoryManager) Push() PushRepository {
+	return r.pushRepo
+}
+
+// Inbox 获取站内通知收件箱仓储
+func (r *repositoryManager) Inbox() InboxRepository {
+	return r.inboxRepo
+}
+
+// QuietHours 获取用户免打扰设置仓储
+func (r *repositoryManager) QuietHours() QuietHoursRepository {
+	return r.quietHoursRepo
+}
+
+// ChangeEvent 获取变更事件仓储
+func (r *repositoryManager) ChangeEvent() ChangeEventRepository {
+	return r.changeEventRepo
+}
+
+// NotificationAttempt 获取通知投递记录仓储
+func (r *repositoryManager) NotificationAttempt() NotificationAttemptRepository {
+	return r.notificationAttemptRepo
+}
+
+// WebhookSubscription 获取出站 Webhook 订阅仓储
+func (r *repositoryManager) WebhookSubscription() WebhookSubscriptionRepository {
+	return r.webhookSubscriptionRepo
+}
+
+// TicketImport 获取工单导入任务仓储
+func (r *repositoryManager) TicketImport() TicketImportRepository {
+	return r.ticketImportRepo
+}
+
+// AlertStats 获取告警统计仓储
+func (r *repositoryManager) AlertStats() AlertStatsRepository {
+	return r.alertStatsRepo
+}
+
+// TicketSurvey 获取工单满意度调查仓储
+func (r *repositoryManager) TicketSurvey() TicketSurveyRepository {
+	return r.ticketSurveyRepo
+}
+
+// KnowledgeFeedback 获取知识库有用性反馈仓储
+func (r *repositoryManager) KnowledgeFeedback() KnowledgeFeedbackRepository {
+	return r.knowledgeFeedbackRepo
+}
+
+// Approval 获取审批请求仓储
+func (r *repositoryManager) Approval() ApprovalRepository {
+	return r.approvalRepo
+}
+
+// IngestRejection 获取告警接入拒绝记录仓储
+func (r *repositoryManager) IngestRejection() IngestRejectionRepository {
+	return r.ingestRejectionRepo
+}
+
+// AsyncTask 获取异步任务仓储
+func (r *repositoryManager) AsyncTask() AsyncTaskRepository {
+	return r.asyncTaskRepo
+}
+
+// NotificationChannel 获取团队通知渠道仓储
+func (r *repositoryManager) NotificationChannel() NotificationChannelRepository {
+	return r.notificationChannelRepo
+}
+
+// Metric 获取内置指标存储仓储
+func (r *repositoryManager) Metric() MetricRepository {
+	return r.metricRepo
+}
+
+// TicketSLAPause 获取工单SLA暂停仓储
+func (r *repositoryManager) TicketSLAPause() TicketSLAPauseRepository {
+	return r.ticketSLAPauseRepo
+}
+
+// AlertSeverityRule 获取告警重新分级规则仓储
+func (r *repositoryManager) AlertSeverityRule() AlertSeverityRuleRepository {
+	return r.alertSeverityRuleRepo
+}
+
+// StatusPage 获取状态页仓储
+func (r *repositoryManager) StatusPage() StatusPageRepository {
+	return r.statusPageRepo
+}
+
 // BeginTx 开始事务
 func (r *repositoryManager) BeginTx(ctx context.Context) (RepositoryManager, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
@@ -101,19 +408,65 @@ func (r *repositoryManager) BeginTx(ctx context.Context) (RepositoryManager, err
 		return nil, err
 	}
 
+	fieldEncryptor, _ := r.encryptionService.(crypto.FieldEncryptor)
 	return &repositoryManager{
 		db: r.db,
 		tx: tx,
 		encryptionService: r.encryptionService,
-		userRepo:       NewUserRepositoryWithTx(tx),
+		encryptUserPII:    r.encryptUserPII,
+		httpClients:       r.httpClients,
+		userRepo:       NewUserRepositoryWithTx(tx, fieldEncryptor, r.encryptUserPII),
 		alertRepo:      NewAlertRepositoryWithTx(tx),
 		ruleRepo:       NewRuleRepositoryWithTx(tx),
-		dataSourceRepo: NewDataSourceRepositoryWithTx(tx, r.encryptionService),
+		dataSourceRepo: NewDataSourceRepositoryWithTx(tx, r.encryptionService, r.httpClients),
 		ticketRepo:     NewTicketRepositoryWithTx(tx),
 		knowledgeRepo:    NewKnowledgeRepositoryWithTx(tx),
 		permissionRepo:   NewPermissionRepositoryWithTx(tx),
 		authRepo:         NewAuthRepositoryWithTx(tx),
+		webhookRepo:      NewWebhookRepositoryWithTx(tx, r.encryptionService),
 		notificationRepo: NewNotificationRepositoryWithTx(tx),
+		customFieldRepo:  NewCustomFieldRepositoryWithTx(tx),
+		knowledgeReviewRepo: NewKnowledgeReviewRepositoryWithTx(tx),
+		knowledgeCommentRepo: NewKnowledgeCommentRepositoryWithTx(tx),
+		knowledgeACLRepo:     NewKnowledgeACLRepositoryWithTx(tx),
+		webhookIntegrationRepo: NewWebhookIntegrationRepositoryWithTx(tx, r.encryptionService),
+		featureFlagRepo:        NewFeatureFlagRepositoryWithTx(tx),
+		dashboardRepo:          NewDashboardRepositoryWithTx(tx),
+		postmortemRepo:         NewPostmortemRepositoryWithTx(tx),
+		remediationRepo:        NewRemediationRepositoryWithTx(tx),
+		chatUserMappingRepo:    NewChatUserMappingRepositoryWithTx(tx),
+		itsmRepo:               NewITSMRepositoryWithTx(tx, r.encryptionService),
+		heartbeatRepo:          NewHeartbeatRepositoryWithTx(tx),
+		serviceCatalogRepo:     NewServiceCatalogRepositoryWithTx(tx),
+		sloRepo:                NewSLORepositoryWithTx(tx),
+		alertSnoozeRepo:        NewAlertSnoozeRepositoryWithTx(tx),
+		notificationRoutingRepo: NewNotificationRoutingRepositoryWithTx(tx),
+		languagePreferenceRepo:  NewLanguagePreferenceRepositoryWithTx(tx),
+		tagRepo:                 NewTagRepositoryWithTx(tx),
+		partitionRepo:           NewPartitionRepositoryWithTx(tx),
+		retentionRepo:           NewRetentionRepositoryWithTx(tx),
+		auditLogRepo:            NewAuditLogRepositoryWithTx(tx),
+		assignmentPolicyRepo:    NewAssignmentPolicyRepositoryWithTx(tx),
+		handoverRepo:            NewHandoverRepositoryWithTx(tx),
+		alertActionLinkRepo:     NewAlertActionLinkRepositoryWithTx(tx),
+		pushRepo:                NewPushRepositoryWithTx(tx),
+		inboxRepo:               NewInboxRepositoryWithTx(tx),
+		quietHoursRepo:          NewQuietHoursRepositoryWithTx(tx),
+		changeEventRepo:         NewChangeEventRepositoryWithTx(tx),
+		notificationAttemptRepo: NewNotificationAttemptRepositoryWithTx(tx),
+		webhookSubscriptionRepo: NewWebhookSubscriptionRepositoryWithTx(tx, r.encryptionService),
+		ticketImportRepo:        NewTicketImportRepositoryWithTx(tx),
+		alertStatsRepo:          NewAlertStatsRepositoryWithTx(tx),
+		ticketSurveyRepo:        NewTicketSurveyRepositoryWithTx(tx),
+		knowledgeFeedbackRepo:   NewKnowledgeFeedbackRepositoryWithTx(tx),
+		approvalRepo:            NewApprovalRepositoryWithTx(tx),
+		ingestRejectionRepo:     NewIngestRejectionRepositoryWithTx(tx),
+		asyncTaskRepo:           NewAsyncTaskRepositoryWithTx(tx),
+		notificationChannelRepo: NewNotificationChannelRepositoryWithTx(tx, r.encryptionService),
+		metricRepo:              NewMetricRepositoryWithTx(tx),
+		ticketSLAPauseRepo:      NewTicketSLAPauseRepositoryWithTx(tx),
+		alertSeverityRuleRepo:   NewAlertSeverityRuleRepositoryWithTx(tx),
+		statusPageRepo:          NewStatusPageRepositoryWithTx(tx),
 	}, nil
 }
 
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// maxMetricQuerySeries 单次即时查询最多匹配的序列数
+const maxMetricQuerySeries = 10000
+
+// metricRepository 内置指标存储仓储实现
+type metricRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewMetricRepository 创建内置指标存储仓储实例
+func NewMetricRepository(db *sqlx.DB) MetricRepository {
+	return &metricRepository{db: db}
+}
+
+// NewMetricRepositoryWithTx 创建带事务的内置指标存储仓储实例
+func NewMetricRepositoryWithTx(tx *sqlx.Tx) MetricRepository {
+	return &metricRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *metricRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Write 写入时间序列的样本，序列按指纹查找，不存在时创建。相同时间戳的样本覆盖已有的值，
+// 重复写入同一批样本的结果不变，采集端重试部分失败的请求是安全的
+func (r *metricRepository) Write(ctx context.Context, series []*models.MetricSeries) (int, error) {
+	exec := r.getExecutor()
+	now := time.Now()
+	written := 0
+
+	for _, s := range series {
+		if s.Fingerprint == "" {
+			s.Fingerprint = models.MetricFingerprint(s.Metric, s.Labels)
+		}
+		labels, err := json.Marshal(s.Labels)
+		if err != nil {
+			return written, fmt.Errorf("序列化标签失败: %w", err)
+		}
+
+		query := `
+			INSERT INTO metric_series (metric, labels, fingerprint, created_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (fingerprint) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+			RETURNING id`
+		if err := sqlx.GetContext(ctx, exec, &s.ID, query, s.Metric, labels, s.Fingerprint, now); err != nil {
+			return written, fmt.Errorf("写入时间序列失败: %w", err)
+		}
+
+		timestamps, values := dedupeMetricSamples(s.Samples)
+		if len(timestamps) == 0 {
+			continue
+		}
+		query = `
+			INSERT INTO metric_samples (series_id, ts, value)
+			SELECT $1, to_timestamp(t / 1000.0), v FROM UNNEST($2::BIGINT[], $3::DOUBLE PRECISION[]) AS s(t, v)
+			ON CONFLICT (series_id, ts) DO UPDATE SET value = EXCLUDED.value`
+		if _, err := exec.ExecContext(ctx, query, s.ID, pq.Int64Array(timestamps), pq.Float64Array(values)); err != nil {
+			return written, fmt.Errorf("写入样本失败: %w", err)
+		}
+		written += len(timestamps)
+	}
+	return written, nil
+}
+
+// dedupeMetricSamples 按时间戳排序样本并去重，同一时间戳保留最后一个值，
+// 同一条 INSERT ... ON CONFLICT 语句不能多次更新同一行
+func dedupeMetricSamples(samples []models.MetricSample) ([]int64, []float64) {
+	byTimestamp := make(map[int64]float64, len(samples))
+	for _, sample := range samples {
+		byTimestamp[sample.Timestamp.UnixMilli()] = sample.Value
+	}
+	timestamps := make([]int64, 0, len(byTimestamp))
+	for ts := range byTimestamp {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	values := make([]float64, len(timestamps))
+	for i, ts := range timestamps {
+		values[i] = byTimestamp[ts]
+	}
+	return timestamps, values
+}
+
+// Latest 获取匹配查询的序列在 (at-lookback, at] 内的最新样本，没有样本的序列不返回
+func (r *metricRepository) Latest(ctx context.Context, query *models.MetricQuery, at time.Time, lookback time.Duration) ([]*models.MetricSeries, error) {
+	conditions := []string{"s.metric = $1"}
+	args := []interface{}{query.Metric, at, at.Add(-lookback)}
+	for _, m := range query.Matchers {
+		value := m.Value
+		var op string
+		switch m.Op {
+		case models.MetricMatchEqual:
+			op = "="
+		case models.MetricMatchNotEqual:
+			op = "<>"
+		case models.MetricMatchRegexp, models.MetricMatchNotRegexp:
+			// 与 PromQL 一致，正则表达式须匹配完整的标签值
+			op = "~"
+			if m.Op == models.MetricMatchNotRegexp {
+				op = "!~"
+			}
+			value = "^(?:" + value + ")$"
+		default:
+			return nil, fmt.Errorf("%w: 不支持的标签匹配方式 %s", models.ErrInvalidInput, m.Op)
+		}
+		args = append(args, m.Name, value)
+		conditions = append(conditions, fmt.Sprintf("COALESCE(s.labels->>$%d, '') %s $%d", len(args)-1, op, len(args)))
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT s.id, s.metric, s.labels, p.ts, p.value
+		FROM metric_series s
+		JOIN LATERAL (
+			SELECT ts, value FROM metric_samples
+			WHERE series_id = s.id AND ts <= $2 AND ts > $3
+			ORDER BY ts DESC
+			LIMIT 1
+		) p ON TRUE
+		WHERE %s
+		ORDER BY s.id
+		LIMIT %d`, strings.Join(conditions, " AND "), maxMetricQuerySeries)
+
+	rows, err := r.getExecutor().QueryxContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询指标失败: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.MetricSeries
+	for rows.Next() {
+		series := &models.MetricSeries{}
+		var labels []byte
+		var sample models.MetricSample
+		if err := rows.Scan(&series.ID, &series.Metric, &labels, &sample.Timestamp, &sample.Value); err != nil {
+			return nil, fmt.Errorf("扫描指标失败: %w", err)
+		}
+		if err := json.Unmarshal(labels, &series.Labels); err != nil {
+			return nil, fmt.Errorf("解析标签失败: %w", err)
+		}
+		sample.SeriesID = series.ID
+		series.Samples = []models.MetricSample{sample}
+		result = append(result, series)
+	}
+	return result, rows.Err()
+}
+
+// DeleteBefore 删除 before 之前的样本，以及此后没有再上报的序列，返回删除的样本数
+func (r *metricRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	exec := r.getExecutor()
+	result, err := exec.ExecContext(ctx, `DELETE FROM metric_samples WHERE ts < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理过期样本失败: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM metric_series WHERE last_seen_at < $1`, before); err != nil {
+		return deleted, fmt.Errorf("清理过期序列失败: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestMetricRepository_Write(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewMetricRepository(sqlx.NewDb(db, "postgres"))
+
+	t0 := time.UnixMilli(1700000000000)
+	series := &models.MetricSeries{
+		Metric: "node_load1",
+		Labels: map[string]string{"instance": "web-1"},
+		Samples: []models.MetricSample{
+			{Timestamp: t0.Add(15 * time.Second), Value: 2},
+			{Timestamp: t0, Value: 1},
+			// 同一时间戳的样本保留最后一个值
+			{Timestamp: t0, Value: 1.5},
+		},
+	}
+
+	mock.ExpectQuery(`INSERT INTO metric_series .+ ON CONFLICT \(fingerprint\) DO UPDATE`).
+		WithArgs("node_load1", []byte(`{"instance":"web-1"}`),
+			models.MetricFingerprint("node_load1", series.Labels), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+	mock.ExpectExec(`INSERT INTO metric_samples .+ ON CONFLICT \(series_id, ts\) DO UPDATE`).
+		WithArgs(int64(7), pq.Int64Array{1700000000000, 1700000015000}, pq.Float64Array{1.5, 2}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	written, err := repo.Write(context.Background(), []*models.MetricSeries{series})
+	require.NoError(t, err)
+	assert.Equal(t, 2, written)
+	assert.Equal(t, int64(7), series.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricRepository_Latest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewMetricRepository(sqlx.NewDb(db, "postgres"))
+
+	query, err := models.ParseMetricQuery(`max(node_load1{job="node",instance=~"web-.*"})`)
+	require.NoError(t, err)
+	at := time.Now()
+
+	// 正则匹配完整的标签值，标签不存在时按空字符串匹配
+	mock.ExpectQuery(`WHERE s.metric = \$1 AND COALESCE\(s.labels->>\$4, ''\) = \$5 AND COALESCE\(s.labels->>\$6, ''\) ~ \$7`).
+		WithArgs("node_load1", at, at.Add(-5*time.Minute), "job", "node", "instance", "^(?:web-.*)$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "metric", "labels", "ts", "value"}).
+			AddRow(int64(7), "node_load1", []byte(`{"job":"node","instance":"web-1"}`), at.Add(-10*time.Second), 1.5))
+
+	series, err := repo.Latest(context.Background(), query, at, 5*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "web-1", series[0].Labels["instance"])
+	assert.Equal(t, 1.5, series[0].Samples[0].Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
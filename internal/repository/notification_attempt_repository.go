@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// notificationAttemptColumns 通知投递记录查询的列
+const notificationAttemptColumns = `id, notification_id, alert_id, channel, target, payload_hash, status, provider_response, error, duration_ms, resent_by, attempted_at`
+
+// notificationAttemptRepository 通知投递记录仓储实现
+type notificationAttemptRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewNotificationAttemptRepository 创建通知投递记录仓储实例
+func NewNotificationAttemptRepository(db *sqlx.DB) NotificationAttemptRepository {
+	return &notificationAttemptRepository{db: db}
+}
+
+// NewNotificationAttemptRepositoryWithTx 创建带事务的通知投递记录仓储实例
+func NewNotificationAttemptRepositoryWithTx(tx *sqlx.Tx) NotificationAttemptRepository {
+	return &notificationAttemptRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *notificationAttemptRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 保存通知投递记录
+func (r *notificationAttemptRepository) Create(ctx context.Context, attempt *models.NotificationAttempt) error {
+	if attempt.ID == "" {
+		attempt.ID = uuid.New().String()
+	}
+	if attempt.AttemptedAt.IsZero() {
+		attempt.AttemptedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO notification_attempts (id, notification_id, alert_id, channel, target, payload_hash, status,
+			provider_response, error, duration_ms, resent_by, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		attempt.ID, attempt.NotificationID, attempt.AlertID, attempt.Channel, attempt.Target, attempt.PayloadHash, attempt.Status,
+		attempt.ProviderResponse, attempt.Error, attempt.DurationMs, attempt.ResentBy, attempt.AttemptedAt,
+	); err != nil {
+		return fmt.Errorf("保存通知投递记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListByNotification 获取通知的投递记录，按投递时间正序
+func (r *notificationAttemptRepository) ListByNotification(ctx context.Context, notificationID string) ([]*models.NotificationAttempt, error) {
+	query := `SELECT ` + notificationAttemptColumns + ` FROM notification_attempts WHERE notification_id = $1 ORDER BY attempted_at`
+
+	attempts := make([]*models.NotificationAttempt, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &attempts, query, notificationID); err != nil {
+		return nil, fmt.Errorf("获取通知投递记录失败: %w", err)
+	}
+	return attempts, nil
+}
+
+// ListByAlert 获取告警关联的全部通知的投递记录，按投递时间正序
+func (r *notificationAttemptRepository) ListByAlert(ctx context.Context, alertID string) ([]*models.NotificationAttempt, error) {
+	query := `SELECT ` + notificationAttemptColumns + ` FROM notification_attempts WHERE alert_id = $1 ORDER BY attempted_at`
+
+	attempts := make([]*models.NotificationAttempt, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &attempts, query, alertID); err != nil {
+		return nil, fmt.Errorf("获取告警的通知投递记录失败: %w", err)
+	}
+	return attempts, nil
+}
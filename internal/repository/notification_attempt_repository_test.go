@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestNotificationAttemptRepository_CreateAndListByAlert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewNotificationAttemptRepository(sqlx.NewDb(db, "postgres"))
+	alertID := "a1"
+	response := "HTTP 502: bad gateway"
+	attempt := &models.NotificationAttempt{
+		NotificationID: "n1", AlertID: &alertID, Channel: models.NotificationTypeWebhook,
+		Target: "https://hooks.example.com/pulse", PayloadHash: "abc", Status: models.NotificationAttemptFailed,
+		ProviderResponse: &response, DurationMs: 120,
+	}
+
+	mock.ExpectExec(`INSERT INTO notification_attempts`).
+		WithArgs(sqlmock.AnyArg(), "n1", &alertID, models.NotificationTypeWebhook, "https://hooks.example.com/pulse", "abc",
+			models.NotificationAttemptFailed, &response, nil, int64(120), nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Create(context.Background(), attempt))
+	assert.NotEmpty(t, attempt.ID)
+	assert.False(t, attempt.AttemptedAt.IsZero())
+
+	mock.ExpectQuery(`FROM notification_attempts WHERE alert_id = \$1 ORDER BY attempted_at`).
+		WithArgs(alertID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "notification_id", "alert_id", "channel", "target", "payload_hash", "status",
+			"provider_response", "error", "duration_ms", "resent_by", "attempted_at"}).
+			AddRow(attempt.ID, "n1", alertID, "webhook", "https://hooks.example.com/pulse", "abc", "failed", response, nil, 120, nil, time.Now()))
+
+	attempts, err := repo.ListByAlert(context.Background(), alertID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, models.NotificationAttemptFailed, attempts[0].Status)
+	assert.Equal(t, response, *attempts[0].ProviderResponse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+const notificationChannelColumns = `id, name, team, type, url, secret, smtp, enabled, created_by, created_at, updated_at`
+
+// notificationChannelRepository 团队通知渠道仓储实现，渠道地址和密钥加密存储
+type notificationChannelRepository struct {
+	db                *sqlx.DB
+	tx                *sqlx.Tx
+	encryptionService crypto.EncryptionService
+}
+
+// NewNotificationChannelRepository 创建团队通知渠道仓储实例
+func NewNotificationChannelRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) NotificationChannelRepository {
+	return &notificationChannelRepository{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// NewNotificationChannelRepositoryWithTx 创建带事务的团队通知渠道仓储实例
+func NewNotificationChannelRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) NotificationChannelRepository {
+	return &notificationChannelRepository{
+		tx:                tx,
+		encryptionService: encryptionService,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *notificationChannelRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 创建通知渠道，地址和密钥加密后存储
+func (r *notificationChannelRepository) Create(ctx context.Context, channel *models.NotificationChannel) error {
+	if channel.ID == "" {
+		channel.ID = uuid.New().String()
+	}
+	now := time.Now()
+	channel.CreatedAt = now
+	channel.UpdatedAt = now
+
+	encryptedURL, secret, smtp, err := r.encode(channel)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO notification_channels (
+			id, name, team, type, url, secret, smtp, enabled, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		channel.ID, channel.Name, channel.Team, channel.Type, encryptedURL, secret, smtp, channel.Enabled,
+		channel.CreatedBy, channel.CreatedAt, channel.UpdatedAt,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrNotificationChannelExists
+		}
+		return fmt.Errorf("创建通知渠道失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取通知渠道，返回的地址和密钥为明文
+func (r *notificationChannelRepository) GetByID(ctx context.Context, id string) (*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE id = $1`
+
+	channel, err := r.scan(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotificationChannelNotFound
+		}
+		return nil, fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+	return channel, nil
+}
+
+// FindEnabled 获取团队最早创建的启用的指定类型渠道，返回的地址和密钥为明文
+func (r *notificationChannelRepository) FindEnabled(ctx context.Context, team string, channelType models.NotificationType) (*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels
+		WHERE team = $1 AND type = $2 AND enabled = TRUE
+		ORDER BY created_at
+		LIMIT 1`
+
+	channel, err := r.scan(r.getExecutor().QueryRowxContext(ctx, query, team, channelType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotificationChannelNotFound
+		}
+		return nil, fmt.Errorf("获取团队通知渠道失败: %w", err)
+	}
+	return channel, nil
+}
+
+// List 按团队和类型获取通知渠道，返回的地址和密钥为明文
+func (r *notificationChannelRepository) List(ctx context.Context, filter *models.NotificationChannelFilter) ([]*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE 1=1`
+	var args []interface{}
+	if filter != nil && filter.Team != "" {
+		args = append(args, filter.Team)
+		query += fmt.Sprintf(" AND team = $%d", len(args))
+	}
+	if filter != nil && filter.Type != "" {
+		args = append(args, filter.Type)
+		query += fmt.Sprintf(" AND type = $%d", len(args))
+	}
+	query += " ORDER BY team, type, created_at"
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知渠道列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	channels := make([]*models.NotificationChannel, 0)
+	for rows.Next() {
+		channel, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描通知渠道失败: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+// Update 更新通知渠道，地址和密钥重新加密
+func (r *notificationChannelRepository) Update(ctx context.Context, channel *models.NotificationChannel) error {
+	channel.UpdatedAt = time.Now()
+
+	encryptedURL, secret, smtp, err := r.encode(channel)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE notification_channels SET
+			name = $1,
+			team = $2,
+			type = $3,
+			url = $4,
+			secret = $5,
+			smtp = $6,
+			enabled = $7,
+			updated_at = $8
+		WHERE id = $9`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		channel.Name, channel.Team, channel.Type, encryptedURL, secret, smtp, channel.Enabled, channel.UpdatedAt, channel.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrNotificationChannelExists
+		}
+		return fmt.Errorf("更新通知渠道失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrNotificationChannelNotFound
+	}
+	return nil
+}
+
+// Delete 删除通知渠道
+func (r *notificationChannelRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除通知渠道失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrNotificationChannelNotFound
+	}
+	return nil
+}
+
+// RotateEncryptionKeys 将所有渠道的地址和密钥重新加密为当前主密钥版本
+// 按ID分批处理；更新时校验地址和密钥未被并发修改，被修改的渠道跳过
+func (r *notificationChannelRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptionService.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+	exec := r.getExecutor()
+
+	selectQuery := `
+		SELECT id, url, secret
+		FROM notification_channels
+		WHERE id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `UPDATE notification_channels SET url = $1, secret = $2 WHERE id = $3 AND url = $4 AND secret = $5`
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID     string `db:"id"`
+			URL    string `db:"url"`
+			Secret string `db:"secret"`
+		}
+		if err := sqlx.SelectContext(ctx, exec, &rows, selectQuery, lastID, batchSize); err != nil {
+			return nil, fmt.Errorf("查询通知渠道失败: %w", err)
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			rotatedURL, urlChanged, err := rotator.Rotate(row.URL)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			secret, secretChanged, err := rotator.Rotate(row.Secret)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			if !urlChanged && !secretChanged {
+				continue
+			}
+
+			res, err := exec.ExecContext(ctx, updateQuery, rotatedURL, secret, row.ID, row.URL, row.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("更新通知渠道密钥失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// encode 加密渠道的地址和密钥，序列化 SMTP 配置
+// 未配置 SMTP 时返回 nil，写入 NULL
+func (r *notificationChannelRepository) encode(channel *models.NotificationChannel) (encryptedURL, secret string, smtp interface{}, err error) {
+	if encryptedURL, err = r.encryptionService.Encrypt(channel.URL); err != nil {
+		return "", "", nil, fmt.Errorf("加密渠道地址失败: %w", err)
+	}
+	if secret, err = r.encryptionService.Encrypt(channel.Secret); err != nil {
+		return "", "", nil, fmt.Errorf("加密渠道密钥失败: %w", err)
+	}
+	if channel.SMTP != nil {
+		data, err := json.Marshal(channel.SMTP)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("序列化SMTP配置失败: %w", err)
+		}
+		smtp = data
+	}
+	return encryptedURL, secret, smtp, nil
+}
+
+// scan 扫描通知渠道并解密地址和密钥
+func (r *notificationChannelRepository) scan(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	var smtp []byte
+
+	if err := scanner.Scan(
+		&channel.ID, &channel.Name, &channel.Team, &channel.Type, &channel.URL, &channel.Secret, &smtp,
+		&channel.Enabled, &channel.CreatedBy, &channel.CreatedAt, &channel.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(smtp) > 0 {
+		if err := json.Unmarshal(smtp, &channel.SMTP); err != nil {
+			return nil, fmt.Errorf("反序列化SMTP配置失败: %w", err)
+		}
+	}
+	var err error
+	if channel.URL, err = r.encryptionService.Decrypt(channel.URL); err != nil {
+		return nil, fmt.Errorf("解密渠道地址失败: %w", err)
+	}
+	if channel.Secret, err = r.encryptionService.Decrypt(channel.Secret); err != nil {
+		return nil, fmt.Errorf("解密渠道密钥失败: %w", err)
+	}
+	return &channel, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+func TestNotificationChannelRepository_SecretsEncrypted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	encryption := crypto.NewAESEncryptionService("test-key")
+	repo := NewNotificationChannelRepository(sqlx.NewDb(db, "postgres"), encryption)
+
+	channel := &models.NotificationChannel{
+		Name:    "支付机器人",
+		Team:    "payments",
+		Type:    models.NotificationTypeDingTalk,
+		URL:     "https://oapi.dingtalk.com/robot/send?access_token=t",
+		Secret:  "plain-secret",
+		Enabled: true,
+	}
+
+	mock.ExpectExec(`INSERT INTO notification_channels`).
+		WithArgs(sqlmock.AnyArg(), "支付机器人", "payments", models.NotificationTypeDingTalk,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), nil, true, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), channel))
+	assert.NotEmpty(t, channel.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 地址和密钥加密存储，读取时解密
+	storedURL, err := encryption.Encrypt(channel.URL)
+	require.NoError(t, err)
+	storedSecret, err := encryption.Encrypt("plain-secret")
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM notification_channels\s+WHERE team = \$1 AND type = \$2 AND enabled = TRUE`).
+		WithArgs("payments", models.NotificationTypeDingTalk).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "team", "type", "url", "secret", "smtp", "enabled", "created_by", "created_at", "updated_at",
+		}).AddRow(channel.ID, "支付机器人", "payments", "dingtalk", storedURL, storedSecret, nil, true, nil, now, now))
+
+	got, err := repo.FindEnabled(context.Background(), "payments", models.NotificationTypeDingTalk)
+	require.NoError(t, err)
+	assert.Equal(t, channel.URL, got.URL)
+	assert.Equal(t, "plain-secret", got.Secret)
+	assert.Nil(t, got.SMTP)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationChannelRepository_FindEnabled_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewNotificationChannelRepository(sqlx.NewDb(db, "postgres"), crypto.NewAESEncryptionService("test-key"))
+
+	mock.ExpectQuery(`SELECT .+ FROM notification_channels`).
+		WithArgs("search", models.NotificationTypeSlack).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.FindEnabled(context.Background(), "search", models.NotificationTypeSlack)
+	assert.ErrorIs(t, err, models.ErrNotificationChannelNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,7 +15,7 @@ import (
 
 // 错误定义
 var (
-	ErrNotificationTemplateNotFound = errors.New("notification template not found")
+	ErrNotificationTemplateNotFound = models.ErrNotificationTemplateNotFound
 )
 
 // notificationRepository 通知仓储实现
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// notificationRoutingRepository 通知路由仓储实现，包含通知日历和延迟通知
+type notificationRoutingRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewNotificationRoutingRepository 创建通知路由仓储实例
+func NewNotificationRoutingRepository(db *sqlx.DB) NotificationRoutingRepository {
+	return &notificationRoutingRepository{db: db}
+}
+
+// NewNotificationRoutingRepositoryWithTx 创建带事务的通知路由仓储实例
+func NewNotificationRoutingRepositoryWithTx(tx *sqlx.Tx) NotificationRoutingRepository {
+	return &notificationRoutingRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *notificationRoutingRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const notificationCalendarColumns = `id, name, team, region, business_hours, holidays, defer_severities,
+	created_by, created_at, updated_at`
+
+const deferredNotificationColumns = `id, calendar_id, alert_id, type, recipient, subject, content, deliver_at, created_at`
+
+// CreateCalendar 创建通知日历
+func (r *notificationRoutingRepository) CreateCalendar(ctx context.Context, calendar *models.NotificationCalendar) error {
+	if calendar.ID == "" {
+		calendar.ID = uuid.New().String()
+	}
+
+	businessHours, holidays, severities, err := marshalNotificationCalendar(calendar)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	calendar.CreatedAt = now
+	calendar.UpdatedAt = now
+
+	query := `
+		INSERT INTO notification_calendars (` + notificationCalendarColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		calendar.ID, calendar.Name, calendar.Team, calendar.Region, businessHours, holidays, severities,
+		calendar.CreatedBy, calendar.CreatedAt, calendar.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrNotificationCalendarExists
+		}
+		return fmt.Errorf("创建通知日历失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetCalendar 根据ID获取通知日历
+func (r *notificationRoutingRepository) GetCalendar(ctx context.Context, id string) (*models.NotificationCalendar, error) {
+	query := `SELECT ` + notificationCalendarColumns + ` FROM notification_calendars WHERE id = $1`
+	calendar, err := scanNotificationCalendar(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotificationCalendarNotFound
+		}
+		return nil, fmt.Errorf("获取通知日历失败: %w", err)
+	}
+	return calendar, nil
+}
+
+// ListCalendars 获取全部通知日历，按名称排序
+func (r *notificationRoutingRepository) ListCalendars(ctx context.Context) ([]*models.NotificationCalendar, error) {
+	query := `SELECT ` + notificationCalendarColumns + ` FROM notification_calendars ORDER BY name`
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知日历列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	calendars := make([]*models.NotificationCalendar, 0)
+	for rows.Next() {
+		calendar, err := scanNotificationCalendar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描通知日历失败: %w", err)
+		}
+		calendars = append(calendars, calendar)
+	}
+
+	return calendars, rows.Err()
+}
+
+// UpdateCalendar 更新通知日历
+func (r *notificationRoutingRepository) UpdateCalendar(ctx context.Context, calendar *models.NotificationCalendar) error {
+	businessHours, holidays, severities, err := marshalNotificationCalendar(calendar)
+	if err != nil {
+		return err
+	}
+
+	calendar.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE notification_calendars SET
+			name = $2, team = $3, region = $4, business_hours = $5, holidays = $6, defer_severities = $7,
+			updated_at = $8
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		calendar.ID, calendar.Name, calendar.Team, calendar.Region, businessHours, holidays, severities,
+		calendar.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrNotificationCalendarExists
+		}
+		return fmt.Errorf("更新通知日历失败: %w", err)
+	}
+
+	return checkNotificationCalendarAffected(result)
+}
+
+// DeleteCalendar 删除通知日历，日历下未发送的延迟通知一并删除
+func (r *notificationRoutingRepository) DeleteCalendar(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM notification_calendars WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除通知日历失败: %w", err)
+	}
+
+	return checkNotificationCalendarAffected(result)
+}
+
+// CreateDeferred 保存延迟通知
+func (r *notificationRoutingRepository) CreateDeferred(ctx context.Context, notification *models.DeferredNotification) error {
+	if notification.ID == "" {
+		notification.ID = uuid.New().String()
+	}
+	notification.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO deferred_notifications (` + deferredNotificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		notification.ID, notification.CalendarID, notification.AlertID, notification.Type, notification.Recipient,
+		notification.Subject, notification.Content, notification.DeliverAt, notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("保存延迟通知失败: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueDeferred 获取发送时间已到的延迟通知，按接收人和创建时间排序
+func (r *notificationRoutingRepository) ListDueDeferred(ctx context.Context, now time.Time, limit int) ([]*models.DeferredNotification, error) {
+	query := `
+		SELECT ` + deferredNotificationColumns + `
+		FROM deferred_notifications
+		WHERE deliver_at <= $1
+		ORDER BY type, recipient, created_at
+		LIMIT $2`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取延迟通知失败: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.DeferredNotification, 0)
+	for rows.Next() {
+		var n models.DeferredNotification
+		if err := rows.Scan(&n.ID, &n.CalendarID, &n.AlertID, &n.Type, &n.Recipient, &n.Subject, &n.Content,
+			&n.DeliverAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描延迟通知失败: %w", err)
+		}
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// DeleteDeferred 删除已汇总发送的延迟通知
+func (r *notificationRoutingRepository) DeleteDeferred(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM deferred_notifications WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("删除延迟通知失败: %w", err)
+	}
+
+	return nil
+}
+
+// marshalNotificationCalendar 序列化通知日历的 JSONB 字段
+func marshalNotificationCalendar(calendar *models.NotificationCalendar) (businessHours, holidays, severities []byte, err error) {
+	if businessHours, err = json.Marshal(calendar.BusinessHours); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化工作时间失败: %w", err)
+	}
+	if calendar.Holidays == nil {
+		calendar.Holidays = []string{}
+	}
+	if holidays, err = json.Marshal(calendar.Holidays); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化节假日失败: %w", err)
+	}
+	if calendar.DeferSeverities == nil {
+		calendar.DeferSeverities = []models.AlertSeverity{}
+	}
+	if severities, err = json.Marshal(calendar.DeferSeverities); err != nil {
+		return nil, nil, nil, fmt.Errorf("序列化延迟通知级别失败: %w", err)
+	}
+	return businessHours, holidays, severities, nil
+}
+
+// checkNotificationCalendarAffected 没有更新任何行时返回通知日历不存在
+func checkNotificationCalendarAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotificationCalendarNotFound
+	}
+
+	return nil
+}
+
+// scanNotificationCalendar 扫描通知日历
+func scanNotificationCalendar(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.NotificationCalendar, error) {
+	var calendar models.NotificationCalendar
+	var businessHours, holidays, severities []byte
+
+	err := scanner.Scan(
+		&calendar.ID, &calendar.Name, &calendar.Team, &calendar.Region, &businessHours, &holidays, &severities,
+		&calendar.CreatedBy, &calendar.CreatedAt, &calendar.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(businessHours, &calendar.BusinessHours); err != nil {
+		return nil, fmt.Errorf("解析工作时间失败: %w", err)
+	}
+	calendar.Holidays = []string{}
+	if len(holidays) > 0 {
+		if err := json.Unmarshal(holidays, &calendar.Holidays); err != nil {
+			return nil, fmt.Errorf("解析节假日失败: %w", err)
+		}
+	}
+	calendar.DeferSeverities = []models.AlertSeverity{}
+	if len(severities) > 0 {
+		if err := json.Unmarshal(severities, &calendar.DeferSeverities); err != nil {
+			return nil, fmt.Errorf("解析延迟通知级别失败: %w", err)
+		}
+	}
+
+	return &calendar, nil
+}
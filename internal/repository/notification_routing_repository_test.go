@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestNotificationRoutingRepository_GetCalendar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewNotificationRoutingRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM notification_calendars WHERE id = \$1`).
+		WithArgs("c1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "team", "region", "business_hours", "holidays",
+			"defer_severities", "created_by", "created_at", "updated_at"}).
+			AddRow("c1", "支付团队", "payments", "cn", []byte(`{"timezone":"Asia/Shanghai","days":["monday"],"start":"09:00","end":"18:00"}`),
+				[]byte(`["2024-10-01"]`), []byte(`["low","medium"]`), "u1", now, now))
+
+	calendar, err := repo.GetCalendar(context.Background(), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Shanghai", calendar.BusinessHours.Timezone)
+	assert.Equal(t, []string{"2024-10-01"}, calendar.Holidays)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityLow, models.AlertSeverityMedium}, calendar.DeferSeverities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRoutingRepository_CreateCalendarConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewNotificationRoutingRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO notification_calendars`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.CreateCalendar(context.Background(), &models.NotificationCalendar{Name: "支付团队", Team: "payments"})
+	assert.ErrorIs(t, err, models.ErrNotificationCalendarExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRoutingRepository_DeleteDeferred(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewNotificationRoutingRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`DELETE FROM deferred_notifications WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"d1", "d2"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, repo.DeleteDeferred(context.Background(), []string{"d1", "d2"}))
+	require.NoError(t, repo.DeleteDeferred(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
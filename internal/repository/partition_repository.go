@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// partitionRepository 分区仓储实现，管理告警表和告警历史表的月分区
+type partitionRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewPartitionRepository 创建分区仓储实例
+func NewPartitionRepository(db *sqlx.DB) PartitionRepository {
+	return &partitionRepository{db: db}
+}
+
+// NewPartitionRepositoryWithTx 创建带事务的分区仓储实例
+func NewPartitionRepositoryWithTx(tx *sqlx.Tx) PartitionRepository {
+	return &partitionRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *partitionRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 创建月分区，分区已存在时返回 false
+func (r *partitionRepository) Create(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	var exists bool
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &exists, `SELECT to_regclass($1) IS NOT NULL`, partition.Name); err != nil {
+		return false, fmt.Errorf("检查分区 %s 失败: %w", partition.Name, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	// DDL 不支持参数，分区范围由月份生成
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+		pq.QuoteIdentifier(partition.Name), pq.QuoteIdentifier(partition.Table),
+		partition.From.Format("2006-01-02"), partition.To.Format("2006-01-02"))
+	if _, err := r.getExecutor().ExecContext(ctx, query); err != nil {
+		return false, fmt.Errorf("创建分区 %s 失败: %w", partition.Name, err)
+	}
+	return true, nil
+}
+
+// List 获取表的全部分区，按分区名排序
+func (r *partitionRepository) List(ctx context.Context, table string) ([]*models.TablePartition, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass($1)
+		ORDER BY c.relname`
+
+	var names []string
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &names, query, table); err != nil {
+		return nil, fmt.Errorf("获取 %s 的分区失败: %w", table, err)
+	}
+
+	partitions := make([]*models.TablePartition, 0, len(names))
+	for _, name := range names {
+		if partition, ok := models.ParseTablePartition(table, name); ok {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions, nil
+}
+
+// Drop 删除分区及其中的全部记录
+func (r *partitionRepository) Drop(ctx context.Context, partition *models.TablePartition) error {
+	if _, err := r.getExecutor().ExecContext(ctx, `DROP TABLE IF EXISTS `+pq.QuoteIdentifier(partition.Name)); err != nil {
+		return fmt.Errorf("删除分区 %s 失败: %w", partition.Name, err)
+	}
+	return nil
+}
+
+// HasActiveAlerts 判断告警分区中是否有未解决的告警
+func (r *partitionRepository) HasActiveAlerts(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE status <> $1 AND deleted_at IS NULL)`,
+		pq.QuoteIdentifier(partition.Name))
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &exists, query, models.AlertStatusResolved); err != nil {
+		return false, fmt.Errorf("检查分区 %s 中的未解决告警失败: %w", partition.Name, err)
+	}
+	return exists, nil
+}
+
+// PurgeInactiveAlerts 删除告警分区中已解决和已删除的告警，返回删除的数量
+func (r *partitionRepository) PurgeInactiveAlerts(ctx context.Context, partition *models.TablePartition) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE status = $1 OR deleted_at IS NOT NULL`, pq.QuoteIdentifier(partition.Name))
+
+	result, err := r.getExecutor().ExecContext(ctx, query, models.AlertStatusResolved)
+	if err != nil {
+		return 0, fmt.Errorf("清理分区 %s 中的告警失败: %w", partition.Name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取清理行数失败: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// HasRows 判断分区中是否还有记录
+func (r *partitionRepository) HasRows(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, pq.QuoteIdentifier(partition.Name))
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &exists, query); err != nil {
+		return false, fmt.Errorf("检查分区 %s 中的记录失败: %w", partition.Name, err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestPartitionRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPartitionRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	partition := models.MonthlyPartition(models.PartitionedTableAlerts, time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC))
+
+	mock.ExpectQuery(`SELECT to_regclass\(\$1\) IS NOT NULL`).
+		WithArgs("alerts_p202412").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "alerts_p202412" PARTITION OF "alerts" FOR VALUES FROM \('2024-12-01'\) TO \('2025-01-01'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	created, err := repo.Create(ctx, partition)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// 已存在的分区不再创建
+	mock.ExpectQuery(`SELECT to_regclass\(\$1\) IS NOT NULL`).
+		WithArgs("alerts_p202412").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	created, err = repo.Create(ctx, partition)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPartitionRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPartitionRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`SELECT c.relname\s+FROM pg_inherits i`).
+		WithArgs("alert_histories").
+		WillReturnRows(sqlmock.NewRows([]string{"relname"}).
+			AddRow("alert_histories_default").
+			AddRow("alert_histories_p202401").
+			AddRow("alert_histories_archive"))
+
+	partitions, err := repo.List(context.Background(), models.PartitionedTableAlertHistories)
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+	assert.True(t, partitions[0].Default)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), partitions[1].From)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), partitions[1].To)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -50,7 +50,7 @@ func (r *permissionRepository) CheckPermission(ctx context.Context, userID strin
 	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return false, fmt.Errorf("用户不存在")
+			return false, models.ErrUserNotFound
 		}
 		return false, fmt.Errorf("获取用户信息失败: %w", err)
 	}
@@ -108,7 +108,7 @@ func (r *permissionRepository) GetUserPermissions(ctx context.Context, userID st
 	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("用户不存在")
+			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("获取用户信息失败: %w", err)
 	}
@@ -207,7 +207,7 @@ func (r *permissionRepository) GetPermissionGroup(ctx context.Context, id string
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("权限组不存在")
+			return nil, models.ErrPermissionGroupNotFound
 		}
 		return nil, fmt.Errorf("获取权限组失败: %w", err)
 	}
@@ -261,7 +261,7 @@ func (r *permissionRepository) UpdatePermissionGroup(ctx context.Context, group
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("权限组不存在或已被删除")
+		return models.ErrPermissionGroupNotFound
 	}
 
 	return nil
@@ -287,7 +287,7 @@ func (r *permissionRepository) DeletePermissionGroup(ctx context.Context, id str
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("权限组不存在或已被删除")
+		return models.ErrPermissionGroupNotFound
 	}
 
 	return nil
@@ -395,7 +395,7 @@ func (r *permissionRepository) GetPermissionOverride(ctx context.Context, id str
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("权限覆盖不存在")
+			return nil, models.ErrPermissionOverrideNotFound
 		}
 		return nil, fmt.Errorf("获取权限覆盖失败: %w", err)
 	}
@@ -437,7 +437,7 @@ func (r *permissionRepository) UpdatePermissionOverride(ctx context.Context, ove
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("权限覆盖不存在或已被删除")
+		return models.ErrPermissionOverrideNotFound
 	}
 
 	return nil
@@ -463,7 +463,7 @@ func (r *permissionRepository) DeletePermissionOverride(ctx context.Context, id
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("权限覆盖不存在或已被删除")
+		return models.ErrPermissionOverrideNotFound
 	}
 
 	return nil
@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// postmortemRepository 事件复盘仓储实现
+type postmortemRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewPostmortemRepository 创建事件复盘仓储实例
+func NewPostmortemRepository(db *sqlx.DB) PostmortemRepository {
+	return &postmortemRepository{db: db}
+}
+
+// NewPostmortemRepositoryWithTx 创建带事务的事件复盘仓储实例
+func NewPostmortemRepositoryWithTx(tx *sqlx.Tx) PostmortemRepository {
+	return &postmortemRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *postmortemRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const postmortemColumns = `id, title, status, template, incident_id, alert_ids, summary, impact, root_cause,
+		       timeline, incident_started_at, incident_ended_at, knowledge_id, published_at,
+		       created_by, updated_by, created_at, updated_at`
+
+const postmortemActionItemColumns = `id, postmortem_id, title, description, priority, assignee_id, due_date,
+		       ticket_id, status, completed_at, created_at, updated_at`
+
+// Create 创建复盘
+func (r *postmortemRepository) Create(ctx context.Context, postmortem *models.Postmortem) error {
+	if postmortem.ID == "" {
+		postmortem.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	postmortem.CreatedAt = now
+	postmortem.UpdatedAt = now
+
+	timeline, err := marshalPostmortemTimeline(postmortem.Timeline)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO postmortems (
+			id, title, status, template, incident_id, alert_ids, summary, impact, root_cause,
+			timeline, incident_started_at, incident_ended_at, knowledge_id, published_at,
+			created_by, updated_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		postmortem.ID, postmortem.Title, postmortem.Status, postmortem.Template, postmortem.IncidentID,
+		pq.Array(postmortem.AlertIDs), postmortem.Summary, postmortem.Impact, postmortem.RootCause,
+		timeline, postmortem.IncidentStartedAt, postmortem.IncidentEndedAt, postmortem.KnowledgeID,
+		postmortem.PublishedAt, postmortem.CreatedBy, postmortem.UpdatedBy,
+		postmortem.CreatedAt, postmortem.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建复盘失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取复盘
+func (r *postmortemRepository) GetByID(ctx context.Context, id string) (*models.Postmortem, error) {
+	query := `
+		SELECT ` + postmortemColumns + `
+		FROM postmortems
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	postmortem, err := scanPostmortem(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrPostmortemNotFound
+		}
+		return nil, fmt.Errorf("获取复盘失败: %w", err)
+	}
+
+	return postmortem, nil
+}
+
+// List 分页获取复盘列表
+func (r *postmortemRepository) List(ctx context.Context, filter *models.PostmortemFilter) (*models.PostmortemList, error) {
+	where := ` WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	argIndex := 0
+
+	if filter.Status != nil {
+		argIndex++
+		where += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, *filter.Status)
+	}
+
+	if filter.IncidentID != nil {
+		argIndex++
+		where += fmt.Sprintf(" AND incident_id = $%d", argIndex)
+		args = append(args, *filter.IncidentID)
+	}
+
+	if filter.AlertID != nil {
+		argIndex++
+		where += fmt.Sprintf(" AND $%d = ANY(alert_ids)", argIndex)
+		args = append(args, *filter.AlertID)
+	}
+
+	var total int64
+	if err := r.getExecutor().QueryRowxContext(ctx, `SELECT COUNT(*) FROM postmortems`+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("获取复盘总数失败: %w", err)
+	}
+
+	query := `SELECT ` + postmortemColumns + ` FROM postmortems` + where + ` ORDER BY created_at DESC`
+	if filter.PageSize > 0 {
+		argIndex++
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filter.PageSize)
+
+		if filter.Page > 0 {
+			argIndex++
+			query += fmt.Sprintf(" OFFSET $%d", argIndex)
+			args = append(args, (filter.Page-1)*filter.PageSize)
+		}
+	}
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询复盘列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	postmortems := make([]*models.Postmortem, 0)
+	for rows.Next() {
+		postmortem, err := scanPostmortem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描复盘失败: %w", err)
+		}
+		postmortems = append(postmortems, postmortem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历复盘列表失败: %w", err)
+	}
+
+	return &models.PostmortemList{
+		Postmortems: postmortems,
+		Total:       total,
+		Page:        filter.Page,
+		PageSize:    filter.PageSize,
+	}, nil
+}
+
+// Update 更新复盘，创建人和创建时间不可修改
+func (r *postmortemRepository) Update(ctx context.Context, postmortem *models.Postmortem) error {
+	postmortem.UpdatedAt = time.Now()
+
+	timeline, err := marshalPostmortemTimeline(postmortem.Timeline)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE postmortems SET
+			title = $1,
+			status = $2,
+			alert_ids = $3,
+			summary = $4,
+			impact = $5,
+			root_cause = $6,
+			timeline = $7,
+			incident_started_at = $8,
+			incident_ended_at = $9,
+			knowledge_id = $10,
+			published_at = $11,
+			updated_by = $12,
+			updated_at = $13
+		WHERE id = $14 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		postmortem.Title, postmortem.Status, pq.Array(postmortem.AlertIDs), postmortem.Summary,
+		postmortem.Impact, postmortem.RootCause, timeline, postmortem.IncidentStartedAt,
+		postmortem.IncidentEndedAt, postmortem.KnowledgeID, postmortem.PublishedAt,
+		postmortem.UpdatedBy, postmortem.UpdatedAt, postmortem.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新复盘失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPostmortemNotFound
+	}
+
+	return nil
+}
+
+// Delete 软删除复盘
+func (r *postmortemRepository) Delete(ctx context.Context, id string) error {
+	query := `UPDATE postmortems SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除复盘失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPostmortemNotFound
+	}
+
+	return nil
+}
+
+// CreateActionItem 创建复盘改进项
+func (r *postmortemRepository) CreateActionItem(ctx context.Context, item *models.PostmortemActionItem) error {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	query := `
+		INSERT INTO postmortem_action_items (
+			id, postmortem_id, title, description, priority, assignee_id, due_date,
+			ticket_id, status, completed_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		item.ID, item.PostmortemID, item.Title, item.Description, item.Priority, item.AssigneeID,
+		item.DueDate, item.TicketID, item.Status, item.CompletedAt, item.CreatedAt, item.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建复盘改进项失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetActionItem 获取复盘下的改进项
+func (r *postmortemRepository) GetActionItem(ctx context.Context, postmortemID, itemID string) (*models.PostmortemActionItem, error) {
+	query := `
+		SELECT ` + postmortemActionItemColumns + `
+		FROM postmortem_action_items
+		WHERE id = $1 AND postmortem_id = $2`
+
+	var item models.PostmortemActionItem
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &item, query, itemID, postmortemID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrPostmortemActionItemNotFound
+		}
+		return nil, fmt.Errorf("获取复盘改进项失败: %w", err)
+	}
+
+	return &item, nil
+}
+
+// ListActionItems 获取复盘的全部改进项
+func (r *postmortemRepository) ListActionItems(ctx context.Context, postmortemID string) ([]*models.PostmortemActionItem, error) {
+	query := `
+		SELECT ` + postmortemActionItemColumns + `
+		FROM postmortem_action_items
+		WHERE postmortem_id = $1
+		ORDER BY created_at`
+
+	items := make([]*models.PostmortemActionItem, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &items, query, postmortemID); err != nil {
+		return nil, fmt.Errorf("获取复盘改进项列表失败: %w", err)
+	}
+
+	return items, nil
+}
+
+// UpdateActionItem 更新复盘改进项
+func (r *postmortemRepository) UpdateActionItem(ctx context.Context, item *models.PostmortemActionItem) error {
+	item.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE postmortem_action_items SET
+			title = $1,
+			description = $2,
+			priority = $3,
+			assignee_id = $4,
+			due_date = $5,
+			ticket_id = $6,
+			status = $7,
+			completed_at = $8,
+			updated_at = $9
+		WHERE id = $10 AND postmortem_id = $11`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		item.Title, item.Description, item.Priority, item.AssigneeID, item.DueDate, item.TicketID,
+		item.Status, item.CompletedAt, item.UpdatedAt, item.ID, item.PostmortemID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新复盘改进项失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPostmortemActionItemNotFound
+	}
+
+	return nil
+}
+
+// DeleteActionItem 删除复盘改进项
+func (r *postmortemRepository) DeleteActionItem(ctx context.Context, postmortemID, itemID string) error {
+	query := `DELETE FROM postmortem_action_items WHERE id = $1 AND postmortem_id = $2`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, itemID, postmortemID)
+	if err != nil {
+		return fmt.Errorf("删除复盘改进项失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPostmortemActionItemNotFound
+	}
+
+	return nil
+}
+
+// marshalPostmortemTimeline 序列化复盘时间线，空时间线存储为空数组
+func marshalPostmortemTimeline(timeline []models.PostmortemTimelineEntry) ([]byte, error) {
+	if timeline == nil {
+		timeline = []models.PostmortemTimelineEntry{}
+	}
+	data, err := json.Marshal(timeline)
+	if err != nil {
+		return nil, fmt.Errorf("序列化复盘时间线失败: %w", err)
+	}
+	return data, nil
+}
+
+// scanPostmortem 扫描复盘
+func scanPostmortem(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Postmortem, error) {
+	var postmortem models.Postmortem
+	var alertIDs pq.StringArray
+	var timeline []byte
+
+	err := scanner.Scan(
+		&postmortem.ID, &postmortem.Title, &postmortem.Status, &postmortem.Template,
+		&postmortem.IncidentID, &alertIDs, &postmortem.Summary, &postmortem.Impact,
+		&postmortem.RootCause, &timeline, &postmortem.IncidentStartedAt, &postmortem.IncidentEndedAt,
+		&postmortem.KnowledgeID, &postmortem.PublishedAt, &postmortem.CreatedBy, &postmortem.UpdatedBy,
+		&postmortem.CreatedAt, &postmortem.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	postmortem.AlertIDs = []string(alertIDs)
+	if postmortem.AlertIDs == nil {
+		postmortem.AlertIDs = []string{}
+	}
+
+	postmortem.Timeline = []models.PostmortemTimelineEntry{}
+	if len(timeline) > 0 {
+		if err := json.Unmarshal(timeline, &postmortem.Timeline); err != nil {
+			return nil, fmt.Errorf("解析复盘时间线失败: %w", err)
+		}
+	}
+
+	return &postmortem, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var postmortemTestColumns = []string{
+	"id", "title", "status", "template", "incident_id", "alert_ids", "summary", "impact", "root_cause",
+	"timeline", "incident_started_at", "incident_ended_at", "knowledge_id", "published_at",
+	"created_by", "updated_by", "created_at", "updated_at",
+}
+
+func TestPostmortemRepository_CreateAndGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPostmortemRepository(sqlx.NewDb(db, "postgres"))
+
+	postmortem := &models.Postmortem{
+		Title:     "支付服务中断",
+		Status:    models.PostmortemStatusDraft,
+		Template:  models.DefaultPostmortemTemplate,
+		AlertIDs:  []string{"a1"},
+		CreatedBy: "u1",
+	}
+
+	mock.ExpectExec(`INSERT INTO postmortems`).
+		WithArgs(sqlmock.AnyArg(), "支付服务中断", models.PostmortemStatusDraft, "standard", nil,
+			sqlmock.AnyArg(), "", "", "", []byte("[]"), nil, nil, nil, nil, "u1", nil,
+			sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), postmortem))
+	assert.NotEmpty(t, postmortem.ID)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM postmortems WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(postmortem.ID).
+		WillReturnRows(sqlmock.NewRows(postmortemTestColumns).AddRow(
+			postmortem.ID, "支付服务中断", "draft", "standard", nil, "{a1}", "", "", "",
+			[]byte(`[{"timestamp":"2024-01-01T10:00:00Z","description":"告警触发","source":"alert"}]`),
+			nil, nil, nil, nil, "u1", nil, now, now))
+
+	got, err := repo.GetByID(context.Background(), postmortem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, got.AlertIDs)
+	require.Len(t, got.Timeline, 1)
+	assert.Equal(t, "告警触发", got.Timeline[0].Description)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostmortemRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPostmortemRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`SELECT .+ FROM postmortems`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrPostmortemNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostmortemRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPostmortemRepository(sqlx.NewDb(db, "postgres"))
+
+	status := models.PostmortemStatusPublished
+	alertID := "a1"
+	filter := &models.PostmortemFilter{Status: &status, AlertID: &alertID, Page: 2, PageSize: 10}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM postmortems WHERE deleted_at IS NULL AND status = \$1 AND \$2 = ANY\(alert_ids\)`).
+		WithArgs(status, alertID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM postmortems WHERE .+ ORDER BY created_at DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(status, alertID, 10, 10).
+		WillReturnRows(sqlmock.NewRows(postmortemTestColumns).AddRow(
+			"p1", "数据库主从切换", "published", "standard", nil, "{a1,a2}", "", "", "",
+			[]byte(`[]`), nil, nil, "k1", now, "u1", nil, now, now))
+
+	list, err := repo.List(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), list.Total)
+	require.Len(t, list.Postmortems, 1)
+	assert.Equal(t, "k1", *list.Postmortems[0].KnowledgeID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostmortemRepository_ActionItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPostmortemRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT .+ FROM postmortem_action_items WHERE postmortem_id = \$1 ORDER BY created_at`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "postmortem_id", "title", "description", "priority", "assignee_id", "due_date",
+			"ticket_id", "status", "completed_at", "created_at", "updated_at",
+		}).AddRow("i1", "p1", "增加连接池监控", "", "high", nil, nil, "t1", "open", nil, time.Now(), time.Now()))
+
+	items, err := repo.ListActionItems(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "t1", *items[0].TicketID)
+
+	mock.ExpectExec(`UPDATE postmortem_action_items SET`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateActionItem(ctx, &models.PostmortemActionItem{ID: "missing", PostmortemID: "p1"})
+	assert.ErrorIs(t, err, models.ErrPostmortemActionItemNotFound)
+
+	mock.ExpectExec(`DELETE FROM postmortem_action_items WHERE id = \$1 AND postmortem_id = \$2`).
+		WithArgs("i1", "p1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.DeleteActionItem(ctx, "p1", "i1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// pushRepository 移动端推送仓储实现
+type pushRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewPushRepository 创建移动端推送仓储实例
+func NewPushRepository(db *sqlx.DB) PushRepository {
+	return &pushRepository{db: db}
+}
+
+// NewPushRepositoryWithTx 创建带事务的移动端推送仓储实例
+func NewPushRepositoryWithTx(tx *sqlx.Tx) PushRepository {
+	return &pushRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *pushRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// UpsertDevice 注册推送设备，设备令牌已注册时转移到当前用户并更新平台、名称和最近活跃时间
+func (r *pushRepository) UpsertDevice(ctx context.Context, device *models.PushDevice) error {
+	now := time.Now()
+	if device.ID == "" {
+		device.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO push_devices (id, user_id, platform, token, name, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (token) DO UPDATE SET
+			user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, name = EXCLUDED.name,
+			last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id, created_at, last_seen_at`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query,
+		device.ID, device.UserID, device.Platform, device.Token, device.Name, now,
+	).Scan(&device.ID, &device.CreatedAt, &device.LastSeenAt)
+	if err != nil {
+		return fmt.Errorf("注册推送设备失败: %w", err)
+	}
+	return nil
+}
+
+// ListDevices 获取用户的推送设备，按最近活跃时间倒序
+func (r *pushRepository) ListDevices(ctx context.Context, userID string) ([]*models.PushDevice, error) {
+	query := `
+		SELECT id, user_id, platform, token, name, created_at, last_seen_at
+		FROM push_devices
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC`
+
+	var devices []*models.PushDevice
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &devices, query, userID); err != nil {
+		return nil, fmt.Errorf("获取推送设备列表失败: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteDevice 删除用户的推送设备
+func (r *pushRepository) DeleteDevice(ctx context.Context, id, userID string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM push_devices WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("删除推送设备失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrPushDeviceNotFound
+	}
+	return nil
+}
+
+// DeleteDeviceByToken 删除设备令牌对应的推送设备，推送服务返回令牌失效时调用
+func (r *pushRepository) DeleteDeviceByToken(ctx context.Context, token string) error {
+	if _, err := r.getExecutor().ExecContext(ctx, `DELETE FROM push_devices WHERE token = $1`, token); err != nil {
+		return fmt.Errorf("删除推送设备失败: %w", err)
+	}
+	return nil
+}
+
+// GetPreference 获取用户的推送偏好
+func (r *pushRepository) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	query := `SELECT user_id, severities, updated_at FROM push_preferences WHERE user_id = $1`
+
+	pref := &models.PushPreference{}
+	var severities pq.StringArray
+	err := r.getExecutor().QueryRowxContext(ctx, query, userID).Scan(&pref.UserID, &severities, &pref.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrPushPreferenceNotFound
+		}
+		return nil, fmt.Errorf("获取推送偏好失败: %w", err)
+	}
+
+	pref.Severities = make([]models.AlertSeverity, 0, len(severities))
+	for _, severity := range severities {
+		pref.Severities = append(pref.Severities, models.AlertSeverity(severity))
+	}
+	return pref, nil
+}
+
+// SetPreference 设置用户的推送偏好，已设置时覆盖
+func (r *pushRepository) SetPreference(ctx context.Context, pref *models.PushPreference) error {
+	pref.UpdatedAt = time.Now()
+	severities := make([]string, 0, len(pref.Severities))
+	for _, severity := range pref.Severities {
+		severities = append(severities, string(severity))
+	}
+
+	query := `
+		INSERT INTO push_preferences (user_id, severities, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET severities = EXCLUDED.severities, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, pref.UserID, pq.Array(severities), pref.UpdatedAt); err != nil {
+		return fmt.Errorf("设置推送偏好失败: %w", err)
+	}
+	return nil
+}
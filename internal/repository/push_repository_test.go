@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestPushRepository_UpsertDevice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPushRepository(sqlx.NewDb(db, "postgres"))
+
+	// 设备令牌已注册时返回原设备的 ID 和注册时间
+	created := time.Now().Add(-24 * time.Hour)
+	mock.ExpectQuery(`INSERT INTO push_devices .+ON CONFLICT \(token\) DO UPDATE`).
+		WithArgs(sqlmock.AnyArg(), "u1", models.PushPlatformFCM, "android-1", "Pixel", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "last_seen_at"}).AddRow("d1", created, time.Now()))
+
+	device := &models.PushDevice{UserID: "u1", Platform: models.PushPlatformFCM, Token: "android-1", Name: "Pixel"}
+	require.NoError(t, repo.UpsertDevice(context.Background(), device))
+	assert.Equal(t, "d1", device.ID)
+	assert.True(t, device.CreatedAt.Equal(created))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPushRepository_Preference(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPushRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`SELECT user_id, severities, updated_at FROM push_preferences WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "severities", "updated_at"}).AddRow("u1", "{critical,high}", time.Now()))
+
+	pref, err := repo.GetPreference(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityCritical, models.AlertSeverityHigh}, pref.Severities)
+
+	mock.ExpectQuery(`SELECT user_id, severities, updated_at FROM push_preferences`).
+		WithArgs("u2").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "severities", "updated_at"}))
+
+	_, err = repo.GetPreference(context.Background(), "u2")
+	assert.ErrorIs(t, err, models.ErrPushPreferenceNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
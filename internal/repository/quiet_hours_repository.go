@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// quietHoursRepository 用户免打扰设置仓储实现
+type quietHoursRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewQuietHoursRepository 创建用户免打扰设置仓储实例
+func NewQuietHoursRepository(db *sqlx.DB) QuietHoursRepository {
+	return &quietHoursRepository{db: db}
+}
+
+// NewQuietHoursRepositoryWithTx 创建带事务的用户免打扰设置仓储实例
+func NewQuietHoursRepositoryWithTx(tx *sqlx.Tx) QuietHoursRepository {
+	return &quietHoursRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *quietHoursRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Get 获取用户的免打扰设置
+func (r *quietHoursRepository) Get(ctx context.Context, userID string) (*models.QuietHours, error) {
+	query := `
+		SELECT user_id, timezone, windows, dnd_until, dnd_allow_severities, updated_at
+		FROM user_quiet_hours
+		WHERE user_id = $1`
+
+	quiet := &models.QuietHours{}
+	var windows []byte
+	var severities pq.StringArray
+	err := r.getExecutor().QueryRowxContext(ctx, query, userID).Scan(
+		&quiet.UserID, &quiet.Timezone, &windows, &quiet.DNDUntil, &severities, &quiet.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrQuietHoursNotFound
+		}
+		return nil, fmt.Errorf("获取免打扰设置失败: %w", err)
+	}
+
+	if err := json.Unmarshal(windows, &quiet.Windows); err != nil {
+		return nil, fmt.Errorf("解析免打扰时段失败: %w", err)
+	}
+	quiet.DNDAllowSeverities = make([]models.AlertSeverity, 0, len(severities))
+	for _, severity := range severities {
+		quiet.DNDAllowSeverities = append(quiet.DNDAllowSeverities, models.AlertSeverity(severity))
+	}
+	return quiet, nil
+}
+
+// Set 保存用户的免打扰设置，已设置时覆盖
+func (r *quietHoursRepository) Set(ctx context.Context, quiet *models.QuietHours) error {
+	quiet.UpdatedAt = time.Now()
+	windows := quiet.Windows
+	if windows == nil {
+		windows = []models.QuietHoursWindow{}
+	}
+	windowsJSON, err := json.Marshal(windows)
+	if err != nil {
+		return fmt.Errorf("序列化免打扰时段失败: %w", err)
+	}
+	severities := make([]string, 0, len(quiet.DNDAllowSeverities))
+	for _, severity := range quiet.DNDAllowSeverities {
+		severities = append(severities, string(severity))
+	}
+
+	query := `
+		INSERT INTO user_quiet_hours (user_id, timezone, windows, dnd_until, dnd_allow_severities, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			timezone = EXCLUDED.timezone, windows = EXCLUDED.windows, dnd_until = EXCLUDED.dnd_until,
+			dnd_allow_severities = EXCLUDED.dnd_allow_severities, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		quiet.UserID, quiet.Timezone, windowsJSON, quiet.DNDUntil, pq.Array(severities), quiet.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("保存免打扰设置失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除用户的免打扰设置
+func (r *quietHoursRepository) Delete(ctx context.Context, userID string) error {
+	if _, err := r.getExecutor().ExecContext(ctx, `DELETE FROM user_quiet_hours WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("删除免打扰设置失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestQuietHoursRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewQuietHoursRepository(sqlx.NewDb(db, "postgres"))
+	columns := []string{"user_id", "timezone", "windows", "dnd_until", "dnd_allow_severities", "updated_at"}
+
+	mock.ExpectQuery(`FROM user_quiet_hours WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("u1", "Asia/Shanghai",
+			[]byte(`[{"days":["monday"],"start":"23:00","end":"07:00","allow_severities":["critical"]}]`),
+			nil, "{critical}", time.Now()))
+
+	quiet, err := repo.Get(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Len(t, quiet.Windows, 1)
+	assert.Equal(t, "23:00", quiet.Windows[0].Start)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityCritical}, quiet.Windows[0].AllowSeverities)
+	assert.Nil(t, quiet.DNDUntil)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityCritical}, quiet.DNDAllowSeverities)
+
+	mock.ExpectQuery(`FROM user_quiet_hours`).
+		WithArgs("u2").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.Get(context.Background(), "u2")
+	assert.ErrorIs(t, err, models.ErrQuietHoursNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuietHoursRepository_Set(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewQuietHoursRepository(sqlx.NewDb(db, "postgres"))
+
+	// 未配置时段时保存为空数组
+	mock.ExpectExec(`INSERT INTO user_quiet_hours .+ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs("u1", "", []byte(`[]`), nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Set(context.Background(), &models.QuietHours{UserID: "u1"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
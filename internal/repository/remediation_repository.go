@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// remediationActionTypes 视为修复动作的规则动作类型
+const remediationActionTypes = `('http', 'awx', 'salt')`
+
+// remediationRepository 修复动作执行记录仓储实现
+type remediationRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewRemediationRepository 创建修复动作执行记录仓储实例
+func NewRemediationRepository(db *sqlx.DB) RemediationRepository {
+	return &remediationRepository{db: db}
+}
+
+// NewRemediationRepositoryWithTx 创建带事务的修复动作执行记录仓储实例
+func NewRemediationRepositoryWithTx(tx *sqlx.Tx) RemediationRepository {
+	return &remediationRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *remediationRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const remediationExecutionColumns = `id, alert_id, rule_id, action_index, action_name, action_type, action,
+		       status, output, error, approved_by, approved_at, started_at, finished_at,
+		       created_at, updated_at`
+
+// ListAlertsToSchedule 获取 since 之后产生、规则配置了修复动作且尚未安排执行的触发中告警ID
+func (r *remediationRepository) ListAlertsToSchedule(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT a.id
+		FROM alerts a
+		JOIN rules r ON r.id = a.rule_id
+		WHERE a.status = 'firing' AND a.deleted_at IS NULL AND a.created_at >= $1
+		  AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements(r.actions) AS act
+			WHERE act->>'type' IN ` + remediationActionTypes + `
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM remediation_executions e WHERE e.alert_id = a.id
+		  )
+		ORDER BY a.created_at
+		LIMIT $2`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询待安排修复动作的告警失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("扫描告警ID失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Create 创建执行记录，同一告警的同一动作已存在时不重复创建并返回 false
+func (r *remediationRepository) Create(ctx context.Context, execution *models.RemediationExecution) (bool, error) {
+	if execution.ID == "" {
+		execution.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	execution.CreatedAt = now
+	execution.UpdatedAt = now
+
+	action, err := json.Marshal(execution.Action)
+	if err != nil {
+		return false, fmt.Errorf("序列化修复动作失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO remediation_executions (
+			id, alert_id, rule_id, action_index, action_name, action_type, action,
+			status, output, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (alert_id, rule_id, action_index) DO NOTHING`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		execution.ID, execution.AlertID, execution.RuleID, execution.ActionIndex, execution.ActionName,
+		execution.ActionType, action, execution.Status, execution.Output,
+		execution.CreatedAt, execution.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("创建修复动作执行记录失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取创建结果失败: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByID 根据ID获取执行记录
+func (r *remediationRepository) GetByID(ctx context.Context, id string) (*models.RemediationExecution, error) {
+	query := `
+		SELECT ` + remediationExecutionColumns + `
+		FROM remediation_executions
+		WHERE id = $1`
+
+	execution, err := scanRemediationExecution(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrRemediationNotFound
+		}
+		return nil, fmt.Errorf("获取修复动作执行记录失败: %w", err)
+	}
+
+	return execution, nil
+}
+
+// ListByAlert 获取告警的全部执行记录
+func (r *remediationRepository) ListByAlert(ctx context.Context, alertID string) ([]*models.RemediationExecution, error) {
+	query := `
+		SELECT ` + remediationExecutionColumns + `
+		FROM remediation_executions
+		WHERE alert_id = $1
+		ORDER BY created_at, action_index`
+
+	return r.queryExecutions(ctx, query, alertID)
+}
+
+// ClaimQueued 领取待执行的记录并标记为执行中，多实例部署时跳过已被其他实例锁定的记录
+func (r *remediationRepository) ClaimQueued(ctx context.Context, limit int) ([]*models.RemediationExecution, error) {
+	query := `
+		UPDATE remediation_executions SET
+			status = 'running',
+			started_at = $1,
+			updated_at = $1
+		WHERE id IN (
+			SELECT id FROM remediation_executions
+			WHERE status = 'queued'
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + remediationExecutionColumns
+
+	return r.queryExecutions(ctx, query, time.Now(), limit)
+}
+
+// Finish 记录执行结果
+func (r *remediationRepository) Finish(ctx context.Context, execution *models.RemediationExecution) error {
+	now := time.Now()
+	execution.FinishedAt = &now
+	execution.UpdatedAt = now
+
+	query := `
+		UPDATE remediation_executions SET
+			status = $1,
+			output = $2,
+			error = $3,
+			finished_at = $4,
+			updated_at = $5
+		WHERE id = $6`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		execution.Status, execution.Output, execution.Error, execution.FinishedAt,
+		execution.UpdatedAt, execution.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新修复动作执行结果失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrRemediationNotFound
+	}
+
+	return nil
+}
+
+// Decide 审批等待中的执行记录，批准后进入待执行队列，拒绝后结束
+func (r *remediationRepository) Decide(ctx context.Context, id string, approved bool, userID string) (*models.RemediationExecution, error) {
+	now := time.Now()
+	status := models.RemediationStatusQueued
+	var finishedAt *time.Time
+	if !approved {
+		status = models.RemediationStatusRejected
+		finishedAt = &now
+	}
+
+	query := `
+		UPDATE remediation_executions SET
+			status = $1,
+			approved_by = $2,
+			approved_at = $3,
+			finished_at = $4,
+			updated_at = $3
+		WHERE id = $5 AND status = 'pending_approval'
+		RETURNING ` + remediationExecutionColumns
+
+	execution, err := scanRemediationExecution(r.getExecutor().QueryRowxContext(ctx, query,
+		status, userID, now, finishedAt, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrRemediationNotPending
+		}
+		return nil, fmt.Errorf("审批修复动作失败: %w", err)
+	}
+
+	return execution, nil
+}
+
+// queryExecutions 查询并扫描执行记录列表
+func (r *remediationRepository) queryExecutions(ctx context.Context, query string, args ...interface{}) ([]*models.RemediationExecution, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询修复动作执行记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	executions := make([]*models.RemediationExecution, 0)
+	for rows.Next() {
+		execution, err := scanRemediationExecution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描修复动作执行记录失败: %w", err)
+		}
+		executions = append(executions, execution)
+	}
+
+	return executions, rows.Err()
+}
+
+// scanRemediationExecution 扫描修复动作执行记录
+func scanRemediationExecution(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.RemediationExecution, error) {
+	var execution models.RemediationExecution
+	var action []byte
+
+	err := scanner.Scan(
+		&execution.ID, &execution.AlertID, &execution.RuleID, &execution.ActionIndex,
+		&execution.ActionName, &execution.ActionType, &action, &execution.Status,
+		&execution.Output, &execution.Error, &execution.ApprovedBy, &execution.ApprovedAt,
+		&execution.StartedAt, &execution.FinishedAt, &execution.CreatedAt, &execution.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(action) > 0 {
+		if err := json.Unmarshal(action, &execution.Action); err != nil {
+			return nil, fmt.Errorf("解析修复动作失败: %w", err)
+		}
+	}
+
+	return &execution, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var remediationTestColumns = []string{
+	"id", "alert_id", "rule_id", "action_index", "action_name", "action_type", "action",
+	"status", "output", "error", "approved_by", "approved_at", "started_at", "finished_at",
+	"created_at", "updated_at",
+}
+
+func TestRemediationRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRemediationRepository(sqlx.NewDb(db, "postgres"))
+	execution := &models.RemediationExecution{
+		AlertID:     "a1",
+		RuleID:      "r1",
+		ActionIndex: 1,
+		ActionName:  "restart nginx",
+		ActionType:  models.RemediationActionSalt,
+		Action:      models.RuleAction{Type: models.RemediationActionSalt, Target: "web-*"},
+		Status:      models.RemediationStatusQueued,
+	}
+
+	mock.ExpectExec(`INSERT INTO remediation_executions .+ ON CONFLICT \(alert_id, rule_id, action_index\) DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	created, err := repo.Create(context.Background(), execution)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEmpty(t, execution.ID)
+
+	mock.ExpectExec(`INSERT INTO remediation_executions`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	created, err = repo.Create(context.Background(), &models.RemediationExecution{AlertID: "a1", RuleID: "r1", ActionIndex: 1})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemediationRepository_ClaimQueued(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRemediationRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE remediation_executions SET .+ WHERE status = 'queued' .+ FOR UPDATE SKIP LOCKED .+ RETURNING`).
+		WithArgs(sqlmock.AnyArg(), 10).
+		WillReturnRows(sqlmock.NewRows(remediationTestColumns).AddRow(
+			"e1", "a1", "r1", 0, "cleanup", "http",
+			[]byte(`{"type":"http","target":"https://ops.example.com/cleanup"}`),
+			"running", "", nil, nil, nil, now, nil, now, now))
+
+	executions, err := repo.ClaimQueued(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	assert.Equal(t, models.RemediationStatusRunning, executions[0].Status)
+	assert.Equal(t, "https://ops.example.com/cleanup", executions[0].Action.Target)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemediationRepository_Decide(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRemediationRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE remediation_executions SET .+ WHERE id = \$5 AND status = 'pending_approval'`).
+		WithArgs(models.RemediationStatusQueued, "u1", sqlmock.AnyArg(), nil, "e1").
+		WillReturnRows(sqlmock.NewRows(remediationTestColumns).AddRow(
+			"e1", "a1", "r1", 0, "job", "awx", []byte(`{"type":"awx","target":"42"}`),
+			"queued", "", nil, "u1", now, nil, nil, now, now))
+
+	execution, err := repo.Decide(context.Background(), "e1", true, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RemediationStatusQueued, execution.Status)
+	assert.Equal(t, "u1", *execution.ApprovedBy)
+
+	mock.ExpectQuery(`UPDATE remediation_executions SET`).
+		WithArgs(models.RemediationStatusRejected, "u1", sqlmock.AnyArg(), sqlmock.AnyArg(), "e1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Decide(context.Background(), "e1", false, "u1")
+	assert.ErrorIs(t, err, models.ErrRemediationNotPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
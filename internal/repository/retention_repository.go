@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+// retentionTable 资源对应的表和可以清理的记录的条件，表别名为 t
+type retentionTable struct {
+	table     string
+	condition string
+}
+
+// retentionTables 支持数据保留的资源，未解决的告警和未发送的通知不清理
+var retentionTables = map[models.RetentionResource]retentionTable{
+	models.RetentionResourceAlerts: {
+		table:     "alerts",
+		condition: fmt.Sprintf("(t.status = '%s' OR t.deleted_at IS NOT NULL)", models.AlertStatusResolved),
+	},
+	models.RetentionResourceAlertHistories: {
+		table:     "alert_histories",
+		condition: "TRUE",
+	},
+	models.RetentionResourceNotifications: {
+		table:     "notifications",
+		condition: fmt.Sprintf("t.status IN ('%s', '%s')", models.NotificationStatusSent, models.NotificationStatusFailed),
+	},
+}
+
+// notRestoredCondition 排除仍在恢复保留期内的时间窗口中的记录，$2 为资源
+const notRestoredCondition = `NOT EXISTS (
+			SELECT 1 FROM data_archive_restores r
+			WHERE r.resource = $2 AND r.expires_at > NOW()
+			  AND t.created_at >= r.window_start AND t.created_at < r.window_end
+		)`
+
+const dataArchiveColumns = `id, resource, object_key, window_start, window_end, record_count, size_bytes, created_at`
+
+// retentionRepository 数据保留仓储实现
+type retentionRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewRetentionRepository 创建数据保留仓储实例
+func NewRetentionRepository(db *sqlx.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// NewRetentionRepositoryWithTx 创建带事务的数据保留仓储实例
+func NewRetentionRepositoryWithTx(tx *sqlx.Tx) RetentionRepository {
+	return &retentionRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *retentionRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// lookupTable 获取资源对应的表
+func lookupTable(resource models.RetentionResource) (retentionTable, error) {
+	table, ok := retentionTables[resource]
+	if !ok {
+		return retentionTable{}, fmt.Errorf("%w: 不支持的资源 %q", models.ErrInvalidInput, resource)
+	}
+	return table, nil
+}
+
+// ExpiredStats 统计创建时间早于 cutoff 且可以清理的记录数和其中最早的创建时间
+func (r *retentionRepository) ExpiredStats(ctx context.Context, resource models.RetentionResource, cutoff time.Time) (int64, *time.Time, error) {
+	table, err := lookupTable(resource)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) AS count, MIN(t.created_at) AS oldest
+		FROM %s t
+		WHERE t.created_at < $1 AND %s AND %s`, table.table, table.condition, notRestoredCondition)
+
+	var stats struct {
+		Count  int64      `db:"count"`
+		Oldest *time.Time `db:"oldest"`
+	}
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &stats, query, cutoff, resource); err != nil {
+		return 0, nil, fmt.Errorf("统计 %s 的过期记录失败: %w", resource, err)
+	}
+	return stats.Count, stats.Oldest, nil
+}
+
+// ListExpired 按创建时间顺序获取最多 limit 条创建时间早于 cutoff 且可以清理的记录
+func (r *retentionRepository) ListExpired(ctx context.Context, resource models.RetentionResource, cutoff time.Time, limit int) ([]*models.ArchiveRecord, error) {
+	table, err := lookupTable(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id::text AS id, t.created_at, row_to_json(t)::text AS data
+		FROM %s t
+		WHERE t.created_at < $1 AND %s AND %s
+		ORDER BY t.created_at, t.id
+		LIMIT $3`, table.table, table.condition, notRestoredCondition)
+
+	records := make([]*models.ArchiveRecord, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &records, query, cutoff, resource, limit); err != nil {
+		return nil, fmt.Errorf("获取 %s 的过期记录失败: %w", resource, err)
+	}
+	return records, nil
+}
+
+// DeleteRecords 按ID删除记录，返回删除的数量
+func (r *retentionRepository) DeleteRecords(ctx context.Context, resource models.RetentionResource, ids []string) (int64, error) {
+	table, err := lookupTable(resource)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1::uuid[])`, table.table)
+	result, err := r.getExecutor().ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("删除 %s 的记录失败: %w", resource, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// RestoreRecords 写回归档中创建时间在 [from, to) 内的记录，已存在的记录跳过，返回写入的数量
+func (r *retentionRepository) RestoreRecords(ctx context.Context, resource models.RetentionResource, records []json.RawMessage, from, to time.Time) (int64, error) {
+	table, err := lookupTable(resource)
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	parts := make([]string, len(records))
+	for i, record := range records {
+		parts[i] = string(record)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s
+		SELECT r.* FROM json_populate_recordset(NULL::%[1]s, $1::json) r
+		WHERE r.created_at >= $2 AND r.created_at < $3
+		ON CONFLICT DO NOTHING`, table.table)
+
+	result, err := r.getExecutor().ExecContext(ctx, query, "["+strings.Join(parts, ",")+"]", from, to)
+	if err != nil {
+		return 0, fmt.Errorf("恢复 %s 的记录失败: %w", resource, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取恢复行数失败: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// CreateArchive 记录归档文件
+func (r *retentionRepository) CreateArchive(ctx context.Context, archive *models.DataArchive) error {
+	if archive.ID == "" {
+		archive.ID = uuid.New().String()
+	}
+	archive.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO data_archives (` + dataArchiveColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		archive.ID, archive.Resource, archive.ObjectKey, archive.WindowStart, archive.WindowEnd,
+		archive.RecordCount, archive.SizeBytes, archive.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("记录归档文件失败: %w", err)
+	}
+	return nil
+}
+
+// ListArchives 分页获取归档文件，按时间窗口倒序排列
+func (r *retentionRepository) ListArchives(ctx context.Context, filter *models.DataArchiveFilter) ([]*models.DataArchive, int64, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 1000 {
+		filter.PageSize = 1000
+	}
+
+	var conditions []string
+	var args []interface{}
+	if filter.Resource != nil {
+		args = append(args, *filter.Resource)
+		conditions = append(conditions, fmt.Sprintf("resource = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("window_end >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("window_start < $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &total, `SELECT COUNT(*) FROM data_archives`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("统计归档文件失败: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM data_archives%s ORDER BY window_start DESC LIMIT $%d OFFSET $%d`,
+		dataArchiveColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	archives := make([]*models.DataArchive, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &archives, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("获取归档文件列表失败: %w", err)
+	}
+	return archives, total, nil
+}
+
+// ArchiveStats 统计资源的归档文件数、已归档的记录数和最近的归档时间
+func (r *retentionRepository) ArchiveStats(ctx context.Context, resource models.RetentionResource) (*models.RetentionStatus, error) {
+	query := `
+		SELECT COUNT(*) AS archives, COALESCE(SUM(record_count), 0) AS archived_records, MAX(created_at) AS last_archived_at
+		FROM data_archives
+		WHERE resource = $1`
+
+	var stats struct {
+		Archives        int64      `db:"archives"`
+		ArchivedRecords int64      `db:"archived_records"`
+		LastArchivedAt  *time.Time `db:"last_archived_at"`
+	}
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &stats, query, resource); err != nil {
+		return nil, fmt.Errorf("统计 %s 的归档文件失败: %w", resource, err)
+	}
+
+	return &models.RetentionStatus{
+		Archives:        stats.Archives,
+		ArchivedRecords: stats.ArchivedRecords,
+		LastArchivedAt:  stats.LastArchivedAt,
+	}, nil
+}
+
+// CreateRestore 记录恢复的时间窗口
+func (r *retentionRepository) CreateRestore(ctx context.Context, restore *models.DataArchiveRestore) error {
+	if restore.ID == "" {
+		restore.ID = uuid.New().String()
+	}
+	restore.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO data_archive_restores (
+			id, resource, window_start, window_end, archives, restored_records, expires_at, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		restore.ID, restore.Resource, restore.WindowStart, restore.WindowEnd, restore.Archives,
+		restore.RestoredRecords, restore.ExpiresAt, restore.CreatedBy, restore.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("记录归档恢复失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestRetentionRepository_ListExpiredAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetentionRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := cutoff.AddDate(0, -1, 0)
+
+	// 只归档已解决或已删除的告警，并跳过恢复保留期内的记录
+	mock.ExpectQuery(`SELECT t.id::text AS id, t.created_at, row_to_json\(t\)::text AS data\s+FROM alerts t\s+`+
+		`WHERE t.created_at < \$1 AND \(t.status = 'resolved' OR t.deleted_at IS NOT NULL\) AND NOT EXISTS \(.*data_archive_restores.*\)\s+`+
+		`ORDER BY t.created_at, t.id\s+LIMIT \$3`).
+		WithArgs(cutoff, models.RetentionResourceAlerts, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "data"}).
+			AddRow("a1", createdAt, `{"id":"a1"}`))
+
+	records, err := repo.ListExpired(ctx, models.RetentionResourceAlerts, cutoff, 100)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "a1", records[0].ID)
+	assert.JSONEq(t, `{"id":"a1"}`, string(records[0].Data))
+
+	mock.ExpectExec(`DELETE FROM alerts WHERE id = ANY\(\$1::uuid\[\]\)`).
+		WithArgs(pq.Array([]string{"a1"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	deleted, err := repo.DeleteRecords(ctx, models.RetentionResourceAlerts, []string{"a1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.ListExpired(ctx, models.RetentionResource("users"), cutoff, 100)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionRepository_RestoreRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetentionRepository(sqlx.NewDb(db, "postgres"))
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectExec(`INSERT INTO alert_histories\s+SELECT r.\* FROM json_populate_recordset\(NULL::alert_histories, \$1::json\) r\s+`+
+		`WHERE r.created_at >= \$2 AND r.created_at < \$3\s+ON CONFLICT DO NOTHING`).
+		WithArgs(`[{"id":"h1"},{"id":"h2"}]`, from, to).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	restored, err := repo.RestoreRecords(context.Background(), models.RetentionResourceAlertHistories,
+		[]json.RawMessage{json.RawMessage(`{"id":"h1"}`), json.RawMessage(`{"id":"h2"}`)}, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), restored)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,26 +4,33 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"pulse/internal/models"
 )
 
 type ruleRepository struct {
-	db *sqlx.DB
-	tx *sqlx.Tx
+	db     *sqlx.DB
+	tx     *sqlx.Tx
+	reader Reader
 }
 
 // NewRuleRepository 创建规则仓储实例
 func NewRuleRepository(db *sqlx.DB) RuleRepository {
+	return NewRuleRepositoryWithReader(db, db)
+}
+
+// NewRuleRepositoryWithReader 创建规则仓储实例，列表和统计查询使用 reader（通常为只读副本）
+func NewRuleRepositoryWithReader(db *sqlx.DB, reader Reader) RuleRepository {
 	return &ruleRepository{
-		db: db,
+		db:     db,
+		reader: reader,
 	}
 }
 
@@ -42,6 +49,14 @@ func (r *ruleRepository) getExecutor() sqlx.ExtContext {
 	return r.db
 }
 
+// readExecutor 获取只读查询执行器，事务内使用事务保证读到未提交的写入
+func (r *ruleRepository) readExecutor() Reader {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.reader
+}
+
 // Create 创建规则
 func (r *ruleRepository) Create(ctx context.Context, rule *models.Rule) error {
 	// 生成ID
@@ -92,6 +107,9 @@ func (r *ruleRepository) Create(ctx context.Context, rule *models.Rule) error {
 		rule.CreatedAt, rule.UpdatedAt,
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrRuleExists
+		}
 		return fmt.Errorf("创建规则失败: %w", err)
 	}
 	
@@ -126,7 +144,7 @@ func (r *ruleRepository) GetByID(ctx context.Context, id string) (*models.Rule,
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("规则不存在")
+			return nil, models.ErrRuleNotFound
 		}
 		return nil, fmt.Errorf("获取规则失败: %w", err)
 	}
@@ -350,7 +368,7 @@ func (r *ruleRepository) List(ctx context.Context, filter *models.RuleFilter) (*
 		args = append(args, filter.PageSize, offset)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("查询规则列表失败: %w", err)
 	}
@@ -462,7 +480,7 @@ func (r *ruleRepository) Count(ctx context.Context, filter *models.RuleFilter) (
 
 	query := fmt.Sprintf("SELECT COUNT(*) FROM rules %s", whereClause)
 	var count int64
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := r.readExecutor().GetContext(ctx, &count, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("获取规则总数失败: %w", err)
 	}
@@ -619,7 +637,7 @@ func (r *ruleRepository) GetStats(ctx context.Context, filter *models.RuleFilter
 	}
 	
 	var total, active, inactive, disabled int64
-	err := r.getExecutor().QueryRowxContext(ctx, query).Scan(&total, &active, &inactive, &disabled)
+	err := r.readExecutor().QueryRowxContext(ctx, query).Scan(&total, &active, &inactive, &disabled)
 	if err != nil {
 		return nil, err
 	}
@@ -652,7 +670,7 @@ func (r *ruleRepository) IncrementAlertCount(ctx context.Context, id string) err
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("规则不存在")
+		return models.ErrRuleNotFound
 	}
 	
 	return nil
@@ -679,7 +697,7 @@ func (r *ruleRepository) IncrementEvaluationCount(ctx context.Context, id string
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("规则不存在")
+		return models.ErrRuleNotFound
 	}
 	
 	return nil
@@ -705,7 +723,7 @@ func (r *ruleRepository) SetTesting(ctx context.Context, id string) error {
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("规则不存在")
+		return models.ErrRuleNotFound
 	}
 	
 	return nil
@@ -737,15 +755,27 @@ func (r *ruleRepository) TestRule(ctx context.Context, rule *models.Rule) (*mode
 	}, nil
 }
 
-// UpdateLastEvaluation 更新最后评估信息
+// UpdateLastEvaluation 更新最后评估信息，同时记录本次评估相对计划时间（上次评估时间加评估间隔）的延迟，
+// 延迟达到一个评估间隔时累加连续错过次数，按时评估时清零
 func (r *ruleRepository) UpdateLastEvaluation(ctx context.Context, id string, evalTime time.Time, result bool, error string) error {
 	query := `
 		UPDATE rules 
 		SET last_eval_at = $1,
 			last_eval_result = $2,
 			eval_count = eval_count + 1,
+			last_eval_scheduled_at = scheduled.at,
+			last_eval_delay_ms = CASE WHEN scheduled.at IS NULL THEN 0
+				ELSE GREATEST(0, FLOOR(EXTRACT(EPOCH FROM ($1::timestamptz - scheduled.at)) * 1000))::BIGINT END,
+			missed_evals = CASE WHEN scheduled.at IS NOT NULL
+				AND $1::timestamptz >= scheduled.at + make_interval(secs => rules.evaluation_interval / 1e9)
+				THEN rules.missed_evals + 1 ELSE 0 END,
 			updated_at = NOW()
-		WHERE id = $3 AND deleted_at IS NULL
+		FROM (
+			SELECT id, CASE WHEN last_eval_at IS NULL OR evaluation_interval <= 0 THEN NULL
+				ELSE last_eval_at + make_interval(secs => evaluation_interval / 1e9) END AS at
+			FROM rules WHERE id = $3
+		) scheduled
+		WHERE rules.id = scheduled.id AND rules.deleted_at IS NULL
 	`
 	
 	// 将结果转换为字符串存储
@@ -781,11 +811,26 @@ func (r *ruleRepository) UpdateLastEvaluation(ctx context.Context, id string, ev
 	return nil
 }
 
+// ListEvaluationLag 获取启用规则的评估延迟，按上次评估时间排序，从未评估的规则排在最前
+func (r *ruleRepository) ListEvaluationLag(ctx context.Context) ([]*models.RuleEvaluationLag, error) {
+	query := `
+		SELECT id, name, evaluation_interval, last_eval_at, last_eval_scheduled_at, last_eval_delay_ms, missed_evals
+		FROM rules
+		WHERE enabled = true AND status = $1 AND deleted_at IS NULL
+		ORDER BY last_eval_at ASC NULLS FIRST`
+
+	var lags []*models.RuleEvaluationLag
+	if err := r.readExecutor().SelectContext(ctx, &lags, query, models.RuleStatusActive); err != nil {
+		return nil, fmt.Errorf("获取规则评估延迟失败: %w", err)
+	}
+	return lags, nil
+}
+
 // GetActiveCount 获取活跃规则数量
 func (r *ruleRepository) GetActiveCount(ctx context.Context) (int64, error) {
 	query := `SELECT COUNT(*) FROM rules WHERE enabled = true AND deleted_at IS NULL`
 	var count int64
-	err := r.db.GetContext(ctx, &count, query)
+	err := r.readExecutor().GetContext(ctx, &count, query)
 	if err != nil {
 		return 0, fmt.Errorf("获取活跃规则数量失败: %w", err)
 	}
@@ -882,7 +927,7 @@ func (r *ruleRepository) GetErrorCount(ctx context.Context) (int64, error) {
 	`
 	
 	var count int64
-	err := r.getExecutor().QueryRowxContext(ctx, query).Scan(&count)
+	err := r.readExecutor().QueryRowxContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get error count: %w", err)
 	}
@@ -970,6 +1015,85 @@ func (r *ruleRepository) GetActiveRules(ctx context.Context) ([]*models.Rule, er
 	return rules, nil
 }
 
+// ListByIDs 批量获取规则，不存在或已删除的规则不在结果中
+func (r *ruleRepository) ListByIDs(ctx context.Context, ids []string) ([]*models.Rule, error) {
+	query := `
+		SELECT id, name, description, type, severity, status, enabled, expression,
+		       conditions, actions, labels, annotations, data_source_id,
+		       evaluation_interval, for_duration, keep_firing_for, threshold,
+		       recovery_threshold, no_data_state, exec_err_state,
+		       last_eval_at, last_eval_result, eval_count, alert_count,
+		       created_by, updated_by, created_at, updated_at
+		FROM rules
+		WHERE id = ANY($1) AND deleted_at IS NULL`
+
+	rows, err := r.readExecutor().QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("批量获取规则失败: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.Rule
+	for rows.Next() {
+		var rule models.Rule
+		var labelsJSON, annotationsJSON, conditionsJSON, actionsJSON string
+
+		err := rows.Scan(
+			&rule.ID, &rule.Name, &rule.Description, &rule.Type, &rule.Severity,
+			&rule.Status, &rule.Enabled, &rule.Expression, &conditionsJSON,
+			&actionsJSON, &labelsJSON, &annotationsJSON, &rule.DataSourceID,
+			&rule.EvaluationInterval, &rule.ForDuration, &rule.KeepFiringFor,
+			&rule.Threshold, &rule.RecoveryThreshold, &rule.NoDataState,
+			&rule.ExecErrState, &rule.LastEvalAt, &rule.LastEvalResult,
+			&rule.EvalCount, &rule.AlertCount, &rule.CreatedBy, &rule.UpdatedBy,
+			&rule.CreatedAt, &rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描规则数据失败: %w", err)
+		}
+
+		// 反序列化条件
+		if conditionsJSON != "" {
+			err = json.Unmarshal([]byte(conditionsJSON), &rule.Conditions)
+			if err != nil {
+				return nil, fmt.Errorf("反序列化条件失败: %w", err)
+			}
+		}
+
+		// 反序列化动作
+		if actionsJSON != "" {
+			err = json.Unmarshal([]byte(actionsJSON), &rule.Actions)
+			if err != nil {
+				return nil, fmt.Errorf("反序列化动作失败: %w", err)
+			}
+		}
+
+		// 反序列化标签
+		if labelsJSON != "" {
+			err = json.Unmarshal([]byte(labelsJSON), &rule.Labels)
+			if err != nil {
+				return nil, fmt.Errorf("反序列化标签失败: %w", err)
+			}
+		}
+
+		// 反序列化注解
+		if annotationsJSON != "" {
+			err = json.Unmarshal([]byte(annotationsJSON), &rule.Annotations)
+			if err != nil {
+				return nil, fmt.Errorf("反序列化注解失败: %w", err)
+			}
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历规则数据失败: %w", err)
+	}
+
+	return rules, nil
+}
+
 // GetRulesForEvaluation 获取需要评估的规则列表
 func (r *ruleRepository) GetRulesForEvaluation(ctx context.Context) ([]*models.Rule, error) {
 	query := `
@@ -1052,24 +1176,28 @@ func (r *ruleRepository) GetRulesForEvaluation(ctx context.Context) ([]*models.R
 	return rules, nil
 }
 
-// BatchCreate 批量创建规则
+// ruleInsertColumns 批量创建规则时写入的列
+var ruleInsertColumns = []string{
+	"id", "name", "description", "type", "severity", "status", "enabled", "expression",
+	"conditions", "actions", "labels", "annotations", "data_source_id",
+	"evaluation_interval", "for_duration", "keep_firing_for", "threshold",
+	"recovery_threshold", "no_data_state", "exec_err_state",
+	"created_by", "created_at", "updated_at",
+}
+
+// BatchCreate 批量创建规则，在一个事务中通过 COPY 或多行 VALUES 写入
 func (r *ruleRepository) BatchCreate(ctx context.Context, rules []*models.Rule) error {
 	if len(rules) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("开始事务失败: %w", err)
-	}
-	defer tx.Rollback()
-
+	now := time.Now()
+	rows := make([][]interface{}, 0, len(rules))
 	for _, rule := range rules {
 		if rule.ID == "" {
 			rule.ID = uuid.New().String()
 		}
 
-		now := time.Now()
 		rule.CreatedAt = now
 		rule.UpdatedAt = now
 
@@ -1098,31 +1226,23 @@ func (r *ruleRepository) BatchCreate(ctx context.Context, rules []*models.Rule)
 			return fmt.Errorf("序列化注解失败: %w", err)
 		}
 
-		query := `
-			INSERT INTO rules (
-				id, name, description, type, severity, status, enabled, expression,
-				conditions, actions, labels, annotations, data_source_id,
-				evaluation_interval, for_duration, keep_firing_for, threshold,
-				recovery_threshold, no_data_state, exec_err_state,
-				created_by, created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
-			)`
-
-		_, err = tx.ExecContext(ctx, query,
+		rows = append(rows, []interface{}{
 			rule.ID, rule.Name, rule.Description, rule.Type, rule.Severity,
 			rule.Status, rule.Enabled, rule.Expression, string(conditionsJSON),
 			string(actionsJSON), string(labelsJSON), string(annotationsJSON), rule.DataSourceID,
 			rule.EvaluationInterval, rule.ForDuration, rule.KeepFiringFor,
 			rule.Threshold, rule.RecoveryThreshold, rule.NoDataState, rule.ExecErrState,
 			rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt,
-		)
-		if err != nil {
-			return fmt.Errorf("批量创建规则失败: %w", err)
-		}
+		})
 	}
 
-	return tx.Commit()
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		return bulkInsert(ctx, tx, "rules", ruleInsertColumns, rows)
+	})
+	if err != nil {
+		return fmt.Errorf("批量创建规则失败: %w", err)
+	}
+	return nil
 }
 
 // Activate 激活规则
@@ -1146,7 +1266,7 @@ func (r *ruleRepository) Activate(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("规则不存在或已被删除")
+		return models.ErrRuleNotFound
 	}
 
 	return nil
@@ -1173,7 +1293,7 @@ func (r *ruleRepository) Deactivate(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("规则不存在或已被删除")
+		return models.ErrRuleNotFound
 	}
 
 	return nil
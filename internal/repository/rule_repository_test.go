@@ -112,6 +112,8 @@ func TestRuleRepository_GetByID_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, rule)
 	assert.Contains(t, err.Error(), "规则不存在")
+	assert.ErrorIs(t, err, models.ErrRuleNotFound)
+	assert.ErrorIs(t, err, models.ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
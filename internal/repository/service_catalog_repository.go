@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// serviceCatalogRepository 服务目录仓储实现
+type serviceCatalogRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewServiceCatalogRepository 创建服务目录仓储实例
+func NewServiceCatalogRepository(db *sqlx.DB) ServiceCatalogRepository {
+	return &serviceCatalogRepository{db: db}
+}
+
+// NewServiceCatalogRepositoryWithTx 创建带事务的服务目录仓储实例
+func NewServiceCatalogRepositoryWithTx(tx *sqlx.Tx) ServiceCatalogRepository {
+	return &serviceCatalogRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *serviceCatalogRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const catalogServiceColumns = `id, name, description, owner_team, tier, dependencies, label_selectors,
+	slo_target, created_by, created_at, updated_at`
+
+// Create 创建服务
+func (r *serviceCatalogRepository) Create(ctx context.Context, service *models.CatalogService) error {
+	if service.ID == "" {
+		service.ID = uuid.New().String()
+	}
+
+	dependencies, selectors, err := marshalCatalogService(service)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	service.CreatedAt = now
+	service.UpdatedAt = now
+
+	query := `
+		INSERT INTO catalog_services (
+			id, name, description, owner_team, tier, dependencies, label_selectors,
+			slo_target, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		service.ID, service.Name, service.Description, service.OwnerTeam, service.Tier, dependencies, selectors,
+		service.SLOTarget, service.CreatedBy, service.CreatedAt, service.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrCatalogServiceExists
+		}
+		return fmt.Errorf("创建服务失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取服务
+func (r *serviceCatalogRepository) GetByID(ctx context.Context, id string) (*models.CatalogService, error) {
+	query := `SELECT ` + catalogServiceColumns + ` FROM catalog_services WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// GetByName 根据名称获取服务
+func (r *serviceCatalogRepository) GetByName(ctx context.Context, name string) (*models.CatalogService, error) {
+	query := `SELECT ` + catalogServiceColumns + ` FROM catalog_services WHERE name = $1`
+	return r.get(ctx, query, name)
+}
+
+func (r *serviceCatalogRepository) get(ctx context.Context, query string, args ...interface{}) (*models.CatalogService, error) {
+	service, err := scanCatalogService(r.getExecutor().QueryRowxContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrCatalogServiceNotFound
+		}
+		return nil, fmt.Errorf("获取服务失败: %w", err)
+	}
+	return service, nil
+}
+
+// List 获取全部服务，按名称排序
+func (r *serviceCatalogRepository) List(ctx context.Context) ([]*models.CatalogService, error) {
+	query := `SELECT ` + catalogServiceColumns + ` FROM catalog_services ORDER BY name`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取服务列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	services := make([]*models.CatalogService, 0)
+	for rows.Next() {
+		service, err := scanCatalogService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描服务失败: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// Update 更新服务
+func (r *serviceCatalogRepository) Update(ctx context.Context, service *models.CatalogService) error {
+	dependencies, selectors, err := marshalCatalogService(service)
+	if err != nil {
+		return err
+	}
+
+	service.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE catalog_services SET
+			name = $2, description = $3, owner_team = $4, tier = $5, dependencies = $6,
+			label_selectors = $7, slo_target = $8, updated_at = $9
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		service.ID, service.Name, service.Description, service.OwnerTeam, service.Tier, dependencies,
+		selectors, service.SLOTarget, service.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrCatalogServiceExists
+		}
+		return fmt.Errorf("更新服务失败: %w", err)
+	}
+
+	return checkCatalogServiceAffected(result)
+}
+
+// Delete 删除服务
+func (r *serviceCatalogRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM catalog_services WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除服务失败: %w", err)
+	}
+
+	return checkCatalogServiceAffected(result)
+}
+
+// ListAlertWindows 获取归属到服务且与 [since, until) 有重叠的告警区间
+func (r *serviceCatalogRepository) ListAlertWindows(ctx context.Context, name string, since, until time.Time) ([]*models.ServiceAlertWindow, error) {
+	query := `
+		SELECT severity, starts_at, resolved_at
+		FROM alerts
+		WHERE labels->>'service' = $1
+		  AND starts_at < $3
+		  AND (resolved_at IS NULL OR resolved_at >= $2)
+		  AND deleted_at IS NULL
+		ORDER BY starts_at`
+
+	windows := []*models.ServiceAlertWindow{}
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &windows, query, name, since, until); err != nil {
+		return nil, fmt.Errorf("获取服务告警失败: %w", err)
+	}
+
+	return windows, nil
+}
+
+// CountActiveAlerts 按服务和级别统计归属到服务的活跃告警，name 为空时统计全部服务。
+// 触发中和已确认的告警视为活跃，已静默和已抑制的告警不影响服务健康度
+func (r *serviceCatalogRepository) CountActiveAlerts(ctx context.Context, name string) ([]*models.ServiceAlertCount, error) {
+	query := `
+		SELECT labels->>'service' AS service, severity, COUNT(*) AS count
+		FROM alerts
+		WHERE labels ? 'service'
+		  AND ($1 = '' OR labels->>'service' = $1)
+		  AND status IN ('firing', 'acked')
+		  AND resolved_at IS NULL
+		  AND deleted_at IS NULL
+		GROUP BY labels->>'service', severity`
+
+	counts := []*models.ServiceAlertCount{}
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &counts, query, name); err != nil {
+		return nil, fmt.Errorf("统计服务活跃告警失败: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetTicketStats 统计 since 之后创建且归属到服务的工单
+func (r *serviceCatalogRepository) GetTicketStats(ctx context.Context, name string, since time.Time) (*models.ServiceTicketStats, error) {
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status NOT IN ('resolved', 'closed', 'cancelled')) AS open
+		FROM tickets
+		WHERE $1 = ANY(tags) AND created_at >= $2 AND deleted_at IS NULL`
+
+	var stats models.ServiceTicketStats
+	err := r.getExecutor().QueryRowxContext(ctx, query, models.ServiceTicketTagPrefix+name, since).
+		Scan(&stats.Total, &stats.Open)
+	if err != nil {
+		return nil, fmt.Errorf("统计服务工单失败: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// marshalCatalogService 序列化服务的依赖和标签选择器
+func marshalCatalogService(service *models.CatalogService) ([]byte, []byte, error) {
+	dependencies := service.Dependencies
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+	dependenciesJSON, err := json.Marshal(dependencies)
+	if err != nil {
+		return nil, nil, fmt.Errorf("序列化依赖服务失败: %w", err)
+	}
+
+	selectors := service.LabelSelectors
+	if selectors == nil {
+		selectors = []models.LabelSelector{}
+	}
+	selectorsJSON, err := json.Marshal(selectors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("序列化标签选择器失败: %w", err)
+	}
+
+	return dependenciesJSON, selectorsJSON, nil
+}
+
+// checkCatalogServiceAffected 没有更新任何行时返回服务不存在
+func checkCatalogServiceAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrCatalogServiceNotFound
+	}
+
+	return nil
+}
+
+// scanCatalogService 扫描服务
+func scanCatalogService(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.CatalogService, error) {
+	var service models.CatalogService
+	var dependencies, selectors []byte
+
+	err := scanner.Scan(
+		&service.ID, &service.Name, &service.Description, &service.OwnerTeam, &service.Tier, &dependencies,
+		&selectors, &service.SLOTarget, &service.CreatedBy, &service.CreatedAt, &service.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Dependencies = []string{}
+	if len(dependencies) > 0 {
+		if err := json.Unmarshal(dependencies, &service.Dependencies); err != nil {
+			return nil, fmt.Errorf("解析依赖服务失败: %w", err)
+		}
+	}
+
+	service.LabelSelectors = []models.LabelSelector{}
+	if len(selectors) > 0 {
+		if err := json.Unmarshal(selectors, &service.LabelSelectors); err != nil {
+			return nil, fmt.Errorf("解析标签选择器失败: %w", err)
+		}
+	}
+
+	return &service, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var catalogServiceTestColumns = []string{
+	"id", "name", "description", "owner_team", "tier", "dependencies", "label_selectors",
+	"slo_target", "created_by", "created_at", "updated_at",
+}
+
+func TestServiceCatalogRepository_CreateDuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewServiceCatalogRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(`INSERT INTO catalog_services`).
+		WithArgs(sqlmock.AnyArg(), "checkout", "", "payments", models.ServiceTier1, []byte(`[]`), []byte(`[]`),
+			99.9, "admin", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.Create(context.Background(), &models.CatalogService{
+		Name: "checkout", OwnerTeam: "payments", Tier: models.ServiceTier1, SLOTarget: 99.9, CreatedBy: "admin",
+	})
+	assert.ErrorIs(t, err, models.ErrCatalogServiceExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestServiceCatalogRepository_GetByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewServiceCatalogRepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM catalog_services WHERE name = \$1`).
+		WithArgs("checkout").
+		WillReturnRows(sqlmock.NewRows(catalogServiceTestColumns).AddRow(
+			"s1", "checkout", "", "payments", "tier1", []byte(`["payment-gateway"]`),
+			[]byte(`[{"namespace":"checkout","app":"api-*"}]`), 99.95, "admin", now, now))
+
+	service, err := repo.GetByName(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payment-gateway"}, service.Dependencies)
+	require.Len(t, service.LabelSelectors, 1)
+	assert.Equal(t, 2, service.Match(map[string]string{"namespace": "checkout", "app": "api-v2"}))
+
+	mock.ExpectQuery(`SELECT .+ FROM catalog_services WHERE name = \$1`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(catalogServiceTestColumns))
+
+	_, err = repo.GetByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrCatalogServiceNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestServiceCatalogRepository_GetTicketStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewServiceCatalogRepository(sqlx.NewDb(db, "postgres"))
+	since := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectQuery(`FROM tickets\s+WHERE \$1 = ANY\(tags\) AND created_at >= \$2`).
+		WithArgs("service:checkout", since).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "open"}).AddRow(5, 2))
+
+	stats, err := repo.GetTicketStats(context.Background(), "checkout", since)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ServiceTicketStats{Total: 5, Open: 2}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
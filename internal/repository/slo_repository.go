@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// sloRepository SLO 仓储实现
+type sloRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewSLORepository 创建 SLO 仓储实例
+func NewSLORepository(db *sqlx.DB) SLORepository {
+	return &sloRepository{db: db}
+}
+
+// NewSLORepositoryWithTx 创建带事务的 SLO 仓储实例
+func NewSLORepositoryWithTx(tx *sqlx.Tx) SLORepository {
+	return &sloRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *sloRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const sloColumns = `id, name, description, service_id, data_source_id, error_query, total_query, target,
+	window_days, enabled, page_rule_id, ticket_rule_id, budget_status, error_ratio, budget_remaining,
+	burn_rate, evaluated_at, last_error, created_by, created_at, updated_at`
+
+// Create 创建 SLO
+func (r *sloRepository) Create(ctx context.Context, slo *models.SLO) error {
+	if slo.ID == "" {
+		slo.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	slo.CreatedAt = now
+	slo.UpdatedAt = now
+	slo.Budget = models.SLOBudget{Status: models.SLOBudgetStatusUnknown}
+
+	query := `
+		INSERT INTO slos (
+			id, name, description, service_id, data_source_id, error_query, total_query, target,
+			window_days, enabled, page_rule_id, ticket_rule_id, budget_status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		slo.ID, slo.Name, slo.Description, slo.ServiceID, slo.DataSourceID, slo.ErrorQuery, slo.TotalQuery,
+		slo.Target, slo.WindowDays, slo.Enabled, slo.PageRuleID, slo.TicketRuleID, slo.Budget.Status,
+		slo.CreatedBy, slo.CreatedAt, slo.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrSLOExists
+		}
+		return fmt.Errorf("创建SLO失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取 SLO
+func (r *sloRepository) GetByID(ctx context.Context, id string) (*models.SLO, error) {
+	query := `SELECT ` + sloColumns + ` FROM slos WHERE id = $1`
+
+	slo, err := scanSLO(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrSLONotFound
+		}
+		return nil, fmt.Errorf("获取SLO失败: %w", err)
+	}
+	return slo, nil
+}
+
+// List 获取 SLO 列表，serviceID 不为空时只返回该服务的 SLO，按名称排序
+func (r *sloRepository) List(ctx context.Context, serviceID string) ([]*models.SLO, error) {
+	if serviceID == "" {
+		return r.list(ctx, `SELECT `+sloColumns+` FROM slos ORDER BY name`)
+	}
+	return r.list(ctx, `SELECT `+sloColumns+` FROM slos WHERE service_id = $1 ORDER BY name`, serviceID)
+}
+
+// ListEnabled 获取需要计算错误预算的 SLO
+func (r *sloRepository) ListEnabled(ctx context.Context) ([]*models.SLO, error) {
+	return r.list(ctx, `SELECT `+sloColumns+` FROM slos WHERE enabled = TRUE ORDER BY name`)
+}
+
+func (r *sloRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.SLO, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取SLO列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	slos := make([]*models.SLO, 0)
+	for rows.Next() {
+		slo, err := scanSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描SLO失败: %w", err)
+		}
+		slos = append(slos, slo)
+	}
+
+	return slos, rows.Err()
+}
+
+// Update 更新 SLO 定义和关联的告警规则，错误预算计算结果不变
+func (r *sloRepository) Update(ctx context.Context, slo *models.SLO) error {
+	slo.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE slos SET
+			name = $2, description = $3, service_id = $4, data_source_id = $5, error_query = $6,
+			total_query = $7, target = $8, window_days = $9, enabled = $10, page_rule_id = $11,
+			ticket_rule_id = $12, updated_at = $13
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		slo.ID, slo.Name, slo.Description, slo.ServiceID, slo.DataSourceID, slo.ErrorQuery, slo.TotalQuery,
+		slo.Target, slo.WindowDays, slo.Enabled, slo.PageRuleID, slo.TicketRuleID, slo.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrSLOExists
+		}
+		return fmt.Errorf("更新SLO失败: %w", err)
+	}
+
+	return checkSLOAffected(result)
+}
+
+// UpdateBudget 保存错误预算计算结果
+func (r *sloRepository) UpdateBudget(ctx context.Context, id string, budget *models.SLOBudget) error {
+	query := `
+		UPDATE slos SET
+			budget_status = $2, error_ratio = $3, budget_remaining = $4, burn_rate = $5,
+			evaluated_at = $6, last_error = $7
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		id, budget.Status, budget.ErrorRatio, budget.Remaining, budget.BurnRate, budget.EvaluatedAt, budget.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("保存错误预算失败: %w", err)
+	}
+
+	return checkSLOAffected(result)
+}
+
+// Delete 删除 SLO
+func (r *sloRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM slos WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除SLO失败: %w", err)
+	}
+
+	return checkSLOAffected(result)
+}
+
+// checkSLOAffected 没有更新任何行时返回 SLO 不存在
+func checkSLOAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrSLONotFound
+	}
+
+	return nil
+}
+
+// scanSLO 扫描 SLO
+func scanSLO(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.SLO, error) {
+	var slo models.SLO
+
+	err := scanner.Scan(
+		&slo.ID, &slo.Name, &slo.Description, &slo.ServiceID, &slo.DataSourceID, &slo.ErrorQuery, &slo.TotalQuery,
+		&slo.Target, &slo.WindowDays, &slo.Enabled, &slo.PageRuleID, &slo.TicketRuleID, &slo.Budget.Status,
+		&slo.Budget.ErrorRatio, &slo.Budget.Remaining, &slo.Budget.BurnRate, &slo.Budget.EvaluatedAt,
+		&slo.Budget.LastError, &slo.CreatedBy, &slo.CreatedAt, &slo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slo, nil
+}
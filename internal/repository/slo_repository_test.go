@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var sloTestColumns = []string{
+	"id", "name", "description", "service_id", "data_source_id", "error_query", "total_query", "target",
+	"window_days", "enabled", "page_rule_id", "ticket_rule_id", "budget_status", "error_ratio", "budget_remaining",
+	"burn_rate", "evaluated_at", "last_error", "created_by", "created_at", "updated_at",
+}
+
+func TestSLORepository_ListByService(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSLORepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .+ FROM slos WHERE service_id = \$1 ORDER BY name`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows(sloTestColumns).
+			AddRow("slo1", "checkout-availability", "", "s1", "ds1", "sum(rate(errors[{{.window}}]))",
+				"sum(rate(requests[{{.window}}]))", 99.9, 30, true, "r1", "r2", "warning", 0.0008, 20.0,
+				2.5, now, nil, "admin", now, now).
+			AddRow("slo2", "checkout-latency", "", "s1", "ds1", "sum(rate(slow[{{.window}}]))",
+				"sum(rate(requests[{{.window}}]))", 99, 7, true, nil, nil, "unknown", nil, nil,
+				nil, nil, nil, "admin", now, now))
+
+	slos, err := repo.List(context.Background(), "s1")
+	require.NoError(t, err)
+	require.Len(t, slos, 2)
+
+	assert.Equal(t, models.SLOBudgetStatusWarning, slos[0].Budget.Status)
+	require.NotNil(t, slos[0].Budget.Remaining)
+	assert.Equal(t, 20.0, *slos[0].Budget.Remaining)
+	require.NotNil(t, slos[0].PageRuleID)
+	assert.Equal(t, "r1", *slos[0].PageRuleID)
+
+	assert.Equal(t, models.SLOBudgetStatusUnknown, slos[1].Budget.Status)
+	assert.Nil(t, slos[1].Budget.Remaining)
+	assert.Nil(t, slos[1].PageRuleID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSLORepository_UpdateBudgetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSLORepository(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+	ratio, remaining := 0.0002, 80.0
+	budget := &models.SLOBudget{
+		Status: models.SLOBudgetStatusHealthy, ErrorRatio: &ratio, Remaining: &remaining, EvaluatedAt: &now,
+	}
+
+	mock.ExpectExec(`UPDATE slos SET\s+budget_status = \$2`).
+		WithArgs("missing", models.SLOBudgetStatusHealthy, &ratio, &remaining, nil, &now, nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateBudget(context.Background(), "missing", budget)
+	assert.ErrorIs(t, err, models.ErrSLONotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
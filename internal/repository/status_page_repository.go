@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/models"
+)
+
+const (
+	statusIncidentColumns = `id, kind, title, status, impact, components, ticket_id, scheduled_start, scheduled_end,
+		resolved_at, created_by, created_at, updated_at`
+	statusIncidentUpdateColumns = `id, incident_id, status, message, notify, notified_at, created_by, created_at`
+	statusSubscriberColumns     = `id, email, components, confirmed_at, created_at`
+)
+
+// statusPageRepository 状态页仓储实现
+type statusPageRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewStatusPageRepository 创建状态页仓储实例
+func NewStatusPageRepository(db *sqlx.DB) StatusPageRepository {
+	return &statusPageRepository{db: db}
+}
+
+// NewStatusPageRepositoryWithTx 创建带事务的状态页仓储实例
+func NewStatusPageRepositoryWithTx(tx *sqlx.Tx) StatusPageRepository {
+	return &statusPageRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *statusPageRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// CreateIncident 创建事件及其第一条进展说明
+func (r *statusPageRepository) CreateIncident(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error {
+	if incident.ID == "" {
+		incident.ID = uuid.New().String()
+	}
+	now := time.Now()
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+
+	components, err := json.Marshal(incident.Components)
+	if err != nil {
+		return fmt.Errorf("序列化受影响组件失败: %w", err)
+	}
+
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO status_incidents (
+				id, kind, title, status, impact, components, ticket_id, scheduled_start, scheduled_end,
+				resolved_at, created_by, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+		if _, err := tx.ExecContext(ctx, query,
+			incident.ID, incident.Kind, incident.Title, incident.Status, incident.Impact, components, incident.TicketID,
+			incident.ScheduledStart, incident.ScheduledEnd, incident.ResolvedAt, incident.CreatedBy,
+			incident.CreatedAt, incident.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("创建状态页事件失败: %w", err)
+		}
+
+		update.IncidentID = incident.ID
+		return insertStatusIncidentUpdate(ctx, tx, update)
+	})
+}
+
+// GetIncident 根据ID获取事件
+func (r *statusPageRepository) GetIncident(ctx context.Context, id string) (*models.StatusIncident, error) {
+	query := `SELECT ` + statusIncidentColumns + ` FROM status_incidents WHERE id = $1`
+
+	incident, err := scanStatusIncident(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrStatusIncidentNotFound
+		}
+		return nil, fmt.Errorf("获取状态页事件失败: %w", err)
+	}
+	return incident, nil
+}
+
+// ListIncidents 获取事件，按创建时间倒序
+func (r *statusPageRepository) ListIncidents(ctx context.Context, filter *models.StatusIncidentFilter) ([]*models.StatusIncident, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Kind != nil {
+		args = append(args, *filter.Kind)
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if filter.Open {
+		if filter.ClosedSince != nil {
+			args = append(args, *filter.ClosedSince)
+			conditions = append(conditions, fmt.Sprintf("(resolved_at IS NULL OR resolved_at >= $%d)", len(args)))
+		} else {
+			conditions = append(conditions, "resolved_at IS NULL")
+		}
+	}
+
+	query := `SELECT ` + statusIncidentColumns + ` FROM status_incidents`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取状态页事件列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make([]*models.StatusIncident, 0)
+	for rows.Next() {
+		incident, err := scanStatusIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描状态页事件失败: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, rows.Err()
+}
+
+// UpdateIncident 更新事件的基本信息
+func (r *statusPageRepository) UpdateIncident(ctx context.Context, incident *models.StatusIncident) error {
+	incident.UpdatedAt = time.Now()
+
+	components, err := json.Marshal(incident.Components)
+	if err != nil {
+		return fmt.Errorf("序列化受影响组件失败: %w", err)
+	}
+
+	query := `
+		UPDATE status_incidents SET
+			title = $1,
+			impact = $2,
+			components = $3,
+			ticket_id = $4,
+			scheduled_start = $5,
+			scheduled_end = $6,
+			updated_at = $7
+		WHERE id = $8`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		incident.Title, incident.Impact, components, incident.TicketID,
+		incident.ScheduledStart, incident.ScheduledEnd, incident.UpdatedAt, incident.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新状态页事件失败: %w", err)
+	}
+	return statusIncidentAffected(result)
+}
+
+// AddUpdate 发布进展说明，同时更新事件的进展、影响程度和结束时间
+func (r *statusPageRepository) AddUpdate(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error {
+	incident.UpdatedAt = time.Now()
+
+	return inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE status_incidents SET
+				status = $1,
+				impact = $2,
+				resolved_at = $3,
+				updated_at = $4
+			WHERE id = $5`
+
+		result, err := tx.ExecContext(ctx, query,
+			incident.Status, incident.Impact, incident.ResolvedAt, incident.UpdatedAt, incident.ID)
+		if err != nil {
+			return fmt.Errorf("更新状态页事件进展失败: %w", err)
+		}
+		if err := statusIncidentAffected(result); err != nil {
+			return err
+		}
+
+		update.IncidentID = incident.ID
+		return insertStatusIncidentUpdate(ctx, tx, update)
+	})
+}
+
+// DeleteIncident 删除事件及其进展说明
+func (r *statusPageRepository) DeleteIncident(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM status_incidents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除状态页事件失败: %w", err)
+	}
+	return statusIncidentAffected(result)
+}
+
+// ListUpdates 获取事件的进展说明，按发布时间倒序
+func (r *statusPageRepository) ListUpdates(ctx context.Context, incidentIDs []string) ([]*models.StatusIncidentUpdate, error) {
+	updates := make([]*models.StatusIncidentUpdate, 0)
+	if len(incidentIDs) == 0 {
+		return updates, nil
+	}
+
+	query := `SELECT ` + statusIncidentUpdateColumns + ` FROM status_incident_updates
+		WHERE incident_id = ANY($1) ORDER BY created_at DESC`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &updates, query, pq.Array(incidentIDs)); err != nil {
+		return nil, fmt.Errorf("获取状态页事件进展失败: %w", err)
+	}
+	return updates, nil
+}
+
+// ListPendingUpdates 获取需要通知且尚未通知的进展说明，按发布时间升序
+func (r *statusPageRepository) ListPendingUpdates(ctx context.Context, limit int) ([]*models.StatusIncidentUpdate, error) {
+	updates := make([]*models.StatusIncidentUpdate, 0)
+	query := `SELECT ` + statusIncidentUpdateColumns + ` FROM status_incident_updates
+		WHERE notify AND notified_at IS NULL ORDER BY created_at ASC LIMIT $1`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &updates, query, limit); err != nil {
+		return nil, fmt.Errorf("获取待通知的状态页事件进展失败: %w", err)
+	}
+	return updates, nil
+}
+
+// MarkUpdateNotified 标记进展说明已通知，其他实例已标记时返回 false
+func (r *statusPageRepository) MarkUpdateNotified(ctx context.Context, id string, at time.Time) (bool, error) {
+	result, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE status_incident_updates SET notified_at = $1 WHERE id = $2 AND notified_at IS NULL`, at, id)
+	if err != nil {
+		return false, fmt.Errorf("标记状态页事件进展已通知失败: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// UpsertSubscriber 创建订阅者，邮箱已订阅但未确认时更新组件并沿用原 ID，已确认时不修改并返回 false
+func (r *statusPageRepository) UpsertSubscriber(ctx context.Context, subscriber *models.StatusSubscriber) (bool, error) {
+	if subscriber.ID == "" {
+		subscriber.ID = uuid.New().String()
+	}
+	subscriber.CreatedAt = time.Now()
+
+	components, err := json.Marshal(subscriber.Components)
+	if err != nil {
+		return false, fmt.Errorf("序列化订阅组件失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO status_subscribers (id, email, components, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (email) DO UPDATE SET components = EXCLUDED.components
+		WHERE status_subscribers.confirmed_at IS NULL
+		RETURNING id, created_at`
+
+	err = r.getExecutor().QueryRowxContext(ctx, query,
+		subscriber.ID, subscriber.Email, components, subscriber.CreatedAt,
+	).Scan(&subscriber.ID, &subscriber.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("创建状态页订阅失败: %w", err)
+	}
+	return true, nil
+}
+
+// GetSubscriber 根据ID获取订阅者
+func (r *statusPageRepository) GetSubscriber(ctx context.Context, id string) (*models.StatusSubscriber, error) {
+	query := `SELECT ` + statusSubscriberColumns + ` FROM status_subscribers WHERE id = $1`
+
+	subscriber, err := scanStatusSubscriber(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrStatusSubscriberNotFound
+		}
+		return nil, fmt.Errorf("获取状态页订阅失败: %w", err)
+	}
+	return subscriber, nil
+}
+
+// ConfirmSubscriber 确认订阅，已确认的订阅保留第一次确认的时间
+func (r *statusPageRepository) ConfirmSubscriber(ctx context.Context, id string, at time.Time) error {
+	result, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE status_subscribers SET confirmed_at = COALESCE(confirmed_at, $1) WHERE id = $2`, at, id)
+	if err != nil {
+		return fmt.Errorf("确认状态页订阅失败: %w", err)
+	}
+	return statusSubscriberAffected(result)
+}
+
+// DeleteSubscriber 删除订阅者
+func (r *statusPageRepository) DeleteSubscriber(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM status_subscribers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("退订状态页失败: %w", err)
+	}
+	return statusSubscriberAffected(result)
+}
+
+// ListConfirmedSubscribers 获取已确认的订阅者
+func (r *statusPageRepository) ListConfirmedSubscribers(ctx context.Context) ([]*models.StatusSubscriber, error) {
+	query := `SELECT ` + statusSubscriberColumns + ` FROM status_subscribers
+		WHERE confirmed_at IS NOT NULL ORDER BY created_at`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取状态页订阅者失败: %w", err)
+	}
+	defer rows.Close()
+
+	subscribers := make([]*models.StatusSubscriber, 0)
+	for rows.Next() {
+		subscriber, err := scanStatusSubscriber(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描状态页订阅者失败: %w", err)
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	return subscribers, rows.Err()
+}
+
+// insertStatusIncidentUpdate 在事务中写入进展说明
+func insertStatusIncidentUpdate(ctx context.Context, tx *sqlx.Tx, update *models.StatusIncidentUpdate) error {
+	if update.ID == "" {
+		update.ID = uuid.New().String()
+	}
+	update.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO status_incident_updates (id, incident_id, status, message, notify, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.ExecContext(ctx, query,
+		update.ID, update.IncidentID, update.Status, update.Message, update.Notify, update.CreatedBy, update.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("创建状态页事件进展失败: %w", err)
+	}
+	return nil
+}
+
+// statusIncidentAffected 检查事件是否存在
+func statusIncidentAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrStatusIncidentNotFound
+	}
+	return nil
+}
+
+// statusSubscriberAffected 检查订阅者是否存在
+func statusSubscriberAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrStatusSubscriberNotFound
+	}
+	return nil
+}
+
+// scanStatusIncident 扫描 statusIncidentColumns 对应的一行事件
+func scanStatusIncident(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.StatusIncident, error) {
+	var incident models.StatusIncident
+	var components []byte
+	if err := scanner.Scan(
+		&incident.ID, &incident.Kind, &incident.Title, &incident.Status, &incident.Impact, &components,
+		&incident.TicketID, &incident.ScheduledStart, &incident.ScheduledEnd, &incident.ResolvedAt,
+		&incident.CreatedBy, &incident.CreatedAt, &incident.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	incident.Components = []string{}
+	if err := json.Unmarshal(components, &incident.Components); err != nil {
+		return nil, fmt.Errorf("解析受影响组件失败: %w", err)
+	}
+	return &incident, nil
+}
+
+// scanStatusSubscriber 扫描 statusSubscriberColumns 对应的一行订阅者
+func scanStatusSubscriber(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.StatusSubscriber, error) {
+	var subscriber models.StatusSubscriber
+	var components []byte
+	if err := scanner.Scan(
+		&subscriber.ID, &subscriber.Email, &components, &subscriber.ConfirmedAt, &subscriber.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	subscriber.Components = []string{}
+	if err := json.Unmarshal(components, &subscriber.Components); err != nil {
+		return nil, fmt.Errorf("解析订阅组件失败: %w", err)
+	}
+	return &subscriber, nil
+}
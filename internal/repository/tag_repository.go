@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// tagRepository 标签治理仓储实现，统计和替换告警、工单、知识库文章中的标签，并保存标签治理任务
+type tagRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewTagRepository 创建标签治理仓储实例
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// NewTagRepositoryWithTx 创建带事务的标签治理仓储实例
+func NewTagRepositoryWithTx(tx *sqlx.Tx) TagRepository {
+	return &tagRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *tagRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// alertTagsExpr 告警 tags 标签拆分后的标签数组
+const alertTagsExpr = `regexp_split_to_array(btrim(labels->>'tags'), '\s*,\s*')`
+
+// tagConditions 各类资源包含标签 $1 的条件，已删除的资源不参与统计和替换
+var tagConditions = map[models.TagResource]string{
+	models.TagResourceAlert:     `deleted_at IS NULL AND labels ? 'tags' AND $1 = ANY(` + alertTagsExpr + `)`,
+	models.TagResourceTicket:    `deleted_at IS NULL AND tags IS NOT NULL AND tags::jsonb ? $1`,
+	models.TagResourceKnowledge: `deleted_at IS NULL AND tags IS NOT NULL AND tags::jsonb ? $1`,
+}
+
+// tagTables 各类资源所在的表
+var tagTables = map[models.TagResource]string{
+	models.TagResourceAlert:     "alerts",
+	models.TagResourceTicket:    "tickets",
+	models.TagResourceKnowledge: "knowledge_articles",
+}
+
+// replacedTagsQuery 将 elements（带序号的标签）中的 $1 替换为 $2，去除空标签和重复标签并保持原有顺序
+const replacedTagsQuery = `
+	SELECT t, MIN(ord) AS ord FROM (
+		SELECT CASE WHEN e.value = $1 THEN $2 ELSE e.value END AS t, e.ord
+		FROM %s WITH ORDINALITY e(value, ord)
+	) x WHERE t <> '' GROUP BY t`
+
+// Usage 统计使用次数不超过 maxUsage 的标签，包含知识库标签目录中定义但未被使用的孤立标签，按使用次数升序排列
+func (r *tagRepository) Usage(ctx context.Context, maxUsage int64, limit int) ([]*models.TagUsage, error) {
+	query := `
+		WITH used AS (
+			SELECT e.tag, 'alert' AS resource
+			FROM alerts, unnest(` + alertTagsExpr + `) AS e(tag)
+			WHERE deleted_at IS NULL AND labels ? 'tags'
+			UNION ALL
+			SELECT e.tag, 'ticket'
+			FROM tickets, jsonb_array_elements_text(tags::jsonb) AS e(tag)
+			WHERE deleted_at IS NULL AND tags IS NOT NULL
+			UNION ALL
+			SELECT e.tag, 'knowledge'
+			FROM knowledge_articles, jsonb_array_elements_text(tags::jsonb) AS e(tag)
+			WHERE deleted_at IS NULL AND tags IS NOT NULL
+		), counted AS (
+			SELECT tag,
+				COUNT(*) FILTER (WHERE resource = 'alert') AS alerts,
+				COUNT(*) FILTER (WHERE resource = 'ticket') AS tickets,
+				COUNT(*) FILTER (WHERE resource = 'knowledge') AS knowledge,
+				COUNT(*) AS total
+			FROM used
+			WHERE tag <> ''
+			GROUP BY tag
+		)
+		SELECT COALESCE(c.tag, d.name) AS tag,
+			COALESCE(c.alerts, 0) AS alerts, COALESCE(c.tickets, 0) AS tickets,
+			COALESCE(c.knowledge, 0) AS knowledge, COALESCE(c.total, 0) AS total,
+			d.name IS NOT NULL AS defined
+		FROM counted c
+		FULL OUTER JOIN knowledge_tags d ON d.name = c.tag
+		WHERE COALESCE(c.total, 0) <= $1
+		ORDER BY total, tag
+		LIMIT $2`
+
+	usages := make([]*models.TagUsage, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &usages, query, maxUsage, limit); err != nil {
+		return nil, fmt.Errorf("统计标签使用情况失败: %w", err)
+	}
+	return usages, nil
+}
+
+// CountTagged 统计使用标签的资源数量
+func (r *tagRepository) CountTagged(ctx context.Context, tag string) (int64, error) {
+	var total int64
+	for _, resource := range models.TagResources {
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, tagTables[resource], tagConditions[resource])
+
+		var count int64
+		if err := sqlx.GetContext(ctx, r.getExecutor(), &count, query, tag); err != nil {
+			return 0, fmt.Errorf("统计使用标签的%s失败: %w", resource, err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// ReplaceTag 将最多 limit 条资源中的标签 from 替换为 to，to 为空时删除标签，返回更新的资源数量。
+// 更新后的资源不再包含 from，重复调用直到返回值小于 limit 即可处理完全部资源
+func (r *tagRepository) ReplaceTag(ctx context.Context, resource models.TagResource, from, to string, limit int) (int64, error) {
+	var query string
+	switch resource {
+	case models.TagResourceAlert:
+		// 告警标签保存在 tags 标签中，替换后没有标签时删除 tags 标签
+		query = `
+			WITH replaced AS (
+				SELECT id, (
+					SELECT string_agg(t, ',' ORDER BY ord) FROM (` +
+			fmt.Sprintf(replacedTagsQuery, "unnest("+alertTagsExpr+")") + `
+					) y
+				) AS tags
+				FROM alerts
+				WHERE ` + tagConditions[resource] + `
+				LIMIT $3
+			)
+			UPDATE alerts SET
+				labels = CASE WHEN replaced.tags IS NULL THEN alerts.labels - 'tags'
+					ELSE jsonb_set(alerts.labels, '{tags}', to_jsonb(replaced.tags)) END,
+				updated_at = NOW()
+			FROM replaced
+			WHERE alerts.id = replaced.id`
+	case models.TagResourceTicket, models.TagResourceKnowledge:
+		table := tagTables[resource]
+		query = `
+			UPDATE ` + table + ` SET
+				tags = (
+					SELECT COALESCE(jsonb_agg(t ORDER BY ord), '[]'::jsonb) FROM (` +
+			fmt.Sprintf(replacedTagsQuery, "jsonb_array_elements_text(tags::jsonb)") + `
+					) y
+				),
+				updated_at = NOW()
+			WHERE id IN (SELECT id FROM ` + table + ` WHERE ` + tagConditions[resource] + ` LIMIT $3)`
+	default:
+		return 0, fmt.Errorf("%w: 不支持的资源类型 %q", models.ErrInvalidInput, resource)
+	}
+
+	result, err := r.getExecutor().ExecContext(ctx, query, from, to, limit)
+	if err != nil {
+		return 0, fmt.Errorf("替换%s标签失败: %w", resource, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// ReplaceDefinition 更新知识库标签目录：to 未定义时将 from 改名为 to，否则删除 from；to 为空时删除 from
+func (r *tagRepository) ReplaceDefinition(ctx context.Context, from, to string) error {
+	query := `DELETE FROM knowledge_tags WHERE name = $1`
+	args := []interface{}{from}
+	if to != "" {
+		query = `
+			WITH renamed AS (
+				UPDATE knowledge_tags SET name = $2, updated_at = NOW()
+				WHERE name = $1 AND NOT EXISTS (SELECT 1 FROM knowledge_tags WHERE name = $2)
+				RETURNING id
+			)
+			DELETE FROM knowledge_tags WHERE name = $1 AND NOT EXISTS (SELECT 1 FROM renamed)`
+		args = append(args, to)
+	}
+
+	if _, err := r.getExecutor().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("更新知识库标签目录失败: %w", err)
+	}
+	return nil
+}
+
+const tagJobColumns = `id, type, tag, target, status, total, processed, error, created_by, created_at, started_at, finished_at`
+
+// CreateJob 创建标签治理任务
+func (r *tagRepository) CreateJob(ctx context.Context, job *models.TagJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	job.Status = models.TagJobStatusPending
+	job.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO tag_jobs (id, type, tag, target, status, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		job.ID, job.Type, job.Tag, job.Target, job.Status, job.CreatedBy, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建标签治理任务失败: %w", err)
+	}
+	return nil
+}
+
+// GetJob 根据ID获取标签治理任务
+func (r *tagRepository) GetJob(ctx context.Context, id string) (*models.TagJob, error) {
+	var job models.TagJob
+	query := `SELECT ` + tagJobColumns + ` FROM tag_jobs WHERE id = $1`
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &job, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrTagJobNotFound
+		}
+		return nil, fmt.Errorf("获取标签治理任务失败: %w", err)
+	}
+	return &job, nil
+}
+
+// ListJobs 获取最近的标签治理任务
+func (r *tagRepository) ListJobs(ctx context.Context, limit int) ([]*models.TagJob, error) {
+	jobs := make([]*models.TagJob, 0)
+	query := `SELECT ` + tagJobColumns + ` FROM tag_jobs ORDER BY created_at DESC LIMIT $1`
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &jobs, query, limit); err != nil {
+		return nil, fmt.Errorf("获取标签治理任务列表失败: %w", err)
+	}
+	return jobs, nil
+}
+
+// ClaimPendingJob 领取最早创建的待执行任务并标记为执行中，没有待执行任务时返回 nil。
+// 多个实例同时领取时跳过已被锁定的任务，同一任务只会被一个实例执行
+func (r *tagRepository) ClaimPendingJob(ctx context.Context) (*models.TagJob, error) {
+	query := `
+		UPDATE tag_jobs SET status = $1, started_at = NOW()
+		WHERE id = (
+			SELECT id FROM tag_jobs WHERE status = $2
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + tagJobColumns
+
+	var job models.TagJob
+	err := sqlx.GetContext(ctx, r.getExecutor(), &job, query, models.TagJobStatusRunning, models.TagJobStatusPending)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("领取标签治理任务失败: %w", err)
+	}
+	return &job, nil
+}
+
+// UpdateJob 更新标签治理任务的状态和进度
+func (r *tagRepository) UpdateJob(ctx context.Context, job *models.TagJob) error {
+	query := `
+		UPDATE tag_jobs SET status = $2, total = $3, processed = $4, error = $5, finished_at = $6
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		job.ID, job.Status, job.Total, job.Processed, job.Error, job.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("更新标签治理任务失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrTagJobNotFound
+	}
+	return nil
+}
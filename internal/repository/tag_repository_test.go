@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestTagRepository_ReplaceTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	mock.ExpectExec(`UPDATE tickets SET .+ WHERE id IN \(SELECT id FROM tickets WHERE deleted_at IS NULL .+ LIMIT \$3\)`).
+		WithArgs("db", "database", 100).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+	updated, err := repo.ReplaceTag(ctx, models.TagResourceTicket, "db", "database", 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), updated)
+
+	_, err = repo.ReplaceTag(ctx, models.TagResource("dashboard"), "db", "", 100)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_ClaimPendingJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "postgres"))
+
+	// 没有待执行任务时返回 nil
+	mock.ExpectQuery(`UPDATE tag_jobs SET status = \$1, started_at = NOW\(\) .+ FOR UPDATE SKIP LOCKED`).
+		WithArgs(models.TagJobStatusRunning, models.TagJobStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	job, err := repo.ClaimPendingJob(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
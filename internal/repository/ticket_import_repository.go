@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// ticketImportRepository 工单导入任务仓储实现
+type ticketImportRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewTicketImportRepository 创建工单导入任务仓储实例
+func NewTicketImportRepository(db *sqlx.DB) TicketImportRepository {
+	return &ticketImportRepository{db: db}
+}
+
+// NewTicketImportRepositoryWithTx 创建带事务的工单导入任务仓储实例
+func NewTicketImportRepositoryWithTx(tx *sqlx.Tx) TicketImportRepository {
+	return &ticketImportRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *ticketImportRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// ticketImportJobColumns 不含文件内容的列，文件内容只在领取任务时读取
+const ticketImportJobColumns = `id, file_name, format, status, headers, mapping, dry_run, total, processed,
+	imported, failed, errors, error, created_by, created_at, started_at, finished_at`
+
+// CreateJob 创建等待确认列映射的工单导入任务
+func (r *ticketImportRepository) CreateJob(ctx context.Context, job *models.TicketImportJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	job.Status = models.TicketImportStatusAwaitingMapping
+	job.CreatedAt = time.Now()
+	if job.Errors == nil {
+		job.Errors = []*models.TicketImportRowError{}
+	}
+
+	headers, err := json.Marshal(job.Headers)
+	if err != nil {
+		return fmt.Errorf("序列化表头失败: %w", err)
+	}
+	mapping, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return fmt.Errorf("序列化列映射失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO ticket_import_jobs (id, file_name, format, status, headers, mapping, content, total, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		job.ID, job.FileName, job.Format, job.Status, headers, mapping, job.Content, job.Total, job.CreatedBy, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建工单导入任务失败: %w", err)
+	}
+	return nil
+}
+
+// GetJob 根据ID获取工单导入任务，不包含文件内容
+func (r *ticketImportRepository) GetJob(ctx context.Context, id string) (*models.TicketImportJob, error) {
+	query := `SELECT ` + ticketImportJobColumns + ` FROM ticket_import_jobs WHERE id = $1`
+	job, err := scanTicketImportJob(r.getExecutor().QueryRowxContext(ctx, query, id), false)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrTicketImportJobNotFound
+		}
+		return nil, fmt.Errorf("获取工单导入任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// ListJobs 获取最近的工单导入任务，不包含文件内容
+func (r *ticketImportRepository) ListJobs(ctx context.Context, limit int) ([]*models.TicketImportJob, error) {
+	query := `SELECT ` + ticketImportJobColumns + ` FROM ticket_import_jobs ORDER BY created_at DESC LIMIT $1`
+	rows, err := r.getExecutor().QueryxContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取工单导入任务列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.TicketImportJob, 0)
+	for rows.Next() {
+		job, err := scanTicketImportJob(rows, false)
+		if err != nil {
+			return nil, fmt.Errorf("扫描工单导入任务失败: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("获取工单导入任务列表失败: %w", err)
+	}
+	return jobs, nil
+}
+
+// StartJob 保存列映射并将任务置为待执行，同时清空上次试运行的进度和行错误。
+// 只能开始等待确认列映射的任务或已完成的试运行，否则返回 ErrTicketImportJobStarted
+func (r *ticketImportRepository) StartJob(ctx context.Context, id string, mapping map[string]string, dryRun bool) error {
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("序列化列映射失败: %w", err)
+	}
+
+	query := `
+		UPDATE ticket_import_jobs SET status = $2, mapping = $3, dry_run = $4,
+			processed = 0, imported = 0, failed = 0, errors = '[]', error = '', started_at = NULL, finished_at = NULL
+		WHERE id = $1 AND (status = $5 OR (dry_run AND status = $6))`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		id, models.TicketImportStatusPending, mappingJSON, dryRun,
+		models.TicketImportStatusAwaitingMapping, models.TicketImportStatusCompleted,
+	)
+	if err != nil {
+		return fmt.Errorf("开始工单导入任务失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := r.GetJob(ctx, id); err != nil {
+			return err
+		}
+		return models.ErrTicketImportJobStarted
+	}
+	return nil
+}
+
+// ClaimPendingJob 领取最早的待执行任务并标记为执行中，返回的任务包含文件内容，没有待执行任务时返回 nil。
+// 多个实例同时领取时跳过已被锁定的任务，同一任务只会被一个实例执行
+func (r *ticketImportRepository) ClaimPendingJob(ctx context.Context) (*models.TicketImportJob, error) {
+	query := `
+		UPDATE ticket_import_jobs SET status = $1, started_at = NOW()
+		WHERE id = (
+			SELECT id FROM ticket_import_jobs WHERE status = $2
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + ticketImportJobColumns + `, content`
+
+	job, err := scanTicketImportJob(r.getExecutor().QueryRowxContext(ctx, query,
+		models.TicketImportStatusRunning, models.TicketImportStatusPending,
+	), true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("领取工单导入任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateJob 更新工单导入任务的状态、进度和行错误
+func (r *ticketImportRepository) UpdateJob(ctx context.Context, job *models.TicketImportJob) error {
+	errorsJSON, err := json.Marshal(job.Errors)
+	if err != nil {
+		return fmt.Errorf("序列化行错误失败: %w", err)
+	}
+
+	query := `
+		UPDATE ticket_import_jobs SET status = $2, total = $3, processed = $4, imported = $5, failed = $6,
+			errors = $7, error = $8, finished_at = $9
+		WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		job.ID, job.Status, job.Total, job.Processed, job.Imported, job.Failed, errorsJSON, job.Error, job.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("更新工单导入任务失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrTicketImportJobNotFound
+	}
+	return nil
+}
+
+// scanTicketImportJob 扫描工单导入任务，withContent 为 true 时最后一列为文件内容
+func scanTicketImportJob(row interface {
+	Scan(dest ...interface{}) error
+}, withContent bool) (*models.TicketImportJob, error) {
+	var job models.TicketImportJob
+	var headers, mapping, rowErrors []byte
+
+	dest := []interface{}{
+		&job.ID, &job.FileName, &job.Format, &job.Status, &headers, &mapping, &job.DryRun, &job.Total, &job.Processed,
+		&job.Imported, &job.Failed, &rowErrors, &job.Error, &job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt,
+	}
+	if withContent {
+		dest = append(dest, &job.Content)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalTicketImportJSON(headers, &job.Headers); err != nil {
+		return nil, fmt.Errorf("解析表头失败: %w", err)
+	}
+	if err := unmarshalTicketImportJSON(mapping, &job.Mapping); err != nil {
+		return nil, fmt.Errorf("解析列映射失败: %w", err)
+	}
+	if err := unmarshalTicketImportJSON(rowErrors, &job.Errors); err != nil {
+		return nil, fmt.Errorf("解析行错误失败: %w", err)
+	}
+	if job.Headers == nil {
+		job.Headers = []string{}
+	}
+	if job.Mapping == nil {
+		job.Mapping = map[string]string{}
+	}
+	if job.Errors == nil {
+		job.Errors = []*models.TicketImportRowError{}
+	}
+	return &job, nil
+}
+
+// unmarshalTicketImportJSON 解析 JSONB 列，NULL 时保持零值
+func unmarshalTicketImportJSON(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
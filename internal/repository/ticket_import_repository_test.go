@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+var ticketImportJobTestColumns = []string{"id", "file_name", "format", "status", "headers", "mapping", "dry_run", "total",
+	"processed", "imported", "failed", "errors", "error", "created_by", "created_at", "started_at", "finished_at"}
+
+func TestTicketImportRepository_StartJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketImportRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	mock.ExpectExec(`UPDATE ticket_import_jobs SET status = \$2, mapping = \$3, dry_run = \$4,.+WHERE id = \$1 AND \(status = \$5 OR \(dry_run AND status = \$6\)\)`).
+		WithArgs("job-1", models.TicketImportStatusPending, []byte(`{"title":"Subject"}`), true,
+			models.TicketImportStatusAwaitingMapping, models.TicketImportStatusCompleted).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.StartJob(ctx, "job-1", map[string]string{"title": "Subject"}, true))
+
+	// 任务已开始时返回冲突，任务不存在时返回不存在
+	mock.ExpectExec(`UPDATE ticket_import_jobs`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT .+ FROM ticket_import_jobs WHERE id = \$1`).WithArgs("job-1").
+		WillReturnRows(sqlmock.NewRows(ticketImportJobTestColumns).AddRow(
+			"job-1", "export.csv", "csv", "running", []byte(`["Subject"]`), []byte(`{"title":"Subject"}`), false, 10,
+			4, 3, 1, []byte(`[{"row":3,"message":"标题不能为空"}]`), "", "u1", time.Now(), nil, nil))
+	err = repo.StartJob(ctx, "job-1", map[string]string{"title": "Subject"}, false)
+	assert.ErrorIs(t, err, models.ErrTicketImportJobStarted)
+
+	mock.ExpectExec(`UPDATE ticket_import_jobs`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT .+ FROM ticket_import_jobs WHERE id = \$1`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(ticketImportJobTestColumns))
+	err = repo.StartJob(ctx, "missing", map[string]string{"title": "Subject"}, false)
+	assert.ErrorIs(t, err, models.ErrTicketImportJobNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketImportRepository_GetJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketImportRepository(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(`SELECT .+ FROM ticket_import_jobs WHERE id = \$1`).WithArgs("job-1").
+		WillReturnRows(sqlmock.NewRows(ticketImportJobTestColumns).AddRow(
+			"job-1", "export.xlsx", "xlsx", "completed", []byte(`["Subject","Owner"]`), []byte(`{"title":"Subject"}`), true, 2,
+			2, 1, 1, []byte(`[{"row":3,"field":"title","column":"Subject","message":"标题不能为空"}]`), "", "u1", time.Now(), nil, nil))
+	job, err := repo.GetJob(context.Background(), "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Subject", "Owner"}, job.Headers)
+	assert.Equal(t, map[string]string{"title": "Subject"}, job.Mapping)
+	assert.Equal(t, []*models.TicketImportRowError{{Row: 3, Field: "title", Column: "Subject", Message: "标题不能为空"}}, job.Errors)
+	assert.True(t, job.Startable())
+	assert.Nil(t, job.Content)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -17,14 +16,21 @@ import (
 )
 
 type ticketRepository struct {
-	db *sqlx.DB
-	tx *sqlx.Tx
+	db     *sqlx.DB
+	tx     *sqlx.Tx
+	reader Reader
 }
 
 // NewTicketRepository 创建工单仓储实例
 func NewTicketRepository(db *sqlx.DB) TicketRepository {
+	return NewTicketRepositoryWithReader(db, db)
+}
+
+// NewTicketRepositoryWithReader 创建工单仓储实例，列表和统计查询使用 reader（通常为只读副本）
+func NewTicketRepositoryWithReader(db *sqlx.DB, reader Reader) TicketRepository {
 	return &ticketRepository{
-		db: db,
+		db:     db,
+		reader: reader,
 	}
 }
 
@@ -43,6 +49,14 @@ func (r *ticketRepository) getExecutor() sqlx.ExtContext {
 	return r.db
 }
 
+// readExecutor 获取只读查询执行器，事务内使用事务保证读到未提交的写入
+func (r *ticketRepository) readExecutor() Reader {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.reader
+}
+
 // Create 创建工单
 func (r *ticketRepository) Create(ctx context.Context, ticket *models.Ticket) error {
 	if ticket.ID == "" {
@@ -104,6 +118,9 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *models.Ticket) er
 	})
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrTicketExists
+		}
 		return fmt.Errorf("创建工单失败: %w", err)
 	}
 
@@ -118,7 +135,7 @@ func (r *ticketRepository) GetByID(ctx context.Context, id string) (*models.Tick
 	query := `
 		SELECT id, number, title, description, status, priority, category, type, source,
 		       reporter_id, assignee_id, tags, custom_fields, due_date, sla_deadline,
-		       resolved_at, closed_at, created_at, updated_at
+		       resolved_at, closed_at, created_at, updated_at, alert_id
 		FROM tickets 
 		WHERE id = $1 AND deleted_at IS NULL`
 
@@ -126,12 +143,12 @@ func (r *ticketRepository) GetByID(ctx context.Context, id string) (*models.Tick
 		&ticket.ID, &ticket.Number, &ticket.Title, &ticket.Description, &ticket.Status, &ticket.Priority,
 		&ticket.Category, &ticket.Type, &ticket.Source, &ticket.ReporterID, &ticket.AssigneeID,
 		&tagsJSON, &customFieldsJSON, &ticket.DueDate, &ticket.SLADeadline,
-		&ticket.ResolvedAt, &ticket.ClosedAt, &ticket.CreatedAt, &ticket.UpdatedAt,
+		&ticket.ResolvedAt, &ticket.ClosedAt, &ticket.CreatedAt, &ticket.UpdatedAt, &ticket.AlertID,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("工单不存在")
+			return nil, models.ErrTicketNotFound
 		}
 		return nil, fmt.Errorf("获取工单失败: %w", err)
 	}
@@ -231,6 +248,72 @@ func (r *ticketRepository) SoftDelete(ctx context.Context, id string) error {
 
 // List 获取工单列表
 func (r *ticketRepository) List(ctx context.Context, filter *models.TicketFilter) (*models.TicketList, error) {
+	whereClause, args, err := ticketListWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+	argIndex := len(args) + 1
+
+	// 获取总数
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tickets %s", whereClause)
+	var total int64
+	err = r.readExecutor().GetContext(ctx, &total, countQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取工单总数失败: %w", err)
+	}
+
+	// 构建查询
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM tickets %s
+		ORDER BY created_at DESC`, ticketListColumns, whereClause)
+
+	// 添加分页
+	if filter != nil && filter.Page > 0 && filter.PageSize > 0 {
+		offset := (filter.Page - 1) * filter.PageSize
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.PageSize, offset)
+	}
+
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询工单列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicketListRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历工单数据失败: %w", err)
+	}
+
+	// 计算分页信息
+	var totalPages int64 = 1
+	if filter != nil && filter.PageSize > 0 {
+		totalPages = (total + int64(filter.PageSize) - 1) / int64(filter.PageSize)
+	}
+
+	return &models.TicketList{
+		Tickets:    tickets,
+		Total:      total,
+		TotalPages: int(totalPages),
+	}, nil
+}
+
+// ticketListColumns 工单列表查询的列，与 scanTicketListRow 的扫描顺序一致
+const ticketListColumns = `id, number, title, description, status, priority, category, type, source,
+		       reporter_id, assignee_id, tags, custom_fields, due_date, sla_deadline,
+		       resolved_at, closed_at, created_at, updated_at, alert_id`
+
+// ticketListWhere 构建工单列表的 WHERE 子句，参数占位符从 $1 开始
+func ticketListWhere(filter *models.TicketFilter) (string, []interface{}, error) {
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
@@ -310,98 +393,104 @@ func (r *ticketRepository) List(ctx context.Context, filter *models.TicketFilter
 			argIndex++
 		}
 
-
-
 		if filter.Overdue != nil && *filter.Overdue {
 			conditions = append(conditions, fmt.Sprintf("due_date < $%d AND status NOT IN ('resolved', 'closed')", argIndex))
 			args = append(args, time.Now())
 			argIndex++
 		}
+
+		if filter.Query != nil && *filter.Query != "" {
+			where, queryArgs, err := compileFilterQuery(ticketFilterSchema, *filter.Query, argIndex)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, where)
+			args = append(args, queryArgs...)
+		}
 	}
 
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
+	return whereClause, args, nil
+}
 
-	// 获取总数
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tickets %s", whereClause)
-	var total int64
-	err := r.db.GetContext(ctx, &total, countQuery, args...)
+// scanTicketListRow 扫描 ticketListColumns 对应的一行工单
+func scanTicketListRow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Ticket, error) {
+	var ticket models.Ticket
+	var tagsJSON, customFieldsJSON string
+
+	err := scanner.Scan(
+		&ticket.ID, &ticket.Number, &ticket.Title, &ticket.Description, &ticket.Status, &ticket.Priority,
+		&ticket.Category, &ticket.Type, &ticket.Source, &ticket.ReporterID, &ticket.AssigneeID,
+		&tagsJSON, &customFieldsJSON, &ticket.DueDate, &ticket.SLADeadline,
+		&ticket.ResolvedAt, &ticket.ClosedAt, &ticket.CreatedAt, &ticket.UpdatedAt, &ticket.AlertID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("获取工单总数失败: %w", err)
+		return nil, fmt.Errorf("扫描工单数据失败: %w", err)
 	}
 
-	// 构建查询
-	query := fmt.Sprintf(`
-		SELECT id, number, title, description, status, priority, category, type, source,
-		       reporter_id, assignee_id, tags, custom_fields, due_date, sla_deadline,
-		       resolved_at, closed_at, created_at, updated_at
-		FROM tickets %s
-		ORDER BY created_at DESC`, whereClause)
-
-	// 添加分页
-	if filter != nil && filter.Page > 0 && filter.PageSize > 0 {
-		offset := (filter.Page - 1) * filter.PageSize
-		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-		args = append(args, filter.PageSize, offset)
+	// 反序列化标签和自定义字段
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &ticket.Tags); err != nil {
+			return nil, fmt.Errorf("反序列化标签失败: %w", err)
+		}
+	}
+	if customFieldsJSON != "" {
+		if err := json.Unmarshal([]byte(customFieldsJSON), &ticket.CustomFields); err != nil {
+			return nil, fmt.Errorf("反序列化自定义字段失败: %w", err)
+		}
 	}
+	return &ticket, nil
+}
+
+// ListByAlertIDs 批量获取多个告警关联的工单，按创建时间倒序
+func (r *ticketRepository) ListByAlertIDs(ctx context.Context, alertIDs []string) ([]*models.Ticket, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM tickets
+		WHERE alert_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at DESC`, ticketListColumns)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, pq.Array(alertIDs))
 	if err != nil {
-		return nil, fmt.Errorf("查询工单列表失败: %w", err)
+		return nil, fmt.Errorf("根据告警ID批量查询工单失败: %w", err)
 	}
 	defer rows.Close()
 
 	var tickets []*models.Ticket
 	for rows.Next() {
-		var ticket models.Ticket
-		var tagsJSON, customFieldsJSON string
-
-		err := rows.Scan(
-			&ticket.ID, &ticket.Number, &ticket.Title, &ticket.Description, &ticket.Status, &ticket.Priority,
-			&ticket.Category, &ticket.Type, &ticket.Source, &ticket.ReporterID, &ticket.AssigneeID,
-			&tagsJSON, &customFieldsJSON, &ticket.DueDate, &ticket.SLADeadline,
-			&ticket.ResolvedAt, &ticket.ClosedAt, &ticket.CreatedAt, &ticket.UpdatedAt,
-		)
+		ticket, err := scanTicketListRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("扫描工单数据失败: %w", err)
-		}
-
-		// 反序列化标签
-		if tagsJSON != "" {
-			err = json.Unmarshal([]byte(tagsJSON), &ticket.Tags)
-			if err != nil {
-				return nil, fmt.Errorf("反序列化标签失败: %w", err)
-			}
-		}
-
-		// 反序列化自定义字段
-		if customFieldsJSON != "" {
-			err = json.Unmarshal([]byte(customFieldsJSON), &ticket.CustomFields)
-			if err != nil {
-				return nil, fmt.Errorf("反序列化自定义字段失败: %w", err)
-			}
+			return nil, err
 		}
-
-		tickets = append(tickets, &ticket)
+		tickets = append(tickets, ticket)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("遍历工单数据失败: %w", err)
 	}
+	return tickets, nil
+}
 
-	// 计算分页信息
-	var totalPages int64 = 1
-	if filter != nil && filter.PageSize > 0 {
-		totalPages = (total + int64(filter.PageSize) - 1) / int64(filter.PageSize)
+// Stream 按列表的过滤条件和排序逐条读取全部工单，忽略分页参数，fn 返回错误时停止读取
+func (r *ticketRepository) Stream(ctx context.Context, filter *models.TicketFilter, fn func(*models.Ticket) error) error {
+	whereClause, args, err := ticketListWhere(filter)
+	if err != nil {
+		return err
 	}
 
-	return &models.TicketList{
-		Tickets:    tickets,
-		Total:      total,
-		TotalPages: int(totalPages),
-	}, nil
+	query := fmt.Sprintf("SELECT %s FROM tickets %s ORDER BY created_at DESC", ticketListColumns, whereClause)
+	return streamCursor(ctx, r.readExecutor(), query, args, func(rows *sqlx.Rows) error {
+		ticket, err := scanTicketListRow(rows)
+		if err != nil {
+			return err
+		}
+		return fn(ticket)
+	})
 }
 
 // Count 获取工单总数
@@ -439,7 +528,7 @@ func (r *ticketRepository) Count(ctx context.Context, filter *models.TicketFilte
 
 	query := fmt.Sprintf("SELECT COUNT(*) FROM tickets %s", whereClause)
 	var count int64
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := r.readExecutor().GetContext(ctx, &count, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("获取工单总数失败: %w", err)
 	}
@@ -655,6 +744,39 @@ func (r *ticketRepository) GetComments(ctx context.Context, ticketID string) ([]
 	return comments, nil
 }
 
+// ListCommentsByTicketIDs 批量获取多个工单的评论，按创建时间正序
+func (r *ticketRepository) ListCommentsByTicketIDs(ctx context.Context, ticketIDs []string) ([]*models.TicketComment, error) {
+	query := `
+		SELECT id, ticket_id, author_id, content, is_internal, created_at, updated_at
+		FROM ticket_comments
+		WHERE ticket_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at ASC`
+
+	rows, err := r.readExecutor().QueryContext(ctx, query, pq.Array(ticketIDs))
+	if err != nil {
+		return nil, fmt.Errorf("批量获取工单评论失败: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.TicketComment
+	for rows.Next() {
+		var comment models.TicketComment
+		err := rows.Scan(
+			&comment.ID, &comment.TicketID, &comment.AuthorID, &comment.Content,
+			&comment.IsInternal, &comment.CreatedAt, &comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描评论数据失败: %w", err)
+		}
+		comments = append(comments, &comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+	return comments, nil
+}
+
 // UpdateComment 更新工单评论
 func (r *ticketRepository) UpdateComment(ctx context.Context, comment *models.TicketComment) error {
 	comment.UpdatedAt = time.Now()
@@ -678,7 +800,7 @@ func (r *ticketRepository) UpdateComment(ctx context.Context, comment *models.Ti
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("评论不存在或已被删除")
+		return models.ErrCommentNotFound
 	}
 
 	return nil
@@ -702,7 +824,7 @@ func (r *ticketRepository) DeleteComment(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("评论不存在或已被删除")
+		return models.ErrCommentNotFound
 	}
 
 	return nil
@@ -788,7 +910,149 @@ func (r *ticketRepository) DeleteAttachment(ctx context.Context, id string) erro
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("附件不存在或已被删除")
+		return models.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// GetAttachment 根据ID获取工单附件
+func (r *ticketRepository) GetAttachment(ctx context.Context, id string) (*models.TicketAttachment, error) {
+	query := `
+		SELECT id, ticket_id, filename, original_filename, file_path, file_size, mime_type, upload_by,
+		       scan_status, scan_result, scanned_at, created_at
+		FROM ticket_attachments
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	var attachment models.TicketAttachment
+	err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(
+		&attachment.ID, &attachment.TicketID, &attachment.Filename, &attachment.OriginalFilename,
+		&attachment.FilePath, &attachment.FileSize, &attachment.MimeType, &attachment.UploadBy,
+		&attachment.ScanStatus, &attachment.ScanResult, &attachment.ScannedAt, &attachment.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("获取附件失败: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// ListScannableAttachments 获取待扫描的附件：到达重试时间的待扫描附件，以及抢占时间早于 staleBefore 仍在扫描中的附件
+func (r *ticketRepository) ListScannableAttachments(ctx context.Context, now, staleBefore time.Time, limit int) ([]*models.TicketAttachment, error) {
+	query := `
+		SELECT id, ticket_id, filename, original_filename, file_path, file_size, mime_type, upload_by,
+		       scan_status, scan_result, scanned_at, scan_attempts, scan_retry_at, created_at
+		FROM ticket_attachments
+		WHERE deleted_at IS NULL AND (
+			(scan_status = $1 AND (scan_retry_at IS NULL OR scan_retry_at <= $2))
+			OR (scan_status = $3 AND (scan_claimed_at IS NULL OR scan_claimed_at < $4))
+		)
+		ORDER BY created_at ASC
+		LIMIT $5`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query,
+		models.AttachmentScanStatusPending, now, models.AttachmentScanStatusScanning, staleBefore, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("获取待扫描附件失败: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.TicketAttachment
+	for rows.Next() {
+		var attachment models.TicketAttachment
+		err := rows.Scan(
+			&attachment.ID, &attachment.TicketID, &attachment.Filename, &attachment.OriginalFilename,
+			&attachment.FilePath, &attachment.FileSize, &attachment.MimeType, &attachment.UploadBy,
+			&attachment.ScanStatus, &attachment.ScanResult, &attachment.ScannedAt,
+			&attachment.ScanAttempts, &attachment.ScanRetryAt, &attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描附件数据失败: %w", err)
+		}
+		attachments = append(attachments, &attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历附件数据失败: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// ClaimAttachmentScan 将附件标记为扫描中并记录抢占时间和扫描次数，返回是否抢占成功。
+// 可抢占到达重试时间的待扫描附件，以及抢占时间早于 staleBefore 的扫描中附件（扫描进程崩溃后遗留），
+// 多个扫描进程并发时只有一个能抢占同一附件
+func (r *ticketRepository) ClaimAttachmentScan(ctx context.Context, id string, now, staleBefore time.Time) (bool, error) {
+	query := `
+		UPDATE ticket_attachments
+		SET scan_status = $1, scan_claimed_at = $2, scan_attempts = scan_attempts + 1
+		WHERE id = $3 AND deleted_at IS NULL AND (
+			(scan_status = $4 AND (scan_retry_at IS NULL OR scan_retry_at <= $2))
+			OR (scan_status = $1 AND (scan_claimed_at IS NULL OR scan_claimed_at < $5))
+		)`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		models.AttachmentScanStatusScanning, now, id, models.AttachmentScanStatusPending, staleBefore,
+	)
+	if err != nil {
+		return false, fmt.Errorf("标记附件扫描状态失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RetryAttachmentScan 扫描器出错时将附件恢复为待扫描，retryAt 之前不再抢占
+func (r *ticketRepository) RetryAttachmentScan(ctx context.Context, id string, scanResult string, retryAt time.Time) error {
+	query := `
+		UPDATE ticket_attachments
+		SET scan_status = $1, scan_result = $2, scan_retry_at = $3, scan_claimed_at = NULL
+		WHERE id = $4 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, models.AttachmentScanStatusPending, scanResult, retryAt, id)
+	if err != nil {
+		return fmt.Errorf("更新附件扫描重试时间失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// UpdateAttachmentScanResult 更新附件扫描结果
+func (r *ticketRepository) UpdateAttachmentScanResult(ctx context.Context, id string, status models.AttachmentScanStatus, scanResult *string, filePath string) error {
+	query := `
+		UPDATE ticket_attachments
+		SET scan_status = $1, scan_result = $2, scanned_at = $3, file_path = $4, scan_claimed_at = NULL, scan_retry_at = NULL
+		WHERE id = $5 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, status, scanResult, time.Now(), filePath, id)
+	if err != nil {
+		return fmt.Errorf("更新附件扫描结果失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrAttachmentNotFound
 	}
 
 	return nil
@@ -843,11 +1107,11 @@ func (r *ticketRepository) GetHistory(ctx context.Context, ticketID string) ([]*
 // BatchAssign 批量分配工单
 func (r *ticketRepository) BatchAssign(ctx context.Context, ids []string, assigneeID string) error {
 	if len(ids) == 0 {
-		return errors.New("工单ID列表不能为空")
+		return fmt.Errorf("%w: 工单ID列表不能为空", models.ErrInvalidInput)
 	}
 	
 	if strings.TrimSpace(assigneeID) == "" {
-		return errors.New("分配人ID不能为空")
+		return fmt.Errorf("%w: 分配人ID不能为空", models.ErrInvalidInput)
 	}
 	
 	query := `
@@ -872,7 +1136,7 @@ func (r *ticketRepository) BatchAssign(ctx context.Context, ids []string, assign
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("没有工单被分配")
+		return models.ErrTicketNotFound
 	}
 	
 	return nil
@@ -881,11 +1145,11 @@ func (r *ticketRepository) BatchAssign(ctx context.Context, ids []string, assign
 // BatchUpdateStatus 批量更新工单状态
 func (r *ticketRepository) BatchUpdateStatus(ctx context.Context, ids []string, status models.TicketStatus) error {
 	if len(ids) == 0 {
-		return errors.New("工单ID列表不能为空")
+		return fmt.Errorf("%w: 工单ID列表不能为空", models.ErrInvalidInput)
 	}
 	
 	if !status.IsValid() {
-		return errors.New("无效的工单状态")
+		return fmt.Errorf("%w: 无效的工单状态", models.ErrInvalidInput)
 	}
 	
 	query := `
@@ -906,7 +1170,7 @@ func (r *ticketRepository) BatchUpdateStatus(ctx context.Context, ids []string,
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("没有工单被更新")
+		return models.ErrTicketNotFound
 	}
 	
 	return nil
@@ -950,7 +1214,7 @@ func (r *ticketRepository) GetStats(ctx context.Context, filter *models.TicketFi
 		WHERE deleted_at IS NULL 
 		GROUP BY status`
 
-	rows, err := r.db.QueryContext(ctx, statusQuery)
+	rows, err := r.readExecutor().QueryContext(ctx, statusQuery)
 	if err != nil {
 		return nil, fmt.Errorf("按状态统计失败: %w", err)
 	}
@@ -974,7 +1238,7 @@ func (r *ticketRepository) GetStats(ctx context.Context, filter *models.TicketFi
 		WHERE deleted_at IS NULL 
 		GROUP BY priority`
 
-	rows, err = r.db.QueryContext(ctx, priorityQuery)
+	rows, err = r.readExecutor().QueryContext(ctx, priorityQuery)
 	if err != nil {
 		return nil, fmt.Errorf("按优先级统计失败: %w", err)
 	}
@@ -993,21 +1257,21 @@ func (r *ticketRepository) GetStats(ctx context.Context, filter *models.TicketFi
 	// 计算其他统计指标
 	// 获取未分配工单数
 	unassignedQuery := `SELECT COUNT(*) FROM tickets WHERE assignee_id IS NULL AND deleted_at IS NULL`
-	err = r.db.GetContext(ctx, &stats.Unassigned, unassignedQuery)
+	err = r.readExecutor().GetContext(ctx, &stats.Unassigned, unassignedQuery)
 	if err != nil {
 		return nil, fmt.Errorf("获取未分配工单数失败: %w", err)
 	}
 
 	// 获取逾期工单数
 	overdueQuery := `SELECT COUNT(*) FROM tickets WHERE due_date < $1 AND status NOT IN ('resolved', 'closed') AND deleted_at IS NULL`
-	err = r.db.GetContext(ctx, &stats.Overdue, overdueQuery, time.Now())
+	err = r.readExecutor().GetContext(ctx, &stats.Overdue, overdueQuery, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("获取逾期工单数失败: %w", err)
 	}
 
 	// 获取即将到期工单数
 	dueSoonQuery := `SELECT COUNT(*) FROM tickets WHERE due_date BETWEEN $1 AND $2 AND status NOT IN ('resolved', 'closed') AND deleted_at IS NULL`
-	err = r.db.GetContext(ctx, &stats.DueSoon, dueSoonQuery, time.Now(), time.Now().Add(24*time.Hour))
+	err = r.readExecutor().GetContext(ctx, &stats.DueSoon, dueSoonQuery, time.Now(), time.Now().Add(24*time.Hour))
 	if err != nil {
 		return nil, fmt.Errorf("获取即将到期工单数失败: %w", err)
 	}
@@ -1076,30 +1340,32 @@ func (r *ticketRepository) UpdateSLA(ctx context.Context, id string, sla *models
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("工单不存在或已被删除")
+		return models.ErrTicketNotFound
 	}
 	
 	return nil
 }
 
-// BatchCreate 批量创建工单
+// ticketInsertColumns 批量创建工单时写入的列
+var ticketInsertColumns = []string{
+	"id", "title", "description", "status", "priority", "category", "type", "source",
+	"reporter_id", "assignee_id", "tags", "custom_fields", "due_date", "sla_deadline",
+	"created_at", "updated_at",
+}
+
+// BatchCreate 批量创建工单，在一个事务中通过 COPY 或多行 VALUES 写入
 func (r *ticketRepository) BatchCreate(ctx context.Context, tickets []*models.Ticket) error {
 	if len(tickets) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("开始事务失败: %w", err)
-	}
-	defer tx.Rollback()
-
+	now := time.Now()
+	rows := make([][]interface{}, 0, len(tickets))
 	for _, ticket := range tickets {
 		if ticket.ID == "" {
 			ticket.ID = uuid.New().String()
 		}
 
-		now := time.Now()
 		ticket.CreatedAt = now
 		ticket.UpdatedAt = now
 
@@ -1122,27 +1388,21 @@ func (r *ticketRepository) BatchCreate(ctx context.Context, tickets []*models.Ti
 			return fmt.Errorf("序列化自定义字段失败: %w", err)
 		}
 
-		query := `
-			INSERT INTO tickets (
-				id, title, description, status, priority, category, type, source,
-				reporter_id, assignee_id, tags, custom_fields, due_date, sla_deadline,
-				created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-			)`
-
-		_, err = tx.ExecContext(ctx, query,
+		rows = append(rows, []interface{}{
 			ticket.ID, ticket.Title, ticket.Description, ticket.Status, ticket.Priority,
 			ticket.Category, ticket.Type, ticket.Source, ticket.ReporterID, ticket.AssigneeID,
 			string(tagsJSON), string(customFieldsJSON), ticket.DueDate, ticket.SLADeadline,
 			ticket.CreatedAt, ticket.UpdatedAt,
-		)
-		if err != nil {
-			return fmt.Errorf("批量创建工单失败: %w", err)
-		}
+		})
 	}
 
-	return tx.Commit()
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		return bulkInsert(ctx, tx, "tickets", ticketInsertColumns, rows)
+	})
+	if err != nil {
+		return fmt.Errorf("批量创建工单失败: %w", err)
+	}
+	return nil
 }
 
 // BatchUpdate 批量更新工单
@@ -1310,13 +1570,23 @@ func (r *ticketRepository) GetMyTickets(ctx context.Context, userID string, filt
 		argIndex++
 	}
 
+	if filter.Query != nil && *filter.Query != "" {
+		where, queryArgs, err := compileFilterQuery(ticketFilterSchema, *filter.Query, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, where)
+		args = append(args, queryArgs...)
+		argIndex += len(queryArgs)
+	}
+
 	// 构建查询语句
 	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// 计算总数
 	countQuery := "SELECT COUNT(*) FROM tickets " + whereClause
 	var total int64
-	err := r.db.GetContext(ctx, &total, countQuery, args...)
+	err := r.readExecutor().GetContext(ctx, &total, countQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("获取工单总数失败: %w", err)
 	}
@@ -1349,7 +1619,7 @@ func (r *ticketRepository) GetMyTickets(ctx context.Context, userID string, filt
 
 	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("查询我的工单失败: %w", err)
 	}
@@ -1496,7 +1766,7 @@ func (r *ticketRepository) GetByAlertID(ctx context.Context, alertID string) ([]
 // GetOpenCount 获取开放状态工单数量
 func (r *ticketRepository) GetOpenCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.GetContext(ctx, &count, `
+	err := r.readExecutor().GetContext(ctx, &count, `
 		SELECT COUNT(*) 
 		FROM tickets 
 		WHERE status IN ('open', 'assigned', 'in_progress') 
@@ -1508,7 +1778,7 @@ func (r *ticketRepository) GetOpenCount(ctx context.Context) (int64, error) {
 // GetOverdueCount 获取逾期工单数量
 func (r *ticketRepository) GetOverdueCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.GetContext(ctx, &count, `
+	err := r.readExecutor().GetContext(ctx, &count, `
 		SELECT COUNT(*) 
 		FROM tickets 
 		WHERE due_date < NOW() 
@@ -1518,7 +1788,7 @@ func (r *ticketRepository) GetOverdueCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
-// GetOverdueSLA 获取SLA逾期的工单
+// GetOverdueSLA 获取SLA逾期的工单，SLA暂停中的工单不算逾期
 func (r *ticketRepository) GetOverdueSLA(ctx context.Context) ([]*models.Ticket, error) {
 	query := `
 		SELECT id, number, title, description, type, status, priority, severity, source,
@@ -1534,6 +1804,7 @@ func (r *ticketRepository) GetOverdueSLA(ctx context.Context) ([]*models.Ticket,
 		  AND sla_deadline IS NOT NULL 
 		  AND sla_deadline < NOW() 
 		  AND status NOT IN ('resolved', 'closed')
+		  AND NOT EXISTS (SELECT 1 FROM ticket_sla_pauses p WHERE p.ticket_id = tickets.id AND p.resumed_at IS NULL)
 		ORDER BY sla_deadline ASC`
 
 	rows, err := r.getExecutor().QueryContext(ctx, query)
@@ -1609,7 +1880,7 @@ func (r *ticketRepository) GetSLA(ctx context.Context, id string) (*models.Ticke
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("工单SLA不存在")
+			return nil, models.ErrTicketSLANotFound
 		}
 		return nil, fmt.Errorf("查询工单SLA失败: %w", err)
 	}
@@ -1676,7 +1947,7 @@ func (r *ticketRepository) GetTrend(ctx context.Context, start, end time.Time, i
 		ORDER BY time_bucket ASC
 	`, timeGroup, whereClause)
 
-	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("查询工单趋势失败: %w", err)
 	}
@@ -1697,4 +1968,275 @@ func (r *ticketRepository) GetTrend(ctx context.Context, start, end time.Time, i
 	}
 
 	return points, nil
-}
\ No newline at end of file
+}
+
+// AddWorklog 添加工作日志
+func (r *ticketRepository) AddWorklog(ctx context.Context, worklog *models.TicketWorklog) error {
+	if worklog.ID == "" {
+		worklog.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	worklog.CreatedAt = now
+	worklog.UpdatedAt = now
+
+	if worklog.WorkType == "" {
+		worklog.WorkType = models.WorklogTypeInvestigation
+	}
+
+	endedAt := worklog.StartedAt.Add(worklog.GetDuration())
+	worklog.EndedAt = &endedAt
+
+	query := `
+		INSERT INTO ticket_work_logs (
+			id, ticket_id, description, work_type, start_time, end_time,
+			duration_minutes, worker_id, billable, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)`
+
+	_, err := r.getExecutor().ExecContext(ctx, query,
+		worklog.ID, worklog.TicketID, worklog.Description, worklog.WorkType,
+		worklog.StartedAt, worklog.EndedAt, worklog.DurationMinutes, worklog.UserID,
+		worklog.Billable, worklog.CreatedAt, worklog.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("添加工作日志失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorklogs 获取工单工作日志
+func (r *ticketRepository) GetWorklogs(ctx context.Context, ticketID string) ([]*models.TicketWorklog, error) {
+	query := `
+		SELECT id, ticket_id, description, work_type, start_time, end_time,
+		       duration_minutes, worker_id, billable, created_at, updated_at
+		FROM ticket_work_logs
+		WHERE ticket_id = $1
+		ORDER BY start_time DESC`
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("获取工作日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	var worklogs []*models.TicketWorklog
+	for rows.Next() {
+		var worklog models.TicketWorklog
+		err := rows.Scan(
+			&worklog.ID, &worklog.TicketID, &worklog.Description, &worklog.WorkType,
+			&worklog.StartedAt, &worklog.EndedAt, &worklog.DurationMinutes, &worklog.UserID,
+			&worklog.Billable, &worklog.CreatedAt, &worklog.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描工作日志数据失败: %w", err)
+		}
+		worklogs = append(worklogs, &worklog)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历工作日志数据失败: %w", err)
+	}
+
+	return worklogs, nil
+}
+
+// GetWorklog 根据ID获取工作日志
+func (r *ticketRepository) GetWorklog(ctx context.Context, id string) (*models.TicketWorklog, error) {
+	var worklog models.TicketWorklog
+
+	query := `
+		SELECT id, ticket_id, description, work_type, start_time, end_time,
+		       duration_minutes, worker_id, billable, created_at, updated_at
+		FROM ticket_work_logs
+		WHERE id = $1`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query, id).Scan(
+		&worklog.ID, &worklog.TicketID, &worklog.Description, &worklog.WorkType,
+		&worklog.StartedAt, &worklog.EndedAt, &worklog.DurationMinutes, &worklog.UserID,
+		&worklog.Billable, &worklog.CreatedAt, &worklog.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrWorklogNotFound
+		}
+		return nil, fmt.Errorf("获取工作日志失败: %w", err)
+	}
+
+	return &worklog, nil
+}
+
+// UpdateWorklog 更新工作日志
+func (r *ticketRepository) UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) error {
+	worklog.UpdatedAt = time.Now()
+
+	endedAt := worklog.StartedAt.Add(worklog.GetDuration())
+	worklog.EndedAt = &endedAt
+
+	query := `
+		UPDATE ticket_work_logs SET
+			description = $1,
+			work_type = $2,
+			start_time = $3,
+			end_time = $4,
+			duration_minutes = $5,
+			billable = $6,
+			updated_at = $7
+		WHERE id = $8`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		worklog.Description, worklog.WorkType, worklog.StartedAt, worklog.EndedAt,
+		worklog.DurationMinutes, worklog.Billable, worklog.UpdatedAt, worklog.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新工作日志失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWorklogNotFound
+	}
+
+	return nil
+}
+
+// DeleteWorklog 删除工作日志
+func (r *ticketRepository) DeleteWorklog(ctx context.Context, id string) error {
+	query := `DELETE FROM ticket_work_logs WHERE id = $1`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("删除工作日志失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWorklogNotFound
+	}
+
+	return nil
+}
+
+// SyncWorkTime 汇总工作日志并回写工单实际工时
+func (r *ticketRepository) SyncWorkTime(ctx context.Context, ticketID string) (*models.TicketWorkTime, error) {
+	var entryCount, totalMinutes, billableMinutes int64
+
+	query := `
+		SELECT COUNT(*),
+		       COALESCE(SUM(duration_minutes), 0),
+		       COALESCE(SUM(CASE WHEN billable THEN duration_minutes ELSE 0 END), 0)
+		FROM ticket_work_logs
+		WHERE ticket_id = $1`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query, ticketID).Scan(&entryCount, &totalMinutes, &billableMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("汇总工作日志失败: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE tickets SET
+			actual_hours = $1,
+			updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`
+
+	_, err = r.getExecutor().ExecContext(ctx, updateQuery, float64(totalMinutes)/60, time.Now(), ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("更新工单实际工时失败: %w", err)
+	}
+
+	return &models.TicketWorkTime{
+		TicketID:   ticketID,
+		EntryCount: entryCount,
+		WorkTime:   time.Duration(billableMinutes) * time.Minute,
+		ActualTime: time.Duration(totalMinutes) * time.Minute,
+	}, nil
+}
+
+// GetTimesheet 获取工时报表
+func (r *ticketRepository) GetTimesheet(ctx context.Context, filter *models.TimesheetFilter) (*models.Timesheet, error) {
+	conditions := []string{"w.start_time >= $1", "w.start_time < $2", "t.deleted_at IS NULL"}
+	args := []interface{}{filter.StartTime, filter.EndTime}
+	argIndex := 3
+
+	if filter.UserID != nil {
+		conditions = append(conditions, fmt.Sprintf("w.worker_id = $%d", argIndex))
+		args = append(args, *filter.UserID)
+		argIndex++
+	}
+
+	if filter.TeamID != nil {
+		conditions = append(conditions, fmt.Sprintf("t.team_id = $%d", argIndex))
+		args = append(args, *filter.TeamID)
+		argIndex++
+	}
+
+	groupBy := filter.GroupBy
+	if groupBy == "" {
+		groupBy = "user"
+	}
+
+	groupColumn := "w.worker_id"
+	if groupBy == "team" {
+		groupColumn = "t.team_id"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS group_key,
+		       COUNT(DISTINCT w.ticket_id),
+		       COUNT(*),
+		       COALESCE(SUM(w.duration_minutes), 0),
+		       COALESCE(SUM(CASE WHEN w.billable THEN w.duration_minutes ELSE 0 END), 0)
+		FROM ticket_work_logs w
+		JOIN tickets t ON t.id = w.ticket_id
+		WHERE %s
+		GROUP BY group_key
+		ORDER BY 4 DESC`, groupColumn, strings.Join(conditions, " AND "))
+
+	rows, err := r.readExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询工时报表失败: %w", err)
+	}
+	defer rows.Close()
+
+	timesheet := &models.Timesheet{
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+		GroupBy:   groupBy,
+		Entries:   []*models.TimesheetEntry{},
+	}
+
+	for rows.Next() {
+		var groupKey *string
+		entry := &models.TimesheetEntry{}
+		err := rows.Scan(&groupKey, &entry.TicketCount, &entry.EntryCount, &entry.TotalMinutes, &entry.BillableMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("扫描工时报表数据失败: %w", err)
+		}
+
+		if groupBy == "team" {
+			entry.TeamID = groupKey
+		} else {
+			entry.UserID = groupKey
+		}
+
+		timesheet.TotalMinutes += entry.TotalMinutes
+		timesheet.BillableMinutes += entry.BillableMinutes
+		timesheet.Entries = append(timesheet.Entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历工时报表数据失败: %w", err)
+	}
+
+	return timesheet, nil
+}
@@ -88,12 +88,12 @@ func TestTicketRepository_GetByID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "number", "title", "description", "status", "priority", "category", "type", "source",
 		"reporter_id", "assignee_id", "tags", "custom_fields", "due_date", "sla_deadline",
-		"resolved_at", "closed_at", "created_at", "updated_at",
+		"resolved_at", "closed_at", "created_at", "updated_at", "alert_id",
 	}).AddRow(
 		ticket.ID, ticket.Number, ticket.Title, ticket.Description, ticket.Status, ticket.Priority,
 		nil, ticket.Type, ticket.Source, ticket.ReporterID, nil,
 		`["test","incident"]`, `{"key":"value"}`, nil, nil,
-		nil, nil, time.Now(), time.Now(),
+		nil, nil, time.Now(), time.Now(), "alert-1",
 	)
 
 	mock.ExpectQuery("SELECT .+ FROM tickets WHERE id = \\$1").WithArgs("ticket-1").WillReturnRows(rows)
@@ -104,6 +104,7 @@ func TestTicketRepository_GetByID(t *testing.T) {
 	assert.Equal(t, "ticket-1", result.ID)
 	assert.Equal(t, "T-001", result.Number)
 	assert.Equal(t, "测试工单", result.Title)
+	assert.Equal(t, "alert-1", *result.AlertID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -203,12 +204,12 @@ func TestTicketRepository_List(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "number", "title", "description", "status", "priority", "category", "type", "source",
 			"reporter_id", "assignee_id", "tags", "custom_fields", "due_date", "sla_deadline",
-			"resolved_at", "closed_at", "created_at", "updated_at",
+			"resolved_at", "closed_at", "created_at", "updated_at", "alert_id",
 		}).AddRow(
 			"ticket-1", "T-001", "Test Ticket", "Test Description", models.TicketStatusOpen, models.TicketPriorityMedium,
 			nil, models.TicketTypeIncident, models.TicketSourceManual, "user-1", "user-2",
 			string(tagsJSON), string(customFieldsJSON), nil, nil,
-			nil, nil, time.Now(), time.Now(),
+			nil, nil, time.Now(), time.Now(), nil,
 		))
 
 	result, err := repo.List(context.Background(), filter)
@@ -749,4 +750,120 @@ func TestTicketRepository_Create_DatabaseError(t *testing.T) {
 	err = repo.Create(context.Background(), ticket)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+func TestTicketRepository_AddWorklog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewTicketRepository(sqlxDB)
+
+	startedAt := time.Now().Add(-time.Hour)
+	worklog := &models.TicketWorklog{
+		ID:              "worklog-1",
+		TicketID:        "ticket-1",
+		UserID:          "user-1",
+		Description:     "排查数据库连接问题",
+		StartedAt:       startedAt,
+		DurationMinutes: 45,
+		Billable:        true,
+	}
+
+	mock.ExpectExec(`INSERT INTO ticket_work_logs`).
+		WithArgs(
+			worklog.ID, worklog.TicketID, worklog.Description, models.WorklogTypeInvestigation,
+			startedAt, sqlmock.AnyArg(), 45, worklog.UserID, true,
+			sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.AddWorklog(context.Background(), worklog)
+	assert.NoError(t, err)
+	require.NotNil(t, worklog.EndedAt)
+	assert.Equal(t, startedAt.Add(45*time.Minute), *worklog.EndedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketRepository_SyncWorkTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewTicketRepository(sqlxDB)
+
+	ticketID := "ticket-1"
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\)(.+)FROM ticket_work_logs`).
+		WithArgs(ticketID).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "total", "billable"}).AddRow(3, 150, 90))
+
+	mock.ExpectExec(`UPDATE tickets SET`).
+		WithArgs(2.5, sqlmock.AnyArg(), ticketID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	workTime, err := repo.SyncWorkTime(context.Background(), ticketID)
+	assert.NoError(t, err)
+	require.NotNil(t, workTime)
+	assert.Equal(t, int64(3), workTime.EntryCount)
+	assert.Equal(t, 90*time.Minute, workTime.WorkTime)
+	assert.Equal(t, 150*time.Minute, workTime.ActualTime)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketRepository_ClaimAttachmentScan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewTicketRepository(sqlxDB)
+
+	now := time.Now()
+	staleBefore := now.Add(-time.Minute)
+	mock.ExpectExec(`UPDATE ticket_attachments\s+SET scan_status = \$1, scan_claimed_at = \$2, scan_attempts = scan_attempts \+ 1`).
+		WithArgs(models.AttachmentScanStatusScanning, now, "attachment-1", models.AttachmentScanStatusPending, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(`UPDATE ticket_attachments\s+SET scan_status`).
+		WithArgs(models.AttachmentScanStatusScanning, now, "attachment-1", models.AttachmentScanStatusPending, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := repo.ClaimAttachmentScan(context.Background(), "attachment-1", now, staleBefore)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// 已被其他实例抢占且未超时
+	claimed, err = repo.ClaimAttachmentScan(context.Background(), "attachment-1", now, staleBefore)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketRepository_GetAttachment_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewTicketRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT (.+) FROM ticket_attachments`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	attachment, err := repo.GetAttachment(context.Background(), "missing")
+	assert.Nil(t, attachment)
+	assert.ErrorIs(t, err, models.ErrAttachmentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// ticketSLAPauseRepository 工单SLA暂停仓储实现
+type ticketSLAPauseRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewTicketSLAPauseRepository 创建工单SLA暂停仓储实例
+func NewTicketSLAPauseRepository(db *sqlx.DB) TicketSLAPauseRepository {
+	return &ticketSLAPauseRepository{db: db}
+}
+
+// NewTicketSLAPauseRepositoryWithTx 创建带事务的工单SLA暂停仓储实例
+func NewTicketSLAPauseRepositoryWithTx(tx *sqlx.Tx) TicketSLAPauseRepository {
+	return &ticketSLAPauseRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *ticketSLAPauseRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// ticketSLAPauseColumns 暂停区间查询的列
+const ticketSLAPauseColumns = `id, ticket_id, source, reason, paused_by, paused_at, resumed_by, resumed_at`
+
+// Pause 开始暂停，进行中的暂停由部分唯一索引保证每个工单最多一个
+func (r *ticketSLAPauseRepository) Pause(ctx context.Context, pause *models.TicketSLAPause) error {
+	query := `
+		INSERT INTO ticket_sla_pauses (ticket_id, source, reason, paused_by, paused_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (ticket_id) WHERE resumed_at IS NULL DO NOTHING
+		RETURNING id`
+
+	err := r.getExecutor().QueryRowxContext(ctx, query, pause.TicketID, pause.Source, pause.Reason, pause.PausedBy, pause.PausedAt).
+		Scan(&pause.ID)
+	if err == sql.ErrNoRows {
+		return models.ErrTicketSLAPaused
+	}
+	if err != nil {
+		return fmt.Errorf("暂停工单SLA失败: %w", err)
+	}
+	return nil
+}
+
+// Resume 结束进行中的暂停，并在同一事务中顺延工单的SLA截止时间，工单没有截止时间时只结束暂停
+func (r *ticketSLAPauseRepository) Resume(ctx context.Context, ticketID string, source models.TicketSLAPauseSource, resumedBy *string, at time.Time) (*models.TicketSLAPause, error) {
+	var pause models.TicketSLAPause
+	err := inTx(ctx, r.db, r.tx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE ticket_sla_pauses SET resumed_by = $1, resumed_at = GREATEST($2, paused_at)
+			WHERE ticket_id = $3 AND resumed_at IS NULL AND ($4 = '' OR source = $4)
+			RETURNING ` + ticketSLAPauseColumns
+		err := tx.QueryRowxContext(ctx, query, resumedBy, at, ticketID, source).StructScan(&pause)
+		if err == sql.ErrNoRows {
+			return models.ErrTicketSLANotPaused
+		}
+		if err != nil {
+			return fmt.Errorf("恢复工单SLA失败: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE tickets SET sla_deadline = sla_deadline + ($1::timestamptz - $2::timestamptz)
+			WHERE id = $3 AND sla_deadline IS NOT NULL`,
+			*pause.ResumedAt, pause.PausedAt, ticketID)
+		if err != nil {
+			return fmt.Errorf("顺延工单SLA截止时间失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pause, nil
+}
+
+// ListByTicket 获取工单的全部暂停区间
+func (r *ticketSLAPauseRepository) ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketSLAPause, error) {
+	query := `SELECT ` + ticketSLAPauseColumns + ` FROM ticket_sla_pauses WHERE ticket_id = $1 ORDER BY paused_at`
+
+	pauses := []*models.TicketSLAPause{}
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &pauses, query, ticketID); err != nil {
+		return nil, fmt.Errorf("获取工单SLA暂停记录失败: %w", err)
+	}
+	return pauses, nil
+}
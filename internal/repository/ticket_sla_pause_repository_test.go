@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestTicketSLAPauseRepository_PauseConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTicketSLAPauseRepository(sqlx.NewDb(db, "postgres"))
+
+	// 已有进行中的暂停时插入被忽略
+	at := time.Now()
+	mock.ExpectQuery(`INSERT INTO ticket_sla_pauses .+ ON CONFLICT \(ticket_id\) WHERE resumed_at IS NULL DO NOTHING`).
+		WithArgs("t1", models.TicketSLAPauseSourceStatus, nil, nil, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err = repo.Pause(context.Background(), &models.TicketSLAPause{TicketID: "t1", Source: models.TicketSLAPauseSourceStatus, PausedAt: at})
+	assert.ErrorIs(t, err, models.ErrTicketSLAPaused)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketSLAPauseRepository_Resume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTicketSLAPauseRepository(sqlx.NewDb(db, "postgres"))
+
+	pausedAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	resumedAt := pausedAt.Add(3 * time.Hour)
+	user := "u1"
+
+	// 结束暂停和顺延截止时间在同一事务中完成
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ticket_sla_pauses SET resumed_by = \$1, resumed_at = GREATEST\(\$2, paused_at\)`).
+		WithArgs(&user, resumedAt, "t1", models.TicketSLAPauseSource("")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticket_id", "source", "reason", "paused_by", "paused_at", "resumed_by", "resumed_at"}).
+			AddRow("p1", "t1", "manual", nil, "u2", pausedAt, "u1", resumedAt))
+	mock.ExpectExec(`UPDATE tickets SET sla_deadline = sla_deadline \+ \(\$1::timestamptz - \$2::timestamptz\)`).
+		WithArgs(resumedAt, pausedAt, "t1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	pause, err := repo.Resume(context.Background(), "t1", "", &user, resumedAt)
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketSLAPauseSourceManual, pause.Source)
+	assert.Equal(t, 3*time.Hour, pause.Duration(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 没有进行中的暂停
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ticket_sla_pauses`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err = repo.Resume(context.Background(), "t1", models.TicketSLAPauseSourceStatus, nil, resumedAt)
+	assert.ErrorIs(t, err, models.ErrTicketSLANotPaused)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/models"
+)
+
+// ticketSurveyRepository 工单满意度调查仓储实现
+type ticketSurveyRepository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+}
+
+// NewTicketSurveyRepository 创建工单满意度调查仓储实例
+func NewTicketSurveyRepository(db *sqlx.DB) TicketSurveyRepository {
+	return &ticketSurveyRepository{db: db}
+}
+
+// NewTicketSurveyRepositoryWithTx 创建带事务的工单满意度调查仓储实例
+func NewTicketSurveyRepositoryWithTx(tx *sqlx.Tx) TicketSurveyRepository {
+	return &ticketSurveyRepository{tx: tx}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *ticketSurveyRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// ticketSurveyColumns 调查表的全部列
+const ticketSurveyColumns = `id, ticket_id, reporter_id, assignee_id, team, token_hash, score, comment,
+		sent_at, expires_at, responded_at`
+
+// ListUnsurveyed 获取 since 之后关闭且尚未发送调查的工单，按关闭时间升序
+func (r *ticketSurveyRepository) ListUnsurveyed(ctx context.Context, since time.Time, limit int) ([]*models.Ticket, error) {
+	query := `
+		SELECT ` + ticketListColumns + `
+		FROM tickets t
+		WHERE t.deleted_at IS NULL AND t.status = 'closed' AND t.closed_at >= $1
+		  AND NOT EXISTS (SELECT 1 FROM ticket_surveys s WHERE s.ticket_id = t.id)
+		ORDER BY t.closed_at ASC
+		LIMIT $2`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询待发送满意度调查的工单失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicketListRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询待发送满意度调查的工单失败: %w", err)
+	}
+	return tickets, nil
+}
+
+// Create 创建调查，工单已有调查时不创建并返回 false，多个实例同时发送时只有一个实例创建成功
+func (r *ticketSurveyRepository) Create(ctx context.Context, survey *models.TicketSurvey) (bool, error) {
+	query := `
+		INSERT INTO ticket_surveys (id, ticket_id, reporter_id, assignee_id, team, token_hash, comment, sent_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, '', $7, $8)
+		ON CONFLICT (ticket_id) DO NOTHING`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		survey.ID, survey.TicketID, survey.ReporterID, survey.AssigneeID, survey.Team,
+		survey.TokenHash, survey.SentAt, survey.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("创建满意度调查失败: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("创建满意度调查失败: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Delete 删除调查
+func (r *ticketSurveyRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.getExecutor().ExecContext(ctx, `DELETE FROM ticket_surveys WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("删除满意度调查失败: %w", err)
+	}
+	return nil
+}
+
+// GetByTokenHash 根据令牌哈希获取调查
+func (r *ticketSurveyRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.TicketSurvey, error) {
+	var survey models.TicketSurvey
+	query := `SELECT ` + ticketSurveyColumns + ` FROM ticket_surveys WHERE token_hash = $1`
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &survey, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrTicketSurveyNotFound
+		}
+		return nil, fmt.Errorf("获取满意度调查失败: %w", err)
+	}
+	return &survey, nil
+}
+
+// Respond 记录评价，调查已评价时返回 ErrTicketSurveyResponded
+func (r *ticketSurveyRepository) Respond(ctx context.Context, id string, score int, comment string) error {
+	query := `
+		UPDATE ticket_surveys
+		SET score = $2, comment = $3, responded_at = NOW()
+		WHERE id = $1 AND responded_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query, id, score, comment)
+	if err != nil {
+		return fmt.Errorf("保存满意度评价失败: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("保存满意度评价失败: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrTicketSurveyResponded
+	}
+	return nil
+}
+
+// CSAT 按团队或处理人统计发送时间在范围内的调查，未分配处理人的调查归入空处理人
+func (r *ticketSurveyRepository) CSAT(ctx context.Context, filter *models.TicketSurveyFilter) ([]*models.TicketCSAT, error) {
+	conditions := []string{"sent_at >= $1", "sent_at < $2"}
+	args := []interface{}{filter.From, filter.To}
+	if filter.Team != nil {
+		args = append(args, *filter.Team)
+		conditions = append(conditions, fmt.Sprintf("team = $%d", len(args)))
+	}
+
+	key := "team"
+	if filter.GroupBy == models.TicketSurveyGroupByAgent {
+		key = "COALESCE(assignee_id, '')"
+	}
+	query := fmt.Sprintf(`
+		SELECT %s AS key,
+			COUNT(*) AS sent,
+			COUNT(score) AS responses,
+			COUNT(*) FILTER (WHERE score >= %d) AS satisfied,
+			COALESCE(SUM(score), 0) AS score_sum
+		FROM ticket_surveys
+		WHERE %s
+		GROUP BY 1
+		ORDER BY 1`, key, models.TicketSurveySatisfiedScore, strings.Join(conditions, " AND "))
+
+	var groups []*models.TicketCSAT
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &groups, query, args...); err != nil {
+		return nil, fmt.Errorf("统计工单满意度失败: %w", err)
+	}
+	return groups, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/models"
+)
+
+func TestTicketSurveyRepository_CreateAndRespond(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketSurveyRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	survey := &models.TicketSurvey{
+		ID: "survey-1", TicketID: "ticket-1", ReporterID: "user-1", Team: "sre",
+		TokenHash: "hash", SentAt: now, ExpiresAt: now.Add(7 * 24 * time.Hour),
+	}
+
+	// 其他实例已为工单创建调查时不重复创建
+	mock.ExpectExec(`INSERT INTO ticket_surveys .+ ON CONFLICT \(ticket_id\) DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO ticket_surveys`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	created, err := repo.Create(ctx, survey)
+	require.NoError(t, err)
+	assert.True(t, created)
+	created, err = repo.Create(ctx, survey)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	// 已评价的调查不能再次评价
+	mock.ExpectExec(`UPDATE ticket_surveys .+ WHERE id = \$1 AND responded_at IS NULL`).
+		WithArgs("survey-1", 5, "很快").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE ticket_surveys`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.Respond(ctx, "survey-1", 5, "很快"))
+	assert.ErrorIs(t, repo.Respond(ctx, "survey-1", 4, ""), models.ErrTicketSurveyResponded)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTicketSurveyRepository_CSAT(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketSurveyRepository(sqlx.NewDb(db, "postgres"))
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	team := "sre"
+
+	mock.ExpectQuery(`SELECT COALESCE\(assignee_id, ''\) AS key,.+FILTER \(WHERE score >= 4\).+WHERE sent_at >= \$1 AND sent_at < \$2 AND team = \$3`).
+		WithArgs(from, to, team).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "sent", "responses", "satisfied", "score_sum"}).
+			AddRow("user-1", 4, 3, 2, 12).
+			AddRow("", 1, 0, 0, 0))
+
+	groups, err := repo.CSAT(context.Background(), &models.TicketSurveyFilter{
+		From: from, To: to, Team: &team, GroupBy: models.TicketSurveyGroupByAgent,
+	})
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "user-1", groups[0].Key)
+	assert.Equal(t, int64(3), groups[0].Responses)
+	assert.Equal(t, int64(12), groups[0].ScoreSum)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,26 +11,36 @@ import (
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
 
+	"pulse/internal/crypto"
 	"pulse/internal/models"
 )
 
 // userRepository 用户仓储实现
+// 启用敏感信息加密时邮箱和手机号加密存储，并写入盲索引用于等值查询；读取时透明解密
 type userRepository struct {
 	db *sqlx.DB
 	tx *sqlx.Tx
+
+	// encryptor 为空时无法读取已加密的数据
+	encryptor  crypto.FieldEncryptor
+	encryptPII bool
 }
 
-// NewUserRepository 创建用户仓储实例
-func NewUserRepository(db *sqlx.DB) UserRepository {
+// NewUserRepository 创建用户仓储实例，encryptPII 为 false 时新写入的邮箱和手机号为明文
+func NewUserRepository(db *sqlx.DB, encryptor crypto.FieldEncryptor, encryptPII bool) UserRepository {
 	return &userRepository{
-		db: db,
+		db:         db,
+		encryptor:  encryptor,
+		encryptPII: encryptPII && encryptor != nil,
 	}
 }
 
 // NewUserRepositoryWithTx 创建带事务的用户仓储实例
-func NewUserRepositoryWithTx(tx *sqlx.Tx) UserRepository {
+func NewUserRepositoryWithTx(tx *sqlx.Tx, encryptor crypto.FieldEncryptor, encryptPII bool) UserRepository {
 	return &userRepository{
-		tx: tx,
+		tx:         tx,
+		encryptor:  encryptor,
+		encryptPII: encryptPII && encryptor != nil,
 	}
 }
 
@@ -59,17 +69,25 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 		user.Status = models.UserStatusInactive
 	}
 
+	stored, err := r.sealUser(user)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO users (
 			id, username, email, password_hash, display_name, role, status,
-			phone, avatar, department, created_at, updated_at
+			phone, avatar, department, email_index, phone_index, created_at, updated_at
 		) VALUES (
 			:id, :username, :email, :password_hash, :display_name, :role, :status,
-			:phone, :avatar, :department, :created_at, :updated_at
+			:phone, :avatar, :department, :email_index, :phone_index, :created_at, :updated_at
 		)`
 
-	_, err := sqlx.NamedExecContext(ctx, r.getExecutor(), query, user)
+	_, err = sqlx.NamedExecContext(ctx, r.getExecutor(), query, stored)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ErrUserExists
+		}
 		return fmt.Errorf("创建用户失败: %w", err)
 	}
 
@@ -88,11 +106,15 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User,
 	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("用户不存在")
+			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("获取用户失败: %w", err)
 	}
 
+	if err := r.openUser(&user); err != nil {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
@@ -108,31 +130,40 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*m
 	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, username)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("用户不存在")
+			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("获取用户失败: %w", err)
 	}
 
+	if err := r.openUser(&user); err != nil {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
-// GetByEmail 根据邮箱获取用户
+// GetByEmail 根据邮箱获取用户，同时匹配明文和加密存储的邮箱
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
+	condition, args := r.emailCondition(email)
 	query := `
 		SELECT id, username, email, password_hash, display_name, role, status,
 		       phone, avatar, department, last_login_at, created_at, updated_at, deleted_at
 		FROM users 
-		WHERE email = $1 AND deleted_at IS NULL`
+		WHERE ` + condition + ` AND deleted_at IS NULL`
 
-	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, email)
+	err := sqlx.GetContext(ctx, r.getExecutor(), &user, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("用户不存在")
+			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("获取用户失败: %w", err)
 	}
 
+	if err := r.openUser(&user); err != nil {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
@@ -140,6 +171,11 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	user.UpdatedAt = time.Now()
 
+	stored, err := r.sealUser(user)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE users SET 
 			username = :username,
@@ -151,11 +187,13 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 			phone = :phone,
 			avatar = :avatar,
 			department = :department,
+			email_index = :email_index,
+			phone_index = :phone_index,
 			last_login_at = :last_login_at,
 			updated_at = :updated_at
 		WHERE id = :id AND deleted_at IS NULL`
 
-	result, err := sqlx.NamedExecContext(ctx, r.getExecutor(), query, user)
+	result, err := sqlx.NamedExecContext(ctx, r.getExecutor(), query, stored)
 	if err != nil {
 		return fmt.Errorf("更新用户失败: %w", err)
 	}
@@ -166,7 +204,7 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在或已被删除")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -187,7 +225,7 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -213,7 +251,7 @@ func (r *userRepository) SoftDelete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在或已被删除")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -298,6 +336,12 @@ func (r *userRepository) List(ctx context.Context, filter *models.UserFilter) (*
 		return nil, fmt.Errorf("获取用户列表失败: %w", err)
 	}
 
+	for _, user := range users {
+		if err := r.openUser(user); err != nil {
+			return nil, err
+		}
+	}
+
 	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
 
 	return &models.UserList{
@@ -384,8 +428,9 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string)
 // ExistsByEmail 检查邮箱是否存在
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM users WHERE email = $1 AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query, email)
+	condition, args := r.emailCondition(email)
+	query := `SELECT COUNT(*) FROM users WHERE ` + condition + ` AND deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &count, query, args...)
 	if err != nil {
 		return false, fmt.Errorf("检查邮箱存在性失败: %w", err)
 	}
@@ -401,13 +446,13 @@ func (r *userRepository) VerifyPassword(ctx context.Context, username, password
 
 	// 检查用户是否可以登录
 	if !user.CanLogin() {
-		return nil, fmt.Errorf("用户账户已被禁用或锁定")
+		return nil, models.ErrUserDisabled
 	}
 
 	// 验证密码
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
-		return nil, fmt.Errorf("用户名或密码错误")
+		return nil, models.ErrInvalidCredentials
 	}
 
 	return user, nil
@@ -432,7 +477,7 @@ func (r *userRepository) UpdatePassword(ctx context.Context, id, hashedPassword
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在或已被删除")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -457,7 +502,7 @@ func (r *userRepository) UpdateLastLogin(ctx context.Context, id string, loginTi
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在或已被删除")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -482,7 +527,7 @@ func (r *userRepository) UpdateStatus(ctx context.Context, id string, status mod
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("用户不存在或已被删除")
+		return models.ErrUserNotFound
 	}
 
 	return nil
@@ -523,16 +568,21 @@ func (r *userRepository) BatchCreate(ctx context.Context, users []*models.User)
 			user.Status = models.UserStatusInactive
 		}
 
+		stored, err := r.sealUser(user)
+		if err != nil {
+			return err
+		}
+
 		query := `
 			INSERT INTO users (
 				id, username, email, password_hash, display_name, role, status,
-				phone, avatar, department, created_at, updated_at
+				phone, avatar, department, email_index, phone_index, created_at, updated_at
 			) VALUES (
 				:id, :username, :email, :password_hash, :display_name, :role, :status,
-				:phone, :avatar, :department, :created_at, :updated_at
+				:phone, :avatar, :department, :email_index, :phone_index, :created_at, :updated_at
 			)`
 
-		_, err := tx.NamedExecContext(ctx, query, user)
+		_, err = tx.NamedExecContext(ctx, query, stored)
 		if err != nil {
 			return fmt.Errorf("批量创建用户失败: %w", err)
 		}
@@ -556,6 +606,11 @@ func (r *userRepository) BatchUpdate(ctx context.Context, users []*models.User)
 	for _, user := range users {
 		user.UpdatedAt = time.Now()
 
+		stored, err := r.sealUser(user)
+		if err != nil {
+			return err
+		}
+
 		query := `
 			UPDATE users SET 
 				username = :username,
@@ -567,11 +622,13 @@ func (r *userRepository) BatchUpdate(ctx context.Context, users []*models.User)
 				phone = :phone,
 				avatar = :avatar,
 				department = :department,
+				email_index = :email_index,
+				phone_index = :phone_index,
 				last_login_at = :last_login_at,
 				updated_at = :updated_at
 			WHERE id = :id AND deleted_at IS NULL`
 
-		_, err := tx.NamedExecContext(ctx, query, user)
+		_, err = tx.NamedExecContext(ctx, query, stored)
 		if err != nil {
 			return fmt.Errorf("批量更新用户失败: %w", err)
 		}
@@ -609,6 +666,196 @@ func (r *userRepository) BatchDelete(ctx context.Context, ids []string) error {
 	return tx.Commit()
 }
 
+// RotateEncryptionKeys 将用户邮箱和手机号转换为当前加密配置
+// 启用敏感信息加密时加密明文数据、将密文重新加密为当前主密钥版本并重建盲索引；未启用时解密已加密的数据
+// 更新时校验数据未被并发修改，被修改的用户跳过，下次执行时重试
+func (r *userRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptor.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+	exec := r.getExecutor()
+
+	selectQuery := `
+		SELECT id, email, phone, email_index, phone_index
+		FROM users
+		WHERE deleted_at IS NULL AND id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `
+		UPDATE users SET email = $1, phone = $2, email_index = $3, phone_index = $4
+		WHERE id = $5 AND email = $6 AND phone IS NOT DISTINCT FROM $7 AND deleted_at IS NULL`
+
+	lastID := ""
+	for {
+		var rows []*models.User
+		if err := sqlx.SelectContext(ctx, exec, &rows, selectQuery, lastID, batchSize); err != nil {
+			return nil, fmt.Errorf("查询用户失败: %w", err)
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			email, emailIndex, err := r.rotateValue(rotator, row.Email)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			phone, phoneIndex := row.Phone, (*string)(nil)
+			if row.Phone != nil {
+				var rotated string
+				rotated, phoneIndex, err = r.rotateValue(rotator, *row.Phone)
+				if err != nil {
+					result.Failed++
+					result.FailedIDs = append(result.FailedIDs, row.ID)
+					continue
+				}
+				phone = &rotated
+			}
+
+			if email == row.Email && equalStringPtr(phone, row.Phone) &&
+				equalStringPtr(emailIndex, row.EmailIndex) && equalStringPtr(phoneIndex, row.PhoneIndex) {
+				continue
+			}
+
+			res, err := exec.ExecContext(ctx, updateQuery, email, phone, emailIndex, phoneIndex, row.ID, row.Email, row.Phone)
+			if err != nil {
+				// 重建盲索引时大小写不同的重复邮箱会违反唯一约束，需人工处理
+				if isUniqueViolation(err) {
+					result.Failed++
+					result.FailedIDs = append(result.FailedIDs, row.ID)
+					continue
+				}
+				return nil, fmt.Errorf("更新用户失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// rotateValue 将存储的字段值转换为当前加密配置，返回新的存储值和盲索引
+func (r *userRepository) rotateValue(rotator crypto.KeyRotator, stored string) (string, *string, error) {
+	plaintext, err := r.openValue(stored)
+	if err != nil {
+		return "", nil, err
+	}
+	if !r.encryptPII || plaintext == "" {
+		return plaintext, nil, nil
+	}
+
+	index := r.encryptor.BlindIndex(plaintext)
+	if !crypto.IsEncrypted(stored) {
+		encrypted, err := r.encryptor.Encrypt(plaintext)
+		return encrypted, &index, err
+	}
+	rotated, _, err := rotator.Rotate(stored)
+	return rotated, &index, err
+}
+
+// sealUser 返回用于写入数据库的用户副本，启用敏感信息加密时加密邮箱和手机号并计算盲索引
+func (r *userRepository) sealUser(user *models.User) (*models.User, error) {
+	stored := *user
+	stored.EmailIndex = nil
+	stored.PhoneIndex = nil
+	if !r.encryptPII {
+		return &stored, nil
+	}
+
+	email, emailIndex, err := r.sealValue(user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("加密用户邮箱失败: %w", err)
+	}
+	stored.Email = email
+	stored.EmailIndex = emailIndex
+
+	if user.Phone != nil {
+		phone, phoneIndex, err := r.sealValue(*user.Phone)
+		if err != nil {
+			return nil, fmt.Errorf("加密用户手机号失败: %w", err)
+		}
+		stored.Phone = &phone
+		stored.PhoneIndex = phoneIndex
+	}
+
+	return &stored, nil
+}
+
+// sealValue 加密字段值并计算盲索引，空值不加密
+func (r *userRepository) sealValue(value string) (string, *string, error) {
+	if value == "" {
+		return "", nil, nil
+	}
+	encrypted, err := r.encryptor.Encrypt(value)
+	if err != nil {
+		return "", nil, err
+	}
+	index := r.encryptor.BlindIndex(value)
+	return encrypted, &index, nil
+}
+
+// openUser 解密用户的邮箱和手机号，兼容明文存储的数据
+func (r *userRepository) openUser(user *models.User) error {
+	email, err := r.openValue(user.Email)
+	if err != nil {
+		return fmt.Errorf("解密用户邮箱失败: %w", err)
+	}
+	user.Email = email
+
+	if user.Phone != nil {
+		phone, err := r.openValue(*user.Phone)
+		if err != nil {
+			return fmt.Errorf("解密用户手机号失败: %w", err)
+		}
+		user.Phone = &phone
+	}
+
+	return nil
+}
+
+// openValue 解密字段值，明文原样返回
+func (r *userRepository) openValue(value string) (string, error) {
+	if !crypto.IsEncrypted(value) {
+		return value, nil
+	}
+	if r.encryptor == nil {
+		return "", fmt.Errorf("未配置加密服务，无法解密")
+	}
+	return r.encryptor.Decrypt(value)
+}
+
+// emailCondition 邮箱查询条件，配置加密服务时同时按盲索引匹配加密存储的邮箱
+func (r *userRepository) emailCondition(email string) (string, []interface{}) {
+	if r.encryptor == nil {
+		return "email = $1", []interface{}{email}
+	}
+	return "(email = $1 OR email_index = $2)", []interface{}{email, r.encryptor.BlindIndex(email)}
+}
+
+// equalStringPtr 比较两个可空字符串
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // HashPassword 密码加密
 func HashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
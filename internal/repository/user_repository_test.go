@@ -2,16 +2,19 @@ package repository
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
+	"pulse/internal/crypto"
 	"pulse/internal/models"
 )
 
@@ -20,7 +23,7 @@ func setupUserRepositoryTest(t *testing.T) (*userRepository, sqlmock.Sqlmock, fu
 	require.NoError(t, err)
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
-	repo := NewUserRepository(sqlxDB)
+	repo := NewUserRepository(sqlxDB, nil, false)
 
 	cleanup := func() {
 		db.Close()
@@ -48,7 +51,7 @@ func TestUserRepository_Create(t *testing.T) {
 	mock.ExpectExec(`INSERT INTO users`).WithArgs(
 		user.ID, user.Username, user.Email, user.PasswordHash,
 		user.DisplayName, user.Role, user.Status, user.Phone,
-		user.Avatar, user.Department, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		user.Avatar, user.Department, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
 	).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := repo.Create(context.Background(), user)
@@ -56,6 +59,26 @@ func TestUserRepository_Create(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUserRepository_Create_Duplicate(t *testing.T) {
+	repo, mock, cleanup := setupUserRepositoryTest(t)
+	defer cleanup()
+
+	user := &models.User{
+		ID:       uuid.New().String(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+
+	// 用户名唯一约束冲突应映射为 ErrUserExists
+	mock.ExpectExec(`INSERT INTO users`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "users_username_key"})
+
+	err := repo.Create(context.Background(), user)
+	assert.ErrorIs(t, err, models.ErrUserExists)
+	assert.ErrorIs(t, err, models.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUserRepository_GetByID(t *testing.T) {
 	repo, mock, cleanup := setupUserRepositoryTest(t)
 	defer cleanup()
@@ -138,7 +161,7 @@ func TestUserRepository_Update(t *testing.T) {
 	mock.ExpectExec(`UPDATE users SET`).WithArgs(
 		user.Username, user.Email, user.PasswordHash, user.DisplayName,
 		user.Role, user.Status, user.Phone, user.Avatar, user.Department,
-		user.LastLoginAt, sqlmock.AnyArg(), user.ID,
+		nil, nil, user.LastLoginAt, sqlmock.AnyArg(), user.ID,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err := repo.Update(context.Background(), user)
@@ -368,6 +391,7 @@ func TestUserRepository_BatchCreate(t *testing.T) {
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 	}
 	mock.ExpectCommit()
@@ -377,4 +401,106 @@ func TestUserRepository_BatchCreate(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// encryptedArg 匹配信封加密格式的参数
+type encryptedArg struct{}
+
+func (encryptedArg) Match(v driver.Value) bool {
+	value, ok := v.(string)
+	return ok && crypto.IsEncrypted(value)
+}
+
+func TestUserRepository_EncryptPII(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{Keys: "v1:first", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	repo := NewUserRepository(sqlx.NewDb(db, "postgres"), keyManager, true)
+
+	user := &models.User{
+		ID:       uuid.New().String(),
+		Username: "testuser",
+		Email:    "test@example.com",
+		Phone:    stringPtr("+8613800000000"),
+	}
+	emailIndex := keyManager.BlindIndex(user.Email)
+
+	mock.ExpectExec(`INSERT INTO users`).WithArgs(
+		user.ID, user.Username, encryptedArg{}, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), encryptedArg{}, nil, nil,
+		emailIndex, keyManager.BlindIndex(*user.Phone), sqlmock.AnyArg(), sqlmock.AnyArg(),
+	).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), user))
+	// 调用方持有的用户仍为明文
+	assert.Equal(t, "test@example.com", user.Email)
+
+	encryptedEmail, err := keyManager.Encrypt(user.Email)
+	require.NoError(t, err)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "phone", "created_at", "updated_at"}).
+		AddRow(user.ID, user.Username, encryptedEmail, "+8613800000000", time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT .+ FROM users WHERE \(email = \$1 OR email_index = \$2\) AND deleted_at IS NULL`).
+		WithArgs("Test@Example.com", emailIndex).
+		WillReturnRows(rows)
+
+	found, err := repo.GetByEmail(context.Background(), "Test@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "test@example.com", found.Email)
+	// 启用加密前写入的明文原样返回
+	assert.Equal(t, "+8613800000000", *found.Phone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_RotateEncryptionKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{Keys: "v1:first", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	current, err := keyManager.Encrypt("done@example.com")
+	require.NoError(t, err)
+	currentIndex := keyManager.BlindIndex("done@example.com")
+
+	columns := []string{"id", "email", "phone", "email_index", "phone_index"}
+	selectQuery := `SELECT id, email, phone, email_index, phone_index FROM users`
+	updateQuery := `UPDATE users SET email = \$1, phone = \$2, email_index = \$3, phone_index = \$4`
+
+	t.Run("启用时加密明文数据", func(t *testing.T) {
+		repo := NewUserRepository(sqlx.NewDb(db, "postgres"), keyManager, true)
+
+		mock.ExpectQuery(selectQuery).WithArgs("", 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("u-1", "plain@example.com", nil, nil, nil).
+				AddRow("u-2", current, nil, currentIndex, nil))
+		mock.ExpectExec(updateQuery).
+			WithArgs(encryptedArg{}, nil, keyManager.BlindIndex("plain@example.com"), nil, "u-1", "plain@example.com", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectQuery).WithArgs("u-2", 2).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		result, err := repo.RotateEncryptionKeys(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, &models.KeyRotationResult{ActiveVersion: "v1", Scanned: 2, Rotated: 1}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("关闭时解密已加密数据", func(t *testing.T) {
+		repo := NewUserRepository(sqlx.NewDb(db, "postgres"), keyManager, false)
+
+		mock.ExpectQuery(selectQuery).WithArgs("", 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("u-2", current, nil, currentIndex, nil))
+		mock.ExpectExec(updateQuery).
+			WithArgs("done@example.com", nil, nil, nil, "u-2", current, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		result, err := repo.RotateEncryptionKeys(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Rotated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 // Helper functions are now in test_helpers.go
\ No newline at end of file
@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+// webhookIntegrationRepository 告警接入集成仓储实现
+type webhookIntegrationRepository struct {
+	db                *sqlx.DB
+	tx                *sqlx.Tx
+	encryptionService crypto.EncryptionService
+}
+
+// NewWebhookIntegrationRepository 创建告警接入集成仓储实例
+func NewWebhookIntegrationRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) WebhookIntegrationRepository {
+	return &webhookIntegrationRepository{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// NewWebhookIntegrationRepositoryWithTx 创建带事务的告警接入集成仓储实例
+func NewWebhookIntegrationRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) WebhookIntegrationRepository {
+	return &webhookIntegrationRepository{
+		tx:                tx,
+		encryptionService: encryptionService,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *webhookIntegrationRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const webhookIntegrationColumns = `id, name, description, source, secret, allowed_cidrs, enabled,
+		       payload_mapping, last_received_at, created_by, created_at, updated_at`
+
+// Create 创建告警接入集成，密钥加密后存储
+func (r *webhookIntegrationRepository) Create(ctx context.Context, integration *models.WebhookIntegration) error {
+	if integration.ID == "" {
+		integration.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	integration.CreatedAt = now
+	integration.UpdatedAt = now
+
+	secret, err := r.encryptionService.Encrypt(integration.Secret)
+	if err != nil {
+		return fmt.Errorf("加密集成密钥失败: %w", err)
+	}
+
+	payloadMapping, err := marshalPayloadMapping(integration.PayloadMapping)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_integrations (
+			id, name, description, source, secret, allowed_cidrs, enabled, payload_mapping,
+			created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.getExecutor().ExecContext(ctx, query,
+		integration.ID, integration.Name, integration.Description, integration.Source, secret,
+		pq.Array(integration.AllowedCIDRs), integration.Enabled, payloadMapping,
+		integration.CreatedBy, integration.CreatedAt, integration.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("创建告警接入集成失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID 根据ID获取告警接入集成，返回的密钥为明文
+func (r *webhookIntegrationRepository) GetByID(ctx context.Context, id string) (*models.WebhookIntegration, error) {
+	query := `
+		SELECT ` + webhookIntegrationColumns + `
+		FROM webhook_integrations
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	integration, err := scanWebhookIntegration(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrWebhookIntegrationNotFound
+		}
+		return nil, fmt.Errorf("获取告警接入集成失败: %w", err)
+	}
+
+	if integration.Secret, err = r.encryptionService.Decrypt(integration.Secret); err != nil {
+		return nil, fmt.Errorf("解密集成密钥失败: %w", err)
+	}
+
+	return integration, nil
+}
+
+// List 获取告警接入集成列表，不解密密钥
+func (r *webhookIntegrationRepository) List(ctx context.Context) ([]*models.WebhookIntegration, error) {
+	query := `
+		SELECT ` + webhookIntegrationColumns + `
+		FROM webhook_integrations
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC`
+
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警接入集成列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	integrations := make([]*models.WebhookIntegration, 0)
+	for rows.Next() {
+		integration, err := scanWebhookIntegration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描告警接入集成失败: %w", err)
+		}
+		integration.Secret = ""
+		integrations = append(integrations, integration)
+	}
+
+	return integrations, rows.Err()
+}
+
+// Update 更新告警接入集成的基本信息，不修改密钥
+func (r *webhookIntegrationRepository) Update(ctx context.Context, integration *models.WebhookIntegration) error {
+	integration.UpdatedAt = time.Now()
+
+	payloadMapping, err := marshalPayloadMapping(integration.PayloadMapping)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_integrations SET
+			name = $1,
+			description = $2,
+			source = $3,
+			allowed_cidrs = $4,
+			enabled = $5,
+			payload_mapping = $6,
+			updated_at = $7
+		WHERE id = $8 AND deleted_at IS NULL`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		integration.Name, integration.Description, integration.Source,
+		pq.Array(integration.AllowedCIDRs), integration.Enabled, payloadMapping,
+		integration.UpdatedAt, integration.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新告警接入集成失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWebhookIntegrationNotFound
+	}
+
+	return nil
+}
+
+// UpdateSecret 更新集成密钥
+func (r *webhookIntegrationRepository) UpdateSecret(ctx context.Context, id, secret string) error {
+	encrypted, err := r.encryptionService.Encrypt(secret)
+	if err != nil {
+		return fmt.Errorf("加密集成密钥失败: %w", err)
+	}
+
+	result, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE webhook_integrations SET secret = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`,
+		encrypted, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("更新集成密钥失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWebhookIntegrationNotFound
+	}
+
+	return nil
+}
+
+// Delete 软删除告警接入集成
+func (r *webhookIntegrationRepository) Delete(ctx context.Context, id string) error {
+	now := time.Now()
+	result, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE webhook_integrations SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`,
+		now, id)
+	if err != nil {
+		return fmt.Errorf("删除告警接入集成失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWebhookIntegrationNotFound
+	}
+
+	return nil
+}
+
+// TouchLastReceived 记录最近一次接收告警的时间
+func (r *webhookIntegrationRepository) TouchLastReceived(ctx context.Context, id string, at time.Time) error {
+	_, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE webhook_integrations SET last_received_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return fmt.Errorf("更新集成接收时间失败: %w", err)
+	}
+	return nil
+}
+
+// RotateEncryptionKeys 将所有集成密钥重新加密为当前主密钥版本
+// 按ID分批处理；更新时校验密钥未被并发重置，被重置的集成跳过
+func (r *webhookIntegrationRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptionService.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+	exec := r.getExecutor()
+
+	selectQuery := `
+		SELECT id, secret
+		FROM webhook_integrations
+		WHERE deleted_at IS NULL AND id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `
+		UPDATE webhook_integrations SET secret = $1
+		WHERE id = $2 AND secret = $3 AND deleted_at IS NULL`
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID     string `db:"id"`
+			Secret string `db:"secret"`
+		}
+		if err := sqlx.SelectContext(ctx, exec, &rows, selectQuery, lastID, batchSize); err != nil {
+			return nil, fmt.Errorf("查询告警接入集成失败: %w", err)
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			secret, changed, err := rotator.Rotate(row.Secret)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			res, err := exec.ExecContext(ctx, updateQuery, secret, row.ID, row.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("更新集成密钥失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// marshalPayloadMapping 序列化载荷映射，未配置时存储 NULL
+func marshalPayloadMapping(mapping *models.WebhookPayloadMapping) (interface{}, error) {
+	if mapping == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("序列化载荷映射失败: %w", err)
+	}
+	return data, nil
+}
+
+// scanWebhookIntegration 扫描告警接入集成
+func scanWebhookIntegration(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookIntegration, error) {
+	var integration models.WebhookIntegration
+	var allowedCIDRs pq.StringArray
+	var payloadMapping []byte
+
+	err := scanner.Scan(
+		&integration.ID, &integration.Name, &integration.Description, &integration.Source,
+		&integration.Secret, &allowedCIDRs, &integration.Enabled, &payloadMapping,
+		&integration.LastReceivedAt, &integration.CreatedBy, &integration.CreatedAt, &integration.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if payloadMapping != nil {
+		if err := json.Unmarshal(payloadMapping, &integration.PayloadMapping); err != nil {
+			return nil, fmt.Errorf("解析载荷映射失败: %w", err)
+		}
+	}
+
+	integration.AllowedCIDRs = []string(allowedCIDRs)
+	if integration.AllowedCIDRs == nil {
+		integration.AllowedCIDRs = []string{}
+	}
+
+	return &integration, nil
+}
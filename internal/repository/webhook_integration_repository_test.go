@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+func TestWebhookIntegrationRepository_SecretEncrypted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	encryption := crypto.NewAESEncryptionService("test-key")
+	repo := NewWebhookIntegrationRepository(sqlx.NewDb(db, "postgres"), encryption)
+
+	integration := &models.WebhookIntegration{
+		Name:         "prometheus",
+		Source:       models.AlertSourcePrometheus,
+		Secret:       "plain-secret",
+		AllowedCIDRs: []string{"10.0.0.0/8"},
+		Enabled:      true,
+	}
+
+	mock.ExpectExec(`INSERT INTO webhook_integrations`).
+		WithArgs(sqlmock.AnyArg(), "prometheus", nil, models.AlertSourcePrometheus,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), true, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), integration))
+	assert.NotEmpty(t, integration.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 密钥加密存储，读取时解密
+	stored, err := encryption.Encrypt("plain-secret")
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM webhook_integrations WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(integration.ID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "description", "source", "secret", "allowed_cidrs", "enabled",
+			"payload_mapping", "last_received_at", "created_by", "created_at", "updated_at",
+		}).AddRow(integration.ID, "prometheus", nil, "prometheus", stored, "{10.0.0.0/8}", true,
+			[]byte(`{"name":{"path":"@.title"},"severity":{"default":"high"}}`), nil, nil, now, now))
+
+	got, err := repo.GetByID(context.Background(), integration.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "plain-secret", got.Secret)
+	assert.Equal(t, []string{"10.0.0.0/8"}, got.AllowedCIDRs)
+	require.NotNil(t, got.PayloadMapping)
+	assert.Equal(t, "@.title", got.PayloadMapping.Name.Path)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookIntegrationRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookIntegrationRepository(sqlx.NewDb(db, "postgres"), crypto.NewAESEncryptionService("test-key"))
+
+	mock.ExpectExec(`UPDATE webhook_integrations SET deleted_at`).
+		WithArgs(sqlmock.AnyArg(), "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrWebhookIntegrationNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookIntegrationRepository_RotateEncryptionKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{Keys: "v1:first,v2:second", LegacyKey: "jwt-secret"})
+	require.NoError(t, err)
+	legacy, err := crypto.NewAESEncryptionService("jwt-secret").Encrypt("legacy-secret")
+	require.NoError(t, err)
+	current, err := keyManager.Encrypt("current-secret")
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id, secret FROM webhook_integrations`).
+		WithArgs("", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}).
+			AddRow("wi-1", legacy).
+			AddRow("wi-2", current).
+			AddRow("wi-3", "enc:v1:v0:AAAA:AAAA"))
+	mock.ExpectExec(`UPDATE webhook_integrations SET secret = \$1`).
+		WithArgs(encryptedArg{}, "wi-1", legacy).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, secret FROM webhook_integrations`).
+		WithArgs("wi-3", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}))
+
+	repo := NewWebhookIntegrationRepository(sqlx.NewDb(db, "postgres"), keyManager)
+	result, err := repo.RotateEncryptionKeys(context.Background(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.KeyRotationResult{
+		ActiveVersion: "v2",
+		Scanned:       3,
+		Rotated:       1,
+		Failed:        1,
+		FailedIDs:     []string{"wi-3"},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
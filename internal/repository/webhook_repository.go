@@ -9,23 +9,27 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+
+	"pulse/internal/crypto"
 	"pulse/internal/models"
 )
 
 // webhookRepository Webhook仓储实现
+// 签名密钥和携带凭据的请求头加密存储，读取时透明解密
 type webhookRepository struct {
-	db *sqlx.DB
-	tx *sqlx.Tx
+	db                *sqlx.DB
+	tx                *sqlx.Tx
+	encryptionService crypto.EncryptionService
 }
 
 // NewWebhookRepository 创建新的Webhook仓储
-func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
-	return &webhookRepository{db: db}
+func NewWebhookRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) WebhookRepository {
+	return &webhookRepository{db: db, encryptionService: encryptionService}
 }
 
 // NewWebhookRepositoryWithTx 创建带事务的Webhook仓储
-func NewWebhookRepositoryWithTx(tx *sqlx.Tx) WebhookRepository {
-	return &webhookRepository{tx: tx}
+func NewWebhookRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) WebhookRepository {
+	return &webhookRepository{tx: tx, encryptionService: encryptionService}
 }
 
 // getDB 获取数据库连接
@@ -57,17 +61,22 @@ func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook)
 		webhook.Status = models.WebhookStatusActive
 	}
 	
+	secret, headers, err := r.sealWebhook(webhook)
+	if err != nil {
+		return err
+	}
+	
 	// 序列化JSON字段
 	eventsJSON, _ := json.Marshal(webhook.Events)
-	headersJSON, _ := json.Marshal(webhook.Headers)
+	headersJSON, _ := json.Marshal(headers)
 	
 	query := `
 		INSERT INTO webhooks (id, name, url, secret, events, headers, timeout, retry_count, status, created_by, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	
-	_, err := r.getDB().ExecContext(ctx, query,
-		webhook.ID, webhook.Name, webhook.URL, webhook.Secret,
+	_, err = r.getDB().ExecContext(ctx, query,
+		webhook.ID, webhook.Name, webhook.URL, secret,
 		string(eventsJSON), string(headersJSON),
 		webhook.Timeout, webhook.RetryCount, webhook.Status,
 		webhook.CreatedBy, webhook.CreatedAt, webhook.UpdatedAt,
@@ -111,6 +120,10 @@ func (r *webhookRepository) GetByID(ctx context.Context, id string) (*models.Web
 	json.Unmarshal([]byte(eventsJSON), &webhook.Events)
 	json.Unmarshal([]byte(headersJSON), &webhook.Headers)
 	
+	if err := r.openWebhook(&webhook); err != nil {
+		return nil, err
+	}
+	
 	return &webhook, nil
 }
 
@@ -118,6 +131,11 @@ func (r *webhookRepository) GetByID(ctx context.Context, id string) (*models.Web
 func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
 	webhook.UpdatedAt = time.Now()
 	
+	secret, headers, err := r.sealWebhook(webhook)
+	if err != nil {
+		return err
+	}
+	
 	// 序列化Events
 	eventsJSON, err := json.Marshal(webhook.Events)
 	if err != nil {
@@ -125,7 +143,7 @@ func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook)
 	}
 	
 	// 序列化Headers
-	headersJSON, err := json.Marshal(webhook.Headers)
+	headersJSON, err := json.Marshal(headers)
 	if err != nil {
 		return fmt.Errorf("序列化请求头失败: %w", err)
 	}
@@ -145,7 +163,7 @@ func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook)
 	`
 	
 	_, err = r.getDB().ExecContext(ctx, query,
-		webhook.ID, webhook.Name, webhook.URL, webhook.Secret,
+		webhook.ID, webhook.Name, webhook.URL, secret,
 		string(eventsJSON), string(headersJSON), webhook.Timeout, webhook.RetryCount,
 		webhook.Status, webhook.UpdatedAt,
 	)
@@ -259,6 +277,10 @@ func (r *webhookRepository) List(ctx context.Context, filter *models.WebhookFilt
 			return nil, fmt.Errorf("反序列化请求头失败: %w", err)
 		}
 		
+		if err := r.openWebhook(&webhook); err != nil {
+			return nil, err
+		}
+		
 		webhooks = append(webhooks, &webhook)
 	}
 	
@@ -355,6 +377,10 @@ func (r *webhookRepository) GetByURL(ctx context.Context, url string) (*models.W
 		return nil, fmt.Errorf("反序列化请求头失败: %w", err)
 	}
 	
+	if err := r.openWebhook(&webhook); err != nil {
+		return nil, err
+	}
+	
 	return &webhook, nil
 }
 
@@ -627,7 +653,7 @@ func (r *webhookRepository) BatchCreate(ctx context.Context, webhooks []*models.
 	}
 	defer tx.Rollback()
 	
-	repoWithTx := NewWebhookRepositoryWithTx(tx)
+	repoWithTx := NewWebhookRepositoryWithTx(tx, r.encryptionService)
 	
 	for _, webhook := range webhooks {
 		if err := repoWithTx.Create(ctx, webhook); err != nil {
@@ -650,7 +676,7 @@ func (r *webhookRepository) BatchUpdate(ctx context.Context, webhooks []*models.
 	}
 	defer tx.Rollback()
 	
-	repoWithTx := NewWebhookRepositoryWithTx(tx)
+	repoWithTx := NewWebhookRepositoryWithTx(tx, r.encryptionService)
 	
 	for _, webhook := range webhooks {
 		if err := repoWithTx.Update(ctx, webhook); err != nil {
@@ -673,7 +699,7 @@ func (r *webhookRepository) BatchEnable(ctx context.Context, ids []string) error
 	}
 	defer tx.Rollback()
 	
-	repoWithTx := NewWebhookRepositoryWithTx(tx)
+	repoWithTx := NewWebhookRepositoryWithTx(tx, r.encryptionService)
 	
 	for _, id := range ids {
 		if err := repoWithTx.Enable(ctx, id); err != nil {
@@ -696,7 +722,7 @@ func (r *webhookRepository) BatchDisable(ctx context.Context, ids []string) erro
 	}
 	defer tx.Rollback()
 	
-	repoWithTx := NewWebhookRepositoryWithTx(tx)
+	repoWithTx := NewWebhookRepositoryWithTx(tx, r.encryptionService)
 	
 	for _, id := range ids {
 		if err := repoWithTx.Disable(ctx, id); err != nil {
@@ -719,7 +745,7 @@ func (r *webhookRepository) BatchDelete(ctx context.Context, ids []string) error
 	}
 	defer tx.Rollback()
 	
-	repoWithTx := NewWebhookRepositoryWithTx(tx)
+	repoWithTx := NewWebhookRepositoryWithTx(tx, r.encryptionService)
 	
 	for _, id := range ids {
 		if err := repoWithTx.Delete(ctx, id); err != nil {
@@ -760,4 +786,185 @@ func (r *webhookRepository) CleanupInactive(ctx context.Context, before time.Tim
 	
 	rowsAffected, err := result.RowsAffected()
 	return rowsAffected, err
-}
\ No newline at end of file
+}
+// RotateEncryptionKeys 将Webhook签名密钥和敏感请求头重新加密为当前主密钥版本
+// 启用加密前以明文存储的值一并加密；更新时校验未被并发修改，被修改的Webhook跳过，下次执行时重试
+func (r *webhookRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptionService.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+
+	selectQuery := `
+		SELECT id::text, secret, COALESCE(headers::text, '{}')
+		FROM webhooks
+		WHERE deleted_at IS NULL AND id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `
+		UPDATE webhooks SET secret = $1, headers = $2
+		WHERE id = $3 AND secret IS NOT DISTINCT FROM $4 AND COALESCE(headers::text, '{}') = $5 AND deleted_at IS NULL`
+
+	type webhookSecrets struct {
+		id      string
+		secret  *string
+		headers string
+	}
+
+	lastID := ""
+	for {
+		rows, err := r.getDB().QueryContext(ctx, selectQuery, lastID, batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询Webhook失败: %w", err)
+		}
+		var batch []webhookSecrets
+		for rows.Next() {
+			var row webhookSecrets
+			if err := rows.Scan(&row.id, &row.secret, &row.headers); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("扫描Webhook数据失败: %w", err)
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("遍历Webhook数据失败: %w", err)
+		}
+
+		for _, row := range batch {
+			lastID = row.id
+			result.Scanned++
+
+			changed := false
+			secret := row.secret
+			if row.secret != nil {
+				rotated, ok, err := r.rotateValue(rotator, *row.secret)
+				if err != nil {
+					result.Failed++
+					result.FailedIDs = append(result.FailedIDs, row.id)
+					continue
+				}
+				secret, changed = &rotated, ok
+			}
+
+			var headers map[string]string
+			if err := json.Unmarshal([]byte(row.headers), &headers); err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.id)
+				continue
+			}
+			failed := false
+			for key, value := range headers {
+				if !crypto.IsSensitiveHeader(key) {
+					continue
+				}
+				rotated, ok, err := r.rotateValue(rotator, value)
+				if err != nil {
+					failed = true
+					break
+				}
+				headers[key] = rotated
+				changed = changed || ok
+			}
+			if failed {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.id)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			headersJSON, err := json.Marshal(headers)
+			if err != nil {
+				return nil, fmt.Errorf("序列化请求头失败: %w", err)
+			}
+
+			res, err := r.getDB().ExecContext(ctx, updateQuery, secret, string(headersJSON), row.id, row.secret, row.headers)
+			if err != nil {
+				return nil, fmt.Errorf("更新Webhook失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// rotateValue 将密文重新加密为当前主密钥版本，明文值直接加密
+func (r *webhookRepository) rotateValue(rotator crypto.KeyRotator, stored string) (string, bool, error) {
+	if stored == "" {
+		return stored, false, nil
+	}
+	if !crypto.IsEncrypted(stored) {
+		encrypted, err := rotator.Encrypt(stored)
+		return encrypted, err == nil, err
+	}
+	return rotator.Rotate(stored)
+}
+
+// sealWebhook 返回加密后的签名密钥和请求头，不修改原Webhook
+func (r *webhookRepository) sealWebhook(webhook *models.Webhook) (*string, map[string]string, error) {
+	secret := webhook.Secret
+	if secret != nil && *secret != "" {
+		encrypted, err := r.encryptionService.Encrypt(*secret)
+		if err != nil {
+			return nil, nil, fmt.Errorf("加密Webhook密钥失败: %w", err)
+		}
+		secret = &encrypted
+	}
+
+	var headers map[string]string
+	if webhook.Headers != nil {
+		headers = make(map[string]string, len(webhook.Headers))
+		for key, value := range webhook.Headers {
+			if crypto.IsSensitiveHeader(key) && value != "" {
+				encrypted, err := r.encryptionService.Encrypt(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf("加密请求头 %s 失败: %w", key, err)
+				}
+				value = encrypted
+			}
+			headers[key] = value
+		}
+	}
+
+	return secret, headers, nil
+}
+
+// openWebhook 解密签名密钥和请求头，兼容明文存储的数据
+func (r *webhookRepository) openWebhook(webhook *models.Webhook) error {
+	if webhook.Secret != nil && crypto.IsEncrypted(*webhook.Secret) {
+		secret, err := r.encryptionService.Decrypt(*webhook.Secret)
+		if err != nil {
+			return fmt.Errorf("解密Webhook密钥失败: %w", err)
+		}
+		webhook.Secret = &secret
+	}
+
+	for key, value := range webhook.Headers {
+		if !crypto.IsEncrypted(value) {
+			continue
+		}
+		decrypted, err := r.encryptionService.Decrypt(value)
+		if err != nil {
+			return fmt.Errorf("解密请求头 %s 失败: %w", key, err)
+		}
+		webhook.Headers[key] = decrypted
+	}
+
+	return nil
+}
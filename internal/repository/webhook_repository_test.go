@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"pulse/internal/crypto"
 	"pulse/internal/models"
 )
 
@@ -18,8 +20,11 @@ func setupWebhookRepositoryTest(t *testing.T) (*webhookRepository, sqlmock.Sqlmo
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 
+	keyManager, err := crypto.NewKeyManager(crypto.KeyManagerConfig{Keys: "v1:first"})
+	require.NoError(t, err)
+
 	sqlxDB := sqlx.NewDb(db, "postgres")
-	repo := NewWebhookRepository(sqlxDB)
+	repo := NewWebhookRepository(sqlxDB, keyManager)
 
 	cleanup := func() {
 		db.Close()
@@ -98,7 +103,7 @@ func TestWebhookRepository_Update(t *testing.T) {
 	}
 
 	mock.ExpectExec(`UPDATE webhooks SET`).WithArgs(
-		webhook.ID, webhook.Name, webhook.URL, webhook.Secret,
+		webhook.ID, webhook.Name, webhook.URL, encryptedArg{},
 		sqlmock.AnyArg(), sqlmock.AnyArg(), webhook.Timeout, webhook.RetryCount,
 		webhook.Status, sqlmock.AnyArg(),
 	).WillReturnResult(sqlmock.NewResult(0, 1))
@@ -217,4 +222,57 @@ func TestWebhookRepository_CreateLog(t *testing.T) {
 	err := repo.CreateLog(context.Background(), log)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+func TestWebhookRepository_SecretsEncrypted(t *testing.T) {
+	repo, mock, cleanup := setupWebhookRepositoryTest(t)
+	defer cleanup()
+
+	webhook := &models.Webhook{
+		Name:    "Test Webhook",
+		URL:     "https://example.com/webhook",
+		Secret:  stringPtr("shared-secret"),
+		Headers: map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json"},
+	}
+
+	var storedHeaders string
+	mock.ExpectExec("INSERT INTO webhooks").
+		WithArgs(sqlmock.AnyArg(), webhook.Name, webhook.URL, encryptedArg{},
+			sqlmock.AnyArg(), captureArg(&storedHeaders), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), webhook))
+	assert.Equal(t, "shared-secret", *webhook.Secret)
+	assert.NotContains(t, storedHeaders, "Bearer token")
+	assert.Contains(t, storedHeaders, "application/json")
+
+	secret, err := repo.encryptionService.Encrypt("shared-secret")
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT (.+) FROM webhooks WHERE id = \\$1").
+		WithArgs(webhook.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url", "secret", "events", "headers", "timeout", "retry_count", "status", "last_triggered", "created_by", "created_at", "updated_at"}).
+			AddRow(webhook.ID, webhook.Name, webhook.URL, secret, `[]`, storedHeaders, 30, 3,
+				models.WebhookStatusActive, nil, uuid.New(), time.Now(), time.Now()))
+
+	found, err := repo.GetByID(context.Background(), webhook.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "shared-secret", *found.Secret)
+	assert.Equal(t, "Bearer token", found.Headers["Authorization"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// capturedArg 记录实际字符串参数的匹配器
+type capturedArg struct {
+	value *string
+}
+
+func captureArg(value *string) capturedArg {
+	return capturedArg{value: value}
+}
+
+func (c capturedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*c.value = s
+	return ok
+}
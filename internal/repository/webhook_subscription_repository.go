@@ -0,0 +1,442 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+const webhookSubscriptionColumns = `id, name, url, secret, events, enabled, max_attempts, disable_after_failures,
+		       consecutive_failures, disabled_reason, last_delivered_at, created_by, created_at, updated_at`
+
+// webhookDeliveryColumns 事件投递记录查询的列
+const webhookDeliveryColumns = `id, subscription_id, seq, event_id, event, payload, status, attempts, next_attempt_at,
+		       last_status_code, last_response, last_error, last_duration_ms, delivered_at, created_at, updated_at`
+
+// webhookSubscriptionRepository 出站 Webhook 订阅仓储实现
+type webhookSubscriptionRepository struct {
+	db                *sqlx.DB
+	tx                *sqlx.Tx
+	encryptionService crypto.EncryptionService
+}
+
+// NewWebhookSubscriptionRepository 创建出站 Webhook 订阅仓储实例
+func NewWebhookSubscriptionRepository(db *sqlx.DB, encryptionService crypto.EncryptionService) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// NewWebhookSubscriptionRepositoryWithTx 创建带事务的出站 Webhook 订阅仓储实例
+func NewWebhookSubscriptionRepositoryWithTx(tx *sqlx.Tx, encryptionService crypto.EncryptionService) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		tx:                tx,
+		encryptionService: encryptionService,
+	}
+}
+
+// getExecutor 获取数据库执行器（事务或普通连接）
+func (r *webhookSubscriptionRepository) getExecutor() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Create 创建订阅，密钥加密后存储
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if subscription.ID == "" {
+		subscription.ID = uuid.New().String()
+	}
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	secret, err := r.encryptionService.Encrypt(subscription.Secret)
+	if err != nil {
+		return fmt.Errorf("加密订阅密钥失败: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, name, url, secret, events, enabled, max_attempts, disable_after_failures, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		subscription.ID, subscription.Name, subscription.URL, secret, pq.Array(subscription.Events), subscription.Enabled,
+		subscription.MaxAttempts, subscription.DisableAfterFailures, subscription.CreatedBy, subscription.CreatedAt, subscription.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("创建Webhook订阅失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取订阅，返回的密钥为明文
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	subscription, err := scanWebhookSubscription(r.getExecutor().QueryRowxContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrWebhookSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("获取Webhook订阅失败: %w", err)
+	}
+
+	if subscription.Secret, err = r.encryptionService.Decrypt(subscription.Secret); err != nil {
+		return nil, fmt.Errorf("解密订阅密钥失败: %w", err)
+	}
+	return subscription, nil
+}
+
+// List 获取订阅列表，不解密密钥
+func (r *webhookSubscriptionRepository) List(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return r.list(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at DESC`)
+}
+
+// ListEnabled 获取启用的订阅，不解密密钥
+func (r *webhookSubscriptionRepository) ListEnabled(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return r.list(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE enabled = TRUE ORDER BY created_at`)
+}
+
+func (r *webhookSubscriptionRepository) list(ctx context.Context, query string) ([]*models.WebhookSubscription, error) {
+	rows, err := r.getExecutor().QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("获取Webhook订阅列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*models.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描Webhook订阅失败: %w", err)
+		}
+		subscription.Secret = ""
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// Update 更新订阅的配置和投递状态，不修改密钥
+func (r *webhookSubscriptionRepository) Update(ctx context.Context, subscription *models.WebhookSubscription) error {
+	subscription.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE webhook_subscriptions SET
+			name = $1,
+			url = $2,
+			events = $3,
+			enabled = $4,
+			max_attempts = $5,
+			disable_after_failures = $6,
+			consecutive_failures = $7,
+			disabled_reason = $8,
+			last_delivered_at = $9,
+			updated_at = $10
+		WHERE id = $11`
+
+	result, err := r.getExecutor().ExecContext(ctx, query,
+		subscription.Name, subscription.URL, pq.Array(subscription.Events), subscription.Enabled,
+		subscription.MaxAttempts, subscription.DisableAfterFailures, subscription.ConsecutiveFailures,
+		subscription.DisabledReason, subscription.LastDeliveredAt, subscription.UpdatedAt, subscription.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新Webhook订阅失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+// UpdateSecret 更新订阅密钥
+func (r *webhookSubscriptionRepository) UpdateSecret(ctx context.Context, id, secret string) error {
+	encrypted, err := r.encryptionService.Encrypt(secret)
+	if err != nil {
+		return fmt.Errorf("加密订阅密钥失败: %w", err)
+	}
+
+	result, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE webhook_subscriptions SET secret = $1, updated_at = $2 WHERE id = $3`, encrypted, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("更新订阅密钥失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+// Delete 删除订阅及其投递记录
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getExecutor().ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("删除Webhook订阅失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+// Claim 获取订阅的投递租约，其他实例持有未过期的租约时返回 false
+func (r *webhookSubscriptionRepository) Claim(ctx context.Context, id string, until time.Time) (bool, error) {
+	result, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE webhook_subscriptions SET delivering_until = $1
+		WHERE id = $2 AND (delivering_until IS NULL OR delivering_until < NOW())`, until, id)
+	if err != nil {
+		return false, fmt.Errorf("获取订阅投递租约失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新结果失败: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// Release 释放订阅的投递租约
+func (r *webhookSubscriptionRepository) Release(ctx context.Context, id string) error {
+	if _, err := r.getExecutor().ExecContext(ctx,
+		`UPDATE webhook_subscriptions SET delivering_until = NULL WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("释放订阅投递租约失败: %w", err)
+	}
+	return nil
+}
+
+// CreateDelivery 为订阅追加待投递的事件，Seq 由数据库按写入顺序生成
+func (r *webhookSubscriptionRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+	}
+	now := time.Now()
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event, payload, status, next_attempt_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING seq`
+
+	if err := r.getExecutor().QueryRowxContext(ctx, query,
+		delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.Event, delivery.Payload,
+		delivery.Status, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	).Scan(&delivery.Seq); err != nil {
+		return fmt.Errorf("创建Webhook投递记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetDelivery 获取订阅的投递记录
+func (r *webhookSubscriptionRepository) GetDelivery(ctx context.Context, subscriptionID, id string) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	err := sqlx.GetContext(ctx, r.getExecutor(), delivery,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1 AND subscription_id = $2`, id, subscriptionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("获取Webhook投递记录失败: %w", err)
+	}
+	return delivery, nil
+}
+
+// NextDelivery 获取订阅中 Seq 最小的待投递事件，没有待投递事件时返回 nil
+func (r *webhookSubscriptionRepository) NextDelivery(ctx context.Context, subscriptionID string) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	err := sqlx.GetContext(ctx, r.getExecutor(), delivery, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND status = $2
+		ORDER BY seq
+		LIMIT 1`, subscriptionID, models.WebhookDeliveryPending)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取待投递事件失败: %w", err)
+	}
+	return delivery, nil
+}
+
+// UpdateDelivery 更新投递结果
+func (r *webhookSubscriptionRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE webhook_deliveries SET
+			status = $1,
+			attempts = $2,
+			next_attempt_at = $3,
+			last_status_code = $4,
+			last_response = $5,
+			last_error = $6,
+			last_duration_ms = $7,
+			delivered_at = $8,
+			updated_at = $9
+		WHERE id = $10`
+
+	if _, err := r.getExecutor().ExecContext(ctx, query,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastResponse,
+		delivery.LastError, delivery.LastDurationMs, delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID,
+	); err != nil {
+		return fmt.Errorf("更新Webhook投递记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries 分页获取订阅的投递记录，按 Seq 倒序
+func (r *webhookSubscriptionRepository) ListDeliveries(ctx context.Context, subscriptionID string, filter *models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 1000 {
+		filter.PageSize = 1000
+	}
+
+	where := " WHERE subscription_id = $1"
+	args := []interface{}{subscriptionID}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.getExecutor(), &total, `SELECT COUNT(*) FROM webhook_deliveries`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("统计Webhook投递记录失败: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries%s ORDER BY seq DESC LIMIT $%d OFFSET $%d`,
+		webhookDeliveryColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err := sqlx.SelectContext(ctx, r.getExecutor(), &deliveries, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("获取Webhook投递记录失败: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// RotateEncryptionKeys 将所有订阅密钥重新加密为当前主密钥版本
+// 按ID分批处理；更新时校验密钥未被并发重置，被重置的订阅跳过
+func (r *webhookSubscriptionRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	rotator, ok := r.encryptionService.(crypto.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("加密服务不支持密钥轮换")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	result := &models.KeyRotationResult{ActiveVersion: rotator.ActiveVersion()}
+	exec := r.getExecutor()
+
+	selectQuery := `
+		SELECT id, secret
+		FROM webhook_subscriptions
+		WHERE id::text > $1
+		ORDER BY id::text
+		LIMIT $2`
+	updateQuery := `UPDATE webhook_subscriptions SET secret = $1 WHERE id = $2 AND secret = $3`
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID     string `db:"id"`
+			Secret string `db:"secret"`
+		}
+		if err := sqlx.SelectContext(ctx, exec, &rows, selectQuery, lastID, batchSize); err != nil {
+			return nil, fmt.Errorf("查询Webhook订阅失败: %w", err)
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			secret, changed, err := rotator.Rotate(row.Secret)
+			if err != nil {
+				result.Failed++
+				result.FailedIDs = append(result.FailedIDs, row.ID)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			res, err := exec.ExecContext(ctx, updateQuery, secret, row.ID, row.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("更新订阅密钥失败: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Rotated++
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// scanWebhookSubscription 扫描出站 Webhook 订阅
+func scanWebhookSubscription(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var events pq.StringArray
+
+	err := scanner.Scan(
+		&subscription.ID, &subscription.Name, &subscription.URL, &subscription.Secret, &events, &subscription.Enabled,
+		&subscription.MaxAttempts, &subscription.DisableAfterFailures, &subscription.ConsecutiveFailures,
+		&subscription.DisabledReason, &subscription.LastDeliveredAt, &subscription.CreatedBy,
+		&subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Events = []string(events)
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+	return &subscription, nil
+}
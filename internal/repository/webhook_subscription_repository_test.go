@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+)
+
+func TestWebhookSubscriptionRepository_SecretEncrypted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	encryption := crypto.NewAESEncryptionService("test-key")
+	repo := NewWebhookSubscriptionRepository(sqlx.NewDb(db, "postgres"), encryption)
+
+	subscription := &models.WebhookSubscription{
+		Name: "crm", URL: "https://crm.example.com/hooks", Secret: "plain-secret",
+		Events: []string{"alert.*"}, Enabled: true,
+	}
+
+	mock.ExpectExec(`INSERT INTO webhook_subscriptions`).
+		WithArgs(sqlmock.AnyArg(), "crm", "https://crm.example.com/hooks", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			true, 0, 0, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(context.Background(), subscription))
+	assert.NotEmpty(t, subscription.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 密钥加密存储，读取时解密
+	stored, err := encryption.Encrypt("plain-secret")
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM webhook_subscriptions WHERE id = \$1`).
+		WithArgs(subscription.ID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "url", "secret", "events", "enabled", "max_attempts", "disable_after_failures",
+			"consecutive_failures", "disabled_reason", "last_delivered_at", "created_by", "created_at", "updated_at",
+		}).AddRow(subscription.ID, "crm", "https://crm.example.com/hooks", stored, "{alert.*,ticket.closed}", true,
+			0, 0, 2, nil, nil, nil, now, now))
+
+	got, err := repo.GetByID(context.Background(), subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "plain-secret", got.Secret)
+	assert.Equal(t, []string{"alert.*", "ticket.closed"}, got.Events)
+	assert.Equal(t, 2, got.ConsecutiveFailures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepository_NextDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepository(sqlx.NewDb(db, "postgres"), crypto.NewAESEncryptionService("test-key"))
+
+	mock.ExpectQuery(`INSERT INTO webhook_deliveries .+ RETURNING seq`).
+		WithArgs(sqlmock.AnyArg(), "s1", "e1", models.WebhookEventAlertCreated, `{}`, models.WebhookDeliveryPending,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(int64(7)))
+
+	delivery := &models.WebhookDelivery{SubscriptionID: "s1", EventID: "e1", Event: models.WebhookEventAlertCreated, Payload: `{}`}
+	require.NoError(t, repo.CreateDelivery(context.Background(), delivery))
+	assert.Equal(t, int64(7), delivery.Seq)
+
+	// 没有待投递事件时返回 nil
+	mock.ExpectQuery(`SELECT .+ FROM webhook_deliveries\s+WHERE subscription_id = \$1 AND status = \$2\s+ORDER BY seq`).
+		WithArgs("s1", models.WebhookDeliveryPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	next, err := repo.NextDelivery(context.Background(), "s1")
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	mock.ExpectExec(`UPDATE webhook_subscriptions SET delivering_until = \$1\s+WHERE id = \$2 AND \(delivering_until IS NULL`).
+		WithArgs(sqlmock.AnyArg(), "s1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := repo.Claim(context.Background(), "s1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPScanner 外部HTTP扫描服务
+// 以 application/octet-stream 提交文件内容，服务返回 {"infected": bool, "signature": string}
+type HTTPScanner struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPScanner 创建HTTP扫描器，client 由 httpclient 工厂创建，使用全局代理和CA证书
+func NewHTTPScanner(url, token string, client *http.Client) *HTTPScanner {
+	return &HTTPScanner{
+		url:    url,
+		token:  token,
+		client: client,
+	}
+}
+
+// Name 扫描器名称
+func (s *HTTPScanner) Name() string {
+	return "http"
+}
+
+// Scan 提交文件内容到外部扫描服务
+func (s *HTTPScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, r)
+	if err != nil {
+		return nil, fmt.Errorf("创建扫描请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求扫描服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("扫描服务返回错误状态 %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析扫描结果失败: %w", err)
+	}
+
+	return &result, nil
+}
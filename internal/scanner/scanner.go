@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"pulse/internal/config"
+)
+
+// Result 扫描结果
+type Result struct {
+	Infected  bool   `json:"infected"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// Scanner 文件病毒扫描器接口
+type Scanner interface {
+	// Name 扫描器名称
+	Name() string
+	// Scan 扫描文件内容
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// New 根据配置创建扫描器，httpClient 为 HTTP 扫描器使用的客户端
+func New(cfg config.AttachmentScanConfig, httpClient *http.Client) (Scanner, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	switch cfg.Scanner {
+	case "", "clamav":
+		if cfg.ClamAVAddress == "" {
+			return nil, fmt.Errorf("ClamAV地址不能为空")
+		}
+		return NewClamAVScanner(cfg.ClamAVAddress, timeout), nil
+	case "http":
+		if cfg.HTTPURL == "" {
+			return nil, fmt.Errorf("扫描服务地址不能为空")
+		}
+		return NewHTTPScanner(cfg.HTTPURL, cfg.HTTPToken, httpClient), nil
+	default:
+		return nil, fmt.Errorf("不支持的扫描器类型: %s", cfg.Scanner)
+	}
+}
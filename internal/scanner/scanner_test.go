@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeClamd 启动模拟的 clamd 服务，收到包含 infectedMarker 的内容时返回病毒
+func startFakeClamd(t *testing.T, infectedMarker string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				if _, err := reader.ReadString('\x00'); err != nil {
+					return
+				}
+
+				var content strings.Builder
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(reader, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					chunk := make([]byte, n)
+					if _, err := io.ReadFull(reader, chunk); err != nil {
+						return
+					}
+					content.Write(chunk)
+				}
+
+				if strings.Contains(content.String(), infectedMarker) {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				conn.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamAVScanner_Scan(t *testing.T) {
+	addr := startFakeClamd(t, "EICAR")
+	s := NewClamAVScanner(addr, 5*time.Second)
+
+	result, err := s.Scan(context.Background(), strings.NewReader("hello world"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = s.Scan(context.Background(), strings.NewReader("X5O!P%@AP EICAR test file"))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+}
+
+func TestParseClamAVReply_Error(t *testing.T) {
+	_, err := parseClamAVReply("INSTREAM size limit exceeded. ERROR\x00")
+	assert.Error(t, err)
+}
+
+func TestHTTPScanner_Scan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(Result{
+			Infected:  strings.Contains(string(body), "EICAR"),
+			Signature: "Test.Signature",
+		})
+	}))
+	defer server.Close()
+
+	s := NewHTTPScanner(server.URL, "secret", server.Client())
+
+	result, err := s.Scan(context.Background(), strings.NewReader("EICAR"))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Test.Signature", result.Signature)
+}
+
+func TestHTTPScanner_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := NewHTTPScanner(server.URL, "", server.Client())
+
+	_, err := s.Scan(context.Background(), strings.NewReader("data"))
+	assert.Error(t, err)
+}
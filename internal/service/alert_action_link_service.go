@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/actionlink"
+	"pulse/internal/repository"
+)
+
+// alertActionLinkPath 操作链接在平台外部访问地址下的路径
+const alertActionLinkPath = "/api/v1/alert-actions/"
+
+// alertActionLinks 通知中生成的操作链接，按顺序展示
+var alertActionLinks = []string{actionlink.ActionAck, actionlink.ActionResolve, actionlink.ActionSnooze}
+
+// alertActionLinkService 告警操作链接服务实现
+type alertActionLinkService struct {
+	repoManager repository.RepositoryManager
+	alerts      AlertService
+	snoozes     AlertSnoozeService
+	signer      *actionlink.Signer
+	cfg         config.ActionLinkConfig
+	logger      *zap.Logger
+}
+
+// NewAlertActionLinkService 创建告警操作链接服务实例，cfg.BaseURL 为空时不生成链接
+func NewAlertActionLinkService(repoManager repository.RepositoryManager, alerts AlertService, snoozes AlertSnoozeService, cfg config.ActionLinkConfig, logger *zap.Logger) AlertActionLinkService {
+	return &alertActionLinkService{
+		repoManager: repoManager,
+		alerts:      alerts,
+		snoozes:     snoozes,
+		signer:      actionlink.NewSigner(cfg.Secret),
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Links 为通知接收人生成告警的确认、解决和暂停链接，未配置外部访问地址时返回 nil
+func (s *alertActionLinkService) Links(ctx context.Context, alertID, userID string) (map[string]string, error) {
+	if s.cfg.BaseURL == "" {
+		return nil, nil
+	}
+
+	expiresAt := time.Now().Add(s.cfg.TTL).Unix()
+	baseURL := strings.TrimRight(s.cfg.BaseURL, "/") + alertActionLinkPath
+	links := make(map[string]string, len(alertActionLinks))
+	for _, action := range alertActionLinks {
+		token, err := s.signer.Sign(actionlink.Claims{AlertID: alertID, UserID: userID, Action: action, ExpiresAt: expiresAt})
+		if err != nil {
+			return nil, fmt.Errorf("签发操作链接失败: %w", err)
+		}
+		links[action] = baseURL + token
+	}
+	return links, nil
+}
+
+// Preview 校验链接并返回对应的操作和告警，不执行操作
+func (s *alertActionLinkService) Preview(ctx context.Context, token string) (*models.AlertActionLinkView, error) {
+	claims, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := s.repoManager.Alert().GetByID(ctx, claims.AlertID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.repoManager.AlertActionLink().IsUsed(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := newAlertActionLinkView(claims, alert)
+	view.Used = used
+	return view, nil
+}
+
+// Execute 以链接接收人的身份执行链接对应的操作，每个链接只能成功执行一次，执行失败时链接可以再次使用
+func (s *alertActionLinkService) Execute(ctx context.Context, token string) (*models.AlertActionLinkView, error) {
+	claims, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CanLogin() {
+		return nil, models.ErrUserDisabled
+	}
+
+	// 先占用链接再执行操作，并发提交同一链接时只有一个请求能执行
+	use := &models.AlertActionLinkUse{TokenID: claims.ID, AlertID: claims.AlertID, UserID: claims.UserID, Action: claims.Action}
+	if err := s.repoManager.AlertActionLink().Claim(ctx, use); err != nil {
+		return nil, err
+	}
+
+	alert, err := s.apply(ctx, claims)
+	if err != nil {
+		if releaseErr := s.repoManager.AlertActionLink().Release(ctx, claims.ID); releaseErr != nil {
+			s.logger.Error("释放操作链接失败", zap.Error(releaseErr), zap.String("alert_id", claims.AlertID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("通过操作链接处理告警", zap.String("alert_id", claims.AlertID), zap.String("user_id", claims.UserID),
+		zap.String("action", claims.Action))
+
+	if updated, err := s.repoManager.Alert().GetByID(ctx, claims.AlertID); err == nil {
+		alert = updated
+	}
+	view := newAlertActionLinkView(claims, alert)
+	view.Used = true
+	return view, nil
+}
+
+// apply 检查告警状态并执行链接对应的操作，返回操作前的告警
+func (s *alertActionLinkService) apply(ctx context.Context, claims *actionlink.Claims) (*models.Alert, error) {
+	alert, err := s.repoManager.Alert().GetByID(ctx, claims.AlertID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch claims.Action {
+	case actionlink.ActionAck:
+		if alert.Status != models.AlertStatusFiring {
+			return nil, models.ErrAlertNotFiring
+		}
+		err = s.alerts.Acknowledge(ctx, claims.AlertID, claims.UserID)
+	case actionlink.ActionResolve:
+		if alert.Status == models.AlertStatusResolved {
+			return nil, models.ErrAlertResolved
+		}
+		err = s.alerts.Resolve(ctx, claims.AlertID, claims.UserID)
+	default:
+		_, err = s.snoozes.Create(ctx, &models.AlertSnoozeRequest{
+			AlertID:         &claims.AlertID,
+			DurationSeconds: int(s.cfg.SnoozeDuration / time.Second),
+			Reason:          "通过通知操作链接暂停",
+		}, claims.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// parse 校验链接令牌，签名无效和过期分别对应令牌无效和令牌过期错误
+func (s *alertActionLinkService) parse(token string) (*actionlink.Claims, error) {
+	if s.cfg.BaseURL == "" {
+		return nil, models.ErrActionLinkDisabled
+	}
+
+	claims, err := s.signer.Parse(token, time.Now())
+	if err != nil {
+		if errors.Is(err, actionlink.ErrExpired) {
+			return nil, models.ErrTokenExpired
+		}
+		return nil, models.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// newAlertActionLinkView 构造链接对应的操作和告警
+func newAlertActionLinkView(claims *actionlink.Claims, alert *models.Alert) *models.AlertActionLinkView {
+	return &models.AlertActionLinkView{
+		Action:    claims.Action,
+		AlertID:   alert.ID,
+		AlertName: alert.Name,
+		Severity:  alert.Severity,
+		Status:    alert.Status,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/actionlink"
+	"pulse/internal/repository"
+)
+
+// fakeAlertActionLinkRepository 内存中的操作链接使用记录
+type fakeAlertActionLinkRepository struct {
+	uses map[string]*models.AlertActionLinkUse
+}
+
+func (r *fakeAlertActionLinkRepository) Claim(ctx context.Context, use *models.AlertActionLinkUse) error {
+	if _, ok := r.uses[use.TokenID]; ok {
+		return models.ErrActionLinkUsed
+	}
+	r.uses[use.TokenID] = use
+	return nil
+}
+
+func (r *fakeAlertActionLinkRepository) Release(ctx context.Context, tokenID string) error {
+	delete(r.uses, tokenID)
+	return nil
+}
+
+func (r *fakeAlertActionLinkRepository) IsUsed(ctx context.Context, tokenID string) (bool, error) {
+	_, ok := r.uses[tokenID]
+	return ok, nil
+}
+
+// fakeActionLinkAlertService 记录确认和解决操作，err 不为空时操作失败
+type fakeActionLinkAlertService struct {
+	AlertService
+	alerts *fakeSnoozeAlertRepository
+	err    error
+}
+
+func (s *fakeActionLinkAlertService) Acknowledge(ctx context.Context, id string, userID string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.alerts.alerts[id].Status = models.AlertStatusAcked
+	s.alerts.alerts[id].AckedBy = &userID
+	return nil
+}
+
+func (s *fakeActionLinkAlertService) Resolve(ctx context.Context, id string, userID string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.alerts.alerts[id].Status = models.AlertStatusResolved
+	return nil
+}
+
+type alertActionLinkRepoManager struct {
+	*notificationCalendarRepoManager
+	links *fakeAlertActionLinkRepository
+}
+
+func (m *alertActionLinkRepoManager) AlertActionLink() repository.AlertActionLinkRepository {
+	return m.links
+}
+
+func newAlertActionLinkTestRepoManager() *alertActionLinkRepoManager {
+	repoManager := &alertActionLinkRepoManager{
+		notificationCalendarRepoManager: &notificationCalendarRepoManager{
+			alertSnoozeRepoManager: newAlertSnoozeTestRepoManager(),
+			routing:                &fakeNotificationRoutingRepository{},
+			notifications:          &fakeSentNotificationRepository{},
+			languages:              newFakeLanguagePreferenceRepository(),
+		},
+		links: &fakeAlertActionLinkRepository{uses: map[string]*models.AlertActionLinkUse{}},
+	}
+	for _, user := range repoManager.users.users {
+		user.Status = models.UserStatusActive
+	}
+	for _, alert := range repoManager.alerts.alerts {
+		alert.Status = models.AlertStatusFiring
+	}
+	return repoManager
+}
+
+var testActionLinkConfig = config.ActionLinkConfig{
+	BaseURL: "https://pulse.example.com/", Secret: "secret", TTL: time.Hour, SnoozeDuration: 30 * time.Minute,
+}
+
+// linkToken 取出链接中的令牌
+func linkToken(t *testing.T, link string) string {
+	token, ok := strings.CutPrefix(link, "https://pulse.example.com"+alertActionLinkPath)
+	require.True(t, ok, link)
+	return token
+}
+
+func TestAlertActionLinkService_Execute(t *testing.T) {
+	ctx := context.Background()
+	repoManager := newAlertActionLinkTestRepoManager()
+	alerts := &fakeActionLinkAlertService{alerts: repoManager.alerts}
+	snoozes := NewAlertSnoozeService(repoManager, config.AlertConfig{}, zap.NewNop())
+	svc := NewAlertActionLinkService(repoManager, alerts, snoozes, testActionLinkConfig, zap.NewNop())
+
+	links, err := svc.Links(ctx, "a1", "u1")
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+
+	// 打开链接只展示确认信息，不消耗链接
+	ackToken := linkToken(t, links[actionlink.ActionAck])
+	view, err := svc.Preview(ctx, ackToken)
+	require.NoError(t, err)
+	assert.Equal(t, actionlink.ActionAck, view.Action)
+	assert.False(t, view.Used)
+
+	// 操作失败时链接可以再次使用
+	alerts.err = errors.New("数据库不可用")
+	_, err = svc.Execute(ctx, ackToken)
+	assert.Error(t, err)
+	assert.Empty(t, repoManager.links.uses)
+	alerts.err = nil
+
+	view, err = svc.Execute(ctx, ackToken)
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusAcked, view.Status)
+	assert.True(t, view.Used)
+	assert.Equal(t, "u1", *repoManager.alerts.alerts["a1"].AckedBy)
+
+	// 每个链接只能使用一次
+	_, err = svc.Execute(ctx, ackToken)
+	assert.ErrorIs(t, err, models.ErrActionLinkUsed)
+	view, err = svc.Preview(ctx, ackToken)
+	require.NoError(t, err)
+	assert.True(t, view.Used)
+
+	// 暂停链接为接收人创建暂停
+	_, err = svc.Execute(ctx, linkToken(t, links[actionlink.ActionSnooze]))
+	require.NoError(t, err)
+	active, err := snoozes.List(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "a1", *active[0].AlertID)
+
+	_, err = svc.Execute(ctx, linkToken(t, links[actionlink.ActionResolve]))
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusResolved, repoManager.alerts.alerts["a1"].Status)
+
+	// 已解决的告警不能再确认
+	links, err = svc.Links(ctx, "a1", "u2")
+	require.NoError(t, err)
+	_, err = svc.Execute(ctx, linkToken(t, links[actionlink.ActionAck]))
+	assert.ErrorIs(t, err, models.ErrAlertNotFiring)
+
+	// 被禁用的用户不能使用链接
+	repoManager.users.users[1].Status = models.UserStatusDisabled
+	links, err = svc.Links(ctx, "a2", "u2")
+	require.NoError(t, err)
+	_, err = svc.Execute(ctx, linkToken(t, links[actionlink.ActionAck]))
+	assert.ErrorIs(t, err, models.ErrUserDisabled)
+}
+
+func TestAlertActionLinkService_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+	repoManager := newAlertActionLinkTestRepoManager()
+	svc := NewAlertActionLinkService(repoManager, &fakeActionLinkAlertService{alerts: repoManager.alerts}, nil, testActionLinkConfig, zap.NewNop())
+
+	expired, err := actionlink.NewSigner("secret").Sign(actionlink.Claims{
+		AlertID: "a1", UserID: "u1", Action: actionlink.ActionAck, ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+	_, err = svc.Execute(ctx, expired)
+	assert.ErrorIs(t, err, models.ErrTokenExpired)
+
+	forged, err := actionlink.NewSigner("other").Sign(actionlink.Claims{
+		AlertID: "a1", UserID: "u1", Action: actionlink.ActionAck, ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	_, err = svc.Preview(ctx, forged)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	// 未配置外部访问地址时不生成链接，也不接受链接
+	disabled := NewAlertActionLinkService(repoManager, nil, nil, config.ActionLinkConfig{Secret: "secret"}, zap.NewNop())
+	links, err := disabled.Links(ctx, "a1", "u1")
+	require.NoError(t, err)
+	assert.Nil(t, links)
+	_, err = disabled.Execute(ctx, expired)
+	assert.ErrorIs(t, err, models.ErrActionLinkDisabled)
+}
+
+func TestNotificationService_AppendsActionLinks(t *testing.T) {
+	ctx := context.Background()
+	repoManager := newAlertActionLinkTestRepoManager()
+	alertID := uuid.New()
+	repoManager.alerts.alerts[alertID.String()] = &models.Alert{ID: alertID.String(), Status: models.AlertStatusFiring}
+	links := NewAlertActionLinkService(repoManager, nil, nil, testActionLinkConfig, zap.NewNop())
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, links, nil, config.NotificationConfig{}, zap.NewNop())
+
+	require.NoError(t, svc.Send(ctx, &models.Notification{
+		AlertID: alertID, Type: models.NotificationTypeSMS, Recipient: "alice", Content: "checkout 告警",
+	}))
+	// 接收人不是平台用户时不附加链接
+	require.NoError(t, svc.Send(ctx, &models.Notification{
+		AlertID: alertID, Type: models.NotificationTypeSMS, Recipient: "13800000000", Content: "checkout 告警",
+	}))
+
+	sent := repoManager.notifications.sent
+	require.Len(t, sent, 2)
+	assert.Contains(t, sent[0].Content, "确认告警：https://pulse.example.com/api/v1/alert-actions/")
+	assert.Contains(t, sent[0].Content, "暂停通知：")
+	assert.Equal(t, "checkout 告警", sent[1].Content)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// selfResolvingAlertSources 由平台自身检测恢复的告警来源，告警持续期间不更新最后评估时间，默认 TTL 不适用
+var selfResolvingAlertSources = map[models.AlertSource]bool{
+	models.AlertSourceSelf:       true,
+	models.AlertSourceHeartbeat:  true,
+	models.AlertSourceKubernetes: true,
+}
+
+// alertAutoResolveService 告警自动解决服务实现
+type alertAutoResolveService struct {
+	repoManager  repository.RepositoryManager
+	alertService AlertService
+	cfg          config.AutoResolveConfig
+	sourceTTLs   map[string]time.Duration
+	logger       *zap.Logger
+}
+
+// NewAlertAutoResolveService 创建告警自动解决服务实例，按来源配置的 TTL 无效时忽略该配置
+func NewAlertAutoResolveService(repoManager repository.RepositoryManager, alertService AlertService, cfg config.AutoResolveConfig, logger *zap.Logger) AlertAutoResolveService {
+	sourceTTLs, err := cfg.ParseSourceTTLs()
+	if err != nil {
+		logger.Warn("按来源配置的自动解决TTL无效，已忽略", zap.Error(err))
+		sourceTTLs = map[string]time.Duration{}
+	}
+	return &alertAutoResolveService{
+		repoManager:  repoManager,
+		alertService: alertService,
+		cfg:          cfg,
+		sourceTTLs:   sourceTTLs,
+		logger:       logger,
+	}
+}
+
+// Interval 自动解决检查的周期
+func (s *alertAutoResolveService) Interval() time.Duration {
+	return s.cfg.CheckInterval
+}
+
+// ResolveStale 解决超过 TTL 未再次触发的告警，返回解决的告警数量
+// TTL 依次取告警的 auto_resolve_after 注解、所属规则的 auto_resolve_after 注解、按来源配置的 TTL 和默认 TTL，
+// 来源每次再次触发告警时更新最后评估时间，TTL 从最后评估时间开始计算
+func (s *alertAutoResolveService) ResolveStale(ctx context.Context) (int, error) {
+	rules, err := s.repoManager.Rule().GetActiveRules(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取活跃规则失败: %w", err)
+	}
+	ruleTTLs := make(map[string]time.Duration)
+	for _, rule := range rules {
+		if ttl, ok := s.parseTTL(rule.Annotations, "rule_id", rule.ID); ok {
+			ruleTTLs[rule.ID] = ttl
+		}
+	}
+
+	// 只查询超过已配置的最短 TTL 的告警；告警注解配置了更短的 TTL 时，在超过最短 TTL 后才会解决
+	minTTL := time.Duration(0)
+	for _, ttl := range s.configuredTTLs(ruleTTLs) {
+		if ttl > 0 && (minTTL == 0 || ttl < minTTL) {
+			minTTL = ttl
+		}
+	}
+
+	now := time.Now()
+	batchSize := s.cfg.BatchSize
+	resolved, offset := 0, 0
+	for {
+		alerts, err := s.repoManager.Alert().ListStale(ctx, now.Add(-minTTL), offset, batchSize)
+		if err != nil {
+			return resolved, err
+		}
+
+		for _, alert := range alerts {
+			ttl := s.ttlFor(alert, ruleTTLs)
+			if ttl <= 0 || now.Sub(alert.LastEvalAt) < ttl {
+				offset++
+				continue
+			}
+			if err := s.resolve(ctx, alert, ttl, now); err != nil {
+				s.logger.Error("自动解决告警失败", zap.Error(err), zap.String("alert_id", alert.ID))
+				offset++
+				continue
+			}
+			resolved++
+		}
+
+		if len(alerts) < batchSize {
+			break
+		}
+	}
+
+	if resolved > 0 {
+		s.logger.Info("超过TTL未再次触发的告警已自动解决", zap.Int("count", resolved))
+	}
+	return resolved, nil
+}
+
+// configuredTTLs 返回默认、按来源和按规则配置的全部 TTL
+func (s *alertAutoResolveService) configuredTTLs(ruleTTLs map[string]time.Duration) []time.Duration {
+	ttls := []time.Duration{s.cfg.DefaultTTL}
+	for _, ttl := range s.sourceTTLs {
+		ttls = append(ttls, ttl)
+	}
+	for _, ttl := range ruleTTLs {
+		ttls = append(ttls, ttl)
+	}
+	return ttls
+}
+
+// ttlFor 计算告警的自动解决 TTL，为 0 时不自动解决
+func (s *alertAutoResolveService) ttlFor(alert *models.Alert, ruleTTLs map[string]time.Duration) time.Duration {
+	if ttl, ok := s.parseTTL(alert.Annotations, "alert_id", alert.ID); ok {
+		return ttl
+	}
+	if alert.RuleID != nil {
+		if ttl, ok := ruleTTLs[*alert.RuleID]; ok {
+			return ttl
+		}
+	}
+	if ttl, ok := s.sourceTTLs[string(alert.Source)]; ok {
+		return ttl
+	}
+	if selfResolvingAlertSources[alert.Source] {
+		return 0
+	}
+	return s.cfg.DefaultTTL
+}
+
+// parseTTL 解析 auto_resolve_after 注解，未配置或无效时 ok 为 false
+func (s *alertAutoResolveService) parseTTL(annotations map[string]string, key, id string) (time.Duration, bool) {
+	raw := strings.TrimSpace(annotations[models.AlertAnnotationAutoResolveAfter])
+	if raw == "" {
+		return 0, false
+	}
+	if raw == "0" {
+		return 0, true
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		s.logger.Warn("自动解决TTL注解无效，已忽略", zap.String(key, id), zap.String("value", raw))
+		return 0, false
+	}
+	return ttl, true
+}
+
+// resolve 以超时为原因解决告警并记录历史
+func (s *alertAutoResolveService) resolve(ctx context.Context, alert *models.Alert, ttl time.Duration, now time.Time) error {
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[models.AlertAnnotationResolveReason] = models.AlertResolveReasonTimeout
+	alert.Status = models.AlertStatusResolved
+	alert.EndsAt = &now
+	alert.ResolvedAt = &now
+	if err := s.alertService.Update(ctx, alert); err != nil {
+		return err
+	}
+
+	comment := fmt.Sprintf("超过 %s 未再次触发，自动解决", ttl)
+	history := &models.AlertHistory{
+		AlertID:  alert.ID,
+		Action:   "resolved",
+		NewValue: map[string]interface{}{"reason": models.AlertResolveReasonTimeout, "ttl": ttl.String()},
+		Comment:  &comment,
+	}
+	if err := s.repoManager.Alert().AddHistory(ctx, history); err != nil {
+		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", alert.ID))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAutoResolveAlertRepository 内存中的告警
+type fakeAutoResolveAlertRepository struct {
+	repository.AlertRepository
+	alerts    map[string]*models.Alert
+	histories []*models.AlertHistory
+}
+
+func (r *fakeAutoResolveAlertRepository) ListStale(ctx context.Context, before time.Time, offset, limit int) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		active := alert.Status == models.AlertStatusFiring || alert.Status == models.AlertStatusAcked
+		if active && alert.LastEvalAt.Before(before) {
+			copied := *alert
+			alerts = append(alerts, &copied)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+	if offset >= len(alerts) {
+		return nil, nil
+	}
+	alerts = alerts[offset:]
+	if len(alerts) > limit {
+		alerts = alerts[:limit]
+	}
+	return alerts, nil
+}
+
+func (r *fakeAutoResolveAlertRepository) AddHistory(ctx context.Context, history *models.AlertHistory) error {
+	r.histories = append(r.histories, history)
+	return nil
+}
+
+// fakeAutoResolveAlertService 更新写回告警仓储
+type fakeAutoResolveAlertService struct {
+	AlertService
+	repo *fakeAutoResolveAlertRepository
+}
+
+func (s *fakeAutoResolveAlertService) Update(ctx context.Context, alert *models.Alert) error {
+	s.repo.alerts[alert.ID] = alert
+	return nil
+}
+
+// fakeAutoResolveRuleRepository 返回固定的活跃规则
+type fakeAutoResolveRuleRepository struct {
+	repository.RuleRepository
+	rules []*models.Rule
+}
+
+func (r *fakeAutoResolveRuleRepository) GetActiveRules(ctx context.Context) ([]*models.Rule, error) {
+	return r.rules, nil
+}
+
+type autoResolveRepoManager struct {
+	*MockRepositoryManager
+	alerts *fakeAutoResolveAlertRepository
+	rules  *fakeAutoResolveRuleRepository
+}
+
+func (m *autoResolveRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *autoResolveRepoManager) Rule() repository.RuleRepository { return m.rules }
+
+func TestAlertAutoResolveService_ResolveStale(t *testing.T) {
+	now := time.Now()
+	ruleID := "r1"
+	alert := func(id string, source models.AlertSource, status models.AlertStatus, idle time.Duration) *models.Alert {
+		return &models.Alert{ID: id, Name: id, Source: source, Status: status, LastEvalAt: now.Add(-idle)}
+	}
+	alerts := map[string]*models.Alert{
+		// custom 来源 TTL 4h
+		"custom-stale": alert("custom-stale", models.AlertSourceCustom, models.AlertStatusAcked, 5*time.Hour),
+		"custom-fresh": alert("custom-fresh", models.AlertSourceCustom, models.AlertStatusFiring, 3*time.Hour),
+		// 默认 TTL 2h
+		"grafana-stale": alert("grafana-stale", models.AlertSourceGrafana, models.AlertStatusFiring, 3*time.Hour),
+		// 规则注解 TTL 1h 覆盖默认 TTL
+		"rule-stale": alert("rule-stale", models.AlertSourcePrometheus, models.AlertStatusFiring, 90*time.Minute),
+		// 告警注解为 0 时不自动解决
+		"annotated": alert("annotated", models.AlertSourceCustom, models.AlertStatusFiring, 10*time.Hour),
+		// 心跳告警由平台检测恢复，不使用默认 TTL
+		"heartbeat": alert("heartbeat", models.AlertSourceHeartbeat, models.AlertStatusFiring, 10*time.Hour),
+		"resolved":  alert("resolved", models.AlertSourceGrafana, models.AlertStatusResolved, 10*time.Hour),
+	}
+	alerts["rule-stale"].RuleID = &ruleID
+	alerts["annotated"].Annotations = map[string]string{models.AlertAnnotationAutoResolveAfter: "0"}
+
+	alertRepo := &fakeAutoResolveAlertRepository{alerts: alerts}
+	repoManager := &autoResolveRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		alerts:                alertRepo,
+		rules: &fakeAutoResolveRuleRepository{rules: []*models.Rule{
+			{ID: ruleID, Annotations: map[string]string{models.AlertAnnotationAutoResolveAfter: "1h"}},
+		}},
+	}
+	cfg := config.AutoResolveConfig{CheckInterval: time.Minute, BatchSize: 2, DefaultTTL: 2 * time.Hour, SourceTTLs: "custom=4h"}
+	svc := NewAlertAutoResolveService(repoManager, &fakeAutoResolveAlertService{repo: alertRepo}, cfg, zap.NewNop())
+
+	resolved, err := svc.ResolveStale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, resolved)
+
+	for _, id := range []string{"custom-stale", "grafana-stale", "rule-stale"} {
+		assert.Equal(t, models.AlertStatusResolved, alerts[id].Status, id)
+		assert.Equal(t, models.AlertResolveReasonTimeout, alerts[id].Annotations[models.AlertAnnotationResolveReason], id)
+		assert.NotNil(t, alerts[id].ResolvedAt, id)
+	}
+	for _, id := range []string{"custom-fresh", "annotated", "heartbeat"} {
+		assert.Equal(t, models.AlertStatusFiring, alerts[id].Status, id)
+	}
+	require.Len(t, alertRepo.histories, 3)
+	assert.Equal(t, "resolved", alertRepo.histories[0].Action)
+	assert.Equal(t, models.AlertResolveReasonTimeout, alertRepo.histories[0].NewValue["reason"])
+
+	// 再次检查时没有需要解决的告警
+	resolved, err = svc.ResolveStale(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, resolved)
+}
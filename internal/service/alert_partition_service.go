@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertPartitionService 告警分区维护服务实现
+type alertPartitionService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.AlertConfig
+	retention   config.RetentionConfig
+	logger      *zap.Logger
+}
+
+// NewAlertPartitionService 创建告警分区维护服务实例
+func NewAlertPartitionService(repoManager repository.RepositoryManager, cfg config.AlertConfig, retention config.RetentionConfig, logger *zap.Logger) AlertPartitionService {
+	return &alertPartitionService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		retention:   retention,
+		logger:      logger,
+	}
+}
+
+// Interval 维护分区的周期
+func (s *alertPartitionService) Interval() time.Duration {
+	return s.cfg.PartitionCheckInterval
+}
+
+// Maintain 为告警表和告警历史表创建当前月份及之后的月分区，并删除超出保留期的分区。
+// 告警分区中仍有未解决的告警时保留分区，只删除其中已解决和已删除的告警。
+// 开启归档时过期记录由归档任务导出后删除，这里只删除已经清空的分区
+func (s *alertPartitionService) Maintain(ctx context.Context) (*models.PartitionMaintenanceResult, error) {
+	now := time.Now()
+	result := &models.PartitionMaintenanceResult{Created: []string{}, Dropped: []string{}}
+
+	retentionDays := map[string]int{
+		models.PartitionedTableAlerts:         s.cfg.RetentionDays,
+		models.PartitionedTableAlertHistories: s.cfg.HistoryRetentionDays,
+	}
+
+	for _, table := range []string{models.PartitionedTableAlerts, models.PartitionedTableAlertHistories} {
+		if err := s.createPartitions(ctx, table, now, result); err != nil {
+			return result, err
+		}
+		if err := s.dropExpiredPartitions(ctx, table, now.AddDate(0, 0, -retentionDays[table]), result); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.Created) > 0 || len(result.Dropped) > 0 || result.Purged > 0 {
+		s.logger.Info("告警分区维护完成", zap.Strings("created", result.Created), zap.Strings("dropped", result.Dropped),
+			zap.Int64("purged", result.Purged))
+	}
+	return result, nil
+}
+
+// createPartitions 创建当前月份和之后 PartitionPremakeMonths 个月的分区
+func (s *alertPartitionService) createPartitions(ctx context.Context, table string, now time.Time, result *models.PartitionMaintenanceResult) error {
+	month := models.MonthlyPartition(table, now).From
+	for i := 0; i <= s.cfg.PartitionPremakeMonths; i++ {
+		partition := models.MonthlyPartition(table, month.AddDate(0, i, 0))
+		created, err := s.repoManager.Partition().Create(ctx, partition)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Created = append(result.Created, partition.Name)
+		}
+	}
+	return nil
+}
+
+// dropExpiredPartitions 删除结束时间不晚于 cutoff 的月分区，默认分区不删除
+func (s *alertPartitionService) dropExpiredPartitions(ctx context.Context, table string, cutoff time.Time, result *models.PartitionMaintenanceResult) error {
+	partitions, err := s.repoManager.Partition().List(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		if partition.Default || partition.To.After(cutoff) {
+			continue
+		}
+
+		if s.retention.ArchiveEnabled {
+			hasRows, err := s.repoManager.Partition().HasRows(ctx, partition)
+			if err != nil {
+				return err
+			}
+			if hasRows {
+				continue
+			}
+		}
+
+		if table == models.PartitionedTableAlerts {
+			active, err := s.repoManager.Partition().HasActiveAlerts(ctx, partition)
+			if err != nil {
+				return err
+			}
+			if active {
+				purged, err := s.repoManager.Partition().PurgeInactiveAlerts(ctx, partition)
+				if err != nil {
+					return err
+				}
+				result.Purged += purged
+				s.logger.Warn("过期的告警分区中仍有未解决的告警，保留分区", zap.String("partition", partition.Name),
+					zap.Int64("purged", purged))
+				continue
+			}
+		}
+
+		if err := s.repoManager.Partition().Drop(ctx, partition); err != nil {
+			return err
+		}
+		result.Dropped = append(result.Dropped, partition.Name)
+	}
+	return nil
+}
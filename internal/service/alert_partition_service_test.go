@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakePartitionRepository 内存中的分区，active 为仍有未解决告警的分区，rows 为有记录的分区
+type fakePartitionRepository struct {
+	partitions map[string]*models.TablePartition
+	active     map[string]bool
+	rows       map[string]bool
+}
+
+func (r *fakePartitionRepository) Create(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	if _, ok := r.partitions[partition.Name]; ok {
+		return false, nil
+	}
+	r.partitions[partition.Name] = partition
+	return true, nil
+}
+
+func (r *fakePartitionRepository) List(ctx context.Context, table string) ([]*models.TablePartition, error) {
+	var partitions []*models.TablePartition
+	for _, partition := range r.partitions {
+		if partition.Table == table {
+			partitions = append(partitions, partition)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Name < partitions[j].Name })
+	return partitions, nil
+}
+
+func (r *fakePartitionRepository) Drop(ctx context.Context, partition *models.TablePartition) error {
+	delete(r.partitions, partition.Name)
+	return nil
+}
+
+func (r *fakePartitionRepository) HasActiveAlerts(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	return r.active[partition.Name], nil
+}
+
+func (r *fakePartitionRepository) HasRows(ctx context.Context, partition *models.TablePartition) (bool, error) {
+	return r.rows[partition.Name], nil
+}
+
+func (r *fakePartitionRepository) PurgeInactiveAlerts(ctx context.Context, partition *models.TablePartition) (int64, error) {
+	return 5, nil
+}
+
+type alertPartitionRepoManager struct {
+	*MockRepositoryManager
+	partitions *fakePartitionRepository
+}
+
+func (m *alertPartitionRepoManager) Partition() repository.PartitionRepository { return m.partitions }
+
+func TestAlertPartitionService_Maintain(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(-2, 0, 0)
+	partitions := &fakePartitionRepository{
+		partitions: map[string]*models.TablePartition{},
+		active:     map[string]bool{models.MonthlyPartition(models.PartitionedTableAlerts, old.AddDate(0, -1, 0)).Name: true},
+	}
+	for _, table := range []string{models.PartitionedTableAlerts, models.PartitionedTableAlertHistories} {
+		for _, month := range []time.Time{old.AddDate(0, -1, 0), old, now} {
+			partition := models.MonthlyPartition(table, month)
+			partitions.partitions[partition.Name] = partition
+		}
+		partitions.partitions[table+"_default"] = &models.TablePartition{Table: table, Name: table + "_default", Default: true}
+	}
+
+	repoManager := &alertPartitionRepoManager{MockRepositoryManager: &MockRepositoryManager{}, partitions: partitions}
+	svc := NewAlertPartitionService(repoManager, config.AlertConfig{
+		RetentionDays: 365, HistoryRetentionDays: 30, PartitionPremakeMonths: 2,
+	}, config.RetentionConfig{}, zap.NewNop())
+
+	result, err := svc.Maintain(context.Background())
+	require.NoError(t, err)
+
+	// 每张表补齐之后两个月的分区
+	assert.Len(t, result.Created, 4)
+	assert.Contains(t, result.Created, models.MonthlyPartition(models.PartitionedTableAlerts, now.AddDate(0, 2, -now.Day()+1)).Name)
+
+	// 有未解决告警的过期分区保留，只清理其中的已解决告警
+	assert.ElementsMatch(t, []string{
+		models.MonthlyPartition(models.PartitionedTableAlerts, old).Name,
+		models.MonthlyPartition(models.PartitionedTableAlertHistories, old.AddDate(0, -1, 0)).Name,
+		models.MonthlyPartition(models.PartitionedTableAlertHistories, old).Name,
+	}, result.Dropped)
+	assert.Equal(t, int64(5), result.Purged)
+	assert.Contains(t, partitions.partitions, models.PartitionedTableAlerts+"_default")
+
+	// 再次执行时没有需要创建或删除的分区
+	result, err = svc.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Empty(t, result.Dropped)
+}
+
+func TestAlertPartitionService_MaintainWithArchive(t *testing.T) {
+	old := time.Now().AddDate(-2, 0, 0)
+	archived := models.MonthlyPartition(models.PartitionedTableAlertHistories, old)
+	pending := models.MonthlyPartition(models.PartitionedTableAlertHistories, old.AddDate(0, 1, 0))
+	partitions := &fakePartitionRepository{
+		partitions: map[string]*models.TablePartition{archived.Name: archived, pending.Name: pending},
+		rows:       map[string]bool{pending.Name: true},
+	}
+
+	repoManager := &alertPartitionRepoManager{MockRepositoryManager: &MockRepositoryManager{}, partitions: partitions}
+	svc := NewAlertPartitionService(repoManager, config.AlertConfig{RetentionDays: 365, HistoryRetentionDays: 30},
+		config.RetentionConfig{ArchiveEnabled: true}, zap.NewNop())
+
+	// 开启归档时只删除归档任务已经清空的分区
+	result, err := svc.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{archived.Name}, result.Dropped)
+	assert.Contains(t, partitions.partitions, pending.Name)
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// errReplayLimitReached 回放的告警数达到上限，停止读取告警
+var errReplayLimitReached = errors.New("回放告警数达到上限")
+
+// alertReplayService 告警回放服务实现
+type alertReplayService struct {
+	repoManager repository.RepositoryManager
+	services    ServiceAttributor
+	assignment  AssignmentPreviewer
+	pusher      PushSender
+	logger      *zap.Logger
+}
+
+// NewAlertReplayService 创建告警回放服务实例，services 为 nil 时使用告警已有的服务和团队标签，
+// pusher 为 nil 时不检查接收人的推送偏好
+func NewAlertReplayService(repoManager repository.RepositoryManager, services ServiceAttributor, assignment AssignmentPreviewer, pusher PushSender, logger *zap.Logger) AlertReplayService {
+	return &alertReplayService{
+		repoManager: repoManager,
+		services:    services,
+		assignment:  assignment,
+		pusher:      pusher,
+		logger:      logger,
+	}
+}
+
+// replayRecipient 回放中缓存的接收人及其免打扰设置
+type replayRecipient struct {
+	user  *models.User
+	quiet *models.QuietHours
+}
+
+// Replay 将时间范围内的告警按当前的服务目录、分派策略、通知日历和接收人的免打扰时段试运行，
+// 报告每个告警会通知哪些接收人。各项检查按告警的触发时间判断，临时免打扰只影响当前的通知，不参与回放
+func (s *alertReplayService) Replay(ctx context.Context, req *models.AlertReplayRequest) (*models.AlertReplayReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 先读取全部告警再逐个回放，避免回放中的查询与告警游标争用连接
+	filter := &models.AlertFilter{StartTime: &req.From, EndTime: &req.To, Labels: req.Labels}
+	if req.Query != "" {
+		filter.Query = &req.Query
+	}
+	report := &models.AlertReplayReport{
+		From:      req.From,
+		To:        req.To,
+		Receivers: []models.AlertReplayReceiverSummary{},
+		Alerts:    []models.AlertReplayResult{},
+	}
+	var alerts []*models.Alert
+	err := s.repoManager.Alert().Stream(ctx, filter, func(alert *models.Alert) error {
+		if len(alerts) == req.Limit {
+			report.Truncated = true
+			return errReplayLimitReached
+		}
+		alerts = append(alerts, alert)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errReplayLimitReached) {
+		return nil, err
+	}
+
+	calendars, err := s.repoManager.NotificationRouting().ListCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make(map[string]*replayRecipient)
+	summaries := make(map[string]int)
+	for _, alert := range alerts {
+		result, err := s.replay(ctx, alert, s.attribute(ctx, alert), calendars, recipients, false)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Replayed++
+		if result.Unrouted != "" {
+			report.Unrouted++
+		}
+		if result.Changed {
+			report.Changed++
+		}
+		for _, receiver := range result.Receivers {
+			index, ok := summaries[receiver.UserID]
+			if !ok {
+				index = len(report.Receivers)
+				summaries[receiver.UserID] = index
+				report.Receivers = append(report.Receivers, models.AlertReplayReceiverSummary{
+					UserID: receiver.UserID, Username: receiver.Username,
+				})
+			}
+			switch receiver.Outcome {
+			case models.AlertReplayNotified:
+				report.Receivers[index].Notified++
+			case models.AlertReplayDeferred:
+				report.Receivers[index].Deferred++
+			case models.AlertReplaySuppressed:
+				report.Receivers[index].Suppressed++
+			}
+		}
+		report.Alerts = append(report.Alerts, *result)
+	}
+
+	report.GeneratedAt = time.Now()
+	s.logger.Info("告警回放完成", zap.Time("from", req.From), zap.Time("to", req.To),
+		zap.Int("replayed", report.Replayed), zap.Int("unrouted", report.Unrouted), zap.Int("changed", report.Changed))
+	return report, nil
+}
+
+// attribute 按当前服务目录重新归属告警，返回写入服务和团队标签后的告警标签，不修改告警
+func (s *alertReplayService) attribute(ctx context.Context, alert *models.Alert) map[string]string {
+	labels := make(map[string]string, len(alert.Labels)+2)
+	for key, value := range alert.Labels {
+		labels[key] = value
+	}
+	if s.services != nil {
+		service, err := s.services.Attribute(ctx, labels)
+		if err != nil {
+			s.logger.Warn("回放告警归属服务失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		} else if service != nil {
+			labels = attributeLabels(labels, service)
+		}
+	}
+	return labels
+}
+
+// replay 按归属后的标签回放单个告警，处理人按团队当前的分派策略预览。
+// live 为 true 时按当前时间试运行，接收人的临时免打扰同样生效
+func (s *alertReplayService) replay(ctx context.Context, alert *models.Alert, labels map[string]string, calendars []*models.NotificationCalendar, recipients map[string]*replayRecipient, live bool) (*models.AlertReplayResult, error) {
+	result := &models.AlertReplayResult{
+		AlertID:          alert.ID,
+		Name:             alert.Name,
+		Severity:         alert.Severity,
+		StartsAt:         alert.StartsAt,
+		Service:          labels[models.ServiceLabel],
+		Team:             labels[models.TeamLabel],
+		Receivers:        []models.AlertReplayReceiver{},
+		OriginalAssignee: alert.Annotations[models.AssigneeAnnotation],
+	}
+
+	var preview *models.AssignmentPreview
+	if result.Team == "" {
+		result.Unrouted = models.AlertReplayNoTeam
+	} else {
+		var err error
+		preview, err = s.assignment.Preview(ctx, result.Team, models.AssignmentTarget{Labels: labels})
+		switch {
+		case errors.Is(err, models.ErrNoAssignee):
+			result.Unrouted = models.AlertReplayNoCandidates
+		case err != nil:
+			return nil, err
+		case preview == nil:
+			result.Unrouted = models.AlertReplayNoPolicy
+		}
+	}
+
+	if preview != nil {
+		result.Strategy = preview.Strategy
+		// 非工作时间的低级别告警通知延迟到下一个工作时间
+		var deliverAt *time.Time
+		if calendar := matchNotificationCalendar(calendars, labels); calendar != nil && calendar.ShouldDefer(alert.Severity, alert.StartsAt) {
+			next := calendar.NextBusinessStart(alert.StartsAt)
+			deliverAt = &next
+		}
+
+		for _, userID := range preview.UserIDs {
+			recipient, err := s.recipient(ctx, userID, recipients, live)
+			if err != nil {
+				return nil, err
+			}
+			receiver := models.AlertReplayReceiver{
+				UserID:   userID,
+				Username: recipient.user.Username,
+				Outcome:  models.AlertReplayNotified,
+				Push:     s.pusher != nil && s.pusher.Wants(ctx, userID, alert.Severity),
+			}
+			switch {
+			case recipient.quiet != nil && recipient.quiet.Suppresses(alert.Severity, alert.StartsAt):
+				receiver.Outcome = models.AlertReplaySuppressed
+			case deliverAt != nil:
+				receiver.Outcome = models.AlertReplayDeferred
+				receiver.DeliverAt = deliverAt
+			}
+			result.Receivers = append(result.Receivers, receiver)
+		}
+	}
+
+	if result.OriginalAssignee != "" {
+		result.Changed = true
+		for _, receiver := range result.Receivers {
+			if receiver.UserID == result.OriginalAssignee {
+				result.Changed = false
+			}
+		}
+	}
+	return result, nil
+}
+
+// recipient 获取接收人及其免打扰设置，同一接收人只查询一次。live 为 false 时只保留每周的免打扰时段
+func (s *alertReplayService) recipient(ctx context.Context, userID string, recipients map[string]*replayRecipient, live bool) (*replayRecipient, error) {
+	if recipient, ok := recipients[userID]; ok {
+		return recipient, nil
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	recipient := &replayRecipient{user: user}
+	quiet, err := s.repoManager.QuietHours().Get(ctx, userID)
+	switch {
+	case err == nil:
+		if !live {
+			quiet.DNDUntil = nil
+		}
+		recipient.quiet = quiet
+	case !errors.Is(err, models.ErrQuietHoursNotFound):
+		return nil, err
+	}
+	recipients[userID] = recipient
+	return recipient, nil
+}
+
+// Preview 按当前配置试运行样例告警的通知路由，返回经过的路由步骤、接收人、告警指纹以及生效的静默和暂停，
+// 用于排查告警为什么没有通知到预期的接收人。与回放不同，试运行按当前时间判断，接收人的临时免打扰和告警暂停同样生效
+func (s *alertReplayService) Preview(ctx context.Context, req *models.RoutePreviewRequest) (*models.RoutePreview, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	alert := &models.Alert{
+		Name:         req.Name,
+		Severity:     req.Severity,
+		Labels:       req.Labels,
+		DataSourceID: req.DataSourceID,
+		Expression:   req.Expression,
+		StartsAt:     now,
+	}
+	preview := &models.RoutePreview{
+		GroupKey: generateFingerprint(alert),
+		Snoozes:  []*models.AlertSnooze{},
+	}
+
+	// 相同指纹的告警合并到已有告警，已有告警的静默和针对该告警的暂停同样生效
+	existing, err := s.repoManager.Alert().GetByFingerprint(ctx, preview.GroupKey)
+	switch {
+	case errors.Is(err, models.ErrAlertNotFound):
+		preview.Route = append(preview.Route, models.RoutePreviewStep{Step: models.RouteStepDeduplicate, Detail: "没有相同指纹的告警，将创建新告警"})
+	case err != nil:
+		return nil, err
+	default:
+		preview.ExistingAlert = existing
+		alert.ID = existing.ID
+		preview.Route = append(preview.Route, models.RoutePreviewStep{
+			Step: models.RouteStepDeduplicate, Matched: true,
+			Detail: fmt.Sprintf("合并到已有告警 %s，状态 %s", existing.ID, existing.Status),
+		})
+		if existing.SilenceID != nil {
+			silence, err := s.repoManager.Alert().GetSilence(ctx, *existing.SilenceID)
+			switch {
+			case err == nil:
+				if silence.Active && now.Before(silence.EndsAt) {
+					preview.Silence = silence
+				}
+			case !errors.Is(err, models.ErrAlertSilenceNotFound):
+				return nil, err
+			}
+		}
+	}
+
+	calendars, err := s.repoManager.NotificationRouting().ListCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels := s.attribute(ctx, alert)
+	result, err := s.replay(ctx, alert, labels, calendars, make(map[string]*replayRecipient), true)
+	if err != nil {
+		return nil, err
+	}
+	alert.Labels = labels
+
+	preview.Labels = labels
+	preview.Service = result.Service
+	preview.Team = result.Team
+	preview.Strategy = result.Strategy
+	preview.Unrouted = result.Unrouted
+	preview.Receivers = result.Receivers
+	var calendar *models.NotificationCalendar
+	if result.Unrouted == "" {
+		calendar = matchNotificationCalendar(calendars, labels)
+		if calendar != nil {
+			preview.Calendar = calendar.Name
+		}
+	}
+	preview.Route = append(preview.Route, routePreviewSteps(result, calendar)...)
+
+	for i := range preview.Receivers {
+		receiver := &preview.Receivers[i]
+		snoozes, err := s.repoManager.AlertSnooze().ListActiveByUser(ctx, receiver.UserID, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, snooze := range snoozes {
+			if snooze.Matches(alert) {
+				preview.Snoozes = append(preview.Snoozes, snooze)
+				receiver.Outcome = models.AlertReplaySuppressed
+				receiver.DeliverAt = nil
+			}
+		}
+	}
+
+	preview.GeneratedAt = now
+	return preview, nil
+}
+
+// routePreviewSteps 根据回放结果生成服务归属、团队、分派策略和通知日历的路由步骤，没有接收人时停在未匹配的步骤
+func routePreviewSteps(result *models.AlertReplayResult, calendar *models.NotificationCalendar) []models.RoutePreviewStep {
+	steps := make([]models.RoutePreviewStep, 0, 4)
+	if result.Service != "" {
+		steps = append(steps, models.RoutePreviewStep{Step: models.RouteStepService, Matched: true, Detail: "归属到服务 " + result.Service})
+	} else {
+		steps = append(steps, models.RoutePreviewStep{Step: models.RouteStepService, Detail: "未匹配服务目录中的服务，使用告警的 team 标签"})
+	}
+
+	if result.Unrouted == models.AlertReplayNoTeam {
+		return append(steps, models.RoutePreviewStep{Step: models.RouteStepTeam, Detail: "告警没有 team 标签，也未归属到有负责团队的服务"})
+	}
+	steps = append(steps, models.RoutePreviewStep{Step: models.RouteStepTeam, Matched: true, Detail: "由团队 " + result.Team + " 负责"})
+
+	switch result.Unrouted {
+	case models.AlertReplayNoPolicy:
+		return append(steps, models.RoutePreviewStep{Step: models.RouteStepAssignment, Detail: "团队 " + result.Team + " 没有启用的分派策略"})
+	case models.AlertReplayNoCandidates:
+		return append(steps, models.RoutePreviewStep{Step: models.RouteStepAssignment, Detail: "分派策略没有可分派的成员"})
+	}
+	steps = append(steps, models.RoutePreviewStep{
+		Step: models.RouteStepAssignment, Matched: true,
+		Detail: fmt.Sprintf("按 %s 策略通知 %d 个接收人", result.Strategy, len(result.Receivers)),
+	})
+
+	switch {
+	case calendar == nil:
+		steps = append(steps, models.RoutePreviewStep{Step: models.RouteStepCalendar, Detail: "没有匹配的通知日历，立即通知"})
+	case calendar.ShouldDefer(result.Severity, result.StartsAt):
+		steps = append(steps, models.RoutePreviewStep{
+			Step: models.RouteStepCalendar, Matched: true,
+			Detail: fmt.Sprintf("非工作时间，按通知日历 %s 延迟到 %s 通知", calendar.Name, calendar.NextBusinessStart(result.StartsAt).Format(time.RFC3339)),
+		})
+	default:
+		steps = append(steps, models.RoutePreviewStep{Step: models.RouteStepCalendar, Matched: true, Detail: "按通知日历 " + calendar.Name + " 立即通知"})
+	}
+	return steps
+}
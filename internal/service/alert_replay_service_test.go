@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeReplayAlertRepository 按触发时间倒序返回时间范围内的告警
+type fakeReplayAlertRepository struct {
+	repository.AlertRepository
+	alerts   []*models.Alert
+	silences []*models.AlertSilence
+}
+
+func (r *fakeReplayAlertRepository) Stream(ctx context.Context, filter *models.AlertFilter, fn func(*models.Alert) error) error {
+	for _, alert := range r.alerts {
+		if alert.StartsAt.Before(*filter.StartTime) || alert.StartsAt.After(*filter.EndTime) {
+			continue
+		}
+		if err := fn(alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeReplayAlertRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	for _, alert := range r.alerts {
+		if alert.Fingerprint == fingerprint {
+			return alert, nil
+		}
+	}
+	return nil, models.ErrAlertNotFound
+}
+
+func (r *fakeReplayAlertRepository) GetSilence(ctx context.Context, silenceID string) (*models.AlertSilence, error) {
+	for _, silence := range r.silences {
+		if silence.ID == silenceID {
+			return silence, nil
+		}
+	}
+	return nil, models.ErrAlertSilenceNotFound
+}
+
+type alertReplayRepoManager struct {
+	*assignmentRepoManager
+	alerts  *fakeReplayAlertRepository
+	routing *fakeNotificationRoutingRepository
+	quiet   *fakeQuietHoursRepository
+	snoozes *fakeAlertSnoozeRepository
+}
+
+func (m *alertReplayRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *alertReplayRepoManager) NotificationRouting() repository.NotificationRoutingRepository {
+	return m.routing
+}
+
+func (m *alertReplayRepoManager) QuietHours() repository.QuietHoursRepository { return m.quiet }
+
+func (m *alertReplayRepoManager) AlertSnooze() repository.AlertSnoozeRepository { return m.snoozes }
+
+func TestAlertReplayService_Replay(t *testing.T) {
+	ctx := context.Background()
+	// 2024-06-08 是周六
+	saturday := time.Date(2024, 6, 8, 3, 0, 0, 0, time.UTC)
+	repoManager := &alertReplayRepoManager{
+		assignmentRepoManager: newAssignmentRepoManager(&models.AssignmentPolicy{
+			ID: "rr", Team: "sre", Strategy: models.AssignmentStrategyRoundRobin, Enabled: true,
+			Members: []models.AssignmentMember{{UserID: "u1"}, {UserID: "u2"}},
+		}),
+		alerts: &fakeReplayAlertRepository{alerts: []*models.Alert{
+			{ID: "a1", Name: "QueueBacklog", Severity: models.AlertSeverityLow, StartsAt: saturday,
+				Labels: map[string]string{"team": "sre"}, Annotations: map[string]string{"assignee": "u1"}},
+			{ID: "a2", Name: "APIDown", Severity: models.AlertSeverityCritical, StartsAt: saturday.Add(-time.Hour),
+				Labels: map[string]string{"team": "sre"}},
+			{ID: "a3", Name: "CheckoutErrors", Severity: models.AlertSeverityHigh, StartsAt: saturday.Add(-2 * time.Hour),
+				Labels: map[string]string{"namespace": "checkout"}, Annotations: map[string]string{"assignee": "u3"}},
+			{ID: "a4", Name: "DiskFull", Severity: models.AlertSeverityHigh, StartsAt: saturday.Add(-3 * time.Hour),
+				Labels: map[string]string{"team": "sre"}},
+		}},
+		routing: &fakeNotificationRoutingRepository{calendars: []*models.NotificationCalendar{
+			{ID: "sre", Name: "SRE", Team: "sre", DeferSeverities: []models.AlertSeverity{models.AlertSeverityLow},
+				BusinessCalendar: models.BusinessCalendar{BusinessHours: models.BusinessHours{
+					Days: []string{"monday", "tuesday", "wednesday", "thursday", "friday"}, Start: "09:00", End: "18:00",
+				}}},
+		}},
+		quiet: &fakeQuietHoursRepository{settings: map[string]*models.QuietHours{}},
+	}
+	repoManager.users.users[0].Username = "alice"
+	repoManager.users.users[1].Username = "bob"
+	// 临时免打扰不参与回放，只按每周的免打扰时段判断
+	dndUntil := time.Now().Add(time.Hour)
+	repoManager.quiet.settings["u1"] = &models.QuietHours{UserID: "u1", DNDUntil: &dndUntil}
+	repoManager.quiet.settings["u2"] = &models.QuietHours{UserID: "u2", Windows: []models.QuietHoursWindow{{
+		Days: []string{"friday"}, Start: "23:00", End: "07:00",
+		AllowSeverities: []models.AlertSeverity{models.AlertSeverityCritical},
+	}}}
+
+	catalog := &fakeServiceAttributor{service: &models.CatalogService{Name: "checkout", OwnerTeam: "payments"}}
+	assignment := NewAssignmentService(repoManager, zap.NewNop())
+	svc := NewAlertReplayService(repoManager, catalog, assignment, nil, zap.NewNop())
+
+	_, err := svc.Replay(ctx, &models.AlertReplayRequest{From: saturday, To: saturday.Add(-time.Hour)})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Replay(ctx, &models.AlertReplayRequest{From: saturday.Add(-40 * 24 * time.Hour), To: saturday})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	report, err := svc.Replay(ctx, &models.AlertReplayRequest{From: saturday.Add(-24 * time.Hour), To: saturday, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Replayed)
+	assert.True(t, report.Truncated)
+	require.Len(t, report.Alerts, 3)
+
+	// 非工作时间的低级别告警延迟到周一，免打扰时段内只通知严重告警
+	low := report.Alerts[0]
+	assert.Equal(t, models.AssignmentStrategyRoundRobin, low.Strategy)
+	require.Len(t, low.Receivers, 2)
+	assert.Equal(t, models.AlertReplayDeferred, low.Receivers[0].Outcome)
+	assert.Equal(t, time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC), *low.Receivers[0].DeliverAt)
+	assert.Equal(t, models.AlertReplaySuppressed, low.Receivers[1].Outcome)
+	assert.False(t, low.Changed)
+
+	critical := report.Alerts[1]
+	require.Len(t, critical.Receivers, 2)
+	assert.Equal(t, models.AlertReplayNotified, critical.Receivers[0].Outcome)
+	assert.Equal(t, models.AlertReplayNotified, critical.Receivers[1].Outcome)
+
+	// 按当前服务目录归属到没有分派策略的团队，原处理人不再收到通知
+	unrouted := report.Alerts[2]
+	assert.Equal(t, "checkout", unrouted.Service)
+	assert.Equal(t, "payments", unrouted.Team)
+	assert.Equal(t, models.AlertReplayNoPolicy, unrouted.Unrouted)
+	assert.True(t, unrouted.Changed)
+	assert.Equal(t, 1, report.Unrouted)
+	assert.Equal(t, 1, report.Changed)
+
+	assert.Equal(t, []models.AlertReplayReceiverSummary{
+		{UserID: "u1", Username: "alice", Notified: 1, Deferred: 1},
+		{UserID: "u2", Username: "bob", Notified: 1, Suppressed: 1},
+	}, report.Receivers)
+
+	// 回放不推进轮询计数
+	assert.Empty(t, repoManager.policies.counters)
+}
+
+func TestAlertReplayService_Preview(t *testing.T) {
+	ctx := context.Background()
+	silenceID := "s1"
+	dndUntil := time.Now().Add(time.Hour)
+	repoManager := &alertReplayRepoManager{
+		assignmentRepoManager: newAssignmentRepoManager(&models.AssignmentPolicy{
+			ID: "rr", Team: "sre", Strategy: models.AssignmentStrategyRoundRobin, Enabled: true,
+			Members: []models.AssignmentMember{{UserID: "u1"}, {UserID: "u2"}},
+		}),
+		alerts: &fakeReplayAlertRepository{
+			alerts: []*models.Alert{{ID: "a1", Name: "APIDown", Status: models.AlertStatusSilenced,
+				Fingerprint: "APIDown-prom-up == 0", SilenceID: &silenceID}},
+			silences: []*models.AlertSilence{{ID: silenceID, Name: "发布窗口", Active: true,
+				StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(time.Hour)}},
+		},
+		routing: &fakeNotificationRoutingRepository{},
+		quiet: &fakeQuietHoursRepository{settings: map[string]*models.QuietHours{
+			"u1": {UserID: "u1", DNDUntil: &dndUntil},
+		}},
+		snoozes: &fakeAlertSnoozeRepository{snoozes: map[string]*models.AlertSnooze{
+			"z1": {ID: "z1", UserID: "u2", Matchers: map[string]string{"service": "checkout"}, ExpiresAt: time.Now().Add(time.Hour)},
+			"z2": {ID: "z2", UserID: "u2", Matchers: map[string]string{"service": "billing"}, ExpiresAt: time.Now().Add(time.Hour)},
+		}},
+	}
+	catalog := &fakeServiceAttributor{service: &models.CatalogService{Name: "checkout", OwnerTeam: "payments"}}
+	svc := NewAlertReplayService(repoManager, catalog, NewAssignmentService(repoManager, zap.NewNop()), nil, zap.NewNop())
+
+	_, err := svc.Preview(ctx, &models.RoutePreviewRequest{Name: " ", Severity: models.AlertSeverityCritical})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 团队标签优先于服务目录的负责团队，已有告警的静默和接收人的临时免打扰、暂停都会生效
+	preview, err := svc.Preview(ctx, &models.RoutePreviewRequest{
+		Name: "APIDown", Severity: models.AlertSeverityCritical, Labels: map[string]string{"team": "sre"},
+		DataSourceID: "prom", Expression: "up == 0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "APIDown-prom-up == 0", preview.GroupKey)
+	require.NotNil(t, preview.ExistingAlert)
+	assert.Equal(t, "a1", preview.ExistingAlert.ID)
+	require.NotNil(t, preview.Silence)
+	assert.Equal(t, "发布窗口", preview.Silence.Name)
+	assert.Equal(t, map[string]string{"team": "sre", "service": "checkout"}, preview.Labels)
+
+	require.Len(t, preview.Receivers, 2)
+	assert.Equal(t, models.AlertReplaySuppressed, preview.Receivers[0].Outcome)
+	assert.Equal(t, models.AlertReplaySuppressed, preview.Receivers[1].Outcome)
+	require.Len(t, preview.Snoozes, 1)
+	assert.Equal(t, "z1", preview.Snoozes[0].ID)
+
+	steps := make([]models.RouteStep, 0, len(preview.Route))
+	for _, step := range preview.Route {
+		assert.NotEmpty(t, step.Detail)
+		steps = append(steps, step.Step)
+	}
+	assert.Equal(t, []models.RouteStep{models.RouteStepDeduplicate, models.RouteStepService, models.RouteStepTeam,
+		models.RouteStepAssignment, models.RouteStepCalendar}, steps)
+	assert.False(t, preview.Route[4].Matched, "没有通知日历时立即通知")
+
+	// 归属到没有分派策略的团队时路由停在分派策略步骤
+	preview, err = svc.Preview(ctx, &models.RoutePreviewRequest{Name: "CheckoutErrors", Severity: models.AlertSeverityHigh})
+	require.NoError(t, err)
+	assert.Nil(t, preview.ExistingAlert)
+	assert.Equal(t, models.AlertReplayNoPolicy, preview.Unrouted)
+	assert.Empty(t, preview.Receivers)
+	last := preview.Route[len(preview.Route)-1]
+	assert.Equal(t, models.RouteStepAssignment, last.Step)
+	assert.False(t, last.Matched)
+	assert.Empty(t, repoManager.policies.counters)
+}
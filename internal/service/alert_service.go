@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,15 +17,39 @@ import (
 type alertService struct {
 	alertRepo repository.AlertRepository
 	userRepo  repository.UserRepository
+	services  ServiceAttributor
+	assigner  Assigner
+	changes   ChangeCorrelator
+	inbox     InboxNotifier
+	events    EventPublisher
+	labels    LabelGuard
+	severity  SeverityClassifier
+	triage    TriageScorer
 	logger    *zap.Logger
+
+	// occurrenceSampleInterval 重复触发记入告警历史的采样间隔
+	occurrenceSampleInterval time.Duration
 }
 
-// NewAlertService 创建告警服务实例
-func NewAlertService(alertRepo repository.AlertRepository, userRepo repository.UserRepository, logger *zap.Logger) AlertService {
+// NewAlertService 创建告警服务实例，services 为 nil 时不将告警归属到服务，assigner 为 nil 时不自动分派处理人，
+// changes 为 nil 时不关联变更，inbox 为 nil 时不向处理人投递站内通知，events 为 nil 时不发布告警事件，
+// labels 为 nil 时不检查标签基数，severity 为 nil 时不按规则重新分级，triage 为 nil 时不调用外部分诊服务评分；
+// occurrenceSampleInterval 为重复触发记入告警历史的采样间隔，不大于 0 时每次重复触发都记录
+func NewAlertService(alertRepo repository.AlertRepository, userRepo repository.UserRepository, services ServiceAttributor, assigner Assigner, changes ChangeCorrelator, inbox InboxNotifier, events EventPublisher, labels LabelGuard, severity SeverityClassifier, triage TriageScorer, occurrenceSampleInterval time.Duration, logger *zap.Logger) AlertService {
 	return &alertService{
 		alertRepo: alertRepo,
 		userRepo:  userRepo,
+		services:  services,
+		assigner:  assigner,
+		changes:   changes,
+		inbox:     inbox,
+		events:    events,
+		labels:    labels,
+		severity:  severity,
+		triage:    triage,
 		logger:    logger,
+
+		occurrenceSampleInterval: occurrenceSampleInterval,
 	}
 }
 
@@ -36,6 +61,11 @@ func (s *alertService) Create(ctx context.Context, alert *models.Alert) error {
 		return fmt.Errorf("告警数据验证失败: %w", err)
 	}
 
+	// 丢弃或规范化高基数标签，在归属服务和分派之前执行，避免请求ID等标签进入存储和分组
+	if s.labels != nil {
+		s.labels.Guard(alert)
+	}
+
 	// 生成告警ID
 	if alert.ID == "" {
 		alert.ID = uuid.New().String()
@@ -59,12 +89,34 @@ func (s *alertService) Create(ctx context.Context, alert *models.Alert) error {
 	// 设置最后评估时间
 	alert.LastEvalAt = now
 	alert.EvalCount = 1
+	alert.OccurrenceCount = 1
+	alert.LastSeenAt = now
 
 	// 生成指纹
 	if alert.Fingerprint == "" {
-		alert.Fingerprint = s.generateFingerprint(alert)
+		alert.Fingerprint = generateFingerprint(alert)
 	}
 
+	// 指纹相同的告警未解决时只累加触发次数，不创建新告警，也不重新分派、发布事件和通知
+	if alert.Status != models.AlertStatusResolved && s.collapse(ctx, alert, now) {
+		return nil
+	}
+
+	// 归属到服务，在生成指纹之后写入标签，服务目录变化不影响告警去重
+	service := s.attributeService(ctx, alert)
+	s.correlateChange(ctx, alert)
+	// 按重新分级规则调整级别，在分派、入库和发布事件之前执行，通知路由使用调整后的级别
+	if s.severity != nil {
+		var tier models.ServiceTier
+		if service != nil {
+			tier = service.Tier
+		}
+		s.severity.Reclassify(ctx, alert, tier)
+	}
+	// 分诊评分在重新分级之后执行，评分服务看到的是最终的级别和服务标签
+	s.score(ctx, alert)
+	assignee := s.assign(ctx, alert)
+
 	// 创建告警
 	if err := s.alertRepo.Create(ctx, alert); err != nil {
 		s.logger.Error("创建告警失败", zap.Error(err), zap.String("alert_id", alert.ID))
@@ -83,6 +135,10 @@ func (s *alertService) Create(ctx context.Context, alert *models.Alert) error {
 	if err := s.alertRepo.AddHistory(ctx, history); err != nil {
 		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", alert.ID))
 	}
+	if assignee != "" {
+		s.notifyAssignee(ctx, alert, assignee)
+	}
+	s.publish(ctx, models.WebhookEventAlertCreated, alert)
 
 	s.logger.Info("告警创建成功", zap.String("alert_id", alert.ID), zap.String("name", alert.Name))
 	return nil
@@ -115,6 +171,11 @@ func (s *alertService) List(ctx context.Context, filter *models.AlertFilter) ([]
 	return alertList.Alerts, alertList.Total, nil
 }
 
+// Stream 按列表的过滤条件逐条读取全部告警，用于导出
+func (s *alertService) Stream(ctx context.Context, filter *models.AlertFilter, fn func(*models.Alert) error) error {
+	return s.alertRepo.Stream(ctx, filter, fn)
+}
+
 // Update 更新告警
 func (s *alertService) Update(ctx context.Context, alert *models.Alert) error {
 	if alert.ID == "" {
@@ -157,6 +218,8 @@ func (s *alertService) Update(ctx context.Context, alert *models.Alert) error {
 		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", alert.ID))
 	}
 
+	s.publish(ctx, models.WebhookEventAlertUpdated, alert)
+
 	s.logger.Info("告警更新成功", zap.String("alert_id", alert.ID))
 	return nil
 }
@@ -244,6 +307,8 @@ func (s *alertService) Acknowledge(ctx context.Context, id string, userID string
 		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", id))
 	}
 
+	s.publishLatest(ctx, models.WebhookEventAlertAcknowledged, id)
+
 	s.logger.Info("告警确认成功", zap.String("alert_id", id), zap.String("user_id", userID), zap.String("username", user.Username))
 	return nil
 }
@@ -295,18 +360,208 @@ func (s *alertService) Resolve(ctx context.Context, id string, userID string) er
 		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", id))
 	}
 
+	s.publishLatest(ctx, models.WebhookEventAlertResolved, id)
+
 	s.logger.Info("告警解决成功", zap.String("alert_id", id), zap.String("user_id", userID), zap.String("username", user.Username))
 	return nil
 }
 
 // 辅助方法
 
-// generateFingerprint 生成告警指纹
-func (s *alertService) generateFingerprint(alert *models.Alert) string {
+// generateFingerprint 生成告警指纹，指纹相同的告警合并为同一个告警
+func generateFingerprint(alert *models.Alert) string {
 	// 简单的指纹生成逻辑，实际项目中可能需要更复杂的算法
 	return fmt.Sprintf("%s-%s-%s", alert.Name, alert.DataSourceID, alert.Expression)
 }
 
+// collapse 将重复触发合并到指纹相同的未解决告警，累加触发次数并按采样间隔记入告警历史，合并后 alert 替换为已有告警。
+// 查询或更新失败时返回 false，按新告警创建，避免丢失告警
+func (s *alertService) collapse(ctx context.Context, alert *models.Alert, now time.Time) bool {
+	existing, err := s.alertRepo.GetOpenByFingerprint(ctx, alert.Fingerprint)
+	if err != nil {
+		if !errors.Is(err, models.ErrAlertNotFound) {
+			s.logger.Warn("查询未解决的重复告警失败", zap.Error(err), zap.String("fingerprint", alert.Fingerprint))
+		}
+		return false
+	}
+
+	occurrence, err := s.alertRepo.RecordOccurrence(ctx, existing.ID, now, alert.Value, s.occurrenceSampleInterval)
+	if err != nil {
+		if !errors.Is(err, models.ErrAlertNotFound) {
+			s.logger.Warn("记录告警重复触发失败", zap.Error(err), zap.String("alert_id", existing.ID))
+		}
+		return false
+	}
+	existing.OccurrenceCount = occurrence.Count
+	existing.LastSeenAt = occurrence.SeenAt
+	existing.LastEvalAt = now
+	existing.EvalCount++
+	if alert.Value != nil {
+		existing.Value = alert.Value
+	}
+	*alert = *existing
+
+	if occurrence.Sampled {
+		history := &models.AlertHistory{
+			ID:      uuid.New().String(),
+			AlertID: alert.ID,
+			Action:  models.AlertHistoryActionOccurred,
+			NewValue: map[string]interface{}{
+				"occurrence_count": occurrence.Count,
+				"value":            alert.Value,
+			},
+			CreatedAt: now,
+		}
+		if err := s.alertRepo.AddHistory(ctx, history); err != nil {
+			s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		}
+	}
+
+	s.logger.Debug("重复告警已合并", zap.String("alert_id", alert.ID), zap.Int64("occurrence_count", occurrence.Count))
+	return true
+}
+
+// attributeService 按服务目录将告警归属到服务，写入 service 和 team 标签并返回归属的服务，匹配失败不影响告警创建
+func (s *alertService) attributeService(ctx context.Context, alert *models.Alert) *models.CatalogService {
+	if s.services == nil {
+		return nil
+	}
+
+	service, err := s.services.Attribute(ctx, alert.Labels)
+	if err != nil {
+		s.logger.Warn("告警归属服务失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		return nil
+	}
+	if service != nil {
+		alert.Labels = attributeLabels(alert.Labels, service)
+	}
+	return service
+}
+
+// correlateChange 将告警触发前同一服务上最近的变更作为可能原因写入注解，关联失败不影响告警创建
+func (s *alertService) correlateChange(ctx context.Context, alert *models.Alert) {
+	if s.changes == nil || alert.Annotations[models.AlertAnnotationPossibleCause] != "" {
+		return
+	}
+
+	change, err := s.changes.Correlate(ctx, alert)
+	if err != nil {
+		s.logger.Warn("告警关联变更失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		return
+	}
+	if change == nil {
+		return
+	}
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[models.AlertAnnotationPossibleCause] = change.Summary()
+	alert.Annotations[models.AlertAnnotationChangeEventID] = change.ID
+}
+
+// score 调用外部分诊服务为告警评分，评分服务不可用、超时或熔断时跳过评分，不影响告警创建
+func (s *alertService) score(ctx context.Context, alert *models.Alert) {
+	if s.triage == nil {
+		return
+	}
+
+	triage, err := s.triage.Score(ctx, alert)
+	if err != nil {
+		s.logger.Warn("告警分诊评分失败，跳过评分", zap.Error(err), zap.String("alert_id", alert.ID))
+		return
+	}
+	triage.Apply(alert)
+}
+
+// assign 按告警 team 标签对应团队的分派策略分派处理人，分派策略没有选中处理人时使用分诊服务建议的处理人，
+// 处理人写入 assignee 注解并返回。分派失败不影响告警创建
+func (s *alertService) assign(ctx context.Context, alert *models.Alert) string {
+	if alert.Annotations[models.AssigneeAnnotation] != "" {
+		return ""
+	}
+
+	userID := s.pickAssignee(ctx, alert)
+	if userID == "" {
+		userID = s.suggestedAssignee(ctx, alert)
+	}
+	if userID == "" {
+		return ""
+	}
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[models.AssigneeAnnotation] = userID
+	return userID
+}
+
+// pickAssignee 按告警 team 标签对应团队的分派策略选择处理人
+func (s *alertService) pickAssignee(ctx context.Context, alert *models.Alert) string {
+	team := alert.Labels[models.TeamLabel]
+	if s.assigner == nil || team == "" {
+		return ""
+	}
+
+	userID, err := s.assigner.Pick(ctx, team, models.AssignmentTarget{Labels: alert.Labels})
+	if err != nil {
+		s.logger.Warn("告警自动分派失败", zap.Error(err), zap.String("alert_id", alert.ID), zap.String("team", team))
+		return ""
+	}
+	return userID
+}
+
+// suggestedAssignee 返回分诊服务建议的处理人，建议的处理人不是平台用户时不分派
+func (s *alertService) suggestedAssignee(ctx context.Context, alert *models.Alert) string {
+	if alert.TriageAssignee == nil || s.userRepo == nil {
+		return ""
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, *alert.TriageAssignee); err != nil {
+		s.logger.Warn("分诊服务建议的处理人无效，不分派", zap.Error(err), zap.String("alert_id", alert.ID), zap.String("user_id", *alert.TriageAssignee))
+		return ""
+	}
+	return *alert.TriageAssignee
+}
+
+// notifyAssignee 向自动分派的处理人投递站内通知，外部来源写入的 assignee 注解不一定是平台用户，不通知
+func (s *alertService) notifyAssignee(ctx context.Context, alert *models.Alert, assignee string) {
+	if s.inbox == nil {
+		return
+	}
+	s.inbox.Notify(ctx, &models.InboxItem{
+		UserID:       assignee,
+		Kind:         models.InboxKindAssigned,
+		ResourceType: models.InboxResourceAlert,
+		ResourceID:   alert.ID,
+		MessageKey:   "inbox.assigned_alert",
+		MessageData: map[string]interface{}{
+			"Name":     alert.Name,
+			"Severity": alert.Severity,
+			"StartsAt": alert.StartsAt.Format(time.RFC3339),
+		},
+	})
+}
+
+// publish 向出站 Webhook 订阅发布告警事件
+func (s *alertService) publish(ctx context.Context, event models.WebhookEvent, alert *models.Alert) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(ctx, event, alert)
+}
+
+// publishLatest 重新读取告警后发布事件，事件内容包含确认人、解决人等最新状态
+func (s *alertService) publishLatest(ctx context.Context, event models.WebhookEvent, id string) {
+	if s.events == nil {
+		return
+	}
+	alert, err := s.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("发布告警事件失败", zap.Error(err), zap.String("alert_id", id), zap.String("event", string(event)))
+		return
+	}
+	s.events.Publish(ctx, event, alert)
+}
+
 // alertToMap 将告警转换为map用于历史记录
 func (s *alertService) alertToMap(alert *models.Alert) map[string]interface{} {
 	return map[string]interface{}{
@@ -320,6 +575,7 @@ func (s *alertService) alertToMap(alert *models.Alert) map[string]interface{} {
 		"expression":      alert.Expression,
 		"value":           alert.Value,
 		"threshold":       alert.Threshold,
+		"triage_score":    alert.TriageScore,
 		"starts_at":       alert.StartsAt,
 		"ends_at":         alert.EndsAt,
 		"acked_by":        alert.AckedBy,
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+)
+
+func newOccurrenceTestAlert(value float64) *models.Alert {
+	return &models.Alert{
+		DataSourceID: "ds1",
+		Name:         "DiskFull",
+		Description:  "磁盘使用率超过 90%",
+		Severity:     models.AlertSeverityHigh,
+		Status:       models.AlertStatusFiring,
+		Source:       models.AlertSourcePrometheus,
+		Expression:   "disk_used_percent > 90",
+		Labels:       map[string]string{"host": "db-1"},
+		Value:        &value,
+		Fingerprint:  "disk-full-db-1",
+	}
+}
+
+func TestAlertService_CreateCollapsesRefires(t *testing.T) {
+	alerts := &fakeServiceAlertRepository{alerts: map[string]*models.Alert{}}
+	svc := NewAlertService(alerts, nil, nil, nil, nil, nil, nil, nil, nil, nil, time.Hour, zap.NewNop())
+	ctx := context.Background()
+
+	first := newOccurrenceTestAlert(91)
+	require.NoError(t, svc.Create(ctx, first))
+	assert.Equal(t, int64(1), first.OccurrenceCount)
+	created := len(alerts.histories)
+
+	// 未解决期间再次触发时累加次数并更新当前值，返回已有告警；采样间隔内只记录第一次重复触发
+	for _, value := range []float64{93, 95} {
+		refire := newOccurrenceTestAlert(value)
+		require.NoError(t, svc.Create(ctx, refire))
+		assert.Equal(t, first.ID, refire.ID)
+	}
+	require.Len(t, alerts.alerts, 1)
+	stored := alerts.alerts[first.ID]
+	assert.Equal(t, int64(3), stored.OccurrenceCount)
+	assert.Equal(t, 95.0, *stored.Value)
+	require.Len(t, alerts.histories, created+1)
+	occurred := alerts.histories[created]
+	assert.Equal(t, models.AlertHistoryActionOccurred, occurred.Action)
+	assert.Equal(t, int64(2), occurred.NewValue["occurrence_count"])
+
+	// 告警解决后再次触发时创建新告警
+	stored.Status = models.AlertStatusResolved
+	refire := newOccurrenceTestAlert(97)
+	require.NoError(t, svc.Create(ctx, refire))
+	assert.NotEqual(t, first.ID, refire.ID)
+	assert.Len(t, alerts.alerts, 2)
+	assert.Equal(t, int64(1), refire.OccurrenceCount)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertSeverityRuleCacheTTL 告警接入时使用的重新分级规则快照有效期，本实例修改规则时立即失效
+const alertSeverityRuleCacheTTL = 30 * time.Second
+
+// alertSeverityRuleService 告警重新分级规则服务实现
+type alertSeverityRuleService struct {
+	repoManager repository.RepositoryManager
+	services    ServiceAttributor
+	logger      *zap.Logger
+	now         func() time.Time
+
+	mu       sync.Mutex
+	snapshot []*models.AlertSeverityRule
+	loadedAt time.Time
+}
+
+// NewAlertSeverityRuleService 创建告警重新分级规则服务实例，services 用于试算时按标签确定服务等级，为 nil 时试算不匹配服务等级条件
+func NewAlertSeverityRuleService(repoManager repository.RepositoryManager, services ServiceAttributor, logger *zap.Logger) AlertSeverityRuleService {
+	return &alertSeverityRuleService{
+		repoManager: repoManager,
+		services:    services,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// List 获取全部规则，按匹配顺序排列
+func (s *alertSeverityRuleService) List(ctx context.Context) ([]*models.AlertSeverityRule, error) {
+	return s.repoManager.AlertSeverityRule().List(ctx)
+}
+
+// Get 获取规则
+func (s *alertSeverityRuleService) Get(ctx context.Context, id string) (*models.AlertSeverityRule, error) {
+	return s.repoManager.AlertSeverityRule().GetByID(ctx, id)
+}
+
+// Create 创建规则，未指定 enabled 时默认启用
+func (s *alertSeverityRuleService) Create(ctx context.Context, req *models.AlertSeverityRuleRequest, createdBy string) (*models.AlertSeverityRule, error) {
+	rule := &models.AlertSeverityRule{Enabled: true, CreatedBy: &createdBy}
+	req.ApplyTo(rule)
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.AlertSeverityRule().Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("创建告警重新分级规则", zap.String("rule_id", rule.ID), zap.String("name", rule.Name),
+		zap.String("created_by", createdBy))
+	return rule, nil
+}
+
+// Update 更新规则，未指定 enabled 时保持原状态
+func (s *alertSeverityRuleService) Update(ctx context.Context, id string, req *models.AlertSeverityRuleRequest) (*models.AlertSeverityRule, error) {
+	rule, err := s.repoManager.AlertSeverityRule().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(rule)
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.AlertSeverityRule().Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return rule, nil
+}
+
+// Delete 删除规则
+func (s *alertSeverityRuleService) Delete(ctx context.Context, id string) error {
+	if err := s.repoManager.AlertSeverityRule().Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Test 按当前启用的规则试算告警的级别，服务等级按服务目录归属确定，不修改任何数据
+func (s *alertSeverityRuleService) Test(ctx context.Context, req *models.AlertSeverityRuleTestRequest) (*models.AlertSeverityRuleTestResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &models.AlertSeverityRuleTestResult{Severity: req.Severity, OriginalSeverity: req.Severity}
+	if s.services != nil {
+		service, err := s.services.Attribute(ctx, req.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if service != nil {
+			result.Service, result.ServiceTier = service.Name, service.Tier
+		}
+	}
+
+	rules, err := s.repoManager.AlertSeverityRule().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	at := s.now()
+	if req.Time != nil {
+		at = *req.Time
+	}
+	if rule := matchAlertSeverityRule(rules, req.Labels, result.ServiceTier, at); rule != nil {
+		result.Rule = rule
+		result.Severity = rule.Apply(req.Severity)
+	}
+	return result, nil
+}
+
+// Reclassify 按第一条匹配的启用规则调整告警级别，原级别和规则名称写入注解。
+// tier 为告警归属服务的等级，未归属时为空；加载规则失败时保持原级别，不影响告警创建
+func (s *alertSeverityRuleService) Reclassify(ctx context.Context, alert *models.Alert, tier models.ServiceTier) {
+	rules, err := s.rules(ctx)
+	if err != nil {
+		s.logger.Warn("加载告警重新分级规则失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		return
+	}
+
+	rule := matchAlertSeverityRule(rules, alert.Labels, tier, s.now())
+	if rule == nil {
+		return
+	}
+	severity := rule.Apply(alert.Severity)
+	if severity == alert.Severity {
+		return
+	}
+
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[models.AlertAnnotationOriginalSeverity] = string(alert.Severity)
+	alert.Annotations[models.AlertAnnotationSeverityRule] = rule.Name
+	s.logger.Info("告警已重新分级", zap.String("alert_id", alert.ID), zap.String("rule", rule.Name),
+		zap.String("from", string(alert.Severity)), zap.String("to", string(severity)))
+	alert.Severity = severity
+}
+
+// rules 获取重新分级规则快照，过期后重新加载
+func (s *alertSeverityRuleService) rules(ctx context.Context) ([]*models.AlertSeverityRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snapshot != nil && time.Since(s.loadedAt) < alertSeverityRuleCacheTTL {
+		return s.snapshot, nil
+	}
+
+	rules, err := s.repoManager.AlertSeverityRule().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshot = rules
+	s.loadedAt = time.Now()
+	return rules, nil
+}
+
+// invalidate 使重新分级规则快照失效
+func (s *alertSeverityRuleService) invalidate() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+}
+
+// matchAlertSeverityRule 返回按顺序第一条匹配的启用规则
+func matchAlertSeverityRule(rules []*models.AlertSeverityRule, labels map[string]string, tier models.ServiceTier, at time.Time) *models.AlertSeverityRule {
+	for _, rule := range rules {
+		if rule.Enabled && rule.Matches(labels, tier, at) {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAlertSeverityRuleRepository 内存中的告警重新分级规则
+type fakeAlertSeverityRuleRepository struct {
+	rules []*models.AlertSeverityRule
+	lists int
+}
+
+func (r *fakeAlertSeverityRuleRepository) Create(ctx context.Context, rule *models.AlertSeverityRule) error {
+	for _, existing := range r.rules {
+		if existing.Name == rule.Name {
+			return models.ErrAlertSeverityRuleExists
+		}
+	}
+	rule.ID = fmt.Sprintf("r%d", len(r.rules)+1)
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *fakeAlertSeverityRuleRepository) GetByID(ctx context.Context, id string) (*models.AlertSeverityRule, error) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			copied := *rule
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrAlertSeverityRuleNotFound
+}
+
+func (r *fakeAlertSeverityRuleRepository) List(ctx context.Context) ([]*models.AlertSeverityRule, error) {
+	r.lists++
+	rules := append([]*models.AlertSeverityRule(nil), r.rules...)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Position < rules[j].Position })
+	return rules, nil
+}
+
+func (r *fakeAlertSeverityRuleRepository) Update(ctx context.Context, rule *models.AlertSeverityRule) error {
+	for i, existing := range r.rules {
+		if existing.ID == rule.ID {
+			r.rules[i] = rule
+			return nil
+		}
+	}
+	return models.ErrAlertSeverityRuleNotFound
+}
+
+func (r *fakeAlertSeverityRuleRepository) Delete(ctx context.Context, id string) error {
+	for i, rule := range r.rules {
+		if rule.ID == id {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrAlertSeverityRuleNotFound
+}
+
+type alertSeverityRuleRepoManager struct {
+	*serviceCatalogRepoManager
+	severityRules *fakeAlertSeverityRuleRepository
+}
+
+func (m *alertSeverityRuleRepoManager) AlertSeverityRule() repository.AlertSeverityRuleRepository {
+	return m.severityRules
+}
+
+func TestAlertSeverityRuleService_Reclassify(t *testing.T) {
+	catalogRepoManager, catalog := newServiceCatalogTestService(t)
+	repoManager := &alertSeverityRuleRepoManager{
+		serviceCatalogRepoManager: catalogRepoManager,
+		severityRules:             &fakeAlertSeverityRuleRepository{},
+	}
+	ctx := context.Background()
+
+	// 周一 23:00，在 tier1 夜间升级规则的时段内
+	now := time.Date(2024, 5, 6, 23, 0, 0, 0, time.UTC)
+	svc := NewAlertSeverityRuleService(repoManager, catalog, zap.NewNop()).(*alertSeverityRuleService)
+	svc.now = func() time.Time { return now }
+
+	info := models.AlertSeverityInfo
+	_, err := svc.Create(ctx, &models.AlertSeverityRuleRequest{
+		Name:     "staging-info",
+		Matchers: map[string]string{"env": "staging"},
+		Severity: &info,
+	}, "admin")
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, &models.AlertSeverityRuleRequest{
+		Name:         "tier1-night",
+		Position:     1,
+		ServiceTiers: []models.ServiceTier{models.ServiceTier1},
+		Windows:      []models.AlertSeverityRuleWindow{{Days: []string{"monday"}, Start: "22:00", End: "08:00"}},
+		Adjust:       1,
+	}, "admin")
+	require.NoError(t, err)
+
+	// 目标级别和调整级数不能同时指定，名称不能重复
+	_, err = svc.Create(ctx, &models.AlertSeverityRuleRequest{Name: "both", Matchers: map[string]string{"env": "dev"}, Severity: &info, Adjust: -1}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Create(ctx, &models.AlertSeverityRuleRequest{Name: "staging-info", Matchers: map[string]string{"env": "dev"}, Adjust: -1}, "admin")
+	assert.ErrorIs(t, err, models.ErrAlertSeverityRuleExists)
+
+	// 先匹配的规则生效，原级别和规则名称写入注解
+	alert := &models.Alert{Severity: models.AlertSeverityHigh, Labels: map[string]string{"env": "staging"}}
+	svc.Reclassify(ctx, alert, models.ServiceTier1)
+	assert.Equal(t, models.AlertSeverityInfo, alert.Severity)
+	assert.Equal(t, "high", alert.Annotations[models.AlertAnnotationOriginalSeverity])
+	assert.Equal(t, "staging-info", alert.Annotations[models.AlertAnnotationSeverityRule])
+
+	// 按级数升级，不超过 critical
+	alert = &models.Alert{Severity: models.AlertSeverityHigh, Labels: map[string]string{"env": "prod"}}
+	svc.Reclassify(ctx, alert, models.ServiceTier1)
+	assert.Equal(t, models.AlertSeverityCritical, alert.Severity)
+	alert = &models.Alert{Severity: models.AlertSeverityCritical, Labels: map[string]string{"env": "prod"}}
+	svc.Reclassify(ctx, alert, models.ServiceTier1)
+	assert.Equal(t, models.AlertSeverityCritical, alert.Severity)
+	assert.Empty(t, alert.Annotations, "级别未变化时不写入注解")
+
+	// 时段和服务等级不匹配时保持原级别
+	now = time.Date(2024, 5, 7, 12, 0, 0, 0, time.UTC)
+	alert = &models.Alert{Severity: models.AlertSeverityHigh, Labels: map[string]string{"env": "prod"}}
+	svc.Reclassify(ctx, alert, models.ServiceTier1)
+	assert.Equal(t, models.AlertSeverityHigh, alert.Severity)
+
+	// 快照有效期内不重复加载规则，修改规则后立即失效
+	lists := repoManager.severityRules.lists
+	svc.Reclassify(ctx, alert, "")
+	assert.Equal(t, lists, repoManager.severityRules.lists)
+	disabled := false
+	_, err = svc.Update(ctx, "r1", &models.AlertSeverityRuleRequest{
+		Name:     "staging-info",
+		Matchers: map[string]string{"env": "staging"},
+		Severity: &info,
+		Enabled:  &disabled,
+	})
+	require.NoError(t, err)
+	alert = &models.Alert{Severity: models.AlertSeverityHigh, Labels: map[string]string{"env": "staging"}}
+	svc.Reclassify(ctx, alert, "")
+	assert.Equal(t, models.AlertSeverityHigh, alert.Severity)
+}
+
+func TestAlertSeverityRuleService_Test(t *testing.T) {
+	catalogRepoManager, catalog := newServiceCatalogTestService(t)
+	repoManager := &alertSeverityRuleRepoManager{
+		serviceCatalogRepoManager: catalogRepoManager,
+		severityRules:             &fakeAlertSeverityRuleRepository{},
+	}
+	ctx := context.Background()
+	svc := NewAlertSeverityRuleService(repoManager, catalog, zap.NewNop())
+
+	_, err := svc.Create(ctx, &models.AlertSeverityRuleRequest{
+		Name:         "tier1-night",
+		ServiceTiers: []models.ServiceTier{models.ServiceTier1},
+		Timezone:     "Asia/Shanghai",
+		Windows:      []models.AlertSeverityRuleWindow{{Days: []string{"monday"}, Start: "22:00", End: "08:00"}},
+		Adjust:       2,
+	}, "admin")
+	require.NoError(t, err)
+
+	// 服务等级按服务目录归属确定，时段按规则的时区判断
+	at := time.Date(2024, 5, 6, 15, 30, 0, 0, time.UTC)
+	result, err := svc.Test(ctx, &models.AlertSeverityRuleTestRequest{
+		Labels:   map[string]string{"namespace": "checkout"},
+		Severity: models.AlertSeverityMedium,
+		Time:     &at,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", result.Service)
+	assert.Equal(t, models.ServiceTier1, result.ServiceTier)
+	assert.Equal(t, models.AlertSeverityCritical, result.Severity)
+	require.NotNil(t, result.Rule)
+	assert.Equal(t, "tier1-night", result.Rule.Name)
+
+	// 未归属到服务时不匹配服务等级条件
+	result, err = svc.Test(ctx, &models.AlertSeverityRuleTestRequest{
+		Labels:   map[string]string{"namespace": "other"},
+		Severity: models.AlertSeverityMedium,
+		Time:     &at,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result.Rule)
+	assert.Equal(t, models.AlertSeverityMedium, result.Severity)
+
+	_, err = svc.Test(ctx, &models.AlertSeverityRuleTestRequest{Severity: "urgent"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestAlertService_CreateReclassifiesSeverity(t *testing.T) {
+	catalogRepoManager, catalog := newServiceCatalogTestService(t)
+	repoManager := &alertSeverityRuleRepoManager{
+		serviceCatalogRepoManager: catalogRepoManager,
+		severityRules:             &fakeAlertSeverityRuleRepository{},
+	}
+	ctx := context.Background()
+	severityRules := NewAlertSeverityRuleService(repoManager, catalog, zap.NewNop())
+
+	_, err := severityRules.Create(ctx, &models.AlertSeverityRuleRequest{
+		Name:         "tier1-upgrade",
+		ServiceTiers: []models.ServiceTier{models.ServiceTier1},
+		Adjust:       1,
+	}, "admin")
+	require.NoError(t, err)
+
+	// 服务等级来自告警归属的服务，入库前调整级别
+	alertSvc := NewAlertService(repoManager.alerts, nil, catalog, nil, nil, nil, nil, nil, severityRules, nil, 0, zap.NewNop())
+	alert := &models.Alert{
+		DataSourceID: "ds1",
+		Name:         "HighLatency",
+		Description:  "结算接口 P99 延迟超过 2s",
+		Severity:     models.AlertSeverityMedium,
+		Status:       models.AlertStatusFiring,
+		Expression:   "histogram_quantile(0.99, checkout_latency_bucket) > 2",
+		Source:       models.AlertSourcePrometheus,
+		Labels:       map[string]string{"namespace": "checkout"},
+		Fingerprint:  "checkout-latency",
+	}
+	require.NoError(t, alertSvc.Create(ctx, alert))
+	assert.Equal(t, models.AlertSeverityHigh, repoManager.alerts.alerts[alert.ID].Severity)
+	assert.Equal(t, "medium", alert.Annotations[models.AlertAnnotationOriginalSeverity])
+	assert.Equal(t, "tier1-upgrade", alert.Annotations[models.AlertAnnotationSeverityRule])
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertSnoozeService 告警暂停服务实现
+type alertSnoozeService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.AlertConfig
+	logger      *zap.Logger
+}
+
+// NewAlertSnoozeService 创建告警暂停服务实例
+func NewAlertSnoozeService(repoManager repository.RepositoryManager, cfg config.AlertConfig, logger *zap.Logger) AlertSnoozeService {
+	return &alertSnoozeService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Interval 清理到期暂停的周期
+func (s *alertSnoozeService) Interval() time.Duration {
+	return s.cfg.SnoozeCleanupInterval
+}
+
+// List 获取用户仍然生效的告警暂停
+func (s *alertSnoozeService) List(ctx context.Context, userID string) ([]*models.AlertSnooze, error) {
+	return s.repoManager.AlertSnooze().ListActiveByUser(ctx, userID, time.Now())
+}
+
+// Create 为用户暂停单条告警或标签匹配的一组告警
+func (s *alertSnoozeService) Create(ctx context.Context, req *models.AlertSnoozeRequest, userID string) (*models.AlertSnooze, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	snooze := &models.AlertSnooze{
+		UserID:    userID,
+		Matchers:  req.Matchers,
+		Reason:    strings.TrimSpace(req.Reason),
+		ExpiresAt: time.Now().Add(req.Duration()),
+	}
+	if req.AlertID != nil && *req.AlertID != "" {
+		alert, err := s.repoManager.Alert().GetByID(ctx, *req.AlertID)
+		if err != nil {
+			return nil, err
+		}
+		snooze.AlertID = &alert.ID
+		snooze.Matchers = nil
+	}
+
+	if err := s.repoManager.AlertSnooze().Create(ctx, snooze); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("告警已暂停", zap.String("snooze_id", snooze.ID), zap.String("user_id", userID),
+		zap.Time("expires_at", snooze.ExpiresAt))
+	return snooze, nil
+}
+
+// Delete 提前结束用户的告警暂停，不能结束其他用户的暂停
+func (s *alertSnoozeService) Delete(ctx context.Context, id, userID string) error {
+	snooze, err := s.repoManager.AlertSnooze().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if snooze.UserID != userID {
+		return models.ErrAlertSnoozeNotFound
+	}
+	return s.repoManager.AlertSnooze().Delete(ctx, id)
+}
+
+// IsSnoozed 判断用户当前是否暂停了告警
+func (s *alertSnoozeService) IsSnoozed(ctx context.Context, userID string, alert *models.Alert) (bool, error) {
+	snoozes, err := s.repoManager.AlertSnooze().ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return alertSnoozed(snoozes, alert), nil
+}
+
+// CleanupExpired 清理已到期的暂停，返回清理的数量
+func (s *alertSnoozeService) CleanupExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.repoManager.AlertSnooze().DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("清理到期的告警暂停失败: %w", err)
+	}
+	if deleted > 0 {
+		s.logger.Info("到期的告警暂停已清理", zap.Int64("count", deleted))
+	}
+	return deleted, nil
+}
+
+// alertSnoozed 判断暂停列表中是否有暂停覆盖告警
+func alertSnoozed(snoozes []*models.AlertSnooze, alert *models.Alert) bool {
+	for _, snooze := range snoozes {
+		if snooze.Matches(alert) {
+			return true
+		}
+	}
+	return false
+}
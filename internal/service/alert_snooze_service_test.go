@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAlertSnoozeRepository 内存中的告警暂停
+type fakeAlertSnoozeRepository struct {
+	snoozes map[string]*models.AlertSnooze
+}
+
+func (r *fakeAlertSnoozeRepository) Create(ctx context.Context, snooze *models.AlertSnooze) error {
+	snooze.ID = uuid.New().String()
+	r.snoozes[snooze.ID] = snooze
+	return nil
+}
+
+func (r *fakeAlertSnoozeRepository) GetByID(ctx context.Context, id string) (*models.AlertSnooze, error) {
+	snooze, ok := r.snoozes[id]
+	if !ok {
+		return nil, models.ErrAlertSnoozeNotFound
+	}
+	return snooze, nil
+}
+
+func (r *fakeAlertSnoozeRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.AlertSnooze, error) {
+	var snoozes []*models.AlertSnooze
+	for _, snooze := range r.snoozes {
+		if snooze.UserID == userID && snooze.Active(now) {
+			snoozes = append(snoozes, snooze)
+		}
+	}
+	return snoozes, nil
+}
+
+func (r *fakeAlertSnoozeRepository) Delete(ctx context.Context, id string) error {
+	delete(r.snoozes, id)
+	return nil
+}
+
+func (r *fakeAlertSnoozeRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for id, snooze := range r.snoozes {
+		if !snooze.Active(before) {
+			delete(r.snoozes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakeSnoozeAlertRepository 按ID返回告警
+type fakeSnoozeAlertRepository struct {
+	repository.AlertRepository
+	alerts map[string]*models.Alert
+}
+
+func (r *fakeSnoozeAlertRepository) GetByID(ctx context.Context, id string) (*models.Alert, error) {
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+type alertSnoozeRepoManager struct {
+	*MockRepositoryManager
+	snoozes *fakeAlertSnoozeRepository
+	alerts  *fakeSnoozeAlertRepository
+	users   *fakeChatOpsUserRepository
+	quiet   *fakeQuietHoursRepository
+	// attempts 记录通知投递，发送通知的测试共用
+	attempts *fakeNotificationAttemptRepository
+	// channels 团队通知渠道，为 nil 时没有团队渠道
+	channels *fakeNotificationChannelRepository
+}
+
+func (m *alertSnoozeRepoManager) AlertSnooze() repository.AlertSnoozeRepository { return m.snoozes }
+
+func (m *alertSnoozeRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *alertSnoozeRepoManager) NotificationAttempt() repository.NotificationAttemptRepository {
+	return m.attempts
+}
+
+func (m *alertSnoozeRepoManager) User() repository.UserRepository { return m.users }
+
+func (m *alertSnoozeRepoManager) QuietHours() repository.QuietHoursRepository { return m.quiet }
+
+func (m *alertSnoozeRepoManager) NotificationChannel() repository.NotificationChannelRepository {
+	if m.channels == nil {
+		return &fakeNotificationChannelRepository{}
+	}
+	return m.channels
+}
+
+func newAlertSnoozeTestRepoManager() *alertSnoozeRepoManager {
+	return &alertSnoozeRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		snoozes:               &fakeAlertSnoozeRepository{snoozes: map[string]*models.AlertSnooze{}},
+		quiet:                 &fakeQuietHoursRepository{settings: map[string]*models.QuietHours{}},
+		attempts:              &fakeNotificationAttemptRepository{},
+		alerts: &fakeSnoozeAlertRepository{alerts: map[string]*models.Alert{
+			"a1": {ID: "a1", Labels: map[string]string{"service": "checkout", "env": "prod"}},
+			"a2": {ID: "a2", Labels: map[string]string{"service": "search"}},
+		}},
+		users: &fakeChatOpsUserRepository{users: []*models.User{
+			{ID: "u1", Username: "alice"},
+			{ID: "u2", Username: "bob"},
+		}},
+	}
+}
+
+func TestAlertSnoozeService_Snooze(t *testing.T) {
+	repoManager := newAlertSnoozeTestRepoManager()
+	svc := NewAlertSnoozeService(repoManager, config.AlertConfig{SnoozeCleanupInterval: time.Minute}, zap.NewNop())
+	ctx := context.Background()
+
+	missing := "missing"
+	_, err := svc.Create(ctx, &models.AlertSnoozeRequest{AlertID: &missing, DurationSeconds: 3600}, "u1")
+	assert.ErrorIs(t, err, models.ErrAlertNotFound)
+	_, err = svc.Create(ctx, &models.AlertSnoozeRequest{Matchers: map[string]string{"service": "checkout"}}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	group, err := svc.Create(ctx, &models.AlertSnoozeRequest{
+		Matchers: map[string]string{"service": "checkout"}, DurationSeconds: 3600,
+	}, "u1")
+	require.NoError(t, err)
+
+	// 暂停只对创建暂停的用户生效
+	a1, a2 := repoManager.alerts.alerts["a1"], repoManager.alerts.alerts["a2"]
+	snoozed, err := svc.IsSnoozed(ctx, "u1", a1)
+	require.NoError(t, err)
+	assert.True(t, snoozed)
+	snoozed, err = svc.IsSnoozed(ctx, "u1", a2)
+	require.NoError(t, err)
+	assert.False(t, snoozed)
+	snoozed, err = svc.IsSnoozed(ctx, "u2", a1)
+	require.NoError(t, err)
+	assert.False(t, snoozed)
+
+	// 其他用户不能取消
+	assert.ErrorIs(t, svc.Delete(ctx, group.ID, "u2"), models.ErrAlertSnoozeNotFound)
+	require.NoError(t, svc.Delete(ctx, group.ID, "u1"))
+
+	// 到期的暂停不再生效并由清理任务删除
+	alertID := "a2"
+	single, err := svc.Create(ctx, &models.AlertSnoozeRequest{AlertID: &alertID, DurationSeconds: 600}, "u1")
+	require.NoError(t, err)
+	single.ExpiresAt = time.Now().Add(-time.Second)
+	snoozes, err := svc.List(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, snoozes)
+	deleted, err := svc.CleanupExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestNotificationService_SkipsSnoozedRecipient(t *testing.T) {
+	repoManager := newAlertSnoozeTestRepoManager()
+	snoozes := NewAlertSnoozeService(repoManager, config.AlertConfig{}, zap.NewNop())
+	alertID := uuid.New()
+	repoManager.alerts.alerts[alertID.String()] = &models.Alert{ID: alertID.String(), Labels: map[string]string{"service": "checkout"}}
+
+	_, err := snoozes.Create(context.Background(), &models.AlertSnoozeRequest{
+		Matchers: map[string]string{"service": "checkout"}, DurationSeconds: 3600,
+	}, "u1")
+	require.NoError(t, err)
+
+	// 接收人暂停了告警时直接返回，不保存通知记录
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+	err = svc.Send(context.Background(), &models.Notification{
+		AlertID:   alertID,
+		Type:      models.NotificationTypeDingTalk,
+		Recipient: "alice",
+		Content:   "checkout 告警",
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertStatsService 告警统计服务实现。告警按开始时间所在的小时物化到汇总表，
+// 统计和趋势只读取汇总表，耗时与告警总数无关。告警被保留策略物理删除后，汇总表中保留其历史计数
+type alertStatsService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.AlertStatsConfig
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewAlertStatsService 创建告警统计服务实例
+func NewAlertStatsService(repoManager repository.RepositoryManager, cfg config.AlertStatsConfig, logger *zap.Logger) AlertStatsService {
+	return &alertStatsService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Interval 汇总周期
+func (s *alertStatsService) Interval() time.Duration {
+	return s.cfg.RollupInterval
+}
+
+// Rollup 重算自上次汇总以来有告警变化的小时，首次执行时重建全部小时
+func (s *alertStatsService) Rollup(ctx context.Context) (int, error) {
+	return s.repoManager.AlertStats().Refresh(ctx, s.cfg.RollupOverlap)
+}
+
+// Stats 获取时间范围内按严重级别、状态、规则和团队汇总的告警数
+func (s *alertStatsService) Stats(ctx context.Context, query *models.AlertStatsQuery) (*models.AlertStatsSummary, error) {
+	if err := query.Normalize(s.now()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repoManager.AlertStats().Summary(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	watermark, err := s.repoManager.AlertStats().GetWatermark(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.AlertStatsSummary{
+		From:           query.From,
+		To:             query.To,
+		BySeverity:     make(map[models.AlertSeverity]int64),
+		ByStatus:       make(map[models.AlertStatus]int64),
+		ByRule:         make(map[string]int64),
+		ByTeam:         make(map[string]int64),
+		MaterializedAt: watermark,
+	}
+	for _, row := range rows {
+		summary.Total += row.Count
+		summary.BySeverity[row.Severity] += row.Count
+		summary.ByStatus[row.Status] += row.Count
+		summary.ByRule[row.RuleID] += row.Count
+		summary.ByTeam[row.Team] += row.Count
+	}
+	return summary, nil
+}
+
+// Trend 获取时间范围内按时间粒度汇总的告警数，没有告警的时间点计为 0
+func (s *alertStatsService) Trend(ctx context.Context, query *models.AlertStatsQuery) (*models.AlertStatsTrend, error) {
+	if err := query.Normalize(s.now()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repoManager.AlertStats().Trend(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	watermark, err := s.repoManager.AlertStats().GetWatermark(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []*models.AlertStatsTrendPoint
+	index := make(map[int64]*models.AlertStatsTrendPoint)
+	for start := alertStatsIntervalStart(query.From, query.Interval); start.Before(query.To); start = alertStatsNextInterval(start, query.Interval) {
+		point := &models.AlertStatsTrendPoint{
+			Timestamp:  start,
+			BySeverity: make(map[models.AlertSeverity]int64),
+		}
+		points = append(points, point)
+		index[start.Unix()] = point
+	}
+	for _, row := range rows {
+		point, ok := index[row.Bucket.Unix()]
+		if !ok {
+			continue
+		}
+		point.Count += row.Count
+		point.BySeverity[row.Severity] += row.Count
+	}
+
+	return &models.AlertStatsTrend{
+		From:           query.From,
+		To:             query.To,
+		Interval:       query.Interval,
+		Points:         points,
+		MaterializedAt: watermark,
+	}, nil
+}
+
+// alertStatsIntervalStart 返回 t 所在时间粒度的起点，与 PostgreSQL date_trunc 一致，周从周一开始
+func alertStatsIntervalStart(t time.Time, interval models.AlertStatsInterval) time.Time {
+	t = t.UTC()
+	switch interval {
+	case models.AlertStatsIntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case models.AlertStatsIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// alertStatsNextInterval 返回下一个时间粒度的起点
+func alertStatsNextInterval(t time.Time, interval models.AlertStatsInterval) time.Time {
+	switch interval {
+	case models.AlertStatsIntervalDay:
+		return t.AddDate(0, 0, 1)
+	case models.AlertStatsIntervalWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.Add(time.Hour)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAlertStatsRepository 返回固定的汇总行，并记录收到的查询
+type fakeAlertStatsRepository struct {
+	repository.AlertStatsRepository
+	summary   []*models.AlertStatsRow
+	trend     []*models.AlertStatsRow
+	watermark *time.Time
+	query     *models.AlertStatsQuery
+}
+
+func (r *fakeAlertStatsRepository) Summary(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error) {
+	r.query = query
+	return r.summary, nil
+}
+
+func (r *fakeAlertStatsRepository) Trend(ctx context.Context, query *models.AlertStatsQuery) ([]*models.AlertStatsRow, error) {
+	r.query = query
+	return r.trend, nil
+}
+
+func (r *fakeAlertStatsRepository) GetWatermark(ctx context.Context) (*time.Time, error) {
+	return r.watermark, nil
+}
+
+type alertStatsRepoManager struct {
+	*MockRepositoryManager
+	stats *fakeAlertStatsRepository
+}
+
+func (m *alertStatsRepoManager) AlertStats() repository.AlertStatsRepository { return m.stats }
+
+func newAlertStatsTestService(repo *fakeAlertStatsRepository, now time.Time) *alertStatsService {
+	svc := NewAlertStatsService(&alertStatsRepoManager{stats: repo}, config.AlertStatsConfig{}, zap.NewNop()).(*alertStatsService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAlertStatsService_Stats(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 25, 0, 0, time.UTC)
+	watermark := now.Add(-time.Minute)
+	repo := &fakeAlertStatsRepository{
+		watermark: &watermark,
+		summary: []*models.AlertStatsRow{
+			{Severity: models.AlertSeverityCritical, Status: models.AlertStatusFiring, RuleID: "r1", Team: "db", Count: 3},
+			{Severity: models.AlertSeverityHigh, Status: models.AlertStatusResolved, RuleID: "r1", Team: "", Count: 5},
+			{Severity: models.AlertSeverityCritical, Status: models.AlertStatusResolved, RuleID: "", Team: "db", Count: 2},
+		},
+	}
+	svc := newAlertStatsTestService(repo, now)
+
+	stats, err := svc.Stats(context.Background(), &models.AlertStatsQuery{})
+	require.NoError(t, err)
+	// 默认统计最近 24 小时，按整点对齐
+	assert.Equal(t, time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), repo.query.From)
+	assert.Equal(t, time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), repo.query.To)
+	assert.Equal(t, int64(10), stats.Total)
+	assert.Equal(t, map[models.AlertSeverity]int64{models.AlertSeverityCritical: 5, models.AlertSeverityHigh: 5}, stats.BySeverity)
+	assert.Equal(t, map[models.AlertStatus]int64{models.AlertStatusFiring: 3, models.AlertStatusResolved: 7}, stats.ByStatus)
+	assert.Equal(t, map[string]int64{"r1": 8, "": 2}, stats.ByRule)
+	assert.Equal(t, map[string]int64{"db": 5, "": 5}, stats.ByTeam)
+	assert.Equal(t, &watermark, stats.MaterializedAt)
+
+	_, err = svc.Stats(context.Background(), &models.AlertStatsQuery{From: now, To: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Stats(context.Background(), &models.AlertStatsQuery{From: now.AddDate(-2, 0, 0)})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestAlertStatsService_Trend(t *testing.T) {
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	repo := &fakeAlertStatsRepository{
+		trend: []*models.AlertStatsRow{
+			{Bucket: time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), Severity: models.AlertSeverityCritical, Count: 4},
+			{Bucket: time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), Severity: models.AlertSeverityInfo, Count: 1},
+			{Bucket: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Severity: models.AlertSeverityHigh, Count: 2},
+		},
+	}
+	svc := newAlertStatsTestService(repo, now)
+
+	// 按周统计时从 From 所在的周一开始，没有告警的周计为 0
+	trend, err := svc.Trend(context.Background(), &models.AlertStatsQuery{
+		From:     time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+		Interval: models.AlertStatsIntervalWeek,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, trend.MaterializedAt)
+	require.Len(t, trend.Points, 2)
+	assert.Equal(t, time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), trend.Points[0].Timestamp)
+	assert.Equal(t, int64(5), trend.Points[0].Count)
+	assert.Equal(t, map[models.AlertSeverity]int64{models.AlertSeverityCritical: 4, models.AlertSeverityInfo: 1}, trend.Points[0].BySeverity)
+	assert.Equal(t, int64(2), trend.Points[1].Count)
+
+	trend, err = svc.Trend(context.Background(), &models.AlertStatsQuery{From: now.Add(-3 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatsIntervalHour, trend.Interval)
+	assert.Len(t, trend.Points, 3)
+	for _, point := range trend.Points {
+		assert.Zero(t, point.Count)
+	}
+
+	_, err = svc.Trend(context.Background(), &models.AlertStatsQuery{Interval: "month"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
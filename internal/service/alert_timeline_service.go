@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertTimelineService 告警时间线服务实现
+type alertTimelineService struct {
+	repoManager repository.RepositoryManager
+	inbox       InboxNotifier
+	logger      *zap.Logger
+}
+
+// NewAlertTimelineService 创建告警时间线服务实例，inbox 为 nil 时不通知评论中 @ 提及的用户
+func NewAlertTimelineService(repoManager repository.RepositoryManager, inbox InboxNotifier, logger *zap.Logger) AlertTimelineService {
+	return &alertTimelineService{
+		repoManager: repoManager,
+		inbox:       inbox,
+		logger:      logger,
+	}
+}
+
+// GetTimeline 合并告警历史、通知、关联工单和静默记录，按时间升序返回
+func (s *alertTimelineService) GetTimeline(ctx context.Context, alertID string) (*models.AlertTimeline, error) {
+	alert, err := s.repoManager.Alert().GetByID(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取告警失败: %w", err)
+	}
+
+	histories, err := s.repoManager.Alert().GetHistory(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警历史失败: %w", err)
+	}
+
+	notifications, err := s.repoManager.Notification().GetByAlertID(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警通知记录失败: %w", err)
+	}
+
+	tickets, err := s.repoManager.Ticket().GetByAlertID(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("获取关联工单失败: %w", err)
+	}
+
+	events := make([]*models.AlertTimelineEvent, 0, len(histories)+len(notifications)+len(tickets)+1)
+	for _, history := range histories {
+		events = append(events, historyEvent(history))
+	}
+	for _, notification := range notifications {
+		events = append(events, notificationEvent(notification))
+	}
+	for _, ticket := range tickets {
+		events = append(events, ticketEvent(ticket))
+	}
+
+	// 静默规则可能已被清理，缺失时不影响时间线的其余部分
+	if alert.SilenceID != nil && *alert.SilenceID != "" {
+		silence, err := s.repoManager.Alert().GetSilence(ctx, *alert.SilenceID)
+		if err != nil {
+			s.logger.Warn("获取告警静默规则失败", zap.Error(err),
+				zap.String("alert_id", alertID), zap.String("silence_id", *alert.SilenceID))
+		} else {
+			events = append(events, silenceEvent(silence))
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return &models.AlertTimeline{
+		AlertID: alertID,
+		Events:  events,
+	}, nil
+}
+
+// AddComment 为告警添加评论，评论记录在告警历史中并出现在时间线上
+func (s *alertTimelineService) AddComment(ctx context.Context, alertID, userID, comment string) (*models.AlertTimelineEvent, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, fmt.Errorf("%w: 评论内容不能为空", models.ErrInvalidInput)
+	}
+
+	exists, err := s.repoManager.Alert().Exists(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("检查告警是否存在失败: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrAlertNotFound
+	}
+
+	history := &models.AlertHistory{
+		ID:        uuid.New().String(),
+		AlertID:   alertID,
+		Action:    models.AlertHistoryActionCommented,
+		Comment:   &comment,
+		CreatedAt: time.Now(),
+	}
+	if userID != "" {
+		history.UserID = &userID
+	}
+
+	if err := s.repoManager.Alert().AddHistory(ctx, history); err != nil {
+		s.logger.Error("添加告警评论失败", zap.Error(err), zap.String("alert_id", alertID))
+		return nil, fmt.Errorf("添加告警评论失败: %w", err)
+	}
+	s.notifyMentions(ctx, alertID, history)
+
+	s.logger.Info("告警评论已添加", zap.String("alert_id", alertID), zap.String("user_id", userID))
+	return historyEvent(history), nil
+}
+
+// notifyMentions 向评论中 @ 提及的用户投递站内通知
+func (s *alertTimelineService) notifyMentions(ctx context.Context, alertID string, history *models.AlertHistory) {
+	if s.inbox == nil || len(models.ParseMentions(*history.Comment)) == 0 {
+		return
+	}
+	alert, err := s.repoManager.Alert().GetByID(ctx, alertID)
+	if err != nil {
+		s.logger.Warn("获取告警失败，不通知评论中提及的用户", zap.Error(err), zap.String("alert_id", alertID))
+		return
+	}
+
+	s.inbox.NotifyMentions(ctx, *history.Comment, models.InboxItem{
+		Body:         *history.Comment,
+		ResourceType: models.InboxResourceAlert,
+		ResourceID:   alertID,
+		ActorID:      history.UserID,
+		MessageKey:   "inbox.mentioned_alert",
+		MessageData:  map[string]interface{}{"Name": alert.Name},
+	})
+}
+
+// historyEvent 将告警历史转换为时间线事件，评论、修复动作结果和重复触发单独归类，其余视为状态变更
+func historyEvent(history *models.AlertHistory) *models.AlertTimelineEvent {
+	event := &models.AlertTimelineEvent{
+		Type:      models.AlertTimelineStateChange,
+		Action:    history.Action,
+		Timestamp: history.CreatedAt,
+		UserID:    history.UserID,
+		Comment:   history.Comment,
+	}
+	switch history.Action {
+	case models.AlertHistoryActionCommented:
+		event.Type = models.AlertTimelineComment
+		return event
+	case models.AlertHistoryActionRemediationSucceeded, models.AlertHistoryActionRemediationFailed,
+		models.AlertHistoryActionRemediationRejected:
+		event.Type = models.AlertTimelineRemediation
+		event.Details = history.NewValue
+		return event
+	case models.AlertHistoryActionOccurred:
+		event.Type = models.AlertTimelineOccurrence
+		event.Details = history.NewValue
+		return event
+	}
+
+	details := make(map[string]interface{})
+	if status, ok := history.OldValue["status"]; ok {
+		details["from_status"] = status
+	}
+	if status, ok := history.NewValue["status"]; ok {
+		details["to_status"] = status
+	}
+	if len(details) > 0 {
+		event.Details = details
+	}
+	return event
+}
+
+// notificationEvent 将通知记录转换为时间线事件，已发送的通知以发送时间为准
+func notificationEvent(notification *models.Notification) *models.AlertTimelineEvent {
+	timestamp := notification.CreatedAt
+	if notification.SentAt != nil {
+		timestamp = *notification.SentAt
+	}
+
+	details := map[string]interface{}{
+		"notification_id": notification.ID.String(),
+		"channel":         notification.Type,
+		"recipient":       notification.Recipient,
+		"retry_count":     notification.RetryCount,
+	}
+	if notification.LastError != nil {
+		details["error"] = *notification.LastError
+	}
+
+	return &models.AlertTimelineEvent{
+		Type:      models.AlertTimelineNotification,
+		Action:    string(notification.Status),
+		Timestamp: timestamp,
+		Details:   details,
+	}
+}
+
+// ticketEvent 将关联工单转换为时间线事件
+func ticketEvent(ticket *models.Ticket) *models.AlertTimelineEvent {
+	return &models.AlertTimelineEvent{
+		Type:      models.AlertTimelineTicket,
+		Action:    "linked",
+		Timestamp: ticket.CreatedAt,
+		UserID:    &ticket.ReporterID,
+		Details: map[string]interface{}{
+			"ticket_id": ticket.ID,
+			"number":    ticket.Number,
+			"title":     ticket.Title,
+			"status":    ticket.Status,
+			"priority":  ticket.Priority,
+		},
+	}
+}
+
+// silenceEvent 将静默规则转换为时间线事件
+func silenceEvent(silence *models.AlertSilence) *models.AlertTimelineEvent {
+	return &models.AlertTimelineEvent{
+		Type:      models.AlertTimelineSilence,
+		Action:    "silenced",
+		Timestamp: silence.StartsAt,
+		UserID:    &silence.CreatedBy,
+		Comment:   silence.Comment,
+		Details: map[string]interface{}{
+			"silence_id": silence.ID,
+			"name":       silence.Name,
+			"ends_at":    silence.EndsAt,
+			"active":     silence.Active,
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeTimelineAlertRepository 内存告警仓储，仅实现时间线所需方法
+type fakeTimelineAlertRepository struct {
+	repository.AlertRepository
+	alert     *models.Alert
+	histories []*models.AlertHistory
+	silence   *models.AlertSilence
+}
+
+func (r *fakeTimelineAlertRepository) GetByID(ctx context.Context, id string) (*models.Alert, error) {
+	if r.alert == nil || r.alert.ID != id {
+		return nil, models.ErrAlertNotFound
+	}
+	return r.alert, nil
+}
+
+func (r *fakeTimelineAlertRepository) Exists(ctx context.Context, id string) (bool, error) {
+	return r.alert != nil && r.alert.ID == id, nil
+}
+
+func (r *fakeTimelineAlertRepository) GetHistory(ctx context.Context, alertID string) ([]*models.AlertHistory, error) {
+	return r.histories, nil
+}
+
+func (r *fakeTimelineAlertRepository) AddHistory(ctx context.Context, history *models.AlertHistory) error {
+	r.histories = append(r.histories, history)
+	return nil
+}
+
+func (r *fakeTimelineAlertRepository) GetSilence(ctx context.Context, silenceID string) (*models.AlertSilence, error) {
+	if r.silence == nil {
+		return nil, models.ErrAlertSilenceNotFound
+	}
+	return r.silence, nil
+}
+
+type fakeTimelineNotificationRepository struct {
+	repository.NotificationRepository
+	notifications []*models.Notification
+}
+
+func (r *fakeTimelineNotificationRepository) GetByAlertID(ctx context.Context, alertID string) ([]*models.Notification, error) {
+	return r.notifications, nil
+}
+
+type fakeTimelineTicketRepository struct {
+	repository.TicketRepository
+	tickets []*models.Ticket
+}
+
+func (r *fakeTimelineTicketRepository) GetByAlertID(ctx context.Context, alertID string) ([]*models.Ticket, error) {
+	return r.tickets, nil
+}
+
+type timelineRepoManager struct {
+	*MockRepositoryManager
+	alerts        *fakeTimelineAlertRepository
+	notifications *fakeTimelineNotificationRepository
+	tickets       *fakeTimelineTicketRepository
+}
+
+func (m *timelineRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *timelineRepoManager) Notification() repository.NotificationRepository {
+	return m.notifications
+}
+
+func (m *timelineRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func TestAlertTimelineService_GetTimeline(t *testing.T) {
+	base := time.Now().Add(-time.Hour)
+	silenceID := "silence-1"
+	sentAt := base.Add(2 * time.Minute)
+
+	repoManager := &timelineRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		alerts: &fakeTimelineAlertRepository{
+			alert: &models.Alert{ID: "a1", SilenceID: &silenceID},
+			histories: []*models.AlertHistory{
+				{Action: "acknowledged", CreatedAt: base.Add(3 * time.Minute),
+					OldValue: map[string]interface{}{"status": "firing"},
+					NewValue: map[string]interface{}{"status": "acked"}},
+				{Action: "created", CreatedAt: base},
+			},
+			silence: &models.AlertSilence{ID: silenceID, Name: "维护窗口", StartsAt: base.Add(5 * time.Minute)},
+		},
+		notifications: &fakeTimelineNotificationRepository{notifications: []*models.Notification{
+			{ID: uuid.New(), Status: models.NotificationStatusSent, CreatedAt: base.Add(time.Minute), SentAt: &sentAt},
+		}},
+		tickets: &fakeTimelineTicketRepository{tickets: []*models.Ticket{
+			{ID: "t1", Number: "INC-1", CreatedAt: base.Add(4 * time.Minute)},
+		}},
+	}
+	svc := NewAlertTimelineService(repoManager, nil, zap.NewNop())
+	ctx := context.Background()
+
+	comment, err := svc.AddComment(ctx, "a1", "u1", "  已联系值班DBA  ")
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertTimelineComment, comment.Type)
+	assert.Equal(t, "已联系值班DBA", *comment.Comment)
+
+	timeline, err := svc.GetTimeline(ctx, "a1")
+	require.NoError(t, err)
+
+	var types []models.AlertTimelineEventType
+	for _, event := range timeline.Events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []models.AlertTimelineEventType{
+		models.AlertTimelineStateChange,
+		models.AlertTimelineNotification,
+		models.AlertTimelineStateChange,
+		models.AlertTimelineTicket,
+		models.AlertTimelineSilence,
+		models.AlertTimelineComment,
+	}, types)
+	assert.Equal(t, "acked", timeline.Events[2].Details["to_status"])
+	assert.Equal(t, sentAt, timeline.Events[1].Timestamp)
+}
+
+func TestAlertTimelineService_NotFound(t *testing.T) {
+	repoManager := &timelineRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		alerts:                &fakeTimelineAlertRepository{},
+	}
+	svc := NewAlertTimelineService(repoManager, nil, zap.NewNop())
+
+	_, err := svc.GetTimeline(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrAlertNotFound)
+
+	_, err = svc.AddComment(context.Background(), "missing", "u1", "comment")
+	assert.ErrorIs(t, err, models.ErrAlertNotFound)
+}
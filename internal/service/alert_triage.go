@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+)
+
+const (
+	// alertTriageBreakerName 分诊服务熔断器名称
+	alertTriageBreakerName = "alert_triage"
+	// triageResponseReadLimit 读取分诊服务响应体的最大字节数
+	triageResponseReadLimit = 64 * 1024
+)
+
+// httpTriageScorer 通过 HTTP 调用外部分诊服务为告警评分。
+// 以 JSON 提交 {"alert": 告警}，服务返回 {"score": 0-100, "assignee": "用户ID"}；
+// 调用在熔断器保护下执行，服务持续不可用时熔断期间直接跳过，不拖慢告警接入
+type httpTriageScorer struct {
+	url     string
+	token   string
+	client  *http.Client
+	breaker *breaker.Breaker
+}
+
+// NewTriageScorer 创建外部分诊服务评分器，未开启时返回 nil，告警创建时不评分
+func NewTriageScorer(cfg config.AlertTriageConfig, client *http.Client, breaker *breaker.Breaker) TriageScorer {
+	if !cfg.Enabled || cfg.URL == "" {
+		return nil
+	}
+	return &httpTriageScorer{url: cfg.URL, token: cfg.Token, client: client, breaker: breaker}
+}
+
+// Score 获取告警的优先级评分和建议的处理人，熔断时返回 breaker.ErrOpen
+func (s *httpTriageScorer) Score(ctx context.Context, alert *models.Alert) (*models.AlertTriage, error) {
+	body, err := json.Marshal(&models.AlertTriageRequest{Alert: alert})
+	if err != nil {
+		return nil, fmt.Errorf("序列化分诊请求失败: %w", err)
+	}
+
+	var triage models.AlertTriage
+	err = s.breaker.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("创建分诊请求失败: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if s.token != "" {
+			req.Header.Set("Authorization", "Bearer "+s.token)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("请求分诊服务失败: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, triageResponseReadLimit))
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("分诊服务返回错误状态 %d: %s", resp.StatusCode, string(respBody))
+		}
+		if err := json.Unmarshal(respBody, &triage); err != nil {
+			return fmt.Errorf("解析分诊结果失败: %w", err)
+		}
+		return triage.Validate()
+	})
+	// 超时返回时请求可能仍在执行，只有成功时才读取结果
+	if err != nil {
+		return nil, err
+	}
+	return &triage, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+)
+
+// fakeTriageAssigner 分派策略总是选中固定的处理人
+type fakeTriageAssigner struct {
+	userID string
+}
+
+func (a *fakeTriageAssigner) Pick(ctx context.Context, team string, target models.AssignmentTarget) (string, error) {
+	return a.userID, nil
+}
+
+// fakeTriageScorer 返回固定的评分结果
+type fakeTriageScorer struct {
+	triage *models.AlertTriage
+}
+
+func (s *fakeTriageScorer) Score(ctx context.Context, alert *models.Alert) (*models.AlertTriage, error) {
+	triage := *s.triage
+	return &triage, nil
+}
+
+func TestAlertService_CreateTriage(t *testing.T) {
+	var (
+		status   = http.StatusOK
+		response = `{"score": 87.5, "assignee": "u1"}`
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "Bearer triage-token", r.Header.Get("Authorization"))
+		var req models.AlertTriageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "DiskFull", req.Alert.Name)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	cfg := config.AlertTriageConfig{Enabled: true, URL: server.URL, Token: "triage-token", Timeout: time.Second}
+	scorer := NewTriageScorer(cfg, server.Client(), breaker.New(breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute}))
+	alerts := &fakeServiceAlertRepository{alerts: map[string]*models.Alert{}}
+	users := &fakeChatOpsUserRepository{users: []*models.User{{ID: "u1", Username: "alice"}}}
+	svc := NewAlertService(alerts, users, nil, nil, nil, nil, nil, nil, nil, scorer, 0, zap.NewNop())
+	ctx := context.Background()
+	create := func(fingerprint string) *models.Alert {
+		alert := newOccurrenceTestAlert(95)
+		alert.Fingerprint = fingerprint
+		require.NoError(t, svc.Create(ctx, alert))
+		return alerts.alerts[alert.ID]
+	}
+
+	// 评分和建议的处理人保存在告警上，没有分派策略时使用建议的处理人
+	alert := create("scored")
+	require.NotNil(t, alert.TriageScore)
+	assert.Equal(t, 87.5, *alert.TriageScore)
+	assert.Equal(t, "u1", *alert.TriageAssignee)
+	assert.Equal(t, "u1", alert.Annotations[models.AssigneeAnnotation])
+
+	// 建议的处理人不是平台用户时只保存建议，不分派
+	response = `{"score": 40, "assignee": "ghost"}`
+	alert = create("unknown-assignee")
+	assert.Equal(t, 40.0, *alert.TriageScore)
+	assert.Equal(t, "ghost", *alert.TriageAssignee)
+	assert.Empty(t, alert.Annotations[models.AssigneeAnnotation])
+
+	// 评分超出范围或评分服务不可用时告警照常创建，连续失败后熔断，不再请求评分服务
+	response = `{"score": 150}`
+	alert = create("invalid-score")
+	assert.Nil(t, alert.TriageScore)
+	status = http.StatusServiceUnavailable
+	alert = create("unavailable")
+	assert.Nil(t, alert.TriageScore)
+	calls := requests
+	alert = create("breaker-open")
+	assert.Nil(t, alert.TriageScore)
+	assert.Equal(t, calls, requests)
+	assert.Len(t, alerts.alerts, 5)
+}
+
+func TestAlertService_CreateTriagePrefersAssignmentPolicy(t *testing.T) {
+	scorer := NewTriageScorer(config.AlertTriageConfig{Enabled: true, URL: "http://127.0.0.1:0"}, http.DefaultClient, breaker.New(breaker.Config{}))
+	require.NotNil(t, scorer)
+	assert.Nil(t, NewTriageScorer(config.AlertTriageConfig{URL: "http://127.0.0.1:0"}, http.DefaultClient, breaker.New(breaker.Config{})))
+
+	alerts := &fakeServiceAlertRepository{alerts: map[string]*models.Alert{}}
+	users := &fakeChatOpsUserRepository{users: []*models.User{{ID: "u1"}, {ID: "u2"}}}
+	svc := NewAlertService(alerts, users, nil, &fakeTriageAssigner{userID: "u2"}, nil, nil, nil, nil, nil, &fakeTriageScorer{
+		triage: &models.AlertTriage{Score: 90, Assignee: "u1"},
+	}, 0, zap.NewNop())
+
+	alert := newOccurrenceTestAlert(95)
+	alert.Labels[models.TeamLabel] = "dba"
+	require.NoError(t, svc.Create(context.Background(), alert))
+	assert.Equal(t, "u2", alert.Annotations[models.AssigneeAnnotation])
+	assert.Equal(t, "u1", *alert.TriageAssignee)
+}
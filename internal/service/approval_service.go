@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// approvalExecutor 执行已批准的敏感操作，返回的结果保存到审批请求中，执行失败时同样保存已产生的结果
+type approvalExecutor func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// approvalService 敏感操作审批服务实现
+type approvalService struct {
+	repoManager repository.RepositoryManager
+	executors   map[models.ApprovalAction]approvalExecutor
+	required    map[models.ApprovalAction]bool
+	ttl         time.Duration
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewApprovalService 创建敏感操作审批服务实例，数据源删除和工单关闭分别通过 dataSources 和 tickets 执行
+func NewApprovalService(repoManager repository.RepositoryManager, dataSources DataSourceService, tickets TicketService, cfg config.ApprovalConfig, logger *zap.Logger) ApprovalService {
+	s := &approvalService{
+		repoManager: repoManager,
+		required:    make(map[models.ApprovalAction]bool),
+		ttl:         cfg.TTL,
+		logger:      logger,
+		now:         time.Now,
+	}
+	for _, action := range cfg.RequiredActions {
+		action := models.ApprovalAction(strings.TrimSpace(action))
+		if !action.IsValid() {
+			logger.Warn("忽略不支持的审批操作", zap.String("action", string(action)))
+			continue
+		}
+		s.required[action] = true
+	}
+
+	s.executors = map[models.ApprovalAction]approvalExecutor{
+		models.ApprovalActionDataSourceDelete: func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+			var p models.DataSourceDeleteParams
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, fmt.Errorf("解析操作参数失败: %w", err)
+			}
+			if err := dataSources.Delete(ctx, p.ID); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"id": p.ID}, nil
+		},
+		models.ApprovalActionRulesDisableAll: s.disableAllRules,
+		models.ApprovalActionTicketsBulkClose: func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+			var p models.TicketBulkCloseParams
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, fmt.Errorf("解析操作参数失败: %w", err)
+			}
+			closed := 0
+			failed := make(map[string]string)
+			for _, id := range p.IDs {
+				if err := tickets.UpdateStatus(ctx, id, models.TicketStatusClosed); err != nil {
+					failed[id] = err.Error()
+					continue
+				}
+				closed++
+			}
+			result := map[string]interface{}{"closed": closed, "failed": failed}
+			if closed == 0 {
+				return result, fmt.Errorf("%d 个工单全部关闭失败", len(p.IDs))
+			}
+			return result, nil
+		},
+	}
+	return s
+}
+
+// disableAllRules 禁用全部生效中的规则，结果中保存被禁用的规则ID，便于恢复
+func (s *approvalService) disableAllRules(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	rules, err := s.repoManager.Rule().GetActiveRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+	if err := s.repoManager.Rule().BatchDeactivate(ctx, ids); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"disabled": len(ids), "rule_ids": ids}, nil
+}
+
+// RequiresApproval 操作是否配置为需要审批
+func (s *approvalService) RequiresApproval(action models.ApprovalAction) bool {
+	return s.required[action]
+}
+
+// Submit 提交敏感操作，配置为需要审批的操作保存为待审批请求，其他操作立即执行
+func (s *approvalService) Submit(ctx context.Context, userID string, req *models.ApprovalSubmitRequest) (*models.ApprovalRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	request := &models.ApprovalRequest{
+		ID:               uuid.New().String(),
+		Action:           req.Action,
+		Params:           req.Params,
+		Reason:           req.Reason,
+		Status:           models.ApprovalStatusPending,
+		RequesterID:      userID,
+		RequiresApproval: s.required[req.Action],
+		ExpiresAt:        now.Add(s.ttl),
+	}
+	if !request.RequiresApproval {
+		request.Status = models.ApprovalStatusApproved
+	}
+
+	audit := approvalAudit(models.ApprovalAuditSubmit, &userID, request.ID, map[string]interface{}{
+		"action":            request.Action,
+		"params":            request.Params,
+		"reason":            request.Reason,
+		"requires_approval": request.RequiresApproval,
+	}, nil)
+	if err := s.repoManager.Approval().Create(ctx, request, audit); err != nil {
+		return nil, err
+	}
+
+	if !request.RequiresApproval {
+		return s.execute(ctx, request, userID)
+	}
+	s.logger.Info("敏感操作等待审批", zap.String("id", request.ID), zap.String("action", string(request.Action)),
+		zap.String("requester_id", userID))
+	return request, nil
+}
+
+// Approve 批准审批请求并立即执行操作，申请人不能批准自己的请求
+func (s *approvalService) Approve(ctx context.Context, id, userID string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	request, err := s.decide(ctx, id, userID, models.ApprovalStatusApproved, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, request, userID)
+}
+
+// Reject 驳回审批请求，申请人不能驳回自己的请求，撤回使用 Cancel
+func (s *approvalService) Reject(ctx context.Context, id, userID string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	if _, err := s.decide(ctx, id, userID, models.ApprovalStatusRejected, req); err != nil {
+		return nil, err
+	}
+	return s.repoManager.Approval().GetByID(ctx, id)
+}
+
+// decide 由申请人以外的用户批准或驳回待审批请求，已过期的请求标记为过期
+func (s *approvalService) decide(ctx context.Context, id, userID string, to models.ApprovalStatus, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	request, err := s.repoManager.Approval().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() {
+		return nil, models.ErrApprovalNotPending
+	}
+	if request.RequesterID == userID {
+		return nil, fmt.Errorf("%w: 不能审批自己提交的请求", models.ErrPermissionDenied)
+	}
+	if !s.now().Before(request.ExpiresAt) {
+		s.expire(ctx, request)
+		return nil, models.ErrApprovalExpired
+	}
+
+	auditAction := models.ApprovalAuditApprove
+	if to == models.ApprovalStatusRejected {
+		auditAction = models.ApprovalAuditReject
+	}
+	audit := approvalAudit(auditAction, &userID, id, map[string]interface{}{"comment": req.Comment}, nil)
+	updated, err := s.repoManager.Approval().Decide(ctx, id, &models.ApprovalDecision{
+		From:       models.ApprovalStatusPending,
+		To:         to,
+		ApproverID: &userID,
+		Comment:    req.Comment,
+	}, audit)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, models.ErrApprovalNotPending
+	}
+
+	s.logger.Info("审批请求已处理", zap.String("id", id), zap.String("action", string(request.Action)),
+		zap.String("status", string(to)), zap.String("approver_id", userID))
+	request.Status = to
+	request.ApproverID = &userID
+	request.DecisionComment = req.Comment
+	return request, nil
+}
+
+// expire 将过期的待审批请求标记为过期，失败只记录日志
+func (s *approvalService) expire(ctx context.Context, request *models.ApprovalRequest) {
+	audit := approvalAudit(models.ApprovalAuditExpire, nil, request.ID, nil, nil)
+	_, err := s.repoManager.Approval().Decide(ctx, request.ID, &models.ApprovalDecision{
+		From: models.ApprovalStatusPending,
+		To:   models.ApprovalStatusExpired,
+	}, audit)
+	if err != nil {
+		s.logger.Error("标记审批请求过期失败", zap.Error(err), zap.String("id", request.ID))
+	}
+}
+
+// Cancel 申请人撤回待审批请求
+func (s *approvalService) Cancel(ctx context.Context, id, userID string) (*models.ApprovalRequest, error) {
+	request, err := s.repoManager.Approval().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.RequesterID != userID {
+		return nil, fmt.Errorf("%w: 只有申请人可以撤回请求", models.ErrPermissionDenied)
+	}
+
+	audit := approvalAudit(models.ApprovalAuditCancel, &userID, id, nil, nil)
+	updated, err := s.repoManager.Approval().Decide(ctx, id, &models.ApprovalDecision{
+		From: models.ApprovalStatusPending,
+		To:   models.ApprovalStatusCancelled,
+	}, audit)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, models.ErrApprovalNotPending
+	}
+	return s.repoManager.Approval().GetByID(ctx, id)
+}
+
+// execute 执行已批准的操作并保存结果，执行失败时请求标记为失败，不返回执行错误
+func (s *approvalService) execute(ctx context.Context, request *models.ApprovalRequest, actorID string) (*models.ApprovalRequest, error) {
+	result, execErr := s.executors[request.Action](ctx, request.Params)
+
+	outcome := &models.ApprovalOutcome{Status: models.ApprovalStatusExecuted}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("序列化执行结果失败: %w", err)
+		}
+		outcome.Result = data
+	}
+	if execErr != nil {
+		outcome.Status = models.ApprovalStatusFailed
+		outcome.Error = execErr.Error()
+		s.logger.Error("执行敏感操作失败", zap.Error(execErr), zap.String("id", request.ID),
+			zap.String("action", string(request.Action)))
+	} else {
+		s.logger.Info("敏感操作已执行", zap.String("id", request.ID), zap.String("action", string(request.Action)),
+			zap.String("requester_id", request.RequesterID), zap.String("actor_id", actorID))
+	}
+
+	audit := approvalAudit(models.ApprovalAuditExecute, &actorID, request.ID, result, execErr)
+	if err := s.repoManager.Approval().Complete(ctx, request.ID, outcome, audit); err != nil {
+		return nil, err
+	}
+	return s.repoManager.Approval().GetByID(ctx, request.ID)
+}
+
+// Get 获取审批请求
+func (s *approvalService) Get(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	return s.repoManager.Approval().GetByID(ctx, id)
+}
+
+// List 按创建时间倒序获取审批请求
+func (s *approvalService) List(ctx context.Context, filter *models.ApprovalFilter) ([]*models.ApprovalRequest, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	requests, err := s.repoManager.Approval().List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []*models.ApprovalRequest{}
+	}
+	return requests, nil
+}
+
+// History 获取审批请求的审计记录，按时间先后排列
+func (s *approvalService) History(ctx context.Context, id string) ([]*models.AuditLog, error) {
+	if _, err := s.repoManager.Approval().GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	resourceType := models.ApprovalAuditResource
+	logs := []*models.AuditLog{}
+	err := s.repoManager.AuditLog().Stream(ctx, &models.AuditLogFilter{ResourceType: &resourceType, ResourceID: &id}, func(log *models.AuditLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+	return logs, nil
+}
+
+// approvalAudit 构造审批请求的审计日志，values 为本次变更的内容
+func approvalAudit(action string, userID *string, requestID string, values interface{}, execErr error) *models.AuditLog {
+	resourceType := models.ApprovalAuditResource
+	log := &models.AuditLog{
+		UserID:       userID,
+		Action:       action,
+		ResourceType: &resourceType,
+		ResourceID:   &requestID,
+		Success:      execErr == nil,
+	}
+	if values != nil {
+		if data, err := json.Marshal(values); err == nil {
+			log.NewValues = data
+		}
+	}
+	if execErr != nil {
+		message := execErr.Error()
+		log.ErrorMessage = &message
+	}
+	return log
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeApprovalRepository 内存中的审批请求，audits 记录写入的审计日志
+type fakeApprovalRepository struct {
+	requests map[string]*models.ApprovalRequest
+	audits   []*models.AuditLog
+}
+
+func (r *fakeApprovalRepository) Create(ctx context.Context, request *models.ApprovalRequest, audit *models.AuditLog) error {
+	copied := *request
+	r.requests[request.ID] = &copied
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+func (r *fakeApprovalRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, models.ErrApprovalNotFound
+	}
+	copied := *request
+	return &copied, nil
+}
+
+func (r *fakeApprovalRepository) List(ctx context.Context, filter *models.ApprovalFilter) ([]*models.ApprovalRequest, error) {
+	return nil, nil
+}
+
+func (r *fakeApprovalRepository) Decide(ctx context.Context, id string, decision *models.ApprovalDecision, audit *models.AuditLog) (bool, error) {
+	request := r.requests[id]
+	if request == nil || request.Status != decision.From {
+		return false, nil
+	}
+	request.Status, request.DecisionComment = decision.To, decision.Comment
+	if decision.ApproverID != nil {
+		request.ApproverID = decision.ApproverID
+	}
+	r.audits = append(r.audits, audit)
+	return true, nil
+}
+
+func (r *fakeApprovalRepository) Complete(ctx context.Context, id string, outcome *models.ApprovalOutcome, audit *models.AuditLog) error {
+	request := r.requests[id]
+	if request.Status != models.ApprovalStatusApproved {
+		return models.ErrApprovalNotPending
+	}
+	request.Status, request.Result, request.Error = outcome.Status, outcome.Result, outcome.Error
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+// fakeApprovalDataSourceService 记录删除的数据源，fail 不为空时删除失败
+type fakeApprovalDataSourceService struct {
+	DataSourceService
+	deleted []string
+	fail    error
+}
+
+func (s *fakeApprovalDataSourceService) Delete(ctx context.Context, id string) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+// fakeApprovalTicketService 记录关闭的工单，missing 中的工单关闭失败
+type fakeApprovalTicketService struct {
+	TicketService
+	closed  []string
+	missing map[string]bool
+}
+
+func (s *fakeApprovalTicketService) UpdateStatus(ctx context.Context, id string, status models.TicketStatus) error {
+	if s.missing[id] {
+		return models.ErrTicketNotFound
+	}
+	s.closed = append(s.closed, id)
+	return nil
+}
+
+type approvalRepoManager struct {
+	*MockRepositoryManager
+	approvals *fakeApprovalRepository
+}
+
+func (m *approvalRepoManager) Approval() repository.ApprovalRepository { return m.approvals }
+
+func newTestApprovalService(required ...string) (*approvalService, *fakeApprovalRepository, *fakeApprovalDataSourceService, *fakeApprovalTicketService) {
+	repo := &fakeApprovalRepository{requests: make(map[string]*models.ApprovalRequest)}
+	repoManager := &approvalRepoManager{MockRepositoryManager: &MockRepositoryManager{}, approvals: repo}
+	dataSources := &fakeApprovalDataSourceService{}
+	tickets := &fakeApprovalTicketService{missing: map[string]bool{}}
+	cfg := config.ApprovalConfig{RequiredActions: required, TTL: time.Hour}
+	svc := NewApprovalService(repoManager, dataSources, tickets, cfg, zap.NewNop()).(*approvalService)
+	return svc, repo, dataSources, tickets
+}
+
+func TestApprovalService_RequiresSecondApprover(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, dataSources, _ := newTestApprovalService("datasource.delete", " tickets.bulk_close", "unknown")
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	request, err := svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{
+		Action: models.ApprovalActionDataSourceDelete,
+		Params: json.RawMessage(`{"id":" ds-1 "}`),
+		Reason: "下线旧集群",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusPending, request.Status)
+	assert.True(t, request.RequiresApproval)
+	assert.JSONEq(t, `{"id":"ds-1"}`, string(request.Params))
+	assert.Empty(t, dataSources.deleted)
+
+	// 申请人不能批准自己的请求
+	_, err = svc.Approve(ctx, request.ID, "alice", &models.ApprovalDecisionRequest{})
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+
+	approved, err := svc.Approve(ctx, request.ID, "bob", &models.ApprovalDecisionRequest{Comment: " 已确认 "})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusExecuted, approved.Status)
+	assert.Equal(t, "bob", *approved.ApproverID)
+	assert.Equal(t, "已确认", approved.DecisionComment)
+	assert.Equal(t, []string{"ds-1"}, dataSources.deleted)
+
+	// 已处理的请求不能再次审批
+	_, err = svc.Reject(ctx, request.ID, "carol", &models.ApprovalDecisionRequest{})
+	assert.ErrorIs(t, err, models.ErrApprovalNotPending)
+
+	actions := make([]string, 0, len(repo.audits))
+	for _, audit := range repo.audits {
+		actions = append(actions, audit.Action)
+		assert.Equal(t, request.ID, *audit.ResourceID)
+	}
+	assert.Equal(t, []string{models.ApprovalAuditSubmit, models.ApprovalAuditApprove, models.ApprovalAuditExecute}, actions)
+	assert.Equal(t, "bob", *repo.audits[2].UserID)
+	assert.True(t, repo.audits[2].Success)
+}
+
+func TestApprovalService_RejectCancelAndExpire(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, tickets := newTestApprovalService("tickets.bulk_close")
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	submit := func() *models.ApprovalRequest {
+		request, err := svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{
+			Action: models.ApprovalActionTicketsBulkClose,
+			Params: json.RawMessage(`{"ids":["t1","t2","t1",""]}`),
+		})
+		require.NoError(t, err)
+		return request
+	}
+
+	rejected, err := svc.Reject(ctx, submit().ID, "bob", &models.ApprovalDecisionRequest{Comment: "范围过大"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusRejected, rejected.Status)
+
+	// 只有申请人可以撤回
+	cancelled := submit()
+	_, err = svc.Cancel(ctx, cancelled.ID, "bob")
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+	result, err := svc.Cancel(ctx, cancelled.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusCancelled, result.Status)
+
+	// 过期的请求不能批准，并标记为过期
+	expired := submit()
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = svc.Approve(ctx, expired.ID, "bob", &models.ApprovalDecisionRequest{})
+	assert.ErrorIs(t, err, models.ErrApprovalExpired)
+	assert.Equal(t, models.ApprovalStatusExpired, repo.requests[expired.ID].Status)
+	assert.Nil(t, repo.audits[len(repo.audits)-1].UserID)
+
+	assert.Empty(t, tickets.closed)
+}
+
+func TestApprovalService_ExecutesImmediatelyWhenNotRequired(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, dataSources, tickets := newTestApprovalService()
+	tickets.missing["t2"] = true
+
+	request, err := svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{
+		Action: models.ApprovalActionTicketsBulkClose,
+		Params: json.RawMessage(`{"ids":["t1","t2"]}`),
+	})
+	require.NoError(t, err)
+	assert.False(t, request.RequiresApproval)
+	assert.Equal(t, models.ApprovalStatusExecuted, request.Status)
+	assert.Equal(t, []string{"t1"}, tickets.closed)
+
+	var result struct {
+		Closed int               `json:"closed"`
+		Failed map[string]string `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal(request.Result, &result))
+	assert.Equal(t, 1, result.Closed)
+	assert.Contains(t, result.Failed, "t2")
+
+	// 执行失败时请求标记为失败并记录错误
+	dataSources.fail = errors.New("数据源不存在")
+	request, err = svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{
+		Action: models.ApprovalActionDataSourceDelete,
+		Params: json.RawMessage(`{"id":"ds-1"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusFailed, request.Status)
+	assert.Equal(t, "数据源不存在", request.Error)
+	last := repo.audits[len(repo.audits)-1]
+	assert.False(t, last.Success)
+	assert.Equal(t, "数据源不存在", *last.ErrorMessage)
+
+	_, err = svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{Action: models.ApprovalActionDataSourceDelete})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Submit(ctx, "alice", &models.ApprovalSubmitRequest{Action: "users.delete_all"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// assignmentService 分派策略服务实现
+type assignmentService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewAssignmentService 创建分派策略服务实例
+func NewAssignmentService(repoManager repository.RepositoryManager, logger *zap.Logger) AssignmentService {
+	return &assignmentService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// List 获取全部分派策略
+func (s *assignmentService) List(ctx context.Context) ([]*models.AssignmentPolicy, error) {
+	return s.repoManager.AssignmentPolicy().List(ctx)
+}
+
+// Get 获取分派策略
+func (s *assignmentService) Get(ctx context.Context, id string) (*models.AssignmentPolicy, error) {
+	return s.repoManager.AssignmentPolicy().GetByID(ctx, id)
+}
+
+// Create 创建分派策略，每个团队只能有一个策略，未指定启用状态时默认启用
+func (s *assignmentService) Create(ctx context.Context, req *models.AssignmentPolicyRequest, createdBy string) (*models.AssignmentPolicy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy := &models.AssignmentPolicy{Enabled: true, CreatedBy: createdBy}
+	req.ApplyTo(policy)
+	if err := s.repoManager.AssignmentPolicy().Create(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建分派策略", zap.String("policy_id", policy.ID), zap.String("team", policy.Team),
+		zap.String("strategy", string(policy.Strategy)), zap.Int("members", len(policy.Members)))
+	return policy, nil
+}
+
+// Update 更新分派策略
+func (s *assignmentService) Update(ctx context.Context, id string, req *models.AssignmentPolicyRequest) (*models.AssignmentPolicy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repoManager.AssignmentPolicy().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(policy)
+	if err := s.repoManager.AssignmentPolicy().Update(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Delete 删除分派策略，团队的工单和告警此后不再自动分派
+func (s *assignmentService) Delete(ctx context.Context, id string) error {
+	return s.repoManager.AssignmentPolicy().Delete(ctx, id)
+}
+
+// Pick 按团队的分派策略选择处理人，团队没有策略或策略已停用时返回空字符串；
+// 已停用或不存在的用户和 target.Exclude 不参与分派，没有可分派的成员时返回 ErrNoAssignee
+func (s *assignmentService) Pick(ctx context.Context, team string, target models.AssignmentTarget) (string, error) {
+	policy, candidates, err := s.policyCandidates(ctx, team, target.Exclude)
+	if err != nil || policy == nil {
+		return "", err
+	}
+
+	if policy.Strategy == models.AssignmentStrategyRoundRobin {
+		counter, err := s.repoManager.AssignmentPolicy().NextRoundRobin(ctx, policy.ID)
+		if err != nil {
+			return "", err
+		}
+		return candidates[int((counter-1)%int64(len(candidates)))].UserID, nil
+	}
+	return s.pickByLoad(ctx, policy, candidates, target)
+}
+
+// Preview 预览按团队的分派策略会选择的处理人，用于告警回放等试运行场景，不推进轮询计数。
+// 轮询策略返回全部候选成员，团队没有策略或策略已停用时返回 nil
+func (s *assignmentService) Preview(ctx context.Context, team string, target models.AssignmentTarget) (*models.AssignmentPreview, error) {
+	policy, candidates, err := s.policyCandidates(ctx, team, target.Exclude)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	preview := &models.AssignmentPreview{PolicyID: policy.ID, Team: policy.Team, Strategy: policy.Strategy}
+	if policy.Strategy == models.AssignmentStrategyRoundRobin {
+		for _, member := range candidates {
+			preview.UserIDs = append(preview.UserIDs, member.UserID)
+		}
+		return preview, nil
+	}
+	userID, err := s.pickByLoad(ctx, policy, candidates, target)
+	if err != nil {
+		return nil, err
+	}
+	preview.UserIDs = []string{userID}
+	return preview, nil
+}
+
+// policyCandidates 获取团队启用中的分派策略和可分派的成员，团队没有策略或策略已停用时返回 nil
+func (s *assignmentService) policyCandidates(ctx context.Context, team, exclude string) (*models.AssignmentPolicy, []models.AssignmentMember, error) {
+	if team == "" {
+		return nil, nil, nil
+	}
+
+	policy, err := s.repoManager.AssignmentPolicy().GetByTeam(ctx, team)
+	if err != nil {
+		if errors.Is(err, models.ErrAssignmentPolicyNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if !policy.Enabled {
+		return nil, nil, nil
+	}
+
+	candidates, err := s.candidates(ctx, policy, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil, models.ErrNoAssignee
+	}
+	return policy, candidates, nil
+}
+
+// pickByLoad 按成员的未关闭工单数选择处理人，技能匹配策略优先选择技能匹配数多的成员
+func (s *assignmentService) pickByLoad(ctx context.Context, policy *models.AssignmentPolicy, candidates []models.AssignmentMember, target models.AssignmentTarget) (string, error) {
+	userIDs := make([]string, 0, len(candidates))
+	for _, member := range candidates {
+		userIDs = append(userIDs, member.UserID)
+	}
+	loads, err := s.repoManager.AssignmentPolicy().OpenTicketCounts(ctx, userIDs)
+	if err != nil {
+		return "", err
+	}
+	return selectAssignee(policy.Strategy, candidates, loads, target), nil
+}
+
+// candidates 返回策略中可分派的成员，跳过 exclude、不存在和非激活状态的用户
+func (s *assignmentService) candidates(ctx context.Context, policy *models.AssignmentPolicy, exclude string) ([]models.AssignmentMember, error) {
+	candidates := make([]models.AssignmentMember, 0, len(policy.Members))
+	for _, member := range policy.Members {
+		if member.UserID == exclude {
+			continue
+		}
+		user, err := s.repoManager.User().GetByID(ctx, member.UserID)
+		if err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				s.logger.Warn("分派策略成员不存在", zap.String("team", policy.Team), zap.String("user_id", member.UserID))
+				continue
+			}
+			return nil, fmt.Errorf("获取分派策略成员失败: %w", err)
+		}
+		if user.Status != models.UserStatusActive {
+			continue
+		}
+		candidates = append(candidates, member)
+	}
+	return candidates, nil
+}
+
+// selectAssignee 按负载选择处理人，技能匹配策略优先选择技能匹配数多的成员；
+// 都不匹配时退化为负载最少者优先，负载相同时按成员顺序
+func selectAssignee(strategy models.AssignmentStrategy, candidates []models.AssignmentMember, loads map[string]int, target models.AssignmentTarget) string {
+	best, bestScore, bestLoad := -1, 0, 0
+	for i := range candidates {
+		score := 0
+		if strategy == models.AssignmentStrategySkillMatch {
+			score = candidates[i].SkillScore(target.Tags, target.Labels)
+		}
+		load := loads[candidates[i].UserID]
+		if best < 0 || score > bestScore || (score == bestScore && load < bestLoad) {
+			best, bestScore, bestLoad = i, score, load
+		}
+	}
+	return candidates[best].UserID
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAssignmentPolicyRepository 内存中的分派策略和未关闭工单数
+type fakeAssignmentPolicyRepository struct {
+	repository.AssignmentPolicyRepository
+	policies []*models.AssignmentPolicy
+	counters map[string]int64
+	loads    map[string]int
+}
+
+func (r *fakeAssignmentPolicyRepository) GetByTeam(ctx context.Context, team string) (*models.AssignmentPolicy, error) {
+	for _, policy := range r.policies {
+		if policy.Team == team {
+			return policy, nil
+		}
+	}
+	return nil, models.ErrAssignmentPolicyNotFound
+}
+
+func (r *fakeAssignmentPolicyRepository) NextRoundRobin(ctx context.Context, id string) (int64, error) {
+	r.counters[id]++
+	return r.counters[id], nil
+}
+
+func (r *fakeAssignmentPolicyRepository) OpenTicketCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return r.loads, nil
+}
+
+type fakeAssignmentTicketRepository struct {
+	repository.TicketRepository
+	ticket *models.Ticket
+}
+
+func (r *fakeAssignmentTicketRepository) GetByID(ctx context.Context, id string) (*models.Ticket, error) {
+	if r.ticket.ID != id {
+		return nil, models.ErrTicketNotFound
+	}
+	return r.ticket, nil
+}
+
+func (r *fakeAssignmentTicketRepository) Assign(ctx context.Context, id string, assigneeID string) error {
+	r.ticket.AssigneeID = &assigneeID
+	return nil
+}
+
+// fakeServiceAttributor 返回固定服务并记录匹配的标签
+type fakeServiceAttributor struct {
+	service *models.CatalogService
+	labels  map[string]string
+}
+
+func (a *fakeServiceAttributor) Attribute(ctx context.Context, labels map[string]string) (*models.CatalogService, error) {
+	a.labels = labels
+	return a.service, nil
+}
+
+type assignmentRepoManager struct {
+	*MockRepositoryManager
+	policies *fakeAssignmentPolicyRepository
+	users    *fakeChatOpsUserRepository
+	tickets  *fakeAssignmentTicketRepository
+}
+
+func (m *assignmentRepoManager) AssignmentPolicy() repository.AssignmentPolicyRepository {
+	return m.policies
+}
+
+func (m *assignmentRepoManager) User() repository.UserRepository { return m.users }
+
+func (m *assignmentRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func newAssignmentRepoManager(policies ...*models.AssignmentPolicy) *assignmentRepoManager {
+	return &assignmentRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		policies: &fakeAssignmentPolicyRepository{
+			policies: policies,
+			counters: map[string]int64{},
+			loads:    map[string]int{"u1": 3, "u2": 1, "u3": 1},
+		},
+		users: &fakeChatOpsUserRepository{users: []*models.User{
+			{ID: "u1", Status: models.UserStatusActive},
+			{ID: "u2", Status: models.UserStatusActive},
+			{ID: "u3", Status: models.UserStatusActive},
+			{ID: "u4", Status: models.UserStatusDisabled},
+		}},
+	}
+}
+
+func TestAssignmentService_Pick(t *testing.T) {
+	ctx := context.Background()
+	members := []models.AssignmentMember{
+		{UserID: "u1", Skills: []string{"mysql", "service=payment"}},
+		{UserID: "u4", Skills: []string{"mysql"}},
+		{UserID: "u2"},
+		{UserID: "missing"},
+		{UserID: "u3", Skills: []string{"redis"}},
+	}
+	repoManager := newAssignmentRepoManager(
+		&models.AssignmentPolicy{ID: "rr", Team: "sre", Strategy: models.AssignmentStrategyRoundRobin, Members: members, Enabled: true},
+		&models.AssignmentPolicy{ID: "load", Team: "dba", Strategy: models.AssignmentStrategyLeastLoad, Members: members, Enabled: true},
+		&models.AssignmentPolicy{ID: "skill", Team: "ops", Strategy: models.AssignmentStrategySkillMatch, Members: members, Enabled: true},
+		&models.AssignmentPolicy{ID: "off", Team: "web", Strategy: models.AssignmentStrategyRoundRobin, Members: members},
+	)
+	svc := NewAssignmentService(repoManager, zap.NewNop())
+
+	// 轮询跳过已禁用和不存在的用户
+	var picked []string
+	for i := 0; i < 4; i++ {
+		userID, err := svc.Pick(ctx, "sre", models.AssignmentTarget{})
+		require.NoError(t, err)
+		picked = append(picked, userID)
+	}
+	assert.Equal(t, []string{"u1", "u2", "u3", "u1"}, picked)
+
+	// 负载最少者优先，相同时按成员顺序；排除当前处理人
+	userID, err := svc.Pick(ctx, "dba", models.AssignmentTarget{})
+	require.NoError(t, err)
+	assert.Equal(t, "u2", userID)
+	userID, err = svc.Pick(ctx, "dba", models.AssignmentTarget{Exclude: "u2"})
+	require.NoError(t, err)
+	assert.Equal(t, "u3", userID)
+
+	// 技能匹配优先于负载，匹配 key=value 标签；都不匹配时按负载
+	userID, err = svc.Pick(ctx, "ops", models.AssignmentTarget{Labels: map[string]string{"service": "payment"}})
+	require.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+	userID, err = svc.Pick(ctx, "ops", models.AssignmentTarget{Tags: []string{"Redis"}})
+	require.NoError(t, err)
+	assert.Equal(t, "u3", userID)
+	userID, err = svc.Pick(ctx, "ops", models.AssignmentTarget{Tags: []string{"kafka"}})
+	require.NoError(t, err)
+	assert.Equal(t, "u2", userID)
+
+	// 没有策略或策略已停用时不分派
+	for _, team := range []string{"web", "unknown", ""} {
+		userID, err = svc.Pick(ctx, team, models.AssignmentTarget{})
+		require.NoError(t, err)
+		assert.Empty(t, userID, team)
+	}
+
+	repoManager.policies.policies = append(repoManager.policies.policies, &models.AssignmentPolicy{
+		ID: "solo", Team: "solo", Strategy: models.AssignmentStrategyLeastLoad, Enabled: true,
+		Members: []models.AssignmentMember{{UserID: "u1"}},
+	})
+	_, err = svc.Pick(ctx, "solo", models.AssignmentTarget{Exclude: "u1"})
+	assert.ErrorIs(t, err, models.ErrNoAssignee)
+}
+
+func TestTicketService_AutoAssign(t *testing.T) {
+	ctx := context.Background()
+	current := "u2"
+	repoManager := newAssignmentRepoManager(&models.AssignmentPolicy{
+		ID: "load", Team: "payments", Strategy: models.AssignmentStrategyLeastLoad, Enabled: true,
+		Members: []models.AssignmentMember{{UserID: "u1"}, {UserID: "u2"}},
+	})
+	repoManager.tickets = &fakeAssignmentTicketRepository{ticket: &models.Ticket{
+		ID: "t1", AssigneeID: &current, Tags: []string{models.ServiceTicketTagPrefix + "checkout"},
+	}}
+	catalog := &fakeServiceAttributor{service: &models.CatalogService{Name: "checkout", OwnerTeam: "payments"}}
+	svc := NewTicketService(repoManager, catalog, NewAssignmentService(repoManager, zap.NewNop()), nil, nil, nil, nil, zap.NewNop())
+
+	// 未指定团队时使用服务的负责团队，升级时分派给当前处理人以外的成员
+	ticket, err := svc.AutoAssign(ctx, "t1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", *ticket.AssigneeID)
+	assert.Equal(t, "payments", *ticket.TeamName)
+	assert.Equal(t, "checkout", catalog.labels[models.ServiceLabel])
+
+	_, err = svc.AutoAssign(ctx, "t1", "unknown")
+	assert.ErrorIs(t, err, models.ErrNoAssignee)
+
+	repoManager.tickets.ticket.Tags = nil
+	_, err = svc.AutoAssign(ctx, "t1", "")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
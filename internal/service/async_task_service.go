@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// asyncTaskDataSourcePageSize 批量检查全部数据源时每页获取的数据源数
+const asyncTaskDataSourcePageSize = 100
+
+// asyncTaskProgress 上报任务进度，total 为 0 表示总数未知
+type asyncTaskProgress func(total, processed int64)
+
+// asyncTaskExecutor 执行异步任务，返回结果摘要和结果文件，执行失败时同样保存已产生的结果
+type asyncTaskExecutor func(ctx context.Context, task *models.AsyncTask, progress asyncTaskProgress) (interface{}, *models.AsyncTaskFile, error)
+
+// asyncTaskService 异步任务服务实现
+type asyncTaskService struct {
+	repoManager repository.RepositoryManager
+	executors   map[models.AsyncTaskType]asyncTaskExecutor
+	cfg         config.AsyncTaskConfig
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewAsyncTaskService 创建异步任务服务实例，数据源检查通过 dataSources 执行，知识库导出按提交人的访问范围导出
+func NewAsyncTaskService(repoManager repository.RepositoryManager, dataSources DataSourceService, knowledgeTransfer KnowledgeTransferService,
+	knowledgeACL KnowledgeACLService, cfg config.AsyncTaskConfig, logger *zap.Logger) AsyncTaskService {
+	s := &asyncTaskService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+	}
+	s.executors = map[models.AsyncTaskType]asyncTaskExecutor{
+		models.AsyncTaskKnowledgeExport: func(ctx context.Context, task *models.AsyncTask, progress asyncTaskProgress) (interface{}, *models.AsyncTaskFile, error) {
+			var p models.KnowledgeExportParams
+			if err := decodeAsyncTaskParams(task.Params, &p); err != nil {
+				return nil, nil, err
+			}
+			var buf bytes.Buffer
+			count, err := knowledgeTransfer.Export(ctx, p.CategoryIDs, knowledgeACL.ViewerScope(ctx, task.CreatedBy), &buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			progress(int64(count), int64(count))
+			return map[string]interface{}{"count": count}, &models.AsyncTaskFile{
+				Name:        fmt.Sprintf("knowledge-export-%s.zip", s.now().Format("20060102150405")),
+				ContentType: "application/zip",
+				Data:        buf.Bytes(),
+			}, nil
+		},
+		models.AsyncTaskDataSourceHealthCheck: func(ctx context.Context, task *models.AsyncTask, progress asyncTaskProgress) (interface{}, *models.AsyncTaskFile, error) {
+			var p models.DataSourceHealthCheckParams
+			if err := decodeAsyncTaskParams(task.Params, &p); err != nil {
+				return nil, nil, err
+			}
+			result, err := s.checkDataSources(ctx, dataSources, p.IDs, progress)
+			return result, nil, err
+		},
+	}
+	return s
+}
+
+// Interval 检查待执行任务的周期
+func (s *asyncTaskService) Interval() time.Duration {
+	return s.cfg.CheckInterval
+}
+
+// Submit 提交异步任务，任务由 Worker 在后台执行
+func (s *asyncTaskService) Submit(ctx context.Context, req *models.AsyncTaskRequest, userID string) (*models.AsyncTask, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := &models.AsyncTask{
+		Type:      req.Type,
+		Params:    req.Params,
+		CreatedBy: userID,
+	}
+	if err := s.repoManager.AsyncTask().Create(ctx, task); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("提交异步任务", zap.String("task_id", task.ID), zap.String("type", string(task.Type)), zap.String("created_by", userID))
+	return task, nil
+}
+
+// Get 获取用户提交的异步任务及其进度，其他用户的任务按不存在处理
+func (s *asyncTaskService) Get(ctx context.Context, id, userID string) (*models.AsyncTask, error) {
+	task, err := s.repoManager.AsyncTask().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.CreatedBy != userID {
+		return nil, models.ErrAsyncTaskNotFound
+	}
+	return task, nil
+}
+
+// List 获取用户最近提交的异步任务
+func (s *asyncTaskService) List(ctx context.Context, userID string) ([]*models.AsyncTask, error) {
+	tasks, err := s.repoManager.AsyncTask().ListByCreator(ctx, userID, models.AsyncTaskDefaultListLimit)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []*models.AsyncTask{}
+	}
+	return tasks, nil
+}
+
+// ResultFile 获取用户提交的已完成任务的结果文件
+func (s *asyncTaskService) ResultFile(ctx context.Context, id, userID string) (*models.AsyncTaskFile, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.repoManager.AsyncTask().GetResultFile(ctx, id)
+}
+
+// RunPending 依次执行所有待执行的任务，返回执行的任务数量。单个任务失败时记录到任务中并继续执行下一个
+func (s *asyncTaskService) RunPending(ctx context.Context) (int, error) {
+	ran := 0
+	for ctx.Err() == nil {
+		task, err := s.repoManager.AsyncTask().ClaimPending(ctx)
+		if err != nil {
+			return ran, err
+		}
+		if task == nil {
+			break
+		}
+
+		s.runTask(ctx, task)
+		ran++
+	}
+	return ran, nil
+}
+
+// Cleanup 将执行超时的任务标记为失败，并删除结束超过保留时间的任务，返回删除的任务数量
+func (s *asyncTaskService) Cleanup(ctx context.Context) (int64, error) {
+	now := s.now()
+	if s.cfg.Timeout > 0 {
+		failed, err := s.repoManager.AsyncTask().FailStale(ctx, now.Add(-s.cfg.Timeout), "任务执行超时")
+		if err != nil {
+			return 0, err
+		}
+		if failed > 0 {
+			s.logger.Warn("异步任务执行超时", zap.Int64("count", failed))
+		}
+	}
+	return s.repoManager.AsyncTask().DeleteFinished(ctx, now.Add(-s.cfg.Retention))
+}
+
+// runTask 执行任务并保存结果。执行期间上报的进度写入任务，保存结果不受服务停止的影响
+func (s *asyncTaskService) runTask(ctx context.Context, task *models.AsyncTask) {
+	outcome := &models.AsyncTaskOutcome{Status: models.AsyncTaskStatusSucceeded}
+	progress := func(total, processed int64) {
+		outcome.Processed = processed
+		if err := s.repoManager.AsyncTask().UpdateProgress(ctx, task.ID, total, processed); err != nil {
+			s.logger.Warn("更新异步任务进度失败", zap.Error(err), zap.String("task_id", task.ID))
+		}
+	}
+
+	var result interface{}
+	var file *models.AsyncTaskFile
+	err := fmt.Errorf("不支持的任务类型 %s", task.Type)
+	if executor, ok := s.executors[task.Type]; ok {
+		runCtx, cancel := context.WithCancel(ctx)
+		if s.cfg.Timeout > 0 {
+			runCtx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		}
+		result, file, err = executor(runCtx, task, progress)
+		cancel()
+	}
+	if err == nil && file != nil && s.cfg.MaxResultSize > 0 && int64(len(file.Data)) > s.cfg.MaxResultSize {
+		err = fmt.Errorf("结果文件大小 %d 字节超过上限 %d 字节", len(file.Data), s.cfg.MaxResultSize)
+	}
+	if err != nil {
+		outcome.Status = models.AsyncTaskStatusFailed
+		outcome.Error = err.Error()
+		file = nil
+	}
+	if result != nil {
+		data, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			s.logger.Error("序列化异步任务结果失败", zap.Error(marshalErr), zap.String("task_id", task.ID))
+		}
+		outcome.Result = data
+	}
+	outcome.File = file
+
+	if err := s.repoManager.AsyncTask().Finish(context.WithoutCancel(ctx), task.ID, outcome); err != nil {
+		s.logger.Error("保存异步任务结果失败", zap.Error(err), zap.String("task_id", task.ID))
+		return
+	}
+	if outcome.Status == models.AsyncTaskStatusFailed {
+		s.logger.Error("异步任务执行失败", zap.String("task_id", task.ID), zap.String("type", string(task.Type)), zap.String("error", outcome.Error))
+		return
+	}
+	s.logger.Info("异步任务执行完成", zap.String("task_id", task.ID), zap.String("type", string(task.Type)))
+}
+
+// checkDataSources 逐个检查数据源连接，ids 为空时检查全部启用的数据源。单个数据源检查失败记录到结果中
+func (s *asyncTaskService) checkDataSources(ctx context.Context, dataSources DataSourceService, ids []string, progress asyncTaskProgress) (interface{}, error) {
+	var targets []*models.DataSource
+	if len(ids) == 0 {
+		status := models.DataSourceStatusActive
+		for page := 1; ; page++ {
+			list, total, err := dataSources.List(ctx, &models.DataSourceFilter{Status: &status, Page: page, PageSize: asyncTaskDataSourcePageSize})
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, list...)
+			if len(list) < asyncTaskDataSourcePageSize || int64(len(targets)) >= total {
+				break
+			}
+		}
+	} else {
+		for _, id := range ids {
+			targets = append(targets, &models.DataSource{ID: id})
+		}
+	}
+
+	results := make([]*models.DataSourceHealthCheckResult, 0, len(targets))
+	healthy := 0
+	progress(int64(len(targets)), 0)
+	for i, dataSource := range targets {
+		if err := ctx.Err(); err != nil {
+			return map[string]interface{}{"healthy": healthy, "checked": len(results), "results": results}, err
+		}
+		result := &models.DataSourceHealthCheckResult{ID: dataSource.ID, Name: dataSource.Name, Healthy: true}
+		if result.Name == "" {
+			if found, err := dataSources.GetByID(ctx, dataSource.ID); err == nil && found != nil {
+				result.Name = found.Name
+			}
+		}
+		if err := dataSources.TestConnection(ctx, dataSource.ID); err != nil {
+			result.Healthy = false
+			result.Error = err.Error()
+		} else {
+			healthy++
+		}
+		results = append(results, result)
+		progress(int64(len(targets)), int64(i+1))
+	}
+
+	return map[string]interface{}{"healthy": healthy, "checked": len(results), "results": results}, nil
+}
+
+// decodeAsyncTaskParams 解析已校验的任务参数，参数为空时保持零值
+func decodeAsyncTaskParams(data json.RawMessage, dest interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAsyncTaskRepository 按提交顺序保存任务，记录每次上报的进度和执行结果
+type fakeAsyncTaskRepository struct {
+	tasks    []*models.AsyncTask
+	progress [][2]int64
+	outcomes map[string]*models.AsyncTaskOutcome
+}
+
+func (r *fakeAsyncTaskRepository) Create(ctx context.Context, task *models.AsyncTask) error {
+	task.ID = "task-" + string(rune('a'+len(r.tasks)))
+	task.Status = models.AsyncTaskStatusPending
+	r.tasks = append(r.tasks, task)
+	return nil
+}
+
+func (r *fakeAsyncTaskRepository) GetByID(ctx context.Context, id string) (*models.AsyncTask, error) {
+	for _, task := range r.tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return nil, models.ErrAsyncTaskNotFound
+}
+
+func (r *fakeAsyncTaskRepository) ListByCreator(ctx context.Context, createdBy string, limit int) ([]*models.AsyncTask, error) {
+	return nil, nil
+}
+
+func (r *fakeAsyncTaskRepository) GetResultFile(ctx context.Context, id string) (*models.AsyncTaskFile, error) {
+	if outcome := r.outcomes[id]; outcome != nil && outcome.File != nil {
+		return outcome.File, nil
+	}
+	return nil, models.ErrAsyncTaskResultNotReady
+}
+
+func (r *fakeAsyncTaskRepository) ClaimPending(ctx context.Context) (*models.AsyncTask, error) {
+	for _, task := range r.tasks {
+		if task.Status == models.AsyncTaskStatusPending {
+			task.Status = models.AsyncTaskStatusRunning
+			return task, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAsyncTaskRepository) UpdateProgress(ctx context.Context, id string, total, processed int64) error {
+	r.progress = append(r.progress, [2]int64{total, processed})
+	return nil
+}
+
+func (r *fakeAsyncTaskRepository) Finish(ctx context.Context, id string, outcome *models.AsyncTaskOutcome) error {
+	task, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	task.Status, task.Result, task.Error = outcome.Status, outcome.Result, outcome.Error
+	r.outcomes[id] = outcome
+	return nil
+}
+
+func (r *fakeAsyncTaskRepository) FailStale(ctx context.Context, startedBefore time.Time, reason string) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeAsyncTaskRepository) DeleteFinished(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+type asyncTaskRepoManager struct {
+	*MockRepositoryManager
+	tasks *fakeAsyncTaskRepository
+}
+
+func (m *asyncTaskRepoManager) AsyncTask() repository.AsyncTaskRepository { return m.tasks }
+
+// fakeAsyncTaskDataSourceService down 中的数据源连接检查失败
+type fakeAsyncTaskDataSourceService struct {
+	DataSourceService
+	sources []*models.DataSource
+	down    map[string]bool
+}
+
+func (s *fakeAsyncTaskDataSourceService) List(ctx context.Context, filter *models.DataSourceFilter) ([]*models.DataSource, int64, error) {
+	return s.sources, int64(len(s.sources)), nil
+}
+
+func (s *fakeAsyncTaskDataSourceService) GetByID(ctx context.Context, id string) (*models.DataSource, error) {
+	for _, source := range s.sources {
+		if source.ID == id {
+			return source, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (s *fakeAsyncTaskDataSourceService) TestConnection(ctx context.Context, id string) error {
+	if s.down[id] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// fakeAsyncTaskKnowledgeTransfer 导出固定内容，记录导出时的访问范围
+type fakeAsyncTaskKnowledgeTransfer struct {
+	KnowledgeTransferService
+	viewer *string
+}
+
+func (s *fakeAsyncTaskKnowledgeTransfer) Export(ctx context.Context, categoryIDs []string, viewerID *string, w io.Writer) (int, error) {
+	s.viewer = viewerID
+	_, err := w.Write([]byte("zip-content"))
+	return 3, err
+}
+
+type fakeAsyncTaskKnowledgeACL struct {
+	KnowledgeACLService
+}
+
+func (s *fakeAsyncTaskKnowledgeACL) ViewerScope(ctx context.Context, userID string) *string {
+	return &userID
+}
+
+func newTestAsyncTaskService(cfg config.AsyncTaskConfig) (*asyncTaskService, *fakeAsyncTaskRepository, *fakeAsyncTaskKnowledgeTransfer) {
+	repo := &fakeAsyncTaskRepository{outcomes: make(map[string]*models.AsyncTaskOutcome)}
+	repoManager := &asyncTaskRepoManager{MockRepositoryManager: &MockRepositoryManager{}, tasks: repo}
+	dataSources := &fakeAsyncTaskDataSourceService{
+		sources: []*models.DataSource{{ID: "prom", Name: "Prometheus"}, {ID: "loki", Name: "Loki"}},
+		down:    map[string]bool{"loki": true},
+	}
+	knowledge := &fakeAsyncTaskKnowledgeTransfer{}
+	svc := NewAsyncTaskService(repoManager, dataSources, knowledge, &fakeAsyncTaskKnowledgeACL{}, cfg, zap.NewNop()).(*asyncTaskService)
+	return svc, repo, knowledge
+}
+
+func TestAsyncTaskService_RunsTasksAndStoresResults(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, knowledge := newTestAsyncTaskService(config.AsyncTaskConfig{Timeout: time.Minute, MaxResultSize: 1024})
+
+	export, err := svc.Submit(ctx, &models.AsyncTaskRequest{Type: models.AsyncTaskKnowledgeExport}, "alice")
+	require.NoError(t, err)
+	check, err := svc.Submit(ctx, &models.AsyncTaskRequest{
+		Type: models.AsyncTaskDataSourceHealthCheck, Params: json.RawMessage(`{"ids": ["prom", "loki", "prom"]}`),
+	}, "alice")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ids": ["prom", "loki"]}`, string(check.Params))
+
+	_, err = svc.Submit(ctx, &models.AsyncTaskRequest{Type: "tickets.export"}, "alice")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	ran, err := svc.RunPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ran)
+
+	// 知识库按提交人的访问范围导出，结果文件可下载
+	require.NotNil(t, knowledge.viewer)
+	assert.Equal(t, "alice", *knowledge.viewer)
+	assert.Equal(t, models.AsyncTaskStatusSucceeded, export.Status)
+	assert.JSONEq(t, `{"count": 3}`, string(export.Result))
+	file, err := svc.ResultFile(ctx, export.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "application/zip", file.ContentType)
+	assert.Equal(t, []byte("zip-content"), file.Data)
+
+	// 数据源逐个检查并上报进度，单个数据源失败不影响任务结果
+	assert.Equal(t, models.AsyncTaskStatusSucceeded, check.Status)
+	assert.Equal(t, [][2]int64{{3, 3}, {2, 0}, {2, 1}, {2, 2}}, repo.progress)
+	var result struct {
+		Healthy int                                   `json:"healthy"`
+		Results []*models.DataSourceHealthCheckResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(check.Result, &result))
+	assert.Equal(t, 1, result.Healthy)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "Loki", result.Results[1].Name)
+	assert.False(t, result.Results[1].Healthy)
+	_, err = svc.ResultFile(ctx, check.ID, "alice")
+	assert.ErrorIs(t, err, models.ErrAsyncTaskResultNotReady)
+
+	// 其他用户看不到任务
+	_, err = svc.Get(ctx, export.ID, "bob")
+	assert.ErrorIs(t, err, models.ErrAsyncTaskNotFound)
+	_, err = svc.ResultFile(ctx, export.ID, "bob")
+	assert.ErrorIs(t, err, models.ErrAsyncTaskNotFound)
+}
+
+func TestAsyncTaskService_FailsOversizedResult(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestAsyncTaskService(config.AsyncTaskConfig{MaxResultSize: 4})
+
+	task, err := svc.Submit(ctx, &models.AsyncTaskRequest{Type: models.AsyncTaskKnowledgeExport}, "alice")
+	require.NoError(t, err)
+	_, err = svc.RunPending(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.AsyncTaskStatusFailed, task.Status)
+	assert.Contains(t, task.Error, "超过上限")
+	assert.Nil(t, repo.outcomes[task.ID].File, "失败的任务不保存结果文件")
+	assert.True(t, task.Status.IsFinished())
+}
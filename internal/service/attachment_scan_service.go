@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+	"pulse/internal/scanner"
+)
+
+// errAttachmentUnreadable 附件文件无法读取，重试也无法扫描
+var errAttachmentUnreadable = errors.New("附件无法读取")
+
+// attachmentScanService 附件安全扫描服务实现
+type attachmentScanService struct {
+	repoManager repository.RepositoryManager
+	scanner     scanner.Scanner
+	cfg         config.AttachmentScanConfig
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewAttachmentScanService 创建附件安全扫描服务实例
+// scanner 为 nil 时视为未启用扫描
+func NewAttachmentScanService(repoManager repository.RepositoryManager, s scanner.Scanner, cfg config.AttachmentScanConfig, logger *zap.Logger) AttachmentScanService {
+	return &attachmentScanService{
+		repoManager: repoManager,
+		scanner:     s,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Enabled 是否启用附件扫描
+func (s *attachmentScanService) Enabled() bool {
+	return s.cfg.Enabled && s.scanner != nil
+}
+
+// ScanPending 扫描一批待扫描附件，返回处理数量。
+// 扫描中超过扫描超时时间的附件视为扫描进程已崩溃，重新抢占扫描
+func (s *attachmentScanService) ScanPending(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 20
+	}
+
+	now := s.now()
+	staleBefore := now.Add(-s.scanTimeout())
+	attachments, err := s.repoManager.Ticket().ListScannableAttachments(ctx, now, staleBefore, batchSize)
+	if err != nil {
+		s.logger.Error("获取待扫描附件失败", zap.Error(err))
+		return 0, fmt.Errorf("获取待扫描附件失败: %w", err)
+	}
+
+	scanned := 0
+	for _, attachment := range attachments {
+		if ctx.Err() != nil {
+			return scanned, ctx.Err()
+		}
+
+		// 抢占附件，避免多个实例重复扫描
+		claimed, err := s.repoManager.Ticket().ClaimAttachmentScan(ctx, attachment.ID, now, staleBefore)
+		if err != nil {
+			s.logger.Error("标记附件扫描状态失败", zap.Error(err), zap.String("id", attachment.ID))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		attachment.ScanAttempts++
+
+		if err := s.ScanAttachment(ctx, attachment); err != nil {
+			s.logger.Error("扫描附件失败", zap.Error(err), zap.String("id", attachment.ID))
+			continue
+		}
+		scanned++
+	}
+
+	return scanned, nil
+}
+
+// ScanAttachment 扫描单个附件并记录结果，感染文件移入隔离区。
+// 扫描器出错（超时、服务不可用等）时附件保持待扫描并按退避时间重试，
+// 只有文件无法读取或达到最多扫描次数时才标记为扫描失败
+func (s *attachmentScanService) ScanAttachment(ctx context.Context, attachment *models.TicketAttachment) error {
+	if !s.Enabled() {
+		return fmt.Errorf("附件扫描未启用")
+	}
+
+	result, err := s.scanFile(ctx, attachment.FilePath)
+	if err != nil {
+		return s.scanFailed(ctx, attachment, err)
+	}
+
+	if !result.Infected {
+		if err := s.repoManager.Ticket().UpdateAttachmentScanResult(ctx, attachment.ID,
+			models.AttachmentScanStatusClean, nil, attachment.FilePath); err != nil {
+			return fmt.Errorf("更新附件扫描结果失败: %w", err)
+		}
+		attachment.ScanStatus = models.AttachmentScanStatusClean
+		return nil
+	}
+
+	// 感染文件移入隔离区
+	quarantinePath, err := s.quarantine(attachment)
+	if err != nil {
+		s.logger.Error("隔离感染附件失败", zap.Error(err), zap.String("id", attachment.ID))
+		quarantinePath = attachment.FilePath
+	}
+
+	signature := result.Signature
+	if err := s.repoManager.Ticket().UpdateAttachmentScanResult(ctx, attachment.ID,
+		models.AttachmentScanStatusInfected, &signature, quarantinePath); err != nil {
+		return fmt.Errorf("更新附件扫描结果失败: %w", err)
+	}
+
+	attachment.ScanStatus = models.AttachmentScanStatusInfected
+	attachment.ScanResult = &signature
+	attachment.FilePath = quarantinePath
+
+	s.logger.Warn("附件检测到病毒，已隔离",
+		zap.String("id", attachment.ID),
+		zap.String("ticket_id", attachment.TicketID),
+		zap.String("signature", signature),
+		zap.String("scanner", s.scanner.Name()),
+	)
+	return nil
+}
+
+// GetDownloadableAttachment 获取工单下允许下载的附件，附件不属于该工单时返回 ErrAttachmentNotFound，
+// 不透露其他工单附件是否存在及其扫描状态
+func (s *attachmentScanService) GetDownloadableAttachment(ctx context.Context, ticketID, id string) (*models.TicketAttachment, error) {
+	if ticketID == "" || id == "" {
+		return nil, fmt.Errorf("工单ID和附件ID不能为空")
+	}
+
+	attachment, err := s.repoManager.Ticket().GetAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.TicketID != ticketID {
+		return nil, models.ErrAttachmentNotFound
+	}
+
+	if !attachment.IsDownloadable(s.Enabled()) {
+		return attachment, models.ErrAttachmentNotClean
+	}
+
+	return attachment, nil
+}
+
+// scanFailed 记录扫描出错，可重试时恢复为待扫描，返回扫描错误
+func (s *attachmentScanService) scanFailed(ctx context.Context, attachment *models.TicketAttachment, scanErr error) error {
+	message := scanErr.Error()
+	attempts := max(attachment.ScanAttempts, 1)
+	if errors.Is(scanErr, errAttachmentUnreadable) || attempts >= max(s.cfg.MaxAttempts, 1) {
+		if err := s.repoManager.Ticket().UpdateAttachmentScanResult(ctx, attachment.ID,
+			models.AttachmentScanStatusError, &message, attachment.FilePath); err != nil {
+			return fmt.Errorf("更新附件扫描结果失败: %w", err)
+		}
+		attachment.ScanStatus = models.AttachmentScanStatusError
+		attachment.ScanResult = &message
+		return scanErr
+	}
+
+	retryAt := s.now().Add(s.backoff(attempts))
+	if err := s.repoManager.Ticket().RetryAttachmentScan(ctx, attachment.ID, message, retryAt); err != nil {
+		return fmt.Errorf("更新附件扫描重试时间失败: %w", err)
+	}
+	attachment.ScanStatus = models.AttachmentScanStatusPending
+	attachment.ScanResult = &message
+	attachment.ScanRetryAt = &retryAt
+	return scanErr
+}
+
+// backoff 第 attempts 次扫描出错后的重试等待时间，按指数增长，不超过上限
+func (s *attachmentScanService) backoff(attempts int) time.Duration {
+	wait := s.cfg.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= s.cfg.MaxRetryBackoff {
+			return s.cfg.MaxRetryBackoff
+		}
+	}
+	return min(wait, s.cfg.MaxRetryBackoff)
+}
+
+// scanTimeout 单个附件的扫描超时时间，抢占超过该时间仍在扫描中的附件可被重新抢占
+func (s *attachmentScanService) scanTimeout() time.Duration {
+	if s.cfg.Timeout > 0 {
+		return s.cfg.Timeout
+	}
+	return 60 * time.Second
+}
+
+// scanFile 打开文件并调用扫描器
+func (s *attachmentScanService) scanFile(ctx context.Context, path string) (*scanner.Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errAttachmentUnreadable, err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, s.scanTimeout())
+	defer cancel()
+
+	return s.scanner.Scan(ctx, file)
+}
+
+// quarantine 将附件移动到隔离目录
+func (s *attachmentScanService) quarantine(attachment *models.TicketAttachment) (string, error) {
+	if err := os.MkdirAll(s.cfg.QuarantinePath, 0700); err != nil {
+		return "", fmt.Errorf("创建隔离目录失败: %w", err)
+	}
+
+	dst := filepath.Join(s.cfg.QuarantinePath, attachment.ID+"_"+filepath.Base(attachment.FilePath))
+	if err := os.Rename(attachment.FilePath, dst); err == nil {
+		_ = os.Chmod(dst, 0400)
+		return dst, nil
+	}
+
+	// 跨设备时无法直接重命名，改为复制后删除
+	if err := copyFile(attachment.FilePath, dst); err != nil {
+		return "", err
+	}
+	if err := os.Remove(attachment.FilePath); err != nil {
+		return "", fmt.Errorf("删除原附件失败: %w", err)
+	}
+
+	return dst, nil
+}
+
+// copyFile 复制文件
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("打开附件失败: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0400)
+	if err != nil {
+		return fmt.Errorf("创建隔离文件失败: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("复制附件失败: %w", err)
+	}
+
+	return out.Close()
+}
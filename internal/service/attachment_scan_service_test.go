@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+	"pulse/internal/scanner"
+)
+
+// fakeScanTicketRepository 内存中的附件扫描状态
+type fakeScanTicketRepository struct {
+	repository.TicketRepository
+	attachments map[string]*models.TicketAttachment
+	claimedAt   map[string]time.Time
+}
+
+func (r *fakeScanTicketRepository) ListScannableAttachments(ctx context.Context, now, staleBefore time.Time, limit int) ([]*models.TicketAttachment, error) {
+	var attachments []*models.TicketAttachment
+	for _, attachment := range r.attachments {
+		if r.scannable(attachment, now, staleBefore) {
+			copied := *attachment
+			attachments = append(attachments, &copied)
+		}
+	}
+	return attachments, nil
+}
+
+func (r *fakeScanTicketRepository) ClaimAttachmentScan(ctx context.Context, id string, now, staleBefore time.Time) (bool, error) {
+	attachment := r.attachments[id]
+	if attachment == nil || !r.scannable(attachment, now, staleBefore) {
+		return false, nil
+	}
+	attachment.ScanStatus = models.AttachmentScanStatusScanning
+	attachment.ScanAttempts++
+	r.claimedAt[id] = now
+	return true, nil
+}
+
+func (r *fakeScanTicketRepository) RetryAttachmentScan(ctx context.Context, id string, scanResult string, retryAt time.Time) error {
+	attachment := r.attachments[id]
+	attachment.ScanStatus = models.AttachmentScanStatusPending
+	attachment.ScanResult = &scanResult
+	attachment.ScanRetryAt = &retryAt
+	delete(r.claimedAt, id)
+	return nil
+}
+
+func (r *fakeScanTicketRepository) UpdateAttachmentScanResult(ctx context.Context, id string, status models.AttachmentScanStatus, scanResult *string, filePath string) error {
+	attachment := r.attachments[id]
+	attachment.ScanStatus = status
+	attachment.ScanResult = scanResult
+	attachment.ScanRetryAt = nil
+	delete(r.claimedAt, id)
+	return nil
+}
+
+func (r *fakeScanTicketRepository) GetAttachment(ctx context.Context, id string) (*models.TicketAttachment, error) {
+	attachment := r.attachments[id]
+	if attachment == nil {
+		return nil, models.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (r *fakeScanTicketRepository) scannable(attachment *models.TicketAttachment, now, staleBefore time.Time) bool {
+	switch attachment.ScanStatus {
+	case models.AttachmentScanStatusPending:
+		return attachment.ScanRetryAt == nil || !attachment.ScanRetryAt.After(now)
+	case models.AttachmentScanStatusScanning:
+		return r.claimedAt[attachment.ID].Before(staleBefore)
+	}
+	return false
+}
+
+type attachmentScanRepoManager struct {
+	*MockRepositoryManager
+	tickets *fakeScanTicketRepository
+}
+
+func (m *attachmentScanRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+// fakeScanner 按顺序返回扫描错误，错误用完后返回扫描通过
+type fakeScanner struct {
+	errs  []error
+	calls int
+}
+
+func (s *fakeScanner) Name() string { return "fake" }
+
+func (s *fakeScanner) Scan(ctx context.Context, r io.Reader) (*scanner.Result, error) {
+	s.calls++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, err
+	}
+	return &scanner.Result{}, nil
+}
+
+func TestAttachmentScanService_RetriesTransientErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("report"), 0600))
+	tickets := &fakeScanTicketRepository{
+		attachments: map[string]*models.TicketAttachment{
+			"a1":      {ID: "a1", FilePath: path, ScanStatus: models.AttachmentScanStatusPending},
+			"missing": {ID: "missing", FilePath: filepath.Join(t.TempDir(), "missing.pdf"), ScanStatus: models.AttachmentScanStatusPending},
+		},
+		claimedAt: map[string]time.Time{},
+	}
+	fake := &fakeScanner{errs: []error{errors.New("connection refused"), errors.New("timeout")}}
+	cfg := config.AttachmentScanConfig{Enabled: true, Timeout: time.Minute, MaxAttempts: 5, RetryBackoff: time.Minute, MaxRetryBackoff: time.Hour}
+	svc := NewAttachmentScanService(&attachmentScanRepoManager{MockRepositoryManager: &MockRepositoryManager{}, tickets: tickets}, fake, cfg, zap.NewNop()).(*attachmentScanService)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 扫描器出错时保持待扫描并按指数退避重试，文件无法读取时直接标记为扫描失败
+	_, err := svc.ScanPending(ctx)
+	require.NoError(t, err)
+	attachment := tickets.attachments["a1"]
+	assert.Equal(t, models.AttachmentScanStatusPending, attachment.ScanStatus)
+	assert.Equal(t, now.Add(time.Minute), *attachment.ScanRetryAt)
+	assert.Equal(t, models.AttachmentScanStatusError, tickets.attachments["missing"].ScanStatus)
+
+	// 未到重试时间不扫描
+	_, err = svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.calls)
+
+	now = now.Add(time.Minute)
+	_, err = svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Minute), *attachment.ScanRetryAt)
+
+	now = now.Add(2 * time.Minute)
+	scanned, err := svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, scanned)
+	assert.Equal(t, models.AttachmentScanStatusClean, attachment.ScanStatus)
+	assert.Equal(t, 3, attachment.ScanAttempts)
+
+	// 达到最多扫描次数后标记为扫描失败
+	attachment.ScanStatus = models.AttachmentScanStatusPending
+	attachment.ScanAttempts = 4
+	fake.errs = []error{errors.New("connection refused")}
+	_, err = svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentScanStatusError, attachment.ScanStatus)
+}
+
+func TestAttachmentScanService_ReclaimsStaleScans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("report"), 0600))
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tickets := &fakeScanTicketRepository{
+		attachments: map[string]*models.TicketAttachment{
+			"a1": {ID: "a1", FilePath: path, ScanStatus: models.AttachmentScanStatusScanning, ScanAttempts: 1},
+		},
+		claimedAt: map[string]time.Time{"a1": now.Add(-30 * time.Second)},
+	}
+	fake := &fakeScanner{}
+	cfg := config.AttachmentScanConfig{Enabled: true, Timeout: time.Minute, MaxAttempts: 5, RetryBackoff: time.Minute, MaxRetryBackoff: time.Hour}
+	svc := NewAttachmentScanService(&attachmentScanRepoManager{MockRepositoryManager: &MockRepositoryManager{}, tickets: tickets}, fake, cfg, zap.NewNop()).(*attachmentScanService)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 抢占未超过扫描超时时间，其他进程可能仍在扫描
+	scanned, err := svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, scanned)
+
+	// 扫描进程崩溃后超时的附件被重新抢占
+	now = now.Add(time.Minute)
+	scanned, err = svc.ScanPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, scanned)
+	assert.Equal(t, models.AttachmentScanStatusClean, tickets.attachments["a1"].ScanStatus)
+	assert.Equal(t, 2, tickets.attachments["a1"].ScanAttempts)
+}
+
+func TestAttachmentScanService_GetDownloadableAttachment(t *testing.T) {
+	tickets := &fakeScanTicketRepository{attachments: map[string]*models.TicketAttachment{
+		"clean":    {ID: "clean", TicketID: "t1", ScanStatus: models.AttachmentScanStatusClean},
+		"infected": {ID: "infected", TicketID: "t1", ScanStatus: models.AttachmentScanStatusInfected},
+	}}
+	cfg := config.AttachmentScanConfig{Enabled: true}
+	svc := NewAttachmentScanService(&attachmentScanRepoManager{MockRepositoryManager: &MockRepositoryManager{}, tickets: tickets}, &fakeScanner{}, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	attachment, err := svc.GetDownloadableAttachment(ctx, "t1", "clean")
+	require.NoError(t, err)
+	assert.Equal(t, "clean", attachment.ID)
+	_, err = svc.GetDownloadableAttachment(ctx, "t1", "infected")
+	assert.ErrorIs(t, err, models.ErrAttachmentNotClean)
+
+	// 通过其他工单的路径访问时按不存在处理，不返回扫描状态
+	attachment, err = svc.GetDownloadableAttachment(ctx, "t2", "infected")
+	assert.ErrorIs(t, err, models.ErrAttachmentNotFound)
+	assert.Nil(t, attachment)
+}
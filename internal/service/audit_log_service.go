@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// auditLogService 审计日志服务实现
+type auditLogService struct {
+	repoManager repository.RepositoryManager
+}
+
+// NewAuditLogService 创建审计日志服务实例
+func NewAuditLogService(repoManager repository.RepositoryManager) AuditLogService {
+	return &auditLogService{repoManager: repoManager}
+}
+
+// Stream 按创建时间倒序逐条读取符合条件的审计日志，用于导出
+func (s *auditLogService) Stream(ctx context.Context, filter *models.AuditLogFilter, fn func(*models.AuditLog) error) error {
+	return s.repoManager.AuditLog().Stream(ctx, filter, fn)
+}
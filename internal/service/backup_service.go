@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/crypto"
+	"pulse/internal/models"
+	"pulse/internal/pkg/backup"
+	"pulse/internal/repository"
+)
+
+// backupService 平台配置备份与恢复服务
+type backupService struct {
+	repoManager repository.RepositoryManager
+	version     string
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewBackupService 创建配置备份服务，version 为当前实例的 Pulse 版本，用于恢复时的兼容性检查
+func NewBackupService(repoManager repository.RepositoryManager, version string, logger *zap.Logger) BackupService {
+	return &backupService{
+		repoManager: repoManager,
+		version:     version,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Export 导出用户、数据源、规则、通知路由、通知模板和团队分派策略，写出签名后的备份包。
+// 用户密码哈希和数据源凭据使用由口令派生的密钥加密，恢复时解密后再由目标实例的密钥重新加密
+func (s *backupService) Export(ctx context.Context, passphrase, userID string, w io.Writer) (*models.BackupManifest, error) {
+	key, err := backup.NewKey(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	secrets, err := backupSecrets(key)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.exportUsers(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	dataSources, err := listAllDataSources(ctx, s.repoManager)
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range dataSources {
+		// 健康状态等运行时字段不备份
+		ds.HealthStatus, ds.LastHealthCheck, ds.ErrorMessage, ds.Metrics, ds.CircuitBreaker = nil, nil, nil, nil, nil
+		if err := sealDataSourceSecrets(secrets, &ds.Config); err != nil {
+			return nil, fmt.Errorf("加密数据源 %s 的凭据失败: %w", ds.Name, err)
+		}
+	}
+	rules, err := listAllRules(ctx, s.repoManager)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := s.repoManager.NotificationRouting().ListCalendars(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知日历列表失败: %w", err)
+	}
+	templates, err := s.repoManager.Notification().GetTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知模板列表失败: %w", err)
+	}
+	policies, err := s.repoManager.AssignmentPolicy().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取分派策略列表失败: %w", err)
+	}
+
+	manifest := &models.BackupManifest{
+		FormatVersion: models.BackupFormatVersion,
+		PulseVersion:  s.version,
+		CreatedAt:     s.now().UTC(),
+		CreatedBy:     userID,
+		Counts: map[string]int{
+			models.BackupKindUser:       len(users),
+			models.BackupKindDataSource: len(dataSources),
+			models.BackupKindRule:       len(rules),
+			models.BackupKindRoute:      len(routes),
+			models.BackupKindTemplate:   len(templates),
+			models.BackupKindAssignment: len(policies),
+		},
+	}
+
+	files := make(map[string][]byte)
+	for name, v := range map[string]interface{}{
+		models.BackupManifestFile:    manifest,
+		models.BackupUsersFile:       users,
+		models.BackupDataSourcesFile: dataSources,
+		models.BackupRulesFile:       rules,
+		models.BackupRoutesFile:      routes,
+		models.BackupTemplatesFile:   templates,
+		models.BackupAssignmentFile:  policies,
+	} {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化 %s 失败: %w", name, err)
+		}
+		files[name] = data
+	}
+	if err := backup.Write(w, key, files); err != nil {
+		return nil, fmt.Errorf("写出备份包失败: %w", err)
+	}
+
+	s.logger.Info("配置备份已导出", zap.String("user_id", userID), zap.Any("counts", manifest.Counts))
+	return manifest, nil
+}
+
+// exportUsers 导出全部用户，列表接口不返回密码哈希，逐个读取详情
+func (s *backupService) exportUsers(ctx context.Context, secrets *crypto.KeyManager) ([]*models.BackupUser, error) {
+	users := []*models.BackupUser{}
+	for page := 1; ; page++ {
+		list, err := s.repoManager.User().List(ctx, &models.UserFilter{Page: page, PageSize: configApplyPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("获取用户列表失败: %w", err)
+		}
+		for _, listed := range list.Users {
+			user, err := s.repoManager.User().GetByID(ctx, listed.ID)
+			if err != nil {
+				return nil, fmt.Errorf("获取用户 %s 失败: %w", listed.Username, err)
+			}
+			hash, err := secrets.Encrypt(user.PasswordHash)
+			if err != nil {
+				return nil, fmt.Errorf("加密用户 %s 的密码失败: %w", user.Username, err)
+			}
+			user.PasswordHash = ""
+			users = append(users, &models.BackupUser{User: *user, PasswordHash: hash})
+		}
+		if len(list.Users) < configApplyPageSize {
+			return users, nil
+		}
+	}
+}
+
+// backupContents 备份包中的资源
+type backupContents struct {
+	users       []*models.BackupUser
+	dataSources []*models.DataSource
+	rules       []*models.Rule
+	routes      []*models.NotificationCalendar
+	templates   []*models.NotificationTemplate
+	policies    []*models.AssignmentPolicy
+}
+
+// Restore 校验备份包签名和版本兼容性后恢复到当前实例，全部资源在同一事务中恢复。
+// 当前实例已有规则、数据源或通知路由时拒绝恢复；用户名或邮箱已存在的用户、同名的通知模板和团队已有的分派策略跳过
+func (s *backupService) Restore(ctx context.Context, data []byte, passphrase, userID string) (*models.BackupRestoreResult, error) {
+	files, key, err := backup.Read(data, passphrase)
+	if err != nil {
+		if errors.Is(err, backup.ErrSignatureMismatch) {
+			return nil, models.ErrBackupSignatureInvalid
+		}
+		return nil, fmt.Errorf("%w: %v", models.ErrBackupInvalid, err)
+	}
+
+	var manifest models.BackupManifest
+	if err := decodeBackupFile(files, models.BackupManifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	if err := manifest.CheckCompatibility(s.version); err != nil {
+		return nil, err
+	}
+
+	var contents backupContents
+	decoded := map[string]interface{}{
+		models.BackupUsersFile:       &contents.users,
+		models.BackupDataSourcesFile: &contents.dataSources,
+		models.BackupRulesFile:       &contents.rules,
+		models.BackupRoutesFile:      &contents.routes,
+		models.BackupTemplatesFile:   &contents.templates,
+	}
+	if manifest.FormatVersion >= models.BackupFormatVersionAssignmentPolicies {
+		decoded[models.BackupAssignmentFile] = &contents.policies
+	}
+	for name, v := range decoded {
+		if err := decodeBackupFile(files, name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	secrets, err := backupSecrets(key)
+	if err != nil {
+		return nil, err
+	}
+	// 先解密全部凭据，避免解密失败时留下部分恢复的数据
+	for _, user := range contents.users {
+		hash, err := secrets.Decrypt(user.PasswordHash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 解密用户 %s 的密码失败", models.ErrBackupInvalid, user.Username)
+		}
+		user.User.PasswordHash = hash
+	}
+	for _, ds := range contents.dataSources {
+		if err := openDataSourceSecrets(secrets, &ds.Config); err != nil {
+			return nil, fmt.Errorf("%w: 解密数据源 %s 的凭据失败", models.ErrBackupInvalid, ds.Name)
+		}
+	}
+
+	result := &models.BackupRestoreResult{
+		Manifest: &manifest,
+		Restored: make(map[string]int),
+		Skipped:  []models.BackupSkippedItem{},
+	}
+	err = s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		if err := checkFreshInstance(ctx, repo); err != nil {
+			return err
+		}
+		return restoreContents(ctx, repo, &contents, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("配置备份已恢复",
+		zap.String("user_id", userID),
+		zap.String("backup_version", manifest.PulseVersion),
+		zap.Time("backup_created_at", manifest.CreatedAt),
+		zap.Any("restored", result.Restored),
+		zap.Int("skipped", len(result.Skipped)))
+	return result, nil
+}
+
+// withTx 在事务中执行
+func (s *backupService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// checkFreshInstance 检查当前实例是否还没有规则、数据源和通知路由
+func checkFreshInstance(ctx context.Context, repo repository.RepositoryManager) error {
+	dataSources, err := repo.DataSource().List(ctx, &models.DataSourceFilter{Page: 1, PageSize: 1})
+	if err != nil {
+		return fmt.Errorf("获取数据源列表失败: %w", err)
+	}
+	rules, err := repo.Rule().List(ctx, &models.RuleFilter{Page: 1, PageSize: 1})
+	if err != nil {
+		return fmt.Errorf("获取规则列表失败: %w", err)
+	}
+	routes, err := repo.NotificationRouting().ListCalendars(ctx)
+	if err != nil {
+		return fmt.Errorf("获取通知日历列表失败: %w", err)
+	}
+	if len(dataSources.DataSources) > 0 || len(rules.Rules) > 0 || len(routes) > 0 {
+		return models.ErrBackupTargetNotEmpty
+	}
+	return nil
+}
+
+// restoreContents 按依赖顺序恢复资源，数据源、用户和通知路由保留原ID，规则通过原数据源ID关联
+func restoreContents(ctx context.Context, repo repository.RepositoryManager, contents *backupContents, result *models.BackupRestoreResult) error {
+	skip := func(kind, name, reason string) {
+		result.Skipped = append(result.Skipped, models.BackupSkippedItem{Kind: kind, Name: name, Reason: reason})
+	}
+
+	for _, backupUser := range contents.users {
+		user := backupUser.User
+		exists, err := repo.User().ExistsByUsername(ctx, user.Username)
+		if err != nil {
+			return fmt.Errorf("检查用户 %s 失败: %w", user.Username, err)
+		}
+		if exists {
+			skip(models.BackupKindUser, user.Username, "用户名已存在")
+			continue
+		}
+		if exists, err = repo.User().ExistsByEmail(ctx, user.Email); err != nil {
+			return fmt.Errorf("检查用户 %s 失败: %w", user.Username, err)
+		}
+		if exists {
+			skip(models.BackupKindUser, user.Username, "邮箱已被其他用户使用")
+			continue
+		}
+		user.LastLoginAt, user.DeletedAt = nil, nil
+		if err := repo.User().Create(ctx, &user); err != nil {
+			return fmt.Errorf("恢复用户 %s 失败: %w", user.Username, err)
+		}
+		result.Restored[models.BackupKindUser]++
+	}
+
+	for _, ds := range contents.dataSources {
+		if err := repo.DataSource().Create(ctx, ds); err != nil {
+			return fmt.Errorf("恢复数据源 %s 失败: %w", ds.Name, err)
+		}
+		result.Restored[models.BackupKindDataSource]++
+	}
+
+	for _, rule := range contents.rules {
+		if err := repo.Rule().Create(ctx, rule); err != nil {
+			return fmt.Errorf("恢复规则 %s 失败: %w", rule.Name, err)
+		}
+		result.Restored[models.BackupKindRule]++
+	}
+
+	for _, route := range contents.routes {
+		if err := repo.NotificationRouting().CreateCalendar(ctx, route); err != nil {
+			return fmt.Errorf("恢复通知日历 %s 失败: %w", route.Name, err)
+		}
+		result.Restored[models.BackupKindRoute]++
+	}
+
+	for _, template := range contents.templates {
+		_, err := repo.Notification().GetTemplateByName(ctx, template.Name)
+		if err == nil {
+			skip(models.BackupKindTemplate, template.Name, "同名模板已存在")
+			continue
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return fmt.Errorf("检查通知模板 %s 失败: %w", template.Name, err)
+		}
+		if err := repo.Notification().CreateTemplate(ctx, template); err != nil {
+			return fmt.Errorf("恢复通知模板 %s 失败: %w", template.Name, err)
+		}
+		result.Restored[models.BackupKindTemplate]++
+	}
+
+	// 轮询计数不备份，恢复后从头开始轮询
+	for _, policy := range contents.policies {
+		_, err := repo.AssignmentPolicy().GetByTeam(ctx, policy.Team)
+		if err == nil {
+			skip(models.BackupKindAssignment, policy.Team, "团队已有分派策略")
+			continue
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return fmt.Errorf("检查团队 %s 的分派策略失败: %w", policy.Team, err)
+		}
+		if err := repo.AssignmentPolicy().Create(ctx, policy); err != nil {
+			return fmt.Errorf("恢复团队 %s 的分派策略失败: %w", policy.Team, err)
+		}
+		result.Restored[models.BackupKindAssignment]++
+	}
+	return nil
+}
+
+// decodeBackupFile 解析备份包中的 JSON 文件
+func decodeBackupFile(files map[string][]byte, name string, v interface{}) error {
+	data, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: 缺少文件 %s", models.ErrBackupInvalid, name)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: 解析 %s 失败: %v", models.ErrBackupInvalid, name, err)
+	}
+	return nil
+}
+
+// backupSecrets 使用备份包的加密密钥创建加密服务
+func backupSecrets(key *backup.Key) (*crypto.KeyManager, error) {
+	secrets, err := crypto.NewKeyManager(crypto.KeyManagerConfig{
+		Keys: "backup:" + base64.StdEncoding.EncodeToString(key.EncryptionKey()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建备份加密密钥失败: %w", err)
+	}
+	return secrets, nil
+}
+
+// sealDataSourceSecrets 加密数据源配置中的凭据字段和携带凭据的请求头
+func sealDataSourceSecrets(secrets *crypto.KeyManager, config *models.DataSourceConfig) error {
+	if err := secrets.EncryptDataSourceConfig(config); err != nil {
+		return err
+	}
+	for name, value := range config.Headers {
+		if !crypto.IsSensitiveHeader(name) || value == "" {
+			continue
+		}
+		sealed, err := secrets.Encrypt(value)
+		if err != nil {
+			return err
+		}
+		config.Headers[name] = sealed
+	}
+	return nil
+}
+
+// openDataSourceSecrets 解密 sealDataSourceSecrets 加密的字段
+func openDataSourceSecrets(secrets *crypto.KeyManager, config *models.DataSourceConfig) error {
+	if err := secrets.DecryptDataSourceConfig(config); err != nil {
+		return err
+	}
+	for name, value := range config.Headers {
+		if !crypto.IsSensitiveHeader(name) || value == "" {
+			continue
+		}
+		opened, err := secrets.Decrypt(value)
+		if err != nil {
+			return err
+		}
+		config.Headers[name] = opened
+	}
+	return nil
+}
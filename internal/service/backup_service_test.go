@@ -0,0 +1,273 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/backup"
+	"pulse/internal/repository"
+)
+
+const testBackupPassphrase = "backup passphrase 2026"
+
+// fakeBackupUserRepository 内存中的用户
+type fakeBackupUserRepository struct {
+	repository.UserRepository
+	users []*models.User
+}
+
+func (r *fakeBackupUserRepository) List(ctx context.Context, filter *models.UserFilter) (*models.UserList, error) {
+	list := &models.UserList{Users: []*models.User{}}
+	start := (filter.Page - 1) * filter.PageSize
+	for i := start; i < len(r.users) && i < start+filter.PageSize; i++ {
+		// 列表不返回密码哈希
+		copied := *r.users[i]
+		copied.PasswordHash = ""
+		list.Users = append(list.Users, &copied)
+	}
+	return list, nil
+}
+
+func (r *fakeBackupUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (r *fakeBackupUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeBackupUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeBackupUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.users = append(r.users, user)
+	return nil
+}
+
+// fakeBackupNotificationRepository 内存中的通知模板
+type fakeBackupNotificationRepository struct {
+	repository.NotificationRepository
+	templates []*models.NotificationTemplate
+}
+
+func (r *fakeBackupNotificationRepository) GetTemplates(ctx context.Context) ([]*models.NotificationTemplate, error) {
+	return r.templates, nil
+}
+
+func (r *fakeBackupNotificationRepository) GetTemplateByName(ctx context.Context, name string) (*models.NotificationTemplate, error) {
+	for _, template := range r.templates {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+	return nil, models.ErrNotificationTemplateNotFound
+}
+
+func (r *fakeBackupNotificationRepository) CreateTemplate(ctx context.Context, template *models.NotificationTemplate) error {
+	r.templates = append(r.templates, template)
+	return nil
+}
+
+// fakeBackupAssignmentPolicyRepository 内存中的分派策略
+type fakeBackupAssignmentPolicyRepository struct {
+	fakeAssignmentPolicyRepository
+}
+
+func (r *fakeBackupAssignmentPolicyRepository) List(ctx context.Context) ([]*models.AssignmentPolicy, error) {
+	return r.policies, nil
+}
+
+func (r *fakeBackupAssignmentPolicyRepository) Create(ctx context.Context, policy *models.AssignmentPolicy) error {
+	r.policies = append(r.policies, policy)
+	return nil
+}
+
+type backupRepoManager struct {
+	*configApplyRepoManager
+	users         *fakeBackupUserRepository
+	notifications *fakeBackupNotificationRepository
+	policies      *fakeBackupAssignmentPolicyRepository
+}
+
+func (m *backupRepoManager) User() repository.UserRepository { return m.users }
+
+func (m *backupRepoManager) Notification() repository.NotificationRepository { return m.notifications }
+
+func (m *backupRepoManager) AssignmentPolicy() repository.AssignmentPolicyRepository {
+	return m.policies
+}
+
+func (m *backupRepoManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
+	return m, nil
+}
+
+// newBackupTestRepoManager 创建只有管理员和默认模板的全新实例
+func newBackupTestRepoManager() *backupRepoManager {
+	return &backupRepoManager{
+		configApplyRepoManager: &configApplyRepoManager{
+			MockRepositoryManager: &MockRepositoryManager{},
+			dataSources:           &fakeConfigDataSourceRepository{},
+			rules:                 &fakeConfigRuleRepository{},
+			routing:               &fakeConfigRoutingRepository{},
+		},
+		users: &fakeBackupUserRepository{users: []*models.User{
+			{ID: "admin-id", Username: "admin", Email: "admin@example.com", PasswordHash: "$2a$10$admin", Role: models.UserRoleAdmin},
+		}},
+		notifications: &fakeBackupNotificationRepository{templates: []*models.NotificationTemplate{
+			{ID: uuid.New(), Name: "默认邮件模板", Type: models.NotificationTypeEmail, IsDefault: true},
+		}},
+		policies: &fakeBackupAssignmentPolicyRepository{},
+	}
+}
+
+// newBackupSourceRepoManager 创建已有配置的源实例
+func newBackupSourceRepoManager() *backupRepoManager {
+	repoManager := newBackupTestRepoManager()
+	source := newConfigApplyTestRepoManager()
+	repoManager.configApplyRepoManager = source
+	source.dataSources.dataSources[0].Config.Headers = map[string]string{"Authorization": "Bearer abc", "X-Scope": "team-a"}
+	source.routing.calendars = []*models.NotificationCalendar{{ID: "cal-1", Name: "支付团队", Team: "payments"}}
+	repoManager.users.users = append(repoManager.users.users,
+		&models.User{ID: "oncall-id", Username: "oncall", Email: "oncall@example.com", PasswordHash: "$2a$10$oncall", Role: models.UserRoleOperator})
+	repoManager.notifications.templates = append(repoManager.notifications.templates,
+		&models.NotificationTemplate{ID: uuid.New(), Name: "支付告警", Type: models.NotificationTypeDingTalk, Content: "{{.Title}}"})
+	repoManager.policies.policies = []*models.AssignmentPolicy{
+		{ID: "policy-dba", Team: "dba", Strategy: models.AssignmentStrategyRoundRobin, Members: []models.AssignmentMember{{UserID: "admin-id"}}, Enabled: true, CreatedBy: "admin-id"},
+		{ID: "policy-payments", Team: "payments", Strategy: models.AssignmentStrategyLeastLoad, Members: []models.AssignmentMember{{UserID: "oncall-id"}}, Enabled: true, CreatedBy: "admin-id"},
+	}
+	return repoManager
+}
+
+func exportTestBackup(t *testing.T, version string) []byte {
+	t.Helper()
+	svc := NewBackupService(newBackupSourceRepoManager(), version, zap.NewNop())
+	var buf bytes.Buffer
+	manifest, err := svc.Export(context.Background(), testBackupPassphrase, "admin-id", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, version, manifest.PulseVersion)
+	assert.Equal(t, map[string]int{
+		models.BackupKindUser: 2, models.BackupKindDataSource: 1, models.BackupKindRule: 2,
+		models.BackupKindRoute: 1, models.BackupKindTemplate: 2, models.BackupKindAssignment: 2,
+	}, manifest.Counts)
+	return buf.Bytes()
+}
+
+func TestBackupService_ExportRestore(t *testing.T) {
+	ctx := context.Background()
+	data := exportTestBackup(t, "1.2.0")
+
+	// 备份包中不包含明文凭据和密码哈希
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		for _, secret := range []string{"s3cret", "Bearer abc", "$2a$10$"} {
+			assert.NotContains(t, string(content), secret, file.Name)
+		}
+	}
+
+	// 恢复到较新次版本的全新实例，用户和模板重名、团队已有分派策略的跳过
+	target := newBackupTestRepoManager()
+	target.policies.policies = []*models.AssignmentPolicy{{ID: "local-dba", Team: "dba", Strategy: models.AssignmentStrategyRoundRobin}}
+	svc := NewBackupService(target, "1.3.1", zap.NewNop())
+	result, err := svc.Restore(ctx, data, testBackupPassphrase, "admin-id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		models.BackupKindUser: 1, models.BackupKindDataSource: 1, models.BackupKindRule: 2,
+		models.BackupKindRoute: 1, models.BackupKindTemplate: 1, models.BackupKindAssignment: 1,
+	}, result.Restored)
+	assert.ElementsMatch(t, []models.BackupSkippedItem{
+		{Kind: models.BackupKindUser, Name: "admin", Reason: "用户名已存在"},
+		{Kind: models.BackupKindTemplate, Name: "默认邮件模板", Reason: "同名模板已存在"},
+		{Kind: models.BackupKindAssignment, Name: "dba", Reason: "团队已有分派策略"},
+	}, result.Skipped)
+	require.Len(t, target.policies.policies, 2)
+	payments := target.policies.policies[1]
+	assert.Equal(t, "policy-payments", payments.ID)
+	assert.Equal(t, models.AssignmentStrategyLeastLoad, payments.Strategy)
+	assert.Equal(t, []models.AssignmentMember{{UserID: "oncall-id"}}, payments.Members)
+
+	require.Len(t, target.dataSources.dataSources, 1)
+	ds := target.dataSources.dataSources[0]
+	assert.Equal(t, "ds-prom", ds.ID)
+	assert.Equal(t, "s3cret", *ds.Config.Password)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer abc", "X-Scope": "team-a"}, ds.Config.Headers)
+	assert.Equal(t, "ds-prom", target.rules.byName("HighCPU").DataSourceID)
+	assert.Equal(t, "cal-1", target.routing.calendars[0].ID)
+	require.Len(t, target.users.users, 2)
+	assert.Equal(t, "oncall-id", target.users.users[1].ID)
+	assert.Equal(t, "$2a$10$oncall", target.users.users[1].PasswordHash)
+
+	// 已有配置的实例拒绝恢复
+	_, err = svc.Restore(ctx, data, testBackupPassphrase, "admin-id")
+	assert.ErrorIs(t, err, models.ErrBackupTargetNotEmpty)
+	assert.ErrorIs(t, err, models.ErrConflict)
+}
+
+func TestBackupService_RestoreRejected(t *testing.T) {
+	ctx := context.Background()
+	data := exportTestBackup(t, "1.2.0")
+
+	target := newBackupTestRepoManager()
+	_, err := NewBackupService(target, "1.2.0", zap.NewNop()).Restore(ctx, data, "wrong passphrase", "admin-id")
+	assert.ErrorIs(t, err, models.ErrBackupSignatureInvalid)
+
+	_, err = NewBackupService(target, "1.1.5", zap.NewNop()).Restore(ctx, data, testBackupPassphrase, "admin-id")
+	assert.ErrorIs(t, err, models.ErrBackupIncompatible)
+	_, err = NewBackupService(target, "2.0.0", zap.NewNop()).Restore(ctx, data, testBackupPassphrase, "admin-id")
+	assert.ErrorIs(t, err, models.ErrBackupIncompatible)
+
+	_, err = NewBackupService(target, "1.2.0", zap.NewNop()).Restore(ctx, []byte("not a zip"), testBackupPassphrase, "admin-id")
+	assert.ErrorIs(t, err, models.ErrBackupInvalid)
+	assert.Empty(t, target.dataSources.dataSources)
+
+	_, err = NewBackupService(target, "1.2.0", zap.NewNop()).Export(ctx, "short", "admin-id", io.Discard)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestBackupService_RestoreFormatVersion1(t *testing.T) {
+	// 格式版本 1 的备份包不包含分派策略，恢复时跳过
+	files, key, err := backup.Read(exportTestBackup(t, "1.2.0"), testBackupPassphrase)
+	require.NoError(t, err)
+	delete(files, models.BackupAssignmentFile)
+	files[models.BackupManifestFile] = []byte(`{"format_version": 1, "pulse_version": "1.2.0", "counts": {}}`)
+	var buf bytes.Buffer
+	require.NoError(t, backup.Write(&buf, key, files))
+
+	target := newBackupTestRepoManager()
+	result, err := NewBackupService(target, "1.2.0", zap.NewNop()).Restore(context.Background(), buf.Bytes(), testBackupPassphrase, "admin-id")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Restored[models.BackupKindRule])
+	assert.Zero(t, result.Restored[models.BackupKindAssignment])
+	assert.Empty(t, target.policies.policies)
+}
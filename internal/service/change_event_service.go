@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// alertChangeLimit 告警详情中最多展示的关联变更数
+const alertChangeLimit = 20
+
+// changeEventService 变更事件服务实现
+type changeEventService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.ChangeEventConfig
+	logger      *zap.Logger
+}
+
+// NewChangeEventService 创建变更事件服务实例
+func NewChangeEventService(repoManager repository.RepositoryManager, cfg config.ChangeEventConfig, logger *zap.Logger) ChangeEventService {
+	return &changeEventService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// List 分页获取变更事件
+func (s *changeEventService) List(ctx context.Context, filter *models.ChangeEventFilter) ([]*models.ChangeEvent, int64, error) {
+	return s.repoManager.ChangeEvent().List(ctx, filter)
+}
+
+// Get 获取变更事件
+func (s *changeEventService) Get(ctx context.Context, id string) (*models.ChangeEvent, error) {
+	return s.repoManager.ChangeEvent().GetByID(ctx, id)
+}
+
+// Create 登记变更事件，未指定发生时间时使用登记时间
+func (s *changeEventService) Create(ctx context.Context, req *models.ChangeEventRequest, createdBy string) (*models.ChangeEvent, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	event := &models.ChangeEvent{
+		Service:     req.Service,
+		Type:        req.Type,
+		Title:       req.Title,
+		Description: req.Description,
+		Version:     req.Version,
+		Source:      req.Source,
+		URL:         req.URL,
+		OccurredAt:  time.Now(),
+	}
+	if req.OccurredAt != nil {
+		event.OccurredAt = *req.OccurredAt
+	}
+	if createdBy != "" {
+		event.CreatedBy = &createdBy
+	}
+	if err := s.repoManager.ChangeEvent().Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("变更事件登记成功", zap.String("change_event_id", event.ID), zap.String("service", event.Service),
+		zap.String("type", string(event.Type)))
+	return event, nil
+}
+
+// Delete 删除变更事件，已写入告警注解的关联不会撤销
+func (s *changeEventService) Delete(ctx context.Context, id string) error {
+	return s.repoManager.ChangeEvent().Delete(ctx, id)
+}
+
+// Correlate 获取告警触发前关联窗口内同一服务上最近的变更，告警未归属到服务或没有变更时返回 nil
+func (s *changeEventService) Correlate(ctx context.Context, alert *models.Alert) (*models.ChangeEvent, error) {
+	changes, err := s.changesBefore(ctx, alert, 1)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	return changes[0], nil
+}
+
+// ForAlert 获取告警触发前关联窗口内同一服务的全部变更，按发生时间倒序
+func (s *changeEventService) ForAlert(ctx context.Context, alertID string) ([]*models.ChangeEvent, error) {
+	alert, err := s.repoManager.Alert().GetByID(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.changesBefore(ctx, alert, alertChangeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []*models.ChangeEvent{}
+	}
+	return changes, nil
+}
+
+// changesBefore 获取告警触发前关联窗口内同一服务的变更
+func (s *changeEventService) changesBefore(ctx context.Context, alert *models.Alert, limit int) ([]*models.ChangeEvent, error) {
+	service := alert.Labels[models.ServiceLabel]
+	if service == "" || s.cfg.CorrelationWindow <= 0 {
+		return nil, nil
+	}
+	startsAt := alert.StartsAt
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+	return s.repoManager.ChangeEvent().ListForService(ctx, service, startsAt.Add(-s.cfg.CorrelationWindow), startsAt, limit)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeChangeEventRepository 内存中的变更事件
+type fakeChangeEventRepository struct {
+	repository.ChangeEventRepository
+	events []*models.ChangeEvent
+}
+
+func (r *fakeChangeEventRepository) Create(ctx context.Context, event *models.ChangeEvent) error {
+	event.ID = fmt.Sprintf("c%d", len(r.events)+1)
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeChangeEventRepository) ListForService(ctx context.Context, service string, from, to time.Time, limit int) ([]*models.ChangeEvent, error) {
+	var events []*models.ChangeEvent
+	for _, event := range r.events {
+		if event.Service == service && !event.OccurredAt.Before(from) && !event.OccurredAt.After(to) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].OccurredAt.After(events[j].OccurredAt) })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+type changeEventRepoManager struct {
+	*serviceCatalogRepoManager
+	changes *fakeChangeEventRepository
+}
+
+func (m *changeEventRepoManager) ChangeEvent() repository.ChangeEventRepository { return m.changes }
+
+func TestChangeEventService_Create(t *testing.T) {
+	ctx := context.Background()
+	repoManager := &changeEventRepoManager{
+		serviceCatalogRepoManager: &serviceCatalogRepoManager{MockRepositoryManager: &MockRepositoryManager{}},
+		changes:                   &fakeChangeEventRepository{},
+	}
+	svc := NewChangeEventService(repoManager, config.ChangeEventConfig{CorrelationWindow: 30 * time.Minute}, zap.NewNop())
+
+	invalidURL := "ftp://deploy.example.com"
+	for _, req := range []*models.ChangeEventRequest{
+		{Service: "check out", Type: models.ChangeEventTypeDeployment, Title: "发布"},
+		{Service: "checkout", Type: "rollback", Title: "发布"},
+		{Service: "checkout", Type: models.ChangeEventTypeDeployment, Title: "  "},
+		{Service: "checkout", Type: models.ChangeEventTypeDeployment, Title: "发布", URL: &invalidURL},
+	} {
+		_, err := svc.Create(ctx, req, "u1")
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	}
+
+	event, err := svc.Create(ctx, &models.ChangeEventRequest{
+		Service: " checkout ", Type: models.ChangeEventTypeDeployment, Title: "升级支付 SDK", Version: "v1.4.2",
+	}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", event.Service)
+	assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Second)
+	assert.Equal(t, "deployment checkout v1.4.2: 升级支付 SDK", event.Summary())
+}
+
+func TestAlertService_CorrelatesChanges(t *testing.T) {
+	ctx := context.Background()
+	catalogRepoManager, catalog := newServiceCatalogTestService(t)
+	repoManager := &changeEventRepoManager{serviceCatalogRepoManager: catalogRepoManager, changes: &fakeChangeEventRepository{}}
+	changes := NewChangeEventService(repoManager, config.ChangeEventConfig{CorrelationWindow: 30 * time.Minute}, zap.NewNop())
+	alertSvc := NewAlertService(repoManager.alerts, nil, catalog, nil, changes, nil, nil, nil, nil, nil, 0, zap.NewNop())
+
+	now := time.Now()
+	before := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	for _, req := range []*models.ChangeEventRequest{
+		{Service: "checkout", Type: models.ChangeEventTypeConfig, Title: "调整连接池", OccurredAt: before(20 * time.Minute)},
+		{Service: "checkout", Type: models.ChangeEventTypeDeployment, Title: "升级支付 SDK", Version: "v1.4.2", OccurredAt: before(5 * time.Minute)},
+		{Service: "checkout", Type: models.ChangeEventTypeDeployment, Title: "过早的发布", OccurredAt: before(time.Hour)},
+		{Service: "postgres", Type: models.ChangeEventTypeFeatureFlag, Title: "开启只读副本", OccurredAt: before(time.Minute)},
+	} {
+		_, err := changes.Create(ctx, req, "")
+		require.NoError(t, err)
+	}
+
+	// 告警按服务目录归属到 checkout 后关联该服务最近的变更
+	alert := &models.Alert{
+		DataSourceID: "ds1",
+		Name:         "HighErrorRate",
+		Description:  "结算接口错误率超过 5%",
+		Severity:     models.AlertSeverityHigh,
+		Status:       models.AlertStatusFiring,
+		Expression:   "rate(checkout_errors_total[5m]) > 0.05",
+		Source:       models.AlertSourcePrometheus,
+		Labels:       map[string]string{"namespace": "checkout"},
+		StartsAt:     now,
+		Fingerprint:  "checkout-errors",
+	}
+	require.NoError(t, alertSvc.Create(ctx, alert))
+	assert.Equal(t, "deployment checkout v1.4.2: 升级支付 SDK", alert.Annotations[models.AlertAnnotationPossibleCause])
+	assert.Equal(t, "c2", alert.Annotations[models.AlertAnnotationChangeEventID])
+
+	related, err := changes.ForAlert(ctx, alert.ID)
+	require.NoError(t, err)
+	require.Len(t, related, 2)
+	assert.Equal(t, "c2", related[0].ID)
+	assert.Equal(t, "c1", related[1].ID)
+
+	// 未归属到服务的告警不关联变更
+	other := &models.Alert{
+		DataSourceID: "ds1",
+		Name:         "DiskFull",
+		Description:  "磁盘剩余空间不足 10%",
+		Severity:     models.AlertSeverityHigh,
+		Status:       models.AlertStatusFiring,
+		Expression:   "disk_free < 0.1",
+		Source:       models.AlertSourcePrometheus,
+		Labels:       map[string]string{"namespace": "other"},
+		StartsAt:     now,
+		Fingerprint:  "disk-full",
+	}
+	require.NoError(t, alertSvc.Create(ctx, other))
+	assert.Empty(t, other.Annotations[models.AlertAnnotationPossibleCause])
+	related, err = changes.ForAlert(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, related)
+}
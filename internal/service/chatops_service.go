@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+// chatOpsService 聊天平台命令服务实现
+type chatOpsService struct {
+	repoManager   repository.RepositoryManager
+	alertService  AlertService
+	ticketService TicketService
+	cfg           config.ChatOpsConfig
+	logger        *zap.Logger
+}
+
+// NewChatOpsService 创建聊天平台命令服务实例
+func NewChatOpsService(repoManager repository.RepositoryManager, alertService AlertService, ticketService TicketService, cfg config.ChatOpsConfig, logger *zap.Logger) ChatOpsService {
+	return &chatOpsService{
+		repoManager:   repoManager,
+		alertService:  alertService,
+		ticketService: ticketService,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Enabled 是否启用聊天平台命令
+func (s *chatOpsService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// Verify 按聊天平台的签名规则校验回调请求，未配置签名密钥的平台拒绝所有请求
+func (s *chatOpsService) Verify(platform models.ChatPlatform, signatureHeader, timestampHeader string, body []byte) error {
+	now := time.Now()
+
+	var err error
+	switch platform {
+	case models.ChatPlatformSlack:
+		if s.cfg.SlackSigningSecret == "" {
+			return fmt.Errorf("%w: 未配置 Slack 签名密钥", models.ErrInvalidSignature)
+		}
+		err = signature.VerifySlack(s.cfg.SlackSigningSecret, signatureHeader, timestampHeader, body, now)
+	case models.ChatPlatformDingTalk:
+		if s.cfg.DingTalkAppSecret == "" {
+			return fmt.Errorf("%w: 未配置钉钉机器人密钥", models.ErrInvalidSignature)
+		}
+		err = signature.VerifyDingTalk(s.cfg.DingTalkAppSecret, signatureHeader, timestampHeader, now)
+	default:
+		return fmt.Errorf("%w: 不支持的聊天平台 %s", models.ErrInvalidInput, platform)
+	}
+
+	if err != nil {
+		s.logger.Warn("聊天平台回调签名校验失败", zap.Error(err), zap.String("platform", string(platform)))
+		return fmt.Errorf("%w: %v", models.ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// Execute 以聊天用户绑定的平台用户身份执行命令，返回回复到聊天中的文本
+func (s *chatOpsService) Execute(ctx context.Context, platform models.ChatPlatform, chatUserID, text string) (string, error) {
+	operator, err := s.operator(ctx, platform, chatUserID)
+	if err != nil {
+		return "", err
+	}
+
+	cmd, err := models.ParseChatCommand(text)
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.Info("执行聊天命令", zap.String("platform", string(platform)), zap.String("chat_user_id", chatUserID),
+		zap.String("user_id", operator.ID), zap.String("action", string(cmd.Action)), zap.String("alert_id", cmd.AlertID))
+
+	switch cmd.Action {
+	case models.ChatCommandAck:
+		if err := s.alertService.Acknowledge(ctx, cmd.AlertID, operator.ID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("告警 %s 已由 %s 确认", cmd.AlertID, operator.Username), nil
+	case models.ChatCommandResolve:
+		if err := s.alertService.Resolve(ctx, cmd.AlertID, operator.ID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("告警 %s 已由 %s 解决", cmd.AlertID, operator.Username), nil
+	case models.ChatCommandAssign:
+		return s.assign(ctx, platform, cmd, operator)
+	case models.ChatCommandSilence:
+		return s.silence(ctx, platform, cmd, operator)
+	default:
+		return models.ChatCommandUsage, nil
+	}
+}
+
+// operator 将聊天用户映射到平台用户，只有状态正常的用户可以执行命令
+func (s *chatOpsService) operator(ctx context.Context, platform models.ChatPlatform, chatUserID string) (*models.User, error) {
+	if chatUserID == "" {
+		return nil, fmt.Errorf("%w: 缺少聊天用户ID", models.ErrInvalidInput)
+	}
+
+	mapping, err := s.repoManager.ChatUserMapping().GetByChatUser(ctx, platform, chatUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, mapping.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取绑定的平台用户失败: %w", err)
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, fmt.Errorf("%w: 用户 %s 未激活或已禁用", models.ErrPermissionDenied, user.Username)
+	}
+
+	return user, nil
+}
+
+// assign 将告警关联的未结工单指派给处理人，告警没有未结工单时创建一张并指派
+func (s *chatOpsService) assign(ctx context.Context, platform models.ChatPlatform, cmd *models.ChatCommand, operator *models.User) (string, error) {
+	assignee, err := s.resolveAssignee(ctx, platform, cmd.Assignee)
+	if err != nil {
+		return "", err
+	}
+
+	alert, err := s.repoManager.Alert().GetByID(ctx, cmd.AlertID)
+	if err != nil {
+		return "", err
+	}
+
+	tickets, err := s.repoManager.Ticket().GetByAlertID(ctx, alert.ID)
+	if err != nil {
+		return "", fmt.Errorf("获取告警关联工单失败: %w", err)
+	}
+
+	ticketIDs := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		if !isOpenTicket(ticket) {
+			continue
+		}
+		if err := s.ticketService.Assign(ctx, ticket.ID, assignee.ID); err != nil {
+			return "", err
+		}
+		ticketIDs = append(ticketIDs, ticket.ID)
+	}
+
+	if len(ticketIDs) == 0 {
+		alertID := alert.ID
+		ticket := &models.Ticket{
+			Title:       alert.Name,
+			Description: alert.Description,
+			Type:        models.TicketTypeIncident,
+			Status:      models.TicketStatusAssigned,
+			Priority:    ticketPriorityForAlert(alert.Severity),
+			Severity:    ticketSeverityForAlert(alert.Severity),
+			Source:      models.TicketSourceAlert,
+			Labels:      alert.Labels,
+			AlertID:     &alertID,
+			RuleID:      alert.RuleID,
+			ReporterID:  operator.ID,
+			AssigneeID:  &assignee.ID,
+		}
+		if err := s.ticketService.Create(ctx, ticket); err != nil {
+			return "", err
+		}
+		ticketIDs = append(ticketIDs, ticket.ID)
+	}
+
+	s.addHistory(ctx, alert.ID, models.AlertHistoryActionAssigned, operator.ID, nil, map[string]interface{}{
+		"assignee_id": assignee.ID,
+		"ticket_ids":  ticketIDs,
+		"platform":    platform,
+	})
+
+	return fmt.Sprintf("告警 %s 已指派给 %s", alert.ID, assignee.Username), nil
+}
+
+// resolveAssignee 解析指派对象，Slack 提及格式 <@U123|name> 按聊天用户映射查找，
+// @name 先按聊天用户映射查找，找不到时按平台用户名查找
+func (s *chatOpsService) resolveAssignee(ctx context.Context, platform models.ChatPlatform, raw string) (*models.User, error) {
+	if strings.HasPrefix(raw, "<@") && strings.HasSuffix(raw, ">") {
+		chatUserID, _, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(raw, "<@"), ">"), "|")
+		mapping, err := s.repoManager.ChatUserMapping().GetByChatUser(ctx, platform, chatUserID)
+		if err != nil {
+			if errors.Is(err, models.ErrChatUserMappingNotFound) {
+				return nil, fmt.Errorf("%w: 用户 %s 未绑定平台用户", models.ErrInvalidInput, raw)
+			}
+			return nil, err
+		}
+		return s.repoManager.User().GetByID(ctx, mapping.UserID)
+	}
+
+	name := strings.TrimPrefix(raw, "@")
+	mapping, err := s.repoManager.ChatUserMapping().GetByChatUser(ctx, platform, name)
+	if err == nil {
+		return s.repoManager.User().GetByID(ctx, mapping.UserID)
+	}
+	if !errors.Is(err, models.ErrChatUserMappingNotFound) {
+		return nil, err
+	}
+
+	user, err := s.repoManager.User().GetByUsername(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 找不到用户 %s", models.ErrInvalidInput, raw)
+	}
+	return user, nil
+}
+
+// silence 按告警指纹创建静默规则并将告警置为静默状态
+func (s *chatOpsService) silence(ctx context.Context, platform models.ChatPlatform, cmd *models.ChatCommand, operator *models.User) (string, error) {
+	if cmd.Duration > s.cfg.MaxSilence {
+		return "", fmt.Errorf("%w: 静默时长不能超过 %s", models.ErrInvalidInput, s.cfg.MaxSilence)
+	}
+
+	alert, err := s.repoManager.Alert().GetByID(ctx, cmd.AlertID)
+	if err != nil {
+		return "", err
+	}
+	if alert.Status == models.AlertStatusResolved {
+		return "", fmt.Errorf("%w: 告警已解决，无需静默", models.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	comment := fmt.Sprintf("%s 通过 %s 静默", operator.Username, platform)
+	silence := &models.AlertSilence{
+		Name:      fmt.Sprintf("ChatOps: %s", alert.Name),
+		Comment:   &comment,
+		StartsAt:  now,
+		EndsAt:    now.Add(cmd.Duration),
+		Active:    true,
+		CreatedBy: operator.ID,
+		Matchers:  map[string]string{"fingerprint": alert.Fingerprint},
+	}
+
+	err = s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		if err := repo.Alert().CreateSilence(ctx, silence); err != nil {
+			return err
+		}
+		return repo.Alert().Silence(ctx, alert.ID, silence.ID, cmd.Duration)
+	})
+	if err != nil {
+		s.logger.Error("静默告警失败", zap.Error(err), zap.String("alert_id", alert.ID))
+		return "", err
+	}
+
+	s.addHistory(ctx, alert.ID, models.AlertHistoryActionSilenced, operator.ID,
+		map[string]interface{}{"status": alert.Status},
+		map[string]interface{}{"status": models.AlertStatusSilenced, "silence_id": silence.ID, "ends_at": silence.EndsAt})
+
+	return fmt.Sprintf("告警 %s 已静默至 %s", alert.ID, silence.EndsAt.Format(time.DateTime)), nil
+}
+
+// ListUserMappings 获取聊天用户映射
+func (s *chatOpsService) ListUserMappings(ctx context.Context, platform models.ChatPlatform) ([]*models.ChatUserMapping, error) {
+	if platform != "" && !platform.IsValid() {
+		return nil, fmt.Errorf("%w: 不支持的聊天平台 %s", models.ErrInvalidInput, platform)
+	}
+	return s.repoManager.ChatUserMapping().List(ctx, platform)
+}
+
+// BindUser 绑定聊天平台用户到平台用户
+func (s *chatOpsService) BindUser(ctx context.Context, req *models.ChatUserMappingRequest, createdBy string) (*models.ChatUserMapping, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repoManager.User().GetByID(ctx, req.UserID); err != nil {
+		return nil, fmt.Errorf("%w: 平台用户不存在", models.ErrInvalidInput)
+	}
+
+	mapping := &models.ChatUserMapping{
+		Platform:   req.Platform,
+		ChatUserID: strings.TrimSpace(req.ChatUserID),
+		UserID:     req.UserID,
+		CreatedBy:  createdBy,
+	}
+	if err := s.repoManager.ChatUserMapping().Create(ctx, mapping); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("聊天用户绑定成功", zap.String("platform", string(mapping.Platform)),
+		zap.String("chat_user_id", mapping.ChatUserID), zap.String("user_id", mapping.UserID))
+	return mapping, nil
+}
+
+// UnbindUser 解除聊天平台用户绑定
+func (s *chatOpsService) UnbindUser(ctx context.Context, id string) error {
+	return s.repoManager.ChatUserMapping().Delete(ctx, id)
+}
+
+// addHistory 记录聊天命令产生的告警历史
+func (s *chatOpsService) addHistory(ctx context.Context, alertID, action, userID string, oldValue, newValue map[string]interface{}) {
+	history := &models.AlertHistory{
+		ID:        uuid.New().String(),
+		AlertID:   alertID,
+		Action:    action,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		UserID:    &userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repoManager.Alert().AddHistory(ctx, history); err != nil {
+		s.logger.Warn("记录告警历史失败", zap.Error(err), zap.String("alert_id", alertID))
+	}
+}
+
+// withTx 在事务中执行
+func (s *chatOpsService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// isOpenTicket 工单是否仍在处理中
+func isOpenTicket(ticket *models.Ticket) bool {
+	switch ticket.Status {
+	case models.TicketStatusResolved, models.TicketStatusClosed, models.TicketStatusCancelled:
+		return false
+	default:
+		return true
+	}
+}
+
+// ticketPriorityForAlert 按告警级别确定工单优先级
+func ticketPriorityForAlert(severity models.AlertSeverity) models.TicketPriority {
+	switch severity {
+	case models.AlertSeverityCritical:
+		return models.TicketPriorityCritical
+	case models.AlertSeverityHigh:
+		return models.TicketPriorityHigh
+	case models.AlertSeverityMedium:
+		return models.TicketPriorityMedium
+	default:
+		return models.TicketPriorityLow
+	}
+}
+
+// ticketSeverityForAlert 按告警级别确定工单严重程度
+func ticketSeverityForAlert(severity models.AlertSeverity) models.TicketSeverity {
+	switch severity {
+	case models.AlertSeverityCritical:
+		return models.TicketSeverityCritical
+	case models.AlertSeverityHigh:
+		return models.TicketSeverityMajor
+	case models.AlertSeverityMedium:
+		return models.TicketSeverityMinor
+	case models.AlertSeverityLow:
+		return models.TicketSeverityWarning
+	default:
+		return models.TicketSeverityInfo
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+// fakeChatUserMappingRepository 内存聊天用户映射仓储
+type fakeChatUserMappingRepository struct {
+	repository.ChatUserMappingRepository
+	mappings []*models.ChatUserMapping
+}
+
+func (r *fakeChatUserMappingRepository) GetByChatUser(ctx context.Context, platform models.ChatPlatform, chatUserID string) (*models.ChatUserMapping, error) {
+	for _, mapping := range r.mappings {
+		if mapping.Platform == platform && mapping.ChatUserID == chatUserID {
+			return mapping, nil
+		}
+	}
+	return nil, models.ErrChatUserMappingNotFound
+}
+
+type fakeChatOpsUserRepository struct {
+	repository.UserRepository
+	users []*models.User
+}
+
+func (r *fakeChatOpsUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (r *fakeChatOpsUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, models.ErrUserNotFound
+}
+
+type fakeChatOpsAlertRepository struct {
+	*fakeTimelineAlertRepository
+	silences []*models.AlertSilence
+}
+
+func (r *fakeChatOpsAlertRepository) CreateSilence(ctx context.Context, silence *models.AlertSilence) error {
+	silence.ID = fmt.Sprintf("s%d", len(r.silences)+1)
+	r.silences = append(r.silences, silence)
+	return nil
+}
+
+func (r *fakeChatOpsAlertRepository) Silence(ctx context.Context, id, silenceID string, duration time.Duration) error {
+	r.alert.Status = models.AlertStatusSilenced
+	r.alert.SilenceID = &silenceID
+	return nil
+}
+
+type chatOpsRepoManager struct {
+	*MockRepositoryManager
+	mappings *fakeChatUserMappingRepository
+	users    *fakeChatOpsUserRepository
+	alerts   *fakeChatOpsAlertRepository
+	tickets  *fakeTimelineTicketRepository
+}
+
+func (m *chatOpsRepoManager) ChatUserMapping() repository.ChatUserMappingRepository {
+	return m.mappings
+}
+
+func (m *chatOpsRepoManager) User() repository.UserRepository { return m.users }
+
+func (m *chatOpsRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *chatOpsRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func (m *chatOpsRepoManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
+	return m, nil
+}
+
+// fakeChatOpsAlertService 记录确认和解决操作的告警服务
+type fakeChatOpsAlertService struct {
+	AlertService
+	acked    map[string]string
+	resolved map[string]string
+}
+
+func (s *fakeChatOpsAlertService) Acknowledge(ctx context.Context, id string, userID string) error {
+	s.acked[id] = userID
+	return nil
+}
+
+func (s *fakeChatOpsAlertService) Resolve(ctx context.Context, id string, userID string) error {
+	s.resolved[id] = userID
+	return nil
+}
+
+// fakeChatOpsTicketService 记录指派和创建操作的工单服务
+type fakeChatOpsTicketService struct {
+	TicketService
+	assigned map[string]string
+	created  []*models.Ticket
+}
+
+func (s *fakeChatOpsTicketService) Assign(ctx context.Context, id string, assigneeID string) error {
+	s.assigned[id] = assigneeID
+	return nil
+}
+
+func (s *fakeChatOpsTicketService) Create(ctx context.Context, ticket *models.Ticket) error {
+	ticket.ID = fmt.Sprintf("t%d", len(s.created)+1)
+	s.created = append(s.created, ticket)
+	return nil
+}
+
+func newTestChatOpsService() (ChatOpsService, *chatOpsRepoManager, *fakeChatOpsAlertService, *fakeChatOpsTicketService) {
+	repoManager := &chatOpsRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		mappings: &fakeChatUserMappingRepository{mappings: []*models.ChatUserMapping{
+			{Platform: models.ChatPlatformSlack, ChatUserID: "U1", UserID: "u1"},
+			{Platform: models.ChatPlatformSlack, ChatUserID: "U2", UserID: "u2"},
+			{Platform: models.ChatPlatformSlack, ChatUserID: "U3", UserID: "u3"},
+		}},
+		users: &fakeChatOpsUserRepository{users: []*models.User{
+			{ID: "u1", Username: "alice", Status: models.UserStatusActive},
+			{ID: "u2", Username: "bob", Status: models.UserStatusActive},
+			{ID: "u3", Username: "carol", Status: models.UserStatusDisabled},
+		}},
+		alerts: &fakeChatOpsAlertRepository{fakeTimelineAlertRepository: &fakeTimelineAlertRepository{
+			alert: &models.Alert{ID: "a1", Name: "disk_full", Status: models.AlertStatusFiring,
+				Severity: models.AlertSeverityCritical, Fingerprint: "fp1"},
+		}},
+		tickets: &fakeTimelineTicketRepository{},
+	}
+	alertService := &fakeChatOpsAlertService{acked: map[string]string{}, resolved: map[string]string{}}
+	ticketService := &fakeChatOpsTicketService{assigned: map[string]string{}}
+	svc := NewChatOpsService(repoManager, alertService, ticketService, config.ChatOpsConfig{
+		Enabled:            true,
+		SlackSigningSecret: "slack-secret",
+		DingTalkAppSecret:  "ding-secret",
+		MaxSilence:         24 * time.Hour,
+	}, zap.NewNop())
+	return svc, repoManager, alertService, ticketService
+}
+
+func TestChatOpsService_AckAndResolve(t *testing.T) {
+	svc, _, alertService, _ := newTestChatOpsService()
+	ctx := context.Background()
+
+	reply, err := svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/ack a1")
+	require.NoError(t, err)
+	assert.Contains(t, reply, "alice")
+	assert.Equal(t, "u1", alertService.acked["a1"])
+
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U2", "resolve a1")
+	require.NoError(t, err)
+	assert.Equal(t, "u2", alertService.resolved["a1"])
+
+	// 未绑定或已禁用的聊天用户不能执行命令
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U9", "/ack a1")
+	assert.ErrorIs(t, err, models.ErrChatUserMappingNotFound)
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U3", "/ack a1")
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/reboot a1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestChatOpsService_Assign(t *testing.T) {
+	svc, repoManager, _, ticketService := newTestChatOpsService()
+	ctx := context.Background()
+
+	// 没有未结工单时创建工单并指派
+	repoManager.tickets.tickets = []*models.Ticket{{ID: "old", Status: models.TicketStatusClosed}}
+	reply, err := svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/assign a1 <@U2|bob>")
+	require.NoError(t, err)
+	assert.Contains(t, reply, "bob")
+	require.Len(t, ticketService.created, 1)
+	assert.Equal(t, "u2", *ticketService.created[0].AssigneeID)
+	assert.Equal(t, models.TicketPriorityCritical, ticketService.created[0].Priority)
+	assert.Empty(t, ticketService.assigned)
+
+	// 已有未结工单时直接指派，@用户名按平台用户名查找
+	repoManager.tickets.tickets = append(repoManager.tickets.tickets, &models.Ticket{ID: "t9", Status: models.TicketStatusOpen})
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/assign a1 @alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"t9": "u1"}, ticketService.assigned)
+
+	_, err = svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/assign a1 @nobody")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	histories := repoManager.alerts.histories
+	require.Len(t, histories, 2)
+	assert.Equal(t, models.AlertHistoryActionAssigned, histories[0].Action)
+}
+
+func TestChatOpsService_Silence(t *testing.T) {
+	svc, repoManager, _, _ := newTestChatOpsService()
+	ctx := context.Background()
+
+	_, err := svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/silence a1 48h")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	reply, err := svc.Execute(ctx, models.ChatPlatformSlack, "U1", "/silence a1 2h")
+	require.NoError(t, err)
+	assert.Contains(t, reply, "已静默")
+
+	require.Len(t, repoManager.alerts.silences, 1)
+	silence := repoManager.alerts.silences[0]
+	assert.Equal(t, map[string]string{"fingerprint": "fp1"}, silence.Matchers)
+	assert.Equal(t, 2*time.Hour, silence.EndsAt.Sub(silence.StartsAt))
+	assert.Equal(t, models.AlertStatusSilenced, repoManager.alerts.alert.Status)
+	assert.Equal(t, models.AlertHistoryActionSilenced, repoManager.alerts.histories[0].Action)
+}
+
+func TestChatOpsService_Verify(t *testing.T) {
+	svc, _, _, _ := newTestChatOpsService()
+	now := time.Now()
+	body := []byte("command=%2Fack&text=a1&user_id=U1")
+
+	timestamp := fmt.Sprint(now.Unix())
+	assert.NoError(t, svc.Verify(models.ChatPlatformSlack, signature.SignSlack("slack-secret", now.Unix(), body), timestamp, body))
+	assert.ErrorIs(t, svc.Verify(models.ChatPlatformSlack, signature.SignSlack("other", now.Unix(), body), timestamp, body),
+		models.ErrInvalidSignature)
+
+	millis := fmt.Sprint(now.UnixMilli())
+	assert.NoError(t, svc.Verify(models.ChatPlatformDingTalk, signature.SignDingTalk("ding-secret", now.UnixMilli()), millis, body))
+	assert.ErrorIs(t, svc.Verify(models.ChatPlatformDingTalk, "bad", millis, body), models.ErrInvalidSignature)
+}
@@ -0,0 +1,654 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// configApplyPageSize 分页读取现有数据源和规则的每页数量
+const configApplyPageSize = 100
+
+// sloRuleLabel SLO 自动生成的规则带有该标签，这些规则由 SLO 维护，声明式配置开启 prune 时也不会删除
+const sloRuleLabel = "slo_id"
+
+// configApplyService 声明式配置服务实现
+type configApplyService struct {
+	repoManager repository.RepositoryManager
+	approvals   ApprovalGate
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewConfigApplyService 创建声明式配置服务实例，approvals 为 nil 时删除数据源和规则不需要审批
+func NewConfigApplyService(repoManager repository.RepositoryManager, approvals ApprovalGate, logger *zap.Logger) ConfigApplyService {
+	return &configApplyService{
+		repoManager: repoManager,
+		approvals:   approvals,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Apply 对比声明式配置与现有配置并执行变更，全部变更在同一个事务中执行。
+// 任一资源校验失败时返回包含 Issues 的结果和 ErrConfigBundleInvalid，不做任何变更
+func (s *configApplyService) Apply(ctx context.Context, bundle *models.ConfigBundle, opts models.ConfigApplyOptions, userID string) (*models.ConfigApplyResult, error) {
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		plan, err := s.plan(ctx, s.repoManager, bundle, opts, userID)
+		if err != nil {
+			return nil, err
+		}
+		return plan.result, plan.err()
+	}
+
+	var result *models.ConfigApplyResult
+	err := s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		plan, err := s.plan(ctx, repo, bundle, opts, userID)
+		if err != nil {
+			return err
+		}
+		result = plan.result
+		if err := plan.err(); err != nil {
+			return err
+		}
+		for _, step := range plan.steps {
+			if err := step(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	s.logger.Info("声明式配置已应用", zap.String("user_id", userID), zap.Any("summary", result.Summary))
+	return result, nil
+}
+
+// plan 读取现有配置并生成执行计划，数据源先于规则创建，被删除的数据源在规则变更之后删除
+func (s *configApplyService) plan(ctx context.Context, repo repository.RepositoryManager, bundle *models.ConfigBundle, opts models.ConfigApplyOptions, userID string) (*configPlan, error) {
+	p := &configPlan{
+		repo:      repo,
+		approvals: s.approvals,
+		userID:    userID,
+		prune:     opts.Prune,
+		now:       s.now(),
+		result: &models.ConfigApplyResult{
+			DryRun:  opts.DryRun,
+			Changes: make([]models.ConfigChange, 0),
+			Summary: make(map[string]int),
+		},
+		dataSources:        make(map[string]*models.DataSource),
+		dataSourceNames:    make(map[string]string),
+		deletedDataSources: make(map[string]bool),
+	}
+
+	existingDataSources, err := listAllDataSources(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range existingDataSources {
+		p.dataSources[ds.Name] = ds
+		p.dataSourceNames[ds.ID] = ds.Name
+	}
+	existingRules, err := listAllRules(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteDataSources := p.planDataSources(existingDataSources, bundle.DataSources)
+	p.planRules(existingRules, bundle.Rules)
+	p.checkDataSourceReferences(existingRules, bundle.Rules)
+	p.steps = append(p.steps, deleteDataSources...)
+
+	if bundle.Routes != nil {
+		calendars, err := repo.NotificationRouting().ListCalendars(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.planRoutes(calendars, bundle.Routes)
+	}
+	if bundle.Silences != nil {
+		snoozes, err := repo.AlertSnooze().ListActiveByUser(ctx, userID, p.now)
+		if err != nil {
+			return nil, err
+		}
+		p.planSilences(snoozes, bundle.Silences)
+	}
+	return p, nil
+}
+
+// withTx 在事务中执行
+func (s *configApplyService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// configPlan 声明式配置的执行计划，先完成全部资源的对比和校验，再按顺序执行变更
+type configPlan struct {
+	repo      repository.RepositoryManager
+	approvals ApprovalGate
+	userID    string
+	prune     bool
+	now       time.Time
+	result    *models.ConfigApplyResult
+	steps     []func(ctx context.Context) error
+
+	// dataSources 应用后的数据源名称到数据源，新数据源预先分配ID供规则引用
+	dataSources map[string]*models.DataSource
+	// dataSourceNames 数据源ID到名称，用于以名称展示规则引用的数据源
+	dataSourceNames map[string]string
+	// deletedDataSources 将被删除的数据源名称
+	deletedDataSources map[string]bool
+}
+
+// err 存在校验失败的资源时返回 ErrConfigBundleInvalid
+func (p *configPlan) err() error {
+	if len(p.result.Issues) > 0 {
+		return models.ErrConfigBundleInvalid
+	}
+	return nil
+}
+
+// requiresApproval 操作是否配置为需要审批
+func (p *configPlan) requiresApproval(action models.ApprovalAction) bool {
+	return p.approvals != nil && p.approvals.RequiresApproval(action)
+}
+
+// record 记录一个资源的变更，有变更时追加执行步骤
+func (p *configPlan) record(change models.ConfigChange, step func(ctx context.Context) error) {
+	p.result.Changes = append(p.result.Changes, change)
+	p.result.Summary[change.Action]++
+	if change.Action != models.ConfigActionUnchanged && step != nil {
+		p.steps = append(p.steps, step)
+	}
+}
+
+// issue 记录一个校验失败的资源
+func (p *configPlan) issue(kind, name string, err error) {
+	p.result.Issues = append(p.result.Issues, models.ConfigApplyIssue{Kind: kind, Name: name, Message: err.Error()})
+}
+
+// planDataSources 对比数据源，返回删除数据源的步骤，删除需要在规则变更之后执行
+func (p *configPlan) planDataSources(existing []*models.DataSource, specs []models.DataSourceSpec) []func(ctx context.Context) error {
+	if specs == nil {
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.Name] = true
+		current := p.dataSources[spec.Name]
+		if current == nil {
+			ds := &models.DataSource{ID: uuid.New().String(), CreatedBy: p.userID}
+			if err := spec.ApplyTo(ds); err != nil {
+				p.issue(models.ConfigKindDataSource, spec.Name, err)
+				continue
+			}
+			p.dataSources[ds.Name] = ds
+			p.dataSourceNames[ds.ID] = ds.Name
+			p.record(models.ConfigChange{
+				Kind:   models.ConfigKindDataSource,
+				Name:   spec.Name,
+				Action: models.ConfigActionCreate,
+				Diff:   configDiff(nil, dataSourceShown(ds), nil, nil),
+			}, func(ctx context.Context) error {
+				if err := p.repo.DataSource().Create(ctx, ds); err != nil {
+					return fmt.Errorf("创建数据源 %s 失败: %w", ds.Name, err)
+				}
+				return nil
+			})
+			continue
+		}
+
+		updated := *current
+		if spec.Config.HasRedacted() {
+			spec.Config.RestoreRedacted(&current.Config)
+		}
+		if err := spec.ApplyTo(&updated); err != nil {
+			p.issue(models.ConfigKindDataSource, spec.Name, err)
+			continue
+		}
+		updatedBy := p.userID
+		updated.UpdatedBy = &updatedBy
+		p.dataSources[updated.Name] = &updated
+		p.recordUpdate(models.ConfigKindDataSource, spec.Name,
+			models.NewDataSourceSpec(current), models.NewDataSourceSpec(&updated),
+			dataSourceShown(current), dataSourceShown(&updated),
+			func(ctx context.Context) error {
+				if err := p.repo.DataSource().Update(ctx, &updated); err != nil {
+					return fmt.Errorf("更新数据源 %s 失败: %w", updated.Name, err)
+				}
+				return nil
+			})
+	}
+
+	if !p.prune {
+		return nil
+	}
+	// 删除数据源需要审批时不能通过 prune 绕过，需逐个提交审批请求
+	gated := p.requiresApproval(models.ApprovalActionDataSourceDelete)
+	var steps []func(ctx context.Context) error
+	for _, ds := range existing {
+		if wanted[ds.Name] {
+			continue
+		}
+		id, name := ds.ID, ds.Name
+		if gated {
+			p.issue(models.ConfigKindDataSource, name, fmt.Errorf("%w: 删除数据源", models.ErrApprovalRequired))
+			continue
+		}
+		p.deletedDataSources[name] = true
+		p.result.Changes = append(p.result.Changes, models.ConfigChange{Kind: models.ConfigKindDataSource, Name: name, Action: models.ConfigActionDelete})
+		p.result.Summary[models.ConfigActionDelete]++
+		steps = append(steps, func(ctx context.Context) error {
+			if err := p.repo.DataSource().SoftDelete(ctx, id); err != nil {
+				return fmt.Errorf("删除数据源 %s 失败: %w", name, err)
+			}
+			return nil
+		})
+	}
+	return steps
+}
+
+// planRules 对比规则，规则引用的数据源按名称在应用后的数据源中查找
+func (p *configPlan) planRules(existing []*models.Rule, specs []models.RuleSpec) {
+	if specs == nil {
+		return
+	}
+
+	current := make(map[string]*models.Rule, len(existing))
+	for _, rule := range existing {
+		current[rule.Name] = rule
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.Name] = true
+		dataSourceID := spec.DataSourceID
+		if spec.DataSource != "" {
+			ds := p.dataSources[spec.DataSource]
+			if ds == nil || p.deletedDataSources[spec.DataSource] {
+				p.issue(models.ConfigKindRule, spec.Name, fmt.Errorf("数据源 %q 不存在", spec.DataSource))
+				continue
+			}
+			dataSourceID = ds.ID
+		}
+
+		rule := current[spec.Name]
+		if rule == nil {
+			created := &models.Rule{CreatedBy: p.userID}
+			if err := spec.ApplyTo(created, dataSourceID); err != nil {
+				p.issue(models.ConfigKindRule, spec.Name, err)
+				continue
+			}
+			p.record(models.ConfigChange{
+				Kind:   models.ConfigKindRule,
+				Name:   spec.Name,
+				Action: models.ConfigActionCreate,
+				Diff:   configDiff(nil, p.ruleSpec(created), nil, nil),
+			}, func(ctx context.Context) error {
+				if err := p.repo.Rule().Create(ctx, created); err != nil {
+					return fmt.Errorf("创建规则 %s 失败: %w", created.Name, err)
+				}
+				return nil
+			})
+			continue
+		}
+
+		updated := *rule
+		if err := spec.ApplyTo(&updated, dataSourceID); err != nil {
+			p.issue(models.ConfigKindRule, spec.Name, err)
+			continue
+		}
+		updatedBy := p.userID
+		updated.UpdatedBy = &updatedBy
+		before, after := p.ruleSpec(rule), p.ruleSpec(&updated)
+		p.recordUpdate(models.ConfigKindRule, spec.Name, before, after, before, after, func(ctx context.Context) error {
+			if err := p.repo.Rule().Update(ctx, &updated); err != nil {
+				return fmt.Errorf("更新规则 %s 失败: %w", updated.Name, err)
+			}
+			return nil
+		})
+	}
+
+	if !p.prune {
+		return
+	}
+	// 批量禁用规则需要审批时，prune 同样不能批量删除规则
+	gated := p.requiresApproval(models.ApprovalActionRulesDisableAll)
+	for _, rule := range existing {
+		if wanted[rule.Name] || rule.Labels[sloRuleLabel] != "" {
+			continue
+		}
+		id, name := rule.ID, rule.Name
+		if gated {
+			p.issue(models.ConfigKindRule, name, fmt.Errorf("%w: 删除规则", models.ErrApprovalRequired))
+			continue
+		}
+		p.record(models.ConfigChange{Kind: models.ConfigKindRule, Name: name, Action: models.ConfigActionDelete}, func(ctx context.Context) error {
+			if err := p.repo.Rule().SoftDelete(ctx, id); err != nil {
+				return fmt.Errorf("删除规则 %s 失败: %w", name, err)
+			}
+			return nil
+		})
+	}
+}
+
+// checkDataSourceReferences 被删除的数据源不能仍被应用后保留的规则引用
+func (p *configPlan) checkDataSourceReferences(existing []*models.Rule, specs []models.RuleSpec) {
+	if len(p.deletedDataSources) == 0 {
+		return
+	}
+
+	// 应用后保留的规则及其引用的数据源名称
+	references := make(map[string]string)
+	for _, rule := range existing {
+		if specs != nil && p.prune && rule.Labels[sloRuleLabel] == "" {
+			continue
+		}
+		references[rule.Name] = p.dataSourceNames[rule.DataSourceID]
+	}
+	for _, spec := range specs {
+		name := spec.DataSource
+		if name == "" {
+			name = p.dataSourceNames[spec.DataSourceID]
+		}
+		references[spec.Name] = name
+	}
+
+	rules := make([]string, 0, len(references))
+	for rule := range references {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		if name := references[rule]; p.deletedDataSources[name] {
+			p.issue(models.ConfigKindDataSource, name, fmt.Errorf("数据源仍被规则 %q 使用，不能删除", rule))
+		}
+	}
+}
+
+// planRoutes 对比通知路由（通知日历），按名称匹配
+func (p *configPlan) planRoutes(existing []*models.NotificationCalendar, specs []models.NotificationCalendarRequest) {
+	current := make(map[string]*models.NotificationCalendar, len(existing))
+	for _, calendar := range existing {
+		if _, ok := current[calendar.Name]; !ok {
+			current[calendar.Name] = calendar
+		}
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for i := range specs {
+		spec := specs[i]
+		wanted[spec.Name] = true
+		if err := spec.Validate(); err != nil {
+			p.issue(models.ConfigKindRoute, spec.Name, err)
+			continue
+		}
+
+		calendar := current[spec.Name]
+		if calendar == nil {
+			created := &models.NotificationCalendar{CreatedBy: p.userID}
+			spec.ApplyTo(created)
+			p.record(models.ConfigChange{
+				Kind:   models.ConfigKindRoute,
+				Name:   spec.Name,
+				Action: models.ConfigActionCreate,
+				Diff:   configDiff(nil, models.NewNotificationCalendarRequest(created), nil, nil),
+			}, func(ctx context.Context) error {
+				if err := p.repo.NotificationRouting().CreateCalendar(ctx, created); err != nil {
+					return fmt.Errorf("创建通知路由 %s 失败: %w", created.Name, err)
+				}
+				return nil
+			})
+			continue
+		}
+
+		updated := *calendar
+		spec.ApplyTo(&updated)
+		before, after := models.NewNotificationCalendarRequest(calendar), models.NewNotificationCalendarRequest(&updated)
+		p.recordUpdate(models.ConfigKindRoute, spec.Name, before, after, before, after, func(ctx context.Context) error {
+			if err := p.repo.NotificationRouting().UpdateCalendar(ctx, &updated); err != nil {
+				return fmt.Errorf("更新通知路由 %s 失败: %w", updated.Name, err)
+			}
+			return nil
+		})
+	}
+
+	if !p.prune {
+		return
+	}
+	for _, calendar := range existing {
+		if wanted[calendar.Name] && current[calendar.Name] == calendar {
+			continue
+		}
+		id, name := calendar.ID, calendar.Name
+		p.record(models.ConfigChange{Kind: models.ConfigKindRoute, Name: name, Action: models.ConfigActionDelete}, func(ctx context.Context) error {
+			if err := p.repo.NotificationRouting().DeleteCalendar(ctx, id); err != nil {
+				return fmt.Errorf("删除通知路由 %s 失败: %w", name, err)
+			}
+			return nil
+		})
+	}
+}
+
+// planSilences 对比当前用户按标签匹配的告警暂停，按单个告警的暂停不受影响。
+// 告警暂停不支持修改，原因或过期时间变化时删除后重新创建
+func (p *configPlan) planSilences(existing []*models.AlertSnooze, specs []models.SilenceSpec) {
+	current := make(map[string]*models.AlertSnooze, len(existing))
+	var managed []*models.AlertSnooze
+	for _, snooze := range existing {
+		if snooze.AlertID != nil {
+			continue
+		}
+		managed = append(managed, snooze)
+		key := models.NewSilenceSpec(snooze).Key()
+		if _, ok := current[key]; !ok {
+			current[key] = snooze
+		}
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		key := spec.Key()
+		wanted[key] = true
+		spec.Reason = strings.TrimSpace(spec.Reason)
+		spec.ExpiresAt = spec.ExpiresAt.UTC().Truncate(time.Second)
+		if err := spec.Validate(p.now); err != nil {
+			p.issue(models.ConfigKindSilence, key, err)
+			continue
+		}
+
+		created := &models.AlertSnooze{UserID: p.userID, Matchers: spec.Matchers, Reason: spec.Reason, ExpiresAt: spec.ExpiresAt}
+		create := func(ctx context.Context) error {
+			if err := p.repo.AlertSnooze().Create(ctx, created); err != nil {
+				return fmt.Errorf("创建告警暂停 %s 失败: %w", key, err)
+			}
+			return nil
+		}
+
+		snooze := current[key]
+		if snooze == nil {
+			p.record(models.ConfigChange{
+				Kind:   models.ConfigKindSilence,
+				Name:   key,
+				Action: models.ConfigActionCreate,
+				Diff:   configDiff(nil, spec, nil, nil),
+			}, create)
+			continue
+		}
+
+		id := snooze.ID
+		before := models.NewSilenceSpec(snooze)
+		p.recordUpdate(models.ConfigKindSilence, key, before, spec, before, spec, func(ctx context.Context) error {
+			if err := p.repo.AlertSnooze().Delete(ctx, id); err != nil {
+				return fmt.Errorf("更新告警暂停 %s 失败: %w", key, err)
+			}
+			return create(ctx)
+		})
+	}
+
+	if !p.prune {
+		return
+	}
+	for _, snooze := range managed {
+		key := models.NewSilenceSpec(snooze).Key()
+		if wanted[key] && current[key] == snooze {
+			continue
+		}
+		id := snooze.ID
+		p.record(models.ConfigChange{Kind: models.ConfigKindSilence, Name: key, Action: models.ConfigActionDelete}, func(ctx context.Context) error {
+			if err := p.repo.AlertSnooze().Delete(ctx, id); err != nil {
+				return fmt.Errorf("删除告警暂停 %s 失败: %w", key, err)
+			}
+			return nil
+		})
+	}
+}
+
+// recordUpdate 对比资源定义，有差异时记录更新，否则记录未变化。
+// before 和 after 用于判断差异，beforeShown 和 afterShown 为展示用的脱敏定义
+func (p *configPlan) recordUpdate(kind, name string, before, after, beforeShown, afterShown interface{}, step func(ctx context.Context) error) {
+	diff := configDiff(before, after, beforeShown, afterShown)
+	action := models.ConfigActionUpdate
+	if len(diff) == 0 {
+		action = models.ConfigActionUnchanged
+	}
+	p.record(models.ConfigChange{Kind: kind, Name: name, Action: action, Diff: diff}, step)
+}
+
+// ruleSpec 生成规则的定义，数据源以名称表示
+func (p *configPlan) ruleSpec(rule *models.Rule) models.RuleSpec {
+	return models.NewRuleSpec(rule, p.dataSourceNames[rule.DataSourceID])
+}
+
+// dataSourceShown 生成展示用的数据源定义，敏感信息已脱敏
+func dataSourceShown(ds *models.DataSource) models.DataSourceSpec {
+	return models.NewDataSourceSpec(ds.Redacted())
+}
+
+// configDiff 按字段比较两个定义，before 为 nil 时列出 after 的全部字段。
+// 判断差异使用 before 和 after，展示的值取自 beforeShown 和 afterShown，为 nil 时直接展示 before 和 after
+func configDiff(before, after, beforeShown, afterShown interface{}) []models.ConfigFieldDiff {
+	if beforeShown == nil {
+		beforeShown = before
+	}
+	if afterShown == nil {
+		afterShown = after
+	}
+	oldFields, newFields := configFields(before), configFields(after)
+	oldShown, newShown := configFields(beforeShown), configFields(afterShown)
+
+	paths := make([]string, 0, len(newFields))
+	for path := range oldFields {
+		paths = append(paths, path)
+	}
+	for path := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	diff := make([]models.ConfigFieldDiff, 0)
+	for _, path := range paths {
+		if reflect.DeepEqual(oldFields[path], newFields[path]) {
+			continue
+		}
+		diff = append(diff, models.ConfigFieldDiff{Field: path, Before: oldShown[path], After: newShown[path]})
+	}
+	return diff
+}
+
+// configFields 将定义按 JSON 编码后展开为字段路径到值的映射，嵌套对象的字段以点号连接。
+// 按 JSON 比较可以忽略 nil 与空集合的差异
+func configFields(v interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if v == nil {
+		return fields
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fields
+	}
+	flattenConfigFields("", doc, fields)
+	return fields
+}
+
+// flattenConfigFields 递归展开嵌套对象
+func flattenConfigFields(prefix string, doc map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range doc {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfigFields(path, nested, fields)
+			continue
+		}
+		fields[path] = value
+	}
+}
+
+// listAllDataSources 分页读取全部数据源
+func listAllDataSources(ctx context.Context, repo repository.RepositoryManager) ([]*models.DataSource, error) {
+	var all []*models.DataSource
+	for page := 1; ; page++ {
+		list, err := repo.DataSource().List(ctx, &models.DataSourceFilter{Page: page, PageSize: configApplyPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("获取数据源列表失败: %w", err)
+		}
+		all = append(all, list.DataSources...)
+		if len(list.DataSources) < configApplyPageSize {
+			return all, nil
+		}
+	}
+}
+
+// listAllRules 分页读取全部规则
+func listAllRules(ctx context.Context, repo repository.RepositoryManager) ([]*models.Rule, error) {
+	var all []*models.Rule
+	for page := 1; ; page++ {
+		list, err := repo.Rule().List(ctx, &models.RuleFilter{Page: page, PageSize: configApplyPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("获取规则列表失败: %w", err)
+		}
+		all = append(all, list.Rules...)
+		if len(list.Rules) < configApplyPageSize {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/redact"
+	"pulse/internal/repository"
+)
+
+// fakeConfigDataSourceRepository 内存中的数据源
+type fakeConfigDataSourceRepository struct {
+	repository.DataSourceRepository
+	dataSources []*models.DataSource
+}
+
+func (r *fakeConfigDataSourceRepository) List(ctx context.Context, filter *models.DataSourceFilter) (*models.DataSourceList, error) {
+	list := &models.DataSourceList{DataSources: []*models.DataSource{}}
+	start := (filter.Page - 1) * filter.PageSize
+	for i := start; i < len(r.dataSources) && i < start+filter.PageSize; i++ {
+		copied := *r.dataSources[i]
+		list.DataSources = append(list.DataSources, &copied)
+	}
+	return list, nil
+}
+
+func (r *fakeConfigDataSourceRepository) Create(ctx context.Context, ds *models.DataSource) error {
+	r.dataSources = append(r.dataSources, ds)
+	return nil
+}
+
+func (r *fakeConfigDataSourceRepository) Update(ctx context.Context, ds *models.DataSource) error {
+	for i, existing := range r.dataSources {
+		if existing.ID == ds.ID {
+			r.dataSources[i] = ds
+		}
+	}
+	return nil
+}
+
+func (r *fakeConfigDataSourceRepository) SoftDelete(ctx context.Context, id string) error {
+	remaining := r.dataSources[:0]
+	for _, ds := range r.dataSources {
+		if ds.ID != id {
+			remaining = append(remaining, ds)
+		}
+	}
+	r.dataSources = remaining
+	return nil
+}
+
+// fakeConfigRuleRepository 内存中的规则
+type fakeConfigRuleRepository struct {
+	repository.RuleRepository
+	rules []*models.Rule
+}
+
+func (r *fakeConfigRuleRepository) List(ctx context.Context, filter *models.RuleFilter) (*models.RuleList, error) {
+	list := &models.RuleList{Rules: []*models.Rule{}}
+	start := (filter.Page - 1) * filter.PageSize
+	for i := start; i < len(r.rules) && i < start+filter.PageSize; i++ {
+		copied := *r.rules[i]
+		list.Rules = append(list.Rules, &copied)
+	}
+	return list, nil
+}
+
+func (r *fakeConfigRuleRepository) Create(ctx context.Context, rule *models.Rule) error {
+	rule.ID = uuid.New().String()
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *fakeConfigRuleRepository) Update(ctx context.Context, rule *models.Rule) error {
+	for i, existing := range r.rules {
+		if existing.ID == rule.ID {
+			r.rules[i] = rule
+		}
+	}
+	return nil
+}
+
+func (r *fakeConfigRuleRepository) SoftDelete(ctx context.Context, id string) error {
+	remaining := r.rules[:0]
+	for _, rule := range r.rules {
+		if rule.ID != id {
+			remaining = append(remaining, rule)
+		}
+	}
+	r.rules = remaining
+	return nil
+}
+
+func (r *fakeConfigRuleRepository) byName(name string) *models.Rule {
+	for _, rule := range r.rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// fakeConfigRoutingRepository 内存中的通知日历
+type fakeConfigRoutingRepository struct {
+	repository.NotificationRoutingRepository
+	calendars []*models.NotificationCalendar
+}
+
+func (r *fakeConfigRoutingRepository) ListCalendars(ctx context.Context) ([]*models.NotificationCalendar, error) {
+	return r.calendars, nil
+}
+
+func (r *fakeConfigRoutingRepository) CreateCalendar(ctx context.Context, calendar *models.NotificationCalendar) error {
+	if calendar.ID == "" {
+		calendar.ID = uuid.New().String()
+	}
+	r.calendars = append(r.calendars, calendar)
+	return nil
+}
+
+type configApplyRepoManager struct {
+	*MockRepositoryManager
+	dataSources *fakeConfigDataSourceRepository
+	rules       *fakeConfigRuleRepository
+	routing     *fakeConfigRoutingRepository
+	snoozes     *fakeAlertSnoozeRepository
+}
+
+func (m *configApplyRepoManager) DataSource() repository.DataSourceRepository { return m.dataSources }
+
+func (m *configApplyRepoManager) Rule() repository.RuleRepository { return m.rules }
+
+func (m *configApplyRepoManager) NotificationRouting() repository.NotificationRoutingRepository {
+	return m.routing
+}
+
+func (m *configApplyRepoManager) AlertSnooze() repository.AlertSnoozeRepository { return m.snoozes }
+
+func (m *configApplyRepoManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
+	return m, nil
+}
+
+func newConfigApplyTestRepoManager() *configApplyRepoManager {
+	password := "s3cret"
+	return &configApplyRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		dataSources: &fakeConfigDataSourceRepository{dataSources: []*models.DataSource{{
+			ID: "ds-prom", Name: "prometheus", Description: "主 Prometheus", Type: models.DataSourceTypePrometheus,
+			Status: models.DataSourceStatusActive, Tags: []string{}, CreatedBy: "admin",
+			Config: models.DataSourceConfig{URL: "http://prometheus:9090", Password: &password},
+		}}},
+		rules: &fakeConfigRuleRepository{rules: []*models.Rule{
+			{
+				ID: "r-cpu", DataSourceID: "ds-prom", Name: "HighCPU", Description: "CPU 使用率过高",
+				Type: models.RuleTypeMetric, Status: models.RuleStatusActive, Enabled: true,
+				Severity: models.AlertSeverityHigh, Expression: "cpu > 0.9", EvaluationInterval: time.Minute,
+				ForDuration: 5 * time.Minute, CreatedBy: "admin",
+			},
+			{
+				ID: "r-slo", DataSourceID: "ds-prom", Name: "SLO checkout 错误预算快速消耗", Description: "由 SLO 自动生成",
+				Type: models.RuleTypeMetric, Status: models.RuleStatusActive, Enabled: true,
+				Severity: models.AlertSeverityCritical, Expression: "burn > 14.4", EvaluationInterval: time.Minute,
+				Labels: map[string]string{sloRuleLabel: "slo-1"}, CreatedBy: "system",
+			},
+		}},
+		routing: &fakeConfigRoutingRepository{},
+		snoozes: &fakeAlertSnoozeRepository{snoozes: map[string]*models.AlertSnooze{}},
+	}
+}
+
+func testConfigBundle(expiresAt time.Time) *models.ConfigBundle {
+	return &models.ConfigBundle{
+		Version: models.ConfigBundleVersion,
+		DataSources: []models.DataSourceSpec{
+			// 密码使用 API 返回的脱敏值，按已保存的值对比
+			{Name: "prometheus", Description: "主 Prometheus", Type: models.DataSourceTypePrometheus,
+				Config: models.DataSourceConfig{URL: "http://prometheus:9090", Password: strPtr(redact.Mask)}},
+			{Name: "loki", Description: "日志", Type: models.DataSourceTypeCustom,
+				Config: models.DataSourceConfig{URL: "http://loki:3100", Token: strPtr("loki-token")}},
+		},
+		Rules: []models.RuleSpec{
+			{Name: "HighCPU", Description: "CPU 使用率过高", Type: models.RuleTypeMetric, Severity: models.AlertSeverityHigh,
+				Enabled: true, DataSource: "prometheus", Expression: "cpu > 0.95", EvaluationInterval: "1m", For: "5m"},
+			{Name: "ErrorLogs", Description: "错误日志过多", Type: models.RuleTypeLog, Severity: models.AlertSeverityMedium,
+				Enabled: true, DataSource: "loki", Expression: "count(error) > 10", EvaluationInterval: "30s"},
+		},
+		Routes: []models.NotificationCalendarRequest{{
+			Name: "支付团队", Team: "payments", DeferSeverities: []models.AlertSeverity{models.AlertSeverityLow},
+			BusinessCalendar: models.BusinessCalendar{BusinessHours: models.BusinessHours{Days: []string{"monday"}, Start: "09:00", End: "18:00"}},
+		}},
+		Silences: []models.SilenceSpec{{Matchers: map[string]string{"env": "staging"}, Reason: "压测", ExpiresAt: expiresAt}},
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func changeActions(result *models.ConfigApplyResult) map[string]string {
+	actions := make(map[string]string, len(result.Changes))
+	for _, change := range result.Changes {
+		actions[change.Kind+"/"+change.Name] = change.Action
+	}
+	return actions
+}
+
+func TestConfigApplyService_DryRunAndApply(t *testing.T) {
+	repoManager := newConfigApplyTestRepoManager()
+	svc := NewConfigApplyService(repoManager, nil, zap.NewNop())
+	ctx := context.Background()
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	bundle := testConfigBundle(expiresAt)
+
+	// 试运行只返回变更，不写入
+	result, err := svc.Apply(ctx, bundle, models.ConfigApplyOptions{DryRun: true, Prune: true}, "u1")
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, map[string]string{
+		"data_source/prometheus": models.ConfigActionUnchanged,
+		"data_source/loki":       models.ConfigActionCreate,
+		"rule/HighCPU":           models.ConfigActionUpdate,
+		"rule/ErrorLogs":         models.ConfigActionCreate,
+		"route/支付团队":             models.ConfigActionCreate,
+		"silence/env=staging":    models.ConfigActionCreate,
+	}, changeActions(result))
+	assert.Len(t, repoManager.dataSources.dataSources, 1)
+	assert.Len(t, repoManager.rules.rules, 2)
+	for _, change := range result.Changes {
+		switch change.Kind + "/" + change.Name {
+		case "rule/HighCPU":
+			assert.Equal(t, []models.ConfigFieldDiff{{Field: "expression", Before: "cpu > 0.9", After: "cpu > 0.95"}}, change.Diff)
+		case "data_source/loki":
+			// 新数据源的敏感字段同样脱敏展示
+			assert.Contains(t, change.Diff, models.ConfigFieldDiff{Field: "config.token", After: redact.Mask})
+		}
+	}
+
+	// 正式执行，SLO 生成的规则不会被删除
+	result, err = svc.Apply(ctx, bundle, models.ConfigApplyOptions{Prune: true}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Summary[models.ConfigActionCreate])
+	require.Len(t, repoManager.dataSources.dataSources, 2)
+	loki := repoManager.dataSources.dataSources[1]
+	assert.Equal(t, "loki", loki.Name)
+	assert.Equal(t, "u1", loki.CreatedBy)
+	assert.Equal(t, loki.ID, repoManager.rules.byName("ErrorLogs").DataSourceID)
+	assert.Equal(t, "cpu > 0.95", repoManager.rules.byName("HighCPU").Expression)
+	assert.Equal(t, "s3cret", *repoManager.dataSources.dataSources[0].Config.Password)
+	assert.NotNil(t, repoManager.rules.byName("SLO checkout 错误预算快速消耗"))
+	require.Len(t, repoManager.routing.calendars, 1)
+	assert.Equal(t, "payments", repoManager.routing.calendars[0].Team)
+	snoozes, _ := repoManager.snoozes.ListActiveByUser(ctx, "u1", time.Now())
+	require.Len(t, snoozes, 1)
+	assert.True(t, expiresAt.Equal(snoozes[0].ExpiresAt))
+
+	// 再次执行相同配置不产生变更
+	result, err = svc.Apply(ctx, bundle, models.ConfigApplyOptions{Prune: true}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{models.ConfigActionUnchanged: 6}, result.Summary)
+
+	// 省略的资源类型不受 prune 影响，列出的空列表删除全部
+	result, err = svc.Apply(ctx, &models.ConfigBundle{Silences: []models.SilenceSpec{}}, models.ConfigApplyOptions{Prune: true}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"silence/env=staging": models.ConfigActionDelete}, changeActions(result))
+	assert.Len(t, repoManager.rules.rules, 3)
+	snoozes, _ = repoManager.snoozes.ListActiveByUser(ctx, "u1", time.Now())
+	assert.Empty(t, snoozes)
+}
+
+func TestConfigApplyService_ValidationIssues(t *testing.T) {
+	repoManager := newConfigApplyTestRepoManager()
+	svc := NewConfigApplyService(repoManager, nil, zap.NewNop())
+	ctx := context.Background()
+
+	// 删除仍被规则引用的数据源、引用不存在的数据源和无效的时长都在执行前报告
+	bundle := &models.ConfigBundle{
+		DataSources: []models.DataSourceSpec{},
+		Rules: []models.RuleSpec{
+			{Name: "Orphan", Description: "d", Type: models.RuleTypeMetric, Severity: models.AlertSeverityLow,
+				DataSource: "missing", Expression: "up == 0", EvaluationInterval: "1m"},
+			{Name: "BadInterval", Description: "d", Type: models.RuleTypeMetric, Severity: models.AlertSeverityLow,
+				DataSourceID: "ds-prom", Expression: "up == 0", EvaluationInterval: "soon"},
+		},
+		Silences: []models.SilenceSpec{{Matchers: map[string]string{"env": "prod"}, ExpiresAt: time.Now().Add(-time.Hour)}},
+	}
+	result, err := svc.Apply(ctx, bundle, models.ConfigApplyOptions{Prune: true}, "u1")
+	assert.ErrorIs(t, err, models.ErrConfigBundleInvalid)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	require.NotNil(t, result)
+
+	issues := make(map[string]bool)
+	for _, issue := range result.Issues {
+		issues[issue.Kind+"/"+issue.Name] = true
+	}
+	assert.Equal(t, map[string]bool{
+		"rule/Orphan":            true,
+		"rule/BadInterval":       true,
+		"data_source/prometheus": true,
+		"silence/env=prod":       true,
+	}, issues)
+	assert.Len(t, repoManager.dataSources.dataSources, 1)
+	assert.Len(t, repoManager.rules.rules, 2)
+
+	_, err = svc.Apply(ctx, &models.ConfigBundle{Version: "pulse/v2"}, models.ConfigApplyOptions{}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Apply(ctx, &models.ConfigBundle{Rules: []models.RuleSpec{{Name: "a"}, {Name: "a"}}}, models.ConfigApplyOptions{}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+// fakeApprovalGate 按操作配置是否需要审批
+type fakeApprovalGate map[models.ApprovalAction]bool
+
+func (g fakeApprovalGate) RequiresApproval(action models.ApprovalAction) bool { return g[action] }
+
+func TestConfigApplyService_PruneRequiresApproval(t *testing.T) {
+	repoManager := newConfigApplyTestRepoManager()
+	gate := fakeApprovalGate{models.ApprovalActionDataSourceDelete: true, models.ApprovalActionRulesDisableAll: true}
+	svc := NewConfigApplyService(repoManager, gate, zap.NewNop())
+	ctx := context.Background()
+
+	// 需要审批的删除不能通过 prune 绕过，试运行和正式执行都报告为校验失败，不做任何变更
+	bundle := &models.ConfigBundle{DataSources: []models.DataSourceSpec{}, Rules: []models.RuleSpec{}}
+	for _, opts := range []models.ConfigApplyOptions{{DryRun: true, Prune: true}, {Prune: true}} {
+		result, err := svc.Apply(ctx, bundle, opts, "u1")
+		assert.ErrorIs(t, err, models.ErrConfigBundleInvalid)
+		require.NotNil(t, result)
+		issues := make(map[string]string)
+		for _, issue := range result.Issues {
+			issues[issue.Kind+"/"+issue.Name] = issue.Message
+		}
+		require.Len(t, issues, 2)
+		assert.Contains(t, issues["data_source/prometheus"], models.ErrApprovalRequired.Error())
+		assert.Contains(t, issues["rule/HighCPU"], models.ErrApprovalRequired.Error())
+	}
+	assert.Len(t, repoManager.dataSources.dataSources, 1)
+	assert.Len(t, repoManager.rules.rules, 2)
+
+	// 只有删除数据源需要审批时，prune 仍可删除规则
+	delete(gate, models.ApprovalActionRulesDisableAll)
+	result, err := svc.Apply(ctx, &models.ConfigBundle{Rules: []models.RuleSpec{}}, models.ConfigApplyOptions{Prune: true}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"rule/HighCPU": models.ConfigActionDelete}, changeActions(result))
+	assert.Nil(t, repoManager.rules.byName("HighCPU"))
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// customFieldService 自定义字段服务实现
+type customFieldService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewCustomFieldService 创建自定义字段服务实例
+func NewCustomFieldService(repoManager repository.RepositoryManager, logger *zap.Logger) CustomFieldService {
+	return &customFieldService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// Create 创建自定义字段定义
+func (s *customFieldService) Create(ctx context.Context, def *models.CustomFieldDefinition) error {
+	if def == nil {
+		return fmt.Errorf("自定义字段定义不能为空")
+	}
+
+	if err := def.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrInvalidInput, err.Error())
+	}
+
+	// 同一资源下字段键唯一
+	exists, err := s.repoManager.CustomField().ExistsByKey(ctx, def.Resource, def.Key)
+	if err != nil {
+		s.logger.Error("检查自定义字段是否存在失败", zap.Error(err), zap.String("key", def.Key))
+		return fmt.Errorf("检查自定义字段是否存在失败: %w", err)
+	}
+	if exists {
+		return models.ErrCustomFieldExists
+	}
+
+	if err := s.repoManager.CustomField().Create(ctx, def); err != nil {
+		s.logger.Error("创建自定义字段定义失败", zap.Error(err), zap.String("key", def.Key))
+		return fmt.Errorf("创建自定义字段定义失败: %w", err)
+	}
+
+	s.logger.Info("自定义字段定义创建成功", zap.String("id", def.ID), zap.String("resource", string(def.Resource)), zap.String("key", def.Key))
+	return nil
+}
+
+// GetByID 根据ID获取自定义字段定义
+func (s *customFieldService) GetByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error) {
+	if id == "" {
+		return nil, fmt.Errorf("自定义字段ID不能为空")
+	}
+
+	def, err := s.repoManager.CustomField().GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrCustomFieldNotFound) {
+			return nil, err
+		}
+		s.logger.Error("获取自定义字段定义失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("获取自定义字段定义失败: %w", err)
+	}
+
+	return def, nil
+}
+
+// List 获取自定义字段定义列表
+func (s *customFieldService) List(ctx context.Context, filter *models.CustomFieldDefinitionFilter) ([]*models.CustomFieldDefinition, int64, error) {
+	list, err := s.repoManager.CustomField().List(ctx, filter)
+	if err != nil {
+		s.logger.Error("获取自定义字段定义列表失败", zap.Error(err))
+		return nil, 0, fmt.Errorf("获取自定义字段定义列表失败: %w", err)
+	}
+
+	return list.Definitions, list.Total, nil
+}
+
+// Update 更新自定义字段定义
+func (s *customFieldService) Update(ctx context.Context, def *models.CustomFieldDefinition) error {
+	if def == nil {
+		return fmt.Errorf("自定义字段定义不能为空")
+	}
+	if def.ID == "" {
+		return fmt.Errorf("自定义字段ID不能为空")
+	}
+
+	existing, err := s.GetByID(ctx, def.ID)
+	if err != nil {
+		return err
+	}
+
+	// 字段键、资源类型和字段类型不可修改
+	if def.Resource != existing.Resource || def.Key != existing.Key || def.Type != existing.Type {
+		return fmt.Errorf("%w: 字段键、资源类型和字段类型创建后不可修改", models.ErrInvalidInput)
+	}
+
+	if err := def.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrInvalidInput, err.Error())
+	}
+
+	if err := s.repoManager.CustomField().Update(ctx, def); err != nil {
+		s.logger.Error("更新自定义字段定义失败", zap.Error(err), zap.String("id", def.ID))
+		return fmt.Errorf("更新自定义字段定义失败: %w", err)
+	}
+
+	s.logger.Info("自定义字段定义更新成功", zap.String("id", def.ID))
+	return nil
+}
+
+// Delete 删除自定义字段定义
+func (s *customFieldService) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("自定义字段ID不能为空")
+	}
+
+	if err := s.repoManager.CustomField().Delete(ctx, id); err != nil {
+		if errors.Is(err, models.ErrCustomFieldNotFound) {
+			return err
+		}
+		s.logger.Error("删除自定义字段定义失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("删除自定义字段定义失败: %w", err)
+	}
+
+	s.logger.Info("自定义字段定义删除成功", zap.String("id", id))
+	return nil
+}
+
+// GetFormSchema 获取资源类型的动态表单字段定义
+func (s *customFieldService) GetFormSchema(ctx context.Context, resource models.CustomFieldResource) ([]*models.CustomFieldDefinition, error) {
+	if !resource.IsValid() {
+		return nil, fmt.Errorf("%w: 无效的资源类型", models.ErrInvalidInput)
+	}
+
+	definitions, err := s.repoManager.CustomField().ListByResource(ctx, resource)
+	if err != nil {
+		s.logger.Error("获取自定义字段定义失败", zap.Error(err), zap.String("resource", string(resource)))
+		return nil, fmt.Errorf("获取自定义字段定义失败: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// Validate 按字段定义校验自定义字段，并返回填充默认值后的结果
+func (s *customFieldService) Validate(ctx context.Context, resource models.CustomFieldResource, values map[string]interface{}) (map[string]interface{}, error) {
+	definitions, err := s.GetFormSchema(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	values = models.ApplyCustomFieldDefaults(definitions, values)
+	if err := models.ValidateCustomFields(definitions, values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
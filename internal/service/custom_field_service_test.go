@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeCustomFieldRepository 内存中的自定义字段定义
+type fakeCustomFieldRepository struct {
+	repository.CustomFieldRepository
+	definitions []*models.CustomFieldDefinition
+}
+
+func (r *fakeCustomFieldRepository) ExistsByKey(ctx context.Context, resource models.CustomFieldResource, key string) (bool, error) {
+	for _, def := range r.definitions {
+		if def.Resource == resource && def.Key == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeCustomFieldRepository) Create(ctx context.Context, def *models.CustomFieldDefinition) error {
+	r.definitions = append(r.definitions, def)
+	return nil
+}
+
+type customFieldRepoManager struct {
+	*MockRepositoryManager
+	fields *fakeCustomFieldRepository
+}
+
+func (m *customFieldRepoManager) CustomField() repository.CustomFieldRepository { return m.fields }
+
+func TestCustomFieldService_OnlyTicketResource(t *testing.T) {
+	fields := &fakeCustomFieldRepository{}
+	svc := NewCustomFieldService(&customFieldRepoManager{MockRepositoryManager: &MockRepositoryManager{}, fields: fields}, zap.NewNop())
+	ctx := context.Background()
+
+	require.NoError(t, svc.Create(ctx, &models.CustomFieldDefinition{Resource: models.CustomFieldResourceTicket, Key: "region", Name: "区域", Type: models.CustomFieldTypeText}))
+
+	// 告警和知识库不校验自定义字段值，不能为其定义字段
+	for _, resource := range []models.CustomFieldResource{"alert", "knowledge"} {
+		err := svc.Create(ctx, &models.CustomFieldDefinition{Resource: resource, Key: "region", Name: "区域", Type: models.CustomFieldTypeText})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+		_, err = svc.GetFormSchema(ctx, resource)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	}
+	assert.Len(t, fields.definitions, 1)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// 仪表盘统计参数
+const (
+	dashboardCacheKey = "dashboard:summary"
+
+	// dashboardCacheTTL 首页轮询频繁，短时缓存合并多个用户的请求
+	dashboardCacheTTL = 15 * time.Second
+
+	// dashboardSLAAtRiskWithin 距SLA截止时间不足该时长的工单视为有超时风险，与工单SLA状态口径一致
+	dashboardSLAAtRiskWithin = 2 * time.Hour
+
+	// dashboardTopRulesWindow 统计告警最多规则的时间窗口
+	dashboardTopRulesWindow = 24 * time.Hour
+
+	dashboardRecentIncidentLimit = 10
+	dashboardTopRuleLimit        = 10
+)
+
+// dashboardService 首页仪表盘服务实现
+type dashboardService struct {
+	repoManager repository.RepositoryManager
+	cache       cache.Cache
+	logger      *zap.Logger
+}
+
+// NewDashboardService 创建首页仪表盘服务实例
+func NewDashboardService(repoManager repository.RepositoryManager, summaryCache cache.Cache, logger *zap.Logger) DashboardService {
+	return &dashboardService{
+		repoManager: repoManager,
+		cache:       summaryCache,
+		logger:      logger,
+	}
+}
+
+// GetSummary 获取首页仪表盘汇总数据，各项统计并行查询，任一失败时返回错误
+func (s *dashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	if summary := s.cached(ctx); summary != nil {
+		return summary, nil
+	}
+
+	repo := s.repoManager.Dashboard()
+	now := time.Now()
+	summary := &models.DashboardSummary{GeneratedAt: now}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	run := func(name string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("获取%s失败: %w", name, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	run("活跃告警统计", func() error {
+		bySeverity, err := repo.GetActiveAlertsBySeverity(ctx)
+		if err != nil {
+			return err
+		}
+		summary.ActiveAlerts = models.DashboardAlertSummary{BySeverity: bySeverity}
+		for _, count := range bySeverity {
+			summary.ActiveAlerts.Total += count
+		}
+		return nil
+	})
+	run("未关闭工单统计", func() error {
+		byPriority, err := repo.GetOpenTicketsByPriority(ctx)
+		if err != nil {
+			return err
+		}
+		summary.OpenTickets = models.DashboardTicketSummary{ByPriority: byPriority}
+		for _, count := range byPriority {
+			summary.OpenTickets.Total += count
+		}
+		return nil
+	})
+	run("SLA风险统计", func() error {
+		sla, err := repo.GetSLASummary(ctx, dashboardSLAAtRiskWithin)
+		if err != nil {
+			return err
+		}
+		summary.SLA = *sla
+		return nil
+	})
+	run("最近事件", func() error {
+		incidents, err := repo.GetRecentIncidents(ctx, dashboardRecentIncidentLimit)
+		if err != nil {
+			return err
+		}
+		summary.RecentIncidents = incidents
+		return nil
+	})
+	run("告警最多的规则", func() error {
+		rules, err := repo.GetTopRules(ctx, now.Add(-dashboardTopRulesWindow), dashboardTopRuleLimit)
+		if err != nil {
+			return err
+		}
+		summary.TopRules = rules
+		return nil
+	})
+	wg.Wait()
+
+	if firstErr != nil {
+		s.logger.Error("获取仪表盘数据失败", zap.Error(firstErr))
+		return nil, firstErr
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, dashboardCacheKey, summary, dashboardCacheTTL); err != nil {
+			s.logger.Warn("写入仪表盘缓存失败", zap.Error(err))
+		}
+	}
+	return summary, nil
+}
+
+// cached 读取缓存的仪表盘数据，未命中或解析失败时返回 nil
+func (s *dashboardService) cached(ctx context.Context) *models.DashboardSummary {
+	if s.cache == nil {
+		return nil
+	}
+
+	cached, err := s.cache.Get(ctx, dashboardCacheKey)
+	if err != nil {
+		s.logger.Warn("读取仪表盘缓存失败", zap.Error(err))
+		return nil
+	}
+	if cached == "" {
+		return nil
+	}
+
+	var summary models.DashboardSummary
+	if err := json.Unmarshal([]byte(cached), &summary); err != nil {
+		return nil
+	}
+	return &summary
+}
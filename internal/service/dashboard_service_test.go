@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeDashboardRepository 返回固定统计结果的仪表盘仓储
+type fakeDashboardRepository struct {
+	calls     atomic.Int32
+	ticketErr error
+}
+
+func (r *fakeDashboardRepository) GetActiveAlertsBySeverity(ctx context.Context) (map[models.AlertSeverity]int64, error) {
+	r.calls.Add(1)
+	return map[models.AlertSeverity]int64{
+		models.AlertSeverityCritical: 2,
+		models.AlertSeverityHigh:     3,
+	}, nil
+}
+
+func (r *fakeDashboardRepository) GetOpenTicketsByPriority(ctx context.Context) (map[models.TicketPriority]int64, error) {
+	r.calls.Add(1)
+	if r.ticketErr != nil {
+		return nil, r.ticketErr
+	}
+	return map[models.TicketPriority]int64{models.TicketPriorityUrgent: 4}, nil
+}
+
+func (r *fakeDashboardRepository) GetSLASummary(ctx context.Context, atRiskWithin time.Duration) (*models.DashboardSLASummary, error) {
+	r.calls.Add(1)
+	return &models.DashboardSLASummary{AtRisk: 1, Breached: 2}, nil
+}
+
+func (r *fakeDashboardRepository) GetRecentIncidents(ctx context.Context, limit int) ([]*models.DashboardIncident, error) {
+	r.calls.Add(1)
+	return []*models.DashboardIncident{{ID: "t1", Number: "INC-1"}}, nil
+}
+
+func (r *fakeDashboardRepository) GetTopRules(ctx context.Context, since time.Time, limit int) ([]*models.DashboardRuleStat, error) {
+	r.calls.Add(1)
+	return []*models.DashboardRuleStat{{RuleID: "r1", Name: "cpu_high", AlertCount: 42}}, nil
+}
+
+type dashboardRepoManager struct {
+	*MockRepositoryManager
+	repo *fakeDashboardRepository
+}
+
+func (m *dashboardRepoManager) Dashboard() repository.DashboardRepository {
+	return m.repo
+}
+
+func TestDashboardService_GetSummary(t *testing.T) {
+	repo := &fakeDashboardRepository{}
+	svc := NewDashboardService(&dashboardRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: repo},
+		cache.NewMemoryCache(), zap.NewNop())
+	ctx := context.Background()
+
+	summary, err := svc.GetSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), summary.ActiveAlerts.Total)
+	assert.Equal(t, int64(4), summary.OpenTickets.Total)
+	assert.Equal(t, int64(2), summary.SLA.Breached)
+	require.Len(t, summary.RecentIncidents, 1)
+	require.Len(t, summary.TopRules, 1)
+	assert.Equal(t, int32(5), repo.calls.Load())
+
+	// 缓存有效期内不再查询数据库
+	cached, err := svc.GetSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), repo.calls.Load())
+	assert.Equal(t, summary.ActiveAlerts, cached.ActiveAlerts)
+	assert.Equal(t, "cpu_high", cached.TopRules[0].Name)
+}
+
+func TestDashboardService_GetSummary_Error(t *testing.T) {
+	repo := &fakeDashboardRepository{ticketErr: errors.New("connection refused")}
+	svc := NewDashboardService(&dashboardRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: repo},
+		cache.NewMemoryCache(), zap.NewNop())
+
+	_, err := svc.GetSummary(context.Background())
+	assert.ErrorContains(t, err, "未关闭工单统计")
+}
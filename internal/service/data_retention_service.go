@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/objectstore"
+	"pulse/internal/repository"
+)
+
+// dataRetentionService 数据保留服务实现
+type dataRetentionService struct {
+	repoManager repository.RepositoryManager
+	store       objectstore.Store
+	alertCfg    config.AlertConfig
+	cfg         config.RetentionConfig
+	logger      *zap.Logger
+}
+
+// NewDataRetentionService 创建数据保留服务实例，store 为 nil 时开启了归档的资源不会被清理
+func NewDataRetentionService(repoManager repository.RepositoryManager, store objectstore.Store, alertCfg config.AlertConfig, cfg config.RetentionConfig, logger *zap.Logger) DataRetentionService {
+	return &dataRetentionService{
+		repoManager: repoManager,
+		store:       store,
+		alertCfg:    alertCfg,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Interval 归档和清理过期记录的周期
+func (s *dataRetentionService) Interval() time.Duration {
+	return s.cfg.ArchiveInterval
+}
+
+// Policies 各资源的保留策略
+func (s *dataRetentionService) Policies() []*models.RetentionPolicy {
+	days := map[models.RetentionResource]int{
+		models.RetentionResourceAlerts:         s.alertCfg.RetentionDays,
+		models.RetentionResourceAlertHistories: s.alertCfg.HistoryRetentionDays,
+		models.RetentionResourceNotifications:  s.cfg.NotificationRetentionDays,
+	}
+
+	policies := make([]*models.RetentionPolicy, 0, len(models.RetentionResources))
+	for _, resource := range models.RetentionResources {
+		policies = append(policies, &models.RetentionPolicy{
+			Resource:       resource,
+			RetentionDays:  days[resource],
+			ArchiveEnabled: s.cfg.ArchiveEnabled,
+		})
+	}
+	return policies
+}
+
+// Status 获取各资源的保留策略、等待清理的记录和已归档的记录
+func (s *dataRetentionService) Status(ctx context.Context) ([]*models.RetentionStatus, error) {
+	now := time.Now()
+	statuses := make([]*models.RetentionStatus, 0, len(models.RetentionResources))
+	for _, policy := range s.Policies() {
+		status, err := s.repoManager.Retention().ArchiveStats(ctx, policy.Resource)
+		if err != nil {
+			return nil, err
+		}
+		status.RetentionPolicy = *policy
+
+		if policy.RetentionDays > 0 {
+			cutoff := policy.Cutoff(now)
+			status.Cutoff = &cutoff
+			status.ExpiredRecords, status.OldestRecordAt, err = s.repoManager.Retention().ExpiredStats(ctx, policy.Resource, cutoff)
+			if err != nil {
+				return nil, err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Run 清理超出保留期的记录，开启归档时每批记录先导出到对象存储再删除。
+// 一个资源失败时继续处理其他资源，返回第一个错误
+func (s *dataRetentionService) Run(ctx context.Context) (*models.RetentionRunResult, error) {
+	now := time.Now()
+	result := &models.RetentionRunResult{}
+
+	var firstErr error
+	for _, policy := range s.Policies() {
+		if policy.RetentionDays <= 0 {
+			continue
+		}
+		if err := s.runPolicy(ctx, policy, policy.Cutoff(now), result); err != nil {
+			s.logger.Error("清理过期记录失败", zap.Error(err), zap.String("resource", string(policy.Resource)))
+			if firstErr == nil {
+				firstErr = fmt.Errorf("清理 %s 的过期记录失败: %w", policy.Resource, err)
+			}
+		}
+	}
+
+	if result.Deleted > 0 {
+		s.logger.Info("过期记录已清理", zap.Int("archives", result.Archives), zap.Int64("archived", result.Archived),
+			zap.Int64("deleted", result.Deleted))
+	}
+	return result, firstErr
+}
+
+// runPolicy 分批归档和删除资源中创建时间早于 cutoff 的记录
+func (s *dataRetentionService) runPolicy(ctx context.Context, policy *models.RetentionPolicy, cutoff time.Time, result *models.RetentionRunResult) error {
+	if policy.ArchiveEnabled && s.store == nil {
+		return fmt.Errorf("未配置可用的对象存储")
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		records, err := s.repoManager.Retention().ListExpired(ctx, policy.Resource, cutoff, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		if policy.ArchiveEnabled {
+			archive, err := s.archive(ctx, policy.Resource, records)
+			if err != nil {
+				return err
+			}
+			result.Archives++
+			result.Archived += archive.RecordCount
+		}
+
+		ids := make([]string, 0, len(records))
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		deleted, err := s.repoManager.Retention().DeleteRecords(ctx, policy.Resource, ids)
+		if err != nil {
+			return err
+		}
+		// 一条都没有删除时停止，避免重复归档同一批记录
+		if deleted == 0 {
+			return fmt.Errorf("归档后未能删除记录")
+		}
+		result.Deleted += deleted
+
+		if len(records) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// archive 将一批记录导出为 gzip 压缩的 NDJSON 上传到对象存储，并记录归档文件
+func (s *dataRetentionService) archive(ctx context.Context, resource models.RetentionResource, records []*models.ArchiveRecord) (*models.DataArchive, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, record := range records {
+		gz.Write(record.Data)
+		gz.Write([]byte("\n"))
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("压缩归档文件失败: %w", err)
+	}
+
+	archive := &models.DataArchive{
+		ID:          uuid.New().String(),
+		Resource:    resource,
+		WindowStart: records[0].CreatedAt,
+		WindowEnd:   records[len(records)-1].CreatedAt,
+		RecordCount: int64(len(records)),
+		SizeBytes:   int64(buf.Len()),
+	}
+	archive.ObjectKey = path.Join(s.cfg.ArchivePrefix, string(resource), archive.WindowStart.UTC().Format("2006/01/02"),
+		archive.ID+".ndjson.gz")
+
+	if err := s.store.Put(ctx, archive.ObjectKey, buf.Bytes(), "application/gzip"); err != nil {
+		return nil, err
+	}
+	if err := s.repoManager.Retention().CreateArchive(ctx, archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// ListArchives 分页获取归档文件
+func (s *dataRetentionService) ListArchives(ctx context.Context, filter *models.DataArchiveFilter) ([]*models.DataArchive, int64, error) {
+	if filter.Resource != nil && !filter.Resource.Valid() {
+		return nil, 0, fmt.Errorf("%w: 不支持的资源 %q", models.ErrInvalidInput, *filter.Resource)
+	}
+	return s.repoManager.Retention().ListArchives(ctx, filter)
+}
+
+// Restore 将与时间窗口有交集的归档文件中创建时间在窗口内的记录写回数据库，已存在的记录跳过。
+// 恢复的记录在 RestoreHoldDays 天内不会被重新归档
+func (s *dataRetentionService) Restore(ctx context.Context, req *models.DataArchiveRestoreRequest, createdBy string) (*models.DataArchiveRestore, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.store == nil {
+		return nil, fmt.Errorf("未配置可用的对象存储")
+	}
+
+	restore := &models.DataArchiveRestore{
+		Resource:    req.Resource,
+		WindowStart: req.From,
+		WindowEnd:   req.To,
+		ExpiresAt:   time.Now().AddDate(0, 0, s.cfg.RestoreHoldDays),
+		CreatedBy:   createdBy,
+	}
+
+	filter := &models.DataArchiveFilter{Resource: &req.Resource, From: &req.From, To: &req.To, Page: 1, PageSize: 1000}
+	for {
+		archives, total, err := s.repoManager.Retention().ListArchives(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, archive := range archives {
+			records, err := s.readArchive(ctx, archive)
+			if err != nil {
+				return nil, err
+			}
+			restored, err := s.repoManager.Retention().RestoreRecords(ctx, req.Resource, records, req.From, req.To)
+			if err != nil {
+				return nil, err
+			}
+			restore.Archives++
+			restore.RestoredRecords += restored
+		}
+
+		if int64(filter.Page*filter.PageSize) >= total {
+			break
+		}
+		filter.Page++
+	}
+
+	if err := s.repoManager.Retention().CreateRestore(ctx, restore); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("归档记录已恢复", zap.String("resource", string(restore.Resource)), zap.Time("from", req.From),
+		zap.Time("to", req.To), zap.Int("archives", restore.Archives), zap.Int64("restored", restore.RestoredRecords))
+	return restore, nil
+}
+
+// readArchive 下载并解压归档文件，返回其中的每条记录
+func (s *dataRetentionService) readArchive(ctx context.Context, archive *models.DataArchive) ([]json.RawMessage, error) {
+	data, err := s.store.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档文件 %s 失败: %w", archive.ObjectKey, err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解压归档文件 %s 失败: %w", archive.ObjectKey, err)
+	}
+	defer gz.Close()
+
+	records := make([]json.RawMessage, 0, archive.RecordCount)
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		records = append(records, json.RawMessage(append([]byte(nil), line...)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解压归档文件 %s 失败: %w", archive.ObjectKey, err)
+	}
+	return records, nil
+}
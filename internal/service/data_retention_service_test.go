@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/objectstore"
+	"pulse/internal/repository"
+)
+
+// fakeRetentionRepository 内存中的记录和归档文件，记录均视为可以清理
+type fakeRetentionRepository struct {
+	records  map[models.RetentionResource]map[string]time.Time
+	archives []*models.DataArchive
+	restores []*models.DataArchiveRestore
+}
+
+func (r *fakeRetentionRepository) ExpiredStats(ctx context.Context, resource models.RetentionResource, cutoff time.Time) (int64, *time.Time, error) {
+	var count int64
+	var oldest *time.Time
+	for _, createdAt := range r.records[resource] {
+		if createdAt.Before(cutoff) {
+			count++
+			if oldest == nil || createdAt.Before(*oldest) {
+				t := createdAt
+				oldest = &t
+			}
+		}
+	}
+	return count, oldest, nil
+}
+
+func (r *fakeRetentionRepository) ListExpired(ctx context.Context, resource models.RetentionResource, cutoff time.Time, limit int) ([]*models.ArchiveRecord, error) {
+	records := make([]*models.ArchiveRecord, 0)
+	for id, createdAt := range r.records[resource] {
+		if createdAt.Before(cutoff) {
+			data, _ := json.Marshal(map[string]interface{}{"id": id, "created_at": createdAt})
+			records = append(records, &models.ArchiveRecord{ID: id, CreatedAt: createdAt, Data: data})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+func (r *fakeRetentionRepository) DeleteRecords(ctx context.Context, resource models.RetentionResource, ids []string) (int64, error) {
+	for _, id := range ids {
+		delete(r.records[resource], id)
+	}
+	return int64(len(ids)), nil
+}
+
+func (r *fakeRetentionRepository) RestoreRecords(ctx context.Context, resource models.RetentionResource, records []json.RawMessage, from, to time.Time) (int64, error) {
+	var restored int64
+	for _, data := range records {
+		var record struct {
+			ID        string    `json:"id"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return 0, err
+		}
+		if _, ok := r.records[resource][record.ID]; ok || record.CreatedAt.Before(from) || !record.CreatedAt.Before(to) {
+			continue
+		}
+		r.records[resource][record.ID] = record.CreatedAt
+		restored++
+	}
+	return restored, nil
+}
+
+func (r *fakeRetentionRepository) CreateArchive(ctx context.Context, archive *models.DataArchive) error {
+	r.archives = append(r.archives, archive)
+	return nil
+}
+
+func (r *fakeRetentionRepository) ListArchives(ctx context.Context, filter *models.DataArchiveFilter) ([]*models.DataArchive, int64, error) {
+	archives := make([]*models.DataArchive, 0)
+	for _, archive := range r.archives {
+		if archive.Resource == *filter.Resource && !archive.WindowEnd.Before(*filter.From) && archive.WindowStart.Before(*filter.To) {
+			archives = append(archives, archive)
+		}
+	}
+	return archives, int64(len(archives)), nil
+}
+
+func (r *fakeRetentionRepository) ArchiveStats(ctx context.Context, resource models.RetentionResource) (*models.RetentionStatus, error) {
+	status := &models.RetentionStatus{}
+	for _, archive := range r.archives {
+		if archive.Resource == resource {
+			status.Archives++
+			status.ArchivedRecords += archive.RecordCount
+		}
+	}
+	return status, nil
+}
+
+func (r *fakeRetentionRepository) CreateRestore(ctx context.Context, restore *models.DataArchiveRestore) error {
+	r.restores = append(r.restores, restore)
+	return nil
+}
+
+type dataRetentionRepoManager struct {
+	*MockRepositoryManager
+	retention *fakeRetentionRepository
+}
+
+func (m *dataRetentionRepoManager) Retention() repository.RetentionRepository { return m.retention }
+
+func TestDataRetentionService_ArchiveAndRestore(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	recent := now.AddDate(0, 0, -1)
+	histories := map[string]time.Time{"recent": recent}
+	for i := 0; i < 5; i++ {
+		histories[fmt.Sprintf("h%d", i)] = now.AddDate(0, 0, -40-i)
+	}
+	from, to := histories["h3"], histories["h0"].Add(time.Second)
+	retention := &fakeRetentionRepository{records: map[models.RetentionResource]map[string]time.Time{
+		models.RetentionResourceAlerts:         {"a1": now.AddDate(-2, 0, 0)},
+		models.RetentionResourceAlertHistories: histories,
+		models.RetentionResourceNotifications:  {"n1": now.AddDate(-2, 0, 0)},
+	}}
+	repoManager := &dataRetentionRepoManager{MockRepositoryManager: &MockRepositoryManager{}, retention: retention}
+	store := objectstore.NewLocalStore(t.TempDir())
+	svc := NewDataRetentionService(repoManager, store, config.AlertConfig{RetentionDays: 365, HistoryRetentionDays: 30},
+		config.RetentionConfig{ArchiveEnabled: true, ArchivePrefix: "archives", BatchSize: 2, RestoreHoldDays: 7}, zap.NewNop())
+	ctx := context.Background()
+
+	statuses, err := svc.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, int64(5), statuses[1].ExpiredRecords)
+	// 通知未配置保留天数，不清理
+	assert.Nil(t, statuses[2].Cutoff)
+
+	// 每批最多两条记录，每批一个归档文件
+	result, err := svc.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Archives)
+	assert.Equal(t, int64(6), result.Deleted)
+	assert.Equal(t, map[string]time.Time{"recent": recent}, retention.records[models.RetentionResourceAlertHistories])
+	assert.Contains(t, retention.records[models.RetentionResourceNotifications], "n1")
+
+	// 只恢复时间窗口内的记录
+	restore, err := svc.Restore(ctx, &models.DataArchiveRestoreRequest{
+		Resource: models.RetentionResourceAlertHistories,
+		From:     from,
+		To:       to,
+	}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), restore.RestoredRecords)
+	assert.Equal(t, 3, restore.Archives)
+	assert.NotContains(t, retention.records[models.RetentionResourceAlertHistories], "h4")
+	assert.Contains(t, retention.records[models.RetentionResourceAlertHistories], "h3")
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), restore.ExpiresAt, time.Minute)
+
+	_, err = svc.Restore(ctx, &models.DataArchiveRestoreRequest{Resource: "users", From: now.AddDate(0, -1, 0), To: now}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
@@ -2,28 +2,37 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
 
 	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
 	"pulse/internal/repository"
 )
 
 // dataSourceService 数据源服务实现
 type dataSourceService struct {
 	repoManager repository.RepositoryManager
+	breakers    *breaker.Registry
 	logger      *zap.Logger
 }
 
-// NewDataSourceService 创建数据源服务实例
-func NewDataSourceService(repoManager repository.RepositoryManager, logger *zap.Logger) DataSourceService {
+// NewDataSourceService 创建数据源服务实例，breakers 为 nil 时连接测试不做熔断保护
+func NewDataSourceService(repoManager repository.RepositoryManager, breakers *breaker.Registry, logger *zap.Logger) DataSourceService {
 	return &dataSourceService{
 		repoManager: repoManager,
+		breakers:    breakers,
 		logger:      logger,
 	}
 }
 
+// dataSourceBreakerName 数据源熔断器名称，健康检查和查询共用同一个熔断器
+func dataSourceBreakerName(id string) string {
+	return "datasource:" + id
+}
+
 // Create 创建数据源
 func (s *dataSourceService) Create(ctx context.Context, dataSource *models.DataSource) error {
 	s.logger.Info("创建数据源", zap.String("name", dataSource.Name), zap.String("type", string(dataSource.Type)))
@@ -67,7 +76,9 @@ func (s *dataSourceService) GetByID(ctx context.Context, id string) (*models.Dat
 		s.logger.Warn("数据源不存在", zap.String("id", id))
 		return nil, fmt.Errorf("数据源不存在: %s", id)
 	}
-	
+
+	status := s.breakers.Status(dataSourceBreakerName(id))
+	dataSource.CircuitBreaker = &status
 	return dataSource, nil
 }
 
@@ -121,6 +132,15 @@ func (s *dataSourceService) Update(ctx context.Context, dataSource *models.DataS
 		return fmt.Errorf("数据源不存在: %s", dataSource.ID)
 	}
 	
+	// 未修改的密码、Token 等以脱敏值提交，从已保存的配置还原
+	if dataSource.Config.HasRedacted() {
+		stored, err := s.repoManager.DataSource().GetByID(ctx, dataSource.ID)
+		if err != nil {
+			return fmt.Errorf("获取数据源失败: %w", err)
+		}
+		dataSource.Config.RestoreRedacted(&stored.Config)
+	}
+	
 	// 验证数据源配置
 	if err := dataSource.Validate(); err != nil {
 		s.logger.Error("数据源配置验证失败", zap.Error(err))
@@ -161,6 +181,7 @@ func (s *dataSourceService) Delete(ctx context.Context, id string) error {
 		s.logger.Error("删除数据源失败", zap.String("id", id), zap.Error(err))
 		return fmt.Errorf("删除数据源失败: %w", err)
 	}
+	s.breakers.Remove(dataSourceBreakerName(id))
 	
 	s.logger.Info("数据源删除成功", zap.String("id", id))
 	return nil
@@ -186,23 +207,30 @@ func (s *dataSourceService) TestConnection(ctx context.Context, id string) error
 		return fmt.Errorf("数据源不存在: %s", id)
 	}
 	
-	// 调用仓储层测试连接
-	testResult, err := s.repoManager.DataSource().TestConnection(ctx, dataSource)
+	// 调用仓储层测试连接，连续失败后熔断，避免不可达的数据源占用健康检查协程
+	err = s.breakers.Get(dataSourceBreakerName(id)).Do(ctx, func(ctx context.Context) error {
+		testResult, err := s.repoManager.DataSource().TestConnection(ctx, dataSource)
+		if err != nil {
+			return err
+		}
+		if testResult != nil && !testResult.Success {
+			errorMsg := "连接测试失败"
+			if testResult.Error != nil {
+				errorMsg = *testResult.Error
+			}
+			return errors.New(errorMsg)
+		}
+		return nil
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		s.logger.Warn("数据源已熔断，跳过连接测试", zap.String("id", id))
+		return fmt.Errorf("数据源连接测试失败: %w", err)
+	}
 	if err != nil {
 		s.logger.Error("数据源连接测试失败", zap.String("id", id), zap.Error(err))
 		return fmt.Errorf("数据源连接测试失败: %w", err)
 	}
 	
-	// 检查测试结果
-	if testResult != nil && !testResult.Success {
-		errorMsg := "连接测试失败"
-		if testResult.Error != nil {
-			errorMsg = *testResult.Error
-		}
-		s.logger.Error("数据源连接测试失败", zap.String("id", id), zap.String("error", errorMsg))
-		return fmt.Errorf("数据源连接测试失败: %s", errorMsg)
-	}
-	
 	s.logger.Info("数据源连接测试成功", zap.String("id", id))
 	return nil
 }
\ No newline at end of file
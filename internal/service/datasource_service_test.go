@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/repository"
+)
+
+// fakeTestConnectionRepository 返回预设的连接测试结果并记录调用次数
+type fakeTestConnectionRepository struct {
+	fakeKubernetesDataSourceRepository
+	success bool
+	calls   int
+	updated *models.DataSource
+}
+
+func (r *fakeTestConnectionRepository) Exists(ctx context.Context, id string) (bool, error) {
+	dataSource, _ := r.GetByID(ctx, id)
+	return dataSource != nil, nil
+}
+
+func (r *fakeTestConnectionRepository) Update(ctx context.Context, dataSource *models.DataSource) error {
+	r.updated = dataSource
+	return nil
+}
+
+func (r *fakeTestConnectionRepository) TestConnection(ctx context.Context, dataSource *models.DataSource) (*models.DataSourceTestResult, error) {
+	r.calls++
+	result := &models.DataSourceTestResult{Success: r.success}
+	if !r.success {
+		errorMsg := "connection refused"
+		result.Error = &errorMsg
+	}
+	return result, nil
+}
+
+type dataSourceRepoManager struct {
+	*MockRepositoryManager
+	dataSources *fakeTestConnectionRepository
+}
+
+func (m *dataSourceRepoManager) DataSource() repository.DataSourceRepository { return m.dataSources }
+
+func TestDataSourceService_TestConnectionCircuitBreaker(t *testing.T) {
+	repo := &fakeTestConnectionRepository{
+		fakeKubernetesDataSourceRepository: fakeKubernetesDataSourceRepository{dataSources: []*models.DataSource{
+			{ID: "prom", Name: "prometheus", Type: models.DataSourceTypePrometheus},
+		}},
+	}
+	breakers := breaker.NewRegistry(breaker.Config{FailureThreshold: 2, OpenTimeout: time.Hour})
+	svc := NewDataSourceService(&dataSourceRepoManager{MockRepositoryManager: &MockRepositoryManager{}, dataSources: repo}, breakers, zap.NewNop())
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		err := svc.TestConnection(ctx, "prom")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+	}
+
+	// 熔断后不再调用数据源
+	err := svc.TestConnection(ctx, "prom")
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.Equal(t, 2, repo.calls)
+
+	dataSource, err := svc.GetByID(ctx, "prom")
+	require.NoError(t, err)
+	require.NotNil(t, dataSource.CircuitBreaker)
+	assert.Equal(t, breaker.StateOpen, dataSource.CircuitBreaker.State)
+	assert.Equal(t, 2, dataSource.CircuitBreaker.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", dataSource.CircuitBreaker.LastError)
+}
+
+func TestDataSourceService_UpdateRestoresRedactedSecrets(t *testing.T) {
+	password, token := "s3cret", "abc123"
+	repo := &fakeTestConnectionRepository{
+		fakeKubernetesDataSourceRepository: fakeKubernetesDataSourceRepository{dataSources: []*models.DataSource{{
+			ID: "es", Name: "elasticsearch", Description: "logs", CreatedBy: "admin",
+			Type: models.DataSourceTypeElastic, Status: models.DataSourceStatusActive,
+			Config: models.DataSourceConfig{
+				URL:      "http://elastic:s3cret@es:9200",
+				Password: &password,
+				Token:    &token,
+				Headers:  map[string]string{"Authorization": "Bearer abc123", "X-Tenant": "ops"},
+			},
+		}}},
+	}
+	svc := NewDataSourceService(&dataSourceRepoManager{MockRepositoryManager: &MockRepositoryManager{}, dataSources: repo}, nil, zap.NewNop())
+
+	// 客户端提交详情接口返回的脱敏配置，只修改了 Token
+	stored, err := svc.GetByID(context.Background(), "es")
+	require.NoError(t, err)
+	update := stored.Redacted()
+	assert.Equal(t, "http://elastic:******@es:9200", update.Config.URL)
+	assert.Equal(t, "******", *update.Config.Password)
+	assert.Equal(t, "******", update.Config.Headers["Authorization"])
+	newToken := "def456"
+	update.Config.Token = &newToken
+
+	require.NoError(t, svc.Update(context.Background(), update))
+	require.NotNil(t, repo.updated)
+	assert.Equal(t, "http://elastic:s3cret@es:9200", repo.updated.Config.URL)
+	assert.Equal(t, "s3cret", *repo.updated.Config.Password)
+	assert.Equal(t, "def456", *repo.updated.Config.Token)
+	assert.Equal(t, "Bearer abc123", repo.updated.Config.Headers["Authorization"])
+	assert.Equal(t, "ops", repo.updated.Config.Headers["X-Tenant"])
+}
@@ -67,6 +67,27 @@ func (s *encryptionService) EncryptDataSourceConfig(config *models.DataSourceCon
 		config.Token = &encryptedToken
 	}
 
+	// 加密代理地址
+	if config.ProxyURL != nil && *config.ProxyURL != "" && !crypto.IsEncrypted(*config.ProxyURL) {
+		encryptedProxyURL, err := s.encryptor.Encrypt(*config.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt proxy url: %w", err)
+		}
+		config.ProxyURL = &encryptedProxyURL
+	}
+
+	// 加密客户端证书和私钥
+	for _, field := range []**string{&config.ClientCert, &config.ClientKey} {
+		if *field == nil || **field == "" || crypto.IsEncrypted(**field) {
+			continue
+		}
+		encrypted, err := s.encryptor.Encrypt(**field)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt client certificate: %w", err)
+		}
+		*field = &encrypted
+	}
+
 	// 加密Headers中的敏感信息
 	for key, value := range config.Headers {
 		if s.isSensitiveKey(key) && !crypto.IsEncrypted(value) {
@@ -118,6 +139,27 @@ func (s *encryptionService) DecryptDataSourceConfig(config *models.DataSourceCon
 		config.Token = &decryptedToken
 	}
 
+	// 解密代理地址
+	if config.ProxyURL != nil && *config.ProxyURL != "" && crypto.IsEncrypted(*config.ProxyURL) {
+		decryptedProxyURL, err := s.encryptor.Decrypt(*config.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt proxy url: %w", err)
+		}
+		config.ProxyURL = &decryptedProxyURL
+	}
+
+	// 解密客户端证书和私钥
+	for _, field := range []**string{&config.ClientCert, &config.ClientKey} {
+		if *field == nil || **field == "" || !crypto.IsEncrypted(**field) {
+			continue
+		}
+		decrypted, err := s.encryptor.Decrypt(**field)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt client certificate: %w", err)
+		}
+		*field = &decrypted
+	}
+
 	// 解密Headers中的敏感信息
 	for key, value := range config.Headers {
 		if s.isSensitiveKey(key) && crypto.IsEncrypted(value) {
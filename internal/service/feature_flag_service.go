@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// 功能开关缓存参数
+// 缓存所有开关的快照，修改时主动失效；TTL 限制 Redis 降级为本地缓存时各实例间的不一致时长
+const (
+	featureFlagCacheKey = "feature_flags:all"
+	featureFlagCacheTTL = 30 * time.Second
+)
+
+// featureFlagService 功能开关服务实现
+type featureFlagService struct {
+	repoManager repository.RepositoryManager
+	cache       cache.Cache
+	logger      *zap.Logger
+
+	mu       sync.RWMutex
+	defaults map[string]bool
+}
+
+// NewFeatureFlagService 创建功能开关服务实例
+// defaults 为配置文件中的 FEATURE_FLAGS，数据库中未定义的开关以其为准
+func NewFeatureFlagService(repoManager repository.RepositoryManager, flagCache cache.Cache, defaults []string, logger *zap.Logger) FeatureFlagService {
+	s := &featureFlagService{
+		repoManager: repoManager,
+		cache:       flagCache,
+		logger:      logger,
+	}
+	s.SetDefaults(defaults)
+	return s
+}
+
+// SetDefaults 更新配置文件中的默认开关，配置热更新时调用
+func (s *featureFlagService) SetDefaults(flags []string) {
+	defaults := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			defaults[flag] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.defaults = defaults
+	s.mu.Unlock()
+}
+
+// List 获取功能开关列表
+func (s *featureFlagService) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	flags, err := s.repoManager.FeatureFlag().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取功能开关列表失败: %w", err)
+	}
+	return flags, nil
+}
+
+// Get 获取功能开关
+func (s *featureFlagService) Get(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	flag, err := s.repoManager.FeatureFlag().GetByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, models.ErrFeatureFlagNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取功能开关失败: %w", err)
+	}
+	return flag, nil
+}
+
+// Create 创建功能开关
+func (s *featureFlagService) Create(ctx context.Context, flag *models.FeatureFlag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repoManager.FeatureFlag().Create(ctx, flag); err != nil {
+		if errors.Is(err, models.ErrFeatureFlagExists) {
+			return err
+		}
+		s.logger.Error("创建功能开关失败", zap.Error(err), zap.String("flag", flag.Key))
+		return fmt.Errorf("创建功能开关失败: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("功能开关已创建",
+		zap.String("flag", flag.Key),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Int("rollout_percentage", flag.RolloutPercentage))
+	return nil
+}
+
+// Update 更新功能开关
+func (s *featureFlagService) Update(ctx context.Context, flag *models.FeatureFlag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repoManager.FeatureFlag().Update(ctx, flag); err != nil {
+		if errors.Is(err, models.ErrFeatureFlagNotFound) {
+			return err
+		}
+		s.logger.Error("更新功能开关失败", zap.Error(err), zap.String("flag", flag.Key))
+		return fmt.Errorf("更新功能开关失败: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("功能开关已更新",
+		zap.String("flag", flag.Key),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Int("rollout_percentage", flag.RolloutPercentage))
+	return nil
+}
+
+// Delete 删除功能开关，删除后回退到配置文件中的默认值
+func (s *featureFlagService) Delete(ctx context.Context, key string) error {
+	if err := s.repoManager.FeatureFlag().Delete(ctx, key); err != nil {
+		if errors.Is(err, models.ErrFeatureFlagNotFound) {
+			return err
+		}
+		return fmt.Errorf("删除功能开关失败: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("功能开关已删除", zap.String("flag", key))
+	return nil
+}
+
+// IsEnabled 评估功能开关对指定用户和租户是否启用
+// 读取开关失败时回退到配置文件中的默认值，避免开关存储故障影响主流程
+func (s *featureFlagService) IsEnabled(ctx context.Context, key string, evalCtx models.FeatureFlagContext) bool {
+	flags, err := s.load(ctx)
+	if err != nil {
+		s.logger.Warn("读取功能开关失败，使用默认配置", zap.Error(err), zap.String("flag", key))
+		return s.defaultEnabled(key)
+	}
+
+	if flag, ok := flags[key]; ok {
+		return flag.Evaluate(evalCtx)
+	}
+	return s.defaultEnabled(key)
+}
+
+// Evaluate 评估所有功能开关对指定用户和租户的结果，包含配置文件中的默认开关
+func (s *featureFlagService) Evaluate(ctx context.Context, evalCtx models.FeatureFlagContext) (map[string]bool, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("读取功能开关失败: %w", err)
+	}
+
+	s.mu.RLock()
+	result := make(map[string]bool, len(flags)+len(s.defaults))
+	for key := range s.defaults {
+		result[key] = true
+	}
+	s.mu.RUnlock()
+
+	for key, flag := range flags {
+		result[key] = flag.Evaluate(evalCtx)
+	}
+	return result, nil
+}
+
+// load 获取所有功能开关，优先读取缓存
+func (s *featureFlagService) load(ctx context.Context) (map[string]*models.FeatureFlag, error) {
+	if s.cache != nil {
+		cached, err := s.cache.Get(ctx, featureFlagCacheKey)
+		if err != nil {
+			s.logger.Warn("读取功能开关缓存失败", zap.Error(err))
+		} else if cached != "" {
+			var flags []*models.FeatureFlag
+			if err := json.Unmarshal([]byte(cached), &flags); err == nil {
+				return indexFeatureFlags(flags), nil
+			}
+		}
+	}
+
+	flags, err := s.repoManager.FeatureFlag().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, featureFlagCacheKey, flags, featureFlagCacheTTL); err != nil {
+			s.logger.Warn("写入功能开关缓存失败", zap.Error(err))
+		}
+	}
+	return indexFeatureFlags(flags), nil
+}
+
+// invalidate 清除功能开关缓存，使修改立即对所有实例生效
+func (s *featureFlagService) invalidate(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Del(ctx, featureFlagCacheKey); err != nil {
+		s.logger.Warn("清除功能开关缓存失败", zap.Error(err))
+	}
+}
+
+// defaultEnabled 判断配置文件中是否启用了功能开关
+func (s *featureFlagService) defaultEnabled(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.defaults[key]
+}
+
+// indexFeatureFlags 按键索引功能开关
+func indexFeatureFlags(flags []*models.FeatureFlag) map[string]*models.FeatureFlag {
+	index := make(map[string]*models.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		index[flag.Key] = flag
+	}
+	return index
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeFeatureFlagRepository 内存功能开关仓储
+type fakeFeatureFlagRepository struct {
+	repository.FeatureFlagRepository
+	flags     map[string]*models.FeatureFlag
+	listCalls int
+	listErr   error
+}
+
+func (r *fakeFeatureFlagRepository) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	r.listCalls++
+	if r.listErr != nil {
+		return nil, r.listErr
+	}
+	flags := make([]*models.FeatureFlag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		copied := *flag
+		flags = append(flags, &copied)
+	}
+	return flags, nil
+}
+
+func (r *fakeFeatureFlagRepository) Update(ctx context.Context, flag *models.FeatureFlag) error {
+	if _, ok := r.flags[flag.Key]; !ok {
+		return models.ErrFeatureFlagNotFound
+	}
+	copied := *flag
+	r.flags[flag.Key] = &copied
+	return nil
+}
+
+type featureFlagRepoManager struct {
+	*MockRepositoryManager
+	repo *fakeFeatureFlagRepository
+}
+
+func (m *featureFlagRepoManager) FeatureFlag() repository.FeatureFlagRepository {
+	return m.repo
+}
+
+func newTestFeatureFlagService(repo *fakeFeatureFlagRepository, defaults ...string) FeatureFlagService {
+	repoManager := &featureFlagRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: repo}
+	return NewFeatureFlagService(repoManager, cache.NewMemoryCache(), defaults, zap.NewNop())
+}
+
+func TestFeatureFlagService_IsEnabled(t *testing.T) {
+	repo := &fakeFeatureFlagRepository{flags: map[string]*models.FeatureFlag{
+		"off":     {Key: "off", Enabled: false, RolloutPercentage: 100, Users: []string{"u1"}},
+		"all":     {Key: "all", Enabled: true, RolloutPercentage: 100},
+		"tenant":  {Key: "tenant", Enabled: true, RolloutPercentage: 100, Tenants: []string{"sre"}},
+		"allow":   {Key: "allow", Enabled: true, RolloutPercentage: 0, Users: []string{"u1"}},
+		"partial": {Key: "partial", Enabled: true, RolloutPercentage: 50},
+	}}
+	svc := newTestFeatureFlagService(repo, "legacy_env_flag")
+	ctx := context.Background()
+
+	u1 := models.FeatureFlagContext{UserID: "u1", Tenant: "dev"}
+	u2 := models.FeatureFlagContext{UserID: "u2", Tenant: "sre"}
+
+	assert.False(t, svc.IsEnabled(ctx, "off", u1))
+	assert.True(t, svc.IsEnabled(ctx, "all", u1))
+	assert.False(t, svc.IsEnabled(ctx, "tenant", u1))
+	assert.True(t, svc.IsEnabled(ctx, "tenant", u2))
+	assert.True(t, svc.IsEnabled(ctx, "allow", u1))
+	assert.False(t, svc.IsEnabled(ctx, "allow", u2))
+	assert.True(t, svc.IsEnabled(ctx, "legacy_env_flag", u2))
+	assert.False(t, svc.IsEnabled(ctx, "unknown", u2))
+
+	// 同一用户的灰度结果稳定，整体比例接近配置值
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		evalCtx := models.FeatureFlagContext{UserID: fmt.Sprintf("user-%d", i)}
+		first := svc.IsEnabled(ctx, "partial", evalCtx)
+		assert.Equal(t, first, svc.IsEnabled(ctx, "partial", evalCtx))
+		if first {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 500, enabled, 100)
+
+	// 开关快照已缓存，只查询一次数据库
+	assert.Equal(t, 1, repo.listCalls)
+}
+
+func TestFeatureFlagService_UpdateInvalidatesCache(t *testing.T) {
+	repo := &fakeFeatureFlagRepository{flags: map[string]*models.FeatureFlag{
+		"rule_engine_v2": {Key: "rule_engine_v2", Enabled: false},
+	}}
+	svc := newTestFeatureFlagService(repo)
+	ctx := context.Background()
+	user := models.FeatureFlagContext{UserID: "u1"}
+
+	assert.False(t, svc.IsEnabled(ctx, "rule_engine_v2", user))
+
+	require.NoError(t, svc.Update(ctx, &models.FeatureFlag{Key: "rule_engine_v2", Enabled: true, RolloutPercentage: 100}))
+	assert.True(t, svc.IsEnabled(ctx, "rule_engine_v2", user))
+	assert.Equal(t, 2, repo.listCalls)
+
+	err := svc.Update(ctx, &models.FeatureFlag{Key: "rule_engine_v2", RolloutPercentage: 101})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestFeatureFlagService_FallsBackToDefaults(t *testing.T) {
+	repo := &fakeFeatureFlagRepository{listErr: errors.New("connection refused")}
+	svc := newTestFeatureFlagService(repo, "legacy_env_flag")
+	ctx := context.Background()
+
+	assert.True(t, svc.IsEnabled(ctx, "legacy_env_flag", models.FeatureFlagContext{}))
+	assert.False(t, svc.IsEnabled(ctx, "rule_engine_v2", models.FeatureFlagContext{}))
+
+	svc.SetDefaults([]string{"rule_engine_v2"})
+	assert.True(t, svc.IsEnabled(ctx, "rule_engine_v2", models.FeatureFlagContext{}))
+	assert.False(t, svc.IsEnabled(ctx, "legacy_env_flag", models.FeatureFlagContext{}))
+}
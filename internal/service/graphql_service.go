@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/dataloader"
+	"pulse/internal/pkg/graphql"
+	"pulse/internal/repository"
+)
+
+// graphqlMaxPageSize 列表字段每页的最大条数，与 REST 接口一致
+const graphqlMaxPageSize = 100
+
+// graphqlService GraphQL 查询服务实现，Schema 在创建时构建，关联数据按请求批量加载
+type graphqlService struct {
+	repoManager  repository.RepositoryManager
+	knowledgeACL KnowledgeACLService
+	logger       *zap.Logger
+	schema       *graphql.Schema
+}
+
+// NewGraphQLService 创建 GraphQL 查询服务实例
+func NewGraphQLService(repoManager repository.RepositoryManager, knowledgeACL KnowledgeACLService, logger *zap.Logger) GraphQLService {
+	s := &graphqlService{
+		repoManager:  repoManager,
+		knowledgeACL: knowledgeACL,
+		logger:       logger,
+	}
+	s.schema = s.buildSchema()
+	return s
+}
+
+// graphqlRequestKey 请求上下文中 graphqlRequest 的键
+type graphqlRequestKey struct{}
+
+// graphqlRequest 单次查询的状态，Loader 只在本次查询内缓存，避免不同用户之间共享数据
+type graphqlRequest struct {
+	userID         string
+	alerts         *dataloader.Loader[string, *models.Alert]
+	rules          *dataloader.Loader[string, *models.Rule]
+	ticketsByAlert *dataloader.Loader[string, []*models.Ticket]
+	comments       *dataloader.Loader[string, []*models.TicketComment]
+}
+
+// Execute 以 userID 的身份执行查询，知识库按访问控制过滤
+func (s *graphqlService) Execute(ctx context.Context, req *graphql.Request, userID string) *graphql.Response {
+	ctx = context.WithValue(ctx, graphqlRequestKey{}, s.newRequest(userID))
+	resp := s.schema.Execute(ctx, req)
+	if len(resp.Errors) > 0 {
+		s.logger.Debug("GraphQL 查询返回错误",
+			zap.String("operation", req.OperationName), zap.String("error", resp.Errors[0].Message))
+	}
+	return resp
+}
+
+// SDL 返回 Schema 定义
+func (s *graphqlService) SDL() string {
+	return s.schema.SDL()
+}
+
+// newRequest 创建本次查询的 Loader，每个 Loader 在一层字段解析完后用一次批量查询加载所有登记的键
+func (s *graphqlService) newRequest(userID string) *graphqlRequest {
+	return &graphqlRequest{
+		userID: userID,
+		alerts: dataloader.New(func(ctx context.Context, ids []string) (map[string]*models.Alert, error) {
+			alerts, err := s.repoManager.Alert().ListByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*models.Alert, len(alerts))
+			for _, alert := range alerts {
+				result[alert.ID] = alert
+			}
+			return result, nil
+		}),
+		rules: dataloader.New(func(ctx context.Context, ids []string) (map[string]*models.Rule, error) {
+			rules, err := s.repoManager.Rule().ListByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*models.Rule, len(rules))
+			for _, rule := range rules {
+				result[rule.ID] = rule
+			}
+			return result, nil
+		}),
+		ticketsByAlert: dataloader.New(func(ctx context.Context, alertIDs []string) (map[string][]*models.Ticket, error) {
+			tickets, err := s.repoManager.Ticket().ListByAlertIDs(ctx, alertIDs)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string][]*models.Ticket, len(alertIDs))
+			for _, id := range alertIDs {
+				result[id] = []*models.Ticket{}
+			}
+			for _, ticket := range tickets {
+				if ticket.AlertID != nil {
+					result[*ticket.AlertID] = append(result[*ticket.AlertID], ticket)
+				}
+			}
+			return result, nil
+		}),
+		comments: dataloader.New(func(ctx context.Context, ticketIDs []string) (map[string][]*models.TicketComment, error) {
+			comments, err := s.repoManager.Ticket().ListCommentsByTicketIDs(ctx, ticketIDs)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string][]*models.TicketComment, len(ticketIDs))
+			for _, id := range ticketIDs {
+				result[id] = []*models.TicketComment{}
+			}
+			for _, comment := range comments {
+				result[comment.TicketID] = append(result[comment.TicketID], comment)
+			}
+			return result, nil
+		}),
+	}
+}
+
+func graphqlRequestFrom(ctx context.Context) *graphqlRequest {
+	return ctx.Value(graphqlRequestKey{}).(*graphqlRequest)
+}
+
+// graphqlThunk 将 Loader 的取值函数转换为 graphql.Thunk
+func graphqlThunk[V any](load func() (V, error)) graphql.Thunk {
+	return func() (interface{}, error) {
+		return load()
+	}
+}
+
+// buildSchema 构建 Schema，告警、工单、规则和知识库的字段名与 REST 接口的 JSON 字段一致
+func (s *graphqlService) buildSchema() *graphql.Schema {
+	nonNullString := graphql.NewNonNull(graphql.String)
+	stringList := graphql.NewList(nonNullString)
+
+	rule := graphql.NewObject("Rule", "告警规则",
+		&graphql.Field{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		&graphql.Field{Name: "name", Type: nonNullString},
+		&graphql.Field{Name: "description", Type: graphql.String},
+		&graphql.Field{Name: "type", Type: graphql.String},
+		&graphql.Field{Name: "status", Type: graphql.String},
+		&graphql.Field{Name: "enabled", Type: graphql.Boolean},
+		&graphql.Field{Name: "severity", Type: graphql.String},
+		&graphql.Field{Name: "expression", Type: graphql.String},
+		&graphql.Field{Name: "labels", Type: graphql.JSON},
+		&graphql.Field{Name: "annotations", Type: graphql.JSON},
+		&graphql.Field{Name: "data_source_id", Type: graphql.ID},
+		&graphql.Field{Name: "last_eval_at", Type: graphql.Time},
+		&graphql.Field{Name: "eval_count", Type: graphql.Int},
+		&graphql.Field{Name: "alert_count", Type: graphql.Int},
+		&graphql.Field{Name: "created_at", Type: graphql.Time},
+		&graphql.Field{Name: "updated_at", Type: graphql.Time},
+	)
+
+	comment := graphql.NewObject("TicketComment", "工单评论",
+		&graphql.Field{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		&graphql.Field{Name: "ticket_id", Type: graphql.ID},
+		&graphql.Field{Name: "author_id", Type: graphql.ID},
+		&graphql.Field{Name: "content", Type: graphql.String},
+		&graphql.Field{Name: "is_internal", Type: graphql.Boolean},
+		&graphql.Field{Name: "created_at", Type: graphql.Time},
+		&graphql.Field{Name: "updated_at", Type: graphql.Time},
+	)
+
+	alert := graphql.NewObject("Alert", "告警",
+		&graphql.Field{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		&graphql.Field{Name: "rule_id", Type: graphql.ID},
+		&graphql.Field{Name: "data_source_id", Type: graphql.ID},
+		&graphql.Field{Name: "name", Type: nonNullString},
+		&graphql.Field{Name: "description", Type: graphql.String},
+		&graphql.Field{Name: "severity", Type: graphql.String},
+		&graphql.Field{Name: "status", Type: graphql.String},
+		&graphql.Field{Name: "source", Type: graphql.String},
+		&graphql.Field{Name: "labels", Type: graphql.JSON},
+		&graphql.Field{Name: "annotations", Type: graphql.JSON},
+		&graphql.Field{Name: "value", Type: graphql.Float},
+		&graphql.Field{Name: "threshold", Type: graphql.Float},
+		&graphql.Field{Name: "fingerprint", Type: graphql.String},
+		&graphql.Field{Name: "starts_at", Type: graphql.Time},
+		&graphql.Field{Name: "ends_at", Type: graphql.Time},
+		&graphql.Field{Name: "acked_by", Type: graphql.ID},
+		&graphql.Field{Name: "acked_at", Type: graphql.Time},
+		&graphql.Field{Name: "resolved_by", Type: graphql.ID},
+		&graphql.Field{Name: "resolved_at", Type: graphql.Time},
+		&graphql.Field{Name: "created_at", Type: graphql.Time},
+		&graphql.Field{Name: "updated_at", Type: graphql.Time},
+		&graphql.Field{Name: "rule", Type: rule, Description: "触发告警的规则",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				ruleID := p.Source.(*models.Alert).RuleID
+				if ruleID == nil {
+					return nil, nil
+				}
+				return graphqlThunk(graphqlRequestFrom(p.Context).rules.Load(p.Context, *ruleID)), nil
+			}},
+	)
+
+	ticket := graphql.NewObject("Ticket", "工单",
+		&graphql.Field{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		&graphql.Field{Name: "number", Type: nonNullString},
+		&graphql.Field{Name: "title", Type: nonNullString},
+		&graphql.Field{Name: "description", Type: graphql.String},
+		&graphql.Field{Name: "status", Type: graphql.String},
+		&graphql.Field{Name: "priority", Type: graphql.String},
+		&graphql.Field{Name: "category", Type: graphql.String},
+		&graphql.Field{Name: "type", Type: graphql.String},
+		&graphql.Field{Name: "source", Type: graphql.String},
+		&graphql.Field{Name: "tags", Type: stringList},
+		&graphql.Field{Name: "alert_id", Type: graphql.ID},
+		&graphql.Field{Name: "reporter_id", Type: graphql.ID},
+		&graphql.Field{Name: "assignee_id", Type: graphql.ID},
+		&graphql.Field{Name: "due_date", Type: graphql.Time},
+		&graphql.Field{Name: "sla_deadline", Type: graphql.Time},
+		&graphql.Field{Name: "resolved_at", Type: graphql.Time},
+		&graphql.Field{Name: "closed_at", Type: graphql.Time},
+		&graphql.Field{Name: "created_at", Type: graphql.Time},
+		&graphql.Field{Name: "updated_at", Type: graphql.Time},
+		&graphql.Field{Name: "alert", Type: alert, Description: "工单关联的告警",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				alertID := p.Source.(*models.Ticket).AlertID
+				if alertID == nil {
+					return nil, nil
+				}
+				return graphqlThunk(graphqlRequestFrom(p.Context).alerts.Load(p.Context, *alertID)), nil
+			}},
+		&graphql.Field{Name: "comments", Type: graphql.NewList(graphql.NewNonNull(comment)), Description: "工单评论，按创建时间正序",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id := p.Source.(*models.Ticket).ID
+				return graphqlThunk(graphqlRequestFrom(p.Context).comments.Load(p.Context, id)), nil
+			}},
+	)
+
+	// 告警和工单相互引用，工单类型创建后再追加告警的工单字段
+	alert.AddFields(&graphql.Field{Name: "tickets", Type: graphql.NewList(graphql.NewNonNull(ticket)), Description: "告警关联的工单，按创建时间倒序",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id := p.Source.(*models.Alert).ID
+			return graphqlThunk(graphqlRequestFrom(p.Context).ticketsByAlert.Load(p.Context, id)), nil
+		}})
+
+	knowledge := graphql.NewObject("Knowledge", "知识库文章",
+		&graphql.Field{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		&graphql.Field{Name: "title", Type: nonNullString},
+		&graphql.Field{Name: "slug", Type: graphql.String},
+		&graphql.Field{Name: "summary", Type: graphql.String},
+		&graphql.Field{Name: "content", Type: graphql.String},
+		&graphql.Field{Name: "type", Type: graphql.String},
+		&graphql.Field{Name: "status", Type: graphql.String},
+		&graphql.Field{Name: "tags", Type: stringList},
+		&graphql.Field{Name: "author_id", Type: graphql.ID},
+		&graphql.Field{Name: "view_count", Type: graphql.Int},
+		&graphql.Field{Name: "created_at", Type: graphql.Time},
+		&graphql.Field{Name: "updated_at", Type: graphql.Time},
+	)
+
+	paged := func(args ...*graphql.Argument) []*graphql.Argument {
+		return append(args,
+			&graphql.Argument{Name: "page", Type: graphql.Int, DefaultValue: 1},
+			&graphql.Argument{Name: "page_size", Type: graphql.Int, DefaultValue: 20, Description: "每页条数，最多 100"},
+		)
+	}
+	idArg := []*graphql.Argument{{Name: "id", Type: graphql.NewNonNull(graphql.ID)}}
+
+	query := graphql.NewObject("Query", "",
+		&graphql.Field{Name: "alert", Type: alert, Args: idArg,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return graphqlThunk(graphqlRequestFrom(p.Context).alerts.Load(p.Context, p.Args["id"].(string))), nil
+			}},
+		&graphql.Field{Name: "alerts", Type: graphql.NewObject("AlertList", "",
+			&graphql.Field{Name: "total", Type: graphql.NewNonNull(graphql.Int)},
+			&graphql.Field{Name: "alerts", Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alert)))},
+		), Args: paged(
+			&graphql.Argument{Name: "status", Type: graphql.String},
+			&graphql.Argument{Name: "severity", Type: graphql.String},
+			&graphql.Argument{Name: "q", Type: graphql.String, Description: "过滤表达式，与告警列表接口的 q 参数相同"},
+		), Resolve: s.resolveAlerts},
+		&graphql.Field{Name: "ticket", Type: ticket, Args: idArg,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return s.repoManager.Ticket().GetByID(p.Context, p.Args["id"].(string))
+			}},
+		&graphql.Field{Name: "tickets", Type: graphql.NewObject("TicketList", "",
+			&graphql.Field{Name: "total", Type: graphql.NewNonNull(graphql.Int)},
+			&graphql.Field{Name: "tickets", Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ticket)))},
+		), Args: paged(
+			&graphql.Argument{Name: "status", Type: graphql.String},
+			&graphql.Argument{Name: "priority", Type: graphql.String},
+			&graphql.Argument{Name: "assignee_id", Type: graphql.ID},
+			&graphql.Argument{Name: "q", Type: graphql.String, Description: "过滤表达式，与工单列表接口的 q 参数相同"},
+		), Resolve: s.resolveTickets},
+		&graphql.Field{Name: "rule", Type: rule, Args: idArg,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return graphqlThunk(graphqlRequestFrom(p.Context).rules.Load(p.Context, p.Args["id"].(string))), nil
+			}},
+		&graphql.Field{Name: "rules", Type: graphql.NewObject("RuleList", "",
+			&graphql.Field{Name: "total", Type: graphql.NewNonNull(graphql.Int)},
+			&graphql.Field{Name: "rules", Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rule)))},
+		), Args: paged(
+			&graphql.Argument{Name: "status", Type: graphql.String},
+			&graphql.Argument{Name: "severity", Type: graphql.String},
+			&graphql.Argument{Name: "keyword", Type: graphql.String},
+		), Resolve: s.resolveRules},
+		&graphql.Field{Name: "knowledge", Type: knowledge, Args: idArg, Description: "没有访问权限时返回错误",
+			Resolve: s.resolveKnowledge},
+		&graphql.Field{Name: "knowledge_list", Type: graphql.NewObject("KnowledgeList", "",
+			&graphql.Field{Name: "total", Type: graphql.NewNonNull(graphql.Int)},
+			&graphql.Field{Name: "knowledge", Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(knowledge)))},
+		), Args: paged(
+			&graphql.Argument{Name: "status", Type: graphql.String},
+			&graphql.Argument{Name: "keyword", Type: graphql.String},
+		), Description: "只返回有访问权限的文章", Resolve: s.resolveKnowledgeList},
+	)
+	return graphql.NewSchema(query)
+}
+
+// graphqlPage 读取分页参数
+func graphqlPage(args map[string]interface{}) (int, int, error) {
+	page, pageSize := args["page"].(int), args["page_size"].(int)
+	if page < 1 {
+		return 0, 0, fmt.Errorf("%w: page 不能小于 1", models.ErrInvalidInput)
+	}
+	if pageSize < 1 || pageSize > graphqlMaxPageSize {
+		return 0, 0, fmt.Errorf("%w: page_size 必须在 1 到 %d 之间", models.ErrInvalidInput, graphqlMaxPageSize)
+	}
+	return page, pageSize, nil
+}
+
+// graphqlStringArg 读取可选的字符串参数，未传或为 null 时返回 nil
+func graphqlStringArg[T ~string](args map[string]interface{}, name string) *T {
+	value, ok := args[name].(string)
+	if !ok {
+		return nil
+	}
+	v := T(value)
+	return &v
+}
+
+func (s *graphqlService) resolveAlerts(p graphql.ResolveParams) (interface{}, error) {
+	page, pageSize, err := graphqlPage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repoManager.Alert().List(p.Context, &models.AlertFilter{
+		Status:   graphqlStringArg[models.AlertStatus](p.Args, "status"),
+		Severity: graphqlStringArg[models.AlertSeverity](p.Args, "severity"),
+		Query:    graphqlStringArg[string](p.Args, "q"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if list.Alerts == nil {
+		list.Alerts = []*models.Alert{}
+	}
+	return list, nil
+}
+
+func (s *graphqlService) resolveTickets(p graphql.ResolveParams) (interface{}, error) {
+	page, pageSize, err := graphqlPage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repoManager.Ticket().List(p.Context, &models.TicketFilter{
+		Status:     graphqlStringArg[models.TicketStatus](p.Args, "status"),
+		Priority:   graphqlStringArg[models.TicketPriority](p.Args, "priority"),
+		AssigneeID: graphqlStringArg[string](p.Args, "assignee_id"),
+		Query:      graphqlStringArg[string](p.Args, "q"),
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if list.Tickets == nil {
+		list.Tickets = []*models.Ticket{}
+	}
+	return list, nil
+}
+
+func (s *graphqlService) resolveRules(p graphql.ResolveParams) (interface{}, error) {
+	page, pageSize, err := graphqlPage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repoManager.Rule().List(p.Context, &models.RuleFilter{
+		Status:   graphqlStringArg[models.RuleStatus](p.Args, "status"),
+		Severity: graphqlStringArg[models.AlertSeverity](p.Args, "severity"),
+		Keyword:  graphqlStringArg[string](p.Args, "keyword"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if list.Rules == nil {
+		list.Rules = []*models.Rule{}
+	}
+	return list, nil
+}
+
+// resolveKnowledge 获取知识库文章，先检查读权限
+func (s *graphqlService) resolveKnowledge(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(string)
+	if err := s.knowledgeACL.CheckAccess(p.Context, id, graphqlRequestFrom(p.Context).userID, models.KnowledgeACLPermissionRead); err != nil {
+		return nil, err
+	}
+	return s.repoManager.Knowledge().GetByID(p.Context, id)
+}
+
+// resolveKnowledgeList 获取知识库列表，非管理员只返回有访问权限的文章
+func (s *graphqlService) resolveKnowledgeList(p graphql.ResolveParams) (interface{}, error) {
+	page, pageSize, err := graphqlPage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repoManager.Knowledge().List(p.Context, &models.KnowledgeFilter{
+		Status:   graphqlStringArg[models.KnowledgeStatus](p.Args, "status"),
+		Keyword:  graphqlStringArg[string](p.Args, "keyword"),
+		ViewerID: s.knowledgeACL.ViewerScope(p.Context, graphqlRequestFrom(p.Context).userID),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if list.Knowledge == nil {
+		list.Knowledge = []*models.Knowledge{}
+	}
+	return list, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/graphql"
+	"pulse/internal/repository"
+)
+
+// graphqlAlertRepository 记录每次查询的参数，用于确认关联数据按层批量加载
+type graphqlAlertRepository struct {
+	repository.AlertRepository
+	alerts []*models.Alert
+	calls  [][]string
+}
+
+func (r *graphqlAlertRepository) List(ctx context.Context, filter *models.AlertFilter) (*models.AlertList, error) {
+	return &models.AlertList{Alerts: r.alerts, Total: int64(len(r.alerts))}, nil
+}
+
+func (r *graphqlAlertRepository) ListByIDs(ctx context.Context, ids []string) ([]*models.Alert, error) {
+	r.calls = append(r.calls, ids)
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		for _, id := range ids {
+			if alert.ID == id {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return alerts, nil
+}
+
+type graphqlRuleRepository struct {
+	repository.RuleRepository
+	calls [][]string
+}
+
+func (r *graphqlRuleRepository) ListByIDs(ctx context.Context, ids []string) ([]*models.Rule, error) {
+	r.calls = append(r.calls, ids)
+	var rules []*models.Rule
+	for _, id := range ids {
+		rules = append(rules, &models.Rule{ID: id, Name: "rule-" + id})
+	}
+	return rules, nil
+}
+
+type graphqlTicketRepository struct {
+	repository.TicketRepository
+	tickets      []*models.Ticket
+	ticketCalls  [][]string
+	commentCalls [][]string
+}
+
+func (r *graphqlTicketRepository) ListByAlertIDs(ctx context.Context, alertIDs []string) ([]*models.Ticket, error) {
+	r.ticketCalls = append(r.ticketCalls, alertIDs)
+	var tickets []*models.Ticket
+	for _, ticket := range r.tickets {
+		for _, id := range alertIDs {
+			if *ticket.AlertID == id {
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets, nil
+}
+
+func (r *graphqlTicketRepository) ListCommentsByTicketIDs(ctx context.Context, ticketIDs []string) ([]*models.TicketComment, error) {
+	r.commentCalls = append(r.commentCalls, ticketIDs)
+	var comments []*models.TicketComment
+	for _, id := range ticketIDs {
+		comments = append(comments, &models.TicketComment{ID: "c-" + id, TicketID: id, Content: "note on " + id})
+	}
+	return comments, nil
+}
+
+type graphqlRepoManager struct {
+	*MockRepositoryManager
+	alerts  *graphqlAlertRepository
+	rules   *graphqlRuleRepository
+	tickets *graphqlTicketRepository
+}
+
+func (m *graphqlRepoManager) Alert() repository.AlertRepository   { return m.alerts }
+func (m *graphqlRepoManager) Rule() repository.RuleRepository     { return m.rules }
+func (m *graphqlRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func TestGraphQLService_BatchesRelationsPerLevel(t *testing.T) {
+	ruleA, ruleB := "r1", "r2"
+	alert1, alert2 := "a1", "a2"
+	repos := &graphqlRepoManager{
+		alerts: &graphqlAlertRepository{alerts: []*models.Alert{
+			{ID: "a1", Name: "APIDown", RuleID: &ruleA},
+			{ID: "a2", Name: "DiskFull", RuleID: &ruleB},
+			{ID: "a3", Name: "Manual"},
+		}},
+		rules: &graphqlRuleRepository{},
+		tickets: &graphqlTicketRepository{tickets: []*models.Ticket{
+			{ID: "t1", Number: "T-1", Title: "api", AlertID: &alert1},
+			{ID: "t2", Number: "T-2", Title: "disk", AlertID: &alert2},
+			{ID: "t3", Number: "T-3", Title: "api again", AlertID: &alert1},
+		}},
+	}
+	svc := NewGraphQLService(repos, nil, zap.NewNop())
+
+	resp := svc.Execute(context.Background(), &graphql.Request{Query: `{
+		alerts(page_size: 10) {
+			total
+			alerts {
+				name
+				rule { name }
+				tickets { number comments { content } alert { name } }
+			}
+		}
+	}`}, "u1")
+	require.Empty(t, resp.Errors)
+
+	data, err := json.Marshal(resp.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"alerts":{"total":3,"alerts":[
+		{"name":"APIDown","rule":{"name":"rule-r1"},"tickets":[
+			{"number":"T-1","comments":[{"content":"note on t1"}],"alert":{"name":"APIDown"}},
+			{"number":"T-3","comments":[{"content":"note on t3"}],"alert":{"name":"APIDown"}}]},
+		{"name":"DiskFull","rule":{"name":"rule-r2"},"tickets":[
+			{"number":"T-2","comments":[{"content":"note on t2"}],"alert":{"name":"DiskFull"}}]},
+		{"name":"Manual","rule":null,"tickets":[]}]}}`, string(data))
+
+	// 每种关联只查询一次，同一层重复的键只加载一次
+	assert.Equal(t, [][]string{{"r1", "r2"}}, repos.rules.calls)
+	assert.Equal(t, [][]string{{"a1", "a2", "a3"}}, repos.tickets.ticketCalls)
+	require.Len(t, repos.tickets.commentCalls, 1)
+	comments := repos.tickets.commentCalls[0]
+	sort.Strings(comments)
+	assert.Equal(t, []string{"t1", "t2", "t3"}, comments)
+	assert.Equal(t, [][]string{{"a1", "a2"}}, repos.alerts.calls)
+}
+
+func TestGraphQLService_InvalidPageSize(t *testing.T) {
+	repos := &graphqlRepoManager{alerts: &graphqlAlertRepository{}}
+	svc := NewGraphQLService(repos, nil, zap.NewNop())
+
+	resp := svc.Execute(context.Background(), &graphql.Request{Query: `{ alerts(page_size: 500) { total } }`}, "u1")
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "page_size 必须在 1 到 100 之间")
+	assert.Nil(t, resp.Data.Get("alerts"))
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// handoverManualWindow 手动生成且团队没有历史报告时的默认班次时长
+const handoverManualWindow = 12 * time.Hour
+
+// handoverService 交班报告服务实现
+type handoverService struct {
+	repoManager   repository.RepositoryManager
+	knowledge     KnowledgeService
+	notifications NotificationService
+	cfg           config.HandoverConfig
+	logger        *zap.Logger
+}
+
+// NewHandoverService 创建交班报告服务实例
+func NewHandoverService(repoManager repository.RepositoryManager, knowledge KnowledgeService, notifications NotificationService, cfg config.HandoverConfig, logger *zap.Logger) HandoverService {
+	return &handoverService{
+		repoManager:   repoManager,
+		knowledge:     knowledge,
+		notifications: notifications,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Interval 交接时刻检查间隔
+func (s *handoverService) Interval() time.Duration {
+	if s.cfg.CheckInterval <= 0 {
+		return time.Minute
+	}
+	return s.cfg.CheckInterval
+}
+
+// itemLimit 交班报告每类内容的最多条数
+func (s *handoverService) itemLimit() int {
+	if s.cfg.ItemLimit <= 0 {
+		return 50
+	}
+	return s.cfg.ItemLimit
+}
+
+// ListSchedules 获取全部交接排班
+func (s *handoverService) ListSchedules(ctx context.Context) ([]*models.HandoverSchedule, error) {
+	return s.repoManager.Handover().ListSchedules(ctx, false)
+}
+
+// GetSchedule 获取交接排班
+func (s *handoverService) GetSchedule(ctx context.Context, id string) (*models.HandoverSchedule, error) {
+	return s.repoManager.Handover().GetSchedule(ctx, id)
+}
+
+// CreateSchedule 创建交接排班，每个团队只能有一个排班，未指定启用状态时默认启用。
+// 创建前已经过去的交接时刻不补发交班报告
+func (s *handoverService) CreateSchedule(ctx context.Context, req *models.HandoverScheduleRequest, createdBy string) (*models.HandoverSchedule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.HandoverSchedule{Enabled: true, CreatedBy: createdBy}
+	req.ApplyTo(schedule)
+	if err := s.repoManager.Handover().CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建交接排班", zap.String("schedule_id", schedule.ID), zap.String("team", schedule.Team),
+		zap.Strings("shift_starts", schedule.ShiftStarts))
+	return schedule, nil
+}
+
+// UpdateSchedule 更新交接排班
+func (s *handoverService) UpdateSchedule(ctx context.Context, id string, req *models.HandoverScheduleRequest) (*models.HandoverSchedule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.repoManager.Handover().GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(schedule)
+	if err := s.repoManager.Handover().UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule 删除交接排班
+func (s *handoverService) DeleteSchedule(ctx context.Context, id string) error {
+	return s.repoManager.Handover().DeleteSchedule(ctx, id)
+}
+
+// ListReports 获取交班报告列表，team 为空时返回所有团队
+func (s *handoverService) ListReports(ctx context.Context, team string, limit int) ([]*models.HandoverReport, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.repoManager.Handover().ListReports(ctx, team, limit)
+}
+
+// GetReport 获取交班报告
+func (s *handoverService) GetReport(ctx context.Context, id string) (*models.HandoverReport, error) {
+	return s.repoManager.Handover().GetReport(ctx, id)
+}
+
+// Generate 手动生成截至当前的交班报告并保存为知识库草稿，req.Notify 为 true 时发送到团队排班配置的频道
+func (s *handoverService) Generate(ctx context.Context, req *models.HandoverGenerateRequest, userID string) (*models.HandoverReport, error) {
+	team := strings.TrimSpace(req.Team)
+	if team == "" {
+		return nil, fmt.Errorf("%w: 团队不能为空", models.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	since := now.Add(-handoverManualWindow)
+	if req.Since != nil {
+		if !req.Since.Before(now) {
+			return nil, fmt.Errorf("%w: 班次开始时间必须早于当前时间", models.ErrInvalidInput)
+		}
+		since = *req.Since
+	} else {
+		reports, err := s.repoManager.Handover().ListReports(ctx, team, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(reports) > 0 && reports[0].ShiftEnd.Before(now) {
+			since = reports[0].ShiftEnd
+		}
+	}
+
+	var schedule *models.HandoverSchedule
+	if req.Notify {
+		var err error
+		schedule, err = s.repoManager.Handover().GetScheduleByTeam(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		if schedule.ChannelType == nil {
+			return nil, fmt.Errorf("%w: 团队交接排班未配置频道", models.ErrInvalidInput)
+		}
+	}
+
+	report := &models.HandoverReport{Team: team, ShiftStart: since, ShiftEnd: now, CreatedBy: &userID}
+	if err := s.produce(ctx, report, schedule, userID); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RunDue 为已到达交接时刻的排班生成上一班次的交班报告，返回生成的报告数。
+// 只生成最近一个交接时刻的报告，服务停机期间错过的交接时刻不补发；多实例时由报告唯一约束保证只生成一次
+func (s *handoverService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.repoManager.Handover().ListSchedules(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, schedule := range schedules {
+		boundary, ok := schedule.LastBoundary(now)
+		if !ok {
+			s.logger.Warn("交接排班时区或交接时刻无效", zap.String("schedule_id", schedule.ID), zap.String("team", schedule.Team))
+			continue
+		}
+		last := schedule.CreatedAt
+		if schedule.LastHandoverAt != nil {
+			last = *schedule.LastHandoverAt
+		}
+		if !boundary.After(last) {
+			continue
+		}
+		start, _ := schedule.PreviousBoundary(boundary)
+
+		report := &models.HandoverReport{ScheduleID: &schedule.ID, Team: schedule.Team, ShiftStart: start, ShiftEnd: boundary}
+		err := s.produce(ctx, report, schedule, schedule.CreatedBy)
+		if err != nil && !errors.Is(err, models.ErrHandoverReportExists) {
+			s.logger.Error("生成交班报告失败", zap.Error(err), zap.String("team", schedule.Team), zap.Time("shift_end", boundary))
+			continue
+		}
+		if err := s.repoManager.Handover().MarkScheduleRun(ctx, schedule.ID, boundary); err != nil {
+			s.logger.Error("记录交接排班执行时间失败", zap.Error(err), zap.String("schedule_id", schedule.ID))
+		}
+		if err == nil {
+			generated++
+		}
+	}
+
+	return generated, nil
+}
+
+// produce 汇总报告内容并保存报告，然后创建知识库草稿，schedule 不为空且配置了频道时发送到团队频道。
+// 保存报告在前，多实例同时到达交接时刻时只有保存成功的实例继续发送
+func (s *handoverService) produce(ctx context.Context, report *models.HandoverReport, schedule *models.HandoverSchedule, authorID string) error {
+	scope, err := s.scope(ctx, report.Team, report.ShiftStart)
+	if err != nil {
+		return err
+	}
+	summary, err := s.repoManager.Handover().Summarize(ctx, *scope)
+	if err != nil {
+		return err
+	}
+	report.Summary = *summary
+	report.Summary.CSAT = s.csat(ctx, report)
+
+	if err := s.repoManager.Handover().CreateReport(ctx, report); err != nil {
+		return err
+	}
+
+	content := renderHandover(report)
+	knowledge := &models.Knowledge{
+		Title:    fmt.Sprintf("%s 交班报告 %s", report.Team, report.ShiftEnd.Format("2006-01-02 15:04")),
+		Content:  content,
+		Type:     models.KnowledgeTypeArticle,
+		Status:   models.KnowledgeStatusDraft,
+		Format:   models.KnowledgeFormatMarkdown,
+		Tags:     []string{"handover"},
+		AuthorID: authorID,
+	}
+	if err := s.knowledge.Create(ctx, knowledge); err != nil {
+		s.logger.Error("保存交班报告知识库草稿失败", zap.Error(err), zap.String("report_id", report.ID))
+	} else {
+		report.KnowledgeID = &knowledge.ID
+	}
+
+	if schedule != nil && schedule.ChannelType != nil && s.notifications != nil {
+		if err := s.notifications.Send(ctx, handoverNotification(report, schedule)); err != nil {
+			s.logger.Error("发送交班报告失败", zap.Error(err), zap.String("report_id", report.ID), zap.String("team", report.Team))
+		} else {
+			report.Notified = true
+		}
+	}
+
+	if err := s.repoManager.Handover().UpdateReport(ctx, report); err != nil {
+		s.logger.Error("更新交班报告状态失败", zap.Error(err), zap.String("report_id", report.ID))
+	}
+
+	s.logger.Info("生成交班报告", zap.String("report_id", report.ID), zap.String("team", report.Team),
+		zap.Int("critical_alerts", len(report.Summary.CriticalAlerts)), zap.Int("tickets", len(report.Summary.InProgressTickets)),
+		zap.Bool("notified", report.Notified))
+	return nil
+}
+
+// scope 团队的统计范围：团队负责的服务和分派策略中的成员
+func (s *handoverService) scope(ctx context.Context, team string, since time.Time) (*models.HandoverScope, error) {
+	scope := &models.HandoverScope{Team: team, Since: since, Limit: s.itemLimit()}
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取团队负责的服务失败: %w", err)
+	}
+	for _, service := range services {
+		if service.OwnerTeam == team {
+			scope.ServiceTags = append(scope.ServiceTags, models.ServiceTicketTagPrefix+service.Name)
+		}
+	}
+
+	policy, err := s.repoManager.AssignmentPolicy().GetByTeam(ctx, team)
+	if err != nil && !errors.Is(err, models.ErrAssignmentPolicyNotFound) {
+		return nil, fmt.Errorf("获取团队成员失败: %w", err)
+	}
+	if policy != nil {
+		for _, member := range policy.Members {
+			scope.Members = append(scope.Members, member.UserID)
+		}
+	}
+
+	return scope, nil
+}
+
+// csat 统计团队在班次内发出的工单满意度调查，统计失败时只记录日志，不影响生成报告
+func (s *handoverService) csat(ctx context.Context, report *models.HandoverReport) *models.TicketCSAT {
+	team := report.Team
+	groups, err := s.repoManager.TicketSurvey().CSAT(ctx, &models.TicketSurveyFilter{
+		From:    report.ShiftStart,
+		To:      report.ShiftEnd,
+		Team:    &team,
+		GroupBy: models.TicketSurveyGroupByTeam,
+	})
+	if err != nil {
+		s.logger.Warn("统计交班工单满意度失败", zap.Error(err), zap.String("team", team))
+		return nil
+	}
+
+	total := &models.TicketCSAT{Key: team}
+	for _, group := range groups {
+		total.Add(group)
+	}
+	if total.Sent == 0 {
+		return nil
+	}
+	total.Finalize()
+	return total
+}
+
+// handoverNotification 构造发送到团队频道的交班报告通知，按团队语言渲染
+func handoverNotification(report *models.HandoverReport, schedule *models.HandoverSchedule) *models.Notification {
+	summary := report.Summary
+	items := make([]string, 0, len(summary.CriticalAlerts)+len(summary.InProgressTickets))
+	for _, alert := range summary.CriticalAlerts {
+		items = append(items, fmt.Sprintf("[%s] %s", alert.Status, alert.Name))
+	}
+	for _, ticket := range summary.InProgressTickets {
+		items = append(items, fmt.Sprintf("[%s] %s %s", ticket.Status, ticket.Number, ticket.Title))
+	}
+	if summary.CSAT != nil {
+		items = append(items, fmt.Sprintf("[CSAT] %.1f%% (%d/%d)", summary.CSAT.CSAT, summary.CSAT.Satisfied, summary.CSAT.Responses))
+	}
+
+	return &models.Notification{
+		Type:       *schedule.ChannelType,
+		Recipient:  schedule.Recipient,
+		Team:       report.Team,
+		MessageKey: "notification.handover",
+		MessageData: map[string]interface{}{
+			"Team":           report.Team,
+			"ShiftStart":     report.ShiftStart.Format("2006-01-02 15:04"),
+			"ShiftEnd":       report.ShiftEnd.Format("2006-01-02 15:04"),
+			"CriticalAlerts": len(summary.CriticalAlerts),
+			"Tickets":        len(summary.InProgressTickets),
+			"Incidents":      len(summary.Incidents),
+			"Actions":        len(summary.PendingActions),
+			"Items":          items,
+		},
+	}
+}
+
+// renderHandover 将交班报告渲染为知识库使用的 Markdown 文档
+func renderHandover(report *models.HandoverReport) string {
+	var b strings.Builder
+	summary := report.Summary
+
+	fmt.Fprintf(&b, "# %s 交班报告\n\n", report.Team)
+	fmt.Fprintf(&b, "- 班次开始：%s\n", report.ShiftStart.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- 班次结束：%s\n\n", report.ShiftEnd.Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "## 未恢复的严重告警（%d）\n\n", len(summary.CriticalAlerts))
+	for _, alert := range summary.CriticalAlerts {
+		fmt.Fprintf(&b, "- [%s] %s", alert.Status, alert.Name)
+		if alert.Service != "" {
+			fmt.Fprintf(&b, "（%s）", alert.Service)
+		}
+		fmt.Fprintf(&b, "，开始于 %s\n", alert.StartsAt.Format("2006-01-02 15:04:05"))
+	}
+	writeHandoverTickets(&b, "处理中的工单", summary.InProgressTickets)
+	writeHandoverTickets(&b, "班次内新事件", summary.Incidents)
+
+	fmt.Fprintf(&b, "\n## 待处理事项（%d）\n\n", len(summary.PendingActions))
+	for _, action := range summary.PendingActions {
+		kind := "复盘改进项"
+		if action.Kind == models.HandoverActionRemediation {
+			kind = "待审批修复动作"
+		}
+		fmt.Fprintf(&b, "- [%s] %s", kind, action.Title)
+		if action.DueDate != nil {
+			fmt.Fprintf(&b, "，截止 %s", action.DueDate.Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+
+	if csat := summary.CSAT; csat != nil {
+		b.WriteString("\n## 工单满意度\n\n")
+		fmt.Fprintf(&b, "- 发出调查：%d，收到评价：%d（回复率 %.1f%%）\n", csat.Sent, csat.Responses, csat.ResponseRate)
+		if csat.Responses > 0 {
+			fmt.Fprintf(&b, "- CSAT：%.1f%%，平均评分：%.2f\n", csat.CSAT, csat.Average)
+		}
+	}
+
+	return b.String()
+}
+
+// writeHandoverTickets 渲染交班报告中的工单列表
+func writeHandoverTickets(b *strings.Builder, title string, tickets []*models.HandoverTicket) {
+	fmt.Fprintf(b, "\n## %s（%d）\n\n", title, len(tickets))
+	for _, ticket := range tickets {
+		fmt.Fprintf(b, "- %s %s [%s/%s]", ticket.Number, ticket.Title, ticket.Priority, ticket.Status)
+		if ticket.SLADeadline != nil {
+			fmt.Fprintf(b, "，SLA 截止 %s", ticket.SLADeadline.Format("2006-01-02 15:04"))
+		}
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeHandoverRepository 内存中的交接排班和交班报告，记录汇总时的统计范围
+type fakeHandoverRepository struct {
+	repository.HandoverRepository
+	schedules []*models.HandoverSchedule
+	reports   []*models.HandoverReport
+	scopes    []models.HandoverScope
+}
+
+func (r *fakeHandoverRepository) ListSchedules(ctx context.Context, enabledOnly bool) ([]*models.HandoverSchedule, error) {
+	return r.schedules, nil
+}
+
+func (r *fakeHandoverRepository) MarkScheduleRun(ctx context.Context, id string, boundary time.Time) error {
+	for _, schedule := range r.schedules {
+		if schedule.ID == id {
+			schedule.LastHandoverAt = &boundary
+		}
+	}
+	return nil
+}
+
+func (r *fakeHandoverRepository) CreateReport(ctx context.Context, report *models.HandoverReport) error {
+	for _, existing := range r.reports {
+		if report.ScheduleID != nil && existing.ScheduleID != nil &&
+			*existing.ScheduleID == *report.ScheduleID && existing.ShiftEnd.Equal(report.ShiftEnd) {
+			return models.ErrHandoverReportExists
+		}
+	}
+	report.ID = fmt.Sprintf("r%d", len(r.reports)+1)
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *fakeHandoverRepository) UpdateReport(ctx context.Context, report *models.HandoverReport) error {
+	return nil
+}
+
+func (r *fakeHandoverRepository) Summarize(ctx context.Context, scope models.HandoverScope) (*models.HandoverSummary, error) {
+	r.scopes = append(r.scopes, scope)
+	return &models.HandoverSummary{
+		CriticalAlerts:    []*models.HandoverAlert{{ID: "a1", Name: "HighErrorRate", Status: models.AlertStatusFiring}},
+		InProgressTickets: []*models.HandoverTicket{{ID: "t1", Number: "T-1", Title: "支付超时", Status: models.TicketStatusInProgress}},
+	}, nil
+}
+
+// fakeHandoverNotificationService 记录发送的通知
+type fakeHandoverNotificationService struct {
+	NotificationService
+	sent []*models.Notification
+}
+
+func (s *fakeHandoverNotificationService) Send(ctx context.Context, notification *models.Notification) error {
+	s.sent = append(s.sent, notification)
+	return nil
+}
+
+type handoverRepoManager struct {
+	*MockRepositoryManager
+	handover *fakeHandoverRepository
+	catalog  *fakeServiceCatalogRepository
+	policies *fakeAssignmentPolicyRepository
+	surveys  *fakeTicketSurveyRepository
+}
+
+func (m *handoverRepoManager) Handover() repository.HandoverRepository { return m.handover }
+
+func (m *handoverRepoManager) ServiceCatalog() repository.ServiceCatalogRepository { return m.catalog }
+
+func (m *handoverRepoManager) AssignmentPolicy() repository.AssignmentPolicyRepository {
+	return m.policies
+}
+
+func (m *handoverRepoManager) TicketSurvey() repository.TicketSurveyRepository { return m.surveys }
+
+func TestHandoverSchedule_LastBoundary(t *testing.T) {
+	schedule := &models.HandoverSchedule{Timezone: "Asia/Shanghai", ShiftStarts: []string{"09:00", "21:00"}}
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	// 当天第一个交接时刻之前，回到前一天最后一个交接时刻
+	boundary, ok := schedule.LastBoundary(time.Date(2024, 5, 1, 0, 30, 0, 0, time.UTC)) // 08:30 +08:00
+	require.True(t, ok)
+	assert.True(t, boundary.Equal(time.Date(2024, 4, 30, 21, 0, 0, 0, shanghai)))
+
+	boundary, ok = schedule.LastBoundary(time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC)) // 09:00 +08:00
+	require.True(t, ok)
+	assert.True(t, boundary.Equal(time.Date(2024, 5, 1, 9, 0, 0, 0, shanghai)))
+
+	start, ok := schedule.PreviousBoundary(boundary)
+	require.True(t, ok)
+	assert.True(t, start.Equal(time.Date(2024, 4, 30, 21, 0, 0, 0, shanghai)))
+
+	_, ok = (&models.HandoverSchedule{Timezone: "Mars/Olympus", ShiftStarts: []string{"09:00"}}).LastBoundary(time.Now())
+	assert.False(t, ok)
+}
+
+func TestHandoverService_RunDue(t *testing.T) {
+	ctx := context.Background()
+	channel := models.NotificationTypeSlack
+	schedule := &models.HandoverSchedule{
+		ID: "s1", Team: "sre", Timezone: "UTC", ShiftStarts: []string{"09:00", "21:00"},
+		ChannelType: &channel, Recipient: "#sre-oncall", Enabled: true, CreatedBy: "admin",
+		CreatedAt: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+	}
+	repoManager := &handoverRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		handover:              &fakeHandoverRepository{schedules: []*models.HandoverSchedule{schedule}},
+		catalog: &fakeServiceCatalogRepository{services: []*models.CatalogService{
+			{Name: "checkout", OwnerTeam: "sre"},
+			{Name: "search", OwnerTeam: "web"},
+		}},
+		policies: &fakeAssignmentPolicyRepository{policies: []*models.AssignmentPolicy{
+			{Team: "sre", Members: []models.AssignmentMember{{UserID: "u1"}, {UserID: "u2"}}},
+		}},
+		surveys: &fakeTicketSurveyRepository{groups: []*models.TicketCSAT{
+			{Key: "sre", Sent: 5, Responses: 4, Satisfied: 3, ScoreSum: 16},
+		}},
+	}
+	knowledge := &fakePostmortemKnowledgeService{documents: map[string]*models.Knowledge{}}
+	notifications := &fakeHandoverNotificationService{}
+	svc := NewHandoverService(repoManager, knowledge, notifications, config.HandoverConfig{ItemLimit: 20}, zap.NewNop())
+
+	// 排班创建前的交接时刻不生成报告
+	generated, err := svc.RunDue(ctx, time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+
+	generated, err = svc.RunDue(ctx, time.Date(2024, 5, 1, 9, 1, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+
+	require.Len(t, repoManager.handover.reports, 1)
+	report := repoManager.handover.reports[0]
+	assert.True(t, report.ShiftStart.Equal(time.Date(2024, 4, 30, 21, 0, 0, 0, time.UTC)))
+	assert.True(t, report.ShiftEnd.Equal(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, report.Notified)
+	require.NotNil(t, report.KnowledgeID)
+	assert.True(t, schedule.LastHandoverAt.Equal(report.ShiftEnd))
+
+	scope := repoManager.handover.scopes[0]
+	assert.Equal(t, []string{models.ServiceTicketTagPrefix + "checkout"}, scope.ServiceTags)
+	assert.Equal(t, []string{"u1", "u2"}, scope.Members)
+	assert.Equal(t, 20, scope.Limit)
+
+	draft := knowledge.documents[*report.KnowledgeID]
+	assert.Equal(t, models.KnowledgeStatusDraft, draft.Status)
+	assert.Equal(t, "admin", draft.AuthorID)
+	assert.Contains(t, draft.Content, "HighErrorRate")
+	assert.Contains(t, draft.Content, "T-1 支付超时")
+	assert.Contains(t, draft.Content, "CSAT：75.0%")
+
+	// 满意度按团队统计班次内发出的调查
+	require.NotNil(t, report.Summary.CSAT)
+	assert.Equal(t, "sre", *repoManager.surveys.filter.Team)
+	assert.True(t, repoManager.surveys.filter.From.Equal(report.ShiftStart))
+	assert.True(t, repoManager.surveys.filter.To.Equal(report.ShiftEnd))
+
+	require.Len(t, notifications.sent, 1)
+	assert.Equal(t, "#sre-oncall", notifications.sent[0].Recipient)
+	assert.Equal(t, "sre", notifications.sent[0].Team)
+	assert.Equal(t, "notification.handover", notifications.sent[0].MessageKey)
+	assert.Equal(t, 1, notifications.sent[0].MessageData["CriticalAlerts"])
+
+	// 同一交接时刻不重复生成
+	generated, err = svc.RunDue(ctx, time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+
+	// 其他实例已生成该交接时刻的报告时跳过发送，只记录执行时间
+	schedule.LastHandoverAt = nil
+	generated, err = svc.RunDue(ctx, time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+	assert.Len(t, notifications.sent, 1)
+	assert.NotNil(t, schedule.LastHandoverAt)
+}
+
+func TestHandoverService_Generate(t *testing.T) {
+	ctx := context.Background()
+	repoManager := &handoverRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		handover:              &fakeHandoverRepository{},
+		catalog:               &fakeServiceCatalogRepository{},
+		policies:              &fakeAssignmentPolicyRepository{},
+		surveys:               &fakeTicketSurveyRepository{},
+	}
+	knowledge := &fakePostmortemKnowledgeService{documents: map[string]*models.Knowledge{}}
+	svc := NewHandoverService(repoManager, knowledge, &fakeHandoverNotificationService{}, config.HandoverConfig{}, zap.NewNop())
+
+	since := time.Now().Add(-time.Hour)
+	report, err := svc.Generate(ctx, &models.HandoverGenerateRequest{Team: "dba", Since: &since}, "u1")
+	require.NoError(t, err)
+	assert.Nil(t, report.ScheduleID)
+	assert.Equal(t, "u1", *report.CreatedBy)
+	assert.False(t, report.Notified)
+	assert.NotNil(t, report.KnowledgeID)
+	assert.Equal(t, 50, repoManager.handover.scopes[0].Limit)
+	// 班次内没有发出调查时不统计满意度
+	assert.Nil(t, report.Summary.CSAT)
+
+	future := time.Now().Add(time.Hour)
+	_, err = svc.Generate(ctx, &models.HandoverGenerateRequest{Team: "dba", Since: &future}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// heartbeatTokenBytes 心跳上报令牌的随机字节数
+	heartbeatTokenBytes = 20
+	// heartbeatDataSourceID 心跳告警不关联外部数据源，使用固定标识
+	heartbeatDataSourceID = "heartbeat"
+	// heartbeatFingerprintPrefix 心跳告警指纹前缀，每个心跳检查对应唯一指纹
+	heartbeatFingerprintPrefix = "heartbeat:"
+)
+
+// heartbeatService 心跳监控服务实现
+type heartbeatService struct {
+	repoManager  repository.RepositoryManager
+	alertService AlertService
+	cfg          config.HeartbeatConfig
+	logger       *zap.Logger
+}
+
+// NewHeartbeatService 创建心跳监控服务实例
+func NewHeartbeatService(repoManager repository.RepositoryManager, alertService AlertService, cfg config.HeartbeatConfig, logger *zap.Logger) HeartbeatService {
+	return &heartbeatService{
+		repoManager:  repoManager,
+		alertService: alertService,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// Interval 超时检查的周期
+func (s *heartbeatService) Interval() time.Duration {
+	return s.cfg.CheckInterval
+}
+
+// List 获取全部心跳检查
+func (s *heartbeatService) List(ctx context.Context) ([]*models.Heartbeat, error) {
+	return s.repoManager.Heartbeat().List(ctx)
+}
+
+// Get 获取心跳检查
+func (s *heartbeatService) Get(ctx context.Context, id string) (*models.Heartbeat, error) {
+	return s.repoManager.Heartbeat().GetByID(ctx, id)
+}
+
+// Create 创建心跳检查并生成上报令牌，从创建时开始计算第一次上报的最晚时间
+func (s *heartbeatService) Create(ctx context.Context, req *models.HeartbeatRequest, createdBy string) (*models.Heartbeat, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := generateHeartbeatToken()
+	if err != nil {
+		return nil, err
+	}
+
+	heartbeat := &models.Heartbeat{
+		Token:     token,
+		Status:    models.HeartbeatStatusNew,
+		CreatedBy: createdBy,
+	}
+	req.ApplyTo(heartbeat)
+	if req.Paused {
+		heartbeat.Status = models.HeartbeatStatusPaused
+	}
+	heartbeat.ExpectedAt = heartbeat.NextExpectedAt(time.Now())
+
+	if err := s.repoManager.Heartbeat().Create(ctx, heartbeat); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建心跳检查", zap.String("heartbeat_id", heartbeat.ID), zap.String("name", heartbeat.Name),
+		zap.Duration("interval", heartbeat.Interval()), zap.Duration("grace", heartbeat.Grace()))
+	return heartbeat, nil
+}
+
+// Update 更新心跳检查。修改周期后按最近一次上报重新计算最晚上报时间；
+// 暂停异常的心跳检查时解决其告警，恢复暂停的心跳检查时重新开始计时
+func (s *heartbeatService) Update(ctx context.Context, id string, req *models.HeartbeatRequest) (*models.Heartbeat, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	heartbeat, err := s.repoManager.Heartbeat().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	wasDown := heartbeat.Status == models.HeartbeatStatusDown
+	req.ApplyTo(heartbeat)
+
+	switch {
+	case req.Paused:
+		heartbeat.Status = models.HeartbeatStatusPaused
+	case heartbeat.Paused():
+		heartbeat.Status = models.HeartbeatStatusNew
+		if heartbeat.LastPingAt != nil {
+			heartbeat.Status = models.HeartbeatStatusUp
+		}
+		heartbeat.ExpectedAt = heartbeat.NextExpectedAt(now)
+	default:
+		from := heartbeat.CreatedAt
+		if heartbeat.LastPingAt != nil {
+			from = *heartbeat.LastPingAt
+		}
+		heartbeat.ExpectedAt = heartbeat.NextExpectedAt(from)
+	}
+
+	if err := s.repoManager.Heartbeat().Update(ctx, heartbeat); err != nil {
+		return nil, err
+	}
+
+	if wasDown && heartbeat.Paused() {
+		s.resolveAlert(ctx, heartbeat, now)
+	}
+	return heartbeat, nil
+}
+
+// Delete 删除心跳检查，异常状态的心跳检查同时解决其告警
+func (s *heartbeatService) Delete(ctx context.Context, id string) error {
+	heartbeat, err := s.repoManager.Heartbeat().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repoManager.Heartbeat().Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if heartbeat.Status == models.HeartbeatStatusDown {
+		s.resolveAlert(ctx, heartbeat, time.Now())
+	}
+	return nil
+}
+
+// Status 获取全部心跳检查的当前状态，异常的排在前面，其余按最晚上报时间排序
+func (s *heartbeatService) Status(ctx context.Context) (*models.HeartbeatStatusList, error) {
+	heartbeats, err := s.repoManager.Heartbeat().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &models.HeartbeatStatusList{
+		Items: make([]*models.HeartbeatState, 0, len(heartbeats)),
+		Counts: map[models.HeartbeatStatus]int{
+			models.HeartbeatStatusNew:    0,
+			models.HeartbeatStatusUp:     0,
+			models.HeartbeatStatusDown:   0,
+			models.HeartbeatStatusPaused: 0,
+		},
+	}
+	for _, heartbeat := range heartbeats {
+		state := &models.HeartbeatState{
+			ID:         heartbeat.ID,
+			Name:       heartbeat.Name,
+			Status:     heartbeat.Status,
+			LastPingAt: heartbeat.LastPingAt,
+			ExpectedAt: heartbeat.ExpectedAt,
+		}
+		if !heartbeat.Paused() && now.After(heartbeat.ExpectedAt) {
+			state.LateSeconds = int64(now.Sub(heartbeat.ExpectedAt) / time.Second)
+		}
+		result.Items = append(result.Items, state)
+		result.Counts[heartbeat.Status]++
+	}
+
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		a, b := result.Items[i], result.Items[j]
+		if (a.Status == models.HeartbeatStatusDown) != (b.Status == models.HeartbeatStatusDown) {
+			return a.Status == models.HeartbeatStatusDown
+		}
+		return a.ExpectedAt.Before(b.ExpectedAt)
+	})
+	return result, nil
+}
+
+// Ping 记录外部任务的一次上报，异常状态的心跳检查恢复正常并解决其告警
+func (s *heartbeatService) Ping(ctx context.Context, token string) error {
+	heartbeat, err := s.repoManager.Heartbeat().GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.repoManager.Heartbeat().RecordPing(ctx, heartbeat.ID, now, heartbeat.NextExpectedAt(now)); err != nil {
+		return err
+	}
+
+	if heartbeat.Status == models.HeartbeatStatusDown {
+		s.logger.Info("心跳恢复上报", zap.String("heartbeat_id", heartbeat.ID), zap.String("name", heartbeat.Name))
+		s.resolveAlert(ctx, heartbeat, now)
+	}
+	return nil
+}
+
+// CheckOverdue 检查超时未上报的心跳并触发告警，返回新触发告警的心跳数
+func (s *heartbeatService) CheckOverdue(ctx context.Context) (int, error) {
+	now := time.Now()
+	heartbeats, err := s.repoManager.Heartbeat().ListOverdue(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, heartbeat := range heartbeats {
+		// 检查期间收到上报或被暂停时不触发告警
+		marked, err := s.repoManager.Heartbeat().MarkDown(ctx, heartbeat.ID, heartbeat.ExpectedAt)
+		if err != nil {
+			s.logger.Error("标记心跳检查异常失败", zap.Error(err), zap.String("heartbeat_id", heartbeat.ID))
+			continue
+		}
+		if !marked {
+			continue
+		}
+
+		if err := s.fireAlert(ctx, heartbeat, now); err != nil {
+			s.logger.Error("触发心跳告警失败", zap.Error(err), zap.String("heartbeat_id", heartbeat.ID))
+			continue
+		}
+		fired++
+	}
+	return fired, nil
+}
+
+// fireAlert 创建心跳超时告警，同一心跳检查的告警指纹唯一，再次超时时重新打开已解决的告警
+func (s *heartbeatService) fireAlert(ctx context.Context, heartbeat *models.Heartbeat, now time.Time) error {
+	description := fmt.Sprintf("心跳检查 %s 超过 %s 未上报", heartbeat.Name, heartbeat.Interval()+heartbeat.Grace())
+	if heartbeat.LastPingAt != nil {
+		description = fmt.Sprintf("心跳检查 %s 自 %s 起未上报，上报周期 %s，宽限时间 %s", heartbeat.Name,
+			heartbeat.LastPingAt.Format(time.RFC3339), heartbeat.Interval(), heartbeat.Grace())
+	}
+
+	fingerprint := heartbeatFingerprintPrefix + heartbeat.ID
+	existing, err := s.repoManager.Alert().GetByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, models.ErrAlertNotFound) {
+		return fmt.Errorf("查询心跳告警失败: %w", err)
+	}
+
+	if existing == nil {
+		labels := map[string]string{
+			"source":       string(models.AlertSourceHeartbeat),
+			"heartbeat":    heartbeat.Name,
+			"heartbeat_id": heartbeat.ID,
+		}
+		for key, value := range heartbeat.Labels {
+			if _, reserved := labels[key]; !reserved {
+				labels[key] = value
+			}
+		}
+
+		alert := &models.Alert{
+			DataSourceID: heartbeatDataSourceID,
+			Name:         heartbeat.Name,
+			Description:  description,
+			Severity:     heartbeat.Severity,
+			Status:       models.AlertStatusFiring,
+			Source:       models.AlertSourceHeartbeat,
+			Labels:       labels,
+			Annotations:  map[string]string{"expected_at": heartbeat.ExpectedAt.Format(time.RFC3339)},
+			Expression:   fmt.Sprintf("heartbeat(interval=%s, grace=%s)", heartbeat.Interval(), heartbeat.Grace()),
+			StartsAt:     now,
+			Fingerprint:  fingerprint,
+		}
+		if err := s.alertService.Create(ctx, alert); err != nil {
+			return err
+		}
+	} else {
+		existing.Status = models.AlertStatusFiring
+		existing.Description = description
+		existing.Severity = heartbeat.Severity
+		existing.StartsAt = now
+		existing.EndsAt = nil
+		existing.ResolvedAt = nil
+		existing.ResolvedBy = nil
+		existing.AckedAt = nil
+		existing.AckedBy = nil
+		delete(existing.Annotations, models.AlertAnnotationResolveReason)
+		existing.LastEvalAt = now
+		existing.EvalCount++
+		if err := s.alertService.Update(ctx, existing); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Warn("心跳超时告警触发", zap.String("heartbeat_id", heartbeat.ID), zap.String("name", heartbeat.Name))
+	return nil
+}
+
+// resolveAlert 解决心跳超时告警，失败只记录日志，不影响上报和配置修改
+func (s *heartbeatService) resolveAlert(ctx context.Context, heartbeat *models.Heartbeat, now time.Time) {
+	alert, err := s.repoManager.Alert().GetByFingerprint(ctx, heartbeatFingerprintPrefix+heartbeat.ID)
+	if err != nil {
+		if !errors.Is(err, models.ErrAlertNotFound) {
+			s.logger.Error("查询心跳告警失败", zap.Error(err), zap.String("heartbeat_id", heartbeat.ID))
+		}
+		return
+	}
+	if alert.Status == models.AlertStatusResolved {
+		return
+	}
+
+	alert.Status = models.AlertStatusResolved
+	alert.EndsAt = &now
+	alert.ResolvedAt = &now
+	alert.LastEvalAt = now
+	if err := s.alertService.Update(ctx, alert); err != nil {
+		s.logger.Error("解决心跳告警失败", zap.Error(err), zap.String("heartbeat_id", heartbeat.ID))
+	}
+}
+
+// generateHeartbeatToken 生成心跳上报令牌，令牌出现在上报地址中，需要足够的随机性
+func generateHeartbeatToken() (string, error) {
+	buf := make([]byte, heartbeatTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成心跳上报令牌失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
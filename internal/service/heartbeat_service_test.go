@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeHeartbeatRepository 内存心跳检查仓储
+type fakeHeartbeatRepository struct {
+	repository.HeartbeatRepository
+	heartbeats map[string]*models.Heartbeat
+}
+
+func (r *fakeHeartbeatRepository) Create(ctx context.Context, heartbeat *models.Heartbeat) error {
+	heartbeat.ID = "h1"
+	heartbeat.CreatedAt = time.Now()
+	r.heartbeats[heartbeat.ID] = heartbeat
+	return nil
+}
+
+func (r *fakeHeartbeatRepository) GetByID(ctx context.Context, id string) (*models.Heartbeat, error) {
+	heartbeat, ok := r.heartbeats[id]
+	if !ok {
+		return nil, models.ErrHeartbeatNotFound
+	}
+	copied := *heartbeat
+	return &copied, nil
+}
+
+func (r *fakeHeartbeatRepository) GetByToken(ctx context.Context, token string) (*models.Heartbeat, error) {
+	for _, heartbeat := range r.heartbeats {
+		if heartbeat.Token == token {
+			copied := *heartbeat
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrHeartbeatNotFound
+}
+
+func (r *fakeHeartbeatRepository) List(ctx context.Context) ([]*models.Heartbeat, error) {
+	heartbeats := make([]*models.Heartbeat, 0, len(r.heartbeats))
+	for _, heartbeat := range r.heartbeats {
+		heartbeats = append(heartbeats, heartbeat)
+	}
+	return heartbeats, nil
+}
+
+func (r *fakeHeartbeatRepository) Update(ctx context.Context, heartbeat *models.Heartbeat) error {
+	r.heartbeats[heartbeat.ID] = heartbeat
+	return nil
+}
+
+func (r *fakeHeartbeatRepository) RecordPing(ctx context.Context, id string, at, expectedAt time.Time) error {
+	heartbeat := r.heartbeats[id]
+	heartbeat.LastPingAt = &at
+	heartbeat.ExpectedAt = expectedAt
+	heartbeat.PingCount++
+	if !heartbeat.Paused() {
+		heartbeat.Status = models.HeartbeatStatusUp
+	}
+	return nil
+}
+
+func (r *fakeHeartbeatRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.Heartbeat, error) {
+	var overdue []*models.Heartbeat
+	for _, heartbeat := range r.heartbeats {
+		if (heartbeat.Status == models.HeartbeatStatusNew || heartbeat.Status == models.HeartbeatStatusUp) &&
+			heartbeat.ExpectedAt.Before(now) {
+			copied := *heartbeat
+			overdue = append(overdue, &copied)
+		}
+	}
+	return overdue, nil
+}
+
+func (r *fakeHeartbeatRepository) MarkDown(ctx context.Context, id string, expectedAt time.Time) (bool, error) {
+	heartbeat := r.heartbeats[id]
+	if !heartbeat.ExpectedAt.Equal(expectedAt) || heartbeat.Status == models.HeartbeatStatusDown {
+		return false, nil
+	}
+	heartbeat.Status = models.HeartbeatStatusDown
+	return true, nil
+}
+
+// fakeHeartbeatAlertRepository 按指纹查询告警
+type fakeHeartbeatAlertRepository struct {
+	repository.AlertRepository
+	alerts map[string]*models.Alert
+}
+
+func (r *fakeHeartbeatAlertRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	alert, ok := r.alerts[fingerprint]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+// fakeHeartbeatAlertService 将创建和更新的告警写入告警仓储
+type fakeHeartbeatAlertService struct {
+	AlertService
+	repo    *fakeHeartbeatAlertRepository
+	created int
+}
+
+func (s *fakeHeartbeatAlertService) Create(ctx context.Context, alert *models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+	s.repo.alerts[alert.Fingerprint] = alert
+	s.created++
+	return nil
+}
+
+func (s *fakeHeartbeatAlertService) Update(ctx context.Context, alert *models.Alert) error {
+	s.repo.alerts[alert.Fingerprint] = alert
+	return nil
+}
+
+type heartbeatRepoManager struct {
+	*MockRepositoryManager
+	heartbeats *fakeHeartbeatRepository
+	alerts     *fakeHeartbeatAlertRepository
+}
+
+func (m *heartbeatRepoManager) Heartbeat() repository.HeartbeatRepository { return m.heartbeats }
+
+func (m *heartbeatRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func newTestHeartbeatService() (HeartbeatService, *heartbeatRepoManager, *fakeHeartbeatAlertService) {
+	repoManager := &heartbeatRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		heartbeats:            &fakeHeartbeatRepository{heartbeats: map[string]*models.Heartbeat{}},
+		alerts:                &fakeHeartbeatAlertRepository{alerts: map[string]*models.Alert{}},
+	}
+	alertService := &fakeHeartbeatAlertService{repo: repoManager.alerts}
+	svc := NewHeartbeatService(repoManager, alertService, config.HeartbeatConfig{
+		CheckInterval: 30 * time.Second,
+		BatchSize:     100,
+	}, zap.NewNop())
+	return svc, repoManager, alertService
+}
+
+func TestHeartbeatService_Create(t *testing.T) {
+	svc, _, _ := newTestHeartbeatService()
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, &models.HeartbeatRequest{Name: "nightly-backup", IntervalSeconds: 10}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	heartbeat, err := svc.Create(ctx, &models.HeartbeatRequest{
+		Name: "nightly-backup", IntervalSeconds: 3600, GraceSeconds: 600,
+	}, "admin")
+	require.NoError(t, err)
+	assert.Len(t, heartbeat.Token, 2*heartbeatTokenBytes)
+	assert.Equal(t, models.HeartbeatStatusNew, heartbeat.Status)
+	assert.Equal(t, models.AlertSeverityHigh, heartbeat.Severity)
+	assert.WithinDuration(t, time.Now().Add(70*time.Minute), heartbeat.ExpectedAt, time.Second)
+}
+
+func TestHeartbeatService_MissedPingFiresAndPingResolves(t *testing.T) {
+	svc, repoManager, alertService := newTestHeartbeatService()
+	ctx := context.Background()
+
+	heartbeat, err := svc.Create(ctx, &models.HeartbeatRequest{
+		Name: "nightly-backup", IntervalSeconds: 3600, Labels: map[string]string{"team": "dba"},
+	}, "admin")
+	require.NoError(t, err)
+
+	// 未超时不触发
+	fired, err := svc.CheckOverdue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+
+	stored := repoManager.heartbeats.heartbeats[heartbeat.ID]
+	stored.ExpectedAt = time.Now().Add(-time.Minute)
+	fired, err = svc.CheckOverdue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, models.HeartbeatStatusDown, stored.Status)
+
+	alert := repoManager.alerts.alerts[heartbeatFingerprintPrefix+heartbeat.ID]
+	require.NotNil(t, alert)
+	assert.Equal(t, models.AlertStatusFiring, alert.Status)
+	assert.Equal(t, models.AlertSourceHeartbeat, alert.Source)
+	assert.Equal(t, "dba", alert.Labels["team"])
+
+	// 已告警的心跳不重复触发
+	fired, err = svc.CheckOverdue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+
+	require.NoError(t, svc.Ping(ctx, heartbeat.Token))
+	assert.Equal(t, models.HeartbeatStatusUp, stored.Status)
+	assert.Equal(t, models.AlertStatusResolved, alert.Status)
+	assert.NotNil(t, alert.ResolvedAt)
+
+	// 再次超时重新打开同一告警
+	stored.ExpectedAt = time.Now().Add(-time.Minute)
+	fired, err = svc.CheckOverdue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, 1, alertService.created)
+	assert.Equal(t, models.AlertStatusFiring, alert.Status)
+	assert.Nil(t, alert.ResolvedAt)
+
+	assert.ErrorIs(t, svc.Ping(ctx, "unknown"), models.ErrHeartbeatNotFound)
+}
+
+func TestHeartbeatService_PauseResolvesAlert(t *testing.T) {
+	svc, repoManager, _ := newTestHeartbeatService()
+	ctx := context.Background()
+
+	heartbeat, err := svc.Create(ctx, &models.HeartbeatRequest{Name: "etl", IntervalSeconds: 600}, "admin")
+	require.NoError(t, err)
+	repoManager.heartbeats.heartbeats[heartbeat.ID].ExpectedAt = time.Now().Add(-time.Minute)
+	_, err = svc.CheckOverdue(ctx)
+	require.NoError(t, err)
+
+	updated, err := svc.Update(ctx, heartbeat.ID, &models.HeartbeatRequest{Name: "etl", IntervalSeconds: 600, Paused: true})
+	require.NoError(t, err)
+	assert.Equal(t, models.HeartbeatStatusPaused, updated.Status)
+	assert.Equal(t, models.AlertStatusResolved, repoManager.alerts.alerts[heartbeatFingerprintPrefix+heartbeat.ID].Status)
+
+	// 暂停期间的上报不改变状态
+	require.NoError(t, svc.Ping(ctx, heartbeat.Token))
+	assert.Equal(t, models.HeartbeatStatusPaused, repoManager.heartbeats.heartbeats[heartbeat.ID].Status)
+
+	// 恢复后重新开始计时
+	updated, err = svc.Update(ctx, heartbeat.ID, &models.HeartbeatRequest{Name: "etl", IntervalSeconds: 600})
+	require.NoError(t, err)
+	assert.Equal(t, models.HeartbeatStatusUp, updated.Status)
+	assert.True(t, updated.ExpectedAt.After(time.Now().Add(9*time.Minute)))
+
+	status, err := svc.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status.Items, 1)
+	assert.Equal(t, 1, status.Counts[models.HeartbeatStatusUp])
+	assert.Zero(t, status.Items[0].LateSeconds)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// impersonationService 模拟登录服务实现，令牌由网关签发，服务负责校验和审计
+type impersonationService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewImpersonationService 创建模拟登录服务实例
+func NewImpersonationService(repoManager repository.RepositoryManager, logger *zap.Logger) ImpersonationService {
+	return &impersonationService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// Start 校验模拟登录请求并记录审计日志，返回不含令牌的会话。不能模拟自己、管理员和已禁用的用户；
+// 审计日志写入失败时不开始模拟登录
+func (s *impersonationService) Start(ctx context.Context, impersonatorID string, req *models.ImpersonationRequest) (*models.ImpersonationSession, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.UserID == impersonatorID {
+		return nil, fmt.Errorf("%w: 不能模拟自己", models.ErrInvalidInput)
+	}
+
+	impersonator, err := s.repoManager.User().GetByID(ctx, impersonatorID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repoManager.User().GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, fmt.Errorf("%w: 用户 %s 未启用", models.ErrInvalidInput, user.Username)
+	}
+	if user.Role == models.UserRoleAdmin {
+		return nil, fmt.Errorf("%w: 不能模拟管理员", models.ErrPermissionDenied)
+	}
+
+	session := &models.ImpersonationSession{
+		User:             user,
+		ImpersonatorID:   impersonator.ID,
+		ImpersonatorName: impersonator.Username,
+		Reason:           req.Reason,
+		Scope:            req.Scope,
+		ExpiresAt:        time.Now().Add(req.Duration()),
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"username":   user.Username,
+		"reason":     session.Reason,
+		"scope":      session.Scope,
+		"expires_at": session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化审计信息失败: %w", err)
+	}
+	resourceType := "user"
+	if err := s.repoManager.AuditLog().Create(ctx, &models.AuditLog{
+		UserID:       &impersonator.ID,
+		Action:       models.AuditActionImpersonationStart,
+		ResourceType: &resourceType,
+		ResourceID:   &user.ID,
+		Success:      true,
+		Metadata:     metadata,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("管理员开始模拟用户登录", zap.String("impersonator", impersonator.Username),
+		zap.String("user", user.Username), zap.String("scope", string(session.Scope)),
+		zap.Time("expires_at", session.ExpiresAt), zap.String("reason", session.Reason))
+	return session, nil
+}
+
+// Record 记录模拟登录会话中的一次请求，审计日志的操作人为执行模拟的管理员，状态码 4xx/5xx 的请求记为失败
+func (s *impersonationService) Record(ctx context.Context, req *models.ImpersonatedRequest) error {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"method":     req.Method,
+		"path":       req.Path,
+		"status":     req.Status,
+		"ip_address": req.IPAddress,
+		"request_id": req.RequestID,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化审计信息失败: %w", err)
+	}
+	resourceType := "user"
+	return s.repoManager.AuditLog().Create(ctx, &models.AuditLog{
+		UserID:       &req.ImpersonatorID,
+		Action:       models.AuditActionImpersonationRequest,
+		ResourceType: &resourceType,
+		ResourceID:   &req.UserID,
+		Success:      req.Status < 400,
+		Metadata:     metadata,
+	})
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeAuditLogRepository 内存中的审计日志
+type fakeAuditLogRepository struct {
+	repository.AuditLogRepository
+	logs []*models.AuditLog
+}
+
+func (r *fakeAuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+type impersonationRepoManager struct {
+	*MockRepositoryManager
+	users  *fakeChatOpsUserRepository
+	audits *fakeAuditLogRepository
+}
+
+func (m *impersonationRepoManager) User() repository.UserRepository { return m.users }
+
+func (m *impersonationRepoManager) AuditLog() repository.AuditLogRepository { return m.audits }
+
+func TestImpersonationService_Start(t *testing.T) {
+	repoManager := &impersonationRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		users: &fakeChatOpsUserRepository{users: []*models.User{
+			{ID: "admin-1", Username: "root", Role: models.UserRoleAdmin, Status: models.UserStatusActive},
+			{ID: "admin-2", Username: "ops-lead", Role: models.UserRoleAdmin, Status: models.UserStatusActive},
+			{ID: "u1", Username: "alice", Role: models.UserRoleViewer, Status: models.UserStatusActive},
+			{ID: "u2", Username: "bob", Role: models.UserRoleOperator, Status: models.UserStatusInactive},
+		}},
+		audits: &fakeAuditLogRepository{},
+	}
+	svc := NewImpersonationService(repoManager, zap.NewNop())
+	ctx := context.Background()
+
+	session, err := svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "u1", Reason: "工单 #42 看不到告警"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", session.User.Username)
+	assert.Equal(t, "root", session.ImpersonatorName)
+	assert.Equal(t, models.ImpersonationScopeReadOnly, session.Scope)
+	assert.WithinDuration(t, time.Now().Add(models.ImpersonationDefaultDuration), session.ExpiresAt, time.Minute)
+
+	// 开始模拟登录记录审计日志，操作人为管理员
+	require.Len(t, repoManager.audits.logs, 1)
+	audit := repoManager.audits.logs[0]
+	assert.Equal(t, models.AuditActionImpersonationStart, audit.Action)
+	assert.Equal(t, "admin-1", *audit.UserID)
+	assert.Equal(t, "u1", *audit.ResourceID)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(audit.Metadata, &metadata))
+	assert.Equal(t, "工单 #42 看不到告警", metadata["reason"])
+
+	// 不能模拟自己、管理员和已禁用的用户，有效期不能超过上限
+	_, err = svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "admin-1", Reason: "x"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "admin-2", Reason: "x"})
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+	_, err = svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "u2", Reason: "x"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "u1", Reason: "x", DurationMinutes: 120})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Start(ctx, "admin-1", &models.ImpersonationRequest{UserID: "u1", Reason: " "})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	assert.Len(t, repoManager.audits.logs, 1)
+
+	// 会话中的请求按状态码记录成功或失败
+	require.NoError(t, svc.Record(ctx, &models.ImpersonatedRequest{
+		ImpersonatorID: "admin-1", UserID: "u1", Method: "POST", Path: "/api/v1/alerts/:id/ack", Status: 403,
+	}))
+	require.Len(t, repoManager.audits.logs, 2)
+	assert.Equal(t, models.AuditActionImpersonationRequest, repoManager.audits.logs[1].Action)
+	assert.False(t, repoManager.audits.logs[1].Success)
+}
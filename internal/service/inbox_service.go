@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/i18n"
+	"pulse/internal/repository"
+)
+
+const (
+	// inboxTitleMaxLength 站内通知标题的最大字符数
+	inboxTitleMaxLength = 255
+	// inboxStreamBatchSize 实时推送每次读取的新通知条数
+	inboxStreamBatchSize = 100
+	// inboxSLABatchSize 每次检查最多通知的 SLA 超时工单数，其余的在下一周期通知
+	inboxSLABatchSize = 500
+)
+
+// inboxService 站内通知收件箱服务实现
+type inboxService struct {
+	repoManager repository.RepositoryManager
+	translator  *i18n.Bundle
+	cfg         config.InboxConfig
+	hub         *inboxHub
+	logger      *zap.Logger
+}
+
+// NewInboxService 创建站内通知收件箱服务实例，translator 为 nil 时标题和内容按原样投递
+func NewInboxService(repoManager repository.RepositoryManager, translator *i18n.Bundle, cfg config.InboxConfig, logger *zap.Logger) InboxService {
+	return &inboxService{
+		repoManager: repoManager,
+		translator:  translator,
+		cfg:         cfg,
+		hub:         newInboxHub(),
+		logger:      logger,
+	}
+}
+
+// Notify 向用户投递站内通知，投递失败只记录日志
+func (s *inboxService) Notify(ctx context.Context, item *models.InboxItem) {
+	if _, err := s.deliver(ctx, item); err != nil {
+		s.logger.Warn("投递站内通知失败", zap.Error(err), zap.String("user_id", item.UserID),
+			zap.String("kind", string(item.Kind)), zap.String("resource_id", item.ResourceID))
+	}
+}
+
+// NotifyMentions 向 content 中 @ 提及的用户投递通知，item 为通知模板。提及人自己和不存在的用户名被忽略
+func (s *inboxService) NotifyMentions(ctx context.Context, content string, item models.InboxItem) {
+	usernames := models.ParseMentions(content)
+	if len(usernames) == 0 {
+		return
+	}
+
+	data := make(map[string]interface{}, len(item.MessageData)+1)
+	for key, value := range item.MessageData {
+		data[key] = value
+	}
+	data["Actor"] = ""
+	if item.ActorID != nil {
+		if actor, err := s.repoManager.User().GetByID(ctx, *item.ActorID); err == nil {
+			data["Actor"] = actor.Username
+		}
+	}
+
+	for _, username := range usernames {
+		user, err := s.repoManager.User().GetByUsername(ctx, username)
+		if err != nil || (item.ActorID != nil && user.ID == *item.ActorID) {
+			continue
+		}
+		mention := item
+		mention.UserID = user.ID
+		mention.Kind = models.InboxKindMentioned
+		mention.MessageData = data
+		s.Notify(ctx, &mention)
+	}
+}
+
+// deliver 按接收人的语言渲染并保存通知，通知订阅者。相同去重键的通知已投递时返回 false
+func (s *inboxService) deliver(ctx context.Context, item *models.InboxItem) (bool, error) {
+	if item.UserID == "" {
+		return false, fmt.Errorf("%w: 站内通知接收人不能为空", models.ErrInvalidInput)
+	}
+
+	s.localize(ctx, item)
+	item.Title = truncateRunes(item.Title, inboxTitleMaxLength)
+	created, err := s.repoManager.Inbox().Create(ctx, item)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		s.hub.publish(item.UserID)
+	}
+	return created, nil
+}
+
+// localize 按接收人的语言渲染通知的标题和内容
+func (s *inboxService) localize(ctx context.Context, item *models.InboxItem) {
+	if item.MessageKey == "" || s.translator == nil {
+		return
+	}
+
+	locale, _ := resolveLocale(ctx, s.repoManager.LanguagePreference(), s.translator, item.UserID, "")
+	item.Title = s.translator.Message(locale, item.MessageKey+".title", item.MessageData)
+	if item.Body == "" {
+		item.Body = s.translator.Message(locale, item.MessageKey+".body", item.MessageData)
+	}
+}
+
+// List 获取用户的站内通知和未读数
+func (s *inboxService) List(ctx context.Context, userID string, filter *models.InboxFilter) (*models.InboxList, error) {
+	items, err := s.repoManager.Inbox().List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repoManager.Inbox().CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InboxList{Items: items, Unread: unread}, nil
+}
+
+// UnreadCount 获取用户的未读通知数
+func (s *inboxService) UnreadCount(ctx context.Context, userID string) (int, error) {
+	return s.repoManager.Inbox().CountUnread(ctx, userID)
+}
+
+// MarkRead 将用户的通知标记为已读，返回剩余的未读数。其他用户的通知被忽略
+func (s *inboxService) MarkRead(ctx context.Context, userID string, ids []string) (int, error) {
+	marked, err := s.repoManager.Inbox().MarkRead(ctx, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return s.afterRead(ctx, userID, marked)
+}
+
+// MarkAllRead 将用户的全部通知标记为已读
+func (s *inboxService) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	marked, err := s.repoManager.Inbox().MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return s.afterRead(ctx, userID, marked)
+}
+
+// afterRead 已读状态变化时通知订阅者更新未读数，返回剩余的未读数
+func (s *inboxService) afterRead(ctx context.Context, userID string, marked int64) (int, error) {
+	if marked > 0 {
+		s.hub.publish(userID)
+	}
+	return s.repoManager.Inbox().CountUnread(ctx, userID)
+}
+
+// Subscribe 订阅用户收件箱的变化，本实例投递新通知或已读状态变化时 changes 收到信号，连接断开时调用 cancel。
+// 其他实例投递的通知由订阅方按 StreamPollInterval 周期检查
+func (s *inboxService) Subscribe(userID string) (<-chan struct{}, func()) {
+	return s.hub.subscribe(userID)
+}
+
+// Since 获取 Seq 大于 after 的新通知，按 Seq 升序
+func (s *inboxService) Since(ctx context.Context, userID string, after int64) ([]*models.InboxItem, error) {
+	return s.repoManager.Inbox().ListAfter(ctx, userID, after, inboxStreamBatchSize)
+}
+
+// LatestSeq 获取用户最新一条通知的 Seq，实时推送从该位置之后开始
+func (s *inboxService) LatestSeq(ctx context.Context, userID string) (int64, error) {
+	return s.repoManager.Inbox().LatestSeq(ctx, userID)
+}
+
+// StreamPollInterval 实时推送检查其他实例投递的通知的周期
+func (s *inboxService) StreamPollInterval() time.Duration {
+	return s.cfg.StreamPollInterval
+}
+
+// Interval SLA 超时检查周期
+func (s *inboxService) Interval() time.Duration {
+	return s.cfg.SLACheckInterval
+}
+
+// CheckSLABreaches 通知处理人已超出 SLA 截止时间的工单，每个工单对同一处理人只通知一次，返回投递的通知数
+func (s *inboxService) CheckSLABreaches(ctx context.Context, now time.Time) (int, error) {
+	breaches, err := s.repoManager.Inbox().ListSLABreaches(ctx, now, inboxSLABatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, breach := range breaches {
+		key := models.SLABreachDedupeKey(breach.TicketID)
+		created, err := s.deliver(ctx, &models.InboxItem{
+			UserID:       breach.AssigneeID,
+			Kind:         models.InboxKindSLABreach,
+			ResourceType: models.InboxResourceTicket,
+			ResourceID:   breach.TicketID,
+			DedupeKey:    &key,
+			MessageKey:   "inbox.sla_breach",
+			MessageData: map[string]interface{}{
+				"Number":   breach.Number,
+				"Title":    breach.Title,
+				"Deadline": breach.SLADeadline.Format(time.RFC3339),
+			},
+		})
+		if err != nil {
+			s.logger.Error("投递SLA超时通知失败", zap.Error(err), zap.String("ticket_id", breach.TicketID))
+			continue
+		}
+		if created {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// inboxHub 进程内的收件箱变化订阅。信号通道容量为 1，订阅方未及时处理的信号合并为一个
+type inboxHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func newInboxHub() *inboxHub {
+	return &inboxHub{subscribers: make(map[string]map[chan struct{}]struct{})}
+}
+
+// subscribe 订阅用户收件箱的变化
+func (h *inboxHub) subscribe(userID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+		})
+	}
+}
+
+// publish 通知用户的所有订阅者，不阻塞
+func (h *inboxHub) publish(userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
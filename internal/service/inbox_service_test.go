@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeInboxRepository 内存中的站内通知
+type fakeInboxRepository struct {
+	items    []*models.InboxItem
+	breaches []*models.InboxSLABreach
+}
+
+func (r *fakeInboxRepository) Create(ctx context.Context, item *models.InboxItem) (bool, error) {
+	for _, existing := range r.items {
+		if item.DedupeKey != nil && existing.DedupeKey != nil && *existing.DedupeKey == *item.DedupeKey && existing.UserID == item.UserID {
+			return false, nil
+		}
+	}
+	item.Seq = int64(len(r.items) + 1)
+	item.ID = fmt.Sprintf("n%d", item.Seq)
+	r.items = append(r.items, item)
+	return true, nil
+}
+
+func (r *fakeInboxRepository) List(ctx context.Context, userID string, filter *models.InboxFilter) ([]*models.InboxItem, error) {
+	var items []*models.InboxItem
+	for _, item := range r.items {
+		if item.UserID == userID && (!filter.UnreadOnly || item.ReadAt == nil) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Seq > items[j].Seq })
+	return items, nil
+}
+
+func (r *fakeInboxRepository) ListAfter(ctx context.Context, userID string, after int64, limit int) ([]*models.InboxItem, error) {
+	var items []*models.InboxItem
+	for _, item := range r.items {
+		if item.UserID == userID && item.Seq > after && len(items) < limit {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeInboxRepository) LatestSeq(ctx context.Context, userID string) (int64, error) {
+	var seq int64
+	for _, item := range r.items {
+		if item.UserID == userID {
+			seq = item.Seq
+		}
+	}
+	return seq, nil
+}
+
+func (r *fakeInboxRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	count := 0
+	for _, item := range r.items {
+		if item.UserID == userID && item.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeInboxRepository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	var marked int64
+	now := time.Now()
+	for _, item := range r.items {
+		for _, id := range ids {
+			if item.ID == id && item.UserID == userID && item.ReadAt == nil {
+				item.ReadAt = &now
+				marked++
+			}
+		}
+	}
+	return marked, nil
+}
+
+func (r *fakeInboxRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	var ids []string
+	for _, item := range r.items {
+		ids = append(ids, item.ID)
+	}
+	return r.MarkRead(ctx, userID, ids)
+}
+
+func (r *fakeInboxRepository) ListSLABreaches(ctx context.Context, now time.Time, limit int) ([]*models.InboxSLABreach, error) {
+	var breaches []*models.InboxSLABreach
+	for _, breach := range r.breaches {
+		if breach.SLADeadline.Before(now) {
+			breaches = append(breaches, breach)
+		}
+	}
+	return breaches, nil
+}
+
+type inboxRepoManager struct {
+	*notificationCalendarRepoManager
+	inbox *fakeInboxRepository
+}
+
+func (m *inboxRepoManager) Inbox() repository.InboxRepository { return m.inbox }
+
+func newInboxTestService(t *testing.T) (InboxService, *inboxRepoManager) {
+	repoManager := &inboxRepoManager{
+		notificationCalendarRepoManager: newLocalizationTestRepoManager(),
+		inbox:                           &fakeInboxRepository{},
+	}
+	svc := NewInboxService(repoManager, testTranslator(t), config.InboxConfig{StreamPollInterval: time.Second}, zap.NewNop())
+	return svc, repoManager
+}
+
+// received 检查订阅是否收到信号
+func received(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestInboxService_NotifyAndRead(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager := newInboxTestService(t)
+	require.NoError(t, repoManager.languages.Set(ctx, &models.LanguagePreference{
+		Scope: models.LanguageScopeUser, Subject: "u2", Language: "en-US",
+	}))
+
+	changes, cancel := svc.Subscribe("u1")
+	defer cancel()
+
+	ticket := &models.Ticket{ID: "t1", Number: "INC-1", Title: "支付失败", Priority: models.TicketPriorityHigh}
+	tickets := NewTicketService(repoManager, nil, nil, svc, nil, nil, nil, zap.NewNop()).(*ticketService)
+	tickets.notifyAssignee(ctx, ticket, "u1")
+	tickets.notifyAssignee(ctx, ticket, "u2")
+
+	// 通知按接收人的语言渲染，只通知对应用户的订阅者
+	assert.True(t, received(changes))
+	list, err := svc.List(ctx, "u1", &models.InboxFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "工单 INC-1《支付失败》已分派给您", list.Items[0].Title)
+	assert.Equal(t, 1, list.Unread)
+	english, err := svc.List(ctx, "u2", &models.InboxFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, `Ticket INC-1 "支付失败" was assigned to you`, english.Items[0].Title)
+
+	// 不能标记其他用户的通知，已读状态变化时通知订阅者
+	unread, err := svc.MarkRead(ctx, "u1", []string{english.Items[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+	assert.False(t, received(changes))
+	unread, err = svc.MarkAllRead(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, unread)
+	assert.True(t, received(changes))
+
+	cancel()
+	tickets.notifyAssignee(ctx, ticket, "u1")
+	assert.False(t, received(changes))
+	items, err := svc.Since(ctx, "u1", list.Items[0].Seq)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
+
+func TestInboxService_NotifyMentions(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager := newInboxTestService(t)
+	actor := "u1"
+
+	content := "@bob 请看一下，@alice 已确认，@nobody 和 carol@example.com 不会收到通知，@bob"
+	svc.NotifyMentions(ctx, content, models.InboxItem{
+		Body:         content,
+		ResourceType: models.InboxResourceKnowledge,
+		ResourceID:   "k1",
+		ActorID:      &actor,
+		MessageKey:   "inbox.mentioned_knowledge",
+		MessageData:  map[string]interface{}{"Title": "数据库故障处理"},
+	})
+
+	require.Len(t, repoManager.inbox.items, 1)
+	item := repoManager.inbox.items[0]
+	assert.Equal(t, "u2", item.UserID)
+	assert.Equal(t, models.InboxKindMentioned, item.Kind)
+	assert.Equal(t, "alice 在知识库文章《数据库故障处理》的评论中提到了您", item.Title)
+	assert.Equal(t, content, item.Body)
+}
+
+func TestInboxService_CheckSLABreaches(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager := newInboxTestService(t)
+	now := time.Now()
+	repoManager.inbox.breaches = []*models.InboxSLABreach{
+		{TicketID: "t1", Number: "INC-1", Title: "支付失败", AssigneeID: "u1", SLADeadline: now.Add(-time.Minute)},
+		{TicketID: "t2", Number: "INC-2", Title: "搜索变慢", AssigneeID: "u2", SLADeadline: now.Add(time.Hour)},
+	}
+
+	notified, err := svc.CheckSLABreaches(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notified)
+	assert.Equal(t, "工单 INC-1 已超出 SLA 截止时间", repoManager.inbox.items[0].Title)
+
+	// 同一工单对同一处理人只通知一次
+	notified, err = svc.CheckSLABreaches(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, notified)
+	notified, err = svc.CheckSLABreaches(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, notified)
+	assert.Len(t, repoManager.inbox.items, 2)
+}
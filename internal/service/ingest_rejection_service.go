@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// ingestRejectionDefaultLimit 默认返回的拒绝记录条数
+	ingestRejectionDefaultLimit = 50
+	// ingestRejectionMaxLimit 单次最多返回的拒绝记录条数
+	ingestRejectionMaxLimit = 200
+	// ingestRejectionCountWindow 拒绝原因统计的时间窗口
+	ingestRejectionCountWindow = 24 * time.Hour
+)
+
+// ingestRejectionService 告警接入拒绝记录服务实现，进程内按原因和集成累计拒绝次数
+type ingestRejectionService struct {
+	repoManager repository.RepositoryManager
+	mode        models.IngestSchemaMode
+	sampleRate  float64
+	keep        int
+	logger      *zap.Logger
+
+	// sample 返回 [0,1) 的随机数，测试中替换
+	sample func() float64
+	now    func() time.Time
+
+	mu            sync.Mutex
+	byReason      map[models.IngestRejectReason]int64
+	byIntegration map[string]map[models.IngestRejectReason]int64
+}
+
+// NewIngestRejectionService 创建告警接入拒绝记录服务实例，未配置或无效的校验模式按 lenient 处理
+func NewIngestRejectionService(repoManager repository.RepositoryManager, cfg config.IngestSchemaConfig, logger *zap.Logger) IngestRejectionService {
+	mode := models.IngestSchemaMode(cfg.Mode)
+	if !mode.IsValid() {
+		mode = models.IngestSchemaLenient
+	}
+	return &ingestRejectionService{
+		repoManager:   repoManager,
+		mode:          mode,
+		sampleRate:    cfg.RejectionSampleRate,
+		keep:          cfg.RejectionKeep,
+		logger:        logger,
+		sample:        rand.Float64,
+		now:           time.Now,
+		byReason:      make(map[models.IngestRejectReason]int64),
+		byIntegration: make(map[string]map[models.IngestRejectReason]int64),
+	}
+}
+
+// Mode 获取接入载荷的校验模式
+func (s *ingestRejectionService) Mode() models.IngestSchemaMode {
+	return s.mode
+}
+
+// Record 记录被拒绝的接入请求并累计拒绝次数，按采样率保存原始载荷。err 不是载荷校验错误时忽略，
+// 保存失败只记录日志，不影响接入请求的响应
+func (s *ingestRejectionService) Record(ctx context.Context, integrationID string, endpoint models.IngestEndpoint, clientIP string, body []byte, err error) {
+	var schemaErr *models.IngestSchemaError
+	if !errors.As(err, &schemaErr) {
+		return
+	}
+
+	s.mu.Lock()
+	s.byReason[schemaErr.Reason]++
+	counts := s.byIntegration[integrationID]
+	if counts == nil {
+		counts = make(map[models.IngestRejectReason]int64)
+		s.byIntegration[integrationID] = counts
+	}
+	counts[schemaErr.Reason]++
+	s.mu.Unlock()
+
+	rejection := &models.IngestRejection{
+		IntegrationID: integrationID,
+		Endpoint:      endpoint,
+		Reason:        schemaErr.Reason,
+		Detail:        schemaErr.Detail,
+		PayloadSize:   len(body),
+		ClientIP:      clientIP,
+		ReceivedAt:    s.now(),
+	}
+	if s.sampleRate > 0 && s.sample() < s.sampleRate {
+		payload := truncatePayload(body)
+		rejection.Payload = &payload
+	}
+	if err := s.repoManager.IngestRejection().Create(ctx, rejection, s.keep); err != nil {
+		s.logger.Warn("保存告警接入拒绝记录失败", zap.Error(err), zap.String("integration_id", integrationID))
+		return
+	}
+	s.logger.Info("告警接入载荷被拒绝", zap.String("integration_id", integrationID), zap.String("endpoint", string(endpoint)),
+		zap.String("reason", string(schemaErr.Reason)), zap.String("detail", schemaErr.Detail))
+}
+
+// Report 获取集成最近的拒绝记录和最近 24 小时按原因的拒绝次数
+func (s *ingestRejectionService) Report(ctx context.Context, integrationID string, reason *models.IngestRejectReason, limit int) (*models.IngestRejectionReport, error) {
+	if _, err := s.repoManager.WebhookIntegration().GetByID(ctx, integrationID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = ingestRejectionDefaultLimit
+	}
+	if limit > ingestRejectionMaxLimit {
+		limit = ingestRejectionMaxLimit
+	}
+
+	rejections, err := s.repoManager.IngestRejection().List(ctx, &models.IngestRejectionFilter{
+		IntegrationID: integrationID, Reason: reason, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repoManager.IngestRejection().CountByReason(ctx, integrationID, s.now().Add(-ingestRejectionCountWindow))
+	if err != nil {
+		return nil, err
+	}
+	if rejections == nil {
+		rejections = []*models.IngestRejection{}
+	}
+	if counts == nil {
+		counts = []*models.IngestRejectionCount{}
+	}
+	return &models.IngestRejectionReport{
+		IntegrationID: integrationID,
+		Mode:          s.mode,
+		Counts:        counts,
+		Rejections:    rejections,
+	}, nil
+}
+
+// Metrics 获取进程启动以来的拒绝总数、按原因和按集成的拒绝次数
+func (s *ingestRejectionService) Metrics() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	byReason := make(map[string]int64, len(s.byReason))
+	for reason, count := range s.byReason {
+		byReason[string(reason)] = count
+		total += count
+	}
+	byIntegration := make(map[string]map[string]int64, len(s.byIntegration))
+	for integrationID, counts := range s.byIntegration {
+		reasons := make(map[string]int64, len(counts))
+		for reason, count := range counts {
+			reasons[string(reason)] = count
+		}
+		byIntegration[integrationID] = reasons
+	}
+	return map[string]interface{}{
+		"mode":           string(s.mode),
+		"total":          total,
+		"by_reason":      byReason,
+		"by_integration": byIntegration,
+	}
+}
+
+// truncatePayload 截断原始载荷，不截断多字节字符，非 UTF-8 内容替换为替换字符
+func truncatePayload(body []byte) string {
+	payload := strings.ToValidUTF8(string(body), "�")
+	if len(payload) <= models.IngestRejectionPayloadMaxLength {
+		return payload
+	}
+	cut := models.IngestRejectionPayloadMaxLength
+	for cut > 0 && !utf8.RuneStart(payload[cut]) {
+		cut--
+	}
+	return payload[:cut] + "..."
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeIngestRejectionRepository 按保存顺序记录拒绝记录
+type fakeIngestRejectionRepository struct {
+	rejections []*models.IngestRejection
+	keep       int
+}
+
+func (r *fakeIngestRejectionRepository) Create(ctx context.Context, rejection *models.IngestRejection, keep int) error {
+	r.rejections = append(r.rejections, rejection)
+	r.keep = keep
+	return nil
+}
+
+func (r *fakeIngestRejectionRepository) List(ctx context.Context, filter *models.IngestRejectionFilter) ([]*models.IngestRejection, error) {
+	return r.rejections, nil
+}
+
+func (r *fakeIngestRejectionRepository) CountByReason(ctx context.Context, integrationID string, since time.Time) ([]*models.IngestRejectionCount, error) {
+	return nil, nil
+}
+
+type ingestRejectionRepoManager struct {
+	*webhookIntegrationRepoManager
+	rejections *fakeIngestRejectionRepository
+}
+
+func (m *ingestRejectionRepoManager) IngestRejection() repository.IngestRejectionRepository {
+	return m.rejections
+}
+
+func TestIngestRejectionService_RecordSamplesPayloads(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeIngestRejectionRepository{}
+	repoManager := &ingestRejectionRepoManager{
+		webhookIntegrationRepoManager: &webhookIntegrationRepoManager{
+			MockRepositoryManager: &MockRepositoryManager{},
+			repo:                  &fakeWebhookIntegrationRepository{integrations: map[string]*models.WebhookIntegration{"grafana": {ID: "grafana"}}},
+		},
+		rejections: repo,
+	}
+	cfg := config.IngestSchemaConfig{Mode: "strict", RejectionSampleRate: 0.5, RejectionKeep: 100}
+	svc := NewIngestRejectionService(repoManager, cfg, zap.NewNop()).(*ingestRejectionService)
+	assert.Equal(t, models.IngestSchemaStrict, svc.Mode())
+
+	samples := []float64{0.2, 0.7}
+	svc.sample = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+
+	body := []byte(`{"name": "` + strings.Repeat("磁", models.IngestRejectionPayloadMaxLength) + `"}`)
+	unknown := models.NewIngestSchemaError(models.IngestRejectUnknownField, "未知字段 \"team\"")
+	svc.Record(ctx, "grafana", models.IngestEndpointAlerts, "10.0.0.1", body, unknown)
+	svc.Record(ctx, "grafana", models.IngestEndpointAlerts, "10.0.0.1", []byte(`{}`),
+		models.NewIngestSchemaError(models.IngestRejectMissingField, "缺少 name"))
+
+	// 不是载荷校验错误时不记录
+	svc.Record(ctx, "grafana", models.IngestEndpointAlerts, "10.0.0.1", body, errors.New("数据库不可用"))
+
+	require.Len(t, repo.rejections, 2)
+	assert.Equal(t, 100, repo.keep)
+	sampled := repo.rejections[0]
+	assert.Equal(t, models.IngestRejectUnknownField, sampled.Reason)
+	assert.Equal(t, len(body), sampled.PayloadSize)
+	require.NotNil(t, sampled.Payload)
+	assert.True(t, strings.HasSuffix(*sampled.Payload, "..."))
+	assert.LessOrEqual(t, len(*sampled.Payload), models.IngestRejectionPayloadMaxLength+len("..."))
+	assert.Nil(t, repo.rejections[1].Payload, "未被采样的记录不保存原始载荷")
+
+	metrics := svc.Metrics()
+	assert.Equal(t, int64(2), metrics["total"])
+	assert.Equal(t, map[string]int64{"unknown_field": 1, "missing_field": 1}, metrics["by_reason"])
+	assert.Equal(t, int64(1), metrics["by_integration"].(map[string]map[string]int64)["grafana"]["unknown_field"])
+
+	report, err := svc.Report(ctx, "grafana", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.IngestSchemaStrict, report.Mode)
+	assert.Len(t, report.Rejections, 2)
+	assert.NotNil(t, report.Counts)
+
+	_, err = svc.Report(ctx, "missing", nil, 0)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/graphql"
+	"pulse/internal/push"
 )
 
 // AlertService 告警服务接口
@@ -11,12 +16,19 @@ type AlertService interface {
 	Create(ctx context.Context, alert *models.Alert) error
 	GetByID(ctx context.Context, id string) (*models.Alert, error)
 	List(ctx context.Context, filter *models.AlertFilter) ([]*models.Alert, int64, error)
+	Stream(ctx context.Context, filter *models.AlertFilter, fn func(*models.Alert) error) error
 	Update(ctx context.Context, alert *models.Alert) error
 	Delete(ctx context.Context, id string) error
 	Acknowledge(ctx context.Context, id string, userID string) error
 	Resolve(ctx context.Context, id string, userID string) error
 }
 
+// AlertTimelineService 告警时间线服务接口
+type AlertTimelineService interface {
+	GetTimeline(ctx context.Context, alertID string) (*models.AlertTimeline, error)
+	AddComment(ctx context.Context, alertID, userID, comment string) (*models.AlertTimelineEvent, error)
+}
+
 // RuleService 规则服务接口
 type RuleService interface {
 	Create(ctx context.Context, rule *models.Rule) error
@@ -26,6 +38,8 @@ type RuleService interface {
 	Delete(ctx context.Context, id string) error
 	Enable(ctx context.Context, id string) error
 	Disable(ctx context.Context, id string) error
+	// EvaluationLag 汇总启用规则的计划评估时间与实际评估时间的差距
+	EvaluationLag(ctx context.Context) (*models.RuleEvaluationLagReport, error)
 }
 
 // DataSourceService 数据源服务接口
@@ -43,20 +57,34 @@ type TicketService interface {
 	Create(ctx context.Context, ticket *models.Ticket) error
 	GetByID(ctx context.Context, id string) (*models.Ticket, error)
 	List(ctx context.Context, filter *models.TicketFilter) ([]*models.Ticket, int64, error)
+	Stream(ctx context.Context, filter *models.TicketFilter, fn func(*models.Ticket) error) error
 	Update(ctx context.Context, ticket *models.Ticket) error
 	Delete(ctx context.Context, id string) error
 	Assign(ctx context.Context, id string, assigneeID string) error
+	// AutoAssign 按团队的分派策略将工单重新分派给当前处理人以外的成员，用于升级
+	AutoAssign(ctx context.Context, id string, team string) (*models.Ticket, error)
 	UpdateStatus(ctx context.Context, id string, status models.TicketStatus) error
+
+	// 工作日志
+	AddWorklog(ctx context.Context, worklog *models.TicketWorklog) (*models.TicketWorkTime, error)
+	GetWorklogs(ctx context.Context, ticketID string) ([]*models.TicketWorklog, *models.TicketWorkTime, error)
+	UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) (*models.TicketWorkTime, error)
+	DeleteWorklog(ctx context.Context, ticketID, id string, userID string) (*models.TicketWorkTime, error)
+	GetTimesheet(ctx context.Context, filter *models.TimesheetFilter) (*models.Timesheet, error)
 }
 
 // KnowledgeService 知识库服务接口
 type KnowledgeService interface {
 	Create(ctx context.Context, knowledge *models.Knowledge) error
 	GetByID(ctx context.Context, id string) (*models.Knowledge, error)
+	GetBySlug(ctx context.Context, slug string) (*models.KnowledgeSlugLookup, error)
 	List(ctx context.Context, filter *models.KnowledgeFilter) ([]*models.Knowledge, int64, error)
 	Update(ctx context.Context, knowledge *models.Knowledge) error
 	Delete(ctx context.Context, id string) error
-	Search(ctx context.Context, query string) ([]*models.Knowledge, error)
+	Search(ctx context.Context, query string, viewerID *string) ([]*models.Knowledge, error)
+	Render(ctx context.Context, id string) (*models.KnowledgeRendered, error)
+	GetAttachment(ctx context.Context, knowledgeID, attachmentID string) (*models.KnowledgeAttachment, error)
+	CheckDuplicates(ctx context.Context, req *models.KnowledgeDuplicateCheckRequest) ([]*models.KnowledgeDuplicateCandidate, error)
 }
 
 // UserService 用户服务接口
@@ -85,6 +113,106 @@ type NotificationService interface {
 	SendBatch(ctx context.Context, notifications []*models.Notification) error
 	GetTemplates(ctx context.Context) ([]*models.NotificationTemplate, error)
 	CreateTemplate(ctx context.Context, template *models.NotificationTemplate) error
+
+	// 投递审计
+	GetDelivery(ctx context.Context, notificationID string) (*models.NotificationDelivery, error)
+	ListAlertDeliveries(ctx context.Context, alertID string) ([]*models.NotificationDelivery, error)
+	ListTicketDeliveries(ctx context.Context, ticketID string) ([]*models.NotificationDelivery, error)
+	// Resend 按原渠道和原内容重发通知，userID 为执行重发的用户
+	Resend(ctx context.Context, notificationID, userID string) (*models.NotificationAttempt, error)
+
+	// TestChannel 向渠道发送测试消息并返回 DNS、TLS、认证和渠道响应的检查结果
+	TestChannel(ctx context.Context, channel models.NotificationType, recipient string) (*models.ChannelTestResult, error)
+	ChannelTester
+}
+
+// ChannelTester 使用指定的渠道配置发送测试消息
+type ChannelTester interface {
+	// TestChannelConfig 使用团队通知渠道的地址、密钥和 SMTP 服务器发送测试消息
+	TestChannelConfig(ctx context.Context, channel *models.NotificationChannel, recipient string) (*models.ChannelTestResult, error)
+}
+
+// NotificationChannelService 团队通知渠道服务接口，团队自行维护通知渠道，返回的渠道不包含地址和密钥
+type NotificationChannelService interface {
+	Create(ctx context.Context, req *models.NotificationChannelRequest, userID string) (*models.NotificationChannel, error)
+	Get(ctx context.Context, id string) (*models.NotificationChannel, error)
+	List(ctx context.Context, filter *models.NotificationChannelFilter) ([]*models.NotificationChannel, error)
+	Update(ctx context.Context, id string, req *models.NotificationChannelRequest) (*models.NotificationChannel, error)
+	Delete(ctx context.Context, id string) error
+	// Test 使用渠道的配置发送测试消息
+	Test(ctx context.Context, id, recipient string) (*models.ChannelTestResult, error)
+}
+
+// AlertActionLinker 生成告警通知中的一键操作链接
+type AlertActionLinker interface {
+	// Links 返回操作类型到链接的映射，未启用操作链接时返回 nil
+	Links(ctx context.Context, alertID, userID string) (map[string]string, error)
+}
+
+// AlertActionLinkService 告警操作链接服务接口
+type AlertActionLinkService interface {
+	AlertActionLinker
+	Preview(ctx context.Context, token string) (*models.AlertActionLinkView, error)
+	Execute(ctx context.Context, token string) (*models.AlertActionLinkView, error)
+}
+
+// PushSender 向平台用户的移动设备发送推送通知
+type PushSender interface {
+	// Wants 检查用户是否接收该级别告警的推送
+	Wants(ctx context.Context, userID string, severity models.AlertSeverity) bool
+	// Push 向用户的所有推送设备发送推送，用户没有可用设备时返回 models.ErrNoPushDevice
+	Push(ctx context.Context, userID string, message *push.Message) error
+}
+
+// PushService 移动端推送服务接口
+type PushService interface {
+	PushSender
+	RegisterDevice(ctx context.Context, req *models.PushDeviceRequest, userID string) (*models.PushDevice, error)
+	ListDevices(ctx context.Context, userID string) ([]*models.PushDevice, error)
+	DeleteDevice(ctx context.Context, id, userID string) error
+	GetPreference(ctx context.Context, userID string) (*models.PushPreference, error)
+	SetPreference(ctx context.Context, req *models.PushPreferenceRequest, userID string) (*models.PushPreference, error)
+}
+
+// QuietHoursService 用户免打扰服务接口，免打扰期间由通知服务统一拦截不允许级别的通知
+type QuietHoursService interface {
+	Get(ctx context.Context, userID string) (*models.QuietHours, error)
+	SetSchedule(ctx context.Context, req *models.QuietHoursRequest, userID string) (*models.QuietHours, error)
+	StartDND(ctx context.Context, req *models.DNDRequest, userID string) (*models.QuietHours, error)
+	EndDND(ctx context.Context, userID string) (*models.QuietHours, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+// InboxNotifier 向用户的站内通知收件箱投递通知，投递失败只记录日志，不影响触发通知的操作
+type InboxNotifier interface {
+	Notify(ctx context.Context, item *models.InboxItem)
+	// NotifyMentions 向 content 中 @ 提及的用户投递通知，item 为通知模板，不通知提及人自己
+	NotifyMentions(ctx context.Context, content string, item models.InboxItem)
+}
+
+// EventPublisher 向出站 Webhook 订阅发布告警和工单事件，发布失败只记录日志，不影响触发事件的操作
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.WebhookEvent, data interface{})
+}
+
+// InboxService 站内通知收件箱服务接口
+type InboxService interface {
+	InboxNotifier
+	List(ctx context.Context, userID string, filter *models.InboxFilter) (*models.InboxList, error)
+	UnreadCount(ctx context.Context, userID string) (int, error)
+	// MarkRead 将通知标记为已读，返回剩余的未读数
+	MarkRead(ctx context.Context, userID string, ids []string) (int, error)
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+
+	// Subscribe 订阅用户收件箱的变化，实时推送连接断开时调用返回的取消函数
+	Subscribe(userID string) (<-chan struct{}, func())
+	Since(ctx context.Context, userID string, after int64) ([]*models.InboxItem, error)
+	LatestSeq(ctx context.Context, userID string) (int64, error)
+	StreamPollInterval() time.Duration
+
+	// CheckSLABreaches 通知处理人已超出 SLA 截止时间的工单，返回投递的通知数
+	CheckSLABreaches(ctx context.Context, now time.Time) (int, error)
+	Interval() time.Duration
 }
 
 // WebhookService Webhook服务接口
@@ -103,4 +231,610 @@ type ConfigService interface {
 	Set(ctx context.Context, key, value string) error
 	Delete(ctx context.Context, key string) error
 	List(ctx context.Context, prefix string) (map[string]string, error)
-}
\ No newline at end of file
+}
+// CustomFieldService 自定义字段服务接口
+type CustomFieldService interface {
+	Create(ctx context.Context, def *models.CustomFieldDefinition) error
+	GetByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error)
+	List(ctx context.Context, filter *models.CustomFieldDefinitionFilter) ([]*models.CustomFieldDefinition, int64, error)
+	Update(ctx context.Context, def *models.CustomFieldDefinition) error
+	Delete(ctx context.Context, id string) error
+
+	// 动态表单与校验
+	GetFormSchema(ctx context.Context, resource models.CustomFieldResource) ([]*models.CustomFieldDefinition, error)
+	Validate(ctx context.Context, resource models.CustomFieldResource, values map[string]interface{}) (map[string]interface{}, error)
+}
+
+// AttachmentScanService 附件安全扫描服务接口
+type AttachmentScanService interface {
+	Enabled() bool
+	ScanPending(ctx context.Context) (int, error)
+	ScanAttachment(ctx context.Context, attachment *models.TicketAttachment) error
+	GetDownloadableAttachment(ctx context.Context, ticketID, id string) (*models.TicketAttachment, error)
+}
+
+// KnowledgeReviewService 知识库审核服务接口
+type KnowledgeReviewService interface {
+	// 审核策略管理
+	CreatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error
+	GetPolicy(ctx context.Context, id string) (*models.KnowledgeReviewPolicy, error)
+	ListPolicies(ctx context.Context) ([]*models.KnowledgeReviewPolicy, error)
+	UpdatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error
+	DeletePolicy(ctx context.Context, id string) error
+
+	// 审核流程
+	SubmitForReview(ctx context.Context, knowledgeID, userID string) (*models.KnowledgeReviewSubmission, error)
+	Approve(ctx context.Context, knowledgeID, reviewerID string, comment *string) (*models.KnowledgeReviewSubmission, error)
+	Reject(ctx context.Context, knowledgeID, reviewerID string, comment *string) (*models.KnowledgeReviewSubmission, error)
+	GetReviewStatus(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error)
+	ListPendingReviews(ctx context.Context, userID string, page, pageSize int) (*models.KnowledgeReviewQueue, error)
+}
+
+// KnowledgeScheduleService 知识库定时发布与过期提醒服务接口
+type KnowledgeScheduleService interface {
+	Schedule(ctx context.Context, id string, req *models.KnowledgeScheduleRequest) (*models.Knowledge, error)
+	CancelSchedule(ctx context.Context, id string) error
+	UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error
+
+	// 后台调度
+	Interval() time.Duration
+	PublishDue(ctx context.Context) (int, error)
+	ExpireOverdue(ctx context.Context) (int64, error)
+	SendExpiryWarnings(ctx context.Context) (int, error)
+}
+
+// KnowledgeTransferService 知识库导入导出服务接口
+type KnowledgeTransferService interface {
+	Import(ctx context.Context, format models.KnowledgeImportFormat, data []byte, authorID string) (*models.KnowledgeImportResult, error)
+	Export(ctx context.Context, categoryIDs []string, viewerID *string, w io.Writer) (int, error)
+}
+
+// KnowledgeACLService 知识库访问控制服务接口
+type KnowledgeACLService interface {
+	GetACL(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID, userID string) ([]*models.KnowledgeACLEntry, error)
+	SetACL(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID, userID string, req *models.KnowledgeACLRequest) ([]*models.KnowledgeACLEntry, error)
+	CheckAccess(ctx context.Context, knowledgeID, userID string, permission models.KnowledgeACLPermission) error
+	ViewerScope(ctx context.Context, userID string) *string
+}
+
+// WebhookIntegrationService 告警接入集成服务接口
+type WebhookIntegrationService interface {
+	List(ctx context.Context) ([]*models.WebhookIntegration, error)
+	Get(ctx context.Context, id string) (*models.WebhookIntegration, error)
+	Create(ctx context.Context, integration *models.WebhookIntegration) (*models.WebhookIntegrationSecret, error)
+	Update(ctx context.Context, integration *models.WebhookIntegration) error
+	Delete(ctx context.Context, id string) error
+	RotateSecret(ctx context.Context, id string) (*models.WebhookIntegrationSecret, error)
+
+	// 接入请求校验
+	Verify(ctx context.Context, id, clientIP, signatureHeader, timestampHeader string, body []byte) (*models.WebhookIntegration, error)
+	VerifyAt(ctx context.Context, id, clientIP, signatureHeader, timestampHeader string, body []byte, receivedAt time.Time) (*models.WebhookIntegration, error)
+	VerifyToken(ctx context.Context, id, clientIP, token string) (*models.WebhookIntegration, error)
+
+	// 自定义 Webhook 载荷映射
+	IngestCustom(ctx context.Context, integration *models.WebhookIntegration, body []byte) (*models.CustomWebhookResult, error)
+	PreviewMapping(ctx context.Context, id string, req *models.WebhookMappingPreviewRequest) (*models.WebhookMappingPreview, error)
+}
+
+// FeatureFlagService 功能开关服务接口
+type FeatureFlagService interface {
+	List(ctx context.Context) ([]*models.FeatureFlag, error)
+	Get(ctx context.Context, key string) (*models.FeatureFlag, error)
+	Create(ctx context.Context, flag *models.FeatureFlag) error
+	Update(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, key string) error
+
+	// 开关评估
+	IsEnabled(ctx context.Context, key string, evalCtx models.FeatureFlagContext) bool
+	Evaluate(ctx context.Context, evalCtx models.FeatureFlagContext) (map[string]bool, error)
+	SetDefaults(flags []string)
+}
+
+// DashboardService 首页仪表盘服务接口
+type DashboardService interface {
+	GetSummary(ctx context.Context) (*models.DashboardSummary, error)
+}
+
+// PostmortemService 事件复盘服务接口
+type PostmortemService interface {
+	Templates() []models.PostmortemTemplate
+	Create(ctx context.Context, req *models.PostmortemCreateRequest, userID string) (*models.Postmortem, error)
+	Get(ctx context.Context, id string) (*models.Postmortem, error)
+	List(ctx context.Context, filter *models.PostmortemFilter) (*models.PostmortemList, error)
+	Update(ctx context.Context, id string, req *models.PostmortemUpdateRequest, userID string) (*models.Postmortem, error)
+	Delete(ctx context.Context, id string) error
+	Publish(ctx context.Context, id, userID string) (*models.Postmortem, error)
+
+	// 改进项
+	AddActionItem(ctx context.Context, postmortemID string, req *models.PostmortemActionItemRequest, userID string) (*models.PostmortemActionItem, error)
+	UpdateActionItem(ctx context.Context, postmortemID, itemID string, req *models.PostmortemActionItemRequest) (*models.PostmortemActionItem, error)
+	DeleteActionItem(ctx context.Context, postmortemID, itemID string) error
+}
+
+// RemediationService 规则修复动作服务接口
+type RemediationService interface {
+	Enabled() bool
+	Interval() time.Duration
+	Schedule(ctx context.Context) (int, error)
+	ExecuteQueued(ctx context.Context) (int, error)
+	Approve(ctx context.Context, id, userID string) (*models.RemediationExecution, error)
+	Reject(ctx context.Context, id, userID string) (*models.RemediationExecution, error)
+	ListByAlert(ctx context.Context, alertID string) ([]*models.RemediationExecution, error)
+}
+
+// ChatOpsService 聊天平台命令服务接口
+type ChatOpsService interface {
+	Enabled() bool
+	Verify(platform models.ChatPlatform, signatureHeader, timestampHeader string, body []byte) error
+	Execute(ctx context.Context, platform models.ChatPlatform, chatUserID, text string) (string, error)
+	ListUserMappings(ctx context.Context, platform models.ChatPlatform) ([]*models.ChatUserMapping, error)
+	BindUser(ctx context.Context, req *models.ChatUserMappingRequest, createdBy string) (*models.ChatUserMapping, error)
+	UnbindUser(ctx context.Context, id string) error
+}
+
+// ITSMService 外部 ITSM 工单同步服务接口
+type ITSMService interface {
+	Enabled() bool
+	Interval() time.Duration
+	ListConnectors(ctx context.Context) ([]*models.ITSMConnector, error)
+	GetConnector(ctx context.Context, id string) (*models.ITSMConnector, error)
+	CreateConnector(ctx context.Context, req *models.ITSMConnectorRequest, createdBy string) (*models.ITSMConnectorSecret, error)
+	UpdateConnector(ctx context.Context, id string, req *models.ITSMConnectorRequest) (*models.ITSMConnector, error)
+	DeleteConnector(ctx context.Context, id string) error
+	ListTicketLinks(ctx context.Context, ticketID string) ([]*models.ITSMTicketLink, error)
+	Sync(ctx context.Context) (int, error)
+	HandleWebhook(ctx context.Context, connectorID, token, signatureHeader string, body []byte) (*models.ITSMWebhookResult, error)
+}
+
+// HeartbeatService 心跳监控服务接口
+type HeartbeatService interface {
+	Interval() time.Duration
+	List(ctx context.Context) ([]*models.Heartbeat, error)
+	Get(ctx context.Context, id string) (*models.Heartbeat, error)
+	Create(ctx context.Context, req *models.HeartbeatRequest, createdBy string) (*models.Heartbeat, error)
+	Update(ctx context.Context, id string, req *models.HeartbeatRequest) (*models.Heartbeat, error)
+	Delete(ctx context.Context, id string) error
+	Status(ctx context.Context) (*models.HeartbeatStatusList, error)
+	Ping(ctx context.Context, token string) error
+	CheckOverdue(ctx context.Context) (int, error)
+}
+
+// ServiceAttributor 根据标签将告警和工单归属到服务目录中的服务
+type ServiceAttributor interface {
+	Attribute(ctx context.Context, labels map[string]string) (*models.CatalogService, error)
+}
+
+// Assigner 按团队的分派策略为工单和告警选择处理人
+type Assigner interface {
+	Pick(ctx context.Context, team string, target models.AssignmentTarget) (string, error)
+}
+
+// LabelGuard 统计入站告警标签的基数，并按策略丢弃或规范化高基数标签
+type LabelGuard interface {
+	Guard(alert *models.Alert)
+}
+
+// LabelCardinalityService 告警标签基数服务接口
+type LabelCardinalityService interface {
+	LabelGuard
+	// Report 获取标签基数报告，top 为返回的标签键数量
+	Report(ctx context.Context, top int) (*models.LabelCardinalityReport, error)
+}
+
+// LabelAutocompleteService 告警标签自动补全服务接口，为过滤界面和通知路由、告警暂停的匹配条件编辑器提供候选
+type LabelAutocompleteService interface {
+	// Keys 获取最近的告警中出现的标签键及告警数
+	Keys(ctx context.Context, query *models.LabelSuggestionQuery) (*models.LabelSuggestions, error)
+	// Values 获取最近的告警中标签键 key 的值及告警数
+	Values(ctx context.Context, key string, query *models.LabelSuggestionQuery) (*models.LabelSuggestions, error)
+}
+
+// SeverityClassifier 在告警接入时、分派和通知路由之前按重新分级规则调整告警级别
+type SeverityClassifier interface {
+	Reclassify(ctx context.Context, alert *models.Alert, tier models.ServiceTier)
+}
+
+// TriageScorer 在告警接入时调用外部分诊服务获取优先级评分和建议的处理人
+type TriageScorer interface {
+	Score(ctx context.Context, alert *models.Alert) (*models.AlertTriage, error)
+}
+
+// AlertSeverityRuleService 告警重新分级规则服务接口
+type AlertSeverityRuleService interface {
+	SeverityClassifier
+	List(ctx context.Context) ([]*models.AlertSeverityRule, error)
+	Get(ctx context.Context, id string) (*models.AlertSeverityRule, error)
+	Create(ctx context.Context, req *models.AlertSeverityRuleRequest, createdBy string) (*models.AlertSeverityRule, error)
+	Update(ctx context.Context, id string, req *models.AlertSeverityRuleRequest) (*models.AlertSeverityRule, error)
+	Delete(ctx context.Context, id string) error
+	// Test 按当前启用的规则试算告警的级别
+	Test(ctx context.Context, req *models.AlertSeverityRuleTestRequest) (*models.AlertSeverityRuleTestResult, error)
+}
+
+// StatusPageService 公开状态页服务接口
+type StatusPageService interface {
+	Interval() time.Duration
+	CacheTTL() time.Duration
+	// Page 获取公开状态页内容，内容在缓存时长内复用
+	Page(ctx context.Context) (*models.StatusPage, error)
+	ListIncidents(ctx context.Context, filter *models.StatusIncidentFilter) ([]*models.StatusIncident, error)
+	GetIncident(ctx context.Context, id string) (*models.StatusIncident, error)
+	CreateIncident(ctx context.Context, req *models.StatusIncidentRequest, createdBy string) (*models.StatusIncident, error)
+	UpdateIncident(ctx context.Context, id string, req *models.StatusIncidentRequest) (*models.StatusIncident, error)
+	// PostUpdate 发布事件进展
+	PostUpdate(ctx context.Context, id string, req *models.StatusIncidentUpdateRequest, createdBy string) (*models.StatusIncident, error)
+	DeleteIncident(ctx context.Context, id string) error
+	// Subscribe 订阅邮件通知并发送确认邮件
+	Subscribe(ctx context.Context, req *models.StatusSubscribeRequest) error
+	Subscription(ctx context.Context, token string) (*models.StatusSubscriptionView, error)
+	Confirm(ctx context.Context, token string) (*models.StatusSubscriptionView, error)
+	Unsubscribe(ctx context.Context, token string) error
+	// NotifyPending 邮件通知订阅者待通知的进展说明，返回发送的邮件数
+	NotifyPending(ctx context.Context) (int, error)
+}
+
+// ChangeCorrelator 查找告警触发前同一服务上最近的变更
+type ChangeCorrelator interface {
+	Correlate(ctx context.Context, alert *models.Alert) (*models.ChangeEvent, error)
+}
+
+// ChangeEventService 变更事件服务接口
+type ChangeEventService interface {
+	ChangeCorrelator
+	List(ctx context.Context, filter *models.ChangeEventFilter) ([]*models.ChangeEvent, int64, error)
+	Get(ctx context.Context, id string) (*models.ChangeEvent, error)
+	Create(ctx context.Context, req *models.ChangeEventRequest, createdBy string) (*models.ChangeEvent, error)
+	Delete(ctx context.Context, id string) error
+	// ForAlert 获取告警触发前关联窗口内同一服务的全部变更
+	ForAlert(ctx context.Context, alertID string) ([]*models.ChangeEvent, error)
+}
+
+// WebhookSubscriptionService 出站 Webhook 订阅服务接口
+type WebhookSubscriptionService interface {
+	EventPublisher
+	Interval() time.Duration
+	List(ctx context.Context) ([]*models.WebhookSubscription, error)
+	Get(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	Create(ctx context.Context, req *models.WebhookSubscriptionRequest, createdBy string) (*models.WebhookSubscriptionSecret, error)
+	Update(ctx context.Context, id string, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+	RotateSecret(ctx context.Context, id string) (*models.WebhookSubscriptionSecret, error)
+	ListDeliveries(ctx context.Context, id string, filter *models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error)
+	// Redeliver 将已投递或失败的事件重新加入投递队列
+	Redeliver(ctx context.Context, id, deliveryID string) (*models.WebhookDelivery, error)
+	// DeliverPending 按顺序投递各订阅到期的事件，返回本轮投递的次数
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+// GraphQLService GraphQL 查询服务接口
+type GraphQLService interface {
+	// Execute 以 userID 的身份执行查询，查询本身的错误在响应的 errors 中返回
+	Execute(ctx context.Context, req *graphql.Request, userID string) *graphql.Response
+	// SDL 以 Schema 定义语言返回可查询的类型
+	SDL() string
+}
+
+// AssignmentPreviewer 预览按团队的分派策略会选择的处理人，不推进轮询计数
+type AssignmentPreviewer interface {
+	Preview(ctx context.Context, team string, target models.AssignmentTarget) (*models.AssignmentPreview, error)
+}
+
+// AlertReplayService 告警回放服务接口
+type AlertReplayService interface {
+	Replay(ctx context.Context, req *models.AlertReplayRequest) (*models.AlertReplayReport, error)
+	Preview(ctx context.Context, req *models.RoutePreviewRequest) (*models.RoutePreview, error)
+}
+
+// AssignmentService 分派策略服务接口
+type AssignmentService interface {
+	Assigner
+	AssignmentPreviewer
+	List(ctx context.Context) ([]*models.AssignmentPolicy, error)
+	Get(ctx context.Context, id string) (*models.AssignmentPolicy, error)
+	Create(ctx context.Context, req *models.AssignmentPolicyRequest, createdBy string) (*models.AssignmentPolicy, error)
+	Update(ctx context.Context, id string, req *models.AssignmentPolicyRequest) (*models.AssignmentPolicy, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// HandoverService 交班报告服务接口
+type HandoverService interface {
+	ListSchedules(ctx context.Context) ([]*models.HandoverSchedule, error)
+	GetSchedule(ctx context.Context, id string) (*models.HandoverSchedule, error)
+	CreateSchedule(ctx context.Context, req *models.HandoverScheduleRequest, createdBy string) (*models.HandoverSchedule, error)
+	UpdateSchedule(ctx context.Context, id string, req *models.HandoverScheduleRequest) (*models.HandoverSchedule, error)
+	DeleteSchedule(ctx context.Context, id string) error
+
+	Generate(ctx context.Context, req *models.HandoverGenerateRequest, userID string) (*models.HandoverReport, error)
+	ListReports(ctx context.Context, team string, limit int) ([]*models.HandoverReport, error)
+	GetReport(ctx context.Context, id string) (*models.HandoverReport, error)
+
+	// 后台调度
+	Interval() time.Duration
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// ServiceCatalogService 服务目录服务接口
+type ServiceCatalogService interface {
+	ServiceAttributor
+	List(ctx context.Context) ([]*models.CatalogService, error)
+	Get(ctx context.Context, id string) (*models.CatalogServiceDetail, error)
+	Create(ctx context.Context, req *models.CatalogServiceRequest, createdBy string) (*models.CatalogService, error)
+	Update(ctx context.Context, id string, req *models.CatalogServiceRequest) (*models.CatalogService, error)
+	Delete(ctx context.Context, id string) error
+	SLO(ctx context.Context, id string, window time.Duration) (*models.ServiceSLO, error)
+}
+
+// ServiceHealthService 服务健康度服务接口
+type ServiceHealthService interface {
+	Health(ctx context.Context, id string) (*models.ServiceHealth, error)
+	Rollup(ctx context.Context) (*models.ServiceHealthRollup, error)
+}
+
+// RuleForecastService 规则预测服务接口
+type RuleForecastService interface {
+	Interval() time.Duration
+	Forecast(ctx context.Context, ruleID string) (*models.RuleForecast, error)
+	Evaluate(ctx context.Context) (int, error)
+}
+
+// SLOService SLO 服务接口
+type SLOService interface {
+	Interval() time.Duration
+	List(ctx context.Context, serviceID string) ([]*models.SLO, error)
+	Get(ctx context.Context, id string) (*models.SLO, error)
+	Create(ctx context.Context, req *models.SLORequest, createdBy string) (*models.SLO, error)
+	Update(ctx context.Context, id string, req *models.SLORequest) (*models.SLO, error)
+	Delete(ctx context.Context, id string) error
+	ServiceBudgets(ctx context.Context, serviceID string) (*models.ServiceSLOBudgets, error)
+	Evaluate(ctx context.Context) (int, error)
+}
+
+// AlertSnoozeService 告警暂停服务接口
+type AlertSnoozeService interface {
+	Interval() time.Duration
+	List(ctx context.Context, userID string) ([]*models.AlertSnooze, error)
+	Create(ctx context.Context, req *models.AlertSnoozeRequest, userID string) (*models.AlertSnooze, error)
+	Delete(ctx context.Context, id, userID string) error
+	IsSnoozed(ctx context.Context, userID string, alert *models.Alert) (bool, error)
+	CleanupExpired(ctx context.Context) (int64, error)
+}
+
+// NotificationCalendarService 通知日历服务接口
+type NotificationCalendarService interface {
+	Interval() time.Duration
+	List(ctx context.Context) ([]*models.NotificationCalendar, error)
+	Get(ctx context.Context, id string) (*models.NotificationCalendar, error)
+	Create(ctx context.Context, req *models.NotificationCalendarRequest, createdBy string) (*models.NotificationCalendar, error)
+	Update(ctx context.Context, id string, req *models.NotificationCalendarRequest) (*models.NotificationCalendar, error)
+	Delete(ctx context.Context, id string) error
+	SendDigests(ctx context.Context) (int, error)
+}
+
+// LocalizationService 多语言服务接口
+type LocalizationService interface {
+	Locales() []apierror.Locale
+	Enums(ctx context.Context, userID string, fallback apierror.Locale) (apierror.Locale, map[string]map[string]string)
+	GetLanguage(ctx context.Context, scope models.LanguageScope, subject string) (*models.LanguagePreference, error)
+	SetLanguage(ctx context.Context, scope models.LanguageScope, subject string, req *models.LanguagePreferenceRequest) (*models.LanguagePreference, error)
+	DeleteLanguage(ctx context.Context, scope models.LanguageScope, subject string) error
+}
+
+// AlertPartitionService 告警分区维护服务接口
+type AlertPartitionService interface {
+	Interval() time.Duration
+	Maintain(ctx context.Context) (*models.PartitionMaintenanceResult, error)
+}
+
+// DataRetentionService 数据保留服务接口
+type DataRetentionService interface {
+	Interval() time.Duration
+	Policies() []*models.RetentionPolicy
+	Status(ctx context.Context) ([]*models.RetentionStatus, error)
+	Run(ctx context.Context) (*models.RetentionRunResult, error)
+	ListArchives(ctx context.Context, filter *models.DataArchiveFilter) ([]*models.DataArchive, int64, error)
+	Restore(ctx context.Context, req *models.DataArchiveRestoreRequest, createdBy string) (*models.DataArchiveRestore, error)
+}
+
+// TagGovernanceService 标签治理服务接口
+type TagGovernanceService interface {
+	Interval() time.Duration
+	Usage(ctx context.Context, maxUsage int64) ([]*models.TagUsage, error)
+	CreateJob(ctx context.Context, req *models.TagJobRequest, createdBy string) (*models.TagJob, error)
+	GetJob(ctx context.Context, id string) (*models.TagJob, error)
+	ListJobs(ctx context.Context) ([]*models.TagJob, error)
+	RunPendingJobs(ctx context.Context) (int, error)
+}
+
+// TicketImportService 工单导入服务接口
+type TicketImportService interface {
+	Interval() time.Duration
+	// Upload 解析上传的文件并创建等待确认列映射的导入任务
+	Upload(ctx context.Context, fileName string, data []byte, createdBy string) (*models.TicketImportJob, error)
+	// Start 确认列映射并开始导入，任务由 Worker 在后台执行
+	Start(ctx context.Context, id string, req *models.TicketImportStartRequest) (*models.TicketImportJob, error)
+	GetJob(ctx context.Context, id string) (*models.TicketImportJob, error)
+	ListJobs(ctx context.Context) ([]*models.TicketImportJob, error)
+	RunPendingJobs(ctx context.Context) (int, error)
+}
+
+// AlertStatsService 告警统计服务接口，统计和趋势读取 Worker 维护的小时汇总表
+type AlertStatsService interface {
+	Interval() time.Duration
+	// Rollup 重算自上次汇总以来有告警变化的小时，返回写入的汇总行数
+	Rollup(ctx context.Context) (int, error)
+	Stats(ctx context.Context, query *models.AlertStatsQuery) (*models.AlertStatsSummary, error)
+	Trend(ctx context.Context, query *models.AlertStatsQuery) (*models.AlertStatsTrend, error)
+}
+
+// TicketSurveyService 工单满意度调查服务接口
+type TicketSurveyService interface {
+	Interval() time.Duration
+	// RunDue 向近期关闭工单的报告人发送评价链接，返回发送的调查数
+	RunDue(ctx context.Context) (int, error)
+	View(ctx context.Context, token string) (*models.TicketSurveyView, error)
+	Respond(ctx context.Context, token string, resp *models.TicketSurveyResponse) (*models.TicketSurveyView, error)
+	CSAT(ctx context.Context, filter *models.TicketSurveyFilter) (*models.TicketCSATReport, error)
+}
+
+// ApprovalService 敏感操作审批服务接口
+type ApprovalService interface {
+	// Submit 提交敏感操作，需要审批的操作保存为待审批请求，其他操作立即执行
+	Submit(ctx context.Context, userID string, req *models.ApprovalSubmitRequest) (*models.ApprovalRequest, error)
+	// Approve 批准请求并执行操作
+	Approve(ctx context.Context, id, userID string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error)
+	Reject(ctx context.Context, id, userID string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error)
+	// Cancel 申请人撤回待审批请求
+	Cancel(ctx context.Context, id, userID string) (*models.ApprovalRequest, error)
+	Get(ctx context.Context, id string) (*models.ApprovalRequest, error)
+	List(ctx context.Context, filter *models.ApprovalFilter) ([]*models.ApprovalRequest, error)
+	// History 获取请求的审计记录
+	History(ctx context.Context, id string) ([]*models.AuditLog, error)
+	ApprovalGate
+}
+
+// ApprovalGate 判断敏感操作是否配置为需要审批，直接执行敏感操作的代码路径需要先检查，不能绕过审批
+type ApprovalGate interface {
+	RequiresApproval(action models.ApprovalAction) bool
+}
+
+// IngestRejectionService 告警接入拒绝记录服务接口
+type IngestRejectionService interface {
+	// Mode 获取接入载荷的校验模式
+	Mode() models.IngestSchemaMode
+	// Record 记录被拒绝的接入请求，err 不是 *models.IngestSchemaError 时忽略
+	Record(ctx context.Context, integrationID string, endpoint models.IngestEndpoint, clientIP string, body []byte, err error)
+	Report(ctx context.Context, integrationID string, reason *models.IngestRejectReason, limit int) (*models.IngestRejectionReport, error)
+	// Metrics 获取进程启动以来的拒绝次数，供 /health/metrics 输出
+	Metrics() map[string]interface{}
+}
+
+// AsyncTaskService 异步任务服务接口
+type AsyncTaskService interface {
+	Interval() time.Duration
+	// Submit 提交异步任务，任务由 Worker 在后台执行
+	Submit(ctx context.Context, req *models.AsyncTaskRequest, userID string) (*models.AsyncTask, error)
+	// Get 获取用户提交的任务及其进度
+	Get(ctx context.Context, id, userID string) (*models.AsyncTask, error)
+	List(ctx context.Context, userID string) ([]*models.AsyncTask, error)
+	// ResultFile 获取用户提交的已完成任务的结果文件
+	ResultFile(ctx context.Context, id, userID string) (*models.AsyncTaskFile, error)
+	RunPending(ctx context.Context) (int, error)
+	// Cleanup 将执行超时的任务标记为失败，并删除结束超过保留时间的任务
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+// QueryCacheService 数据源查询结果缓存服务接口
+type QueryCacheService interface {
+	// Invalidate 使数据源的缓存查询结果失效，dataSourceID 为空时使全部缓存失效
+	Invalidate(ctx context.Context, dataSourceID string) error
+	// Metrics 获取进程启动以来的缓存命中次数，供 /health/metrics 输出
+	Metrics() map[string]interface{}
+}
+
+// KnowledgeFeedbackPrompter 工单解决时提示解决人评价工单引用的知识库文章，失败只记录日志
+type KnowledgeFeedbackPrompter interface {
+	PromptResolved(ctx context.Context, ticket *models.Ticket)
+}
+
+// KnowledgeFeedbackService 知识库文章有用性反馈服务接口
+type KnowledgeFeedbackService interface {
+	KnowledgeFeedbackPrompter
+	Link(ctx context.Context, ticketID, knowledgeID, userID string) (*models.TicketKnowledgeLink, error)
+	Unlink(ctx context.Context, ticketID, knowledgeID string) error
+	ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketKnowledgeLink, error)
+	// Submit 解决人评价引用的文章是否有用
+	Submit(ctx context.Context, ticketID, knowledgeID, userID string, req *models.KnowledgeFeedbackRequest) (*models.TicketKnowledgeLink, error)
+	// Pending 获取用户待评价的引用
+	Pending(ctx context.Context, userID string) ([]*models.TicketKnowledgeLink, error)
+	// Quality 获取文章质量，viewerID 非空时只返回该用户有权阅读的文章
+	Quality(ctx context.Context, flaggedOnly bool, limit int, viewerID *string) ([]*models.KnowledgeQuality, error)
+}
+
+// SLAClockSyncer 工单状态变化时自动暂停或恢复SLA计时，失败只记录日志
+type SLAClockSyncer interface {
+	SyncSLAClock(ctx context.Context, ticketID string, status models.TicketStatus)
+}
+
+// TicketSLAService 工单SLA暂停服务接口，工单等待客户或第三方回复期间暂停SLA计时
+type TicketSLAService interface {
+	SLAClockSyncer
+	// Pause 手动暂停SLA计时
+	Pause(ctx context.Context, ticketID, userID string, req *models.TicketSLAPauseRequest) (*models.TicketSLAPause, error)
+	// Resume 恢复SLA计时，截止时间顺延暂停的时长
+	Resume(ctx context.Context, ticketID, userID string) (*models.TicketSLAPause, error)
+	// Clock 获取工单的SLA计时状态和暂停记录
+	Clock(ctx context.Context, ticketID string) (*models.TicketSLAClock, error)
+}
+
+// AlertAutoResolveService 告警自动解决服务接口
+type AlertAutoResolveService interface {
+	Interval() time.Duration
+	ResolveStale(ctx context.Context) (int, error)
+}
+
+// KubernetesService Kubernetes 数据源采集服务接口
+type KubernetesService interface {
+	Interval() time.Duration
+	Sync(ctx context.Context) (int, error)
+	SyncDataSource(ctx context.Context, id string) (*models.KubernetesSyncResult, error)
+}
+
+// KeyRotationService 加密密钥轮换服务接口
+type KeyRotationService interface {
+	// RotateEncryptionKeys 将数据源配置、集成密钥、Webhook凭据、用户敏感信息和通知渠道凭据重新加密为当前主密钥版本
+	RotateEncryptionKeys(ctx context.Context) (*models.KeyRotationReport, error)
+}
+
+// KnowledgeCommentService 知识库评论服务接口
+type KnowledgeCommentService interface {
+	List(ctx context.Context, knowledgeID, viewerID string) ([]*models.KnowledgeComment, error)
+	Create(ctx context.Context, knowledgeID, authorID string, req *models.KnowledgeCommentCreateRequest) (*models.KnowledgeComment, error)
+	Update(ctx context.Context, id, userID, content string) (*models.KnowledgeComment, error)
+	Delete(ctx context.Context, id, userID string) error
+
+	// 互动与审核
+	React(ctx context.Context, id, userID, reaction string, add bool) (*models.KnowledgeComment, error)
+	Flag(ctx context.Context, id, userID string, reason *string) error
+	Moderate(ctx context.Context, id, moderatorID string, status models.KnowledgeCommentStatus, reason *string) (*models.KnowledgeComment, error)
+	ListFlagged(ctx context.Context, moderatorID string, limit int) ([]*models.KnowledgeComment, error)
+}
+
+// AuditLogService 审计日志服务接口
+type AuditLogService interface {
+	Stream(ctx context.Context, filter *models.AuditLogFilter, fn func(*models.AuditLog) error) error
+}
+
+// ImpersonationService 模拟登录服务接口，管理员以其他用户的身份复现权限和可见性问题
+type ImpersonationService interface {
+	// Start 校验并审计模拟登录，返回的会话由网关签发令牌
+	Start(ctx context.Context, impersonatorID string, req *models.ImpersonationRequest) (*models.ImpersonationSession, error)
+	// Record 审计模拟登录会话中的一次请求
+	Record(ctx context.Context, req *models.ImpersonatedRequest) error
+}
+
+// MetricService 内置指标存储服务接口，接收 Prometheus remote-write 协议推送的指标并提供即时查询
+type MetricService interface {
+	// RemoteWrite 解析 remote-write 请求体，存入内置指标存储或转发到配置的后端
+	RemoteWrite(ctx context.Context, body []byte) (*models.RemoteWriteResult, error)
+	// Query 执行即时查询，返回聚合后的值，没有数据时返回 ErrMetricNoData
+	Query(ctx context.Context, expr string, at time.Time) (float64, error)
+	// Evaluate 执行即时查询，返回聚合后的值和匹配的序列
+	Evaluate(ctx context.Context, expr string, at time.Time) (*models.MetricQueryResult, error)
+	// Interval 清理过期样本的周期
+	Interval() time.Duration
+	// Cleanup 删除超过保留时间的样本，返回删除的样本数
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+// ConfigApplyService 声明式配置服务接口，按配置文件创建、更新和删除数据源、规则、通知路由和告警暂停
+type ConfigApplyService interface {
+	Apply(ctx context.Context, bundle *models.ConfigBundle, opts models.ConfigApplyOptions, userID string) (*models.ConfigApplyResult, error)
+}
+
+// BackupService 平台配置备份与恢复服务接口
+type BackupService interface {
+	// Export 将平台配置写出为使用口令签名和加密的备份包
+	Export(ctx context.Context, passphrase, userID string, w io.Writer) (*models.BackupManifest, error)
+	// Restore 校验备份包后恢复到全新实例
+	Restore(ctx context.Context, data []byte, passphrase, userID string) (*models.BackupRestoreResult, error)
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/itsm"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// itsmSignaturePrefix Webhook 签名头的前缀，签名为请求体的 HMAC-SHA256 十六进制值
+const itsmSignaturePrefix = "sha256="
+
+// ITSMClient 外部 ITSM 系统客户端，由 itsm 包按连接器类型实现
+type ITSMClient interface {
+	CreateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) (*models.ITSMRemoteIssue, error)
+	UpdateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) error
+	AddComment(ctx context.Context, issue *models.ITSMRemoteIssue, body string) (string, error)
+	ParseWebhook(body []byte) (*models.ITSMWebhookEvent, error)
+}
+
+// ITSMClientFactory 根据连接器创建客户端
+type ITSMClientFactory func(connector *models.ITSMConnector) (ITSMClient, error)
+
+// itsmService 外部 ITSM 工单同步服务实现
+type itsmService struct {
+	repoManager repository.RepositoryManager
+	newClient   ITSMClientFactory
+	cfg         config.ITSMConfig
+	logger      *zap.Logger
+}
+
+// NewITSMService 创建外部 ITSM 工单同步服务实例
+func NewITSMService(repoManager repository.RepositoryManager, newClient ITSMClientFactory, cfg config.ITSMConfig, logger *zap.Logger) ITSMService {
+	return &itsmService{
+		repoManager: repoManager,
+		newClient:   newClient,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Enabled 是否启用工单同步
+func (s *itsmService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// Interval 推送工单的周期
+func (s *itsmService) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+// ListConnectors 获取连接器列表
+func (s *itsmService) ListConnectors(ctx context.Context) ([]*models.ITSMConnector, error) {
+	return s.repoManager.ITSM().ListConnectors(ctx, false)
+}
+
+// GetConnector 获取连接器
+func (s *itsmService) GetConnector(ctx context.Context, id string) (*models.ITSMConnector, error) {
+	return s.repoManager.ITSM().GetConnector(ctx, id)
+}
+
+// CreateConnector 创建连接器并生成 Webhook 密钥，密钥只在创建时返回一次
+func (s *itsmService) CreateConnector(ctx context.Context, req *models.ITSMConnectorRequest, createdBy string) (*models.ITSMConnectorSecret, error) {
+	connector := &models.ITSMConnector{SyncComments: true, Enabled: true, CreatedBy: createdBy}
+	req.ApplyTo(connector)
+	if err := s.validateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookIntegrationSecret()
+	if err != nil {
+		return nil, err
+	}
+	connector.WebhookSecret = secret
+
+	if err := s.repoManager.ITSM().CreateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建ITSM连接器", zap.String("connector_id", connector.ID),
+		zap.String("provider", string(connector.Provider)), zap.String("project", connector.Project))
+	return &models.ITSMConnectorSecret{Connector: connector, WebhookSecret: secret}, nil
+}
+
+// UpdateConnector 更新连接器，系统类型不可修改
+func (s *itsmService) UpdateConnector(ctx context.Context, id string, req *models.ITSMConnectorRequest) (*models.ITSMConnector, error) {
+	connector, err := s.repoManager.ITSM().GetConnector(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Provider != connector.Provider {
+		return nil, fmt.Errorf("%w: 不能修改连接器的ITSM系统类型", models.ErrInvalidInput)
+	}
+
+	req.ApplyTo(connector)
+	if err := s.validateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+	if err := s.repoManager.ITSM().UpdateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+	return connector, nil
+}
+
+// DeleteConnector 删除连接器
+func (s *itsmService) DeleteConnector(ctx context.Context, id string) error {
+	return s.repoManager.ITSM().DeleteConnector(ctx, id)
+}
+
+// ListTicketLinks 获取工单的外部工单关联
+func (s *itsmService) ListTicketLinks(ctx context.Context, ticketID string) ([]*models.ITSMTicketLink, error) {
+	return s.repoManager.ITSM().ListLinksByTicket(ctx, ticketID)
+}
+
+// validateConnector 验证连接器，同步用户必须存在，外部评论以该用户身份写入平台
+func (s *itsmService) validateConnector(ctx context.Context, connector *models.ITSMConnector) error {
+	if err := connector.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.repoManager.User().GetByID(ctx, connector.SyncUserID); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return fmt.Errorf("%w: 同步用户不存在", models.ErrInvalidInput)
+		}
+		return err
+	}
+	return nil
+}
+
+// Sync 将各启用连接器下新建或有修改的工单推送到外部系统，返回推送成功的工单数
+func (s *itsmService) Sync(ctx context.Context) (int, error) {
+	connectors, err := s.repoManager.ITSM().ListConnectors(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, connector := range connectors {
+		count, err := s.syncConnector(ctx, connector)
+		if err != nil {
+			s.logger.Error("同步ITSM连接器失败", zap.Error(err), zap.String("connector_id", connector.ID))
+			continue
+		}
+		synced += count
+	}
+	return synced, nil
+}
+
+// syncConnector 推送单个连接器下的待同步工单
+func (s *itsmService) syncConnector(ctx context.Context, connector *models.ITSMConnector) (int, error) {
+	client, err := s.newClient(connector)
+	if err != nil {
+		return 0, err
+	}
+
+	ticketIDs, err := s.repoManager.ITSM().ListTicketsToSync(ctx, connector, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, ticketID := range ticketIDs {
+		if err := s.pushTicket(ctx, connector, client, ticketID); err != nil {
+			s.logger.Warn("推送工单到ITSM系统失败", zap.Error(err),
+				zap.String("connector_id", connector.ID), zap.String("ticket_id", ticketID))
+			continue
+		}
+		synced++
+	}
+
+	if err := s.repoManager.ITSM().TouchConnectorSynced(ctx, connector.ID, time.Now()); err != nil {
+		s.logger.Warn("记录ITSM连接器同步时间失败", zap.Error(err), zap.String("connector_id", connector.ID))
+	}
+	return synced, nil
+}
+
+// pushTicket 推送工单字段和评论，未关联的工单在外部系统中创建。
+// 上次同步后外部工单也有修改且冲突策略以外部为准时只推送评论，字段由外部 Webhook 覆盖
+func (s *itsmService) pushTicket(ctx context.Context, connector *models.ITSMConnector, client ITSMClient, ticketID string) error {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.repoManager.ITSM().GetLink(ctx, connector.ID, ticketID)
+	if err != nil && !errors.Is(err, models.ErrITSMTicketLinkNotFound) {
+		return err
+	}
+
+	issue := s.remoteIssue(connector, ticket)
+	syncedAt := time.Now()
+
+	var pushErr error
+	if link == nil {
+		created, err := client.CreateIssue(ctx, issue)
+		if created == nil {
+			return err
+		}
+		// 创建成功但状态流转失败时仍保存关联，避免重复创建
+		pushErr = err
+		link = &models.ITSMTicketLink{
+			ConnectorID: connector.ID,
+			TicketID:    ticket.ID,
+			RemoteID:    created.ID,
+			RemoteKey:   created.Key,
+			RemoteURL:   created.URL,
+		}
+		issue = created
+	} else {
+		issue.ID = link.RemoteID
+		issue.Key = link.RemoteKey
+		issue.URL = link.RemoteURL
+		remoteChanged := link.RemoteUpdatedAt != nil && link.RemoteUpdatedAt.After(link.LastSyncedAt)
+		if remoteChanged && connector.ConflictStrategy.PreferRemote(ticket.UpdatedAt, *link.RemoteUpdatedAt) {
+			s.logger.Info("外部工单有更新的修改，跳过字段推送", zap.String("connector_id", connector.ID),
+				zap.String("ticket_id", ticket.ID), zap.String("remote_key", link.RemoteKey))
+		} else {
+			pushErr = client.UpdateIssue(ctx, issue)
+		}
+	}
+
+	if pushErr == nil && connector.SyncComments {
+		pushErr = s.pushComments(ctx, connector, client, ticket.ID, issue)
+	}
+
+	if pushErr != nil {
+		message := pushErr.Error()
+		link.LastError = &message
+		// 推送失败时不更新同步时间，下个周期重试
+		if link.LastSyncedAt.IsZero() {
+			link.LastSyncedAt = ticket.CreatedAt
+		}
+	} else {
+		link.LastError = nil
+		link.LastSyncedAt = syncedAt
+	}
+
+	if err := s.repoManager.ITSM().SaveLink(ctx, link); err != nil {
+		return err
+	}
+	return pushErr
+}
+
+// remoteIssue 按字段映射将平台工单转换为外部工单
+func (s *itsmService) remoteIssue(connector *models.ITSMConnector, ticket *models.Ticket) *models.ITSMRemoteIssue {
+	mapping := connector.FieldMapping
+	issue := &models.ITSMRemoteIssue{
+		Title:       fmt.Sprintf("[%s] %s", ticket.Number, ticket.Title),
+		Description: ticket.Description,
+		Status:      mapping.RemoteStatus(ticket.Status),
+		Priority:    mapping.RemotePriority(ticket.Priority),
+		IssueType:   mapping.IssueType,
+	}
+
+	for key, field := range mapping.CustomFields {
+		if value, ok := ticket.CustomFields[key]; ok {
+			if issue.Fields == nil {
+				issue.Fields = make(map[string]interface{})
+			}
+			issue.Fields[field] = value
+		}
+	}
+	return issue
+}
+
+// pushComments 推送尚未同步的公开评论，内部评论和同步用户写入的评论不推送
+func (s *itsmService) pushComments(ctx context.Context, connector *models.ITSMConnector, client ITSMClient, ticketID string, issue *models.ITSMRemoteIssue) error {
+	comments, err := s.repoManager.Ticket().GetComments(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+
+	synced, err := s.repoManager.ITSM().ListSyncedCommentIDs(ctx, connector.ID, ticketID)
+	if err != nil {
+		return err
+	}
+
+	authors := make(map[string]string)
+	for _, comment := range comments {
+		if synced[comment.ID] || comment.IsInternal || comment.AuthorID == connector.SyncUserID {
+			continue
+		}
+
+		author, ok := authors[comment.AuthorID]
+		if !ok {
+			author = comment.AuthorID
+			if user, err := s.repoManager.User().GetByID(ctx, comment.AuthorID); err == nil {
+				author = user.Username
+			}
+			authors[comment.AuthorID] = author
+		}
+
+		body := fmt.Sprintf("%s %s: %s", itsm.CommentMarker, author, comment.Content)
+		remoteID, err := client.AddComment(ctx, issue, body)
+		if err != nil {
+			return err
+		}
+
+		var remoteCommentID *string
+		if remoteID != "" {
+			remoteCommentID = &remoteID
+		}
+		if err := s.repoManager.ITSM().LinkComment(ctx, connector.ID, ticketID, comment.ID, remoteCommentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleWebhook 处理外部系统推送的工单变更。请求需携带 X-Hub-Signature 签名头或 token 查询参数之一。
+// 上次同步后平台工单没有修改时直接应用外部修改，双方都有修改时按冲突策略决定是否应用
+func (s *itsmService) HandleWebhook(ctx context.Context, connectorID, token, signatureHeader string, body []byte) (*models.ITSMWebhookResult, error) {
+	connector, err := s.repoManager.ITSM().GetConnector(ctx, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if !connector.Enabled {
+		return nil, models.ErrITSMConnectorDisabled
+	}
+	if err := verifyITSMWebhook(connector.WebhookSecret, token, signatureHeader, body); err != nil {
+		s.logger.Warn("ITSM Webhook 校验失败", zap.Error(err), zap.String("connector_id", connectorID))
+		return nil, err
+	}
+
+	client, err := s.newClient(connector)
+	if err != nil {
+		return nil, err
+	}
+	event, err := client.ParseWebhook(body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ITSMWebhookResult{}
+	link, err := s.repoManager.ITSM().GetLinkByRemote(ctx, connector.ID, event.Issue.ID)
+	if errors.Is(err, models.ErrITSMTicketLinkNotFound) {
+		result.IgnoredReason = "外部工单未关联平台工单"
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.TicketID = link.TicketID
+
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, link.TicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Comment != nil && connector.SyncComments {
+		added, err := s.pullComment(ctx, connector, link, event.Comment)
+		if err != nil {
+			return nil, err
+		}
+		result.CommentAdded = added
+	}
+
+	localChanged := ticket.UpdatedAt.After(link.LastSyncedAt)
+	keepLocal := localChanged && !connector.ConflictStrategy.PreferRemote(ticket.UpdatedAt, event.Issue.UpdatedAt)
+	result.Conflict = localChanged
+	if keepLocal {
+		s.logger.Info("平台工单与外部工单修改冲突，保留平台修改", zap.String("connector_id", connector.ID),
+			zap.String("ticket_id", ticket.ID), zap.String("remote_key", link.RemoteKey))
+	} else {
+		applied, err := s.applyRemote(ctx, connector, ticket, &event.Issue)
+		if err != nil {
+			return nil, err
+		}
+		result.FieldsApplied = applied
+	}
+
+	remoteUpdatedAt := event.Issue.UpdatedAt
+	link.RemoteUpdatedAt = &remoteUpdatedAt
+	if event.Issue.Key != "" {
+		link.RemoteKey = event.Issue.Key
+	}
+	// 保留平台修改时不更新同步时间，由同步 Worker 将平台修改推送到外部系统
+	if !keepLocal {
+		link.LastSyncedAt = time.Now()
+	}
+	if err := s.repoManager.ITSM().SaveLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// pullComment 将外部评论写入平台，平台同步出去的评论和已同步的评论不重复写入
+func (s *itsmService) pullComment(ctx context.Context, connector *models.ITSMConnector, link *models.ITSMTicketLink, comment *models.ITSMRemoteComment) (bool, error) {
+	if strings.TrimSpace(comment.Body) == "" || itsm.IsSyncedComment(comment.Body) {
+		return false, nil
+	}
+
+	var remoteCommentID *string
+	if comment.ID != "" {
+		exists, err := s.repoManager.ITSM().RemoteCommentSynced(ctx, connector.ID, comment.ID)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return false, nil
+		}
+		remoteCommentID = &comment.ID
+	}
+
+	ticketComment := &models.TicketComment{
+		TicketID: link.TicketID,
+		AuthorID: connector.SyncUserID,
+		Content:  fmt.Sprintf("[%s] %s: %s", itsmProviderName(connector.Provider), comment.Author, comment.Body),
+	}
+	if err := s.repoManager.Ticket().AddComment(ctx, ticketComment); err != nil {
+		return false, err
+	}
+	if err := s.repoManager.ITSM().LinkComment(ctx, connector.ID, link.TicketID, ticketComment.ID, remoteCommentID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// applyRemote 将外部工单的标题、描述、状态和优先级写回平台工单。
+// 外部状态或优先级与平台当前值映射结果一致时保留平台值，避免多对一映射反向覆盖
+func (s *itsmService) applyRemote(ctx context.Context, connector *models.ITSMConnector, ticket *models.Ticket, issue *models.ITSMRemoteIssue) (bool, error) {
+	mapping := connector.FieldMapping
+	changed := false
+
+	if title := strings.TrimPrefix(issue.Title, "["+ticket.Number+"] "); title != "" && title != ticket.Title {
+		ticket.Title = title
+		changed = true
+	}
+	if issue.Description != "" && issue.Description != ticket.Description {
+		ticket.Description = issue.Description
+		changed = true
+	}
+
+	if issue.Status != "" && !strings.EqualFold(mapping.RemoteStatus(ticket.Status), issue.Status) {
+		if status, ok := mapping.LocalStatus(issue.Status); ok && status != ticket.Status {
+			now := time.Now()
+			switch status {
+			case models.TicketStatusResolved:
+				ticket.ResolvedAt = &now
+			case models.TicketStatusClosed:
+				ticket.ClosedAt = &now
+			}
+			ticket.Status = status
+			changed = true
+		}
+	}
+
+	if issue.Priority != "" && !strings.EqualFold(mapping.RemotePriority(ticket.Priority), issue.Priority) {
+		if priority, ok := mapping.LocalPriority(issue.Priority); ok && priority != ticket.Priority {
+			ticket.Priority = priority
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	if err := s.repoManager.Ticket().Update(ctx, ticket); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// verifyITSMWebhook 校验 Webhook 签名头，外部系统不支持签名时校验 token 查询参数
+func verifyITSMWebhook(secret, token, signatureHeader string, body []byte) error {
+	if signatureHeader != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := itsmSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+		if hmac.Equal([]byte(expected), []byte(signatureHeader)) {
+			return nil
+		}
+		return fmt.Errorf("%w: 签名不匹配", models.ErrInvalidSignature)
+	}
+
+	if token != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1 {
+		return nil
+	}
+	return fmt.Errorf("%w: 缺少有效的签名或令牌", models.ErrInvalidSignature)
+}
+
+// itsmProviderName 外部系统在评论前缀中显示的名称
+func itsmProviderName(provider models.ITSMProvider) string {
+	if provider == models.ITSMProviderServiceNow {
+		return "ServiceNow"
+	}
+	return "Jira"
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeITSMRepository 内存 ITSM 同步仓储
+type fakeITSMRepository struct {
+	repository.ITSMRepository
+	connector      *models.ITSMConnector
+	ticketIDs      []string
+	links          map[string]*models.ITSMTicketLink
+	commentLinks   map[string]*string
+	remoteComments map[string]bool
+}
+
+func (r *fakeITSMRepository) GetConnector(ctx context.Context, id string) (*models.ITSMConnector, error) {
+	if r.connector == nil || r.connector.ID != id {
+		return nil, models.ErrITSMConnectorNotFound
+	}
+	return r.connector, nil
+}
+
+func (r *fakeITSMRepository) ListConnectors(ctx context.Context, enabledOnly bool) ([]*models.ITSMConnector, error) {
+	return []*models.ITSMConnector{r.connector}, nil
+}
+
+func (r *fakeITSMRepository) TouchConnectorSynced(ctx context.Context, id string, at time.Time) error {
+	r.connector.LastSyncedAt = &at
+	return nil
+}
+
+func (r *fakeITSMRepository) ListTicketsToSync(ctx context.Context, connector *models.ITSMConnector, limit int) ([]string, error) {
+	return r.ticketIDs, nil
+}
+
+func (r *fakeITSMRepository) GetLink(ctx context.Context, connectorID, ticketID string) (*models.ITSMTicketLink, error) {
+	if link, ok := r.links[ticketID]; ok {
+		copied := *link
+		return &copied, nil
+	}
+	return nil, models.ErrITSMTicketLinkNotFound
+}
+
+func (r *fakeITSMRepository) GetLinkByRemote(ctx context.Context, connectorID, remoteID string) (*models.ITSMTicketLink, error) {
+	for _, link := range r.links {
+		if link.RemoteID == remoteID {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrITSMTicketLinkNotFound
+}
+
+func (r *fakeITSMRepository) SaveLink(ctx context.Context, link *models.ITSMTicketLink) error {
+	r.links[link.TicketID] = link
+	return nil
+}
+
+func (r *fakeITSMRepository) ListSyncedCommentIDs(ctx context.Context, connectorID, ticketID string) (map[string]bool, error) {
+	synced := make(map[string]bool)
+	for id := range r.commentLinks {
+		synced[id] = true
+	}
+	return synced, nil
+}
+
+func (r *fakeITSMRepository) RemoteCommentSynced(ctx context.Context, connectorID, remoteCommentID string) (bool, error) {
+	return r.remoteComments[remoteCommentID], nil
+}
+
+func (r *fakeITSMRepository) LinkComment(ctx context.Context, connectorID, ticketID, commentID string, remoteCommentID *string) error {
+	r.commentLinks[commentID] = remoteCommentID
+	if remoteCommentID != nil {
+		r.remoteComments[*remoteCommentID] = true
+	}
+	return nil
+}
+
+// fakeITSMTicketRepository 内存工单仓储
+type fakeITSMTicketRepository struct {
+	repository.TicketRepository
+	ticket   *models.Ticket
+	comments []*models.TicketComment
+	updated  int
+}
+
+func (r *fakeITSMTicketRepository) GetByID(ctx context.Context, id string) (*models.Ticket, error) {
+	copied := *r.ticket
+	return &copied, nil
+}
+
+func (r *fakeITSMTicketRepository) Update(ctx context.Context, ticket *models.Ticket) error {
+	ticket.UpdatedAt = time.Now()
+	r.ticket = ticket
+	r.updated++
+	return nil
+}
+
+func (r *fakeITSMTicketRepository) GetComments(ctx context.Context, ticketID string) ([]*models.TicketComment, error) {
+	return r.comments, nil
+}
+
+func (r *fakeITSMTicketRepository) AddComment(ctx context.Context, comment *models.TicketComment) error {
+	comment.ID = fmt.Sprintf("c%d", len(r.comments)+1)
+	r.comments = append(r.comments, comment)
+	return nil
+}
+
+type itsmRepoManager struct {
+	*MockRepositoryManager
+	itsm    *fakeITSMRepository
+	tickets *fakeITSMTicketRepository
+	users   *fakeChatOpsUserRepository
+}
+
+func (m *itsmRepoManager) ITSM() repository.ITSMRepository { return m.itsm }
+
+func (m *itsmRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func (m *itsmRepoManager) User() repository.UserRepository { return m.users }
+
+// fakeITSMClient 记录推送内容的外部系统客户端
+type fakeITSMClient struct {
+	created  []*models.ITSMRemoteIssue
+	updated  []*models.ITSMRemoteIssue
+	comments []string
+	event    *models.ITSMWebhookEvent
+}
+
+func (c *fakeITSMClient) CreateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) (*models.ITSMRemoteIssue, error) {
+	c.created = append(c.created, issue)
+	result := *issue
+	result.ID = "10001"
+	result.Key = "OPS-1"
+	return &result, nil
+}
+
+func (c *fakeITSMClient) UpdateIssue(ctx context.Context, issue *models.ITSMRemoteIssue) error {
+	c.updated = append(c.updated, issue)
+	return nil
+}
+
+func (c *fakeITSMClient) AddComment(ctx context.Context, issue *models.ITSMRemoteIssue, body string) (string, error) {
+	c.comments = append(c.comments, body)
+	return fmt.Sprintf("r%d", len(c.comments)), nil
+}
+
+func (c *fakeITSMClient) ParseWebhook(body []byte) (*models.ITSMWebhookEvent, error) {
+	return c.event, nil
+}
+
+func newTestITSMService(strategy models.ITSMConflictStrategy) (ITSMService, *itsmRepoManager, *fakeITSMClient) {
+	now := time.Now()
+	connector := &models.ITSMConnector{
+		ID:               "conn1",
+		Provider:         models.ITSMProviderJira,
+		FieldMapping:     models.DefaultITSMFieldMapping(models.ITSMProviderJira),
+		ConflictStrategy: strategy,
+		SyncComments:     true,
+		SyncUserID:       "bot",
+		WebhookSecret:    "secret",
+		Enabled:          true,
+	}
+	connector.FieldMapping.CustomFields = map[string]string{"host": "customfield_10010"}
+
+	repoManager := &itsmRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		itsm: &fakeITSMRepository{
+			connector:      connector,
+			links:          map[string]*models.ITSMTicketLink{},
+			commentLinks:   map[string]*string{},
+			remoteComments: map[string]bool{},
+		},
+		tickets: &fakeITSMTicketRepository{ticket: &models.Ticket{
+			ID: "t1", Number: "INC-1", Title: "磁盘已满", Status: models.TicketStatusAssigned,
+			Priority: models.TicketPriorityHigh, CustomFields: map[string]interface{}{"host": "web-01"},
+			CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour),
+		}},
+		users: &fakeChatOpsUserRepository{users: []*models.User{
+			{ID: "u1", Username: "alice"},
+			{ID: "bot", Username: "itsm-bot"},
+		}},
+	}
+	client := &fakeITSMClient{}
+	svc := NewITSMService(repoManager, func(*models.ITSMConnector) (ITSMClient, error) {
+		return client, nil
+	}, config.ITSMConfig{Enabled: true, BatchSize: 10}, zap.NewNop())
+	return svc, repoManager, client
+}
+
+func TestITSMService_Sync(t *testing.T) {
+	svc, repoManager, client := newTestITSMService(models.ITSMConflictLatestWins)
+	ctx := context.Background()
+	repoManager.itsm.ticketIDs = []string{"t1"}
+	repoManager.tickets.comments = []*models.TicketComment{
+		{ID: "c1", AuthorID: "u1", Content: "正在扩容"},
+		{ID: "c2", AuthorID: "u1", Content: "内部备注", IsInternal: true},
+		{ID: "c3", AuthorID: "bot", Content: "[Jira] Bob: 已确认"},
+	}
+
+	synced, err := svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced)
+
+	require.Len(t, client.created, 1)
+	issue := client.created[0]
+	assert.Equal(t, "[INC-1] 磁盘已满", issue.Title)
+	assert.Equal(t, "To Do", issue.Status)
+	assert.Equal(t, "High", issue.Priority)
+	assert.Equal(t, "Task", issue.IssueType)
+	assert.Equal(t, "web-01", issue.Fields["customfield_10010"])
+
+	// 只推送公开评论，同步用户写入的外部评论不回推
+	assert.Equal(t, []string{"[Pulse] alice: 正在扩容"}, client.comments)
+
+	link := repoManager.itsm.links["t1"]
+	require.NotNil(t, link)
+	assert.Equal(t, "OPS-1", link.RemoteKey)
+	assert.Nil(t, link.LastError)
+	assert.NotNil(t, repoManager.itsm.connector.LastSyncedAt)
+
+	// 已关联的工单更新外部工单，已同步的评论不重复推送
+	synced, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	assert.Len(t, client.created, 1)
+	require.Len(t, client.updated, 1)
+	assert.Equal(t, "OPS-1", client.updated[0].Key)
+	assert.Len(t, client.comments, 1)
+}
+
+func TestITSMService_HandleWebhook(t *testing.T) {
+	svc, repoManager, client := newTestITSMService(models.ITSMConflictLatestWins)
+	ctx := context.Background()
+	syncedAt := time.Now().Add(-30 * time.Minute)
+	repoManager.itsm.links["t1"] = &models.ITSMTicketLink{
+		ConnectorID: "conn1", TicketID: "t1", RemoteID: "10001", RemoteKey: "OPS-1", LastSyncedAt: syncedAt,
+	}
+	body := []byte(`{}`)
+
+	_, err := svc.HandleWebhook(ctx, "conn1", "wrong", "", body)
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+
+	// 外部状态与平台状态的映射一致时保留平台状态，其他字段照常应用
+	client.event = &models.ITSMWebhookEvent{
+		Issue:   models.ITSMRemoteIssue{ID: "10001", Key: "OPS-1", Title: "[INC-1] 磁盘已满", Status: "To Do", Priority: "Highest", UpdatedAt: time.Now()},
+		Comment: &models.ITSMRemoteComment{ID: "900", Author: "Bob", Body: "已扩容"},
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	result, err := svc.HandleWebhook(ctx, "conn1", "", "sha256="+hex.EncodeToString(mac.Sum(nil)), body)
+	require.NoError(t, err)
+	assert.True(t, result.FieldsApplied)
+	assert.True(t, result.CommentAdded)
+	assert.False(t, result.Conflict)
+
+	ticket := repoManager.tickets.ticket
+	assert.Equal(t, models.TicketStatusAssigned, ticket.Status)
+	assert.Equal(t, models.TicketPriorityCritical, ticket.Priority)
+	require.Len(t, repoManager.tickets.comments, 1)
+	assert.Equal(t, "[Jira] Bob: 已扩容", repoManager.tickets.comments[0].Content)
+	assert.Equal(t, "bot", repoManager.tickets.comments[0].AuthorID)
+	assert.True(t, repoManager.itsm.links["t1"].LastSyncedAt.After(syncedAt))
+
+	// 重复推送的评论和平台同步出去的评论不写入
+	client.event.Comment = &models.ITSMRemoteComment{ID: "900", Author: "Bob", Body: "已扩容"}
+	result, err = svc.HandleWebhook(ctx, "conn1", "secret", "", body)
+	require.NoError(t, err)
+	assert.False(t, result.CommentAdded)
+	client.event.Comment = &models.ITSMRemoteComment{ID: "901", Author: "bot", Body: "[Pulse] alice: 正在扩容"}
+	result, err = svc.HandleWebhook(ctx, "conn1", "secret", "", body)
+	require.NoError(t, err)
+	assert.False(t, result.CommentAdded)
+
+	// 未关联的外部工单被忽略
+	client.event = &models.ITSMWebhookEvent{Issue: models.ITSMRemoteIssue{ID: "20002"}}
+	result, err = svc.HandleWebhook(ctx, "conn1", "secret", "", body)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.IgnoredReason)
+}
+
+func TestITSMService_HandleWebhookConflict(t *testing.T) {
+	svc, repoManager, client := newTestITSMService(models.ITSMConflictLocalWins)
+	ctx := context.Background()
+	syncedAt := time.Now().Add(-2 * time.Hour)
+	repoManager.itsm.links["t1"] = &models.ITSMTicketLink{
+		ConnectorID: "conn1", TicketID: "t1", RemoteID: "10001", RemoteKey: "OPS-1", LastSyncedAt: syncedAt,
+	}
+	client.event = &models.ITSMWebhookEvent{
+		Issue: models.ITSMRemoteIssue{ID: "10001", Status: "Done", UpdatedAt: time.Now()},
+	}
+
+	// 平台工单在上次同步后有修改，以平台为准时不应用外部修改，也不更新同步时间
+	result, err := svc.HandleWebhook(ctx, "conn1", "secret", "", []byte(`{}`))
+	require.NoError(t, err)
+	assert.True(t, result.Conflict)
+	assert.False(t, result.FieldsApplied)
+	assert.Equal(t, models.TicketStatusAssigned, repoManager.tickets.ticket.Status)
+	assert.Zero(t, repoManager.tickets.updated)
+
+	link := repoManager.itsm.links["t1"]
+	assert.Equal(t, syncedAt, link.LastSyncedAt)
+	require.NotNil(t, link.RemoteUpdatedAt)
+
+	// 以外部为准时应用外部状态
+	repoManager.itsm.connector.ConflictStrategy = models.ITSMConflictRemoteWins
+	result, err = svc.HandleWebhook(ctx, "conn1", "secret", "", []byte(`{}`))
+	require.NoError(t, err)
+	assert.True(t, result.Conflict)
+	assert.True(t, result.FieldsApplied)
+	assert.Equal(t, models.TicketStatusResolved, repoManager.tickets.ticket.Status)
+	assert.NotNil(t, repoManager.tickets.ticket.ResolvedAt)
+}
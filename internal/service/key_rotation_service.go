@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// keyRotationBatchSize 密钥轮换时每批处理的记录数量
+const keyRotationBatchSize = 100
+
+// keyRotationService 加密密钥轮换服务实现
+type keyRotationService struct {
+	repoManager repository.RepositoryManager
+	batchSize   int
+	logger      *zap.Logger
+}
+
+// NewKeyRotationService 创建密钥轮换服务实例
+func NewKeyRotationService(repoManager repository.RepositoryManager, batchSize int, logger *zap.Logger) KeyRotationService {
+	if batchSize <= 0 {
+		batchSize = keyRotationBatchSize
+	}
+	return &keyRotationService{
+		repoManager: repoManager,
+		batchSize:   batchSize,
+		logger:      logger,
+	}
+}
+
+// RotateEncryptionKeys 依次轮换各类加密数据，任一类别出错时中止
+// 单条记录解密失败不会中止轮换，计入失败数量，需确认历史密钥仍在配置中后重新执行
+func (s *keyRotationService) RotateEncryptionKeys(ctx context.Context) (*models.KeyRotationReport, error) {
+	s.logger.Info("开始轮换加密密钥")
+
+	report := &models.KeyRotationReport{}
+	steps := []struct {
+		name   string
+		rotate func(ctx context.Context, batchSize int) (*models.KeyRotationResult, error)
+		result **models.KeyRotationResult
+	}{
+		{"data_sources", s.repoManager.DataSource().RotateEncryptionKeys, &report.DataSources},
+		{"webhook_integrations", s.repoManager.WebhookIntegration().RotateEncryptionKeys, &report.WebhookIntegrations},
+		{"webhooks", s.repoManager.Webhook().RotateEncryptionKeys, &report.Webhooks},
+		{"users", s.repoManager.User().RotateEncryptionKeys, &report.Users},
+		{"webhook_subscriptions", s.repoManager.WebhookSubscription().RotateEncryptionKeys, &report.WebhookSubscriptions},
+		{"notification_channels", s.repoManager.NotificationChannel().RotateEncryptionKeys, &report.NotificationChannels},
+	}
+
+	for _, step := range steps {
+		result, err := step.rotate(ctx, s.batchSize)
+		if err != nil {
+			s.logger.Error("密钥轮换失败", zap.String("target", step.name), zap.Error(err))
+			return nil, fmt.Errorf("轮换 %s 加密密钥失败: %w", step.name, err)
+		}
+		*step.result = result
+		report.ActiveVersion = result.ActiveVersion
+
+		s.logger.Info("密钥轮换完成",
+			zap.String("target", step.name),
+			zap.String("active_version", result.ActiveVersion),
+			zap.Int("scanned", result.Scanned),
+			zap.Int("rotated", result.Rotated),
+			zap.Int("skipped", result.Skipped),
+			zap.Int("failed", result.Failed),
+		)
+		if result.Failed > 0 {
+			s.logger.Warn("部分数据无法解密，请确认历史密钥仍在配置中",
+				zap.String("target", step.name), zap.Strings("ids", result.FailedIDs))
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// knowledgeACLService 知识库访问控制服务实现
+type knowledgeACLService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewKnowledgeACLService 创建知识库访问控制服务实例
+func NewKnowledgeACLService(repoManager repository.RepositoryManager, logger *zap.Logger) KnowledgeACLService {
+	return &knowledgeACLService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// GetACL 获取文章或分类的授权条目
+// 分类授权仅管理员可查看，文章授权对有写权限的用户可见
+func (s *knowledgeACLService) GetACL(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID, userID string) ([]*models.KnowledgeACLEntry, error) {
+	if err := s.checkResource(ctx, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	switch resourceType {
+	case models.KnowledgeACLResourceCategory:
+		if !s.isAdmin(ctx, userID) {
+			return nil, models.ErrPermissionDenied
+		}
+	case models.KnowledgeACLResourceArticle:
+		if err := s.CheckAccess(ctx, resourceID, userID, models.KnowledgeACLPermissionWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := s.repoManager.KnowledgeACL().ListEntries(ctx, resourceType, resourceID)
+	if err != nil {
+		s.logger.Error("获取访问控制条目失败", zap.Error(err), zap.String("resource_id", resourceID))
+		return nil, fmt.Errorf("获取访问控制条目失败: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SetACL 整体替换文章或分类的授权条目，空列表表示取消限制
+// 分类授权仅管理员可修改，文章授权由管理员或作者修改
+func (s *knowledgeACLService) SetACL(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID, userID string, req *models.KnowledgeACLRequest) ([]*models.KnowledgeACLEntry, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if err := s.checkResource(ctx, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+	if !s.canManage(ctx, resourceType, resourceID, userID) {
+		return nil, models.ErrPermissionDenied
+	}
+
+	entries := make([]*models.KnowledgeACLEntry, 0, len(req.Entries))
+	for _, item := range req.Entries {
+		createdBy := userID
+		entries = append(entries, &models.KnowledgeACLEntry{
+			PrincipalType: item.PrincipalType,
+			PrincipalID:   strings.TrimSpace(item.PrincipalID),
+			Permission:    item.Permission,
+			CreatedBy:     &createdBy,
+		})
+	}
+
+	err := s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		return repo.KnowledgeACL().ReplaceEntries(ctx, resourceType, resourceID, entries)
+	})
+	if err != nil {
+		s.logger.Error("设置访问控制失败", zap.Error(err),
+			zap.String("resource_type", string(resourceType)), zap.String("resource_id", resourceID))
+		return nil, fmt.Errorf("设置访问控制失败: %w", err)
+	}
+
+	s.logger.Info("知识库访问控制已更新",
+		zap.String("resource_type", string(resourceType)),
+		zap.String("resource_id", resourceID),
+		zap.Int("entries", len(entries)),
+		zap.String("user_id", userID))
+
+	return s.repoManager.KnowledgeACL().ListEntries(ctx, resourceType, resourceID)
+}
+
+// CheckAccess 检查用户对文章的访问权限，无权限时返回 ErrPermissionDenied
+func (s *knowledgeACLService) CheckAccess(ctx context.Context, knowledgeID, userID string, permission models.KnowledgeACLPermission) error {
+	if knowledgeID == "" {
+		return fmt.Errorf("%w: 知识库条目ID不能为空", models.ErrInvalidInput)
+	}
+	if s.isAdmin(ctx, userID) {
+		return nil
+	}
+
+	allowed, err := s.repoManager.KnowledgeACL().HasAccess(ctx, knowledgeID, userID, permission)
+	if err != nil {
+		s.logger.Error("检查知识库访问权限失败", zap.Error(err), zap.String("knowledge_id", knowledgeID))
+		return fmt.Errorf("检查知识库访问权限失败: %w", err)
+	}
+	if allowed {
+		return nil
+	}
+
+	// 区分文章不存在和无权限
+	exists, err := s.repoManager.Knowledge().Exists(ctx, knowledgeID)
+	if err != nil {
+		return fmt.Errorf("检查知识库文章失败: %w", err)
+	}
+	if !exists {
+		return models.ErrKnowledgeNotFound
+	}
+
+	return models.ErrPermissionDenied
+}
+
+// ViewerScope 返回列表和搜索查询使用的访问控制范围，管理员不受限制时返回 nil
+func (s *knowledgeACLService) ViewerScope(ctx context.Context, userID string) *string {
+	if s.isAdmin(ctx, userID) {
+		return nil
+	}
+	return &userID
+}
+
+// checkResource 检查受控资源是否存在
+func (s *knowledgeACLService) checkResource(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID string) error {
+	if !resourceType.IsValid() {
+		return fmt.Errorf("%w: 无效的资源类型", models.ErrInvalidInput)
+	}
+	if resourceID == "" {
+		return fmt.Errorf("%w: 资源ID不能为空", models.ErrInvalidInput)
+	}
+
+	if resourceType == models.KnowledgeACLResourceCategory {
+		if _, err := s.repoManager.Knowledge().GetCategory(ctx, resourceID); err != nil {
+			if errors.Is(err, models.ErrKnowledgeCategoryNotFound) {
+				return err
+			}
+			return fmt.Errorf("获取知识分类失败: %w", err)
+		}
+		return nil
+	}
+
+	exists, err := s.repoManager.Knowledge().Exists(ctx, resourceID)
+	if err != nil {
+		return fmt.Errorf("检查知识库文章失败: %w", err)
+	}
+	if !exists {
+		return models.ErrKnowledgeNotFound
+	}
+
+	return nil
+}
+
+// canManage 判断用户能否修改授权，分类仅管理员，文章为管理员或作者
+func (s *knowledgeACLService) canManage(ctx context.Context, resourceType models.KnowledgeACLResourceType, resourceID, userID string) bool {
+	if s.isAdmin(ctx, userID) {
+		return true
+	}
+	if resourceType != models.KnowledgeACLResourceArticle || userID == "" {
+		return false
+	}
+
+	knowledge, err := s.repoManager.Knowledge().GetByID(ctx, resourceID)
+	if err != nil {
+		return false
+	}
+
+	return knowledge.AuthorID == userID
+}
+
+// isAdmin 判断用户是否为管理员，管理员不受访问控制限制
+func (s *knowledgeACLService) isAdmin(ctx context.Context, userID string) bool {
+	if userID == "" {
+		return false
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, userID)
+	if err != nil {
+		return false
+	}
+
+	return user.Role == models.UserRoleAdmin
+}
+
+// withTx 在事务中执行
+func (s *knowledgeACLService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// knowledgeCommentService 知识库评论服务实现
+type knowledgeCommentService struct {
+	repoManager   repository.RepositoryManager
+	notifications NotificationService
+	inbox         InboxNotifier
+	logger        *zap.Logger
+}
+
+// NewKnowledgeCommentService 创建知识库评论服务实例，inbox 为 nil 时不通知评论中 @ 提及的用户
+func NewKnowledgeCommentService(repoManager repository.RepositoryManager, notifications NotificationService, inbox InboxNotifier, logger *zap.Logger) KnowledgeCommentService {
+	return &knowledgeCommentService{
+		repoManager:   repoManager,
+		notifications: notifications,
+		inbox:         inbox,
+		logger:        logger,
+	}
+}
+
+// List 获取文章评论讨论串，非管理员看不到被隐藏评论的内容
+func (s *knowledgeCommentService) List(ctx context.Context, knowledgeID, viewerID string) ([]*models.KnowledgeComment, error) {
+	if knowledgeID == "" {
+		return nil, fmt.Errorf("知识库条目ID不能为空")
+	}
+
+	comments, err := s.repoManager.KnowledgeComment().ListByKnowledge(ctx, knowledgeID)
+	if err != nil {
+		s.logger.Error("获取评论列表失败", zap.Error(err), zap.String("knowledge_id", knowledgeID))
+		return nil, fmt.Errorf("获取评论列表失败: %w", err)
+	}
+
+	moderator := s.isModerator(ctx, viewerID)
+	for _, comment := range comments {
+		if comment.DeletedAt != nil || !moderator {
+			comment.Redact()
+		}
+	}
+
+	return models.BuildKnowledgeCommentTree(comments), nil
+}
+
+// Create 发表评论或回复，并通知文章作者和被回复人
+func (s *knowledgeCommentService) Create(ctx context.Context, knowledgeID, authorID string, req *models.KnowledgeCommentCreateRequest) (*models.KnowledgeComment, error) {
+	if knowledgeID == "" || authorID == "" {
+		return nil, fmt.Errorf("知识库条目ID和评论人不能为空")
+	}
+	if err := models.ValidateKnowledgeCommentContent(req.Content); err != nil {
+		return nil, err
+	}
+
+	knowledge, err := s.repoManager.Knowledge().GetByID(ctx, knowledgeID)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取知识库条目失败: %w", err)
+	}
+
+	var parent *models.KnowledgeComment
+	if req.ParentID != nil && *req.ParentID != "" {
+		parent, err = s.repoManager.KnowledgeComment().GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.KnowledgeID != knowledgeID {
+			return nil, fmt.Errorf("%w: 回复的评论不属于该文章", models.ErrInvalidInput)
+		}
+	}
+
+	comment := &models.KnowledgeComment{
+		KnowledgeID: knowledgeID,
+		AuthorID:    authorID,
+		Content:     req.Content,
+	}
+	if parent != nil {
+		comment.ParentID = &parent.ID
+	}
+
+	if err := s.repoManager.KnowledgeComment().Create(ctx, comment); err != nil {
+		s.logger.Error("创建评论失败", zap.Error(err), zap.String("knowledge_id", knowledgeID))
+		return nil, fmt.Errorf("创建评论失败: %w", err)
+	}
+
+	// 通知文章作者和被回复人，评论人自己不通知
+	recipients := make(map[string]bool)
+	if knowledge.AuthorID != "" && knowledge.AuthorID != authorID {
+		recipients[knowledge.AuthorID] = true
+	}
+	if parent != nil && parent.AuthorID != authorID {
+		recipients[parent.AuthorID] = true
+	}
+	for userID := range recipients {
+		s.notify(ctx, userID, knowledge, comment, parent != nil && userID == parent.AuthorID)
+	}
+	if s.inbox != nil {
+		s.inbox.NotifyMentions(ctx, comment.Content, models.InboxItem{
+			Body:         comment.Content,
+			ResourceType: models.InboxResourceKnowledge,
+			ResourceID:   knowledgeID,
+			ActorID:      &authorID,
+			MessageKey:   "inbox.mentioned_knowledge",
+			MessageData:  map[string]interface{}{"Title": knowledge.Title},
+		})
+	}
+
+	s.logger.Info("评论创建成功", zap.String("id", comment.ID), zap.String("knowledge_id", knowledgeID))
+	return comment, nil
+}
+
+// Update 编辑评论，仅评论作者可编辑
+func (s *knowledgeCommentService) Update(ctx context.Context, id, userID, content string) (*models.KnowledgeComment, error) {
+	if err := models.ValidateKnowledgeCommentContent(content); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, fmt.Errorf("%w: 只能编辑自己的评论", models.ErrPermissionDenied)
+	}
+
+	if err := s.repoManager.KnowledgeComment().UpdateContent(ctx, id, content); err != nil {
+		s.logger.Error("编辑评论失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("编辑评论失败: %w", err)
+	}
+
+	return s.getComment(ctx, id)
+}
+
+// Delete 删除评论，评论作者和管理员可删除
+func (s *knowledgeCommentService) Delete(ctx context.Context, id, userID string) error {
+	comment, err := s.getComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID && !s.isModerator(ctx, userID) {
+		return fmt.Errorf("%w: 只能删除自己的评论", models.ErrPermissionDenied)
+	}
+
+	if err := s.repoManager.KnowledgeComment().SoftDelete(ctx, id); err != nil {
+		if errors.Is(err, models.ErrCommentNotFound) {
+			return err
+		}
+		s.logger.Error("删除评论失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("删除评论失败: %w", err)
+	}
+
+	s.logger.Info("评论删除成功", zap.String("id", id), zap.String("user_id", userID))
+	return nil
+}
+
+// React 添加或取消表情回应
+func (s *knowledgeCommentService) React(ctx context.Context, id, userID, reaction string, add bool) (*models.KnowledgeComment, error) {
+	if !models.IsValidKnowledgeCommentReaction(reaction) {
+		return nil, fmt.Errorf("%w: 不支持的表情回应 %s", models.ErrInvalidInput, reaction)
+	}
+
+	if _, err := s.getComment(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if add {
+		err := s.repoManager.KnowledgeComment().AddReaction(ctx, id, userID, reaction)
+		if err != nil {
+			s.logger.Error("添加表情回应失败", zap.Error(err), zap.String("id", id))
+			return nil, fmt.Errorf("添加表情回应失败: %w", err)
+		}
+	} else {
+		err := s.repoManager.KnowledgeComment().RemoveReaction(ctx, id, userID, reaction)
+		if err != nil {
+			s.logger.Error("取消表情回应失败", zap.Error(err), zap.String("id", id))
+			return nil, fmt.Errorf("取消表情回应失败: %w", err)
+		}
+	}
+
+	return s.getComment(ctx, id)
+}
+
+// Flag 举报评论，同一用户重复举报不重复计数
+func (s *knowledgeCommentService) Flag(ctx context.Context, id, userID string, reason *string) error {
+	if reason != nil && utf8.RuneCountInString(*reason) > 500 {
+		return fmt.Errorf("%w: 举报原因不能超过500个字符", models.ErrInvalidInput)
+	}
+
+	comment, err := s.getComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID == userID {
+		return fmt.Errorf("%w: 不能举报自己的评论", models.ErrInvalidInput)
+	}
+
+	added, err := s.repoManager.KnowledgeComment().Flag(ctx, id, userID, reason)
+	if err != nil {
+		s.logger.Error("举报评论失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("举报评论失败: %w", err)
+	}
+
+	if added {
+		s.logger.Info("评论被举报", zap.String("id", id), zap.String("user_id", userID), zap.Int("flag_count", comment.FlagCount+1))
+	}
+	return nil
+}
+
+// Moderate 管理员隐藏或恢复评论
+func (s *knowledgeCommentService) Moderate(ctx context.Context, id, moderatorID string, status models.KnowledgeCommentStatus, reason *string) (*models.KnowledgeComment, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: 无效的评论状态 %s", models.ErrInvalidInput, status)
+	}
+	if !s.isModerator(ctx, moderatorID) {
+		return nil, fmt.Errorf("%w: 只有管理员可以审核评论", models.ErrPermissionDenied)
+	}
+
+	if err := s.repoManager.KnowledgeComment().SetStatus(ctx, id, status, moderatorID, reason); err != nil {
+		if errors.Is(err, models.ErrCommentNotFound) {
+			return nil, err
+		}
+		s.logger.Error("审核评论失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("审核评论失败: %w", err)
+	}
+
+	s.logger.Info("评论审核完成", zap.String("id", id), zap.String("moderator_id", moderatorID), zap.String("status", string(status)))
+	return s.getComment(ctx, id)
+}
+
+// ListFlagged 获取待处理的被举报评论，仅管理员可用
+func (s *knowledgeCommentService) ListFlagged(ctx context.Context, moderatorID string, limit int) ([]*models.KnowledgeComment, error) {
+	if !s.isModerator(ctx, moderatorID) {
+		return nil, fmt.Errorf("%w: 只有管理员可以查看举报评论", models.ErrPermissionDenied)
+	}
+
+	comments, err := s.repoManager.KnowledgeComment().ListFlagged(ctx, limit)
+	if err != nil {
+		s.logger.Error("获取举报评论失败", zap.Error(err))
+		return nil, fmt.Errorf("获取举报评论失败: %w", err)
+	}
+
+	return comments, nil
+}
+
+// getComment 获取评论
+func (s *knowledgeCommentService) getComment(ctx context.Context, id string) (*models.KnowledgeComment, error) {
+	if id == "" {
+		return nil, fmt.Errorf("评论ID不能为空")
+	}
+
+	comment, err := s.repoManager.KnowledgeComment().GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrCommentNotFound) {
+			return nil, err
+		}
+		s.logger.Error("获取评论失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+
+	return comment, nil
+}
+
+// isModerator 判断用户是否为评论管理员
+func (s *knowledgeCommentService) isModerator(ctx context.Context, userID string) bool {
+	if userID == "" {
+		return false
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, userID)
+	if err != nil {
+		return false
+	}
+
+	return user.Role == models.UserRoleAdmin
+}
+
+// notify 发送评论通知，失败只记录日志不影响评论
+func (s *knowledgeCommentService) notify(ctx context.Context, userID string, knowledge *models.Knowledge, comment *models.KnowledgeComment, isReply bool) {
+	if s.notifications == nil {
+		return
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, userID)
+	if err != nil || user.Email == "" {
+		s.logger.Warn("获取评论通知接收人失败", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+
+	messageKey := "notification.knowledge_comment"
+	if isReply {
+		messageKey = "notification.knowledge_reply"
+	}
+
+	notification := &models.Notification{
+		Type:        models.NotificationTypeEmail,
+		Recipient:   user.Email,
+		Content:     comment.Content,
+		MessageKey:  messageKey,
+		MessageData: map[string]interface{}{"Title": knowledge.Title},
+	}
+	if err := s.notifications.Send(ctx, notification); err != nil {
+		s.logger.Warn("发送评论通知失败", zap.Error(err), zap.String("user_id", userID), zap.String("comment_id", comment.ID))
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// knowledgeFeedbackPendingLimit 待评价列表最多返回的引用数
+	knowledgeFeedbackPendingLimit = 50
+	// knowledgeQualityDefaultLimit 文章质量列表默认返回的文章数
+	knowledgeQualityDefaultLimit = 50
+	// knowledgeQualityMaxLimit 文章质量列表最多返回的文章数
+	knowledgeQualityMaxLimit = 200
+)
+
+// knowledgeFeedbackService 知识库有用性反馈服务实现。工单处理人引用知识库文章，工单解决时提示解决人评价文章是否有用，
+// 评价汇总为文章质量分，用于提升搜索排序和标记需要更新的文章
+type knowledgeFeedbackService struct {
+	repoManager repository.RepositoryManager
+	inbox       InboxNotifier
+	policy      models.KnowledgeQualityPolicy
+	logger      *zap.Logger
+}
+
+// NewKnowledgeFeedbackService 创建知识库有用性反馈服务实例
+func NewKnowledgeFeedbackService(repoManager repository.RepositoryManager, inbox InboxNotifier, cfg config.KnowledgeConfig, logger *zap.Logger) KnowledgeFeedbackService {
+	return &knowledgeFeedbackService{
+		repoManager: repoManager,
+		inbox:       inbox,
+		policy: models.KnowledgeQualityPolicy{
+			Threshold:   cfg.QualityFlagThreshold,
+			MinFeedback: int64(cfg.QualityMinFeedback),
+		},
+		logger: logger,
+	}
+}
+
+// Link 为工单关联知识库文章，工单已解决时立即提示解决人评价
+func (s *knowledgeFeedbackService) Link(ctx context.Context, ticketID, knowledgeID, userID string) (*models.TicketKnowledgeLink, error) {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.repoManager.Knowledge().Exists(ctx, knowledgeID)
+	if err != nil {
+		return nil, fmt.Errorf("检查知识库文章是否存在失败: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrKnowledgeNotFound
+	}
+
+	link := &models.TicketKnowledgeLink{TicketID: ticketID, KnowledgeID: knowledgeID, LinkedBy: userID}
+	if err := s.repoManager.KnowledgeFeedback().Link(ctx, link); err != nil {
+		return nil, err
+	}
+
+	if ticket.IsResolved() || ticket.IsClosed() {
+		s.PromptResolved(ctx, ticket)
+	}
+	return s.repoManager.KnowledgeFeedback().Get(ctx, ticketID, knowledgeID)
+}
+
+// Unlink 取消工单对文章的引用
+func (s *knowledgeFeedbackService) Unlink(ctx context.Context, ticketID, knowledgeID string) error {
+	return s.repoManager.KnowledgeFeedback().Unlink(ctx, ticketID, knowledgeID)
+}
+
+// ListByTicket 获取工单引用的文章及评价
+func (s *knowledgeFeedbackService) ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketKnowledgeLink, error) {
+	links, err := s.repoManager.KnowledgeFeedback().ListByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		links = []*models.TicketKnowledgeLink{}
+	}
+	return links, nil
+}
+
+// PromptResolved 工单解决时提示处理人评价引用的文章，每个引用只提示一次。
+// 失败只记录日志，不影响工单状态更新
+func (s *knowledgeFeedbackService) PromptResolved(ctx context.Context, ticket *models.Ticket) {
+	resolverID := ""
+	if ticket.AssigneeID != nil {
+		resolverID = *ticket.AssigneeID
+	}
+	if resolverID == "" {
+		return
+	}
+
+	links, err := s.repoManager.KnowledgeFeedback().MarkPrompted(ctx, ticket.ID, resolverID)
+	if err != nil {
+		s.logger.Error("提示评价引用文章失败", zap.Error(err), zap.String("ticket_id", ticket.ID))
+		return
+	}
+	if s.inbox == nil {
+		return
+	}
+	for _, link := range links {
+		s.inbox.Notify(ctx, &models.InboxItem{
+			UserID:       resolverID,
+			Kind:         models.InboxKindFeedbackRequest,
+			ResourceType: models.InboxResourceTicket,
+			ResourceID:   ticket.ID,
+			MessageKey:   "inbox.knowledge_feedback",
+			MessageData: map[string]interface{}{
+				"Number": ticket.Number,
+				"Title":  link.KnowledgeTitle,
+			},
+		})
+	}
+}
+
+// Submit 解决人评价引用的文章是否有用，可以修改评价。评价后文章变为需要更新时通知作者
+func (s *knowledgeFeedbackService) Submit(ctx context.Context, ticketID, knowledgeID, userID string, req *models.KnowledgeFeedbackRequest) (*models.TicketKnowledgeLink, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	link, err := s.repoManager.KnowledgeFeedback().Get(ctx, ticketID, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if link.PromptedAt == nil || link.ResolverID == nil {
+		return nil, models.ErrKnowledgeFeedbackNotPrompted
+	}
+	if *link.ResolverID != userID {
+		return nil, fmt.Errorf("%w: 只有工单解决人可以评价引用的文章", models.ErrPermissionDenied)
+	}
+
+	before, after, err := s.repoManager.KnowledgeFeedback().RecordFeedback(ctx, link.ID, *req.Useful, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if !s.policy.Flagged(before) && s.policy.Flagged(after) {
+		s.notifyFlagged(ctx, after)
+	}
+
+	s.logger.Info("收到知识库文章有用性评价", zap.String("ticket_id", ticketID), zap.String("knowledge_id", knowledgeID),
+		zap.Bool("useful", *req.Useful))
+	return s.repoManager.KnowledgeFeedback().Get(ctx, ticketID, knowledgeID)
+}
+
+// notifyFlagged 通知作者文章需要更新
+func (s *knowledgeFeedbackService) notifyFlagged(ctx context.Context, quality *models.KnowledgeQuality) {
+	s.logger.Warn("知识库文章质量分低于阈值", zap.String("knowledge_id", quality.KnowledgeID),
+		zap.Float64("score", quality.Score), zap.Int64("not_useful", quality.NotUsefulCount))
+	if s.inbox == nil || quality.AuthorID == "" {
+		return
+	}
+	s.inbox.Notify(ctx, &models.InboxItem{
+		UserID:       quality.AuthorID,
+		Kind:         models.InboxKindKnowledgeFlagged,
+		ResourceType: models.InboxResourceKnowledge,
+		ResourceID:   quality.KnowledgeID,
+		MessageKey:   "inbox.knowledge_flagged",
+		MessageData: map[string]interface{}{
+			"Title":     quality.Title,
+			"NotUseful": quality.NotUsefulCount,
+			"Total":     quality.UsefulCount + quality.NotUsefulCount,
+		},
+	})
+}
+
+// Pending 获取用户待评价的引用
+func (s *knowledgeFeedbackService) Pending(ctx context.Context, userID string) ([]*models.TicketKnowledgeLink, error) {
+	links, err := s.repoManager.KnowledgeFeedback().ListPending(ctx, userID, knowledgeFeedbackPendingLimit)
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		links = []*models.TicketKnowledgeLink{}
+	}
+	return links, nil
+}
+
+// Quality 获取有评价的文章的质量，按质量分升序，flaggedOnly 时只返回需要更新的文章，viewerID 非空时按访问控制过滤
+func (s *knowledgeFeedbackService) Quality(ctx context.Context, flaggedOnly bool, limit int, viewerID *string) ([]*models.KnowledgeQuality, error) {
+	if limit <= 0 {
+		limit = knowledgeQualityDefaultLimit
+	}
+	if limit > knowledgeQualityMaxLimit {
+		limit = knowledgeQualityMaxLimit
+	}
+
+	qualities, err := s.repoManager.KnowledgeFeedback().ListQuality(ctx, &models.KnowledgeQualityFilter{
+		FlaggedOnly: flaggedOnly,
+		Policy:      s.policy,
+		Limit:       limit,
+		ViewerID:    viewerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if qualities == nil {
+		qualities = []*models.KnowledgeQuality{}
+	}
+	for _, quality := range qualities {
+		quality.Flagged = s.policy.Flagged(quality)
+	}
+	return qualities, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeKnowledgeFeedbackRepository 内存中的工单引用，RecordFeedback 按内存中的评价重算质量分
+type fakeKnowledgeFeedbackRepository struct {
+	repository.KnowledgeFeedbackRepository
+	links    []*models.TicketKnowledgeLink
+	articles map[string]*models.KnowledgeQuality
+}
+
+func (r *fakeKnowledgeFeedbackRepository) Get(ctx context.Context, ticketID, knowledgeID string) (*models.TicketKnowledgeLink, error) {
+	for _, link := range r.links {
+		if link.TicketID == ticketID && link.KnowledgeID == knowledgeID {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrTicketKnowledgeLinkNotFound
+}
+
+func (r *fakeKnowledgeFeedbackRepository) MarkPrompted(ctx context.Context, ticketID, resolverID string) ([]*models.TicketKnowledgeLink, error) {
+	var marked []*models.TicketKnowledgeLink
+	for _, link := range r.links {
+		if link.TicketID == ticketID && link.PromptedAt == nil {
+			now := time.Now()
+			link.ResolverID, link.PromptedAt = &resolverID, &now
+			link.KnowledgeTitle = r.articles[link.KnowledgeID].Title
+			marked = append(marked, link)
+		}
+	}
+	return marked, nil
+}
+
+func (r *fakeKnowledgeFeedbackRepository) RecordFeedback(ctx context.Context, linkID string, useful bool, comment string) (*models.KnowledgeQuality, *models.KnowledgeQuality, error) {
+	for _, link := range r.links {
+		if link.ID != linkID {
+			continue
+		}
+		now := time.Now()
+		link.Useful, link.Comment, link.RatedAt = &useful, comment, &now
+
+		article := r.articles[link.KnowledgeID]
+		before := *article
+		article.UsefulCount, article.NotUsefulCount = 0, 0
+		for _, other := range r.links {
+			if other.KnowledgeID == link.KnowledgeID && other.Useful != nil {
+				if *other.Useful {
+					article.UsefulCount++
+				} else {
+					article.NotUsefulCount++
+				}
+			}
+		}
+		article.Score = models.KnowledgeQualityScore(article.UsefulCount, article.NotUsefulCount)
+		after := *article
+		return &before, &after, nil
+	}
+	return nil, nil, models.ErrTicketKnowledgeLinkNotFound
+}
+
+// fakeFeedbackTicketRepository 支持更新状态的内存工单
+type fakeFeedbackTicketRepository struct {
+	fakeSurveyTicketRepository
+}
+
+func (r *fakeFeedbackTicketRepository) Exists(ctx context.Context, id string) (bool, error) {
+	_, err := r.GetByID(ctx, id)
+	return err == nil, nil
+}
+
+func (r *fakeFeedbackTicketRepository) UpdateStatus(ctx context.Context, id string, status models.TicketStatus) error {
+	ticket, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	ticket.Status = status
+	return nil
+}
+
+// recordingInbox 记录投递的站内通知
+type recordingInbox struct {
+	items []*models.InboxItem
+}
+
+func (n *recordingInbox) Notify(ctx context.Context, item *models.InboxItem) {
+	n.items = append(n.items, item)
+}
+
+func (n *recordingInbox) NotifyMentions(ctx context.Context, content string, item models.InboxItem) {}
+
+type knowledgeFeedbackRepoManager struct {
+	*MockRepositoryManager
+	feedback *fakeKnowledgeFeedbackRepository
+	tickets  *fakeFeedbackTicketRepository
+}
+
+func (m *knowledgeFeedbackRepoManager) KnowledgeFeedback() repository.KnowledgeFeedbackRepository {
+	return m.feedback
+}
+
+func (m *knowledgeFeedbackRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func TestKnowledgeFeedbackService_PromptOnResolveAndSubmit(t *testing.T) {
+	ctx := context.Background()
+	assignee := "u2"
+	ticket := &models.Ticket{ID: "t1", Number: "T-1", Status: models.TicketStatusInProgress, AssigneeID: &assignee}
+	repoManager := &knowledgeFeedbackRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		feedback: &fakeKnowledgeFeedbackRepository{
+			links: []*models.TicketKnowledgeLink{
+				{ID: "l1", TicketID: "t1", KnowledgeID: "kb1", LinkedBy: "u1"},
+				// 同一文章此前已有两条无用评价
+				{ID: "l0", TicketID: "t0", KnowledgeID: "kb1", Useful: new(bool)},
+				{ID: "l9", TicketID: "t9", KnowledgeID: "kb1", Useful: new(bool)},
+			},
+			articles: map[string]*models.KnowledgeQuality{
+				"kb1": {KnowledgeID: "kb1", Title: "重启网关", AuthorID: "author", NotUsefulCount: 2, Score: models.KnowledgeQualityScore(0, 2)},
+			},
+		},
+		tickets: &fakeFeedbackTicketRepository{fakeSurveyTicketRepository{tickets: []*models.Ticket{ticket}}},
+	}
+	inbox := &recordingInbox{}
+	cfg := config.KnowledgeConfig{QualityFlagThreshold: 0.4, QualityMinFeedback: 3}
+	feedback := NewKnowledgeFeedbackService(repoManager, inbox, cfg, zap.NewNop())
+	tickets := NewTicketService(repoManager, nil, nil, nil, nil, feedback, nil, zap.NewNop())
+
+	// 解决前不能评价
+	useful := false
+	_, err := feedback.Submit(ctx, "t1", "kb1", "u2", &models.KnowledgeFeedbackRequest{Useful: &useful})
+	assert.ErrorIs(t, err, models.ErrKnowledgeFeedbackNotPrompted)
+
+	// 解决工单时提示处理人评价，重复解决不再提示
+	require.NoError(t, tickets.UpdateStatus(ctx, "t1", models.TicketStatusResolved))
+	require.NoError(t, tickets.UpdateStatus(ctx, "t1", models.TicketStatusResolved))
+	require.Len(t, inbox.items, 1)
+	prompt := inbox.items[0]
+	assert.Equal(t, "u2", prompt.UserID)
+	assert.Equal(t, models.InboxKindFeedbackRequest, prompt.Kind)
+	assert.Equal(t, "inbox.knowledge_feedback", prompt.MessageKey)
+	assert.Equal(t, "重启网关", prompt.MessageData["Title"])
+
+	// 只有解决人可以评价
+	_, err = feedback.Submit(ctx, "t1", "kb1", "u1", &models.KnowledgeFeedbackRequest{Useful: &useful})
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+	_, err = feedback.Submit(ctx, "t1", "kb1", "u2", &models.KnowledgeFeedbackRequest{})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 评价数达到下限且质量分低于阈值时通知作者
+	link, err := feedback.Submit(ctx, "t1", "kb1", "u2", &models.KnowledgeFeedbackRequest{Useful: &useful, Comment: " 步骤过时 "})
+	require.NoError(t, err)
+	assert.False(t, *link.Useful)
+	assert.Equal(t, "步骤过时", link.Comment)
+	require.Len(t, inbox.items, 2)
+	flagged := inbox.items[1]
+	assert.Equal(t, "author", flagged.UserID)
+	assert.Equal(t, models.InboxKindKnowledgeFlagged, flagged.Kind)
+	assert.Equal(t, models.InboxResourceKnowledge, flagged.ResourceType)
+	assert.Equal(t, int64(3), flagged.MessageData["Total"])
+
+	// 已标记的文章再次评价不重复通知
+	_, err = feedback.Submit(ctx, "t1", "kb1", "u2", &models.KnowledgeFeedbackRequest{Useful: &useful})
+	require.NoError(t, err)
+	assert.Len(t, inbox.items, 2)
+}
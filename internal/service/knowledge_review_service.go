@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// knowledgeReviewService 知识库审核服务实现
+type knowledgeReviewService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewKnowledgeReviewService 创建知识库审核服务实例
+func NewKnowledgeReviewService(repoManager repository.RepositoryManager, logger *zap.Logger) KnowledgeReviewService {
+	return &knowledgeReviewService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// CreatePolicy 创建审核策略
+func (s *knowledgeReviewService) CreatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error {
+	if policy == nil {
+		return fmt.Errorf("审核策略不能为空")
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	// 每个分类（含默认）只能有一个策略
+	exists, err := s.repoManager.KnowledgeReview().ExistsPolicyForCategory(ctx, policy.CategoryID, "")
+	if err != nil {
+		s.logger.Error("检查审核策略是否存在失败", zap.Error(err))
+		return fmt.Errorf("检查审核策略是否存在失败: %w", err)
+	}
+	if exists {
+		return models.ErrKnowledgeReviewPolicyExists
+	}
+
+	if err := s.repoManager.KnowledgeReview().CreatePolicy(ctx, policy); err != nil {
+		s.logger.Error("创建审核策略失败", zap.Error(err), zap.String("name", policy.Name))
+		return fmt.Errorf("创建审核策略失败: %w", err)
+	}
+
+	s.logger.Info("审核策略创建成功", zap.String("id", policy.ID), zap.String("name", policy.Name))
+	return nil
+}
+
+// GetPolicy 根据ID获取审核策略
+func (s *knowledgeReviewService) GetPolicy(ctx context.Context, id string) (*models.KnowledgeReviewPolicy, error) {
+	if id == "" {
+		return nil, fmt.Errorf("审核策略ID不能为空")
+	}
+
+	policy, err := s.repoManager.KnowledgeReview().GetPolicy(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeReviewPolicyNotFound) {
+			return nil, err
+		}
+		s.logger.Error("获取审核策略失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("获取审核策略失败: %w", err)
+	}
+
+	return policy, nil
+}
+
+// ListPolicies 获取审核策略列表
+func (s *knowledgeReviewService) ListPolicies(ctx context.Context) ([]*models.KnowledgeReviewPolicy, error) {
+	policies, err := s.repoManager.KnowledgeReview().ListPolicies(ctx)
+	if err != nil {
+		s.logger.Error("获取审核策略列表失败", zap.Error(err))
+		return nil, fmt.Errorf("获取审核策略列表失败: %w", err)
+	}
+
+	return policies, nil
+}
+
+// UpdatePolicy 更新审核策略，已提交的审核不受影响
+func (s *knowledgeReviewService) UpdatePolicy(ctx context.Context, policy *models.KnowledgeReviewPolicy) error {
+	if policy == nil || policy.ID == "" {
+		return fmt.Errorf("审核策略ID不能为空")
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	exists, err := s.repoManager.KnowledgeReview().ExistsPolicyForCategory(ctx, policy.CategoryID, policy.ID)
+	if err != nil {
+		s.logger.Error("检查审核策略是否存在失败", zap.Error(err), zap.String("id", policy.ID))
+		return fmt.Errorf("检查审核策略是否存在失败: %w", err)
+	}
+	if exists {
+		return models.ErrKnowledgeReviewPolicyExists
+	}
+
+	if err := s.repoManager.KnowledgeReview().UpdatePolicy(ctx, policy); err != nil {
+		if errors.Is(err, models.ErrKnowledgeReviewPolicyNotFound) {
+			return err
+		}
+		s.logger.Error("更新审核策略失败", zap.Error(err), zap.String("id", policy.ID))
+		return fmt.Errorf("更新审核策略失败: %w", err)
+	}
+
+	s.logger.Info("审核策略更新成功", zap.String("id", policy.ID))
+	return nil
+}
+
+// DeletePolicy 删除审核策略
+func (s *knowledgeReviewService) DeletePolicy(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("审核策略ID不能为空")
+	}
+
+	if err := s.repoManager.KnowledgeReview().DeletePolicy(ctx, id); err != nil {
+		if errors.Is(err, models.ErrKnowledgeReviewPolicyNotFound) {
+			return err
+		}
+		s.logger.Error("删除审核策略失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("删除审核策略失败: %w", err)
+	}
+
+	s.logger.Info("审核策略删除成功", zap.String("id", id))
+	return nil
+}
+
+// SubmitForReview 提交知识库文章审核，按文章分类匹配审核策略
+func (s *knowledgeReviewService) SubmitForReview(ctx context.Context, knowledgeID, userID string) (*models.KnowledgeReviewSubmission, error) {
+	if knowledgeID == "" || userID == "" {
+		return nil, fmt.Errorf("知识库条目ID和提交人不能为空")
+	}
+
+	var submission *models.KnowledgeReviewSubmission
+	err := s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		knowledge, err := repo.Knowledge().GetByID(ctx, knowledgeID)
+		if err != nil {
+			return err
+		}
+
+		policy, err := repo.KnowledgeReview().GetPolicyForCategory(ctx, knowledge.CategoryID)
+		if err != nil {
+			if !errors.Is(err, models.ErrKnowledgeReviewPolicyNotFound) {
+				return err
+			}
+			policy = models.DefaultKnowledgeReviewPolicy()
+		}
+
+		if err := repo.Knowledge().SubmitForReview(ctx, knowledgeID); err != nil {
+			return err
+		}
+
+		submission = models.NewKnowledgeReviewSubmission(knowledgeID, userID, policy)
+		submission.KnowledgeTitle = knowledge.Title
+		return repo.KnowledgeReview().CreateSubmission(ctx, submission)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) || errors.Is(err, models.ErrKnowledgeInvalidStatus) {
+			return nil, err
+		}
+		s.logger.Error("提交知识库审核失败", zap.Error(err), zap.String("id", knowledgeID))
+		return nil, fmt.Errorf("提交知识库审核失败: %w", err)
+	}
+
+	s.logger.Info("知识库条目已提交审核",
+		zap.String("id", knowledgeID),
+		zap.String("submission_id", submission.ID),
+		zap.Int("required_approvals", submission.RequiredApprovals),
+	)
+	return submission, nil
+}
+
+// Approve 审核通过，达到策略要求的通过人数后发布文章
+func (s *knowledgeReviewService) Approve(ctx context.Context, knowledgeID, reviewerID string, comment *string) (*models.KnowledgeReviewSubmission, error) {
+	return s.decide(ctx, knowledgeID, reviewerID, models.KnowledgeReviewDecisionApproved, comment)
+}
+
+// Reject 审核驳回，任一审核人驳回即退回草稿
+func (s *knowledgeReviewService) Reject(ctx context.Context, knowledgeID, reviewerID string, comment *string) (*models.KnowledgeReviewSubmission, error) {
+	return s.decide(ctx, knowledgeID, reviewerID, models.KnowledgeReviewDecisionRejected, comment)
+}
+
+// GetReviewStatus 获取文章最近一次审核提交及审核记录
+func (s *knowledgeReviewService) GetReviewStatus(ctx context.Context, knowledgeID string) (*models.KnowledgeReviewSubmission, error) {
+	if knowledgeID == "" {
+		return nil, fmt.Errorf("知识库条目ID不能为空")
+	}
+
+	submission, err := s.repoManager.KnowledgeReview().GetLatestSubmission(ctx, knowledgeID)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeReviewNotFound) {
+			return nil, err
+		}
+		s.logger.Error("获取审核状态失败", zap.Error(err), zap.String("id", knowledgeID))
+		return nil, fmt.Errorf("获取审核状态失败: %w", err)
+	}
+
+	records, err := s.repoManager.KnowledgeReview().GetRecords(ctx, submission.ID)
+	if err != nil {
+		s.logger.Error("获取审核记录失败", zap.Error(err), zap.String("submission_id", submission.ID))
+		return nil, fmt.Errorf("获取审核记录失败: %w", err)
+	}
+	submission.Decisions = records
+
+	return submission, nil
+}
+
+// ListPendingReviews 获取用户的待审核队列
+func (s *knowledgeReviewService) ListPendingReviews(ctx context.Context, userID string, page, pageSize int) (*models.KnowledgeReviewQueue, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("用户ID不能为空")
+	}
+
+	user, err := s.repoManager.User().GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("获取用户信息失败", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	queue, err := s.repoManager.KnowledgeReview().ListPendingForReviewer(ctx, &models.KnowledgeReviewQueueFilter{
+		ReviewerID:   userID,
+		ReviewerRole: user.Role,
+		Page:         page,
+		PageSize:     pageSize,
+	})
+	if err != nil {
+		s.logger.Error("获取待审核队列失败", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("获取待审核队列失败: %w", err)
+	}
+
+	return queue, nil
+}
+
+// decide 记录审核决定并推进文章状态
+func (s *knowledgeReviewService) decide(ctx context.Context, knowledgeID, reviewerID string, decision models.KnowledgeReviewDecision, comment *string) (*models.KnowledgeReviewSubmission, error) {
+	if knowledgeID == "" || reviewerID == "" {
+		return nil, fmt.Errorf("知识库条目ID和审核人不能为空")
+	}
+
+	reviewer, err := s.repoManager.User().GetByID(ctx, reviewerID)
+	if err != nil {
+		s.logger.Error("获取审核人信息失败", zap.Error(err), zap.String("reviewer_id", reviewerID))
+		return nil, fmt.Errorf("获取审核人信息失败: %w", err)
+	}
+
+	var submission *models.KnowledgeReviewSubmission
+	err = s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		// 锁定待审核提交，避免并发审核导致计数错误
+		submission, err = repo.KnowledgeReview().GetPendingSubmission(ctx, knowledgeID)
+		if err != nil {
+			return err
+		}
+
+		if !submission.CanReview(reviewerID, reviewer.Role) {
+			return fmt.Errorf("%w: 不在该文章的审核人范围内", models.ErrPermissionDenied)
+		}
+
+		if err := repo.KnowledgeReview().AddRecord(ctx, &models.KnowledgeReviewRecord{
+			SubmissionID: submission.ID,
+			ReviewerID:   reviewerID,
+			Decision:     decision,
+			Comment:      comment,
+		}); err != nil {
+			return err
+		}
+
+		records, err := repo.KnowledgeReview().GetRecords(ctx, submission.ID)
+		if err != nil {
+			return err
+		}
+		submission.Decisions = records
+		submission.Approvals = 0
+		for _, record := range records {
+			if record.Decision == models.KnowledgeReviewDecisionApproved {
+				submission.Approvals++
+			}
+		}
+
+		switch {
+		case decision == models.KnowledgeReviewDecisionRejected:
+			if err := repo.KnowledgeReview().CompleteSubmission(ctx, submission.ID, models.KnowledgeReviewSubmissionRejected); err != nil {
+				return err
+			}
+			submission.Status = models.KnowledgeReviewSubmissionRejected
+			return repo.Knowledge().Reject(ctx, knowledgeID, reviewerID, comment)
+
+		case submission.Approvals >= submission.RequiredApprovals:
+			if err := repo.KnowledgeReview().CompleteSubmission(ctx, submission.ID, models.KnowledgeReviewSubmissionApproved); err != nil {
+				return err
+			}
+			submission.Status = models.KnowledgeReviewSubmissionApproved
+			return repo.Knowledge().Approve(ctx, knowledgeID, reviewerID, comment)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeReviewNotFound) || errors.Is(err, models.ErrKnowledgeReviewDecided) ||
+			errors.Is(err, models.ErrPermissionDenied) || errors.Is(err, models.ErrKnowledgeInvalidStatus) {
+			return nil, err
+		}
+		s.logger.Error("审核知识库条目失败", zap.Error(err), zap.String("id", knowledgeID), zap.String("reviewer_id", reviewerID))
+		return nil, fmt.Errorf("审核知识库条目失败: %w", err)
+	}
+
+	s.logger.Info("知识库条目审核记录成功",
+		zap.String("id", knowledgeID),
+		zap.String("reviewer_id", reviewerID),
+		zap.String("decision", string(decision)),
+		zap.Int("approvals", submission.Approvals),
+		zap.Int("required_approvals", submission.RequiredApprovals),
+		zap.String("status", string(submission.Status)),
+	)
+	return submission, nil
+}
+
+// withTx 在事务中执行操作
+func (s *knowledgeReviewService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
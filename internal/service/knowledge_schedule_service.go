@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// knowledgeScheduleService 知识库定时发布与过期提醒服务实现
+type knowledgeScheduleService struct {
+	repoManager   repository.RepositoryManager
+	notifications NotificationService
+	cfg           config.KnowledgeConfig
+	logger        *zap.Logger
+}
+
+// NewKnowledgeScheduleService 创建知识库定时发布服务实例
+func NewKnowledgeScheduleService(repoManager repository.RepositoryManager, notifications NotificationService, cfg config.KnowledgeConfig, logger *zap.Logger) KnowledgeScheduleService {
+	return &knowledgeScheduleService{
+		repoManager:   repoManager,
+		notifications: notifications,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Interval 调度检查间隔
+func (s *knowledgeScheduleService) Interval() time.Duration {
+	if s.cfg.SchedulerInterval <= 0 {
+		return time.Minute
+	}
+	return s.cfg.SchedulerInterval
+}
+
+// Schedule 设置文章在指定时间自动发布
+func (s *knowledgeScheduleService) Schedule(ctx context.Context, id string, req *models.KnowledgeScheduleRequest) (*models.Knowledge, error) {
+	if id == "" {
+		return nil, fmt.Errorf("知识库条目ID不能为空")
+	}
+	if !req.PublishAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: 定时发布时间必须晚于当前时间", models.ErrInvalidInput)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(req.PublishAt) {
+		return nil, fmt.Errorf("%w: 过期时间必须晚于发布时间", models.ErrInvalidInput)
+	}
+
+	knowledge, err := s.repoManager.Knowledge().GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取知识库条目失败: %w", err)
+	}
+	if !knowledge.CanSchedule() {
+		return nil, fmt.Errorf("%w: 只有草稿可以设置定时发布", models.ErrKnowledgeInvalidStatus)
+	}
+
+	if err := s.repoManager.Knowledge().SchedulePublish(ctx, id, req.PublishAt); err != nil {
+		if errors.Is(err, models.ErrKnowledgeInvalidStatus) {
+			return nil, err
+		}
+		s.logger.Error("设置定时发布失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("设置定时发布失败: %w", err)
+	}
+
+	if req.ExpiresAt != nil {
+		if err := s.repoManager.Knowledge().UpdateExpiry(ctx, id, req.ExpiresAt); err != nil {
+			s.logger.Error("更新过期时间失败", zap.Error(err), zap.String("id", id))
+			return nil, fmt.Errorf("更新过期时间失败: %w", err)
+		}
+		knowledge.ExpiresAt = req.ExpiresAt
+	}
+
+	publishAt := req.PublishAt
+	knowledge.Status = models.KnowledgeStatusScheduled
+	knowledge.PublishAt = &publishAt
+
+	s.logger.Info("设置定时发布成功", zap.String("id", id), zap.Time("publish_at", publishAt))
+	return knowledge, nil
+}
+
+// CancelSchedule 取消定时发布
+func (s *knowledgeScheduleService) CancelSchedule(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("知识库条目ID不能为空")
+	}
+
+	if err := s.repoManager.Knowledge().CancelSchedule(ctx, id); err != nil {
+		if errors.Is(err, models.ErrKnowledgeInvalidStatus) {
+			return fmt.Errorf("%w: 文章未设置定时发布", err)
+		}
+		s.logger.Error("取消定时发布失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("取消定时发布失败: %w", err)
+	}
+
+	s.logger.Info("取消定时发布成功", zap.String("id", id))
+	return nil
+}
+
+// UpdateExpiry 更新文章过期时间，作者复查后可延长有效期
+func (s *knowledgeScheduleService) UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error {
+	if id == "" {
+		return fmt.Errorf("知识库条目ID不能为空")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: 过期时间必须晚于当前时间", models.ErrInvalidInput)
+	}
+
+	if err := s.repoManager.Knowledge().UpdateExpiry(ctx, id, expiresAt); err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) {
+			return err
+		}
+		s.logger.Error("更新过期时间失败", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("更新过期时间失败: %w", err)
+	}
+
+	s.logger.Info("更新过期时间成功", zap.String("id", id))
+	return nil
+}
+
+// PublishDue 发布所有到期的定时文章，返回发布数量
+func (s *knowledgeScheduleService) PublishDue(ctx context.Context) (int, error) {
+	ids, err := s.repoManager.Knowledge().PublishDue(ctx, time.Now())
+	if err != nil {
+		s.logger.Error("发布定时文章失败", zap.Error(err))
+		return 0, fmt.Errorf("发布定时文章失败: %w", err)
+	}
+
+	for _, id := range ids {
+		s.logger.Info("定时文章已发布", zap.String("id", id))
+	}
+
+	return len(ids), nil
+}
+
+// ExpireOverdue 将已过期的已发布文章标记为过期
+func (s *knowledgeScheduleService) ExpireOverdue(ctx context.Context) (int64, error) {
+	expired, err := s.repoManager.Knowledge().CleanupExpired(ctx)
+	if err != nil {
+		s.logger.Error("标记过期文章失败", zap.Error(err))
+		return 0, fmt.Errorf("标记过期文章失败: %w", err)
+	}
+
+	if expired > 0 {
+		s.logger.Info("文章已过期", zap.Int64("count", expired))
+	}
+
+	return expired, nil
+}
+
+// SendExpiryWarnings 向即将过期文章的作者发送复查提醒，返回提醒数量
+func (s *knowledgeScheduleService) SendExpiryWarnings(ctx context.Context) (int, error) {
+	if s.cfg.ExpiryWarningDays <= 0 || s.notifications == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	before := now.AddDate(0, 0, s.cfg.ExpiryWarningDays)
+	articles, err := s.repoManager.Knowledge().ListExpiring(ctx, before, 100)
+	if err != nil {
+		s.logger.Error("获取即将过期文章失败", zap.Error(err))
+		return 0, fmt.Errorf("获取即将过期文章失败: %w", err)
+	}
+
+	warned := 0
+	for _, article := range articles {
+		sent, err := s.warnAuthor(ctx, article)
+		if err != nil {
+			// 发送失败不记录，下一轮重试
+			s.logger.Warn("发送过期提醒失败", zap.Error(err), zap.String("id", article.ID))
+			continue
+		}
+
+		if err := s.repoManager.Knowledge().MarkExpiryWarned(ctx, article.ID, now); err != nil {
+			s.logger.Error("记录过期提醒失败", zap.Error(err), zap.String("id", article.ID))
+			continue
+		}
+		if sent {
+			warned++
+		}
+	}
+
+	return warned, nil
+}
+
+// warnAuthor 发送过期提醒，作者无邮箱时跳过并返回 false
+func (s *knowledgeScheduleService) warnAuthor(ctx context.Context, article *models.Knowledge) (bool, error) {
+	author, err := s.repoManager.User().GetByID(ctx, article.AuthorID)
+	if err != nil || author.Email == "" {
+		s.logger.Warn("文章作者无可用邮箱，跳过过期提醒", zap.String("id", article.ID), zap.String("author_id", article.AuthorID))
+		return false, nil
+	}
+
+	notification := &models.Notification{
+		Type:       models.NotificationTypeEmail,
+		Recipient:  author.Email,
+		MessageKey: "notification.knowledge_expiry",
+		MessageData: map[string]interface{}{
+			"Title":     article.Title,
+			"ExpiresAt": article.ExpiresAt.Format("2006-01-02 15:04"),
+		},
+	}
+	if err := s.notifications.Send(ctx, notification); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
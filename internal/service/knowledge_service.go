@@ -2,25 +2,40 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	"pulse/internal/config"
 	"pulse/internal/models"
+	"pulse/internal/pkg/markdown"
+	"pulse/internal/pkg/slug"
 	"pulse/internal/repository"
 )
 
+// maxSlugAttempts 生成唯一slug时最多尝试的序号
+const maxSlugAttempts = 100
+
 // knowledgeService 知识库服务实现
 type knowledgeService struct {
 	repoManager repository.RepositoryManager
 	logger      *zap.Logger
+	storage     config.FileStorageConfig
 }
 
 // NewKnowledgeService 创建知识库服务实例
-func NewKnowledgeService(repoManager repository.RepositoryManager, logger *zap.Logger) KnowledgeService {
+func NewKnowledgeService(repoManager repository.RepositoryManager, logger *zap.Logger, storage config.FileStorageConfig) KnowledgeService {
 	return &knowledgeService{
 		repoManager: repoManager,
 		logger:      logger,
+		storage:     storage,
 	}
 }
 
@@ -55,7 +70,18 @@ func (s *knowledgeService) Create(ctx context.Context, knowledge *models.Knowled
 		knowledge.Visibility = models.KnowledgeVisibilityPublic
 	}
 
-	err := s.repoManager.Knowledge().Create(ctx, knowledge)
+	// 未指定slug时根据标题生成，指定的slug冲突时报错
+	var err error
+	if knowledge.Slug != "" {
+		knowledge.Slug, err = uniqueKnowledgeSlug(ctx, s.repoManager.Knowledge(), knowledge.Slug, "", false)
+	} else {
+		knowledge.Slug, err = uniqueKnowledgeSlug(ctx, s.repoManager.Knowledge(), knowledge.Title, "", true)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.repoManager.Knowledge().Create(ctx, knowledge)
 	if err != nil {
 		s.logger.Error("创建知识库条目失败", zap.Error(err), zap.String("title", knowledge.Title))
 		return fmt.Errorf("创建知识库条目失败: %w", err)
@@ -111,16 +137,66 @@ func (s *knowledgeService) Update(ctx context.Context, knowledge *models.Knowled
 	knowledge.CreatedAt = existing.CreatedAt
 	knowledge.AuthorID = existing.AuthorID
 
+	// 显式指定slug时使用指定值，标题修改时重新生成，旧slug保留用于重定向
+	newSlug := ""
+	switch {
+	case knowledge.Slug != "":
+		newSlug, err = uniqueKnowledgeSlug(ctx, s.repoManager.Knowledge(), knowledge.Slug, knowledge.ID, false)
+	case knowledge.Title != existing.Title:
+		newSlug, err = uniqueKnowledgeSlug(ctx, s.repoManager.Knowledge(), knowledge.Title, knowledge.ID, true)
+	}
+	if err != nil {
+		return err
+	}
+
 	err = s.repoManager.Knowledge().Update(ctx, knowledge)
 	if err != nil {
 		s.logger.Error("更新知识库条目失败", zap.Error(err), zap.String("id", knowledge.ID))
 		return fmt.Errorf("更新知识库条目失败: %w", err)
 	}
 
+	if newSlug != "" {
+		if err := s.repoManager.Knowledge().UpdateSlug(ctx, knowledge.ID, newSlug); err != nil {
+			s.logger.Error("更新知识库条目slug失败", zap.Error(err), zap.String("id", knowledge.ID))
+			return fmt.Errorf("更新知识库条目slug失败: %w", err)
+		}
+		knowledge.Slug = newSlug
+	}
+
 	s.logger.Info("知识库条目更新成功", zap.String("id", knowledge.ID), zap.String("title", knowledge.Title))
 	return nil
 }
 
+// GetBySlug 根据slug获取知识库条目，命中改名前的旧slug时返回重定向信息
+func (s *knowledgeService) GetBySlug(ctx context.Context, value string) (*models.KnowledgeSlugLookup, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: slug不能为空", models.ErrInvalidInput)
+	}
+
+	knowledge, err := s.repoManager.Knowledge().GetBySlug(ctx, value)
+	if err == nil {
+		return &models.KnowledgeSlugLookup{
+			KnowledgeID: knowledge.ID,
+			Slug:        value,
+			Knowledge:   knowledge,
+		}, nil
+	}
+	if !errors.Is(err, models.ErrKnowledgeNotFound) {
+		s.logger.Error("根据slug获取知识库条目失败", zap.Error(err), zap.String("slug", value))
+		return nil, fmt.Errorf("获取知识库条目失败: %w", err)
+	}
+
+	lookup, err := s.repoManager.Knowledge().ResolveSlugRedirect(ctx, value)
+	if err != nil {
+		if errors.Is(err, models.ErrKnowledgeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取知识库条目失败: %w", err)
+	}
+
+	return lookup, nil
+}
+
 // Delete 删除知识库条目
 func (s *knowledgeService) Delete(ctx context.Context, id string) error {
 	if id == "" {
@@ -145,7 +221,7 @@ func (s *knowledgeService) Delete(ctx context.Context, id string) error {
 }
 
 // Search 搜索知识库条目
-func (s *knowledgeService) Search(ctx context.Context, query string) ([]*models.Knowledge, error) {
+func (s *knowledgeService) Search(ctx context.Context, query string, viewerID *string) ([]*models.Knowledge, error) {
 	if query == "" {
 		return nil, fmt.Errorf("搜索关键词不能为空")
 	}
@@ -158,6 +234,7 @@ func (s *knowledgeService) Search(ctx context.Context, query string) ([]*models.
 			status := models.KnowledgeStatusPublished
 			return &status
 		}(),
+		ViewerID: viewerID,
 	}
 
 	result, err := s.repoManager.Knowledge().Search(ctx, query, filter)
@@ -167,4 +244,201 @@ func (s *knowledgeService) Search(ctx context.Context, query string) ([]*models.
 	}
 
 	return result.Knowledge, nil
-}
\ No newline at end of file
+}
+
+// CheckDuplicates 查找与给定标题和内容相似的已有文章
+func (s *knowledgeService) CheckDuplicates(ctx context.Context, req *models.KnowledgeDuplicateCheckRequest) ([]*models.KnowledgeDuplicateCandidate, error) {
+	if req == nil {
+		return nil, fmt.Errorf("%w: 重复检测请求不能为空", models.ErrInvalidInput)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+
+	candidates, err := s.repoManager.Knowledge().FindSimilar(ctx, req)
+	if err != nil {
+		s.logger.Error("查找相似文章失败", zap.Error(err), zap.String("title", req.Title))
+		return nil, fmt.Errorf("查找相似文章失败: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// Render 渲染知识库条目为安全的HTML并生成目录
+func (s *knowledgeService) Render(ctx context.Context, id string) (*models.KnowledgeRendered, error) {
+	knowledge, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repoManager.Knowledge().GetAttachments(ctx, knowledge.ID)
+	if err != nil {
+		s.logger.Error("获取知识库附件失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("获取知识库附件失败: %w", err)
+	}
+
+	opts := markdown.Options{ImageResolver: s.imageResolver(knowledge.ID, attachments)}
+
+	var doc *markdown.Document
+	switch knowledge.Format {
+	case models.KnowledgeFormatMarkdown:
+		doc, err = markdown.Render(knowledge.Content, opts)
+	case models.KnowledgeFormatHTML:
+		doc, err = markdown.Sanitize(knowledge.Content, opts)
+	default:
+		// 纯文本、JSON、YAML 原样展示
+		doc = &markdown.Document{
+			HTML: "<pre>" + html.EscapeString(knowledge.Content) + "</pre>",
+			TOC:  []markdown.TOCEntry{},
+		}
+	}
+	if err != nil {
+		s.logger.Error("渲染知识库条目失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("渲染知识库条目失败: %w", err)
+	}
+
+	toc := make([]models.KnowledgeTOCEntry, 0, len(doc.TOC))
+	for _, entry := range doc.TOC {
+		toc = append(toc, models.KnowledgeTOCEntry{Level: entry.Level, Text: entry.Text, Anchor: entry.Anchor})
+	}
+
+	return &models.KnowledgeRendered{
+		KnowledgeID: knowledge.ID,
+		Title:       knowledge.Title,
+		Format:      knowledge.Format,
+		Version:     knowledge.Version,
+		HTML:        doc.HTML,
+		TOC:         toc,
+		RenderedAt:  time.Now(),
+	}, nil
+}
+
+// GetAttachment 获取知识库条目下的附件
+func (s *knowledgeService) GetAttachment(ctx context.Context, knowledgeID, attachmentID string) (*models.KnowledgeAttachment, error) {
+	if knowledgeID == "" || attachmentID == "" {
+		return nil, fmt.Errorf("知识库条目ID和附件ID不能为空")
+	}
+
+	attachments, err := s.repoManager.Knowledge().GetAttachments(ctx, knowledgeID)
+	if err != nil {
+		s.logger.Error("获取知识库附件失败", zap.Error(err), zap.String("id", knowledgeID))
+		return nil, fmt.Errorf("获取知识库附件失败: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		if attachment.ID == attachmentID {
+			return attachment, nil
+		}
+	}
+
+	return nil, models.ErrAttachmentNotFound
+}
+
+// imageResolver 将文章中的图片引用解析为附件访问地址
+// 支持按附件ID（attachment:<id>）、文件名或存储路径文件名引用
+func (s *knowledgeService) imageResolver(knowledgeID string, attachments []*models.KnowledgeAttachment) markdown.ImageResolver {
+	index := make(map[string]*models.KnowledgeAttachment, len(attachments)*3)
+	for _, attachment := range attachments {
+		index[attachment.ID] = attachment
+		if attachment.FileName != "" {
+			index[attachment.FileName] = attachment
+		}
+		if attachment.FilePath != "" {
+			index[path.Base(attachment.FilePath)] = attachment
+		}
+	}
+
+	return func(src string) (string, bool) {
+		ref := strings.TrimSpace(src)
+		if u, err := url.Parse(ref); err != nil || (u.Scheme != "" && u.Scheme != "attachment") || u.Host != "" {
+			return "", false
+		}
+		ref = strings.TrimPrefix(ref, "attachment:")
+		ref = strings.TrimPrefix(ref, "./")
+
+		attachment, ok := index[ref]
+		if !ok {
+			attachment, ok = index[path.Base(ref)]
+		}
+		if !ok {
+			return "", false
+		}
+		return s.attachmentURL(knowledgeID, attachment), true
+	}
+}
+
+// attachmentURL 根据存储后端生成附件访问地址
+func (s *knowledgeService) attachmentURL(knowledgeID string, attachment *models.KnowledgeAttachment) string {
+	key := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(attachment.FilePath)), "/")
+
+	if s.storage.PublicURL != "" {
+		if s.storage.Type == "local" || s.storage.Type == "" {
+			localRoot := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(s.storage.LocalPath)), "/")
+			key = strings.TrimPrefix(strings.TrimPrefix(key, localRoot), "/")
+		}
+		return strings.TrimRight(s.storage.PublicURL, "/") + "/" + escapePath(key)
+	}
+
+	switch s.storage.Type {
+	case "s3":
+		if s.storage.S3.Endpoint != "" {
+			return strings.TrimRight(s.storage.S3.Endpoint, "/") + "/" + s.storage.S3.Bucket + "/" + escapePath(key)
+		}
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.storage.S3.Bucket, s.storage.S3.Region, escapePath(key))
+	case "oss":
+		endpoint := strings.TrimPrefix(strings.TrimPrefix(s.storage.OSS.Endpoint, "https://"), "http://")
+		return fmt.Sprintf("https://%s.%s/%s", s.storage.OSS.Bucket, strings.TrimRight(endpoint, "/"), escapePath(key))
+	}
+
+	// 本地存储通过API读取
+	return fmt.Sprintf("/api/v1/knowledge/%s/attachments/%s/raw", url.PathEscape(knowledgeID), url.PathEscape(attachment.ID))
+}
+
+// uniqueKnowledgeSlug 根据来源文本生成未被占用的slug
+// allowSuffix 为 true 时冲突自动追加序号，否则返回 ErrKnowledgeExists；
+// knowledgeID 不为空时该文章自己当前或以前使用的slug视为可用
+func uniqueKnowledgeSlug(ctx context.Context, repo repository.KnowledgeRepository, source, knowledgeID string, allowSuffix bool) (string, error) {
+	base := slug.Make(source)
+	if base == "" {
+		if !allowSuffix {
+			return "", fmt.Errorf("%w: slug只能包含字母、数字和汉字", models.ErrInvalidInput)
+		}
+		base = "knowledge"
+	}
+
+	for n := 1; n <= maxSlugAttempts; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = slug.WithSuffix(base, n)
+		}
+
+		taken, err := repo.ExistsBySlug(ctx, candidate)
+		if err != nil {
+			return "", fmt.Errorf("检查slug失败: %w", err)
+		}
+		if taken && knowledgeID != "" {
+			owner, err := repo.GetSlugOwner(ctx, candidate)
+			if err != nil {
+				return "", fmt.Errorf("检查slug失败: %w", err)
+			}
+			taken = owner != knowledgeID
+		}
+		if !taken {
+			return candidate, nil
+		}
+		if !allowSuffix {
+			return "", fmt.Errorf("%w: slug %s 已被使用", models.ErrKnowledgeExists, candidate)
+		}
+	}
+
+	return "", fmt.Errorf("%w: 无法为 %s 生成唯一的slug", models.ErrKnowledgeExists, base)
+}
+
+// escapePath 按段转义对象路径
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
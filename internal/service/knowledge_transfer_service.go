@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/knowledgeio"
+	"pulse/internal/repository"
+)
+
+// knowledgeExportPageSize 导出时每次查询的文章数量
+const knowledgeExportPageSize = 100
+
+// knowledgeTransferService 知识库导入导出服务实现
+type knowledgeTransferService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewKnowledgeTransferService 创建知识库导入导出服务实例
+func NewKnowledgeTransferService(repoManager repository.RepositoryManager, logger *zap.Logger) KnowledgeTransferService {
+	return &knowledgeTransferService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// Import 导入 Markdown 压缩包或 Confluence 空间导出
+// 文章以草稿状态导入，分类不存在时自动创建，单篇失败不影响其他文章
+func (s *knowledgeTransferService) Import(ctx context.Context, format models.KnowledgeImportFormat, data []byte, authorID string) (*models.KnowledgeImportResult, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("%w: 不支持的导入格式 %s", models.ErrInvalidInput, format)
+	}
+	if authorID == "" {
+		return nil, fmt.Errorf("%w: 导入人不能为空", models.ErrInvalidInput)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: 导入文件为空", models.ErrInvalidInput)
+	}
+
+	result := &models.KnowledgeImportResult{
+		Format:            format,
+		CreatedCategories: make([]string, 0),
+		Items:             make([]*models.KnowledgeImportItem, 0),
+	}
+
+	var docs []*knowledgeio.Document
+	switch format {
+	case models.KnowledgeImportFormatMarkdown:
+		parsed, fileErrors, err := knowledgeio.ReadMarkdownBundle(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+		}
+		for _, fileErr := range fileErrors {
+			result.Items = append(result.Items, &models.KnowledgeImportItem{Path: fileErr.Path, Error: fileErr.Err.Error()})
+			result.Failed++
+		}
+		docs = parsed
+	case models.KnowledgeImportFormatConfluence:
+		parsed, err := knowledgeio.ReadConfluenceExport(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+		}
+		docs = parsed
+	}
+
+	categories, err := s.categoryIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		item := &models.KnowledgeImportItem{Path: doc.Path, Title: doc.Title}
+		result.Items = append(result.Items, item)
+
+		categoryID, created, err := s.resolveCategory(ctx, categories, doc.Category)
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		if created {
+			result.CreatedCategories = append(result.CreatedCategories, doc.Category)
+		}
+
+		knowledge := documentToKnowledge(doc, authorID, categoryID)
+		source := knowledge.Slug
+		if source == "" {
+			source = knowledge.Title
+		}
+		knowledge.Slug, err = uniqueKnowledgeSlug(ctx, s.repoManager.Knowledge(), source, "", true)
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+			continue
+		}
+
+		if err := s.repoManager.Knowledge().Create(ctx, knowledge); err != nil {
+			s.logger.Warn("导入知识库文章失败", zap.Error(err), zap.String("path", doc.Path))
+			item.Error = err.Error()
+			result.Failed++
+			continue
+		}
+
+		item.ID = knowledge.ID
+		result.Imported++
+	}
+
+	result.Total = result.Imported + result.Failed
+	s.logger.Info("知识库导入完成",
+		zap.String("format", string(format)),
+		zap.Int("imported", result.Imported),
+		zap.Int("failed", result.Failed),
+		zap.String("author_id", authorID))
+	return result, nil
+}
+
+// Export 将指定分类下的文章导出为 Markdown 压缩包，返回导出数量
+// viewerID 不为空时只导出该用户有权查看的文章
+func (s *knowledgeTransferService) Export(ctx context.Context, categoryIDs []string, viewerID *string, w io.Writer) (int, error) {
+	if len(categoryIDs) == 0 {
+		return 0, fmt.Errorf("%w: 请选择要导出的分类", models.ErrInvalidInput)
+	}
+
+	categories, err := s.repoManager.Knowledge().GetCategories(ctx)
+	if err != nil {
+		s.logger.Error("获取分类列表失败", zap.Error(err))
+		return 0, fmt.Errorf("获取分类列表失败: %w", err)
+	}
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	var docs []*knowledgeio.Document
+	for _, categoryID := range categoryIDs {
+		name, ok := names[categoryID]
+		if !ok {
+			return 0, fmt.Errorf("%w: 分类 %s 不存在", models.ErrInvalidInput, categoryID)
+		}
+
+		id := categoryID
+		for page := 1; ; page++ {
+			list, err := s.repoManager.Knowledge().List(ctx, &models.KnowledgeFilter{
+				CategoryID: &id,
+				Page:       page,
+				PageSize:   knowledgeExportPageSize,
+				ViewerID:   viewerID,
+			})
+			if err != nil {
+				s.logger.Error("获取分类文章失败", zap.Error(err), zap.String("category_id", categoryID))
+				return 0, fmt.Errorf("获取分类文章失败: %w", err)
+			}
+
+			for _, article := range list.Knowledge {
+				docs = append(docs, knowledgeToDocument(article, name))
+			}
+			if len(list.Knowledge) < knowledgeExportPageSize {
+				break
+			}
+		}
+	}
+
+	if err := knowledgeio.WriteMarkdownBundle(w, docs); err != nil {
+		s.logger.Error("写入导出文件失败", zap.Error(err))
+		return 0, fmt.Errorf("写入导出文件失败: %w", err)
+	}
+
+	s.logger.Info("知识库导出完成", zap.Strings("category_ids", categoryIDs), zap.Int("count", len(docs)))
+	return len(docs), nil
+}
+
+// categoryIndex 按小写名称索引已有分类
+func (s *knowledgeTransferService) categoryIndex(ctx context.Context) (map[string]string, error) {
+	categories, err := s.repoManager.Knowledge().GetCategories(ctx)
+	if err != nil {
+		s.logger.Error("获取分类列表失败", zap.Error(err))
+		return nil, fmt.Errorf("获取分类列表失败: %w", err)
+	}
+
+	index := make(map[string]string, len(categories))
+	for _, category := range categories {
+		index[strings.ToLower(category.Name)] = category.ID
+	}
+	return index, nil
+}
+
+// resolveCategory 根据名称查找分类，不存在时创建
+func (s *knowledgeTransferService) resolveCategory(ctx context.Context, index map[string]string, name string) (*string, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, nil
+	}
+
+	key := strings.ToLower(name)
+	if id, ok := index[key]; ok {
+		return &id, false, nil
+	}
+
+	category := &models.KnowledgeCategory{
+		Name:     name,
+		IsActive: true,
+	}
+	if err := s.repoManager.Knowledge().CreateCategory(ctx, category); err != nil {
+		s.logger.Error("创建分类失败", zap.Error(err), zap.String("name", name))
+		return nil, false, fmt.Errorf("创建分类 %s 失败: %w", name, err)
+	}
+
+	index[key] = category.ID
+	return &category.ID, true, nil
+}
+
+// documentToKnowledge 将导入文档转换为草稿文章
+func documentToKnowledge(doc *knowledgeio.Document, authorID string, categoryID *string) *models.Knowledge {
+	knowledge := &models.Knowledge{
+		Title:      doc.Title,
+		Slug:       doc.Slug,
+		Content:    doc.Content,
+		Type:       models.KnowledgeType(doc.Type),
+		Status:     models.KnowledgeStatusDraft,
+		Visibility: models.KnowledgeVisibilityInternal,
+		Format:     models.KnowledgeFormatMarkdown,
+		CategoryID: categoryID,
+		Tags:       doc.Tags,
+		Language:   doc.Language,
+		AuthorID:   authorID,
+	}
+
+	if !knowledge.Type.IsValid() {
+		knowledge.Type = models.KnowledgeTypeArticle
+	}
+	if doc.Format == knowledgeio.FormatHTML {
+		knowledge.Format = models.KnowledgeFormatHTML
+	}
+	if knowledge.Language == "" {
+		knowledge.Language = "zh-CN"
+	}
+	if knowledge.Tags == nil {
+		knowledge.Tags = []string{}
+	}
+	if doc.Summary != "" {
+		summary := doc.Summary
+		knowledge.Summary = &summary
+	}
+
+	return knowledge
+}
+
+// knowledgeToDocument 将文章转换为导出文档
+func knowledgeToDocument(article *models.Knowledge, category string) *knowledgeio.Document {
+	doc := &knowledgeio.Document{
+		Title:    article.Title,
+		Slug:     article.Slug,
+		Category: category,
+		Tags:     article.Tags,
+		Type:     string(article.Type),
+		Language: article.Language,
+		Format:   knowledgeio.FormatMarkdown,
+		Content:  article.Content,
+	}
+	if article.Summary != nil {
+		doc.Summary = *article.Summary
+	}
+	return doc
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/kubernetes"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// kubernetesFingerprintPrefix Kubernetes 告警指纹前缀
+	kubernetesFingerprintPrefix = "kubernetes:"
+	// kubernetesPageSize 分页查询数据源和告警的数量
+	kubernetesPageSize = 100
+	// kubernetesMaxMessageLength 告警描述中保留的事件消息最大字符数
+	kubernetesMaxMessageLength = 800
+)
+
+// KubernetesClientFactory 根据数据源创建集群客户端
+type KubernetesClientFactory func(dataSource *models.DataSource) (kubernetes.Source, error)
+
+// kubernetesService Kubernetes 数据源采集服务实现
+type kubernetesService struct {
+	repoManager  repository.RepositoryManager
+	alertService AlertService
+	newClient    KubernetesClientFactory
+	cfg          config.KubernetesConfig
+	logger       *zap.Logger
+}
+
+// NewKubernetesService 创建 Kubernetes 数据源采集服务实例
+func NewKubernetesService(repoManager repository.RepositoryManager, alertService AlertService, newClient KubernetesClientFactory, cfg config.KubernetesConfig, logger *zap.Logger) KubernetesService {
+	return &kubernetesService{
+		repoManager:  repoManager,
+		alertService: alertService,
+		newClient:    newClient,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// Interval 采集周期
+func (s *kubernetesService) Interval() time.Duration {
+	return s.cfg.SyncInterval
+}
+
+// Sync 采集所有活跃的 Kubernetes 数据源，单个集群失败不影响其他集群，返回触发和解决的告警数量
+func (s *kubernetesService) Sync(ctx context.Context) (int, error) {
+	dsType := models.DataSourceTypeKubernetes
+	status := models.DataSourceStatusActive
+	filter := &models.DataSourceFilter{Type: &dsType, Status: &status, Page: 1, PageSize: kubernetesPageSize}
+
+	changed := 0
+	for {
+		list, err := s.repoManager.DataSource().List(ctx, filter)
+		if err != nil {
+			return changed, fmt.Errorf("获取Kubernetes数据源失败: %w", err)
+		}
+
+		for _, dataSource := range list.DataSources {
+			result, err := s.syncDataSource(ctx, dataSource)
+			if err != nil {
+				s.logger.Error("采集Kubernetes集群失败", zap.Error(err),
+					zap.String("data_source_id", dataSource.ID), zap.String("name", dataSource.Name))
+				continue
+			}
+			changed += result.Fired + result.Resolved
+		}
+
+		if len(list.DataSources) < filter.PageSize {
+			return changed, nil
+		}
+		filter.Page++
+	}
+}
+
+// SyncDataSource 立即采集指定的 Kubernetes 数据源
+func (s *kubernetesService) SyncDataSource(ctx context.Context, id string) (*models.KubernetesSyncResult, error) {
+	dataSource, err := s.repoManager.DataSource().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dataSource == nil {
+		return nil, models.ErrDataSourceNotFound
+	}
+	if dataSource.Type != models.DataSourceTypeKubernetes {
+		return nil, fmt.Errorf("%w: 数据源 %s 不是Kubernetes类型", models.ErrInvalidInput, dataSource.Name)
+	}
+
+	return s.syncDataSource(ctx, dataSource)
+}
+
+// syncDataSource 采集单个集群，异常对应的告警不存在时触发，已恢复的异常解决对应告警
+// 采集失败时不解决任何告警，避免集群短暂不可达时告警反复触发和解决
+func (s *kubernetesService) syncDataSource(ctx context.Context, dataSource *models.DataSource) (*models.KubernetesSyncResult, error) {
+	client, err := s.newClient(dataSource)
+	if err != nil {
+		return nil, fmt.Errorf("创建Kubernetes客户端失败: %w", err)
+	}
+
+	now := time.Now()
+	findings, err := kubernetes.Collect(ctx, client, kubernetes.CollectOptions{
+		Namespaces:       dataSource.Config.Namespaces,
+		EventWindow:      s.cfg.EventWindow,
+		PodNotReadyAfter: s.cfg.PodNotReadyAfter,
+		Now:              now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.activeAlerts(ctx, dataSource.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.KubernetesSyncResult{DataSourceID: dataSource.ID, Findings: len(findings)}
+	for i := range findings {
+		finding := &findings[i]
+		fingerprint := kubernetesFingerprint(dataSource.ID, finding.Key)
+		if alert, ok := active[fingerprint]; ok {
+			delete(active, fingerprint)
+			if err := s.refreshAlert(ctx, alert, finding, now); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		if err := s.fireAlert(ctx, dataSource, finding, fingerprint, now); err != nil {
+			return result, err
+		}
+		result.Fired++
+	}
+
+	for _, alert := range active {
+		alert.Status = models.AlertStatusResolved
+		alert.EndsAt = &now
+		alert.ResolvedAt = &now
+		alert.LastEvalAt = now
+		if err := s.alertService.Update(ctx, alert); err != nil {
+			return result, fmt.Errorf("解决Kubernetes告警失败: %w", err)
+		}
+		result.Resolved++
+	}
+
+	if result.Fired > 0 || result.Resolved > 0 {
+		s.logger.Info("Kubernetes集群告警已同步", zap.String("data_source_id", dataSource.ID),
+			zap.Int("findings", result.Findings), zap.Int("fired", result.Fired), zap.Int("resolved", result.Resolved))
+	}
+	return result, nil
+}
+
+// activeAlerts 获取数据源下未解决的 Kubernetes 告警，按指纹索引
+func (s *kubernetesService) activeAlerts(ctx context.Context, dataSourceID string) (map[string]*models.Alert, error) {
+	source := models.AlertSourceKubernetes
+	alerts := make(map[string]*models.Alert)
+	for _, status := range []models.AlertStatus{models.AlertStatusFiring, models.AlertStatusAcked} {
+		status := status
+		filter := &models.AlertFilter{
+			DataSourceID: &dataSourceID,
+			Source:       &source,
+			Status:       &status,
+			Page:         1,
+			PageSize:     kubernetesPageSize,
+		}
+		for {
+			list, err := s.repoManager.Alert().List(ctx, filter)
+			if err != nil {
+				return nil, fmt.Errorf("获取Kubernetes告警失败: %w", err)
+			}
+			for _, alert := range list.Alerts {
+				alerts[alert.Fingerprint] = alert
+			}
+			if len(list.Alerts) < filter.PageSize {
+				break
+			}
+			filter.Page++
+		}
+	}
+	return alerts, nil
+}
+
+// fireAlert 触发告警，同一异常的历史告警已解决时重新打开
+func (s *kubernetesService) fireAlert(ctx context.Context, dataSource *models.DataSource, finding *kubernetes.Finding, fingerprint string, now time.Time) error {
+	existing, err := s.repoManager.Alert().GetByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, models.ErrAlertNotFound) {
+		return fmt.Errorf("查询Kubernetes告警失败: %w", err)
+	}
+
+	if existing != nil {
+		existing.Status = models.AlertStatusFiring
+		existing.StartsAt = now
+		existing.EndsAt = nil
+		existing.ResolvedAt = nil
+		existing.ResolvedBy = nil
+		existing.AckedAt = nil
+		existing.AckedBy = nil
+		delete(existing.Annotations, models.AlertAnnotationResolveReason)
+		applyKubernetesFinding(existing, finding, now)
+		if err := s.alertService.Update(ctx, existing); err != nil {
+			return fmt.Errorf("重新打开Kubernetes告警失败: %w", err)
+		}
+		return nil
+	}
+
+	labels := finding.Labels()
+	labels["source"] = string(models.AlertSourceKubernetes)
+	labels["cluster"] = dataSource.Name
+
+	alert := &models.Alert{
+		DataSourceID: dataSource.ID,
+		Name:         truncateRunes(finding.Reason+" "+finding.Object(), 200),
+		Status:       models.AlertStatusFiring,
+		Source:       models.AlertSourceKubernetes,
+		Labels:       labels,
+		Expression:   kubernetesExpression(finding),
+		StartsAt:     now,
+		Fingerprint:  fingerprint,
+	}
+	applyKubernetesFinding(alert, finding, now)
+	if err := s.alertService.Create(ctx, alert); err != nil {
+		return fmt.Errorf("创建Kubernetes告警失败: %w", err)
+	}
+	return nil
+}
+
+// refreshAlert 异常仍然存在时更新告警的描述和次数，内容未变化时不写入
+func (s *kubernetesService) refreshAlert(ctx context.Context, alert *models.Alert, finding *kubernetes.Finding, now time.Time) error {
+	count := float64(finding.Count)
+	if alert.Description == kubernetesDescription(finding) && alert.Severity == finding.Severity &&
+		alert.Value != nil && *alert.Value == count {
+		return nil
+	}
+
+	applyKubernetesFinding(alert, finding, now)
+	if err := s.alertService.Update(ctx, alert); err != nil {
+		return fmt.Errorf("更新Kubernetes告警失败: %w", err)
+	}
+	return nil
+}
+
+// applyKubernetesFinding 将异常的最新内容写入告警
+func applyKubernetesFinding(alert *models.Alert, finding *kubernetes.Finding, now time.Time) {
+	count := float64(finding.Count)
+	alert.Description = kubernetesDescription(finding)
+	alert.Severity = finding.Severity
+	alert.Value = &count
+	alert.LastEvalAt = now
+	alert.EvalCount++
+
+	if alert.Annotations == nil {
+		alert.Annotations = map[string]string{}
+	}
+	alert.Annotations["kind"] = string(finding.Kind)
+	alert.Annotations["count"] = strconv.Itoa(int(finding.Count))
+	if !finding.Since.IsZero() {
+		alert.Annotations["since"] = finding.Since.Format(time.RFC3339)
+	}
+}
+
+// kubernetesDescription 告警描述
+func kubernetesDescription(finding *kubernetes.Finding) string {
+	message := truncateRunes(finding.Message, kubernetesMaxMessageLength)
+	if message == "" {
+		message = finding.Reason
+	}
+	if finding.Kind == kubernetes.FindingKindEvent && finding.Count > 1 {
+		return fmt.Sprintf("%s: %s（%d 次）", finding.Object(), message, finding.Count)
+	}
+	return fmt.Sprintf("%s: %s", finding.Object(), message)
+}
+
+// kubernetesExpression 告警表达式，说明异常的判定条件
+func kubernetesExpression(finding *kubernetes.Finding) string {
+	switch finding.Kind {
+	case kubernetes.FindingKindEvent:
+		return fmt.Sprintf(`kube_event{type="Warning", reason=%q}`, finding.Reason)
+	case kubernetes.FindingKindNode:
+		return fmt.Sprintf(`kube_node_condition{reason=%q}`, finding.Reason)
+	default:
+		return fmt.Sprintf(`kube_pod_status{reason=%q}`, finding.Reason)
+	}
+}
+
+// kubernetesFingerprint 告警指纹，资源名称可能很长，对数据源ID和异常的 Key 取摘要以满足指纹长度限制
+func kubernetesFingerprint(dataSourceID, key string) string {
+	sum := sha256.Sum256([]byte(dataSourceID + "|" + key))
+	return kubernetesFingerprintPrefix + hex.EncodeToString(sum[:20])
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/kubernetes"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeKubernetesDataSourceRepository 返回固定的 Kubernetes 数据源
+type fakeKubernetesDataSourceRepository struct {
+	repository.DataSourceRepository
+	dataSources []*models.DataSource
+}
+
+func (r *fakeKubernetesDataSourceRepository) List(ctx context.Context, filter *models.DataSourceFilter) (*models.DataSourceList, error) {
+	return &models.DataSourceList{DataSources: r.dataSources, Total: int64(len(r.dataSources))}, nil
+}
+
+func (r *fakeKubernetesDataSourceRepository) GetByID(ctx context.Context, id string) (*models.DataSource, error) {
+	for _, dataSource := range r.dataSources {
+		if dataSource.ID == id {
+			return dataSource, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeKubernetesAlertRepository 按指纹保存告警
+type fakeKubernetesAlertRepository struct {
+	repository.AlertRepository
+	alerts map[string]*models.Alert
+}
+
+func (r *fakeKubernetesAlertRepository) List(ctx context.Context, filter *models.AlertFilter) (*models.AlertList, error) {
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		if alert.DataSourceID == *filter.DataSourceID && alert.Source == *filter.Source && alert.Status == *filter.Status {
+			alerts = append(alerts, alert)
+		}
+	}
+	return &models.AlertList{Alerts: alerts, Total: int64(len(alerts))}, nil
+}
+
+func (r *fakeKubernetesAlertRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	alert, ok := r.alerts[fingerprint]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+// fakeKubernetesAlertService 将创建和更新的告警写入告警仓储
+type fakeKubernetesAlertService struct {
+	AlertService
+	repo    *fakeKubernetesAlertRepository
+	created int
+	updated int
+}
+
+func (s *fakeKubernetesAlertService) Create(ctx context.Context, alert *models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+	s.repo.alerts[alert.Fingerprint] = alert
+	s.created++
+	return nil
+}
+
+func (s *fakeKubernetesAlertService) Update(ctx context.Context, alert *models.Alert) error {
+	s.repo.alerts[alert.Fingerprint] = alert
+	s.updated++
+	return nil
+}
+
+type kubernetesRepoManager struct {
+	*MockRepositoryManager
+	dataSources *fakeKubernetesDataSourceRepository
+	alerts      *fakeKubernetesAlertRepository
+}
+
+func (m *kubernetesRepoManager) DataSource() repository.DataSourceRepository { return m.dataSources }
+
+func (m *kubernetesRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+// fakeKubernetesSource 可修改的集群资源
+type fakeKubernetesSource struct {
+	nodes []kubernetes.Node
+}
+
+func (s *fakeKubernetesSource) ListWarningEvents(ctx context.Context, namespace string) ([]kubernetes.Event, error) {
+	return nil, nil
+}
+
+func (s *fakeKubernetesSource) ListNodes(ctx context.Context) ([]kubernetes.Node, error) {
+	return s.nodes, nil
+}
+
+func (s *fakeKubernetesSource) ListPods(ctx context.Context, namespace string) ([]kubernetes.Pod, error) {
+	return nil, nil
+}
+
+func notReadyNode(name string) kubernetes.Node {
+	node := kubernetes.Node{Metadata: kubernetes.ObjectMeta{Name: name}}
+	node.Status.Conditions = []kubernetes.Condition{{Type: "Ready", Status: "False", Message: "container runtime is down"}}
+	return node
+}
+
+func TestKubernetesService_SyncFiresAndResolves(t *testing.T) {
+	repoManager := &kubernetesRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		dataSources: &fakeKubernetesDataSourceRepository{dataSources: []*models.DataSource{
+			{ID: "ds1", Name: "prod", Type: models.DataSourceTypeKubernetes, Status: models.DataSourceStatusActive},
+		}},
+		alerts: &fakeKubernetesAlertRepository{alerts: map[string]*models.Alert{}},
+	}
+	alertService := &fakeKubernetesAlertService{repo: repoManager.alerts}
+	source := &fakeKubernetesSource{nodes: []kubernetes.Node{notReadyNode("node-1")}}
+	svc := NewKubernetesService(repoManager, alertService, func(*models.DataSource) (kubernetes.Source, error) {
+		return source, nil
+	}, config.KubernetesConfig{SyncInterval: time.Minute, EventWindow: 15 * time.Minute}, zap.NewNop())
+	ctx := context.Background()
+
+	changed, err := svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	fingerprint := kubernetesFingerprint("ds1", "node:node-1:Ready")
+	assert.LessOrEqual(t, len(fingerprint), 64)
+	alert := repoManager.alerts.alerts[fingerprint]
+	require.NotNil(t, alert)
+	assert.Equal(t, models.AlertSourceKubernetes, alert.Source)
+	assert.Equal(t, models.AlertSeverityCritical, alert.Severity)
+	assert.Equal(t, "prod", alert.Labels["cluster"])
+	assert.Equal(t, "node-1", alert.Labels["node"])
+	assert.Equal(t, "Node/node-1: container runtime is down", alert.Description)
+
+	// 异常未变化时不重复写入
+	changed, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	assert.Equal(t, 1, alertService.created)
+	assert.Zero(t, alertService.updated)
+
+	// 节点恢复后解决告警
+	source.nodes = nil
+	changed, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, models.AlertStatusResolved, alert.Status)
+	assert.NotNil(t, alert.ResolvedAt)
+
+	// 再次异常时重新打开同一告警
+	source.nodes = []kubernetes.Node{notReadyNode("node-1")}
+	result, err := svc.SyncDataSource(ctx, "ds1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Fired)
+	assert.Equal(t, 1, alertService.created)
+	assert.Equal(t, models.AlertStatusFiring, alert.Status)
+	assert.Nil(t, alert.ResolvedAt)
+
+	_, err = svc.SyncDataSource(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrDataSourceNotFound)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// labelAutocompleteCachePrefix 标签自动补全缓存键前缀
+const labelAutocompleteCachePrefix = "labels:autocomplete:"
+
+// labelAutocompleteService 告警标签自动补全服务实现，统计结果按查询缓存，
+// 过滤界面和匹配条件编辑器逐字输入时的请求大多命中缓存
+type labelAutocompleteService struct {
+	repoManager repository.RepositoryManager
+	cache       cache.Cache
+	cfg         config.LabelAutocompleteConfig
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewLabelAutocompleteService 创建告警标签自动补全服务实例，suggestionCache 为 nil 时不缓存
+func NewLabelAutocompleteService(repoManager repository.RepositoryManager, suggestionCache cache.Cache, cfg config.LabelAutocompleteConfig, logger *zap.Logger) LabelAutocompleteService {
+	return &labelAutocompleteService{
+		repoManager: repoManager,
+		cache:       suggestionCache,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Keys 获取最近的告警中出现的标签键
+func (s *labelAutocompleteService) Keys(ctx context.Context, query *models.LabelSuggestionQuery) (*models.LabelSuggestions, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("%skeys:%d:%s", labelAutocompleteCachePrefix, query.Limit, url.QueryEscape(strings.ToLower(query.Prefix)))
+	return s.suggest(ctx, cacheKey, "", func(since time.Time) ([]*models.LabelSuggestion, error) {
+		return s.repoManager.Alert().ListLabelKeys(ctx, since, query)
+	})
+}
+
+// Values 获取最近的告警中标签键 key 的值
+func (s *labelAutocompleteService) Values(ctx context.Context, key string, query *models.LabelSuggestionQuery) (*models.LabelSuggestions, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("%w: 标签键不能为空", models.ErrInvalidInput)
+	}
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("%svalues:%s:%d:%s", labelAutocompleteCachePrefix, url.QueryEscape(key), query.Limit,
+		url.QueryEscape(strings.ToLower(query.Prefix)))
+	return s.suggest(ctx, cacheKey, key, func(since time.Time) ([]*models.LabelSuggestion, error) {
+		return s.repoManager.Alert().ListLabelValues(ctx, key, since, query)
+	})
+}
+
+// suggest 读取缓存的统计结果，未命中时按统计窗口查询并写入缓存，缓存读写失败时直接查询
+func (s *labelAutocompleteService) suggest(ctx context.Context, cacheKey, key string, list func(since time.Time) ([]*models.LabelSuggestion, error)) (*models.LabelSuggestions, error) {
+	if cached := s.cached(ctx, cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	items, err := list(s.now().Add(-s.cfg.Window))
+	if err != nil {
+		return nil, err
+	}
+	result := &models.LabelSuggestions{Key: key, Window: s.cfg.Window.String(), Items: items}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, result, s.cfg.CacheTTL); err != nil {
+			s.logger.Warn("写入标签自动补全缓存失败", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+// cached 读取缓存的统计结果，未命中或解析失败时返回 nil
+func (s *labelAutocompleteService) cached(ctx context.Context, cacheKey string) *models.LabelSuggestions {
+	if s.cache == nil {
+		return nil
+	}
+
+	cached, err := s.cache.Get(ctx, cacheKey)
+	if err != nil {
+		s.logger.Warn("读取标签自动补全缓存失败", zap.Error(err))
+		return nil
+	}
+	if cached == "" {
+		return nil
+	}
+
+	var result models.LabelSuggestions
+	if err := json.Unmarshal([]byte(cached), &result); err != nil {
+		return nil
+	}
+	return &result
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+type labelAutocompleteAlertRepository struct {
+	repository.AlertRepository
+	calls int
+	since time.Time
+	query models.LabelSuggestionQuery
+}
+
+func (r *labelAutocompleteAlertRepository) ListLabelKeys(ctx context.Context, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error) {
+	r.calls++
+	r.since, r.query = since, *query
+	return []*models.LabelSuggestion{{Value: "service", Count: 40}, {Value: "severity", Count: 38}}, nil
+}
+
+func (r *labelAutocompleteAlertRepository) ListLabelValues(ctx context.Context, key string, since time.Time, query *models.LabelSuggestionQuery) ([]*models.LabelSuggestion, error) {
+	r.calls++
+	r.since, r.query = since, *query
+	return []*models.LabelSuggestion{{Value: "payment-api", Count: 12}}, nil
+}
+
+type labelAutocompleteRepoManager struct {
+	*MockRepositoryManager
+	alerts *labelAutocompleteAlertRepository
+}
+
+func (m *labelAutocompleteRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func TestLabelAutocompleteService(t *testing.T) {
+	alerts := &labelAutocompleteAlertRepository{}
+	cfg := config.LabelAutocompleteConfig{Window: 24 * time.Hour, CacheTTL: time.Minute}
+	svc := NewLabelAutocompleteService(&labelAutocompleteRepoManager{alerts: alerts}, cache.NewMemoryCache(), cfg, zap.NewNop()).(*labelAutocompleteService)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	keys, err := svc.Keys(ctx, &models.LabelSuggestionQuery{Prefix: " se ", Limit: 1000})
+	require.NoError(t, err)
+	require.Len(t, keys.Items, 2)
+	assert.Equal(t, "24h0m0s", keys.Window)
+	assert.Equal(t, now.Add(-24*time.Hour), alerts.since)
+	assert.Equal(t, models.LabelSuggestionQuery{Prefix: "se", Limit: models.LabelSuggestionMaxLimit}, alerts.query)
+
+	// 相同查询命中缓存，前缀不区分大小写
+	_, err = svc.Keys(ctx, &models.LabelSuggestionQuery{Prefix: "SE", Limit: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, 1, alerts.calls)
+
+	values, err := svc.Values(ctx, "service", &models.LabelSuggestionQuery{Prefix: "pay"})
+	require.NoError(t, err)
+	assert.Equal(t, "service", values.Key)
+	assert.Equal(t, "payment-api", values.Items[0].Value)
+	assert.Equal(t, models.LabelSuggestionDefaultLimit, alerts.query.Limit)
+	assert.Equal(t, 2, alerts.calls)
+
+	// 不同标签键的值分别缓存
+	_, err = svc.Values(ctx, "team", &models.LabelSuggestionQuery{Prefix: "pay"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, alerts.calls)
+
+	_, err = svc.Values(ctx, " ", &models.LabelSuggestionQuery{})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
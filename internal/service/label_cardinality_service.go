@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// 标签基数跟踪参数
+const (
+	// labelCardinalityMaxKeys 每个统计窗口内最多跟踪的标签键数量，限制标签键本身爆炸时的内存占用
+	labelCardinalityMaxKeys = 10000
+	// labelCardinalitySamples 每个标签键保留的示例值数量
+	labelCardinalitySamples = 5
+	// labelCardinalityDefaultTop 基数报告默认返回的标签键数量
+	labelCardinalityDefaultTop = 20
+	// labelCardinalityMaxTop 基数报告最多返回的标签键数量
+	labelCardinalityMaxTop = 200
+	// labelNormalizedMaxLength 规范化后标签值的最大长度
+	labelNormalizedMaxLength = 128
+)
+
+// 规范化标签值时替换的模式，按 UUID、IP、十六进制串、数字的顺序替换
+var (
+	labelUUIDPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	labelIPPattern     = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`)
+	labelHexPattern    = regexp.MustCompile(`\b[0-9a-fA-F]{8,}\b`)
+	labelNumberPattern = regexp.MustCompile(`\d+`)
+)
+
+// labelGuardChanges 标签处理方式在 label_guard 注解中的写法
+var labelGuardChanges = map[models.LabelGuardAction]string{
+	models.LabelGuardActionDrop:      "dropped",
+	models.LabelGuardActionNormalize: "normalized",
+}
+
+// normalizeLabelValue 将标签值中的 UUID、IP、十六进制串和数字替换为占位符，并截断过长的值，
+// 如 /api/users/123/orders 规范化为 /api/users/{n}/orders
+func normalizeLabelValue(value string) string {
+	value = labelUUIDPattern.ReplaceAllString(value, "{uuid}")
+	value = labelIPPattern.ReplaceAllString(value, "{ip}")
+	value = labelHexPattern.ReplaceAllStringFunc(value, func(match string) string {
+		// 全为数字的串由数字规则处理
+		if strings.Trim(match, "0123456789") == "" {
+			return match
+		}
+		return "{hex}"
+	})
+	value = labelNumberPattern.ReplaceAllString(value, "{n}")
+	if utf8.RuneCountInString(value) > labelNormalizedMaxLength {
+		value = string([]rune(value)[:labelNormalizedMaxLength])
+	}
+	return value
+}
+
+// labelKeyStats 一个标签键在当前窗口内的统计，超过上限后不再保存值的哈希
+type labelKeyStats struct {
+	values   map[uint64]struct{}
+	exceeded bool
+	alerts   int64
+	guarded  int64
+	action   models.LabelGuardAction
+	samples  []string
+}
+
+// labelCardinalityService 告警标签基数服务实现。在本实例内按窗口统计入站告警各标签键的不同值数量，
+// 基数超过上限的标签键在窗口剩余时间内按配置丢弃或规范化，窗口结束后重新统计
+type labelCardinalityService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.LabelCardinalityConfig
+	logger      *zap.Logger
+	now         func() time.Time
+
+	drop      map[string]bool
+	normalize map[string]bool
+	protected map[string]bool
+
+	mu          sync.Mutex
+	windowStart time.Time
+	keys        map[string]*labelKeyStats
+	untracked   int64
+}
+
+// NewLabelCardinalityService 创建告警标签基数服务实例，service、team、region 和 tags 标签始终不处理
+func NewLabelCardinalityService(repoManager repository.RepositoryManager, cfg config.LabelCardinalityConfig, logger *zap.Logger) LabelCardinalityService {
+	protected := labelKeySet(cfg.Protected)
+	for _, key := range []string{models.ServiceLabel, models.TeamLabel, models.RegionLabel, models.AlertTagsLabel} {
+		protected[key] = true
+	}
+
+	return &labelCardinalityService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+		drop:        labelKeySet(cfg.Drop),
+		normalize:   labelKeySet(cfg.Normalize),
+		protected:   protected,
+		keys:        make(map[string]*labelKeyStats),
+	}
+}
+
+// labelKeySet 将配置中的标签键列表转换为集合
+func labelKeySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+// Guard 统计告警标签的基数，并按策略丢弃或规范化标签，处理过的标签记录在 label_guard 注解中
+func (s *labelCardinalityService) Guard(alert *models.Alert) {
+	if len(alert.Labels) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(alert.Labels))
+	for key := range alert.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	s.mu.Lock()
+	s.rotate()
+	var changes []string
+	for _, key := range keys {
+		value := alert.Labels[key]
+		if s.protected[key] {
+			s.observe(key, value)
+			continue
+		}
+
+		action := models.LabelGuardAction("")
+		switch {
+		case s.drop[key]:
+			action = models.LabelGuardActionDrop
+			s.observe(key, value)
+		case s.normalize[key]:
+			// 始终规范化的标签键按规范化后的值统计基数
+			action = models.LabelGuardActionNormalize
+			s.observe(key, normalizeLabelValue(value))
+		default:
+			if stats := s.observe(key, value); stats != nil && stats.exceeded {
+				action = models.LabelGuardAction(s.cfg.Action)
+			}
+		}
+
+		switch action {
+		case models.LabelGuardActionDrop:
+			delete(alert.Labels, key)
+		case models.LabelGuardActionNormalize:
+			normalized := normalizeLabelValue(value)
+			if normalized == alert.Labels[key] {
+				continue
+			}
+			alert.Labels[key] = normalized
+		default:
+			continue
+		}
+		if stats := s.keys[key]; stats != nil {
+			stats.guarded++
+			stats.action = action
+		}
+		changes = append(changes, key+":"+labelGuardChanges[action])
+	}
+	s.mu.Unlock()
+
+	if len(changes) == 0 {
+		return
+	}
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[models.LabelGuardAnnotation] = strings.Join(changes, ",")
+}
+
+// rotate 窗口结束后清空统计，调用方需持有锁
+func (s *labelCardinalityService) rotate() {
+	now := s.now()
+	if !s.windowStart.IsZero() && now.Sub(s.windowStart) < s.cfg.Window {
+		return
+	}
+
+	for key, stats := range s.keys {
+		if stats.exceeded {
+			s.logger.Info("标签基数统计窗口结束",
+				zap.String("label", key),
+				zap.Int64("alerts", stats.alerts),
+				zap.Int64("guarded", stats.guarded))
+		}
+	}
+	s.windowStart = now
+	s.keys = make(map[string]*labelKeyStats)
+	s.untracked = 0
+}
+
+// observe 记录标签值，返回标签键的统计，跟踪的标签键数量达到上限时返回 nil。调用方需持有锁
+func (s *labelCardinalityService) observe(key, value string) *labelKeyStats {
+	stats, ok := s.keys[key]
+	if !ok {
+		if len(s.keys) >= labelCardinalityMaxKeys {
+			s.untracked++
+			return nil
+		}
+		stats = &labelKeyStats{values: make(map[uint64]struct{})}
+		s.keys[key] = stats
+	}
+	stats.alerts++
+	if stats.exceeded {
+		return stats
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(value))
+	sum := hash.Sum64()
+	if _, seen := stats.values[sum]; seen {
+		return stats
+	}
+	if len(stats.samples) < labelCardinalitySamples {
+		stats.samples = append(stats.samples, value)
+	}
+	stats.values[sum] = struct{}{}
+
+	if len(stats.values) > s.cfg.Limit {
+		stats.exceeded = true
+		stats.values = nil
+		s.logger.Warn("告警标签基数超过上限",
+			zap.String("label", key),
+			zap.Int("limit", s.cfg.Limit),
+			zap.String("action", s.cfg.Action))
+	}
+	return stats
+}
+
+// Report 获取标签基数报告，top 为每部分返回的标签键数量
+func (s *labelCardinalityService) Report(ctx context.Context, top int) (*models.LabelCardinalityReport, error) {
+	if top <= 0 {
+		top = labelCardinalityDefaultTop
+	}
+	if top > labelCardinalityMaxTop {
+		top = labelCardinalityMaxTop
+	}
+
+	s.mu.Lock()
+	s.rotate()
+	report := &models.LabelCardinalityReport{
+		WindowStart:   s.windowStart,
+		Window:        s.cfg.Window.String(),
+		Limit:         s.cfg.Limit,
+		Action:        models.LabelGuardAction(s.cfg.Action),
+		Keys:          make([]*models.LabelCardinalityKey, 0, len(s.keys)),
+		UntrackedKeys: s.untracked,
+	}
+	for key, stats := range s.keys {
+		distinct := len(stats.values)
+		if stats.exceeded {
+			distinct = s.cfg.Limit + 1
+		}
+		report.Keys = append(report.Keys, &models.LabelCardinalityKey{
+			Key:            key,
+			DistinctValues: distinct,
+			Exceeded:       stats.exceeded,
+			Alerts:         stats.alerts,
+			Action:         stats.action,
+			Guarded:        stats.guarded,
+			Samples:        append([]string(nil), stats.samples...),
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(report.Keys, func(i, j int) bool {
+		a, b := report.Keys[i], report.Keys[j]
+		if a.DistinctValues != b.DistinctValues {
+			return a.DistinctValues > b.DistinctValues
+		}
+		if a.Alerts != b.Alerts {
+			return a.Alerts > b.Alerts
+		}
+		return a.Key < b.Key
+	})
+	if len(report.Keys) > top {
+		report.Keys = report.Keys[:top]
+	}
+
+	// 已存储告警统计最近一个窗口时长内创建的告警，不受本实例窗口起点影响
+	stored, err := s.repoManager.Alert().GetLabelCardinality(ctx, s.now().Add(-s.cfg.Window), top)
+	if err != nil {
+		return nil, err
+	}
+	report.Stored = stored
+	return report, nil
+}
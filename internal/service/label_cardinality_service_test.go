@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+type labelCardinalityAlertRepository struct {
+	repository.AlertRepository
+	since time.Time
+}
+
+func (r *labelCardinalityAlertRepository) GetLabelCardinality(ctx context.Context, since time.Time, limit int) ([]*models.LabelCardinalityStat, error) {
+	r.since = since
+	return []*models.LabelCardinalityStat{{Key: "request_id", DistinctValues: 9000, Alerts: 9000}}, nil
+}
+
+type labelCardinalityRepoManager struct {
+	*MockRepositoryManager
+	alerts *labelCardinalityAlertRepository
+}
+
+func (m *labelCardinalityRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func newLabelCardinalityTestService(cfg config.LabelCardinalityConfig, now *time.Time) (*labelCardinalityService, *labelCardinalityAlertRepository) {
+	alerts := &labelCardinalityAlertRepository{}
+	svc := NewLabelCardinalityService(&labelCardinalityRepoManager{alerts: alerts}, cfg, zap.NewNop()).(*labelCardinalityService)
+	svc.now = func() time.Time { return *now }
+	return svc, alerts
+}
+
+func TestNormalizeLabelValue(t *testing.T) {
+	assert.Equal(t, "/api/users/{n}/orders", normalizeLabelValue("/api/users/123/orders"))
+	assert.Equal(t, "req-{uuid}", normalizeLabelValue("req-6f1c2a9e-3b4d-4c5e-8f90-a1b2c3d4e5f6"))
+	assert.Equal(t, "{ip}:{n}", normalizeLabelValue("10.0.12.7:8080"))
+	assert.Equal(t, "trace {hex}", normalizeLabelValue("trace 4bf92f3577b34da6"))
+	assert.Equal(t, "payments", normalizeLabelValue("payments"))
+}
+
+func TestLabelCardinalityService_Guard(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, alerts := newLabelCardinalityTestService(config.LabelCardinalityConfig{
+		Limit:     3,
+		Window:    time.Hour,
+		Action:    string(models.LabelGuardActionDrop),
+		Drop:      []string{"trace_id"},
+		Normalize: []string{" path "},
+	}, &now)
+
+	// 未超过上限的标签不处理，始终丢弃和始终规范化的标签立即处理
+	alert := &models.Alert{Labels: map[string]string{
+		"request_id": "r0", "trace_id": "abc", "path": "/orders/42", "team": "db",
+	}}
+	svc.Guard(alert)
+	assert.Equal(t, map[string]string{"request_id": "r0", "path": "/orders/{n}", "team": "db"}, alert.Labels)
+	assert.Equal(t, "path:normalized,trace_id:dropped", alert.Annotations[models.LabelGuardAnnotation])
+
+	// 第 4 个不同值超过上限，从该告警起丢弃该标签；team 标签始终不处理
+	for i := 1; i <= 4; i++ {
+		alert = &models.Alert{Labels: map[string]string{"request_id": fmt.Sprintf("r%d", i), "team": fmt.Sprintf("t%d", i)}}
+		svc.Guard(alert)
+	}
+	assert.Equal(t, map[string]string{"team": "t4"}, alert.Labels)
+	assert.Equal(t, "request_id:dropped", alert.Annotations[models.LabelGuardAnnotation])
+
+	report, err := svc.Report(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, report.Keys, 2)
+	assert.Equal(t, &models.LabelCardinalityKey{
+		Key: "request_id", DistinctValues: 4, Exceeded: true, Alerts: 5,
+		Action: models.LabelGuardActionDrop, Guarded: 2, Samples: []string{"r0", "r1", "r2", "r3"},
+	}, report.Keys[0])
+	assert.Equal(t, "team", report.Keys[1].Key)
+	assert.Equal(t, now.Add(-time.Hour), alerts.since)
+	assert.Len(t, report.Stored, 1)
+
+	// 窗口结束后重新统计
+	now = now.Add(time.Hour)
+	alert = &models.Alert{Labels: map[string]string{"request_id": "r9"}}
+	svc.Guard(alert)
+	assert.Equal(t, "r9", alert.Labels["request_id"])
+	assert.Empty(t, alert.Annotations)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/i18n"
+	"pulse/internal/repository"
+)
+
+// localizationService 多语言服务实现
+type localizationService struct {
+	repoManager repository.RepositoryManager
+	translator  *i18n.Bundle
+	logger      *zap.Logger
+}
+
+// NewLocalizationService 创建多语言服务实例
+func NewLocalizationService(repoManager repository.RepositoryManager, translator *i18n.Bundle, logger *zap.Logger) LocalizationService {
+	return &localizationService{
+		repoManager: repoManager,
+		translator:  translator,
+		logger:      logger,
+	}
+}
+
+// Locales 已加载消息目录的语言
+func (s *localizationService) Locales() []apierror.Locale {
+	return s.translator.Locales()
+}
+
+// Enums 获取枚举显示名，使用用户的语言偏好，未设置时使用 fallback
+func (s *localizationService) Enums(ctx context.Context, userID string, fallback apierror.Locale) (apierror.Locale, map[string]map[string]string) {
+	locale, ok := resolveLocale(ctx, s.repoManager.LanguagePreference(), s.translator, userID, "")
+	if !ok {
+		locale = fallback
+	}
+	return locale, s.translator.Enums(locale)
+}
+
+// GetLanguage 获取用户或团队的语言偏好
+func (s *localizationService) GetLanguage(ctx context.Context, scope models.LanguageScope, subject string) (*models.LanguagePreference, error) {
+	return s.repoManager.LanguagePreference().Get(ctx, scope, subject)
+}
+
+// SetLanguage 设置用户或团队的语言偏好，语言必须是已加载消息目录的语言，保存时规范为目录的语言标签
+func (s *localizationService) SetLanguage(ctx context.Context, scope models.LanguageScope, subject string, req *models.LanguagePreferenceRequest) (*models.LanguagePreference, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: 团队不能为空", models.ErrInvalidInput)
+	}
+
+	locale, ok := s.translator.Match(req.Language)
+	if !ok {
+		return nil, fmt.Errorf("%w: 不支持的语言 %q，可选 %v", models.ErrInvalidInput, req.Language, s.translator.Locales())
+	}
+
+	pref := &models.LanguagePreference{Scope: scope, Subject: subject, Language: string(locale)}
+	if err := s.repoManager.LanguagePreference().Set(ctx, pref); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("设置语言偏好", zap.String("scope", string(scope)), zap.String("subject", subject),
+		zap.String("language", pref.Language))
+	return pref, nil
+}
+
+// DeleteLanguage 删除用户或团队的语言偏好，之后使用默认语言
+func (s *localizationService) DeleteLanguage(ctx context.Context, scope models.LanguageScope, subject string) error {
+	return s.repoManager.LanguagePreference().Delete(ctx, scope, subject)
+}
+
+// resolveLocale 依次使用用户和团队的语言偏好，都未设置或语言不受支持时返回默认语言和 false
+func resolveLocale(ctx context.Context, prefs repository.LanguagePreferenceRepository, translator *i18n.Bundle, userID, team string) (apierror.Locale, bool) {
+	candidates := []struct {
+		scope   models.LanguageScope
+		subject string
+	}{
+		{models.LanguageScopeUser, userID},
+		{models.LanguageScopeTeam, team},
+	}
+	for _, c := range candidates {
+		if c.subject == "" {
+			continue
+		}
+		pref, err := prefs.Get(ctx, c.scope, c.subject)
+		if err != nil {
+			continue
+		}
+		if locale, ok := translator.Match(pref.Language); ok {
+			return locale, true
+		}
+	}
+	return apierror.DefaultLocale, false
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/apierror"
+	"pulse/internal/pkg/i18n"
+)
+
+// fakeLanguagePreferenceRepository 内存中的语言偏好
+type fakeLanguagePreferenceRepository struct {
+	prefs map[string]*models.LanguagePreference
+}
+
+func newFakeLanguagePreferenceRepository() *fakeLanguagePreferenceRepository {
+	return &fakeLanguagePreferenceRepository{prefs: map[string]*models.LanguagePreference{}}
+}
+
+func (r *fakeLanguagePreferenceRepository) Get(ctx context.Context, scope models.LanguageScope, subject string) (*models.LanguagePreference, error) {
+	pref, ok := r.prefs[string(scope)+"/"+subject]
+	if !ok {
+		return nil, models.ErrLanguagePreferenceNotFound
+	}
+	return pref, nil
+}
+
+func (r *fakeLanguagePreferenceRepository) Set(ctx context.Context, pref *models.LanguagePreference) error {
+	r.prefs[string(pref.Scope)+"/"+pref.Subject] = pref
+	return nil
+}
+
+func (r *fakeLanguagePreferenceRepository) Delete(ctx context.Context, scope models.LanguageScope, subject string) error {
+	delete(r.prefs, string(scope)+"/"+subject)
+	return nil
+}
+
+func testTranslator(t *testing.T) *i18n.Bundle {
+	translator, err := i18n.NewBundle(i18n.BundledLoader())
+	require.NoError(t, err)
+	return translator
+}
+
+func newLocalizationTestRepoManager() *notificationCalendarRepoManager {
+	return &notificationCalendarRepoManager{
+		alertSnoozeRepoManager: newAlertSnoozeTestRepoManager(),
+		routing:                &fakeNotificationRoutingRepository{},
+		notifications:          &fakeSentNotificationRepository{},
+		languages:              newFakeLanguagePreferenceRepository(),
+	}
+}
+
+func TestLocalizationService_SetLanguage(t *testing.T) {
+	repoManager := newLocalizationTestRepoManager()
+	svc := NewLocalizationService(repoManager, testTranslator(t), zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.SetLanguage(ctx, models.LanguageScopeUser, "u1", &models.LanguagePreferenceRequest{Language: "fr-FR"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 语言按主语言标签匹配并规范为消息目录的语言
+	pref, err := svc.SetLanguage(ctx, models.LanguageScopeUser, "u1", &models.LanguagePreferenceRequest{Language: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "en-US", pref.Language)
+
+	locale, enums := svc.Enums(ctx, "u1", apierror.LocaleZhCN)
+	assert.Equal(t, apierror.LocaleEnUS, locale)
+	assert.Equal(t, "Acknowledged", enums["alert_status"]["acked"])
+
+	// 未设置语言的用户使用请求的语言
+	locale, enums = svc.Enums(ctx, "u2", apierror.LocaleZhCN)
+	assert.Equal(t, apierror.LocaleZhCN, locale)
+	assert.Equal(t, "严重", enums["alert_severity"]["critical"])
+}
+
+func TestNotificationService_LocalizesForRecipient(t *testing.T) {
+	repoManager := newLocalizationTestRepoManager()
+	alertID := uuid.New()
+	repoManager.alerts.alerts[alertID.String()] = &models.Alert{ID: alertID.String(),
+		Labels: map[string]string{models.TeamLabel: "payments"}}
+	repoManager.languages.prefs["team/payments"] = &models.LanguagePreference{Language: "en-US"}
+	repoManager.languages.prefs["user/u1"] = &models.LanguagePreference{Language: "zh-CN"}
+
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	// 用户的语言优先于告警所属团队的语言
+	for _, recipient := range []string{"alice", "bob"} {
+		require.NoError(t, svc.Send(ctx, &models.Notification{
+			AlertID:     alertID,
+			Type:        models.NotificationTypeDingTalk,
+			Recipient:   recipient,
+			MessageKey:  "notification.digest",
+			MessageData: map[string]interface{}{"Count": 1, "Items": []map[string]string{{"Time": "2024-01-01 08:00", "Title": "磁盘空间不足"}}},
+		}))
+	}
+
+	require.Len(t, repoManager.notifications.sent, 2)
+	assert.Equal(t, "非工作时间告警汇总（1 条）", repoManager.notifications.sent[0].Subject)
+	assert.Equal(t, "After-hours alert digest (1)", repoManager.notifications.sent[1].Subject)
+	assert.Contains(t, repoManager.notifications.sent[1].Content, "[2024-01-01 08:00] 磁盘空间不足")
+}
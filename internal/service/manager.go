@@ -1,15 +1,29 @@
 package service
 
 import (
+
 	"go.uber.org/zap"
 
+	"pulse/internal/cache"
 	"pulse/internal/config"
+	"pulse/internal/itsm"
+	"pulse/internal/kubernetes"
+	"pulse/internal/models"
+	"pulse/internal/objectstore"
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/i18n"
+	"pulse/internal/prometheus"
+	"pulse/internal/push"
+	"pulse/internal/remediation"
 	"pulse/internal/repository"
+	"pulse/internal/scanner"
 )
 
 // ServiceManager 服务管理器接口
 type ServiceManager interface {
 	Alert() AlertService
+	AlertTimeline() AlertTimelineService
 	Rule() RuleService
 	DataSource() DataSourceService
 	Ticket() TicketService
@@ -19,6 +33,63 @@ type ServiceManager interface {
 	Notification() NotificationService
 	Webhook() WebhookService
 	Config() ConfigService
+	CustomField() CustomFieldService
+	AttachmentScan() AttachmentScanService
+	KnowledgeReview() KnowledgeReviewService
+	KnowledgeComment() KnowledgeCommentService
+	KnowledgeSchedule() KnowledgeScheduleService
+	KnowledgeTransfer() KnowledgeTransferService
+	KnowledgeACL() KnowledgeACLService
+	WebhookIntegration() WebhookIntegrationService
+	KeyRotation() KeyRotationService
+	FeatureFlag() FeatureFlagService
+	Dashboard() DashboardService
+	Postmortem() PostmortemService
+	Remediation() RemediationService
+	ChatOps() ChatOpsService
+	ITSM() ITSMService
+	Heartbeat() HeartbeatService
+	Kubernetes() KubernetesService
+	ServiceCatalog() ServiceCatalogService
+	SLO() SLOService
+	AlertAutoResolve() AlertAutoResolveService
+	AlertSnooze() AlertSnoozeService
+	NotificationCalendar() NotificationCalendarService
+	Localization() LocalizationService
+	TagGovernance() TagGovernanceService
+	TicketImport() TicketImportService
+	AlertStats() AlertStatsService
+	LabelCardinality() LabelCardinalityService
+	LabelAutocomplete() LabelAutocompleteService
+	TicketSurvey() TicketSurveyService
+	KnowledgeFeedback() KnowledgeFeedbackService
+	Approval() ApprovalService
+	IngestRejection() IngestRejectionService
+	AsyncTask() AsyncTaskService
+	QueryCache() QueryCacheService
+	NotificationChannel() NotificationChannelService
+	AlertPartition() AlertPartitionService
+	DataRetention() DataRetentionService
+	AuditLog() AuditLogService
+	Impersonation() ImpersonationService
+	Metric() MetricService
+	TicketSLA() TicketSLAService
+	AlertSeverityRule() AlertSeverityRuleService
+	StatusPage() StatusPageService
+	ServiceHealth() ServiceHealthService
+	RuleForecast() RuleForecastService
+	ConfigApply() ConfigApplyService
+	Backup() BackupService
+	Assignment() AssignmentService
+	Handover() HandoverService
+	AlertActionLink() AlertActionLinkService
+	Push() PushService
+	Inbox() InboxService
+	QuietHours() QuietHoursService
+	ChangeEvent() ChangeEventService
+	AlertReplay() AlertReplayService
+	WebhookSubscription() WebhookSubscriptionService
+	GraphQL() GraphQLService
 }
 
 // serviceManager 服务管理器实现
@@ -28,6 +99,7 @@ type serviceManager struct {
 
 	// 服务实例
 	alertService        AlertService
+	alertTimeline       AlertTimelineService
 	ruleService         RuleService
 	dataSourceService   DataSourceService
 	ticketService       TicketService
@@ -37,22 +109,173 @@ type serviceManager struct {
 	notificationService NotificationService
 	webhookService      WebhookService
 	configService       ConfigService
+	customFieldService  CustomFieldService
+	attachmentScan      AttachmentScanService
+	knowledgeReview     KnowledgeReviewService
+	knowledgeComment    KnowledgeCommentService
+	knowledgeSchedule   KnowledgeScheduleService
+	knowledgeTransfer   KnowledgeTransferService
+	knowledgeACL        KnowledgeACLService
+	webhookIntegration  WebhookIntegrationService
+	keyRotation         KeyRotationService
+	featureFlag         FeatureFlagService
+	dashboard           DashboardService
+	postmortem          PostmortemService
+	remediation         RemediationService
+	chatOps             ChatOpsService
+	itsm                ITSMService
+	heartbeat           HeartbeatService
+	kubernetes          KubernetesService
+	serviceCatalog      ServiceCatalogService
+	slo                 SLOService
+	alertAutoResolve    AlertAutoResolveService
+	alertSnooze         AlertSnoozeService
+	notificationCalendar NotificationCalendarService
+	localization        LocalizationService
+	tagGovernance       TagGovernanceService
+	alertPartition      AlertPartitionService
+	dataRetention       DataRetentionService
+	auditLog            AuditLogService
+	impersonation       ImpersonationService
+	metric              MetricService
+	ticketSLA           TicketSLAService
+	alertSeverityRule   AlertSeverityRuleService
+	statusPage          StatusPageService
+	serviceHealth       ServiceHealthService
+	ruleForecast        RuleForecastService
+	configApply         ConfigApplyService
+	backup              BackupService
+	assignment          AssignmentService
+	handover            HandoverService
+	alertActionLink     AlertActionLinkService
+	push                PushService
+	inbox               InboxService
+	quietHours          QuietHoursService
+	changeEvent         ChangeEventService
+	alertReplay         AlertReplayService
+	webhookSubscription WebhookSubscriptionService
+	graphql             GraphQLService
+	ticketImport        TicketImportService
+	alertStats          AlertStatsService
+	labelCardinality    LabelCardinalityService
+	labelAutocomplete   LabelAutocompleteService
+	ticketSurvey        TicketSurveyService
+	knowledgeFeedback   KnowledgeFeedbackService
+	approval            ApprovalService
+	ingestRejection     IngestRejectionService
+	asyncTask           AsyncTaskService
+	queryCache          QueryCacheService
+	notificationChannel NotificationChannelService
 }
 
-// NewServiceManager 创建新的服务管理器
+// NewServiceManager 创建新的服务管理器，功能开关和仪表盘使用进程内缓存
 func NewServiceManager(repoManager repository.RepositoryManager, logger *zap.Logger, cfg *config.Config) ServiceManager {
-	// 初始化服务
-	alertService := NewAlertService(repoManager.Alert(), repoManager.User(), logger)
+	httpClients, err := cfg.NewHTTPClientFactory()
+	if err != nil {
+		logger.Error("创建HTTP客户端工厂失败，使用默认超时和重试策略", zap.Error(err))
+	}
+	return NewServiceManagerWithCache(repoManager, cache.NewMemoryCache(), httpClients, logger, cfg)
+}
+
+// NewServiceManagerWithCache 创建新的服务管理器，功能开关、仪表盘数据和标签自动补全结果使用 sharedCache 在实例间共享，
+// 数据源查询和通知发送的 HTTP 客户端由 httpClients 创建
+func NewServiceManagerWithCache(repoManager repository.RepositoryManager, sharedCache cache.Cache, httpClients *httpclient.Factory, logger *zap.Logger, cfg *config.Config) ServiceManager {
+	// 多语言消息目录，额外消息目录加载失败时只使用内置消息目录
+	translator, err := i18n.NewBundle(i18n.BundledLoader(), i18n.DirLoader(cfg.I18n.CatalogDir))
+	if err != nil {
+		logger.Error("加载消息目录失败，使用内置消息目录", zap.Error(err), zap.String("dir", cfg.I18n.CatalogDir))
+		translator, _ = i18n.NewBundle(i18n.BundledLoader())
+	}
+	// 站内通知收件箱，告警和工单分派给用户、评论中 @ 提及用户时投递
+	inbox := NewInboxService(repoManager, translator, cfg.Inbox, logger)
+
+	// 初始化服务，告警和工单创建时按服务目录归属到服务，并按所属团队的分派策略分派处理人，
+	// 告警关联触发前同一服务上最近的变更
+	serviceCatalog := NewServiceCatalogService(repoManager, logger)
+	serviceHealth := NewServiceHealthService(repoManager, cfg.ServiceHealth, logger)
+	assignment := NewAssignmentService(repoManager, logger)
+	changeEvent := NewChangeEventService(repoManager, cfg.ChangeEvent, logger)
+	// 出站 Webhook 订阅，告警和工单的变化作为事件加入订阅的投递队列
+	webhookSubscription := NewWebhookSubscriptionService(repoManager, httpClients, cfg.WebhookSubscription, logger)
+	// 入站告警的高基数标签按策略丢弃或规范化
+	labelCardinality := NewLabelCardinalityService(repoManager, cfg.LabelCardinality, logger)
+	// 入站告警在分派和通知路由之前按重新分级规则调整级别
+	alertSeverityRule := NewAlertSeverityRuleService(repoManager, serviceCatalog, logger)
+	// 外部调用熔断器，每个数据源、Webhook 和分诊服务独立熔断
+	breakers := breaker.NewRegistry(breaker.Config{
+		FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+		OpenTimeout:      cfg.CircuitBreaker.OpenTimeout,
+		HalfOpenMaxCalls: cfg.CircuitBreaker.HalfOpenMaxCalls,
+		CallTimeout:      cfg.CircuitBreaker.CallTimeout,
+	})
+	// 入站告警在分派之前调用外部分诊服务评分，评分服务持续失败时熔断，告警照常创建
+	triageScorer := NewTriageScorer(cfg.AlertTriage, httpClients.Client(httpclient.Policy{Timeout: cfg.AlertTriage.Timeout, MaxAttempts: 1}), breakers.Get(alertTriageBreakerName))
+	alertService := NewAlertService(repoManager.Alert(), repoManager.User(), serviceCatalog, assignment, changeEvent, inbox, webhookSubscription, labelCardinality, alertSeverityRule, triageScorer, cfg.Alert.OccurrenceSampleInterval, logger)
 	ruleService := NewRuleService(repoManager, logger)
-	dataSourceService := NewDataSourceService(repoManager, logger)
-	ticketService := NewTicketService(repoManager, logger)
-	knowledgeService := NewKnowledgeService(repoManager, logger)
-	notificationService := NewNotificationService(repoManager, logger)
+	dataSourceService := NewDataSourceService(repoManager, breakers, logger)
+	// 工单解决时提示解决人评价引用的知识库文章
+	knowledgeFeedback := NewKnowledgeFeedbackService(repoManager, inbox, cfg.Knowledge, logger)
+	// 工单进入等待状态时暂停SLA计时
+	ticketSLA := NewTicketSLAService(repoManager, cfg.TicketSLA, logger)
+	ticketService := NewTicketService(repoManager, serviceCatalog, assignment, inbox, webhookSubscription, knowledgeFeedback, ticketSLA, logger)
+	knowledgeService := NewKnowledgeService(repoManager, logger, cfg.FileStorage)
+	// 声明式配置删除数据源和规则前检查是否需要审批
+	approval := NewApprovalService(repoManager, dataSourceService, ticketService, cfg.Approval, logger)
+
+	// 告警通知中的一键操作链接，使用单独配置的签名密钥
+	alertSnooze := NewAlertSnoozeService(repoManager, cfg.Alert, logger)
+	actionLinkCfg := cfg.Notification.ActionLink
+	alertActionLink := NewAlertActionLinkService(repoManager, alertService, alertSnooze, actionLinkCfg, logger)
+	// 移动端推送，凭据配置错误时不推送
+	var pushProviders push.Providers
+	if pushClient, err := httpClients.Channel(string(models.NotificationTypePush)); err != nil {
+		logger.Error("创建推送HTTP客户端失败", zap.Error(err))
+	} else if pushProviders, err = push.New(cfg.Notification.Push, pushClient); err != nil {
+		logger.Error("创建推送服务客户端失败", zap.Error(err))
+	}
+	pushService := NewPushService(repoManager, pushProviders, cfg.Notification.Push, logger)
+	notificationService := NewNotificationService(repoManager, translator, httpClients, alertActionLink, pushService, cfg.Notification, logger)
+	alertTimeline := NewAlertTimelineService(repoManager, inbox, logger)
+
+	knowledgeACL := NewKnowledgeACLService(repoManager, logger)
+	knowledgeTransfer := NewKnowledgeTransferService(repoManager, logger)
+
+	// 数据源查询结果缓存，评估间隔内相同数据源和表达式的查询共享结果
+	var queryResultCache cache.Cache
+	if cfg.QueryCache.Enabled {
+		queryResultCache = sharedCache
+	}
+	queryCache := NewQueryCacheService(queryResultCache, logger).(*queryCacheService)
+	sloQueriers := queryCache.wrapFactory(newSLOQuerierFactory(cfg.SLO, httpClients, breakers), cfg.SLO.EvaluationInterval)
+
+	// 附件扫描器，未启用或配置错误时不扫描；HTTP 扫描器使用全局代理和CA证书，
+	// 文件内容无法重放，客户端只发送一次，扫描出错由扫描服务按退避时间重试
+	var attachmentScanner scanner.Scanner
+	if cfg.FileStorage.Scan.Enabled {
+		s, err := scanner.New(cfg.FileStorage.Scan, httpClients.Client(httpclient.Policy{Timeout: cfg.FileStorage.Scan.Timeout, MaxAttempts: 1}))
+		if err != nil {
+			logger.Error("创建附件扫描器失败", zap.Error(err))
+		} else {
+			attachmentScanner = s
+		}
+	}
+
+	// 数据归档的对象存储，未开启归档或配置错误时不归档，开启了归档的资源也不清理
+	var archiveStore objectstore.Store
+	if cfg.Retention.ArchiveEnabled {
+		s, err := objectstore.New(cfg.FileStorage)
+		if err != nil {
+			logger.Error("创建归档对象存储失败", zap.Error(err))
+		} else {
+			archiveStore = s
+		}
+	}
 
 	return &serviceManager{
 		repoManager: repoManager,
 		logger:      logger,
 		alertService:        alertService,
+		alertTimeline:       alertTimeline,
 		ruleService:         ruleService,
 		dataSourceService:   dataSourceService,
 		ticketService:       ticketService,
@@ -60,8 +283,65 @@ func NewServiceManager(repoManager repository.RepositoryManager, logger *zap.Log
 		userService:         NewUserService(repoManager.User()),
 		authService:         NewAuthService(repoManager.User(), repoManager.Auth(), cfg.JWT.Secret),
 		notificationService: notificationService,
-		webhookService:      NewWebhookService(repoManager, logger),
+		webhookService:      NewWebhookService(repoManager, breakers, httpClients, logger),
 		configService:       NewConfigService(repoManager, logger),
+		customFieldService:  NewCustomFieldService(repoManager, logger),
+		attachmentScan:      NewAttachmentScanService(repoManager, attachmentScanner, cfg.FileStorage.Scan, logger),
+		knowledgeReview:     NewKnowledgeReviewService(repoManager, logger),
+		knowledgeComment:    NewKnowledgeCommentService(repoManager, notificationService, inbox, logger),
+		knowledgeSchedule:   NewKnowledgeScheduleService(repoManager, notificationService, cfg.Knowledge, logger),
+		knowledgeTransfer:   knowledgeTransfer,
+		knowledgeACL:        knowledgeACL,
+		webhookIntegration:  NewWebhookIntegrationService(repoManager, alertService, models.IngestSchemaMode(cfg.IngestSchema.Mode), logger),
+		keyRotation:         NewKeyRotationService(repoManager, keyRotationBatchSize, logger),
+		featureFlag:         NewFeatureFlagService(repoManager, sharedCache, cfg.App.FeatureFlags, logger),
+		dashboard:           NewDashboardService(repoManager, sharedCache, logger),
+		postmortem:          NewPostmortemService(repoManager, ticketService, knowledgeService, alertTimeline, logger),
+		remediation:         NewRemediationService(repoManager, remediation.New(cfg.Remediation), cfg.Remediation, logger),
+		chatOps:             NewChatOpsService(repoManager, alertService, ticketService, cfg.ChatOps, logger),
+		itsm:                NewITSMService(repoManager, newITSMClientFactory(cfg.ITSM, httpClients), cfg.ITSM, logger),
+		heartbeat:           NewHeartbeatService(repoManager, alertService, cfg.Heartbeat, logger),
+		kubernetes:          NewKubernetesService(repoManager, alertService, newKubernetesClientFactory(cfg.Kubernetes), cfg.Kubernetes, logger),
+		ruleForecast:        NewRuleForecastService(repoManager, alertService, newForecastQuerierFactory(cfg.RuleForecast, httpClients, breakers), cfg.RuleForecast, logger),
+		serviceCatalog:      serviceCatalog,
+		slo:                 NewSLOService(repoManager, sloQueriers, cfg.SLO, logger),
+		alertAutoResolve:    NewAlertAutoResolveService(repoManager, alertService, cfg.AutoResolve, logger),
+		alertSnooze:         alertSnooze,
+		notificationCalendar: NewNotificationCalendarService(repoManager, notificationService, cfg.Notification, logger),
+		localization:        NewLocalizationService(repoManager, translator, logger),
+		tagGovernance:       NewTagGovernanceService(repoManager, cfg.TagGovernance, logger),
+		alertPartition:      NewAlertPartitionService(repoManager, cfg.Alert, cfg.Retention, logger),
+		dataRetention:       NewDataRetentionService(repoManager, archiveStore, cfg.Alert, cfg.Retention, logger),
+		auditLog:            NewAuditLogService(repoManager),
+		impersonation:       NewImpersonationService(repoManager, logger),
+		metric:              NewMetricService(repoManager, httpClients, cfg.RemoteWrite),
+		ticketSLA:           ticketSLA,
+		alertSeverityRule:   alertSeverityRule,
+		statusPage:          NewStatusPageService(repoManager, serviceCatalog, serviceHealth, notificationService, cfg.StatusPage, actionLinkCfg.BaseURL, actionLinkCfg.Secret, logger),
+		serviceHealth:       serviceHealth,
+		configApply:         NewConfigApplyService(repoManager, approval, logger),
+		backup:              NewBackupService(repoManager, cfg.App.Version, logger),
+		assignment:          assignment,
+		handover:            NewHandoverService(repoManager, knowledgeService, notificationService, cfg.Handover, logger),
+		alertActionLink:     alertActionLink,
+		push:                pushService,
+		inbox:               inbox,
+		quietHours:          NewQuietHoursService(repoManager, logger),
+		changeEvent:         changeEvent,
+		alertReplay:         NewAlertReplayService(repoManager, serviceCatalog, assignment, pushService, logger),
+		webhookSubscription: webhookSubscription,
+		graphql:             NewGraphQLService(repoManager, knowledgeACL, logger),
+		ticketImport:        NewTicketImportService(repoManager, ticketService, cfg.TicketImport, logger),
+		alertStats:          NewAlertStatsService(repoManager, cfg.AlertStats, logger),
+		labelCardinality:    labelCardinality,
+		labelAutocomplete:   NewLabelAutocompleteService(repoManager, sharedCache, cfg.LabelAutocomplete, logger),
+		ticketSurvey:        NewTicketSurveyService(repoManager, notificationService, serviceCatalog, cfg.TicketSurvey, actionLinkCfg.BaseURL, logger),
+		knowledgeFeedback:   knowledgeFeedback,
+		approval:            approval,
+		ingestRejection:     NewIngestRejectionService(repoManager, cfg.IngestSchema, logger),
+		asyncTask:           NewAsyncTaskService(repoManager, dataSourceService, knowledgeTransfer, knowledgeACL, cfg.AsyncTask, logger),
+		queryCache:          queryCache,
+		notificationChannel: NewNotificationChannelService(repoManager, notificationService, logger),
 	}
 }
 
@@ -70,6 +350,11 @@ func (s *serviceManager) Alert() AlertService {
 	return s.alertService
 }
 
+// AlertTimeline 获取告警时间线服务
+func (s *serviceManager) AlertTimeline() AlertTimelineService {
+	return s.alertTimeline
+}
+
 // Rule 获取规则服务
 func (s *serviceManager) Rule() RuleService {
 	return s.ruleService
@@ -113,4 +398,340 @@ func (s *serviceManager) Webhook() WebhookService {
 // Config 获取配置服务
 func (s *serviceManager) Config() ConfigService {
 	return s.configService
-}
\ No newline at end of file
+}
+
+// CustomField 获取自定义字段服务
+func (s *serviceManager) CustomField() CustomFieldService {
+	return s.customFieldService
+}
+
+// AttachmentScan 获取附件安全扫描服务
+func (s *serviceManager) AttachmentScan() AttachmentScanService {
+	return s.attachmentScan
+}
+
+// KnowledgeReview 获取知识库审核服务
+func (s *serviceManager) KnowledgeReview() KnowledgeReviewService {
+	return s.knowledgeReview
+}
+
+// KnowledgeComment 获取知识库评论服务
+func (s *serviceManager) KnowledgeComment() KnowledgeCommentService {
+	return s.knowledgeComment
+}
+
+// KnowledgeSchedule 获取知识库定时发布服务
+func (s *serviceManager) KnowledgeSchedule() KnowledgeScheduleService {
+	return s.knowledgeSchedule
+}
+
+// KnowledgeTransfer 获取知识库导入导出服务
+func (s *serviceManager) KnowledgeTransfer() KnowledgeTransferService {
+	return s.knowledgeTransfer
+}
+
+// KnowledgeACL 获取知识库访问控制服务
+func (s *serviceManager) KnowledgeACL() KnowledgeACLService {
+	return s.knowledgeACL
+}
+
+// WebhookIntegration 获取告警接入集成服务
+func (s *serviceManager) WebhookIntegration() WebhookIntegrationService {
+	return s.webhookIntegration
+}
+
+// KeyRotation 获取加密密钥轮换服务
+func (s *serviceManager) KeyRotation() KeyRotationService {
+	return s.keyRotation
+}
+
+// FeatureFlag 获取功能开关服务
+func (s *serviceManager) FeatureFlag() FeatureFlagService {
+	return s.featureFlag
+}
+
+// Dashboard 获取首页仪表盘服务
+func (s *serviceManager) Dashboard() DashboardService {
+	return s.dashboard
+}
+
+// Postmortem 获取事件复盘服务
+func (s *serviceManager) Postmortem() PostmortemService {
+	return s.postmortem
+}
+
+// Remediation 获取规则修复动作服务
+func (s *serviceManager) Remediation() RemediationService {
+	return s.remediation
+}
+
+// ChatOps 获取聊天平台命令服务
+func (s *serviceManager) ChatOps() ChatOpsService {
+	return s.chatOps
+}
+
+// ITSM 获取外部 ITSM 工单同步服务
+func (s *serviceManager) ITSM() ITSMService {
+	return s.itsm
+}
+
+// Heartbeat 获取心跳监控服务
+func (s *serviceManager) Heartbeat() HeartbeatService {
+	return s.heartbeat
+}
+
+// Kubernetes 获取 Kubernetes 数据源采集服务
+func (s *serviceManager) Kubernetes() KubernetesService {
+	return s.kubernetes
+}
+
+// ServiceCatalog 获取服务目录服务
+func (s *serviceManager) ServiceCatalog() ServiceCatalogService {
+	return s.serviceCatalog
+}
+
+// SLO 获取 SLO 服务
+func (s *serviceManager) SLO() SLOService {
+	return s.slo
+}
+
+// AlertAutoResolve 获取告警自动解决服务
+func (s *serviceManager) AlertAutoResolve() AlertAutoResolveService {
+	return s.alertAutoResolve
+}
+
+// AlertSnooze 获取告警暂停服务
+func (s *serviceManager) AlertSnooze() AlertSnoozeService {
+	return s.alertSnooze
+}
+
+// NotificationCalendar 获取通知日历服务
+func (s *serviceManager) NotificationCalendar() NotificationCalendarService {
+	return s.notificationCalendar
+}
+
+// Localization 获取多语言服务
+func (s *serviceManager) Localization() LocalizationService {
+	return s.localization
+}
+
+// TagGovernance 获取标签治理服务
+func (s *serviceManager) TagGovernance() TagGovernanceService {
+	return s.tagGovernance
+}
+
+// AlertPartition 获取告警分区维护服务
+func (s *serviceManager) AlertPartition() AlertPartitionService {
+	return s.alertPartition
+}
+
+// DataRetention 获取数据保留服务
+func (s *serviceManager) DataRetention() DataRetentionService {
+	return s.dataRetention
+}
+
+// AuditLog 获取审计日志服务
+func (s *serviceManager) AuditLog() AuditLogService {
+	return s.auditLog
+}
+
+// Impersonation 获取模拟登录服务
+func (s *serviceManager) Impersonation() ImpersonationService {
+	return s.impersonation
+}
+
+// Metric 获取内置指标存储服务
+func (s *serviceManager) Metric() MetricService {
+	return s.metric
+}
+
+// TicketSLA 获取工单SLA暂停服务
+func (s *serviceManager) TicketSLA() TicketSLAService {
+	return s.ticketSLA
+}
+
+// AlertSeverityRule 获取告警重新分级规则服务
+func (s *serviceManager) AlertSeverityRule() AlertSeverityRuleService {
+	return s.alertSeverityRule
+}
+
+// StatusPage 获取公开状态页服务
+func (s *serviceManager) StatusPage() StatusPageService {
+	return s.statusPage
+}
+
+// ServiceHealth 获取服务健康度服务
+func (s *serviceManager) ServiceHealth() ServiceHealthService {
+	return s.serviceHealth
+}
+
+// RuleForecast 获取规则预测服务
+func (s *serviceManager) RuleForecast() RuleForecastService {
+	return s.ruleForecast
+}
+
+// ConfigApply 获取声明式配置服务
+func (s *serviceManager) ConfigApply() ConfigApplyService {
+	return s.configApply
+}
+
+// Backup 获取配置备份服务
+func (s *serviceManager) Backup() BackupService {
+	return s.backup
+}
+
+// Assignment 获取分派策略服务
+func (s *serviceManager) Assignment() AssignmentService {
+	return s.assignment
+}
+
+// Handover 获取交班报告服务
+func (s *serviceManager) Handover() HandoverService {
+	return s.handover
+}
+
+// AlertActionLink 获取告警操作链接服务
+func (s *serviceManager) AlertActionLink() AlertActionLinkService {
+	return s.alertActionLink
+}
+
+// Push 获取移动端推送服务
+func (s *serviceManager) Push() PushService {
+	return s.push
+}
+
+// Inbox 获取站内通知收件箱服务
+func (s *serviceManager) Inbox() InboxService {
+	return s.inbox
+}
+
+// QuietHours 获取用户免打扰服务
+func (s *serviceManager) QuietHours() QuietHoursService {
+	return s.quietHours
+}
+
+// ChangeEvent 获取变更事件服务
+func (s *serviceManager) ChangeEvent() ChangeEventService {
+	return s.changeEvent
+}
+
+// AlertReplay 获取告警回放服务
+func (s *serviceManager) AlertReplay() AlertReplayService {
+	return s.alertReplay
+}
+
+// WebhookSubscription 获取出站 Webhook 订阅服务
+func (s *serviceManager) WebhookSubscription() WebhookSubscriptionService {
+	return s.webhookSubscription
+}
+
+// GraphQL 获取 GraphQL 查询服务
+func (s *serviceManager) GraphQL() GraphQLService {
+	return s.graphql
+}
+
+// TicketImport 获取工单导入服务
+func (s *serviceManager) TicketImport() TicketImportService {
+	return s.ticketImport
+}
+
+// AlertStats 获取告警统计服务
+func (s *serviceManager) AlertStats() AlertStatsService {
+	return s.alertStats
+}
+
+// LabelCardinality 获取告警标签基数服务
+func (s *serviceManager) LabelCardinality() LabelCardinalityService {
+	return s.labelCardinality
+}
+
+// LabelAutocomplete 获取告警标签自动补全服务
+func (s *serviceManager) LabelAutocomplete() LabelAutocompleteService {
+	return s.labelAutocomplete
+}
+
+// TicketSurvey 获取工单满意度调查服务
+func (s *serviceManager) TicketSurvey() TicketSurveyService {
+	return s.ticketSurvey
+}
+
+// KnowledgeFeedback 获取知识库有用性反馈服务
+func (s *serviceManager) KnowledgeFeedback() KnowledgeFeedbackService {
+	return s.knowledgeFeedback
+}
+
+// Approval 获取敏感操作审批服务
+func (s *serviceManager) Approval() ApprovalService {
+	return s.approval
+}
+
+// IngestRejection 获取告警接入拒绝记录服务
+func (s *serviceManager) IngestRejection() IngestRejectionService {
+	return s.ingestRejection
+}
+
+// AsyncTask 获取异步任务服务
+func (s *serviceManager) AsyncTask() AsyncTaskService {
+	return s.asyncTask
+}
+
+// QueryCache 获取数据源查询结果缓存服务
+func (s *serviceManager) QueryCache() QueryCacheService {
+	return s.queryCache
+}
+
+// NotificationChannel 获取团队通知渠道服务
+func (s *serviceManager) NotificationChannel() NotificationChannelService {
+	return s.notificationChannel
+}
+
+// newITSMClientFactory 创建按连接器类型构造 ITSM 客户端的工厂，所有客户端共用同一个 HTTP 客户端，使用全局代理和CA证书
+func newITSMClientFactory(cfg config.ITSMConfig, httpClients *httpclient.Factory) ITSMClientFactory {
+	httpClient := httpClients.Client(httpclient.Policy{Timeout: cfg.HTTPTimeout, MaxAttempts: 1})
+	return func(connector *models.ITSMConnector) (ITSMClient, error) {
+		return itsm.New(connector, httpClient)
+	}
+}
+
+// newKubernetesClientFactory 创建根据数据源连接配置构造 Kubernetes 客户端的工厂
+func newKubernetesClientFactory(cfg config.KubernetesConfig) KubernetesClientFactory {
+	return func(dataSource *models.DataSource) (kubernetes.Source, error) {
+		clientConfig, err := kubernetes.ConfigFromDataSource(&dataSource.Config)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewClient(clientConfig, cfg.HTTPTimeout)
+	}
+}
+
+// newForecastQuerierFactory 创建规则预测使用的 Prometheus 区间查询客户端工厂，
+// 数据源未配置超时时间时使用 RULE_FORECAST_QUERY_TIMEOUT，并使用数据源的代理、CA证书和熔断器
+func newForecastQuerierFactory(cfg config.RuleForecastConfig, httpClients *httpclient.Factory, breakers *breaker.Registry) ForecastQuerierFactory {
+	return func(dataSource *models.DataSource) (ForecastQuerier, error) {
+		httpClient, err := httpClients.ClientWithOptions(dataSource.Config.TransportOptions(), dataSource.Config.HTTPPolicy(), httpclient.Policy{Timeout: cfg.QueryTimeout})
+		if err != nil {
+			return nil, err
+		}
+		client, err := prometheus.NewClient(&dataSource.Config, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return &breakerForecastQuerier{querier: client, breaker: breakers.Get(dataSourceBreakerName(dataSource.ID))}, nil
+	}
+}
+
+// newSLOQuerierFactory 创建根据数据源连接配置构造 Prometheus 查询客户端的工厂，
+// 查询按数据源的重试策略超时和重试，数据源未配置超时时间时使用 SLO_QUERY_TIMEOUT，并使用数据源的代理、CA证书和熔断器
+func newSLOQuerierFactory(cfg config.SLOConfig, httpClients *httpclient.Factory, breakers *breaker.Registry) SLOQuerierFactory {
+	return func(dataSource *models.DataSource) (SLOQuerier, error) {
+		httpClient, err := httpClients.ClientWithOptions(dataSource.Config.TransportOptions(), dataSource.Config.HTTPPolicy(), httpclient.Policy{Timeout: cfg.QueryTimeout})
+		if err != nil {
+			return nil, err
+		}
+		client, err := prometheus.NewClient(&dataSource.Config, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return &breakerSLOQuerier{querier: client, breaker: breakers.Get(dataSourceBreakerName(dataSource.ID))}, nil
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/remotewrite"
+	"pulse/internal/repository"
+)
+
+// maxRemoteWriteDecodedSize remote-write 请求体解压后的大小上限
+const maxRemoteWriteDecodedSize = 32 << 20
+
+// remoteWriteForwardTimeout 转发 remote-write 请求的超时时间
+const remoteWriteForwardTimeout = 30 * time.Second
+
+// metricService 内置指标存储服务实现
+type metricService struct {
+	repoManager repository.RepositoryManager
+	httpClients *httpclient.Factory
+	cfg         config.RemoteWriteConfig
+}
+
+// NewMetricService 创建内置指标存储服务
+func NewMetricService(repoManager repository.RepositoryManager, httpClients *httpclient.Factory, cfg config.RemoteWriteConfig) MetricService {
+	return &metricService{
+		repoManager: repoManager,
+		httpClients: httpClients,
+		cfg:         cfg,
+	}
+}
+
+// RemoteWrite 解析 remote-write 请求体，配置了转发地址时原样转发，否则存入内置指标存储。
+// 请求格式错误时返回 ErrInvalidInput，采集端不应重试；存储或转发失败时返回其他错误，采集端可以重试
+func (s *metricService) RemoteWrite(ctx context.Context, body []byte) (*models.RemoteWriteResult, error) {
+	series, err := remotewrite.Decode(body, maxRemoteWriteDecodedSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidInput, err)
+	}
+
+	result := &models.RemoteWriteResult{Series: len(series)}
+	for i := range series {
+		result.Samples += len(series[i].Samples)
+	}
+	if s.cfg.MaxSamples > 0 && result.Samples > s.cfg.MaxSamples {
+		return nil, fmt.Errorf("%w: 请求包含 %d 个样本，超过上限 %d", models.ErrInvalidInput, result.Samples, s.cfg.MaxSamples)
+	}
+
+	if s.cfg.ForwardURL != "" {
+		if err := s.forward(ctx, body); err != nil {
+			return nil, err
+		}
+		result.Forwarded = true
+		return result, nil
+	}
+
+	toWrite, err := metricSeriesFromRemoteWrite(series)
+	if err != nil {
+		return nil, err
+	}
+	if result.Samples, err = s.repoManager.Metric().Write(ctx, toWrite); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// metricSeriesFromRemoteWrite 将 remote-write 的时间序列转换为内置指标存储的序列，
+// NaN 样本（包括 Prometheus 的序列过期标记）不存储
+func metricSeriesFromRemoteWrite(series []remotewrite.TimeSeries) ([]*models.MetricSeries, error) {
+	result := make([]*models.MetricSeries, 0, len(series))
+	for i := range series {
+		ts := &series[i]
+		metric := ts.Metric()
+		if metric == "" {
+			return nil, fmt.Errorf("%w: 时间序列缺少 %s 标签", models.ErrInvalidInput, remotewrite.MetricNameLabel)
+		}
+
+		labels := make(map[string]string, len(ts.Labels))
+		for _, label := range ts.Labels {
+			if label.Name != remotewrite.MetricNameLabel {
+				labels[label.Name] = label.Value
+			}
+		}
+		samples := make([]models.MetricSample, 0, len(ts.Samples))
+		for _, sample := range ts.Samples {
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+			samples = append(samples, models.MetricSample{Timestamp: time.UnixMilli(sample.Timestamp), Value: sample.Value})
+		}
+		result = append(result, &models.MetricSeries{Metric: metric, Labels: labels, Samples: samples})
+	}
+	return result, nil
+}
+
+// forward 将请求体原样转发到配置的 remote-write 后端。后端拒绝请求（4xx）时返回 ErrInvalidInput，
+// 与后端直接接收时一致，采集端不再重试
+func (s *metricService) forward(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.ForwardURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建转发请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", remotewrite.ContentType)
+	req.Header.Set("Content-Encoding", remotewrite.ContentEncoding)
+	req.Header.Set(remotewrite.HeaderVersion, remotewrite.Version)
+
+	// 重试由采集端负责，客户端只发送一次
+	client := s.httpClients.Client(httpclient.Policy{Timeout: remoteWriteForwardTimeout, MaxAttempts: 1})
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("转发到远程写入后端失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseReadLimit))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: 远程写入后端拒绝请求（HTTP %d）: %s", models.ErrInvalidInput, resp.StatusCode, respBody)
+	default:
+		return fmt.Errorf("远程写入后端返回 HTTP %d: %s", resp.StatusCode, respBody)
+	}
+}
+
+// Query 执行即时查询，返回聚合后的值，与 Prometheus 数据源的查询客户端一致，可作为 SLOQuerier 使用
+func (s *metricService) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	result, err := s.Evaluate(ctx, expr, at)
+	if err != nil {
+		return 0, err
+	}
+	return result.Value, nil
+}
+
+// Evaluate 执行即时查询，返回聚合后的值和匹配的序列，at 之前 Lookback 内没有样本的序列视为无数据
+func (s *metricService) Evaluate(ctx context.Context, expr string, at time.Time) (*models.MetricQueryResult, error) {
+	query, err := models.ParseMetricQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	series, err := s.repoManager.Metric().Latest(ctx, query, at, s.cfg.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(series))
+	for _, ts := range series {
+		values = append(values, ts.Samples[0].Value)
+	}
+
+	value, err := query.Aggregate(values)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		series = []*models.MetricSeries{}
+	}
+	return &models.MetricQueryResult{Query: expr, Time: at, Value: value, Series: series}, nil
+}
+
+// Interval 返回清理过期样本的周期
+func (s *metricService) Interval() time.Duration {
+	return s.cfg.CleanupInterval
+}
+
+// Cleanup 删除超过保留时间的样本和不再上报的序列，转发模式下不在本地存储，无需清理
+func (s *metricService) Cleanup(ctx context.Context) (int64, error) {
+	if s.cfg.ForwardURL != "" {
+		return 0, nil
+	}
+	return s.repoManager.Metric().DeleteBefore(ctx, time.Now().Add(-s.cfg.Retention))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/remotewrite"
+	"pulse/internal/repository"
+)
+
+// fakeMetricRepository 内存中的内置指标存储
+type fakeMetricRepository struct {
+	series map[string]*models.MetricSeries
+}
+
+func (r *fakeMetricRepository) Write(ctx context.Context, series []*models.MetricSeries) (int, error) {
+	if r.series == nil {
+		r.series = map[string]*models.MetricSeries{}
+	}
+	written := 0
+	for _, s := range series {
+		fingerprint := models.MetricFingerprint(s.Metric, s.Labels)
+		stored, ok := r.series[fingerprint]
+		if !ok {
+			stored = &models.MetricSeries{ID: int64(len(r.series) + 1), Metric: s.Metric, Labels: s.Labels, Fingerprint: fingerprint}
+			r.series[fingerprint] = stored
+		}
+		stored.Samples = append(stored.Samples, s.Samples...)
+		written += len(s.Samples)
+	}
+	return written, nil
+}
+
+func (r *fakeMetricRepository) Latest(ctx context.Context, query *models.MetricQuery, at time.Time, lookback time.Duration) ([]*models.MetricSeries, error) {
+	var result []*models.MetricSeries
+	for _, s := range r.series {
+		if s.Metric != query.Metric || !matchMetricLabels(query.Matchers, s.Labels) {
+			continue
+		}
+		var latest *models.MetricSample
+		for i, sample := range s.Samples {
+			if sample.Timestamp.After(at) || !sample.Timestamp.After(at.Add(-lookback)) {
+				continue
+			}
+			if latest == nil || sample.Timestamp.After(latest.Timestamp) {
+				latest = &s.Samples[i]
+			}
+		}
+		if latest != nil {
+			result = append(result, &models.MetricSeries{ID: s.ID, Metric: s.Metric, Labels: s.Labels, Samples: []models.MetricSample{*latest}})
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeMetricRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func matchMetricLabels(matchers []models.MetricMatcher, labels map[string]string) bool {
+	for _, m := range matchers {
+		value := labels[m.Name]
+		switch m.Op {
+		case models.MetricMatchEqual:
+			if value != m.Value {
+				return false
+			}
+		case models.MetricMatchNotEqual:
+			if value == m.Value {
+				return false
+			}
+		case models.MetricMatchRegexp, models.MetricMatchNotRegexp:
+			matched := regexp.MustCompile("^(?:" + m.Value + ")$").MatchString(value)
+			if matched != (m.Op == models.MetricMatchRegexp) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type metricRepoManager struct {
+	*MockRepositoryManager
+	metrics *fakeMetricRepository
+}
+
+func (m *metricRepoManager) Metric() repository.MetricRepository { return m.metrics }
+
+func TestMetricService_RemoteWriteAndQuery(t *testing.T) {
+	repoManager := &metricRepoManager{MockRepositoryManager: &MockRepositoryManager{}, metrics: &fakeMetricRepository{}}
+	svc := NewMetricService(repoManager, nil, config.RemoteWriteConfig{MaxSamples: 10, Lookback: 5 * time.Minute})
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+
+	body := remotewrite.Encode([]remotewrite.TimeSeries{
+		{
+			Labels: []remotewrite.Label{{Name: "__name__", Value: "node_load1"}, {Name: "instance", Value: "web-1"}},
+			Samples: []remotewrite.Sample{
+				{Value: 0.5, Timestamp: now.Add(-time.Minute).UnixMilli()},
+				{Value: 2.5, Timestamp: now.UnixMilli()},
+			},
+		},
+		{
+			Labels: []remotewrite.Label{{Name: "__name__", Value: "node_load1"}, {Name: "instance", Value: "web-2"}},
+			// 过期标记不存储
+			Samples: []remotewrite.Sample{{Value: 1.5, Timestamp: now.UnixMilli()}, {Value: math.NaN(), Timestamp: now.UnixMilli()}},
+		},
+		{
+			Labels:  []remotewrite.Label{{Name: "__name__", Value: "node_load1"}, {Name: "instance", Value: "db-1"}},
+			Samples: []remotewrite.Sample{{Value: 9, Timestamp: now.Add(-time.Hour).UnixMilli()}},
+		},
+	})
+	result, err := svc.RemoteWrite(ctx, body)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Series)
+	assert.Equal(t, 4, result.Samples)
+	assert.False(t, result.Forwarded)
+	assert.Equal(t, map[string]string{"instance": "web-1"}, repoManager.metrics.series[models.MetricFingerprint("node_load1", map[string]string{"instance": "web-1"})].Labels)
+
+	// 按最新样本聚合，超过查找范围的序列视为无数据
+	value, err := svc.Query(ctx, `max(node_load1)`, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, value)
+	value, err = svc.Query(ctx, `sum(node_load1{instance=~"web-.*"})`, now)
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, value)
+	value, err = svc.Query(ctx, `node_load1{instance="web-1"}`, now.Add(-30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+	value, err = svc.Query(ctx, `count(node_load1{instance="db-1"})`, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value)
+
+	_, err = svc.Query(ctx, `node_load1`, now)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Query(ctx, `node_load1{instance="db-1"}`, now)
+	assert.ErrorIs(t, err, models.ErrMetricNoData)
+	_, err = svc.Query(ctx, `rate(node_load1[5m])`, now)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 格式错误、缺少指标名和样本数超过上限的请求
+	_, err = svc.RemoteWrite(ctx, []byte("not snappy"))
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.RemoteWrite(ctx, remotewrite.Encode([]remotewrite.TimeSeries{{
+		Labels: []remotewrite.Label{{Name: "job", Value: "node"}}, Samples: []remotewrite.Sample{{Value: 1}},
+	}}))
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.RemoteWrite(ctx, remotewrite.Encode([]remotewrite.TimeSeries{{
+		Labels: []remotewrite.Label{{Name: "__name__", Value: "up"}}, Samples: make([]remotewrite.Sample, 11),
+	}}))
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestMetricService_RemoteWriteForward(t *testing.T) {
+	var received []byte
+	status := http.StatusNoContent
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, remotewrite.Version, r.Header.Get(remotewrite.HeaderVersion))
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer backend.Close()
+
+	repoManager := &metricRepoManager{MockRepositoryManager: &MockRepositoryManager{}, metrics: &fakeMetricRepository{}}
+	svc := NewMetricService(repoManager, nil, config.RemoteWriteConfig{ForwardURL: backend.URL, MaxSamples: 10})
+	body := remotewrite.Encode([]remotewrite.TimeSeries{{
+		Labels: []remotewrite.Label{{Name: "__name__", Value: "up"}}, Samples: []remotewrite.Sample{{Value: 1, Timestamp: 1}},
+	}})
+
+	// 请求体原样转发，不在本地存储
+	result, err := svc.RemoteWrite(context.Background(), body)
+	require.NoError(t, err)
+	assert.True(t, result.Forwarded)
+	assert.Equal(t, body, received)
+	assert.Empty(t, repoManager.metrics.series)
+
+	// 后端拒绝的请求采集端不应重试，后端故障时返回可重试的错误
+	status = http.StatusBadRequest
+	_, err = svc.RemoteWrite(context.Background(), body)
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	status = http.StatusServiceUnavailable
+	_, err = svc.RemoteWrite(context.Background(), body)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrInvalidInput)
+}
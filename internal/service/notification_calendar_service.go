@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// notificationDigestBatchSize 每次汇总发送处理的延迟通知数量上限
+const notificationDigestBatchSize = 500
+
+// notificationCalendarService 通知日历服务实现
+type notificationCalendarService struct {
+	repoManager         repository.RepositoryManager
+	notificationService NotificationService
+	cfg                 config.NotificationConfig
+	logger              *zap.Logger
+}
+
+// NewNotificationCalendarService 创建通知日历服务实例
+func NewNotificationCalendarService(repoManager repository.RepositoryManager, notificationService NotificationService, cfg config.NotificationConfig, logger *zap.Logger) NotificationCalendarService {
+	return &notificationCalendarService{
+		repoManager:         repoManager,
+		notificationService: notificationService,
+		cfg:                 cfg,
+		logger:              logger,
+	}
+}
+
+// Interval 汇总发送延迟通知的周期
+func (s *notificationCalendarService) Interval() time.Duration {
+	return s.cfg.DigestInterval
+}
+
+// List 获取全部通知日历
+func (s *notificationCalendarService) List(ctx context.Context) ([]*models.NotificationCalendar, error) {
+	return s.repoManager.NotificationRouting().ListCalendars(ctx)
+}
+
+// Get 获取通知日历
+func (s *notificationCalendarService) Get(ctx context.Context, id string) (*models.NotificationCalendar, error) {
+	return s.repoManager.NotificationRouting().GetCalendar(ctx, id)
+}
+
+// Create 创建通知日历，同一团队和地区只能有一个日历
+func (s *notificationCalendarService) Create(ctx context.Context, req *models.NotificationCalendarRequest, createdBy string) (*models.NotificationCalendar, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	calendar := &models.NotificationCalendar{CreatedBy: createdBy}
+	req.ApplyTo(calendar)
+	if err := s.repoManager.NotificationRouting().CreateCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建通知日历", zap.String("calendar_id", calendar.ID), zap.String("name", calendar.Name),
+		zap.String("team", calendar.Team), zap.String("region", calendar.Region))
+	return calendar, nil
+}
+
+// Update 更新通知日历
+func (s *notificationCalendarService) Update(ctx context.Context, id string, req *models.NotificationCalendarRequest) (*models.NotificationCalendar, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.repoManager.NotificationRouting().GetCalendar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(calendar)
+	if err := s.repoManager.NotificationRouting().UpdateCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// Delete 删除通知日历，日历下尚未发送的延迟通知一并删除
+func (s *notificationCalendarService) Delete(ctx context.Context, id string) error {
+	return s.repoManager.NotificationRouting().DeleteCalendar(ctx, id)
+}
+
+// SendDigests 将发送时间已到的延迟通知按通知类型和接收人合并为一条汇总通知发送，返回已发送的延迟通知数量。
+// 汇总通知发送失败时保留延迟通知，下个周期重试
+func (s *notificationCalendarService) SendDigests(ctx context.Context) (int, error) {
+	due, err := s.repoManager.NotificationRouting().ListDueDeferred(ctx, time.Now(), notificationDigestBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("获取到期的延迟通知失败: %w", err)
+	}
+
+	sent := 0
+	for _, group := range groupDeferredNotifications(due) {
+		digest := &models.Notification{
+			Type:        group[0].Type,
+			Recipient:   group[0].Recipient,
+			MessageKey:  "notification.digest",
+			MessageData: deferredDigestData(group),
+		}
+		if err := s.notificationService.Send(ctx, digest); err != nil {
+			s.logger.Error("发送延迟通知汇总失败", zap.Error(err), zap.String("recipient", digest.Recipient),
+				zap.String("type", string(digest.Type)))
+			continue
+		}
+
+		ids := make([]string, 0, len(group))
+		for _, n := range group {
+			ids = append(ids, n.ID)
+		}
+		if err := s.repoManager.NotificationRouting().DeleteDeferred(ctx, ids); err != nil {
+			return sent, err
+		}
+		sent += len(group)
+	}
+
+	if sent > 0 {
+		s.logger.Info("延迟通知汇总已发送", zap.Int("count", sent))
+	}
+	return sent, nil
+}
+
+// matchNotificationCalendar 返回与告警标签匹配程度最高的通知日历，没有匹配的日历时返回 nil
+func matchNotificationCalendar(calendars []*models.NotificationCalendar, labels map[string]string) *models.NotificationCalendar {
+	var best *models.NotificationCalendar
+	bestScore := -1
+	for _, calendar := range calendars {
+		if score := calendar.Match(labels); score > bestScore {
+			best, bestScore = calendar, score
+		}
+	}
+	return best
+}
+
+// groupDeferredNotifications 按通知类型和接收人分组，notifications 已按类型和接收人排序
+func groupDeferredNotifications(notifications []*models.DeferredNotification) [][]*models.DeferredNotification {
+	var groups [][]*models.DeferredNotification
+	for i, n := range notifications {
+		if i == 0 || n.Type != notifications[i-1].Type || n.Recipient != notifications[i-1].Recipient {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], n)
+	}
+	return groups
+}
+
+// deferredDigestData 生成汇总通知的消息参数，按延迟时间顺序列出每条通知
+func deferredDigestData(notifications []*models.DeferredNotification) map[string]interface{} {
+	items := make([]map[string]string, 0, len(notifications))
+	for _, n := range notifications {
+		title := n.Subject
+		if title == "" {
+			title = strings.SplitN(n.Content, "\n", 2)[0]
+		}
+		items = append(items, map[string]string{"Time": n.CreatedAt.Format("2006-01-02 15:04"), "Title": title})
+	}
+	return map[string]interface{}{"Count": len(notifications), "Items": items}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeNotificationRoutingRepository 内存中的通知日历和延迟通知
+type fakeNotificationRoutingRepository struct {
+	repository.NotificationRoutingRepository
+	calendars []*models.NotificationCalendar
+	deferred  []*models.DeferredNotification
+}
+
+func (r *fakeNotificationRoutingRepository) ListCalendars(ctx context.Context) ([]*models.NotificationCalendar, error) {
+	return r.calendars, nil
+}
+
+func (r *fakeNotificationRoutingRepository) CreateDeferred(ctx context.Context, notification *models.DeferredNotification) error {
+	notification.ID = uuid.New().String()
+	notification.CreatedAt = time.Now()
+	r.deferred = append(r.deferred, notification)
+	return nil
+}
+
+func (r *fakeNotificationRoutingRepository) ListDueDeferred(ctx context.Context, now time.Time, limit int) ([]*models.DeferredNotification, error) {
+	var due []*models.DeferredNotification
+	for _, n := range r.deferred {
+		if !n.DeliverAt.After(now) {
+			due = append(due, n)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeNotificationRoutingRepository) DeleteDeferred(ctx context.Context, ids []string) error {
+	remaining := r.deferred[:0]
+	for _, n := range r.deferred {
+		deleted := false
+		for _, id := range ids {
+			deleted = deleted || n.ID == id
+		}
+		if !deleted {
+			remaining = append(remaining, n)
+		}
+	}
+	r.deferred = remaining
+	return nil
+}
+
+// fakeSentNotificationRepository 记录立即发送的通知
+type fakeSentNotificationRepository struct {
+	repository.NotificationRepository
+	sent []*models.Notification
+}
+
+func (r *fakeSentNotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	r.sent = append(r.sent, notification)
+	return nil
+}
+
+func (r *fakeSentNotificationRepository) Update(ctx context.Context, notification *models.Notification) error {
+	return nil
+}
+
+func (r *fakeSentNotificationRepository) GetByID(ctx context.Context, id string) (*models.Notification, error) {
+	for _, notification := range r.sent {
+		if notification.ID.String() == id {
+			return notification, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSentNotificationRepository) GetByAlertID(ctx context.Context, alertID string) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	for _, notification := range r.sent {
+		if notification.AlertID.String() == alertID {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+type notificationCalendarRepoManager struct {
+	*alertSnoozeRepoManager
+	routing       *fakeNotificationRoutingRepository
+	notifications *fakeSentNotificationRepository
+	languages     *fakeLanguagePreferenceRepository
+}
+
+func (m *notificationCalendarRepoManager) NotificationRouting() repository.NotificationRoutingRepository {
+	return m.routing
+}
+
+func (m *notificationCalendarRepoManager) Notification() repository.NotificationRepository {
+	return m.notifications
+}
+
+func (m *notificationCalendarRepoManager) LanguagePreference() repository.LanguagePreferenceRepository {
+	return m.languages
+}
+
+func TestNotificationCalendarService_DefersOutsideBusinessHours(t *testing.T) {
+	// 今天是节假日，下一个工作时间开始于明天 09:00
+	today := time.Now().UTC()
+	repoManager := &notificationCalendarRepoManager{
+		alertSnoozeRepoManager: newAlertSnoozeTestRepoManager(),
+		routing: &fakeNotificationRoutingRepository{calendars: []*models.NotificationCalendar{
+			{ID: "any", Name: "默认", DeferSeverities: []models.AlertSeverity{models.AlertSeverityLow}, BusinessCalendar: models.BusinessCalendar{
+				BusinessHours: models.BusinessHours{Days: []string{"monday"}, Start: "00:00", End: "23:59"},
+			}},
+			{ID: "payments", Name: "支付团队", Team: "payments", DeferSeverities: []models.AlertSeverity{models.AlertSeverityLow}, BusinessCalendar: models.BusinessCalendar{
+				BusinessHours: models.BusinessHours{
+					Days:  []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"},
+					Start: "09:00", End: "18:00",
+				},
+				Holidays: []string{today.Format("2006-01-02")},
+			}},
+		}},
+		notifications: &fakeSentNotificationRepository{},
+		languages:     newFakeLanguagePreferenceRepository(),
+	}
+	low, critical := uuid.New(), uuid.New()
+	repoManager.alerts.alerts[low.String()] = &models.Alert{ID: low.String(), Severity: models.AlertSeverityLow,
+		Labels: map[string]string{models.TeamLabel: "payments"}}
+	repoManager.alerts.alerts[critical.String()] = &models.Alert{ID: critical.String(), Severity: models.AlertSeverityCritical,
+		Labels: map[string]string{models.TeamLabel: "payments"}}
+
+	notifications := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+	svc := NewNotificationCalendarService(repoManager, notifications, config.NotificationConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	// 低级别告警按团队日历延迟，严重告警始终立即发送
+	for _, alertID := range []uuid.UUID{low, critical} {
+		require.NoError(t, notifications.Send(ctx, &models.Notification{
+			AlertID: alertID, Type: models.NotificationTypeDingTalk, Recipient: "alice", Subject: "支付告警", Content: "支付延迟升高",
+		}))
+	}
+	require.Len(t, repoManager.routing.deferred, 1)
+	deferred := repoManager.routing.deferred[0]
+	assert.Equal(t, "payments", deferred.CalendarID)
+	tomorrow := today.AddDate(0, 0, 1)
+	assert.Equal(t, time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 9, 0, 0, 0, time.UTC), deferred.DeliverAt)
+	require.Len(t, repoManager.notifications.sent, 1)
+	assert.Equal(t, critical, repoManager.notifications.sent[0].AlertID)
+
+	// 未到发送时间时不汇总
+	sent, err := svc.SendDigests(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// 到期后按接收人合并为一条汇总通知
+	deferred.DeliverAt = time.Now().Add(-time.Minute)
+	repoManager.routing.deferred = append(repoManager.routing.deferred, &models.DeferredNotification{
+		ID: "d2", CalendarID: "payments", Type: models.NotificationTypeDingTalk, Recipient: "alice",
+		Content: "支付成功率下降\n详情", DeliverAt: deferred.DeliverAt,
+	})
+	sent, err = svc.SendDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Empty(t, repoManager.routing.deferred)
+	require.Len(t, repoManager.notifications.sent, 2)
+	digest := repoManager.notifications.sent[1]
+	assert.Equal(t, "非工作时间告警汇总（2 条）", digest.Subject)
+	assert.Contains(t, digest.Content, "支付告警")
+	assert.Contains(t, digest.Content, "支付成功率下降")
+	assert.NotContains(t, digest.Content, "详情")
+}
+
+func TestNotificationCalendarService_RejectsCritical(t *testing.T) {
+	svc := NewNotificationCalendarService(&MockRepositoryManager{}, nil, config.NotificationConfig{}, zap.NewNop())
+
+	_, err := svc.Create(context.Background(), &models.NotificationCalendarRequest{
+		Name: "夜间", DeferSeverities: []models.AlertSeverity{models.AlertSeverityCritical},
+		BusinessCalendar: models.BusinessCalendar{BusinessHours: models.BusinessHours{
+			Days: []string{"monday"}, Start: "09:00", End: "18:00",
+		}},
+	}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
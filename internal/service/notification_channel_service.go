@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// notificationChannelService 团队通知渠道服务实现
+type notificationChannelService struct {
+	repoManager repository.RepositoryManager
+	tester      ChannelTester
+	logger      *zap.Logger
+}
+
+// NewNotificationChannelService 创建团队通知渠道服务实例，tester 使用渠道配置发送测试消息
+func NewNotificationChannelService(repoManager repository.RepositoryManager, tester ChannelTester, logger *zap.Logger) NotificationChannelService {
+	return &notificationChannelService{
+		repoManager: repoManager,
+		tester:      tester,
+		logger:      logger,
+	}
+}
+
+// Create 创建团队通知渠道，返回的渠道不包含地址和密钥
+func (s *notificationChannelService) Create(ctx context.Context, req *models.NotificationChannelRequest, userID string) (*models.NotificationChannel, error) {
+	channel := &models.NotificationChannel{Enabled: true}
+	req.ApplyTo(channel)
+	if err := channel.Validate(); err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		channel.CreatedBy = &userID
+	}
+
+	if err := s.repoManager.NotificationChannel().Create(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建团队通知渠道", zap.String("channel_id", channel.ID), zap.String("team", channel.Team),
+		zap.String("type", string(channel.Type)), zap.String("created_by", userID))
+	channel.Redact()
+	return channel, nil
+}
+
+// Get 获取团队通知渠道，不返回地址和密钥
+func (s *notificationChannelService) Get(ctx context.Context, id string) (*models.NotificationChannel, error) {
+	channel, err := s.repoManager.NotificationChannel().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	channel.Redact()
+	return channel, nil
+}
+
+// List 按团队和类型获取通知渠道，不返回地址和密钥
+func (s *notificationChannelService) List(ctx context.Context, filter *models.NotificationChannelFilter) ([]*models.NotificationChannel, error) {
+	channels, err := s.repoManager.NotificationChannel().List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range channels {
+		channel.Redact()
+	}
+	return channels, nil
+}
+
+// Update 更新团队通知渠道，请求中未提供的地址和密钥保持不变
+func (s *notificationChannelService) Update(ctx context.Context, id string, req *models.NotificationChannelRequest) (*models.NotificationChannel, error) {
+	channel, err := s.repoManager.NotificationChannel().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(channel)
+	if err := channel.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.NotificationChannel().Update(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("更新团队通知渠道", zap.String("channel_id", channel.ID), zap.String("team", channel.Team),
+		zap.String("type", string(channel.Type)), zap.Bool("enabled", channel.Enabled))
+	channel.Redact()
+	return channel, nil
+}
+
+// Delete 删除团队通知渠道，删除后团队告警的通知使用全局渠道
+func (s *notificationChannelService) Delete(ctx context.Context, id string) error {
+	if err := s.repoManager.NotificationChannel().Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("删除团队通知渠道", zap.String("channel_id", id))
+	return nil
+}
+
+// Test 使用渠道的配置发送测试消息，停用的渠道同样可以测试
+func (s *notificationChannelService) Test(ctx context.Context, id, recipient string) (*models.ChannelTestResult, error) {
+	channel, err := s.repoManager.NotificationChannel().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.tester.TestChannelConfig(ctx, channel, recipient)
+}
+
+// teamChannel 获取通知关联告警的团队启用的同类型渠道，没有关联告警、team 标签或团队渠道时返回 nil，使用全局渠道。
+// Webhook 通知的接收者为 HTTP 地址时直接发送到该地址，不使用团队渠道
+func (s *notificationService) teamChannel(ctx context.Context, notification *models.Notification) *models.NotificationChannel {
+	if notification.AlertID == uuid.Nil || !models.IsNotificationChannelType(notification.Type) {
+		return nil
+	}
+	if notification.Type == models.NotificationTypeWebhook {
+		if target, err := url.Parse(notification.Recipient); err == nil && target.Host != "" {
+			return nil
+		}
+	}
+
+	alert, err := s.repoManager.Alert().GetByID(ctx, notification.AlertID.String())
+	if err != nil || alert.Labels[models.TeamLabel] == "" {
+		return nil
+	}
+	team := alert.Labels[models.TeamLabel]
+	channel, err := s.repoManager.NotificationChannel().FindEnabled(ctx, team, notification.Type)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotificationChannelNotFound) {
+			s.logger.Warn("获取团队通知渠道失败，使用全局渠道", zap.Error(err), zap.String("team", team))
+		}
+		return nil
+	}
+	return channel
+}
+
+// deliverToChannel 通过团队渠道发送通知，返回渠道的响应
+func (s *notificationService) deliverToChannel(ctx context.Context, channel *models.NotificationChannel, notification *models.Notification) (string, error) {
+	switch channel.Type {
+	case models.NotificationTypeEmail:
+		return "", s.sendChannelEmail(ctx, channel, notification)
+	case models.NotificationTypeWebhook:
+		target := *notification
+		target.Recipient = channel.URL
+		return s.sendWebhook(ctx, &target)
+	}
+
+	target, err := url.Parse(channel.URL)
+	if err != nil {
+		return "", fmt.Errorf("无效的渠道地址: %w", err)
+	}
+	if channel.Type == models.NotificationTypeDingTalk && channel.Secret != "" {
+		target = signDingTalkURL(target, channel.Secret, time.Now())
+	}
+	body, err := json.Marshal(channelPayload(notification))
+	if err != nil {
+		return "", fmt.Errorf("序列化通知消息失败: %w", err)
+	}
+
+	status, respBody, err := s.postChannel(ctx, channel.Type, target.String(), body)
+	if err != nil {
+		return "", err
+	}
+	response := fmt.Sprintf("HTTP %d", status)
+	if respBody != "" {
+		response += ": " + respBody
+	}
+	authErr, providerErr := classifyChannelResponse(channel.Type, status, respBody)
+	if authErr != nil {
+		return response, authErr
+	}
+	if providerErr == nil {
+		s.logger.Info("通过团队渠道发送通知", zap.String("channel_id", channel.ID), zap.String("team", channel.Team),
+			zap.String("type", string(channel.Type)))
+	}
+	return response, providerErr
+}
+
+// sendChannelEmail 通过团队渠道的 SMTP 服务器发送邮件，465 端口直接建立 TLS 连接，其他端口启用 TLS 时使用 STARTTLS
+func (s *notificationService) sendChannelEmail(ctx context.Context, channel *models.NotificationChannel, notification *models.Notification) error {
+	cfg := channel.SMTP
+	if cfg == nil || cfg.Host == "" {
+		return fmt.Errorf("邮件渠道 %s 未配置 SMTP 服务器", channel.Name)
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	ctx, cancel := context.WithTimeout(ctx, channelTestTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if cfg.TLS && port == 465 {
+		tlsConfig, tlsErr := s.channelTLSConfig(notification.Type, cfg.Host)
+		if tlsErr != nil {
+			return tlsErr
+		}
+		conn, err = (&tls.Dialer{NetDialer: &net.Dialer{}, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	client, err := newSMTPClient(ctx, conn, cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if cfg.TLS && port != 465 {
+		tlsConfig, err := s.channelTLSConfig(notification.Type, cfg.Host)
+		if err != nil {
+			return err
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS 失败: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, channel.Secret, cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP 登录失败: %w", err)
+		}
+	}
+	return sendSMTPMessage(client, cfg.From, notification)
+}
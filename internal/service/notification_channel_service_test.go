@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeNotificationChannelRepository 内存中的团队通知渠道，保存明文地址和密钥
+type fakeNotificationChannelRepository struct {
+	repository.NotificationChannelRepository
+	channels []*models.NotificationChannel
+}
+
+func (r *fakeNotificationChannelRepository) Create(ctx context.Context, channel *models.NotificationChannel) error {
+	channel.ID = uuid.New().String()
+	stored := *channel
+	r.channels = append(r.channels, &stored)
+	return nil
+}
+
+func (r *fakeNotificationChannelRepository) GetByID(ctx context.Context, id string) (*models.NotificationChannel, error) {
+	for _, channel := range r.channels {
+		if channel.ID == id {
+			stored := *channel
+			return &stored, nil
+		}
+	}
+	return nil, models.ErrNotificationChannelNotFound
+}
+
+func (r *fakeNotificationChannelRepository) FindEnabled(ctx context.Context, team string, channelType models.NotificationType) (*models.NotificationChannel, error) {
+	for _, channel := range r.channels {
+		if channel.Team == team && channel.Type == channelType && channel.Enabled {
+			stored := *channel
+			return &stored, nil
+		}
+	}
+	return nil, models.ErrNotificationChannelNotFound
+}
+
+func TestNotificationChannelService_CreateRedactsSecrets(t *testing.T) {
+	repoManager := newAlertSnoozeTestRepoManager()
+	repoManager.channels = &fakeNotificationChannelRepository{}
+	svc := NewNotificationChannelService(repoManager, nil, zap.NewNop())
+	ctx := context.Background()
+
+	channel, err := svc.Create(ctx, &models.NotificationChannelRequest{
+		Name:   "支付机器人",
+		Team:   "payments",
+		Type:   models.NotificationTypeDingTalk,
+		URL:    "https://oapi.dingtalk.com/robot/send?access_token=t",
+		Secret: "s",
+	}, "u1")
+	require.NoError(t, err)
+	assert.True(t, channel.Enabled)
+	assert.Empty(t, channel.URL)
+	assert.Empty(t, channel.Secret)
+	assert.Equal(t, "oapi.dingtalk.com", channel.URLHost)
+	assert.True(t, channel.HasSecret)
+
+	// 仓储中保存地址和密钥，只有接口响应不包含
+	stored, err := repoManager.channels.GetByID(ctx, channel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s", stored.Secret)
+
+	_, err = svc.Create(ctx, &models.NotificationChannelRequest{Name: "邮件", Team: "payments", Type: models.NotificationTypeEmail}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Create(ctx, &models.NotificationChannelRequest{Name: "短信", Team: "payments", Type: models.NotificationTypeSMS}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestNotificationService_DeliversThroughTeamChannel(t *testing.T) {
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	repoManager := newLocalizationTestRepoManager()
+	repoManager.channels = &fakeNotificationChannelRepository{channels: []*models.NotificationChannel{
+		{ID: "c1", Name: "支付机器人", Team: "payments", Type: models.NotificationTypeDingTalk,
+			URL: server.URL + "/robot/send?access_token=t", Secret: "s", Enabled: true},
+	}}
+	paymentsAlert, searchAlert := uuid.New(), uuid.New()
+	repoManager.alerts.alerts[paymentsAlert.String()] = &models.Alert{ID: paymentsAlert.String(),
+		Labels: map[string]string{models.TeamLabel: "payments"}}
+	repoManager.alerts.alerts[searchAlert.String()] = &models.Alert{ID: searchAlert.String(),
+		Labels: map[string]string{models.TeamLabel: "search"}}
+
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	// 团队配置了钉钉渠道时通过团队机器人发送并加签
+	require.NoError(t, svc.Send(ctx, &models.Notification{
+		AlertID: paymentsAlert, Type: models.NotificationTypeDingTalk, Recipient: "alice", Subject: "磁盘空间不足", Content: "磁盘使用率 95%",
+	}))
+	require.Len(t, requests, 1)
+	assert.Equal(t, "t", requests[0].Get("access_token"))
+	assert.NotEmpty(t, requests[0].Get("sign"))
+	require.Len(t, repoManager.attempts.attempts, 1)
+	assert.Equal(t, models.NotificationAttemptSent, repoManager.attempts.attempts[0].Status)
+	require.NotNil(t, repoManager.attempts.attempts[0].ProviderResponse)
+	assert.Contains(t, *repoManager.attempts.attempts[0].ProviderResponse, "HTTP 200")
+
+	// 没有团队渠道的告警使用全局渠道
+	require.NoError(t, svc.Send(ctx, &models.Notification{
+		AlertID: searchAlert, Type: models.NotificationTypeDingTalk, Recipient: "bob", Subject: "磁盘空间不足", Content: "磁盘使用率 95%",
+	}))
+	assert.Len(t, requests, 1)
+}
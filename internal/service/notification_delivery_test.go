@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeNotificationAttemptRepository 按保存顺序记录通知投递
+type fakeNotificationAttemptRepository struct {
+	repository.NotificationAttemptRepository
+	attempts []*models.NotificationAttempt
+}
+
+func (r *fakeNotificationAttemptRepository) Create(ctx context.Context, attempt *models.NotificationAttempt) error {
+	attempt.ID = uuid.New().String()
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeNotificationAttemptRepository) ListByNotification(ctx context.Context, notificationID string) ([]*models.NotificationAttempt, error) {
+	attempts := []*models.NotificationAttempt{}
+	for _, attempt := range r.attempts {
+		if attempt.NotificationID == notificationID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func (r *fakeNotificationAttemptRepository) ListByAlert(ctx context.Context, alertID string) ([]*models.NotificationAttempt, error) {
+	attempts := []*models.NotificationAttempt{}
+	for _, attempt := range r.attempts {
+		if attempt.AlertID != nil && *attempt.AlertID == alertID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func TestNotificationService_RecordsAttemptsAndResends(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(strings.Repeat("x", models.NotificationProviderResponseMaxLength+100)))
+	}))
+	defer server.Close()
+
+	repoManager := newLocalizationTestRepoManager()
+	alertID := uuid.New()
+	repoManager.alerts.alerts[alertID.String()] = &models.Alert{ID: alertID.String(), Severity: models.AlertSeverityCritical}
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+
+	notification := &models.Notification{
+		AlertID: alertID, Type: models.NotificationTypeWebhook, Recipient: server.URL,
+		Subject: "APIDown", Content: "checkout 接口不可用",
+	}
+	require.Error(t, svc.Send(ctx, notification))
+
+	// 失败的投递记录渠道响应，过长的响应体截断
+	attempts := repoManager.attempts.attempts
+	require.Len(t, attempts, 1)
+	failed := attempts[0]
+	assert.Equal(t, models.NotificationAttemptFailed, failed.Status)
+	assert.Equal(t, notification.ID.String(), failed.NotificationID)
+	assert.Equal(t, alertID.String(), *failed.AlertID)
+	assert.Equal(t, server.URL, failed.Target)
+	assert.True(t, strings.HasPrefix(*failed.ProviderResponse, "HTTP 502: xxx"))
+	assert.LessOrEqual(t, len(*failed.ProviderResponse), models.NotificationProviderResponseMaxLength+len("..."))
+	assert.NotNil(t, failed.Error)
+	assert.Nil(t, failed.ResentBy)
+
+	// 手动重发按原内容投递，追加投递记录并更新通知状态
+	status = http.StatusOK
+	attempt, err := svc.Resend(ctx, notification.ID.String(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, models.NotificationAttemptSent, attempt.Status)
+	assert.Equal(t, "u1", *attempt.ResentBy)
+	assert.Equal(t, failed.PayloadHash, attempt.PayloadHash)
+	assert.Equal(t, models.NotificationStatusSent, notification.Status)
+	assert.Equal(t, 1, notification.RetryCount)
+
+	deliveries, err := svc.ListAlertDeliveries(ctx, alertID.String())
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Len(t, deliveries[0].Attempts, 2)
+
+	_, err = svc.Resend(ctx, uuid.New().String(), "u1")
+	assert.ErrorIs(t, err, models.ErrNotificationNotFound)
+	_, err = svc.GetDelivery(ctx, "not-a-uuid")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
@@ -0,0 +1,521 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+)
+
+// channelTestModeResponse 测试模式的渠道记录在投递记录中的渠道响应
+const channelTestModeResponse = "test mode: delivery skipped"
+
+// channelTestTimeout 渠道测试中 DNS 解析、TLS 握手和 SMTP 会话的超时
+const channelTestTimeout = 10 * time.Second
+
+// channelTestMessageKey 渠道测试消息的多语言消息标识
+const channelTestMessageKey = "notification.channel_test"
+
+// TestChannel 向渠道发送一条固定内容的测试消息，依次检查 DNS 解析、TLS 握手、认证和渠道响应，
+// 某一步失败后跳过后续步骤。测试总是实际发送，不受渠道测试模式影响；检查失败记录在结果中而不是返回错误。
+// 钉钉、企业微信和 Slack 的接收者为机器人地址，为空时使用配置的地址
+func (s *notificationService) TestChannel(ctx context.Context, channel models.NotificationType, recipient string) (*models.ChannelTestResult, error) {
+	if !models.IsNotificationType(channel) {
+		return nil, fmt.Errorf("%w: 不支持的通知类型: %s", models.ErrInvalidInput, channel)
+	}
+	return s.testChannel(ctx, channel, s.configChannel(channel), recipient)
+}
+
+// TestChannelConfig 使用团队通知渠道的地址、密钥和 SMTP 服务器发送测试消息，检查步骤与 TestChannel 相同。
+// 机器人和 Webhook 渠道的接收者为空时使用渠道地址
+func (s *notificationService) TestChannelConfig(ctx context.Context, channel *models.NotificationChannel, recipient string) (*models.ChannelTestResult, error) {
+	return s.testChannel(ctx, channel.Type, channel, recipient)
+}
+
+// testChannel 按 settings 中的地址、密钥和 SMTP 服务器测试渠道
+func (s *notificationService) testChannel(ctx context.Context, channel models.NotificationType, settings *models.NotificationChannel, recipient string) (*models.ChannelTestResult, error) {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" && channel != models.NotificationTypeEmail {
+		recipient = settings.URL
+	}
+	if recipient == "" {
+		return nil, fmt.Errorf("%w: 测试 %s 渠道需要指定接收者", models.ErrInvalidInput, channel)
+	}
+
+	now := time.Now()
+	notification := &models.Notification{
+		ID:         uuid.New(),
+		Type:       channel,
+		Recipient:  recipient,
+		MessageKey: channelTestMessageKey,
+		MessageData: map[string]interface{}{
+			"Channel": string(channel),
+			"Time":    now.Format(time.RFC3339),
+		},
+		CreatedAt: now,
+	}
+	s.localize(ctx, notification)
+	if notification.Content == "" {
+		notification.Subject, notification.Content = "Pulse", "Pulse notification channel test"
+	}
+
+	result := &models.ChannelTestResult{
+		Channel:   channel,
+		Recipient: recipient,
+		TestMode:  s.testMode[channel],
+		TestedAt:  now,
+	}
+	probe := newChannelProbe(result)
+
+	switch channel {
+	case models.NotificationTypeEmail:
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return nil, fmt.Errorf("%w: 邮件的接收者必须是邮箱地址: %s", models.ErrInvalidInput, recipient)
+		}
+		if settings.SMTP == nil || settings.SMTP.Host == "" {
+			return nil, fmt.Errorf("%w: 未配置 SMTP 服务器", models.ErrInvalidInput)
+		}
+		s.probeSMTP(ctx, probe, notification, settings)
+	case models.NotificationTypeWebhook, models.NotificationTypeDingTalk, models.NotificationTypeWeChat, models.NotificationTypeSlack:
+		target, err := url.Parse(recipient)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return nil, fmt.Errorf("%w: %s 渠道的接收者必须是HTTP地址", models.ErrInvalidInput, channel)
+		}
+		result.Recipient = target.Redacted()
+		s.probeHTTP(ctx, probe, notification, target, settings.Secret)
+	default:
+		// 短信和推送没有可单独检查的连接，只检查渠道是否接受消息
+		probe.skip(models.ChannelTestStepDNS, models.ChannelTestStepTLS, models.ChannelTestStepAuth)
+		started := time.Now()
+		response, err := s.deliver(ctx, notification)
+		probe.record(models.ChannelTestStepProvider, started, response, err)
+		result.Response = response
+	}
+
+	result.Success = result.Error == ""
+	if result.Response != "" {
+		result.Response = models.TruncateProviderResponse(result.Response)
+	}
+	s.logger.Info("通知渠道测试完成", zap.String("channel", string(channel)), zap.Bool("success", result.Success),
+		zap.String("error", result.Error))
+	return result, nil
+}
+
+// configChannel 配置文件中的全局渠道，机器人类渠道为机器人地址和钉钉加签密钥，邮件为 SMTP 服务器和登录密码
+func (s *notificationService) configChannel(channel models.NotificationType) *models.NotificationChannel {
+	settings := &models.NotificationChannel{Type: channel}
+	switch channel {
+	case models.NotificationTypeEmail:
+		settings.Secret = s.channels.SMTP.Password
+		if s.channels.SMTP.Host != "" {
+			settings.SMTP = &models.NotificationChannelSMTP{
+				Host:     s.channels.SMTP.Host,
+				Port:     s.channels.SMTP.Port,
+				Username: s.channels.SMTP.Username,
+				From:     s.channels.SMTP.From,
+				TLS:      s.channels.SMTP.TLS,
+			}
+		}
+	case models.NotificationTypeDingTalk:
+		settings.URL, settings.Secret = s.channels.DingTalk.WebhookURL, s.channels.DingTalk.Secret
+	case models.NotificationTypeWeChat:
+		settings.URL = s.channels.WeCom.WebhookURL
+	case models.NotificationTypeSlack:
+		settings.URL = s.channels.Slack.WebhookURL
+	}
+	return settings
+}
+
+// probeHTTP 检查 HTTP 类渠道：解析域名，https 地址单独完成一次 TLS 握手，然后按渠道的消息格式发送测试消息。
+// 401 和 403 以及钉钉、企业微信的签名和密钥错误码视为认证失败，其他非 2xx 状态码和非零错误码视为渠道错误。
+// 配置了代理时由代理解析地址，DNS 检查失败不代表实际投递一定失败
+func (s *notificationService) probeHTTP(ctx context.Context, probe *channelProbe, notification *models.Notification, target *url.URL, secret string) {
+	host := target.Hostname()
+	if !probe.run(models.ChannelTestStepDNS, func() (string, error) { return s.resolveHost(ctx, host) }) {
+		return
+	}
+	if target.Scheme == "https" {
+		port := target.Port()
+		if port == "" {
+			port = "443"
+		}
+		if !probe.run(models.ChannelTestStepTLS, func() (string, error) {
+			return s.checkTLS(ctx, notification.Type, net.JoinHostPort(host, port), host)
+		}) {
+			return
+		}
+	} else {
+		probe.skipWith(models.ChannelTestStepTLS, "非 HTTPS 地址")
+	}
+
+	if notification.Type == models.NotificationTypeDingTalk && secret != "" {
+		target = signDingTalkURL(target, secret, time.Now())
+	}
+	body, err := json.Marshal(channelPayload(notification))
+	if err != nil {
+		probe.record(models.ChannelTestStepProvider, time.Now(), "", fmt.Errorf("序列化测试消息失败: %w", err))
+		return
+	}
+
+	started := time.Now()
+	status, respBody, err := s.postChannel(ctx, notification.Type, target.String(), body)
+	if err != nil {
+		probe.skipWith(models.ChannelTestStepAuth, "未收到渠道响应")
+		probe.record(models.ChannelTestStepProvider, started, "", err)
+		return
+	}
+	probe.result.Response = fmt.Sprintf("HTTP %d", status)
+	if respBody != "" {
+		probe.result.Response += ": " + respBody
+	}
+
+	authErr, providerErr := classifyChannelResponse(notification.Type, status, respBody)
+	if !probe.record(models.ChannelTestStepAuth, started, fmt.Sprintf("HTTP %d", status), authErr) {
+		return
+	}
+	probe.record(models.ChannelTestStepProvider, started, fmt.Sprintf("HTTP %d", status), providerErr)
+}
+
+// postChannel 使用渠道的 HTTP 客户端发送 JSON 请求，返回响应的状态码和响应体
+func (s *notificationService) postChannel(ctx context.Context, channel models.NotificationType, target string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("创建渠道请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client, err := s.httpClients.Channel(string(channel))
+	if err != nil {
+		return 0, "", fmt.Errorf("创建 %s 客户端失败: %w", channel, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("发送渠道消息失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, models.NotificationProviderResponseMaxLength+1))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, strings.TrimSpace(string(respBody)), nil
+}
+
+// channelPayload 按渠道的消息格式构造消息，Webhook 消息标记为测试消息
+func channelPayload(notification *models.Notification) interface{} {
+	text := notification.Content
+	if notification.Subject != "" {
+		text = notification.Subject + "\n" + notification.Content
+	}
+	switch notification.Type {
+	case models.NotificationTypeDingTalk, models.NotificationTypeWeChat:
+		return map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": text}}
+	case models.NotificationTypeSlack:
+		return map[string]string{"text": text}
+	default:
+		return map[string]interface{}{
+			"id":      notification.ID,
+			"subject": notification.Subject,
+			"content": notification.Content,
+			"test":    true,
+		}
+	}
+}
+
+// channelAuthErrCodes 钉钉和企业微信机器人表示签名、关键词、IP 白名单或密钥不正确的错误码
+var channelAuthErrCodes = map[models.NotificationType]map[int]bool{
+	models.NotificationTypeDingTalk: {310000: true, 300001: true},
+	models.NotificationTypeWeChat:   {40001: true, 40014: true, 93000: true},
+}
+
+// classifyChannelResponse 按状态码和机器人接口的错误码区分认证错误和渠道错误
+func classifyChannelResponse(channel models.NotificationType, status int, body string) (authErr, providerErr error) {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return fmt.Errorf("渠道拒绝认证，状态码: %d", status), nil
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("渠道返回错误，状态码: %d", status)
+	}
+	if _, ok := channelAuthErrCodes[channel]; !ok {
+		return nil, nil
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if json.Unmarshal([]byte(body), &result) != nil || result.ErrCode == 0 {
+		return nil, nil
+	}
+	err := fmt.Errorf("渠道返回错误码 %d: %s", result.ErrCode, result.ErrMsg)
+	if channelAuthErrCodes[channel][result.ErrCode] {
+		return err, nil
+	}
+	return nil, err
+}
+
+// signDingTalkURL 为钉钉机器人地址附加加签参数
+func signDingTalkURL(target *url.URL, secret string, now time.Time) *url.URL {
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+
+	signed := *target
+	query := signed.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	signed.RawQuery = query.Encode()
+	return &signed
+}
+
+// probeSMTP 检查邮件渠道：解析 SMTP 服务器，465 端口直接建立 TLS 连接，其他端口启用 SMTP_TLS 时使用 STARTTLS，
+// 配置了用户名时登录，最后向接收者发送测试邮件
+func (s *notificationService) probeSMTP(ctx context.Context, probe *channelProbe, notification *models.Notification, settings *models.NotificationChannel) {
+	cfg := settings.SMTP
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	if !probe.run(models.ChannelTestStepDNS, func() (string, error) { return s.resolveHost(ctx, cfg.Host) }) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, channelTestTimeout)
+	defer cancel()
+
+	var client *smtp.Client
+	started := time.Now()
+	if cfg.TLS && port == 465 {
+		tlsConfig, err := s.channelTLSConfig(notification.Type, cfg.Host)
+		if err != nil {
+			probe.record(models.ChannelTestStepTLS, started, "", err)
+			return
+		}
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{}, Config: tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			probe.record(models.ChannelTestStepTLS, started, "", fmt.Errorf("TLS 连接 SMTP 服务器失败: %w", err))
+			return
+		}
+		if client, err = newSMTPClient(ctx, conn, cfg.Host); err != nil {
+			probe.record(models.ChannelTestStepTLS, started, "", err)
+			return
+		}
+		probe.record(models.ChannelTestStepTLS, started, tlsStateDetail(conn.(*tls.Conn).ConnectionState()), nil)
+	} else {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			probe.skip(models.ChannelTestStepTLS, models.ChannelTestStepAuth)
+			probe.record(models.ChannelTestStepProvider, started, "", fmt.Errorf("连接 SMTP 服务器失败: %w", err))
+			return
+		}
+		if client, err = newSMTPClient(ctx, conn, cfg.Host); err != nil {
+			probe.skip(models.ChannelTestStepTLS, models.ChannelTestStepAuth)
+			probe.record(models.ChannelTestStepProvider, started, "", err)
+			return
+		}
+		if cfg.TLS {
+			ok := probe.run(models.ChannelTestStepTLS, func() (string, error) {
+				if supported, _ := client.Extension("STARTTLS"); !supported {
+					return "", errors.New("SMTP 服务器不支持 STARTTLS")
+				}
+				tlsConfig, err := s.channelTLSConfig(notification.Type, cfg.Host)
+				if err != nil {
+					return "", err
+				}
+				if err := client.StartTLS(tlsConfig); err != nil {
+					return "", fmt.Errorf("STARTTLS 失败: %w", err)
+				}
+				state, _ := client.TLSConnectionState()
+				return tlsStateDetail(state), nil
+			})
+			if !ok {
+				client.Close()
+				return
+			}
+		} else {
+			probe.skipWith(models.ChannelTestStepTLS, "未启用 SMTP_TLS")
+		}
+	}
+	defer client.Close()
+
+	if cfg.Username != "" {
+		ok := probe.run(models.ChannelTestStepAuth, func() (string, error) {
+			if err := client.Auth(smtp.PlainAuth("", cfg.Username, settings.Secret, cfg.Host)); err != nil {
+				return "", fmt.Errorf("SMTP 登录失败: %w", err)
+			}
+			return cfg.Username, nil
+		})
+		if !ok {
+			return
+		}
+	} else {
+		probe.skipWith(models.ChannelTestStepAuth, "未配置 SMTP_USERNAME")
+	}
+
+	probe.run(models.ChannelTestStepProvider, func() (string, error) {
+		return "", sendSMTPMessage(client, cfg.From, notification)
+	})
+}
+
+// newSMTPClient 在已建立的连接上创建 SMTP 客户端，会话使用 ctx 的截止时间
+func newSMTPClient(ctx context.Context, conn net.Conn, host string) (*smtp.Client, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP 握手失败: %w", err)
+	}
+	return client, nil
+}
+
+// sendSMTPMessage 在已登录的 SMTP 会话中发送邮件
+func sendSMTPMessage(client *smtp.Client, from string, notification *models.Notification) error {
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("SMTP 服务器拒绝发件人: %w", err)
+	}
+	if err := client.Rcpt(notification.Recipient); err != nil {
+		return fmt.Errorf("SMTP 服务器拒绝收件人: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP 服务器拒绝邮件内容: %w", err)
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		from, notification.Recipient, notification.Subject, notification.Content)
+	if _, err := w.Write([]byte(message)); err != nil {
+		return fmt.Errorf("写入邮件内容失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP 服务器拒绝邮件: %w", err)
+	}
+	return client.Quit()
+}
+
+// resolveHost 解析主机名，IP 地址直接返回
+func (s *notificationService) resolveHost(ctx context.Context, host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, channelTestTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("解析 %s 失败: %w", host, err)
+	}
+	return strings.Join(addrs, ", "), nil
+}
+
+// checkTLS 使用渠道的证书配置与 addr 完成一次 TLS 握手
+func (s *notificationService) checkTLS(ctx context.Context, channel models.NotificationType, addr, serverName string) (string, error) {
+	tlsConfig, err := s.channelTLSConfig(channel, serverName)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, channelTestTimeout)
+	defer cancel()
+	conn, err := (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("TLS 握手失败: %w", err)
+	}
+	defer conn.Close()
+	return tlsStateDetail(conn.(*tls.Conn).ConnectionState()), nil
+}
+
+// channelTLSConfig 获取渠道的 TLS 配置，未配置证书相关选项时使用系统根证书
+func (s *notificationService) channelTLSConfig(channel models.NotificationType, serverName string) (*tls.Config, error) {
+	tlsConfig, err := s.httpClients.ChannelTLSConfig(string(channel))
+	if err != nil {
+		return nil, fmt.Errorf("渠道的 TLS 配置无效: %w", err)
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.ServerName = serverName
+	return tlsConfig, nil
+}
+
+// tlsStateDetail 描述 TLS 连接的协议版本和服务端证书
+func tlsStateDetail(state tls.ConnectionState) string {
+	detail := tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		detail += fmt.Sprintf("，证书 %s 有效期至 %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+	}
+	return detail
+}
+
+// channelProbe 按 DNS、TLS、认证、渠道响应的顺序记录渠道测试的检查结果，某一步失败后其余步骤保持跳过
+type channelProbe struct {
+	result *models.ChannelTestResult
+	failed bool
+}
+
+func newChannelProbe(result *models.ChannelTestResult) *channelProbe {
+	for _, step := range []models.ChannelTestStep{
+		models.ChannelTestStepDNS, models.ChannelTestStepTLS, models.ChannelTestStepAuth, models.ChannelTestStepProvider,
+	} {
+		result.Checks = append(result.Checks, &models.ChannelTestCheck{Step: step, Status: models.ChannelTestSkipped})
+	}
+	return &channelProbe{result: result}
+}
+
+// run 执行检查步骤并记录结果，返回是否通过。之前的步骤已失败时不执行
+func (p *channelProbe) run(step models.ChannelTestStep, check func() (string, error)) bool {
+	if p.failed {
+		return false
+	}
+	started := time.Now()
+	detail, err := check()
+	return p.record(step, started, detail, err)
+}
+
+// record 记录检查步骤的结果，返回是否通过。第一个失败的步骤的错误作为测试结果的错误
+func (p *channelProbe) record(step models.ChannelTestStep, started time.Time, detail string, err error) bool {
+	if p.failed {
+		return false
+	}
+	check := p.result.Check(step)
+	check.DurationMs = time.Since(started).Milliseconds()
+	check.Status, check.Detail = models.ChannelTestOK, detail
+	if err != nil {
+		check.Status, check.Detail = models.ChannelTestFailed, err.Error()
+		p.result.Error = err.Error()
+		p.failed = true
+		return false
+	}
+	return true
+}
+
+// skip 将渠道不涉及的步骤标记为跳过
+func (p *channelProbe) skip(steps ...models.ChannelTestStep) {
+	for _, step := range steps {
+		p.result.Check(step).Status = models.ChannelTestSkipped
+	}
+}
+
+// skipWith 将步骤标记为跳过并说明原因
+func (p *channelProbe) skipWith(step models.ChannelTestStep, reason string) {
+	check := p.result.Check(step)
+	check.Status, check.Detail = models.ChannelTestSkipped, reason
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+func TestNotificationService_TestChannelReportsChecks(t *testing.T) {
+	ctx := context.Background()
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cfg := config.NotificationConfig{TestModeChannels: []string{" webhook"}}
+	svc := NewNotificationService(newLocalizationTestRepoManager(), testTranslator(t), nil, nil, nil, cfg, zap.NewNop())
+
+	result, err := svc.TestChannel(ctx, models.NotificationTypeWebhook, server.URL)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, result.TestMode)
+	assert.Equal(t, "HTTP 200: ok", result.Response)
+	assert.Equal(t, models.ChannelTestOK, result.Check(models.ChannelTestStepDNS).Status)
+	assert.Equal(t, models.ChannelTestSkipped, result.Check(models.ChannelTestStepTLS).Status)
+	assert.Equal(t, models.ChannelTestOK, result.Check(models.ChannelTestStepAuth).Status)
+	assert.Equal(t, models.ChannelTestOK, result.Check(models.ChannelTestStepProvider).Status)
+
+	// 测试消息按接收者语言渲染，测试模式不影响渠道测试的实际发送
+	assert.Equal(t, true, received["test"])
+	assert.Contains(t, received["subject"], "webhook")
+
+	_, err = svc.TestChannel(ctx, "pager", "x")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.TestChannel(ctx, models.NotificationTypeSlack, "")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestNotificationService_TestChannelClassifiesFailures(t *testing.T) {
+	ctx := context.Background()
+	var query map[string][]string
+	status, body := http.StatusOK, `{"errcode":310000,"errmsg":"sign not match"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := config.NotificationConfig{DingTalk: config.DingTalkConfig{WebhookURL: server.URL + "/robot/send?access_token=t", Secret: "s"}}
+	svc := NewNotificationService(newLocalizationTestRepoManager(), testTranslator(t), nil, nil, nil, cfg, zap.NewNop())
+
+	// 未指定接收者时使用配置的机器人地址并加签，签名错误码视为认证失败
+	result, err := svc.TestChannel(ctx, models.NotificationTypeDingTalk, "")
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, query, "sign")
+	assert.Equal(t, "t", query["access_token"][0])
+	assert.Equal(t, models.ChannelTestFailed, result.Check(models.ChannelTestStepAuth).Status)
+	assert.Equal(t, models.ChannelTestSkipped, result.Check(models.ChannelTestStepProvider).Status)
+	assert.Contains(t, result.Error, "310000")
+
+	// 其他错误码和非 2xx 状态码视为渠道错误
+	body = `{"errcode":130101,"errmsg":"send too fast"}`
+	result, err = svc.TestChannel(ctx, models.NotificationTypeDingTalk, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.ChannelTestOK, result.Check(models.ChannelTestStepAuth).Status)
+	assert.Equal(t, models.ChannelTestFailed, result.Check(models.ChannelTestStepProvider).Status)
+
+	status, body = http.StatusForbidden, "invalid_token"
+	result, err = svc.TestChannel(ctx, models.NotificationTypeSlack, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, models.ChannelTestFailed, result.Check(models.ChannelTestStepAuth).Status)
+	assert.Equal(t, "HTTP 403: invalid_token", result.Response)
+
+	// 连接失败时没有渠道响应，记录为渠道错误
+	server.Close()
+	result, err = svc.TestChannel(ctx, models.NotificationTypeWebhook, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, models.ChannelTestSkipped, result.Check(models.ChannelTestStepAuth).Status)
+	assert.Equal(t, models.ChannelTestFailed, result.Check(models.ChannelTestStepProvider).Status)
+}
+
+func TestNotificationService_TestModeSkipsDelivery(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	repoManager := newLocalizationTestRepoManager()
+	cfg := config.NotificationConfig{TestModeChannels: []string{"webhook"}}
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, cfg, zap.NewNop())
+
+	notification := &models.Notification{Type: models.NotificationTypeWebhook, Recipient: server.URL, Content: "磁盘使用率过高"}
+	require.NoError(t, svc.Send(ctx, notification))
+	assert.Zero(t, calls)
+	assert.Equal(t, models.NotificationStatusSent, notification.Status)
+
+	attempts := repoManager.attempts.attempts
+	require.Len(t, attempts, 1)
+	assert.Equal(t, models.NotificationAttemptSent, attempts[0].Status)
+	assert.Equal(t, channelTestModeResponse, *attempts[0].ProviderResponse)
+}
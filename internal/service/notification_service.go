@@ -1,27 +1,60 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"pulse/internal/config"
 	"pulse/internal/models"
+	"pulse/internal/pkg/actionlink"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/i18n"
+	"pulse/internal/push"
 	"pulse/internal/repository"
 )
 
 // notificationService 通知服务实现
 type notificationService struct {
 	repoManager repository.RepositoryManager
+	translator  *i18n.Bundle
+	httpClients *httpclient.Factory
+	links       AlertActionLinker
+	pusher      PushSender
+	channels    config.NotificationConfig
+	testMode    map[models.NotificationType]bool
 	logger      *zap.Logger
 }
 
-// NewNotificationService 创建通知服务实例
-func NewNotificationService(repoManager repository.RepositoryManager, logger *zap.Logger) NotificationService {
+// NewNotificationService 创建通知服务实例，translator 用于按接收人的语言渲染多语言消息，
+// httpClients 按通知渠道的超时和重试策略创建 HTTP 客户端，为 nil 时使用默认策略，
+// links 为告警通知生成一键操作链接，为 nil 时不附加链接，pusher 发送移动端推送通知，
+// cfg 提供各渠道的连接配置和处于测试模式的渠道
+func NewNotificationService(repoManager repository.RepositoryManager, translator *i18n.Bundle, httpClients *httpclient.Factory, links AlertActionLinker, pusher PushSender, cfg config.NotificationConfig, logger *zap.Logger) NotificationService {
+	testMode := make(map[models.NotificationType]bool)
+	for _, channel := range cfg.TestModeChannels {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			testMode[models.NotificationType(channel)] = true
+		}
+	}
 	return &notificationService{
 		repoManager: repoManager,
+		translator:  translator,
+		httpClients: httpClients,
+		links:       links,
+		pusher:      pusher,
+		channels:    cfg,
+		testMode:    testMode,
 		logger:      logger,
 	}
 }
@@ -39,10 +72,38 @@ func (s *notificationService) Send(ctx context.Context, notification *models.Not
 	if notification.Recipient == "" {
 		return fmt.Errorf("接收者不能为空")
 	}
+	s.localize(ctx, notification)
 	if notification.Content == "" {
 		return fmt.Errorf("通知内容不能为空")
 	}
 
+	// 接收人暂停了关联的告警时不发送
+	if s.snoozedByRecipient(ctx, notification) {
+		s.logger.Info("接收人已暂停该告警，跳过通知", zap.String("recipient", notification.Recipient),
+			zap.String("alert_id", notification.AlertID.String()))
+		return nil
+	}
+
+	// 接收人处于免打扰时段或临时免打扰中，且不允许该级别的告警时不发送
+	if s.quietForRecipient(ctx, notification) {
+		return nil
+	}
+
+	// 接收人的推送偏好不包含该级别的告警时不推送
+	if !s.pushWanted(ctx, notification) {
+		s.logger.Debug("接收人未订阅该级别告警的推送，跳过通知", zap.String("recipient", notification.Recipient),
+			zap.String("alert_id", notification.AlertID.String()))
+		return nil
+	}
+
+	// 非工作时间的低级别告警通知延迟到工作时间汇总发送
+	if s.deferOutsideBusinessHours(ctx, notification) {
+		return nil
+	}
+
+	// 告警通知附加接收人专属的确认、解决和暂停链接
+	s.appendActionLinks(ctx, notification)
+
 	// 设置默认值
 	if notification.ID == uuid.Nil {
 		notification.ID = uuid.New()
@@ -63,24 +124,10 @@ func (s *notificationService) Send(ctx context.Context, notification *models.Not
 		return fmt.Errorf("保存通知记录失败: %w", err)
 	}
 
-	// 根据通知类型发送通知
-	var err error
-	switch notification.Type {
-	case models.NotificationTypeEmail:
-		err = s.sendEmail(ctx, notification)
-	case models.NotificationTypeSMS:
-		err = s.sendSMS(ctx, notification)
-	case models.NotificationTypeDingTalk:
-		err = s.sendDingTalk(ctx, notification)
-	case models.NotificationTypeWeChat:
-		err = s.sendWeChat(ctx, notification)
-	case models.NotificationTypeSlack:
-		err = s.sendSlack(ctx, notification)
-	case models.NotificationTypeWebhook:
-		err = s.sendWebhook(ctx, notification)
-	default:
-		err = fmt.Errorf("不支持的通知类型: %s", notification.Type)
-	}
+	// 根据通知类型发送通知，并记录本次投递
+	started := time.Now()
+	response, err := s.dispatch(ctx, notification)
+	s.recordAttempt(ctx, notification, started, response, err, nil)
 
 	// 更新通知状态
 	if err != nil {
@@ -102,6 +149,363 @@ func (s *notificationService) Send(ctx context.Context, notification *models.Not
 	return err
 }
 
+// dispatch 投递通知，处于测试模式的渠道只返回测试模式的响应，不实际投递
+func (s *notificationService) dispatch(ctx context.Context, notification *models.Notification) (string, error) {
+	if s.testMode[notification.Type] {
+		s.logger.Info("通知渠道处于测试模式，跳过投递", zap.String("notification_id", notification.ID.String()),
+			zap.String("channel", string(notification.Type)))
+		return channelTestModeResponse, nil
+	}
+	return s.deliver(ctx, notification)
+}
+
+// deliver 按通知类型发送通知，返回渠道的响应。告警所属团队配置了同类型的渠道时通过团队渠道发送
+func (s *notificationService) deliver(ctx context.Context, notification *models.Notification) (string, error) {
+	if channel := s.teamChannel(ctx, notification); channel != nil {
+		return s.deliverToChannel(ctx, channel, notification)
+	}
+
+	switch notification.Type {
+	case models.NotificationTypeEmail:
+		return "", s.sendEmail(ctx, notification)
+	case models.NotificationTypeSMS:
+		return "", s.sendSMS(ctx, notification)
+	case models.NotificationTypeDingTalk:
+		return "", s.sendDingTalk(ctx, notification)
+	case models.NotificationTypeWeChat:
+		return "", s.sendWeChat(ctx, notification)
+	case models.NotificationTypeSlack:
+		return "", s.sendSlack(ctx, notification)
+	case models.NotificationTypeWebhook:
+		return s.sendWebhook(ctx, notification)
+	case models.NotificationTypePush:
+		return "", s.sendPush(ctx, notification)
+	default:
+		return "", fmt.Errorf("不支持的通知类型: %s", notification.Type)
+	}
+}
+
+// recordAttempt 保存通知的投递记录，resentBy 为手动重发的用户。保存失败时只记录日志，不影响通知发送
+func (s *notificationService) recordAttempt(ctx context.Context, notification *models.Notification, started time.Time, response string, sendErr error, resentBy *string) *models.NotificationAttempt {
+	attempt := &models.NotificationAttempt{
+		NotificationID: notification.ID.String(),
+		Channel:        notification.Type,
+		Target:         notification.Recipient,
+		PayloadHash:    models.NotificationPayloadHash(notification),
+		Status:         models.NotificationAttemptSent,
+		DurationMs:     time.Since(started).Milliseconds(),
+		ResentBy:       resentBy,
+		AttemptedAt:    started,
+	}
+	if notification.AlertID != uuid.Nil {
+		alertID := notification.AlertID.String()
+		attempt.AlertID = &alertID
+	}
+	if notification.Type == models.NotificationTypeWebhook {
+		if target, err := url.Parse(notification.Recipient); err == nil {
+			attempt.Target = target.Redacted()
+		}
+	}
+	if response != "" {
+		response = models.TruncateProviderResponse(response)
+		attempt.ProviderResponse = &response
+	}
+	if sendErr != nil {
+		msg := sendErr.Error()
+		attempt.Status = models.NotificationAttemptFailed
+		attempt.Error = &msg
+	}
+
+	if err := s.repoManager.NotificationAttempt().Create(ctx, attempt); err != nil {
+		s.logger.Warn("保存通知投递记录失败", zap.Error(err), zap.String("notification_id", attempt.NotificationID))
+	}
+	return attempt
+}
+
+// Resend 手动重发已保存的通知，按原渠道和原内容立即投递，不再检查暂停、免打扰和通知日历，
+// 投递结果追加到通知的投递记录。投递失败时返回失败的投递记录而不是错误
+func (s *notificationService) Resend(ctx context.Context, notificationID, userID string) (*models.NotificationAttempt, error) {
+	notification, err := s.getNotification(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	response, sendErr := s.dispatch(ctx, notification)
+	attempt := s.recordAttempt(ctx, notification, started, response, sendErr, &userID)
+
+	notification.RetryCount++
+	if sendErr != nil {
+		msg := sendErr.Error()
+		notification.Status = models.NotificationStatusFailed
+		notification.LastError = &msg
+		s.logger.Warn("重发通知失败", zap.Error(sendErr), zap.String("notification_id", notificationID), zap.String("user_id", userID))
+	} else {
+		now := time.Now()
+		notification.Status = models.NotificationStatusSent
+		notification.SentAt = &now
+		s.logger.Info("通知重发成功", zap.String("notification_id", notificationID), zap.String("user_id", userID))
+	}
+	notification.UpdatedAt = time.Now()
+	if err := s.repoManager.Notification().Update(ctx, notification); err != nil {
+		s.logger.Error("更新通知状态失败", zap.Error(err), zap.String("notification_id", notificationID))
+	}
+	return attempt, nil
+}
+
+// GetDelivery 获取通知及其投递记录
+func (s *notificationService) GetDelivery(ctx context.Context, notificationID string) (*models.NotificationDelivery, error) {
+	notification, err := s.getNotification(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repoManager.NotificationAttempt().ListByNotification(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationDelivery{Notification: notification, Attempts: attempts}, nil
+}
+
+// ListAlertDeliveries 获取告警的全部通知及其投递记录，按通知创建时间倒序
+func (s *notificationService) ListAlertDeliveries(ctx context.Context, alertID string) ([]*models.NotificationDelivery, error) {
+	if _, err := uuid.Parse(alertID); err != nil {
+		return nil, fmt.Errorf("%w: 无效的告警ID %q", models.ErrInvalidInput, alertID)
+	}
+	if _, err := s.repoManager.Alert().GetByID(ctx, alertID); err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.repoManager.Notification().GetByAlertID(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警通知记录失败: %w", err)
+	}
+	attempts, err := s.repoManager.NotificationAttempt().ListByAlert(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	byNotification := make(map[string][]*models.NotificationAttempt, len(notifications))
+	for _, attempt := range attempts {
+		byNotification[attempt.NotificationID] = append(byNotification[attempt.NotificationID], attempt)
+	}
+	deliveries := make([]*models.NotificationDelivery, 0, len(notifications))
+	for _, notification := range notifications {
+		delivery := &models.NotificationDelivery{
+			Notification: notification,
+			Attempts:     byNotification[notification.ID.String()],
+		}
+		if delivery.Attempts == nil {
+			delivery.Attempts = []*models.NotificationAttempt{}
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// ListTicketDeliveries 获取工单关联告警的全部通知及其投递记录，工单未关联告警时返回空列表
+func (s *notificationService) ListTicketDeliveries(ctx context.Context, ticketID string) ([]*models.NotificationDelivery, error) {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.AlertID == nil || *ticket.AlertID == "" {
+		return []*models.NotificationDelivery{}, nil
+	}
+	return s.ListAlertDeliveries(ctx, *ticket.AlertID)
+}
+
+// getNotification 根据ID获取通知，通知不存在时返回 ErrNotificationNotFound
+func (s *notificationService) getNotification(ctx context.Context, id string) (*models.Notification, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: 无效的通知ID %q", models.ErrInvalidInput, id)
+	}
+	notification, err := s.repoManager.Notification().GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知失败: %w", err)
+	}
+	if notification == nil {
+		return nil, models.ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+// snoozedByRecipient 判断通知接收人是否暂停了通知关联的告警，接收人按邮箱或用户名对应到平台用户
+// 查询失败时按未暂停处理，避免漏发通知
+func (s *notificationService) snoozedByRecipient(ctx context.Context, notification *models.Notification) bool {
+	if notification.AlertID == uuid.Nil {
+		return false
+	}
+
+	user, err := s.recipientUser(ctx, notification.Recipient)
+	if err != nil {
+		return false
+	}
+
+	snoozes, err := s.repoManager.AlertSnooze().ListActiveByUser(ctx, user.ID, time.Now())
+	if err != nil {
+		s.logger.Warn("获取告警暂停失败", zap.Error(err), zap.String("user_id", user.ID))
+		return false
+	}
+	if len(snoozes) == 0 {
+		return false
+	}
+
+	alert, err := s.repoManager.Alert().GetByID(ctx, notification.AlertID.String())
+	if err != nil {
+		s.logger.Warn("获取通知关联的告警失败", zap.Error(err), zap.String("alert_id", notification.AlertID.String()))
+		return false
+	}
+	return alertSnoozed(snoozes, alert)
+}
+
+// quietForRecipient 判断通知接收人当前是否免打扰，与告警无关的通知在免打扰期间都不发送。
+// 接收人不是平台用户或查询失败时按未免打扰处理，避免漏发通知
+func (s *notificationService) quietForRecipient(ctx context.Context, notification *models.Notification) bool {
+	user, err := s.recipientUser(ctx, notification.Recipient)
+	if err != nil {
+		return false
+	}
+
+	quiet, err := s.repoManager.QuietHours().Get(ctx, user.ID)
+	if err != nil {
+		if !errors.Is(err, models.ErrQuietHoursNotFound) {
+			s.logger.Warn("获取免打扰设置失败", zap.Error(err), zap.String("user_id", user.ID))
+		}
+		return false
+	}
+
+	var severity models.AlertSeverity
+	if notification.AlertID != uuid.Nil {
+		alert, err := s.repoManager.Alert().GetByID(ctx, notification.AlertID.String())
+		if err != nil {
+			s.logger.Warn("获取通知关联的告警失败", zap.Error(err), zap.String("alert_id", notification.AlertID.String()))
+			return false
+		}
+		severity = alert.Severity
+	}
+	if !quiet.Suppresses(severity, time.Now()) {
+		return false
+	}
+
+	s.logger.Info("接收人处于免打扰中，跳过通知", zap.String("recipient", notification.Recipient),
+		zap.String("alert_id", notification.AlertID.String()), zap.String("severity", string(severity)))
+	return true
+}
+
+// localize 按接收人的语言渲染多语言消息，接收人未设置语言时使用告警所属团队的语言，都未设置时使用默认语言
+func (s *notificationService) localize(ctx context.Context, notification *models.Notification) {
+	if notification.MessageKey == "" || s.translator == nil {
+		return
+	}
+
+	var userID string
+	team := notification.Team
+	if user, err := s.recipientUser(ctx, notification.Recipient); err == nil {
+		userID = user.ID
+	}
+	if notification.AlertID != uuid.Nil {
+		if alert, err := s.repoManager.Alert().GetByID(ctx, notification.AlertID.String()); err == nil {
+			team = alert.Labels[models.TeamLabel]
+		}
+	}
+
+	locale, _ := resolveLocale(ctx, s.repoManager.LanguagePreference(), s.translator, userID, team)
+	notification.Subject = s.translator.Message(locale, notification.MessageKey+".subject", notification.MessageData)
+	if notification.Content == "" {
+		notification.Content = s.translator.Message(locale, notification.MessageKey+".content", notification.MessageData)
+	}
+}
+
+// appendActionLinks 接收人是平台用户时，在告警通知内容末尾附加以该用户身份执行操作的链接。
+// 生成链接失败时只记录日志，不影响通知发送
+func (s *notificationService) appendActionLinks(ctx context.Context, notification *models.Notification) {
+	// 推送内容中的链接无法点击，移动端应用按推送数据中的告警 ID 打开告警
+	if s.links == nil || s.translator == nil || notification.AlertID == uuid.Nil || notification.Type == models.NotificationTypePush {
+		return
+	}
+	user, err := s.recipientUser(ctx, notification.Recipient)
+	if err != nil {
+		return
+	}
+
+	alertID := notification.AlertID.String()
+	links, err := s.links.Links(ctx, alertID, user.ID)
+	if err != nil {
+		s.logger.Warn("生成告警操作链接失败", zap.Error(err), zap.String("alert_id", alertID))
+		return
+	}
+	if len(links) == 0 {
+		return
+	}
+
+	team := notification.Team
+	if alert, err := s.repoManager.Alert().GetByID(ctx, alertID); err == nil {
+		team = alert.Labels[models.TeamLabel]
+	}
+	locale, _ := resolveLocale(ctx, s.repoManager.LanguagePreference(), s.translator, user.ID, team)
+	notification.Content += "\n\n" + s.translator.Message(locale, "notification.action_links", map[string]interface{}{
+		"Ack":     links[actionlink.ActionAck],
+		"Resolve": links[actionlink.ActionResolve],
+		"Snooze":  links[actionlink.ActionSnooze],
+	})
+}
+
+// recipientUser 将通知接收人对应到平台用户，包含 @ 时按邮箱查找，否则按用户名查找
+func (s *notificationService) recipientUser(ctx context.Context, recipient string) (*models.User, error) {
+	if strings.Contains(recipient, "@") {
+		return s.repoManager.User().GetByEmail(ctx, recipient)
+	}
+	return s.repoManager.User().GetByUsername(ctx, recipient)
+}
+
+// deferOutsideBusinessHours 按告警标签选择最匹配的通知日历，非工作时间内需要延迟的通知保存为延迟通知，
+// 由汇总任务在下一个工作时间开始时发送。查询或保存失败时立即发送，避免漏发通知
+func (s *notificationService) deferOutsideBusinessHours(ctx context.Context, notification *models.Notification) bool {
+	if notification.AlertID == uuid.Nil {
+		return false
+	}
+
+	calendars, err := s.repoManager.NotificationRouting().ListCalendars(ctx)
+	if err != nil {
+		s.logger.Warn("获取通知日历失败", zap.Error(err))
+		return false
+	}
+	if len(calendars) == 0 {
+		return false
+	}
+
+	alertID := notification.AlertID.String()
+	alert, err := s.repoManager.Alert().GetByID(ctx, alertID)
+	if err != nil {
+		s.logger.Warn("获取通知关联的告警失败", zap.Error(err), zap.String("alert_id", alertID))
+		return false
+	}
+
+	now := time.Now()
+	calendar := matchNotificationCalendar(calendars, alert.Labels)
+	if calendar == nil || !calendar.ShouldDefer(alert.Severity, now) {
+		return false
+	}
+
+	deferred := &models.DeferredNotification{
+		CalendarID: calendar.ID,
+		AlertID:    &alertID,
+		Type:       notification.Type,
+		Recipient:  notification.Recipient,
+		Subject:    notification.Subject,
+		Content:    notification.Content,
+		DeliverAt:  calendar.NextBusinessStart(now),
+	}
+	if err := s.repoManager.NotificationRouting().CreateDeferred(ctx, deferred); err != nil {
+		s.logger.Warn("保存延迟通知失败", zap.Error(err), zap.String("alert_id", alertID))
+		return false
+	}
+
+	s.logger.Info("非工作时间通知已延迟", zap.String("alert_id", alertID), zap.String("calendar_id", calendar.ID),
+		zap.String("recipient", notification.Recipient), zap.Time("deliver_at", deferred.DeliverAt))
+	return true
+}
+
 // GetByID 根据ID获取通知
 func (s *notificationService) GetByID(ctx context.Context, id string) (*models.Notification, error) {
 	if id == "" {
@@ -224,9 +628,104 @@ func (s *notificationService) sendSlack(ctx context.Context, notification *model
 	return nil // 暂时返回成功，实际需要集成Slack API
 }
 
-// sendWebhook 发送Webhook通知
-func (s *notificationService) sendWebhook(ctx context.Context, notification *models.Notification) error {
-	// TODO: 发送HTTP请求到指定的Webhook URL
-	s.logger.Info("发送Webhook通知", zap.String("recipient", notification.Recipient))
-	return nil // 暂时返回成功，实际需要发送HTTP请求
+// pushWanted 检查推送通知的接收人是否订阅了关联告警的级别，非推送通知和非告警通知总是发送
+func (s *notificationService) pushWanted(ctx context.Context, notification *models.Notification) bool {
+	if notification.Type != models.NotificationTypePush || notification.AlertID == uuid.Nil || s.pusher == nil {
+		return true
+	}
+	user, err := s.recipientUser(ctx, notification.Recipient)
+	if err != nil {
+		return true
+	}
+	alert, err := s.repoManager.Alert().GetByID(ctx, notification.AlertID.String())
+	if err != nil {
+		return true
+	}
+	return s.pusher.Wants(ctx, user.ID, alert.Severity)
+}
+
+// sendPush 发送移动端推送通知，接收者为平台用户的用户名或邮箱。同一告警指纹的推送使用相同的折叠键，
+// 告警重复触发时更新设备上已有的通知
+func (s *notificationService) sendPush(ctx context.Context, notification *models.Notification) error {
+	if s.pusher == nil {
+		return fmt.Errorf("未启用移动端推送")
+	}
+	user, err := s.recipientUser(ctx, notification.Recipient)
+	if err != nil {
+		return fmt.Errorf("推送通知的接收者必须是平台用户: %s", notification.Recipient)
+	}
+
+	message := &push.Message{
+		Title: notification.Subject,
+		Body:  notification.Content,
+		Data:  map[string]string{"notification_id": notification.ID.String()},
+	}
+	if notification.AlertID != uuid.Nil {
+		alertID := notification.AlertID.String()
+		message.Data["alert_id"] = alertID
+		message.CollapseKey = push.CollapseKey(alertID)
+		if alert, err := s.repoManager.Alert().GetByID(ctx, alertID); err == nil {
+			message.Data["severity"] = string(alert.Severity)
+			message.Critical = alert.Severity == models.AlertSeverityCritical
+			if alert.Fingerprint != "" {
+				message.CollapseKey = push.CollapseKey(alert.Fingerprint)
+			}
+		}
+	}
+	if message.Title == "" {
+		message.Title = "Pulse"
+	}
+
+	return s.pusher.Push(ctx, user.ID, message)
+}
+
+// sendWebhook 发送Webhook通知，接收者为 Webhook URL，按 webhook 渠道的策略超时和重试，
+// 返回响应的状态码和响应体
+func (s *notificationService) sendWebhook(ctx context.Context, notification *models.Notification) (string, error) {
+	target, err := url.Parse(notification.Recipient)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return "", fmt.Errorf("Webhook通知的接收者必须是HTTP地址: %s", notification.Recipient)
+	}
+
+	payload := map[string]interface{}{
+		"id":      notification.ID,
+		"subject": notification.Subject,
+		"content": notification.Content,
+	}
+	if notification.AlertID != uuid.Nil {
+		payload["alert_id"] = notification.AlertID
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("序列化Webhook通知失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("创建Webhook请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client, err := s.httpClients.Channel(string(models.NotificationTypeWebhook))
+	if err != nil {
+		return "", fmt.Errorf("创建Webhook客户端失败: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("发送Webhook通知失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, models.NotificationProviderResponseMaxLength+1))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	response := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if text := strings.TrimSpace(string(respBody)); text != "" {
+		response += ": " + text
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("发送Webhook通知失败，状态码: %d", resp.StatusCode)
+	}
+
+	s.logger.Info("发送Webhook通知", zap.String("recipient", target.Redacted()))
+	return response, nil
 }
\ No newline at end of file
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// postmortemTicketLabel 改进项工单上记录所属复盘的标签
+const postmortemTicketLabel = "postmortem_id"
+
+// postmortemService 事件复盘服务实现
+type postmortemService struct {
+	repoManager      repository.RepositoryManager
+	ticketService    TicketService
+	knowledgeService KnowledgeService
+	alertTimeline    AlertTimelineService
+	logger           *zap.Logger
+}
+
+// NewPostmortemService 创建事件复盘服务实例
+func NewPostmortemService(
+	repoManager repository.RepositoryManager,
+	ticketService TicketService,
+	knowledgeService KnowledgeService,
+	alertTimeline AlertTimelineService,
+	logger *zap.Logger,
+) PostmortemService {
+	return &postmortemService{
+		repoManager:      repoManager,
+		ticketService:    ticketService,
+		knowledgeService: knowledgeService,
+		alertTimeline:    alertTimeline,
+		logger:           logger,
+	}
+}
+
+// Templates 获取内置复盘模板
+func (s *postmortemService) Templates() []models.PostmortemTemplate {
+	return models.PostmortemTemplates()
+}
+
+// Create 创建复盘，未填写的章节使用模板提纲，未提供时间线时根据关联告警的时间线生成
+func (s *postmortemService) Create(ctx context.Context, req *models.PostmortemCreateRequest, userID string) (*models.Postmortem, error) {
+	templateKey := req.Template
+	if templateKey == "" {
+		templateKey = models.DefaultPostmortemTemplate
+	}
+	template, ok := models.GetPostmortemTemplate(templateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: 复盘模板不存在", models.ErrInvalidInput)
+	}
+
+	postmortem := &models.Postmortem{
+		Title:             strings.TrimSpace(req.Title),
+		Status:            models.PostmortemStatusDraft,
+		Template:          template.Key,
+		IncidentID:        req.IncidentID,
+		AlertIDs:          req.AlertIDs,
+		Summary:           template.Summary,
+		Impact:            template.Impact,
+		RootCause:         template.RootCause,
+		Timeline:          req.Timeline,
+		IncidentStartedAt: req.IncidentStartedAt,
+		IncidentEndedAt:   req.IncidentEndedAt,
+		CreatedBy:         userID,
+	}
+	if postmortem.AlertIDs == nil {
+		postmortem.AlertIDs = []string{}
+	}
+	if req.Summary != nil {
+		postmortem.Summary = *req.Summary
+	}
+	if req.Impact != nil {
+		postmortem.Impact = *req.Impact
+	}
+	if req.RootCause != nil {
+		postmortem.RootCause = *req.RootCause
+	}
+
+	if postmortem.IncidentID != nil {
+		if _, err := s.ticketService.GetByID(ctx, *postmortem.IncidentID); err != nil {
+			return nil, fmt.Errorf("%w: 关联的事件工单不存在", models.ErrInvalidInput)
+		}
+	}
+
+	if len(postmortem.Timeline) == 0 && len(postmortem.AlertIDs) > 0 {
+		timeline, err := s.buildTimeline(ctx, postmortem.AlertIDs)
+		if err != nil {
+			return nil, err
+		}
+		postmortem.Timeline = timeline
+	}
+	if postmortem.IncidentStartedAt == nil && len(postmortem.Timeline) > 0 {
+		startedAt := postmortem.Timeline[0].Timestamp
+		postmortem.IncidentStartedAt = &startedAt
+	}
+
+	if err := postmortem.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.Postmortem().Create(ctx, postmortem); err != nil {
+		s.logger.Error("创建复盘失败", zap.Error(err), zap.String("title", postmortem.Title))
+		return nil, fmt.Errorf("创建复盘失败: %w", err)
+	}
+
+	s.logger.Info("复盘创建成功", zap.String("id", postmortem.ID), zap.String("template", postmortem.Template))
+	return postmortem, nil
+}
+
+// Get 获取复盘详情，改进项状态按跟踪工单同步并统计完成进度
+func (s *postmortemService) Get(ctx context.Context, id string) (*models.Postmortem, error) {
+	postmortem, err := s.repoManager.Postmortem().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repoManager.Postmortem().ListActionItems(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取复盘改进项失败: %w", err)
+	}
+	for _, item := range items {
+		s.syncActionItem(ctx, item)
+	}
+
+	postmortem.ActionItems = items
+	postmortem.Progress = models.NewPostmortemProgress(items)
+	return postmortem, nil
+}
+
+// List 分页获取复盘列表
+func (s *postmortemService) List(ctx context.Context, filter *models.PostmortemFilter) (*models.PostmortemList, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	list, err := s.repoManager.Postmortem().List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("获取复盘列表失败: %w", err)
+	}
+	return list, nil
+}
+
+// Update 更新复盘，已发布的复盘不能修改，发布需通过 Publish 完成
+func (s *postmortemService) Update(ctx context.Context, id string, req *models.PostmortemUpdateRequest, userID string) (*models.Postmortem, error) {
+	postmortem, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != nil && *req.Status == models.PostmortemStatusPublished {
+		return nil, fmt.Errorf("%w: 请通过发布接口发布复盘", models.ErrInvalidInput)
+	}
+
+	req.ApplyTo(postmortem)
+	postmortem.UpdatedBy = &userID
+	if err := postmortem.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.Postmortem().Update(ctx, postmortem); err != nil {
+		s.logger.Error("更新复盘失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("更新复盘失败: %w", err)
+	}
+
+	return postmortem, nil
+}
+
+// Delete 删除复盘，已创建的改进项工单保留
+func (s *postmortemService) Delete(ctx context.Context, id string) error {
+	if err := s.repoManager.Postmortem().Delete(ctx, id); err != nil {
+		if errors.Is(err, models.ErrPostmortemNotFound) {
+			return err
+		}
+		return fmt.Errorf("删除复盘失败: %w", err)
+	}
+
+	s.logger.Info("复盘已删除", zap.String("id", id))
+	return nil
+}
+
+// Publish 发布复盘到知识库，重复发布时更新已有的知识库条目
+func (s *postmortemService) Publish(ctx context.Context, id, userID string) (*models.Postmortem, error) {
+	postmortem, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content := renderPostmortem(postmortem)
+	if postmortem.KnowledgeID != nil {
+		knowledge, err := s.knowledgeService.GetByID(ctx, *postmortem.KnowledgeID)
+		if err != nil {
+			return nil, fmt.Errorf("获取复盘知识库条目失败: %w", err)
+		}
+		knowledge.Title = postmortem.Title
+		knowledge.Content = content
+		if err := s.knowledgeService.Update(ctx, knowledge); err != nil {
+			return nil, fmt.Errorf("更新复盘知识库条目失败: %w", err)
+		}
+	} else {
+		knowledge := &models.Knowledge{
+			Title:    postmortem.Title,
+			Content:  content,
+			Type:     models.KnowledgeTypeTroubleshooting,
+			Status:   models.KnowledgeStatusPublished,
+			Format:   models.KnowledgeFormatMarkdown,
+			Tags:     []string{"postmortem"},
+			AuthorID: userID,
+		}
+		if err := s.knowledgeService.Create(ctx, knowledge); err != nil {
+			return nil, fmt.Errorf("发布复盘到知识库失败: %w", err)
+		}
+		postmortem.KnowledgeID = &knowledge.ID
+	}
+
+	now := time.Now()
+	postmortem.Status = models.PostmortemStatusPublished
+	postmortem.PublishedAt = &now
+	postmortem.UpdatedBy = &userID
+	if err := s.repoManager.Postmortem().Update(ctx, postmortem); err != nil {
+		s.logger.Error("更新复盘发布状态失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("更新复盘发布状态失败: %w", err)
+	}
+
+	s.logger.Info("复盘已发布到知识库", zap.String("id", id), zap.String("knowledge_id", *postmortem.KnowledgeID))
+	return postmortem, nil
+}
+
+// AddActionItem 添加改进项并创建跟踪工单
+func (s *postmortemService) AddActionItem(ctx context.Context, postmortemID string, req *models.PostmortemActionItemRequest, userID string) (*models.PostmortemActionItem, error) {
+	postmortem, err := s.getEditable(ctx, postmortemID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.PostmortemActionItem{
+		PostmortemID: postmortemID,
+		Priority:     models.TicketPriorityMedium,
+		Status:       models.PostmortemActionItemOpen,
+	}
+	req.ApplyTo(item)
+	item.Status = models.PostmortemActionItemOpen
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+
+	ticket := &models.Ticket{
+		Title:       item.Title,
+		Description: fmt.Sprintf("复盘「%s」的改进项\n\n%s", postmortem.Title, item.Description),
+		Type:        models.TicketTypeProblem,
+		Priority:    item.Priority,
+		Severity:    models.TicketSeverityMinor,
+		Labels:      map[string]string{postmortemTicketLabel: postmortemID},
+		AssigneeID:  item.AssigneeID,
+		DueDate:     item.DueDate,
+		ReporterID:  userID,
+	}
+	if err := s.ticketService.Create(ctx, ticket); err != nil {
+		return nil, fmt.Errorf("创建改进项工单失败: %w", err)
+	}
+	item.TicketID = &ticket.ID
+
+	if err := s.repoManager.Postmortem().CreateActionItem(ctx, item); err != nil {
+		s.logger.Error("创建复盘改进项失败", zap.Error(err),
+			zap.String("postmortem_id", postmortemID), zap.String("ticket_id", ticket.ID))
+		return nil, fmt.Errorf("创建复盘改进项失败: %w", err)
+	}
+
+	return item, nil
+}
+
+// UpdateActionItem 更新改进项，状态变更同步到跟踪工单
+func (s *postmortemService) UpdateActionItem(ctx context.Context, postmortemID, itemID string, req *models.PostmortemActionItemRequest) (*models.PostmortemActionItem, error) {
+	item, err := s.repoManager.Postmortem().GetActionItem(ctx, postmortemID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := item.Status
+	req.ApplyTo(item)
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+
+	if item.Status != previous {
+		setActionItemCompletedAt(item)
+		if item.TicketID != nil {
+			if err := s.ticketService.UpdateStatus(ctx, *item.TicketID, ticketStatusForActionItem(item.Status)); err != nil {
+				return nil, fmt.Errorf("同步改进项工单状态失败: %w", err)
+			}
+		}
+	}
+
+	if err := s.repoManager.Postmortem().UpdateActionItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("更新复盘改进项失败: %w", err)
+	}
+
+	return item, nil
+}
+
+// DeleteActionItem 删除改进项，跟踪工单保留
+func (s *postmortemService) DeleteActionItem(ctx context.Context, postmortemID, itemID string) error {
+	if err := s.repoManager.Postmortem().DeleteActionItem(ctx, postmortemID, itemID); err != nil {
+		if errors.Is(err, models.ErrPostmortemActionItemNotFound) {
+			return err
+		}
+		return fmt.Errorf("删除复盘改进项失败: %w", err)
+	}
+	return nil
+}
+
+// getEditable 获取可编辑的复盘，已发布的复盘返回 ErrPostmortemPublished
+func (s *postmortemService) getEditable(ctx context.Context, id string) (*models.Postmortem, error) {
+	postmortem, err := s.repoManager.Postmortem().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if postmortem.Status == models.PostmortemStatusPublished {
+		return nil, models.ErrPostmortemPublished
+	}
+	return postmortem, nil
+}
+
+// syncActionItem 按跟踪工单状态更新改进项状态，工单不可用时保留原状态
+func (s *postmortemService) syncActionItem(ctx context.Context, item *models.PostmortemActionItem) {
+	if item.TicketID == nil {
+		return
+	}
+
+	ticket, err := s.ticketService.GetByID(ctx, *item.TicketID)
+	if err != nil {
+		s.logger.Warn("获取改进项工单失败", zap.Error(err),
+			zap.String("item_id", item.ID), zap.String("ticket_id", *item.TicketID))
+		return
+	}
+
+	status := models.ActionItemStatusFromTicket(ticket.Status)
+	if status == item.Status {
+		return
+	}
+
+	item.Status = status
+	setActionItemCompletedAt(item)
+	if err := s.repoManager.Postmortem().UpdateActionItem(ctx, item); err != nil {
+		s.logger.Warn("同步改进项状态失败", zap.Error(err), zap.String("item_id", item.ID))
+	}
+}
+
+// buildTimeline 根据关联告警的状态变更和关联工单生成复盘时间线
+func (s *postmortemService) buildTimeline(ctx context.Context, alertIDs []string) ([]models.PostmortemTimelineEntry, error) {
+	entries := make([]models.PostmortemTimelineEntry, 0)
+	for _, alertID := range alertIDs {
+		timeline, err := s.alertTimeline.GetTimeline(ctx, alertID)
+		if err != nil {
+			if errors.Is(err, models.ErrAlertNotFound) {
+				return nil, fmt.Errorf("%w: 关联的告警 %s 不存在", models.ErrInvalidInput, alertID)
+			}
+			return nil, fmt.Errorf("获取告警时间线失败: %w", err)
+		}
+
+		for _, event := range timeline.Events {
+			switch event.Type {
+			case models.AlertTimelineStateChange:
+				entries = append(entries, models.PostmortemTimelineEntry{
+					Timestamp:   event.Timestamp,
+					Description: fmt.Sprintf("告警 %s: %s", alertID, event.Action),
+					Source:      "alert",
+				})
+			case models.AlertTimelineTicket:
+				entries = append(entries, models.PostmortemTimelineEntry{
+					Timestamp:   event.Timestamp,
+					Description: fmt.Sprintf("创建工单 %v", event.Details["number"]),
+					Source:      "ticket",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// setActionItemCompletedAt 根据改进项状态设置完成时间
+func setActionItemCompletedAt(item *models.PostmortemActionItem) {
+	if item.Status == models.PostmortemActionItemDone {
+		now := time.Now()
+		item.CompletedAt = &now
+		return
+	}
+	item.CompletedAt = nil
+}
+
+// ticketStatusForActionItem 改进项状态对应的工单状态
+func ticketStatusForActionItem(status models.PostmortemActionItemStatus) models.TicketStatus {
+	switch status {
+	case models.PostmortemActionItemDone:
+		return models.TicketStatusResolved
+	case models.PostmortemActionItemCancelled:
+		return models.TicketStatusCancelled
+	default:
+		return models.TicketStatusOpen
+	}
+}
+
+// renderPostmortem 将复盘渲染为知识库使用的 Markdown 文档
+func renderPostmortem(postmortem *models.Postmortem) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", postmortem.Title)
+	if postmortem.IncidentStartedAt != nil {
+		fmt.Fprintf(&b, "- 开始时间：%s\n", postmortem.IncidentStartedAt.Format(time.RFC3339))
+	}
+	if postmortem.IncidentEndedAt != nil {
+		fmt.Fprintf(&b, "- 结束时间：%s\n", postmortem.IncidentEndedAt.Format(time.RFC3339))
+	}
+	if postmortem.IncidentStartedAt != nil && postmortem.IncidentEndedAt != nil {
+		fmt.Fprintf(&b, "- 持续时长：%s\n", postmortem.IncidentEndedAt.Sub(*postmortem.IncidentStartedAt).Round(time.Minute))
+	}
+	b.WriteString("\n")
+
+	for _, section := range []string{postmortem.Summary, postmortem.Impact, postmortem.RootCause} {
+		if strings.TrimSpace(section) == "" {
+			continue
+		}
+		b.WriteString(strings.TrimSpace(section))
+		b.WriteString("\n\n")
+	}
+
+	if len(postmortem.Timeline) > 0 {
+		b.WriteString("## 时间线\n\n")
+		for _, entry := range postmortem.Timeline {
+			fmt.Fprintf(&b, "- %s %s\n", entry.Timestamp.Format("2006-01-02 15:04:05"), entry.Description)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(postmortem.ActionItems) > 0 {
+		b.WriteString("## 改进项\n\n")
+		for _, item := range postmortem.ActionItems {
+			mark := " "
+			if item.Status == models.PostmortemActionItemDone {
+				mark = "x"
+			}
+			line := fmt.Sprintf("- [%s] %s", mark, item.Title)
+			if item.Status == models.PostmortemActionItemCancelled {
+				line = fmt.Sprintf("- ~~%s~~（已取消）", item.Title)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
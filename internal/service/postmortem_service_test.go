@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakePostmortemRepository 内存复盘仓储
+type fakePostmortemRepository struct {
+	repository.PostmortemRepository
+	postmortems map[string]*models.Postmortem
+	items       map[string]*models.PostmortemActionItem
+}
+
+func newFakePostmortemRepository() *fakePostmortemRepository {
+	return &fakePostmortemRepository{
+		postmortems: make(map[string]*models.Postmortem),
+		items:       make(map[string]*models.PostmortemActionItem),
+	}
+}
+
+func (r *fakePostmortemRepository) Create(ctx context.Context, postmortem *models.Postmortem) error {
+	postmortem.ID = fmt.Sprintf("p%d", len(r.postmortems)+1)
+	r.postmortems[postmortem.ID] = postmortem
+	return nil
+}
+
+func (r *fakePostmortemRepository) GetByID(ctx context.Context, id string) (*models.Postmortem, error) {
+	postmortem, ok := r.postmortems[id]
+	if !ok {
+		return nil, models.ErrPostmortemNotFound
+	}
+	copied := *postmortem
+	return &copied, nil
+}
+
+func (r *fakePostmortemRepository) Update(ctx context.Context, postmortem *models.Postmortem) error {
+	r.postmortems[postmortem.ID] = postmortem
+	return nil
+}
+
+func (r *fakePostmortemRepository) CreateActionItem(ctx context.Context, item *models.PostmortemActionItem) error {
+	item.ID = fmt.Sprintf("i%d", len(r.items)+1)
+	item.CreatedAt = time.Now()
+	r.items[item.ID] = item
+	return nil
+}
+
+func (r *fakePostmortemRepository) GetActionItem(ctx context.Context, postmortemID, itemID string) (*models.PostmortemActionItem, error) {
+	item, ok := r.items[itemID]
+	if !ok || item.PostmortemID != postmortemID {
+		return nil, models.ErrPostmortemActionItemNotFound
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (r *fakePostmortemRepository) ListActionItems(ctx context.Context, postmortemID string) ([]*models.PostmortemActionItem, error) {
+	items := make([]*models.PostmortemActionItem, 0)
+	for _, item := range r.items {
+		if item.PostmortemID == postmortemID {
+			copied := *item
+			items = append(items, &copied)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakePostmortemRepository) UpdateActionItem(ctx context.Context, item *models.PostmortemActionItem) error {
+	r.items[item.ID] = item
+	return nil
+}
+
+type postmortemRepoManager struct {
+	*MockRepositoryManager
+	repo *fakePostmortemRepository
+}
+
+func (m *postmortemRepoManager) Postmortem() repository.PostmortemRepository { return m.repo }
+
+// fakePostmortemTicketService 内存工单服务
+type fakePostmortemTicketService struct {
+	TicketService
+	tickets map[string]*models.Ticket
+}
+
+func (s *fakePostmortemTicketService) Create(ctx context.Context, ticket *models.Ticket) error {
+	ticket.ID = fmt.Sprintf("t%d", len(s.tickets)+1)
+	ticket.Status = models.TicketStatusOpen
+	s.tickets[ticket.ID] = ticket
+	return nil
+}
+
+func (s *fakePostmortemTicketService) GetByID(ctx context.Context, id string) (*models.Ticket, error) {
+	ticket, ok := s.tickets[id]
+	if !ok {
+		return nil, models.ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+func (s *fakePostmortemTicketService) UpdateStatus(ctx context.Context, id string, status models.TicketStatus) error {
+	s.tickets[id].Status = status
+	return nil
+}
+
+// fakePostmortemKnowledgeService 内存知识库服务
+type fakePostmortemKnowledgeService struct {
+	KnowledgeService
+	documents map[string]*models.Knowledge
+}
+
+func (s *fakePostmortemKnowledgeService) Create(ctx context.Context, knowledge *models.Knowledge) error {
+	knowledge.ID = fmt.Sprintf("k%d", len(s.documents)+1)
+	s.documents[knowledge.ID] = knowledge
+	return nil
+}
+
+func (s *fakePostmortemKnowledgeService) GetByID(ctx context.Context, id string) (*models.Knowledge, error) {
+	return s.documents[id], nil
+}
+
+func (s *fakePostmortemKnowledgeService) Update(ctx context.Context, knowledge *models.Knowledge) error {
+	s.documents[knowledge.ID] = knowledge
+	return nil
+}
+
+type fakePostmortemAlertTimeline struct {
+	AlertTimelineService
+	timelines map[string]*models.AlertTimeline
+}
+
+func (s *fakePostmortemAlertTimeline) GetTimeline(ctx context.Context, alertID string) (*models.AlertTimeline, error) {
+	timeline, ok := s.timelines[alertID]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	return timeline, nil
+}
+
+func newTestPostmortemService() (PostmortemService, *fakePostmortemTicketService, *fakePostmortemKnowledgeService) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tickets := &fakePostmortemTicketService{tickets: make(map[string]*models.Ticket)}
+	knowledge := &fakePostmortemKnowledgeService{documents: make(map[string]*models.Knowledge)}
+	timeline := &fakePostmortemAlertTimeline{timelines: map[string]*models.AlertTimeline{
+		"a1": {AlertID: "a1", Events: []*models.AlertTimelineEvent{
+			{Type: models.AlertTimelineStateChange, Action: "resolved", Timestamp: base.Add(30 * time.Minute)},
+			{Type: models.AlertTimelineNotification, Action: "sent", Timestamp: base.Add(time.Minute)},
+			{Type: models.AlertTimelineStateChange, Action: "created", Timestamp: base},
+		}},
+	}}
+	repoManager := &postmortemRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: newFakePostmortemRepository()}
+	svc := NewPostmortemService(repoManager, tickets, knowledge, timeline, zap.NewNop())
+	return svc, tickets, knowledge
+}
+
+func TestPostmortemService_Create(t *testing.T) {
+	svc, _, _ := newTestPostmortemService()
+	ctx := context.Background()
+
+	impact := "支付成功率下降 30%"
+	postmortem, err := svc.Create(ctx, &models.PostmortemCreateRequest{
+		Title:    "支付服务中断",
+		AlertIDs: []string{"a1"},
+		Impact:   &impact,
+	}, "u1")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.PostmortemStatusDraft, postmortem.Status)
+	assert.Equal(t, models.DefaultPostmortemTemplate, postmortem.Template)
+	assert.Contains(t, postmortem.RootCause, "根因分析")
+	assert.Equal(t, impact, postmortem.Impact)
+
+	// 时间线由告警状态变更生成，通知事件不计入
+	require.Len(t, postmortem.Timeline, 2)
+	assert.Contains(t, postmortem.Timeline[0].Description, "created")
+	require.NotNil(t, postmortem.IncidentStartedAt)
+	assert.Equal(t, postmortem.Timeline[0].Timestamp, *postmortem.IncidentStartedAt)
+
+	_, err = svc.Create(ctx, &models.PostmortemCreateRequest{Title: "x", Template: "unknown"}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	_, err = svc.Create(ctx, &models.PostmortemCreateRequest{Title: "x", AlertIDs: []string{"missing"}}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestPostmortemService_ActionItemsAndPublish(t *testing.T) {
+	svc, tickets, knowledge := newTestPostmortemService()
+	ctx := context.Background()
+
+	postmortem, err := svc.Create(ctx, &models.PostmortemCreateRequest{Title: "数据库主从切换", Template: "brief"}, "u1")
+	require.NoError(t, err)
+
+	title := "增加复制延迟告警"
+	first, err := svc.AddActionItem(ctx, postmortem.ID, &models.PostmortemActionItemRequest{Title: &title}, "u1")
+	require.NoError(t, err)
+	require.NotNil(t, first.TicketID)
+	ticket := tickets.tickets[*first.TicketID]
+	assert.Equal(t, models.TicketTypeProblem, ticket.Type)
+	assert.Equal(t, postmortem.ID, ticket.Labels[postmortemTicketLabel])
+
+	title = "演练主从切换"
+	second, err := svc.AddActionItem(ctx, postmortem.ID, &models.PostmortemActionItemRequest{Title: &title}, "u1")
+	require.NoError(t, err)
+
+	// 工单解决后改进项自动完成
+	ticket.Status = models.TicketStatusResolved
+	got, err := svc.Get(ctx, postmortem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.PostmortemProgress{Total: 2, Completed: 1, Percent: 50}, got.Progress)
+
+	// 改进项取消时同步取消工单
+	cancelled := models.PostmortemActionItemCancelled
+	updated, err := svc.UpdateActionItem(ctx, postmortem.ID, second.ID, &models.PostmortemActionItemRequest{Status: &cancelled})
+	require.NoError(t, err)
+	assert.Equal(t, models.PostmortemActionItemCancelled, updated.Status)
+	assert.Equal(t, models.TicketStatusCancelled, tickets.tickets[*second.TicketID].Status)
+
+	published, err := svc.Publish(ctx, postmortem.ID, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, models.PostmortemStatusPublished, published.Status)
+	require.NotNil(t, published.KnowledgeID)
+	document := knowledge.documents[*published.KnowledgeID]
+	assert.Equal(t, models.KnowledgeStatusPublished, document.Status)
+	assert.Contains(t, document.Content, "- [x] 增加复制延迟告警")
+
+	// 已发布的复盘不能修改，再次发布更新原知识库条目
+	_, err = svc.Update(ctx, postmortem.ID, &models.PostmortemUpdateRequest{Title: &title}, "u1")
+	assert.ErrorIs(t, err, models.ErrPostmortemPublished)
+
+	_, err = svc.Publish(ctx, postmortem.ID, "u2")
+	require.NoError(t, err)
+	assert.Len(t, knowledge.documents, 1)
+}
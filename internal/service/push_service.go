@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/push"
+	"pulse/internal/repository"
+)
+
+// pushService 移动端推送服务实现
+type pushService struct {
+	repoManager       repository.RepositoryManager
+	providers         push.Providers
+	defaultSeverities []models.AlertSeverity
+	logger            *zap.Logger
+}
+
+// NewPushService 创建移动端推送服务实例，providers 只包含已配置凭据的推送平台
+func NewPushService(repoManager repository.RepositoryManager, providers push.Providers, cfg config.PushConfig, logger *zap.Logger) PushService {
+	var defaults []models.AlertSeverity
+	for _, item := range strings.Split(cfg.DefaultSeverities, ",") {
+		if severity := models.AlertSeverity(strings.TrimSpace(item)); severity.IsValid() {
+			defaults = append(defaults, severity)
+		}
+	}
+
+	return &pushService{
+		repoManager:       repoManager,
+		providers:         providers,
+		defaultSeverities: defaults,
+		logger:            logger,
+	}
+}
+
+// RegisterDevice 注册当前用户的推送设备，未配置推送服务的平台不能注册
+func (s *pushService) RegisterDevice(ctx context.Context, req *models.PushDeviceRequest, userID string) (*models.PushDevice, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.providers[req.Platform] == nil {
+		return nil, models.ErrPushPlatformDisabled
+	}
+
+	device := &models.PushDevice{UserID: userID, Platform: req.Platform, Token: req.Token, Name: req.Name}
+	if err := s.repoManager.Push().UpsertDevice(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// ListDevices 获取当前用户的推送设备
+func (s *pushService) ListDevices(ctx context.Context, userID string) ([]*models.PushDevice, error) {
+	return s.repoManager.Push().ListDevices(ctx, userID)
+}
+
+// DeleteDevice 删除当前用户的推送设备，应用退出登录时调用
+func (s *pushService) DeleteDevice(ctx context.Context, id, userID string) error {
+	return s.repoManager.Push().DeleteDevice(ctx, id, userID)
+}
+
+// GetPreference 获取当前用户的推送偏好，未设置时返回默认推送的告警级别
+func (s *pushService) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	pref, err := s.repoManager.Push().GetPreference(ctx, userID)
+	if errors.Is(err, models.ErrPushPreferenceNotFound) {
+		return &models.PushPreference{UserID: userID, Severities: s.defaultSeverities}, nil
+	}
+	return pref, err
+}
+
+// SetPreference 设置当前用户的推送偏好
+func (s *pushService) SetPreference(ctx context.Context, req *models.PushPreferenceRequest, userID string) (*models.PushPreference, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	pref := &models.PushPreference{UserID: userID, Severities: req.Severities}
+	if err := s.repoManager.Push().SetPreference(ctx, pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// Wants 检查用户是否接收该级别告警的推送，查询偏好失败时按默认级别判断
+func (s *pushService) Wants(ctx context.Context, userID string, severity models.AlertSeverity) bool {
+	pref, err := s.GetPreference(ctx, userID)
+	if err != nil {
+		s.logger.Warn("获取推送偏好失败", zap.Error(err), zap.String("user_id", userID))
+		pref = &models.PushPreference{Severities: s.defaultSeverities}
+	}
+	return pref.Allows(severity)
+}
+
+// Push 向用户的所有推送设备发送推送，至少一台设备送达即成功。推送服务返回令牌失效的设备会被删除
+func (s *pushService) Push(ctx context.Context, userID string, message *push.Message) error {
+	devices, err := s.repoManager.Push().ListDevices(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	delivered := 0
+	var lastErr error
+	for _, device := range devices {
+		provider := s.providers[device.Platform]
+		if provider == nil {
+			continue
+		}
+
+		err := provider.Send(ctx, device.Token, message)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, push.ErrUnregistered):
+			s.logger.Info("推送设备令牌已失效，删除设备", zap.String("user_id", userID), zap.String("device_id", device.ID))
+			if err := s.repoManager.Push().DeleteDeviceByToken(ctx, device.Token); err != nil {
+				s.logger.Warn("删除失效的推送设备失败", zap.Error(err), zap.String("device_id", device.ID))
+			}
+		default:
+			s.logger.Warn("推送失败", zap.Error(err), zap.String("user_id", userID), zap.String("device_id", device.ID),
+				zap.String("platform", string(device.Platform)))
+			lastErr = err
+		}
+	}
+
+	if delivered > 0 {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("推送失败: %w", lastErr)
+	}
+	return models.ErrNoPushDevice
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/push"
+	"pulse/internal/repository"
+)
+
+// fakePushRepository 内存中的推送设备和推送偏好
+type fakePushRepository struct {
+	devices []*models.PushDevice
+	prefs   map[string]*models.PushPreference
+}
+
+func (r *fakePushRepository) UpsertDevice(ctx context.Context, device *models.PushDevice) error {
+	for _, existing := range r.devices {
+		if existing.Token == device.Token {
+			existing.UserID, existing.Platform, existing.Name = device.UserID, device.Platform, device.Name
+			device.ID = existing.ID
+			return nil
+		}
+	}
+	device.ID = fmt.Sprintf("d%d", len(r.devices)+1)
+	r.devices = append(r.devices, device)
+	return nil
+}
+
+func (r *fakePushRepository) ListDevices(ctx context.Context, userID string) ([]*models.PushDevice, error) {
+	var devices []*models.PushDevice
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *fakePushRepository) DeleteDevice(ctx context.Context, id, userID string) error {
+	for i, device := range r.devices {
+		if device.ID == id && device.UserID == userID {
+			r.devices = append(r.devices[:i], r.devices[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrPushDeviceNotFound
+}
+
+func (r *fakePushRepository) DeleteDeviceByToken(ctx context.Context, token string) error {
+	for i, device := range r.devices {
+		if device.Token == token {
+			r.devices = append(r.devices[:i], r.devices[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakePushRepository) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	pref, ok := r.prefs[userID]
+	if !ok {
+		return nil, models.ErrPushPreferenceNotFound
+	}
+	return pref, nil
+}
+
+func (r *fakePushRepository) SetPreference(ctx context.Context, pref *models.PushPreference) error {
+	r.prefs[pref.UserID] = pref
+	return nil
+}
+
+// fakePushProvider 记录推送，failures 中的设备令牌返回对应的错误
+type fakePushProvider struct {
+	sent     map[string][]*push.Message
+	failures map[string]error
+}
+
+func (p *fakePushProvider) Send(ctx context.Context, token string, message *push.Message) error {
+	if err := p.failures[token]; err != nil {
+		return err
+	}
+	p.sent[token] = append(p.sent[token], message)
+	return nil
+}
+
+type pushRepoManager struct {
+	*notificationCalendarRepoManager
+	push *fakePushRepository
+}
+
+func (m *pushRepoManager) Push() repository.PushRepository { return m.push }
+
+func newPushTestService() (PushService, *pushRepoManager, *fakePushProvider) {
+	repoManager := &pushRepoManager{
+		notificationCalendarRepoManager: &notificationCalendarRepoManager{
+			alertSnoozeRepoManager: newAlertSnoozeTestRepoManager(),
+			routing:                &fakeNotificationRoutingRepository{},
+			notifications:          &fakeSentNotificationRepository{},
+			languages:              newFakeLanguagePreferenceRepository(),
+		},
+		push: &fakePushRepository{prefs: map[string]*models.PushPreference{}},
+	}
+	provider := &fakePushProvider{sent: map[string][]*push.Message{}, failures: map[string]error{}}
+	svc := NewPushService(repoManager, push.Providers{models.PushPlatformFCM: provider},
+		config.PushConfig{DefaultSeverities: "critical, high"}, zap.NewNop())
+	return svc, repoManager, provider
+}
+
+func TestPushService_DevicesAndPreferences(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager, _ := newPushTestService()
+
+	_, err := svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: models.PushPlatformAPNs, Token: "ios-1"}, "u1")
+	assert.ErrorIs(t, err, models.ErrPushPlatformDisabled)
+	_, err = svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: "mqtt", Token: "x"}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	device, err := svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: models.PushPlatformFCM, Token: " android-1 ", Name: "Pixel"}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "android-1", device.Token)
+
+	// 同一设备令牌被其他用户注册时转移到该用户
+	_, err = svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: models.PushPlatformFCM, Token: "android-1"}, "u2")
+	require.NoError(t, err)
+	devices, err := svc.ListDevices(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+	assert.ErrorIs(t, svc.DeleteDevice(ctx, device.ID, "u1"), models.ErrPushDeviceNotFound)
+	require.NoError(t, svc.DeleteDevice(ctx, device.ID, "u2"))
+	assert.Empty(t, repoManager.push.devices)
+
+	// 未设置偏好时推送默认级别
+	pref, err := svc.GetPreference(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityCritical, models.AlertSeverityHigh}, pref.Severities)
+	assert.True(t, svc.Wants(ctx, "u1", models.AlertSeverityHigh))
+	assert.False(t, svc.Wants(ctx, "u1", models.AlertSeverityLow))
+
+	_, err = svc.SetPreference(ctx, &models.PushPreferenceRequest{Severities: []models.AlertSeverity{"urgent"}}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	pref, err = svc.SetPreference(ctx, &models.PushPreferenceRequest{
+		Severities: []models.AlertSeverity{models.AlertSeverityCritical, models.AlertSeverityCritical},
+	}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlertSeverity{models.AlertSeverityCritical}, pref.Severities)
+	assert.False(t, svc.Wants(ctx, "u1", models.AlertSeverityHigh))
+}
+
+func TestPushService_Push(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager, provider := newPushTestService()
+	message := &push.Message{Title: "HighErrorRate", Body: "checkout"}
+
+	assert.ErrorIs(t, svc.Push(ctx, "u1", message), models.ErrNoPushDevice)
+
+	for _, token := range []string{"stale", "broken", "ok"} {
+		_, err := svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: models.PushPlatformFCM, Token: token}, "u1")
+		require.NoError(t, err)
+	}
+	provider.failures["stale"] = push.ErrUnregistered
+	provider.failures["broken"] = errors.New("service unavailable")
+
+	// 部分设备送达即成功，令牌失效的设备被删除
+	require.NoError(t, svc.Push(ctx, "u1", message))
+	assert.Len(t, provider.sent["ok"], 1)
+	devices, err := svc.ListDevices(ctx, "u1")
+	require.NoError(t, err)
+	assert.Len(t, devices, 2)
+
+	provider.failures["ok"] = errors.New("service unavailable")
+	assert.Error(t, svc.Push(ctx, "u1", message))
+	assert.Len(t, repoManager.push.devices, 2)
+}
+
+func TestNotificationService_SendPush(t *testing.T) {
+	ctx := context.Background()
+	svc, repoManager, provider := newPushTestService()
+	_, err := svc.RegisterDevice(ctx, &models.PushDeviceRequest{Platform: models.PushPlatformFCM, Token: "android-1"}, "u1")
+	require.NoError(t, err)
+
+	firstFire, secondFire, lowFire := uuid.New(), uuid.New(), uuid.New()
+	for id, severity := range map[uuid.UUID]models.AlertSeverity{
+		firstFire: models.AlertSeverityCritical, secondFire: models.AlertSeverityCritical, lowFire: models.AlertSeverityLow,
+	} {
+		repoManager.alerts.alerts[id.String()] = &models.Alert{
+			ID: id.String(), Fingerprint: "checkout-" + string(severity), Severity: severity, Status: models.AlertStatusFiring,
+		}
+	}
+	notifications := NewNotificationService(repoManager, testTranslator(t), nil, nil, svc, config.NotificationConfig{}, zap.NewNop())
+
+	for _, alertID := range []uuid.UUID{firstFire, secondFire, lowFire} {
+		require.NoError(t, notifications.Send(ctx, &models.Notification{
+			AlertID: alertID, Type: models.NotificationTypePush, Recipient: "alice", Subject: "HighErrorRate", Content: "checkout 错误率过高",
+		}))
+	}
+
+	// 同一指纹的两次触发使用相同的折叠键，未订阅的低级别告警不推送也不记录
+	sent := provider.sent["android-1"]
+	require.Len(t, sent, 2)
+	assert.Equal(t, push.CollapseKey("checkout-critical"), sent[0].CollapseKey)
+	assert.Equal(t, sent[0].CollapseKey, sent[1].CollapseKey)
+	assert.True(t, sent[0].Critical)
+	assert.Equal(t, secondFire.String(), sent[1].Data["alert_id"])
+	assert.Len(t, repoManager.notifications.sent, 2)
+	assert.Equal(t, models.NotificationStatusSent, repoManager.notifications.sent[0].Status)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/prometheus"
+)
+
+const (
+	// queryCacheKeyPrefix 查询结果缓存键前缀
+	queryCacheKeyPrefix = "query_cache:"
+	// queryCacheNoData 缓存的空查询结果
+	queryCacheNoData = "nodata"
+)
+
+// queryCacheService 数据源查询结果缓存，缓存键为 (数据源, 规范化表达式, 步长)，多个规则或 SLO 使用相同表达式和数据源时共享查询结果。
+// 缓存键包含全局和数据源的版本号，失效时递增版本号，旧缓存随 TTL 自然过期
+type queryCacheService struct {
+	cache  cache.Cache
+	logger *zap.Logger
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	errors        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// NewQueryCacheService 创建查询结果缓存，queryCache 为 nil 时不缓存
+func NewQueryCacheService(queryCache cache.Cache, logger *zap.Logger) QueryCacheService {
+	return &queryCacheService{cache: queryCache, logger: logger}
+}
+
+// Invalidate 使数据源的缓存查询结果失效，dataSourceID 为空时使全部缓存失效
+func (s *queryCacheService) Invalidate(ctx context.Context, dataSourceID string) error {
+	if s.cache == nil {
+		return nil
+	}
+	if _, err := s.cache.Incr(ctx, queryCacheGenerationKey(dataSourceID)); err != nil {
+		return fmt.Errorf("清除查询结果缓存失败: %w", err)
+	}
+	s.invalidations.Add(1)
+	s.logger.Info("清除查询结果缓存", zap.String("data_source_id", dataSourceID))
+	return nil
+}
+
+// Metrics 获取进程启动以来的缓存命中次数，供 /health/metrics 输出
+func (s *queryCacheService) Metrics() map[string]interface{} {
+	hits, misses := s.hits.Load(), s.misses.Load()
+	var hitRatio float64
+	if hits+misses > 0 {
+		hitRatio = float64(hits) / float64(hits+misses)
+	}
+	return map[string]interface{}{
+		"enabled":       s.cache != nil,
+		"hits":          hits,
+		"misses":        misses,
+		"hit_ratio":     hitRatio,
+		"errors":        s.errors.Load(),
+		"invalidations": s.invalidations.Load(),
+	}
+}
+
+// wrapFactory 为查询客户端工厂创建的客户端添加结果缓存，缓存时间和步长为评估间隔，ttl 不大于 0 时不缓存
+func (s *queryCacheService) wrapFactory(factory SLOQuerierFactory, ttl time.Duration) SLOQuerierFactory {
+	if s.cache == nil || ttl <= 0 {
+		return factory
+	}
+	return func(dataSource *models.DataSource) (SLOQuerier, error) {
+		querier, err := factory(dataSource)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedQuerier{querier: querier, cache: s, dataSourceID: dataSource.ID, ttl: ttl}, nil
+	}
+}
+
+// lookup 读取缓存的查询结果，未命中或缓存不可用时 ok 为 false
+func (s *queryCacheService) lookup(ctx context.Context, key string) (value float64, noData, ok bool) {
+	cached, err := s.cache.Get(ctx, key)
+	if err != nil {
+		s.errors.Add(1)
+		s.logger.Debug("读取查询结果缓存失败", zap.Error(err))
+		return 0, false, false
+	}
+	if cached == "" {
+		return 0, false, false
+	}
+	if cached == queryCacheNoData {
+		return 0, true, true
+	}
+	value, err = strconv.ParseFloat(cached, 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return value, false, true
+}
+
+// store 缓存查询结果，写入失败只记录日志
+func (s *queryCacheService) store(ctx context.Context, key string, value float64, noData bool, ttl time.Duration) {
+	cached := strconv.FormatFloat(value, 'g', -1, 64)
+	if noData {
+		cached = queryCacheNoData
+	}
+	if err := s.cache.Set(ctx, key, cached, ttl); err != nil {
+		s.errors.Add(1)
+		s.logger.Debug("写入查询结果缓存失败", zap.Error(err))
+	}
+}
+
+// key 生成查询结果的缓存键，读取版本号失败时返回空字符串，本次查询不使用缓存
+func (s *queryCacheService) key(ctx context.Context, dataSourceID, expr string, step time.Duration) string {
+	generations, err := s.cache.MGet(ctx, queryCacheGenerationKey(""), queryCacheGenerationKey(dataSourceID))
+	if err != nil || len(generations) != 2 {
+		s.errors.Add(1)
+		s.logger.Debug("读取查询结果缓存版本失败", zap.Error(err))
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(normalizeQueryExpr(expr)))
+	return fmt.Sprintf("%s%s:%v:%v:%d:%s", queryCacheKeyPrefix, dataSourceID,
+		queryCacheGeneration(generations[0]), queryCacheGeneration(generations[1]), step.Milliseconds(), hex.EncodeToString(sum[:16]))
+}
+
+// cachedQuerier 先读取缓存的查询结果，未命中时查询数据源并缓存结果。查询失败不缓存，查询结果为空时同样缓存
+type cachedQuerier struct {
+	querier      SLOQuerier
+	cache        *queryCacheService
+	dataSourceID string
+	ttl          time.Duration
+}
+
+// Query 执行即时查询，评估间隔内相同表达式的查询返回缓存结果
+func (q *cachedQuerier) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	key := q.cache.key(ctx, q.dataSourceID, expr, q.ttl)
+	if key == "" {
+		return q.querier.Query(ctx, expr, at)
+	}
+	if value, noData, ok := q.cache.lookup(ctx, key); ok {
+		q.cache.hits.Add(1)
+		if noData {
+			return 0, prometheus.ErrNoData
+		}
+		return value, nil
+	}
+	q.cache.misses.Add(1)
+
+	value, err := q.querier.Query(ctx, expr, at)
+	switch {
+	case err == nil:
+		q.cache.store(ctx, key, value, false, q.ttl)
+	case errors.Is(err, prometheus.ErrNoData):
+		q.cache.store(ctx, key, 0, true, q.ttl)
+	}
+	return value, err
+}
+
+// queryCacheGenerationKey 缓存版本号的键，dataSourceID 为空时为全局版本号
+func queryCacheGenerationKey(dataSourceID string) string {
+	if dataSourceID == "" {
+		return queryCacheKeyPrefix + "generation"
+	}
+	return queryCacheKeyPrefix + "generation:" + dataSourceID
+}
+
+// queryCacheGeneration 解析 MGet 返回的版本号，未设置时为 0
+func queryCacheGeneration(v interface{}) interface{} {
+	if v == nil {
+		return 0
+	}
+	return v
+}
+
+// normalizeQueryExpr 合并表达式中的空白，仅空白不同的表达式共享缓存
+func normalizeQueryExpr(expr string) string {
+	return strings.Join(strings.Fields(expr), " ")
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/cache"
+	"pulse/internal/models"
+	"pulse/internal/prometheus"
+)
+
+// countingQuerier 记录每个表达式的查询次数，noData 中的表达式返回空结果
+type countingQuerier struct {
+	calls  map[string]int
+	noData map[string]bool
+}
+
+func (q *countingQuerier) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	q.calls[expr]++
+	if q.noData[expr] {
+		return 0, prometheus.ErrNoData
+	}
+	return float64(len(expr)), nil
+}
+
+func TestQueryCacheService_SharesResultsAndInvalidates(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingQuerier{calls: make(map[string]int), noData: map[string]bool{"absent": true}}
+	svc := NewQueryCacheService(cache.NewMemoryCache(), zap.NewNop()).(*queryCacheService)
+	factory := svc.wrapFactory(func(dataSource *models.DataSource) (SLOQuerier, error) { return backend, nil }, time.Minute)
+
+	prom, err := factory(&models.DataSource{ID: "prom"})
+	require.NoError(t, err)
+	thanos, err := factory(&models.DataSource{ID: "thanos"})
+	require.NoError(t, err)
+
+	// 空白不同的相同表达式共享缓存，空结果同样缓存
+	for _, expr := range []string{"sum(rate(x[5m]))", "sum(rate(x[5m]))", "sum(rate(x[5m]))  "} {
+		value, err := prom.Query(ctx, expr, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, float64(len("sum(rate(x[5m]))")), value)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = prom.Query(ctx, "absent", time.Now())
+		assert.ErrorIs(t, err, prometheus.ErrNoData)
+	}
+	assert.Equal(t, 1, backend.calls["sum(rate(x[5m]))"])
+	assert.Equal(t, 1, backend.calls["absent"])
+
+	// 不同数据源分别缓存
+	_, err = thanos.Query(ctx, "sum(rate(x[5m]))", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.calls["sum(rate(x[5m]))"])
+
+	// 清除单个数据源的缓存不影响其他数据源
+	require.NoError(t, svc.Invalidate(ctx, "prom"))
+	_, _ = prom.Query(ctx, "sum(rate(x[5m]))", time.Now())
+	_, _ = thanos.Query(ctx, "sum(rate(x[5m]))", time.Now())
+	assert.Equal(t, 3, backend.calls["sum(rate(x[5m]))"])
+
+	// 清除全部缓存
+	require.NoError(t, svc.Invalidate(ctx, ""))
+	_, _ = thanos.Query(ctx, "sum(rate(x[5m]))", time.Now())
+	assert.Equal(t, 4, backend.calls["sum(rate(x[5m]))"])
+
+	metrics := svc.Metrics()
+	assert.Equal(t, int64(4), metrics["hits"])
+	assert.Equal(t, int64(5), metrics["misses"])
+	assert.Equal(t, int64(2), metrics["invalidations"])
+}
+
+func TestQueryCacheService_DisabledWithoutCache(t *testing.T) {
+	backend := &countingQuerier{calls: make(map[string]int)}
+	svc := NewQueryCacheService(nil, zap.NewNop()).(*queryCacheService)
+	factory := svc.wrapFactory(func(dataSource *models.DataSource) (SLOQuerier, error) { return backend, nil }, time.Minute)
+
+	querier, err := factory(&models.DataSource{ID: "prom"})
+	require.NoError(t, err)
+	_, _ = querier.Query(context.Background(), "up", time.Now())
+	_, _ = querier.Query(context.Background(), "up", time.Now())
+	assert.Equal(t, 2, backend.calls["up"])
+	assert.NoError(t, svc.Invalidate(context.Background(), ""))
+	assert.Equal(t, false, svc.Metrics()["enabled"])
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// quietHoursService 用户免打扰服务实现
+type quietHoursService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+}
+
+// NewQuietHoursService 创建用户免打扰服务实例
+func NewQuietHoursService(repoManager repository.RepositoryManager, logger *zap.Logger) QuietHoursService {
+	return &quietHoursService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// Get 获取用户的免打扰设置，未设置时返回空设置
+func (s *quietHoursService) Get(ctx context.Context, userID string) (*models.QuietHours, error) {
+	quiet, err := s.repoManager.QuietHours().Get(ctx, userID)
+	if errors.Is(err, models.ErrQuietHoursNotFound) {
+		return &models.QuietHours{UserID: userID, Windows: []models.QuietHoursWindow{}, DNDAllowSeverities: []models.AlertSeverity{}}, nil
+	}
+	return quiet, err
+}
+
+// SetSchedule 设置用户每周的免打扰时段，不影响正在进行的临时免打扰
+func (s *quietHoursService) SetSchedule(ctx context.Context, req *models.QuietHoursRequest, userID string) (*models.QuietHours, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	quiet, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	quiet.Timezone = req.Timezone
+	quiet.Windows = req.Windows
+	if err := s.repoManager.QuietHours().Set(ctx, quiet); err != nil {
+		return nil, err
+	}
+	s.logger.Info("免打扰时段已更新", zap.String("user_id", userID), zap.Int("windows", len(quiet.Windows)))
+	return quiet, nil
+}
+
+// StartDND 临时开启免打扰，期间只通知允许级别的告警，重复开启时覆盖截止时间
+func (s *quietHoursService) StartDND(ctx context.Context, req *models.DNDRequest, userID string) (*models.QuietHours, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	quiet, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	until := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	quiet.DNDUntil = &until
+	quiet.DNDAllowSeverities = req.AllowSeverities
+	if quiet.DNDAllowSeverities == nil {
+		quiet.DNDAllowSeverities = []models.AlertSeverity{}
+	}
+	if err := s.repoManager.QuietHours().Set(ctx, quiet); err != nil {
+		return nil, err
+	}
+	s.logger.Info("已开启临时免打扰", zap.String("user_id", userID), zap.Time("until", until))
+	return quiet, nil
+}
+
+// EndDND 提前结束临时免打扰
+func (s *quietHoursService) EndDND(ctx context.Context, userID string) (*models.QuietHours, error) {
+	quiet, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if quiet.DNDUntil == nil {
+		return quiet, nil
+	}
+	quiet.DNDUntil = nil
+	quiet.DNDAllowSeverities = []models.AlertSeverity{}
+	if err := s.repoManager.QuietHours().Set(ctx, quiet); err != nil {
+		return nil, err
+	}
+	return quiet, nil
+}
+
+// Delete 清除用户的免打扰设置
+func (s *quietHoursService) Delete(ctx context.Context, userID string) error {
+	return s.repoManager.QuietHours().Delete(ctx, userID)
+}
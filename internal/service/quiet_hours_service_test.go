@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+// fakeQuietHoursRepository 内存中的用户免打扰设置
+type fakeQuietHoursRepository struct {
+	settings map[string]*models.QuietHours
+}
+
+func (r *fakeQuietHoursRepository) Get(ctx context.Context, userID string) (*models.QuietHours, error) {
+	quiet, ok := r.settings[userID]
+	if !ok {
+		return nil, models.ErrQuietHoursNotFound
+	}
+	copied := *quiet
+	return &copied, nil
+}
+
+func (r *fakeQuietHoursRepository) Set(ctx context.Context, quiet *models.QuietHours) error {
+	copied := *quiet
+	r.settings[quiet.UserID] = &copied
+	return nil
+}
+
+func (r *fakeQuietHoursRepository) Delete(ctx context.Context, userID string) error {
+	delete(r.settings, userID)
+	return nil
+}
+
+func TestQuietHours_Suppresses(t *testing.T) {
+	quiet := &models.QuietHours{
+		Timezone: "Asia/Shanghai",
+		Windows: []models.QuietHoursWindow{{
+			Days: []string{"friday"}, Start: "23:00", End: "07:00",
+			AllowSeverities: []models.AlertSeverity{models.AlertSeverityCritical},
+		}},
+	}
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	// 周五 23:00 开始的时段持续到周六 07:00，只通知严重告警
+	saturdayNight := time.Date(2024, 6, 8, 3, 0, 0, 0, shanghai)
+	assert.False(t, quiet.Suppresses(models.AlertSeverityCritical, saturdayNight))
+	assert.True(t, quiet.Suppresses(models.AlertSeverityHigh, saturdayNight))
+	assert.True(t, quiet.Suppresses("", saturdayNight))
+	assert.True(t, quiet.Suppresses(models.AlertSeverityHigh, time.Date(2024, 6, 7, 23, 30, 0, 0, shanghai)))
+	assert.False(t, quiet.Suppresses(models.AlertSeverityHigh, time.Date(2024, 6, 8, 23, 30, 0, 0, shanghai)))
+	assert.False(t, quiet.Suppresses(models.AlertSeverityHigh, time.Date(2024, 6, 8, 7, 0, 0, 0, shanghai)))
+
+	// 临时免打扰期间只通知允许的级别，过期后恢复
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, shanghai)
+	until := now.Add(time.Hour)
+	quiet.DNDUntil = &until
+	assert.True(t, quiet.Suppresses(models.AlertSeverityCritical, now))
+	quiet.DNDAllowSeverities = []models.AlertSeverity{models.AlertSeverityCritical}
+	assert.False(t, quiet.Suppresses(models.AlertSeverityCritical, now))
+	assert.True(t, quiet.Suppresses(models.AlertSeverityMedium, now))
+	assert.False(t, quiet.Suppresses(models.AlertSeverityMedium, until))
+}
+
+func TestQuietHoursService_ScheduleAndDND(t *testing.T) {
+	ctx := context.Background()
+	repoManager := newAlertSnoozeTestRepoManager()
+	svc := NewQuietHoursService(repoManager, zap.NewNop())
+
+	quiet, err := svc.Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, quiet.Windows)
+
+	invalid := []*models.QuietHoursRequest{
+		{Timezone: "Mars/Base", Windows: []models.QuietHoursWindow{}},
+		{Windows: []models.QuietHoursWindow{{Days: []string{"someday"}, Start: "23:00", End: "07:00"}}},
+		{Windows: []models.QuietHoursWindow{{Days: []string{"monday"}, Start: "23:00", End: "23:00"}}},
+		{Windows: []models.QuietHoursWindow{{Days: []string{"monday"}, Start: "23:00", End: "07:00",
+			AllowSeverities: []models.AlertSeverity{"urgent"}}}},
+	}
+	for _, req := range invalid {
+		_, err := svc.SetSchedule(ctx, req, "u1")
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	}
+
+	_, err = svc.SetSchedule(ctx, &models.QuietHoursRequest{
+		Timezone: "Asia/Shanghai",
+		Windows:  []models.QuietHoursWindow{{Days: []string{"monday"}, Start: "23:00", End: "07:00"}},
+	}, "u1")
+	require.NoError(t, err)
+
+	// 开启和结束临时免打扰不影响每周时段
+	quiet, err = svc.StartDND(ctx, &models.DNDRequest{Minutes: 30}, "u1")
+	require.NoError(t, err)
+	require.NotNil(t, quiet.DNDUntil)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), *quiet.DNDUntil, time.Minute)
+	quiet, err = svc.EndDND(ctx, "u1")
+	require.NoError(t, err)
+	assert.Nil(t, quiet.DNDUntil)
+	assert.Len(t, quiet.Windows, 1)
+
+	require.NoError(t, svc.Delete(ctx, "u1"))
+	quiet, err = svc.Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, quiet.Windows)
+}
+
+func TestNotificationService_SkipsDuringQuietHours(t *testing.T) {
+	ctx := context.Background()
+	repoManager := newLocalizationTestRepoManager()
+	critical, high := uuid.New(), uuid.New()
+	repoManager.alerts.alerts[critical.String()] = &models.Alert{ID: critical.String(), Severity: models.AlertSeverityCritical}
+	repoManager.alerts.alerts[high.String()] = &models.Alert{ID: high.String(), Severity: models.AlertSeverityHigh}
+	until := time.Now().Add(time.Hour)
+	require.NoError(t, repoManager.quiet.Set(ctx, &models.QuietHours{
+		UserID: "u1", DNDUntil: &until, DNDAllowSeverities: []models.AlertSeverity{models.AlertSeverityCritical},
+	}))
+	svc := NewNotificationService(repoManager, testTranslator(t), nil, nil, nil, config.NotificationConfig{}, zap.NewNop())
+
+	for _, alertID := range []uuid.UUID{critical, high} {
+		require.NoError(t, svc.Send(ctx, &models.Notification{
+			AlertID: alertID, Type: models.NotificationTypeSMS, Recipient: "alice", Content: "checkout 告警",
+		}))
+	}
+	// 免打扰只作用于设置的用户
+	require.NoError(t, svc.Send(ctx, &models.Notification{
+		AlertID: high, Type: models.NotificationTypeSMS, Recipient: "bob", Content: "checkout 告警",
+	}))
+
+	sent := repoManager.notifications.sent
+	require.Len(t, sent, 2)
+	assert.Equal(t, critical, sent[0].AlertID)
+	assert.Equal(t, "bob", sent[1].Recipient)
+}
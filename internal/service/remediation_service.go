@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// RemediationExecutor 修复动作执行器，由 remediation 包按动作类型分发实现
+type RemediationExecutor interface {
+	Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error)
+}
+
+// remediationService 规则修复动作服务实现
+type remediationService struct {
+	repoManager repository.RepositoryManager
+	executor    RemediationExecutor
+	cfg         config.RemediationConfig
+	logger      *zap.Logger
+}
+
+// NewRemediationService 创建规则修复动作服务实例
+func NewRemediationService(repoManager repository.RepositoryManager, executor RemediationExecutor, cfg config.RemediationConfig, logger *zap.Logger) RemediationService {
+	return &remediationService{
+		repoManager: repoManager,
+		executor:    executor,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Enabled 是否启用自动修复
+func (s *remediationService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// Interval 安排和执行修复动作的周期
+func (s *remediationService) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+// Schedule 为新触发的告警安排其规则上的修复动作，需要审批的动作进入等待审批状态
+func (s *remediationService) Schedule(ctx context.Context) (int, error) {
+	alertIDs, err := s.repoManager.Remediation().ListAlertsToSchedule(ctx, time.Now().Add(-s.cfg.Lookback), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	scheduled := 0
+	for _, alertID := range alertIDs {
+		count, err := s.scheduleAlert(ctx, alertID)
+		if err != nil {
+			s.logger.Error("安排修复动作失败", zap.Error(err), zap.String("alert_id", alertID))
+			continue
+		}
+		scheduled += count
+	}
+
+	return scheduled, nil
+}
+
+// scheduleAlert 为单个告警创建修复动作执行记录
+func (s *remediationService) scheduleAlert(ctx context.Context, alertID string) (int, error) {
+	alert, err := s.repoManager.Alert().GetByID(ctx, alertID)
+	if err != nil {
+		return 0, fmt.Errorf("获取告警失败: %w", err)
+	}
+	if alert.RuleID == nil {
+		return 0, nil
+	}
+
+	rule, err := s.repoManager.Rule().GetByID(ctx, *alert.RuleID)
+	if err != nil {
+		return 0, fmt.Errorf("获取规则失败: %w", err)
+	}
+
+	scheduled := 0
+	for i, action := range rule.Actions {
+		if !action.IsRemediation() {
+			continue
+		}
+
+		execution := &models.RemediationExecution{
+			AlertID:     alert.ID,
+			RuleID:      rule.ID,
+			ActionIndex: i,
+			ActionName:  action.DisplayName(),
+			ActionType:  action.Type,
+			Action:      action,
+			Status:      models.RemediationStatusQueued,
+		}
+		if action.RequireApproval {
+			execution.Status = models.RemediationStatusPendingApproval
+		}
+
+		created, err := s.repoManager.Remediation().Create(ctx, execution)
+		if err != nil {
+			return scheduled, err
+		}
+		if created {
+			scheduled++
+			s.logger.Info("修复动作已安排", zap.String("alert_id", alert.ID),
+				zap.String("action", execution.ActionName), zap.String("status", string(execution.Status)))
+		}
+	}
+
+	return scheduled, nil
+}
+
+// ExecuteQueued 执行待执行的修复动作，结果写入执行记录并附加到告警历史
+func (s *remediationService) ExecuteQueued(ctx context.Context) (int, error) {
+	executions, err := s.repoManager.Remediation().ClaimQueued(ctx, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, execution := range executions {
+		s.execute(ctx, execution)
+	}
+
+	return len(executions), nil
+}
+
+// execute 执行单个修复动作
+func (s *remediationService) execute(ctx context.Context, execution *models.RemediationExecution) {
+	alert, err := s.repoManager.Alert().GetByID(ctx, execution.AlertID)
+	if err == nil {
+		execCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+		var result *models.RemediationResult
+		result, err = s.executor.Execute(execCtx, execution.Action, alert)
+		cancel()
+		if result != nil {
+			execution.Output = result.Output
+		}
+	} else {
+		err = fmt.Errorf("获取告警失败: %w", err)
+	}
+
+	historyAction := models.AlertHistoryActionRemediationSucceeded
+	execution.Status = models.RemediationStatusSucceeded
+	execution.Error = nil
+	if err != nil {
+		message := err.Error()
+		historyAction = models.AlertHistoryActionRemediationFailed
+		execution.Status = models.RemediationStatusFailed
+		execution.Error = &message
+		s.logger.Warn("修复动作执行失败", zap.Error(err),
+			zap.String("execution_id", execution.ID), zap.String("alert_id", execution.AlertID))
+	} else {
+		s.logger.Info("修复动作执行成功",
+			zap.String("execution_id", execution.ID), zap.String("alert_id", execution.AlertID))
+	}
+
+	if err := s.repoManager.Remediation().Finish(ctx, execution); err != nil {
+		s.logger.Error("保存修复动作执行结果失败", zap.Error(err), zap.String("execution_id", execution.ID))
+	}
+	s.addHistory(ctx, execution, historyAction, nil)
+}
+
+// Approve 批准等待审批的修复动作，由 Worker 在下一周期执行
+func (s *remediationService) Approve(ctx context.Context, id, userID string) (*models.RemediationExecution, error) {
+	return s.decide(ctx, id, true, userID)
+}
+
+// Reject 拒绝等待审批的修复动作
+func (s *remediationService) Reject(ctx context.Context, id, userID string) (*models.RemediationExecution, error) {
+	execution, err := s.decide(ctx, id, false, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.addHistory(ctx, execution, models.AlertHistoryActionRemediationRejected, &userID)
+	return execution, nil
+}
+
+// decide 审批修复动作，区分记录不存在和不在等待审批状态
+func (s *remediationService) decide(ctx context.Context, id string, approved bool, userID string) (*models.RemediationExecution, error) {
+	if _, err := s.repoManager.Remediation().GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	execution, err := s.repoManager.Remediation().Decide(ctx, id, approved, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRemediationNotPending) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("审批修复动作失败: %w", err)
+	}
+
+	s.logger.Info("修复动作已审批", zap.String("execution_id", id),
+		zap.Bool("approved", approved), zap.String("user_id", userID))
+	return execution, nil
+}
+
+// ListByAlert 获取告警的修复动作执行记录
+func (s *remediationService) ListByAlert(ctx context.Context, alertID string) ([]*models.RemediationExecution, error) {
+	exists, err := s.repoManager.Alert().Exists(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("检查告警是否存在失败: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrAlertNotFound
+	}
+
+	return s.repoManager.Remediation().ListByAlert(ctx, alertID)
+}
+
+// addHistory 将修复动作结果记录到告警历史，出现在告警时间线上
+func (s *remediationService) addHistory(ctx context.Context, execution *models.RemediationExecution, action string, userID *string) {
+	newValue := map[string]interface{}{
+		"execution_id": execution.ID,
+		"action":       execution.ActionName,
+		"action_type":  execution.ActionType,
+		"status":       execution.Status,
+	}
+	if execution.Error != nil {
+		newValue["error"] = *execution.Error
+	}
+
+	history := &models.AlertHistory{
+		ID:        uuid.New().String(),
+		AlertID:   execution.AlertID,
+		Action:    action,
+		NewValue:  newValue,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repoManager.Alert().AddHistory(ctx, history); err != nil {
+		s.logger.Warn("记录修复动作告警历史失败", zap.Error(err), zap.String("execution_id", execution.ID))
+	}
+}
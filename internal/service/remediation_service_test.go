@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeRemediationRepository 内存修复动作执行记录仓储
+type fakeRemediationRepository struct {
+	repository.RemediationRepository
+	pendingAlerts []string
+	executions    []*models.RemediationExecution
+}
+
+func (r *fakeRemediationRepository) ListAlertsToSchedule(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return r.pendingAlerts, nil
+}
+
+func (r *fakeRemediationRepository) Create(ctx context.Context, execution *models.RemediationExecution) (bool, error) {
+	for _, existing := range r.executions {
+		if existing.AlertID == execution.AlertID && existing.ActionIndex == execution.ActionIndex {
+			return false, nil
+		}
+	}
+	execution.ID = execution.ActionName
+	r.executions = append(r.executions, execution)
+	return true, nil
+}
+
+func (r *fakeRemediationRepository) GetByID(ctx context.Context, id string) (*models.RemediationExecution, error) {
+	for _, execution := range r.executions {
+		if execution.ID == id {
+			return execution, nil
+		}
+	}
+	return nil, models.ErrRemediationNotFound
+}
+
+func (r *fakeRemediationRepository) ClaimQueued(ctx context.Context, limit int) ([]*models.RemediationExecution, error) {
+	claimed := make([]*models.RemediationExecution, 0)
+	for _, execution := range r.executions {
+		if execution.Status == models.RemediationStatusQueued {
+			execution.Status = models.RemediationStatusRunning
+			claimed = append(claimed, execution)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeRemediationRepository) Finish(ctx context.Context, execution *models.RemediationExecution) error {
+	return nil
+}
+
+func (r *fakeRemediationRepository) Decide(ctx context.Context, id string, approved bool, userID string) (*models.RemediationExecution, error) {
+	execution, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if execution.Status != models.RemediationStatusPendingApproval {
+		return nil, models.ErrRemediationNotPending
+	}
+	execution.Status = models.RemediationStatusRejected
+	if approved {
+		execution.Status = models.RemediationStatusQueued
+	}
+	execution.ApprovedBy = &userID
+	return execution, nil
+}
+
+type fakeRemediationRuleRepository struct {
+	repository.RuleRepository
+	rule *models.Rule
+}
+
+func (r *fakeRemediationRuleRepository) GetByID(ctx context.Context, id string) (*models.Rule, error) {
+	return r.rule, nil
+}
+
+type remediationRepoManager struct {
+	*MockRepositoryManager
+	alerts       *fakeTimelineAlertRepository
+	rules        *fakeRemediationRuleRepository
+	remediations *fakeRemediationRepository
+}
+
+func (m *remediationRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func (m *remediationRepoManager) Rule() repository.RuleRepository { return m.rules }
+
+func (m *remediationRepoManager) Remediation() repository.RemediationRepository {
+	return m.remediations
+}
+
+// fakeRemediationExecutor 按动作目标返回成功或失败
+type fakeRemediationExecutor struct {
+	executed []string
+}
+
+func (e *fakeRemediationExecutor) Execute(ctx context.Context, action models.RuleAction, alert *models.Alert) (*models.RemediationResult, error) {
+	e.executed = append(e.executed, action.Target)
+	if action.Target == "broken" {
+		return &models.RemediationResult{Output: "exit 1"}, errors.New("command failed")
+	}
+	return &models.RemediationResult{Output: "ok"}, nil
+}
+
+func TestRemediationService_ScheduleAndExecute(t *testing.T) {
+	ruleID := "r1"
+	repoManager := &remediationRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		alerts:                &fakeTimelineAlertRepository{alert: &models.Alert{ID: "a1", RuleID: &ruleID}},
+		rules: &fakeRemediationRuleRepository{rule: &models.Rule{ID: ruleID, Actions: []models.RuleAction{
+			{Type: "email", Target: "ops@example.com"},
+			{Type: models.RemediationActionHTTP, Name: "cleanup", Target: "https://ops.example.com/cleanup"},
+			{Type: models.RemediationActionSalt, Name: "restart", Target: "broken"},
+			{Type: models.RemediationActionAWX, Name: "failover", Target: "42", RequireApproval: true},
+		}}},
+		remediations: &fakeRemediationRepository{pendingAlerts: []string{"a1"}},
+	}
+	executor := &fakeRemediationExecutor{}
+	svc := NewRemediationService(repoManager, executor, config.RemediationConfig{
+		Enabled: true, Timeout: time.Second, BatchSize: 10, Lookback: time.Hour,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	scheduled, err := svc.Schedule(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, scheduled)
+
+	// 重复安排不会生成新的执行记录
+	scheduled, err = svc.Schedule(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, scheduled)
+
+	executed, err := svc.ExecuteQueued(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, executed)
+	assert.Equal(t, []string{"https://ops.example.com/cleanup", "broken"}, executor.executed)
+
+	executions := repoManager.remediations.executions
+	assert.Equal(t, models.RemediationStatusSucceeded, executions[0].Status)
+	assert.Equal(t, models.RemediationStatusFailed, executions[1].Status)
+	assert.Equal(t, "exit 1", executions[1].Output)
+	assert.Equal(t, models.RemediationStatusPendingApproval, executions[2].Status)
+
+	// 执行结果记录到告警历史
+	require.Len(t, repoManager.alerts.histories, 2)
+	assert.Equal(t, models.AlertHistoryActionRemediationFailed, repoManager.alerts.histories[1].Action)
+
+	// 审批后进入待执行队列，由下一轮执行
+	approved, err := svc.Approve(ctx, "failover", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RemediationStatusQueued, approved.Status)
+
+	_, err = svc.Reject(ctx, "failover", "u1")
+	assert.ErrorIs(t, err, models.ErrRemediationNotPending)
+
+	executed, err = svc.ExecuteQueued(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, "42", executor.executed[2])
+
+	_, err = svc.Approve(ctx, "missing", "u1")
+	assert.ErrorIs(t, err, models.ErrRemediationNotFound)
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/repository"
+)
+
+const (
+	// ruleForecastFingerprintPrefix 预测告警指纹前缀，每个规则对应唯一指纹
+	ruleForecastFingerprintPrefix = "forecast:"
+	// ruleForecastMinSamples 拟合需要的最少样本数
+	ruleForecastMinSamples = 10
+
+	// Holt-Winters 的水平、趋势和季节平滑系数
+	holtWintersAlpha = 0.5
+	holtWintersBeta  = 0.2
+	holtWintersGamma = 0.1
+)
+
+// ForecastQuerier 执行区间查询，获取规则表达式的近期历史
+type ForecastQuerier interface {
+	QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]models.MetricSample, error)
+}
+
+// ForecastQuerierFactory 根据 Prometheus 数据源创建区间查询客户端
+type ForecastQuerierFactory func(dataSource *models.DataSource) (ForecastQuerier, error)
+
+// breakerForecastQuerier 在数据源熔断器保护下执行区间查询
+type breakerForecastQuerier struct {
+	querier ForecastQuerier
+	breaker *breaker.Breaker
+}
+
+// QueryRange 执行区间查询，数据源熔断时直接返回 breaker.ErrOpen
+func (q *breakerForecastQuerier) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]models.MetricSample, error) {
+	var samples []models.MetricSample
+	err := q.breaker.Do(ctx, func(ctx context.Context) error {
+		result, err := q.querier.QueryRange(ctx, expr, start, end, step)
+		samples = result
+		return err
+	})
+	// 超时返回时查询可能仍在执行，只有成功时才读取结果
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// ruleForecastService 规则预测服务实现
+type ruleForecastService struct {
+	repoManager  repository.RepositoryManager
+	alertService AlertService
+	newQuerier   ForecastQuerierFactory
+	cfg          config.RuleForecastConfig
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewRuleForecastService 创建规则预测服务实例
+func NewRuleForecastService(repoManager repository.RepositoryManager, alertService AlertService, newQuerier ForecastQuerierFactory, cfg config.RuleForecastConfig, logger *zap.Logger) RuleForecastService {
+	return &ruleForecastService{
+		repoManager:  repoManager,
+		alertService: alertService,
+		newQuerier:   newQuerier,
+		cfg:          cfg,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Interval 预测的周期
+func (s *ruleForecastService) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+// Forecast 立即预测规则何时达到阈值，不创建或解决预测告警，用于配置预测时预览结果
+func (s *ruleForecastService) Forecast(ctx context.Context, ruleID string) (*models.RuleForecast, error) {
+	rule, err := s.repoManager.Rule().GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := s.spec(rule)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("%w: 规则未配置 %s 注解", models.ErrInvalidInput, models.RuleAnnotationForecast)
+	}
+
+	querier, err := s.querier(ctx, rule.DataSourceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.forecast(ctx, querier, rule, spec, s.now())
+}
+
+// Evaluate 预测所有配置了预测的活跃规则，预测在预测范围内达到阈值时创建或更新预测告警，否则解决预测告警。
+// 单个规则查询失败时记录日志并保留已有的预测告警，返回触发中的预测告警数量
+func (s *ruleForecastService) Evaluate(ctx context.Context) (int, error) {
+	if !s.cfg.Enabled {
+		return 0, nil
+	}
+
+	rules, err := s.repoManager.Rule().GetActiveRules(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取活跃规则失败: %w", err)
+	}
+
+	now := s.now()
+	queriers := make(map[string]ForecastQuerier)
+	firing := 0
+	for _, rule := range rules {
+		spec, err := s.spec(rule)
+		if err != nil {
+			s.logger.Warn("规则的预测配置无效，已跳过", zap.Error(err), zap.String("rule_id", rule.ID))
+			continue
+		}
+		if spec == nil {
+			continue
+		}
+
+		querier, ok := queriers[rule.DataSourceID]
+		if !ok {
+			querier, err = s.querier(ctx, rule.DataSourceID)
+			if err != nil {
+				s.logger.Warn("创建预测查询客户端失败", zap.Error(err), zap.String("rule_id", rule.ID),
+					zap.String("data_source_id", rule.DataSourceID))
+				continue
+			}
+			queriers[rule.DataSourceID] = querier
+		}
+
+		forecast, err := s.forecast(ctx, querier, rule, spec, now)
+		if err != nil {
+			s.logger.Warn("规则预测失败", zap.Error(err), zap.String("rule_id", rule.ID))
+			continue
+		}
+		if forecast.BreachAt == nil {
+			s.resolveAlert(ctx, rule, now)
+			continue
+		}
+		if err := s.fireAlert(ctx, rule, spec, forecast, now); err != nil {
+			s.logger.Error("创建预测告警失败", zap.Error(err), zap.String("rule_id", rule.ID))
+			continue
+		}
+		firing++
+	}
+	return firing, nil
+}
+
+// spec 解析规则的预测配置，未配置的注解使用全局配置
+func (s *ruleForecastService) spec(rule *models.Rule) (*models.RuleForecastSpec, error) {
+	return models.ParseRuleForecast(rule, s.cfg.Lookback, s.cfg.Horizon, models.AlertSeverity(s.cfg.Severity))
+}
+
+// querier 创建数据源的区间查询客户端，只支持 Prometheus 数据源
+func (s *ruleForecastService) querier(ctx context.Context, dataSourceID string) (ForecastQuerier, error) {
+	dataSource, err := s.repoManager.DataSource().GetByID(ctx, dataSourceID)
+	if err != nil {
+		return nil, err
+	}
+	if dataSource == nil {
+		return nil, models.ErrDataSourceNotFound
+	}
+	if dataSource.Type != models.DataSourceTypePrometheus {
+		return nil, fmt.Errorf("%w: 预测只支持 Prometheus 数据源", models.ErrInvalidInput)
+	}
+	return s.newQuerier(dataSource)
+}
+
+// forecast 查询规则表达式在历史范围内的样本，按预测方法拟合后逐个步长外推，找出预测范围内首次达到阈值的时间
+func (s *ruleForecastService) forecast(ctx context.Context, querier ForecastQuerier, rule *models.Rule, spec *models.RuleForecastSpec, now time.Time) (*models.RuleForecast, error) {
+	step := s.cfg.Step
+	samples, err := querier.QueryRange(ctx, rule.Expression, now.Add(-spec.Lookback), now, step)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(samples))
+	var last time.Time
+	for _, sample := range samples {
+		if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			continue
+		}
+		values = append(values, sample.Value)
+		last = sample.Timestamp
+	}
+
+	predict, err := fitForecast(spec.Method, values, int(spec.Season/step))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RuleForecast{
+		RuleID:      rule.ID,
+		Method:      spec.Method,
+		Current:     values[len(values)-1],
+		Threshold:   spec.Threshold,
+		Below:       spec.Below,
+		Samples:     len(values),
+		Horizon:     spec.Horizon.String(),
+		EvaluatedAt: now,
+	}
+	if spec.Breached(result.Current) {
+		result.Breached = true
+		return result, nil
+	}
+
+	// 最后一个样本距今超过一个步长时从最后一个样本外推，预测范围仍从现在开始计算
+	deadline := now.Add(spec.Horizon)
+	for h := 1; ; h++ {
+		at := last.Add(time.Duration(h) * step)
+		if at.After(deadline) {
+			break
+		}
+		if spec.Breached(predict(h)) {
+			if at.Before(now) {
+				at = now
+			}
+			result.BreachAt = &at
+			break
+		}
+	}
+	return result, nil
+}
+
+// fitForecast 按预测方法拟合按步长排列的样本，返回预测最后一个样本之后第 h 个步长数值的函数。
+// season 为季节周期包含的步长数，只用于 holt_winters，不大于 1 时只考虑趋势
+func fitForecast(method models.ForecastMethod, values []float64, season int) (func(h int) float64, error) {
+	if len(values) < ruleForecastMinSamples {
+		return nil, fmt.Errorf("历史样本不足: 需要至少 %d 个样本，实际 %d 个", ruleForecastMinSamples, len(values))
+	}
+
+	switch method {
+	case models.ForecastMethodLinear:
+		return fitLinear(values), nil
+	case models.ForecastMethodHoltWinters:
+		if season <= 1 {
+			return fitHolt(values), nil
+		}
+		if len(values) < 2*season {
+			return nil, fmt.Errorf("历史样本不足: 季节周期包含 %d 个步长，需要至少两个周期的样本，实际 %d 个", season, len(values))
+		}
+		return fitHoltWinters(values, season), nil
+	default:
+		return nil, fmt.Errorf("不支持的预测方法 %s", method)
+	}
+}
+
+// fitLinear 最小二乘线性回归，以最后一个样本为原点
+func fitLinear(values []float64) func(h int) float64 {
+	n := float64(len(values))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := float64(i - len(values) + 1)
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+	return func(h int) float64 {
+		return intercept + slope*float64(h)
+	}
+}
+
+// fitHolt Holt 双指数平滑，跟踪水平和趋势
+func fitHolt(values []float64) func(h int) float64 {
+	level, trend := values[0], values[1]-values[0]
+	for _, value := range values[1:] {
+		previous := level
+		level = holtWintersAlpha*value + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-previous) + (1-holtWintersBeta)*trend
+	}
+	return func(h int) float64 {
+		return level + float64(h)*trend
+	}
+}
+
+// fitHoltWinters 加法 Holt-Winters 三指数平滑，用前两个季节周期的均值初始化水平和趋势
+func fitHoltWinters(values []float64, season int) func(h int) float64 {
+	mean := func(values []float64) float64 {
+		sum := 0.0
+		for _, value := range values {
+			sum += value
+		}
+		return sum / float64(len(values))
+	}
+
+	level := mean(values[:season])
+	trend := (mean(values[season:2*season]) - level) / float64(season)
+	seasonals := make([]float64, season)
+	for i := 0; i < season; i++ {
+		seasonals[i] = values[i] - level
+	}
+	for i := season; i < len(values); i++ {
+		seasonal := seasonals[i%season]
+		previous := level
+		level = holtWintersAlpha*(values[i]-seasonal) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-previous) + (1-holtWintersBeta)*trend
+		seasonals[i%season] = holtWintersGamma*(values[i]-level) + (1-holtWintersGamma)*seasonal
+	}
+
+	n := len(values)
+	return func(h int) float64 {
+		return level + float64(h)*trend + seasonals[(n-1+h)%season]
+	}
+}
+
+// fireAlert 创建或更新规则的预测告警，同一规则的预测告警指纹唯一，再次预测达到阈值时重新打开已解决的告警
+func (s *ruleForecastService) fireAlert(ctx context.Context, rule *models.Rule, spec *models.RuleForecastSpec, forecast *models.RuleForecast, now time.Time) error {
+	direction := "达到"
+	if spec.Below {
+		direction = "降至"
+	}
+	description := fmt.Sprintf("规则 %s 预计约 %.1f 小时后%s阈值 %g（当前值 %g，%s 预测）", rule.Name,
+		forecast.BreachIn().Hours(), direction, spec.Threshold, forecast.Current, spec.Method)
+	annotations := map[string]string{
+		models.AlertAnnotationForecastMethod:   string(spec.Method),
+		models.AlertAnnotationForecastBreachAt: forecast.BreachAt.Format(time.RFC3339),
+	}
+	current := forecast.Current
+
+	fingerprint := ruleForecastFingerprintPrefix + rule.ID
+	existing, err := s.repoManager.Alert().GetByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, models.ErrAlertNotFound) {
+		return fmt.Errorf("查询预测告警失败: %w", err)
+	}
+
+	if existing == nil {
+		labels := map[string]string{
+			"source":   string(models.AlertSourceForecast),
+			"rule_id":  rule.ID,
+			"forecast": string(spec.Method),
+		}
+		for key, value := range rule.Labels {
+			if _, reserved := labels[key]; !reserved {
+				labels[key] = value
+			}
+		}
+
+		// 告警名称带预测标记，超过长度限制时使用规则名称
+		name := rule.Name + "（预测）"
+		if len(name) > 200 {
+			name = rule.Name
+		}
+		ruleID := rule.ID
+		alert := &models.Alert{
+			RuleID:       &ruleID,
+			DataSourceID: rule.DataSourceID,
+			Name:         name,
+			Description:  description,
+			Severity:     spec.Severity,
+			Status:       models.AlertStatusFiring,
+			Source:       models.AlertSourceForecast,
+			Labels:       labels,
+			Annotations:  annotations,
+			Value:        &current,
+			Threshold:    &spec.Threshold,
+			Expression:   rule.Expression,
+			StartsAt:     now,
+			Fingerprint:  fingerprint,
+		}
+		if err := s.alertService.Create(ctx, alert); err != nil {
+			return err
+		}
+		s.logger.Warn("规则预测将达到阈值", zap.String("rule_id", rule.ID), zap.String("name", rule.Name),
+			zap.Time("breach_at", *forecast.BreachAt))
+		return nil
+	}
+
+	if existing.Status == models.AlertStatusResolved {
+		existing.Status = models.AlertStatusFiring
+		existing.StartsAt = now
+		existing.EndsAt = nil
+		existing.ResolvedAt = nil
+		existing.ResolvedBy = nil
+		existing.AckedAt = nil
+		existing.AckedBy = nil
+		delete(existing.Annotations, models.AlertAnnotationResolveReason)
+	}
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	for key, value := range annotations {
+		existing.Annotations[key] = value
+	}
+	existing.Description = description
+	existing.Severity = spec.Severity
+	existing.Value = &current
+	existing.Threshold = &spec.Threshold
+	existing.LastEvalAt = now
+	existing.EvalCount++
+	return s.alertService.Update(ctx, existing)
+}
+
+// resolveAlert 解决规则的预测告警，失败只记录日志
+func (s *ruleForecastService) resolveAlert(ctx context.Context, rule *models.Rule, now time.Time) {
+	alert, err := s.repoManager.Alert().GetByFingerprint(ctx, ruleForecastFingerprintPrefix+rule.ID)
+	if err != nil {
+		if !errors.Is(err, models.ErrAlertNotFound) {
+			s.logger.Error("查询预测告警失败", zap.Error(err), zap.String("rule_id", rule.ID))
+		}
+		return
+	}
+	if alert.Status == models.AlertStatusResolved {
+		return
+	}
+
+	alert.Status = models.AlertStatusResolved
+	alert.EndsAt = &now
+	alert.ResolvedAt = &now
+	alert.LastEvalAt = now
+	if err := s.alertService.Update(ctx, alert); err != nil {
+		s.logger.Error("解决预测告警失败", zap.Error(err), zap.String("rule_id", rule.ID))
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeForecastQuerier 按表达式返回以 now 结尾、按步长对齐的样本
+type fakeForecastQuerier struct {
+	series map[string]func(i int) float64
+	calls  int
+}
+
+func (q *fakeForecastQuerier) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]models.MetricSample, error) {
+	q.calls++
+	value := q.series[expr]
+	var samples []models.MetricSample
+	for i, at := 0, start; !at.After(end); i, at = i+1, at.Add(step) {
+		samples = append(samples, models.MetricSample{Timestamp: at, Value: value(i)})
+	}
+	return samples, nil
+}
+
+// fakeForecastRuleRepository 返回固定的规则
+type fakeForecastRuleRepository struct {
+	repository.RuleRepository
+	rules []*models.Rule
+}
+
+func (r *fakeForecastRuleRepository) GetActiveRules(ctx context.Context) ([]*models.Rule, error) {
+	return r.rules, nil
+}
+
+func (r *fakeForecastRuleRepository) GetByID(ctx context.Context, id string) (*models.Rule, error) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return nil, models.ErrRuleNotFound
+}
+
+type ruleForecastRepoManager struct {
+	*MockRepositoryManager
+	rules       *fakeForecastRuleRepository
+	dataSources *fakeKubernetesDataSourceRepository
+	alerts      *fakeHeartbeatAlertRepository
+}
+
+func (m *ruleForecastRepoManager) Rule() repository.RuleRepository { return m.rules }
+
+func (m *ruleForecastRepoManager) DataSource() repository.DataSourceRepository { return m.dataSources }
+
+func (m *ruleForecastRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func TestRuleForecastService_Evaluate(t *testing.T) {
+	threshold := func(v float64) *float64 { return &v }
+	forecastRule := func(id, expr string, limit float64, annotations map[string]string, conditions ...models.RuleCondition) *models.Rule {
+		return &models.Rule{ID: id, DataSourceID: "prom", Name: id, Expression: expr, Threshold: threshold(limit),
+			Annotations: annotations, Conditions: conditions, Labels: map[string]string{"team": "infra"}}
+	}
+	repoManager := &ruleForecastRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		rules: &fakeForecastRuleRepository{rules: []*models.Rule{
+			// 6 小时内从 40% 线性增长到 64%，约 6.6 小时后达到 90%
+			forecastRule("disk", "disk_used", 90, map[string]string{"forecast": "linear"}),
+			// 剩余空间按相同速度下降，低于阈值触发
+			forecastRule("free", "disk_free", 10, map[string]string{"forecast": "holt_winters"},
+				models.RuleCondition{Field: "value", Operator: models.RuleOperatorLT}),
+			// 预测范围只有 1 小时，不会达到阈值
+			forecastRule("short", "disk_used", 90, map[string]string{"forecast": "linear", "forecast_horizon": "1h"}),
+			// 已达到阈值时由规则本身告警
+			forecastRule("full", "disk_used", 50, map[string]string{"forecast": "linear"}),
+			// 按 2 小时周期波动且没有趋势，峰值低于阈值
+			forecastRule("daily", "cpu", 90, map[string]string{"forecast": "holt_winters", "forecast_season": "2h", "forecast_lookback": "8h"}),
+			// 未配置预测或配置无效的规则跳过
+			forecastRule("plain", "disk_used", 90, nil),
+			forecastRule("invalid", "disk_used", 90, map[string]string{"forecast": "arima"}),
+		}},
+		dataSources: &fakeKubernetesDataSourceRepository{dataSources: []*models.DataSource{
+			{ID: "prom", Type: models.DataSourceTypePrometheus},
+		}},
+		alerts: &fakeHeartbeatAlertRepository{alerts: map[string]*models.Alert{}},
+	}
+	querier := &fakeForecastQuerier{series: map[string]func(i int) float64{
+		"disk_used": func(i int) float64 { return 40 + 0.333*float64(i) },
+		"disk_free": func(i int) float64 { return 60 - 0.333*float64(i) },
+		"cpu":       func(i int) float64 { return 50 + 20*math.Sin(2*math.Pi*float64(i)/24) },
+	}}
+	alertService := &fakeHeartbeatAlertService{repo: repoManager.alerts}
+	cfg := config.RuleForecastConfig{Enabled: true, Lookback: 6 * time.Hour, Horizon: 24 * time.Hour, Step: 5 * time.Minute, Severity: "medium"}
+	svc := NewRuleForecastService(repoManager, alertService, func(dataSource *models.DataSource) (ForecastQuerier, error) {
+		return querier, nil
+	}, cfg, zap.NewNop()).(*ruleForecastService)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	firing, err := svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, firing)
+	require.Len(t, repoManager.alerts.alerts, 2)
+
+	alert := repoManager.alerts.alerts["forecast:disk"]
+	require.NotNil(t, alert)
+	assert.Equal(t, models.AlertSourceForecast, alert.Source)
+	assert.Equal(t, models.AlertSeverityMedium, alert.Severity)
+	assert.Equal(t, "disk（预测）", alert.Name)
+	assert.Equal(t, "infra", alert.Labels["team"])
+	assert.Equal(t, "linear", alert.Annotations[models.AlertAnnotationForecastMethod])
+	assert.Contains(t, alert.Description, "约 6.6 小时后达到阈值 90")
+	breachAt, err := time.Parse(time.RFC3339, alert.Annotations[models.AlertAnnotationForecastBreachAt])
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(395*time.Minute), breachAt, 5*time.Minute)
+
+	free := repoManager.alerts.alerts["forecast:free"]
+	require.NotNil(t, free)
+	assert.Contains(t, free.Description, "降至阈值 10")
+
+	// 增长停止后解决预测告警，再次预测达到阈值时重新打开
+	querier.series["disk_used"] = func(i int) float64 { return 64 }
+	_, err = svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusResolved, repoManager.alerts.alerts["forecast:disk"].Status)
+
+	querier.series["disk_used"] = func(i int) float64 { return 40 + 0.333*float64(i) }
+	_, err = svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusFiring, repoManager.alerts.alerts["forecast:disk"].Status)
+	assert.Equal(t, 2, alertService.created)
+
+	// 预览不创建告警，未配置预测的规则返回参数错误
+	forecast, err := svc.Forecast(ctx, "full")
+	require.NoError(t, err)
+	assert.True(t, forecast.Breached)
+	assert.Nil(t, forecast.BreachAt)
+	_, err = svc.Forecast(ctx, "plain")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 未开启时不查询
+	calls := querier.calls
+	svc.cfg.Enabled = false
+	firing, err = svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, firing)
+	assert.Equal(t, calls, querier.calls)
+}
+
+func TestFitForecast(t *testing.T) {
+	linear := make([]float64, 20)
+	for i := range linear {
+		linear[i] = 10 + 2*float64(i)
+	}
+	predict, err := fitForecast(models.ForecastMethodLinear, linear, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 50, predict(1), 1e-9)
+
+	predict, err = fitForecast(models.ForecastMethodHoltWinters, linear, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 58, predict(5), 0.5)
+
+	// 季节分量延续周期波动
+	seasonal := make([]float64, 48)
+	for i := range seasonal {
+		seasonal[i] = 100 + 10*math.Sin(2*math.Pi*float64(i)/12)
+	}
+	predict, err = fitForecast(models.ForecastMethodHoltWinters, seasonal, 12)
+	require.NoError(t, err)
+	assert.InDelta(t, 110, predict(4), 0.5)
+	assert.InDelta(t, 90, predict(10), 0.5)
+
+	_, err = fitForecast(models.ForecastMethodHoltWinters, seasonal[:20], 12)
+	assert.ErrorContains(t, err, "历史样本不足")
+	_, err = fitForecast(models.ForecastMethodLinear, linear[:5], 0)
+	assert.ErrorContains(t, err, "历史样本不足")
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -254,4 +256,42 @@ func (s *ruleService) Disable(ctx context.Context, id string) error {
 
 	s.logger.Info("规则禁用成功", zap.String("id", id), zap.String("name", existingRule.Name))
 	return nil
-}
\ No newline at end of file
+}
+// EvaluationLag 汇总启用规则的评估延迟，逾期最久的规则排在最前
+func (s *ruleService) EvaluationLag(ctx context.Context) (*models.RuleEvaluationLagReport, error) {
+	lags, err := s.repoManager.Rule().ListEvaluationLag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &models.RuleEvaluationLagReport{Total: len(lags), Rules: lags, GeneratedAt: now}
+	if report.Rules == nil {
+		report.Rules = []*models.RuleEvaluationLag{}
+	}
+	for _, lag := range lags {
+		lag.SetOverdue(now)
+		if lag.LastDelayMs > 0 {
+			report.Late++
+		}
+		if lag.MissedEvals > 0 {
+			report.Missed++
+		}
+		if lag.OverdueMs > 0 {
+			report.Overdue++
+		}
+		if lag.LastDelayMs > report.MaxDelayMs {
+			report.MaxDelayMs = lag.LastDelayMs
+		}
+		if lag.OverdueMs > report.MaxOverdueMs {
+			report.MaxOverdueMs = lag.OverdueMs
+		}
+	}
+	sort.SliceStable(report.Rules, func(i, j int) bool {
+		if report.Rules[i].OverdueMs != report.Rules[j].OverdueMs {
+			return report.Rules[i].OverdueMs > report.Rules[j].OverdueMs
+		}
+		return report.Rules[i].LastDelayMs > report.Rules[j].LastDelayMs
+	})
+	return report, nil
+}
@@ -40,6 +40,14 @@ func (m *MockRuleRepository) GetByName(ctx context.Context, name string) (*model
 	return args.Get(0).(*models.Rule), args.Error(1)
 }
 
+func (m *MockRuleRepository) ListByIDs(ctx context.Context, ids []string) ([]*models.Rule, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Rule), args.Error(1)
+}
+
 func (m *MockRuleRepository) List(ctx context.Context, filter *models.RuleFilter) (*models.RuleList, error) {
 	args := m.Called(ctx, filter)
 	return args.Get(0).(*models.RuleList), args.Error(1)
@@ -177,6 +185,14 @@ func (m *MockRuleRepository) UpdateLastEvaluation(ctx context.Context, id string
 	return args.Error(0)
 }
 
+func (m *MockRuleRepository) ListEvaluationLag(ctx context.Context) ([]*models.RuleEvaluationLag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RuleEvaluationLag), args.Error(1)
+}
+
 // MockRuleRepositoryManager 模拟仓储管理器
 type MockRuleRepositoryManager struct {
 	mockRuleRepo *MockRuleRepository
@@ -222,6 +238,174 @@ func (m *MockRuleRepositoryManager) Notification() repository.NotificationReposi
 	return nil
 }
 
+func (m *MockRuleRepositoryManager) CustomField() repository.CustomFieldRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) KnowledgeReview() repository.KnowledgeReviewRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) KnowledgeComment() repository.KnowledgeCommentRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) KnowledgeACL() repository.KnowledgeACLRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) WebhookIntegration() repository.WebhookIntegrationRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) FeatureFlag() repository.FeatureFlagRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Dashboard() repository.DashboardRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Postmortem() repository.PostmortemRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Remediation() repository.RemediationRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) ChatUserMapping() repository.ChatUserMappingRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) ITSM() repository.ITSMRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Heartbeat() repository.HeartbeatRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) ServiceCatalog() repository.ServiceCatalogRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) SLO() repository.SLORepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AlertSnooze() repository.AlertSnoozeRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) NotificationRouting() repository.NotificationRoutingRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) LanguagePreference() repository.LanguagePreferenceRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Tag() repository.TagRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Partition() repository.PartitionRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Retention() repository.RetentionRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AuditLog() repository.AuditLogRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AssignmentPolicy() repository.AssignmentPolicyRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Handover() repository.HandoverRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AlertActionLink() repository.AlertActionLinkRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Push() repository.PushRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Inbox() repository.InboxRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) QuietHours() repository.QuietHoursRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) ChangeEvent() repository.ChangeEventRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) NotificationAttempt() repository.NotificationAttemptRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) WebhookSubscription() repository.WebhookSubscriptionRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) TicketImport() repository.TicketImportRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AlertStats() repository.AlertStatsRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) TicketSurvey() repository.TicketSurveyRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) KnowledgeFeedback() repository.KnowledgeFeedbackRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Approval() repository.ApprovalRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) IngestRejection() repository.IngestRejectionRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AsyncTask() repository.AsyncTaskRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) NotificationChannel() repository.NotificationChannelRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) Metric() repository.MetricRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) TicketSLAPause() repository.TicketSLAPauseRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) AlertSeverityRule() repository.AlertSeverityRuleRepository {
+	return nil
+}
+
+func (m *MockRuleRepositoryManager) StatusPage() repository.StatusPageRepository {
+	return nil
+}
+
 func (m *MockRuleRepositoryManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
 	return m, nil
 }
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// serviceCatalogCacheTTL 归属匹配使用的服务目录快照有效期，本实例修改服务目录时立即失效
+	serviceCatalogCacheTTL = 30 * time.Second
+
+	// ServiceSLOMinWindow SLO 统计窗口的最小值
+	ServiceSLOMinWindow = time.Hour
+	// ServiceSLOMaxWindow SLO 统计窗口的最大值
+	ServiceSLOMaxWindow = 90 * 24 * time.Hour
+)
+
+// serviceCatalogService 服务目录服务实现
+type serviceCatalogService struct {
+	repoManager repository.RepositoryManager
+	logger      *zap.Logger
+
+	mu       sync.Mutex
+	snapshot []*models.CatalogService
+	loadedAt time.Time
+}
+
+// NewServiceCatalogService 创建服务目录服务实例
+func NewServiceCatalogService(repoManager repository.RepositoryManager, logger *zap.Logger) ServiceCatalogService {
+	return &serviceCatalogService{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// List 获取全部服务
+func (s *serviceCatalogService) List(ctx context.Context) ([]*models.CatalogService, error) {
+	return s.repoManager.ServiceCatalog().List(ctx)
+}
+
+// Get 获取服务详情，包含直接依赖该服务的服务
+func (s *serviceCatalogService) Get(ctx context.Context, id string) (*models.CatalogServiceDetail, error) {
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CatalogServiceDetail{
+		CatalogService: service,
+		Dependents:     dependentsOf(services, service.Name),
+	}, nil
+}
+
+// Create 创建服务
+func (s *serviceCatalogService) Create(ctx context.Context, req *models.CatalogServiceRequest, createdBy string) (*models.CatalogService, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	service := &models.CatalogService{CreatedBy: createdBy}
+	req.ApplyTo(service)
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkServiceDependencies(services, service); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.ServiceCatalog().Create(ctx, service); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("创建服务", zap.String("service_id", service.ID), zap.String("name", service.Name),
+		zap.String("owner_team", service.OwnerTeam), zap.String("tier", string(service.Tier)))
+	return service, nil
+}
+
+// Update 更新服务。被其他服务依赖的服务不能改名，避免依赖关系指向不存在的服务
+func (s *serviceCatalogService) Update(ctx context.Context, id string, req *models.CatalogServiceRequest) (*models.CatalogService, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := service.Name
+	req.ApplyTo(service)
+	if service.Name != oldName {
+		if dependents := dependentsOf(services, oldName); len(dependents) > 0 {
+			return nil, fmt.Errorf("%w，不能修改名称: 被 %v 依赖", models.ErrCatalogServiceInUse, dependents)
+		}
+	}
+	if err := checkServiceDependencies(services, service); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.ServiceCatalog().Update(ctx, service); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return service, nil
+}
+
+// Delete 删除服务，被其他服务依赖或定义了 SLO 的服务不能删除
+func (s *serviceCatalogService) Delete(ctx context.Context, id string) error {
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return err
+	}
+	if dependents := dependentsOf(services, service.Name); len(dependents) > 0 {
+		return fmt.Errorf("%w，不能删除: 被 %v 依赖", models.ErrCatalogServiceInUse, dependents)
+	}
+	slos, err := s.repoManager.SLO().List(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(slos) > 0 {
+		return fmt.Errorf("%w，不能删除: 存在 %d 个SLO", models.ErrCatalogServiceInUse, len(slos))
+	}
+
+	if err := s.repoManager.ServiceCatalog().Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// SLO 统计服务在最近 window 时长内的可用性、错误预算、告警和工单
+func (s *serviceCatalogService) SLO(ctx context.Context, id string, window time.Duration) (*models.ServiceSLO, error) {
+	if window < ServiceSLOMinWindow || window > ServiceSLOMaxWindow {
+		return nil, fmt.Errorf("%w: 统计窗口必须在 %s 到 %s 之间", models.ErrInvalidInput, ServiceSLOMinWindow, ServiceSLOMaxWindow)
+	}
+
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-window)
+	windows, err := s.repoManager.ServiceCatalog().ListAlertWindows(ctx, service.Name, start, end)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := s.repoManager.ServiceCatalog().GetTicketStats(ctx, service.Name, start)
+	if err != nil {
+		return nil, err
+	}
+
+	slo := computeServiceSLO(service, windows, start, end)
+	slo.Tickets = *tickets
+	return slo, nil
+}
+
+// Attribute 根据标签确定告警或工单所属的服务，没有匹配的服务时返回 nil。
+// 标签中已指定服务目录中的服务时直接使用；指定的服务不在服务目录中时不归属，避免覆盖来源系统的标记。
+// 多个服务匹配时选择匹配标签最多的选择器所属的服务，再按等级和名称排序
+func (s *serviceCatalogService) Attribute(ctx context.Context, labels map[string]string) (*models.CatalogService, error) {
+	services, err := s.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name, ok := labels[models.ServiceLabel]; ok {
+		for _, service := range services {
+			if service.Name == name {
+				return service, nil
+			}
+		}
+		return nil, nil
+	}
+
+	var best *models.CatalogService
+	bestScore := 0
+	for _, service := range services {
+		score := service.Match(labels)
+		if score == 0 {
+			continue
+		}
+		if best == nil || score > bestScore ||
+			(score == bestScore && (service.Tier < best.Tier || (service.Tier == best.Tier && service.Name < best.Name))) {
+			best, bestScore = service, score
+		}
+	}
+	return best, nil
+}
+
+// services 获取服务目录快照，过期后重新加载
+func (s *serviceCatalogService) services(ctx context.Context) ([]*models.CatalogService, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snapshot != nil && time.Since(s.loadedAt) < serviceCatalogCacheTTL {
+		return s.snapshot, nil
+	}
+
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshot = services
+	s.loadedAt = time.Now()
+	return services, nil
+}
+
+// invalidate 使服务目录快照失效
+func (s *serviceCatalogService) invalidate() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+}
+
+// checkServiceDependencies 检查依赖的服务都存在且依赖关系不成环
+func checkServiceDependencies(services []*models.CatalogService, service *models.CatalogService) error {
+	graph := make(map[string][]string, len(services)+1)
+	for _, existing := range services {
+		if existing.ID != service.ID {
+			graph[existing.Name] = existing.Dependencies
+		}
+	}
+	for _, dependency := range service.Dependencies {
+		if _, ok := graph[dependency]; !ok {
+			return fmt.Errorf("%w: 依赖的服务 %s 不存在", models.ErrInvalidInput, dependency)
+		}
+	}
+	graph[service.Name] = service.Dependencies
+
+	// 从当前服务出发深度优先遍历，回到当前服务即成环
+	visited := make(map[string]bool)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		for _, dependency := range graph[name] {
+			if dependency == service.Name {
+				return true
+			}
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+			if visit(dependency) {
+				return true
+			}
+		}
+		return false
+	}
+	if visit(service.Name) {
+		return fmt.Errorf("%w: 服务 %s 的依赖关系存在循环", models.ErrInvalidInput, service.Name)
+	}
+	return nil
+}
+
+// dependentsOf 直接依赖指定服务的服务名称
+func dependentsOf(services []*models.CatalogService, name string) []string {
+	dependents := []string{}
+	for _, service := range services {
+		if service.DependsOn(name) {
+			dependents = append(dependents, service.Name)
+		}
+	}
+	return dependents
+}
+
+// computeServiceSLO 根据告警区间计算 [start, end) 内的可用性。critical 和 high 告警持续期间视为不可用，
+// 重叠的区间合并后计算；告警数量和平均解决时长只统计窗口内开始的告警
+func computeServiceSLO(service *models.CatalogService, windows []*models.ServiceAlertWindow, start, end time.Time) *models.ServiceSLO {
+	slo := &models.ServiceSLO{
+		ServiceID:   service.ID,
+		Name:        service.Name,
+		OwnerTeam:   service.OwnerTeam,
+		Tier:        service.Tier,
+		WindowStart: start,
+		WindowEnd:   end,
+		Target:      service.SLOTarget,
+	}
+
+	type interval struct{ from, to time.Time }
+	var outages []interval
+	var resolved int64
+	var repairTime time.Duration
+	for _, window := range windows {
+		to := end
+		if window.ResolvedAt != nil {
+			if window.ResolvedAt.Before(end) {
+				to = *window.ResolvedAt
+			}
+		} else {
+			slo.OpenAlerts++
+		}
+
+		if !window.StartsAt.Before(start) {
+			slo.AlertCount++
+			if window.ResolvedAt != nil {
+				resolved++
+				repairTime += window.ResolvedAt.Sub(window.StartsAt)
+			}
+		}
+
+		if window.Severity != models.AlertSeverityCritical && window.Severity != models.AlertSeverityHigh {
+			continue
+		}
+		from := window.StartsAt
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(from) {
+			outages = append(outages, interval{from, to})
+		}
+	}
+	if resolved > 0 {
+		slo.MTTRSeconds = int64((repairTime / time.Duration(resolved)) / time.Second)
+	}
+
+	sort.Slice(outages, func(i, j int) bool { return outages[i].from.Before(outages[j].from) })
+	var downtime time.Duration
+	var current *interval
+	for i := range outages {
+		switch {
+		case current == nil:
+			current = &outages[i]
+		case !outages[i].from.After(current.to):
+			if outages[i].to.After(current.to) {
+				current.to = outages[i].to
+			}
+		default:
+			downtime += current.to.Sub(current.from)
+			current = &outages[i]
+		}
+	}
+	if current != nil {
+		downtime += current.to.Sub(current.from)
+	}
+
+	total := end.Sub(start)
+	budget := time.Duration(float64(total) * (100 - service.SLOTarget) / 100)
+	slo.DowntimeSeconds = int64(downtime / time.Second)
+	slo.ErrorBudgetSeconds = int64(budget / time.Second)
+	slo.Availability = 100 * (1 - float64(downtime)/float64(total))
+	if budget > 0 {
+		slo.ErrorBudgetRemaining = 100 * float64(budget-downtime) / float64(budget)
+	}
+	return slo
+}
+
+// attributeLabels 将服务归属写入标签，已有的 team 标签不覆盖
+func attributeLabels(labels map[string]string, service *models.CatalogService) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[models.ServiceLabel] = service.Name
+	if _, ok := labels[models.TeamLabel]; !ok && service.OwnerTeam != "" {
+		labels[models.TeamLabel] = service.OwnerTeam
+	}
+	return labels
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeServiceCatalogRepository 内存中的服务目录
+type fakeServiceCatalogRepository struct {
+	repository.ServiceCatalogRepository
+	services []*models.CatalogService
+	lists    int
+	// active 各服务活跃告警按级别的数量
+	active []*models.ServiceAlertCount
+}
+
+func (r *fakeServiceCatalogRepository) Create(ctx context.Context, service *models.CatalogService) error {
+	service.ID = service.Name
+	r.services = append(r.services, service)
+	return nil
+}
+
+func (r *fakeServiceCatalogRepository) GetByID(ctx context.Context, id string) (*models.CatalogService, error) {
+	for _, service := range r.services {
+		if service.ID == id {
+			copied := *service
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrCatalogServiceNotFound
+}
+
+func (r *fakeServiceCatalogRepository) List(ctx context.Context) ([]*models.CatalogService, error) {
+	r.lists++
+	return r.services, nil
+}
+
+func (r *fakeServiceCatalogRepository) CountActiveAlerts(ctx context.Context, name string) ([]*models.ServiceAlertCount, error) {
+	var counts []*models.ServiceAlertCount
+	for _, count := range r.active {
+		if name == "" || count.Service == name {
+			counts = append(counts, count)
+		}
+	}
+	return counts, nil
+}
+
+func (r *fakeServiceCatalogRepository) Update(ctx context.Context, service *models.CatalogService) error {
+	for i, existing := range r.services {
+		if existing.ID == service.ID {
+			r.services[i] = service
+		}
+	}
+	return nil
+}
+
+// fakeServiceAlertRepository 保存创建的告警、重复触发的采样时间和告警历史
+type fakeServiceAlertRepository struct {
+	repository.AlertRepository
+	alerts    map[string]*models.Alert
+	sampledAt map[string]time.Time
+	histories []*models.AlertHistory
+}
+
+func (r *fakeServiceAlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	r.alerts[alert.ID] = alert
+	return nil
+}
+
+func (r *fakeServiceAlertRepository) GetByID(ctx context.Context, id string) (*models.Alert, error) {
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, models.ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+func (r *fakeServiceAlertRepository) GetOpenByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	for _, alert := range r.alerts {
+		if alert.Fingerprint == fingerprint && alert.Status != models.AlertStatusResolved {
+			copied := *alert
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrAlertNotFound
+}
+
+func (r *fakeServiceAlertRepository) RecordOccurrence(ctx context.Context, id string, at time.Time, value *float64, sampleInterval time.Duration) (*models.AlertOccurrence, error) {
+	alert, ok := r.alerts[id]
+	if !ok || alert.Status == models.AlertStatusResolved {
+		return nil, models.ErrAlertNotFound
+	}
+	alert.OccurrenceCount++
+	alert.LastSeenAt = at
+	if value != nil {
+		alert.Value = value
+	}
+
+	if r.sampledAt == nil {
+		r.sampledAt = make(map[string]time.Time)
+	}
+	last, sampled := r.sampledAt[id]
+	sampled = !sampled || !last.After(at.Add(-sampleInterval))
+	if sampled {
+		r.sampledAt[id] = at
+	}
+	return &models.AlertOccurrence{AlertID: id, Count: alert.OccurrenceCount, SeenAt: at, Sampled: sampled}, nil
+}
+
+func (r *fakeServiceAlertRepository) AddHistory(ctx context.Context, history *models.AlertHistory) error {
+	r.histories = append(r.histories, history)
+	return nil
+}
+
+type serviceCatalogRepoManager struct {
+	*MockRepositoryManager
+	catalog *fakeServiceCatalogRepository
+	alerts  *fakeServiceAlertRepository
+}
+
+func (m *serviceCatalogRepoManager) ServiceCatalog() repository.ServiceCatalogRepository {
+	return m.catalog
+}
+
+func (m *serviceCatalogRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func newServiceCatalogTestService(t *testing.T) (*serviceCatalogRepoManager, ServiceCatalogService) {
+	repoManager := &serviceCatalogRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		catalog:               &fakeServiceCatalogRepository{},
+		alerts:                &fakeServiceAlertRepository{alerts: map[string]*models.Alert{}},
+	}
+	svc := NewServiceCatalogService(repoManager, zap.NewNop())
+
+	ctx := context.Background()
+	for _, req := range []*models.CatalogServiceRequest{
+		{Name: "postgres", OwnerTeam: "dba", Tier: models.ServiceTier1,
+			LabelSelectors: []models.LabelSelector{{"job": "postgres*"}}},
+		{Name: "checkout", OwnerTeam: "payments", Tier: models.ServiceTier1, Dependencies: []string{"postgres"},
+			LabelSelectors: []models.LabelSelector{{"namespace": "checkout"}, {"namespace": "checkout", "job": "postgres*"}}},
+	} {
+		_, err := svc.Create(ctx, req, "admin")
+		require.NoError(t, err)
+	}
+	return repoManager, svc
+}
+
+func TestServiceCatalogService_Dependencies(t *testing.T) {
+	_, svc := newServiceCatalogTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, &models.CatalogServiceRequest{Name: "web", OwnerTeam: "frontend", Dependencies: []string{"cart"}}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// postgres -> checkout -> postgres 成环
+	_, err = svc.Update(ctx, "postgres", &models.CatalogServiceRequest{Name: "postgres", OwnerTeam: "dba", Dependencies: []string{"checkout"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	_, err = svc.Update(ctx, "postgres", &models.CatalogServiceRequest{Name: "pg", OwnerTeam: "dba"})
+	assert.ErrorIs(t, err, models.ErrCatalogServiceInUse)
+	assert.ErrorIs(t, svc.Delete(ctx, "postgres"), models.ErrCatalogServiceInUse)
+
+	detail, err := svc.Get(ctx, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"checkout"}, detail.Dependents)
+}
+
+func TestServiceCatalogService_Attribute(t *testing.T) {
+	repoManager, svc := newServiceCatalogTestService(t)
+	ctx := context.Background()
+
+	// 匹配标签最多的选择器优先
+	service, err := svc.Attribute(ctx, map[string]string{"namespace": "checkout", "job": "postgres-exporter"})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", service.Name)
+
+	service, err = svc.Attribute(ctx, map[string]string{"job": "postgres-exporter"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", service.Name)
+
+	// 显式指定的服务优先，不在服务目录中的服务不归属
+	service, err = svc.Attribute(ctx, map[string]string{"service": "postgres", "namespace": "checkout"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", service.Name)
+	service, err = svc.Attribute(ctx, map[string]string{"service": "legacy", "namespace": "checkout"})
+	require.NoError(t, err)
+	assert.Nil(t, service)
+
+	// 快照有效期内不重复加载服务目录
+	lists := repoManager.catalog.lists
+	_, err = svc.Attribute(ctx, map[string]string{"namespace": "other"})
+	require.NoError(t, err)
+	assert.Equal(t, lists, repoManager.catalog.lists)
+}
+
+func TestAlertAndTicketAttribution(t *testing.T) {
+	repoManager, catalog := newServiceCatalogTestService(t)
+	ctx := context.Background()
+
+	alertSvc := NewAlertService(repoManager.alerts, nil, catalog, nil, nil, nil, nil, nil, nil, nil, 0, zap.NewNop())
+	alert := &models.Alert{
+		DataSourceID: "ds1",
+		Name:         "HighLatency",
+		Description:  "结算接口 P99 延迟超过 2s",
+		Severity:     models.AlertSeverityHigh,
+		Status:       models.AlertStatusFiring,
+		Expression:   "histogram_quantile(0.99, checkout_latency_bucket) > 2",
+		Source:       models.AlertSourcePrometheus,
+		Labels:       map[string]string{"namespace": "checkout", "team": "sre"},
+		StartsAt:     time.Now(),
+		Fingerprint:  "checkout-latency",
+	}
+	require.NoError(t, alertSvc.Create(ctx, alert))
+	assert.Equal(t, "checkout", alert.Labels["service"])
+	assert.Equal(t, "sre", alert.Labels["team"], "已有的 team 标签不覆盖")
+
+	ticketSvc := &ticketService{repoManager: repoManager, services: catalog, logger: zap.NewNop()}
+	ticket := &models.Ticket{Title: "结算延迟升高", AlertID: &alert.ID, Tags: []string{"latency"}}
+	ticketSvc.attributeService(ctx, ticket)
+	assert.Equal(t, "checkout", ticket.Labels["service"])
+	assert.Equal(t, "payments", ticket.Labels["team"])
+	assert.Equal(t, []string{"latency", "service:checkout"}, ticket.Tags)
+	require.NotNil(t, ticket.TeamName)
+	assert.Equal(t, "payments", *ticket.TeamName)
+}
+
+func TestComputeServiceSLO(t *testing.T) {
+	end := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	start := end.Add(-24 * time.Hour)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	resolved := func(minutes int) *time.Time { t := at(minutes); return &t }
+
+	service := &models.CatalogService{ID: "s1", Name: "checkout", SLOTarget: 99}
+	windows := []*models.ServiceAlertWindow{
+		// 窗口开始前触发，只计算窗口内的部分
+		{Severity: models.AlertSeverityCritical, StartsAt: at(-60), ResolvedAt: resolved(6)},
+		// 两个重叠的告警合并计算
+		{Severity: models.AlertSeverityHigh, StartsAt: at(120), ResolvedAt: resolved(132)},
+		{Severity: models.AlertSeverityCritical, StartsAt: at(126), ResolvedAt: resolved(138)},
+		// 低级别告警不影响可用性
+		{Severity: models.AlertSeverityLow, StartsAt: at(300)},
+	}
+
+	slo := computeServiceSLO(service, windows, start, end)
+	// 不可用 0.1h + 0.3h，错误预算为 24h 的 1%
+	assert.Equal(t, int64(1440), slo.DowntimeSeconds)
+	assert.Equal(t, int64(864), slo.ErrorBudgetSeconds)
+	assert.InDelta(t, 100*(1-1440.0/86400), slo.Availability, 0.0001)
+	assert.InDelta(t, 100*(864.0-1440)/864, slo.ErrorBudgetRemaining, 0.0001)
+	assert.Equal(t, 3, slo.AlertCount)
+	assert.Equal(t, 1, slo.OpenAlerts)
+	assert.Equal(t, int64(720), slo.MTTRSeconds)
+}
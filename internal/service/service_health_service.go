@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// serviceHealthService 服务健康度服务实现
+type serviceHealthService struct {
+	repoManager repository.RepositoryManager
+	weights     map[models.AlertSeverity]int
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewServiceHealthService 创建服务健康度服务实例，告警级别权重无效时使用默认权重
+func NewServiceHealthService(repoManager repository.RepositoryManager, cfg config.ServiceHealthConfig, logger *zap.Logger) ServiceHealthService {
+	raw, err := cfg.ParseWeights()
+	if err != nil {
+		logger.Warn("服务健康度的告警级别权重无效，使用默认权重", zap.Error(err))
+		raw, _ = config.ServiceHealthConfig{}.ParseWeights()
+	}
+	weights := make(map[models.AlertSeverity]int, len(raw))
+	for severity, weight := range raw {
+		weights[models.AlertSeverity(severity)] = weight
+	}
+	return &serviceHealthService{
+		repoManager: repoManager,
+		weights:     weights,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Health 计算单个服务的健康度
+func (s *serviceHealthService) Health(ctx context.Context, id string) (*models.ServiceHealth, error) {
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repoManager.ServiceCatalog().CountActiveAlerts(ctx, service.Name)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewServiceHealth(service, counts, s.weights, s.now()), nil
+}
+
+// Rollup 计算服务目录中全部服务的健康度，活跃告警只统计一次
+func (s *serviceHealthService) Rollup(ctx context.Context) (*models.ServiceHealthRollup, error) {
+	services, err := s.repoManager.ServiceCatalog().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repoManager.ServiceCatalog().CountActiveAlerts(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byService := make(map[string][]*models.ServiceAlertCount)
+	for _, count := range counts {
+		byService[count.Service] = append(byService[count.Service], count)
+	}
+	now := s.now()
+	health := make([]*models.ServiceHealth, 0, len(services))
+	for _, service := range services {
+		health = append(health, models.NewServiceHealth(service, byService[service.Name], s.weights, now))
+	}
+	return models.NewServiceHealthRollup(health, now), nil
+}
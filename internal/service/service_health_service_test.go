@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+)
+
+func TestServiceHealthService_Health(t *testing.T) {
+	repoManager, _ := newServiceCatalogTestService(t)
+	repoManager.catalog.active = []*models.ServiceAlertCount{
+		{Service: "checkout", Severity: models.AlertSeverityLow, Count: 3},
+		{Service: "checkout", Severity: models.AlertSeverityHigh, Count: 1},
+		{Service: "postgres", Severity: models.AlertSeverityInfo, Count: 2},
+	}
+	svc := NewServiceHealthService(repoManager, config.ServiceHealthConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	// 最高级别决定健康状态，权重为 0 的 info 告警只计数
+	health, err := svc.Health(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentPartialOutage, health.State)
+	assert.Equal(t, models.AlertSeverityHigh, health.WorstSeverity)
+	assert.Equal(t, 40, health.Score)
+	assert.Equal(t, int64(4), health.ActiveAlerts)
+	assert.Equal(t, int64(3), health.BySeverity[models.AlertSeverityLow])
+
+	health, err = svc.Health(ctx, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentOperational, health.State)
+	assert.Empty(t, health.WorstSeverity)
+	assert.Equal(t, 100, health.Score)
+	assert.Equal(t, int64(2), health.ActiveAlerts)
+
+	_, err = svc.Health(ctx, "cart")
+	assert.ErrorIs(t, err, models.ErrCatalogServiceNotFound)
+
+	rollup, err := svc.Rollup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentPartialOutage, rollup.State)
+	require.Len(t, rollup.Services, 2)
+	assert.Equal(t, "checkout", rollup.Services[0].Name)
+	assert.Equal(t, 1, rollup.Counts[models.StatusComponentPartialOutage])
+	assert.Equal(t, 1, rollup.Counts[models.StatusComponentOperational])
+}
+
+func TestServiceHealthService_Weights(t *testing.T) {
+	repoManager, _ := newServiceCatalogTestService(t)
+	repoManager.catalog.active = []*models.ServiceAlertCount{
+		{Service: "checkout", Severity: models.AlertSeverityHigh, Count: 1},
+		{Service: "postgres", Severity: models.AlertSeverityInfo, Count: 1},
+	}
+	ctx := context.Background()
+
+	// 调整后的权重改变健康状态，未配置的级别沿用默认权重
+	svc := NewServiceHealthService(repoManager, config.ServiceHealthConfig{Weights: "high=100, info=20"}, zap.NewNop())
+	rollup, err := svc.Rollup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentMajorOutage, rollup.Services[0].State)
+	assert.Equal(t, models.StatusComponentDegradedPerformance, rollup.Services[1].State)
+
+	// 无效的权重配置使用默认权重
+	_, err = config.ServiceHealthConfig{Weights: "fatal=100"}.ParseWeights()
+	assert.Error(t, err)
+	_, err = config.ServiceHealthConfig{Weights: "high=150"}.ParseWeights()
+	assert.Error(t, err)
+	svc = NewServiceHealthService(repoManager, config.ServiceHealthConfig{Weights: "high=150"}, zap.NewNop())
+	health, err := svc.Health(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentPartialOutage, health.State)
+}
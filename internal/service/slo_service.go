@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/prometheus"
+	"pulse/internal/repository"
+)
+
+const (
+	// sloBudgetWarningRemaining 剩余错误预算低于该百分比时状态为 warning
+	sloBudgetWarningRemaining = 25
+	// sloBurnRateWindow 计算当前消耗速率的时间窗口
+	sloBurnRateWindow = time.Hour
+)
+
+// SLOQuerier 执行 SLI 即时查询
+type SLOQuerier interface {
+	Query(ctx context.Context, expr string, at time.Time) (float64, error)
+}
+
+// SLOQuerierFactory 根据 Prometheus 数据源创建查询客户端
+type SLOQuerierFactory func(dataSource *models.DataSource) (SLOQuerier, error)
+
+// breakerSLOQuerier 在数据源熔断器保护下执行查询
+type breakerSLOQuerier struct {
+	querier SLOQuerier
+	breaker *breaker.Breaker
+}
+
+// Query 执行即时查询，数据源熔断时直接返回 breaker.ErrOpen
+func (q *breakerSLOQuerier) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	var value float64
+	err := q.breaker.Do(ctx, func(ctx context.Context) error {
+		v, err := q.querier.Query(ctx, expr, at)
+		value = v
+		return err
+	})
+	// 超时返回时查询可能仍在执行，只有成功时才读取结果
+	if err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+// sloBurnWindow 多窗口消耗速率告警的一组窗口，长窗口内消耗了 budget 比例的错误预算且短窗口内仍在消耗时触发
+type sloBurnWindow struct {
+	long   time.Duration
+	short  time.Duration
+	budget float64
+}
+
+var (
+	// sloPageWindows 快速消耗：1 小时消耗 2% 或 6 小时消耗 5% 的错误预算
+	sloPageWindows = []sloBurnWindow{
+		{long: time.Hour, short: 5 * time.Minute, budget: 0.02},
+		{long: 6 * time.Hour, short: 30 * time.Minute, budget: 0.05},
+	}
+	// sloTicketWindows 缓慢消耗：1 天或 3 天消耗 10% 的错误预算
+	sloTicketWindows = []sloBurnWindow{
+		{long: 24 * time.Hour, short: 2 * time.Hour, budget: 0.1},
+		{long: 72 * time.Hour, short: 6 * time.Hour, budget: 0.1},
+	}
+
+	// sloStatusRank 错误预算状态的严重程度，用于计算服务的整体状态
+	sloStatusRank = map[models.SLOBudgetStatus]int{
+		models.SLOBudgetStatusHealthy:   0,
+		models.SLOBudgetStatusUnknown:   1,
+		models.SLOBudgetStatusWarning:   2,
+		models.SLOBudgetStatusExhausted: 3,
+	}
+)
+
+// sloService SLO 服务实现
+type sloService struct {
+	repoManager repository.RepositoryManager
+	newQuerier  SLOQuerierFactory
+	cfg         config.SLOConfig
+	logger      *zap.Logger
+}
+
+// NewSLOService 创建 SLO 服务实例
+func NewSLOService(repoManager repository.RepositoryManager, newQuerier SLOQuerierFactory, cfg config.SLOConfig, logger *zap.Logger) SLOService {
+	return &sloService{
+		repoManager: repoManager,
+		newQuerier:  newQuerier,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Interval 错误预算计算周期
+func (s *sloService) Interval() time.Duration {
+	return s.cfg.EvaluationInterval
+}
+
+// List 获取 SLO 列表，serviceID 不为空时只返回该服务的 SLO
+func (s *sloService) List(ctx context.Context, serviceID string) ([]*models.SLO, error) {
+	return s.repoManager.SLO().List(ctx, serviceID)
+}
+
+// Get 获取 SLO
+func (s *sloService) Get(ctx context.Context, id string) (*models.SLO, error) {
+	return s.repoManager.SLO().GetByID(ctx, id)
+}
+
+// Create 创建 SLO 并生成消耗速率告警规则
+func (s *sloService) Create(ctx context.Context, req *models.SLORequest, createdBy string) (*models.SLO, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	slo := &models.SLO{CreatedBy: createdBy}
+	req.ApplyTo(slo)
+
+	service, err := s.checkReferences(ctx, slo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		if err := repo.SLO().Create(ctx, slo); err != nil {
+			return err
+		}
+		if err := s.syncRules(ctx, repo, slo, service); err != nil {
+			return err
+		}
+		return repo.SLO().Update(ctx, slo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建SLO", zap.String("slo_id", slo.ID), zap.String("name", slo.Name),
+		zap.String("service", service.Name), zap.Float64("target", slo.Target), zap.Int("window_days", slo.WindowDays))
+	return slo, nil
+}
+
+// Update 更新 SLO 并同步消耗速率告警规则，规则被删除时重新生成
+func (s *sloService) Update(ctx context.Context, id string, req *models.SLORequest) (*models.SLO, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	slo, err := s.repoManager.SLO().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(slo)
+
+	service, err := s.checkReferences(ctx, slo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		if err := s.syncRules(ctx, repo, slo, service); err != nil {
+			return err
+		}
+		return repo.SLO().Update(ctx, slo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slo, nil
+}
+
+// Delete 删除 SLO 及其生成的告警规则
+func (s *sloService) Delete(ctx context.Context, id string) error {
+	slo, err := s.repoManager.SLO().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.withTx(ctx, func(repo repository.RepositoryManager) error {
+		for _, ruleID := range []*string{slo.PageRuleID, slo.TicketRuleID} {
+			if ruleID == nil {
+				continue
+			}
+			if err := repo.Rule().SoftDelete(ctx, *ruleID); err != nil {
+				return err
+			}
+		}
+		return repo.SLO().Delete(ctx, id)
+	})
+}
+
+// ServiceBudgets 获取服务下全部 SLO 的错误预算，服务状态取最差的 SLO 状态，没有 SLO 时为 unknown
+func (s *sloService) ServiceBudgets(ctx context.Context, serviceID string) (*models.ServiceSLOBudgets, error) {
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	slos, err := s.repoManager.SLO().List(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := &models.ServiceSLOBudgets{
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		OwnerTeam:   service.OwnerTeam,
+		SLOs:        slos,
+		Status:      models.SLOBudgetStatusUnknown,
+	}
+	for i, slo := range slos {
+		if i == 0 || sloStatusRank[slo.Budget.Status] > sloStatusRank[budgets.Status] {
+			budgets.Status = slo.Budget.Status
+		}
+	}
+	return budgets, nil
+}
+
+// Evaluate 计算所有启用的 SLO 的错误预算，单个 SLO 查询失败时记录错误并将状态置为 unknown，返回计算成功的数量
+func (s *sloService) Evaluate(ctx context.Context) (int, error) {
+	slos, err := s.repoManager.SLO().ListEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	queriers := make(map[string]SLOQuerier)
+	evaluated := 0
+	for _, slo := range slos {
+		querier, ok := queriers[slo.DataSourceID]
+		if !ok {
+			querier, err = s.querier(ctx, slo.DataSourceID)
+			if err != nil {
+				s.saveBudget(ctx, slo, failedBudget(err, now))
+				continue
+			}
+			queriers[slo.DataSourceID] = querier
+		}
+
+		budget := s.evaluate(ctx, querier, slo, now)
+		if budget.Status != models.SLOBudgetStatusUnknown {
+			evaluated++
+		}
+		s.saveBudget(ctx, slo, budget)
+	}
+	return evaluated, nil
+}
+
+// evaluate 计算单个 SLO 在统计窗口内的错误比例、剩余错误预算和最近 1 小时的消耗速率
+func (s *sloService) evaluate(ctx context.Context, querier SLOQuerier, slo *models.SLO, now time.Time) *models.SLOBudget {
+	ratio, err := s.errorRatio(ctx, querier, slo, slo.Window(), now)
+	if err != nil {
+		return failedBudget(err, now)
+	}
+
+	errorBudget := slo.ErrorBudget()
+	remaining := 100 * (1 - ratio/errorBudget)
+	budget := &models.SLOBudget{
+		Status:      sloBudgetStatus(remaining),
+		ErrorRatio:  &ratio,
+		Remaining:   &remaining,
+		EvaluatedAt: &now,
+	}
+
+	// 消耗速率只用于展示，查询失败不影响错误预算
+	recent, err := s.errorRatio(ctx, querier, slo, sloBurnRateWindow, now)
+	if err != nil {
+		s.logger.Warn("计算SLO消耗速率失败", zap.Error(err), zap.String("slo_id", slo.ID))
+	} else {
+		burnRate := recent / errorBudget
+		budget.BurnRate = &burnRate
+	}
+	return budget
+}
+
+// errorRatio 查询 window 内的错误比例。错误请求查询没有数据时视为没有错误，总请求数为 0 时视为没有流量，错误比例为 0
+func (s *sloService) errorRatio(ctx context.Context, querier SLOQuerier, slo *models.SLO, window time.Duration, now time.Time) (float64, error) {
+	total, err := querier.Query(ctx, sloQuery(slo.TotalQuery, window), now)
+	if err != nil {
+		if errors.Is(err, prometheus.ErrNoData) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("查询总请求数失败: %w", err)
+	}
+	if total <= 0 || math.IsNaN(total) {
+		return 0, nil
+	}
+
+	errorCount, err := querier.Query(ctx, sloQuery(slo.ErrorQuery, window), now)
+	if err != nil {
+		if errors.Is(err, prometheus.ErrNoData) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("查询错误请求数失败: %w", err)
+	}
+	if math.IsNaN(errorCount) || errorCount < 0 {
+		return 0, nil
+	}
+	return errorCount / total, nil
+}
+
+// querier 创建数据源的查询客户端
+func (s *sloService) querier(ctx context.Context, dataSourceID string) (SLOQuerier, error) {
+	dataSource, err := s.repoManager.DataSource().GetByID(ctx, dataSourceID)
+	if err != nil {
+		return nil, err
+	}
+	if dataSource == nil {
+		return nil, models.ErrDataSourceNotFound
+	}
+	return s.newQuerier(dataSource)
+}
+
+// saveBudget 保存错误预算计算结果
+func (s *sloService) saveBudget(ctx context.Context, slo *models.SLO, budget *models.SLOBudget) {
+	if budget.LastError != nil {
+		s.logger.Warn("计算SLO错误预算失败", zap.String("slo_id", slo.ID), zap.String("name", slo.Name),
+			zap.String("error", *budget.LastError))
+	}
+	if err := s.repoManager.SLO().UpdateBudget(ctx, slo.ID, budget); err != nil {
+		s.logger.Error("保存SLO错误预算失败", zap.Error(err), zap.String("slo_id", slo.ID))
+	}
+}
+
+// checkReferences 检查 SLO 所属的服务存在，数据源是 Prometheus 类型
+func (s *sloService) checkReferences(ctx context.Context, slo *models.SLO) (*models.CatalogService, error) {
+	service, err := s.repoManager.ServiceCatalog().GetByID(ctx, slo.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataSource, err := s.repoManager.DataSource().GetByID(ctx, slo.DataSourceID)
+	if err != nil {
+		return nil, err
+	}
+	if dataSource == nil {
+		return nil, models.ErrDataSourceNotFound
+	}
+	if dataSource.Type != models.DataSourceTypePrometheus {
+		return nil, fmt.Errorf("%w: 数据源 %s 不是Prometheus类型", models.ErrInvalidInput, dataSource.Name)
+	}
+	return service, nil
+}
+
+// syncRules 创建或更新 SLO 的快速消耗和缓慢消耗告警规则，并记录规则ID
+func (s *sloService) syncRules(ctx context.Context, repo repository.RepositoryManager, slo *models.SLO, service *models.CatalogService) error {
+	pageRule, ticketRule := sloBurnRateRules(slo, service, s.cfg.RuleEvaluationInterval)
+
+	var err error
+	if slo.PageRuleID, err = s.syncRule(ctx, repo, slo.PageRuleID, pageRule); err != nil {
+		return err
+	}
+	slo.TicketRuleID, err = s.syncRule(ctx, repo, slo.TicketRuleID, ticketRule)
+	return err
+}
+
+// syncRule 规则已存在时更新定义，不存在或已被删除时重新创建，返回规则ID
+func (s *sloService) syncRule(ctx context.Context, repo repository.RepositoryManager, ruleID *string, rule *models.Rule) (*string, error) {
+	if ruleID != nil {
+		existing, err := repo.Rule().GetByID(ctx, *ruleID)
+		switch {
+		case err == nil:
+			existing.DataSourceID = rule.DataSourceID
+			existing.Name = rule.Name
+			existing.Description = rule.Description
+			existing.Enabled = rule.Enabled
+			existing.Severity = rule.Severity
+			existing.Expression = rule.Expression
+			existing.Labels = rule.Labels
+			existing.Annotations = rule.Annotations
+			if err := repo.Rule().Update(ctx, existing); err != nil {
+				return nil, fmt.Errorf("更新SLO告警规则失败: %w", err)
+			}
+			return ruleID, nil
+		case !errors.Is(err, models.ErrRuleNotFound):
+			return nil, err
+		}
+	}
+
+	if err := repo.Rule().Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("创建SLO告警规则失败: %w", err)
+	}
+	id := rule.ID
+	return &id, nil
+}
+
+// withTx 在事务中执行
+func (s *sloService) withTx(ctx context.Context, fn func(repo repository.RepositoryManager) error) error {
+	tx, err := s.repoManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// sloBurnRateRules 生成多窗口消耗速率告警规则：快速消耗为 critical，缓慢消耗为 high。
+// 消耗速率阈值按统计窗口换算，30 天窗口下分别为 14.4、6、3、1；超过统计窗口的告警窗口不使用
+func sloBurnRateRules(slo *models.SLO, service *models.CatalogService, interval time.Duration) (page, ticket *models.Rule) {
+	newRule := func(kind, summary string, severity models.AlertSeverity, windows []sloBurnWindow) *models.Rule {
+		return &models.Rule{
+			DataSourceID: slo.DataSourceID,
+			Name:         fmt.Sprintf("SLO %s 错误预算%s", slo.Name, kind),
+			Description:  fmt.Sprintf("服务 %s 的 SLO %s（目标 %g%%，%d 天）错误预算%s，由 SLO 自动生成，请勿手动修改", service.Name, slo.Name, slo.Target, slo.WindowDays, kind),
+			Type:         models.RuleTypeMetric,
+			Status:       models.RuleStatusActive,
+			Enabled:      slo.Enabled,
+			Severity:     severity,
+			Expression:   sloBurnRateExpression(slo, windows),
+			Labels: map[string]string{
+				"slo":               slo.Name,
+				"slo_id":            slo.ID,
+				models.ServiceLabel: service.Name,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("SLO %s 的错误预算%s", slo.Name, summary),
+			},
+			EvaluationInterval: interval,
+			CreatedBy:          slo.CreatedBy,
+		}
+	}
+
+	page = newRule("快速消耗", "正在快速消耗，按当前速率将在数天内耗尽", models.AlertSeverityCritical, sloPageWindows)
+	ticket = newRule("缓慢消耗", "持续消耗，按当前速率将在统计窗口结束前耗尽", models.AlertSeverityHigh, sloTicketWindows)
+	return page, ticket
+}
+
+// sloBurnRateExpression 生成多窗口消耗速率表达式，任一组窗口的长窗口和短窗口同时超过阈值时触发
+func sloBurnRateExpression(slo *models.SLO, windows []sloBurnWindow) string {
+	errorBudget := slo.ErrorBudget()
+	conditions := make([]string, 0, len(windows))
+	for _, window := range windows {
+		if window.long > slo.Window() {
+			continue
+		}
+		threshold := fmt.Sprintf("%.6g", window.budget*float64(slo.Window())/float64(window.long)*errorBudget)
+		conditions = append(conditions, fmt.Sprintf("(%s > %s and %s > %s)",
+			sloRatioQuery(slo, window.long), threshold, sloRatioQuery(slo, window.short), threshold))
+	}
+	return strings.Join(conditions, " or ")
+}
+
+// sloRatioQuery 窗口内错误比例的查询表达式
+func sloRatioQuery(slo *models.SLO, window time.Duration) string {
+	return fmt.Sprintf("(%s) / (%s)", sloQuery(slo.ErrorQuery, window), sloQuery(slo.TotalQuery, window))
+}
+
+// sloQuery 将查询中的时间窗口占位符替换为 PromQL 时长
+func sloQuery(query string, window time.Duration) string {
+	return strings.ReplaceAll(query, models.SLOWindowPlaceholder, promDuration(window))
+}
+
+// promDuration 将时长格式化为 PromQL 时长，如 30d、6h、5m
+func promDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+// sloBudgetStatus 根据剩余错误预算百分比确定状态
+func sloBudgetStatus(remaining float64) models.SLOBudgetStatus {
+	switch {
+	case remaining <= 0:
+		return models.SLOBudgetStatusExhausted
+	case remaining < sloBudgetWarningRemaining:
+		return models.SLOBudgetStatusWarning
+	default:
+		return models.SLOBudgetStatusHealthy
+	}
+}
+
+// failedBudget 查询失败时的错误预算，保留错误信息便于排查
+func failedBudget(err error, now time.Time) *models.SLOBudget {
+	message := err.Error()
+	return &models.SLOBudget{
+		Status:      models.SLOBudgetStatusUnknown,
+		EvaluatedAt: &now,
+		LastError:   &message,
+	}
+}
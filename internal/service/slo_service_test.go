@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/prometheus"
+	"pulse/internal/repository"
+)
+
+// fakeSLORepository 内存中的 SLO
+type fakeSLORepository struct {
+	repository.SLORepository
+	slos map[string]*models.SLO
+}
+
+func (r *fakeSLORepository) Create(ctx context.Context, slo *models.SLO) error {
+	slo.ID = slo.Name
+	slo.Budget = models.SLOBudget{Status: models.SLOBudgetStatusUnknown}
+	r.slos[slo.ID] = slo
+	return nil
+}
+
+func (r *fakeSLORepository) GetByID(ctx context.Context, id string) (*models.SLO, error) {
+	slo, ok := r.slos[id]
+	if !ok {
+		return nil, models.ErrSLONotFound
+	}
+	copied := *slo
+	return &copied, nil
+}
+
+func (r *fakeSLORepository) List(ctx context.Context, serviceID string) ([]*models.SLO, error) {
+	var slos []*models.SLO
+	for _, slo := range r.slos {
+		if serviceID == "" || slo.ServiceID == serviceID {
+			slos = append(slos, slo)
+		}
+	}
+	return slos, nil
+}
+
+func (r *fakeSLORepository) ListEnabled(ctx context.Context) ([]*models.SLO, error) {
+	return r.List(ctx, "")
+}
+
+func (r *fakeSLORepository) Update(ctx context.Context, slo *models.SLO) error {
+	r.slos[slo.ID] = slo
+	return nil
+}
+
+func (r *fakeSLORepository) UpdateBudget(ctx context.Context, id string, budget *models.SLOBudget) error {
+	r.slos[id].Budget = *budget
+	return nil
+}
+
+func (r *fakeSLORepository) Delete(ctx context.Context, id string) error {
+	delete(r.slos, id)
+	return nil
+}
+
+// fakeSLORuleRepository 保存生成的告警规则
+type fakeSLORuleRepository struct {
+	repository.RuleRepository
+	rules   map[string]*models.Rule
+	created int
+}
+
+func (r *fakeSLORuleRepository) Create(ctx context.Context, rule *models.Rule) error {
+	r.created++
+	rule.ID = rule.Name
+	r.rules[rule.ID] = rule
+	return nil
+}
+
+func (r *fakeSLORuleRepository) GetByID(ctx context.Context, id string) (*models.Rule, error) {
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, models.ErrRuleNotFound
+	}
+	return rule, nil
+}
+
+func (r *fakeSLORuleRepository) Update(ctx context.Context, rule *models.Rule) error {
+	r.rules[rule.ID] = rule
+	return nil
+}
+
+func (r *fakeSLORuleRepository) SoftDelete(ctx context.Context, id string) error {
+	delete(r.rules, id)
+	return nil
+}
+
+type sloRepoManager struct {
+	*MockRepositoryManager
+	slos        *fakeSLORepository
+	rules       *fakeSLORuleRepository
+	catalog     *fakeServiceCatalogRepository
+	dataSources *fakeKubernetesDataSourceRepository
+}
+
+func (m *sloRepoManager) SLO() repository.SLORepository { return m.slos }
+
+func (m *sloRepoManager) Rule() repository.RuleRepository { return m.rules }
+
+func (m *sloRepoManager) ServiceCatalog() repository.ServiceCatalogRepository { return m.catalog }
+
+func (m *sloRepoManager) DataSource() repository.DataSourceRepository { return m.dataSources }
+
+func (m *sloRepoManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
+	return m, nil
+}
+
+// fakeSLOQuerier 按查询表达式返回结果，未配置的查询没有数据
+type fakeSLOQuerier map[string]float64
+
+func (q fakeSLOQuerier) Query(ctx context.Context, expr string, at time.Time) (float64, error) {
+	if expr == "broken" {
+		return 0, errors.New("parse error")
+	}
+	value, ok := q[expr]
+	if !ok {
+		return 0, prometheus.ErrNoData
+	}
+	return value, nil
+}
+
+func newSLOTestService(querier fakeSLOQuerier) (*sloRepoManager, SLOService) {
+	repoManager := &sloRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		slos:                  &fakeSLORepository{slos: map[string]*models.SLO{}},
+		rules:                 &fakeSLORuleRepository{rules: map[string]*models.Rule{}},
+		catalog: &fakeServiceCatalogRepository{services: []*models.CatalogService{
+			{ID: "checkout", Name: "checkout", OwnerTeam: "payments"},
+		}},
+		dataSources: &fakeKubernetesDataSourceRepository{dataSources: []*models.DataSource{
+			{ID: "prom", Name: "prometheus", Type: models.DataSourceTypePrometheus},
+			{ID: "k8s", Name: "cluster", Type: models.DataSourceTypeKubernetes},
+		}},
+	}
+	factory := func(dataSource *models.DataSource) (SLOQuerier, error) { return querier, nil }
+	cfg := config.SLOConfig{EvaluationInterval: 5 * time.Minute, RuleEvaluationInterval: time.Minute}
+	return repoManager, NewSLOService(repoManager, factory, cfg, zap.NewNop())
+}
+
+func sloTestRequest(name string) *models.SLORequest {
+	return &models.SLORequest{
+		Name:         name,
+		ServiceID:    "checkout",
+		DataSourceID: "prom",
+		ErrorQuery:   `sum(rate(http_requests_total{job="` + name + `",code=~"5.."}[{{.window}}]))`,
+		TotalQuery:   `sum(rate(http_requests_total{job="` + name + `"}[{{.window}}]))`,
+		Target:       99.9,
+	}
+}
+
+func TestSLOService_BurnRateRules(t *testing.T) {
+	repoManager, svc := newSLOTestService(nil)
+	ctx := context.Background()
+
+	req := sloTestRequest("api")
+	req.DataSourceID = "k8s"
+	_, err := svc.Create(ctx, req, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	slo, err := svc.Create(ctx, sloTestRequest("api"), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.SLODefaultWindowDays, slo.WindowDays)
+	require.NotNil(t, slo.PageRuleID)
+	require.NotNil(t, slo.TicketRuleID)
+
+	// 30 天窗口、99.9% 目标下快速消耗阈值为 14.4 倍和 6 倍，缓慢消耗为 3 倍和 1 倍
+	page := repoManager.rules.rules[*slo.PageRuleID]
+	assert.Equal(t, models.AlertSeverityCritical, page.Severity)
+	assert.Equal(t, "checkout", page.Labels["service"])
+	assert.Contains(t, page.Expression, `(sum(rate(http_requests_total{job="api",code=~"5.."}[1h]))) / (sum(rate(http_requests_total{job="api"}[1h]))) > 0.0144 and`)
+	assert.Contains(t, page.Expression, `[30m]))) > 0.006)`)
+	ticket := repoManager.rules.rules[*slo.TicketRuleID]
+	assert.Equal(t, models.AlertSeverityHigh, ticket.Severity)
+	assert.Contains(t, ticket.Expression, `[1d]))) > 0.003 and`)
+	assert.Contains(t, ticket.Expression, `[3d]))) > 0.001 and`)
+
+	// 更新时同步已有规则，被删除的规则重新生成
+	delete(repoManager.rules.rules, *slo.TicketRuleID)
+	req = sloTestRequest("api")
+	req.WindowDays = 1
+	req.Disabled = true
+	slo, err = svc.Update(ctx, slo.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, repoManager.rules.created)
+	assert.False(t, repoManager.rules.rules[*slo.PageRuleID].Enabled)
+	assert.NotContains(t, repoManager.rules.rules[*slo.TicketRuleID].Expression, "[3d]", "超过统计窗口的告警窗口不使用")
+
+	// 定义了 SLO 的服务不能删除
+	catalog := NewServiceCatalogService(repoManager, zap.NewNop())
+	assert.ErrorIs(t, catalog.Delete(ctx, "checkout"), models.ErrCatalogServiceInUse)
+
+	require.NoError(t, svc.Delete(ctx, slo.ID))
+	assert.Empty(t, repoManager.rules.rules)
+	assert.Empty(t, repoManager.slos.slos)
+}
+
+func TestSLOService_Evaluate(t *testing.T) {
+	querier := fakeSLOQuerier{
+		// api: 30 天错误比例 0.05%，剩余一半预算；最近 1 小时错误比例 1%，消耗速率 10
+		`sum(rate(http_requests_total{job="api"}[30d]))`:             1000000,
+		`sum(rate(http_requests_total{job="api",code=~"5.."}[30d]))`: 500,
+		`sum(rate(http_requests_total{job="api"}[1h]))`:              1000,
+		`sum(rate(http_requests_total{job="api",code=~"5.."}[1h]))`:  10,
+		// web: 剩余 10% 预算
+		`sum(rate(http_requests_total{job="web"}[30d]))`:             1000000,
+		`sum(rate(http_requests_total{job="web",code=~"5.."}[30d]))`: 900,
+		// idle: 没有错误指标视为没有错误
+		`sum(rate(http_requests_total{job="idle"}[30d]))`: 1000,
+	}
+	repoManager, svc := newSLOTestService(querier)
+	ctx := context.Background()
+
+	for _, name := range []string{"api", "web", "idle", "broken"} {
+		_, err := svc.Create(ctx, sloTestRequest(name), "admin")
+		require.NoError(t, err)
+	}
+	repoManager.slos.slos["broken"].TotalQuery = "broken"
+
+	evaluated, err := svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, evaluated)
+
+	api := repoManager.slos.slos["api"].Budget
+	assert.Equal(t, models.SLOBudgetStatusHealthy, api.Status)
+	assert.InDelta(t, 50, *api.Remaining, 1e-9)
+	assert.InDelta(t, 10, *api.BurnRate, 1e-9)
+
+	web := repoManager.slos.slos["web"].Budget
+	assert.Equal(t, models.SLOBudgetStatusWarning, web.Status)
+	assert.InDelta(t, 10, *web.Remaining, 1e-9)
+
+	idle := repoManager.slos.slos["idle"].Budget
+	assert.Equal(t, models.SLOBudgetStatusHealthy, idle.Status)
+	assert.InDelta(t, 100, *idle.Remaining, 1e-9)
+
+	broken := repoManager.slos.slos["broken"].Budget
+	assert.Equal(t, models.SLOBudgetStatusUnknown, broken.Status)
+	require.NotNil(t, broken.LastError)
+	assert.Contains(t, *broken.LastError, "parse error")
+
+	budgets, err := svc.ServiceBudgets(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Len(t, budgets.SLOs, 4)
+	assert.Equal(t, models.SLOBudgetStatusWarning, budgets.Status)
+}
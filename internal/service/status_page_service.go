@@ -0,0 +1,528 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// statusPagePath 状态页在平台外部访问地址下的路径
+	statusPagePath = "/status-page"
+	// statusSubscriptionPath 确认和退订链接在平台外部访问地址下的路径
+	statusSubscriptionPath = "/status-page/subscriptions/"
+	// statusNotifyBatchSize 每次检查最多通知的进展说明数
+	statusNotifyBatchSize = 20
+)
+
+// statusPageService 公开状态页服务实现
+type statusPageService struct {
+	repoManager   repository.RepositoryManager
+	catalog       ServiceCatalogService
+	health        ServiceHealthService
+	notifications NotificationService
+	cfg           config.StatusPageConfig
+	baseURL       string
+	secret        []byte
+	logger        *zap.Logger
+	now           func() time.Time
+
+	mu       sync.Mutex
+	snapshot *models.StatusPage
+	loadedAt time.Time
+}
+
+// NewStatusPageService 创建公开状态页服务实例。baseURL 为平台外部访问地址，为空时不能订阅；
+// secret 用于签名确认和退订链接中的订阅者 ID；开启 AlertHealth 时组件状态同时参考 health 计算的告警健康度
+func NewStatusPageService(repoManager repository.RepositoryManager, catalog ServiceCatalogService, health ServiceHealthService, notifications NotificationService, cfg config.StatusPageConfig, baseURL, secret string, logger *zap.Logger) StatusPageService {
+	return &statusPageService{
+		repoManager:   repoManager,
+		catalog:       catalog,
+		health:        health,
+		notifications: notifications,
+		cfg:           cfg,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		secret:        []byte(secret),
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Interval 检查待通知进展说明的周期
+func (s *statusPageService) Interval() time.Duration {
+	return s.cfg.NotifyInterval
+}
+
+// CacheTTL 状态页内容的缓存时长
+func (s *statusPageService) CacheTTL() time.Duration {
+	return s.cfg.CacheTTL
+}
+
+// Page 获取公开状态页内容。状态页不需要登录且可能被大量访问，内容在缓存时长内复用，本实例修改事件时立即失效
+func (s *statusPageService) Page(ctx context.Context) (*models.StatusPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.snapshot != nil && now.Sub(s.loadedAt) < s.cfg.CacheTTL {
+		return s.snapshot, nil
+	}
+
+	page, err := s.build(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshot = page
+	s.loadedAt = now
+	return page, nil
+}
+
+// invalidate 使状态页快照失效
+func (s *statusPageService) invalidate() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+}
+
+// build 根据服务目录和未结束及最近结束的事件生成状态页，只展示配置的组件，
+// 只影响未展示组件的事件不出现在状态页上
+func (s *statusPageService) build(ctx context.Context, now time.Time) (*models.StatusPage, error) {
+	components, err := s.components(ctx)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]*models.StatusComponent, len(components))
+	for _, component := range components {
+		visible[component.Name] = component
+	}
+
+	since := now.Add(-s.cfg.HistoryWindow)
+	incidents, err := s.repoManager.StatusPage().ListIncidents(ctx, &models.StatusIncidentFilter{Open: true, ClosedSince: &since})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachUpdates(ctx, incidents); err != nil {
+		return nil, err
+	}
+	if s.cfg.AlertHealth {
+		rollup, err := s.health.Rollup(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, health := range rollup.Services {
+			if component, ok := visible[health.Name]; ok {
+				component.State = component.State.Worse(health.State)
+			}
+		}
+	}
+
+	page := &models.StatusPage{
+		Title:        s.cfg.Title,
+		State:        models.StatusComponentOperational,
+		Components:   components,
+		Incidents:    []*models.StatusIncident{},
+		Maintenances: []*models.StatusIncident{},
+		History:      []*models.StatusIncident{},
+		GeneratedAt:  now,
+	}
+	for _, incident := range incidents {
+		public := incident.Public()
+		public.Components = make([]string, 0, len(incident.Components))
+		for _, name := range incident.Components {
+			if component, ok := visible[name]; ok {
+				component.State = component.State.Worse(incident.ComponentState())
+				public.Components = append(public.Components, name)
+			}
+		}
+		if len(public.Components) == 0 {
+			continue
+		}
+
+		switch {
+		case incident.Status.IsClosed():
+			page.History = append(page.History, public)
+		case incident.Kind == models.StatusIncidentKindMaintenance:
+			page.Maintenances = append(page.Maintenances, public)
+		default:
+			page.Incidents = append(page.Incidents, public)
+		}
+	}
+	for _, component := range components {
+		page.State = page.State.Worse(component.State)
+	}
+	return page, nil
+}
+
+// components 状态页展示的组件，未配置时展示服务目录中的全部服务，按名称排序
+func (s *statusPageService) components(ctx context.Context) ([]*models.StatusComponent, error) {
+	services, err := s.catalog.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(s.cfg.Components))
+	for _, name := range s.cfg.Components {
+		allowed[strings.TrimSpace(name)] = true
+	}
+	components := make([]*models.StatusComponent, 0, len(services))
+	for _, service := range services {
+		if len(allowed) > 0 && !allowed[service.Name] {
+			continue
+		}
+		components = append(components, &models.StatusComponent{
+			Name:        service.Name,
+			Description: service.Description,
+			State:       models.StatusComponentOperational,
+		})
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+	return components, nil
+}
+
+// attachUpdates 为事件补充进展说明
+func (s *statusPageService) attachUpdates(ctx context.Context, incidents []*models.StatusIncident) error {
+	ids := make([]string, 0, len(incidents))
+	byID := make(map[string]*models.StatusIncident, len(incidents))
+	for _, incident := range incidents {
+		ids = append(ids, incident.ID)
+		byID[incident.ID] = incident
+	}
+	updates, err := s.repoManager.StatusPage().ListUpdates(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		if incident, ok := byID[update.IncidentID]; ok {
+			incident.Updates = append(incident.Updates, update)
+		}
+	}
+	return nil
+}
+
+// ListIncidents 获取事件，不包含进展说明
+func (s *statusPageService) ListIncidents(ctx context.Context, filter *models.StatusIncidentFilter) ([]*models.StatusIncident, error) {
+	return s.repoManager.StatusPage().ListIncidents(ctx, filter)
+}
+
+// GetIncident 获取事件及其进展说明
+func (s *statusPageService) GetIncident(ctx context.Context, id string) (*models.StatusIncident, error) {
+	incident, err := s.repoManager.StatusPage().GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachUpdates(ctx, []*models.StatusIncident{incident}); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// CreateIncident 创建事件，请求中的说明作为第一条进展说明
+func (s *statusPageService) CreateIncident(ctx context.Context, req *models.StatusIncidentRequest, createdBy string) (*models.StatusIncident, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Message == "" {
+		return nil, fmt.Errorf("%w: 进展说明不能为空", models.ErrInvalidInput)
+	}
+	if err := s.validateComponents(ctx, req.Components); err != nil {
+		return nil, err
+	}
+
+	incident := &models.StatusIncident{
+		Kind:      req.Kind,
+		Status:    req.Status,
+		CreatedBy: createdBy,
+	}
+	req.ApplyTo(incident)
+	if incident.Status.IsClosed() {
+		now := s.now()
+		incident.ResolvedAt = &now
+	}
+	update := &models.StatusIncidentUpdate{
+		Status:    req.Status,
+		Message:   req.Message,
+		Notify:    req.Notify == nil || *req.Notify,
+		CreatedBy: createdBy,
+	}
+
+	if err := s.repoManager.StatusPage().CreateIncident(ctx, incident, update); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	incident.Updates = []*models.StatusIncidentUpdate{update}
+
+	s.logger.Info("发布状态页事件", zap.String("incident_id", incident.ID), zap.String("kind", string(incident.Kind)),
+		zap.Strings("components", incident.Components), zap.String("created_by", createdBy))
+	return incident, nil
+}
+
+// UpdateIncident 修改事件的标题、影响程度、组件和计划时间，不通知订阅者；进展通过 PostUpdate 发布
+func (s *statusPageService) UpdateIncident(ctx context.Context, id string, req *models.StatusIncidentRequest) (*models.StatusIncident, error) {
+	incident, err := s.repoManager.StatusPage().GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.Kind = incident.Kind
+	req.Status = incident.Status
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.validateComponents(ctx, req.Components); err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(incident)
+	if err := s.repoManager.StatusPage().UpdateIncident(ctx, incident); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return s.GetIncident(ctx, id)
+}
+
+// PostUpdate 发布事件进展，进展为 resolved 或 completed 时结束事件，重新打开时清除结束时间
+func (s *statusPageService) PostUpdate(ctx context.Context, id string, req *models.StatusIncidentUpdateRequest, createdBy string) (*models.StatusIncident, error) {
+	incident, err := s.repoManager.StatusPage().GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(incident.Kind); err != nil {
+		return nil, err
+	}
+
+	incident.Status = req.Status
+	if req.Impact != nil {
+		incident.Impact = *req.Impact
+	}
+	switch {
+	case !req.Status.IsClosed():
+		incident.ResolvedAt = nil
+	case incident.ResolvedAt == nil:
+		now := s.now()
+		incident.ResolvedAt = &now
+	}
+	update := &models.StatusIncidentUpdate{
+		Status:    req.Status,
+		Message:   req.Message,
+		Notify:    req.Notify == nil || *req.Notify,
+		CreatedBy: createdBy,
+	}
+
+	if err := s.repoManager.StatusPage().AddUpdate(ctx, incident, update); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("发布状态页事件进展", zap.String("incident_id", id), zap.String("status", string(req.Status)),
+		zap.String("created_by", createdBy))
+	return s.GetIncident(ctx, id)
+}
+
+// DeleteIncident 删除误发布的事件
+func (s *statusPageService) DeleteIncident(ctx context.Context, id string) error {
+	if err := s.repoManager.StatusPage().DeleteIncident(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// validateComponents 检查受影响的组件都在服务目录中
+func (s *statusPageService) validateComponents(ctx context.Context, components []string) error {
+	services, err := s.catalog.List(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(services))
+	for _, service := range services {
+		known[service.Name] = true
+	}
+	for _, component := range components {
+		if !known[component] {
+			return fmt.Errorf("%w: 服务目录中不存在服务 %s", models.ErrInvalidInput, component)
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅邮件通知并发送确认邮件。邮箱已确认订阅时不修改订阅也不发送邮件，
+// 返回结果与新订阅相同，避免通过订阅接口探测邮箱是否已订阅
+func (s *statusPageService) Subscribe(ctx context.Context, req *models.StatusSubscribeRequest) error {
+	if s.baseURL == "" || s.notifications == nil {
+		return models.ErrStatusSubscribeDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	components, err := s.components(ctx)
+	if err != nil {
+		return err
+	}
+	visible := make(map[string]bool, len(components))
+	for _, component := range components {
+		visible[component.Name] = true
+	}
+	subscribed := make([]string, 0, len(req.Components))
+	for _, name := range req.Components {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !visible[name] {
+			return fmt.Errorf("%w: 状态页中不存在组件 %s", models.ErrInvalidInput, name)
+		}
+		subscribed = append(subscribed, name)
+	}
+
+	subscriber := &models.StatusSubscriber{Email: req.Email, Components: subscribed}
+	created, err := s.repoManager.StatusPage().UpsertSubscriber(ctx, subscriber)
+	if err != nil || !created {
+		return err
+	}
+
+	return s.notifications.Send(ctx, &models.Notification{
+		Type:       models.NotificationTypeEmail,
+		Recipient:  subscriber.Email,
+		MessageKey: "notification.status_subscribe",
+		MessageData: map[string]interface{}{
+			"Page": s.cfg.Title,
+			"Link": s.baseURL + statusSubscriptionPath + s.subscriptionToken(subscriber.ID),
+		},
+	})
+}
+
+// Subscription 获取确认和退订链接对应的订阅
+func (s *statusPageService) Subscription(ctx context.Context, token string) (*models.StatusSubscriptionView, error) {
+	subscriber, err := s.subscriber(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return statusSubscriptionView(subscriber), nil
+}
+
+// Confirm 确认订阅，重复确认不报错
+func (s *statusPageService) Confirm(ctx context.Context, token string) (*models.StatusSubscriptionView, error) {
+	subscriber, err := s.subscriber(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if subscriber.ConfirmedAt == nil {
+		now := s.now()
+		if err := s.repoManager.StatusPage().ConfirmSubscriber(ctx, subscriber.ID, now); err != nil {
+			return nil, err
+		}
+		subscriber.ConfirmedAt = &now
+	}
+	return statusSubscriptionView(subscriber), nil
+}
+
+// Unsubscribe 退订
+func (s *statusPageService) Unsubscribe(ctx context.Context, token string) error {
+	subscriber, err := s.subscriber(ctx, token)
+	if err != nil {
+		return err
+	}
+	return s.repoManager.StatusPage().DeleteSubscriber(ctx, subscriber.ID)
+}
+
+// subscriber 校验链接中的令牌并获取订阅者
+func (s *statusPageService) subscriber(ctx context.Context, token string) (*models.StatusSubscriber, error) {
+	// 未配置签名密钥时不签发链接，也不接受任何令牌
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || len(s.secret) == 0 || !hmac.Equal([]byte(sig), []byte(s.sign(id))) {
+		return nil, models.ErrInvalidToken
+	}
+	return s.repoManager.StatusPage().GetSubscriber(ctx, id)
+}
+
+// subscriptionToken 确认和退订链接中的令牌，为订阅者 ID 及其签名，同一订阅者的令牌不变
+func (s *statusPageService) subscriptionToken(id string) string {
+	return id + "." + s.sign(id)
+}
+
+func (s *statusPageService) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("status-subscriber:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func statusSubscriptionView(subscriber *models.StatusSubscriber) *models.StatusSubscriptionView {
+	return &models.StatusSubscriptionView{
+		Email:      subscriber.Email,
+		Components: subscriber.Components,
+		Confirmed:  subscriber.ConfirmedAt != nil,
+	}
+}
+
+// NotifyPending 邮件通知订阅者待通知的进展说明，返回发送的邮件数。
+// 发送前先标记进展已通知，多实例时只有一个实例发送；单个订阅者发送失败不重试
+func (s *statusPageService) NotifyPending(ctx context.Context) (int, error) {
+	if !s.cfg.Enabled || s.baseURL == "" || s.notifications == nil {
+		return 0, nil
+	}
+
+	updates, err := s.repoManager.StatusPage().ListPendingUpdates(ctx, statusNotifyBatchSize)
+	if err != nil || len(updates) == 0 {
+		return 0, err
+	}
+	subscribers, err := s.repoManager.StatusPage().ListConfirmedSubscribers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, update := range updates {
+		claimed, err := s.repoManager.StatusPage().MarkUpdateNotified(ctx, update.ID, s.now())
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		incident, err := s.repoManager.StatusPage().GetIncident(ctx, update.IncidentID)
+		if err != nil {
+			s.logger.Error("获取状态页事件失败", zap.Error(err), zap.String("incident_id", update.IncidentID))
+			continue
+		}
+
+		for _, subscriber := range subscribers {
+			if !subscriber.Subscribed(incident.Components) {
+				continue
+			}
+			notification := &models.Notification{
+				Type:       models.NotificationTypeEmail,
+				Recipient:  subscriber.Email,
+				MessageKey: "notification.status_update",
+				MessageData: map[string]interface{}{
+					"Page":        s.cfg.Title,
+					"Title":       incident.Title,
+					"Components":  strings.Join(incident.Components, ", "),
+					"Status":      string(update.Status),
+					"Message":     update.Message,
+					"Link":        s.baseURL + statusPagePath,
+					"Unsubscribe": s.baseURL + statusSubscriptionPath + s.subscriptionToken(subscriber.ID),
+				},
+			}
+			if err := s.notifications.Send(ctx, notification); err != nil {
+				s.logger.Error("发送状态页通知失败", zap.Error(err), zap.String("update_id", update.ID),
+					zap.String("subscriber_id", subscriber.ID))
+				continue
+			}
+			sent++
+		}
+	}
+	return sent, nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeStatusPageRepository 内存中的状态页事件和订阅者
+type fakeStatusPageRepository struct {
+	incidents   []*models.StatusIncident
+	updates     []*models.StatusIncidentUpdate
+	subscribers []*models.StatusSubscriber
+	lists       int
+}
+
+func (r *fakeStatusPageRepository) CreateIncident(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error {
+	incident.ID = fmt.Sprintf("i%d", len(r.incidents)+1)
+	incident.CreatedAt = time.Now()
+	r.incidents = append(r.incidents, incident)
+	return r.addUpdate(incident.ID, update)
+}
+
+func (r *fakeStatusPageRepository) addUpdate(incidentID string, update *models.StatusIncidentUpdate) error {
+	update.ID = fmt.Sprintf("u%d", len(r.updates)+1)
+	update.IncidentID = incidentID
+	update.CreatedAt = time.Now()
+	r.updates = append(r.updates, update)
+	return nil
+}
+
+func (r *fakeStatusPageRepository) GetIncident(ctx context.Context, id string) (*models.StatusIncident, error) {
+	for _, incident := range r.incidents {
+		if incident.ID == id {
+			copied := *incident
+			copied.Updates = nil
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrStatusIncidentNotFound
+}
+
+func (r *fakeStatusPageRepository) ListIncidents(ctx context.Context, filter *models.StatusIncidentFilter) ([]*models.StatusIncident, error) {
+	r.lists++
+	incidents := make([]*models.StatusIncident, 0)
+	for _, incident := range r.incidents {
+		if filter.Open && incident.ResolvedAt != nil && (filter.ClosedSince == nil || incident.ResolvedAt.Before(*filter.ClosedSince)) {
+			continue
+		}
+		copied := *incident
+		copied.Updates = nil
+		incidents = append(incidents, &copied)
+	}
+	return incidents, nil
+}
+
+func (r *fakeStatusPageRepository) UpdateIncident(ctx context.Context, incident *models.StatusIncident) error {
+	for i, existing := range r.incidents {
+		if existing.ID == incident.ID {
+			r.incidents[i] = incident
+			return nil
+		}
+	}
+	return models.ErrStatusIncidentNotFound
+}
+
+func (r *fakeStatusPageRepository) AddUpdate(ctx context.Context, incident *models.StatusIncident, update *models.StatusIncidentUpdate) error {
+	if err := r.UpdateIncident(ctx, incident); err != nil {
+		return err
+	}
+	return r.addUpdate(incident.ID, update)
+}
+
+func (r *fakeStatusPageRepository) DeleteIncident(ctx context.Context, id string) error {
+	for i, incident := range r.incidents {
+		if incident.ID == id {
+			r.incidents = append(r.incidents[:i], r.incidents[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrStatusIncidentNotFound
+}
+
+func (r *fakeStatusPageRepository) ListUpdates(ctx context.Context, incidentIDs []string) ([]*models.StatusIncidentUpdate, error) {
+	var updates []*models.StatusIncidentUpdate
+	for _, update := range r.updates {
+		for _, id := range incidentIDs {
+			if update.IncidentID == id {
+				updates = append(updates, update)
+			}
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool { return updates[i].ID > updates[j].ID })
+	return updates, nil
+}
+
+func (r *fakeStatusPageRepository) ListPendingUpdates(ctx context.Context, limit int) ([]*models.StatusIncidentUpdate, error) {
+	var updates []*models.StatusIncidentUpdate
+	for _, update := range r.updates {
+		if update.Notify && update.NotifiedAt == nil && len(updates) < limit {
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+func (r *fakeStatusPageRepository) MarkUpdateNotified(ctx context.Context, id string, at time.Time) (bool, error) {
+	for _, update := range r.updates {
+		if update.ID == id && update.NotifiedAt == nil {
+			update.NotifiedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeStatusPageRepository) UpsertSubscriber(ctx context.Context, subscriber *models.StatusSubscriber) (bool, error) {
+	for _, existing := range r.subscribers {
+		if existing.Email == subscriber.Email {
+			if existing.ConfirmedAt != nil {
+				return false, nil
+			}
+			existing.Components = subscriber.Components
+			subscriber.ID = existing.ID
+			return true, nil
+		}
+	}
+	subscriber.ID = fmt.Sprintf("s%d", len(r.subscribers)+1)
+	r.subscribers = append(r.subscribers, subscriber)
+	return true, nil
+}
+
+func (r *fakeStatusPageRepository) GetSubscriber(ctx context.Context, id string) (*models.StatusSubscriber, error) {
+	for _, subscriber := range r.subscribers {
+		if subscriber.ID == id {
+			copied := *subscriber
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrStatusSubscriberNotFound
+}
+
+func (r *fakeStatusPageRepository) ConfirmSubscriber(ctx context.Context, id string, at time.Time) error {
+	for _, subscriber := range r.subscribers {
+		if subscriber.ID == id {
+			subscriber.ConfirmedAt = &at
+			return nil
+		}
+	}
+	return models.ErrStatusSubscriberNotFound
+}
+
+func (r *fakeStatusPageRepository) DeleteSubscriber(ctx context.Context, id string) error {
+	for i, subscriber := range r.subscribers {
+		if subscriber.ID == id {
+			r.subscribers = append(r.subscribers[:i], r.subscribers[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrStatusSubscriberNotFound
+}
+
+func (r *fakeStatusPageRepository) ListConfirmedSubscribers(ctx context.Context) ([]*models.StatusSubscriber, error) {
+	var subscribers []*models.StatusSubscriber
+	for _, subscriber := range r.subscribers {
+		if subscriber.ConfirmedAt != nil {
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+	return subscribers, nil
+}
+
+type statusPageRepoManager struct {
+	*serviceCatalogRepoManager
+	statusPage *fakeStatusPageRepository
+}
+
+func (m *statusPageRepoManager) StatusPage() repository.StatusPageRepository {
+	return m.statusPage
+}
+
+func newStatusPageTestService(t *testing.T, cfg config.StatusPageConfig) (*statusPageRepoManager, *fakeSurveyNotificationService, *statusPageService) {
+	catalogRepoManager, catalog := newServiceCatalogTestService(t)
+	repoManager := &statusPageRepoManager{
+		serviceCatalogRepoManager: catalogRepoManager,
+		statusPage:                &fakeStatusPageRepository{},
+	}
+	notifications := &fakeSurveyNotificationService{}
+	health := NewServiceHealthService(repoManager, config.ServiceHealthConfig{}, zap.NewNop())
+	cfg.Enabled = true
+	cfg.Title = "Pulse Status"
+	cfg.CacheTTL = time.Minute
+	cfg.HistoryWindow = 7 * 24 * time.Hour
+	svc := NewStatusPageService(repoManager, catalog, health, notifications, cfg, "https://pulse.example.com/", "secret", zap.NewNop()).(*statusPageService)
+	return repoManager, notifications, svc
+}
+
+func TestStatusPageService_Page(t *testing.T) {
+	repoManager, _, svc := newStatusPageTestService(t, config.StatusPageConfig{})
+	ctx := context.Background()
+
+	// 组件必须在服务目录中，故障需要影响程度，维护需要计划时间
+	_, err := svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "结算失败", Impact: models.StatusImpactMajor,
+		Components: []string{"cart"}, Message: "排查中"}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "结算失败", Components: []string{"checkout"}, Message: "排查中"}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.CreateIncident(ctx, &models.StatusIncidentRequest{Kind: models.StatusIncidentKindMaintenance, Title: "数据库升级",
+		Components: []string{"postgres"}, Message: "计划升级"}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	incident, err := svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "结算失败", Impact: models.StatusImpactMajor,
+		Components: []string{"checkout"}, TicketID: strPtr("t1"), Message: "正在排查结算接口报错"}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusIncidentInvestigating, incident.Status)
+	start := time.Now().Add(time.Hour)
+	end := start.Add(time.Hour)
+	_, err = svc.CreateIncident(ctx, &models.StatusIncidentRequest{Kind: models.StatusIncidentKindMaintenance, Title: "数据库升级",
+		Components: []string{"postgres"}, ScheduledStart: &start, ScheduledEnd: &end, Message: "计划升级数据库"}, "admin")
+	require.NoError(t, err)
+
+	// 故障影响的组件按影响程度降级，尚未开始的维护不影响组件；公开内容不包含内部工单和发布人
+	page, err := svc.Page(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentPartialOutage, page.State)
+	require.Len(t, page.Components, 2)
+	assert.Equal(t, "checkout", page.Components[0].Name)
+	assert.Equal(t, models.StatusComponentPartialOutage, page.Components[0].State)
+	assert.Equal(t, models.StatusComponentOperational, page.Components[1].State)
+	require.Len(t, page.Incidents, 1)
+	assert.Nil(t, page.Incidents[0].TicketID)
+	assert.Empty(t, page.Incidents[0].CreatedBy)
+	require.Len(t, page.Incidents[0].Updates, 1)
+	assert.Empty(t, page.Incidents[0].Updates[0].CreatedBy)
+	assert.Len(t, page.Maintenances, 1)
+
+	// 缓存时长内复用内容，发布进展后立即失效
+	lists := repoManager.statusPage.lists
+	_, err = svc.Page(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, lists, repoManager.statusPage.lists)
+
+	_, err = svc.PostUpdate(ctx, incident.ID, &models.StatusIncidentUpdateRequest{Status: models.StatusMaintenanceCompleted, Message: "完成"}, "admin")
+	assert.ErrorIs(t, err, models.ErrInvalidInput, "故障不能使用维护的进展")
+	resolved, err := svc.PostUpdate(ctx, incident.ID, &models.StatusIncidentUpdateRequest{Status: models.StatusIncidentResolved, Message: "已修复"}, "admin")
+	require.NoError(t, err)
+	assert.NotNil(t, resolved.ResolvedAt)
+	require.Len(t, resolved.Updates, 2)
+	assert.Equal(t, "已修复", resolved.Updates[0].Message)
+
+	page, err = svc.Page(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusComponentOperational, page.State)
+	assert.Empty(t, page.Incidents)
+	require.Len(t, page.History, 1)
+	assert.Equal(t, incident.ID, page.History[0].ID)
+}
+
+func TestStatusPageService_ConfiguredComponents(t *testing.T) {
+	_, _, svc := newStatusPageTestService(t, config.StatusPageConfig{Components: []string{"checkout"}})
+	ctx := context.Background()
+
+	// 只影响未展示组件的事件不出现在状态页上
+	_, err := svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "主库延迟", Impact: models.StatusImpactMinor,
+		Components: []string{"postgres"}, Message: "复制延迟升高"}, "admin")
+	require.NoError(t, err)
+
+	page, err := svc.Page(ctx)
+	require.NoError(t, err)
+	require.Len(t, page.Components, 1)
+	assert.Equal(t, models.StatusComponentOperational, page.State)
+	assert.Empty(t, page.Incidents)
+
+	// 只能订阅展示的组件
+	err = svc.Subscribe(ctx, &models.StatusSubscribeRequest{Email: "user@example.com", Components: []string{"postgres"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestStatusPageService_AlertHealth(t *testing.T) {
+	ctx := context.Background()
+	for _, alertHealth := range []bool{false, true} {
+		repoManager, _, svc := newStatusPageTestService(t, config.StatusPageConfig{AlertHealth: alertHealth})
+		repoManager.catalog.active = []*models.ServiceAlertCount{
+			{Service: "postgres", Severity: models.AlertSeverityCritical, Count: 1},
+		}
+
+		// 开启后组件状态取事件和告警健康度中更差的状态
+		page, err := svc.Page(ctx)
+		require.NoError(t, err)
+		want := models.StatusComponentOperational
+		if alertHealth {
+			want = models.StatusComponentMajorOutage
+		}
+		assert.Equal(t, want, page.State)
+		assert.Equal(t, want, page.Components[1].State)
+	}
+}
+
+func TestStatusPageService_Subscriptions(t *testing.T) {
+	repoManager, notifications, svc := newStatusPageTestService(t, config.StatusPageConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.Subscribe(ctx, &models.StatusSubscribeRequest{Email: "User@Example.com", Components: []string{"checkout"}}))
+	require.NoError(t, svc.Subscribe(ctx, &models.StatusSubscribeRequest{Email: "ops@example.com"}))
+	require.Len(t, notifications.sent, 2)
+	assert.Equal(t, "user@example.com", notifications.sent[0].Recipient)
+	link := notifications.sent[0].MessageData["Link"].(string)
+	require.True(t, strings.HasPrefix(link, "https://pulse.example.com/status-page/subscriptions/"))
+	token := strings.TrimPrefix(link, "https://pulse.example.com/status-page/subscriptions/")
+
+	// 篡改的令牌无效
+	_, err := svc.Subscription(ctx, "s2."+strings.SplitN(token, ".", 2)[1])
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	view, err := svc.Confirm(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, view.Confirmed)
+	opsToken := strings.TrimPrefix(notifications.sent[1].MessageData["Link"].(string), "https://pulse.example.com/status-page/subscriptions/")
+	_, err = svc.Confirm(ctx, opsToken)
+	require.NoError(t, err)
+
+	// 已确认的邮箱再次订阅时不修改订阅也不发送邮件
+	require.NoError(t, svc.Subscribe(ctx, &models.StatusSubscribeRequest{Email: "user@example.com", Components: []string{"postgres"}}))
+	assert.Len(t, notifications.sent, 2)
+	assert.Equal(t, []string{"checkout"}, repoManager.statusPage.subscribers[0].Components)
+
+	// 发布的进展按订阅的组件通知，每条进展只通知一次，不通知的进展不发送
+	notifications.sent = nil
+	_, err = svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "主库延迟", Impact: models.StatusImpactMinor,
+		Components: []string{"postgres"}, Message: "复制延迟升高"}, "admin")
+	require.NoError(t, err)
+	silent := false
+	_, err = svc.CreateIncident(ctx, &models.StatusIncidentRequest{Title: "结算失败", Impact: models.StatusImpactCritical,
+		Components: []string{"checkout"}, Message: "内部排查", Notify: &silent}, "admin")
+	require.NoError(t, err)
+
+	sent, err := svc.NotifyPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, notifications.sent, 1)
+	assert.Equal(t, "ops@example.com", notifications.sent[0].Recipient)
+	assert.Equal(t, "notification.status_update", notifications.sent[0].MessageKey)
+	assert.Equal(t, "https://pulse.example.com/status-page/subscriptions/"+opsToken, notifications.sent[0].MessageData["Unsubscribe"])
+	sent, err = svc.NotifyPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	require.NoError(t, svc.Unsubscribe(ctx, opsToken))
+	_, err = svc.Subscription(ctx, opsToken)
+	assert.ErrorIs(t, err, models.ErrStatusSubscriberNotFound)
+
+	// 未配置签名密钥时不接受任何令牌，避免以空密钥伪造
+	svc.secret = nil
+	_, err = svc.Subscription(ctx, "s1."+svc.sign("s1"))
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// tagUsageLimit 标签使用情况最多返回的标签数量
+	tagUsageLimit = 1000
+	// tagJobListLimit 任务列表最多返回的任务数量
+	tagJobListLimit = 100
+)
+
+// tagGovernanceService 标签治理服务实现
+type tagGovernanceService struct {
+	repoManager repository.RepositoryManager
+	cfg         config.TagGovernanceConfig
+	logger      *zap.Logger
+}
+
+// NewTagGovernanceService 创建标签治理服务实例
+func NewTagGovernanceService(repoManager repository.RepositoryManager, cfg config.TagGovernanceConfig, logger *zap.Logger) TagGovernanceService {
+	return &tagGovernanceService{
+		repoManager: repoManager,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Interval 检查待执行任务的周期
+func (s *tagGovernanceService) Interval() time.Duration {
+	return s.cfg.JobCheckInterval
+}
+
+// Usage 获取使用次数不超过 maxUsage 的标签，maxUsage 为 0 时只返回孤立标签
+func (s *tagGovernanceService) Usage(ctx context.Context, maxUsage int64) ([]*models.TagUsage, error) {
+	if maxUsage < 0 {
+		return nil, fmt.Errorf("%w: 使用次数上限不能为负数", models.ErrInvalidInput)
+	}
+	return s.repoManager.Tag().Usage(ctx, maxUsage, tagUsageLimit)
+}
+
+// CreateJob 创建标签治理任务，任务由 Worker 在后台执行。重命名的目标标签不能已被使用，已被使用时应合并
+func (s *tagGovernanceService) CreateJob(ctx context.Context, req *models.TagJobRequest, createdBy string) (*models.TagJob, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.Type == models.TagJobTypeRename {
+		count, err := s.repoManager.Tag().CountTagged(ctx, req.Target)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: %s 已被 %d 条记录使用", models.ErrTagInUse, req.Target, count)
+		}
+	}
+
+	job := &models.TagJob{
+		Type:      req.Type,
+		Tag:       req.Tag,
+		Target:    req.Target,
+		CreatedBy: createdBy,
+	}
+	if err := s.repoManager.Tag().CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建标签治理任务", zap.String("job_id", job.ID), zap.String("type", string(job.Type)),
+		zap.String("tag", job.Tag), zap.String("target", job.Target), zap.String("created_by", createdBy))
+	return job, nil
+}
+
+// GetJob 获取标签治理任务及其进度
+func (s *tagGovernanceService) GetJob(ctx context.Context, id string) (*models.TagJob, error) {
+	return s.repoManager.Tag().GetJob(ctx, id)
+}
+
+// ListJobs 获取最近的标签治理任务
+func (s *tagGovernanceService) ListJobs(ctx context.Context) ([]*models.TagJob, error) {
+	return s.repoManager.Tag().ListJobs(ctx, tagJobListLimit)
+}
+
+// RunPendingJobs 依次执行所有待执行的任务，返回执行的任务数量。单个任务失败时记录到任务中并继续执行下一个
+func (s *tagGovernanceService) RunPendingJobs(ctx context.Context) (int, error) {
+	ran := 0
+	for ctx.Err() == nil {
+		job, err := s.repoManager.Tag().ClaimPendingJob(ctx)
+		if err != nil {
+			return ran, err
+		}
+		if job == nil {
+			break
+		}
+
+		s.runJob(ctx, job)
+		ran++
+	}
+	return ran, nil
+}
+
+// runJob 执行任务并记录结果
+func (s *tagGovernanceService) runJob(ctx context.Context, job *models.TagJob) {
+	err := s.replaceTag(ctx, job)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = models.TagJobStatusCompleted
+	if err != nil {
+		job.Status = models.TagJobStatusFailed
+		job.Error = err.Error()
+		s.logger.Error("标签治理任务执行失败", zap.Error(err), zap.String("job_id", job.ID))
+	} else {
+		s.logger.Info("标签治理任务执行完成", zap.String("job_id", job.ID), zap.Int64("processed", job.Processed))
+	}
+	if job.Processed > job.Total {
+		job.Total = job.Processed
+	}
+
+	if err := s.repoManager.Tag().UpdateJob(ctx, job); err != nil {
+		s.logger.Error("更新标签治理任务失败", zap.Error(err), zap.String("job_id", job.ID))
+	}
+}
+
+// replaceTag 分批替换各类资源中的标签，每批完成后更新任务进度，最后更新知识库标签目录
+func (s *tagGovernanceService) replaceTag(ctx context.Context, job *models.TagJob) error {
+	tags := s.repoManager.Tag()
+
+	total, err := tags.CountTagged(ctx, job.Tag)
+	if err != nil {
+		return err
+	}
+	job.Total = total
+	if err := tags.UpdateJob(ctx, job); err != nil {
+		return err
+	}
+
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	for _, resource := range models.TagResources {
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			updated, err := tags.ReplaceTag(ctx, resource, job.Tag, job.Replacement(), batchSize)
+			if err != nil {
+				return err
+			}
+			if updated > 0 {
+				job.Processed += updated
+				if err := tags.UpdateJob(ctx, job); err != nil {
+					return err
+				}
+			}
+			if updated < int64(batchSize) {
+				break
+			}
+		}
+	}
+
+	return tags.ReplaceDefinition(ctx, job.Tag, job.Replacement())
+}
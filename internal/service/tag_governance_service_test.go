@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeTagRepository 内存中的资源标签和标签治理任务
+type fakeTagRepository struct {
+	repository.TagRepository
+	tags        map[models.TagResource][][]string
+	definitions map[string]bool
+	jobs        []*models.TagJob
+	progress    []int64
+}
+
+func (r *fakeTagRepository) CountTagged(ctx context.Context, tag string) (int64, error) {
+	var count int64
+	for _, items := range r.tags {
+		for _, tags := range items {
+			if containsString(tags, tag) {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeTagRepository) ReplaceTag(ctx context.Context, resource models.TagResource, from, to string, limit int) (int64, error) {
+	var updated int64
+	for i, tags := range r.tags[resource] {
+		if int(updated) == limit {
+			break
+		}
+		if !containsString(tags, from) {
+			continue
+		}
+		replaced := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if tag == from {
+				tag = to
+			}
+			if tag != "" && !containsString(replaced, tag) {
+				replaced = append(replaced, tag)
+			}
+		}
+		r.tags[resource][i] = replaced
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *fakeTagRepository) ReplaceDefinition(ctx context.Context, from, to string) error {
+	if r.definitions[from] {
+		delete(r.definitions, from)
+		if to != "" {
+			r.definitions[to] = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeTagRepository) CreateJob(ctx context.Context, job *models.TagJob) error {
+	job.ID = uuid.New().String()
+	job.Status = models.TagJobStatusPending
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func (r *fakeTagRepository) ClaimPendingJob(ctx context.Context) (*models.TagJob, error) {
+	for _, job := range r.jobs {
+		if job.Status == models.TagJobStatusPending {
+			job.Status = models.TagJobStatusRunning
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTagRepository) UpdateJob(ctx context.Context, job *models.TagJob) error {
+	r.progress = append(r.progress, job.Processed)
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type tagGovernanceRepoManager struct {
+	*MockRepositoryManager
+	tags *fakeTagRepository
+}
+
+func (m *tagGovernanceRepoManager) Tag() repository.TagRepository { return m.tags }
+
+func newTagGovernanceTestRepoManager() *tagGovernanceRepoManager {
+	return &tagGovernanceRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		tags: &fakeTagRepository{
+			tags: map[models.TagResource][][]string{
+				models.TagResourceAlert:     {{"db"}, {"db", "payments"}, {"web"}},
+				models.TagResourceTicket:    {{"db", "database"}},
+				models.TagResourceKnowledge: {{"database"}},
+			},
+			definitions: map[string]bool{"db": true, "database": true},
+		},
+	}
+}
+
+func TestTagGovernanceService_Merge(t *testing.T) {
+	repoManager := newTagGovernanceTestRepoManager()
+	svc := NewTagGovernanceService(repoManager, config.TagGovernanceConfig{BatchSize: 1}, zap.NewNop())
+	ctx := context.Background()
+
+	// 目标标签已被使用时不能重命名，只能合并
+	_, err := svc.CreateJob(ctx, &models.TagJobRequest{Type: models.TagJobTypeRename, Tag: "db", Target: "database"}, "u1")
+	assert.ErrorIs(t, err, models.ErrTagInUse)
+	_, err = svc.CreateJob(ctx, &models.TagJobRequest{Type: models.TagJobTypeMerge, Tag: "db", Target: "db"}, "u1")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	job, err := svc.CreateJob(ctx, &models.TagJobRequest{Type: models.TagJobTypeMerge, Tag: " db ", Target: "database"}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "db", job.Tag)
+
+	ran, err := svc.RunPendingJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+
+	// 每批完成后更新进度
+	assert.Equal(t, models.TagJobStatusCompleted, job.Status)
+	assert.Equal(t, int64(3), job.Total)
+	assert.Equal(t, int64(3), job.Processed)
+	assert.Equal(t, []int64{0, 1, 2, 3, 3}, repoManager.tags.progress)
+	assert.NotNil(t, job.FinishedAt)
+
+	assert.Equal(t, [][]string{{"database"}, {"database", "payments"}, {"web"}}, repoManager.tags.tags[models.TagResourceAlert])
+	assert.Equal(t, [][]string{{"database"}}, repoManager.tags.tags[models.TagResourceTicket])
+	assert.Equal(t, map[string]bool{"database": true}, repoManager.tags.definitions)
+}
+
+func TestTagGovernanceService_Delete(t *testing.T) {
+	repoManager := newTagGovernanceTestRepoManager()
+	svc := NewTagGovernanceService(repoManager, config.TagGovernanceConfig{BatchSize: 10}, zap.NewNop())
+	ctx := context.Background()
+
+	job, err := svc.CreateJob(ctx, &models.TagJobRequest{Type: models.TagJobTypeDelete, Tag: "database", Target: "ignored"}, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, job.Target)
+
+	_, err = svc.RunPendingJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), job.Processed)
+	assert.Equal(t, [][]string{{"db"}}, repoManager.tags.tags[models.TagResourceTicket])
+	assert.Equal(t, [][]string{{}}, repoManager.tags.tags[models.TagResourceKnowledge])
+	assert.Equal(t, map[string]bool{"db": true}, repoManager.tags.definitions)
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/tabular"
+	"pulse/internal/repository"
+)
+
+// ticketImportListLimit 导入任务列表最多返回的任务数量
+const ticketImportListLimit = 100
+
+// ticketImportDateLayouts 截止时间支持的格式，不带时区的按 UTC 解析
+var ticketImportDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// ticketImportService 工单导入服务实现
+type ticketImportService struct {
+	repoManager repository.RepositoryManager
+	tickets     TicketService
+	cfg         config.TicketImportConfig
+	logger      *zap.Logger
+}
+
+// NewTicketImportService 创建工单导入服务实例
+func NewTicketImportService(repoManager repository.RepositoryManager, tickets TicketService, cfg config.TicketImportConfig, logger *zap.Logger) TicketImportService {
+	return &ticketImportService{
+		repoManager: repoManager,
+		tickets:     tickets,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Interval 检查待执行任务的周期
+func (s *ticketImportService) Interval() time.Duration {
+	return s.cfg.JobCheckInterval
+}
+
+// Upload 解析上传的 CSV 或 XLSX 文件并创建等待确认列映射的导入任务，任务中包含表头和按表头推荐的列映射
+func (s *ticketImportService) Upload(ctx context.Context, fileName string, data []byte, createdBy string) (*models.TicketImportJob, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: 导入文件为空", models.ErrInvalidInput)
+	}
+
+	format := tabular.DetectFormat(fileName, data)
+	table, err := tabular.Read(format, data, models.TicketImportMaxRows)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if len(table.Rows) == 0 {
+		return nil, fmt.Errorf("%w: 导入文件没有数据行", models.ErrInvalidInput)
+	}
+
+	job := &models.TicketImportJob{
+		FileName:  filepath.Base(fileName),
+		Format:    format,
+		Headers:   table.Headers,
+		Mapping:   models.SuggestTicketImportMapping(table.Headers),
+		Content:   data,
+		Total:     len(table.Rows),
+		CreatedBy: createdBy,
+	}
+	if err := s.repoManager.TicketImport().CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建工单导入任务", zap.String("job_id", job.ID), zap.String("file_name", job.FileName),
+		zap.String("format", string(job.Format)), zap.Int("total", job.Total), zap.String("created_by", createdBy))
+	return job, nil
+}
+
+// Start 确认列映射并开始导入，未指定列映射时使用上传时推荐的映射。任务由 Worker 在后台执行，
+// 试运行完成后可以修改列映射再次开始
+func (s *ticketImportService) Start(ctx context.Context, id string, req *models.TicketImportStartRequest) (*models.TicketImportJob, error) {
+	job, err := s.repoManager.TicketImport().GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Startable() {
+		return nil, models.ErrTicketImportJobStarted
+	}
+
+	if req.Mapping == nil {
+		req.Mapping = job.Mapping
+	}
+	if err := req.Validate(job.Headers); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.TicketImport().StartJob(ctx, id, req.Mapping, req.DryRun); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("开始工单导入任务", zap.String("job_id", id), zap.Bool("dry_run", req.DryRun))
+	return s.repoManager.TicketImport().GetJob(ctx, id)
+}
+
+// GetJob 获取工单导入任务及其进度和行错误
+func (s *ticketImportService) GetJob(ctx context.Context, id string) (*models.TicketImportJob, error) {
+	return s.repoManager.TicketImport().GetJob(ctx, id)
+}
+
+// ListJobs 获取最近的工单导入任务
+func (s *ticketImportService) ListJobs(ctx context.Context) ([]*models.TicketImportJob, error) {
+	return s.repoManager.TicketImport().ListJobs(ctx, ticketImportListLimit)
+}
+
+// RunPendingJobs 依次执行所有待执行的导入任务，返回执行的任务数量
+func (s *ticketImportService) RunPendingJobs(ctx context.Context) (int, error) {
+	ran := 0
+	for ctx.Err() == nil {
+		job, err := s.repoManager.TicketImport().ClaimPendingJob(ctx)
+		if err != nil {
+			return ran, err
+		}
+		if job == nil {
+			break
+		}
+
+		s.runJob(ctx, job)
+		ran++
+	}
+	return ran, nil
+}
+
+// runJob 执行导入任务并记录结果，单行失败不影响其他行，只有文件无法读取或查询用户失败时任务失败
+func (s *ticketImportService) runJob(ctx context.Context, job *models.TicketImportJob) {
+	err := s.importRows(ctx, job)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = models.TicketImportStatusCompleted
+	if err != nil {
+		job.Status = models.TicketImportStatusFailed
+		job.Error = err.Error()
+		s.logger.Error("工单导入任务执行失败", zap.Error(err), zap.String("job_id", job.ID))
+	} else {
+		s.logger.Info("工单导入任务执行完成", zap.String("job_id", job.ID), zap.Bool("dry_run", job.DryRun),
+			zap.Int("imported", job.Imported), zap.Int("failed", job.Failed))
+	}
+
+	if err := s.repoManager.TicketImport().UpdateJob(ctx, job); err != nil {
+		s.logger.Error("更新工单导入任务失败", zap.Error(err), zap.String("job_id", job.ID))
+	}
+}
+
+// importRows 逐行校验并创建工单，每处理 ProgressBatchSize 行更新一次进度。试运行只校验，Imported 为校验通过的行数
+func (s *ticketImportService) importRows(ctx context.Context, job *models.TicketImportJob) error {
+	table, err := tabular.Read(job.Format, job.Content, models.TicketImportMaxRows)
+	if err != nil {
+		return err
+	}
+	job.Total = len(table.Rows)
+
+	columns := make(map[string]int, len(job.Mapping))
+	for field, header := range job.Mapping {
+		if i := table.Column(header); i >= 0 {
+			columns[field] = i
+		}
+	}
+
+	builder := &ticketImportRowBuilder{
+		job:     job,
+		columns: columns,
+		users:   s.repoManager.User(),
+		userIDs: make(map[string]string),
+	}
+	numbers := make(map[string]int)
+
+	for _, row := range table.Rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ticket, rowErr, err := builder.build(ctx, row)
+		if err != nil {
+			return err
+		}
+		if rowErr == nil && ticket.Number != "" {
+			if first, ok := numbers[ticket.Number]; ok {
+				rowErr = builder.fieldError(row, models.TicketImportFieldNumber, fmt.Sprintf("工单编号与第 %d 行重复", first))
+			} else {
+				numbers[ticket.Number] = row.Line
+			}
+		}
+
+		switch {
+		case rowErr != nil:
+			job.AddError(rowErr)
+		case job.DryRun:
+			job.Imported++
+		default:
+			if err := s.tickets.Create(ctx, ticket); err != nil {
+				job.AddError(&models.TicketImportRowError{Row: row.Line, Message: err.Error()})
+			} else {
+				job.Imported++
+			}
+		}
+
+		job.Processed++
+		if s.cfg.ProgressBatchSize > 0 && job.Processed%s.cfg.ProgressBatchSize == 0 {
+			if err := s.repoManager.TicketImport().UpdateJob(ctx, job); err != nil {
+				s.logger.Warn("更新工单导入进度失败", zap.Error(err), zap.String("job_id", job.ID))
+			}
+		}
+	}
+	return nil
+}
+
+// ticketImportRowBuilder 按列映射将数据行转换为工单，缓存按用户名或邮箱查到的用户
+type ticketImportRowBuilder struct {
+	job     *models.TicketImportJob
+	columns map[string]int
+	users   repository.UserRepository
+	// userIDs 用户名或邮箱对应的用户ID，用户不存在时为空字符串
+	userIDs map[string]string
+}
+
+// value 获取行中字段映射的列的值，字段未映射时返回空字符串
+func (b *ticketImportRowBuilder) value(row tabular.Row, field models.TicketImportField) string {
+	i, ok := b.columns[string(field)]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(row.Value(i))
+}
+
+// fieldError 创建字段的行错误，包含字段映射的列和截断后的值
+func (b *ticketImportRowBuilder) fieldError(row tabular.Row, field models.TicketImportField, message string) *models.TicketImportRowError {
+	return &models.TicketImportRowError{
+		Row:     row.Line,
+		Field:   string(field),
+		Column:  b.job.Mapping[string(field)],
+		Value:   truncateRunes(b.value(row, field), 100),
+		Message: message,
+	}
+}
+
+// build 将数据行转换为工单，数据不合法时返回行错误，查询用户失败时返回 error
+func (b *ticketImportRowBuilder) build(ctx context.Context, row tabular.Row) (*models.Ticket, *models.TicketImportRowError, error) {
+	ticket := &models.Ticket{
+		Number:      b.value(row, models.TicketImportFieldNumber),
+		Title:       b.value(row, models.TicketImportFieldTitle),
+		Description: b.value(row, models.TicketImportFieldDescription),
+		Type:        models.TicketTypeIncident,
+		Source:      models.TicketSourceImport,
+		Tags:        splitTicketImportTags(b.value(row, models.TicketImportFieldTags)),
+		ReporterID:  b.job.CreatedBy,
+	}
+
+	if ticket.Title == "" {
+		return nil, b.fieldError(row, models.TicketImportFieldTitle, "标题不能为空"), nil
+	}
+	if utf8.RuneCountInString(ticket.Title) > 200 {
+		return nil, b.fieldError(row, models.TicketImportFieldTitle, "标题不能超过200个字符"), nil
+	}
+	if utf8.RuneCountInString(ticket.Description) > 5000 {
+		return nil, b.fieldError(row, models.TicketImportFieldDescription, "描述不能超过5000个字符"), nil
+	}
+	if len(ticket.Number) > 50 {
+		return nil, b.fieldError(row, models.TicketImportFieldNumber, "工单编号不能超过50个字符"), nil
+	}
+
+	if v := normalizeTicketImportEnum(b.value(row, models.TicketImportFieldType)); v != "" {
+		ticket.Type = models.TicketType(v)
+		if !ticket.Type.IsValid() {
+			return nil, b.fieldError(row, models.TicketImportFieldType, "无效的工单类型"), nil
+		}
+	}
+	if v := normalizeTicketImportEnum(b.value(row, models.TicketImportFieldStatus)); v != "" {
+		ticket.Status = models.TicketStatus(v)
+		if !ticket.Status.IsValid() {
+			return nil, b.fieldError(row, models.TicketImportFieldStatus, "无效的工单状态"), nil
+		}
+	}
+	if v := normalizeTicketImportEnum(b.value(row, models.TicketImportFieldPriority)); v != "" {
+		ticket.Priority = models.TicketPriority(v)
+		if !ticket.Priority.IsValid() {
+			return nil, b.fieldError(row, models.TicketImportFieldPriority, "无效的优先级"), nil
+		}
+	}
+	if v := normalizeTicketImportEnum(b.value(row, models.TicketImportFieldSeverity)); v != "" {
+		ticket.Severity = models.TicketSeverity(v)
+		if !ticket.Severity.IsValid() {
+			return nil, b.fieldError(row, models.TicketImportFieldSeverity, "无效的严重程度"), nil
+		}
+	}
+	if v := b.value(row, models.TicketImportFieldCategory); v != "" {
+		ticket.Category = &v
+	}
+	if v := b.value(row, models.TicketImportFieldSubcategory); v != "" {
+		ticket.Subcategory = &v
+	}
+
+	if v := b.value(row, models.TicketImportFieldDueDate); v != "" {
+		due, ok := parseTicketImportDate(v, b.job.Format)
+		if !ok {
+			return nil, b.fieldError(row, models.TicketImportFieldDueDate, "无法识别的日期，请使用 2006-01-02 或 RFC 3339 格式"), nil
+		}
+		ticket.DueDate = &due
+	}
+
+	for _, field := range []models.TicketImportField{models.TicketImportFieldReporter, models.TicketImportFieldAssignee} {
+		key := b.value(row, field)
+		if key == "" {
+			continue
+		}
+		userID, err := b.lookupUser(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if userID == "" {
+			return nil, b.fieldError(row, field, "用户不存在"), nil
+		}
+		if field == models.TicketImportFieldReporter {
+			ticket.ReporterID = userID
+		} else {
+			ticket.AssigneeID = &userID
+		}
+	}
+
+	for field, i := range b.columns {
+		name, ok := strings.CutPrefix(field, models.TicketImportCustomFieldPrefix)
+		if !ok {
+			continue
+		}
+		if v := strings.TrimSpace(row.Value(i)); v != "" {
+			if ticket.CustomFields == nil {
+				ticket.CustomFields = make(map[string]interface{})
+			}
+			ticket.CustomFields[name] = v
+		}
+	}
+
+	return ticket, nil, nil
+}
+
+// lookupUser 按邮箱或用户名查找用户，用户不存在时返回空字符串
+func (b *ticketImportRowBuilder) lookupUser(ctx context.Context, key string) (string, error) {
+	if userID, ok := b.userIDs[key]; ok {
+		return userID, nil
+	}
+
+	var user *models.User
+	var err error
+	if strings.Contains(key, "@") {
+		user, err = b.users.GetByEmail(ctx, key)
+	} else {
+		user, err = b.users.GetByUsername(ctx, key)
+	}
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return "", fmt.Errorf("查询用户 %s 失败: %w", key, err)
+	}
+
+	userID := ""
+	if user != nil {
+		userID = user.ID
+	}
+	b.userIDs[key] = userID
+	return userID, nil
+}
+
+// normalizeTicketImportEnum 将旧系统中的枚举值转换为小写下划线形式，如 In Progress 转换为 in_progress
+func normalizeTicketImportEnum(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+}
+
+// splitTicketImportTags 以逗号或分号拆分标签，去除空标签和重复标签
+func splitTicketImportTags(value string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseTicketImportDate 解析截止时间，XLSX 中的日期单元格保存为序列号
+func parseTicketImportDate(value string, format tabular.Format) (time.Time, bool) {
+	for _, layout := range ticketImportDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	if format == tabular.FormatXLSX {
+		return tabular.SerialTime(value)
+	}
+	return time.Time{}, false
+}
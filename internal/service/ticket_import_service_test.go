@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeTicketImportRepository 内存中的工单导入任务，progress 记录每次更新时的已处理行数
+type fakeTicketImportRepository struct {
+	repository.TicketImportRepository
+	jobs     map[string]*models.TicketImportJob
+	progress []int
+}
+
+func (r *fakeTicketImportRepository) CreateJob(ctx context.Context, job *models.TicketImportJob) error {
+	job.ID = "job-1"
+	job.Status = models.TicketImportStatusAwaitingMapping
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *fakeTicketImportRepository) GetJob(ctx context.Context, id string) (*models.TicketImportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, models.ErrTicketImportJobNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *fakeTicketImportRepository) StartJob(ctx context.Context, id string, mapping map[string]string, dryRun bool) error {
+	job := r.jobs[id]
+	job.Status = models.TicketImportStatusPending
+	job.Mapping = mapping
+	job.DryRun = dryRun
+	job.Processed, job.Imported, job.Failed, job.Errors = 0, 0, 0, nil
+	return nil
+}
+
+func (r *fakeTicketImportRepository) ClaimPendingJob(ctx context.Context) (*models.TicketImportJob, error) {
+	for _, job := range r.jobs {
+		if job.Status == models.TicketImportStatusPending {
+			job.Status = models.TicketImportStatusRunning
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTicketImportRepository) UpdateJob(ctx context.Context, job *models.TicketImportJob) error {
+	r.progress = append(r.progress, job.Processed)
+	copied := *job
+	r.jobs[job.ID] = &copied
+	return nil
+}
+
+type ticketImportUserRepository struct {
+	repository.UserRepository
+	lookups int
+}
+
+func (r *ticketImportUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.lookups++
+	if username == "alice" {
+		return &models.User{ID: "u-alice", Username: "alice"}, nil
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (r *ticketImportUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.lookups++
+	if email == "bob@example.com" {
+		return &models.User{ID: "u-bob", Email: "bob@example.com"}, nil
+	}
+	return nil, models.ErrUserNotFound
+}
+
+// ticketImportTicketService 记录创建的工单，标题为 conflict 的工单创建失败
+type ticketImportTicketService struct {
+	TicketService
+	created []*models.Ticket
+}
+
+func (s *ticketImportTicketService) Create(ctx context.Context, ticket *models.Ticket) error {
+	if ticket.Title == "conflict" {
+		return errors.New("工单编号已存在")
+	}
+	s.created = append(s.created, ticket)
+	return nil
+}
+
+type ticketImportRepoManager struct {
+	*MockRepositoryManager
+	imports *fakeTicketImportRepository
+	users   *ticketImportUserRepository
+}
+
+func (m *ticketImportRepoManager) TicketImport() repository.TicketImportRepository { return m.imports }
+func (m *ticketImportRepoManager) User() repository.UserRepository                 { return m.users }
+
+const ticketImportCSV = "Subject,Body,Priority,State,Owner,Requester,Due Date,Labels,Key,Region\n" +
+	"Disk full,Node 3 disk at 95%,High,In Progress,alice,bob@example.com,2024-03-01,\"disk; storage,disk\",HD-1,eu\n" +
+	",missing title,low,open,,,,,HD-2,\n" +
+	"Login broken,,extreme,open,,,,,HD-3,\n" +
+	"VPN slow,,,,carol,,,,HD-4,\n" +
+	"Printer jam,,,,alice,,31/02/2024,,HD-5,\n" +
+	"Duplicate,,,,,,,,HD-1,\n" +
+	"conflict,,,,,,,,HD-7,\n" +
+	"Mail bounced,,medium,resolved,,,2024-03-02T10:00:00Z,,,us\n"
+
+func newTicketImportTestService() (*ticketImportService, *fakeTicketImportRepository, *ticketImportTicketService, *ticketImportUserRepository) {
+	repo := &fakeTicketImportRepository{jobs: map[string]*models.TicketImportJob{}}
+	users := &ticketImportUserRepository{}
+	tickets := &ticketImportTicketService{}
+	svc := NewTicketImportService(&ticketImportRepoManager{imports: repo, users: users}, tickets,
+		config.TicketImportConfig{ProgressBatchSize: 3}, zap.NewNop())
+	return svc.(*ticketImportService), repo, tickets, users
+}
+
+func TestTicketImportService_UploadSuggestsMapping(t *testing.T) {
+	svc, _, _, _ := newTicketImportTestService()
+	ctx := context.Background()
+
+	job, err := svc.Upload(ctx, "legacy/export.csv", []byte(ticketImportCSV), "importer")
+	require.NoError(t, err)
+	assert.Equal(t, "export.csv", job.FileName)
+	assert.Equal(t, 8, job.Total)
+	assert.Equal(t, models.TicketImportStatusAwaitingMapping, job.Status)
+	assert.Equal(t, map[string]string{
+		"title": "Subject", "description": "Body", "priority": "Priority", "status": "State",
+		"assignee": "Owner", "reporter": "Requester", "due_date": "Due Date", "tags": "Labels", "number": "Key",
+	}, job.Mapping)
+
+	_, err = svc.Upload(ctx, "empty.csv", []byte("Subject,Body\n"), "importer")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	// 映射的列必须存在，且必须映射标题
+	_, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{Mapping: map[string]string{"title": "Summary"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{Mapping: map[string]string{"description": "Body"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	_, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{Mapping: map[string]string{"title": "Subject", "severity": "Subject"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestTicketImportService_DryRunThenImport(t *testing.T) {
+	svc, repo, tickets, users := newTicketImportTestService()
+	ctx := context.Background()
+
+	job, err := svc.Upload(ctx, "export.csv", []byte(ticketImportCSV), "importer")
+	require.NoError(t, err)
+	mapping := job.Mapping
+	mapping["custom_fields.region"] = "Region"
+
+	// 试运行只校验，不创建工单
+	job, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{Mapping: mapping, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketImportStatusPending, job.Status)
+
+	ran, err := svc.RunPendingJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	assert.Empty(t, tickets.created)
+
+	job, err = svc.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketImportStatusCompleted, job.Status)
+	assert.Equal(t, 8, job.Processed)
+	assert.Equal(t, 3, job.Imported)
+	assert.Equal(t, 5, job.Failed)
+	assert.Equal(t, []*models.TicketImportRowError{
+		{Row: 3, Field: "title", Column: "Subject", Message: "标题不能为空"},
+		{Row: 4, Field: "priority", Column: "Priority", Value: "extreme", Message: "无效的优先级"},
+		{Row: 5, Field: "assignee", Column: "Owner", Value: "carol", Message: "用户不存在"},
+		{Row: 6, Field: "due_date", Column: "Due Date", Value: "31/02/2024", Message: "无法识别的日期，请使用 2006-01-02 或 RFC 3339 格式"},
+		{Row: 7, Field: "number", Column: "Key", Value: "HD-1", Message: "工单编号与第 2 行重复"},
+	}, job.Errors)
+	// 每处理 3 行更新一次进度，最后更新结果
+	assert.Equal(t, []int{3, 6, 8}, repo.progress)
+	// 同一用户只查询一次
+	assert.Equal(t, 3, users.lookups)
+
+	// 试运行完成后可以再次开始正式导入
+	_, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{Mapping: mapping})
+	require.NoError(t, err)
+	_, err = svc.RunPendingJobs(ctx)
+	require.NoError(t, err)
+
+	job, err = svc.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, job.Imported)
+	assert.Equal(t, 6, job.Failed)
+	assert.Equal(t, &models.TicketImportRowError{Row: 8, Message: "工单编号已存在"}, job.Errors[5])
+
+	require.Len(t, tickets.created, 2)
+	disk := tickets.created[0]
+	assert.Equal(t, "HD-1", disk.Number)
+	assert.Equal(t, "Node 3 disk at 95%", disk.Description)
+	assert.Equal(t, models.TicketPriorityHigh, disk.Priority)
+	assert.Equal(t, models.TicketStatusInProgress, disk.Status)
+	assert.Equal(t, models.TicketTypeIncident, disk.Type)
+	assert.Equal(t, models.TicketSourceImport, disk.Source)
+	assert.Equal(t, "u-bob", disk.ReporterID)
+	assert.Equal(t, "u-alice", *disk.AssigneeID)
+	assert.Equal(t, []string{"disk", "storage"}, disk.Tags)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *disk.DueDate)
+	assert.Equal(t, map[string]interface{}{"region": "eu"}, disk.CustomFields)
+
+	mail := tickets.created[1]
+	assert.Empty(t, mail.Number)
+	assert.Equal(t, "importer", mail.ReporterID)
+	assert.Equal(t, models.TicketStatusResolved, mail.Status)
+
+	// 正式导入后不能再次开始
+	_, err = svc.Start(ctx, job.ID, &models.TicketImportStartRequest{})
+	assert.ErrorIs(t, err, models.ErrTicketImportJobStarted)
+}
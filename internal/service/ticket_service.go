@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -13,15 +14,31 @@ import (
 
 // ticketService 工单服务实现
 type ticketService struct {
-	repoManager repository.RepositoryManager
-	logger      *zap.Logger
+	repoManager  repository.RepositoryManager
+	customFields CustomFieldService
+	services     ServiceAttributor
+	assigner     Assigner
+	inbox        InboxNotifier
+	events       EventPublisher
+	feedback     KnowledgeFeedbackPrompter
+	slaClock     SLAClockSyncer
+	logger       *zap.Logger
 }
 
-// NewTicketService 创建工单服务实例
-func NewTicketService(repoManager repository.RepositoryManager, logger *zap.Logger) TicketService {
+// NewTicketService 创建工单服务实例，services 为 nil 时不将工单归属到服务，assigner 为 nil 时不自动分派处理人，
+// inbox 为 nil 时不向处理人投递站内通知，events 为 nil 时不发布工单事件，feedback 为 nil 时解决工单不提示评价引用的文章，
+// slaClock 为 nil 时状态变化不暂停或恢复SLA计时
+func NewTicketService(repoManager repository.RepositoryManager, services ServiceAttributor, assigner Assigner, inbox InboxNotifier, events EventPublisher, feedback KnowledgeFeedbackPrompter, slaClock SLAClockSyncer, logger *zap.Logger) TicketService {
 	return &ticketService{
-		repoManager: repoManager,
-		logger:      logger,
+		repoManager:  repoManager,
+		customFields: NewCustomFieldService(repoManager, logger),
+		services:     services,
+		assigner:     assigner,
+		inbox:        inbox,
+		events:       events,
+		feedback:     feedback,
+		slaClock:     slaClock,
+		logger:       logger,
 	}
 }
 
@@ -52,13 +69,39 @@ func (s *ticketService) Create(ctx context.Context, ticket *models.Ticket) error
 		ticket.Priority = models.TicketPriorityMedium
 	}
 
+	// 校验自定义字段
+	customFields, err := s.customFields.Validate(ctx, models.CustomFieldResourceTicket, ticket.CustomFields)
+	if err != nil {
+		return err
+	}
+	ticket.CustomFields = customFields
+
+	// 归属到服务并分派给服务的负责团队
+	s.attributeService(ctx, ticket)
+
+	// 未指定处理人时按处理团队的分派策略分派，分派失败不影响工单创建
+	if ticket.AssigneeID == nil && ticket.TeamName != nil {
+		userID, err := s.pickAssignee(ctx, *ticket.TeamName, ticket, "")
+		if err != nil {
+			s.logger.Warn("工单自动分派失败", zap.Error(err), zap.String("title", ticket.Title), zap.String("team", *ticket.TeamName))
+		} else if userID != "" {
+			ticket.AssigneeID = &userID
+		}
+	}
+
 	// 创建工单
-	err := s.repoManager.Ticket().Create(ctx, ticket)
+	err = s.repoManager.Ticket().Create(ctx, ticket)
 	if err != nil {
 		s.logger.Error("创建工单失败", zap.Error(err), zap.String("title", ticket.Title))
 		return fmt.Errorf("创建工单失败: %w", err)
 	}
 
+	// 报告人把工单分派给自己时不通知
+	if ticket.AssigneeID != nil && *ticket.AssigneeID != ticket.ReporterID {
+		s.notifyAssignee(ctx, ticket, *ticket.AssigneeID)
+	}
+	s.publish(ctx, models.WebhookEventTicketCreated, ticket)
+
 	s.logger.Info("工单创建成功", zap.String("id", ticket.ID), zap.String("number", ticket.Number))
 	return nil
 }
@@ -89,6 +132,11 @@ func (s *ticketService) List(ctx context.Context, filter *models.TicketFilter) (
 	return ticketList.Tickets, ticketList.Total, nil
 }
 
+// Stream 按列表的过滤条件逐条读取全部工单，用于导出
+func (s *ticketService) Stream(ctx context.Context, filter *models.TicketFilter, fn func(*models.Ticket) error) error {
+	return s.repoManager.Ticket().Stream(ctx, filter, fn)
+}
+
 // Update 更新工单
 func (s *ticketService) Update(ctx context.Context, ticket *models.Ticket) error {
 	if ticket == nil {
@@ -108,6 +156,13 @@ func (s *ticketService) Update(ctx context.Context, ticket *models.Ticket) error
 		return fmt.Errorf("工单不存在")
 	}
 
+	// 校验自定义字段
+	customFields, err := s.customFields.Validate(ctx, models.CustomFieldResourceTicket, ticket.CustomFields)
+	if err != nil {
+		return err
+	}
+	ticket.CustomFields = customFields
+
 	// 更新工单
 	err = s.repoManager.Ticket().Update(ctx, ticket)
 	if err != nil {
@@ -115,6 +170,14 @@ func (s *ticketService) Update(ctx context.Context, ticket *models.Ticket) error
 		return fmt.Errorf("更新工单失败: %w", err)
 	}
 
+	if ticket.Status != "" {
+		s.syncSLAClock(ctx, ticket.ID, ticket.Status)
+	}
+	s.publishLatest(ctx, models.WebhookEventTicketUpdated, ticket.ID)
+	if ticket.Status == models.TicketStatusResolved {
+		s.promptKnowledgeFeedback(ctx, ticket.ID)
+	}
+
 	s.logger.Info("工单更新成功", zap.String("id", ticket.ID))
 	return nil
 }
@@ -172,10 +235,154 @@ func (s *ticketService) Assign(ctx context.Context, id string, userID string) er
 		return fmt.Errorf("分配工单失败: %w", err)
 	}
 
+	if s.inbox != nil || s.events != nil {
+		if ticket, err := s.repoManager.Ticket().GetByID(ctx, id); err == nil {
+			s.notifyAssignee(ctx, ticket, userID)
+			s.publish(ctx, models.WebhookEventTicketAssigned, ticket)
+		}
+	}
+
 	s.logger.Info("工单分配成功", zap.String("id", id), zap.String("userID", userID))
 	return nil
 }
 
+// AutoAssign 按团队的分派策略将工单重新分派给当前处理人以外的成员，用于升级；
+// team 为空时使用工单所属服务的负责团队
+func (s *ticketService) AutoAssign(ctx context.Context, id string, team string) (*models.Ticket, error) {
+	if id == "" {
+		return nil, fmt.Errorf("工单ID不能为空")
+	}
+
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if team == "" {
+		team = s.ownerTeam(ctx, ticket)
+	}
+	if team == "" {
+		return nil, fmt.Errorf("%w: 工单未归属到有负责团队的服务，请指定团队", models.ErrInvalidInput)
+	}
+
+	exclude := ""
+	if ticket.AssigneeID != nil {
+		exclude = *ticket.AssigneeID
+	}
+	userID, err := s.pickAssignee(ctx, team, ticket, exclude)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, models.ErrNoAssignee
+	}
+
+	if err := s.repoManager.Ticket().Assign(ctx, id, userID); err != nil {
+		s.logger.Error("分配工单失败", zap.Error(err), zap.String("id", id), zap.String("userID", userID))
+		return nil, fmt.Errorf("分配工单失败: %w", err)
+	}
+
+	ticket.AssigneeID = &userID
+	ticket.TeamName = &team
+	s.notifyAssignee(ctx, ticket, userID)
+	s.publish(ctx, models.WebhookEventTicketAssigned, ticket)
+	s.logger.Info("工单自动分派成功", zap.String("id", id), zap.String("team", team),
+		zap.String("previous", exclude), zap.String("userID", userID))
+	return ticket, nil
+}
+
+// notifyAssignee 向工单的处理人投递站内通知
+func (s *ticketService) notifyAssignee(ctx context.Context, ticket *models.Ticket, userID string) {
+	if s.inbox == nil {
+		return
+	}
+	s.inbox.Notify(ctx, &models.InboxItem{
+		UserID:       userID,
+		Kind:         models.InboxKindAssigned,
+		ResourceType: models.InboxResourceTicket,
+		ResourceID:   ticket.ID,
+		MessageKey:   "inbox.assigned_ticket",
+		MessageData: map[string]interface{}{
+			"Number":   ticket.Number,
+			"Title":    ticket.Title,
+			"Priority": ticket.Priority,
+		},
+	})
+}
+
+// publish 向出站 Webhook 订阅发布工单事件
+func (s *ticketService) publish(ctx context.Context, event models.WebhookEvent, ticket *models.Ticket) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(ctx, event, ticket)
+}
+
+// promptKnowledgeFeedback 工单解决后提示处理人评价引用的知识库文章
+func (s *ticketService) promptKnowledgeFeedback(ctx context.Context, id string) {
+	if s.feedback == nil {
+		return
+	}
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("提示评价引用文章失败", zap.Error(err), zap.String("id", id))
+		return
+	}
+	s.feedback.PromptResolved(ctx, ticket)
+}
+
+// publishLatest 重新读取工单后发布事件，事件内容包含关闭人、状态等最新信息
+func (s *ticketService) publishLatest(ctx context.Context, event models.WebhookEvent, id string) {
+	if s.events == nil {
+		return
+	}
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("发布工单事件失败", zap.Error(err), zap.String("id", id), zap.String("event", string(event)))
+		return
+	}
+	s.events.Publish(ctx, event, ticket)
+}
+
+// pickAssignee 按团队的分派策略为工单选择处理人，未配置分派时返回空字符串
+func (s *ticketService) pickAssignee(ctx context.Context, team string, ticket *models.Ticket, exclude string) (string, error) {
+	if s.assigner == nil {
+		return "", nil
+	}
+	return s.assigner.Pick(ctx, team, models.AssignmentTarget{Tags: ticket.Tags, Labels: ticket.Labels, Exclude: exclude})
+}
+
+// ownerTeam 返回工单所属服务的负责团队，工单的 labels 不持久化，服务归属从 service: 标签读取
+func (s *ticketService) ownerTeam(ctx context.Context, ticket *models.Ticket) string {
+	team, err := ticketOwnerTeam(ctx, s.services, ticket)
+	if err != nil {
+		s.logger.Warn("获取工单所属服务失败", zap.Error(err), zap.String("id", ticket.ID))
+	}
+	return team
+}
+
+// ticketOwnerTeam 按工单第一个能识别的 service: 标签返回服务的负责团队，services 为 nil 时返回空
+func ticketOwnerTeam(ctx context.Context, services ServiceAttributor, ticket *models.Ticket) (string, error) {
+	if services == nil {
+		return "", nil
+	}
+	for _, tag := range ticket.Tags {
+		if !strings.HasPrefix(tag, models.ServiceTicketTagPrefix) {
+			continue
+		}
+		service, err := services.Attribute(ctx, map[string]string{
+			models.ServiceLabel: strings.TrimPrefix(tag, models.ServiceTicketTagPrefix),
+		})
+		if err != nil {
+			return "", err
+		}
+		if service != nil {
+			return service.OwnerTeam, nil
+		}
+	}
+	return "", nil
+}
+
 // Close 关闭工单
 func (s *ticketService) Close(ctx context.Context, id string, userID string) error {
 	if id == "" {
@@ -202,6 +409,9 @@ func (s *ticketService) Close(ctx context.Context, id string, userID string) err
 		return fmt.Errorf("关闭工单失败: %w", err)
 	}
 
+	s.syncSLAClock(ctx, id, models.TicketStatusClosed)
+	s.publishLatest(ctx, models.WebhookEventTicketClosed, id)
+
 	s.logger.Info("工单关闭成功", zap.String("id", id), zap.String("userID", userID))
 	return nil
 }
@@ -229,13 +439,241 @@ func (s *ticketService) UpdateStatus(ctx context.Context, id string, status mode
 		return fmt.Errorf("更新工单状态失败: %w", err)
 	}
 
+	s.syncSLAClock(ctx, id, status)
+	event := models.WebhookEventTicketUpdated
+	if status == models.TicketStatusClosed {
+		event = models.WebhookEventTicketClosed
+	}
+	s.publishLatest(ctx, event, id)
+	if status == models.TicketStatusResolved {
+		s.promptKnowledgeFeedback(ctx, id)
+	}
+
 	s.logger.Info("工单状态更新成功", zap.String("id", id), zap.String("status", string(status)))
 	return nil
 }
 
+// syncSLAClock 按工单的新状态暂停或恢复SLA计时
+func (s *ticketService) syncSLAClock(ctx context.Context, id string, status models.TicketStatus) {
+	if s.slaClock != nil {
+		s.slaClock.SyncSLAClock(ctx, id, status)
+	}
+}
+
 // generateTicketNumber 生成工单编号
 func (s *ticketService) generateTicketNumber() string {
 	// 使用时间戳生成工单编号，格式：TK-YYYYMMDD-HHMMSS
 	now := time.Now()
 	return fmt.Sprintf("TK-%s-%s", now.Format("20060102"), now.Format("150405"))
-}
\ No newline at end of file
+}
+
+// AddWorklog 登记工作日志
+func (s *ticketService) AddWorklog(ctx context.Context, worklog *models.TicketWorklog) (*models.TicketWorkTime, error) {
+	if worklog == nil {
+		return nil, fmt.Errorf("工作日志信息不能为空")
+	}
+
+	if worklog.WorkType == "" {
+		worklog.WorkType = models.WorklogTypeInvestigation
+	}
+	if worklog.StartedAt.IsZero() {
+		worklog.StartedAt = time.Now().Add(-worklog.GetDuration())
+	}
+
+	if err := worklog.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: 工作日志验证失败: %v", models.ErrInvalidInput, err)
+	}
+
+	// 检查工单是否存在
+	exists, err := s.repoManager.Ticket().Exists(ctx, worklog.TicketID)
+	if err != nil {
+		s.logger.Error("检查工单是否存在失败", zap.Error(err), zap.String("id", worklog.TicketID))
+		return nil, fmt.Errorf("检查工单是否存在失败: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrTicketNotFound
+	}
+
+	if err := s.repoManager.Ticket().AddWorklog(ctx, worklog); err != nil {
+		s.logger.Error("登记工作日志失败", zap.Error(err), zap.String("ticket_id", worklog.TicketID))
+		return nil, fmt.Errorf("登记工作日志失败: %w", err)
+	}
+
+	s.logger.Info("工作日志登记成功",
+		zap.String("id", worklog.ID),
+		zap.String("ticket_id", worklog.TicketID),
+		zap.Int("duration_minutes", worklog.DurationMinutes))
+
+	return s.syncWorkTime(ctx, worklog.TicketID)
+}
+
+// GetWorklogs 获取工单工作日志及工时汇总
+func (s *ticketService) GetWorklogs(ctx context.Context, ticketID string) ([]*models.TicketWorklog, *models.TicketWorkTime, error) {
+	if ticketID == "" {
+		return nil, nil, fmt.Errorf("工单ID不能为空")
+	}
+
+	worklogs, err := s.repoManager.Ticket().GetWorklogs(ctx, ticketID)
+	if err != nil {
+		s.logger.Error("获取工作日志失败", zap.Error(err), zap.String("ticket_id", ticketID))
+		return nil, nil, fmt.Errorf("获取工作日志失败: %w", err)
+	}
+
+	workTime := &models.TicketWorkTime{TicketID: ticketID}
+	for _, worklog := range worklogs {
+		workTime.EntryCount++
+		workTime.ActualTime += worklog.GetDuration()
+		if worklog.Billable {
+			workTime.WorkTime += worklog.GetDuration()
+		}
+	}
+
+	return worklogs, workTime, nil
+}
+
+// UpdateWorklog 更新工单 worklog.TicketID 下的工作日志，仅登记人本人可修改；
+// 工作日志不属于该工单时返回 ErrWorklogNotFound
+func (s *ticketService) UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) (*models.TicketWorkTime, error) {
+	if worklog == nil {
+		return nil, fmt.Errorf("工作日志信息不能为空")
+	}
+	if worklog.ID == "" {
+		return nil, fmt.Errorf("工作日志ID不能为空")
+	}
+
+	existing, err := s.repoManager.Ticket().GetWorklog(ctx, worklog.ID)
+	if err != nil {
+		s.logger.Error("获取工作日志失败", zap.Error(err), zap.String("id", worklog.ID))
+		return nil, fmt.Errorf("获取工作日志失败: %w", err)
+	}
+	if existing.TicketID != worklog.TicketID {
+		return nil, models.ErrWorklogNotFound
+	}
+	if existing.UserID != worklog.UserID {
+		return nil, models.ErrPermissionDenied
+	}
+
+	// 登记时间以原记录为准
+	worklog.CreatedAt = existing.CreatedAt
+	if worklog.StartedAt.IsZero() {
+		worklog.StartedAt = existing.StartedAt
+	}
+	if worklog.WorkType == "" {
+		worklog.WorkType = existing.WorkType
+	}
+
+	if err := worklog.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: 工作日志验证失败: %v", models.ErrInvalidInput, err)
+	}
+
+	if err := s.repoManager.Ticket().UpdateWorklog(ctx, worklog); err != nil {
+		s.logger.Error("更新工作日志失败", zap.Error(err), zap.String("id", worklog.ID))
+		return nil, fmt.Errorf("更新工作日志失败: %w", err)
+	}
+
+	s.logger.Info("工作日志更新成功", zap.String("id", worklog.ID))
+	return s.syncWorkTime(ctx, worklog.TicketID)
+}
+
+// DeleteWorklog 删除工单 ticketID 下的工作日志，仅登记人本人可删除；
+// 工作日志不属于该工单时返回 ErrWorklogNotFound
+func (s *ticketService) DeleteWorklog(ctx context.Context, ticketID, id string, userID string) (*models.TicketWorkTime, error) {
+	if id == "" {
+		return nil, fmt.Errorf("工作日志ID不能为空")
+	}
+
+	existing, err := s.repoManager.Ticket().GetWorklog(ctx, id)
+	if err != nil {
+		s.logger.Error("获取工作日志失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("获取工作日志失败: %w", err)
+	}
+	if existing.TicketID != ticketID {
+		return nil, models.ErrWorklogNotFound
+	}
+	if existing.UserID != userID {
+		return nil, models.ErrPermissionDenied
+	}
+
+	if err := s.repoManager.Ticket().DeleteWorklog(ctx, id); err != nil {
+		s.logger.Error("删除工作日志失败", zap.Error(err), zap.String("id", id))
+		return nil, fmt.Errorf("删除工作日志失败: %w", err)
+	}
+
+	s.logger.Info("工作日志删除成功", zap.String("id", id))
+	return s.syncWorkTime(ctx, existing.TicketID)
+}
+
+// GetTimesheet 获取按用户或团队汇总的工时报表
+func (s *ticketService) GetTimesheet(ctx context.Context, filter *models.TimesheetFilter) (*models.Timesheet, error) {
+	if filter == nil {
+		return nil, fmt.Errorf("报表过滤条件不能为空")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("报表过滤条件无效: %w", err)
+	}
+
+	timesheet, err := s.repoManager.Ticket().GetTimesheet(ctx, filter)
+	if err != nil {
+		s.logger.Error("获取工时报表失败", zap.Error(err))
+		return nil, fmt.Errorf("获取工时报表失败: %w", err)
+	}
+
+	return timesheet, nil
+}
+
+// syncWorkTime 重新汇总工单工时
+func (s *ticketService) syncWorkTime(ctx context.Context, ticketID string) (*models.TicketWorkTime, error) {
+	workTime, err := s.repoManager.Ticket().SyncWorkTime(ctx, ticketID)
+	if err != nil {
+		s.logger.Error("汇总工单工时失败", zap.Error(err), zap.String("ticket_id", ticketID))
+		return nil, fmt.Errorf("汇总工单工时失败: %w", err)
+	}
+	return workTime, nil
+}
+
+// attributeService 按服务目录将工单归属到服务。关联告警的工单优先使用告警所属的服务；
+// 归属后写入 service 和 team 标签及服务标签，未指定处理团队时分派给服务的负责团队。匹配失败不影响工单创建
+func (s *ticketService) attributeService(ctx context.Context, ticket *models.Ticket) {
+	if s.services == nil {
+		return
+	}
+
+	labels := make(map[string]string, len(ticket.Labels)+1)
+	for key, value := range ticket.Labels {
+		labels[key] = value
+	}
+	if _, ok := labels[models.ServiceLabel]; !ok && ticket.AlertID != nil {
+		alert, err := s.repoManager.Alert().GetByID(ctx, *ticket.AlertID)
+		if err != nil {
+			s.logger.Warn("获取工单关联告警失败", zap.Error(err), zap.String("alert_id", *ticket.AlertID))
+		} else if name := alert.Labels[models.ServiceLabel]; name != "" {
+			labels[models.ServiceLabel] = name
+		}
+	}
+
+	service, err := s.services.Attribute(ctx, labels)
+	if err != nil {
+		s.logger.Warn("工单归属服务失败", zap.Error(err), zap.String("title", ticket.Title))
+		return
+	}
+	if service == nil {
+		return
+	}
+
+	ticket.Labels = attributeLabels(ticket.Labels, service)
+	tag := models.ServiceTicketTagPrefix + service.Name
+	hasTag := false
+	for _, existing := range ticket.Tags {
+		if existing == tag {
+			hasTag = true
+			break
+		}
+	}
+	if !hasTag {
+		ticket.Tags = append(ticket.Tags, tag)
+	}
+	if ticket.TeamName == nil && service.OwnerTeam != "" {
+		team := service.OwnerTeam
+		ticket.TeamName = &team
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// ticketSLAService 工单SLA暂停服务实现。工单进入等待客户或第三方回复的状态时自动暂停SLA计时，
+// 处理人也可以手动暂停；暂停期间不判定超时，恢复时截止时间顺延暂停的时长
+type ticketSLAService struct {
+	repoManager   repository.RepositoryManager
+	pauseStatuses map[models.TicketStatus]bool
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewTicketSLAService 创建工单SLA暂停服务实例
+func NewTicketSLAService(repoManager repository.RepositoryManager, cfg config.TicketSLAConfig, logger *zap.Logger) TicketSLAService {
+	pauseStatuses := make(map[models.TicketStatus]bool, len(cfg.PauseStatuses))
+	for _, status := range cfg.PauseStatuses {
+		if status = strings.TrimSpace(status); status != "" {
+			pauseStatuses[models.TicketStatus(status)] = true
+		}
+	}
+	return &ticketSLAService{
+		repoManager:   repoManager,
+		pauseStatuses: pauseStatuses,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Pause 手动暂停SLA计时，已解决或关闭的工单不能暂停
+func (s *ticketSLAService) Pause(ctx context.Context, ticketID, userID string, req *models.TicketSLAPauseRequest) (*models.TicketSLAPause, error) {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticketSLAStopped(ticket.Status) {
+		return nil, models.ErrTicketClosed
+	}
+
+	pause := &models.TicketSLAPause{
+		TicketID: ticketID,
+		Source:   models.TicketSLAPauseSourceManual,
+		PausedBy: &userID,
+		PausedAt: s.now(),
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		pause.Reason = &reason
+	}
+	if err := s.repoManager.TicketSLAPause().Pause(ctx, pause); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("工单SLA计时已暂停", zap.String("ticket_id", ticketID), zap.String("user_id", userID))
+	return pause, nil
+}
+
+// Resume 恢复SLA计时，手动暂停和按状态自动暂停都可以手动恢复
+func (s *ticketSLAService) Resume(ctx context.Context, ticketID, userID string) (*models.TicketSLAPause, error) {
+	if _, err := s.repoManager.Ticket().GetByID(ctx, ticketID); err != nil {
+		return nil, err
+	}
+
+	pause, err := s.repoManager.TicketSLAPause().Resume(ctx, ticketID, "", &userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("工单SLA计时已恢复", zap.String("ticket_id", ticketID), zap.String("user_id", userID),
+		zap.Duration("paused", pause.Duration(s.now())))
+	return pause, nil
+}
+
+// Clock 获取工单的SLA计时状态和暂停记录
+func (s *ticketSLAService) Clock(ctx context.Context, ticketID string) (*models.TicketSLAClock, error) {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := s.repoManager.TicketSLAPause().ListByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewTicketSLAClock(ticket, pauses, s.now()), nil
+}
+
+// SyncSLAClock 工单进入暂停状态时开始暂停，离开时结束按状态开始的暂停；
+// 手动暂停不随状态恢复，但工单解决、关闭或取消时结束全部暂停，计时随之停止
+func (s *ticketSLAService) SyncSLAClock(ctx context.Context, ticketID string, status models.TicketStatus) {
+	var err error
+	switch {
+	case s.pauseStatuses[status]:
+		err = s.repoManager.TicketSLAPause().Pause(ctx, &models.TicketSLAPause{
+			TicketID: ticketID,
+			Source:   models.TicketSLAPauseSourceStatus,
+			PausedAt: s.now(),
+		})
+		if errors.Is(err, models.ErrTicketSLAPaused) {
+			return
+		}
+	case ticketSLAStopped(status):
+		_, err = s.repoManager.TicketSLAPause().Resume(ctx, ticketID, "", nil, s.now())
+	default:
+		_, err = s.repoManager.TicketSLAPause().Resume(ctx, ticketID, models.TicketSLAPauseSourceStatus, nil, s.now())
+	}
+	if err != nil && !errors.Is(err, models.ErrTicketSLANotPaused) {
+		s.logger.Warn("同步工单SLA计时失败", zap.Error(err), zap.String("ticket_id", ticketID), zap.String("status", string(status)))
+	}
+}
+
+// ticketSLAStopped 工单处于解决、关闭或取消状态时SLA计时停止
+func ticketSLAStopped(status models.TicketStatus) bool {
+	switch status {
+	case models.TicketStatusResolved, models.TicketStatusClosed, models.TicketStatusCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeTicketSLAPauseRepository 内存中的工单SLA暂停记录，恢复时顺延工单的截止时间
+type fakeTicketSLAPauseRepository struct {
+	tickets *fakeFeedbackTicketRepository
+	pauses  []*models.TicketSLAPause
+}
+
+func (r *fakeTicketSLAPauseRepository) Pause(ctx context.Context, pause *models.TicketSLAPause) error {
+	for _, p := range r.pauses {
+		if p.TicketID == pause.TicketID && p.ResumedAt == nil {
+			return models.ErrTicketSLAPaused
+		}
+	}
+	pause.ID = fmt.Sprintf("p%d", len(r.pauses)+1)
+	r.pauses = append(r.pauses, pause)
+	return nil
+}
+
+func (r *fakeTicketSLAPauseRepository) Resume(ctx context.Context, ticketID string, source models.TicketSLAPauseSource, resumedBy *string, at time.Time) (*models.TicketSLAPause, error) {
+	for _, p := range r.pauses {
+		if p.TicketID != ticketID || p.ResumedAt != nil || (source != "" && p.Source != source) {
+			continue
+		}
+		p.ResumedAt, p.ResumedBy = &at, resumedBy
+		ticket, err := r.tickets.GetByID(ctx, ticketID)
+		if err != nil {
+			return nil, err
+		}
+		if ticket.SLADeadline != nil {
+			deadline := ticket.SLADeadline.Add(at.Sub(p.PausedAt))
+			ticket.SLADeadline = &deadline
+		}
+		return p, nil
+	}
+	return nil, models.ErrTicketSLANotPaused
+}
+
+func (r *fakeTicketSLAPauseRepository) ListByTicket(ctx context.Context, ticketID string) ([]*models.TicketSLAPause, error) {
+	var pauses []*models.TicketSLAPause
+	for _, p := range r.pauses {
+		if p.TicketID == ticketID {
+			pauses = append(pauses, p)
+		}
+	}
+	return pauses, nil
+}
+
+type ticketSLARepoManager struct {
+	*MockRepositoryManager
+	tickets *fakeFeedbackTicketRepository
+	pauses  *fakeTicketSLAPauseRepository
+}
+
+func (m *ticketSLARepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func (m *ticketSLARepoManager) TicketSLAPause() repository.TicketSLAPauseRepository { return m.pauses }
+
+func TestTicketSLAService_PauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	deadline := created.Add(8 * time.Hour)
+	ticket := &models.Ticket{ID: "t1", Status: models.TicketStatusInProgress, CreatedAt: created, SLADeadline: &deadline}
+	tickets := &fakeFeedbackTicketRepository{fakeSurveyTicketRepository{tickets: []*models.Ticket{ticket}}}
+	repoManager := &ticketSLARepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		tickets:               tickets,
+		pauses:                &fakeTicketSLAPauseRepository{tickets: tickets},
+	}
+
+	now := created.Add(time.Hour)
+	svc := NewTicketSLAService(repoManager, config.TicketSLAConfig{PauseStatuses: []string{"pending"}}, zap.NewNop()).(*ticketSLAService)
+	svc.now = func() time.Time { return now }
+	ticketService := NewTicketService(repoManager, nil, nil, nil, nil, nil, svc, zap.NewNop())
+
+	// 进入等待状态自动暂停，暂停期间截止时间按当前时刻顺延，不判定超时
+	require.NoError(t, ticketService.UpdateStatus(ctx, "t1", models.TicketStatusPending))
+	now = created.Add(10 * time.Hour)
+	clock, err := svc.Clock(ctx, "t1")
+	require.NoError(t, err)
+	assert.True(t, clock.Paused)
+	assert.Equal(t, models.TicketSLAStatusPaused, clock.Status)
+	assert.Equal(t, created.Add(17*time.Hour), *clock.Deadline)
+	assert.Equal(t, int64(time.Hour.Seconds()), clock.ElapsedSeconds)
+
+	// 已暂停时不能再手动暂停，离开等待状态时恢复并顺延已保存的截止时间
+	_, err = svc.Pause(ctx, "t1", "u1", &models.TicketSLAPauseRequest{})
+	assert.ErrorIs(t, err, models.ErrTicketSLAPaused)
+	require.NoError(t, ticketService.UpdateStatus(ctx, "t1", models.TicketStatusInProgress))
+	assert.Equal(t, created.Add(17*time.Hour), *ticket.SLADeadline)
+
+	// 手动暂停不随状态变化恢复
+	now = created.Add(11 * time.Hour)
+	pause, err := svc.Pause(ctx, "t1", "u1", &models.TicketSLAPauseRequest{Reason: " 等待供应商 "})
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketSLAPauseSourceManual, pause.Source)
+	assert.Equal(t, "等待供应商", *pause.Reason)
+	require.NoError(t, ticketService.UpdateStatus(ctx, "t1", models.TicketStatusAssigned))
+	now = created.Add(12 * time.Hour)
+	clock, err = svc.Clock(ctx, "t1")
+	require.NoError(t, err)
+	assert.True(t, clock.Paused)
+
+	// 手动恢复后计时继续，解决耗时不含暂停时长
+	resumed, err := svc.Resume(ctx, "t1", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", *resumed.ResumedBy)
+	_, err = svc.Resume(ctx, "t1", "u1")
+	assert.ErrorIs(t, err, models.ErrTicketSLANotPaused)
+	assert.Equal(t, created.Add(18*time.Hour), *ticket.SLADeadline)
+
+	now = created.Add(13 * time.Hour)
+	resolvedAt := now
+	ticket.ResolvedAt = &resolvedAt
+	require.NoError(t, ticketService.UpdateStatus(ctx, "t1", models.TicketStatusResolved))
+	clock, err = svc.Clock(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketSLAStatusOnTrack, clock.Status)
+	assert.Equal(t, int64((10 * time.Hour).Seconds()), clock.PausedSeconds)
+	require.NotNil(t, clock.ResolutionSeconds)
+	assert.Equal(t, int64((3 * time.Hour).Seconds()), *clock.ResolutionSeconds)
+	assert.Len(t, clock.Pauses, 2)
+
+	// 已解决的工单不能暂停
+	_, err = svc.Pause(ctx, "t1", "u1", &models.TicketSLAPauseRequest{})
+	assert.ErrorIs(t, err, models.ErrTicketClosed)
+}
+
+func TestTicketSLAService_ResolveStopsManualPause(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	deadline := created.Add(4 * time.Hour)
+	ticket := &models.Ticket{ID: "t1", Status: models.TicketStatusInProgress, CreatedAt: created, SLADeadline: &deadline}
+	tickets := &fakeFeedbackTicketRepository{fakeSurveyTicketRepository{tickets: []*models.Ticket{ticket}}}
+	repoManager := &ticketSLARepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		tickets:               tickets,
+		pauses:                &fakeTicketSLAPauseRepository{tickets: tickets},
+	}
+
+	now := created.Add(time.Hour)
+	svc := NewTicketSLAService(repoManager, config.TicketSLAConfig{}, zap.NewNop()).(*ticketSLAService)
+	svc.now = func() time.Time { return now }
+
+	// 未配置暂停状态时只能手动暂停
+	svc.SyncSLAClock(ctx, "t1", models.TicketStatusPending)
+	assert.Empty(t, repoManager.pauses.pauses)
+	_, err := svc.Pause(ctx, "t1", "u1", &models.TicketSLAPauseRequest{})
+	require.NoError(t, err)
+
+	// 关闭工单时结束手动暂停，截止时间顺延后不再变化
+	now = created.Add(6 * time.Hour)
+	svc.SyncSLAClock(ctx, "t1", models.TicketStatusClosed)
+	assert.NotNil(t, repoManager.pauses.pauses[0].ResumedAt)
+	assert.Equal(t, created.Add(9*time.Hour), *ticket.SLADeadline)
+}
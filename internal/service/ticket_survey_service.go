@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+const (
+	// ticketSurveyPath 评价链接在平台外部访问地址下的路径
+	ticketSurveyPath = "/api/v1/ticket-surveys/"
+	// ticketSurveyTokenBytes 评价令牌的随机字节数
+	ticketSurveyTokenBytes = 32
+	// ticketSurveyBatchSize 每次检查最多发送的调查数
+	ticketSurveyBatchSize = 100
+)
+
+// ticketSurveyService 工单满意度调查服务实现
+type ticketSurveyService struct {
+	repoManager   repository.RepositoryManager
+	notifications NotificationService
+	services      ServiceAttributor
+	cfg           config.TicketSurveyConfig
+	baseURL       string
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewTicketSurveyService 创建工单满意度调查服务实例，baseURL 为平台外部访问地址，为空时不发送调查
+func NewTicketSurveyService(repoManager repository.RepositoryManager, notifications NotificationService, services ServiceAttributor, cfg config.TicketSurveyConfig, baseURL string, logger *zap.Logger) TicketSurveyService {
+	return &ticketSurveyService{
+		repoManager:   repoManager,
+		notifications: notifications,
+		services:      services,
+		cfg:           cfg,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Interval 检查周期
+func (s *ticketSurveyService) Interval() time.Duration {
+	return s.cfg.CheckInterval
+}
+
+// RunDue 向回看时长内关闭且尚未发送调查的工单报告人发送评价链接，返回发送的调查数。
+// 先创建调查再发送，多实例时由工单唯一约束保证只发送一次；发送失败时删除调查，下次检查时重试
+func (s *ticketSurveyService) RunDue(ctx context.Context) (int, error) {
+	if !s.cfg.Enabled || s.baseURL == "" || s.notifications == nil {
+		return 0, nil
+	}
+
+	now := s.now()
+	tickets, err := s.repoManager.TicketSurvey().ListUnsurveyed(ctx, now.Add(-s.cfg.Lookback), ticketSurveyBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, ticket := range tickets {
+		ok, err := s.send(ctx, ticket, now)
+		if err != nil {
+			s.logger.Error("发送工单满意度调查失败", zap.Error(err), zap.String("ticket_id", ticket.ID))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// send 为工单创建调查并发送评价链接，其他实例已创建调查时返回 false
+func (s *ticketSurveyService) send(ctx context.Context, ticket *models.Ticket, now time.Time) (bool, error) {
+	reporter, err := s.repoManager.User().GetByID(ctx, ticket.ReporterID)
+	if err != nil {
+		return false, fmt.Errorf("获取工单报告人失败: %w", err)
+	}
+	team, err := ticketOwnerTeam(ctx, s.services, ticket)
+	if err != nil {
+		s.logger.Warn("获取工单所属服务失败", zap.Error(err), zap.String("ticket_id", ticket.ID))
+	}
+	token, err := generateTicketSurveyToken()
+	if err != nil {
+		return false, err
+	}
+
+	survey := &models.TicketSurvey{
+		ID:         uuid.New().String(),
+		TicketID:   ticket.ID,
+		ReporterID: ticket.ReporterID,
+		AssigneeID: ticket.AssigneeID,
+		Team:       team,
+		TokenHash:  hashTicketSurveyToken(token),
+		SentAt:     now,
+		ExpiresAt:  now.Add(s.cfg.TTL),
+	}
+	created, err := s.repoManager.TicketSurvey().Create(ctx, survey)
+	if err != nil || !created {
+		return false, err
+	}
+
+	notification := &models.Notification{
+		Type:       models.NotificationTypeEmail,
+		Recipient:  reporter.Email,
+		MessageKey: "notification.ticket_survey",
+		MessageData: map[string]interface{}{
+			"Number":    ticket.Number,
+			"Title":     ticket.Title,
+			"Link":      s.baseURL + ticketSurveyPath + token,
+			"ExpiresAt": survey.ExpiresAt.Format("2006-01-02 15:04"),
+		},
+	}
+	if err := s.notifications.Send(ctx, notification); err != nil {
+		if deleteErr := s.repoManager.TicketSurvey().Delete(ctx, survey.ID); deleteErr != nil {
+			s.logger.Error("删除未发送的工单满意度调查失败", zap.Error(deleteErr), zap.String("ticket_id", ticket.ID))
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// View 获取评价链接对应的调查
+func (s *ticketSurveyService) View(ctx context.Context, token string) (*models.TicketSurveyView, error) {
+	survey, err := s.repoManager.TicketSurvey().GetByTokenHash(ctx, hashTicketSurveyToken(token))
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, survey)
+}
+
+// Respond 提交评价，每个调查只能评价一次，链接过期后不能评价
+func (s *ticketSurveyService) Respond(ctx context.Context, token string, resp *models.TicketSurveyResponse) (*models.TicketSurveyView, error) {
+	if err := resp.Validate(); err != nil {
+		return nil, err
+	}
+
+	survey, err := s.repoManager.TicketSurvey().GetByTokenHash(ctx, hashTicketSurveyToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if survey.RespondedAt != nil {
+		return nil, models.ErrTicketSurveyResponded
+	}
+	if !s.now().Before(survey.ExpiresAt) {
+		return nil, models.ErrTicketSurveyExpired
+	}
+
+	if err := s.repoManager.TicketSurvey().Respond(ctx, survey.ID, resp.Score, resp.Comment); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	survey.Score = &resp.Score
+	survey.Comment = resp.Comment
+	survey.RespondedAt = &now
+
+	s.logger.Info("收到工单满意度评价", zap.String("ticket_id", survey.TicketID), zap.Int("score", resp.Score))
+	return s.view(ctx, survey)
+}
+
+// view 补充工单编号和标题
+func (s *ticketSurveyService) view(ctx context.Context, survey *models.TicketSurvey) (*models.TicketSurveyView, error) {
+	ticket, err := s.repoManager.Ticket().GetByID(ctx, survey.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TicketSurveyView{
+		TicketNumber: ticket.Number,
+		TicketTitle:  ticket.Title,
+		Score:        survey.Score,
+		Comment:      survey.Comment,
+		ExpiresAt:    survey.ExpiresAt,
+		RespondedAt:  survey.RespondedAt,
+	}, nil
+}
+
+// CSAT 按团队或处理人统计满意度，Total 为全部分组的合计
+func (s *ticketSurveyService) CSAT(ctx context.Context, filter *models.TicketSurveyFilter) (*models.TicketCSATReport, error) {
+	if err := filter.Validate(s.now()); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repoManager.TicketSurvey().CSAT(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total := &models.TicketCSAT{}
+	for _, group := range groups {
+		group.Finalize()
+		total.Add(group)
+	}
+	total.Finalize()
+	if groups == nil {
+		groups = []*models.TicketCSAT{}
+	}
+
+	return &models.TicketCSATReport{
+		From:    filter.From,
+		To:      filter.To,
+		GroupBy: filter.GroupBy,
+		Total:   total,
+		Groups:  groups,
+	}, nil
+}
+
+// generateTicketSurveyToken 生成评价令牌，令牌出现在评价链接中，需要足够的随机性
+func generateTicketSurveyToken() (string, error) {
+	buf := make([]byte, ticketSurveyTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成评价令牌失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashTicketSurveyToken 计算评价令牌的哈希，数据库只保存哈希
+func hashTicketSurveyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeTicketSurveyRepository 内存中的满意度调查，CSAT 返回固定的分组并记录收到的过滤条件
+type fakeTicketSurveyRepository struct {
+	repository.TicketSurveyRepository
+	unsurveyed []*models.Ticket
+	surveys    []*models.TicketSurvey
+	groups     []*models.TicketCSAT
+	filter     *models.TicketSurveyFilter
+}
+
+func (r *fakeTicketSurveyRepository) ListUnsurveyed(ctx context.Context, since time.Time, limit int) ([]*models.Ticket, error) {
+	return r.unsurveyed, nil
+}
+
+func (r *fakeTicketSurveyRepository) Create(ctx context.Context, survey *models.TicketSurvey) (bool, error) {
+	for _, existing := range r.surveys {
+		if existing.TicketID == survey.TicketID {
+			return false, nil
+		}
+	}
+	r.surveys = append(r.surveys, survey)
+	return true, nil
+}
+
+func (r *fakeTicketSurveyRepository) Delete(ctx context.Context, id string) error {
+	for i, survey := range r.surveys {
+		if survey.ID == id {
+			r.surveys = append(r.surveys[:i], r.surveys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakeTicketSurveyRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.TicketSurvey, error) {
+	for _, survey := range r.surveys {
+		if survey.TokenHash == tokenHash {
+			copied := *survey
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrTicketSurveyNotFound
+}
+
+func (r *fakeTicketSurveyRepository) Respond(ctx context.Context, id string, score int, comment string) error {
+	for _, survey := range r.surveys {
+		if survey.ID == id {
+			if survey.RespondedAt != nil {
+				return models.ErrTicketSurveyResponded
+			}
+			now := time.Now()
+			survey.Score, survey.Comment, survey.RespondedAt = &score, comment, &now
+			return nil
+		}
+	}
+	return models.ErrTicketSurveyNotFound
+}
+
+func (r *fakeTicketSurveyRepository) CSAT(ctx context.Context, filter *models.TicketSurveyFilter) ([]*models.TicketCSAT, error) {
+	r.filter = filter
+	return r.groups, nil
+}
+
+type fakeSurveyTicketRepository struct {
+	repository.TicketRepository
+	tickets []*models.Ticket
+}
+
+func (r *fakeSurveyTicketRepository) GetByID(ctx context.Context, id string) (*models.Ticket, error) {
+	for _, ticket := range r.tickets {
+		if ticket.ID == id {
+			return ticket, nil
+		}
+	}
+	return nil, models.ErrTicketNotFound
+}
+
+// fakeSurveyNotificationService 记录发送的通知，fail 不为空时发送失败
+type fakeSurveyNotificationService struct {
+	NotificationService
+	sent []*models.Notification
+	fail error
+}
+
+func (s *fakeSurveyNotificationService) Send(ctx context.Context, notification *models.Notification) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	s.sent = append(s.sent, notification)
+	return nil
+}
+
+type ticketSurveyRepoManager struct {
+	*MockRepositoryManager
+	surveys *fakeTicketSurveyRepository
+	tickets *fakeSurveyTicketRepository
+	users   *fakeChatOpsUserRepository
+}
+
+func (m *ticketSurveyRepoManager) TicketSurvey() repository.TicketSurveyRepository { return m.surveys }
+
+func (m *ticketSurveyRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func (m *ticketSurveyRepoManager) User() repository.UserRepository { return m.users }
+
+func TestTicketSurveyService_RunDueAndRespond(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	assignee := "u2"
+	ticket := &models.Ticket{
+		ID: "t1", Number: "T-1", Title: "支付超时", ReporterID: "u1", AssigneeID: &assignee,
+		Tags: []string{"urgent", models.ServiceTicketTagPrefix + "checkout"},
+	}
+	repoManager := &ticketSurveyRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		surveys:               &fakeTicketSurveyRepository{unsurveyed: []*models.Ticket{ticket}},
+		tickets:               &fakeSurveyTicketRepository{tickets: []*models.Ticket{ticket}},
+		users:                 &fakeChatOpsUserRepository{users: []*models.User{{ID: "u1", Email: "alice@example.com"}}},
+	}
+	notifications := &fakeSurveyNotificationService{}
+	services := &fakeServiceAttributor{service: &models.CatalogService{Name: "checkout", OwnerTeam: "sre"}}
+	cfg := config.TicketSurveyConfig{Enabled: true, TTL: 24 * time.Hour, Lookback: time.Hour}
+	svc := NewTicketSurveyService(repoManager, notifications, services, cfg, "https://pulse.example.com/", zap.NewNop()).(*ticketSurveyService)
+	svc.now = func() time.Time { return now }
+
+	// 发送失败时删除调查，下次检查时重试
+	notifications.fail = errors.New("smtp unavailable")
+	sent, err := svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, repoManager.surveys.surveys)
+
+	notifications.fail = nil
+	sent, err = svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	require.Len(t, repoManager.surveys.surveys, 1)
+	survey := repoManager.surveys.surveys[0]
+	assert.Equal(t, "sre", survey.Team)
+	assert.Equal(t, "u2", *survey.AssigneeID)
+	assert.True(t, survey.ExpiresAt.Equal(now.Add(24*time.Hour)))
+
+	require.Len(t, notifications.sent, 1)
+	notification := notifications.sent[0]
+	assert.Equal(t, models.NotificationTypeEmail, notification.Type)
+	assert.Equal(t, "alice@example.com", notification.Recipient)
+	assert.Equal(t, "notification.ticket_survey", notification.MessageKey)
+	link := notification.MessageData["Link"].(string)
+	require.Contains(t, link, "https://pulse.example.com"+ticketSurveyPath)
+	token := link[len("https://pulse.example.com"+ticketSurveyPath):]
+	assert.Equal(t, hashTicketSurveyToken(token), survey.TokenHash)
+
+	// 已有调查的工单不重复发送
+	sent, err = svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	view, err := svc.View(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "T-1", view.TicketNumber)
+	assert.Nil(t, view.RespondedAt)
+
+	_, err = svc.Respond(ctx, token, &models.TicketSurveyResponse{Score: 6})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	view, err = svc.Respond(ctx, token, &models.TicketSurveyResponse{Score: 5, Comment: "  很快  "})
+	require.NoError(t, err)
+	assert.Equal(t, 5, *view.Score)
+	assert.Equal(t, "很快", view.Comment)
+
+	_, err = svc.Respond(ctx, token, &models.TicketSurveyResponse{Score: 4})
+	assert.ErrorIs(t, err, models.ErrTicketSurveyResponded)
+
+	_, err = svc.View(ctx, "unknown")
+	assert.ErrorIs(t, err, models.ErrTicketSurveyNotFound)
+}
+
+func TestTicketSurveyService_RespondExpired(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	repoManager := &ticketSurveyRepoManager{
+		MockRepositoryManager: &MockRepositoryManager{},
+		surveys: &fakeTicketSurveyRepository{surveys: []*models.TicketSurvey{
+			{ID: "s1", TicketID: "t1", TokenHash: hashTicketSurveyToken("token"), ExpiresAt: now},
+		}},
+	}
+	svc := NewTicketSurveyService(repoManager, nil, nil, config.TicketSurveyConfig{}, "", zap.NewNop()).(*ticketSurveyService)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.Respond(context.Background(), "token", &models.TicketSurveyResponse{Score: 3})
+	assert.ErrorIs(t, err, models.ErrTicketSurveyExpired)
+
+	// 未开启或未配置外部访问地址时不发送
+	sent, err := svc.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+func TestTicketSurveyService_CSAT(t *testing.T) {
+	now := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	repo := &fakeTicketSurveyRepository{groups: []*models.TicketCSAT{
+		{Key: "u1", Sent: 4, Responses: 3, Satisfied: 2, ScoreSum: 12},
+		{Key: "u2", Sent: 2, Responses: 1, Satisfied: 1, ScoreSum: 5},
+	}}
+	repoManager := &ticketSurveyRepoManager{MockRepositoryManager: &MockRepositoryManager{}, surveys: repo}
+	svc := NewTicketSurveyService(repoManager, nil, nil, config.TicketSurveyConfig{}, "", zap.NewNop()).(*ticketSurveyService)
+	svc.now = func() time.Time { return now }
+
+	report, err := svc.CSAT(context.Background(), &models.TicketSurveyFilter{GroupBy: models.TicketSurveyGroupByAgent})
+	require.NoError(t, err)
+	assert.True(t, repo.filter.From.Equal(now.AddDate(0, 0, -30)))
+	assert.True(t, report.To.Equal(now))
+
+	assert.InDelta(t, 4.0, report.Groups[0].Average, 0.001)
+	assert.InDelta(t, 66.667, report.Groups[0].CSAT, 0.001)
+	assert.InDelta(t, 75.0, report.Groups[0].ResponseRate, 0.001)
+
+	assert.Equal(t, int64(6), report.Total.Sent)
+	assert.InDelta(t, 75.0, report.Total.CSAT, 0.001)
+	assert.InDelta(t, 4.25, report.Total.Average, 0.001)
+
+	_, err = svc.CSAT(context.Background(), &models.TicketSurveyFilter{GroupBy: "region"})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
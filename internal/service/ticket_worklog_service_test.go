@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/repository"
+)
+
+// fakeWorklogTicketRepository 内存中的工作日志，汇总工时时同步工单实际工时
+type fakeWorklogTicketRepository struct {
+	repository.TicketRepository
+	worklogs    map[string]*models.TicketWorklog
+	actualHours map[string]float64
+}
+
+func (r *fakeWorklogTicketRepository) GetWorklog(ctx context.Context, id string) (*models.TicketWorklog, error) {
+	worklog := r.worklogs[id]
+	if worklog == nil {
+		return nil, models.ErrWorklogNotFound
+	}
+	copied := *worklog
+	return &copied, nil
+}
+
+func (r *fakeWorklogTicketRepository) UpdateWorklog(ctx context.Context, worklog *models.TicketWorklog) error {
+	copied := *worklog
+	r.worklogs[worklog.ID] = &copied
+	return nil
+}
+
+func (r *fakeWorklogTicketRepository) DeleteWorklog(ctx context.Context, id string) error {
+	delete(r.worklogs, id)
+	return nil
+}
+
+func (r *fakeWorklogTicketRepository) SyncWorkTime(ctx context.Context, ticketID string) (*models.TicketWorkTime, error) {
+	workTime := &models.TicketWorkTime{TicketID: ticketID}
+	for _, worklog := range r.worklogs {
+		if worklog.TicketID != ticketID {
+			continue
+		}
+		workTime.EntryCount++
+		workTime.ActualTime += worklog.GetDuration()
+		if worklog.Billable {
+			workTime.WorkTime += worklog.GetDuration()
+		}
+	}
+	r.actualHours[ticketID] = workTime.ActualTime.Hours()
+	return workTime, nil
+}
+
+type worklogRepoManager struct {
+	*MockRepositoryManager
+	tickets *fakeWorklogTicketRepository
+}
+
+func (m *worklogRepoManager) Ticket() repository.TicketRepository { return m.tickets }
+
+func newWorklogTestService() (TicketService, *fakeWorklogTicketRepository) {
+	startedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tickets := &fakeWorklogTicketRepository{
+		worklogs: map[string]*models.TicketWorklog{
+			"w1": {ID: "w1", TicketID: "t1", UserID: "u1", Description: "排查数据库连接", WorkType: models.WorklogTypeInvestigation, StartedAt: startedAt, DurationMinutes: 60, Billable: true},
+			"w2": {ID: "w2", TicketID: "t1", UserID: "u2", Description: "修复配置", WorkType: models.WorklogTypeDevelopment, StartedAt: startedAt, DurationMinutes: 30, Billable: true},
+		},
+		actualHours: map[string]float64{"t1": 1.5},
+	}
+	repoManager := &worklogRepoManager{MockRepositoryManager: &MockRepositoryManager{}, tickets: tickets}
+	return NewTicketService(repoManager, nil, nil, nil, nil, nil, nil, zap.NewNop()), tickets
+}
+
+func TestTicketService_WorklogOwnerOnly(t *testing.T) {
+	svc, tickets := newWorklogTestService()
+	ctx := context.Background()
+
+	// 只有登记人可以修改和删除自己的工作日志
+	_, err := svc.UpdateWorklog(ctx, &models.TicketWorklog{ID: "w1", TicketID: "t1", UserID: "u2", Description: "改写他人记录", DurationMinutes: 10})
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+	assert.Equal(t, 60, tickets.worklogs["w1"].DurationMinutes)
+	_, err = svc.DeleteWorklog(ctx, "t1", "w1", "u2")
+	assert.ErrorIs(t, err, models.ErrPermissionDenied)
+	assert.Contains(t, tickets.worklogs, "w1")
+
+	workTime, err := svc.UpdateWorklog(ctx, &models.TicketWorklog{ID: "w1", TicketID: "t1", UserID: "u1", Description: "排查数据库连接池", DurationMinutes: 90, Billable: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, workTime.ActualTime)
+	assert.Equal(t, models.WorklogTypeInvestigation, tickets.worklogs["w1"].WorkType)
+	assert.Equal(t, 2.0, tickets.actualHours["t1"])
+}
+
+func TestTicketService_WorklogTicketMismatch(t *testing.T) {
+	svc, tickets := newWorklogTestService()
+	ctx := context.Background()
+
+	// 通过其他工单的路径访问时按工作日志不存在处理，不检查登记人
+	_, err := svc.UpdateWorklog(ctx, &models.TicketWorklog{ID: "w1", TicketID: "t2", UserID: "u2", Description: "跨工单修改", DurationMinutes: 10})
+	assert.ErrorIs(t, err, models.ErrWorklogNotFound)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	assert.Equal(t, "t1", tickets.worklogs["w1"].TicketID)
+	_, err = svc.DeleteWorklog(ctx, "t2", "w1", "u1")
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	assert.Contains(t, tickets.worklogs, "w1")
+	assert.Equal(t, 1.5, tickets.actualHours["t1"])
+}
+
+func TestTicketService_DeleteWorklogSyncsActualHours(t *testing.T) {
+	svc, tickets := newWorklogTestService()
+	ctx := context.Background()
+
+	// 删除后重新汇总工时并同步工单实际工时
+	workTime, err := svc.DeleteWorklog(ctx, "t1", "w1", "u1")
+	require.NoError(t, err)
+	assert.NotContains(t, tickets.worklogs, "w1")
+	assert.Equal(t, int64(1), workTime.EntryCount)
+	assert.Equal(t, 30*time.Minute, workTime.ActualTime)
+	assert.Equal(t, 0.5, tickets.actualHours["t1"])
+}
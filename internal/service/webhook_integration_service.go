@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/ipfilter"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+// webhookIntegrationSecretBytes 集成密钥的随机字节数
+const webhookIntegrationSecretBytes = 32
+
+// webhookIntegrationService 告警接入集成服务实现
+type webhookIntegrationService struct {
+	repoManager  repository.RepositoryManager
+	alertService AlertService
+	schemaMode   models.IngestSchemaMode
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewWebhookIntegrationService 创建告警接入集成服务实例，schemaMode 为 strict 时自定义 Webhook 载荷中
+// 任一告警转换失败即拒绝整个载荷
+func NewWebhookIntegrationService(repoManager repository.RepositoryManager, alertService AlertService, schemaMode models.IngestSchemaMode, logger *zap.Logger) WebhookIntegrationService {
+	return &webhookIntegrationService{
+		repoManager:  repoManager,
+		alertService: alertService,
+		schemaMode:   schemaMode,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// List 获取告警接入集成列表
+func (s *webhookIntegrationService) List(ctx context.Context) ([]*models.WebhookIntegration, error) {
+	integrations, err := s.repoManager.WebhookIntegration().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取告警接入集成列表失败: %w", err)
+	}
+	return integrations, nil
+}
+
+// Get 获取告警接入集成，不返回密钥
+func (s *webhookIntegrationService) Get(ctx context.Context, id string) (*models.WebhookIntegration, error) {
+	integration, err := s.repoManager.WebhookIntegration().GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrWebhookIntegrationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取告警接入集成失败: %w", err)
+	}
+
+	integration.Secret = ""
+	return integration, nil
+}
+
+// Create 创建告警接入集成并生成密钥，明文密钥只在返回值中出现一次
+func (s *webhookIntegrationService) Create(ctx context.Context, integration *models.WebhookIntegration) (*models.WebhookIntegrationSecret, error) {
+	if err := s.validate(integration); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookIntegrationSecret()
+	if err != nil {
+		return nil, err
+	}
+	integration.Secret = secret
+
+	if err := s.repoManager.WebhookIntegration().Create(ctx, integration); err != nil {
+		s.logger.Error("创建告警接入集成失败", zap.Error(err), zap.String("name", integration.Name))
+		return nil, fmt.Errorf("创建告警接入集成失败: %w", err)
+	}
+
+	s.logger.Info("告警接入集成已创建",
+		zap.String("integration_id", integration.ID),
+		zap.String("source", string(integration.Source)))
+
+	integration.Secret = ""
+	return &models.WebhookIntegrationSecret{Integration: integration, Secret: secret}, nil
+}
+
+// Update 更新告警接入集成
+func (s *webhookIntegrationService) Update(ctx context.Context, integration *models.WebhookIntegration) error {
+	if err := s.validate(integration); err != nil {
+		return err
+	}
+
+	if err := s.repoManager.WebhookIntegration().Update(ctx, integration); err != nil {
+		if errors.Is(err, models.ErrWebhookIntegrationNotFound) {
+			return err
+		}
+		s.logger.Error("更新告警接入集成失败", zap.Error(err), zap.String("integration_id", integration.ID))
+		return fmt.Errorf("更新告警接入集成失败: %w", err)
+	}
+
+	integration.Secret = ""
+	return nil
+}
+
+// Delete 删除告警接入集成
+func (s *webhookIntegrationService) Delete(ctx context.Context, id string) error {
+	if err := s.repoManager.WebhookIntegration().Delete(ctx, id); err != nil {
+		if errors.Is(err, models.ErrWebhookIntegrationNotFound) {
+			return err
+		}
+		return fmt.Errorf("删除告警接入集成失败: %w", err)
+	}
+
+	s.logger.Info("告警接入集成已删除", zap.String("integration_id", id))
+	return nil
+}
+
+// RotateSecret 重新生成集成密钥，旧密钥立即失效
+func (s *webhookIntegrationService) RotateSecret(ctx context.Context, id string) (*models.WebhookIntegrationSecret, error) {
+	secret, err := generateWebhookIntegrationSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.WebhookIntegration().UpdateSecret(ctx, id, secret); err != nil {
+		if errors.Is(err, models.ErrWebhookIntegrationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("重置集成密钥失败: %w", err)
+	}
+
+	integration, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("告警接入集成密钥已重置", zap.String("integration_id", id))
+	return &models.WebhookIntegrationSecret{Integration: integration, Secret: secret}, nil
+}
+
+// Verify 校验接入请求的来源IP和签名，通过后返回集成配置（不含密钥）
+func (s *webhookIntegrationService) Verify(ctx context.Context, id, clientIP, signatureHeader, timestampHeader string, body []byte) (*models.WebhookIntegration, error) {
+	return s.VerifyAt(ctx, id, clientIP, signatureHeader, timestampHeader, body, s.now())
+}
+
+// VerifyAt 按接收时间校验签名，用于维护期间暂存后补处理的请求
+func (s *webhookIntegrationService) VerifyAt(ctx context.Context, id, clientIP, signatureHeader, timestampHeader string, body []byte, receivedAt time.Time) (*models.WebhookIntegration, error) {
+	return s.verify(ctx, id, clientIP, receivedAt, func(secret string) error {
+		return signature.Verify(secret, signatureHeader, timestampHeader, body, receivedAt, signature.DefaultTolerance)
+	})
+}
+
+// VerifyToken 校验接入请求的来源IP和令牌，用于无法计算签名的外部系统，令牌即集成密钥
+func (s *webhookIntegrationService) VerifyToken(ctx context.Context, id, clientIP, token string) (*models.WebhookIntegration, error) {
+	return s.verify(ctx, id, clientIP, s.now(), func(secret string) error {
+		if token == "" {
+			return errors.New("缺少令牌")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return errors.New("令牌不匹配")
+		}
+		return nil
+	})
+}
+
+// verify 检查集成已启用、来源IP在白名单中并执行凭据校验，通过后记录接收时间
+func (s *webhookIntegrationService) verify(ctx context.Context, id, clientIP string, now time.Time, check func(secret string) error) (*models.WebhookIntegration, error) {
+	integration, err := s.repoManager.WebhookIntegration().GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrWebhookIntegrationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("获取告警接入集成失败: %w", err)
+	}
+
+	if !integration.Enabled {
+		return nil, models.ErrWebhookIntegrationDisabled
+	}
+
+	filter, err := ipfilter.New(integration.AllowedCIDRs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("解析集成IP白名单失败: %w", err)
+	}
+	if !filter.Allowed(clientIP) {
+		s.logger.Warn("告警接入请求来源IP不在白名单中",
+			zap.String("integration_id", id), zap.String("client_ip", clientIP))
+		return nil, models.ErrSourceIPNotAllowed
+	}
+
+	if err := check(integration.Secret); err != nil {
+		s.logger.Warn("告警接入请求签名校验失败", zap.Error(err),
+			zap.String("integration_id", id), zap.String("client_ip", clientIP))
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidSignature, err)
+	}
+
+	if err := s.repoManager.WebhookIntegration().TouchLastReceived(ctx, id, now); err != nil {
+		s.logger.Warn("更新集成接收时间失败", zap.Error(err), zap.String("integration_id", id))
+	}
+
+	integration.Secret = ""
+	integration.LastReceivedAt = &now
+	return integration, nil
+}
+
+// IngestCustom 按集成的载荷映射将任意结构的载荷转换为告警。同一指纹的未解决告警更新内容，
+// 已解决的告警重新打开，映射为 resolved 的条目解决对应告警；单条告警失败不影响其他告警。
+// 载荷格式错误和严格模式下的转换失败返回 *models.IngestSchemaError
+func (s *webhookIntegrationService) IngestCustom(ctx context.Context, integration *models.WebhookIntegration, body []byte) (*models.CustomWebhookResult, error) {
+	if integration.PayloadMapping == nil {
+		return nil, fmt.Errorf("%w: 集成 %s 未配置载荷映射", models.ErrInvalidInput, integration.Name)
+	}
+	mapping, err := compilePayloadMapping(integration.PayloadMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	alerts, errs, err := mapping.transform(integration, body, now)
+	if err != nil {
+		return nil, err
+	}
+	if s.schemaMode == models.IngestSchemaStrict && len(errs) > 0 {
+		return nil, models.NewIngestSchemaError(models.IngestRejectMappingError, "%s", strings.Join(errs, "; "))
+	}
+
+	result := &models.CustomWebhookResult{Received: len(alerts) + len(errs), Errors: errs}
+	for _, mapped := range alerts {
+		if err := s.applyMappedAlert(ctx, mapped, now, result); err != nil {
+			s.logger.Error("处理自定义Webhook告警失败", zap.Error(err),
+				zap.String("integration_id", integration.ID), zap.String("fingerprint", mapped.alert.Fingerprint))
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", mapped.alert.Name, err))
+		}
+	}
+
+	s.logger.Info("自定义Webhook载荷已处理", zap.String("integration_id", integration.ID),
+		zap.Int("received", result.Received), zap.Int("created", result.Created), zap.Int("updated", result.Updated),
+		zap.Int("resolved", result.Resolved), zap.Int("errors", len(result.Errors)))
+	return result, nil
+}
+
+// applyMappedAlert 按指纹创建、更新或解决告警
+func (s *webhookIntegrationService) applyMappedAlert(ctx context.Context, mapped *mappedAlert, now time.Time, result *models.CustomWebhookResult) error {
+	alert := mapped.alert
+	existing, err := s.repoManager.Alert().GetByFingerprint(ctx, alert.Fingerprint)
+	if err != nil && !errors.Is(err, models.ErrAlertNotFound) {
+		return fmt.Errorf("查询告警失败: %w", err)
+	}
+	active := existing != nil && existing.Status != models.AlertStatusResolved
+
+	if mapped.resolved {
+		if !active {
+			result.Skipped++
+			return nil
+		}
+		existing.Status = models.AlertStatusResolved
+		existing.EndsAt = &now
+		existing.ResolvedAt = &now
+		existing.LastEvalAt = now
+		if err := s.alertService.Update(ctx, existing); err != nil {
+			return err
+		}
+		result.Resolved++
+		return nil
+	}
+
+	if existing == nil {
+		if err := s.alertService.Create(ctx, alert); err != nil {
+			return err
+		}
+		result.Created++
+		return nil
+	}
+
+	if !active {
+		existing.Status = models.AlertStatusFiring
+		existing.StartsAt = alert.StartsAt
+		existing.EndsAt = nil
+		existing.ResolvedAt = nil
+		existing.ResolvedBy = nil
+		existing.AckedAt = nil
+		existing.AckedBy = nil
+	}
+	existing.Name = alert.Name
+	existing.Description = alert.Description
+	existing.Severity = alert.Severity
+	existing.Labels = alert.Labels
+	existing.Annotations = alert.Annotations
+	existing.Value = alert.Value
+	existing.Expression = alert.Expression
+	existing.GeneratorURL = alert.GeneratorURL
+	existing.LastEvalAt = now
+	existing.EvalCount++
+	if err := s.alertService.Update(ctx, existing); err != nil {
+		return err
+	}
+	result.Updated++
+	return nil
+}
+
+// PreviewMapping 使用指定的载荷映射转换示例载荷，不创建告警，用于配置映射时检查结果
+func (s *webhookIntegrationService) PreviewMapping(ctx context.Context, id string, req *models.WebhookMappingPreviewRequest) (*models.WebhookMappingPreview, error) {
+	integration, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := compilePayloadMapping(&req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	alerts, errs, err := mapping.transform(integration, req.Payload, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.WebhookMappingPreview{Alerts: make([]*models.Alert, 0, len(alerts)), Errors: errs}
+	for _, mapped := range alerts {
+		if mapped.resolved {
+			mapped.alert.Status = models.AlertStatusResolved
+		}
+		preview.Alerts = append(preview.Alerts, mapped.alert)
+	}
+	return preview, nil
+}
+
+// validate 校验集成配置，IP白名单必须是合法的 CIDR 或IP，载荷映射的路径必须能够编译
+func (s *webhookIntegrationService) validate(integration *models.WebhookIntegration) error {
+	if err := integration.Validate(); err != nil {
+		return err
+	}
+	if _, err := ipfilter.ParseCIDRs(integration.AllowedCIDRs); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if integration.PayloadMapping != nil {
+		if _, err := compilePayloadMapping(integration.PayloadMapping); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateWebhookIntegrationSecret 生成随机集成密钥
+func generateWebhookIntegrationSecret() (string, error) {
+	buf := make([]byte, webhookIntegrationSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成集成密钥失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+// fakeWebhookIntegrationRepository 内存告警接入集成仓储
+type fakeWebhookIntegrationRepository struct {
+	repository.WebhookIntegrationRepository
+	integrations map[string]*models.WebhookIntegration
+	touched      []string
+}
+
+func (r *fakeWebhookIntegrationRepository) GetByID(ctx context.Context, id string) (*models.WebhookIntegration, error) {
+	integration, ok := r.integrations[id]
+	if !ok {
+		return nil, models.ErrWebhookIntegrationNotFound
+	}
+	copied := *integration
+	return &copied, nil
+}
+
+func (r *fakeWebhookIntegrationRepository) TouchLastReceived(ctx context.Context, id string, at time.Time) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+type webhookIntegrationRepoManager struct {
+	*MockRepositoryManager
+	repo   *fakeWebhookIntegrationRepository
+	alerts *fakeKubernetesAlertRepository
+}
+
+func (m *webhookIntegrationRepoManager) WebhookIntegration() repository.WebhookIntegrationRepository {
+	return m.repo
+}
+
+func (m *webhookIntegrationRepoManager) Alert() repository.AlertRepository { return m.alerts }
+
+func TestWebhookIntegrationService_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"name":"cpu high"}`)
+	timestamp := "1700000000"
+
+	repo := &fakeWebhookIntegrationRepository{integrations: map[string]*models.WebhookIntegration{
+		"prom": {
+			ID: "prom", Name: "prometheus", Source: models.AlertSourcePrometheus,
+			Secret: "secret", AllowedCIDRs: []string{"10.0.0.0/8"}, Enabled: true,
+		},
+		"disabled": {ID: "disabled", Secret: "secret", AllowedCIDRs: []string{}},
+	}}
+	svc := &webhookIntegrationService{
+		repoManager: &webhookIntegrationRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: repo},
+		logger:      zap.NewNop(),
+		now:         func() time.Time { return now },
+	}
+	ctx := context.Background()
+	valid := signature.Sign("secret", now.Unix(), body)
+
+	integration, err := svc.Verify(ctx, "prom", "10.1.2.3", valid, timestamp, body)
+	require.NoError(t, err)
+	assert.Empty(t, integration.Secret, "校验结果不应携带密钥")
+	assert.Equal(t, []string{"prom"}, repo.touched)
+
+	_, err = svc.Verify(ctx, "prom", "192.168.0.1", valid, timestamp, body)
+	assert.ErrorIs(t, err, models.ErrSourceIPNotAllowed)
+
+	_, err = svc.Verify(ctx, "prom", "10.1.2.3", signature.Sign("wrong", now.Unix(), body), timestamp, body)
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+
+	_, err = svc.Verify(ctx, "prom", "10.1.2.3", "", "", body)
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+
+	_, err = svc.Verify(ctx, "disabled", "10.1.2.3", valid, timestamp, body)
+	assert.ErrorIs(t, err, models.ErrWebhookIntegrationDisabled)
+
+	_, err = svc.Verify(ctx, "missing", "10.1.2.3", valid, timestamp, body)
+	assert.ErrorIs(t, err, models.ErrWebhookIntegrationNotFound)
+
+	// 维护期间暂存的请求按接收时间校验，补处理时签名已超出有效期也能通过
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = svc.Verify(ctx, "prom", "10.1.2.3", valid, timestamp, body)
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	_, err = svc.VerifyAt(ctx, "prom", "10.1.2.3", valid, timestamp, body, now)
+	require.NoError(t, err)
+}
+
+func TestWebhookIntegrationService_Create_InvalidCIDR(t *testing.T) {
+	svc := NewWebhookIntegrationService(&MockRepositoryManager{}, nil, models.IngestSchemaLenient, zap.NewNop())
+
+	_, err := svc.Create(context.Background(), &models.WebhookIntegration{
+		Name:         "grafana",
+		Source:       models.AlertSourceGrafana,
+		AllowedCIDRs: []string{"10.0.0.0/40"},
+	})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestWebhookIntegrationService_VerifyToken(t *testing.T) {
+	repo := &fakeWebhookIntegrationRepository{integrations: map[string]*models.WebhookIntegration{
+		"custom": {ID: "custom", Secret: "secret", AllowedCIDRs: []string{}, Enabled: true},
+	}}
+	svc := NewWebhookIntegrationService(&webhookIntegrationRepoManager{MockRepositoryManager: &MockRepositoryManager{}, repo: repo}, nil, models.IngestSchemaLenient, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.VerifyToken(ctx, "custom", "10.1.2.3", "secret")
+	require.NoError(t, err)
+
+	_, err = svc.VerifyToken(ctx, "custom", "10.1.2.3", "guess")
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	_, err = svc.VerifyToken(ctx, "custom", "10.1.2.3", "")
+	assert.ErrorIs(t, err, models.ErrInvalidSignature)
+}
+
+func TestWebhookIntegrationService_IngestCustom(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	alerts := &fakeKubernetesAlertRepository{alerts: map[string]*models.Alert{}}
+	alertService := &fakeKubernetesAlertService{repo: alerts}
+	svc := &webhookIntegrationService{
+		repoManager:  &webhookIntegrationRepoManager{MockRepositoryManager: &MockRepositoryManager{}, alerts: alerts},
+		alertService: alertService,
+		logger:       zap.NewNop(),
+		now:          func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	integration := &models.WebhookIntegration{
+		ID:     "uptime",
+		Name:   "uptime-kuma",
+		Source: models.AlertSourceCustom,
+		PayloadMapping: &models.WebhookPayloadMapping{
+			AlertsPath:  "$.events[*]",
+			Name:        models.WebhookFieldMapping{Template: "${@.monitor.name} 不可用"},
+			Description: models.WebhookFieldMapping{Path: "@.msg"},
+			Severity: models.WebhookFieldMapping{Path: "@.priority",
+				Values: map[string]string{"P1": "critical", "P2": "high"}, Default: "medium"},
+			Status:      models.WebhookFieldMapping{Path: "@.state", Values: map[string]string{"up": "resolved"}},
+			Fingerprint: models.WebhookFieldMapping{Path: "@.monitor.id"},
+			Value:       models.WebhookFieldMapping{Path: "@.latency"},
+			LabelsPath:  "@.tags",
+			Labels:      map[string]models.WebhookFieldMapping{"env": {Path: "$.env"}},
+		},
+	}
+
+	body := []byte(`{"env": "prod", "events": [
+		{"monitor": {"id": 1, "name": "api"}, "msg": "timeout", "priority": "P1", "state": "down", "latency": 5000, "tags": {"team": "web"}},
+		{"monitor": {"id": 2, "name": "db"}, "state": "down"},
+		{"monitor": {"id": 3, "name": "cache"}, "priority": "P9", "state": "down"}
+	]}`)
+	result, err := svc.IngestCustom(ctx, integration, body)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Received)
+	assert.Equal(t, 2, result.Created)
+	require.Len(t, result.Errors, 1, "无法映射的级别只跳过该条告警")
+	assert.Contains(t, result.Errors[0], "P9")
+
+	var api *models.Alert
+	for _, alert := range alerts.alerts {
+		if alert.Name == "api 不可用" {
+			api = alert
+		}
+	}
+	require.NotNil(t, api)
+	assert.Equal(t, models.AlertSeverityCritical, api.Severity)
+	assert.Equal(t, "timeout", api.Description)
+	assert.Equal(t, map[string]string{"team": "web", "env": "prod"}, api.Labels)
+	assert.Equal(t, "uptime", api.DataSourceID)
+	require.NotNil(t, api.Value)
+	assert.Equal(t, 5000.0, *api.Value)
+
+	// 同一指纹再次推送时更新告警，恢复事件解决告警
+	body = []byte(`{"env": "prod", "events": [
+		{"monitor": {"id": 1, "name": "api"}, "msg": "still timing out", "priority": "P2", "state": "down"},
+		{"monitor": {"id": 2, "name": "db"}, "state": "up"},
+		{"monitor": {"id": 4, "name": "queue"}, "state": "up"}
+	]}`)
+	result, err = svc.IngestCustom(ctx, integration, body)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Resolved)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, models.AlertSeverityHigh, api.Severity)
+	assert.Len(t, alerts.alerts, 2)
+
+	_, err = svc.IngestCustom(ctx, integration, []byte(`not json`))
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	var schemaErr *models.IngestSchemaError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, models.IngestRejectInvalidJSON, schemaErr.Reason)
+
+	// 严格模式下任一告警转换失败即拒绝整个载荷，其他告警也不创建
+	svc.schemaMode = models.IngestSchemaStrict
+	body = []byte(`{"events": [
+		{"monitor": {"id": 5, "name": "mq"}, "state": "down"},
+		{"monitor": {"id": 6, "name": "s3"}, "priority": "P9", "state": "down"}
+	]}`)
+	_, err = svc.IngestCustom(ctx, integration, body)
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, models.IngestRejectMappingError, schemaErr.Reason)
+	assert.Contains(t, schemaErr.Detail, "P9")
+	assert.Len(t, alerts.alerts, 2)
+}
+
+func TestWebhookIntegrationService_InvalidPayloadMapping(t *testing.T) {
+	svc := NewWebhookIntegrationService(&MockRepositoryManager{}, nil, models.IngestSchemaLenient, zap.NewNop())
+
+	for _, mapping := range []*models.WebhookPayloadMapping{
+		{Severity: models.WebhookFieldMapping{Default: "high"}},
+		{Name: models.WebhookFieldMapping{Path: "title"}, Severity: models.WebhookFieldMapping{Default: "high"}},
+		{Name: models.WebhookFieldMapping{Template: "${$..title}"}, Severity: models.WebhookFieldMapping{Default: "high"}},
+	} {
+		_, err := svc.Create(context.Background(), &models.WebhookIntegration{
+			Name: "custom", Source: models.AlertSourceCustom, PayloadMapping: mapping,
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pulse/internal/models"
+	"pulse/internal/pkg/jsonpath"
+)
+
+const (
+	// customWebhookFingerprintPrefix 自定义 Webhook 告警指纹前缀
+	customWebhookFingerprintPrefix = "webhook:"
+	// customWebhookMaxAlerts 单个载荷最多转换的告警数量
+	customWebhookMaxAlerts = 500
+)
+
+// templatePattern 模板中的 ${路径} 占位符
+var templatePattern = regexp.MustCompile(`\$\{\s*([^}]+?)\s*\}`)
+
+// compiledFieldMapping 编译后的字段映射
+type compiledFieldMapping struct {
+	mapping  models.WebhookFieldMapping
+	path     *jsonpath.Path
+	template []*jsonpath.Path
+}
+
+// compiledPayloadMapping 编译后的载荷映射
+type compiledPayloadMapping struct {
+	alerts       *jsonpath.Path
+	name         compiledFieldMapping
+	description  compiledFieldMapping
+	severity     compiledFieldMapping
+	status       compiledFieldMapping
+	fingerprint  compiledFieldMapping
+	expression   compiledFieldMapping
+	value        compiledFieldMapping
+	startsAt     compiledFieldMapping
+	generatorURL compiledFieldMapping
+	labelsPath   *jsonpath.Path
+	labels       map[string]compiledFieldMapping
+	annotations  map[string]compiledFieldMapping
+}
+
+// mappedAlert 载荷中的一条告警，resolved 为 true 时解决同一指纹的告警
+type mappedAlert struct {
+	alert    *models.Alert
+	resolved bool
+}
+
+// compilePayloadMapping 编译载荷映射，路径语法错误时返回 ErrInvalidInput
+func compilePayloadMapping(mapping *models.WebhookPayloadMapping) (*compiledPayloadMapping, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledPayloadMapping{
+		labels:      make(map[string]compiledFieldMapping, len(mapping.Labels)),
+		annotations: make(map[string]compiledFieldMapping, len(mapping.Annotations)),
+	}
+	var err error
+	if compiled.alerts, err = compileOptionalPath("alerts_path", mapping.AlertsPath); err != nil {
+		return nil, err
+	}
+	if compiled.labelsPath, err = compileOptionalPath("labels_path", mapping.LabelsPath); err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		name    string
+		mapping models.WebhookFieldMapping
+		target  *compiledFieldMapping
+	}{
+		{"name", mapping.Name, &compiled.name},
+		{"description", mapping.Description, &compiled.description},
+		{"severity", mapping.Severity, &compiled.severity},
+		{"status", mapping.Status, &compiled.status},
+		{"fingerprint", mapping.Fingerprint, &compiled.fingerprint},
+		{"expression", mapping.Expression, &compiled.expression},
+		{"value", mapping.Value, &compiled.value},
+		{"starts_at", mapping.StartsAt, &compiled.startsAt},
+		{"generator_url", mapping.GeneratorURL, &compiled.generatorURL},
+	}
+	for _, field := range fields {
+		if *field.target, err = compileFieldMapping(field.name, field.mapping); err != nil {
+			return nil, err
+		}
+	}
+	for key, field := range mapping.Labels {
+		if compiled.labels[key], err = compileFieldMapping("labels."+key, field); err != nil {
+			return nil, err
+		}
+	}
+	for key, field := range mapping.Annotations {
+		if compiled.annotations[key], err = compileFieldMapping("annotations."+key, field); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+// compileOptionalPath 编译可选的路径，为空时返回 nil
+func compileOptionalPath(field, expr string) (*jsonpath.Path, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	path, err := jsonpath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", models.ErrInvalidInput, field, err)
+	}
+	return path, nil
+}
+
+// compileFieldMapping 编译字段映射的路径和模板
+func compileFieldMapping(field string, mapping models.WebhookFieldMapping) (compiledFieldMapping, error) {
+	compiled := compiledFieldMapping{mapping: mapping}
+
+	var err error
+	if compiled.path, err = compileOptionalPath(field, mapping.Path); err != nil {
+		return compiled, err
+	}
+	for _, match := range templatePattern.FindAllStringSubmatch(mapping.Template, -1) {
+		path, err := compileOptionalPath(field, match[1])
+		if err != nil {
+			return compiled, err
+		}
+		compiled.template = append(compiled.template, path)
+	}
+	return compiled, nil
+}
+
+// eval 计算字段的值
+func (f *compiledFieldMapping) eval(root, current interface{}) string {
+	var value string
+	switch {
+	case f.mapping.Template != "":
+		i := 0
+		value = templatePattern.ReplaceAllStringFunc(f.mapping.Template, func(string) string {
+			path := f.template[i]
+			i++
+			node, _ := path.Get(root, current)
+			return stringifyNode(node)
+		})
+	case f.path != nil:
+		node, _ := f.path.Get(root, current)
+		value = stringifyNode(node)
+	}
+
+	value = strings.TrimSpace(value)
+	if mapped, ok := f.mapping.Values[value]; ok {
+		value = mapped
+	}
+	if value == "" {
+		value = f.mapping.Default
+	}
+	return value
+}
+
+// transform 将载荷转换为告警，单条告警映射失败时记录错误并跳过
+func (m *compiledPayloadMapping) transform(integration *models.WebhookIntegration, body []byte, now time.Time) ([]*mappedAlert, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, nil, &models.IngestSchemaError{Reason: models.IngestRejectInvalidJSON, Detail: fmt.Sprintf("载荷不是合法的JSON: %v", err), Cause: err}
+	}
+
+	items := []interface{}{root}
+	if m.alerts != nil {
+		items = m.alerts.Select(root, root)
+	}
+	if len(items) > customWebhookMaxAlerts {
+		return nil, nil, models.NewIngestSchemaError(models.IngestRejectInvalidValue, "单个载荷最多包含 %d 条告警，实际 %d 条", customWebhookMaxAlerts, len(items))
+	}
+
+	alerts := make([]*mappedAlert, 0, len(items))
+	var errs []string
+	for i, item := range items {
+		alert, err := m.transformItem(integration, root, item, now)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("第 %d 条告警: %v", i+1, err))
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, errs, nil
+}
+
+// transformItem 转换单条告警
+func (m *compiledPayloadMapping) transformItem(integration *models.WebhookIntegration, root, item interface{}, now time.Time) (*mappedAlert, error) {
+	severity := m.severity.eval(root, item)
+	alert := &models.Alert{
+		DataSourceID: integration.ID,
+		Name:         truncateRunes(m.name.eval(root, item), 200),
+		Description:  truncateRunes(m.description.eval(root, item), 1000),
+		Severity:     models.AlertSeverity(strings.ToLower(severity)),
+		Status:       models.AlertStatusFiring,
+		Source:       integration.Source,
+		Labels:       m.evalLabels(root, item),
+		Annotations:  map[string]string{"integration_id": integration.ID},
+		Expression:   m.expression.eval(root, item),
+		StartsAt:     now,
+	}
+	if alert.Name == "" {
+		return nil, fmt.Errorf("告警名称为空")
+	}
+	if !alert.Severity.IsValid() {
+		return nil, fmt.Errorf("无效的告警级别 %q，可通过 values 映射为 critical、high、medium、low 或 info", severity)
+	}
+	if alert.Description == "" {
+		alert.Description = alert.Name
+	}
+	if alert.Expression == "" {
+		alert.Expression = "webhook:" + integration.Name
+	}
+	for key, field := range m.annotations {
+		if value := field.eval(root, item); value != "" {
+			alert.Annotations[key] = value
+		}
+	}
+
+	if raw := m.value.eval(root, item); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的告警值 %q", raw)
+		}
+		alert.Value = &value
+	}
+	if raw := m.startsAt.eval(root, item); raw != "" {
+		startsAt, err := parseWebhookTime(raw)
+		if err != nil {
+			return nil, err
+		}
+		alert.StartsAt = startsAt
+	}
+	if url := m.generatorURL.eval(root, item); url != "" {
+		alert.GeneratorURL = &url
+	}
+
+	// 指纹按集成隔离，未映射时由名称和标签确定
+	key := m.fingerprint.eval(root, item)
+	if key == "" {
+		key = alert.Name + "|" + canonicalLabels(alert.Labels)
+	}
+	sum := sha256.Sum256([]byte(integration.ID + "|" + key))
+	alert.Fingerprint = customWebhookFingerprintPrefix + hex.EncodeToString(sum[:20])
+
+	resolved := strings.EqualFold(m.status.eval(root, item), string(models.AlertStatusResolved))
+	return &mappedAlert{alert: alert, resolved: resolved}, nil
+}
+
+// evalLabels 计算标签，labels 中的标签覆盖 labels_path 中的同名标签
+func (m *compiledPayloadMapping) evalLabels(root, item interface{}) map[string]string {
+	labels := make(map[string]string)
+	if m.labelsPath != nil {
+		if node, ok := m.labelsPath.Get(root, item); ok {
+			if object, ok := node.(map[string]interface{}); ok {
+				for key, value := range object {
+					if text := stringifyNode(value); text != "" {
+						labels[key] = text
+					}
+				}
+			}
+		}
+	}
+	for key, field := range m.labels {
+		if value := field.eval(root, item); value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// stringifyNode 将 JSON 节点转换为字符串，对象和数组输出为 JSON
+func stringifyNode(node interface{}) string {
+	switch value := node.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// parseWebhookTime 解析 RFC 3339 时间或 Unix 时间戳（秒或毫秒）
+func parseWebhookTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if number, err := strconv.ParseFloat(raw, 64); err == nil {
+		// 大于 1e12 视为毫秒
+		if number > 1e12 {
+			return time.UnixMilli(int64(number)), nil
+		}
+		return time.Unix(int64(number), 0), nil
+	}
+	return time.Time{}, fmt.Errorf("无效的开始时间 %q，需要 RFC 3339 时间或 Unix 时间戳", raw)
+}
+
+// canonicalLabels 按标签名排序拼接标签，用于生成稳定的指纹
+func canonicalLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(labels[key])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"pulse/internal/models"
+	"pulse/internal/pkg/breaker"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/redact"
 	"pulse/internal/repository"
 
 	"github.com/google/uuid"
@@ -18,17 +22,37 @@ import (
 // webhookService Webhook服务实现
 type webhookService struct {
 	repoManager repository.RepositoryManager
+	breakers    *breaker.Registry
+	httpClients *httpclient.Factory
 	logger      *zap.Logger
 }
 
-// NewWebhookService 创建Webhook服务实例
-func NewWebhookService(repoManager repository.RepositoryManager, logger *zap.Logger) WebhookService {
+// NewWebhookService 创建Webhook服务实例，breakers 为 nil 时投递不做熔断保护，
+// 投递使用 httpClients 的全局代理和CA证书，重试由投递循环控制
+func NewWebhookService(repoManager repository.RepositoryManager, breakers *breaker.Registry, httpClients *httpclient.Factory, logger *zap.Logger) WebhookService {
 	return &webhookService{
 		repoManager: repoManager,
+		breakers:    breakers,
+		httpClients: httpClients,
 		logger:      logger,
 	}
 }
 
+// webhookBreakerName Webhook 熔断器名称
+func webhookBreakerName(id string) string {
+	return "webhook:" + id
+}
+
+// webhookServerError Webhook 返回 5xx 状态码，计入熔断失败
+type webhookServerError struct {
+	statusCode int
+	body       string
+}
+
+func (e *webhookServerError) Error() string {
+	return fmt.Sprintf("HTTP状态码: %d", e.statusCode)
+}
+
 // Create 创建Webhook
 func (s *webhookService) Create(ctx context.Context, webhook *models.Webhook) error {
 	if webhook == nil {
@@ -118,6 +142,15 @@ func (s *webhookService) Update(ctx context.Context, webhook *models.Webhook) er
 		return fmt.Errorf("webhook URL不能为空")
 	}
 
+	// 未修改的密钥和请求头以脱敏值提交，从已保存的配置还原
+	if webhook.HasRedacted() {
+		stored, err := s.repoManager.Webhook().GetByID(ctx, webhook.ID.String())
+		if err != nil {
+			return fmt.Errorf("Webhook不存在或获取失败: %w", err)
+		}
+		webhook.RestoreRedacted(stored)
+	}
+
 	// 调用仓储层更新Webhook
 	err := s.repoManager.Webhook().Update(ctx, webhook)
 	if err != nil {
@@ -148,6 +181,7 @@ func (s *webhookService) Delete(ctx context.Context, id string) error {
 		s.logger.Error("删除Webhook失败", zap.Error(err), zap.String("id", id))
 		return fmt.Errorf("删除Webhook失败: %w", err)
 	}
+	s.breakers.Remove(webhookBreakerName(id))
 
 	s.logger.Info("Webhook删除成功", zap.String("id", id))
 	return nil
@@ -187,6 +221,7 @@ func (s *webhookService) Trigger(ctx context.Context, id string, payload interfa
 func (s *webhookService) executeWebhook(ctx context.Context, webhook *models.Webhook, payload []byte) error {
 	start := time.Now()
 	var lastErr error
+	cb := s.breakers.Get(webhookBreakerName(webhook.ID.String()))
 
 	// 重试逻辑
 	for attempt := 0; attempt <= webhook.RetryCount; attempt++ {
@@ -212,44 +247,71 @@ func (s *webhookService) executeWebhook(ctx context.Context, webhook *models.Web
 		}
 
 		// 创建HTTP客户端
-		client := &http.Client{
-			Timeout: time.Duration(webhook.Timeout) * time.Second,
+		client := s.httpClients.Client(httpclient.Policy{
+			Timeout:     time.Duration(webhook.Timeout) * time.Second,
+			MaxAttempts: 1,
+		})
+
+		// 在熔断器保护下执行请求，连续失败后暂停投递，避免挂起的接收端占用投递协程
+		var statusCode int
+		var responseBody string
+		err = cb.Do(ctx, func(ctx context.Context) error {
+			resp, err := client.Do(req.WithContext(ctx))
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			// 读取响应
+			var body bytes.Buffer
+			if _, err := body.ReadFrom(resp.Body); err != nil {
+				s.logger.Warn("读取响应失败", zap.Error(err), zap.String("webhook_id", webhook.ID.String()))
+			}
+			if resp.StatusCode >= 500 {
+				return &webhookServerError{statusCode: resp.StatusCode, body: body.String()}
+			}
+			statusCode = resp.StatusCode
+			responseBody = body.String()
+			return nil
+		})
+
+		if errors.Is(err, breaker.ErrOpen) {
+			s.logger.Warn("Webhook已熔断，跳过投递", zap.String("webhook_id", webhook.ID.String()))
+			lastErr = err
+			s.logWebhookCall(ctx, webhook.ID.String(), payload, 0, "", err.Error(), time.Since(start))
+			// 熔断期间重试同样会被拒绝
+			break
 		}
 
-		// 执行请求
-		resp, err := client.Do(req)
-		if err != nil {
+		var serverErr *webhookServerError
+		switch {
+		case errors.As(err, &serverErr):
+			statusCode = serverErr.statusCode
+			responseBody = serverErr.body
+		case err != nil:
 			s.logger.Warn("Webhook调用失败", zap.Error(err), zap.String("webhook_id", webhook.ID.String()), zap.Int("attempt", attempt+1))
 			lastErr = err
 			// 记录失败日志
 			s.logWebhookCall(ctx, webhook.ID.String(), payload, 0, "", err.Error(), time.Since(start))
 			continue
 		}
-		defer resp.Body.Close()
-
-		// 读取响应
-		var responseBody bytes.Buffer
-		_, err = responseBody.ReadFrom(resp.Body)
-		if err != nil {
-			s.logger.Warn("读取响应失败", zap.Error(err), zap.String("webhook_id", webhook.ID.String()))
-		}
 
 		// 检查响应状态码
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if statusCode >= 200 && statusCode < 300 {
 			// 成功
-			s.logger.Info("Webhook调用成功", zap.String("webhook_id", webhook.ID.String()), zap.Int("status_code", resp.StatusCode))
+			s.logger.Info("Webhook调用成功", zap.String("webhook_id", webhook.ID.String()), zap.Int("status_code", statusCode))
 			// 记录成功日志
-			s.logWebhookCall(ctx, webhook.ID.String(), payload, resp.StatusCode, responseBody.String(), "", time.Since(start))
+			s.logWebhookCall(ctx, webhook.ID.String(), payload, statusCode, responseBody, "", time.Since(start))
 			// 更新成功计数
 			s.repoManager.Webhook().IncrementSuccessCount(ctx, webhook.ID.String())
 			s.repoManager.Webhook().UpdateLastTriggered(ctx, webhook.ID.String())
 			return nil
 		} else {
 			// 失败
-			lastErr = fmt.Errorf("HTTP状态码: %d", resp.StatusCode)
-			s.logger.Warn("Webhook调用返回错误状态码", zap.String("webhook_id", webhook.ID.String()), zap.Int("status_code", resp.StatusCode))
+			lastErr = fmt.Errorf("HTTP状态码: %d", statusCode)
+			s.logger.Warn("Webhook调用返回错误状态码", zap.String("webhook_id", webhook.ID.String()), zap.Int("status_code", statusCode))
 			// 记录失败日志
-			s.logWebhookCall(ctx, webhook.ID.String(), payload, resp.StatusCode, responseBody.String(), lastErr.Error(), time.Since(start))
+			s.logWebhookCall(ctx, webhook.ID.String(), payload, statusCode, responseBody, lastErr.Error(), time.Since(start))
 		}
 
 		// 如果不是最后一次尝试，等待一段时间再重试
@@ -282,10 +344,13 @@ func (s *webhookService) logWebhookCall(ctx context.Context, webhookID string, p
 		CreatedAt:  time.Now(),
 	}
 
+	// 接收端的响应和错误信息可能回显请求中的凭据，保存前脱敏
 	if response != "" {
+		response = redact.String(response)
 		log.Response = &response
 	}
 	if errorMsg != "" {
+		errorMsg = redact.String(errorMsg)
 		log.Error = &errorMsg
 	}
 
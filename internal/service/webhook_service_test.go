@@ -93,6 +93,14 @@ func (m *MockWebhookRepository) CleanupInactive(ctx context.Context, before time
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockWebhookRepository) RotateEncryptionKeys(ctx context.Context, batchSize int) (*models.KeyRotationResult, error) {
+	args := m.Called(ctx, batchSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.KeyRotationResult), args.Error(1)
+}
+
 func (m *MockWebhookRepository) CleanupLogs(ctx context.Context, before time.Time) (int64, error) {
 	args := m.Called(ctx, before)
 	return args.Get(0).(int64), args.Error(1)
@@ -215,6 +223,174 @@ func (m *MockRepositoryManager) Notification() repository.NotificationRepository
 	return nil
 }
 
+func (m *MockRepositoryManager) CustomField() repository.CustomFieldRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) KnowledgeReview() repository.KnowledgeReviewRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) KnowledgeComment() repository.KnowledgeCommentRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) KnowledgeACL() repository.KnowledgeACLRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) WebhookIntegration() repository.WebhookIntegrationRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) FeatureFlag() repository.FeatureFlagRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Dashboard() repository.DashboardRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Postmortem() repository.PostmortemRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Remediation() repository.RemediationRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) ChatUserMapping() repository.ChatUserMappingRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) ITSM() repository.ITSMRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Heartbeat() repository.HeartbeatRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) ServiceCatalog() repository.ServiceCatalogRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) SLO() repository.SLORepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AlertSnooze() repository.AlertSnoozeRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) NotificationRouting() repository.NotificationRoutingRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) LanguagePreference() repository.LanguagePreferenceRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Tag() repository.TagRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Partition() repository.PartitionRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Retention() repository.RetentionRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AuditLog() repository.AuditLogRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AssignmentPolicy() repository.AssignmentPolicyRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Handover() repository.HandoverRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AlertActionLink() repository.AlertActionLinkRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Push() repository.PushRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Inbox() repository.InboxRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) QuietHours() repository.QuietHoursRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) ChangeEvent() repository.ChangeEventRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) NotificationAttempt() repository.NotificationAttemptRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) WebhookSubscription() repository.WebhookSubscriptionRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) TicketImport() repository.TicketImportRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AlertStats() repository.AlertStatsRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) TicketSurvey() repository.TicketSurveyRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) KnowledgeFeedback() repository.KnowledgeFeedbackRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Approval() repository.ApprovalRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) IngestRejection() repository.IngestRejectionRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AsyncTask() repository.AsyncTaskRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) NotificationChannel() repository.NotificationChannelRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) Metric() repository.MetricRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) TicketSLAPause() repository.TicketSLAPauseRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) AlertSeverityRule() repository.AlertSeverityRuleRepository {
+	return nil
+}
+
+func (m *MockRepositoryManager) StatusPage() repository.StatusPageRepository {
+	return nil
+}
+
 func (m *MockRepositoryManager) BeginTx(ctx context.Context) (repository.RepositoryManager, error) {
 	return m, nil
 }
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/httpclient"
+	"pulse/internal/pkg/redact"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+const (
+	// webhookDeliveryBatchSize 每轮每个订阅最多投递的事件数，避免单个订阅占满投递周期
+	webhookDeliveryBatchSize = 20
+	// webhookResponseReadLimit 读取接收端响应体的最大字节数
+	webhookResponseReadLimit = 64 * 1024
+
+	// 投递请求头，签名和时间戳请求头见 signature 包
+	webhookHeaderEvent    = "X-Pulse-Event"
+	webhookHeaderDelivery = "X-Pulse-Delivery"
+)
+
+// webhookSubscriptionService 出站 Webhook 订阅服务实现
+type webhookSubscriptionService struct {
+	repoManager repository.RepositoryManager
+	httpClients *httpclient.Factory
+	cfg         config.WebhookSubscriptionConfig
+	logger      *zap.Logger
+	now         func() time.Time
+	lookupIP    func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewWebhookSubscriptionService 创建出站 Webhook 订阅服务实例，httpClients 为 nil 时使用默认连接选项
+func NewWebhookSubscriptionService(repoManager repository.RepositoryManager, httpClients *httpclient.Factory, cfg config.WebhookSubscriptionConfig, logger *zap.Logger) WebhookSubscriptionService {
+	return &webhookSubscriptionService{
+		repoManager: repoManager,
+		httpClients: httpClients,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+		lookupIP:    net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// Interval 投递待发送事件的周期
+func (s *webhookSubscriptionService) Interval() time.Duration {
+	return s.cfg.DeliveryInterval
+}
+
+// List 获取订阅列表
+func (s *webhookSubscriptionService) List(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	subscriptions, err := s.repoManager.WebhookSubscription().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取Webhook订阅列表失败: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// Get 获取订阅，不返回密钥
+func (s *webhookSubscriptionService) Get(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	subscription, err := s.repoManager.WebhookSubscription().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// Create 创建订阅并生成签名密钥，密钥只在此时返回一次
+func (s *webhookSubscriptionService) Create(ctx context.Context, req *models.WebhookSubscriptionRequest, createdBy string) (*models.WebhookSubscriptionSecret, error) {
+	subscription := &models.WebhookSubscription{Enabled: true}
+	req.ApplyTo(subscription)
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.validateTarget(ctx, subscription.URL); err != nil {
+		return nil, err
+	}
+	if createdBy != "" {
+		subscription.CreatedBy = &createdBy
+	}
+
+	secret, err := generateWebhookIntegrationSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = secret
+
+	if err := s.repoManager.WebhookSubscription().Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+
+	s.logger.Info("Webhook订阅已创建", zap.String("subscription_id", subscription.ID),
+		zap.String("url", redact.URL(subscription.URL)), zap.Strings("events", subscription.Events))
+	return &models.WebhookSubscriptionSecret{Subscription: subscription, Secret: secret}, nil
+}
+
+// Update 更新订阅配置，重新启用被自动停用的订阅时清零连续失败数
+func (s *webhookSubscriptionService) Update(ctx context.Context, id string, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.repoManager.WebhookSubscription().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	wasEnabled := subscription.Enabled
+	req.ApplyTo(subscription)
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.validateTarget(ctx, subscription.URL); err != nil {
+		return nil, err
+	}
+	if subscription.Enabled && !wasEnabled {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledReason = nil
+	}
+
+	if err := s.repoManager.WebhookSubscription().Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+
+	s.logger.Info("Webhook订阅已更新", zap.String("subscription_id", id), zap.Bool("enabled", subscription.Enabled))
+	return subscription, nil
+}
+
+// validateTarget 校验订阅地址不指向本机、内网或链路本地地址，避免通过投递请求访问内部服务
+func (s *webhookSubscriptionService) validateTarget(ctx context.Context, rawURL string) error {
+	if s.cfg.AllowPrivateTargets {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: 订阅地址无效", models.ErrInvalidInput)
+	}
+
+	host := u.Hostname()
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, err := s.lookupIP(ctx, host)
+		if err != nil || len(addrs) == 0 {
+			return fmt.Errorf("%w: 无法解析订阅地址的主机 %s", models.ErrInvalidInput, host)
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+			return fmt.Errorf("%w: 订阅地址不能指向本机或内网地址 %s", models.ErrInvalidInput, host)
+		}
+	}
+	return nil
+}
+
+// Delete 删除订阅，未投递的事件一并删除
+func (s *webhookSubscriptionService) Delete(ctx context.Context, id string) error {
+	if err := s.repoManager.WebhookSubscription().Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Webhook订阅已删除", zap.String("subscription_id", id))
+	return nil
+}
+
+// RotateSecret 重新生成签名密钥，旧密钥立即失效，重试中的事件使用新密钥签名
+func (s *webhookSubscriptionService) RotateSecret(ctx context.Context, id string) (*models.WebhookSubscriptionSecret, error) {
+	secret, err := generateWebhookIntegrationSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repoManager.WebhookSubscription().UpdateSecret(ctx, id, secret); err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Webhook订阅密钥已重置", zap.String("subscription_id", id))
+	return &models.WebhookSubscriptionSecret{Subscription: subscription, Secret: secret}, nil
+}
+
+// ListDeliveries 分页获取订阅的投递记录
+func (s *webhookSubscriptionService) ListDeliveries(ctx context.Context, id string, filter *models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	if _, err := s.repoManager.WebhookSubscription().GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repoManager.WebhookSubscription().ListDeliveries(ctx, id, filter)
+}
+
+// Redeliver 将投递记录中的事件重新加入订阅的投递队列末尾，事件 ID 和内容不变
+func (s *webhookSubscriptionService) Redeliver(ctx context.Context, id, deliveryID string) (*models.WebhookDelivery, error) {
+	original, err := s.repoManager.WebhookSubscription().GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: id,
+		EventID:        original.EventID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		NextAttemptAt:  s.now(),
+	}
+	if err := s.repoManager.WebhookSubscription().CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Webhook事件已重新加入投递队列", zap.String("subscription_id", id),
+		zap.String("delivery_id", deliveryID), zap.String("event_id", delivery.EventID))
+	return delivery, nil
+}
+
+// Publish 将事件加入订阅了该事件的启用订阅的投递队列，由投递任务按顺序发送
+func (s *webhookSubscriptionService) Publish(ctx context.Context, event models.WebhookEvent, data interface{}) {
+	subscriptions, err := s.repoManager.WebhookSubscription().ListEnabled(ctx)
+	if err != nil {
+		s.logger.Error("获取Webhook订阅失败", zap.Error(err), zap.String("event", string(event)))
+		return
+	}
+
+	var payload []byte
+	eventID := uuid.New().String()
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(&models.WebhookEventPayload{
+				ID: eventID, Event: event, OccurredAt: s.now(), Data: data,
+			})
+			if err != nil {
+				s.logger.Error("序列化Webhook事件失败", zap.Error(err), zap.String("event", string(event)))
+				return
+			}
+		}
+
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        string(payload),
+			NextAttemptAt:  s.now(),
+		}
+		if err := s.repoManager.WebhookSubscription().CreateDelivery(ctx, delivery); err != nil {
+			s.logger.Error("Webhook事件加入投递队列失败", zap.Error(err),
+				zap.String("subscription_id", subscription.ID), zap.String("event", string(event)))
+		}
+	}
+}
+
+// DeliverPending 投递各启用订阅到期的事件，返回本轮投递的次数
+// 同一订阅的事件按加入队列的顺序投递，最早的事件等待重试期间不投递后续事件
+func (s *webhookSubscriptionService) DeliverPending(ctx context.Context) (int, error) {
+	subscriptions, err := s.repoManager.WebhookSubscription().ListEnabled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取Webhook订阅失败: %w", err)
+	}
+
+	delivered := 0
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		n, err := s.deliverSubscription(ctx, subscription.ID)
+		if err != nil {
+			s.logger.Error("投递Webhook订阅事件失败", zap.Error(err), zap.String("subscription_id", subscription.ID))
+		}
+		delivered += n
+	}
+	return delivered, nil
+}
+
+// deliverSubscription 持有订阅的投递租约，按顺序投递到期的事件，其他实例正在投递时跳过
+func (s *webhookSubscriptionService) deliverSubscription(ctx context.Context, id string) (int, error) {
+	repo := s.repoManager.WebhookSubscription()
+	lease := s.cfg.DeliveryTimeout*webhookDeliveryBatchSize + time.Minute
+	claimed, err := repo.Claim(ctx, id, s.now().Add(lease))
+	if err != nil || !claimed {
+		return 0, err
+	}
+	defer func() {
+		if err := repo.Release(ctx, id); err != nil {
+			s.logger.Warn("释放Webhook订阅投递租约失败", zap.Error(err), zap.String("subscription_id", id))
+		}
+	}()
+
+	// 读取带明文密钥的订阅，并确认取得租约前订阅未被停用
+	subscription, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for attempted < webhookDeliveryBatchSize && subscription.Enabled {
+		delivery, err := repo.NextDelivery(ctx, id)
+		if err != nil {
+			return attempted, err
+		}
+		if delivery == nil || delivery.NextAttemptAt.After(s.now()) {
+			return attempted, nil
+		}
+
+		attempted++
+		if err := s.deliver(ctx, subscription, delivery); err != nil {
+			return attempted, err
+		}
+		if delivery.Status == models.WebhookDeliveryPending {
+			// 等待重试，保持顺序不投递后续事件
+			return attempted, nil
+		}
+	}
+	return attempted, nil
+}
+
+// deliver 投递一次事件并记录结果，达到最多投递次数仍失败时放弃该事件，连续失败的事件数达到阈值时停用订阅
+func (s *webhookSubscriptionService) deliver(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	start := s.now()
+	statusCode, response, sendErr := s.send(ctx, subscription, delivery, start)
+	now := s.now()
+
+	delivery.Attempts++
+	delivery.LastDurationMs = now.Sub(start).Milliseconds()
+	delivery.LastStatusCode = nil
+	if statusCode > 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+	delivery.LastResponse = nil
+	if response != "" {
+		response = models.TruncateProviderResponse(redact.String(response))
+		delivery.LastResponse = &response
+	}
+	delivery.LastError = nil
+
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		subscription.ConsecutiveFailures = 0
+		subscription.LastDeliveredAt = &now
+	} else {
+		message := redact.Error(sendErr)
+		delivery.LastError = &message
+		if delivery.Attempts < s.maxAttempts(subscription) {
+			delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+		} else {
+			delivery.Status = models.WebhookDeliveryFailed
+			subscription.ConsecutiveFailures++
+			if subscription.ConsecutiveFailures >= s.disableAfterFailures(subscription) {
+				reason := fmt.Sprintf("连续%d个事件投递失败，最近一次错误: %s", subscription.ConsecutiveFailures, message)
+				subscription.Enabled = false
+				subscription.DisabledReason = &reason
+			}
+		}
+		s.logger.Warn("Webhook事件投递失败", zap.Error(sendErr),
+			zap.String("subscription_id", subscription.ID), zap.String("delivery_id", delivery.ID),
+			zap.Int("attempts", delivery.Attempts), zap.String("status", string(delivery.Status)))
+	}
+
+	if err := s.repoManager.WebhookSubscription().UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		return nil
+	}
+	if err := s.repoManager.WebhookSubscription().Update(ctx, subscription); err != nil {
+		return err
+	}
+	if !subscription.Enabled {
+		s.logger.Warn("Webhook订阅连续投递失败，已自动停用", zap.String("subscription_id", subscription.ID),
+			zap.Int("consecutive_failures", subscription.ConsecutiveFailures))
+	}
+	return nil
+}
+
+// send 发送签名的投递请求，返回状态码和响应体，非 2xx 响应视为失败
+func (s *webhookSubscriptionService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, at time.Time) (int, string, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("创建投递请求失败: %w", err)
+	}
+	timestamp := at.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookHeaderEvent, string(delivery.Event))
+	req.Header.Set(webhookHeaderDelivery, delivery.ID)
+	req.Header.Set(signature.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(signature.HeaderSignature, signature.Sign(subscription.Secret, timestamp, body))
+
+	// 重试由投递队列按退避时间处理，客户端只发送一次
+	client := s.httpClients.Client(httpclient.Policy{Timeout: s.cfg.DeliveryTimeout, MaxAttempts: 1})
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("发送投递请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseReadLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("接收端返回 HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// backoff 第 attempts 次投递失败后的重试等待时间，按指数增长，不超过上限
+func (s *webhookSubscriptionService) backoff(attempts int) time.Duration {
+	wait := s.cfg.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= s.cfg.MaxRetryBackoff {
+			return s.cfg.MaxRetryBackoff
+		}
+	}
+	return min(wait, s.cfg.MaxRetryBackoff)
+}
+
+// maxAttempts 订阅单个事件的最多投递次数
+func (s *webhookSubscriptionService) maxAttempts(subscription *models.WebhookSubscription) int {
+	if subscription.MaxAttempts > 0 {
+		return subscription.MaxAttempts
+	}
+	return max(s.cfg.MaxAttempts, 1)
+}
+
+// disableAfterFailures 订阅停用前允许连续失败的事件数
+func (s *webhookSubscriptionService) disableAfterFailures(subscription *models.WebhookSubscription) int {
+	if subscription.DisableAfterFailures > 0 {
+		return subscription.DisableAfterFailures
+	}
+	return max(s.cfg.DisableAfterFailures, 1)
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"pulse/internal/config"
+	"pulse/internal/models"
+	"pulse/internal/pkg/signature"
+	"pulse/internal/repository"
+)
+
+// fakeWebhookSubscriptionRepository 内存中的订阅和投递队列，Seq 按加入顺序递增
+type fakeWebhookSubscriptionRepository struct {
+	repository.WebhookSubscriptionRepository
+	subscriptions map[string]*models.WebhookSubscription
+	deliveries    []*models.WebhookDelivery
+	seq           int64
+}
+
+func (r *fakeWebhookSubscriptionRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	subscription.ID = uuid.New().String()
+	stored := *subscription
+	r.subscriptions[subscription.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, models.ErrWebhookSubscriptionNotFound
+	}
+	copied := *subscription
+	return &copied, nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) ListEnabled(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	subscriptions := []*models.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if subscription.Enabled {
+			copied := *subscription
+			copied.Secret = ""
+			subscriptions = append(subscriptions, &copied)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].Name < subscriptions[j].Name })
+	return subscriptions, nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) Update(ctx context.Context, subscription *models.WebhookSubscription) error {
+	stored, ok := r.subscriptions[subscription.ID]
+	if !ok {
+		return models.ErrWebhookSubscriptionNotFound
+	}
+	secret := stored.Secret
+	*stored = *subscription
+	stored.Secret = secret
+	return nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) Claim(ctx context.Context, id string, until time.Time) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) Release(ctx context.Context, id string) error { return nil }
+
+func (r *fakeWebhookSubscriptionRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.seq++
+	delivery.ID = uuid.New().String()
+	delivery.Seq = r.seq
+	delivery.Status = models.WebhookDeliveryPending
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) NextDelivery(ctx context.Context, subscriptionID string) (*models.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.Status == models.WebhookDeliveryPending {
+			return delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookSubscriptionRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return nil
+}
+
+// receivedWebhook 接收端收到的一次投递
+type receivedWebhook struct {
+	event   string
+	payload models.WebhookEventPayload
+	status  int
+}
+
+// webhookReceiver 校验签名并记录收到的投递，按 statuses 依次返回状态码，用完后返回 200
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	now      time.Time
+	statuses []int
+	received []receivedWebhook
+}
+
+func (rv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if err := signature.Verify(rv.secret, r.Header.Get(signature.HeaderSignature), r.Header.Get(signature.HeaderTimestamp),
+		body, rv.now, signature.DefaultTolerance); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	status := http.StatusOK
+	if len(rv.statuses) > 0 {
+		status, rv.statuses = rv.statuses[0], rv.statuses[1:]
+	}
+	var payload models.WebhookEventPayload
+	_ = json.Unmarshal(body, &payload)
+	rv.received = append(rv.received, receivedWebhook{event: r.Header.Get("X-Pulse-Event"), payload: payload, status: status})
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (rv *webhookReceiver) events() []string {
+	events := []string{}
+	for _, received := range rv.received {
+		events = append(events, received.event)
+	}
+	return events
+}
+
+type webhookSubscriptionRepoManager struct {
+	*MockRepositoryManager
+	subscriptions *fakeWebhookSubscriptionRepository
+}
+
+func (m *webhookSubscriptionRepoManager) WebhookSubscription() repository.WebhookSubscriptionRepository {
+	return m.subscriptions
+}
+
+func newWebhookSubscriptionTestService(now *time.Time) (*webhookSubscriptionService, *fakeWebhookSubscriptionRepository) {
+	repo := &fakeWebhookSubscriptionRepository{subscriptions: map[string]*models.WebhookSubscription{}}
+	svc := NewWebhookSubscriptionService(&webhookSubscriptionRepoManager{subscriptions: repo}, nil, config.WebhookSubscriptionConfig{
+		DeliveryTimeout: 5 * time.Second, RetryBackoff: 30 * time.Second, MaxRetryBackoff: 10 * time.Minute,
+		MaxAttempts: 3, DisableAfterFailures: 2, AllowPrivateTargets: true,
+	}, zap.NewNop()).(*webhookSubscriptionService)
+	svc.now = func() time.Time { return *now }
+	return svc, repo
+}
+
+func TestWebhookSubscriptionService_DeliversInOrderWithRetry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc, repo := newWebhookSubscriptionTestService(&now)
+
+	receiver := &webhookReceiver{now: now, statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	created, err := svc.Create(ctx, &models.WebhookSubscriptionRequest{Name: "crm", URL: server.URL, Events: []string{"alert.*"}}, "u1")
+	require.NoError(t, err)
+	assert.Len(t, created.Secret, 2*webhookIntegrationSecretBytes)
+	receiver.secret = created.Secret
+
+	// 未订阅的事件不加入队列
+	svc.Publish(ctx, models.WebhookEventAlertCreated, &models.Alert{ID: "a1", Name: "APIDown"})
+	svc.Publish(ctx, models.WebhookEventTicketCreated, &models.Ticket{ID: "t1"})
+	svc.Publish(ctx, models.WebhookEventAlertResolved, &models.Alert{ID: "a1", Name: "APIDown"})
+	require.Len(t, repo.deliveries, 2)
+
+	// 第一个事件投递失败后等待重试，期间不投递后续事件
+	attempted, err := svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	first := repo.deliveries[0]
+	assert.Equal(t, models.WebhookDeliveryPending, first.Status)
+	assert.Equal(t, 1, first.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, *first.LastStatusCode)
+	assert.Equal(t, now.Add(30*time.Second), first.NextAttemptAt)
+
+	attempted, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+
+	// 到达重试时间后按顺序投递，重试使用相同的事件 ID
+	now = now.Add(30 * time.Second)
+	receiver.now = now
+	attempted, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempted)
+	assert.Equal(t, []string{"alert.created", "alert.created", "alert.resolved"}, receiver.events())
+	assert.Equal(t, receiver.received[0].payload.ID, receiver.received[1].payload.ID)
+	assert.Equal(t, models.WebhookDeliveryDelivered, repo.deliveries[0].Status)
+	assert.Equal(t, models.WebhookDeliveryDelivered, repo.deliveries[1].Status)
+	assert.Equal(t, now, *repo.subscriptions[created.Subscription.ID].LastDeliveredAt)
+}
+
+func TestWebhookSubscriptionService_DisablesAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc, repo := newWebhookSubscriptionTestService(&now)
+
+	receiver := &webhookReceiver{now: now, statuses: []int{500, 500, 500, 500}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	created, err := svc.Create(ctx, &models.WebhookSubscriptionRequest{
+		Name: "crm", URL: server.URL, Events: []string{"*"}, MaxAttempts: 1,
+	}, "")
+	require.NoError(t, err)
+	receiver.secret = created.Secret
+	id := created.Subscription.ID
+
+	svc.Publish(ctx, models.WebhookEventTicketCreated, &models.Ticket{ID: "t1"})
+	svc.Publish(ctx, models.WebhookEventTicketClosed, &models.Ticket{ID: "t1"})
+
+	// 达到最多投递次数的事件放弃后继续投递后续事件，连续失败的事件数达到阈值时停用订阅
+	_, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[1].Status)
+	subscription := repo.subscriptions[id]
+	assert.False(t, subscription.Enabled)
+	assert.Equal(t, 2, subscription.ConsecutiveFailures)
+	require.NotNil(t, subscription.DisabledReason)
+
+	// 停用的订阅不再接收事件
+	svc.Publish(ctx, models.WebhookEventTicketUpdated, &models.Ticket{ID: "t1"})
+	assert.Len(t, repo.deliveries, 2)
+
+	// 重新启用后清零连续失败数
+	enabled := true
+	updated, err := svc.Update(ctx, id, &models.WebhookSubscriptionRequest{
+		Name: "crm", URL: server.URL, Events: []string{"*"}, Enabled: &enabled, MaxAttempts: 1,
+	})
+	require.NoError(t, err)
+	assert.True(t, updated.Enabled)
+	assert.Zero(t, updated.ConsecutiveFailures)
+	assert.Nil(t, updated.DisabledReason)
+	assert.Empty(t, updated.Secret)
+	assert.Equal(t, created.Secret, repo.subscriptions[id].Secret)
+
+	_, err = svc.Create(ctx, &models.WebhookSubscriptionRequest{Name: "bad", URL: server.URL, Events: []string{"rule.*"}}, "")
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func TestWebhookSubscriptionService_RejectsPrivateTargets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc, repo := newWebhookSubscriptionTestService(&now)
+	svc.cfg.AllowPrivateTargets = false
+	svc.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("192.168.1.10")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	// 本机、内网、链路本地地址以及解析到内网的域名都不能作为订阅地址
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+		"https://internal.example.com/hook",
+	} {
+		_, err := svc.Create(ctx, &models.WebhookSubscriptionRequest{Name: "crm", URL: target, Events: []string{"*"}}, "")
+		assert.ErrorIs(t, err, models.ErrInvalidInput, target)
+	}
+	assert.Empty(t, repo.subscriptions)
+
+	created, err := svc.Create(ctx, &models.WebhookSubscriptionRequest{Name: "crm", URL: "https://hooks.example.com/pulse", Events: []string{"*"}}, "")
+	require.NoError(t, err)
+
+	// 更新时同样校验，拒绝后保留原地址
+	id := created.Subscription.ID
+	_, err = svc.Update(ctx, id, &models.WebhookSubscriptionRequest{Name: "crm", URL: "http://172.16.0.1/hook", Events: []string{"*"}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+	assert.Equal(t, "https://hooks.example.com/pulse", repo.subscriptions[id].URL)
+}
+
+func TestWebhookSubscriptionService_Backoff(t *testing.T) {
+	now := time.Now()
+	svc, _ := newWebhookSubscriptionTestService(&now)
+
+	assert.Equal(t, 30*time.Second, svc.backoff(1))
+	assert.Equal(t, time.Minute, svc.backoff(2))
+	assert.Equal(t, 8*time.Minute, svc.backoff(5))
+	assert.Equal(t, 10*time.Minute, svc.backoff(6))
+	assert.Equal(t, 10*time.Minute, svc.backoff(40))
+}
@@ -8,4 +8,12 @@ var (
 	ErrWorkerNotFound      = errors.New("worker not found")
 	ErrWorkerNotStarted    = errors.New("worker not started")
 	ErrWorkerAlreadyStarted = errors.New("worker already started")
-)
\ No newline at end of file
+)
+
+// 后台任务相关错误定义
+var (
+	ErrJobAlreadyExists = errors.New("job already exists")
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobNotScheduled  = errors.New("job not scheduled")
+	ErrJobNotRunning    = errors.New("job not running")
+)
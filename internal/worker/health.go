@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"pulse/internal/monitor"
+)
+
+// healthCheck Worker存活健康检查
+type healthCheck struct {
+	manager    Manager
+	staleAfter time.Duration
+}
+
+// NewHealthCheck 创建Worker存活健康检查
+// Worker未在运行或超过 staleAfter 未上报心跳时视为失活
+func NewHealthCheck(manager Manager, staleAfter time.Duration) monitor.HealthCheck {
+	return &healthCheck{
+		manager:    manager,
+		staleAfter: staleAfter,
+	}
+}
+
+// Name 返回健康检查名称
+func (h *healthCheck) Name() string {
+	return "workers"
+}
+
+// Check 检查所有Worker的运行状态和心跳
+func (h *healthCheck) Check(ctx context.Context) monitor.HealthResult {
+	result := monitor.HealthResult{
+		Timestamp: time.Now(),
+		Details:   make(map[string]interface{}),
+	}
+
+	var dead []string
+	for name, status := range h.manager.GetStatus() {
+		alive := (status.Status == "running" || status.Status == "idle") &&
+			time.Since(status.LastSeen) <= h.staleAfter
+		if !alive {
+			dead = append(dead, name)
+		}
+		result.Details[name] = map[string]interface{}{
+			"status":    status.Status,
+			"alive":     alive,
+			"last_seen": status.LastSeen,
+			"error":     status.Error,
+		}
+	}
+
+	if len(dead) > 0 {
+		result.Status = monitor.HealthStatusUnhealthy
+		result.Message = fmt.Sprintf("Workers not alive: %s", strings.Join(dead, ", "))
+		return result
+	}
+
+	result.Status = monitor.HealthStatusHealthy
+	result.Message = "All workers are alive"
+	return result
+}
@@ -0,0 +1,308 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"pulse/internal/lock"
+	"pulse/internal/pkg/redact"
+)
+
+// 后台任务状态
+const (
+	JobStateDisabled = "disabled" // 功能未开启，Worker 未调度该任务
+	JobStateIdle     = "idle"     // 等待下一次执行
+	JobStateRunning  = "running"  // 正在执行
+	JobStatePaused   = "paused"   // 已暂停，定时执行被跳过，仍可手动触发
+)
+
+// 后台任务最近一次执行的结果
+const (
+	JobResultSucceeded = "succeeded"
+	JobResultFailed    = "failed"
+	JobResultCanceled  = "canceled"
+	JobResultSkipped   = "skipped" // 任务锁由其他实例持有，本实例跳过本次执行
+)
+
+// JobLocker 多实例部署时的任务锁，获取 key 对应的锁后执行 fn，执行期间失去锁时取消 fn 的 ctx。
+// 锁由其他实例持有时不执行 fn，返回 lock.ErrLockNotAcquired
+type JobLocker func(ctx context.Context, key string, fn func(ctx context.Context) error) error
+
+// JobStatus 后台任务状态
+type JobStatus struct {
+	Name         string        `json:"name"`
+	State        string        `json:"state"`
+	Interval     time.Duration `json:"interval"`
+	NextRunAt    *time.Time    `json:"next_run_at,omitempty"`
+	RunStartedAt *time.Time    `json:"run_started_at,omitempty"`
+	LastRunAt    *time.Time    `json:"last_run_at,omitempty"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastResult   string        `json:"last_result,omitempty"`
+	LastError    string        `json:"last_error,omitempty"`
+	Runs         int64         `json:"runs"`
+	Failures     int64         `json:"failures"`
+	Skips        int64         `json:"skips"`
+}
+
+// Job 由 Worker 定时执行的后台任务，记录执行情况并支持手动触发、暂停和取消
+type Job struct {
+	name    string
+	trigger chan struct{}
+
+	mu           sync.Mutex
+	scheduled    bool
+	interval     time.Duration
+	nextRun      time.Time
+	paused       bool
+	runStarted   time.Time
+	cancelRun    context.CancelFunc
+	canceled     bool
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastResult   string
+	lastError    string
+	runs         int64
+	failures     int64
+	skips        int64
+	// locker 任务锁，单实例部署时为 nil
+	locker JobLocker
+}
+
+// NewJob 创建后台任务
+func NewJob(name string) *Job {
+	return &Job{
+		name:    name,
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Name 获取任务名称
+func (j *Job) Name() string {
+	return j.name
+}
+
+// Schedule 标记任务已由 Worker 按 interval 调度，Worker 创建定时器时调用
+func (j *Job) Schedule(interval time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.scheduled = true
+	j.interval = interval
+	j.nextRun = time.Now().Add(interval)
+}
+
+// Unschedule 标记任务不再调度，Worker 退出时调用
+func (j *Job) Unschedule() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.scheduled = false
+	j.nextRun = time.Time{}
+}
+
+// SetLocker 设置任务锁，之后每次执行前先获取锁，nil 表示不加锁
+func (j *Job) SetLocker(locker JobLocker) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.locker = locker
+}
+
+// Run 执行一次任务并记录开始时间、耗时和结果，执行期间可通过 Cancel 取消。
+// 设置了任务锁时先获取锁，锁由其他实例持有时跳过本次执行并返回 nil
+func (j *Job) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	j.mu.Lock()
+	start := time.Now()
+	j.runStarted = start
+	j.cancelRun = cancel
+	j.canceled = false
+	locker := j.locker
+	j.mu.Unlock()
+
+	var err error
+	if locker != nil {
+		err = locker(runCtx, "job:"+j.name, fn)
+	} else {
+		err = fn(runCtx)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.runStarted = time.Time{}
+	j.cancelRun = nil
+	j.lastRun = start
+	j.lastDuration = time.Since(start)
+	if errors.Is(err, lock.ErrLockNotAcquired) {
+		j.lastResult = JobResultSkipped
+		j.lastError = ""
+		j.skips++
+		return nil
+	}
+	j.lastError = redact.Error(err)
+	j.runs++
+	switch {
+	case j.canceled:
+		j.lastResult = JobResultCanceled
+	case err != nil:
+		j.lastResult = JobResultFailed
+		j.failures++
+	default:
+		j.lastResult = JobResultSucceeded
+	}
+	return err
+}
+
+// Wait 等待下一次执行：定时器到期或手动触发时返回 true，ctx 结束时返回 false。暂停期间跳过定时执行
+func (j *Job) Wait(ctx context.Context, tick <-chan time.Time) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-j.trigger:
+			return true
+		case <-tick:
+			j.mu.Lock()
+			j.nextRun = time.Now().Add(j.interval)
+			paused := j.paused
+			j.mu.Unlock()
+			if !paused {
+				return true
+			}
+		}
+	}
+}
+
+// Trigger 立即执行一次任务，任务正在执行时在本次结束后再执行一次
+func (j *Job) Trigger() error {
+	j.mu.Lock()
+	scheduled := j.scheduled
+	j.mu.Unlock()
+	if !scheduled {
+		return ErrJobNotScheduled
+	}
+	select {
+	case j.trigger <- struct{}{}:
+	default:
+		// 已有待执行的触发，合并为一次
+	}
+	return nil
+}
+
+// Pause 暂停定时执行，不影响正在进行的执行
+func (j *Job) Pause() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.paused = true
+}
+
+// Resume 恢复定时执行
+func (j *Job) Resume() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.paused = false
+}
+
+// Cancel 取消正在进行的执行，任务未在执行时返回 ErrJobNotRunning
+func (j *Job) Cancel() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.cancelRun == nil {
+		return ErrJobNotRunning
+	}
+	j.canceled = true
+	j.cancelRun()
+	return nil
+}
+
+// Status 获取任务状态
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := JobStatus{
+		Name:         j.name,
+		Interval:     j.interval,
+		LastDuration: j.lastDuration,
+		LastResult:   j.lastResult,
+		LastError:    j.lastError,
+		Runs:         j.runs,
+		Failures:     j.failures,
+		Skips:        j.skips,
+	}
+	switch {
+	case !j.runStarted.IsZero():
+		status.State = JobStateRunning
+	case !j.scheduled:
+		status.State = JobStateDisabled
+	case j.paused:
+		status.State = JobStatePaused
+	default:
+		status.State = JobStateIdle
+	}
+	if !j.runStarted.IsZero() {
+		started := j.runStarted
+		status.RunStartedAt = &started
+	}
+	if !j.lastRun.IsZero() {
+		lastRun := j.lastRun
+		status.LastRunAt = &lastRun
+	}
+	if j.scheduled && !j.paused && !j.nextRun.IsZero() {
+		nextRun := j.nextRun
+		status.NextRunAt = &nextRun
+	}
+	return status
+}
+
+// JobRegistry 后台任务注册表，Worker 管理器注册 Worker 时收集其定时任务，供管理接口查询和控制
+type JobRegistry struct {
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewJobRegistry 创建后台任务注册表
+func NewJobRegistry() *JobRegistry {
+	return &JobRegistry{jobs: make(map[string]*Job)}
+}
+
+// Register 注册任务，同名任务已存在时返回 ErrJobAlreadyExists
+func (r *JobRegistry) Register(job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.jobs[job.Name()]; exists {
+		return ErrJobAlreadyExists
+	}
+	r.jobs[job.Name()] = job
+	return nil
+}
+
+// Get 按名称获取任务
+func (r *JobRegistry) Get(name string) (*Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// List 获取所有任务的状态，按名称排序
+func (r *JobRegistry) List() []JobStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]JobStatus, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		statuses = append(statuses, job.Status())
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// jobWorker 按间隔定时执行任务的 Worker
+type jobWorker interface {
+	Job() *Job
+}
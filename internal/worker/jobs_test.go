@@ -0,0 +1,149 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pulse/internal/lock"
+)
+
+func TestJob_RunRecordsResult(t *testing.T) {
+	job := NewJob("slo")
+	assert.Equal(t, JobStateDisabled, job.Status().State)
+	assert.ErrorIs(t, job.Trigger(), ErrJobNotScheduled)
+
+	job.Schedule(time.Minute)
+	require.NoError(t, job.Run(context.Background(), func(ctx context.Context) error { return nil }))
+	err := job.Run(context.Background(), func(ctx context.Context) error {
+		return errors.New("dial postgres://pulse:secret@db:5432 failed")
+	})
+	require.Error(t, err)
+
+	status := job.Status()
+	assert.Equal(t, JobStateIdle, status.State)
+	assert.Equal(t, int64(2), status.Runs)
+	assert.Equal(t, int64(1), status.Failures)
+	assert.Equal(t, JobResultFailed, status.LastResult)
+	assert.NotContains(t, status.LastError, "secret")
+	assert.NotNil(t, status.LastRunAt)
+	assert.NotNil(t, status.NextRunAt)
+}
+
+func TestJob_WaitTriggerAndPause(t *testing.T) {
+	job := NewJob("notification_digest")
+	job.Schedule(time.Minute)
+	tick := make(chan time.Time, 1)
+
+	require.NoError(t, job.Trigger())
+	require.NoError(t, job.Trigger())
+	assert.True(t, job.Wait(context.Background(), tick))
+
+	// 暂停期间定时器到期不执行，手动触发仍执行
+	job.Pause()
+	assert.Equal(t, JobStatePaused, job.Status().State)
+	assert.Nil(t, job.Status().NextRunAt)
+	tick <- time.Now()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = job.Trigger()
+	}()
+	assert.True(t, job.Wait(context.Background(), tick))
+	assert.Empty(t, tick)
+
+	job.Resume()
+	tick <- time.Now()
+	assert.True(t, job.Wait(context.Background(), tick))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, job.Wait(ctx, tick))
+}
+
+func TestJob_Cancel(t *testing.T) {
+	job := NewJob("data_retention")
+	job.Schedule(time.Hour)
+	assert.ErrorIs(t, job.Cancel(), ErrJobNotRunning)
+
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- job.Run(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	<-started
+	assert.Equal(t, JobStateRunning, job.Status().State)
+	assert.NotNil(t, job.Status().RunStartedAt)
+	require.NoError(t, job.Cancel())
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	status := job.Status()
+	assert.Equal(t, JobResultCanceled, status.LastResult)
+	assert.Equal(t, int64(0), status.Failures)
+}
+
+func TestJobRegistry(t *testing.T) {
+	registry := NewJobRegistry()
+	require.NoError(t, registry.Register(NewJob("slo")))
+	require.NoError(t, registry.Register(NewJob("alert_snooze")))
+	assert.ErrorIs(t, registry.Register(NewJob("slo")), ErrJobAlreadyExists)
+
+	_, err := registry.Get("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	statuses := registry.List()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "alert_snooze", statuses[0].Name)
+	assert.Equal(t, "slo", statuses[1].Name)
+}
+
+func TestJob_RunWithLocker(t *testing.T) {
+	job := NewJob("slo")
+	job.Schedule(time.Minute)
+
+	held := false
+	var lockedKey string
+	job.SetLocker(func(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+		lockedKey = key
+		if held {
+			return lock.ErrLockNotAcquired
+		}
+		return fn(ctx)
+	})
+
+	ran := 0
+	run := func(ctx context.Context) error {
+		ran++
+		return nil
+	}
+	require.NoError(t, job.Run(context.Background(), run))
+	assert.Equal(t, "job:slo", lockedKey)
+
+	// 其他实例持有任务锁时跳过，不计为失败
+	held = true
+	require.NoError(t, job.Run(context.Background(), run))
+	assert.Equal(t, 1, ran)
+
+	status := job.Status()
+	assert.Equal(t, JobResultSkipped, status.LastResult)
+	assert.Equal(t, int64(1), status.Runs)
+	assert.Equal(t, int64(1), status.Skips)
+	assert.Equal(t, int64(0), status.Failures)
+
+	// 执行期间失去锁计为失败
+	held = false
+	job.SetLocker(func(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+		return errors.Join(lock.ErrLockLost, fn(ctx))
+	})
+	err := job.Run(context.Background(), run)
+	assert.ErrorIs(t, err, lock.ErrLockLost)
+	assert.Equal(t, JobResultFailed, job.Status().LastResult)
+}
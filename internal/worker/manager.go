@@ -16,6 +16,9 @@ type Manager interface {
 	Stop() error
 	GetStatus() map[string]WorkerStatus
 	RegisterWorker(name string, worker Worker) error
+	Jobs() *JobRegistry
+	// UseJobLocker 为所有定时任务设置任务锁，多实例部署时保证同一任务同一时间只在一个实例执行
+	UseJobLocker(locker JobLocker)
 }
 
 // WorkerStatus Worker状态
@@ -40,6 +43,8 @@ type manager struct {
 	serviceManager service.ServiceManager
 	logger         *zap.Logger
 	workers        map[string]Worker
+	jobs           *JobRegistry
+	jobLocker      JobLocker
 	mu             sync.RWMutex
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -52,6 +57,7 @@ func NewManager(serviceManager service.ServiceManager, logger *zap.Logger) Manag
 		serviceManager: serviceManager,
 		logger:         logger,
 		workers:        make(map[string]Worker),
+		jobs:           NewJobRegistry(),
 	}
 }
 
@@ -131,30 +137,194 @@ func (m *manager) RegisterWorker(name string, worker Worker) error {
 		return ErrWorkerAlreadyExists
 	}
 
+	// 按间隔定时执行的 Worker 同时注册其任务，供管理接口查询和控制
+	if jw, ok := worker.(jobWorker); ok && jw.Job() != nil {
+		if err := m.jobs.Register(jw.Job()); err != nil {
+			return err
+		}
+		jw.Job().SetLocker(m.jobLocker)
+	}
+
 	m.workers[name] = worker
 	m.logger.Info("Worker registered", zap.String("name", name))
 	return nil
 }
 
-// registerDefaultWorkers 注册默认的Worker
+// Jobs 获取后台任务注册表
+func (m *manager) Jobs() *JobRegistry {
+	return m.jobs
+}
+
+// UseJobLocker 为已注册和之后注册的定时任务设置任务锁
+func (m *manager) UseJobLocker(locker JobLocker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobLocker = locker
+	for _, worker := range m.workers {
+		if jw, ok := worker.(jobWorker); ok && jw.Job() != nil {
+			jw.Job().SetLocker(locker)
+		}
+	}
+}
+
+// registerDefaultWorkers 注册默认的Worker，每个Worker使用以注册名命名的日志器，便于按模块调整日志级别
 func (m *manager) registerDefaultWorkers() error {
 	// 注册通知Worker
-	notificationWorker := NewNotificationWorker(m.serviceManager, m.logger)
+	notificationWorker := NewNotificationWorker(m.serviceManager, m.logger.Named("notification"))
 	if err := m.RegisterWorker("notification", notificationWorker); err != nil {
 		return err
 	}
 
 	// 注册告警处理Worker
-	alertWorker := NewAlertWorker(m.serviceManager, m.logger)
+	alertWorker := NewAlertWorker(m.serviceManager, m.logger.Named("alert"))
 	if err := m.RegisterWorker("alert", alertWorker); err != nil {
 		return err
 	}
 
 	// 注册数据收集Worker
-	collectorWorker := NewCollectorWorker(m.serviceManager, m.logger)
+	collectorWorker := NewCollectorWorker(m.serviceManager, m.logger.Named("collector"))
 	if err := m.RegisterWorker("collector", collectorWorker); err != nil {
 		return err
 	}
 
+	// 注册附件安全扫描Worker
+	attachmentScanWorker := NewAttachmentScanWorker(m.serviceManager, m.logger.Named("attachment_scan"))
+	if err := m.RegisterWorker("attachment_scan", attachmentScanWorker); err != nil {
+		return err
+	}
+
+	// 注册知识库定时发布Worker
+	knowledgeScheduleWorker := NewKnowledgeScheduleWorker(m.serviceManager, m.logger.Named("knowledge_schedule"))
+	if err := m.RegisterWorker("knowledge_schedule", knowledgeScheduleWorker); err != nil {
+		return err
+	}
+
+	// 注册规则修复动作Worker
+	remediationWorker := NewRemediationWorker(m.serviceManager, m.logger.Named("remediation"))
+	if err := m.RegisterWorker("remediation", remediationWorker); err != nil {
+		return err
+	}
+
+	// 注册外部 ITSM 工单同步Worker
+	itsmSyncWorker := NewITSMSyncWorker(m.serviceManager, m.logger.Named("itsm_sync"))
+	if err := m.RegisterWorker("itsm_sync", itsmSyncWorker); err != nil {
+		return err
+	}
+
+	// 注册心跳监控Worker
+	heartbeatWorker := NewHeartbeatWorker(m.serviceManager, m.logger.Named("heartbeat"))
+	if err := m.RegisterWorker("heartbeat", heartbeatWorker); err != nil {
+		return err
+	}
+
+	// 注册 Kubernetes 数据源采集Worker
+	kubernetesWorker := NewKubernetesWorker(m.serviceManager, m.logger.Named("kubernetes"))
+	if err := m.RegisterWorker("kubernetes", kubernetesWorker); err != nil {
+		return err
+	}
+
+	// 注册 SLO 错误预算计算Worker
+	sloWorker := NewSLOWorker(m.serviceManager, m.logger.Named("slo"))
+	if err := m.RegisterWorker("slo", sloWorker); err != nil {
+		return err
+	}
+
+	// 注册规则预测Worker
+	ruleForecastWorker := NewRuleForecastWorker(m.serviceManager, m.logger.Named("rule_forecast"))
+	if err := m.RegisterWorker("rule_forecast", ruleForecastWorker); err != nil {
+		return err
+	}
+
+	// 注册告警自动解决Worker
+	alertAutoResolveWorker := NewAlertAutoResolveWorker(m.serviceManager, m.logger.Named("alert_auto_resolve"))
+	if err := m.RegisterWorker("alert_auto_resolve", alertAutoResolveWorker); err != nil {
+		return err
+	}
+
+	// 注册告警暂停清理Worker
+	alertSnoozeWorker := NewAlertSnoozeWorker(m.serviceManager, m.logger.Named("alert_snooze"))
+	if err := m.RegisterWorker("alert_snooze", alertSnoozeWorker); err != nil {
+		return err
+	}
+
+	// 注册延迟通知汇总发送Worker
+	notificationDigestWorker := NewNotificationDigestWorker(m.serviceManager, m.logger.Named("notification_digest"))
+	if err := m.RegisterWorker("notification_digest", notificationDigestWorker); err != nil {
+		return err
+	}
+
+	// 注册标签治理任务执行Worker
+	tagGovernanceWorker := NewTagGovernanceWorker(m.serviceManager, m.logger.Named("tag_governance"))
+	if err := m.RegisterWorker("tag_governance", tagGovernanceWorker); err != nil {
+		return err
+	}
+
+	// 注册告警分区维护Worker
+	alertPartitionWorker := NewAlertPartitionWorker(m.serviceManager, m.logger.Named("alert_partition"))
+	if err := m.RegisterWorker("alert_partition", alertPartitionWorker); err != nil {
+		return err
+	}
+
+	// 注册数据保留Worker
+	dataRetentionWorker := NewDataRetentionWorker(m.serviceManager, m.logger.Named("data_retention"))
+	if err := m.RegisterWorker("data_retention", dataRetentionWorker); err != nil {
+		return err
+	}
+
+	// 注册交班报告Worker
+	handoverWorker := NewHandoverWorker(m.serviceManager, m.logger.Named("handover"))
+	if err := m.RegisterWorker("handover", handoverWorker); err != nil {
+		return err
+	}
+
+	// 注册SLA超时通知Worker
+	slaBreachWorker := NewSLABreachWorker(m.serviceManager, m.logger.Named("sla_breach"))
+	if err := m.RegisterWorker("sla_breach", slaBreachWorker); err != nil {
+		return err
+	}
+
+	// 注册出站 Webhook 投递Worker
+	webhookDeliveryWorker := NewWebhookDeliveryWorker(m.serviceManager, m.logger.Named("webhook_delivery"))
+	if err := m.RegisterWorker("webhook_delivery", webhookDeliveryWorker); err != nil {
+		return err
+	}
+
+	// 注册工单导入任务执行Worker
+	ticketImportWorker := NewTicketImportWorker(m.serviceManager, m.logger.Named("ticket_import"))
+	if err := m.RegisterWorker("ticket_import", ticketImportWorker); err != nil {
+		return err
+	}
+
+	// 注册告警统计小时汇总Worker
+	alertStatsRollupWorker := NewAlertStatsRollupWorker(m.serviceManager, m.logger.Named("alert_stats_rollup"))
+	if err := m.RegisterWorker("alert_stats_rollup", alertStatsRollupWorker); err != nil {
+		return err
+	}
+
+	// 注册工单满意度调查Worker
+	ticketSurveyWorker := NewTicketSurveyWorker(m.serviceManager, m.logger.Named("ticket_survey"))
+	if err := m.RegisterWorker("ticket_survey", ticketSurveyWorker); err != nil {
+		return err
+	}
+
+	// 注册状态页订阅通知Worker
+	statusPageWorker := NewStatusPageWorker(m.serviceManager, m.logger.Named("status_page"))
+	if err := m.RegisterWorker("status_page", statusPageWorker); err != nil {
+		return err
+	}
+
+	// 注册异步任务执行Worker
+	asyncTaskWorker := NewAsyncTaskWorker(m.serviceManager, m.logger.Named("async_task"))
+	if err := m.RegisterWorker("async_task", asyncTaskWorker); err != nil {
+		return err
+	}
+
+	// 注册内置指标存储清理Worker
+	metricRetentionWorker := NewMetricRetentionWorker(m.serviceManager, m.logger.Named("metric_retention"))
+	if err := m.RegisterWorker("metric_retention", metricRetentionWorker); err != nil {
+		return err
+	}
+
 	return nil
 }
\ No newline at end of file
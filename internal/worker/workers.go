@@ -22,6 +22,8 @@ type baseWorker struct {
 	mu             sync.RWMutex
 	ctx            context.Context
 	cancel         context.CancelFunc
+	// job 按间隔定时执行的任务，持续运行的 Worker 为 nil
+	job *Job
 }
 
 // Name 获取Worker名称
@@ -43,6 +45,11 @@ func (w *baseWorker) Status() WorkerStatus {
 	}
 }
 
+// Job 获取Worker定时执行的任务，持续运行的 Worker 返回 nil
+func (w *baseWorker) Job() *Job {
+	return w.job
+}
+
 // updateStatus 更新Worker状态
 func (w *baseWorker) updateStatus(status string, err error) {
 	w.mu.Lock()
@@ -210,4 +217,1462 @@ func (w *collectorWorker) Stop() error {
 		w.cancel()
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// attachmentScanWorker 附件安全扫描Worker
+type attachmentScanWorker struct {
+	*baseWorker
+}
+
+// NewAttachmentScanWorker 创建新的附件安全扫描Worker
+func NewAttachmentScanWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &attachmentScanWorker{
+		baseWorker: &baseWorker{
+			name:           "attachment_scan",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "attachment_scan")),
+			status:         "stopped",
+		},
+	}
+}
+
+// Start 启动附件安全扫描Worker
+func (w *attachmentScanWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Attachment scan worker started")
+
+	scanService := w.serviceManager.AttachmentScan()
+
+	// 主循环
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Attachment scan worker stopped")
+			return nil
+		default:
+			if !scanService.Enabled() {
+				w.updateStatus("idle", nil)
+				time.Sleep(30 * time.Second)
+				continue
+			}
+
+			scanned, err := scanService.ScanPending(w.ctx)
+			if err != nil {
+				w.logger.Error("Failed to scan pending attachments", zap.Error(err))
+				w.updateStatus("running", err)
+			} else {
+				w.updateStatus("running", nil)
+			}
+
+			// 本批有处理时立即继续，否则等待下一轮
+			if scanned == 0 {
+				time.Sleep(10 * time.Second)
+			}
+		}
+	}
+}
+
+// Stop 停止附件安全扫描Worker
+func (w *attachmentScanWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+// knowledgeScheduleWorker 知识库定时发布与过期提醒Worker
+type knowledgeScheduleWorker struct {
+	*baseWorker
+}
+
+// NewKnowledgeScheduleWorker 创建新的知识库定时发布Worker
+func NewKnowledgeScheduleWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &knowledgeScheduleWorker{
+		baseWorker: &baseWorker{
+			name:           "knowledge_schedule",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "knowledge_schedule")),
+			status:         "stopped",
+			job:            NewJob("knowledge_schedule"),
+		},
+	}
+}
+
+// Start 启动知识库定时发布Worker
+func (w *knowledgeScheduleWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Knowledge schedule worker started")
+
+	scheduleService := w.serviceManager.KnowledgeSchedule()
+	ticker := time.NewTicker(scheduleService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(scheduleService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			return w.runOnce(ctx, scheduleService)
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Knowledge schedule worker stopped")
+			return nil
+		}
+	}
+}
+
+// runOnce 执行一轮定时发布、过期标记和过期提醒，返回最后一个错误
+func (w *knowledgeScheduleWorker) runOnce(ctx context.Context, scheduleService service.KnowledgeScheduleService) error {
+	var lastErr error
+
+	if _, err := scheduleService.PublishDue(ctx); err != nil {
+		w.logger.Error("Failed to publish scheduled knowledge", zap.Error(err))
+		lastErr = err
+	}
+
+	if _, err := scheduleService.ExpireOverdue(ctx); err != nil {
+		w.logger.Error("Failed to expire overdue knowledge", zap.Error(err))
+		lastErr = err
+	}
+
+	if _, err := scheduleService.SendExpiryWarnings(ctx); err != nil {
+		w.logger.Error("Failed to send knowledge expiry warnings", zap.Error(err))
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// Stop 停止知识库定时发布Worker
+func (w *knowledgeScheduleWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// remediationWorker 规则修复动作Worker，为新告警安排修复动作并执行已批准的动作
+type remediationWorker struct {
+	*baseWorker
+}
+
+// NewRemediationWorker 创建新的规则修复动作Worker
+func NewRemediationWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &remediationWorker{
+		baseWorker: &baseWorker{
+			name:           "remediation",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "remediation")),
+			status:         "stopped",
+			job:            NewJob("remediation"),
+		},
+	}
+}
+
+// Start 启动规则修复动作Worker
+func (w *remediationWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+
+	remediationService := w.serviceManager.Remediation()
+	if !remediationService.Enabled() {
+		w.updateStatus("idle", nil)
+		w.logger.Info("Remediation worker disabled")
+		<-w.ctx.Done()
+		w.updateStatus("stopped", nil)
+		return nil
+	}
+
+	w.updateStatus("running", nil)
+	w.logger.Info("Remediation worker started")
+
+	ticker := time.NewTicker(remediationService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(remediationService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			return w.runOnce(ctx, remediationService)
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Remediation worker stopped")
+			return nil
+		}
+	}
+}
+
+// runOnce 执行一轮修复动作安排和执行，返回最后一个错误
+func (w *remediationWorker) runOnce(ctx context.Context, remediationService service.RemediationService) error {
+	var lastErr error
+
+	if _, err := remediationService.Schedule(ctx); err != nil {
+		w.logger.Error("Failed to schedule remediation actions", zap.Error(err))
+		lastErr = err
+	}
+
+	if _, err := remediationService.ExecuteQueued(ctx); err != nil {
+		w.logger.Error("Failed to execute remediation actions", zap.Error(err))
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// Stop 停止规则修复动作Worker
+func (w *remediationWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// itsmSyncWorker 外部 ITSM 工单同步Worker，将新建或有修改的工单推送到外部系统
+type itsmSyncWorker struct {
+	*baseWorker
+}
+
+// NewITSMSyncWorker 创建新的外部 ITSM 工单同步Worker
+func NewITSMSyncWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &itsmSyncWorker{
+		baseWorker: &baseWorker{
+			name:           "itsm_sync",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "itsm_sync")),
+			status:         "stopped",
+			job:            NewJob("itsm_sync"),
+		},
+	}
+}
+
+// Start 启动外部 ITSM 工单同步Worker
+func (w *itsmSyncWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+
+	itsmService := w.serviceManager.ITSM()
+	if !itsmService.Enabled() {
+		w.updateStatus("idle", nil)
+		w.logger.Info("ITSM sync worker disabled")
+		<-w.ctx.Done()
+		w.updateStatus("stopped", nil)
+		return nil
+	}
+
+	w.updateStatus("running", nil)
+	w.logger.Info("ITSM sync worker started")
+
+	ticker := time.NewTicker(itsmService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(itsmService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			synced, err := itsmService.Sync(ctx)
+			if err != nil {
+				w.logger.Error("Failed to sync tickets to ITSM", zap.Error(err))
+			} else if synced > 0 {
+				w.logger.Info("Synced tickets to ITSM", zap.Int("count", synced))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("ITSM sync worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止外部 ITSM 工单同步Worker
+func (w *itsmSyncWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// heartbeatWorker 心跳监控Worker，检查超时未上报的心跳并触发告警
+type heartbeatWorker struct {
+	*baseWorker
+}
+
+// NewHeartbeatWorker 创建新的心跳监控Worker
+func NewHeartbeatWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &heartbeatWorker{
+		baseWorker: &baseWorker{
+			name:           "heartbeat",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "heartbeat")),
+			status:         "stopped",
+			job:            NewJob("heartbeat"),
+		},
+	}
+}
+
+// Start 启动心跳监控Worker
+func (w *heartbeatWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Heartbeat worker started")
+
+	heartbeatService := w.serviceManager.Heartbeat()
+	ticker := time.NewTicker(heartbeatService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(heartbeatService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			fired, err := heartbeatService.CheckOverdue(ctx)
+			if err != nil {
+				w.logger.Error("Failed to check overdue heartbeats", zap.Error(err))
+			} else if fired > 0 {
+				w.logger.Warn("Heartbeat alerts fired", zap.Int("count", fired))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Heartbeat worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止心跳监控Worker
+func (w *heartbeatWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// kubernetesWorker Kubernetes 数据源采集Worker，将集群的 Warning 事件和异常状况同步为告警
+type kubernetesWorker struct {
+	*baseWorker
+}
+
+// NewKubernetesWorker 创建新的 Kubernetes 数据源采集Worker
+func NewKubernetesWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &kubernetesWorker{
+		baseWorker: &baseWorker{
+			name:           "kubernetes",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "kubernetes")),
+			status:         "stopped",
+			job:            NewJob("kubernetes"),
+		},
+	}
+}
+
+// Start 启动 Kubernetes 数据源采集Worker
+func (w *kubernetesWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Kubernetes worker started")
+
+	kubernetesService := w.serviceManager.Kubernetes()
+	ticker := time.NewTicker(kubernetesService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(kubernetesService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			changed, err := kubernetesService.Sync(ctx)
+			if err != nil {
+				w.logger.Error("Failed to sync Kubernetes data sources", zap.Error(err))
+			} else if changed > 0 {
+				w.logger.Info("Kubernetes alerts synced", zap.Int("count", changed))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Kubernetes worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止 Kubernetes 数据源采集Worker
+func (w *kubernetesWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// sloWorker SLO 错误预算计算Worker
+type sloWorker struct {
+	*baseWorker
+}
+
+// NewSLOWorker 创建新的 SLO 错误预算计算Worker
+func NewSLOWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &sloWorker{
+		baseWorker: &baseWorker{
+			name:           "slo",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "slo")),
+			status:         "stopped",
+			job:            NewJob("slo"),
+		},
+	}
+}
+
+// Start 启动 SLO 错误预算计算Worker
+func (w *sloWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("SLO worker started")
+
+	sloService := w.serviceManager.SLO()
+	ticker := time.NewTicker(sloService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(sloService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			evaluated, err := sloService.Evaluate(ctx)
+			if err != nil {
+				w.logger.Error("Failed to evaluate SLO error budgets", zap.Error(err))
+			} else {
+				w.logger.Debug("SLO error budgets evaluated", zap.Int("count", evaluated))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("SLO worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止 SLO 错误预算计算Worker
+func (w *sloWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// ruleForecastWorker 规则预测Worker
+type ruleForecastWorker struct {
+	*baseWorker
+}
+
+// NewRuleForecastWorker 创建新的规则预测Worker
+func NewRuleForecastWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &ruleForecastWorker{
+		baseWorker: &baseWorker{
+			name:           "rule_forecast",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "rule_forecast")),
+			status:         "stopped",
+			job:            NewJob("rule_forecast"),
+		},
+	}
+}
+
+// Start 启动规则预测Worker
+func (w *ruleForecastWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Rule forecast worker started")
+
+	ruleForecast := w.serviceManager.RuleForecast()
+	ticker := time.NewTicker(ruleForecast.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(ruleForecast.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			firing, err := ruleForecast.Evaluate(ctx)
+			if err != nil {
+				w.logger.Error("Failed to evaluate rule forecasts", zap.Error(err))
+			} else if firing > 0 {
+				w.logger.Info("Rule forecasts predict threshold breaches", zap.Int("count", firing))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Rule forecast worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止规则预测Worker
+func (w *ruleForecastWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// alertAutoResolveWorker 告警自动解决Worker
+type alertAutoResolveWorker struct {
+	*baseWorker
+}
+
+// NewAlertAutoResolveWorker 创建新的告警自动解决Worker
+func NewAlertAutoResolveWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &alertAutoResolveWorker{
+		baseWorker: &baseWorker{
+			name:           "alert_auto_resolve",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "alert_auto_resolve")),
+			status:         "stopped",
+			job:            NewJob("alert_auto_resolve"),
+		},
+	}
+}
+
+// Start 启动告警自动解决Worker
+func (w *alertAutoResolveWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Alert auto-resolve worker started")
+
+	autoResolveService := w.serviceManager.AlertAutoResolve()
+	ticker := time.NewTicker(autoResolveService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(autoResolveService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			resolved, err := autoResolveService.ResolveStale(ctx)
+			if err != nil {
+				w.logger.Error("Failed to resolve stale alerts", zap.Error(err))
+			} else {
+				w.logger.Debug("Stale alerts checked", zap.Int("resolved", resolved))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Alert auto-resolve worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止告警自动解决Worker
+func (w *alertAutoResolveWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// alertSnoozeWorker 告警暂停清理Worker
+type alertSnoozeWorker struct {
+	*baseWorker
+}
+
+// NewAlertSnoozeWorker 创建新的告警暂停清理Worker
+func NewAlertSnoozeWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &alertSnoozeWorker{
+		baseWorker: &baseWorker{
+			name:           "alert_snooze",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "alert_snooze")),
+			status:         "stopped",
+			job:            NewJob("alert_snooze"),
+		},
+	}
+}
+
+// Start 启动告警暂停清理Worker
+func (w *alertSnoozeWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Alert snooze worker started")
+
+	snoozeService := w.serviceManager.AlertSnooze()
+	ticker := time.NewTicker(snoozeService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(snoozeService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			deleted, err := snoozeService.CleanupExpired(ctx)
+			if err != nil {
+				w.logger.Error("Failed to clean up expired alert snoozes", zap.Error(err))
+			} else {
+				w.logger.Debug("Expired alert snoozes cleaned up", zap.Int64("count", deleted))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Alert snooze worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止告警暂停清理Worker
+func (w *alertSnoozeWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// notificationDigestWorker 延迟通知汇总发送Worker
+type notificationDigestWorker struct {
+	*baseWorker
+}
+
+// NewNotificationDigestWorker 创建新的延迟通知汇总发送Worker
+func NewNotificationDigestWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &notificationDigestWorker{
+		baseWorker: &baseWorker{
+			name:           "notification_digest",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "notification_digest")),
+			status:         "stopped",
+			job:            NewJob("notification_digest"),
+		},
+	}
+}
+
+// Start 启动延迟通知汇总发送Worker
+func (w *notificationDigestWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Notification digest worker started")
+
+	calendarService := w.serviceManager.NotificationCalendar()
+	ticker := time.NewTicker(calendarService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(calendarService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			sent, err := calendarService.SendDigests(ctx)
+			if err != nil {
+				w.logger.Error("Failed to send notification digests", zap.Error(err))
+			} else {
+				w.logger.Debug("Notification digests sent", zap.Int("count", sent))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Notification digest worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止延迟通知汇总发送Worker
+func (w *notificationDigestWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// tagGovernanceWorker 标签治理任务执行Worker
+type tagGovernanceWorker struct {
+	*baseWorker
+}
+
+// NewTagGovernanceWorker 创建新的标签治理任务执行Worker
+func NewTagGovernanceWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &tagGovernanceWorker{
+		baseWorker: &baseWorker{
+			name:           "tag_governance",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "tag_governance")),
+			status:         "stopped",
+			job:            NewJob("tag_governance"),
+		},
+	}
+}
+
+// Start 启动标签治理任务执行Worker
+func (w *tagGovernanceWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Tag governance worker started")
+
+	tagService := w.serviceManager.TagGovernance()
+	ticker := time.NewTicker(tagService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(tagService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			ran, err := tagService.RunPendingJobs(ctx)
+			if err != nil {
+				w.logger.Error("Failed to run tag governance jobs", zap.Error(err))
+			} else {
+				w.logger.Debug("Tag governance jobs finished", zap.Int("count", ran))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Tag governance worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止标签治理任务执行Worker
+func (w *tagGovernanceWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// alertPartitionWorker 告警分区维护Worker
+type alertPartitionWorker struct {
+	*baseWorker
+}
+
+// NewAlertPartitionWorker 创建新的告警分区维护Worker
+func NewAlertPartitionWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &alertPartitionWorker{
+		baseWorker: &baseWorker{
+			name:           "alert_partition",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "alert_partition")),
+			status:         "stopped",
+			job:            NewJob("alert_partition"),
+		},
+	}
+}
+
+// Start 启动告警分区维护Worker
+func (w *alertPartitionWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Alert partition worker started")
+
+	partitionService := w.serviceManager.AlertPartition()
+	ticker := time.NewTicker(partitionService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(partitionService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			result, err := partitionService.Maintain(ctx)
+			if err != nil {
+				w.logger.Error("Failed to maintain alert partitions", zap.Error(err))
+			} else {
+				w.logger.Debug("Alert partitions maintained", zap.Int("created", len(result.Created)),
+					zap.Int("dropped", len(result.Dropped)), zap.Int64("purged", result.Purged))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Alert partition worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止告警分区维护Worker
+func (w *alertPartitionWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// dataRetentionWorker 数据保留Worker，归档并清理超出保留期的记录
+type dataRetentionWorker struct {
+	*baseWorker
+}
+
+// NewDataRetentionWorker 创建新的数据保留Worker
+func NewDataRetentionWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &dataRetentionWorker{
+		baseWorker: &baseWorker{
+			name:           "data_retention",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "data_retention")),
+			status:         "stopped",
+			job:            NewJob("data_retention"),
+		},
+	}
+}
+
+// Start 启动数据保留Worker
+func (w *dataRetentionWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Data retention worker started")
+
+	retentionService := w.serviceManager.DataRetention()
+	ticker := time.NewTicker(retentionService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(retentionService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环，启动后等待第一个间隔再执行
+	for w.job.Wait(w.ctx, ticker.C) {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			result, err := retentionService.Run(ctx)
+			if err != nil {
+				w.logger.Error("Failed to apply data retention", zap.Error(err))
+			} else {
+				w.logger.Debug("Data retention applied", zap.Int("archives", result.Archives),
+					zap.Int64("deleted", result.Deleted))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+	}
+
+	w.updateStatus("stopped", nil)
+	w.logger.Info("Data retention worker stopped")
+	return nil
+}
+
+// Stop 停止数据保留Worker
+func (w *dataRetentionWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// handoverWorker 交班报告Worker，在团队的交接时刻生成上一班次的交班报告
+type handoverWorker struct {
+	*baseWorker
+}
+
+// NewHandoverWorker 创建新的交班报告Worker
+func NewHandoverWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &handoverWorker{
+		baseWorker: &baseWorker{
+			name:           "handover",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "handover")),
+			status:         "stopped",
+			job:            NewJob("handover"),
+		},
+	}
+}
+
+// Start 启动交班报告Worker
+func (w *handoverWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Handover worker started")
+
+	handoverService := w.serviceManager.Handover()
+	ticker := time.NewTicker(handoverService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(handoverService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			generated, err := handoverService.RunDue(ctx, time.Now())
+			if err != nil {
+				w.logger.Error("Failed to generate handover reports", zap.Error(err))
+			} else if generated > 0 {
+				w.logger.Info("Handover reports generated", zap.Int("count", generated))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Handover worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止交班报告Worker
+func (w *handoverWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// slaBreachWorker SLA超时通知Worker，向超出 SLA 截止时间的工单处理人投递站内通知
+type slaBreachWorker struct {
+	*baseWorker
+}
+
+// NewSLABreachWorker 创建新的SLA超时通知Worker
+func NewSLABreachWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &slaBreachWorker{
+		baseWorker: &baseWorker{
+			name:           "sla_breach",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "sla_breach")),
+			status:         "stopped",
+			job:            NewJob("sla_breach"),
+		},
+	}
+}
+
+// Start 启动SLA超时通知Worker
+func (w *slaBreachWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("SLA breach worker started")
+
+	inboxService := w.serviceManager.Inbox()
+	ticker := time.NewTicker(inboxService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(inboxService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			notified, err := inboxService.CheckSLABreaches(ctx, time.Now())
+			if err != nil {
+				w.logger.Error("Failed to check SLA breaches", zap.Error(err))
+			} else if notified > 0 {
+				w.logger.Info("SLA breach notifications delivered", zap.Int("count", notified))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("SLA breach worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止SLA超时通知Worker
+func (w *slaBreachWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// webhookDeliveryWorker 出站 Webhook 投递Worker，按顺序投递订阅的告警和工单事件并重试失败的投递
+type webhookDeliveryWorker struct {
+	*baseWorker
+}
+
+// NewWebhookDeliveryWorker 创建新的出站 Webhook 投递Worker
+func NewWebhookDeliveryWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &webhookDeliveryWorker{
+		baseWorker: &baseWorker{
+			name:           "webhook_delivery",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "webhook_delivery")),
+			status:         "stopped",
+			job:            NewJob("webhook_delivery"),
+		},
+	}
+}
+
+// Start 启动出站 Webhook 投递Worker
+func (w *webhookDeliveryWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Webhook delivery worker started")
+
+	subscriptionService := w.serviceManager.WebhookSubscription()
+	ticker := time.NewTicker(subscriptionService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(subscriptionService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			attempted, err := subscriptionService.DeliverPending(ctx)
+			if err != nil {
+				w.logger.Error("Failed to deliver webhook events", zap.Error(err))
+			} else if attempted > 0 {
+				w.logger.Debug("Webhook events delivered", zap.Int("attempts", attempted))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Webhook delivery worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止出站 Webhook 投递Worker
+func (w *webhookDeliveryWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// ticketImportWorker 工单导入任务执行Worker
+type ticketImportWorker struct {
+	*baseWorker
+}
+
+// NewTicketImportWorker 创建新的工单导入任务执行Worker
+func NewTicketImportWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &ticketImportWorker{
+		baseWorker: &baseWorker{
+			name:           "ticket_import",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "ticket_import")),
+			status:         "stopped",
+			job:            NewJob("ticket_import"),
+		},
+	}
+}
+
+// Start 启动工单导入任务执行Worker
+func (w *ticketImportWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Ticket import worker started")
+
+	importService := w.serviceManager.TicketImport()
+	ticker := time.NewTicker(importService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(importService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			ran, err := importService.RunPendingJobs(ctx)
+			if err != nil {
+				w.logger.Error("Failed to run ticket import jobs", zap.Error(err))
+			} else {
+				w.logger.Debug("Ticket import jobs finished", zap.Int("count", ran))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Ticket import worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止工单导入任务执行Worker
+func (w *ticketImportWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// alertStatsRollupWorker 告警统计小时汇总Worker
+type alertStatsRollupWorker struct {
+	*baseWorker
+}
+
+// NewAlertStatsRollupWorker 创建新的告警统计小时汇总Worker
+func NewAlertStatsRollupWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &alertStatsRollupWorker{
+		baseWorker: &baseWorker{
+			name:           "alert_stats_rollup",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "alert_stats_rollup")),
+			status:         "stopped",
+			job:            NewJob("alert_stats_rollup"),
+		},
+	}
+}
+
+// Start 启动告警统计小时汇总Worker
+func (w *alertStatsRollupWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Alert stats rollup worker started")
+
+	statsService := w.serviceManager.AlertStats()
+	ticker := time.NewTicker(statsService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(statsService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			rows, err := statsService.Rollup(ctx)
+			if err != nil {
+				w.logger.Error("Failed to roll up alert stats", zap.Error(err))
+			} else {
+				w.logger.Debug("Alert stats rolled up", zap.Int("rows", rows))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Alert stats rollup worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止告警统计小时汇总Worker
+func (w *alertStatsRollupWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// ticketSurveyWorker 工单满意度调查Worker
+type ticketSurveyWorker struct {
+	*baseWorker
+}
+
+// NewTicketSurveyWorker 创建新的工单满意度调查Worker
+func NewTicketSurveyWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &ticketSurveyWorker{
+		baseWorker: &baseWorker{
+			name:           "ticket_survey",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "ticket_survey")),
+			status:         "stopped",
+			job:            NewJob("ticket_survey"),
+		},
+	}
+}
+
+// Start 启动工单满意度调查Worker
+func (w *ticketSurveyWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Ticket survey worker started")
+
+	surveyService := w.serviceManager.TicketSurvey()
+	ticker := time.NewTicker(surveyService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(surveyService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			sent, err := surveyService.RunDue(ctx)
+			if err != nil {
+				w.logger.Error("Failed to send ticket surveys", zap.Error(err))
+			} else if sent > 0 {
+				w.logger.Info("Ticket surveys sent", zap.Int("count", sent))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Ticket survey worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止工单满意度调查Worker
+func (w *ticketSurveyWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// statusPageWorker 状态页订阅通知Worker
+type statusPageWorker struct {
+	*baseWorker
+}
+
+// NewStatusPageWorker 创建新的状态页订阅通知Worker
+func NewStatusPageWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &statusPageWorker{
+		baseWorker: &baseWorker{
+			name:           "status_page",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "status_page")),
+			status:         "stopped",
+			job:            NewJob("status_page"),
+		},
+	}
+}
+
+// Start 启动状态页订阅通知Worker
+func (w *statusPageWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Status page worker started")
+
+	statusPage := w.serviceManager.StatusPage()
+	ticker := time.NewTicker(statusPage.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(statusPage.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			sent, err := statusPage.NotifyPending(ctx)
+			if err != nil {
+				w.logger.Error("Failed to notify status page subscribers", zap.Error(err))
+			} else if sent > 0 {
+				w.logger.Info("Status page notifications sent", zap.Int("count", sent))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Status page worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止状态页订阅通知Worker
+func (w *statusPageWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// asyncTaskWorker 异步任务执行Worker
+type asyncTaskWorker struct {
+	*baseWorker
+}
+
+// NewAsyncTaskWorker 创建新的异步任务执行Worker
+func NewAsyncTaskWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &asyncTaskWorker{
+		baseWorker: &baseWorker{
+			name:           "async_task",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "async_task")),
+			status:         "stopped",
+			job:            NewJob("async_task"),
+		},
+	}
+}
+
+// Start 启动异步任务执行Worker，每个周期执行所有待执行的任务并清理过期的任务
+func (w *asyncTaskWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Async task worker started")
+
+	taskService := w.serviceManager.AsyncTask()
+	ticker := time.NewTicker(taskService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(taskService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			ran, err := taskService.RunPending(ctx)
+			if err != nil {
+				w.logger.Error("Failed to run async tasks", zap.Error(err))
+				return err
+			}
+			if ran > 0 {
+				w.logger.Info("Async tasks executed", zap.Int("count", ran))
+			}
+
+			deleted, err := taskService.Cleanup(ctx)
+			if err != nil {
+				w.logger.Error("Failed to clean up async tasks", zap.Error(err))
+			} else if deleted > 0 {
+				w.logger.Info("Expired async tasks deleted", zap.Int64("count", deleted))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Async task worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止异步任务执行Worker
+func (w *asyncTaskWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+// metricRetentionWorker 内置指标存储清理Worker，删除超过保留时间的样本和不再上报的序列
+type metricRetentionWorker struct {
+	*baseWorker
+}
+
+// NewMetricRetentionWorker 创建新的内置指标存储清理Worker
+func NewMetricRetentionWorker(serviceManager service.ServiceManager, logger *zap.Logger) Worker {
+	return &metricRetentionWorker{
+		baseWorker: &baseWorker{
+			name:           "metric_retention",
+			serviceManager: serviceManager,
+			logger:         logger.With(zap.String("worker", "metric_retention")),
+			status:         "stopped",
+			job:            NewJob("metric_retention"),
+		},
+	}
+}
+
+// Start 启动内置指标存储清理Worker
+func (w *metricRetentionWorker) Start(ctx context.Context) error {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.startTime = time.Now()
+	w.updateStatus("running", nil)
+
+	w.logger.Info("Metric retention worker started")
+
+	metricService := w.serviceManager.Metric()
+	ticker := time.NewTicker(metricService.Interval())
+	defer ticker.Stop()
+	w.job.Schedule(metricService.Interval())
+	defer w.job.Unschedule()
+
+	// 主循环
+	for {
+		err := w.job.Run(w.ctx, func(ctx context.Context) error {
+			deleted, err := metricService.Cleanup(ctx)
+			if err != nil {
+				w.logger.Error("Failed to clean up expired metric samples", zap.Error(err))
+			} else if deleted > 0 {
+				w.logger.Info("Expired metric samples deleted", zap.Int64("count", deleted))
+			}
+			return err
+		})
+		w.updateStatus("running", err)
+
+		if !w.job.Wait(w.ctx, ticker.C) {
+			w.updateStatus("stopped", nil)
+			w.logger.Info("Metric retention worker stopped")
+			return nil
+		}
+	}
+}
+
+// Stop 停止内置指标存储清理Worker
+func (w *metricRetentionWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+-- 回滚自定义字段定义表
+-- 创建时间: 2024-01-01
+-- 描述: 删除自定义字段定义表
+
+DROP TRIGGER IF EXISTS update_custom_field_definitions_updated_at ON custom_field_definitions;
+DROP TABLE IF EXISTS custom_field_definitions;
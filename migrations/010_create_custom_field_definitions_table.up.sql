@@ -0,0 +1,40 @@
+-- 创建自定义字段定义表
+-- 创建时间: 2024-01-01
+-- 描述: 为工单、告警、知识库的 custom_fields 提供字段定义、类型和校验规则
+
+CREATE TABLE custom_field_definitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+    -- 字段基本信息
+    resource VARCHAR(50) NOT NULL, -- ticket, alert, knowledge
+    field_key VARCHAR(100) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    field_type VARCHAR(50) NOT NULL, -- text, textarea, number, boolean, select, multi_select, date, url
+
+    -- 字段配置
+    options JSONB DEFAULT '[]', -- select/multi_select 可选值
+    validation JSONB DEFAULT '{}', -- 长度、范围、正则等校验规则
+    default_value JSONB,
+    required BOOLEAN NOT NULL DEFAULT false,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+
+    -- 审计字段
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+-- 同一资源下字段键唯一
+CREATE UNIQUE INDEX idx_custom_field_definitions_resource_key
+    ON custom_field_definitions(resource, field_key)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_custom_field_definitions_resource ON custom_field_definitions(resource, sort_order);
+
+CREATE TRIGGER update_custom_field_definitions_updated_at
+    BEFORE UPDATE ON custom_field_definitions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
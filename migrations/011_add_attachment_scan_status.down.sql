@@ -0,0 +1,11 @@
+-- 回滚工单附件病毒扫描状态
+-- 创建时间: 2024-01-01
+-- 描述: 删除附件扫描相关字段
+
+DROP INDEX IF EXISTS idx_ticket_attachments_scan_status;
+
+ALTER TABLE ticket_attachments
+    DROP CONSTRAINT IF EXISTS ticket_attachments_scan_status_check,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_result,
+    DROP COLUMN IF EXISTS scan_status;
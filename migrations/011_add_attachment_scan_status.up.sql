@@ -0,0 +1,15 @@
+-- 为工单附件添加病毒扫描状态
+-- 创建时间: 2024-01-01
+-- 描述: 附件上传后异步扫描，记录扫描状态、结果和扫描时间
+
+ALTER TABLE ticket_attachments
+    ADD COLUMN scan_status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, scanning, clean, infected, error
+    ADD COLUMN scan_result TEXT, -- 病毒特征或扫描错误信息
+    ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE ticket_attachments
+    ADD CONSTRAINT ticket_attachments_scan_status_check
+    CHECK (scan_status IN ('pending', 'scanning', 'clean', 'infected', 'error'));
+
+CREATE INDEX idx_ticket_attachments_scan_status ON ticket_attachments(scan_status)
+    WHERE scan_status IN ('pending', 'scanning');
@@ -0,0 +1,8 @@
+-- 回滚知识库多人审核相关表
+-- 创建时间: 2024-01-01
+-- 描述: 删除审核记录、审核提交和审核策略表
+
+DROP TABLE IF EXISTS knowledge_review_records;
+DROP TABLE IF EXISTS knowledge_review_submissions;
+DROP TRIGGER IF EXISTS update_knowledge_review_policies_updated_at ON knowledge_review_policies;
+DROP TABLE IF EXISTS knowledge_review_policies;
@@ -0,0 +1,76 @@
+-- 创建知识库多人审核相关表
+-- 创建时间: 2024-01-01
+-- 描述: 支持按分类配置审核策略（通过人数、审核人/角色），记录每次提交的审核提交及审核人决定
+
+CREATE TABLE knowledge_review_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+    -- 策略信息
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    category_id UUID, -- 为空表示默认策略
+
+    -- 审核规则
+    required_approvals INTEGER NOT NULL DEFAULT 1,
+    reviewer_ids UUID[] NOT NULL DEFAULT '{}', -- 指定审核人
+    reviewer_roles VARCHAR(50)[] NOT NULL DEFAULT '{}', -- 指定审核角色
+    allow_self_review BOOLEAN NOT NULL DEFAULT false,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+
+    -- 审计字段
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT knowledge_review_policies_approvals_check CHECK (required_approvals >= 1)
+);
+
+-- 每个分类（含默认）只能有一个策略
+CREATE UNIQUE INDEX idx_knowledge_review_policies_category
+    ON knowledge_review_policies(COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid))
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_knowledge_review_policies_updated_at
+    BEFORE UPDATE ON knowledge_review_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE knowledge_review_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    knowledge_id UUID NOT NULL,
+    policy_id UUID REFERENCES knowledge_review_policies(id),
+
+    -- 提交时的策略快照
+    required_approvals INTEGER NOT NULL,
+    reviewer_ids UUID[] NOT NULL DEFAULT '{}',
+    reviewer_roles VARCHAR(50)[] NOT NULL DEFAULT '{}',
+    allow_self_review BOOLEAN NOT NULL DEFAULT false,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected, cancelled
+    submitted_by UUID NOT NULL,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+
+    CONSTRAINT knowledge_review_submissions_status_check
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled'))
+);
+
+-- 每篇文章同时只能有一个待审核提交
+CREATE UNIQUE INDEX idx_knowledge_review_submissions_pending
+    ON knowledge_review_submissions(knowledge_id)
+    WHERE status = 'pending';
+
+CREATE INDEX idx_knowledge_review_submissions_knowledge ON knowledge_review_submissions(knowledge_id, submitted_at DESC);
+
+CREATE TABLE knowledge_review_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    submission_id UUID NOT NULL REFERENCES knowledge_review_submissions(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL,
+    decision VARCHAR(20) NOT NULL, -- approved, rejected
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT knowledge_review_records_decision_check CHECK (decision IN ('approved', 'rejected')),
+    CONSTRAINT knowledge_review_records_unique UNIQUE (submission_id, reviewer_id)
+);
@@ -0,0 +1,8 @@
+-- 回滚知识库评论相关表
+-- 创建时间: 2024-01-01
+-- 描述: 删除评论举报、表情回应和评论表
+
+DROP TABLE IF EXISTS knowledge_comment_flags;
+DROP TABLE IF EXISTS knowledge_comment_reactions;
+DROP TRIGGER IF EXISTS update_knowledge_comments_updated_at ON knowledge_comments;
+DROP TABLE IF EXISTS knowledge_comments;
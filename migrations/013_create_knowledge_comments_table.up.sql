@@ -0,0 +1,57 @@
+-- 创建知识库评论相关表
+-- 创建时间: 2024-01-01
+-- 描述: 支持知识库文章的多级评论、编辑删除、表情回应和举报审核
+
+CREATE TABLE knowledge_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    knowledge_id UUID NOT NULL,
+    parent_id UUID REFERENCES knowledge_comments(id),
+
+    -- 评论内容
+    author_id UUID NOT NULL,
+    content TEXT NOT NULL,
+
+    -- 审核状态
+    status VARCHAR(20) NOT NULL DEFAULT 'visible', -- visible, hidden
+    flag_count INTEGER NOT NULL DEFAULT 0,
+    moderated_by UUID,
+    moderated_at TIMESTAMPTZ,
+    moderation_reason TEXT,
+
+    -- 审计字段
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT knowledge_comments_status_check CHECK (status IN ('visible', 'hidden'))
+);
+
+CREATE INDEX idx_knowledge_comments_knowledge ON knowledge_comments(knowledge_id, created_at);
+CREATE INDEX idx_knowledge_comments_parent ON knowledge_comments(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX idx_knowledge_comments_flagged ON knowledge_comments(flag_count DESC) WHERE flag_count > 0 AND deleted_at IS NULL;
+
+CREATE TRIGGER update_knowledge_comments_updated_at
+    BEFORE UPDATE ON knowledge_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 评论表情回应，每个用户对同一评论的同一表情只能回应一次
+CREATE TABLE knowledge_comment_reactions (
+    comment_id UUID NOT NULL REFERENCES knowledge_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (comment_id, user_id, reaction)
+);
+
+-- 评论举报记录，每个用户对同一评论只能举报一次
+CREATE TABLE knowledge_comment_flags (
+    comment_id UUID NOT NULL REFERENCES knowledge_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (comment_id, user_id)
+);
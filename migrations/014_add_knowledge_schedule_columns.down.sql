@@ -0,0 +1,10 @@
+-- 回滚知识库定时发布与过期提醒
+-- 创建时间: 2024-01-01
+-- 描述: 删除定时发布时间和过期提醒发送时间
+
+DROP INDEX IF EXISTS idx_knowledge_articles_expires_at;
+DROP INDEX IF EXISTS idx_knowledge_articles_publish_at;
+
+ALTER TABLE knowledge_articles
+    DROP COLUMN IF EXISTS expiry_warned_at,
+    DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,14 @@
+-- 知识库定时发布与过期提醒
+-- 创建时间: 2024-01-01
+-- 描述: 为知识库文章增加定时发布时间和过期提醒发送时间
+
+ALTER TABLE knowledge_articles
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS expiry_warned_at TIMESTAMP WITH TIME ZONE;
+
+-- 调度器按到期时间扫描定时发布和即将过期的文章
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_publish_at
+    ON knowledge_articles(publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_expires_at
+    ON knowledge_articles(expires_at) WHERE status = 'published' AND expires_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- 回滚知识库重复检测
+-- 创建时间: 2024-01-01
+-- 描述: 删除知识库文章的三元组索引，pg_trgm 扩展可能被其他对象使用因此保留
+
+DROP INDEX IF EXISTS idx_knowledge_articles_content_trgm;
+DROP INDEX IF EXISTS idx_knowledge_articles_title_trgm;
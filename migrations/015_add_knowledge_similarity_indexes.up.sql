@@ -0,0 +1,12 @@
+-- 知识库重复检测
+-- 创建时间: 2024-01-01
+-- 描述: 启用 pg_trgm 扩展并为知识库文章标题和内容建立三元组索引
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_title_trgm
+    ON knowledge_articles USING GIN (title gin_trgm_ops);
+
+-- 内容只取前 2000 个字符参与相似度计算，避免长文拖慢查询
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_content_trgm
+    ON knowledge_articles USING GIN ((LEFT(content, 2000)) gin_trgm_ops);
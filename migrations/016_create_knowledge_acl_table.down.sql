@@ -0,0 +1,5 @@
+-- 回滚知识库访问控制表
+-- 创建时间: 2024-01-01
+-- 描述: 删除知识库访问控制表
+
+DROP TABLE IF EXISTS knowledge_acl_entries;
@@ -0,0 +1,27 @@
+-- 创建知识库访问控制表
+-- 创建时间: 2024-01-01
+-- 描述: 按分类和文章为角色、团队或用户授予读写权限，列表和搜索查询据此过滤
+
+CREATE TABLE knowledge_acl_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+    -- 受控资源
+    resource_type VARCHAR(20) NOT NULL, -- article, category
+    resource_id VARCHAR(64) NOT NULL,
+
+    -- 授权对象
+    principal_type VARCHAR(20) NOT NULL, -- role, team, user
+    principal_id VARCHAR(100) NOT NULL,
+    permission VARCHAR(20) NOT NULL, -- read, write
+
+    -- 审计字段
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT knowledge_acl_entries_resource_type_check CHECK (resource_type IN ('article', 'category')),
+    CONSTRAINT knowledge_acl_entries_principal_type_check CHECK (principal_type IN ('role', 'team', 'user')),
+    CONSTRAINT knowledge_acl_entries_permission_check CHECK (permission IN ('read', 'write')),
+    CONSTRAINT knowledge_acl_entries_unique UNIQUE (resource_type, resource_id, principal_type, principal_id, permission)
+);
+
+CREATE INDEX idx_knowledge_acl_entries_resource ON knowledge_acl_entries(resource_type, resource_id);
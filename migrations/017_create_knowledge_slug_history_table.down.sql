@@ -0,0 +1,6 @@
+-- 回滚知识库 slug 历史表
+-- 创建时间: 2024-01-01
+-- 描述: 删除 slug 历史表和 slug 唯一索引
+
+DROP TABLE IF EXISTS knowledge_slug_history;
+DROP INDEX IF EXISTS idx_knowledge_articles_slug_unique;
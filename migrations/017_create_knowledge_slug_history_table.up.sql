@@ -0,0 +1,16 @@
+-- 创建知识库 slug 历史表
+-- 创建时间: 2024-01-01
+-- 描述: 文章标题修改后保留旧 slug 用于重定向，并保证 slug 唯一
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_articles_slug_unique
+    ON knowledge_articles(slug)
+    WHERE deleted_at IS NULL AND slug <> '';
+
+-- 旧 slug 到文章的映射，同一个 slug 只能指向一篇文章
+CREATE TABLE knowledge_slug_history (
+    slug VARCHAR(200) PRIMARY KEY,
+    knowledge_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_knowledge_slug_history_knowledge ON knowledge_slug_history(knowledge_id);
@@ -0,0 +1,5 @@
+-- 回滚告警接入集成表
+-- 创建时间: 2024-01-01
+-- 描述: 删除告警接入集成表
+
+DROP TABLE IF EXISTS webhook_integrations;
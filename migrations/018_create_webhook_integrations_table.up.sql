@@ -0,0 +1,25 @@
+-- 创建告警接入集成表
+-- 创建时间: 2024-01-01
+-- 描述: 外部系统推送告警的接入配置，每个集成使用独立的共享密钥签名并可限制来源IP
+
+CREATE TABLE webhook_integrations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    source VARCHAR(20) NOT NULL, -- prometheus, grafana, zabbix, custom, system
+
+    -- 签名密钥（加密存储）和来源IP白名单
+    secret TEXT NOT NULL,
+    allowed_cidrs TEXT[] NOT NULL DEFAULT '{}',
+
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_received_at TIMESTAMPTZ,
+
+    -- 审计字段
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_integrations_created_at ON webhook_integrations(created_at DESC) WHERE deleted_at IS NULL;
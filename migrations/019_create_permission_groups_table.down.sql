@@ -0,0 +1,5 @@
+-- 回滚权限组表
+-- 创建时间: 2024-01-01
+-- 描述: 删除权限组表
+
+DROP TABLE IF EXISTS permission_groups;
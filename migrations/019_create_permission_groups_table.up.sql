@@ -0,0 +1,18 @@
+-- 创建权限组表
+-- 创建时间: 2024-01-01
+-- 描述: 权限组（角色）定义，内置角色由 migrate -action seed 写入
+
+CREATE TABLE permission_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    permissions JSONB NOT NULL DEFAULT '[]',
+
+    -- 审计字段
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_permission_groups_name ON permission_groups(name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_permission_groups_created_at ON permission_groups(created_at DESC) WHERE deleted_at IS NULL;
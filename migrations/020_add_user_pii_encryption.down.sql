@@ -0,0 +1,14 @@
+-- 回滚用户敏感信息加密存储
+-- 创建时间: 2024-01-01
+-- 描述: 删除盲索引列，回滚前需关闭 ENCRYPT_USER_PII 并确认用户数据已解密
+
+DROP INDEX IF EXISTS idx_users_phone_index;
+DROP INDEX IF EXISTS idx_users_email_index;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS phone_index,
+    DROP COLUMN IF EXISTS email_index,
+    ALTER COLUMN phone TYPE VARCHAR(20),
+    ALTER COLUMN email TYPE VARCHAR(255),
+    ADD CONSTRAINT users_email_format CHECK (email ~* '^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$'),
+    ADD CONSTRAINT users_phone_format CHECK (phone IS NULL OR phone ~* '^\+?[1-9]\d{1,14}$');
@@ -0,0 +1,15 @@
+-- 用户敏感信息加密存储
+-- 创建时间: 2024-01-01
+-- 描述: 邮箱和手机号可加密存储，密文长度超出原字段限制且无法通过格式校验；
+--       通过 HMAC 盲索引按邮箱、手机号查询和校验唯一性
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_email_format,
+    DROP CONSTRAINT IF EXISTS users_phone_format,
+    ALTER COLUMN email TYPE TEXT,
+    ALTER COLUMN phone TYPE TEXT,
+    ADD COLUMN email_index VARCHAR(64),
+    ADD COLUMN phone_index VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_users_email_index ON users(email_index) WHERE deleted_at IS NULL AND email_index IS NOT NULL;
+CREATE INDEX idx_users_phone_index ON users(phone_index) WHERE deleted_at IS NULL AND phone_index IS NOT NULL;
@@ -0,0 +1,5 @@
+-- 回滚功能开关表
+-- 创建时间: 2024-01-01
+-- 描述: 删除功能开关表
+
+DROP TABLE IF EXISTS feature_flags;
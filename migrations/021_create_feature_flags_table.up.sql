@@ -0,0 +1,21 @@
+-- 创建功能开关表
+-- 创建时间: 2024-01-01
+-- 描述: 运行时功能开关，按租户白名单、用户白名单和灰度比例逐步放量高风险功能
+
+CREATE TABLE feature_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    flag_key VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+
+    -- 灰度规则
+    rollout_percentage INTEGER NOT NULL DEFAULT 0 CHECK (rollout_percentage BETWEEN 0 AND 100),
+    tenants TEXT[] NOT NULL DEFAULT '{}',
+    users TEXT[] NOT NULL DEFAULT '{}',
+
+    -- 审计字段
+    created_by UUID,
+    updated_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
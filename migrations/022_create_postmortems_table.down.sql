@@ -0,0 +1,6 @@
+-- 回滚事后复盘表
+-- 创建时间: 2024-01-01
+-- 描述: 删除事后复盘及改进项表
+
+DROP TABLE IF EXISTS postmortem_action_items;
+DROP TABLE IF EXISTS postmortems;
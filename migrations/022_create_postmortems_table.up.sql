@@ -0,0 +1,56 @@
+-- 创建事后复盘表
+-- 创建时间: 2024-01-01
+-- 描述: 事件复盘记录及其改进项，改进项以工单跟踪完成情况，复盘可发布到知识库
+
+CREATE TABLE postmortems (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- draft, in_review, published
+    template VARCHAR(50) NOT NULL DEFAULT 'standard',
+
+    -- 关联的事件工单和告警
+    incident_id UUID,
+    alert_ids TEXT[] NOT NULL DEFAULT '{}',
+
+    -- 复盘内容
+    summary TEXT NOT NULL DEFAULT '',
+    impact TEXT NOT NULL DEFAULT '',
+    root_cause TEXT NOT NULL DEFAULT '',
+    timeline JSONB NOT NULL DEFAULT '[]',
+    incident_started_at TIMESTAMPTZ,
+    incident_ended_at TIMESTAMPTZ,
+
+    -- 发布到知识库
+    knowledge_id UUID,
+    published_at TIMESTAMPTZ,
+
+    -- 审计字段
+    created_by UUID NOT NULL,
+    updated_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_postmortems_status ON postmortems(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_postmortems_incident_id ON postmortems(incident_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_postmortems_alert_ids_gin ON postmortems USING GIN(alert_ids);
+
+-- 复盘改进项，每项对应一个跟踪工单
+CREATE TABLE postmortem_action_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    postmortem_id UUID NOT NULL REFERENCES postmortems(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    priority VARCHAR(20) NOT NULL DEFAULT 'medium',
+    assignee_id UUID,
+    due_date TIMESTAMPTZ,
+    ticket_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, done, cancelled
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_postmortem_action_items_postmortem_id ON postmortem_action_items(postmortem_id);
+CREATE INDEX idx_postmortem_action_items_ticket_id ON postmortem_action_items(ticket_id);
@@ -0,0 +1,5 @@
+-- 回滚修复动作执行记录表
+-- 创建时间: 2024-01-01
+-- 描述: 删除修复动作执行记录表
+
+DROP TABLE IF EXISTS remediation_executions;
@@ -0,0 +1,35 @@
+-- 创建修复动作执行记录表
+-- 创建时间: 2024-01-01
+-- 描述: 规则上配置的修复动作（HTTP 调用、AWX 作业、SaltStack 命令）针对告警的执行记录和日志
+
+CREATE TABLE remediation_executions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL,
+    rule_id UUID NOT NULL,
+
+    -- 动作在规则 actions 中的位置，以及安排时的动作配置快照
+    action_index INTEGER NOT NULL,
+    action_name VARCHAR(255) NOT NULL,
+    action_type VARCHAR(20) NOT NULL, -- http, awx, salt
+    action JSONB NOT NULL DEFAULT '{}',
+
+    -- 执行状态和日志
+    status VARCHAR(20) NOT NULL DEFAULT 'queued', -- pending_approval, queued, running, succeeded, failed, rejected
+    output TEXT NOT NULL DEFAULT '',
+    error TEXT,
+
+    -- 审批
+    approved_by UUID,
+    approved_at TIMESTAMPTZ,
+
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- 同一告警的同一动作只安排一次
+    UNIQUE (alert_id, rule_id, action_index)
+);
+
+CREATE INDEX idx_remediation_executions_alert_id ON remediation_executions(alert_id);
+CREATE INDEX idx_remediation_executions_queued ON remediation_executions(created_at) WHERE status = 'queued';
@@ -0,0 +1,5 @@
+-- 回滚聊天用户映射表
+-- 创建时间: 2024-01-01
+-- 描述: 删除聊天用户映射表
+
+DROP TABLE IF EXISTS chat_user_mappings;
@@ -0,0 +1,19 @@
+-- 创建聊天用户映射表
+-- 创建时间: 2024-01-01
+-- 描述: Slack/钉钉用户与平台用户的映射，ChatOps 命令以映射到的平台用户身份执行
+
+CREATE TABLE chat_user_mappings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    platform VARCHAR(20) NOT NULL, -- slack, dingtalk
+    chat_user_id VARCHAR(100) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- 同一聊天平台用户只能绑定一个平台用户
+    UNIQUE (platform, chat_user_id)
+);
+
+CREATE INDEX idx_chat_user_mappings_user_id ON chat_user_mappings(user_id);
@@ -0,0 +1,7 @@
+-- 回滚外部 ITSM 同步表
+-- 创建时间: 2024-01-01
+-- 描述: 删除 ITSM 同步连接器、工单关联和评论关联表
+
+DROP TABLE IF EXISTS itsm_comment_links;
+DROP TABLE IF EXISTS itsm_ticket_links;
+DROP TABLE IF EXISTS itsm_connectors;
@@ -0,0 +1,68 @@
+-- 创建外部 ITSM 同步表
+-- 创建时间: 2024-01-01
+-- 描述: Jira/ServiceNow 同步连接器、平台工单与外部工单的关联，以及已同步评论的对应关系
+
+CREATE TABLE itsm_connectors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    provider VARCHAR(20) NOT NULL, -- jira, servicenow
+
+    -- 外部系统连接信息，API 令牌和 Webhook 密钥加密存储
+    base_url VARCHAR(500) NOT NULL,
+    username VARCHAR(200) NOT NULL DEFAULT '',
+    api_token TEXT NOT NULL,
+    webhook_secret TEXT NOT NULL,
+
+    -- 同步范围和规则：Jira 项目键或 ServiceNow 表名，空的工单类型表示同步全部类型
+    project VARCHAR(100) NOT NULL,
+    ticket_types TEXT[] NOT NULL DEFAULT '{}',
+    field_mapping JSONB NOT NULL DEFAULT '{}',
+    conflict_strategy VARCHAR(20) NOT NULL DEFAULT 'latest_wins', -- local_wins, remote_wins, latest_wins
+    sync_comments BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- 外部变更写回平台时使用的用户
+    sync_user_id UUID NOT NULL REFERENCES users(id),
+
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_synced_at TIMESTAMPTZ,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE itsm_ticket_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    connector_id UUID NOT NULL REFERENCES itsm_connectors(id) ON DELETE CASCADE,
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+
+    remote_id VARCHAR(100) NOT NULL,
+    remote_key VARCHAR(100) NOT NULL,
+    remote_url VARCHAR(500) NOT NULL DEFAULT '',
+
+    -- 工单 updated_at 晚于 last_synced_at 时需要推送到外部系统
+    last_synced_at TIMESTAMPTZ NOT NULL,
+    remote_updated_at TIMESTAMPTZ,
+    last_error TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (connector_id, ticket_id),
+    UNIQUE (connector_id, remote_id)
+);
+
+CREATE INDEX idx_itsm_ticket_links_ticket_id ON itsm_ticket_links(ticket_id);
+
+-- 已同步的评论，避免重复推送和回环
+CREATE TABLE itsm_comment_links (
+    connector_id UUID NOT NULL REFERENCES itsm_connectors(id) ON DELETE CASCADE,
+    comment_id UUID NOT NULL,
+    ticket_id UUID NOT NULL,
+    remote_comment_id VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (connector_id, comment_id),
+    UNIQUE (connector_id, remote_comment_id)
+);
+
+CREATE INDEX idx_itsm_comment_links_ticket_id ON itsm_comment_links(connector_id, ticket_id);
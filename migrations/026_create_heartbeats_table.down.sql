@@ -0,0 +1,5 @@
+-- 回滚心跳检查表
+-- 创建时间: 2024-01-01
+-- 描述: 删除心跳检查表
+
+DROP TABLE IF EXISTS heartbeats;
@@ -0,0 +1,26 @@
+-- 创建心跳检查表
+-- 创建时间: 2024-01-01
+-- 描述: 外部定时任务按周期上报心跳，超过上报周期加宽限时间未上报时触发告警
+
+CREATE TABLE heartbeats (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    token VARCHAR(64) NOT NULL UNIQUE,
+    interval_seconds INTEGER NOT NULL CHECK (interval_seconds > 0),
+    grace_seconds INTEGER NOT NULL DEFAULT 0 CHECK (grace_seconds >= 0),
+    severity VARCHAR(20) NOT NULL DEFAULT 'high',
+    labels JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'new', -- new, up, down, paused
+    last_ping_at TIMESTAMPTZ,
+    -- 最晚上报时间，上报或修改周期时重新计算
+    expected_at TIMESTAMPTZ NOT NULL,
+    ping_count BIGINT NOT NULL DEFAULT 0,
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 超时检查只扫描未暂停且未告警的心跳
+CREATE INDEX idx_heartbeats_expected_at ON heartbeats(expected_at) WHERE status IN ('new', 'up');
@@ -0,0 +1,3 @@
+-- 回滚 Kubernetes 数据源类型
+-- 创建时间: 2024-01-01
+-- 描述: PostgreSQL 不支持删除枚举值，保留 kubernetes 类型，已创建的 Kubernetes 数据源需手动删除
@@ -0,0 +1,5 @@
+-- 新增 Kubernetes 数据源类型
+-- 创建时间: 2024-01-01
+-- 描述: Kubernetes 数据源采集集群的 Warning 事件和节点、Pod 异常状况并同步为告警
+
+ALTER TYPE datasource_type ADD VALUE IF NOT EXISTS 'kubernetes';
@@ -0,0 +1,6 @@
+-- 回滚服务目录表
+-- 创建时间: 2024-01-01
+-- 描述: 删除服务目录表和告警的服务标签索引
+
+DROP INDEX IF EXISTS idx_alerts_service_label;
+DROP TABLE IF EXISTS catalog_services;
@@ -0,0 +1,25 @@
+-- 创建服务目录表
+-- 创建时间: 2024-01-01
+-- 描述: 服务目录记录服务的负责团队、等级、依赖和标签选择器，告警和工单按标签选择器归属到服务
+
+CREATE TABLE catalog_services (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    owner_team VARCHAR(100) NOT NULL,
+    tier VARCHAR(20) NOT NULL DEFAULT 'tier3', -- tier1, tier2, tier3, tier4
+    -- 依赖的服务名称
+    dependencies JSONB NOT NULL DEFAULT '[]',
+    -- 标签选择器列表，任一选择器匹配即归属到该服务
+    label_selectors JSONB NOT NULL DEFAULT '[]',
+    slo_target DOUBLE PRECISION NOT NULL DEFAULT 99.9 CHECK (slo_target > 0 AND slo_target < 100),
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_catalog_services_owner_team ON catalog_services(owner_team);
+
+-- 按服务统计 SLO 时按 service 标签查询告警
+CREATE INDEX idx_alerts_service_label ON alerts ((labels->>'service'), starts_at);
@@ -0,0 +1,5 @@
+-- 回滚 SLO 表
+-- 创建时间: 2024-01-01
+-- 描述: 删除 SLO 表，自动生成的告警规则保留在规则表中
+
+DROP TABLE IF EXISTS slos;
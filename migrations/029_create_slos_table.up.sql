@@ -0,0 +1,34 @@
+-- 创建 SLO 表
+-- 创建时间: 2024-01-01
+-- 描述: SLO 定义了服务的可用性目标和基于 Prometheus 的 SLI 查询，定期计算错误预算并自动生成消耗速率告警规则
+
+CREATE TABLE slos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    service_id UUID NOT NULL REFERENCES catalog_services(id),
+    data_source_id UUID NOT NULL,
+    -- SLI 查询，{{.window}} 为时间窗口占位符
+    error_query TEXT NOT NULL,
+    total_query TEXT NOT NULL,
+    target DOUBLE PRECISION NOT NULL CHECK (target > 0 AND target < 100),
+    window_days INTEGER NOT NULL DEFAULT 30 CHECK (window_days BETWEEN 1 AND 90),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- 自动生成的消耗速率告警规则
+    page_rule_id UUID,
+    ticket_rule_id UUID,
+
+    -- 最近一次错误预算计算结果
+    budget_status VARCHAR(20) NOT NULL DEFAULT 'unknown', -- unknown, healthy, warning, exhausted
+    error_ratio DOUBLE PRECISION,
+    budget_remaining DOUBLE PRECISION,
+    burn_rate DOUBLE PRECISION,
+    evaluated_at TIMESTAMPTZ,
+    last_error TEXT,
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_slos_service_id ON slos(service_id);
@@ -0,0 +1,5 @@
+-- 回滚告警接入集成载荷映射
+-- 创建时间: 2024-01-01
+-- 描述: 删除载荷映射字段
+
+ALTER TABLE webhook_integrations DROP COLUMN IF EXISTS payload_mapping;
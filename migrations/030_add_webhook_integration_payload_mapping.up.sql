@@ -0,0 +1,5 @@
+-- 为告警接入集成添加载荷映射
+-- 创建时间: 2024-01-01
+-- 描述: 自定义 Webhook 使用 JSONPath 映射将任意结构的载荷转换为告警，为空时集成只接收标准格式的告警
+
+ALTER TABLE webhook_integrations ADD COLUMN payload_mapping JSONB;
@@ -0,0 +1,5 @@
+-- 回滚告警暂停表
+-- 创建时间: 2024-01-01
+-- 描述: 删除告警暂停表
+
+DROP TABLE IF EXISTS alert_snoozes;
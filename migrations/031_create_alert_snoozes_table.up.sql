@@ -0,0 +1,20 @@
+-- 创建告警暂停表
+-- 创建时间: 2024-01-01
+-- 描述: 用户可暂停关注单条告警或标签匹配的一组告警，暂停期间告警不出现在该用户的默认告警列表中，也不向该用户发送通知。
+--       与全局静默不同，暂停只对创建它的用户生效，到期后由 Worker 清理
+
+CREATE TABLE alert_snoozes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- 暂停单条告警时为告警ID，暂停一组告警时为空并使用 matchers
+    alert_id UUID REFERENCES alerts(id) ON DELETE CASCADE,
+    -- 标签匹配条件，告警标签包含全部条件时匹配
+    matchers JSONB NOT NULL DEFAULT '{}',
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (alert_id IS NOT NULL OR matchers <> '{}'::jsonb)
+);
+
+CREATE INDEX idx_alert_snoozes_user_expires ON alert_snoozes(user_id, expires_at);
+CREATE INDEX idx_alert_snoozes_expires_at ON alert_snoozes(expires_at);
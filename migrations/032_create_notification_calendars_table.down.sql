@@ -0,0 +1,6 @@
+-- 回滚通知日历表和延迟通知表
+-- 创建时间: 2024-01-01
+-- 描述: 删除延迟通知表和通知日历表，未发送的延迟通知一并删除
+
+DROP TABLE IF EXISTS deferred_notifications;
+DROP TABLE IF EXISTS notification_calendars;
@@ -0,0 +1,37 @@
+-- 创建通知日历表和延迟通知表
+-- 创建时间: 2024-01-01
+-- 描述: 通知日历按团队和地区定义工作时间和节假日，非工作时间内低级别告警的通知延迟到下一个工作时间开始时汇总发送，
+--       严重告警始终立即通知。工作时间的结构与工单 SLA 的 business_hours 相同
+
+CREATE TABLE notification_calendars (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    -- 匹配告警的 team 和 region 标签，为空时匹配任意值
+    team VARCHAR(100) NOT NULL DEFAULT '',
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    -- 工作时间，如 {"timezone": "Asia/Shanghai", "days": ["monday", "friday"], "start": "09:00", "end": "18:00"}
+    business_hours JSONB NOT NULL,
+    -- 节假日，格式为 YYYY-MM-DD
+    holidays JSONB NOT NULL DEFAULT '[]',
+    -- 非工作时间延迟通知的告警级别，critical 不允许延迟
+    defer_severities JSONB NOT NULL DEFAULT '[]',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (team, region)
+);
+
+CREATE TABLE deferred_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    calendar_id UUID NOT NULL REFERENCES notification_calendars(id) ON DELETE CASCADE,
+    alert_id UUID,
+    type VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    -- 汇总发送的时间，即下一个工作时间开始
+    deliver_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_deferred_notifications_deliver_at ON deferred_notifications(deliver_at);
@@ -0,0 +1,5 @@
+-- 回滚语言偏好表
+-- 创建时间: 2024-01-01
+-- 描述: 删除语言偏好表
+
+DROP TABLE IF EXISTS language_preferences;
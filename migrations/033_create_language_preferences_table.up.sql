@@ -0,0 +1,14 @@
+-- 创建语言偏好表
+-- 创建时间: 2024-01-01
+-- 描述: 用户和团队的语言偏好，用于选择通知消息和枚举显示名的语言。
+--       通知优先使用接收人的语言，其次使用告警所属团队的语言，都未设置时使用默认语言
+
+CREATE TABLE language_preferences (
+    -- user 或 team
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('user', 'team')),
+    -- scope 为 user 时为用户ID，为 team 时为团队名
+    subject VARCHAR(100) NOT NULL,
+    language VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, subject)
+);
@@ -0,0 +1,5 @@
+-- 回滚标签治理任务表
+-- 创建时间: 2024-01-01
+-- 描述: 删除标签治理任务表
+
+DROP TABLE IF EXISTS tag_jobs;
@@ -0,0 +1,24 @@
+-- 创建标签治理任务表
+-- 创建时间: 2024-01-01
+-- 描述: 管理员发起的标签重命名、合并和删除任务。任务会修改大量告警、工单和知识库文章，
+--       由 Worker 在后台分批执行并记录进度
+
+CREATE TABLE tag_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('rename', 'merge', 'delete')),
+    -- 被重命名、合并或删除的标签
+    tag VARCHAR(255) NOT NULL,
+    -- 重命名后的标签或合并到的标签，删除任务为空
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    -- 开始执行时统计的待处理记录数和已处理记录数
+    total BIGINT NOT NULL DEFAULT 0,
+    processed BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_tag_jobs_status_created_at ON tag_jobs(status, created_at);
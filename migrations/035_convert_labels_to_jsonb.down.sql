@@ -0,0 +1,5 @@
+-- 回滚标签和注解列的 GIN 索引
+-- 创建时间: 2024-01-01
+-- 描述: 删除本次新增的 GIN 索引。其余索引在建表迁移中已存在，列类型保持 JSONB 不回滚
+
+DROP INDEX IF EXISTS idx_heartbeats_labels_gin;
@@ -0,0 +1,40 @@
+-- 将标签和注解列统一为 JSONB 并创建 GIN 索引
+-- 创建时间: 2024-01-01
+-- 描述: 早期部署中部分标签和注解列以 TEXT 或 JSON 保存，按标签过滤只能逐行解析。
+--       统一转换为 JSONB 并补齐 GIN 索引，仓储层的标签过滤使用 @> 包含查询以命中索引
+
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND (table_name, column_name) IN (
+              ('alerts', 'labels'), ('alerts', 'annotations'),
+              ('rules', 'labels'), ('rules', 'annotations'),
+              ('data_sources', 'labels'), ('data_sources', 'annotations'),
+              ('tickets', 'labels'),
+              ('heartbeats', 'labels')
+          )
+          AND data_type <> 'jsonb'
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I DROP DEFAULT', col.table_name, col.column_name);
+        EXECUTE format(
+            'ALTER TABLE %I ALTER COLUMN %I TYPE JSONB USING COALESCE(NULLIF(%I::text, ''''), ''{}'')::jsonb',
+            col.table_name, col.column_name, col.column_name
+        );
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I SET DEFAULT ''{}''::jsonb', col.table_name, col.column_name);
+    END LOOP;
+END $$;
+
+-- GIN 索引支持 @>、? 和 ?| 查询
+CREATE INDEX IF NOT EXISTS idx_alerts_labels_gin ON alerts USING GIN(labels);
+CREATE INDEX IF NOT EXISTS idx_alerts_annotations_gin ON alerts USING GIN(annotations);
+CREATE INDEX IF NOT EXISTS idx_rules_labels_gin ON rules USING GIN(labels);
+CREATE INDEX IF NOT EXISTS idx_rules_annotations_gin ON rules USING GIN(annotations);
+CREATE INDEX IF NOT EXISTS idx_data_sources_labels_gin ON data_sources USING GIN(labels);
+CREATE INDEX IF NOT EXISTS idx_data_sources_annotations_gin ON data_sources USING GIN(annotations);
+CREATE INDEX IF NOT EXISTS idx_tickets_labels_gin ON tickets USING GIN(labels);
+CREATE INDEX IF NOT EXISTS idx_heartbeats_labels_gin ON heartbeats USING GIN(labels);
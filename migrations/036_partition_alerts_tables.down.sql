@@ -0,0 +1,40 @@
+-- 回滚告警表和告警历史表的按月分区
+-- 创建时间: 2024-01-01
+-- 描述: 将分区表转换回普通表，主键恢复为 id。删除的外键和唯一约束不恢复
+
+-- unpartition 将分区表转换为普通表
+CREATE FUNCTION pg_temp.unpartition(tbl TEXT) RETURNS VOID AS $$
+DECLARE
+    legacy TEXT := tbl || '_partitioned';
+    rec RECORD;
+    index_defs TEXT[] := '{}';
+    index_def TEXT;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass(tbl)) THEN
+        RETURN;
+    END IF;
+
+    FOR rec IN
+        SELECT pg_get_indexdef(indexrelid) AS def FROM pg_index
+        WHERE indrelid = to_regclass(tbl) AND NOT indisprimary
+    LOOP
+        index_defs := index_defs || rec.def;
+    END LOOP;
+
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', tbl, legacy);
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS)',
+        tbl, legacy
+    );
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', tbl, legacy);
+    EXECUTE format('DROP TABLE %I CASCADE', legacy);
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (id)', tbl);
+
+    FOREACH index_def IN ARRAY index_defs LOOP
+        EXECUTE replace(index_def, ' ON ONLY ', ' ON ');
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT pg_temp.unpartition('alert_histories');
+SELECT pg_temp.unpartition('alerts');
@@ -0,0 +1,82 @@
+-- 将告警表和告警历史表改为按月分区
+-- 创建时间: 2024-01-01
+-- 描述: 告警和告警历史按 created_at 按月范围分区，Worker 提前创建后续月份的分区，
+--       清理时直接删除过期分区而不是对整表执行 DELETE。
+--       分区表的主键和唯一索引必须包含分区列：主键改为 (id, created_at)，原有唯一索引改为普通索引，
+--       引用告警表的外键删除，关联关系由应用保证。超出已建分区范围的数据写入默认分区
+
+-- partition_monthly 将普通表转换为按 col 按月分区的表，并创建从最早数据到当前月份之后 premake 个月的分区
+CREATE FUNCTION pg_temp.partition_monthly(tbl TEXT, col TEXT, premake INT) RETURNS VOID AS $$
+DECLARE
+    legacy TEXT := tbl || '_legacy';
+    rec RECORD;
+    index_defs TEXT[] := '{}';
+    index_def TEXT;
+    first_month DATE;
+    month DATE;
+BEGIN
+    -- 已经是分区表时跳过
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass(tbl)) THEN
+        RETURN;
+    END IF;
+
+    FOR rec IN
+        SELECT conname, conrelid::regclass AS rel FROM pg_constraint
+        WHERE confrelid = to_regclass(tbl) AND contype = 'f'
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', rec.rel, rec.conname);
+    END LOOP;
+
+    -- 记录主键以外的索引定义，删除原表后在分区表上重建
+    FOR rec IN
+        SELECT pg_get_indexdef(indexrelid) AS def FROM pg_index
+        WHERE indrelid = to_regclass(tbl) AND NOT indisprimary
+    LOOP
+        index_defs := index_defs || replace(rec.def, 'CREATE UNIQUE INDEX', 'CREATE INDEX');
+    END LOOP;
+
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', tbl, legacy);
+    EXECUTE format('UPDATE %I SET %I = NOW() WHERE %I IS NULL', legacy, col, col);
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS) PARTITION BY RANGE (%I)',
+        tbl, legacy, col
+    );
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN %I SET NOT NULL', tbl, col);
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (id, %I)', tbl, col);
+
+    EXECUTE format('SELECT date_trunc(''month'', MIN(%I))::date FROM %I', col, legacy) INTO first_month;
+    month := COALESCE(first_month, date_trunc('month', NOW())::date);
+    WHILE month <= (date_trunc('month', NOW()) + make_interval(months => premake))::date LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+            tbl || '_p' || to_char(month, 'YYYYMM'), tbl, month, (month + INTERVAL '1 month')::date
+        );
+        month := (month + INTERVAL '1 month')::date;
+    END LOOP;
+    EXECUTE format('CREATE TABLE %I PARTITION OF %I DEFAULT', tbl || '_default', tbl);
+
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', tbl, legacy);
+    EXECUTE format('DROP TABLE %I CASCADE', legacy);
+
+    FOREACH index_def IN ARRAY index_defs LOOP
+        EXECUTE index_def;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 告警历史表在部分部署中尚未创建
+CREATE TABLE IF NOT EXISTS alert_histories (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    old_value JSONB,
+    new_value JSONB,
+    user_id UUID,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_histories_alert_id ON alert_histories(alert_id, created_at);
+
+SELECT pg_temp.partition_monthly('alerts', 'created_at', 3);
+SELECT pg_temp.partition_monthly('alert_histories', 'created_at', 3);
@@ -0,0 +1,6 @@
+-- 回滚数据归档表
+-- 创建时间: 2024-01-01
+-- 描述: 删除数据归档表和归档恢复记录表，对象存储中的归档文件不会删除
+
+DROP TABLE IF EXISTS data_archive_restores;
+DROP TABLE IF EXISTS data_archives;
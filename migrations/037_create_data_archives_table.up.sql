@@ -0,0 +1,34 @@
+-- 创建数据归档表
+-- 创建时间: 2024-01-01
+-- 描述: 超出保留期的告警、告警历史和通知记录导出为 gzip 压缩的 NDJSON 保存到对象存储后再删除，
+--       data_archives 记录每个归档文件包含的时间窗口，用于查询和恢复。
+--       data_archive_restores 记录从归档恢复的时间窗口，到期前窗口内恢复的记录不会被重新归档
+
+CREATE TABLE data_archives (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- alerts、alert_histories 或 notifications
+    resource VARCHAR(50) NOT NULL,
+    object_key VARCHAR(1024) NOT NULL UNIQUE,
+    -- 归档记录中最早和最晚的创建时间
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end TIMESTAMPTZ NOT NULL,
+    record_count BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_data_archives_resource_window ON data_archives(resource, window_start, window_end);
+
+CREATE TABLE data_archive_restores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resource VARCHAR(50) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end TIMESTAMPTZ NOT NULL,
+    archives INTEGER NOT NULL DEFAULT 0,
+    restored_records BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_data_archive_restores_resource_expires_at ON data_archive_restores(resource, expires_at);
@@ -0,0 +1,5 @@
+-- 回滚分派策略表
+-- 创建时间: 2024-01-01
+-- 描述: 删除分派策略表，已分派的工单和告警不受影响
+
+DROP TABLE IF EXISTS assignment_policies;
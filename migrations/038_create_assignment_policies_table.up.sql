@@ -0,0 +1,19 @@
+-- 创建分派策略表
+-- 创建时间: 2024-01-01
+-- 描述: 按团队配置工单和告警的自动分派策略（轮询、按未关闭工单负载、按技能标签匹配），
+--       自动创建的工单和告警以及升级重新分派时按所属团队的策略选择处理人。
+--       members 保存成员及其技能标签，rr_counter 为轮询计数，每次轮询分派原子递增
+
+CREATE TABLE assignment_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- 团队名，与工单 team_name 和告警 team 标签对应
+    team VARCHAR(100) NOT NULL UNIQUE,
+    -- round_robin、least_load 或 skill_match
+    strategy VARCHAR(20) NOT NULL,
+    members JSONB NOT NULL DEFAULT '[]',
+    rr_counter BIGINT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- 回滚交班报告表
+-- 创建时间: 2024-01-01
+-- 描述: 删除交班报告和交接排班表，已保存的知识库草稿不会删除
+
+DROP TABLE IF EXISTS handover_reports;
+DROP TABLE IF EXISTS handover_schedules;
@@ -0,0 +1,39 @@
+-- 创建交班报告表
+-- 创建时间: 2024-01-01
+-- 描述: handover_schedules 按团队配置每天的班次交接时刻和团队频道，到达交接时刻时汇总上一班次
+--       未恢复的严重告警、处理中的工单、新发生的事件和待处理事项，发送到团队频道并保存为知识库草稿。
+--       handover_reports 保存生成的交班报告，同一排班的同一交接时刻只生成一次，多实例时由唯一约束去重
+
+CREATE TABLE handover_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team VARCHAR(100) NOT NULL UNIQUE,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    -- 每天的交接时刻，如 ["09:00", "21:00"]
+    shift_starts JSONB NOT NULL DEFAULT '[]',
+    -- 团队频道的通知类型和接收人，为空时只保存知识库草稿
+    channel_type VARCHAR(20),
+    recipient VARCHAR(500) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- 最近一次生成交班报告的交接时刻
+    last_handover_at TIMESTAMPTZ,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE handover_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- 手动生成的报告没有排班
+    schedule_id UUID REFERENCES handover_schedules(id) ON DELETE SET NULL,
+    team VARCHAR(100) NOT NULL,
+    shift_start TIMESTAMPTZ NOT NULL,
+    shift_end TIMESTAMPTZ NOT NULL,
+    summary JSONB NOT NULL DEFAULT '{}',
+    knowledge_id UUID,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (schedule_id, shift_end)
+);
+
+CREATE INDEX idx_handover_reports_team_shift_end ON handover_reports(team, shift_end DESC);
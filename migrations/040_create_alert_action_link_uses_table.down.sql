@@ -0,0 +1,5 @@
+-- 回滚告警操作链接使用记录表
+-- 创建时间: 2024-01-01
+-- 描述: 删除使用记录后，尚未过期的已使用链接可以再次使用
+
+DROP TABLE IF EXISTS alert_action_link_uses;
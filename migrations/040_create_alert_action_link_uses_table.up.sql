@@ -0,0 +1,14 @@
+-- 创建告警操作链接使用记录表
+-- 创建时间: 2024-01-01
+-- 描述: 告警通知中的一键操作链接（确认、解决、暂停）携带签名令牌，接收人无需登录即可执行操作。
+--       每个令牌只能使用一次，执行前按令牌 ID 插入使用记录，主键冲突表示链接已被使用
+
+CREATE TABLE alert_action_link_uses (
+    token_id VARCHAR(64) PRIMARY KEY,
+    alert_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL, -- ack, resolve, snooze
+    used_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_action_link_uses_alert_id ON alert_action_link_uses(alert_id);
@@ -0,0 +1,6 @@
+-- 回滚移动端推送设备和推送偏好表
+-- 创建时间: 2024-01-01
+-- 描述: PostgreSQL 不支持删除枚举值，保留 push 通知渠道类型
+
+DROP TABLE IF EXISTS push_preferences;
+DROP TABLE IF EXISTS push_devices;
@@ -0,0 +1,25 @@
+-- 创建移动端推送设备和推送偏好表
+-- 创建时间: 2024-01-01
+-- 描述: 用户在移动端应用中注册 FCM 或 APNs 设备令牌，告警通知按用户的推送偏好选择推送的告警级别。
+--       同一设备令牌只属于最后注册它的用户，推送服务返回令牌失效时删除设备
+
+ALTER TYPE notification_channel_type ADD VALUE IF NOT EXISTS 'push';
+
+CREATE TABLE push_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(20) NOT NULL, -- fcm, apns
+    token VARCHAR(1024) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_push_devices_user_id ON push_devices(user_id);
+
+CREATE TABLE push_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    -- 推送的告警级别，为空数组时不推送告警
+    severities VARCHAR(20)[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
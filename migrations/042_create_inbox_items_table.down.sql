@@ -0,0 +1,4 @@
+-- 回滚站内通知收件箱表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS inbox_items;
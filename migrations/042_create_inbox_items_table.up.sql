@@ -0,0 +1,23 @@
+-- 创建站内通知收件箱表
+-- 创建时间: 2024-01-01
+-- 描述: 告警或工单分派、评论中被 @ 提及、工单超出 SLA 时向用户投递站内通知，记录已读状态。
+--       seq 单调递增，实时推送按 seq 断点续传；同一用户相同 dedupe_key 的通知只投递一次
+
+CREATE TABLE inbox_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    seq BIGSERIAL NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL, -- assigned, mentioned, sla_breach
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    resource_type VARCHAR(20) NOT NULL, -- alert, ticket, knowledge
+    resource_id VARCHAR(100) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    dedupe_key VARCHAR(255),
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, dedupe_key)
+);
+
+CREATE INDEX idx_inbox_items_user_seq ON inbox_items(user_id, seq DESC);
+CREATE INDEX idx_inbox_items_unread ON inbox_items(user_id) WHERE read_at IS NULL;
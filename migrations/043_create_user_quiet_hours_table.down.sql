@@ -0,0 +1,4 @@
+-- 回滚用户免打扰设置表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS user_quiet_hours;
@@ -0,0 +1,14 @@
+-- 创建用户免打扰设置表
+-- 创建时间: 2024-01-01
+-- 描述: 用户配置每周的免打扰时段和临时免打扰，免打扰期间只通知允许级别的告警。
+--       通知服务在按渠道发送之前统一检查接收人的免打扰设置
+
+CREATE TABLE user_quiet_hours (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    -- 免打扰时段：[{"days": ["monday"], "start": "23:00", "end": "07:00", "allow_severities": ["critical"]}]
+    windows JSONB NOT NULL DEFAULT '[]',
+    dnd_until TIMESTAMPTZ,
+    dnd_allow_severities VARCHAR(20)[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
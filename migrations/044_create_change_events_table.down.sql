@@ -0,0 +1,4 @@
+-- 回滚变更事件表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS change_events;
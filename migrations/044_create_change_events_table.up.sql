@@ -0,0 +1,21 @@
+-- 创建变更事件表
+-- 创建时间: 2024-01-01
+-- 描述: 记录发布部署、配置变更和功能开关切换等变更事件。告警创建时关联触发前窗口内
+--       同一服务最近的变更，作为可能原因写入告警注解
+
+CREATE TABLE change_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    service VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    version VARCHAR(100) NOT NULL DEFAULT '',
+    source VARCHAR(100) NOT NULL DEFAULT '',
+    url TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_change_events_service_occurred_at ON change_events(service, occurred_at DESC);
+CREATE INDEX idx_change_events_occurred_at ON change_events(occurred_at DESC);
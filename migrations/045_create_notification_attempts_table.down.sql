@@ -0,0 +1,4 @@
+-- 回滚通知投递记录表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS notification_attempts;
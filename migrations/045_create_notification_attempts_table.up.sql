@@ -0,0 +1,23 @@
+-- 创建通知投递记录表
+-- 创建时间: 2024-01-01
+-- 描述: 记录每条通知的每次投递，包括渠道、目标、内容摘要、结果和渠道响应，手动重发也追加记录。
+--       作为审计记录保留，不随通知记录一起删除
+
+CREATE TABLE notification_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL,
+    alert_id UUID,
+    channel VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    payload_hash CHAR(64) NOT NULL,
+    -- sent 或 failed
+    status VARCHAR(20) NOT NULL,
+    provider_response TEXT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    resent_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_attempts_notification_id ON notification_attempts(notification_id, attempted_at);
+CREATE INDEX idx_notification_attempts_alert_id ON notification_attempts(alert_id, attempted_at);
@@ -0,0 +1,5 @@
+-- 回滚出站 Webhook 订阅表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,51 @@
+-- 创建出站 Webhook 订阅表
+-- 创建时间: 2024-01-01
+-- 描述: 外部系统订阅告警和工单事件，事件发生时为每个匹配的订阅写入一条投递记录，
+--       由 Worker 按 seq 顺序逐个投递，失败后按指数退避重试，连续多个事件投递失败后自动停用订阅
+
+CREATE TABLE webhook_subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    url TEXT NOT NULL,
+    -- 签名密钥，加密存储
+    secret TEXT NOT NULL,
+    -- 订阅的事件模式，如 alert.*、ticket.created
+    events TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- 为 0 时使用全局配置
+    max_attempts INTEGER NOT NULL DEFAULT 0,
+    disable_after_failures INTEGER NOT NULL DEFAULT 0,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_reason TEXT,
+    last_delivered_at TIMESTAMPTZ,
+    -- 正在投递的实例持有的租约，避免多个实例同时投递同一订阅而打乱顺序
+    delivering_until TIMESTAMPTZ,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    seq BIGSERIAL NOT NULL,
+    -- 同一事件投递给不同订阅时相同
+    event_id UUID NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    -- 签名的请求体，按原样保存以便重试时签名一致
+    payload TEXT NOT NULL,
+    -- pending、delivered 或 failed
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_response TEXT,
+    last_error TEXT,
+    last_duration_ms BIGINT NOT NULL DEFAULT 0,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(subscription_id, seq) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription_seq ON webhook_deliveries(subscription_id, seq DESC);
@@ -0,0 +1,32 @@
+-- 创建工单导入任务表
+-- 创建时间: 2024-01-01
+-- 描述: 从旧工单系统导出的 CSV 或 XLSX 文件批量导入工单。上传后确认列映射，
+--       由 Worker 在后台逐行校验并创建工单，记录进度和行错误
+
+CREATE TABLE ticket_import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    status VARCHAR(20) NOT NULL DEFAULT 'awaiting_mapping'
+        CHECK (status IN ('awaiting_mapping', 'pending', 'running', 'completed', 'failed')),
+    -- 文件的表头和工单字段到表头的映射
+    headers JSONB NOT NULL DEFAULT '[]',
+    mapping JSONB NOT NULL DEFAULT '{}',
+    -- 试运行只校验不创建工单
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    -- 上传的文件内容，执行时读取
+    content BYTEA NOT NULL,
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    -- 行错误，最多记录 1000 条
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_ticket_import_jobs_status_created_at ON ticket_import_jobs(status, created_at);
@@ -0,0 +1,5 @@
+-- 回滚告警统计小时汇总表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS alert_stats_rollup_state;
+DROP TABLE IF EXISTS alert_stats_hourly;
@@ -0,0 +1,25 @@
+-- 创建告警统计小时汇总表
+-- 创建时间: 2024-01-01
+-- 描述: 按告警开始时间所在的小时、严重级别、状态、规则和团队物化告警数，
+--       由 Worker 根据告警更新时间增量重算受影响的小时，统计和趋势接口直接读取汇总表
+
+CREATE TABLE alert_stats_hourly (
+    bucket TIMESTAMPTZ NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    -- 没有规则或没有 team 标签的告警记为空字符串
+    rule_id VARCHAR(36) NOT NULL DEFAULT '',
+    team VARCHAR(255) NOT NULL DEFAULT '',
+    count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bucket, severity, status, rule_id, team)
+);
+
+-- 汇总进度，只有一行。watermark 为已处理到的告警更新时间，为空时下次汇总重建全部小时
+CREATE TABLE alert_stats_rollup_state (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    watermark TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO alert_stats_rollup_state (id) VALUES (1);
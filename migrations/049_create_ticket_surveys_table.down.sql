@@ -0,0 +1,4 @@
+-- 回滚工单满意度调查表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS ticket_surveys;
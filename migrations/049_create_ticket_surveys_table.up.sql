@@ -0,0 +1,22 @@
+-- 创建工单满意度调查表
+-- 创建时间: 2024-01-01
+-- 描述: 工单关闭后向报告人发送满意度评价链接，保存评分和意见，按团队和处理人统计 CSAT。
+--       团队和处理人在发送时从工单快照，令牌只保存 SHA-256 哈希
+
+CREATE TABLE ticket_surveys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- 每个工单只发送一次调查，重新打开后再次关闭不重复发送
+    ticket_id UUID NOT NULL UNIQUE REFERENCES tickets(id) ON DELETE CASCADE,
+    reporter_id VARCHAR(255) NOT NULL,
+    assignee_id VARCHAR(255),
+    team VARCHAR(100) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    score SMALLINT CHECK (score BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_ticket_surveys_sent_at ON ticket_surveys(sent_at);
+CREATE INDEX idx_ticket_surveys_team_sent_at ON ticket_surveys(team, sent_at);
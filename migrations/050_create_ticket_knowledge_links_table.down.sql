@@ -0,0 +1,11 @@
+-- 回滚工单引用知识库文章表
+-- 创建时间: 2024-01-01
+
+DROP INDEX IF EXISTS idx_knowledge_articles_quality_score;
+
+ALTER TABLE knowledge_articles
+    DROP COLUMN IF EXISTS quality_score,
+    DROP COLUMN IF EXISTS not_useful_count,
+    DROP COLUMN IF EXISTS useful_count;
+
+DROP TABLE IF EXISTS ticket_knowledge_links;
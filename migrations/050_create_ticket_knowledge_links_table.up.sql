@@ -0,0 +1,36 @@
+-- 创建工单引用知识库文章表
+-- 创建时间: 2024-01-01
+-- 描述: 记录工单处理过程中引用的知识库文章，工单解决时提示解决人评价文章是否有用，
+--       评价汇总到 knowledge_articles 的有用数、无用数和质量分，搜索结果按质量分提升排序
+
+CREATE TABLE ticket_knowledge_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    knowledge_id VARCHAR(64) NOT NULL,
+    linked_by VARCHAR(255) NOT NULL,
+
+    -- 工单解决时提示评价的用户，每个引用只提示一次
+    resolver_id VARCHAR(255),
+    prompted_at TIMESTAMPTZ,
+
+    -- 评价结果，可以修改
+    useful BOOLEAN,
+    comment TEXT NOT NULL DEFAULT '',
+    rated_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT ticket_knowledge_links_unique UNIQUE (ticket_id, knowledge_id)
+);
+
+CREATE INDEX idx_ticket_knowledge_links_knowledge_id ON ticket_knowledge_links(knowledge_id);
+CREATE INDEX idx_ticket_knowledge_links_pending ON ticket_knowledge_links(resolver_id)
+    WHERE prompted_at IS NOT NULL AND rated_at IS NULL;
+
+-- 文章质量分，没有评价时为 0.5
+ALTER TABLE knowledge_articles
+    ADD COLUMN IF NOT EXISTS useful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS not_useful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS quality_score DOUBLE PRECISION NOT NULL DEFAULT 0.5;
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_quality_score ON knowledge_articles(quality_score);
@@ -0,0 +1,4 @@
+-- 回滚敏感操作审批请求表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS approval_requests;
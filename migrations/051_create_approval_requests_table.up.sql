@@ -0,0 +1,28 @@
+-- 创建敏感操作审批请求表
+-- 创建时间: 2024-01-01
+-- 描述: 删除数据源、禁用全部规则、批量关闭工单等敏感操作配置为需要审批时，先保存为待审批请求，
+--       由申请人以外的管理员批准后执行。每次状态变更同时写入 user_audit_logs 作为审计记录
+
+CREATE TABLE approval_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action VARCHAR(50) NOT NULL,
+    params JSONB,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'expired', 'executed', 'failed')),
+    requester_id VARCHAR(255) NOT NULL,
+    requires_approval BOOLEAN NOT NULL DEFAULT TRUE,
+    approver_id VARCHAR(255),
+    decision_comment TEXT NOT NULL DEFAULT '',
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    decided_at TIMESTAMPTZ,
+    executed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- 申请人不能审批自己的请求
+    CHECK (approver_id IS NULL OR NOT requires_approval OR approver_id <> requester_id)
+);
+
+CREATE INDEX idx_approval_requests_status_created_at ON approval_requests(status, created_at DESC);
+CREATE INDEX idx_approval_requests_requester_id ON approval_requests(requester_id, created_at DESC);
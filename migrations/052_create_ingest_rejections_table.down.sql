@@ -0,0 +1,4 @@
+-- 回滚告警接入拒绝记录表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS ingest_rejections;
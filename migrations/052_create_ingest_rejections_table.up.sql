@@ -0,0 +1,18 @@
+-- 创建告警接入拒绝记录表
+-- 创建时间: 2024-01-01
+-- 描述: 记录签名校验通过但载荷格式不符合要求的接入请求及拒绝原因，按采样率保存原始载荷，
+--       用于排查上游推送格式异常。每个集成只保留最近的若干条记录
+
+CREATE TABLE ingest_rejections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    integration_id UUID NOT NULL REFERENCES webhook_integrations(id) ON DELETE CASCADE,
+    endpoint VARCHAR(20) NOT NULL CHECK (endpoint IN ('alerts', 'custom')),
+    reason VARCHAR(30) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    payload TEXT,
+    payload_size INTEGER NOT NULL DEFAULT 0,
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ingest_rejections_integration_received_at ON ingest_rejections(integration_id, received_at DESC);
@@ -0,0 +1,4 @@
+-- 回滚异步任务表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS async_tasks;
@@ -0,0 +1,28 @@
+-- 创建异步任务表
+-- 创建时间: 2024-01-01
+-- 描述: 导出知识库、批量检查数据源等耗时操作提交为异步任务，由 Worker 领取执行，调用方轮询任务获取进度和结果。
+--       结果文件保存在 result_file 中，结束超过保留时间的任务由 Worker 清理
+
+CREATE TABLE async_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(50) NOT NULL,
+    params JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    total BIGINT NOT NULL DEFAULT 0,
+    processed BIGINT NOT NULL DEFAULT 0,
+    result JSONB,
+    result_file BYTEA,
+    result_file_name VARCHAR(255) NOT NULL DEFAULT '',
+    result_file_type VARCHAR(100) NOT NULL DEFAULT '',
+    result_file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_async_tasks_status_created_at ON async_tasks(status, created_at);
+CREATE INDEX idx_async_tasks_created_by ON async_tasks(created_by, created_at DESC);
+CREATE INDEX idx_async_tasks_finished_at ON async_tasks(finished_at) WHERE finished_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- 回滚规则评估延迟记录
+-- 创建时间: 2024-01-01
+
+ALTER TABLE rules
+    DROP COLUMN IF EXISTS last_eval_scheduled_at,
+    DROP COLUMN IF EXISTS last_eval_delay_ms,
+    DROP COLUMN IF EXISTS missed_evals;
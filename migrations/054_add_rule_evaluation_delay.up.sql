@@ -0,0 +1,9 @@
+-- 为规则添加评估延迟记录
+-- 创建时间: 2024-01-01
+-- 描述: 每次评估记录计划评估时间（上次评估时间加评估间隔）和实际评估时间的差值，
+--       missed_evals 为连续错过至少一个评估周期的评估次数，按时评估后清零，自监控据此发现评估器过载
+
+ALTER TABLE rules
+    ADD COLUMN IF NOT EXISTS last_eval_scheduled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS last_eval_delay_ms BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS missed_evals INTEGER NOT NULL DEFAULT 0;
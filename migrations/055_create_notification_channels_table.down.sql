@@ -0,0 +1,4 @@
+-- 回滚团队通知渠道表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,26 @@
+-- 创建团队通知渠道表
+-- 创建时间: 2024-01-01
+-- 描述: 团队自行维护的邮件、钉钉、企业微信、Slack 和 Webhook 通知渠道，
+--       团队告警的通知优先使用团队启用的同类型渠道，没有时使用配置文件中的全局渠道
+
+CREATE TABLE notification_channels (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    -- 对应告警的 team 标签
+    team VARCHAR(100) NOT NULL,
+    -- email、dingtalk、wechat、slack 或 webhook
+    type VARCHAR(20) NOT NULL,
+    -- 机器人或 Webhook 地址，加密存储
+    url TEXT NOT NULL DEFAULT '',
+    -- 钉钉加签密钥或 SMTP 登录密码，加密存储
+    secret TEXT NOT NULL DEFAULT '',
+    -- 邮件渠道的 SMTP 服务器配置
+    smtp JSONB,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT notification_channels_team_name_unique UNIQUE (team, name)
+);
+
+CREATE INDEX idx_notification_channels_team_type ON notification_channels(team, type) WHERE enabled;
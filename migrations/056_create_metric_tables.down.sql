@@ -0,0 +1,5 @@
+-- 回滚内置指标存储表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS metric_samples;
+DROP TABLE IF EXISTS metric_series;
@@ -0,0 +1,28 @@
+-- 创建内置指标存储表
+-- 创建时间: 2024-01-01
+-- 描述: 采集端通过 Prometheus remote-write 协议推送的指标，序列的标签只存储一次，
+--       样本按序列ID和时间戳存储，超过保留时间的样本和不再上报的序列由 Worker 定期清理
+
+CREATE TABLE metric_series (
+    id BIGSERIAL PRIMARY KEY,
+    metric VARCHAR(255) NOT NULL,
+    -- 除 __name__ 以外的标签
+    labels JSONB NOT NULL DEFAULT '{}',
+    -- 指标名和排序后的标签的 SHA-256，写入时按指纹查找已有的序列
+    fingerprint CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT metric_series_fingerprint_unique UNIQUE (fingerprint)
+);
+
+CREATE INDEX idx_metric_series_metric ON metric_series(metric);
+CREATE INDEX idx_metric_series_last_seen_at ON metric_series(last_seen_at);
+
+CREATE TABLE metric_samples (
+    series_id BIGINT NOT NULL REFERENCES metric_series(id) ON DELETE CASCADE,
+    ts TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (series_id, ts)
+);
+
+CREATE INDEX idx_metric_samples_ts ON metric_samples(ts);
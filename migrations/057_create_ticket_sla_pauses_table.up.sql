@@ -0,0 +1,20 @@
+-- 创建工单SLA暂停表
+-- 创建时间: 2024-01-01
+-- 描述: 工单等待客户或第三方回复期间的SLA暂停区间，暂停期间不判定超时，
+--       恢复时 tickets.sla_deadline 顺延暂停的时长，响应和解决耗时不含暂停时长
+
+CREATE TABLE ticket_sla_pauses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    -- status：进入等待状态时自动暂停；manual：手动暂停
+    source VARCHAR(20) NOT NULL,
+    reason TEXT,
+    paused_by VARCHAR(255),
+    paused_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resumed_by VARCHAR(255),
+    resumed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_ticket_sla_pauses_ticket_id ON ticket_sla_pauses(ticket_id, paused_at);
+-- 每个工单同一时间最多一个进行中的暂停，超时检测据此排除暂停中的工单
+CREATE UNIQUE INDEX idx_ticket_sla_pauses_open ON ticket_sla_pauses(ticket_id) WHERE resumed_at IS NULL;
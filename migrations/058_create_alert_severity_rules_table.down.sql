@@ -0,0 +1,4 @@
+-- 回滚告警重新分级规则表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS alert_severity_rules;
@@ -0,0 +1,28 @@
+-- 创建告警重新分级规则表
+-- 创建时间: 2024-01-01
+-- 描述: 告警接入时按标签、服务等级和时段升级或降级告警级别（如 staging 环境的告警降为 info），
+--       在分派和通知路由之前执行，按 position 升序匹配，第一条匹配的规则生效
+
+CREATE TABLE alert_severity_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    -- 告警标签需要全部等于的值
+    matchers JSONB NOT NULL DEFAULT '{}',
+    -- 告警归属服务的等级，如 ["tier3","tier4"]
+    service_tiers JSONB NOT NULL DEFAULT '[]',
+    -- 生效时段及其时区
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    windows JSONB NOT NULL DEFAULT '[]',
+    -- 设置的目标级别，为空时按 adjust 升级（正数）或降级（负数）
+    severity VARCHAR(20),
+    adjust INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT alert_severity_rules_action_check CHECK ((severity IS NULL) <> (adjust = 0))
+);
+
+CREATE INDEX idx_alert_severity_rules_position ON alert_severity_rules(position, created_at) WHERE enabled;
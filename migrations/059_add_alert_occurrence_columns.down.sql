@@ -0,0 +1,8 @@
+-- 回滚告警重复触发计数
+-- 创建时间: 2024-01-01
+-- 描述: 删除重复触发计数相关字段
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS occurrence_sampled_at,
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS occurrence_count;
@@ -0,0 +1,19 @@
+-- 为告警添加重复触发计数
+-- 创建时间: 2024-01-01
+-- 描述: 指纹相同的告警在未解决期间再次触发时不再插入新行，而是累加已有告警的触发次数并更新最近触发时间，
+--       触发历史按采样间隔记入告警历史，occurrence_sampled_at 为最近一次采样的时间
+
+ALTER TABLE alerts
+    ADD COLUMN occurrence_count BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN last_seen_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN occurrence_sampled_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE alerts SET last_seen_at = created_at;
+
+ALTER TABLE alerts
+    ALTER COLUMN last_seen_at SET DEFAULT NOW(),
+    ALTER COLUMN last_seen_at SET NOT NULL;
+
+COMMENT ON COLUMN alerts.occurrence_count IS '告警未解决期间的触发次数，首次触发计为 1';
+COMMENT ON COLUMN alerts.last_seen_at IS '最近一次触发的时间';
+COMMENT ON COLUMN alerts.occurrence_sampled_at IS '最近一次记入告警历史的触发时间';
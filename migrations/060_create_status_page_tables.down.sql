@@ -0,0 +1,6 @@
+-- 回滚状态页事件和订阅者表
+-- 创建时间: 2024-01-01
+
+DROP TABLE IF EXISTS status_subscribers;
+DROP TABLE IF EXISTS status_incident_updates;
+DROP TABLE IF EXISTS status_incidents;
@@ -0,0 +1,50 @@
+-- 创建状态页事件和订阅者表
+-- 创建时间: 2024-01-01
+-- 描述: 公开状态页展示的故障和计划维护及其进展说明，组件为服务目录中的服务名称；
+--       订阅者确认邮件中的链接后，发布进展时由 Worker 邮件通知，确认和退订链接为订阅者 ID 的签名
+
+CREATE TABLE status_incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('incident', 'maintenance')),
+    title VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    -- 故障的影响程度，计划维护为空
+    impact VARCHAR(20) NOT NULL DEFAULT '',
+    -- 受影响的组件（服务目录中的服务名称），如 ["checkout","postgres"]
+    components JSONB NOT NULL DEFAULT '[]',
+    -- 关联的内部事件工单，不在公开状态页展示
+    ticket_id UUID REFERENCES tickets(id) ON DELETE SET NULL,
+    scheduled_start TIMESTAMPTZ,
+    scheduled_end TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_status_incidents_open ON status_incidents(created_at) WHERE resolved_at IS NULL;
+CREATE INDEX idx_status_incidents_resolved_at ON status_incidents(resolved_at) WHERE resolved_at IS NOT NULL;
+
+CREATE TABLE status_incident_updates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_id UUID NOT NULL REFERENCES status_incidents(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+    -- 是否邮件通知订阅者，notified_at 为通知时间
+    notify BOOLEAN NOT NULL DEFAULT TRUE,
+    notified_at TIMESTAMPTZ,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_status_incident_updates_incident_id ON status_incident_updates(incident_id, created_at);
+CREATE INDEX idx_status_incident_updates_pending ON status_incident_updates(created_at) WHERE notify AND notified_at IS NULL;
+
+CREATE TABLE status_subscribers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email VARCHAR(255) NOT NULL UNIQUE,
+    -- 订阅的组件，为空时订阅全部组件
+    components JSONB NOT NULL DEFAULT '[]',
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+-- 回滚告警分诊评分
+-- 创建时间: 2024-01-01
+-- 描述: 删除分诊评分相关字段
+
+DROP INDEX IF EXISTS idx_alerts_triage_score;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS triage_assignee,
+    DROP COLUMN IF EXISTS triage_score;
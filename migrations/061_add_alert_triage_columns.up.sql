@@ -0,0 +1,13 @@
+-- 为告警添加分诊评分
+-- 创建时间: 2024-01-01
+-- 描述: 新告警创建时调用外部分诊服务，保存优先级评分和建议的处理人，评分服务不可用时为空，
+--       告警列表可按评分排序，过滤表达式可按评分和建议处理人过滤
+
+ALTER TABLE alerts
+    ADD COLUMN triage_score DOUBLE PRECISION,
+    ADD COLUMN triage_assignee VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_triage_score ON alerts(triage_score DESC NULLS LAST) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN alerts.triage_score IS '外部分诊服务给出的优先级评分（0-100）';
+COMMENT ON COLUMN alerts.triage_assignee IS '外部分诊服务建议的处理人';
@@ -0,0 +1,8 @@
+-- 回滚工单附件扫描重试
+-- 创建时间: 2024-01-01
+-- 描述: 删除扫描重试和抢占时间字段
+
+ALTER TABLE ticket_attachments
+    DROP COLUMN IF EXISTS scan_retry_at,
+    DROP COLUMN IF EXISTS scan_claimed_at,
+    DROP COLUMN IF EXISTS scan_attempts;
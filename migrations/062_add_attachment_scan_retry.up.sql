@@ -0,0 +1,16 @@
+-- 为工单附件扫描添加重试和抢占超时
+-- 创建时间: 2024-01-01
+-- 描述: 扫描器暂时不可用时附件保持待扫描，按退避时间重试；记录抢占时间，扫描进程崩溃后超时的扫描中附件可被重新抢占
+
+ALTER TABLE ticket_attachments
+    ADD COLUMN scan_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN scan_claimed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN scan_retry_at TIMESTAMP WITH TIME ZONE;
+
+-- 此前扫描器出错的附件都被标记为扫描失败，卡在扫描中的附件无法恢复，统一重新扫描
+UPDATE ticket_attachments SET scan_status = 'pending'
+WHERE scan_status IN ('scanning', 'error');
+
+COMMENT ON COLUMN ticket_attachments.scan_attempts IS '已尝试扫描的次数';
+COMMENT ON COLUMN ticket_attachments.scan_claimed_at IS '扫描进程抢占附件的时间，超过扫描超时仍在扫描中时可被重新抢占';
+COMMENT ON COLUMN ticket_attachments.scan_retry_at IS '扫描器出错后下次重试的时间';
@@ -0,0 +1,3 @@
+-- 回滚删除告警和知识库的自定义字段定义
+-- 创建时间: 2024-01-01
+-- 描述: 告警和知识库不支持自定义字段，已删除的定义不恢复
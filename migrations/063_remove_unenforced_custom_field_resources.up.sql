@@ -0,0 +1,6 @@
+-- 删除告警和知识库的自定义字段定义
+-- 创建时间: 2024-01-01
+-- 描述: 告警和知识库创建、更新时不校验自定义字段值，自定义字段定义仅支持工单，删除此前为其他资源创建的定义
+
+UPDATE custom_field_definitions SET deleted_at = NOW()
+WHERE resource <> 'ticket' AND deleted_at IS NULL;
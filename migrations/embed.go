@@ -0,0 +1,9 @@
+// Package migrations 数据库迁移文件，编译进二进制，运行时无需依赖 migrations 目录
+package migrations
+
+import "embed"
+
+// FS 所有迁移文件
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,19 @@
+// Package web 内嵌前端构建产物，执行 make build-web 后构建的二进制即包含前端页面
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist 返回以构建输出目录为根的文件系统，前端未构建时只包含占位文件
+func Dist() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}